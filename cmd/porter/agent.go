@@ -0,0 +1,118 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func buildAgentCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Execute bundles on behalf of remote clients",
+		Long: `Run Porter as a long-running agent that executes bundle actions, lists installations and streams logs for remote clients.
+
+Clients send commands to the agent with the --remote flag, for example porter install --remote https://porter.example.com:8080, so that bundles are executed centrally with the credentials, runtime driver and storage configured for the agent.`,
+		Annotations: map[string]string{
+			"group": "meta",
+		},
+	}
+
+	cmd.AddCommand(buildAgentServeCommand(p))
+
+	return cmd
+}
+
+func buildAgentServeCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.AgentServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the agent API until the command is stopped",
+		Long: `Serve the agent API until the command is stopped.
+
+Clients must present the token defined by agent-token in the Porter config file, or the PORTER_AGENT_TOKEN environment variable. The same setting is used by clients when they connect to the agent.
+
+Each request is executed with the porter CLI on the agent, so actions are run with the configuration, credential sets, parameter sets and runtime driver of the agent. Actions that were started by a client continue to run when the client disconnects, use porter logs show --remote to see their output.
+
+Prometheus metrics about the actions executed by the agent are served at /metrics, which does not require the token.`,
+		Example: `  porter agent serve
+  porter agent serve --listen :8443 --tls-cert agent.crt --tls-key agent.key
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ServeAgent(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.Listen, "listen", ":8080",
+		"Address that the agent listens on.")
+	f.StringVar(&opts.TLSCertFile, "tls-cert", "",
+		"Path to the TLS certificate used to serve the agent over https.")
+	f.StringVar(&opts.TLSKeyFile, "tls-key", "",
+		"Path to the private key of the TLS certificate.")
+
+	return cmd
+}
+
+// addRemoteActionFlag adds the --remote flag to a bundle action command. When
+// it is specified, the command is sent to the agent and the bundle is not
+// validated or executed locally.
+func addRemoteActionFlag(p *porter.Porter, cmd *cobra.Command, action string) {
+	opts := porter.RemoteActionOptions{}
+	opts.Action = action
+	cmd.Flags().StringVar(&opts.Remote, "remote", "",
+		"Address of an agent started with porter agent serve, for example https://porter.example.com:8080, that executes the action instead of executing the bundle locally.")
+
+	// Capture the flags set on the command line before the configuration is
+	// loaded, which sets flags from the config file, so that the agent applies
+	// its own configuration.
+	var remoteFlags []string
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		remoteFlags = buildRemoteFlags(cmd.LocalFlags())
+		for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+			if parent.PersistentPreRunE != nil {
+				return parent.PersistentPreRunE(cmd, args)
+			}
+		}
+		return nil
+	}
+
+	validate, run := cmd.PreRunE, cmd.RunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if opts.Remote == "" {
+			return validate(cmd, args)
+		}
+		opts.Args = append(append([]string{}, args...), remoteFlags...)
+		return opts.Validate()
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if opts.Remote == "" {
+			return run(cmd, args)
+		}
+		return p.ExecuteRemoteAction(cmd.Context(), opts)
+	}
+}
+
+// buildRemoteFlags returns the flags that were set, in the form --NAME=VALUE,
+// excluding --remote.
+func buildRemoteFlags(flags *pflag.FlagSet) []string {
+	var args []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if !f.Changed || f.Name == "remote" {
+			return
+		}
+
+		if values, ok := f.Value.(pflag.SliceValue); ok {
+			for _, value := range values.GetSlice() {
+				args = append(args, "--"+f.Name+"="+value)
+			}
+			return
+		}
+		args = append(args, "--"+f.Name+"="+f.Value.String())
+	})
+	return args
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteActionCommand(t *testing.T) {
+	// The driver from the config is applied to the --driver flag, and should not be sent to the agent
+	os.Setenv("PORTER_RUNTIME_DRIVER", "debug")
+	defer os.Unsetenv("PORTER_RUNTIME_DRIVER")
+	os.Setenv("PORTER_AGENT_TOKEN", "secret")
+	defer os.Unsetenv("PORTER_AGENT_TOKEN")
+
+	var gotToken string
+	var gotReq porter.AgentActionRequest
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		json.NewEncoder(w).Encode(porter.AgentEvent{Output: "installing myapp\n"})
+		json.NewEncoder(w).Encode(porter.AgentEvent{Done: true})
+	}))
+	defer agent.Close()
+
+	p := porter.NewTestPorter(t)
+	defer p.Close()
+
+	var out bytes.Buffer
+	rootCmd := buildRootCommandFrom(p.Porter)
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(strings.Split("install myapp -r example.com/myapp:v1.0.0 --param a=b,c --param d=e -l env=dev --allow-hooks --remote "+agent.URL, " "))
+	err := rootCmd.Execute()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer secret", gotToken)
+	assert.Equal(t, "install", gotReq.Action)
+	assert.Equal(t, []string{
+		"myapp",
+		"--allow-hooks=true",
+		"--label=env=dev",
+		"--param=a=b,c",
+		"--param=d=e",
+		"--reference=example.com/myapp:v1.0.0",
+	}, gotReq.Args)
+	assert.Equal(t, "installing myapp\n", out.String())
+}
+
+func TestValidateRemoteActionCommand(t *testing.T) {
+	testcases := []struct {
+		name      string
+		args      string
+		wantError string
+	}{
+		{"local file", "install --file porter.yaml --remote porter.example.com", "--file is not supported when the action is executed remotely"},
+		{"invalid remote", "uninstall myapp --remote ftp://porter.example.com", "invalid --remote ftp://porter.example.com, the supported schemes are http and https"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := porter.NewTestPorter(t)
+			defer p.Close()
+
+			rootCmd := buildRootCommandFrom(p.Porter)
+			rootCmd.SetArgs(strings.Split(tc.args, " "))
+			err := rootCmd.Execute()
+			require.EqualError(t, err, tc.wantError)
+		})
+	}
+}
@@ -23,6 +23,9 @@ func buildAliasCommands(p *porter.Porter) []*cobra.Command {
 		buildExplainAlias(p),
 		buildCopyAlias(p),
 		buildInspectAlias(p),
+		buildVerifyAlias(p),
+		buildScanAlias(p),
+		buildSearchAlias(p),
 		buildLogsAlias(p),
 	}
 }
@@ -120,6 +123,9 @@ func buildListAlias(p *porter.Porter) *cobra.Command {
 func buildArchiveAlias(p *porter.Porter) *cobra.Command {
 	cmd := buildBundleArchiveCommand(p)
 	cmd.Example = strings.Replace(cmd.Example, "porter bundle archive", "porter archive", -1)
+	for _, subCmd := range cmd.Commands() {
+		subCmd.Example = strings.Replace(subCmd.Example, "porter bundle archive", "porter archive", -1)
+	}
 	cmd.Annotations = map[string]string{
 		"group": "alias",
 	}
@@ -129,6 +135,9 @@ func buildArchiveAlias(p *porter.Porter) *cobra.Command {
 func buildExplainAlias(p *porter.Porter) *cobra.Command {
 	cmd := buildBundleExplainCommand(p)
 	cmd.Example = strings.Replace(cmd.Example, "porter bundle explain", "porter explain", -1)
+	for _, subCmd := range cmd.Commands() {
+		subCmd.Example = strings.Replace(subCmd.Example, "porter bundle explain", "porter explain", -1)
+	}
 	cmd.Annotations = map[string]string{
 		"group": "alias",
 	}
@@ -153,6 +162,33 @@ func buildInspectAlias(p *porter.Porter) *cobra.Command {
 	return cmd
 }
 
+func buildVerifyAlias(p *porter.Porter) *cobra.Command {
+	cmd := buildBundleVerifyCommand(p)
+	cmd.Example = strings.Replace(cmd.Example, "porter bundle verify", "porter verify", -1)
+	cmd.Annotations = map[string]string{
+		"group": "alias",
+	}
+	return cmd
+}
+
+func buildScanAlias(p *porter.Porter) *cobra.Command {
+	cmd := buildBundleScanCommand(p)
+	cmd.Example = strings.Replace(cmd.Example, "porter bundle scan", "porter scan", -1)
+	cmd.Annotations = map[string]string{
+		"group": "alias",
+	}
+	return cmd
+}
+
+func buildSearchAlias(p *porter.Porter) *cobra.Command {
+	cmd := buildBundleSearchCommand(p)
+	cmd.Example = strings.Replace(cmd.Example, "porter bundle search", "porter search", -1)
+	cmd.Annotations = map[string]string{
+		"group": "alias",
+	}
+	return cmd
+}
+
 func buildLogsAlias(p *porter.Porter) *cobra.Command {
 	cmd := buildInstallationLogShowCommand(p)
 	cmd.Use = "logs"
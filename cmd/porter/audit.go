@@ -0,0 +1,59 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildAuditCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "audit",
+		Annotations: map[string]string{"group": "resource"},
+		Short:       "Audit log commands",
+		Long:        `Commands for viewing the audit log, which records who created, updated or deleted installations, credential sets and parameter sets.`,
+	}
+
+	cmd.AddCommand(buildAuditListCommand(p))
+
+	return cmd
+}
+
+func buildAuditListCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.AuditListOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List audit events",
+		Long: `List the events recorded when installations, credential sets and parameter sets were created, updated or deleted, most recent first.
+
+Each event records who modified the resource, when, the command that was run, and the names of the fields that were changed by an update.
+The events may be filtered by the type of resource, such as installation, creds or params, and optionally the name of the resource.`,
+		Example: `  porter audit list
+  porter audit list --resource creds/mycreds
+  porter audit list --resource installation --namespace prod
+  porter audit list --all-namespaces --limit 20 --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintAuditEvents(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace of the modified resources. Defaults to the global namespace.")
+	f.BoolVar(&opts.AllNamespaces, "all-namespaces", false,
+		"Include all namespaces in the results.")
+	f.StringVar(&opts.Resource, "resource", "",
+		"Filter the events by resource, formatted as TYPE or TYPE/NAME. Allowed types: installation, credentialset (creds), parameterset (params).")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+	f.Int64Var(&opts.Skip, "skip", 0,
+		"Skip the number of events by a certain amount. Defaults to 0.")
+	f.Int64Var(&opts.Limit, "limit", 0,
+		"Limit the number of events by a certain amount. Defaults to 0.")
+
+	return cmd
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"get.porter.sh/porter/pkg/porter"
+	"get.porter.sh/porter/pkg/sbom"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +27,10 @@ func buildBundleCommands(p *porter.Porter) *cobra.Command {
 	cmd.AddCommand(buildBundleExplainCommand(p))
 	cmd.AddCommand(buildBundleCopyCommand(p))
 	cmd.AddCommand(buildBundleInspectCommand(p))
+	cmd.AddCommand(buildBundleVerifyCommand(p))
+	cmd.AddCommand(buildBundleScanCommand(p))
+	cmd.AddCommand(buildBundleSearchCommand(p))
+	cmd.AddCommand(buildBundleIndexCommands(p))
 
 	return cmd
 }
@@ -61,6 +66,9 @@ The docker driver builds the bundle image using the local Docker host. To use a
   porter build --file path/to/porter.yaml
   porter build --dir path/to/build/context
   porter build --custom version=0.2.0 --custom myapp.version=0.1.2
+  porter build --platform linux/amd64,linux/arm64 --builder mybuilder
+  porter build --builder mybuilder --cache-from type=registry,ref=example.com/mybuns:cache --cache-to type=registry,ref=example.com/mybuns:cache,mode=max
+  porter build --sbom --sbom-format cyclonedx
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(p)
@@ -89,8 +97,20 @@ The docker driver builds the bundle image using the local Docker host. To use a
 		"Secret file to expose to the build (format: id=mysecret,src=/local/secret). Custom values are assessible as build arguments in the template Dockerfile and in the manifest using template variables. May be specified multiple times.")
 	f.BoolVar(&opts.NoCache, "no-cache", false,
 		"Do not use the Docker cache when building the bundle's invocation image.")
+	f.StringSliceVar(&opts.Platforms, "platform", nil,
+		"Target platforms for the invocation image, for example linux/amd64,linux/arm64. When more than one platform is specified, the image is saved as a multi-architecture image index that is pushed by porter publish and requires a buildx builder that supports multiple platforms, such as the docker-container driver. May be specified multiple times.")
+	f.StringVar(&opts.Builder, "builder", "",
+		"Name of the buildx builder used to build the invocation image. Defaults to the default docker builder.")
+	f.StringArrayVar(&opts.CacheFrom, "cache-from", nil,
+		"External cache sources for the invocation image build (format: type=registry,ref=example.com/mybuns:cache). May be specified multiple times.")
+	f.StringArrayVar(&opts.CacheTo, "cache-to", nil,
+		"Cache export destinations for the invocation image build (format: type=registry,ref=example.com/mybuns:cache,mode=max). Exporting to a registry requires a buildx builder that supports cache export, such as the docker-container driver. May be specified multiple times.")
 	f.StringArrayVar(&opts.Customs, "custom", nil,
 		"Define an individual key-value pair for the custom section in the form of NAME=VALUE. Use dot notation to specify a nested custom field. May be specified multiple times.")
+	f.BoolVar(&opts.SBOM, "sbom", false,
+		"Generate a software bill of materials (SBOM) for the invocation image with syft, which is attached to the image when the bundle is published.")
+	f.StringVar(&opts.SBOMFormat, "sbom-format", "",
+		fmt.Sprintf("Format of the generated SBOM. Allowed values are: %s. Defaults to %s.", strings.Join(sbom.AllowedFormats, ", "), sbom.DefaultFormat))
 
 	// Allow configuring the --driver flag with build-driver, to avoid conflicts with other commands
 	cmd.Flag("driver").Annotations = map[string][]string{
@@ -107,10 +127,15 @@ func buildBundleLintCommand(p *porter.Porter) *cobra.Command {
 		Short: "Lint a bundle",
 		Long: `Check the bundle for problems and adherence to best practices by running linters for porter and the mixins used in the bundle.
 
-The lint command is run automatically when you build a bundle. The command is available separately so that you can just lint your bundle without also building it.`,
+The lint command is run automatically when you build a bundle. The command is available separately so that you can just lint your bundle without also building it.
+
+Rules can be disabled, or the level of their results changed, with a porter-lint.yaml file in the same directory as the porter manifest. Use --fail-on to return a non-zero exit code when a result has the specified level or higher, for example to fail a pipeline on warnings.`,
 		Example: `  porter lint
   porter lint --file path/to/porter.yaml
   porter lint --output plaintext
+  porter lint --output sarif > porter-lint.sarif
+  porter lint --fail-on warning
+  porter lint --config path/to/porter-lint.yaml
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(p.Context)
@@ -125,6 +150,10 @@ The lint command is run automatically when you build a bundle. The command is av
 		"Path to the porter manifest file. Defaults to the bundle in the current directory.")
 	f.StringVarP(&opts.RawFormat, "output", "o", string(porter.LintDefaultFormats),
 		"Specify an output format.  Allowed values: "+porter.LintAllowFormats.String())
+	f.StringVar(&opts.FailOn, "fail-on", "",
+		"Fail when a result has this level or higher. Allowed values: error, warning, info, none. Defaults to the failOn setting in the lint configuration file, or none.")
+	f.StringVar(&opts.Config, "config", "",
+		"Path to the lint configuration file. Defaults to porter-lint.yaml in the same directory as the porter manifest.")
 
 	return cmd
 }
@@ -137,13 +166,22 @@ func buildBundlePublishCommand(p *porter.Porter) *cobra.Command {
 		Short: "Publish a bundle",
 		Long: `Publishes a bundle by pushing the invocation image and bundle to a registry.
 
+The bundle is built first when it is out-of-date. When the registry already has an identical bundle and invocation image, nothing is pushed and the bundle is reported as up to date. Otherwise, publish stops when the bundle already exists in the registry, unless --force is specified.
+
+When the bundle was built with --sbom, the generated software bill of materials is attached to the published invocation image.
+
+When publishing from an archive, the bundle may be published to multiple registries in one pass by repeating --reference or listing the references in a file with --destinations-file. The archive is extracted once and pushed to each destination. Use --relocation-map-dir to save the relocation mapping for each destination.
+
 Note: if overrides for registry/tag/reference are provided, this command only re-tags the invocation image and bundle; it does not re-build the bundle.`,
 		Example: `  porter bundle publish
   porter bundle publish --file myapp/porter.yaml
   porter bundle publish --dir myapp
   porter bundle publish --archive /tmp/mybuns.tgz --reference myrepo/my-buns:0.1.0
+  porter bundle publish --archive /tmp/mybuns.tgz --reference reg1.example.com/my-buns:0.1.0 --reference reg2.example.com/my-buns:0.1.0 --relocation-map-dir ./relocations
+  porter bundle publish --archive /tmp/mybuns.tgz --destinations-file destinations.txt
   porter bundle publish --tag latest
   porter bundle publish --registry myregistry.com/myorg
+  porter bundle publish --sign
 		`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(p.Config)
@@ -160,9 +198,17 @@ Note: if overrides for registry/tag/reference are provided, this command only re
 	f.StringVarP(&opts.ArchiveFile, "archive", "a", "", "Path to the bundle archive in .tgz format")
 	f.StringVar(&opts.Tag, "tag", "", "Override the Docker tag portion of the bundle reference, e.g. latest, v0.1.1")
 	f.StringVar(&opts.Registry, "registry", "", "Override the registry portion of the bundle reference, e.g. docker.io, myregistry.com/myorg")
-	addReferenceFlag(f, &opts.BundlePullOptions)
+	f.StringArrayVarP(&opts.References, "reference", "r", nil,
+		"Publish the bundle to the given reference. May be specified multiple times when publishing from an archive to publish to multiple registries.")
+	f.StringVar(&opts.DestinationsFile, "destinations-file", "",
+		"Path to a file with the references to publish the bundle to, one per line. Only supported when publishing from an archive.")
+	f.StringVar(&opts.RelocationMapDir, "relocation-map-dir", "",
+		"Directory where the relocation mapping for each destination is written when publishing from an archive.")
 	addInsecureRegistryFlag(f, &opts.BundlePullOptions)
 	f.BoolVar(&opts.Force, "force", false, "Force push the bundle to overwrite the previously published bundle")
+	f.BoolVar(&opts.Sign, "sign", false, "Sign the bundle and its invocation images after they are pushed, using the signer configured in the Porter configuration file")
+	f.IntVar(&opts.Parallelism, "parallelism", porter.DefaultParallelism,
+		"Maximum number of images, and layers of each image, that are pushed at the same time.")
 	// Allow configuring the --force flag with "force-overwrite" in the configuration file
 	cmd.Flag("force").Annotations = map[string][]string{
 		"viper-key": {"force-overwrite"},
@@ -174,14 +220,23 @@ Note: if overrides for registry/tag/reference are provided, this command only re
 func buildBundleArchiveCommand(p *porter.Porter) *cobra.Command {
 
 	opts := porter.ArchiveOptions{}
+	var includeImages bool
 	cmd := cobra.Command{
 		Use:   "archive FILENAME --reference PUBLISHED_BUNDLE",
 		Short: "Archive a bundle from a reference",
-		Long:  "Archives a bundle by generating a gzipped tar archive containing the bundle, invocation image and any referenced images.",
+		Long: `Archives a bundle by generating a compressed tar archive containing the bundle, invocation image and any referenced images.
+
+Image layers are downloaded in parallel into the content store in PORTER_HOME/content, where they are reused by later archives, and then copied into the archive. Use --parallelism 1 to stream the layers from the registry directly into the archive one at a time instead, so that no additional disk space is required beyond the archive itself.
+
+Requests that are rate limited by the registry are retried.`,
 		Example: `  porter bundle archive mybun.tgz --reference ghcr.io/getporter/examples/porter-hello:v0.2.0
   porter bundle archive mybun.tgz --reference localhost:5000/ghcr.io/getporter/examples/porter-hello:v0.2.0 --force
+  porter bundle archive mybun.tar.zst --reference ghcr.io/getporter/examples/porter-hello:v0.2.0 --compression zstd
+  porter bundle archive mybun.tgz --reference ghcr.io/getporter/examples/porter-hello:v0.2.0 --include-images=false
+  porter bundle archive mybun.tgz --reference ghcr.io/getporter/examples/porter-hello:v0.2.0 --exclude-image 'training-data*'
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			opts.ExcludeReferencedImages = !includeImages
 			return opts.Validate(cmd.Context(), args, p)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -189,7 +244,41 @@ func buildBundleArchiveCommand(p *porter.Porter) *cobra.Command {
 		},
 	}
 
-	addBundlePullFlags(cmd.Flags(), &opts.BundlePullOptions)
+	f := cmd.Flags()
+	addBundlePullFlags(f, &opts.BundlePullOptions)
+	f.StringVar(&opts.Compression, "compression", porter.ArchiveCompressionGzip,
+		fmt.Sprintf("Compression used for the archive. Allowed values are: %s", strings.Join(porter.ArchiveCompressions, ", ")))
+	f.BoolVar(&includeImages, "include-images", true,
+		"Include the images referenced by the bundle in the archive. When false, only the bundle and its invocation image are archived.")
+	f.StringSliceVar(&opts.ExcludeImages, "exclude-image", nil,
+		"Omit referenced images from the archive that match the glob pattern. The pattern is matched against the image name in the bundle and the image reference. May be specified multiple times.")
+	f.BoolVar(&opts.Verify, "verify", false,
+		"Verify the integrity of the archive after it is created.")
+	f.IntVar(&opts.Parallelism, "parallelism", porter.DefaultParallelism,
+		"Maximum number of images and layers that are downloaded at the same time.")
+
+	cmd.AddCommand(buildBundleArchiveVerifyCommand(p))
+
+	return &cmd
+}
+
+func buildBundleArchiveVerifyCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ArchiveVerifyOptions{}
+	cmd := cobra.Command{
+		Use:   "verify FILENAME",
+		Short: "Verify the integrity of a bundle archive",
+		Long: `Verifies the integrity of a bundle archive by validating the checksum of every file in the archive and the digest of every image layer.
+
+Use this command after moving an archive, for example across an air gap, to detect a corrupted transfer before publishing the archive.`,
+		Example: `  porter bundle archive verify mybun.tgz
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.VerifyArchive(cmd.Context(), opts)
+		},
+	}
 
 	return &cmd
 }
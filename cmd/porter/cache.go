@@ -0,0 +1,91 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildCacheCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "cache",
+		Annotations: map[string]string{"group": "resource"},
+		Short:       "Bundle cache commands",
+		Long: `Commands for managing the local bundle cache in PORTER_HOME/cache.
+
+Bundles are cached when they are pulled from a registry, and resolved from the cache the next time they are used.
+When cache.max-size is set in the Porter configuration file, the least recently used bundles are evicted after a bundle is cached.`,
+	}
+
+	cmd.AddCommand(buildCacheListCommand(p))
+	cmd.AddCommand(buildCachePruneCommand(p))
+	cmd.AddCommand(buildCacheClearCommand(p))
+
+	return cmd
+}
+
+func buildCacheListCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.CacheListOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List cached bundles",
+		Long:    `List the bundles in the local bundle cache, and their size, starting with the most recently used bundle.`,
+		Example: `  porter cache list
+  porter cache list --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintCachedBundles(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+
+	return cmd
+}
+
+func buildCachePruneCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.CachePruneOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove the least recently used bundles from the cache",
+		Long: `Remove the least recently used bundles from the local bundle cache until it is no larger than the maximum size.
+
+The most recently used bundle is always kept. When --max-size is not specified, the cache.max-size setting from the Porter configuration file is used.`,
+		Example: `  porter cache prune --max-size 500MB
+  porter cache prune --max-size 2GB --dry-run`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PruneCache(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.MaxSize, "max-size", "",
+		"Maximum size of the cache, for example 500MB or 2GB. Defaults to cache.max-size from the Porter configuration file.")
+	f.BoolVar(&opts.DryRun, "dry-run", false,
+		"List the bundles that would be removed without removing them.")
+
+	return cmd
+}
+
+func buildCacheClearCommand(p *porter.Porter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every bundle from the cache",
+		Long: `Remove every bundle from the local bundle cache, and the image layers saved in PORTER_HOME/content.
+
+Bundles and image layers are pulled from their registry again the next time they are used.`,
+		Example: `  porter cache clear`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ClearCache(cmd.Context())
+		},
+	}
+}
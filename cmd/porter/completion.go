@@ -35,3 +35,63 @@ For additional details see: https://getporter.org/install#command-completion`,
 	}
 	return cmd
 }
+
+// completeInstallationNames completes the installation name argument of a
+// command with the installations in the namespace of the command.
+func completeInstallationNames(p *porter.Porter) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names, err := p.CompleteInstallationNames(cmd.Context(), getCompletionNamespace(p, cmd), toComplete)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeCredentialSetNames completes a flag with the credential sets in the
+// namespace of the command.
+func completeCredentialSetNames(p *porter.Porter) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, err := p.CompleteCredentialSetNames(cmd.Context(), getCompletionNamespace(p, cmd), toComplete)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeReferences completes a flag with the references of recently used
+// bundles from the bundle cache.
+func completeReferences(p *porter.Porter) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		refs, err := p.CompleteReferences(toComplete)
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return refs, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// addBundleActionCompletions registers the completion of the flags added by
+// addBundleActionFlags that reference resources, such as credential sets.
+func addBundleActionCompletions(p *porter.Porter, cmd *cobra.Command) {
+	cmd.RegisterFlagCompletionFunc("credential-set", completeCredentialSetNames(p))
+	cmd.RegisterFlagCompletionFunc("reference", completeReferences(p))
+}
+
+// getCompletionNamespace returns the namespace used to complete resource
+// names: the --namespace flag when specified, otherwise the namespace from the
+// porter config.
+func getCompletionNamespace(p *porter.Porter, cmd *cobra.Command) string {
+	if f := cmd.Flags().Lookup("namespace"); f != nil && f.Changed {
+		return f.Value.String()
+	}
+	return p.Data.Namespace
+}
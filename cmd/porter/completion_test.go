@@ -2,10 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"testing"
 
+	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/porter"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,3 +36,25 @@ func TestCompletion_SkipConfig(t *testing.T) {
 	shouldSkip := shouldSkipConfig(cmd)
 	require.True(t, shouldSkip, "expected that we skip loading configuration for the completion command")
 }
+
+func TestCompletion_Reference(t *testing.T) {
+	p := porter.NewTestPorter(t)
+	defer p.Close()
+
+	bunRef := cnab.BundleReference{
+		Reference:  cnab.MustParseOCIReference("ghcr.io/getporter/examples/porter-hello:v0.2.0"),
+		Definition: cnab.NewBundle(bundle.Bundle{Name: "porter-hello"}),
+	}
+	_, err := p.Cache.StoreBundle(context.Background(), bunRef)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	rootCmd := buildRootCommandFrom(p.Porter)
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{cobra.ShellCompRequestCmd, "upgrade", "--reference", "ghcr.io/"})
+	err = rootCmd.Execute()
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "ghcr.io/getporter/examples/porter-hello:v0.2.0\n")
+	assert.Contains(t, out.String(), ":4\n", "file completion should be disabled")
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func buildConfigCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Configuration commands",
+		Long: `Commands for working with Porter's configuration file.
+
+Contexts are named sets of configuration settings, such as the storage account, secrets plugin, default namespace and registry settings used in an environment. The settings of the current context are layered over the rest of the configuration file.`,
+		Annotations: map[string]string{
+			"group": "meta",
+		},
+	}
+
+	cmd.AddCommand(buildConfigListContextsCommand(p))
+	cmd.AddCommand(buildConfigCurrentContextCommand(p))
+	cmd.AddCommand(buildConfigUseContextCommand(p))
+	cmd.AddCommand(buildConfigValidateCommand(p))
+	cmd.AddCommand(buildConfigViewCommand(p))
+
+	return cmd
+}
+
+func buildConfigListContextsCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ContextListOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list-contexts",
+		Short: "List the contexts defined in the configuration file",
+		Long:  "List the contexts defined in the configuration file. The current context is marked with an asterisk.",
+		Example: `  porter config list-contexts
+  porter config list-contexts --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintContexts(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+
+	return cmd
+}
+
+func buildConfigCurrentContextCommand(p *porter.Porter) *cobra.Command {
+	return &cobra.Command{
+		Use:     "current-context",
+		Short:   "Print the name of the current context",
+		Long:    "Print the name of the current context, which is selected by the PORTER_CONTEXT environment variable or the current-context setting in the configuration file.",
+		Example: `  porter config current-context`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintCurrentContext(cmd.Context())
+		},
+	}
+}
+
+func buildConfigUseContextCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.UseContextOptions{}
+
+	return &cobra.Command{
+		Use:   "use-context NAME",
+		Short: "Select the current context",
+		Long: `Select the context whose settings are layered over the rest of the configuration file, by saving it as current-context in the configuration file.
+
+The PORTER_CONTEXT environment variable overrides the context selected in the configuration file, for example to use a different context in a single terminal.`,
+		Example: `  porter config use-context prod
+  PORTER_CONTEXT=dev porter installations list`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.UseContext(cmd.Context(), opts)
+		},
+	}
+}
+
+func buildConfigValidateCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ConfigValidateOptions{}
+
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration file",
+		Long: `Validate the configuration file, reporting every problem found.
+
+Settings that are not recognized, such as typos, and values with the wrong data type or that are not allowed are reported. The plugins used by the storage and secrets sections must be available, and the configuration of plugins provided by Porter is checked against the settings supported by the plugin. Template variables, such as ${env.NAME} and ${secret.NAME}, are rendered before the configuration is validated.`,
+		Example: `  porter config validate
+  PORTER_CONTEXT=prod porter config validate`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Flags = listFlags(cmd.Root())
+			return p.ValidateConfig(cmd.Context(), opts)
+		},
+	}
+}
+
+// listFlags returns the names of the flags defined by the command and its
+// sub-commands, which may also be set in the config file.
+func listFlags(cmd *cobra.Command) []string {
+	var names []string
+	addFlag := func(f *pflag.Flag) {
+		name := f.Name
+		if altKey, ok := f.Annotations["viper-key"]; ok && len(altKey) > 0 {
+			name = altKey[0]
+		}
+		names = append(names, name)
+	}
+	cmd.Flags().VisitAll(addFlag)
+	cmd.PersistentFlags().VisitAll(addFlag)
+
+	for _, child := range cmd.Commands() {
+		names = append(names, listFlags(child)...)
+	}
+	return names
+}
+
+func buildConfigViewCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ConfigViewOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Print the configuration",
+		Long: `Print the settings defined in the configuration file.
+
+When --resolved is specified, the effective configuration is printed instead: template variables, such as ${env.NAME} and ${secret.NAME}, are rendered, the settings of the current context are applied, and values from environment variables and defaults are included.
+
+Sensitive values, such as tokens, passwords and values that came from a secret, are masked.`,
+		Example: `  porter config view
+  porter config view --resolved
+  porter config view --resolved --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ViewConfig(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&opts.Resolved, "resolved", false,
+		"Print the effective configuration, after template variables are rendered and the current context, environment variables and defaults are applied.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "yaml",
+		"Specify an output format.  Allowed values: yaml, json")
+
+	return cmd
+}
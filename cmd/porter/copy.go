@@ -16,10 +16,16 @@ func buildBundleCopyCommand(p *porter.Porter) *cobra.Command {
 Source bundle can be either a tagged reference or a digest reference.
 Destination can be either a registry, a registry/repository, or a fully tagged bundle reference. 
 If the source bundle is a digest reference, destination must be a tagged reference.
+
+The invocation images and every image referenced by the bundle are copied into the destination repository and pinned by digest, so that the copied bundle does not depend upon the source registry.
+Use --relocation-output to save the relocation mapping of the copied bundle to a file, which can be passed to porter install --relocation-mapping.
+
+Images, and the layers of each image, are copied in parallel. Use --parallelism to limit how many are copied at the same time. Requests that are rate limited by the registry are retried.
 `,
 		Example: `  porter bundle copy
   porter bundle copy --source ghcr.io/getporter/examples/porter-hello:v0.2.0 --destination portersh
   porter bundle copy --source ghcr.io/getporter/examples/porter-hello:v0.2.0 --destination portersh --insecure-registry
+  porter bundle copy --source ghcr.io/getporter/examples/porter-hello:v0.2.0 --destination myregistry.example.com/porter-hello:v0.2.0 --relocation-output relocation-mapping.json
 		  `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(p.Config)
@@ -33,6 +39,8 @@ If the source bundle is a digest reference, destination must be a tagged referen
 	f.StringVarP(&opts.Destination, "destination", "", "", "The registry to copy the bundle to. Can be registry name, registry plus a repo prefix, or a new tagged reference. All images and the bundle will be prefixed with registry.")
 	f.BoolVar(&opts.InsecureRegistry, "insecure-registry", false, "Don't require TLS for registries")
 	f.BoolVar(&opts.Force, "force", false, "Force push the bundle to overwrite the previously published bundle")
+	f.StringVar(&opts.RelocationOutput, "relocation-output", "", "Path to a file where the relocation mapping of the copied bundle is written. The file can be used with porter install --relocation-mapping.")
+	f.IntVar(&opts.Parallelism, "parallelism", porter.DefaultParallelism, "Maximum number of images, and layers of each image, that are copied at the same time.")
 	// Allow configuring the --force flag with "force-overwrite" in the configuration file
 	cmd.Flag("force").Annotations = map[string][]string{
 		"viper-key": {"force-overwrite"},
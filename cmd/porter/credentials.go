@@ -20,6 +20,7 @@ func buildCredentialsCommands(p *porter.Porter) *cobra.Command {
 	cmd.AddCommand(buildCredentialsDeleteCommand(p))
 	cmd.AddCommand(buildCredentialsShowCommand(p))
 	cmd.AddCommand(buildCredentialsCreateCommand(p))
+	cmd.AddCommand(buildCredentialsToCRDCommand(p))
 
 	return cmd
 }
@@ -144,7 +145,10 @@ The results may also be filtered by associated labels and the namespace in which
   porter credentials list --all-namespaces,
   porter credentials list --name myapp
   porter credentials list --label env=dev
-  porter credentials list --skip 2 --limit 2`,
+  porter credentials list -o wide
+  porter credentials list --columns name,credentials --sort-by name
+  porter credentials list --skip 2 --limit 2
+  porter credentials list --limit 100 --page 3`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate()
 		},
@@ -163,11 +167,19 @@ The results may also be filtered by associated labels and the namespace in which
 	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
 		"Filter the credential sets by a label formatted as: KEY=VALUE. May be specified multiple times.")
 	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
-		"Specify an output format.  Allowed values: plaintext, json, yaml")
+		"Specify an output format.  Allowed values: plaintext, wide, csv, json, yaml")
+	f.StringSliceVar(&opts.Columns, "columns", nil,
+		"Comma separated list of the columns to print, in order, for example NAME,NAMESPACE,MODIFIED. Any column of the wide output may be selected.")
+	f.StringVar(&opts.SortBy, "sort-by", "",
+		"Name of the column used to sort the results, for example modified.")
 	f.Int64Var(&opts.Skip, "skip", 0,
 		"Skip the number of credential sets by a certain amount. Defaults to 0.")
 	f.Int64Var(&opts.Limit, "limit", 0,
 		"Limit the number of credential sets by a certain amount. Defaults to 0.")
+	f.Int64Var(&opts.Page, "page", 0,
+		"Page of credential sets to return, starting at 1. Requires --limit, which sets the size of each page.")
+	f.StringVar(&opts.Continue, "continue", "",
+		"Continue listing credential sets after the previous page, using the token printed with that page.")
 
 	return cmd
 }
@@ -191,6 +203,8 @@ func buildCredentialsDeleteCommand(p *porter.Porter) *cobra.Command {
 	f := cmd.Flags()
 	f.StringVarP(&opts.Namespace, "namespace", "n", "",
 		"Namespace in which the credential set is defined. Defaults to the global namespace.")
+	f.BoolVar(&opts.Force, "force", false,
+		"Delete the credential set even when its namespace is protected.")
 
 	return cmd
 }
@@ -199,11 +213,14 @@ func buildCredentialsShowCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.CredentialShowOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "show",
+		Use:   "show [NAME]",
 		Short: "Show a Credential",
-		Long:  `Show a particular credential set, including all named credentials and their corresponding mappings.`,
+		Long: `Show a particular credential set, including all named credentials and their corresponding mappings.
+
+When the name is not specified, you are asked to pick a credential set from the namespace when run from an interactive terminal.`,
 		Example: `  porter credential show github --namespace dev
-  porter credential show prodcluster --output json`,
+  porter credential show prodcluster --output json
+  porter credential show --namespace dev`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args)
 		},
@@ -244,3 +261,35 @@ func buildCredentialsCreateCommand(p *porter.Porter) *cobra.Command {
 
 	return cmd
 }
+
+func buildCredentialsToCRDCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.CRDOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "to-crd NAME",
+		Short: "Print a credential set as a Porter Operator custom resource",
+		Long: `Print a credential set as a CredentialSet custom resource of the Porter Operator, so that it can be migrated from the CLI to the operator with kubectl apply.
+
+The operator resolves credentials from Kubernetes secrets. A warning is printed for credentials that are resolved from another source, such as an environment variable or a file, which must be changed before the resource is applied. The secrets are not copied.
+`,
+		Example: `  porter credentials to-crd github
+  porter credentials to-crd github --namespace dev --k8s-namespace porter-dev
+  porter credentials to-crd github --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.CredentialSetToCRD(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the credential set is defined. Defaults to the global namespace.")
+	f.StringVar(&opts.KubernetesNamespace, "k8s-namespace", "",
+		"Kubernetes namespace of the custom resource. Defaults to the namespace selected when the resource is applied.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "yaml",
+		"Specify an output format.  Allowed values: yaml, json")
+
+	return cmd
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildDiagnosticsCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.DiagnosticsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diagnostics",
+		Short: "Collect diagnostics to attach to a bug report",
+		Long: `Collect information about Porter and its environment into a tarball that can be attached to a bug report.
+
+The tarball contains:
+  * version.json: The version of Porter, the operating system and architecture, and the installed mixins.
+  * plugins.json: The installed plugins.
+  * config.yaml: The resolved configuration, with sensitive values and the config of every plugin masked.
+  * checks.json: The results of checking that Porter can connect to its storage.
+  * logs: Porter's most recent log files, when logs.log-to-file is set in the config file.
+  * runs: The logs of the last run of the most recently modified installations.
+  * errors.txt: Any problems encountered while collecting the diagnostics.
+
+Bundles may print sensitive data in their logs, so review the contents of the tarball before sharing it, or specify --runs 0 to exclude the logs of the runs.`,
+		Example: `  porter diagnostics
+  porter diagnostics --file bug-report.tgz
+  porter diagnostics --runs 0
+`,
+		Annotations: map[string]string{
+			"group": "meta",
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.CollectDiagnostics(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.File, "file", "f", "",
+		"Path of the tarball to create. Defaults to porter-diagnostics-TIMESTAMP.tgz in the current directory.")
+	f.IntVar(&opts.Runs, "runs", 10,
+		"Number of installations, most recently modified first, whose last run logs are included.")
+
+	return cmd
+}
@@ -11,13 +11,16 @@ func buildBundleExplainCommand(p *porter.Porter) *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "explain REFERENCE",
 		Short: "Explain a bundle",
-		Long:  "Explain how to use a bundle by printing the parameters, credentials, outputs, actions.",
+		Long: `Explain how to use a bundle by printing the parameters, credentials, outputs, actions, required extensions and host prerequisites.
+
+Use --check to validate that the current host satisfies the requirements of the bundle before installing it. The minimum Porter version, the required CNAB extensions, and the commands that the mixins used by the bundle require on the host are checked, and an error is returned when a requirement is not satisfied.`,
 		Example: `  porter bundle explain
   porter bundle explain ghcr.io/getporter/examples/porter-hello:v0.2.0
   porter bundle explain localhost:5000/ghcr.io/getporter/examples/porter-hello:v0.2.0 --insecure-registry --force
   porter bundle explain --file another/porter.yaml
   porter bundle explain --cnab-file some/bundle.json
   porter bundle explain --action install
+  porter bundle explain --check
 		  `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args, p.Context)
@@ -32,7 +35,39 @@ func buildBundleExplainCommand(p *porter.Porter) *cobra.Command {
 	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
 		"Specify an output format.  Allowed values: plaintext, json, yaml")
 	f.StringVar(&opts.Action, "action", "", "Hide parameters and outputs that are not used by the specified action.")
+	f.BoolVar(&opts.Check, "check", false, "Check that the current host satisfies the minimum Porter version, required extensions and host prerequisites of the bundle.")
 	addBundlePullFlags(f, &opts.BundlePullOptions)
 
+	cmd.AddCommand(buildBundleExplainDiffCommand(p))
+
 	return &cmd
 }
+
+func buildBundleExplainDiffCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ExplainDiffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff OLD_REFERENCE NEW_REFERENCE",
+		Short: "Compare two versions of a bundle",
+		Long: `Compare the parameters, credentials, outputs, images, and custom actions of two versions of a bundle, to review the impact of upgrading an installation from the old bundle to the new bundle.
+
+Each item that was added, removed or changed is listed with the values that changed. Changes that may break upgrading existing installations are marked as breaking, such as a removed parameter, output or custom action, a parameter whose type changed, and a new required parameter without a default or a new required credential.`,
+		Example: `  porter bundle explain diff ghcr.io/getporter/examples/porter-hello:v0.1.0 ghcr.io/getporter/examples/porter-hello:v0.2.0
+  porter bundle explain diff localhost:5000/mybuns:v1.0.0 localhost:5000/mybuns:v1.1.0 --insecure-registry
+  porter bundle explain diff ghcr.io/getporter/examples/porter-hello:v0.1.0 ghcr.io/getporter/examples/porter-hello:v0.2.0 --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ExplainDiff(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+	f.BoolVar(&opts.InsecureRegistry, "insecure-registry", false,
+		"Don't require TLS for the registry")
+	f.BoolVar(&opts.Force, "force", false,
+		"Force a fresh pull of the bundles")
+	return cmd
+}
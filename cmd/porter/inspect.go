@@ -1,12 +1,16 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
 	"get.porter.sh/porter/pkg/porter"
+	"get.porter.sh/porter/pkg/sbom"
 	"github.com/spf13/cobra"
 )
 
 func buildBundleInspectCommand(p *porter.Porter) *cobra.Command {
-	opts := porter.ExplainOpts{}
+	opts := porter.InspectOptions{}
 	cmd := cobra.Command{
 		Use:   "inspect REFERENCE",
 		Short: "Inspect a bundle",
@@ -14,12 +18,16 @@ func buildBundleInspectCommand(p *porter.Porter) *cobra.Command {
 
 If you would like more information about the bundle, the porter explain command will provide additional information,
 like parameters, credentials, outputs and custom actions available.
+
+Use --sbom to print the software bill of materials (SBOM) attached to the invocation image of a published bundle, which is generated with porter build --sbom.
 `,
 		Example: `  porter bundle inspect
   porter bundle inspect ghcr.io/getporter/examples/porter-hello:v0.2.0
   porter bundle inspect localhost:5000/ghcr.io/getporter/examples/porter-hello:v0.2.0 --insecure-registry --force
   porter bundle inspect --file another/porter.yaml
   porter bundle inspect --cnab-file some/bundle.json
+  porter bundle inspect ghcr.io/getporter/examples/porter-hello:v0.2.0 --sbom
+  porter bundle inspect ghcr.io/getporter/examples/porter-hello:v0.2.0 --sbom --sbom-format cyclonedx
 		  `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args, p.Context)
@@ -33,6 +41,10 @@ like parameters, credentials, outputs and custom actions available.
 	f.StringVar(&opts.CNABFile, "cnab-file", "", "Path to the CNAB bundle.json file.")
 	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
 		"Specify an output format.  Allowed values: plaintext, json, yaml")
+	f.BoolVar(&opts.SBOM, "sbom", false,
+		"Print the software bill of materials (SBOM) attached to the invocation image of the published bundle.")
+	f.StringVar(&opts.SBOMFormat, "sbom-format", "",
+		fmt.Sprintf("Format of the SBOM to print. Allowed values are: %s. Defaults to the first SBOM found.", strings.Join(sbom.AllowedFormats, ", ")))
 	addBundlePullFlags(f, &opts.BundlePullOptions)
 	return &cmd
 }
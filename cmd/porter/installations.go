@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"get.porter.sh/porter/pkg/porter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -19,9 +21,18 @@ func buildInstallationCommands(p *porter.Porter) *cobra.Command {
 
 	cmd.AddCommand(buildInstallationsListCommand(p))
 	cmd.AddCommand(buildInstallationShowCommand(p))
+	cmd.AddCommand(buildInstallationStatusCommand(p))
 	cmd.AddCommand(buildInstallationApplyCommand(p))
+	cmd.AddCommand(buildInstallationDiffCommand(p))
+	cmd.AddCommand(buildInstallationGraphCommand(p))
 	cmd.AddCommand(buildInstallationOutputsCommands(p))
+	cmd.AddCommand(buildInstallationMoveCommand(p))
+	cmd.AddCommand(buildInstallationImportCommand(p))
 	cmd.AddCommand(buildInstallationDeleteCommand(p))
+	cmd.AddCommand(buildInstallationPauseCommand(p))
+	cmd.AddCommand(buildInstallationToCRDCommand(p))
+	cmd.AddCommand(buildInstallationResumeCommand(p))
+	cmd.AddCommand(buildInstallationPruneHistoryCommand(p))
 	cmd.AddCommand(buildInstallationLogCommands(p))
 	cmd.AddCommand(buildInstallationRunsCommands(p))
 	cmd.AddCommand(buildInstallationInstallCommand(p))
@@ -34,6 +45,7 @@ func buildInstallationCommands(p *porter.Porter) *cobra.Command {
 
 func buildInstallationsListCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.ListOptions{}
+	var remote string
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -42,19 +54,29 @@ func buildInstallationsListCommand(p *porter.Porter) *cobra.Command {
 
 A listing of bundles currently installed by Porter will be provided, along with metadata such as creation time, last action, last status, etc.
 Optionally filters the results name, which returns all results whose name contain the provided query.
-The results may also be filtered by associated labels and the namespace in which the installation is defined. 
+The results may also be filtered by associated labels, the annotations of the installed bundle, and the namespace in which the installation is defined. 
 
 Optional output formats include json and yaml.`,
 		Example: `  porter installations list
   porter installations list -o json
+  porter installations list -o wide
+  porter installations list -o csv > installations.csv
+  porter installations list --columns NAME,NAMESPACE,MODIFIED --sort-by modified
   porter installations list --all-namespaces,
   porter installations list --label owner=myname --namespace dev
+  porter installations list --annotation team=platform
   porter installations list --name myapp
-  porter installations list --skip 2 --limit 2`,
+  porter installations list --skip 2 --limit 2
+  porter installations list --limit 100 --page 3
+  porter installations list --limit 100 --continue TOKEN
+  porter installations list --remote https://porter.example.com:8080`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if remote != "" {
+				return p.PrintRemoteInstallations(cmd.Context(), remote, opts)
+			}
 			return p.PrintInstallations(cmd.Context(), opts)
 		},
 	}
@@ -68,12 +90,24 @@ Optional output formats include json and yaml.`,
 		"Filter the installations where the name contains the specified substring.")
 	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
 		"Filter the installations by a label formatted as: KEY=VALUE. May be specified multiple times.")
+	f.StringSliceVar(&opts.Annotations, "annotation", nil,
+		"Filter the installations by an annotation of the installed bundle formatted as: KEY=VALUE. May be specified multiple times.")
 	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
-		"Specify an output format.  Allowed values: plaintext, json, yaml")
+		"Specify an output format.  Allowed values: plaintext, wide, csv, json, yaml")
+	f.StringSliceVar(&opts.Columns, "columns", nil,
+		"Comma separated list of the columns to print, in order, for example NAME,NAMESPACE,MODIFIED. Any column of the wide output may be selected.")
+	f.StringVar(&opts.SortBy, "sort-by", "",
+		"Name of the column used to sort the results, for example modified.")
 	f.Int64Var(&opts.Skip, "skip", 0,
 		"Skip the number of installations by a certain amount. Defaults to 0.")
 	f.Int64Var(&opts.Limit, "limit", 0,
 		"Limit the number of installations by a certain amount. Defaults to 0.")
+	f.Int64Var(&opts.Page, "page", 0,
+		"Page of installations to return, starting at 1. Requires --limit, which sets the size of each page.")
+	f.StringVar(&opts.Continue, "continue", "",
+		"Continue listing installations after the previous page, using the token printed with that page.")
+	f.StringVar(&remote, "remote", "",
+		"Address of an agent started with porter agent serve, for example https://porter.example.com:8080, that lists its installations.")
 
 	return cmd
 }
@@ -82,11 +116,17 @@ func buildInstallationShowCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.ShowOptions{}
 
 	cmd := cobra.Command{
-		Use:   "show [INSTALLATION]",
-		Short: "Show an installation of a bundle",
-		Long:  "Displays info relating to an installation of a bundle, including status and a listing of outputs.",
+		Use:               "show [INSTALLATION]",
+		ValidArgsFunction: completeInstallationNames(p),
+		Short:             "Show an installation of a bundle",
+		Long: `Displays info relating to an installation of a bundle, including status and a listing of outputs.
+
+The status, duration and error message of the most recent run are included. Use --runs to also include a history of the most recent runs.
+
+When the installation name is not specified, and there is no bundle in the current directory, you are asked to pick an installation from the namespace when run from an interactive terminal.`,
 		Example: `  porter installation show
   porter installation show another-bundle
+  porter installation show another-bundle --runs 5
 
 Optional output formats include json and yaml.
 `,
@@ -98,6 +138,44 @@ Optional output formats include json and yaml.
 		},
 	}
 
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+	f.IntVar(&opts.Runs, "runs", 0,
+		"Include a history of the specified number of most recent runs.")
+
+	return &cmd
+}
+
+func buildInstallationStatusCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.StatusOptions{}
+
+	cmd := cobra.Command{
+		Use:               "status [INSTALLATION]",
+		ValidArgsFunction: completeInstallationNames(p),
+		Short:             "Show the status of an installation",
+		Long: `Show a summary of the status of an installation, intended for automation such as CI gates and the Porter Operator.
+
+The json and yaml output is a stable document with the following fields:
+  phase: defined, running, installed, failed, uninstalled or paused
+  lastAction: the action of the most recent run
+  lastSucceededAction: the action of the most recent run that succeeded
+  drifted: true when the installation does not match its desired state, and reconciling it would execute the bundle
+  outputsDigest: a digest of the most recent outputs, which changes when any output changes
+
+Optional output formats include json and yaml.`,
+		Example: `  porter installation status
+  porter installation status myapp --namespace dev --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintInstallationStatus(cmd.Context(), opts)
+		},
+	}
+
 	f := cmd.Flags()
 	f.StringVarP(&opts.Namespace, "namespace", "n", "",
 		"Namespace in which the installation is defined. Defaults to the global namespace.")
@@ -120,10 +198,14 @@ When the namespace is not set in the file, the current namespace is used.
 
 You can use the show command to create the initial file:
   porter installation show mybuns --output yaml > mybuns.yaml
+
+Use --watch to keep reconciling the file with the installation at every --interval until the command is stopped, as a lightweight GitOps loop. The file is read again and the bundle is pulled again each time, so the bundle is executed when the file changes, or when the tag of the bundle is moved to a new digest. Failures are logged and retried at the next interval. Specify --metrics-listen to serve Prometheus metrics about the reconciliations and the actions executed, at /metrics.
 `,
 		Example: `  porter installation apply myapp.yaml
   porter installation apply myapp.yaml --dry-run
-  porter installation apply myapp.yaml --force`,
+  porter installation apply myapp.yaml --force
+  porter installation apply myapp.yaml --watch --interval 5m
+  porter installation apply myapp.yaml --watch --metrics-listen :9090`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(p.Context, args)
 		},
@@ -139,6 +221,135 @@ You can use the show command to create the initial file:
 		"Force the bundle to be executed when no changes are detected.")
 	f.BoolVar(&opts.DryRun, "dry-run", false,
 		"Evaluate if the bundle would be executed based on the changes in the file.")
+	f.BoolVar(&opts.Watch, "watch", false,
+		"Keep reconciling the file with the installation until the command is stopped.")
+	f.DurationVar(&opts.Interval, "interval", time.Minute,
+		"How often to reconcile the file when --watch is specified.")
+	f.StringVar(&opts.MetricsListen, "metrics-listen", "",
+		"Address where Prometheus metrics are served when --watch is specified, for example :9090. Metrics are not served by default.")
+	return &cmd
+}
+
+func buildInstallationDiffCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.InstallationDiffOptions{}
+
+	cmd := cobra.Command{
+		Use:   "diff FILE",
+		Short: "Compare an installation file with the installation",
+		Long: `Compare the specified installation file, as used by porter installation apply, with the installation and the parameters from its last run.
+
+Reports if applying the file would execute the bundle, which action would be run, and why.`,
+		Example: `  porter installation diff myapp.yaml
+  porter installation diff myapp.yaml --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(p.Context, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintInstallationDiff(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the namespace defined in the file.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+	return &cmd
+}
+
+func buildInstallationGraphCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.InstallationGraphOptions{}
+
+	cmd := cobra.Command{
+		Use:   "graph [INSTALLATION]",
+		Short: "Show the dependencies between installations",
+		Long: `Show the relationships between installations as a graph, so that you can see what depends on an installation before uninstalling it.
+
+An installation depends upon another installation when it was created for one of the dependencies of its bundle, or when one of its parameters is set from the output of a dependency.
+When an installation name is specified, only the installations that it depends upon, and the installations that depend upon it, are included.
+
+The graph is printed in the Graphviz DOT language by default. Optional output formats include mermaid, json and yaml.`,
+		Example: `  porter installation graph
+  porter installation graph myapp --namespace dev
+  porter installation graph --all-namespaces --output mermaid
+  porter installation graph | dot -Tsvg > installations.svg`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintInstallationGraph(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace of the installations. Defaults to the global namespace.")
+	f.BoolVar(&opts.AllNamespaces, "all-namespaces", false,
+		"Include installations in all namespaces.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "dot",
+		"Specify an output format.  Allowed values: dot, mermaid, json, yaml")
+	return &cmd
+}
+
+func buildInstallationMoveCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.MoveOptions{}
+
+	cmd := cobra.Command{
+		Use:   "move [INSTALLATION]",
+		Short: "Rename an installation or move it to another namespace",
+		Long: `Rename an installation, or move it to another namespace, without executing the bundle.
+
+The runs, results and outputs of the installation are moved with it, so its history is preserved. The installations of its dependencies are moved and renamed along with it. An installation cannot be moved while an action is running, or to another namespace when it has shared dependencies.`,
+		Example: `  porter installation move myapp --namespace dev --target-namespace prod
+  porter installation move myapp --new-name myapp-legacy
+  porter installation move myapp --namespace dev --target-namespace prod --new-name myapp-v2`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			opts.TargetNamespaceSet = cmd.Flags().Changed("target-namespace")
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.MoveInstallation(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+	f.StringVar(&opts.TargetNamespace, "target-namespace", "",
+		"Namespace to move the installation to. Defaults to the namespace in which the installation is defined.")
+	f.StringVar(&opts.NewName, "new-name", "",
+		"New name of the installation. Defaults to the current name.")
+
+	return &cmd
+}
+
+func buildInstallationImportCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ImportClaimOptions{}
+
+	cmd := cobra.Command{
+		Use:   "import FILE",
+		Short: "Import an installation from CNAB claims",
+		Long: `Import an installation from CNAB claims generated by another tool, such as cnab-go or duffle, so that the history of the installation is kept when migrating to Porter.
+
+The file may contain a single claim or an array of claims for the same installation. Each claim is imported as a run of the installation. The results of each claim may be included in the results field of the claim, and the outputs in the outputs field.
+
+Sensitive parameters and outputs, as defined by the bundle, are saved to the secret store. The bundle is not executed.`,
+		Example: `  porter installation import claims.json
+  porter installation import claim.json --namespace dev --name mysql`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ImportClaims(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which to create the installation. Defaults to the global namespace.")
+	f.StringVar(&opts.Name, "name", "",
+		"Name of the installation. Defaults to the installation name in the claims.")
+
 	return &cmd
 }
 
@@ -146,12 +357,19 @@ func buildInstallationDeleteCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.DeleteOptions{}
 
 	cmd := cobra.Command{
-		Use:   "delete [INSTALLATION]",
-		Short: "Delete an installation",
-		Long:  "Deletes all records and outputs associated with an installation",
+		Use:               "delete [INSTALLATION]",
+		ValidArgsFunction: completeInstallationNames(p),
+		Short:             "Delete an installation",
+		Long: `Deletes all records and outputs associated with an installation.
+
+Use --force to delete an installation when the last action was not a successful uninstall, for example when the last run is stuck in the running state because porter was interrupted.
+Use --allow-protected to delete an installation from a protected namespace.
+Use --cleanup-orphans to also remove runs, results and outputs that were left behind by a previous delete, even when the installation no longer exists.`,
 		Example: `  porter installation delete
   porter installation delete wordpress
   porter installation delete --force
+  porter installation delete wordpress --force --cleanup-orphans
+  porter installation delete wordpress --namespace prod --allow-protected
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args, p.Context)
@@ -166,6 +384,99 @@ func buildInstallationDeleteCommand(p *porter.Porter) *cobra.Command {
 		"Namespace in which the installation is defined. Defaults to the global namespace.")
 	f.BoolVar(&opts.Force, "force", false,
 		"Force a delete the installation, regardless of last completed action")
+	f.BoolVar(&opts.AllowProtected, "allow-protected", false,
+		"Allow the installation to be deleted when its namespace is protected")
+	f.BoolVar(&opts.CleanupOrphans, "cleanup-orphans", false,
+		"Remove all runs, results and outputs that reference the installation, even if the installation was already deleted")
+
+	return &cmd
+}
+
+func buildInstallationPauseCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.PauseOptions{}
+
+	cmd := cobra.Command{
+		Use:   "pause [INSTALLATION]",
+		Short: "Pause reconciliation of an installation",
+		Long: `Pause reconciliation of an installation.
+
+When an installation is paused, porter installation apply and the Porter Operator save changes to the installation but do not execute the bundle. Use porter installation resume to resume reconciliation of the installation.`,
+		Example: `  porter installation pause
+  porter installation pause wordpress --namespace dev
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PauseInstallation(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+
+	return &cmd
+}
+
+func buildInstallationResumeCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.PauseOptions{}
+
+	cmd := cobra.Command{
+		Use:   "resume [INSTALLATION]",
+		Short: "Resume reconciliation of an installation",
+		Long: `Resume reconciliation of an installation that was paused with porter installation pause.
+
+Changes applied to the installation while it was paused are reconciled the next time the installation is applied.`,
+		Example: `  porter installation resume
+  porter installation resume wordpress --namespace dev
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ResumeInstallation(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+
+	return &cmd
+}
+
+func buildInstallationPruneHistoryCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.PruneHistoryOptions{}
+
+	cmd := cobra.Command{
+		Use:   "prune-history [INSTALLATION]",
+		Short: "Remove old runs of an installation",
+		Long: `Remove old runs of an installation, along with their results, outputs and logs.
+
+The most recent run, and the runs that produced the current value of each output, are always kept.
+When --max-runs and --max-age are not specified, the history.max-runs and history.max-age settings from the Porter configuration file are used. When they are defined, the policy is also applied automatically after each action.`,
+		Example: `  porter installation prune-history wordpress --max-runs 10
+  porter installation prune-history wordpress --max-age 30d --dry-run
+  porter installation prune-history --namespace dev
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PruneInstallationHistory(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+	f.IntVar(&opts.MaxRuns, "max-runs", 0,
+		"Maximum number of runs to keep. Defaults to history.max-runs from the Porter configuration file.")
+	f.StringVar(&opts.MaxAge, "max-age", "",
+		"Maximum age of the runs to keep, for example 720h or 30d. Defaults to history.max-age from the Porter configuration file.")
+	f.BoolVar(&opts.DryRun, "dry-run", false,
+		"List the runs that would be removed without removing them.")
 
 	return &cmd
 }
@@ -179,6 +490,7 @@ func buildInstallationRunsCommands(p *porter.Porter) *cobra.Command {
 	}
 
 	cmd.AddCommand(buildInstallationRunsListCommand(p))
+	cmd.AddCommand(buildInstallationRunShowCommand(p))
 
 	return cmd
 }
@@ -193,6 +505,7 @@ func buildInstallationRunsListCommand(p *porter.Porter) *cobra.Command {
 		Example: `  porter installation runs list [NAME] [--namespace NAMESPACE] [--output FORMAT]
 
   porter installations runs list --name myapp --namespace dev
+  porter installations runs list myapp --columns run-id,action,status --sort-by action
 
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -206,6 +519,37 @@ func buildInstallationRunsListCommand(p *porter.Porter) *cobra.Command {
 	f := cmd.Flags()
 	f.StringVarP(&opts.Namespace, "namespace", "n", "",
 		"Namespace in which the installation is defined. Defaults to the global namespace.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, wide, csv, json, yaml")
+	f.StringSliceVar(&opts.Columns, "columns", nil,
+		"Comma separated list of the columns to print, in order, for example NAME,NAMESPACE,MODIFIED. Any column of the wide output may be selected.")
+	f.StringVar(&opts.SortBy, "sort-by", "",
+		"Name of the column used to sort the results, for example modified.")
+
+	return &cmd
+}
+
+func buildInstallationRunShowCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.RunShowOptions{}
+
+	cmd := cobra.Command{
+		Use:   "show RUN_ID",
+		Short: "Show a run of an Installation",
+		Long: `Show a run of an Installation, including how long each step of the bundle took to execute.
+
+Step durations are only available for bundles that set metrics to true in porter.yaml.`,
+		Example: `  porter installation runs show 01FZVC5AVP8Z7A78CSCP1EJ604
+  porter installation runs show 01FZVC5AVP8Z7A78CSCP1EJ604 --output json
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintInstallationRun(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
 	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
 		"Specify an output format.  Allowed values: plaintext, json, yaml")
 
@@ -239,6 +583,8 @@ The docker driver runs the bundle container using the local Docker host. To use
   porter installation install --credential-set azure --credential-set kubernetes
   porter installation install --driver debug
   porter installation install --label env=dev --label owner=myuser
+  porter installation install --reference ghcr.io/getporter/examples/kubernetes:v0.2.0 --dry-run
+  porter installation install --wait --wait-timeout 15m
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(cmd.Context(), args, p)
@@ -257,23 +603,30 @@ The docker driver runs the bundle container using the local Docker host. To use
 		"Create the installation in the specified namespace. Defaults to the global namespace.")
 	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
 		"Associate the specified labels with the installation. May be specified multiple times.")
+	f.BoolVar(&opts.DryRun, "dry-run", false,
+		"Resolve the bundle, its dependencies, parameters and credentials, and print the execution plan without saving the installation or running the bundle.")
 	addBundleActionFlags(f, opts)
+	addBundleActionCompletions(p, cmd)
+	addWaitFlags(f, &opts.WaitOptions)
 
 	// Allow configuring the --driver flag with runtime-driver, to avoid conflicts with other commands
 	cmd.Flag("driver").Annotations = map[string][]string{
 		"viper-key": {"runtime-driver"},
 	}
+	addRemoteActionFlag(p, cmd, "install")
 	return cmd
 }
 
 func buildInstallationUpgradeCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.NewUpgradeOptions()
 	cmd := &cobra.Command{
-		Use:   "upgrade [INSTALLATION]",
-		Short: "Upgrade an installation",
+		Use:               "upgrade [INSTALLATION]",
+		ValidArgsFunction: completeInstallationNames(p),
+		Short:             "Upgrade an installation",
 		Long: `Upgrade an installation.
 
 The first argument is the installation name to upgrade. This defaults to the name of the bundle.
+Use --all to upgrade every installation in the namespace that matches --selector instead.
 
 Porter uses the docker driver as the default runtime for executing a bundle's invocation image, but an alternate driver may be supplied via '--driver/-d' or the PORTER_RUNTIME_DRIVER environment variable.
 For example, the 'debug' driver may be specified, which simply logs the info given to it and then exits.
@@ -290,6 +643,9 @@ The docker driver runs the bundle container using the local Docker host. To use
   porter installation upgrade --parameter-set azure --param test-mode=true --param header-color=blue
   porter installation upgrade --credential-set azure --credential-set kubernetes
   porter installation upgrade --driver debug
+  porter installation upgrade --all --selector app=web --namespace dev --max-concurrency 3
+  porter installation upgrade --version 0.2.0 --dry-run
+  porter installation upgrade --version 0.2.0 --wait
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(cmd.Context(), args, p)
@@ -308,23 +664,31 @@ The docker driver runs the bundle container using the local Docker host. To use
 		"Namespace of the specified installation. Defaults to the global namespace.")
 	f.StringVar(&opts.Version, "version", "",
 		"Version to which the installation should be upgraded. This represents the version of the bundle, which assumes the convention of setting the bundle tag to its version.")
+	f.BoolVar(&opts.DryRun, "dry-run", false,
+		"Resolve the bundle, its dependencies, parameters and credentials, and print the execution plan without updating the installation or running the bundle.")
 	addBundleActionFlags(f, opts)
+	addBundleActionCompletions(p, cmd)
+	addBulkActionFlags(f, &opts.BulkOptions)
+	addWaitFlags(f, &opts.WaitOptions)
 
 	// Allow configuring the --driver flag with runtime-driver, to avoid conflicts with other commands
 	cmd.Flag("driver").Annotations = map[string][]string{
 		"viper-key": {"runtime-driver"},
 	}
+	addRemoteActionFlag(p, cmd, "upgrade")
 	return cmd
 }
 
 func buildInstallationInvokeCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.NewInvokeOptions()
 	cmd := &cobra.Command{
-		Use:   "invoke [INSTALLATION] --action ACTION",
-		Short: "Invoke a custom action on an installation",
+		Use:               "invoke [INSTALLATION] --action ACTION",
+		ValidArgsFunction: completeInstallationNames(p),
+		Short:             "Invoke a custom action on an installation",
 		Long: `Invoke a custom action on an installation.
 
 The first argument is the installation name upon which to invoke the action. This defaults to the name of the bundle.
+Use --all to invoke the action on every installation in the namespace that matches --selector instead.
 
 Porter uses the docker driver as the default runtime for executing a bundle's invocation image, but an alternate driver may be supplied via '--driver/-d' or the PORTER_RUNTIME_DRIVER environment variable.
 For example, the 'debug' driver may be specified, which simply logs the info given to it and then exits.
@@ -341,6 +705,8 @@ The docker driver runs the bundle container using the local Docker host. To use
   porter installation invoke --action ACTION  --parameter-set azure --param test-mode=true --param header-color=blue
   porter installation invoke --action ACTION --credential-set azure --credential-set kubernetes
   porter installation invoke --action ACTION --driver debug
+  porter installation invoke --action ACTION --all --selector app=web --namespace dev
+  porter installation invoke --action backup --arg target=s3://mybucket/backups
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(cmd.Context(), args, p)
@@ -353,6 +719,8 @@ The docker driver runs the bundle container using the local Docker host. To use
 	f := cmd.Flags()
 	f.StringVar(&opts.Action, "action", "",
 		"Custom action name to invoke.")
+	f.StringArrayVar(&opts.Arguments, "arg", nil,
+		"Define an argument of the custom action in the form NAME=VALUE. Arguments only apply to this invocation and are not saved on the installation. May be specified multiple times.")
 	f.StringVarP(&opts.File, "file", "f", "",
 		"Path to the porter manifest file. Defaults to the bundle in the current directory.")
 	f.StringVar(&opts.CNABFile, "cnab-file", "",
@@ -360,22 +728,27 @@ The docker driver runs the bundle container using the local Docker host. To use
 	f.StringVarP(&opts.Namespace, "namespace", "n", "",
 		"Namespace of the specified installation. Defaults to the global namespace.")
 	addBundleActionFlags(f, opts)
+	addBundleActionCompletions(p, cmd)
+	addBulkActionFlags(f, &opts.BulkOptions)
 
 	// Allow configuring the --driver flag with runtime-driver, to avoid conflicts with other commands
 	cmd.Flag("driver").Annotations = map[string][]string{
 		"viper-key": {"runtime-driver"},
 	}
+	addRemoteActionFlag(p, cmd, "invoke")
 	return cmd
 }
 
 func buildInstallationUninstallCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.NewUninstallOptions()
 	cmd := &cobra.Command{
-		Use:   "uninstall [INSTALLATION]",
-		Short: "Uninstall an installation",
+		Use:               "uninstall [INSTALLATION]",
+		ValidArgsFunction: completeInstallationNames(p),
+		Short:             "Uninstall an installation",
 		Long: `Uninstall an installation
 
 The first argument is the installation name to uninstall. This defaults to the name of the bundle.
+Use --all to uninstall every installation in the namespace that matches --selector instead.
 
 Porter uses the docker driver as the default runtime for executing a bundle's invocation image, but an alternate driver may be supplied via '--driver/-d'' or the PORTER_RUNTIME_DRIVER environment variable.
 For example, the 'debug' driver may be specified, which simply logs the info given to it and then exits.
@@ -394,6 +767,9 @@ The docker driver runs the bundle container using the local Docker host. To use
   porter installation uninstall --driver debug
   porter installation uninstall --delete
   porter installation uninstall --force-delete
+  porter installation uninstall --delete --namespace prod --allow-protected
+  porter installation uninstall --all --selector env=test --namespace dev
+  porter installation uninstall --all --namespace test --yes
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(cmd.Context(), args, p)
@@ -412,23 +788,49 @@ The docker driver runs the bundle container using the local Docker host. To use
 		"Delete all records associated with the installation, assuming the uninstall action succeeds")
 	f.BoolVar(&opts.ForceDelete, "force-delete", false,
 		"UNSAFE. Delete all records associated with the installation, even if uninstall fails. This is intended for cleaning up test data and is not recommended for production environments.")
+	f.BoolVar(&opts.AllowProtected, "allow-protected", false,
+		"Allow the installation to be deleted with --delete or --force-delete when its namespace is protected")
 	f.StringVarP(&opts.Namespace, "namespace", "n", "",
 		"Namespace of the specified installation. Defaults to the global namespace.")
 	addBundleActionFlags(f, opts)
+	addBundleActionCompletions(p, cmd)
+	addBulkActionFlags(f, &opts.BulkOptions)
+	f.BoolVar(&opts.Confirmed, "yes", false,
+		"Confirm that --all without --selector should uninstall every installation in the namespace.")
 
 	// Allow configuring the --driver flag with runtime-driver, to avoid conflicts with other commands
 	cmd.Flag("driver").Annotations = map[string][]string{
 		"viper-key": {"runtime-driver"},
 	}
+	addRemoteActionFlag(p, cmd, "uninstall")
 	return cmd
 }
 
+// Add flags for commands that run a bundle against multiple installations (upgrade, invoke and uninstall)
+func addBulkActionFlags(f *pflag.FlagSet, opts *porter.BulkOptions) {
+	f.BoolVar(&opts.All, "all", false,
+		"Run the action against every installation in the namespace that matches --selector, instead of a single installation.")
+	f.StringSliceVar(&opts.Selector, "selector", nil,
+		"Select installations with the specified label, in the form KEY=VALUE, when --all is specified. May be specified multiple times.")
+	f.IntVar(&opts.MaxConcurrency, "max-concurrency", 1,
+		"Maximum number of installations to run at the same time when --all is specified.")
+}
+
+func addWaitFlags(f *pflag.FlagSet, opts *porter.WaitOptions) {
+	f.BoolVar(&opts.Wait, "wait", false,
+		"Wait for the installation to be ready after the action succeeds, by invoking the status action of the bundle until its ready output is true, or the action succeeds when the bundle does not define a ready output.")
+	f.DurationVar(&opts.WaitTimeout, "wait-timeout", 0,
+		"How long to wait for the installation to be ready when --wait is specified, for example 15m. Defaults to 10m.")
+}
+
 // Add flags for command that execute a bundle (install, upgrade, invoke and uninstall)
 func addBundleActionFlags(f *pflag.FlagSet, actionOpts porter.BundleAction) {
 	opts := actionOpts.GetOptions()
 	addBundlePullFlags(f, &opts.BundlePullOptions)
 	f.BoolVar(&opts.AllowDockerHostAccess, "allow-docker-host-access", false,
 		"Controls if the bundle should have access to the host's Docker daemon with elevated privileges. See https://getporter.org/configuration/#allow-docker-host-access for the full implications of this flag.")
+	f.BoolVar(&opts.AllowHooks, "allow-hooks", false,
+		"Run the pre and post hooks defined by the bundle. Hooks are commands that run on this host, outside of the bundle, so only allow hooks for bundles that you trust.")
 	f.BoolVar(&opts.NoLogs, "no-logs", false,
 		"Do not persist the bundle execution logs")
 	f.StringArrayVarP(&opts.ParameterSets, "parameter-set", "p", nil,
@@ -438,11 +840,59 @@ func addBundleActionFlags(f *pflag.FlagSet, actionOpts porter.BundleAction) {
 	f.StringArrayVarP(&opts.CredentialIdentifiers, "credential-set", "c", nil,
 		"Credential sets to use when running the bundle. It should be a named set of credentials and may be specified multiple times.")
 	f.StringVarP(&opts.Driver, "driver", "d", porter.DefaultDriver,
-		"Specify a driver to use. Allowed values: docker, debug")
+		"Specify a driver to use. Allowed values: docker, podman, containerd, kubernetes, debug")
+	f.StringArrayVar(&opts.DriverOptions, "driver-opt", nil,
+		"Configure the invocation container run by the docker driver in the form KEY=VALUE. Allowed keys: cpus, memory, network, mount (SOURCE:TARGET[:ro]), env (NAME of an environment variable to pass through). Overrides the docker settings in the config file. May be specified multiple times.")
+	f.StringVar(&opts.RelocationMapping, "relocation-mapping", "",
+		"Path to a relocation mapping file, such as the one written by porter copy --relocation-output, that maps the images referenced by the bundle to their location in another registry.")
 	f.BoolVar(&opts.DebugMode, "debug", false,
 		"Run the bundle in debug mode.")
+	f.StringVar(&opts.LogFormat, "log-format", "text",
+		"Format of the output generated by the bundle. Allowed values: text, json. Use json to write each line of output as a JSON record labeled with a timestamp, the step and the stream, which is also how the logs are persisted.")
+	f.IntVar(&opts.Retries, "retries", 0,
+		"Number of times a failed step is retried before the action fails. Overrides the retry policy defined in the bundle.")
+	f.DurationVar(&opts.RetryBackoff, "retry-backoff", 0,
+		"How long to wait before retrying a failed step, for example 30s. The wait is doubled after each retry. Overrides the retry policy defined in the bundle, which defaults to 10s.")
+	f.DurationVar(&opts.Timeout, "timeout", 0,
+		"How long the action may run before it is stopped and the cleanup steps defined in the bundle are executed, for example 30m. Overrides the timeout policy defined in the bundle.")
+	f.IntVar(&opts.DependencyParallelism, "dependency-parallelism", 1,
+		"Maximum number of dependencies that are executed at the same time. Dependencies that use the outputs of another dependency are executed after it.")
+	f.DurationVar(&opts.WaitForLock, "wait-for-lock", 0,
+		"How long to wait for another action that is running against the installation to complete, for example 5m. Defaults to failing immediately when the installation is locked.")
 
 	// Gracefully support any renamed flags
 	f.StringArrayVar(&opts.CredentialIdentifiers, "cred", nil, "DEPRECATED")
 	f.MarkDeprecated("cred", "please use credential-set instead.")
 }
+
+func buildInstallationToCRDCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.CRDOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "to-crd NAME",
+		Short: "Print an installation as a Porter Operator custom resource",
+		Long: `Print an installation as a Installation custom resource of the Porter Operator, so that it can be migrated from the CLI to the operator with kubectl apply.
+
+Parameters that are stored in a secret, such as sensitive parameters, are not included because the operator only accepts plaintext parameters on an installation. Move them to a parameter set that resolves them from a Kubernetes secret. The credential and parameter sets of the installation must also be migrated, with porter credentials to-crd and porter parameters to-crd.
+`,
+		Example: `  porter installation to-crd mybuns
+  porter installation to-crd mybuns --namespace dev --k8s-namespace porter-dev
+  porter installation to-crd mybuns --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.InstallationToCRD(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+	f.StringVar(&opts.KubernetesNamespace, "k8s-namespace", "",
+		"Kubernetes namespace of the custom resource. Defaults to the namespace selected when the resource is applied.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "yaml",
+		"Specify an output format.  Allowed values: yaml, json")
+
+	return cmd
+}
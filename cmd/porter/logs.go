@@ -23,19 +23,27 @@ func buildInstallationLogCommands(p *porter.Porter) *cobra.Command {
 
 func buildInstallationLogShowCommand(p *porter.Porter) *cobra.Command {
 	opts := &porter.LogsShowOptions{}
+	var remote string
 
 	cmd := &cobra.Command{
 		Use:   "show",
 		Short: "Show the logs from an installation",
 		Long: `Show the logs from an installation.
 
-Either display the logs from a specific run of a bundle with --run, or use --installation to display the logs from its most recent run.`,
+Either display the logs from a specific run of a bundle with --run, or use --installation to display the logs from its most recent run.
+
+Use --follow to stream the logs of a run that is in progress, for example when the bundle is executing in another terminal or by the Porter Operator, until the run completes.`,
 		Example: `  porter installation logs show --installation wordpress --namespace dev
-  porter installations logs show --run 01EZSWJXFATDE24XDHS5D5PWK6`,
+  porter installations logs show --run 01EZSWJXFATDE24XDHS5D5PWK6
+  porter installation logs show --installation wordpress --follow
+  porter installation logs show --installation wordpress --remote https://porter.example.com:8080`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(p.Context)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if remote != "" {
+				return p.ShowRemoteInstallationLogs(cmd.Context(), remote, opts)
+			}
 			return p.ShowInstallationLogs(cmd.Context(), opts)
 		},
 	}
@@ -47,6 +55,10 @@ Either display the logs from a specific run of a bundle with --run, or use --ins
 		"The installation that generated the logs.")
 	f.StringVarP(&opts.RunID, "run", "r", "",
 		"The bundle run that generated the logs.")
+	f.BoolVarP(&opts.Follow, "follow", "f", false,
+		"Stream the logs of the run until it completes.")
+	f.StringVar(&remote, "remote", "",
+		"Address of an agent started with porter agent serve, for example https://porter.example.com:8080, that executed the run.")
 
 	return cmd
 }
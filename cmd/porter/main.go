@@ -12,6 +12,8 @@ import (
 	"get.porter.sh/porter/pkg/cli"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/porter"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.opentelemetry.io/otel/attribute"
@@ -62,6 +64,8 @@ func main() {
 		}
 
 		ctx, log := p.StartRootSpan(ctx, commandName, attribute.String("command", formattedCommand))
+		// Record the command in the audit log, without its arguments which may contain sensitive data
+		ctx = storage.WithCommand(ctx, commandName)
 		defer func() {
 			// Capture panics and trace them
 			if panicErr := recover(); panicErr != nil {
@@ -79,8 +83,13 @@ func main() {
 
 		if err := rootCmd.ExecuteContext(ctx); err != nil {
 			// Ideally we log all errors in the span that generated it,
-			// but as a failsafe, always log the error at the root span as well
-			log.Error(err)
+			// but as a failsafe, always log the error at the root span as well.
+			// Use the logger configured with the command's flags, such as --log-format.
+			var errLog tracing.TraceLogger = log
+			if cmd != nil && cmd.Context() != nil {
+				errLog = tracing.LoggerFromContext(cmd.Context())
+			}
+			errLog.Error(err)
 			return cli.ExitCodeErr
 		}
 		return cli.ExitCodeSuccess
@@ -210,6 +219,14 @@ Try our QuickStart https://getporter.org/quickstart to learn how to use Porter.
 	// These flags are available for every command
 	globalFlags := cmd.PersistentFlags()
 	globalFlags.StringVar(&p.Data.Verbosity, "verbosity", config.DefaultVerbosity, "Threshold for printing messages to the console. Available values are: debug, info, warning, error.")
+	globalFlags.StringVar(&p.Data.Logs.Format, "log-format", config.LogFormatPlaintext, "Format of the messages printed to the console. Available values are: plaintext, json.")
+	// Allow configuring the --log-format flag with logs.format, alongside the other log settings
+	globalFlags.Lookup("log-format").Annotations = map[string][]string{
+		"viper-key": {"logs.format"},
+	}
+	globalFlags.BoolVarP(&p.Data.Quiet, "quiet", "q", false, "Suppress informational messages, only printing errors and the requested output. Overrides --verbosity.")
+	globalFlags.BoolVar(&p.Data.Offline, "offline", false, "Do not access registries or feeds, resolving bundles from the local bundle cache. Commands that need the network fail instead.")
+	globalFlags.BoolVar(&p.Data.NoColor, "no-color", false, "Disable colored output. Colors are also disabled when the NO_COLOR environment variable is set, or when the output is not a terminal.")
 	globalFlags.StringSliceVar(&p.Data.ExperimentalFlags, "experimental", nil, "Comma separated list of experimental features to enable. See https://getporter.org/configuration/#experimental-feature-flags for available feature flags.")
 
 	// Flags for just the porter command only, does not apply to sub-commands
@@ -217,6 +234,8 @@ Try our QuickStart https://getporter.org/quickstart to learn how to use Porter.
 
 	cmd.AddCommand(buildVersionCommand(p))
 	cmd.AddCommand(buildSchemaCommand(p))
+	cmd.AddCommand(buildManifestCommands(p))
+	cmd.AddCommand(buildTemplateCommands(p))
 	cmd.AddCommand(buildStorageCommand(p))
 	cmd.AddCommand(buildRunCommand(p))
 	cmd.AddCommand(buildBundleCommands(p))
@@ -225,6 +244,13 @@ Try our QuickStart https://getporter.org/quickstart to learn how to use Porter.
 	cmd.AddCommand(buildPluginsCommands(p))
 	cmd.AddCommand(buildCredentialsCommands(p))
 	cmd.AddCommand(buildParametersCommands(p))
+	cmd.AddCommand(buildNamespacesCommands(p))
+	cmd.AddCommand(buildAuditCommands(p))
+	cmd.AddCommand(buildCacheCommands(p))
+	cmd.AddCommand(buildSchedulerCommands(p))
+	cmd.AddCommand(buildConfigCommands(p))
+	cmd.AddCommand(buildDiagnosticsCommand(p))
+	cmd.AddCommand(buildAgentCommands(p))
 	cmd.AddCommand(buildCompletionCommand(p))
 
 	for _, alias := range buildAliasCommands(p) {
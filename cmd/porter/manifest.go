@@ -0,0 +1,52 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildManifestCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Manifest commands",
+		Long:  "Commands for working with a porter manifest, porter.yaml, without building the bundle.",
+		Annotations: map[string]string{
+			"group": "meta",
+		},
+	}
+
+	cmd.AddCommand(buildManifestValidateCommand(p))
+
+	return cmd
+}
+
+func buildManifestValidateCommand(p *porter.Porter) *cobra.Command {
+	var opts porter.ManifestValidateOptions
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a porter manifest",
+		Long: `Validate a porter manifest against the manifest schema, including the schemas of the mixins used by the bundle.
+
+The manifest is also checked for problems that the schema does not detect, such as templates that use undeclared parameters, credentials, images or dependencies, steps with duplicate descriptions, and steps that use a mixin that is not declared.
+
+Each problem is reported with its line and column in the manifest. The bundle is not built, so Docker is not required.`,
+		Example: `  porter manifest validate
+  porter manifest validate --file path/to/porter.yaml
+  porter manifest validate --output json
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintManifestValidationResults(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.File, "file", "f", "",
+		"Path to the porter manifest file. Defaults to the bundle in the current directory.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json")
+
+	return cmd
+}
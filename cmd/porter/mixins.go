@@ -19,9 +19,14 @@ func buildMixinCommands(p *porter.Porter) *cobra.Command {
 	}
 
 	cmd.AddCommand(buildMixinsListCommand(p))
+	cmd.AddCommand(buildMixinShowCommand(p))
 	cmd.AddCommand(buildMixinsSearchCommand(p))
 	cmd.AddCommand(BuildMixinInstallCommand(p))
 	cmd.AddCommand(BuildMixinUninstallCommand(p))
+	cmd.AddCommand(buildMixinsEnsureCommand(p))
+	cmd.AddCommand(buildMixinsPublishCommand(p))
+	cmd.AddCommand(buildMixinsOutdatedCommand(p))
+	cmd.AddCommand(buildMixinsUpgradeCommand(p))
 	cmd.AddCommand(buildMixinsFeedCommand(p))
 	cmd.AddCommand(buildMixinsCreateCommand(p))
 
@@ -48,6 +53,31 @@ func buildMixinsListCommand(p *porter.Porter) *cobra.Command {
 	return cmd
 }
 
+func buildMixinShowCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ShowMixinOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "show NAME",
+		Short: "Show details about an installed mixin",
+		Long: `Show details about an installed mixin.
+
+The details include the version of the mixin, where it was installed from, when it was installed, the path to its binary, and the versions of the mixin protocol that it supports.`,
+		Example: `  porter mixin show exec
+  porter mixin show helm3 --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ShowMixin(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Output format, allowed values are: plaintext, json, yaml")
+
+	return cmd
+}
+
 func buildMixinsSearchCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.SearchOptions{
 		Type: "mixin",
@@ -89,7 +119,8 @@ func BuildMixinInstallCommand(p *porter.Porter) *cobra.Command {
 By default mixins are downloaded from the official Porter mixin feed at https://cdn.porter.sh/mixins/atom.xml. To download from a mirror, set the environment variable PORTER_MIRROR, or mirror in the Porter config file, with the value to replace https://cdn.porter.sh with.`,
 		Example: `  porter mixin install helm3 --feed-url https://mchorfa.github.io/porter-helm3/atom.xml
   porter mixin install azure --version v0.4.0-ralpha.1+dubonnet --url https://cdn.porter.sh/mixins/azure
-  porter mixin install kubernetes --version canary --url https://cdn.porter.sh/mixins/kubernetes`,
+  porter mixin install kubernetes --version canary --url https://cdn.porter.sh/mixins/kubernetes
+  porter mixin install terraform --source oci://ghcr.io/getporter/mixins/terraform:v1.2.3`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args)
 		},
@@ -105,6 +136,10 @@ By default mixins are downloaded from the official Porter mixin feed at https://
 		"URL from where the mixin can be downloaded, for example https://github.com/org/proj/releases/downloads")
 	flags.StringVar(&opts.FeedURL, "feed-url", "",
 		"URL of an atom feed where the mixin can be downloaded. Defaults to the official Porter mixin feed.")
+	flags.StringVar(&opts.Source, "source", "",
+		"OCI reference to the mixin in a registry, for example oci://ghcr.io/org/mixins/NAME:v1.0.0. The mixin is installed from the registry instead of a feed.")
+	flags.BoolVar(&opts.InsecureRegistry, "insecure-registry", false,
+		"Don't require TLS when installing the mixin from --source")
 	flags.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
 		"Mirror of official Porter assets")
 	return cmd
@@ -127,6 +162,46 @@ func BuildMixinUninstallCommand(p *porter.Porter) *cobra.Command {
 	return cmd
 }
 
+func buildMixinsEnsureCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.EnsureMixinsOptions{}
+	cmd := &cobra.Command{
+		Use:   "ensure",
+		Short: "Install the mixins used by a bundle",
+		Long: `Install the mixins declared in the porter manifest, and record their exact versions and digests in porter.lock.
+
+A mixin may declare the version that the bundle requires with a semver constraint:
+
+mixins:
+  - exec
+  - name: terraform
+    version: 1.0.x
+
+When porter.lock exists in the same directory as the porter manifest, the versions recorded in the lock file are installed, so that the bundle is built with the same mixins on every machine. Commit porter.lock with your bundle, and use --update to select new versions of the mixins after changing the declared versions.
+
+By default mixins are downloaded from the official Porter mixin feed at https://cdn.porter.sh/mixins/atom.xml. To download from a mirror, set the environment variable PORTER_MIRROR, or mirror in the Porter config file, with the value to replace https://cdn.porter.sh with.`,
+		Example: `  porter mixins ensure
+  porter mixins ensure --file path/to/porter.yaml
+  porter mixins ensure --update`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.EnsureMixins(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.File, "file", "f", "",
+		"Path to the porter manifest file. Defaults to the bundle in the current directory.")
+	flags.BoolVar(&opts.Update, "update", false,
+		"Ignore the versions recorded in porter.lock and select the versions of the mixins again.")
+	flags.StringVar(&opts.FeedURL, "feed-url", "",
+		"URL of an atom feed where the mixins can be downloaded. Defaults to the official Porter mixin feed.")
+	flags.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
+		"Mirror of official Porter assets")
+	return cmd
+}
+
 func buildMixinsFeedCommand(p *porter.Porter) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "feed",
@@ -202,18 +277,23 @@ func buildMixinsCreateCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.MixinsCreateOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "create NAME --author \"My Name\" --username mygithubusername [--dir /path/to/mixin/dir]",
-		Short: "Create a new mixin project based on the getporter/skeletor repository",
-		Long: `Create a new mixin project based on the getporter/skeletor repository.
+		Use:   "create NAME --author \"My Name\" [--username mygithubusername | --module example.com/mymixin] [--dir /path/to/mixin/dir]",
+		Short: "Create a new mixin project",
+		Long: `Create a new mixin project.
 The first argument is the name of the mixin to create and is required.
 
+The project contains the commands called by Porter: build, schema, version, lint, install, upgrade, uninstall and invoke.
+It also has unit tests, an example feed template, and a magefile with targets to build, test, install, cross-compile,
+generate a feed for and publish the mixin.
+
 A flag of --author to declare the author of the mixin is a required input.
-A flag of --username to specify the GitHub's username of the mixin's author is a required input.
+A flag of --username to specify the GitHub username of the mixin's author, or --module to specify the Go module path of the mixin, is required.
+The module path defaults to github.com/USERNAME/NAME.
 
 You can also specify where to put the mixin directory. It will default to the current directory.`,
-		Example: ` porter mixin create MyMixin --author "My Name" --username mygithubusername
-		porter mixin create MyMixin --author "My Name" --username mygithubusername --dir path/to/mymixin
-		`,
+		Example: `  porter mixin create MyMixin --author "My Name" --username mygithubusername
+  porter mixin create MyMixin --author "My Name" --username mygithubusername --dir path/to/mymixin
+  porter mixin create MyMixin --author "My Name" --module example.com/mixins/mymixin`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args, p.Context)
 		},
@@ -225,7 +305,108 @@ You can also specify where to put the mixin directory. It will default to the cu
 	f := cmd.Flags()
 	f.StringVar(&opts.AuthorName, "author", "", "Your full name.")
 	f.StringVar(&opts.AuthorUsername, "username", "", "Your GitHub username.")
+	f.StringVar(&opts.ModulePath, "module", "", "The Go module path of the mixin. Defaults to github.com/USERNAME/NAME.")
 	f.StringVar(&opts.DirPath, "dir", "", "Path to the designated location of the mixin's directory.")
 
 	return cmd
 }
+
+func buildMixinsPublishCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.PublishPackageOptions{
+		Type: "mixin",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "publish NAME",
+		Short: "Publish a mixin to a registry",
+		Long: `Publish the binaries of a mixin to a registry as an OCI artifact, so that it can be installed with porter mixin install --source.
+
+The file names of the binaries must follow the naming conventions required of published mixins:
+
+NAME-GOOS-GOARCH[FILE_EXT]
+
+The linux/amd64 binary is required because it is used in the invocation image. The binaries are stored in the artifact with their digests, which are verified when the mixin is installed.`,
+		Example: `  porter mixin publish NAME --reference oci://ghcr.io/org/mixins/NAME:v1.0.0
+  porter mixin publish NAME --dir bin/mixins/NAME/v1.0.0 --reference ghcr.io/org/mixins/NAME:v1.0.0
+  porter mixin publish NAME --reference localhost:5000/mixins/NAME:canary --version v1.1.0-canary --insecure-registry`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PublishPackage(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.Reference, "reference", "r", "",
+		"OCI reference to which the mixin is published, for example oci://ghcr.io/org/mixins/NAME:v1.0.0")
+	flags.StringVarP(&opts.Dir, "dir", "d", "",
+		"The directory that contains the mixin binaries. Defaults to the current directory.")
+	flags.StringVarP(&opts.Version, "version", "v", "",
+		"The mixin version. Defaults to the tag of the reference.")
+	flags.BoolVar(&opts.InsecureRegistry, "insecure-registry", false,
+		"Don't require TLS for the registry")
+	return cmd
+}
+
+func buildMixinsOutdatedCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.OutdatedPackagesOptions{}
+	opts.PackageType = "mixin"
+
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "List outdated mixins",
+		Long: `List the installed mixins that have a newer version published in their feeds.
+
+By default each mixin is compared to the feed from which it was installed, or the official Porter mixin feed at https://cdn.porter.sh/mixins/atom.xml. To check a mirror, set the environment variable PORTER_MIRROR, or mirror in the Porter config file, with the value to replace https://cdn.porter.sh with.`,
+		Example: `  porter mixin outdated
+  porter mixin outdated --feed-url https://cdn.porter.sh/mixins/atom.xml
+  porter mixin outdated -o json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintOutdatedPackages(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Output format, allowed values are: plaintext, json, yaml")
+	flags.StringVar(&opts.FeedURL, "feed-url", "",
+		"URL of an atom feed where the mixins are published. Defaults to the feed from which each mixin was installed.")
+	flags.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
+		"Mirror of official Porter assets")
+	return cmd
+}
+
+func buildMixinsUpgradeCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.UpgradePackagesOptions{}
+	opts.PackageType = "mixin"
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [NAME...]",
+		Short: "Upgrade mixins",
+		Long: `Upgrade the installed mixins to the latest version published in their feeds.
+
+Specify the names of the mixins to upgrade, or --all to upgrade every outdated mixin. The mixins that are upgraded are listed with their current and latest versions.`,
+		Example: `  porter mixin upgrade --all
+  porter mixin upgrade helm3 terraform
+  porter mixin upgrade --all --feed-url https://cdn.porter.sh/mixins/atom.xml`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.UpgradePackages(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.All, "all", false,
+		"Upgrade every outdated mixin")
+	flags.StringVar(&opts.FeedURL, "feed-url", "",
+		"URL of an atom feed where the mixins are published. Defaults to the feed from which each mixin was installed.")
+	flags.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
+		"Mirror of official Porter assets")
+	return cmd
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildNamespacesCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "namespaces",
+		Aliases:     []string{"namespace", "ns"},
+		Annotations: map[string]string{"group": "resource"},
+		Short:       "Namespace commands",
+		Long: `Commands for managing namespaces, which group installations, credential sets and parameter sets.
+
+Set the namespace in the porter config file to change the default namespace of the commands that have a --namespace flag.`,
+	}
+
+	cmd.AddCommand(buildNamespacesListCommand(p))
+	cmd.AddCommand(buildNamespacesCreateCommand(p))
+	cmd.AddCommand(buildNamespacesUpdateCommand(p))
+	cmd.AddCommand(buildNamespacesDeleteCommand(p))
+
+	return cmd
+}
+
+func buildNamespacesListCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.NamespaceListOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List namespaces",
+		Long: `List the namespaces that were created, and the namespaces that have installations, credential sets or parameter sets.
+
+Optionally filters the results name, which returns all results whose name contain the provided query.
+The results may also be filtered by associated labels, which only returns namespaces that were created.`,
+		Example: `  porter namespaces list
+  porter namespaces list --name dev
+  porter namespaces list --label team=red
+  porter namespaces list --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintNamespaces(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.Name, "name", "",
+		"Filter the namespaces where the name contains the specified substring.")
+	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
+		"Filter the namespaces by a label formatted as: KEY=VALUE. May be specified multiple times.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+
+	return cmd
+}
+
+func buildNamespacesCreateCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.NamespaceCreateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a namespace",
+		Long: `Create a namespace with a description, labels and policies.
+
+Resources may be defined in a namespace that was not created, creating a namespace saves its metadata.
+Credential sets and parameter sets in a protected namespace can only be deleted with --force, and installations with --allow-protected.`,
+		Example: `  porter namespaces create dev
+  porter namespaces create prod --description "Production environment" --label team=red
+  porter namespaces create prod --protected --max-installations 20`,
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return p.CreateNamespace(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.Description, "description", "",
+		"Description of the namespace.")
+	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
+		"Associate the specified labels with the namespace. May be specified multiple times.")
+	f.BoolVar(&opts.Protected, "protected", false,
+		"Require --force to delete installations, credential sets and parameter sets in the namespace.")
+	f.Int64Var(&opts.MaxInstallations, "max-installations", 0,
+		"Maximum number of installations in the namespace. Defaults to 0, which does not limit the installations.")
+
+	return cmd
+}
+
+func buildNamespacesUpdateCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.NamespaceUpdateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "update NAME",
+		Short: "Update a namespace",
+		Long: `Update the description, labels and policies of a namespace.
+
+Only the specified flags are changed. Labels are added to the existing labels of the namespace.`,
+		Example: `  porter namespaces update prod --protected
+  porter namespaces update prod --protected=false
+  porter namespaces update dev --max-installations 5 --label team=blue`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			opts.DescriptionSet = cmd.Flags().Changed("description")
+			opts.ProtectedSet = cmd.Flags().Changed("protected")
+			opts.MaxInstallationsSet = cmd.Flags().Changed("max-installations")
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return p.UpdateNamespace(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.Description, "description", "",
+		"Description of the namespace.")
+	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
+		"Associate the specified labels with the namespace. May be specified multiple times.")
+	f.BoolVar(&opts.Protected, "protected", false,
+		"Require --force to delete installations, credential sets and parameter sets in the namespace.")
+	f.Int64Var(&opts.MaxInstallations, "max-installations", 0,
+		"Maximum number of installations in the namespace. Use 0 to remove the limit.")
+
+	return cmd
+}
+
+func buildNamespacesDeleteCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.NamespaceDeleteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete a namespace",
+		Long: `Delete the metadata of a namespace.
+
+The namespace must not have any installations, credential sets or parameter sets.`,
+		Example: `  porter namespaces delete dev`,
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return p.DeleteNamespace(cmd.Context(), opts)
+		},
+	}
+
+	return cmd
+}
@@ -17,6 +17,7 @@ func buildInstallationOutputsCommands(p *porter.Porter) *cobra.Command {
 
 	cmd.AddCommand(buildBundleOutputShowCommand(p))
 	cmd.AddCommand(buildBundleOutputListCommand(p))
+	cmd.AddCommand(buildBundleOutputDownloadCommand(p))
 
 	return cmd
 }
@@ -27,9 +28,12 @@ func buildBundleOutputListCommand(p *porter.Porter) *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "list [--installation|i INSTALLATION]",
 		Short: "List installation outputs",
-		Long:  "Displays a listing of installation outputs.",
+		Long: `Displays a listing of installation outputs.
+
+The values of sensitive outputs are hidden unless --reveal is specified.`,
 		Example: `  porter installation outputs list
     porter installation outputs list --installation another-bundle
+    porter installation outputs list --reveal --output json
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args, p.Context)
@@ -46,6 +50,8 @@ func buildBundleOutputListCommand(p *porter.Porter) *cobra.Command {
 		"Namespace in which the installation is defined. Defaults to the global namespace.")
 	f.StringVarP(&opts.Name, "installation", "i", "",
 		"Specify the installation to which the output belongs.")
+	f.BoolVar(&opts.Reveal, "reveal", false,
+		"Print the values of sensitive outputs.")
 
 	return &cmd
 }
@@ -56,9 +62,12 @@ func buildBundleOutputShowCommand(p *porter.Porter) *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "show NAME [--installation|-i INSTALLATION]",
 		Short: "Show the output of an installation",
-		Long:  "Show the output of an installation",
+		Long: `Show the output of an installation.
+
+The value of a sensitive output is only printed when --reveal is specified.`,
 		Example: `  porter installation output show kubeconfig
-    porter installation output show subscription-id --installation azure-mysql`,
+    porter installation output show subscription-id --installation azure-mysql
+    porter installation output show admin-password --reveal`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args, p.Context)
 		},
@@ -72,6 +81,39 @@ func buildBundleOutputShowCommand(p *porter.Porter) *cobra.Command {
 		"Namespace in which the installation is defined. Defaults to the global namespace.")
 	f.StringVarP(&opts.Name, "installation", "i", "",
 		"Specify the installation to which the output belongs.")
+	f.BoolVar(&opts.Reveal, "reveal", false,
+		"Print the value of the output when it is sensitive.")
+
+	return &cmd
+}
+
+func buildBundleOutputDownloadCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.OutputDownloadOptions{}
+
+	cmd := cobra.Command{
+		Use:   "download DIRECTORY [--installation|-i INSTALLATION]",
+		Short: "Download installation outputs to files",
+		Long: `Write the outputs of an installation to files in the specified directory.
+
+Each output is written to a file named after the output, with its value unmodified so that binary outputs, such as archives, are preserved. The directory is created if it does not exist and existing files are overwritten.`,
+		Example: `  porter installation outputs download ./outputs
+    porter installation outputs download ./outputs --installation mycluster --name kubeconfig
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.DownloadBundleOutputs(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+	f.StringVarP(&opts.Name, "installation", "i", "",
+		"Specify the installation to which the outputs belong.")
+	f.StringSliceVar(&opts.Outputs, "name", nil,
+		"Name of an output to download. May be specified multiple times. Defaults to all outputs.")
 
 	return &cmd
 }
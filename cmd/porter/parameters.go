@@ -16,10 +16,13 @@ func buildParametersCommands(p *porter.Porter) *cobra.Command {
 	cmd.AddCommand(buildParametersApplyCommand(p))
 	cmd.AddCommand(buildParametersEditCommand(p))
 	cmd.AddCommand(buildParametersGenerateCommand(p))
+	cmd.AddCommand(buildParametersImportCommand(p))
 	cmd.AddCommand(buildParametersListCommand(p))
 	cmd.AddCommand(buildParametersDeleteCommand(p))
 	cmd.AddCommand(buildParametersShowCommand(p))
 	cmd.AddCommand(buildParametersCreateCommand(p))
+	cmd.AddCommand(buildParametersDiffCommand(p))
+	cmd.AddCommand(buildParametersToCRDCommand(p))
 
 	return cmd
 }
@@ -66,7 +69,7 @@ func buildParametersEditCommand(p *porter.Porter) *cobra.Command {
 			return opts.Validate(args)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return p.EditParameter(cmd.Context(), opts)
+			return p.EditParameterSet(cmd.Context(), opts)
 		},
 	}
 
@@ -128,6 +131,51 @@ will then provide it to the bundle in the correct location. `,
 	return cmd
 }
 
+func buildParametersImportCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ParameterImportOptions{}
+	cmd := &cobra.Command{
+		Use:   "import [NAME]",
+		Short: "Import parameters from a .env or Helm values file",
+		Long: `Import parameters from a .env or Helm values file into a parameter set. If the parameter set doesn't already exist, it is created.
+
+The first argument is the name of the parameter set to create or update. If not provided, this will default to the bundle name.
+
+Keys in the imported file are matched to the bundle's parameters by name, ignoring case and treating _ and . the same as -, or by the environment variable to which the parameter is injected.
+Nested keys in a Helm values file are flattened into dotted keys, for example image.tag.
+Use --map to import a key into a parameter with a different name. Keys that do not match a parameter are skipped.`,
+		Example: `  porter parameters import myparamset --from-env-file .env
+  porter parameters import myparamset --from-env-file .env --map DB_PASS=mysql-password
+  porter parameters import myparamset --from-values values.yaml --reference getporter/wordpress:v0.1.3
+  porter parameters import myparamset --from-values values.yaml --map image.tag=wordpress-version --namespace dev
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(cmd.Context(), args, p)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ImportParameters(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.EnvFile, "from-env-file", "",
+		"Path to a .env file containing NAME=VALUE pairs to import.")
+	f.StringVar(&opts.ValuesFile, "from-values", "",
+		"Path to a Helm values file to import.")
+	f.StringSliceVar(&opts.Mappings, "map", nil,
+		"Import a key from the file into the specified parameter, formatted as KEY=PARAMETER. May be specified multiple times.")
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the parameter set is defined. Defaults to the global namespace.")
+	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
+		"Associate the specified labels with the parameter set. May be specified multiple times.")
+	f.StringVarP(&opts.File, "file", "f", "",
+		"Path to the porter manifest file. Defaults to the bundle in the current directory.")
+	f.StringVar(&opts.CNABFile, "cnab-file", "",
+		"Path to the CNAB bundle.json file.")
+	addBundlePullFlags(f, &opts.BundlePullOptions)
+
+	return cmd
+}
+
 func buildParametersListCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.ListOptions{}
 
@@ -144,7 +192,10 @@ The results may also be filtered by associated labels and the namespace in which
   porter parameters list --all-namespaces,
   porter parameters list --name myapp
   porter parameters list --label env=dev
-  porter parameters list --skip 2 --limit 2`,
+  porter parameters list -o wide
+  porter parameters list --columns name,parameters --sort-by name
+  porter parameters list --skip 2 --limit 2
+  porter parameters list --limit 100 --page 3`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate()
 		},
@@ -163,11 +214,19 @@ The results may also be filtered by associated labels and the namespace in which
 	f.StringSliceVarP(&opts.Labels, "label", "l", nil,
 		"Filter the parameter sets by a label formatted as: KEY=VALUE. May be specified multiple times.")
 	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
-		"Specify an output format.  Allowed values: plaintext, json, yaml")
+		"Specify an output format.  Allowed values: plaintext, wide, csv, json, yaml")
+	f.StringSliceVar(&opts.Columns, "columns", nil,
+		"Comma separated list of the columns to print, in order, for example NAME,NAMESPACE,MODIFIED. Any column of the wide output may be selected.")
+	f.StringVar(&opts.SortBy, "sort-by", "",
+		"Name of the column used to sort the results, for example modified.")
 	f.Int64Var(&opts.Skip, "skip", 0,
 		"Skip the number of parameter sets by a certain amount. Defaults to 0.")
 	f.Int64Var(&opts.Limit, "limit", 0,
 		"Limit the number of parameter sets by a certain amount. Defaults to 0.")
+	f.Int64Var(&opts.Page, "page", 0,
+		"Page of parameter sets to return, starting at 1. Requires --limit, which sets the size of each page.")
+	f.StringVar(&opts.Continue, "continue", "",
+		"Continue listing parameter sets after the previous page, using the token printed with that page.")
 
 	return cmd
 }
@@ -190,6 +249,8 @@ func buildParametersDeleteCommand(p *porter.Porter) *cobra.Command {
 	f := cmd.Flags()
 	f.StringVarP(&opts.Namespace, "namespace", "n", "",
 		"Namespace in which the parameter set is defined. Defaults to the global namespace.")
+	f.BoolVar(&opts.Force, "force", false,
+		"Delete the parameter set even when its namespace is protected.")
 
 	return cmd
 }
@@ -243,3 +304,70 @@ func buildParametersCreateCommand(p *porter.Porter) *cobra.Command {
 
 	return cmd
 }
+
+func buildParametersDiffCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ParameterDiffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff INSTALLATION",
+		Short: "Compare parameters against an installation",
+		Long: `Compare the parameters recorded on the last run of an installation against the parameters that would be used on the next upgrade.
+
+By default the parameter sets and parameter overrides already associated with the installation are used. Specify --parameter-set or --param to preview the effect of changing them before running porter upgrade.
+
+Sensitive parameter values are never printed, only whether they changed.`,
+		Example: `  porter parameters diff myapp
+  porter parameters diff myapp --namespace dev --parameter-set myapp-prod
+  porter parameters diff myapp --param replicas=3 --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintParametersDiff(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the installation is defined. Defaults to the global namespace.")
+	f.StringArrayVarP(&opts.ParameterSets, "parameter-set", "p", nil,
+		"Name of a parameter set to compare against the installation. May be specified multiple times. Defaults to the parameter sets associated with the installation.")
+	f.StringArrayVar(&opts.Params, "param", nil,
+		"Define an individual parameter in the form NAME=VALUE. Overrides parameters otherwise set via --parameter-set. May be specified multiple times. Defaults to the parameter overrides associated with the installation.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+
+	return cmd
+}
+
+func buildParametersToCRDCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.CRDOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "to-crd NAME",
+		Short: "Print a parameter set as a Porter Operator custom resource",
+		Long: `Print a parameter set as a ParameterSet custom resource of the Porter Operator, so that it can be migrated from the CLI to the operator with kubectl apply.
+
+The operator resolves parameters from a value or a Kubernetes secret. A warning is printed for parameters that are resolved from another source, such as an environment variable or a file, which must be changed before the resource is applied. The secrets are not copied.
+`,
+		Example: `  porter parameters to-crd myparams
+  porter parameters to-crd myparams --namespace dev --k8s-namespace porter-dev
+  porter parameters to-crd myparams --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.ParameterSetToCRD(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace in which the parameter set is defined. Defaults to the global namespace.")
+	f.StringVar(&opts.KubernetesNamespace, "k8s-namespace", "",
+		"Kubernetes namespace of the custom resource. Defaults to the namespace selected when the resource is applied.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "yaml",
+		"Specify an output format.  Allowed values: yaml, json")
+
+	return cmd
+}
@@ -22,6 +22,11 @@ func buildPluginsCommands(p *porter.Porter) *cobra.Command {
 	cmd.AddCommand(buildPluginShowCommand(p))
 	cmd.AddCommand(BuildPluginInstallCommand(p))
 	cmd.AddCommand(BuildPluginUninstallCommand(p))
+	cmd.AddCommand(buildPluginsCreateCommand(p))
+	cmd.AddCommand(buildPluginsDoctorCommand(p))
+	cmd.AddCommand(buildPluginsPublishCommand(p))
+	cmd.AddCommand(buildPluginsOutdatedCommand(p))
+	cmd.AddCommand(buildPluginsUpgradeCommand(p))
 	cmd.AddCommand(buildPluginRunCommand(p))
 
 	return cmd
@@ -82,8 +87,13 @@ func buildPluginShowCommand(p *porter.Porter) *cobra.Command {
 	opts := porter.ShowPluginOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "show",
+		Use:   "show NAME",
 		Short: "Show details about an installed plugin",
+		Long: `Show details about an installed plugin.
+
+The details include the version of the plugin, where it was installed from, when it was installed, the path to its binary, and the plugins that it implements with the version of the plugin protocol used for each.`,
+		Example: `  porter plugin show azure
+  porter plugin show kubernetes --output json`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return opts.Validate(args)
 		},
@@ -122,6 +132,7 @@ By default plugins are downloaded from the official Porter plugin feed at https:
   porter plugin install azure --feed-url https://cdn.porter.sh/plugins/atom.xml
   porter plugin install azure --version v0.8.2-beta.1
   porter plugin install azure --version canary 
+  porter plugin install azure --source oci://ghcr.io/getporter/plugins/azure:v1.0.0
   porter plugin install --file plugins.yaml --feed-url https://cdn.porter.sh/plugins/atom.xml
   porter plugin install --file plugins.yaml --mirror https://cdn.porter.sh`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -139,6 +150,10 @@ By default plugins are downloaded from the official Porter plugin feed at https:
 		"URL from where the plugin can be downloaded, for example https://github.com/org/proj/releases/downloads")
 	flags.StringVar(&opts.FeedURL, "feed-url", "",
 		"URL of an atom feed where the plugin can be downloaded. Defaults to the official Porter plugin feed.")
+	flags.StringVar(&opts.Source, "source", "",
+		"OCI reference to the plugin in a registry, for example oci://ghcr.io/org/plugins/NAME:v1.0.0. The plugin is installed from the registry instead of a feed.")
+	flags.BoolVar(&opts.InsecureRegistry, "insecure-registry", false,
+		"Don't require TLS when installing the plugin from --source")
 	flags.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
 		"Mirror of official Porter assets")
 	flags.StringVarP(&opts.File, "file", "f", "",
@@ -178,3 +193,174 @@ func buildPluginRunCommand(p *porter.Porter) *cobra.Command {
 
 	return cmd
 }
+
+func buildPluginsCreateCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.PluginsCreateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create NAME --type secrets|storage --author \"My Name\" [--username mygithubusername | --module example.com/myplugin] [--dir /path/to/plugin/dir]",
+		Short: "Create a new plugin project",
+		Long: `Create a new secrets or storage plugin project.
+The first argument is the name of the plugin to create and is required.
+
+The project contains the run and version commands called by Porter, an example implementation of the plugin, unit tests,
+an example feed template, and a magefile with targets to build, test, install, cross-compile, generate a feed for and publish the plugin.
+
+A flag of --type to declare the type of plugin, secrets or storage, is a required input.
+A flag of --author to declare the author of the plugin is a required input.
+A flag of --username to specify the GitHub username of the plugin's author, or --module to specify the Go module path of the plugin, is required.
+The module path defaults to github.com/USERNAME/NAME.
+
+You can also specify where to put the plugin directory. It will default to the current directory.`,
+		Example: `  porter plugin create myplugin --type secrets --author "My Name" --username mygithubusername
+  porter plugin create myplugin --type storage --author "My Name" --username mygithubusername --dir path/to/myplugin
+  porter plugin create myplugin --type secrets --author "My Name" --module example.com/plugins/myplugin`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.CreatePlugin(opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.PluginType, "type", "", "The type of plugin. Allowed values: secrets, storage")
+	f.StringVar(&opts.AuthorName, "author", "", "Your full name.")
+	f.StringVar(&opts.AuthorUsername, "username", "", "Your GitHub username.")
+	f.StringVar(&opts.ModulePath, "module", "", "The Go module path of the plugin. Defaults to github.com/USERNAME/NAME.")
+	f.StringVar(&opts.DirPath, "dir", "", "Path to the designated location of the plugin's directory.")
+
+	return cmd
+}
+
+func buildPluginsDoctorCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.PluginsDoctorOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the plugins configured in the Porter config file",
+		Long: `Check the plugins configured in the Porter config file.
+
+Each storage and secrets plugin is started and tested: storage plugins query their database, and secrets plugins resolve a secret that is not expected to exist.
+The time to start each plugin and respond to the test is reported, along with the version of the plugin and the plugin protocol that it supports.
+
+The command fails when a plugin cannot be started, does not support the plugin protocol used by this version of Porter, or does not respond to the test.`,
+		Example: `  porter plugins doctor
+  porter plugins doctor --timeout 30s
+  porter plugins doctor --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.DoctorPlugins(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.DurationVar(&opts.Timeout, "timeout", porter.DefaultPluginDoctorTimeout,
+		"How long each plugin has to respond to its connectivity test.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+
+	return cmd
+}
+
+func buildPluginsPublishCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.PublishPackageOptions{
+		Type: "plugin",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "publish NAME",
+		Short: "Publish a plugin to a registry",
+		Long: `Publish the binaries of a plugin to a registry as an OCI artifact, so that it can be installed with porter plugin install --source.
+
+The file names of the binaries must follow the naming conventions required of published plugins:
+
+NAME-GOOS-GOARCH[FILE_EXT]
+
+The linux/amd64 binary is required because it is used in the invocation image. The binaries are stored in the artifact with their digests, which are verified when the plugin is installed.`,
+		Example: `  porter plugin publish NAME --reference oci://ghcr.io/org/plugins/NAME:v1.0.0
+  porter plugin publish NAME --dir bin/plugins/NAME/v1.0.0 --reference ghcr.io/org/plugins/NAME:v1.0.0
+  porter plugin publish NAME --reference localhost:5000/plugins/NAME:canary --version v1.1.0-canary --insecure-registry`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PublishPackage(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.Reference, "reference", "r", "",
+		"OCI reference to which the plugin is published, for example oci://ghcr.io/org/plugins/NAME:v1.0.0")
+	flags.StringVarP(&opts.Dir, "dir", "d", "",
+		"The directory that contains the plugin binaries. Defaults to the current directory.")
+	flags.StringVarP(&opts.Version, "version", "v", "",
+		"The plugin version. Defaults to the tag of the reference.")
+	flags.BoolVar(&opts.InsecureRegistry, "insecure-registry", false,
+		"Don't require TLS for the registry")
+	return cmd
+}
+
+func buildPluginsOutdatedCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.OutdatedPackagesOptions{}
+	opts.PackageType = "plugin"
+
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "List outdated plugins",
+		Long: `List the installed plugins that have a newer version published in their feeds.
+
+By default each plugin is compared to the feed from which it was installed, or the official Porter plugin feed at https://cdn.porter.sh/plugins/atom.xml. To check a mirror, set the environment variable PORTER_MIRROR, or mirror in the Porter config file, with the value to replace https://cdn.porter.sh with.`,
+		Example: `  porter plugin outdated
+  porter plugin outdated --feed-url https://cdn.porter.sh/plugins/atom.xml
+  porter plugin outdated -o json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.PrintOutdatedPackages(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Output format, allowed values are: plaintext, json, yaml")
+	flags.StringVar(&opts.FeedURL, "feed-url", "",
+		"URL of an atom feed where the plugins are published. Defaults to the feed from which each plugin was installed.")
+	flags.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
+		"Mirror of official Porter assets")
+	return cmd
+}
+
+func buildPluginsUpgradeCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.UpgradePackagesOptions{}
+	opts.PackageType = "plugin"
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [NAME...]",
+		Short: "Upgrade plugins",
+		Long: `Upgrade the installed plugins to the latest version published in their feeds.
+
+Specify the names of the plugins to upgrade, or --all to upgrade every outdated plugin. The plugins that are upgraded are listed with their current and latest versions.`,
+		Example: `  porter plugin upgrade --all
+  porter plugin upgrade azure kubernetes
+  porter plugin upgrade --all --feed-url https://cdn.porter.sh/plugins/atom.xml`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.UpgradePackages(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.All, "all", false,
+		"Upgrade every outdated plugin")
+	flags.StringVar(&opts.FeedURL, "feed-url", "",
+		"URL of an atom feed where the plugins are published. Defaults to the feed from which each plugin was installed.")
+	flags.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
+		"Mirror of official Porter assets")
+	return cmd
+}
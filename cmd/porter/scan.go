@@ -0,0 +1,40 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildBundleScanCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.ScanOptions{}
+	cmd := cobra.Command{
+		Use:   "scan REFERENCE",
+		Short: "Scan the images of a bundle for vulnerabilities",
+		Long: `Scan the invocation image and the images referenced by a published bundle for vulnerabilities.
+
+The images are scanned with the scanner set with --scanner, or in the scan section of the Porter configuration file, which defaults to trivy. The scanner must be installed and on the PATH.
+
+Use --fail-on to exit with a non-zero exit code when vulnerabilities with the specified severity, or higher, are found, for example to fail a CI build.`,
+		Example: `  porter bundle scan ghcr.io/getporter/examples/porter-hello:v0.2.0
+  porter bundle scan ghcr.io/getporter/examples/porter-hello:v0.2.0 --fail-on high
+  porter bundle scan ghcr.io/getporter/examples/porter-hello:v0.2.0 --scanner grype --output json
+  porter bundle scan localhost:5000/ghcr.io/getporter/examples/porter-hello:v0.2.0 --insecure-registry --force
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.Scan(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.Scanner, "scanner", "",
+		"Scanner used to scan the images. Allowed values are: trivy, grype. Defaults to the scanner in the configuration file, or trivy.")
+	f.StringVar(&opts.FailOn, "fail-on", "",
+		"Exit with a non-zero exit code when vulnerabilities with this severity or higher are found. Allowed values are: unknown, low, medium, high, critical.")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Specify an output format.  Allowed values: plaintext, json, yaml")
+	addBundlePullFlags(f, &opts.BundlePullOptions)
+	return &cmd
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"time"
+
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildSchedulerCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scheduler",
+		Short: "Run scheduled actions on installations",
+		Long: `Run the actions that installations schedule on a recurring basis.
+
+An installation schedules an action by defining a schedule in the installation file applied with porter installation apply, for example:
+
+  schedule:
+    action: upgrade
+    cron: "0 3 * * *"
+`,
+		Annotations: map[string]string{
+			"group": "resource",
+		},
+	}
+
+	cmd.AddCommand(buildSchedulerRunCommand(p))
+
+	return cmd
+}
+
+func buildSchedulerRunCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.SchedulerOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run scheduled actions when they are due",
+		Long: `Run the scheduled actions of installations when they are due, until the command is stopped.
+
+Paused installations, and installations that have not been installed, are skipped. When the scheduler was not running at the time an action was due, the action is run once when the scheduler next checks the installation. The time that each scheduled action was last started is saved with the installation, so that the scheduler can be restarted without repeating actions. Cron expressions are evaluated in the time zone of the scheduler.`,
+		Example: `  porter scheduler run
+  porter scheduler run --namespace dev --interval 5m
+  porter scheduler run --all-namespaces --once
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.RunScheduler(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace of the installations to schedule. Defaults to the global namespace.")
+	f.BoolVar(&opts.AllNamespaces, "all-namespaces", false,
+		"Schedule installations in all namespaces.")
+	f.DurationVar(&opts.Interval, "interval", time.Minute,
+		"How often to check for scheduled actions that are due.")
+	f.BoolVar(&opts.Once, "once", false,
+		"Run the scheduled actions that are due and then exit, instead of running until the command is stopped.")
+
+	return cmd
+}
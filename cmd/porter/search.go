@@ -0,0 +1,86 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildBundleSearchCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.BundleSearchOptions{}
+	cmd := &cobra.Command{
+		Use:   "search [QUERY]",
+		Short: "Search bundle indexes for bundles",
+		Long: `Search bundle indexes for bundles. The results must match every term in the query, and are ranked by how closely the bundle name, keywords, description and reference match the query. Every bundle is listed when a query is not specified.
+
+The bundle indexes defined in the bundle-indexes section of the Porter configuration file are searched, and the results merged. When no bundle indexes are configured, the community bundle index at https://cdn.porter.sh/bundles/index.json is searched. To search from a mirror, set the environment variable PORTER_MIRROR, or mirror in the Porter config file, with the value to replace https://cdn.porter.sh with.
+
+Use porter bundles index generate to create a bundle index from the bundles published to a registry.`,
+		Example: `  porter bundle search
+  porter bundle search mysql
+  porter bundle search mysql --index internal
+  porter bundle search kubernetes operator -o json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args, p)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.SearchBundles(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringSliceVar(&opts.Indexes, "index", nil,
+		"Name of a bundle index from the configuration file to search. May be specified multiple times. Defaults to every configured index.")
+	f.BoolVar(&opts.InsecureRegistry, "insecure-registry", false,
+		"Don't require TLS when loading a bundle index from a registry")
+	f.StringVarP(&opts.RawFormat, "output", "o", "plaintext",
+		"Output format, allowed values are: plaintext, json, yaml")
+	f.StringVar(&opts.Mirror, "mirror", pkgmgmt.DefaultPackageMirror,
+		"Mirror of official Porter assets")
+	return cmd
+}
+
+func buildBundleIndexCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Bundle index commands",
+		Long:  "Commands for working with the bundle indexes that are searched with porter search.",
+	}
+
+	cmd.AddCommand(buildBundleIndexGenerateCommand(p))
+
+	return cmd
+}
+
+func buildBundleIndexGenerateCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.BundleIndexGenerateOptions{}
+	cmd := &cobra.Command{
+		Use:   "generate REGISTRY[/NAMESPACE]",
+		Short: "Generate a bundle index from a registry",
+		Long: `Generate a bundle index that lists the latest version of every bundle published to a registry namespace. The repositories are found with the registry catalog API, so the registry must support listing its repositories.
+
+Only tags that are a semantic version are considered when selecting the latest version of a bundle, and repositories that do not contain a bundle are skipped.
+
+The index is printed unless it is written to a file with --output, or published to a registry as an OCI artifact with --publish. Add the file, or the published artifact, to the bundle-indexes section of the Porter configuration file to search it with porter search.`,
+		Example: `  porter bundles index generate localhost:5000/getporter --insecure-registry
+  porter bundles index generate example.azurecr.io/bundles --output index.json
+  porter bundles index generate example.azurecr.io/bundles --publish example.azurecr.io/bundle-index:latest`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.GenerateBundleIndex(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.Output, "output", "",
+		"Path where the bundle index is written")
+	f.StringVar(&opts.Publish, "publish", "",
+		"Reference where the bundle index is published as an OCI artifact")
+	f.BoolVar(&opts.IncludePrereleases, "include-prereleases", false,
+		"List the latest prerelease version of a bundle when it is newer than the latest release")
+	f.BoolVar(&opts.InsecureRegistry, "insecure-registry", false,
+		"Don't require TLS for the registry")
+	return cmd
+}
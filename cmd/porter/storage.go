@@ -18,6 +18,7 @@ func buildStorageCommand(p *porter.Porter) *cobra.Command {
 
 	cmd.AddCommand(buildStorageMigrateCommand(p))
 	cmd.AddCommand(buildStorageFixPermissionsCommand(p))
+	cmd.AddCommand(buildStorageRewrapCommand(p))
 
 	return &cmd
 }
@@ -72,3 +73,18 @@ func buildStorageFixPermissionsCommand(p *porter.Porter) *cobra.Command {
 		},
 	}
 }
+
+func buildStorageRewrapCommand(p *porter.Porter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rewrap",
+		Short: "Rotate the data key used to encrypt sensitive data",
+		Long: `Rotate the data key used to encrypt sensitive data saved by Porter.
+
+When encryption is configured in the Porter configuration file, sensitive parameter and output values are encrypted with a data key before they are saved.
+This command generates a new data key, re-encrypts existing values with it, and then removes the previous data keys.`,
+		Example: `  porter storage rewrap`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.RewrapStorage(cmd.Context())
+		},
+	}
+}
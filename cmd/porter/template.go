@@ -0,0 +1,58 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildTemplateCommands(p *porter.Porter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Template commands",
+		Long:  "Commands for debugging the templating used in a porter manifest.",
+		Annotations: map[string]string{
+			"group": "meta",
+		},
+	}
+
+	cmd.AddCommand(buildTemplateRenderCommand(p))
+
+	return cmd
+}
+
+func buildTemplateRenderCommand(p *porter.Porter) *cobra.Command {
+	var opts porter.TemplateRenderOptions
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the templating in a porter manifest",
+		Long: `Render the templating in a porter manifest with the specified parameter values, and print the resolved manifest.
+
+Use this command to debug the templating used in a manifest, including template functions such as ${ bundle.parameters.name | default "world" | upper }, before building the bundle.
+Parameters that are not specified use their default value. Values that are only known when the bundle is run, such as credentials, outputs and dependency outputs, are printed as template expressions.`,
+		Example: `  porter template render
+  porter template render --param logLevel=debug --param replicas=3
+  porter template render --file path/to/porter.yaml --action upgrade
+  porter template render --installation mysql --namespace dev
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(p.Context)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.RenderManifestTemplate(cmd.Context(), opts)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&opts.File, "file", "f", "",
+		"Path to the porter manifest file. Defaults to the bundle in the current directory.")
+	f.StringVar(&opts.Action, "action", "",
+		"Action used to determine which parameters apply. Defaults to install.")
+	f.StringVar(&opts.Name, "installation", "",
+		"Name of the installation used for installation.name. Defaults to the name of the bundle.")
+	f.StringVarP(&opts.Namespace, "namespace", "n", "",
+		"Namespace of the installation used for installation.namespace. Defaults to the namespace set in the porter configuration.")
+	f.StringArrayVar(&opts.Params, "param", nil,
+		"Define an individual parameter in the form NAME=VALUE. May be specified multiple times.")
+
+	return cmd
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"get.porter.sh/porter/pkg/porter"
+	"github.com/spf13/cobra"
+)
+
+func buildBundleVerifyCommand(p *porter.Porter) *cobra.Command {
+	opts := porter.VerifyOptions{}
+	cmd := cobra.Command{
+		Use:   "verify REFERENCE",
+		Short: "Verify the signatures of a bundle",
+		Long: `Verify that a published bundle and its invocation images have trusted signatures.
+
+The signatures are verified with the signer configured in the signing section of the Porter configuration file, which defaults to cosign. The signer must be installed and on the PATH.`,
+		Example: `  porter bundle verify ghcr.io/getporter/examples/porter-hello:v0.2.0
+  porter bundle verify --reference ghcr.io/getporter/examples/porter-hello:v0.2.0
+  porter bundle verify localhost:5000/ghcr.io/getporter/examples/porter-hello:v0.2.0 --insecure-registry --force
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Validate(args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return p.Verify(cmd.Context(), opts)
+		},
+	}
+
+	addBundlePullFlags(cmd.Flags(), &opts.BundlePullOptions)
+	return &cmd
+}
@@ -3,6 +3,7 @@ package build
 import (
 	"context"
 	"path/filepath"
+	"strings"
 
 	"get.porter.sh/porter/pkg/manifest"
 )
@@ -27,6 +28,10 @@ var (
 	// user-provided manifest and any dynamic overrides
 	LOCAL_MANIFEST = filepath.Join(LOCAL_APP, "porter.yaml")
 
+	// LOCAL_IMAGE_LAYOUT is the generated OCI image layout containing the
+	// invocation image, when it is built for multiple platforms.
+	LOCAL_IMAGE_LAYOUT = filepath.Join(LOCAL_CNAB, "invocation-image")
+
 	// LOCAL_MIXINS is the path where Porter stages the /cnab/app/mixins directory.
 	LOCAL_MIXINS = filepath.Join(LOCAL_APP, "mixins")
 
@@ -63,4 +68,49 @@ type BuildImageOptions struct {
 
 	// NoCache is the docker build --no-cache flag specified.
 	NoCache bool
+
+	// Platforms is the set of docker build --platform flags specified, e.g. linux/amd64.
+	Platforms []string
+
+	// Builder is the name of the buildx builder used to build the invocation image.
+	Builder string
+
+	// CacheFrom is the set of docker build --cache-from flags specified.
+	CacheFrom []string
+
+	// CacheTo is the set of docker build --cache-to flags specified.
+	CacheTo []string
+}
+
+// GetSecretIDs returns the ids of the build secrets specified with --secret.
+// The secrets can be mounted in the Dockerfile with RUN --mount=type=secret,id=ID.
+func (o BuildImageOptions) GetSecretIDs() []string {
+	ids := make([]string, 0, len(o.Secrets))
+	for _, spec := range o.Secrets {
+		for _, field := range strings.Split(spec, ",") {
+			key, value, ok := strings.Cut(field, "=")
+			if ok && strings.EqualFold(key, "id") {
+				ids = append(ids, value)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// GetSSHIDs returns the ids of the SSH agent sockets or keys specified with --ssh.
+// They can be mounted in the Dockerfile with RUN --mount=type=ssh,id=ID.
+func (o BuildImageOptions) GetSSHIDs() []string {
+	ids := make([]string, 0, len(o.SSH))
+	for _, spec := range o.SSH {
+		id, _, _ := strings.Cut(spec, "=")
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// IsMultiPlatform indicates if the invocation image is built for more than one
+// platform, and is saved as an OCI image layout instead of in the local image cache.
+func (o BuildImageOptions) IsMultiPlatform() bool {
+	return len(o.Platforms) > 1
 }
@@ -0,0 +1,25 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildImageOptions_GetSecretIDs(t *testing.T) {
+	opts := BuildImageOptions{
+		Secrets: []string{
+			"id=mysecret,src=./token",
+			"src=/tmp/pip.conf,id=pip",
+			"type=env,id=GITHUB_TOKEN",
+		},
+	}
+	assert.Equal(t, []string{"mysecret", "pip", "GITHUB_TOKEN"}, opts.GetSecretIDs())
+}
+
+func TestBuildImageOptions_GetSSHIDs(t *testing.T) {
+	opts := BuildImageOptions{
+		SSH: []string{"default", "github=/home/me/.ssh/id_rsa"},
+	}
+	assert.Equal(t, []string{"default", "github"}, opts.GetSSHIDs())
+}
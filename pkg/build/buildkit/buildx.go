@@ -17,12 +17,15 @@ import (
 	"github.com/cnabio/cnab-go/driver/docker"
 	buildx "github.com/docker/buildx/build"
 	"github.com/docker/buildx/builder"
-	_ "github.com/docker/buildx/driver/docker" // Register the docker driver with buildkit
+	_ "github.com/docker/buildx/driver/docker"           // Register the docker driver with buildkit
+	_ "github.com/docker/buildx/driver/docker-container" // Register the docker-container driver, used to build for multiple platforms
 	"github.com/docker/buildx/util/buildflags"
 	"github.com/docker/buildx/util/confutil"
 	"github.com/docker/buildx/util/dockerutil"
+	"github.com/docker/buildx/util/platformutil"
 	"github.com/docker/buildx/util/progress"
 	dockerconfig "github.com/docker/cli/cli/config"
+	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/auth/authprovider"
 	"go.opentelemetry.io/otel/attribute"
@@ -69,8 +72,12 @@ func (b *Builder) BuildInvocationImage(ctx context.Context, manifest *manifest.M
 		return log.Error(err)
 	}
 
+	builderName := opts.Builder
+	if builderName == "" {
+		builderName = "default"
+	}
 	bldr, err := builder.New(cli,
-		builder.WithName("default"),
+		builder.WithName(builderName),
 		builder.WithContextPathHash(b.Getwd()),
 	)
 	if err != nil {
@@ -94,6 +101,15 @@ func (b *Builder) BuildInvocationImage(ctx context.Context, manifest *manifest.M
 	}
 	session = append(session, secrets)
 
+	cacheFrom, err := parseCacheEntries("--cache-from", opts.CacheFrom)
+	if err != nil {
+		return err
+	}
+	cacheTo, err := parseCacheEntries("--cache-to", opts.CacheTo)
+	if err != nil {
+		return err
+	}
+
 	args := make(map[string]string, len(opts.BuildArgs)+1)
 	parseBuildArgs(opts.BuildArgs, args)
 	args["BUNDLE_DIR"] = build.BUNDLE_DIR
@@ -107,9 +123,27 @@ func (b *Builder) BuildInvocationImage(ctx context.Context, manifest *manifest.M
 		args[strings.ToUpper(strings.Replace(k, ".", "_", -1))] = v
 	}
 
+	platforms, err := platformutil.Parse(opts.Platforms)
+	if err != nil {
+		return fmt.Errorf("error parsing the --platform flags: %w", err)
+	}
+
+	var exports []client.ExportEntry
+	if opts.IsMultiPlatform() {
+		// Docker can't load an image built for multiple platforms, so save it
+		// as an OCI image layout that porter publish pushes as an image index.
+		layoutDir := filepath.Join(b.Getwd(), build.LOCAL_IMAGE_LAYOUT)
+		exports, err = buildflags.ParseOutputs([]string{fmt.Sprintf("type=oci,tar=false,dest=%s", layoutDir)})
+		if err != nil {
+			return log.Error(err)
+		}
+	}
+
 	buildxOpts := map[string]buildx.Options{
 		"default": {
-			Tags: []string{manifest.Image},
+			Tags:      []string{manifest.Image},
+			Platforms: platforms,
+			Exports:   exports,
 			Inputs: buildx.Inputs{
 				ContextPath:    b.Getwd(),
 				DockerfilePath: filepath.Join(b.Getwd(), build.DOCKER_FILE),
@@ -118,6 +152,8 @@ func (b *Builder) BuildInvocationImage(ctx context.Context, manifest *manifest.M
 			BuildArgs: args,
 			Session:   session,
 			NoCache:   opts.NoCache,
+			CacheFrom: cacheFrom,
+			CacheTo:   cacheTo,
 		},
 	}
 
@@ -156,6 +192,17 @@ func parseBuildArgs(unparsed []string, parsed map[string]string) {
 	}
 }
 
+// parseCacheEntries parses the --cache-from or --cache-to flags into cache
+// import and export options for buildkit, for example type=registry,ref=example.com/mybuns:cache.
+// A value without a type is treated as the reference of a registry cache.
+func parseCacheEntries(flag string, unparsed []string) ([]client.CacheOptionsEntry, error) {
+	entries, err := buildflags.ParseCacheEntry(unparsed)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the %s flags: %w", flag, err)
+	}
+	return entries, nil
+}
+
 func (b *Builder) TagInvocationImage(ctx context.Context, origTag, newTag string) error {
 	ctx, log := tracing.StartSpan(ctx, attribute.String("source-tag", origTag), attribute.String("destination-tag", newTag))
 	defer log.EndSpan()
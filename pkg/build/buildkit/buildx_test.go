@@ -3,6 +3,7 @@ package buildkit
 import (
 	"testing"
 
+	"github.com/moby/buildkit/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,6 +29,39 @@ func Test_parseBuildArgs(t *testing.T) {
 	}
 }
 
+func Test_parseCacheEntries(t *testing.T) {
+	testcases := []struct {
+		name        string
+		input       []string
+		wantEntries []client.CacheOptionsEntry
+		wantErr     string
+	}{
+		{name: "registry cache", input: []string{"type=registry,ref=example.com/mybuns:cache,mode=max"},
+			wantEntries: []client.CacheOptionsEntry{{Type: "registry", Attrs: map[string]string{"ref": "example.com/mybuns:cache", "mode": "max"}}}},
+		{name: "reference only", input: []string{"example.com/mybuns:cache"},
+			wantEntries: []client.CacheOptionsEntry{{Type: "registry", Attrs: map[string]string{"ref": "example.com/mybuns:cache"}}}},
+		{name: "local cache", input: []string{"type=local,src=/tmp/cache", "type=local,dest=/tmp/cache2"},
+			wantEntries: []client.CacheOptionsEntry{
+				{Type: "local", Attrs: map[string]string{"src": "/tmp/cache"}},
+				{Type: "local", Attrs: map[string]string{"dest": "/tmp/cache2"}},
+			}},
+		{name: "missing type", input: []string{"ref=example.com/mybuns:cache"},
+			wantErr: "error parsing the --cache-from flags: type required"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotEntries, err := parseCacheEntries("--cache-from", tc.input)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantEntries, gotEntries)
+		})
+	}
+}
+
 func Test_flattenMap(t *testing.T) {
 	tt := []struct {
 		desc string
@@ -30,6 +30,11 @@ type DockerfileGenerator struct {
 	*manifest.Manifest
 	*templates.Templates
 	Mixins pkgmgmt.PackageManager
+
+	// BuildOptions are the docker build flags used to build the invocation
+	// image. The ids of the build secrets and SSH agents are passed to the
+	// mixins so that they can mount them in the Dockerfile lines that they generate.
+	BuildOptions BuildImageOptions
 }
 
 func NewDockerfileGenerator(config *config.Config, m *manifest.Manifest, tmpl *templates.Templates, mp pkgmgmt.PackageManager) *DockerfileGenerator {
@@ -175,7 +180,10 @@ func (g *DockerfileGenerator) buildMixinsSection(ctx context.Context) ([]string,
 	q := query.New(g.Context, g.Mixins)
 	q.RequireAllMixinResponses = true
 	q.LogMixinErrors = true
-	results, err := q.Execute(ctx, "build", query.NewManifestGenerator(g.Manifest))
+	inputGenerator := query.NewManifestGenerator(g.Manifest)
+	inputGenerator.Secrets = g.BuildOptions.GetSecretIDs()
+	inputGenerator.SSH = g.BuildOptions.GetSSHIDs()
+	results, err := q.Execute(ctx, "build", inputGenerator)
 	if err != nil {
 		return nil, err
 	}
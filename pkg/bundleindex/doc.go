@@ -0,0 +1,3 @@
+// Package bundleindex reads, searches and generates indexes of published bundles.
+// An index is loaded from a source, such as a URL, an OCI artifact or a git repository.
+package bundleindex
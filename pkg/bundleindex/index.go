@@ -0,0 +1,135 @@
+package bundleindex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	// SchemaVersion of the bundle index format.
+	SchemaVersion = "1.0.0"
+
+	// ArtifactType is the artifact type of a bundle index published to a registry.
+	ArtifactType = "application/vnd.getporter.bundle-index.v1"
+
+	// MediaType is the media type of the layer that contains the bundle index in an OCI artifact.
+	MediaType = "application/vnd.getporter.bundle-index.v1+json"
+)
+
+// Index is a list of published bundles.
+type Index struct {
+	// SchemaVersion of the index format.
+	SchemaVersion string `json:"schemaVersion" yaml:"schemaVersion"`
+
+	// Bundles in the index.
+	Bundles []Listing `json:"bundles" yaml:"bundles"`
+}
+
+// Listing describes a published bundle in an index.
+type Listing struct {
+	// Name of the bundle.
+	Name string `json:"name" yaml:"name"`
+
+	// Description of the bundle.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Version of the bundle.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Reference to the published bundle, for example ghcr.io/getporter/examples/porter-hello:v0.2.0.
+	Reference string `json:"reference" yaml:"reference"`
+
+	// Keywords used to find the bundle.
+	Keywords []string `json:"keywords,omitempty" yaml:"keywords,omitempty"`
+}
+
+// NewListing creates a listing for a bundle published to the specified reference.
+func NewListing(ref cnab.OCIReference, bun cnab.ExtendedBundle) Listing {
+	return Listing{
+		Name:        bun.Name,
+		Description: bun.Description,
+		Version:     bun.Version,
+		Reference:   ref.String(),
+		Keywords:    bun.Keywords,
+	}
+}
+
+// NewIndex creates an index of the bundles, sorted by name.
+func NewIndex(bundles []Listing) Index {
+	sorted := make([]Listing, len(bundles))
+	copy(sorted, bundles)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Reference < sorted[j].Reference
+	})
+	return Index{SchemaVersion: SchemaVersion, Bundles: sorted}
+}
+
+// ParseIndex parses and validates a bundle index.
+func ParseIndex(data []byte) (Index, error) {
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return Index{}, fmt.Errorf("error parsing the bundle index: %w", err)
+	}
+	if err := idx.Validate(); err != nil {
+		return Index{}, err
+	}
+	return idx, nil
+}
+
+// Validate the bundle index.
+func (i Index) Validate() error {
+	if i.SchemaVersion == "" {
+		return errors.New("invalid bundle index: schemaVersion is required")
+	}
+	if v, err := semver.NewVersion(i.SchemaVersion); err != nil || v.Major() != 1 {
+		return fmt.Errorf("unsupported bundle index schemaVersion %s, the supported version is %s", i.SchemaVersion, SchemaVersion)
+	}
+
+	for _, b := range i.Bundles {
+		if b.Name == "" || b.Reference == "" {
+			return fmt.Errorf("invalid bundle index: the name and reference are required for every bundle, but got name %q and reference %q", b.Name, b.Reference)
+		}
+	}
+	return nil
+}
+
+// Marshal the index to indented JSON.
+func (i Index) Marshal() ([]byte, error) {
+	return json.MarshalIndent(i, "", "  ")
+}
+
+// LatestVersionTag returns the tag of the most recent semantic version from the tags.
+// Tags that are not a semantic version are ignored, and prereleases are only considered when includePrereleases is true.
+// Returns false when none of the tags is a semantic version.
+func LatestVersionTag(tags []string, includePrereleases bool) (string, bool) {
+	var latest *semver.Version
+	var latestTag string
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if !includePrereleases && v.Prerelease() != "" {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+			latestTag = tag
+		}
+	}
+	return latestTag, latest != nil
+}
+
+// containsFold determines if substr is in s, ignoring case.
+func containsFold(s string, substr string) bool {
+	return strings.Contains(strings.ToLower(s), substr)
+}
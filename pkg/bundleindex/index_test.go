@@ -0,0 +1,96 @@
+package bundleindex
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListing(t *testing.T) {
+	ref := cnab.MustParseOCIReference("example.com/mysql:v1.0.0")
+	bun := cnab.NewBundle(bundle.Bundle{
+		Name:        "mysql",
+		Description: "A MySQL database",
+		Version:     "1.0.0",
+		Keywords:    []string{"database"},
+	})
+
+	assert.Equal(t, Listing{
+		Name:        "mysql",
+		Description: "A MySQL database",
+		Version:     "1.0.0",
+		Reference:   "example.com/mysql:v1.0.0",
+		Keywords:    []string{"database"},
+	}, NewListing(ref, bun))
+}
+
+func TestNewIndex(t *testing.T) {
+	idx := NewIndex([]Listing{
+		{Name: "wordpress", Reference: "example.com/wordpress:v1.0.0"},
+		{Name: "mysql", Reference: "example.com/mysql:v2.0.0"},
+		{Name: "mysql", Reference: "example.com/bitnami/mysql:v1.0.0"},
+	})
+
+	assert.Equal(t, SchemaVersion, idx.SchemaVersion)
+	require.Len(t, idx.Bundles, 3)
+	assert.Equal(t, "example.com/bitnami/mysql:v1.0.0", idx.Bundles[0].Reference)
+	assert.Equal(t, "example.com/mysql:v2.0.0", idx.Bundles[1].Reference)
+	assert.Equal(t, "example.com/wordpress:v1.0.0", idx.Bundles[2].Reference)
+}
+
+func TestParseIndex(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		idx, err := ParseIndex([]byte(`{"schemaVersion":"1.0.0","bundles":[{"name":"mysql","reference":"example.com/mysql:v1.0.0","keywords":["database"]}]}`))
+		require.NoError(t, err)
+		require.Len(t, idx.Bundles, 1)
+		assert.Equal(t, []string{"database"}, idx.Bundles[0].Keywords)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		want := NewIndex([]Listing{{Name: "mysql", Version: "1.0.0", Reference: "example.com/mysql:v1.0.0"}})
+		data, err := want.Marshal()
+		require.NoError(t, err)
+
+		got, err := ParseIndex(data)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := ParseIndex([]byte(`<html>`))
+		require.ErrorContains(t, err, "error parsing the bundle index")
+	})
+
+	t.Run("missing schema version", func(t *testing.T) {
+		_, err := ParseIndex([]byte(`{"bundles":[]}`))
+		require.ErrorContains(t, err, "schemaVersion is required")
+	})
+
+	t.Run("unsupported schema version", func(t *testing.T) {
+		_, err := ParseIndex([]byte(`{"schemaVersion":"2.0.0","bundles":[]}`))
+		require.ErrorContains(t, err, "unsupported bundle index schemaVersion 2.0.0")
+	})
+
+	t.Run("missing reference", func(t *testing.T) {
+		_, err := ParseIndex([]byte(`{"schemaVersion":"1.0.0","bundles":[{"name":"mysql"}]}`))
+		require.ErrorContains(t, err, "the name and reference are required for every bundle")
+	})
+}
+
+func TestLatestVersionTag(t *testing.T) {
+	tags := []string{"latest", "v1.0.0", "v1.2.0", "v1.10.0", "v2.0.0-beta.1", "canary"}
+
+	tag, ok := LatestVersionTag(tags, false)
+	require.True(t, ok)
+	assert.Equal(t, "v1.10.0", tag)
+
+	tag, ok = LatestVersionTag(tags, true)
+	require.True(t, ok)
+	assert.Equal(t, "v2.0.0-beta.1", tag)
+
+	_, ok = LatestVersionTag([]string{"latest", "canary"}, true)
+	assert.False(t, ok)
+}
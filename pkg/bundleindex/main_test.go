@@ -0,0 +1,11 @@
+package bundleindex
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/test"
+)
+
+func TestMain(m *testing.M) {
+	test.TestMainWithMockedCommandHandlers(m)
+}
@@ -0,0 +1,100 @@
+package bundleindex
+
+import (
+	"sort"
+	"strings"
+)
+
+// Result is a bundle that matched a search.
+type Result struct {
+	Listing `yaml:",inline"`
+
+	// Source is the name of the index that contains the bundle.
+	Source string `json:"source" yaml:"source"`
+
+	// score ranks how closely the bundle matched the search.
+	score int
+}
+
+// SourceIndex is an index loaded from a source.
+type SourceIndex struct {
+	// Source is the name of the index.
+	Source string
+
+	// Index loaded from the source.
+	Index Index
+}
+
+// Search the indexes for bundles that match every term in the query, returning the
+// best matches first. Every bundle is returned, sorted by name, when the query is empty.
+//
+// The indexes are merged in order, so when the same bundle reference is listed by multiple
+// indexes, the result is from the first index.
+func Search(indexes []SourceIndex, query string) []Result {
+	terms := strings.Fields(strings.ToLower(query))
+
+	seen := make(map[string]bool)
+	var results []Result
+	for _, idx := range indexes {
+		for _, b := range idx.Index.Bundles {
+			if seen[b.Reference] {
+				continue
+			}
+			seen[b.Reference] = true
+
+			score, ok := scoreListing(b, terms)
+			if !ok {
+				continue
+			}
+			results = append(results, Result{Listing: b, Source: idx.Source, score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// scoreListing ranks how closely the bundle matches the search terms.
+// Matches on the name of the bundle rank higher than matches on its keywords, description or reference.
+// Returns false when a term does not match the bundle.
+func scoreListing(b Listing, terms []string) (int, bool) {
+	total := 0
+	for _, term := range terms {
+		score := scoreTerm(b, term)
+		if score == 0 {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+func scoreTerm(b Listing, term string) int {
+	name := strings.ToLower(b.Name)
+	switch {
+	case name == term:
+		return 100
+	case strings.HasPrefix(name, term):
+		return 50
+	case strings.Contains(name, term):
+		return 30
+	}
+
+	for _, keyword := range b.Keywords {
+		if strings.ToLower(keyword) == term {
+			return 20
+		}
+	}
+	switch {
+	case containsFold(b.Description, term):
+		return 10
+	case containsFold(b.Reference, term):
+		return 5
+	}
+	return 0
+}
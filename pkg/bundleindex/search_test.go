@@ -0,0 +1,60 @@
+package bundleindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	community := SourceIndex{Source: "community", Index: NewIndex([]Listing{
+		{Name: "mysql", Description: "A MySQL database", Reference: "example.com/mysql:v1.0.0", Keywords: []string{"database"}},
+		{Name: "mysql-operator", Description: "Manage MySQL on Kubernetes", Reference: "example.com/mysql-operator:v1.0.0", Keywords: []string{"kubernetes"}},
+		{Name: "wordpress", Description: "A blog backed by a mysql database", Reference: "example.com/wordpress:v1.0.0"},
+		{Name: "postgres", Description: "A PostgreSQL database", Reference: "example.com/postgres:v1.0.0", Keywords: []string{"database"}},
+	})}
+	internal := SourceIndex{Source: "internal", Index: NewIndex([]Listing{
+		{Name: "mysql", Description: "Our MySQL database", Reference: "example.com/mysql:v1.0.0"},
+		{Name: "my-sql-proxy", Reference: "internal.example.com/mysql/proxy:v1.0.0"},
+	})}
+
+	names := func(results []Result) []string {
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Source+"/"+r.Name)
+		}
+		return got
+	}
+
+	t.Run("ranked by match", func(t *testing.T) {
+		results := Search([]SourceIndex{community, internal}, "MySQL")
+		assert.Equal(t, []string{"community/mysql", "community/mysql-operator", "community/wordpress", "internal/my-sql-proxy"}, names(results))
+	})
+
+	t.Run("first index wins", func(t *testing.T) {
+		results := Search([]SourceIndex{internal, community}, "mysql")
+		require.NotEmpty(t, results)
+		assert.Equal(t, "internal", results[0].Source)
+		assert.Equal(t, "Our MySQL database", results[0].Description)
+	})
+
+	t.Run("every term must match", func(t *testing.T) {
+		results := Search([]SourceIndex{community}, "mysql kubernetes")
+		assert.Equal(t, []string{"community/mysql-operator"}, names(results))
+	})
+
+	t.Run("keyword", func(t *testing.T) {
+		results := Search([]SourceIndex{community}, "database")
+		assert.Equal(t, []string{"community/mysql", "community/postgres", "community/wordpress"}, names(results))
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		results := Search([]SourceIndex{community}, "")
+		assert.Equal(t, []string{"community/mysql", "community/mysql-operator", "community/postgres", "community/wordpress"}, names(results))
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		assert.Empty(t, Search([]SourceIndex{community}, "redis"))
+	})
+}
@@ -0,0 +1,228 @@
+package bundleindex
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source loads a bundle index.
+type Source interface {
+	// Name of the index.
+	Name() string
+
+	// Load the bundle index from the source.
+	Load(ctx context.Context) (Index, error)
+}
+
+// NewSource creates a source for the configured bundle index.
+// The crane options are used to connect to the registry of an OCI index.
+func NewSource(c *portercontext.Context, cfg config.BundleIndex, craneOpts ...crane.Option) (Source, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case config.BundleIndexTypeHTTP:
+		return NewHTTPSource(cfg.Name, cfg.URL), nil
+	case config.BundleIndexTypeOCI:
+		return NewOCISource(cfg.Name, cfg.Reference, craneOpts...), nil
+	default:
+		return NewGitSource(c, cfg.Name, cfg.URL, cfg.Branch, cfg.GetPath()), nil
+	}
+}
+
+var _ Source = HTTPSource{}
+
+// HTTPSource downloads a bundle index from a URL.
+type HTTPSource struct {
+	name string
+	url  string
+}
+
+// NewHTTPSource creates a source that downloads the index from the URL.
+func NewHTTPSource(name string, url string) HTTPSource {
+	return HTTPSource{name: name, url: url}
+}
+
+func (s HTTPSource) Name() string {
+	return s.name
+}
+
+func (s HTTPSource) Load(ctx context.Context) (Index, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return Index{}, fmt.Errorf("invalid bundle index url %s: %w", s.url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Index{}, fmt.Errorf("unable to fetch the bundle index from %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Index{}, fmt.Errorf("unable to fetch the bundle index from %s: %s", s.url, http.StatusText(resp.StatusCode))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Index{}, fmt.Errorf("unable to read the bundle index from %s: %w", s.url, err)
+	}
+	return ParseIndex(data)
+}
+
+var _ Source = OCISource{}
+
+// OCISource pulls a bundle index that is published to a registry as an OCI artifact.
+type OCISource struct {
+	name      string
+	reference string
+	craneOpts []crane.Option
+}
+
+// NewOCISource creates a source that pulls the index from the OCI artifact at the reference.
+func NewOCISource(name string, reference string, craneOpts ...crane.Option) OCISource {
+	return OCISource{name: name, reference: reference, craneOpts: craneOpts}
+}
+
+func (s OCISource) Name() string {
+	return s.name
+}
+
+func (s OCISource) Load(ctx context.Context) (Index, error) {
+	opts := append([]crane.Option{crane.WithContext(ctx)}, s.craneOpts...)
+	img, err := crane.Pull(s.reference, opts...)
+	if err != nil {
+		return Index{}, fmt.Errorf("unable to pull the bundle index from %s: %w", s.reference, err)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return Index{}, fmt.Errorf("unable to read the bundle index from %s: %w", s.reference, err)
+	}
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil || mediaType != MediaType {
+			continue
+		}
+
+		// The index is stored as-is, so the "compressed" content is the index
+		rc, err := layer.Compressed()
+		if err != nil {
+			return Index{}, fmt.Errorf("unable to read the bundle index from %s: %w", s.reference, err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return Index{}, fmt.Errorf("unable to read the bundle index from %s: %w", s.reference, err)
+		}
+		return ParseIndex(data)
+	}
+	return Index{}, fmt.Errorf("%s is not a bundle index, it does not have a layer with the media type %s", s.reference, MediaType)
+}
+
+// Push publishes the index to a registry as an OCI artifact, returning the digest of the artifact.
+func Push(ctx context.Context, idx Index, reference string, craneOpts ...crane.Option) (string, error) {
+	data, err := idx.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	img, err := mutate.AppendLayers(empty.Image, static.NewLayer(data, MediaType))
+	if err != nil {
+		return "", fmt.Errorf("error creating the bundle index artifact: %w", err)
+	}
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, ArtifactType)
+
+	opts := append([]crane.Option{crane.WithContext(ctx)}, craneOpts...)
+	if err = crane.Push(img, reference, opts...); err != nil {
+		return "", fmt.Errorf("error publishing the bundle index to %s: %w", reference, err)
+	}
+
+	d, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+var _ Source = GitSource{}
+
+// GitSource reads a bundle index from a git repository with the git CLI.
+type GitSource struct {
+	*portercontext.Context
+
+	name   string
+	url    string
+	branch string
+	path   string
+}
+
+// NewGitSource creates a source that reads the index from the file at the path in the git repository.
+func NewGitSource(c *portercontext.Context, name string, url string, branch string, path string) GitSource {
+	return GitSource{Context: c, name: name, url: url, branch: branch, path: path}
+}
+
+func (s GitSource) Name() string {
+	return s.name
+}
+
+func (s GitSource) Load(ctx context.Context) (Index, error) {
+	ctx, log := tracing.StartSpan(ctx, attribute.String("url", s.url), attribute.String("branch", s.branch))
+	defer log.EndSpan()
+
+	tmpDir, err := s.FileSystem.TempDir("", "porter-bundle-index")
+	if err != nil {
+		return Index{}, log.Errorf("error creating a temporary directory for the git repository: %w", err)
+	}
+	defer s.FileSystem.RemoveAll(tmpDir)
+	repoDir := filepath.Join(tmpDir, "repo")
+
+	cloneArgs := []string{"clone", "--depth", "1"}
+	if s.branch != "" {
+		cloneArgs = append(cloneArgs, "--branch", s.branch)
+	}
+	cloneArgs = append(cloneArgs, s.url, repoDir)
+	if _, err = s.runGit(ctx, cloneArgs...); err != nil {
+		return Index{}, log.Errorf("unable to clone the bundle index repository %s: %w", s.url, err)
+	}
+
+	data, err := s.runGit(ctx, "-C", repoDir, "show", "HEAD:"+s.path)
+	if err != nil {
+		return Index{}, log.Errorf("unable to read %s from the bundle index repository %s: %w", s.path, s.url, err)
+	}
+	idx, err := ParseIndex(data)
+	return idx, log.Error(err)
+}
+
+// runGit executes the git CLI and returns its standard output,
+// including the standard error of git in the returned error when it fails.
+func (s GitSource) runGit(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := s.NewCommand(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("could not run git, make sure that it is installed and on the PATH: %w", err)
+		}
+		return nil, fmt.Errorf("git failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
@@ -0,0 +1,115 @@
+package bundleindex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIndex = `{"schemaVersion":"1.0.0","bundles":[{"name":"mysql","reference":"example.com/mysql:v1.0.0"}]}`
+
+func TestNewSource(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+
+	s, err := NewSource(c.Context, config.BundleIndex{Name: "community", Type: config.BundleIndexTypeHTTP, URL: "https://example.com/index.json"})
+	require.NoError(t, err)
+	assert.IsType(t, HTTPSource{}, s)
+	assert.Equal(t, "community", s.Name())
+
+	s, err = NewSource(c.Context, config.BundleIndex{Name: "internal", Type: config.BundleIndexTypeOCI, Reference: "example.com/index:latest"})
+	require.NoError(t, err)
+	assert.IsType(t, OCISource{}, s)
+
+	s, err = NewSource(c.Context, config.BundleIndex{Name: "team", Type: config.BundleIndexTypeGit, URL: "https://example.com/index.git"})
+	require.NoError(t, err)
+	assert.IsType(t, GitSource{}, s)
+
+	_, err = NewSource(c.Context, config.BundleIndex{Name: "team", Type: "svn"})
+	require.ErrorContains(t, err, "invalid bundle-indexes.type")
+}
+
+func TestHTTPSource_Load(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bundles/index.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(testIndex))
+	}))
+	defer srv.Close()
+
+	idx, err := NewHTTPSource("community", srv.URL+"/bundles/index.json").Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, idx.Bundles, 1)
+	assert.Equal(t, "mysql", idx.Bundles[0].Name)
+
+	_, err = NewHTTPSource("community", srv.URL+"/missing.json").Load(ctx)
+	require.ErrorContains(t, err, "Not Found")
+}
+
+func TestOCISource_Load(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	want := NewIndex([]Listing{{Name: "mysql", Version: "1.0.0", Reference: "example.com/mysql:v1.0.0"}})
+	ref := u.Host + "/bundle-index:latest"
+	digest, err := Push(ctx, want, ref, crane.Insecure)
+	require.NoError(t, err)
+	assert.Contains(t, digest, "sha256:")
+
+	manifest, err := crane.Manifest(ref, crane.Insecure)
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), ArtifactType)
+
+	got, err := NewOCISource("internal", ref, crane.Insecure).Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	t.Run("not an index", func(t *testing.T) {
+		img, err := crane.Image(map[string][]byte{"index.json": []byte(testIndex)})
+		require.NoError(t, err)
+		imgRef := u.Host + "/not-an-index:latest"
+		require.NoError(t, crane.Push(img, imgRef, crane.Insecure))
+
+		_, err = NewOCISource("internal", imgRef, crane.Insecure).Load(ctx)
+		require.ErrorContains(t, err, "is not a bundle index")
+	})
+}
+
+func TestGitSource_Load(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("index found", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandOutputEnv, testIndex)
+
+		idx, err := NewGitSource(c.Context, "team", "https://example.com/index.git", "main", "index.json").Load(ctx)
+		require.NoError(t, err)
+		require.Len(t, idx.Bundles, 1)
+		assert.Equal(t, "mysql", idx.Bundles[0].Name)
+	})
+
+	t.Run("git fails", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "128")
+		c.Setenv(test.ExpectedCommandErrorEnv, "repository not found")
+
+		_, err := NewGitSource(c.Context, "team", "https://example.com/index.git", "", "index.json").Load(ctx)
+		require.ErrorContains(t, err, "unable to clone the bundle index repository https://example.com/index.git")
+		require.ErrorContains(t, err, "repository not found")
+	})
+}
@@ -1,23 +1,32 @@
 package cache
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
 	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/cnab"
 	configadapter "get.porter.sh/porter/pkg/cnab/config-adapter"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/encoding"
+	"get.porter.sh/porter/pkg/tracing"
 	"github.com/opencontainers/go-digest"
 )
 
 type BundleCache interface {
-	FindBundle(tag cnab.OCIReference) (bun CachedBundle, found bool, err error)
-	StoreBundle(bundleRef cnab.BundleReference) (CachedBundle, error)
+	FindBundle(ctx context.Context, tag cnab.OCIReference) (bun CachedBundle, found bool, err error)
+	StoreBundle(ctx context.Context, bundleRef cnab.BundleReference) (CachedBundle, error)
 	GetCacheDir() (string, error)
+	ListReferences() ([]cnab.OCIReference, error)
+	ListBundles() ([]Entry, error)
+	RemoveBundle(ref cnab.OCIReference) error
+	Clear() error
 }
 
 var _ BundleCache = &Cache{}
@@ -37,7 +46,10 @@ func New(cfg *config.Config) BundleCache {
 // empty string and the boolean false value are returned. If the bundle is found,
 // and a relocation mapping file is present, it will be returned as well. If the relocation
 // is not found, an empty string is returned.
-func (c *Cache) FindBundle(ref cnab.OCIReference) (CachedBundle, bool, error) {
+func (c *Cache) FindBundle(ctx context.Context, ref cnab.OCIReference) (CachedBundle, bool, error) {
+	_, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
 	cb := CachedBundle{}
 	cb.Reference = ref
 
@@ -54,15 +66,23 @@ func (c *Cache) FindBundle(ref cnab.OCIReference) (CachedBundle, bool, error) {
 	if !found {
 		return CachedBundle{}, false, nil
 	}
-	return cb, true, nil
 
+	// Record when the bundle was last used, so that the least recently used bundles are evicted first
+	now := time.Now()
+	if err = c.FileSystem.Chtimes(cb.BuildMetadataPath(), now, now); err != nil {
+		span.Warnf("could not record that the cached bundle %s was used: %s", ref, err)
+	}
+	return cb, true, nil
 }
 
 // StoreBundle will write a given bundle to the bundle cache, in a location derived
 // from the bundleTag. If a relocation mapping is provided, it will be stored along side
 // the bundle. If successful, returns the path to the bundle, along with the path to a
 // relocation mapping, if provided. Otherwise, returns an error.
-func (c *Cache) StoreBundle(bundleRef cnab.BundleReference) (CachedBundle, error) {
+func (c *Cache) StoreBundle(ctx context.Context, bundleRef cnab.BundleReference) (CachedBundle, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
 	cb := CachedBundle{BundleReference: bundleRef}
 
 	cacheDir, err := c.GetCacheDir()
@@ -99,7 +119,7 @@ func (c *Cache) StoreBundle(bundleRef cnab.BundleReference) (CachedBundle, error
 		return CachedBundle{}, err
 	}
 
-	err = c.cacheManifest(&cb)
+	err = c.cacheManifest(ctx, &cb)
 	if err != nil {
 		return CachedBundle{}, err
 	}
@@ -125,6 +145,8 @@ func (c *Cache) StoreBundle(bundleRef cnab.BundleReference) (CachedBundle, error
 
 	}
 
+	c.evictBundles(ctx, cb.Reference)
+
 	return cb, nil
 }
 
@@ -146,16 +168,18 @@ type Metadata struct {
 
 // cacheManifest extracts the porter.yaml from the bundle, if present and caches it
 // in the same cache directory as the rest of the bundle.
-func (c *Cache) cacheManifest(cb *CachedBundle) error {
+func (c *Cache) cacheManifest(ctx context.Context, cb *CachedBundle) error {
+	log := tracing.LoggerFromContext(ctx)
+
 	if cb.Definition.IsPorterBundle() {
 		stamp, err := configadapter.LoadStamp(cb.Definition)
 		if err != nil {
-			fmt.Fprintf(c.Err, "WARNING: Bundle %s was created by porter but could not load the Porter stamp. This may be because it was created by an older version of Porter.\n", cb.Reference)
+			log.Warnf("Bundle %s was created by porter but could not load the Porter stamp. This may be because it was created by an older version of Porter.", cb.Reference)
 			return nil
 		}
 
 		if stamp.EncodedManifest == "" {
-			fmt.Fprintf(c.Err, "WARNING: Bundle %s was created by porter but could not find a porter manifest embedded. This may be because it was created by an older version of Porter.\n", cb.Reference)
+			log.Warnf("Bundle %s was created by porter but could not find a porter manifest embedded. This may be because it was created by an older version of Porter.", cb.Reference)
 			return nil
 		}
 
@@ -176,3 +200,194 @@ func (c *Cache) GetCacheDir() (string, error) {
 	}
 	return filepath.Join(home, "cache"), nil
 }
+
+// ListReferences returns the references of the cached bundles, starting
+// with the most recently used bundle.
+func (c *Cache) ListReferences() ([]cnab.OCIReference, error) {
+	entries, err := c.ListBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]cnab.OCIReference, len(entries))
+	for i, entry := range entries {
+		results[i] = entry.Reference
+	}
+	return results, nil
+}
+
+// Entry describes a bundle in the cache.
+type Entry struct {
+	// Reference of the cached bundle.
+	Reference cnab.OCIReference
+
+	// Digest of the cached bundle.
+	Digest digest.Digest
+
+	// Size of the cached files in bytes.
+	Size int64
+
+	// LastUsed is when the bundle was cached, or last resolved from the cache.
+	LastUsed time.Time
+}
+
+// ListBundles returns the cached bundles, starting with the most recently used bundle.
+func (c *Cache) ListBundles() ([]Entry, error) {
+	cacheDir, err := c.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := c.FileSystem.DirExists(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to access the bundle cache at %s: %w", cacheDir, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	dirs, err := c.FileSystem.ReadDir(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the bundle cache at %s: %w", cacheDir, err)
+	}
+
+	var entries []Entry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		metaPath := filepath.Join(cacheDir, dir.Name(), "metadata.json")
+		metaInfo, err := c.FileSystem.Stat(metaPath)
+		if err != nil {
+			// Bundles cached by older versions of porter do not have metadata
+			continue
+		}
+
+		var meta Metadata
+		if err = encoding.UnmarshalFile(c.FileSystem, metaPath, &meta); err != nil {
+			return nil, fmt.Errorf("unable to parse cached bundle metadata at %s: %w", metaPath, err)
+		}
+
+		size, err := c.getDirSize(filepath.Join(cacheDir, dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, Entry{
+			Reference: meta.Reference,
+			Digest:    meta.Digest,
+			Size:      size,
+			LastUsed:  metaInfo.ModTime(),
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+// getDirSize returns the total size of the files in a directory.
+func (c *Cache) getDirSize(dir string) (int64, error) {
+	var size int64
+	err := c.FileSystem.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to determine the size of the cached bundle at %s: %w", dir, err)
+	}
+	return size, nil
+}
+
+// RemoveBundle removes a bundle from the cache. Removing a bundle that is not
+// cached is not an error.
+func (c *Cache) RemoveBundle(ref cnab.OCIReference) error {
+	cacheDir, err := c.GetCacheDir()
+	if err != nil {
+		return err
+	}
+
+	cb := CachedBundle{BundleReference: cnab.BundleReference{Reference: ref}}
+	cb.SetCacheDir(cacheDir)
+	if err = c.FileSystem.RemoveAll(cb.cacheDir); err != nil {
+		return fmt.Errorf("unable to remove cached bundle %s from %s: %w", ref, cb.cacheDir, err)
+	}
+	return nil
+}
+
+// Clear removes every bundle from the cache, including bundles cached by older
+// versions of porter.
+func (c *Cache) Clear() error {
+	cacheDir, err := c.GetCacheDir()
+	if err != nil {
+		return err
+	}
+
+	if err = c.FileSystem.RemoveAll(cacheDir); err != nil {
+		return fmt.Errorf("unable to clear the bundle cache at %s: %w", cacheDir, err)
+	}
+	return nil
+}
+
+// SelectEvictions returns the bundles that are evicted so that the cache is no
+// larger than maxSize. The entries must be sorted from the most to the least
+// recently used, and the least recently used bundles are evicted first.
+// The most recently used bundle is always kept.
+func SelectEvictions(entries []Entry, maxSize int64) []Entry {
+	if maxSize <= 0 {
+		return nil
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+
+	var evicted []Entry
+	for i := len(entries) - 1; i > 0 && total > maxSize; i-- {
+		evicted = append(evicted, entries[i])
+		total -= entries[i].Size
+	}
+	return evicted
+}
+
+// evictBundles removes the least recently used bundles when the cache is larger
+// than cache.max-size in the Porter configuration file. The bundle that was
+// just cached is always kept. Failing to evict bundles does not fail caching
+// the bundle, so errors are only logged as warnings.
+func (c *Cache) evictBundles(ctx context.Context, cached cnab.OCIReference) {
+	_, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	maxSize, err := c.Data.Cache.GetMaxSize()
+	if err != nil {
+		span.Warnf("skipping evicting bundles from the cache: invalid cache.max-size in the Porter configuration file: %s", err)
+		return
+	}
+	if maxSize == 0 {
+		return
+	}
+
+	entries, err := c.ListBundles()
+	if err != nil {
+		span.Warnf("could not evict bundles from the cache: %s", err)
+		return
+	}
+
+	for _, entry := range SelectEvictions(entries, maxSize) {
+		if entry.Reference.String() == cached.String() {
+			continue
+		}
+		if err = c.RemoveBundle(entry.Reference); err != nil {
+			span.Warnf("could not evict bundles from the cache: %s", err)
+			return
+		}
+	}
+}
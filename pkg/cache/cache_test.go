@@ -1,10 +1,12 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/config"
@@ -32,7 +34,7 @@ func TestFindBundleCacheExists(t *testing.T) {
 	cfg.TestContext.AddTestDirectory("testdata", cacheDir)
 	c := New(cfg.Config)
 
-	_, ok, err := c.FindBundle(kahnlatest)
+	_, ok, err := c.FindBundle(context.Background(), kahnlatest)
 	assert.NoError(t, err, "the cache dir should exist, no error should have happened")
 	assert.False(t, ok, "the bundle shouldn't exist")
 }
@@ -47,7 +49,7 @@ func TestFindBundleCacheDoesNotExist(t *testing.T) {
 	cfg.TestContext.AddTestDirectory("testdata", cacheDir)
 	c := New(cfg.Config)
 
-	_, ok, err := c.FindBundle(kahnlatest)
+	_, ok, err := c.FindBundle(context.Background(), kahnlatest)
 	assert.NoError(t, err, "the cache dir doesn't exist, but this shouldn't be an error")
 	assert.False(t, ok, "the bundle shouldn't exist")
 }
@@ -68,7 +70,7 @@ func TestFindBundleBundleCached(t *testing.T) {
 	require.True(t, foundIt, "test data not loaded")
 	c := New(cfg.Config)
 
-	cb, ok, err := c.FindBundle(kahn1dot01)
+	cb, ok, err := c.FindBundle(context.Background(), kahn1dot01)
 	require.NoError(t, err, "the cache dir should exist, no error should have happened")
 	require.True(t, ok, "the bundle should exist")
 	assert.Equal(t, expectedCacheFile, cb.BundlePath)
@@ -79,7 +81,7 @@ func TestFindBundleBundleNotCached(t *testing.T) {
 
 	cfg := config.NewTestConfig(t)
 	c := New(cfg.Config)
-	cb, ok, err := c.FindBundle(kahnlatest)
+	cb, ok, err := c.FindBundle(context.Background(), kahnlatest)
 	require.NoError(t, err, "the cache dir should exist, no error should have happened")
 	assert.False(t, ok, "the bundle should not exist")
 	assert.Empty(t, cb.BundlePath, "should not have a path")
@@ -94,7 +96,7 @@ func TestCacheWriteNoCacheDir(t *testing.T) {
 	require.NoError(t, err, "bundle should have been valid")
 
 	c := New(cfg.Config)
-	cb, err := c.StoreBundle(cnab.BundleReference{Reference: kahn1dot01, Definition: bun})
+	cb, err := c.StoreBundle(context.Background(), cnab.BundleReference{Reference: kahn1dot01, Definition: bun})
 	assert.NoError(t, err, "storing bundle should have succeeded")
 
 	home, err := cfg.Config.GetHomeDir()
@@ -127,7 +129,7 @@ func TestCacheWriteCacheDirExists(t *testing.T) {
 		RelocationMap: reloMap,
 		Digest:        digest.Digest("sha256:2249472f86d0cea9ac8809331931e9100e1d0464afff3d2869bbb8dedfe2d396"),
 	}
-	cb, err := c.StoreBundle(bundleRef)
+	cb, err := c.StoreBundle(context.Background(), bundleRef)
 
 	expectedCacheDirectory := filepath.Join(cacheDir, kahn1dot0Hash)
 	expectedCacheCNABDirectory := filepath.Join(expectedCacheDirectory, "cnab")
@@ -176,11 +178,11 @@ func TestStoreRelocationMapping(t *testing.T) {
 			cfg := config.NewTestConfig(t)
 			c := New(cfg.Config)
 
-			cb, err := c.StoreBundle(cnab.BundleReference{Reference: tc.tag, Definition: tc.bundle, RelocationMap: tc.relocationMapping})
+			cb, err := c.StoreBundle(context.Background(), cnab.BundleReference{Reference: tc.tag, Definition: tc.bundle, RelocationMap: tc.relocationMapping})
 			assert.NoError(t, err, fmt.Sprintf("didn't expect storage error for test %s", tc.name))
 			assert.Equal(t, tc.wantedReloPath, cb.RelocationFilePath, "didn't get expected path for store")
 
-			cb, _, err = c.FindBundle(tc.tag)
+			cb, _, err = c.FindBundle(context.Background(), tc.tag)
 			assert.NoError(t, err, "didn't expect find bundle error for test %s", tc.tag)
 			assert.Equal(t, tc.wantedReloPath, cb.RelocationFilePath, "didn't get expected path for load")
 		})
@@ -237,7 +239,7 @@ func TestStoreManifest(t *testing.T) {
 			cfg.TestContext.AddTestDirectory("testdata", cacheDir)
 			c := New(cfg.Config)
 
-			cb, err := c.StoreBundle(cnab.BundleReference{Reference: tc.tag, Definition: tc.bundle})
+			cb, err := c.StoreBundle(context.Background(), cnab.BundleReference{Reference: tc.tag, Definition: tc.bundle})
 			require.NoError(t, err, "StoreBundle failed")
 
 			cachedManifestExists, _ := cfg.FileSystem.Exists(cb.BuildManifestPath())
@@ -271,7 +273,7 @@ func TestCache_StoreBundle_Overwrite(t *testing.T) {
 	cfg.FileSystem.Create(junkPath)
 
 	// Refresh the cache
-	cb, err := c.StoreBundle(cb.BundleReference)
+	cb, err := c.StoreBundle(context.Background(), cb.BundleReference)
 	require.NoError(t, err, "StoreBundle failed")
 
 	exists, _ := cfg.FileSystem.Exists(cb.BuildBundlePath())
@@ -286,3 +288,156 @@ func TestCache_StoreBundle_Overwrite(t *testing.T) {
 	exists, _ = cfg.FileSystem.Exists(junkPath)
 	assert.False(t, exists, "the random file should have been deleted from the bundle cache")
 }
+
+func TestCache_ListReferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no cache dir", func(t *testing.T) {
+		cfg := config.NewTestConfig(t)
+		c := New(cfg.Config)
+
+		refs, err := c.ListReferences()
+		require.NoError(t, err)
+		assert.Empty(t, refs)
+	})
+
+	t.Run("most recent first", func(t *testing.T) {
+		cfg := config.NewTestConfig(t)
+		home, _ := cfg.Config.GetHomeDir()
+		cacheDir := filepath.Join(home, "cache")
+		cfg.TestContext.AddTestDirectory("testdata", cacheDir)
+		c := New(cfg.Config)
+
+		cb, err := c.StoreBundle(context.Background(), cnab.BundleReference{Reference: kahnlatest})
+		require.NoError(t, err, "StoreBundle failed")
+		oldTime := time.Now().Add(-time.Hour)
+		require.NoError(t, cfg.FileSystem.Chtimes(filepath.Join(cacheDir, kahn1dot0Hash, "metadata.json"), oldTime, oldTime))
+
+		refs, err := c.ListReferences()
+		require.NoError(t, err)
+		assert.Equal(t, []cnab.OCIReference{cb.Reference, kahn1dot01}, refs)
+	})
+}
+
+func TestCache_FindBundle_RecordsLastUsed(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewTestConfig(t)
+	home, _ := cfg.Config.GetHomeDir()
+	cacheDir := filepath.Join(home, "cache")
+	cfg.TestContext.AddTestDirectory("testdata", cacheDir)
+	c := New(cfg.Config)
+
+	metaPath := filepath.Join(cacheDir, kahn1dot0Hash, "metadata.json")
+	oldTime := time.Now().Add(-time.Hour)
+	require.NoError(t, cfg.FileSystem.Chtimes(metaPath, oldTime, oldTime))
+
+	_, ok, err := c.FindBundle(context.Background(), kahn1dot01)
+	require.NoError(t, err)
+	require.True(t, ok, "the bundle should exist")
+
+	entries, err := c.ListBundles()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].LastUsed.After(oldTime), "the last used time should be updated when the bundle is found")
+}
+
+func TestCache_ListBundles(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewTestConfig(t)
+	home, _ := cfg.Config.GetHomeDir()
+	cacheDir := filepath.Join(home, "cache")
+	cfg.TestContext.AddTestDirectory("testdata", cacheDir)
+	c := New(cfg.Config)
+
+	entries, err := c.ListBundles()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, kahn1dot01, entries[0].Reference)
+	assert.Greater(t, entries[0].Size, int64(0), "the size of the cached files should be reported")
+}
+
+func TestCache_RemoveBundle(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewTestConfig(t)
+	home, _ := cfg.Config.GetHomeDir()
+	cacheDir := filepath.Join(home, "cache")
+	cfg.TestContext.AddTestDirectory("testdata", cacheDir)
+	c := New(cfg.Config)
+
+	require.NoError(t, c.RemoveBundle(kahn1dot01))
+	_, ok, err := c.FindBundle(context.Background(), kahn1dot01)
+	require.NoError(t, err)
+	assert.False(t, ok, "the bundle should have been removed from the cache")
+
+	require.NoError(t, c.RemoveBundle(kahnlatest), "removing a bundle that is not cached should not be an error")
+}
+
+func TestCache_Clear(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewTestConfig(t)
+	home, _ := cfg.Config.GetHomeDir()
+	cacheDir := filepath.Join(home, "cache")
+	cfg.TestContext.AddTestDirectory("testdata", cacheDir)
+	c := New(cfg.Config)
+
+	require.NoError(t, c.Clear())
+	exists, _ := cfg.FileSystem.Exists(cacheDir)
+	assert.False(t, exists, "the cache directory should have been removed")
+
+	entries, err := c.ListBundles()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSelectEvictions(t *testing.T) {
+	entries := []Entry{
+		{Reference: cnab.MustParseOCIReference("example.com/newest:v1"), Size: 100},
+		{Reference: cnab.MustParseOCIReference("example.com/middle:v1"), Size: 100},
+		{Reference: cnab.MustParseOCIReference("example.com/oldest:v1"), Size: 100},
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		assert.Empty(t, SelectEvictions(entries, 0))
+	})
+
+	t.Run("under the limit", func(t *testing.T) {
+		assert.Empty(t, SelectEvictions(entries, 300))
+	})
+
+	t.Run("least recently used first", func(t *testing.T) {
+		evicted := SelectEvictions(entries, 150)
+		require.Len(t, evicted, 2)
+		assert.Equal(t, "example.com/oldest:v1", evicted[0].Reference.String())
+		assert.Equal(t, "example.com/middle:v1", evicted[1].Reference.String())
+	})
+
+	t.Run("keep the most recent", func(t *testing.T) {
+		evicted := SelectEvictions(entries, 10)
+		assert.Len(t, evicted, 2, "the most recently used bundle should be kept even when it is larger than the limit")
+	})
+}
+
+func TestCache_StoreBundle_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewTestConfig(t)
+	home, _ := cfg.Config.GetHomeDir()
+	cacheDir := filepath.Join(home, "cache")
+	cfg.TestContext.AddTestDirectory("testdata", cacheDir)
+	cfg.Data.Cache.MaxSize = "1"
+	c := New(cfg.Config)
+
+	oldTime := time.Now().Add(-time.Hour)
+	require.NoError(t, cfg.FileSystem.Chtimes(filepath.Join(cacheDir, kahn1dot0Hash, "metadata.json"), oldTime, oldTime))
+
+	_, err := c.StoreBundle(context.Background(), cnab.BundleReference{Reference: kahnlatest})
+	require.NoError(t, err, "StoreBundle failed")
+
+	refs, err := c.ListReferences()
+	require.NoError(t, err)
+	assert.Equal(t, []cnab.OCIReference{kahnlatest}, refs, "the least recently used bundle should have been evicted")
+}
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"context"
+
 	"get.porter.sh/porter/pkg/cnab"
 )
 
@@ -19,20 +21,36 @@ func NewTestCache(cache BundleCache) *TestCache {
 	}
 }
 
-func (c *TestCache) FindBundle(ref cnab.OCIReference) (CachedBundle, bool, error) {
+func (c *TestCache) FindBundle(ctx context.Context, ref cnab.OCIReference) (CachedBundle, bool, error) {
 	if c.FindBundleMock != nil {
 		return c.FindBundleMock(ref)
 	}
-	return c.cache.FindBundle(ref)
+	return c.cache.FindBundle(ctx, ref)
 }
 
-func (c *TestCache) StoreBundle(bundleRef cnab.BundleReference) (CachedBundle, error) {
+func (c *TestCache) StoreBundle(ctx context.Context, bundleRef cnab.BundleReference) (CachedBundle, error) {
 	if c.StoreBundleMock != nil {
 		return c.StoreBundleMock(bundleRef)
 	}
-	return c.cache.StoreBundle(bundleRef)
+	return c.cache.StoreBundle(ctx, bundleRef)
 }
 
 func (c *TestCache) GetCacheDir() (string, error) {
 	return c.cache.GetCacheDir()
 }
+
+func (c *TestCache) ListReferences() ([]cnab.OCIReference, error) {
+	return c.cache.ListReferences()
+}
+
+func (c *TestCache) ListBundles() ([]Entry, error) {
+	return c.cache.ListBundles()
+}
+
+func (c *TestCache) RemoveBundle(ref cnab.OCIReference) error {
+	return c.cache.RemoveBundle(ref)
+}
+
+func (c *TestCache) Clear() error {
+	return c.cache.Clear()
+}
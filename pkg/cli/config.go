@@ -80,6 +80,8 @@ func getViperValue(flags *pflag.FlagSet, f *pflag.Flag) interface{} {
 		out, err = flags.GetStringSlice(f.Name)
 	case "stringArray":
 		out, err = flags.GetStringArray(f.Name)
+	case "duration":
+		out, err = flags.GetDuration(f.Name)
 	default:
 		panic(fmt.Errorf("unsupported type for conversion between flag %s and viper configuration: %T", f.Name, flagType))
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/config"
 	"github.com/spf13/cobra"
@@ -15,9 +16,11 @@ func TestLoadHierarchicalConfig(t *testing.T) {
 	// Cannot be run in parallel because viper reads directly from env vars
 	buildCommand := func(c *config.Config) *cobra.Command {
 		var buildDriver string
+		var timeout time.Duration
 		cmd := &cobra.Command{}
 		cmd.Flags().StringVar(&c.Data.Verbosity, "verbosity", "info", "verbosity")
 		cmd.Flags().StringVar(&buildDriver, "driver", "", "build driver")
+		cmd.Flags().DurationVar(&timeout, "timeout", time.Second, "timeout")
 		cmd.Flag("driver").Annotations = map[string][]string{
 			"viper-key": {"build-driver"},
 		}
@@ -155,4 +158,15 @@ func TestLoadHierarchicalConfig(t *testing.T) {
 		require.NoError(t, err, "dataloader failed")
 		assert.Equal(t, "debug", c.Data.Verbosity, "config.Verbosity should have been set by the flag and not the env var or config")
 	})
+
+	t.Run("duration flag", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		c.SetHomeDir("/home/myuser/.porter")
+
+		cmd := buildCommand(c.Config)
+		cmd.SetArgs([]string{"--timeout=3s"})
+		err := cmd.Execute()
+
+		require.NoError(t, err, "dataloader failed")
+	})
 }
@@ -0,0 +1,73 @@
+package cnab
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ActionArgumentSchemaID is the $id of the definitions generated by Porter for
+// the arguments of a custom action.
+const ActionArgumentSchemaID = "https://getporter.org/generated-bundle/#porter-action-argument"
+
+// ActionArgument is an argument that may be passed to a custom action,
+// porter invoke --action ACTION --arg NAME=VALUE. An argument is passed to
+// the bundle as an internal parameter that only applies to the action.
+type ActionArgument struct {
+	// Name of the argument.
+	Name string
+
+	// Description of the argument.
+	Description string
+
+	// Default value of the argument.
+	Default string
+
+	// Required indicates that the argument must be specified when the action is invoked.
+	Required bool
+
+	// Parameter is the name of the internal parameter used to pass the argument to the bundle.
+	Parameter string
+}
+
+// GetActionArgumentParameterName builds the name of the internal parameter used by
+// Porter to pass an argument to a custom action.
+func GetActionArgumentParameterName(action string, argument string) string {
+	return fmt.Sprintf("porter-%s-%s-arg", action, argument)
+}
+
+// GetActionArguments returns the arguments accepted by a custom action, sorted by name.
+func (b ExtendedBundle) GetActionArguments(action string) []ActionArgument {
+	prefix := fmt.Sprintf("porter-%s-", action)
+	const suffix = "-arg"
+
+	var args []ActionArgument
+	for paramName, param := range b.Parameters {
+		if len(param.ApplyTo) != 1 || param.ApplyTo[0] != action {
+			continue
+		}
+		if !strings.HasPrefix(paramName, prefix) || !strings.HasSuffix(paramName, suffix) || len(paramName) <= len(prefix)+len(suffix) {
+			continue
+		}
+		def, ok := b.Definitions[param.Definition]
+		if !ok || def.ID != ActionArgumentSchemaID {
+			continue
+		}
+
+		arg := ActionArgument{
+			Name:        strings.TrimSuffix(strings.TrimPrefix(paramName, prefix), suffix),
+			Description: param.Description,
+			Required:    param.Required,
+			Parameter:   paramName,
+		}
+		if def.Default != nil {
+			arg.Default = fmt.Sprintf("%v", def.Default)
+		}
+		args = append(args, arg)
+	}
+
+	sort.Slice(args, func(i, j int) bool {
+		return args[i].Name < args[j].Name
+	})
+	return args
+}
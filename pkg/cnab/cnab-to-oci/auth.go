@@ -0,0 +1,299 @@
+package cnabtooci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/docker/cli/cli/config/configfile"
+	clitypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RegistryCredentials are used to authenticate to a registry.
+type RegistryCredentials struct {
+	Username string
+	Password string
+}
+
+// CredentialHelper obtains short-lived credentials for a cloud provider's registry,
+// using the ambient credentials of the cloud provider, so that a prior docker login is not required.
+type CredentialHelper interface {
+	// Name of the credential helper.
+	Name() string
+
+	// Matches determines if the credential helper can obtain credentials for the registry.
+	Matches(registry string) bool
+
+	// GetCredentials exchanges the ambient cloud credentials for a registry token.
+	GetCredentials(ctx context.Context, registry string) (RegistryCredentials, error)
+}
+
+// DefaultCredentialHelpers returns the built-in credential helpers for
+// Amazon Elastic Container Registry, Azure Container Registry, and Google Artifact Registry.
+func DefaultCredentialHelpers(c *portercontext.Context) []CredentialHelper {
+	return []CredentialHelper{
+		NewECRCredentialHelper(c),
+		NewACRCredentialHelper(c),
+		NewGARCredentialHelper(c),
+	}
+}
+
+// registryCredentialsCache caches the credentials obtained by the credential helpers,
+// so that a token is only requested once per registry.
+type registryCredentialsCache struct {
+	lock        sync.Mutex
+	credentials map[string]RegistryCredentials
+
+	// failed are the registries for which the credentials could not be obtained,
+	// so that we do not retry, and warn, each time the registry is accessed.
+	failed map[string]bool
+}
+
+// getHelperCredentials uses the first matching credential helper to obtain credentials for the registry.
+// Returns false when no credential helper matches the registry, or the credentials could not be obtained.
+func (r *Registry) getHelperCredentials(ctx context.Context, registry string) (RegistryCredentials, bool) {
+	var helper CredentialHelper
+	for _, h := range r.CredentialHelpers {
+		if h.Matches(registry) {
+			helper = h
+			break
+		}
+	}
+	if helper == nil {
+		return RegistryCredentials{}, false
+	}
+
+	r.credentialsCache.lock.Lock()
+	defer r.credentialsCache.lock.Unlock()
+	if creds, ok := r.credentialsCache.credentials[registry]; ok {
+		return creds, true
+	}
+	if r.credentialsCache.failed[registry] {
+		return RegistryCredentials{}, false
+	}
+
+	ctx, log := tracing.StartSpan(ctx, attribute.String("registry", registry), attribute.String("credential-helper", helper.Name()))
+	defer log.EndSpan()
+
+	creds, err := helper.GetCredentials(ctx, registry)
+	if err != nil {
+		// Fallback to anonymous access, the registry may not require authentication
+		log.Warnf("Unable to obtain credentials for %s with the %s credential helper: %s", registry, helper.Name(), err)
+		if r.credentialsCache.failed == nil {
+			r.credentialsCache.failed = make(map[string]bool)
+		}
+		r.credentialsCache.failed[registry] = true
+		return RegistryCredentials{}, false
+	}
+
+	if r.credentialsCache.credentials == nil {
+		r.credentialsCache.credentials = make(map[string]RegistryCredentials)
+	}
+	r.credentialsCache.credentials[registry] = creds
+	return creds, true
+}
+
+// addHelperCredentials adds credentials from the credential helpers to the docker config
+// for the registries that the user has not logged into.
+func (r *Registry) addHelperCredentials(ctx context.Context, cfg *configfile.ConfigFile, registries []string) {
+	for _, registry := range registries {
+		if a, err := cfg.GetAuthConfig(registry); err == nil && (a.Username != "" || a.IdentityToken != "" || a.Auth != "") {
+			continue
+		}
+
+		creds, ok := r.getHelperCredentials(ctx, registry)
+		if !ok {
+			continue
+		}
+
+		if cfg.AuthConfigs == nil {
+			cfg.AuthConfigs = make(map[string]clitypes.AuthConfig)
+		}
+		cfg.AuthConfigs[registry] = clitypes.AuthConfig{Username: creds.Username, Password: creds.Password, ServerAddress: registry}
+
+		// Use the credentials we just added instead of the configured credential store
+		if cfg.CredentialHelpers == nil {
+			cfg.CredentialHelpers = make(map[string]string)
+		}
+		cfg.CredentialHelpers[registry] = ""
+	}
+}
+
+// resolveAuthConfig returns the credentials for the registry from the credential helpers,
+// when the user has not logged into the registry.
+func (r *Registry) resolveAuthConfig(ctx context.Context, registry string, authConfig types.AuthConfig) types.AuthConfig {
+	if !isEmptyAuthConfig(authConfig) {
+		return authConfig
+	}
+
+	creds, ok := r.getHelperCredentials(ctx, registry)
+	if !ok {
+		return authConfig
+	}
+	return types.AuthConfig{Username: creds.Username, Password: creds.Password, ServerAddress: registry}
+}
+
+func isEmptyAuthConfig(a types.AuthConfig) bool {
+	return a.Username == "" && a.Password == "" && a.IdentityToken == "" && a.RegistryToken == "" && a.Auth == ""
+}
+
+var _ authn.Keychain = helperKeychain{}
+
+// helperKeychain resolves credentials for crane using the credential helpers.
+type helperKeychain struct {
+	ctx      context.Context
+	registry *Registry
+}
+
+func (k helperKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	creds, ok := k.registry.getHelperCredentials(k.ctx, resource.RegistryStr())
+	if !ok {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(authn.AuthConfig{Username: creds.Username, Password: creds.Password}), nil
+}
+
+// getKeychain returns a keychain that uses the docker config, and then the credential helpers.
+func (r *Registry) getKeychain(ctx context.Context) authn.Keychain {
+	return authn.NewMultiKeychain(authn.DefaultKeychain, helperKeychain{ctx: ctx, registry: r})
+}
+
+// runCredentialHelper executes a cloud provider's CLI and returns its trimmed standard output,
+// including the standard error of the CLI in the returned error when it fails.
+func runCredentialHelper(ctx context.Context, c *portercontext.Context, name string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := c.NewCommand(ctx, name, args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("could not run %s, make sure that it is installed and on the PATH: %w", name, err)
+		}
+		return "", fmt.Errorf("%s failed: %w\n%s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", fmt.Errorf("%s did not return a token", name)
+	}
+	return token, nil
+}
+
+var _ CredentialHelper = &ECRCredentialHelper{}
+
+// ecrRegistryRegex matches the host of an Amazon Elastic Container Registry, capturing the region.
+// Example: 123456789012.dkr.ecr.us-east-1.amazonaws.com
+var ecrRegistryRegex = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// ECRCredentialHelper obtains credentials for Amazon Elastic Container Registry with the aws CLI.
+type ECRCredentialHelper struct {
+	*portercontext.Context
+}
+
+// NewECRCredentialHelper creates an ECRCredentialHelper.
+func NewECRCredentialHelper(c *portercontext.Context) *ECRCredentialHelper {
+	return &ECRCredentialHelper{Context: c}
+}
+
+func (h *ECRCredentialHelper) Name() string {
+	return "ecr"
+}
+
+func (h *ECRCredentialHelper) Matches(registry string) bool {
+	return ecrRegistryRegex.MatchString(registry)
+}
+
+func (h *ECRCredentialHelper) GetCredentials(ctx context.Context, registry string) (RegistryCredentials, error) {
+	matches := ecrRegistryRegex.FindStringSubmatch(registry)
+	if matches == nil {
+		return RegistryCredentials{}, fmt.Errorf("%s is not an Amazon Elastic Container Registry", registry)
+	}
+
+	token, err := runCredentialHelper(ctx, h.Context, "aws", "ecr", "get-login-password", "--region", matches[1])
+	if err != nil {
+		return RegistryCredentials{}, err
+	}
+	return RegistryCredentials{Username: "AWS", Password: token}, nil
+}
+
+var _ CredentialHelper = &ACRCredentialHelper{}
+
+// acrRegistryRegex matches the host of an Azure Container Registry, capturing the registry name.
+// Example: myregistry.azurecr.io
+var acrRegistryRegex = regexp.MustCompile(`^([a-z0-9]+)\.azurecr\.(?:io|cn|us)$`)
+
+// acrTokenUsername is the username used to authenticate with an Azure Container Registry access token.
+const acrTokenUsername = "00000000-0000-0000-0000-000000000000"
+
+// ACRCredentialHelper obtains credentials for Azure Container Registry with the az CLI.
+type ACRCredentialHelper struct {
+	*portercontext.Context
+}
+
+// NewACRCredentialHelper creates an ACRCredentialHelper.
+func NewACRCredentialHelper(c *portercontext.Context) *ACRCredentialHelper {
+	return &ACRCredentialHelper{Context: c}
+}
+
+func (h *ACRCredentialHelper) Name() string {
+	return "acr"
+}
+
+func (h *ACRCredentialHelper) Matches(registry string) bool {
+	return acrRegistryRegex.MatchString(registry)
+}
+
+func (h *ACRCredentialHelper) GetCredentials(ctx context.Context, registry string) (RegistryCredentials, error) {
+	matches := acrRegistryRegex.FindStringSubmatch(registry)
+	if matches == nil {
+		return RegistryCredentials{}, fmt.Errorf("%s is not an Azure Container Registry", registry)
+	}
+
+	token, err := runCredentialHelper(ctx, h.Context, "az", "acr", "login", "--name", matches[1], "--expose-token", "--output", "tsv", "--query", "accessToken")
+	if err != nil {
+		return RegistryCredentials{}, err
+	}
+	return RegistryCredentials{Username: acrTokenUsername, Password: token}, nil
+}
+
+var _ CredentialHelper = &GARCredentialHelper{}
+
+// garRegistryRegex matches the host of a Google Artifact Registry, or Google Container Registry.
+// Example: us-central1-docker.pkg.dev, gcr.io, eu.gcr.io
+var garRegistryRegex = regexp.MustCompile(`^(?:[a-z0-9-]+-docker\.pkg\.dev|(?:[a-z]+\.)?gcr\.io)$`)
+
+// GARCredentialHelper obtains credentials for Google Artifact Registry, and Google Container Registry, with the gcloud CLI.
+type GARCredentialHelper struct {
+	*portercontext.Context
+}
+
+// NewGARCredentialHelper creates a GARCredentialHelper.
+func NewGARCredentialHelper(c *portercontext.Context) *GARCredentialHelper {
+	return &GARCredentialHelper{Context: c}
+}
+
+func (h *GARCredentialHelper) Name() string {
+	return "gar"
+}
+
+func (h *GARCredentialHelper) Matches(registry string) bool {
+	return garRegistryRegex.MatchString(registry)
+}
+
+func (h *GARCredentialHelper) GetCredentials(ctx context.Context, registry string) (RegistryCredentials, error) {
+	token, err := runCredentialHelper(ctx, h.Context, "gcloud", "auth", "print-access-token")
+	if err != nil {
+		return RegistryCredentials{}, err
+	}
+	return RegistryCredentials{Username: "oauth2accesstoken", Password: token}, nil
+}
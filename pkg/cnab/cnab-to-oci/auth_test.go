@@ -0,0 +1,172 @@
+package cnabtooci
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/docker/cli/cli/config/configfile"
+	clitypes "github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialHelpers_Matches(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+	ecr := NewECRCredentialHelper(c.Context)
+	acr := NewACRCredentialHelper(c.Context)
+	gar := NewGARCredentialHelper(c.Context)
+
+	testcases := []struct {
+		registry string
+		want     CredentialHelper
+	}{
+		{registry: "123456789012.dkr.ecr.us-east-1.amazonaws.com", want: ecr},
+		{registry: "123456789012.dkr.ecr-fips.us-gov-west-1.amazonaws.com", want: ecr},
+		{registry: "123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn", want: ecr},
+		{registry: "myregistry.azurecr.io", want: acr},
+		{registry: "us-central1-docker.pkg.dev", want: gar},
+		{registry: "gcr.io", want: gar},
+		{registry: "eu.gcr.io", want: gar},
+		{registry: "docker.io"},
+		{registry: "ghcr.io"},
+		{registry: "dkr.ecr.us-east-1.amazonaws.com"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.registry, func(t *testing.T) {
+			for _, h := range []CredentialHelper{ecr, acr, gar} {
+				assert.Equal(t, tc.want == h, h.Matches(tc.registry), "unexpected result from the %s credential helper", h.Name())
+			}
+		})
+	}
+}
+
+func TestCredentialHelpers_GetCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	testcases := []struct {
+		name         string
+		newHelper    func(c *portercontext.Context) CredentialHelper
+		registry     string
+		wantCommand  string
+		wantUsername string
+	}{
+		{name: "ecr", newHelper: func(c *portercontext.Context) CredentialHelper { return NewECRCredentialHelper(c) },
+			registry: "123456789012.dkr.ecr.us-east-1.amazonaws.com", wantCommand: "aws ecr get-login-password --region us-east-1", wantUsername: "AWS"},
+		{name: "acr", newHelper: func(c *portercontext.Context) CredentialHelper { return NewACRCredentialHelper(c) },
+			registry: "myregistry.azurecr.io", wantCommand: "az acr login --name myregistry --expose-token --output tsv --query accessToken", wantUsername: acrTokenUsername},
+		{name: "gar", newHelper: func(c *portercontext.Context) CredentialHelper { return NewGARCredentialHelper(c) },
+			registry: "us-central1-docker.pkg.dev", wantCommand: "gcloud auth print-access-token", wantUsername: "oauth2accesstoken"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := portercontext.NewTestContext(t)
+			c.Setenv(test.ExpectedCommandEnv, tc.wantCommand)
+			c.Setenv(test.ExpectedCommandOutputEnv, "mytoken")
+
+			creds, err := tc.newHelper(c.Context).GetCredentials(ctx, tc.registry)
+			require.NoError(t, err)
+			assert.Equal(t, RegistryCredentials{Username: tc.wantUsername, Password: "mytoken"}, creds)
+		})
+	}
+
+	t.Run("cli fails", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+		c.Setenv(test.ExpectedCommandErrorEnv, "Unable to locate credentials")
+
+		_, err := NewECRCredentialHelper(c.Context).GetCredentials(ctx, "123456789012.dkr.ecr.us-east-1.amazonaws.com")
+		require.ErrorContains(t, err, "aws failed")
+		require.ErrorContains(t, err, "Unable to locate credentials")
+	})
+}
+
+func TestRegistry_getHelperCredentials(t *testing.T) {
+	ctx := context.Background()
+	const registry = "123456789012.dkr.ecr.us-east-1.amazonaws.com"
+
+	t.Run("cached", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		r := NewRegistry(c.Context)
+		c.Setenv(test.ExpectedCommandOutputEnv, "mytoken")
+
+		creds, ok := r.getHelperCredentials(ctx, registry)
+		require.True(t, ok)
+		assert.Equal(t, "mytoken", creds.Password)
+
+		// The token should not be requested again
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+		creds, ok = r.getHelperCredentials(ctx, registry)
+		require.True(t, ok)
+		assert.Equal(t, "mytoken", creds.Password)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		r := NewRegistry(c.Context)
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+
+		_, ok := r.getHelperCredentials(ctx, registry)
+		require.False(t, ok)
+		assert.True(t, r.credentialsCache.failed[registry], "the failure should be remembered")
+	})
+
+	t.Run("no matching helper", func(t *testing.T) {
+		r := NewRegistry(portercontext.NewTestContext(t).Context)
+
+		_, ok := r.getHelperCredentials(ctx, "ghcr.io")
+		require.False(t, ok)
+	})
+}
+
+func TestRegistry_addHelperCredentials(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+	c.Setenv(test.ExpectedCommandOutputEnv, "mytoken")
+	r := NewRegistry(c.Context)
+
+	cfg := configfile.New("config.json")
+	cfg.CredentialsStore = "desktop"
+	cfg.CredentialHelpers = map[string]string{"myregistry.azurecr.io": "acr-env"}
+	cfg.AuthConfigs["gcr.io"] = clitypes.AuthConfig{Username: "sally", Password: "topsecret"}
+	cfg.CredentialHelpers["gcr.io"] = ""
+
+	r.addHelperCredentials(context.Background(), cfg, []string{"123456789012.dkr.ecr.us-east-1.amazonaws.com", "gcr.io", "ghcr.io"})
+
+	a, err := cfg.GetAuthConfig("123456789012.dkr.ecr.us-east-1.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, "AWS", a.Username)
+	assert.Equal(t, "mytoken", a.Password)
+
+	a, err = cfg.GetAuthConfig("gcr.io")
+	require.NoError(t, err)
+	assert.Equal(t, "sally", a.Username, "existing credentials should not be replaced")
+
+	assert.NotContains(t, cfg.AuthConfigs, "ghcr.io", "registries without a matching credential helper should not be modified")
+	assert.Equal(t, "acr-env", cfg.CredentialHelpers["myregistry.azurecr.io"])
+}
+
+func TestHelperKeychain(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+	c.Setenv(test.ExpectedCommandOutputEnv, "mytoken")
+	r := NewRegistry(c.Context)
+	keychain := helperKeychain{ctx: context.Background(), registry: r}
+
+	repo, err := name.NewRepository("us-central1-docker.pkg.dev/myproject/mybuns")
+	require.NoError(t, err)
+	auth, err := keychain.Resolve(repo)
+	require.NoError(t, err)
+	cfg, err := auth.Authorization()
+	require.NoError(t, err)
+	assert.Equal(t, "oauth2accesstoken", cfg.Username)
+	assert.Equal(t, "mytoken", cfg.Password)
+
+	repo, err = name.NewRepository("ghcr.io/getporter/mybuns")
+	require.NoError(t, err)
+	auth, err = keychain.Resolve(repo)
+	require.NoError(t, err)
+	assert.Equal(t, authn.Anonymous, auth)
+}
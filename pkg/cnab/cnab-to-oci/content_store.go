@@ -0,0 +1,231 @@
+package cnabtooci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/config"
+	containerdRemotes "github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/afero"
+)
+
+// ContentStore is a local store of image blobs, such as layers, addressed by
+// their digest. Blobs downloaded by one command are reused by later commands,
+// for example when archiving or copying bundles that share images, instead of
+// pulling them from the registry again.
+type ContentStore struct {
+	*config.Config
+}
+
+// NewContentStore creates a content store in PORTER_HOME/content.
+func NewContentStore(c *config.Config) *ContentStore {
+	return &ContentStore{Config: c}
+}
+
+// GetContentDir returns the directory where the blobs are stored.
+func (s *ContentStore) GetContentDir() (string, error) {
+	home, err := s.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "content"), nil
+}
+
+// getBlobPath returns the location of a blob in the store.
+func (s *ContentStore) getBlobPath(d digest.Digest) (string, error) {
+	dir, err := s.GetContentDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "blobs", d.Algorithm().String(), d.Encoded()), nil
+}
+
+// Open returns the contents of a blob, and false when the blob is not in the store.
+func (s *ContentStore) Open(d digest.Digest) (io.ReadCloser, bool, error) {
+	blobPath, err := s.getBlobPath(d)
+	if err != nil {
+		return nil, false, err
+	}
+
+	f, err := s.FileSystem.Open(blobPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("unable to open blob %s from the content store: %w", d, err)
+	}
+	return f, true, nil
+}
+
+// Fetch returns the contents of a blob from the store. When the blob is not in
+// the store, it is read with fetch and saved to the store as it is read. The
+// blob is only saved once it has been read completely and matches its digest,
+// so an interrupted download is never reused.
+func (s *ContentStore) Fetch(d digest.Digest, fetch func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	// Only blobs with a valid digest can be verified before they are saved
+	if d.Validate() != nil {
+		return fetch()
+	}
+
+	rc, found, err := s.Open(d)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return rc, nil
+	}
+
+	upstream, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	// Saving the blob is best effort, it is still returned when it cannot be saved
+	blobPath, err := s.getBlobPath(d)
+	if err != nil {
+		return upstream, nil
+	}
+	if err = s.FileSystem.MkdirAll(filepath.Dir(blobPath), pkg.FileModeDirectory); err != nil {
+		return upstream, nil
+	}
+	tmp, err := s.FileSystem.TempFile(filepath.Dir(blobPath), d.Encoded()+".*.tmp")
+	if err != nil {
+		return upstream, nil
+	}
+
+	return &contentStoreWriter{
+		upstream: upstream,
+		tmp:      tmp,
+		digester: d.Algorithm().Digester(),
+		expected: d,
+		blobPath: blobPath,
+		store:    s,
+	}, nil
+}
+
+// Size returns the total size of the blobs in the store.
+func (s *ContentStore) Size() (int64, error) {
+	dir, err := s.GetContentDir()
+	if err != nil {
+		return 0, err
+	}
+
+	exists, err := s.FileSystem.DirExists(dir)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var size int64
+	err = s.FileSystem.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to determine the size of the content store at %s: %w", dir, err)
+	}
+	return size, nil
+}
+
+// Clear removes every blob from the store.
+func (s *ContentStore) Clear() error {
+	dir, err := s.GetContentDir()
+	if err != nil {
+		return err
+	}
+
+	if err = s.FileSystem.RemoveAll(dir); err != nil {
+		return fmt.Errorf("unable to clear the content store at %s: %w", dir, err)
+	}
+	return nil
+}
+
+var _ io.ReadCloser = &contentStoreWriter{}
+
+// contentStoreWriter returns a blob as it is downloaded, while writing it to a
+// temporary file that is moved into the content store when the blob is complete.
+type contentStoreWriter struct {
+	upstream io.ReadCloser
+	tmp      afero.File
+	digester digest.Digester
+	expected digest.Digest
+	blobPath string
+	store    *ContentStore
+
+	// complete is set when the entire blob has been read.
+	complete bool
+
+	// failed is set when the blob could not be written to the temporary file.
+	failed bool
+}
+
+func (w *contentStoreWriter) Read(p []byte) (int, error) {
+	n, err := w.upstream.Read(p)
+	if n > 0 && !w.failed {
+		w.digester.Hash().Write(p[:n])
+		if _, writeErr := w.tmp.Write(p[:n]); writeErr != nil {
+			w.failed = true
+		}
+	}
+	if err == io.EOF {
+		w.complete = true
+	}
+	return n, err
+}
+
+// Close saves the blob to the content store when it was read completely and
+// matches its digest, otherwise the temporary file is discarded.
+func (w *contentStoreWriter) Close() error {
+	err := w.upstream.Close()
+	w.tmp.Close()
+
+	if w.complete && !w.failed && w.digester.Digest() == w.expected {
+		if renameErr := w.store.FileSystem.Rename(w.tmp.Name(), w.blobPath); renameErr == nil {
+			return err
+		}
+	}
+	w.store.FileSystem.Remove(w.tmp.Name())
+	return err
+}
+
+var _ containerdRemotes.Resolver = contentStoreResolver{}
+
+// contentStoreResolver fetches blobs through the content store, so that blobs
+// that were already downloaded are not fetched from the registry again.
+type contentStoreResolver struct {
+	containerdRemotes.Resolver
+
+	store *ContentStore
+}
+
+// newContentStoreResolver wraps the resolver so that blobs are fetched through the content store.
+func (r *Registry) newContentStoreResolver(resolver containerdRemotes.Resolver) containerdRemotes.Resolver {
+	if r.ContentStore == nil {
+		return resolver
+	}
+	return contentStoreResolver{Resolver: resolver, store: r.ContentStore}
+}
+
+func (r contentStoreResolver) Fetcher(ctx context.Context, ref string) (containerdRemotes.Fetcher, error) {
+	upstream, err := r.Resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return containerdRemotes.FetcherFunc(func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+		return r.store.Fetch(desc.Digest, func() (io.ReadCloser, error) {
+			return upstream.Fetch(ctx, desc)
+		})
+	}), nil
+}
@@ -0,0 +1,149 @@
+package cnabtooci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentStore_Fetch(t *testing.T) {
+	blob := []byte("a layer of the image")
+	d := digest.FromBytes(blob)
+
+	fetches := 0
+	fetch := func() (io.ReadCloser, error) {
+		fetches++
+		return io.NopCloser(bytes.NewReader(blob)), nil
+	}
+	readBlob := func(t *testing.T, s *ContentStore, fetch func() (io.ReadCloser, error)) []byte {
+		rc, err := s.Fetch(d, fetch)
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		return data
+	}
+
+	t.Run("reuse downloaded blobs", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		s := NewContentStore(c.Config)
+		fetches = 0
+
+		assert.Equal(t, blob, readBlob(t, s, fetch))
+		assert.Equal(t, blob, readBlob(t, s, fetch))
+		assert.Equal(t, 1, fetches, "the blob should only be fetched once")
+
+		size, err := s.Size()
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(blob)), size)
+
+		require.NoError(t, s.Clear())
+		_, found, err := s.Open(d)
+		require.NoError(t, err)
+		assert.False(t, found, "the blob should have been removed")
+	})
+
+	t.Run("partially read blobs are not saved", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		s := NewContentStore(c.Config)
+
+		rc, err := s.Fetch(d, fetch)
+		require.NoError(t, err)
+		_, err = rc.Read(make([]byte, 5))
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		_, found, err := s.Open(d)
+		require.NoError(t, err)
+		assert.False(t, found, "an incomplete blob should not be saved")
+		size, err := s.Size()
+		require.NoError(t, err)
+		assert.Zero(t, size, "the temporary file should have been removed")
+	})
+
+	t.Run("blobs that do not match their digest are not saved", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		s := NewContentStore(c.Config)
+
+		tampered := func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("something else")), nil
+		}
+		readBlob(t, s, tampered)
+
+		_, found, err := s.Open(d)
+		require.NoError(t, err)
+		assert.False(t, found, "a blob that does not match its digest should not be saved")
+	})
+
+	t.Run("fetch fails", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		s := NewContentStore(c.Config)
+
+		_, err := s.Fetch(d, func() (io.ReadCloser, error) {
+			return nil, errors.New("connection refused")
+		})
+		require.EqualError(t, err, "connection refused")
+	})
+}
+
+func TestContentStoreResolver(t *testing.T) {
+	// Count the blobs that are downloaded from the registry
+	var blobPulls int
+	reg := registry.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+			blobPulls++
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	imgRef := host + "/getporter/whalesay:latest"
+	require.NoError(t, crane.Push(img, imgRef, crane.Insecure))
+	layers, err := img.Layers()
+	require.NoError(t, err)
+	layerDigest, err := layers[0].Digest()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	r := NewRegistry(c.Context)
+	r.ContentStore = NewContentStore(c.Config)
+	upstreamResolver, err := r.createResolver(ctx, RegistryOptions{InsecureRegistry: true}, []string{host})
+	require.NoError(t, err)
+	resolver := r.newContentStoreResolver(upstreamResolver)
+
+	_, desc, err := resolver.Resolve(ctx, imgRef)
+	require.NoError(t, err)
+	desc.Digest = digest.Digest(layerDigest.String())
+	desc.Size, err = layers[0].Size()
+	require.NoError(t, err)
+	desc.MediaType = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+
+	for i := 0; i < 2; i++ {
+		fetcher, err := resolver.Fetcher(ctx, imgRef)
+		require.NoError(t, err)
+		rc, err := fetcher.Fetch(ctx, desc)
+		require.NoError(t, err)
+		_, err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+	assert.Equal(t, 1, blobPulls, "the layer should only be downloaded once")
+}
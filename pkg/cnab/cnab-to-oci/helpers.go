@@ -15,10 +15,14 @@ type TestRegistry struct {
 	MockPullBundle        func(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (cnab.BundleReference, error)
 	MockPushBundle        func(ctx context.Context, ref cnab.BundleReference, opts RegistryOptions) (bundleReference cnab.BundleReference, err error)
 	MockPushImage         func(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (imageDigest digest.Digest, err error)
+	MockPushImageIndex    func(ctx context.Context, ref cnab.OCIReference, layoutPath string, opts RegistryOptions) (imageDigest digest.Digest, err error)
 	MockGetCachedImage    func(ctx context.Context, ref cnab.OCIReference) (ImageSummary, error)
 	MockListTags          func(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) ([]string, error)
+	MockListRepositories  func(ctx context.Context, registry string, opts RegistryOptions) ([]string, error)
 	MockPullImage         func(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) error
 	MockGetBundleMetadata func(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (BundleMetadata, error)
+	MockPushReferrer      func(ctx context.Context, subject cnab.OCIReference, artifact Artifact, opts RegistryOptions) (digest.Digest, error)
+	MockPullReferrer      func(ctx context.Context, subject cnab.OCIReference, artifactType string, opts RegistryOptions) (Artifact, error)
 	cache                 map[string]ImageSummary
 }
 
@@ -53,6 +57,13 @@ func (t *TestRegistry) PushImage(ctx context.Context, ref cnab.OCIReference, opt
 	return "sha256:75c495e5ce9c428d482973d72e3ce9925e1db304a97946c9aa0b540d7537e041", nil
 }
 
+func (t *TestRegistry) PushImageIndex(ctx context.Context, ref cnab.OCIReference, layoutPath string, opts RegistryOptions) (digest.Digest, error) {
+	if t.MockPushImageIndex != nil {
+		return t.MockPushImageIndex(ctx, ref, layoutPath, opts)
+	}
+	return "sha256:75c495e5ce9c428d482973d72e3ce9925e1db304a97946c9aa0b540d7537e041", nil
+}
+
 func (t *TestRegistry) GetCachedImage(ctx context.Context, ref cnab.OCIReference) (ImageSummary, error) {
 	if t.MockGetCachedImage != nil {
 		return t.MockGetCachedImage(ctx, ref)
@@ -61,7 +72,7 @@ func (t *TestRegistry) GetCachedImage(ctx context.Context, ref cnab.OCIReference
 	img := ref.String()
 	sum, ok := t.cache[img]
 	if !ok {
-		return ImageSummary{}, fmt.Errorf("image %s not found in cache", img)
+		return ImageSummary{}, fmt.Errorf("image %s not found in cache: %w", img, ErrNotFound{Reference: ref})
 	}
 	return sum, nil
 }
@@ -74,6 +85,14 @@ func (t *TestRegistry) ListTags(ctx context.Context, ref cnab.OCIReference, opts
 	return nil, nil
 }
 
+func (t *TestRegistry) ListRepositories(ctx context.Context, registry string, opts RegistryOptions) ([]string, error) {
+	if t.MockListRepositories != nil {
+		return t.MockListRepositories(ctx, registry, opts)
+	}
+
+	return nil, nil
+}
+
 func (t *TestRegistry) PullImage(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) error {
 	if t.MockPullImage != nil {
 		return t.MockPullImage(ctx, ref, opts)
@@ -98,3 +117,19 @@ func (t TestRegistry) GetBundleMetadata(ctx context.Context, ref cnab.OCIReferen
 
 	return BundleMetadata{}, ErrNotFound{Reference: ref}
 }
+
+func (t *TestRegistry) PushReferrer(ctx context.Context, subject cnab.OCIReference, artifact Artifact, opts RegistryOptions) (digest.Digest, error) {
+	if t.MockPushReferrer != nil {
+		return t.MockPushReferrer(ctx, subject, artifact, opts)
+	}
+
+	return digest.FromBytes(artifact.Content), nil
+}
+
+func (t *TestRegistry) PullReferrer(ctx context.Context, subject cnab.OCIReference, artifactType string, opts RegistryOptions) (Artifact, error) {
+	if t.MockPullReferrer != nil {
+		return t.MockPullReferrer(ctx, subject, artifactType, opts)
+	}
+
+	return Artifact{}, ErrNotFound{Reference: subject}
+}
@@ -0,0 +1,222 @@
+package cnabtooci
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/carolynvs/aferox"
+	containerdRemotes "github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	dockerconfig "github.com/docker/cli/cli/config"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// MirroredReference is the location of an image on the mirror configured for its registry.
+type MirroredReference struct {
+	// Reference to the image on the mirror.
+	Reference cnab.OCIReference
+
+	// Mirror configuration used to connect to the mirror.
+	Mirror config.RegistryMirror
+}
+
+// FindMirror returns the location of the image on the mirror configured for the image's registry.
+// Returns false when the image's registry is not mirrored.
+func (o RegistryOptions) FindMirror(ref cnab.OCIReference) (MirroredReference, bool, error) {
+	if len(o.Mirrors) == 0 {
+		return MirroredReference{}, false, nil
+	}
+	if err := o.Mirrors.Validate(); err != nil {
+		return MirroredReference{}, false, fmt.Errorf("invalid registry-mirrors configuration: %w", err)
+	}
+
+	mirror, ok := o.Mirrors.Find(ref.Registry())
+	if !ok {
+		return MirroredReference{}, false, nil
+	}
+
+	mirroredRef, err := ref.WithRegistry(mirror.Mirror)
+	if err != nil {
+		return MirroredReference{}, false, fmt.Errorf("error determining the location of %s on the registry mirror %s: %w", ref, mirror.Mirror, err)
+	}
+	return MirroredReference{Reference: mirroredRef, Mirror: mirror}, true, nil
+}
+
+// withoutMirror returns a copy of the options that does not use a mirror for the specified registry.
+func (o RegistryOptions) withoutMirror(registry string) RegistryOptions {
+	var mirrors config.RegistryMirrors
+	for _, mirror := range o.Mirrors {
+		if _, ok := (config.RegistryMirrors{mirror}).Find(registry); !ok {
+			mirrors = append(mirrors, mirror)
+		}
+	}
+	o.Mirrors = mirrors
+	return o
+}
+
+// GetMirrorCraneOptions returns the crane options used to connect to the registry mirror,
+// applying the mirror's credentials and TLS settings.
+func GetMirrorCraneOptions(fs aferox.Aferox, mirror config.RegistryMirror) ([]crane.Option, error) {
+	transport, err := getMirrorTransport(fs, mirror)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []crane.Option{crane.WithTransport(NewRetryTransport(transport))}
+	if mirror.Insecure {
+		opts = append(opts, crane.Insecure)
+	}
+	if mirror.Username != "" {
+		opts = append(opts, crane.WithAuth(&authn.Basic{Username: mirror.Username, Password: mirror.Password}))
+	}
+	return opts, nil
+}
+
+// getMirrorTransport returns a copy of the default http transport that uses the mirror's TLS settings.
+func getMirrorTransport(fs aferox.Aferox, mirror config.RegistryMirror) (*http.Transport, error) {
+	if mirror.Insecure {
+		return GetInsecureRegistryTransport(), nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if mirror.CACertFile != "" {
+		certB, err := fs.ReadFile(mirror.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry-mirrors.ca-cert-file %s for the %s registry: %w", mirror.CACertFile, mirror.Registry, err)
+		}
+
+		pool, ok := newCertPool(certB)
+		if !ok {
+			return nil, fmt.Errorf("could not use the certificate in %s for the %s registry mirror", mirror.CACertFile, mirror.Registry)
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	return transport, nil
+}
+
+// createMirrorResolver creates a resolver that connects to the registry mirror
+// using the mirror's credentials and TLS settings.
+func (r *Registry) createMirrorResolver(mirror config.RegistryMirror) (containerdRemotes.Resolver, error) {
+	transport, err := getMirrorTransport(r.FileSystem, mirror)
+	if err != nil {
+		return nil, err
+	}
+	creds := func(host string) (string, string, error) {
+		if mirror.Username != "" {
+			return mirror.Username, mirror.Password, nil
+		}
+		return dockerConfigCredentials(dockerconfig.LoadDefaultConfigFile(r.Out))(host)
+	}
+	return newHostResolver(transport, mirror.Mirror, mirror.Insecure, docker.MatchLocalhost, creds), nil
+}
+
+var _ containerdRemotes.Resolver = mirrorResolver{}
+
+// mirrorResolver resolves and fetches images through the mirror configured for their registry,
+// falling back to the registry when the mirror does not have the image.
+// Pushes are always made directly to the registry.
+type mirrorResolver struct {
+	containerdRemotes.Resolver
+
+	opts RegistryOptions
+
+	// mirrors are the resolvers for each registry mirror, keyed by the mirror host.
+	mirrors     map[string]containerdRemotes.Resolver
+	mirrorsLock *sync.Mutex
+
+	// createMirrorResolver creates the resolver for a registry mirror.
+	createMirrorResolver func(mirror config.RegistryMirror) (containerdRemotes.Resolver, error)
+}
+
+// newMirrorResolver wraps the resolver so that images are pulled through the configured registry mirrors.
+func (r *Registry) newMirrorResolver(resolver containerdRemotes.Resolver, opts RegistryOptions) containerdRemotes.Resolver {
+	if len(opts.Mirrors) == 0 {
+		return resolver
+	}
+	return mirrorResolver{
+		Resolver:             resolver,
+		opts:                 opts,
+		mirrors:              make(map[string]containerdRemotes.Resolver),
+		mirrorsLock:          &sync.Mutex{},
+		createMirrorResolver: r.createMirrorResolver,
+	}
+}
+
+// getMirror returns the mirrored reference and the resolver for the mirror.
+func (r mirrorResolver) getMirror(ctx context.Context, ref string) (string, containerdRemotes.Resolver, bool) {
+	log := tracing.LoggerFromContext(ctx)
+
+	parsedRef, err := cnab.ParseOCIReference(ref)
+	if err != nil {
+		return "", nil, false
+	}
+	mirrorRef, ok, err := r.opts.FindMirror(parsedRef)
+	if err != nil {
+		log.Warnf("Ignoring the registry mirror for %s: %s", ref, err)
+		return "", nil, false
+	}
+	if !ok {
+		return "", nil, false
+	}
+
+	r.mirrorsLock.Lock()
+	defer r.mirrorsLock.Unlock()
+	resolver, ok := r.mirrors[mirrorRef.Mirror.Mirror]
+	if !ok {
+		resolver, err = r.createMirrorResolver(mirrorRef.Mirror)
+		if err != nil {
+			log.Warnf("Ignoring the registry mirror for %s: %s", ref, err)
+			return "", nil, false
+		}
+		r.mirrors[mirrorRef.Mirror.Mirror] = resolver
+	}
+	return mirrorRef.Reference.Named.String(), resolver, true
+}
+
+func (r mirrorResolver) Resolve(ctx context.Context, ref string) (string, ocispec.Descriptor, error) {
+	if mirrorRef, mirror, ok := r.getMirror(ctx, ref); ok {
+		_, desc, err := mirror.Resolve(ctx, mirrorRef)
+		if err == nil {
+			return ref, desc, nil
+		}
+		tracing.LoggerFromContext(ctx).Debugf("Unable to resolve %s from the registry mirror, resolving it from the registry instead: %s", ref, err)
+	}
+	return r.Resolver.Resolve(ctx, ref)
+}
+
+func (r mirrorResolver) Fetcher(ctx context.Context, ref string) (containerdRemotes.Fetcher, error) {
+	upstream, err := r.Resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorRef, mirror, ok := r.getMirror(ctx, ref)
+	if !ok {
+		return upstream, nil
+	}
+	mirrorFetcher, err := mirror.Fetcher(ctx, mirrorRef)
+	if err != nil {
+		return upstream, nil
+	}
+
+	return containerdRemotes.FetcherFunc(func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+		rc, err := mirrorFetcher.Fetch(ctx, desc)
+		if err == nil {
+			return rc, nil
+		}
+		tracing.LoggerFromContext(ctx).Debugf("Unable to fetch %s from the registry mirror, fetching it from the registry instead: %s", desc.Digest, err)
+		return upstream.Fetch(ctx, desc)
+	}), nil
+}
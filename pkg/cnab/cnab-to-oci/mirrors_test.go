@@ -0,0 +1,122 @@
+package cnabtooci
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryOptions_FindMirror(t *testing.T) {
+	opts := RegistryOptions{Mirrors: config.RegistryMirrors{
+		{Registry: "docker.io", Mirror: "dockerhub.example.com"},
+	}}
+
+	t.Run("mirrored", func(t *testing.T) {
+		mirrorRef, ok, err := opts.FindMirror(cnab.MustParseOCIReference("getporter/porter-hello:v0.1.0"))
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "dockerhub.example.com/getporter/porter-hello:v0.1.0", mirrorRef.Reference.String())
+		assert.Equal(t, "docker.io", mirrorRef.Mirror.Registry)
+	})
+
+	t.Run("not mirrored", func(t *testing.T) {
+		_, ok, err := opts.FindMirror(cnab.MustParseOCIReference("ghcr.io/getporter/porter-hello:v0.1.0"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid mirror", func(t *testing.T) {
+		opts := RegistryOptions{Mirrors: config.RegistryMirrors{{Registry: "docker.io"}}}
+		_, _, err := opts.FindMirror(cnab.MustParseOCIReference("getporter/porter-hello:v0.1.0"))
+		require.ErrorContains(t, err, "invalid registry-mirrors configuration")
+	})
+}
+
+func TestRegistryOptions_withoutMirror(t *testing.T) {
+	opts := RegistryOptions{Mirrors: config.RegistryMirrors{
+		{Registry: "docker.io", Mirror: "dockerhub.example.com"},
+		{Registry: "ghcr.io", Mirror: "ghcr.example.com"},
+	}}
+
+	result := opts.withoutMirror("index.docker.io")
+	require.Len(t, result.Mirrors, 1)
+	assert.Equal(t, "ghcr.io", result.Mirrors[0].Registry)
+	assert.Len(t, opts.Mirrors, 2, "the original options should not be modified")
+}
+
+func TestGetMirrorCraneOptions(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+
+	_, err := GetMirrorCraneOptions(c.FileSystem, config.RegistryMirror{Registry: "docker.io", Mirror: "dockerhub.example.com", CACertFile: "missing.pem"})
+	require.ErrorContains(t, err, "invalid registry-mirrors.ca-cert-file missing.pem")
+
+	require.NoError(t, c.FileSystem.WriteFile("bad.pem", []byte("not a certificate"), 0600))
+	_, err = GetMirrorCraneOptions(c.FileSystem, config.RegistryMirror{Registry: "docker.io", Mirror: "dockerhub.example.com", CACertFile: "bad.pem"})
+	require.ErrorContains(t, err, "could not use the certificate in bad.pem")
+}
+
+func TestMirrorResolver(t *testing.T) {
+	upstream := httptest.NewServer(registry.New())
+	defer upstream.Close()
+	upstreamHost := strings.TrimPrefix(upstream.URL, "http://")
+
+	mirror := httptest.NewServer(registry.New())
+	defer mirror.Close()
+	mirrorHost := strings.TrimPrefix(mirror.URL, "http://")
+
+	// The image is only available on the mirror
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, crane.Push(img, mirrorHost+"/getporter/whalesay:latest", crane.Insecure))
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	r := NewRegistry(portercontext.NewTestContext(t).Context)
+	opts := RegistryOptions{Mirrors: config.RegistryMirrors{{Registry: upstreamHost, Mirror: mirrorHost, Insecure: true}}}
+	upstreamResolver, err := r.createResolver(ctx, RegistryOptions{}, nil)
+	require.NoError(t, err)
+	resolver := r.newMirrorResolver(upstreamResolver, opts)
+
+	t.Run("resolve through the mirror", func(t *testing.T) {
+		imgRef := upstreamHost + "/getporter/whalesay:latest"
+		name, desc, err := resolver.Resolve(ctx, imgRef)
+		require.NoError(t, err)
+		assert.Equal(t, imgRef, name, "the mirror should be transparent")
+		assert.Equal(t, imgDigest.String(), desc.Digest.String())
+
+		fetcher, err := resolver.Fetcher(ctx, imgRef)
+		require.NoError(t, err)
+		rc, err := fetcher.Fetch(ctx, desc)
+		require.NoError(t, err)
+		defer rc.Close()
+		manifest, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		wantManifest, err := img.RawManifest()
+		require.NoError(t, err)
+		assert.Equal(t, wantManifest, manifest)
+	})
+
+	t.Run("fallback to the registry", func(t *testing.T) {
+		other, err := random.Image(64, 1)
+		require.NoError(t, err)
+		require.NoError(t, crane.Push(other, upstreamHost+"/getporter/other:latest", crane.Insecure))
+		otherDigest, err := other.Digest()
+		require.NoError(t, err)
+
+		_, desc, err := resolver.Resolve(ctx, upstreamHost+"/getporter/other:latest")
+		require.NoError(t, err)
+		assert.Equal(t, otherDigest.String(), desc.Digest.String())
+	})
+}
@@ -0,0 +1,99 @@
+package cnabtooci
+
+import (
+	"context"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"github.com/opencontainers/go-digest"
+)
+
+var _ RegistryProvider = &OfflineRegistry{}
+
+// OfflineRegistry fails every operation that accesses a registry when
+// Porter is in offline mode, and otherwise passes them through to the wrapped
+// registry. Reading from the local Docker image cache is always allowed.
+type OfflineRegistry struct {
+	RegistryProvider
+
+	config *config.Config
+}
+
+// NewOfflineRegistry wraps a registry so that it is not accessed in offline mode.
+func NewOfflineRegistry(c *config.Config, registry RegistryProvider) *OfflineRegistry {
+	return &OfflineRegistry{
+		RegistryProvider: registry,
+		config:           c,
+	}
+}
+
+func (r *OfflineRegistry) PullBundle(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (cnab.BundleReference, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("pull the bundle %s", ref)); err != nil {
+		return cnab.BundleReference{}, err
+	}
+	return r.RegistryProvider.PullBundle(ctx, ref, opts)
+}
+
+func (r *OfflineRegistry) PushBundle(ctx context.Context, ref cnab.BundleReference, opts RegistryOptions) (cnab.BundleReference, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("push the bundle %s", ref.Reference)); err != nil {
+		return cnab.BundleReference{}, err
+	}
+	return r.RegistryProvider.PushBundle(ctx, ref, opts)
+}
+
+func (r *OfflineRegistry) PushImage(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (digest.Digest, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("push the image %s", ref)); err != nil {
+		return "", err
+	}
+	return r.RegistryProvider.PushImage(ctx, ref, opts)
+}
+
+func (r *OfflineRegistry) PushImageIndex(ctx context.Context, ref cnab.OCIReference, layoutPath string, opts RegistryOptions) (digest.Digest, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("push the image %s", ref)); err != nil {
+		return "", err
+	}
+	return r.RegistryProvider.PushImageIndex(ctx, ref, layoutPath, opts)
+}
+
+func (r *OfflineRegistry) ListTags(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) ([]string, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("list the tags of %s", ref.Repository())); err != nil {
+		return nil, err
+	}
+	return r.RegistryProvider.ListTags(ctx, ref, opts)
+}
+
+func (r *OfflineRegistry) ListRepositories(ctx context.Context, registry string, opts RegistryOptions) ([]string, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("list the repositories in %s", registry)); err != nil {
+		return nil, err
+	}
+	return r.RegistryProvider.ListRepositories(ctx, registry, opts)
+}
+
+func (r *OfflineRegistry) PullImage(ctx context.Context, image cnab.OCIReference, opts RegistryOptions) error {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("pull the image %s", image)); err != nil {
+		return err
+	}
+	return r.RegistryProvider.PullImage(ctx, image, opts)
+}
+
+func (r *OfflineRegistry) PushReferrer(ctx context.Context, subject cnab.OCIReference, artifact Artifact, opts RegistryOptions) (digest.Digest, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("push the %s artifact for %s", artifact.ArtifactType, subject)); err != nil {
+		return "", err
+	}
+	return r.RegistryProvider.PushReferrer(ctx, subject, artifact, opts)
+}
+
+func (r *OfflineRegistry) PullReferrer(ctx context.Context, subject cnab.OCIReference, artifactType string, opts RegistryOptions) (Artifact, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("pull the %s artifact for %s", artifactType, subject)); err != nil {
+		return Artifact{}, err
+	}
+	return r.RegistryProvider.PullReferrer(ctx, subject, artifactType, opts)
+}
+
+func (r *OfflineRegistry) GetBundleMetadata(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (BundleMetadata, error) {
+	if err := r.config.CheckNetworkAccess(fmt.Sprintf("look up the bundle %s", ref)); err != nil {
+		return BundleMetadata{}, err
+	}
+	return r.RegistryProvider.GetBundleMetadata(ctx, ref, opts)
+}
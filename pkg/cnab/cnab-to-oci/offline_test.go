@@ -0,0 +1,49 @@
+package cnabtooci
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineRegistry(t *testing.T) {
+	ctx := context.Background()
+	ref := cnab.MustParseOCIReference("example.com/mybuns:v1.0.0")
+
+	t.Run("online", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		r := NewOfflineRegistry(c.Config, NewTestRegistry())
+
+		_, err := r.PullBundle(ctx, ref, RegistryOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		c.Data.Offline = true
+		r := NewOfflineRegistry(c.Config, NewTestRegistry())
+
+		_, err := r.PullBundle(ctx, ref, RegistryOptions{})
+		require.ErrorIs(t, err, config.ErrOffline)
+		assert.Contains(t, err.Error(), "cannot pull the bundle example.com/mybuns:v1.0.0")
+
+		_, err = r.PushBundle(ctx, cnab.BundleReference{Reference: ref}, RegistryOptions{})
+		require.ErrorIs(t, err, config.ErrOffline)
+
+		_, err = r.ListTags(ctx, ref, RegistryOptions{})
+		require.ErrorIs(t, err, config.ErrOffline)
+
+		err = r.PullImage(ctx, ref, RegistryOptions{})
+		require.ErrorIs(t, err, config.ErrOffline)
+
+		_, err = r.GetBundleMetadata(ctx, ref, RegistryOptions{})
+		require.ErrorIs(t, err, config.ErrOffline)
+
+		_, err = r.GetCachedImage(ctx, ref)
+		require.ErrorIs(t, err, ErrNotFound{}, "the local image cache should be checked in offline mode")
+	})
+}
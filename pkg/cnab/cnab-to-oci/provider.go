@@ -4,7 +4,7 @@ import (
 	"context"
 
 	"get.porter.sh/porter/pkg/cnab"
-	"github.com/google/go-containerregistry/pkg/crane"
+	"get.porter.sh/porter/pkg/config"
 	"github.com/opencontainers/go-digest"
 )
 
@@ -21,6 +21,11 @@ type RegistryProvider interface {
 	// Returns the image digest from the registry.
 	PushImage(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (digest.Digest, error)
 
+	// PushImageIndex pushes the multi-platform image saved in the OCI image layout
+	// to the specified location, preserving the image index.
+	// Returns the digest of the image index from the registry.
+	PushImageIndex(ctx context.Context, ref cnab.OCIReference, layoutPath string, opts RegistryOptions) (digest.Digest, error)
+
 	// GetCachedImage returns a particular image from the local image cache.
 	// Use ErrNotFound to detect if the failure is because the image is not in the local Docker cache.
 	GetCachedImage(ctx context.Context, ref cnab.OCIReference) (ImageSummary, error)
@@ -28,9 +33,21 @@ type RegistryProvider interface {
 	// ListTags returns all tags defined on the specified repository.
 	ListTags(ctx context.Context, repo cnab.OCIReference, opts RegistryOptions) ([]string, error)
 
+	// ListRepositories returns the names of the repositories in the registry, without the registry host,
+	// using the registry's catalog API.
+	ListRepositories(ctx context.Context, registry string, opts RegistryOptions) ([]string, error)
+
 	// PullImage pulls an image from an OCI registry and returns the image's digest
 	PullImage(ctx context.Context, image cnab.OCIReference, opts RegistryOptions) error
 
+	// PushReferrer attaches an artifact to the subject image in the registry.
+	// Returns the digest of the artifact manifest.
+	PushReferrer(ctx context.Context, subject cnab.OCIReference, artifact Artifact, opts RegistryOptions) (digest.Digest, error)
+
+	// PullReferrer returns the most recent artifact of the specified type that is attached to the subject image in the registry.
+	// Use ErrNotFound to detect if the error is because no artifact is attached to the image.
+	PullReferrer(ctx context.Context, subject cnab.OCIReference, artifactType string, opts RegistryOptions) (Artifact, error)
+
 	// GetBundleMetadata returns information about a bundle in a registry
 	// Use ErrNotFound to detect if the error is because the bundle is not in the registry.
 	GetBundleMetadata(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (BundleMetadata, error)
@@ -40,13 +57,14 @@ type RegistryProvider interface {
 type RegistryOptions struct {
 	// InsecureRegistry allows connecting to an unsecured registry or one without verifiable certificates.
 	InsecureRegistry bool
-}
 
-func (o RegistryOptions) toCraneOptions() []crane.Option {
-	var result []crane.Option
-	if o.InsecureRegistry {
-		transport := GetInsecureRegistryTransport()
-		result = []crane.Option{crane.Insecure, crane.WithTransport(transport)}
-	}
-	return result
+	// Mirrors are used instead of the mirrored registry when pulling bundles and images.
+	Mirrors config.RegistryMirrors
+
+	// RegistryTLS are the TLS settings for specific registries.
+	RegistryTLS config.RegistryTLSConfigs
+
+	// Parallelism is the maximum number of images, and layers of each image, that are transferred at the same time.
+	// Defaults to DefaultParallelism.
+	Parallelism int
 }
@@ -0,0 +1,346 @@
+package cnabtooci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/opencontainers/go-digest"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// emptyConfigMediaType is the media type of the empty config blob that is
+// referenced by artifact manifests, which do not have a configuration.
+const emptyConfigMediaType types.MediaType = "application/vnd.oci.empty.v1+json"
+
+// Artifact is content that is attached to an image in a registry, such as a
+// software bill of materials, and refers to the image as its subject.
+type Artifact struct {
+	// ArtifactType identifies the kind of artifact, e.g. application/spdx+json.
+	ArtifactType string
+
+	// Content of the artifact, saved as the only layer of the artifact manifest.
+	Content []byte
+
+	// Annotations on the artifact manifest.
+	Annotations map[string]string
+}
+
+// artifactManifest is an OCI image manifest that refers to another manifest,
+// its subject. The manifest types from go-containerregistry do not support
+// the artifactType and subject fields.
+type artifactManifest struct {
+	SchemaVersion int64             `json:"schemaVersion"`
+	MediaType     types.MediaType   `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        v1.Descriptor     `json:"config"`
+	Layers        []v1.Descriptor   `json:"layers"`
+	Subject       *v1.Descriptor    `json:"subject,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// referrersIndex is the image index that lists the manifests that refer to a subject.
+type referrersIndex struct {
+	SchemaVersion int64                `json:"schemaVersion"`
+	MediaType     types.MediaType      `json:"mediaType"`
+	Manifests     []referrerDescriptor `json:"manifests"`
+}
+
+// referrerDescriptor is a descriptor of a manifest that refers to a subject.
+type referrerDescriptor struct {
+	v1.Descriptor
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// rawManifest is a manifest that is pushed to a registry as-is.
+type rawManifest struct {
+	mediaType types.MediaType
+	data      []byte
+}
+
+func (m rawManifest) RawManifest() ([]byte, error) {
+	return m.data, nil
+}
+
+func (m rawManifest) MediaType() (types.MediaType, error) {
+	return m.mediaType, nil
+}
+
+// PushReferrer attaches an artifact to the subject image in the registry.
+// Returns the digest of the artifact manifest.
+func (r *Registry) PushReferrer(ctx context.Context, subject cnab.OCIReference, artifact Artifact, opts RegistryOptions) (digest.Digest, error) {
+	ctx, log := tracing.StartSpan(ctx, attribute.String("subject", subject.String()), attribute.String("artifactType", artifact.ArtifactType))
+	defer log.EndSpan()
+
+	opt, err := r.getCraneOptions(ctx, opts, subject.Registry())
+	if err != nil {
+		return "", log.Error(err)
+	}
+	craneOpts := crane.GetOptions(opt...)
+	subjectRef, subjectDesc, err := r.resolveSubject(subject, craneOpts)
+	if err != nil {
+		return "", log.Error(err)
+	}
+	repo := subjectRef.Context()
+
+	config := static.NewLayer([]byte("{}"), emptyConfigMediaType)
+	content := static.NewLayer(artifact.Content, types.MediaType(artifact.ArtifactType))
+	for _, blob := range []v1.Layer{config, content} {
+		if err = remote.WriteLayer(repo, blob, craneOpts.Remote...); err != nil {
+			return "", log.Errorf("error pushing the %s artifact for %s: %w", artifact.ArtifactType, subject, err)
+		}
+	}
+
+	configDesc, err := partial.Descriptor(config)
+	if err != nil {
+		return "", log.Error(err)
+	}
+	contentDesc, err := partial.Descriptor(content)
+	if err != nil {
+		return "", log.Error(err)
+	}
+	m := artifactManifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		ArtifactType:  artifact.ArtifactType,
+		Config:        *configDesc,
+		Layers:        []v1.Descriptor{*contentDesc},
+		Subject:       &subjectDesc,
+		Annotations:   artifact.Annotations,
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", log.Errorf("error marshaling the %s artifact manifest: %w", artifact.ArtifactType, err)
+	}
+	artifactDigest := digest.FromBytes(data)
+	if err = remote.Put(repo.Digest(artifactDigest.String()), rawManifest{mediaType: m.MediaType, data: data}, craneOpts.Remote...); err != nil {
+		return "", log.Errorf("error pushing the %s artifact manifest for %s: %w", artifact.ArtifactType, subject, err)
+	}
+
+	// Registries that do not support the referrers API rely upon clients
+	// maintaining a tag that lists the referrers of the subject.
+	_, supported, err := r.getReferrersFromAPI(ctx, repo, subjectDesc.Digest, opts)
+	if err != nil {
+		return "", log.Error(err)
+	}
+	if !supported {
+		artifactDesc := referrerDescriptor{
+			Descriptor: v1.Descriptor{
+				MediaType:   m.MediaType,
+				Size:        int64(len(data)),
+				Digest:      v1.Hash{Algorithm: artifactDigest.Algorithm().String(), Hex: artifactDigest.Encoded()},
+				Annotations: artifact.Annotations,
+			},
+			ArtifactType: artifact.ArtifactType,
+		}
+		if err = updateReferrersTag(repo, subjectDesc.Digest, artifactDesc, craneOpts); err != nil {
+			return "", log.Error(err)
+		}
+	}
+
+	log.Debugf("Attached %s artifact %s to %s", artifact.ArtifactType, artifactDigest, subject)
+	return artifactDigest, nil
+}
+
+// PullReferrer returns the most recent artifact of the specified type that is
+// attached to the subject image in the registry.
+// Use ErrNotFound to detect if the error is because no artifact is attached to the image.
+func (r *Registry) PullReferrer(ctx context.Context, subject cnab.OCIReference, artifactType string, opts RegistryOptions) (Artifact, error) {
+	ctx, log := tracing.StartSpan(ctx, attribute.String("subject", subject.String()), attribute.String("artifactType", artifactType))
+	defer log.EndSpan()
+
+	opt, err := r.getCraneOptions(ctx, opts, subject.Registry())
+	if err != nil {
+		return Artifact{}, log.Error(err)
+	}
+	craneOpts := crane.GetOptions(opt...)
+	subjectRef, subjectDesc, err := r.resolveSubject(subject, craneOpts)
+	if err != nil {
+		return Artifact{}, log.Error(err)
+	}
+	repo := subjectRef.Context()
+
+	referrers, supported, err := r.getReferrersFromAPI(ctx, repo, subjectDesc.Digest, opts)
+	if err != nil {
+		return Artifact{}, log.Error(err)
+	}
+	if !supported {
+		referrers, err = getReferrersTag(repo, subjectDesc.Digest, craneOpts)
+		if err != nil {
+			return Artifact{}, log.Error(err)
+		}
+	}
+
+	// The referrers are listed in the order that they were attached, use the most recent one
+	var match *referrerDescriptor
+	for i := range referrers.Manifests {
+		if referrers.Manifests[i].ArtifactType == artifactType {
+			match = &referrers.Manifests[i]
+		}
+	}
+	if match == nil {
+		return Artifact{}, log.Error(fmt.Errorf("no %s artifact is attached to %s: %w", artifactType, subject, ErrNotFound{Reference: subject}))
+	}
+
+	desc, err := remote.Get(repo.Digest(match.Digest.String()), craneOpts.Remote...)
+	if err != nil {
+		return Artifact{}, log.Errorf("error retrieving the %s artifact manifest %s: %w", artifactType, match.Digest, err)
+	}
+	var m artifactManifest
+	if err = json.Unmarshal(desc.Manifest, &m); err != nil {
+		return Artifact{}, log.Errorf("error parsing the %s artifact manifest %s: %w", artifactType, match.Digest, err)
+	}
+	if len(m.Layers) != 1 {
+		return Artifact{}, log.Errorf("expected the %s artifact manifest %s to contain a single layer but found %d", artifactType, match.Digest, len(m.Layers))
+	}
+
+	layer, err := remote.Layer(repo.Digest(m.Layers[0].Digest.String()), craneOpts.Remote...)
+	if err != nil {
+		return Artifact{}, log.Errorf("error retrieving the %s artifact %s: %w", artifactType, match.Digest, err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return Artifact{}, log.Errorf("error retrieving the %s artifact %s: %w", artifactType, match.Digest, err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return Artifact{}, log.Errorf("error reading the %s artifact %s: %w", artifactType, match.Digest, err)
+	}
+
+	return Artifact{
+		ArtifactType: m.ArtifactType,
+		Content:      content,
+		Annotations:  m.Annotations,
+	}, nil
+}
+
+// resolveSubject returns the descriptor of the subject image in the registry.
+func (r *Registry) resolveSubject(subject cnab.OCIReference, craneOpts crane.Options) (name.Reference, v1.Descriptor, error) {
+	ref, err := name.ParseReference(subject.String(), craneOpts.Name...)
+	if err != nil {
+		return nil, v1.Descriptor{}, fmt.Errorf("error parsing %s as an image reference: %w", subject, err)
+	}
+
+	desc, err := remote.Head(ref, craneOpts.Remote...)
+	if err != nil {
+		if notFoundErr := asNotFoundError(err, subject); notFoundErr != nil {
+			return nil, v1.Descriptor{}, notFoundErr
+		}
+		return nil, v1.Descriptor{}, fmt.Errorf("error retrieving image %s: %w", subject, err)
+	}
+	return ref, v1.Descriptor{MediaType: desc.MediaType, Size: desc.Size, Digest: desc.Digest}, nil
+}
+
+// getReferrersFromAPI lists the referrers of the subject with the referrers API.
+// Returns false when the registry does not support the referrers API.
+func (r *Registry) getReferrersFromAPI(ctx context.Context, repo name.Repository, subject v1.Hash, opts RegistryOptions) (referrersIndex, bool, error) {
+	auth, err := r.getKeychain(ctx).Resolve(repo)
+	if err != nil {
+		return referrersIndex{}, false, fmt.Errorf("error resolving the credentials for %s: %w", repo, err)
+	}
+
+	baseTransport, err := GetRegistryTransport(r.FileSystem, opts, repo.RegistryStr())
+	if err != nil {
+		return referrersIndex{}, false, err
+	}
+	rt, err := transport.NewWithContext(ctx, repo.Registry, auth, baseTransport, []string{repo.Scope(transport.PullScope)})
+	if err != nil {
+		return referrersIndex{}, false, fmt.Errorf("error connecting to %s: %w", repo.Registry, err)
+	}
+
+	u := url.URL{
+		Scheme: repo.Registry.Scheme(),
+		Host:   repo.RegistryStr(),
+		Path:   fmt.Sprintf("/v2/%s/referrers/%s", repo.RepositoryStr(), subject),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return referrersIndex{}, false, err
+	}
+	req.Header.Set("Accept", string(types.OCIImageIndex))
+
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		return referrersIndex{}, false, fmt.Errorf("error listing the referrers of %s@%s: %w", repo, subject, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return referrersIndex{}, false, nil
+	}
+	if err = transport.CheckError(resp, http.StatusOK); err != nil {
+		return referrersIndex{}, false, fmt.Errorf("error listing the referrers of %s@%s: %w", repo, subject, err)
+	}
+	// Registries that do not implement the API may respond with something other than an index
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), string(types.OCIImageIndex)) {
+		return referrersIndex{}, false, nil
+	}
+
+	var idx referrersIndex
+	if err = json.NewDecoder(resp.Body).Decode(&idx); err != nil {
+		return referrersIndex{}, false, fmt.Errorf("error parsing the referrers of %s@%s: %w", repo, subject, err)
+	}
+	return idx, true, nil
+}
+
+// getReferrersTagName returns the tag of the index that lists the referrers of the
+// subject, for registries that do not support the referrers API.
+func getReferrersTagName(subject v1.Hash) string {
+	return fmt.Sprintf("%s-%s", subject.Algorithm, subject.Hex)
+}
+
+// getReferrersTag returns the index that lists the referrers of the subject,
+// for registries that do not support the referrers API.
+func getReferrersTag(repo name.Repository, subject v1.Hash, craneOpts crane.Options) (referrersIndex, error) {
+	tag := repo.Tag(getReferrersTagName(subject))
+	desc, err := remote.Get(tag, craneOpts.Remote...)
+	if err != nil {
+		var httpError *transport.Error
+		if errors.As(err, &httpError) && httpError.StatusCode == http.StatusNotFound {
+			return referrersIndex{SchemaVersion: 2, MediaType: types.OCIImageIndex}, nil
+		}
+		return referrersIndex{}, fmt.Errorf("error retrieving the referrers of %s@%s: %w", repo, subject, err)
+	}
+
+	var idx referrersIndex
+	if err = json.Unmarshal(desc.Manifest, &idx); err != nil {
+		return referrersIndex{}, fmt.Errorf("error parsing the referrers of %s@%s: %w", repo, subject, err)
+	}
+	return idx, nil
+}
+
+// updateReferrersTag adds the artifact to the index that lists the referrers of the
+// subject, for registries that do not support the referrers API.
+func updateReferrersTag(repo name.Repository, subject v1.Hash, artifact referrerDescriptor, craneOpts crane.Options) error {
+	idx, err := getReferrersTag(repo, subject, craneOpts)
+	if err != nil {
+		return err
+	}
+	idx.Manifests = append(idx.Manifests, artifact)
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("error marshaling the referrers of %s@%s: %w", repo, subject, err)
+	}
+	tag := repo.Tag(getReferrersTagName(subject))
+	if err = remote.Put(tag, rawManifest{mediaType: types.OCIImageIndex, data: data}, craneOpts.Remote...); err != nil {
+		return fmt.Errorf("error updating the referrers of %s@%s: %w", repo, subject, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cnabtooci
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Referrers(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	imgRef := host + "/mybuns-installer:v1.0.0"
+	require.NoError(t, crane.Push(img, imgRef, crane.Insecure))
+
+	r := NewRegistry(portercontext.NewTestContext(t).Context)
+	subject := cnab.MustParseOCIReference(imgRef)
+	opts := RegistryOptions{InsecureRegistry: true}
+
+	_, err = r.PullReferrer(ctx, subject, "application/spdx+json", opts)
+	require.True(t, errors.Is(err, ErrNotFound{}), "expected ErrNotFound before anything is attached, got %v", err)
+
+	_, err = r.PushReferrer(ctx, subject, Artifact{ArtifactType: "application/spdx+json", Content: []byte(`{"spdxVersion":"SPDX-2.3"}`)}, opts)
+	require.NoError(t, err)
+	_, err = r.PushReferrer(ctx, subject, Artifact{
+		ArtifactType: "application/spdx+json",
+		Content:      []byte(`{"spdxVersion":"SPDX-2.3","name":"latest"}`),
+		Annotations:  map[string]string{"org.opencontainers.image.created": "2022-10-31T00:00:00Z"},
+	}, opts)
+	require.NoError(t, err)
+
+	artifact, err := r.PullReferrer(ctx, subject, "application/spdx+json", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "application/spdx+json", artifact.ArtifactType)
+	assert.Equal(t, `{"spdxVersion":"SPDX-2.3","name":"latest"}`, string(artifact.Content), "expected the most recently attached artifact")
+	assert.Equal(t, "2022-10-31T00:00:00Z", artifact.Annotations["org.opencontainers.image.created"])
+
+	_, err = r.PullReferrer(ctx, subject, "application/vnd.cyclonedx+json", opts)
+	require.True(t, errors.Is(err, ErrNotFound{}), "expected ErrNotFound for an artifact type that is not attached, got %v", err)
+
+	// The referrers are listed with a tag because the test registry does not support the referrers API
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+	tags, err := crane.ListTags(host+"/mybuns-installer", crane.Insecure)
+	require.NoError(t, err)
+	assert.Contains(t, tags, "sha256-"+imgDigest.Hex)
+}
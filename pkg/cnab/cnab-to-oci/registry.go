@@ -7,24 +7,32 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/metrics"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/tracing"
+	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-go/driver/docker"
 	"github.com/cnabio/cnab-to-oci/relocation"
 	"github.com/cnabio/cnab-to-oci/remotes"
 	containerdRemotes "github.com/containerd/containerd/remotes"
 	"github.com/docker/cli/cli/command"
-	dockerconfig "github.com/docker/cli/cli/config"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/moby/term"
 	"github.com/opencontainers/go-digest"
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/sync/errgroup"
 )
 
 // ErrNoContentDigest represents an error due to an image not having a
@@ -41,11 +49,22 @@ var _ RegistryProvider = &Registry{}
 
 type Registry struct {
 	*portercontext.Context
+
+	// CredentialHelpers obtain credentials for registries that the user has not logged into with docker login.
+	CredentialHelpers []CredentialHelper
+
+	// ContentStore saves the blobs that are copied between registries so that
+	// they are not downloaded again. Blobs are always fetched from the registry when it is nil.
+	ContentStore *ContentStore
+
+	credentialsCache *registryCredentialsCache
 }
 
 func NewRegistry(c *portercontext.Context) *Registry {
 	return &Registry{
-		Context: c,
+		Context:           c,
+		CredentialHelpers: DefaultCredentialHelpers(c),
+		credentialsCache:  &registryCredentialsCache{},
 	}
 }
 
@@ -56,13 +75,24 @@ func (r *Registry) PullBundle(ctx context.Context, ref cnab.OCIReference, opts R
 		attribute.Bool("insecure", opts.InsecureRegistry),
 	)
 	defer span.EndSpan()
+	defer metrics.ObserveRegistryPull(time.Now())
 
-	var insecureRegistries []string
-	if opts.InsecureRegistry {
-		reg := ref.Registry()
-		insecureRegistries = append(insecureRegistries, reg)
+	resolver, err := r.createResolver(ctx, opts, []string{ref.Registry()})
+	if err != nil {
+		return cnab.BundleReference{}, span.Error(err)
+	}
+
+	mirrorRef, hasMirror, err := opts.FindMirror(ref)
+	if err != nil {
+		return cnab.BundleReference{}, span.Error(err)
+	}
+	if hasMirror {
+		bundleRef, err := r.pullBundleFromMirror(ctx, ref, mirrorRef)
+		if err == nil {
+			return bundleRef, nil
+		}
+		span.Warnf("Unable to pull bundle %s from the registry mirror %s, pulling it from %s instead: %s", ref, mirrorRef.Mirror.Mirror, ref.Registry(), err)
 	}
-	resolver := r.createResolver(insecureRegistries)
 
 	if span.ShouldLog(zapcore.DebugLevel) {
 		msg := strings.Builder{}
@@ -74,14 +104,52 @@ func (r *Registry) PullBundle(ctx context.Context, ref cnab.OCIReference, opts R
 		span.Debug(msg.String())
 	}
 
-	bun, reloMap, digest, err := remotes.Pull(ctx, ref.Named, resolver)
+	bundleRef, err := pullBundle(ctx, ref, ref, resolver)
+	if err != nil {
+		return cnab.BundleReference{}, span.Error(err)
+	}
+	return bundleRef, nil
+}
+
+// pullBundleFromMirror pulls the bundle through the registry mirror.
+// The relocation mapping of the pulled bundle refers to the mirrored registry so that the mirror remains transparent.
+func (r *Registry) pullBundleFromMirror(ctx context.Context, ref cnab.OCIReference, mirrorRef MirroredReference) (cnab.BundleReference, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	resolver, err := r.createMirrorResolver(mirrorRef.Mirror)
 	if err != nil {
-		return cnab.BundleReference{}, span.Errorf("unable to pull bundle: %w", err)
+		return cnab.BundleReference{}, err
+	}
+
+	log.Debugf("Pulling bundle %s from the registry mirror %s", ref, mirrorRef.Reference)
+	bundleRef, err := pullBundle(ctx, ref, mirrorRef.Reference, resolver)
+	if err != nil {
+		return cnab.BundleReference{}, err
+	}
+
+	for originalImg, relocatedImg := range bundleRef.RelocationMap {
+		relocatedRef, err := cnab.ParseOCIReference(relocatedImg)
+		if err != nil || relocatedRef.Registry() != mirrorRef.Reference.Registry() {
+			continue
+		}
+		if relocatedRef, err = relocatedRef.WithRegistry(ref.Registry()); err != nil {
+			return cnab.BundleReference{}, err
+		}
+		bundleRef.RelocationMap[originalImg] = relocatedRef.Named.String()
+	}
+	return bundleRef, nil
+}
+
+// pullBundle pulls the bundle from pullRef, returning it as the bundle ref.
+func pullBundle(ctx context.Context, ref cnab.OCIReference, pullRef cnab.OCIReference, resolver containerdRemotes.Resolver) (cnab.BundleReference, error) {
+	bun, reloMap, digest, err := remotes.Pull(ctx, pullRef.Named, resolver)
+	if err != nil {
+		return cnab.BundleReference{}, fmt.Errorf("unable to pull bundle: %w", err)
 	}
 
 	invocationImage := bun.InvocationImages[0]
 	if invocationImage.Digest == "" {
-		return cnab.BundleReference{}, span.Error(NewErrNoContentDigest(invocationImage.Image))
+		return cnab.BundleReference{}, NewErrNoContentDigest(invocationImage.Image)
 	}
 
 	bundleRef := cnab.BundleReference{
@@ -98,31 +166,41 @@ func (r *Registry) PushBundle(ctx context.Context, bundleRef cnab.BundleReferenc
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
 
-	var insecureRegistries []string
-	if opts.InsecureRegistry {
-		// Get all source registries
-		registries, err := bundleRef.Definition.GetReferencedRegistries()
-		if err != nil {
-			return cnab.BundleReference{}, err
-		}
+	// Get all source registries
+	registries, err := bundleRef.Definition.GetReferencedRegistries()
+	if err != nil {
+		return cnab.BundleReference{}, err
+	}
 
-		// Include our destination registry
-		destReg := bundleRef.Reference.Registry()
-		found := false
-		for _, reg := range registries {
-			if destReg == reg {
-				found = true
-			}
-		}
-		if !found {
-			registries = append(registries, destReg)
+	// Include our destination registry
+	destReg := bundleRef.Reference.Registry()
+	found := false
+	for _, reg := range registries {
+		if destReg == reg {
+			found = true
 		}
+	}
+	if !found {
+		registries = append(registries, destReg)
+	}
 
-		// All registries used should be marked as allowing insecure connections
-		insecureRegistries = registries
-		log.SetAttributes(attribute.String("insecure-registries", strings.Join(registries, ",")))
+	var insecureRegistries []string
+	for _, reg := range registries {
+		if opts.IsInsecure(reg) {
+			insecureRegistries = append(insecureRegistries, reg)
+		}
+	}
+	if len(insecureRegistries) > 0 {
+		log.SetAttributes(attribute.String("insecure-registries", strings.Join(insecureRegistries, ",")))
 	}
-	resolver := r.createResolver(insecureRegistries)
+	resolver, err := r.createResolver(ctx, opts, registries)
+	if err != nil {
+		return cnab.BundleReference{}, log.Error(err)
+	}
+	// Copy the bundle's images through the registry mirrors, but always push directly to the destination registry
+	resolver = r.newMirrorResolver(resolver, opts.withoutMirror(destReg))
+	// Reuse the blobs that were already downloaded, instead of fetching them from the registry again
+	resolver = r.newContentStoreResolver(resolver)
 
 	if log.ShouldLog(zapcore.DebugLevel) {
 		msg := strings.Builder{}
@@ -138,10 +216,7 @@ func (r *Registry) PushBundle(ctx context.Context, bundleRef cnab.BundleReferenc
 	if bundleRef.RelocationMap == nil {
 		bundleRef.RelocationMap = make(relocation.ImageRelocationMap)
 	}
-	rm, err := remotes.FixupBundle(ctx, &bundleRef.Definition.Bundle, bundleRef.Reference.Named, resolver,
-		remotes.WithEventCallback(r.displayEvent),
-		remotes.WithAutoBundleUpdate(),
-		remotes.WithRelocationMap(bundleRef.RelocationMap))
+	rm, err := r.fixupBundle(ctx, &bundleRef, resolver, opts)
 	if err != nil {
 		return cnab.BundleReference{}, log.Error(fmt.Errorf("error preparing the bundle with cnab-to-oci before pushing: %w", err))
 	}
@@ -157,6 +232,72 @@ func (r *Registry) PushBundle(ctx context.Context, bundleRef cnab.BundleReferenc
 	return bundleRef, nil
 }
 
+// fixupBundle copies the images of the bundle into the bundle's repository, updating the bundle
+// with the digest of each image, and returns the relocation mapping of the copied images.
+// Up to opts.Parallelism images are copied at the same time, and up to opts.Parallelism layers of each image.
+func (r *Registry) fixupBundle(ctx context.Context, bundleRef *cnab.BundleReference, resolver containerdRemotes.Resolver, opts RegistryOptions) (relocation.ImageRelocationMap, error) {
+	parallelism := opts.GetParallelism()
+	bun := &bundleRef.Definition.Bundle
+
+	baseImages := make([]*bundle.BaseImage, 0, len(bun.InvocationImages)+len(bun.Images))
+	for i := range bun.InvocationImages {
+		baseImages = append(baseImages, &bun.InvocationImages[i].BaseImage)
+	}
+	images := make(map[string]*bundle.Image, len(bun.Images))
+	for name, img := range bun.Images {
+		img := img
+		images[name] = &img
+		baseImages = append(baseImages, &img.BaseImage)
+	}
+
+	relocationMap := make(relocation.ImageRelocationMap, len(bundleRef.RelocationMap))
+	for k, v := range bundleRef.RelocationMap {
+		relocationMap[k] = v
+	}
+	var relocationMapLock sync.Mutex
+
+	// cnab-to-oci copies the images of a bundle one at a time, so copy each image
+	// separately, using a bundle that only has that image as its invocation image
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(parallelism)
+	for _, baseImage := range baseImages {
+		baseImage := baseImage
+		g.Go(func() error {
+			imageBundle := bundle.Bundle{InvocationImages: []bundle.InvocationImage{{BaseImage: *baseImage}}}
+			// Copy relocated images from their new location
+			imageRelocationMap := make(relocation.ImageRelocationMap, 1)
+			if relocated, ok := bundleRef.RelocationMap[baseImage.Image]; ok {
+				imageRelocationMap[baseImage.Image] = relocated
+			}
+
+			rm, err := remotes.FixupBundle(ctx, &imageBundle, bundleRef.Reference.Named, resolver,
+				remotes.WithEventCallback(r.displayEvent),
+				remotes.WithAutoBundleUpdate(),
+				remotes.WithRelocationMap(imageRelocationMap),
+				remotes.WithParallelism(parallelism, fixupJobsBufferLength))
+			if err != nil {
+				return err
+			}
+			*baseImage = imageBundle.InvocationImages[0].BaseImage
+
+			relocationMapLock.Lock()
+			defer relocationMapLock.Unlock()
+			for k, v := range rm {
+				relocationMap[k] = v
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for name, img := range images {
+		bun.Images[name] = *img
+	}
+	return relocationMap, nil
+}
+
 // PushImage pushes the image from the Docker image cache to the specified location
 // the expected format of the image is REGISTRY/NAME:TAG.
 // Returns the image digest from the registry.
@@ -174,7 +315,7 @@ func (r *Registry) PushImage(ctx context.Context, ref cnab.OCIReference, opts Re
 	if err != nil {
 		return "", log.Errorf("error parsing the repository potion of the image reference %s: %w", ref, err)
 	}
-	authConfig := command.ResolveAuthConfig(ctx, cli, repoInfo.Index)
+	authConfig := r.resolveAuthConfig(ctx, ref.Registry(), command.ResolveAuthConfig(ctx, cli, repoInfo.Index))
 	encodedAuth, err := command.EncodeAuthToBase64(authConfig)
 	if err != nil {
 		return "", log.Errorf("error encoding authentication information for the docker client: %w", err)
@@ -208,6 +349,73 @@ func (r *Registry) PushImage(ctx context.Context, ref cnab.OCIReference, opts Re
 	return dist.Descriptor.Digest, nil
 }
 
+// PushImageIndex pushes the multi-platform image saved in the OCI image layout
+// to the specified location, preserving the image index.
+// Returns the digest of the image index from the registry.
+func (r *Registry) PushImageIndex(ctx context.Context, ref cnab.OCIReference, layoutPath string, opts RegistryOptions) (digest.Digest, error) {
+	//lint:ignore SA4006 ignore unused context for now
+	ctx, log := tracing.StartSpan(ctx, attribute.String("reference", ref.String()), attribute.String("layout", layoutPath))
+	defer log.EndSpan()
+
+	idx, desc, err := readImageLayoutIndex(layoutPath)
+	if err != nil {
+		return "", log.Error(err)
+	}
+
+	dest, err := name.ParseReference(ref.String())
+	if err != nil {
+		return "", log.Errorf("error parsing %s as an image reference: %w", ref, err)
+	}
+
+	log.Info("Pushing bundle image index...")
+	opt, err := r.getCraneOptions(ctx, opts, ref.Registry())
+	if err != nil {
+		return "", log.Error(err)
+	}
+	craneOpts := crane.GetOptions(opt...)
+	if err = remote.WriteIndex(dest, idx, craneOpts.Remote...); err != nil {
+		return "", log.Errorf("error pushing image index %s: %w", ref, err)
+	}
+
+	return digest.Digest(desc.Digest.String()), nil
+}
+
+// GetImageLayoutDigest returns the digest of the multi-platform image index saved
+// in the OCI image layout, which is the digest that it has once pushed to a registry.
+func GetImageLayoutDigest(layoutPath string) (digest.Digest, error) {
+	_, desc, err := readImageLayoutIndex(layoutPath)
+	if err != nil {
+		return "", err
+	}
+	return digest.Digest(desc.Digest.String()), nil
+}
+
+// readImageLayoutIndex returns the image index saved in the OCI image layout,
+// and its descriptor.
+func readImageLayoutIndex(layoutPath string) (v1.ImageIndex, v1.Descriptor, error) {
+	layoutIndex, err := layout.ImageIndexFromPath(layoutPath)
+	if err != nil {
+		return nil, v1.Descriptor{}, fmt.Errorf("error reading the OCI image layout at %s: %w", layoutPath, err)
+	}
+	layoutManifest, err := layoutIndex.IndexManifest()
+	if err != nil {
+		return nil, v1.Descriptor{}, fmt.Errorf("error reading the index of the OCI image layout at %s: %w", layoutPath, err)
+	}
+	if len(layoutManifest.Manifests) != 1 {
+		return nil, v1.Descriptor{}, fmt.Errorf("expected the OCI image layout at %s to contain a single image but found %d", layoutPath, len(layoutManifest.Manifests))
+	}
+
+	desc := layoutManifest.Manifests[0]
+	if !desc.MediaType.IsIndex() {
+		return nil, v1.Descriptor{}, fmt.Errorf("expected the OCI image layout at %s to contain an image index but found %s", layoutPath, desc.MediaType)
+	}
+	idx, err := layoutIndex.ImageIndex(desc.Digest)
+	if err != nil {
+		return nil, v1.Descriptor{}, fmt.Errorf("error reading image index %s from the OCI image layout at %s: %w", desc.Digest, layoutPath, err)
+	}
+	return idx, desc, nil
+}
+
 // PullImage pulls an image from an OCI registry.
 func (r *Registry) PullImage(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) error {
 	ctx, log := tracing.StartSpan(ctx)
@@ -218,15 +426,60 @@ func (r *Registry) PullImage(ctx context.Context, ref cnab.OCIReference, opts Re
 		return log.Error(err)
 	}
 
+	mirrorRef, hasMirror, err := opts.FindMirror(ref)
+	if err != nil {
+		return log.Error(err)
+	}
+	if hasMirror {
+		err = r.pullImageFromMirror(ctx, cli, ref, mirrorRef)
+		if err == nil {
+			return nil
+		}
+		log.Warnf("Unable to pull image %s from the registry mirror %s, pulling it from %s instead: %s", ref, mirrorRef.Mirror.Mirror, ref.Registry(), err)
+	}
+
 	// Resolve the Repository name from fqn to RepositoryInfo
 	repoInfo, err := ref.ParseRepositoryInfo()
 	if err != nil {
 		return log.Error(err)
 	}
-	authConfig := command.ResolveAuthConfig(ctx, cli, repoInfo.Index)
+	authConfig := r.resolveAuthConfig(ctx, ref.Registry(), command.ResolveAuthConfig(ctx, cli, repoInfo.Index))
+	return log.Error(dockerPull(ctx, cli, ref, authConfig))
+}
+
+// pullImageFromMirror pulls the image through the registry mirror with docker,
+// and then tags it with the original image reference so that the mirror remains transparent.
+func (r *Registry) pullImageFromMirror(ctx context.Context, cli *command.DockerCli, ref cnab.OCIReference, mirrorRef MirroredReference) error {
+	var authConfig types.AuthConfig
+	if mirrorRef.Mirror.Username != "" {
+		authConfig = types.AuthConfig{
+			Username:      mirrorRef.Mirror.Username,
+			Password:      mirrorRef.Mirror.Password,
+			ServerAddress: mirrorRef.Mirror.Mirror,
+		}
+	} else {
+		repoInfo, err := mirrorRef.Reference.ParseRepositoryInfo()
+		if err != nil {
+			return err
+		}
+		authConfig = command.ResolveAuthConfig(ctx, cli, repoInfo.Index)
+	}
+
+	if err := dockerPull(ctx, cli, mirrorRef.Reference, authConfig); err != nil {
+		return err
+	}
+
+	if err := cli.Client().ImageTag(ctx, mirrorRef.Reference.String(), ref.String()); err != nil {
+		return fmt.Errorf("error tagging image %s as %s: %w", mirrorRef.Reference, ref, err)
+	}
+	return nil
+}
+
+// dockerPull pulls the image into the local docker cache.
+func dockerPull(ctx context.Context, cli *command.DockerCli, ref cnab.OCIReference, authConfig types.AuthConfig) error {
 	encodedAuth, err := command.EncodeAuthToBase64(authConfig)
 	if err != nil {
-		return log.Error(fmt.Errorf("failed to serialize docker auth config: %w", err))
+		return fmt.Errorf("failed to serialize docker auth config: %w", err)
 	}
 	options := types.ImagePullOptions{
 		RegistryAuth: encodedAuth,
@@ -235,7 +488,7 @@ func (r *Registry) PullImage(ctx context.Context, ref cnab.OCIReference, opts Re
 	imgRef := ref.String()
 	rd, err := cli.Client().ImagePull(ctx, imgRef, options)
 	if err != nil {
-		return log.Error(fmt.Errorf("docker pull for image %s failed: %w", imgRef, err))
+		return fmt.Errorf("docker pull for image %s failed: %w", imgRef, err)
 	}
 	defer rd.Close()
 
@@ -248,8 +501,14 @@ func (r *Registry) PullImage(ctx context.Context, ref cnab.OCIReference, opts Re
 	return nil
 }
 
-func (r *Registry) createResolver(insecureRegistries []string) containerdRemotes.Resolver {
-	return remotes.CreateResolver(dockerconfig.LoadDefaultConfigFile(r.Out), insecureRegistries...)
+// getCraneOptions returns the crane options for connecting to a registry, applying its TLS settings, and
+// using the docker config for credentials, and the credential helpers for any registries that the user has not logged into.
+func (r *Registry) getCraneOptions(ctx context.Context, opts RegistryOptions, registry string) ([]crane.Option, error) {
+	result, err := GetCraneOptions(r.FileSystem, opts, registry)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, crane.WithAuthFromKeychain(r.getKeychain(ctx))), nil
 }
 
 func (r *Registry) displayEvent(ev remotes.FixupEvent) {
@@ -297,7 +556,11 @@ func (r *Registry) ListTags(ctx context.Context, ref cnab.OCIReference, opts Reg
 	ctx, span := tracing.StartSpan(ctx, attribute.String("repository", repository))
 	defer span.EndSpan()
 
-	tags, err := crane.ListTags(repository, opts.toCraneOptions()...)
+	craneOpts, err := r.getCraneOptions(ctx, opts, ref.Registry())
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	tags, err := crane.ListTags(repository, craneOpts...)
 	if err != nil {
 		if notFoundErr := asNotFoundError(err, ref); notFoundErr != nil {
 			return nil, span.Error(notFoundErr)
@@ -308,6 +571,25 @@ func (r *Registry) ListTags(ctx context.Context, ref cnab.OCIReference, opts Reg
 	return tags, nil
 }
 
+// ListRepositories returns the names of the repositories in the registry, without the registry host,
+// using the registry's catalog API.
+func (r *Registry) ListRepositories(ctx context.Context, registry string, opts RegistryOptions) ([]string, error) {
+	//lint:ignore SA4006 ignore unused context for now
+	ctx, span := tracing.StartSpan(ctx, attribute.String("registry", registry))
+	defer span.EndSpan()
+
+	craneOpts, err := r.getCraneOptions(ctx, opts, registry)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	repos, err := crane.Catalog(registry, craneOpts...)
+	if err != nil {
+		return nil, span.Errorf("error listing the repositories in %s: %w", registry, err)
+	}
+
+	return repos, nil
+}
+
 // GetBundleMetadata returns information about a bundle in a registry
 // Use ErrNotFound to detect if the error is because the bundle is not in the registry.
 func (r *Registry) GetBundleMetadata(ctx context.Context, ref cnab.OCIReference, opts RegistryOptions) (BundleMetadata, error) {
@@ -315,7 +597,11 @@ func (r *Registry) GetBundleMetadata(ctx context.Context, ref cnab.OCIReference,
 	ctx, span := tracing.StartSpan(ctx, attribute.String("reference", ref.String()))
 	defer span.EndSpan()
 
-	bundleDigest, err := crane.Digest(ref.String(), opts.toCraneOptions()...)
+	craneOpts, err := r.getCraneOptions(ctx, opts, ref.Registry())
+	if err != nil {
+		return BundleMetadata{}, span.Error(err)
+	}
+	bundleDigest, err := crane.Digest(ref.String(), craneOpts...)
 	if err != nil {
 		if notFoundErr := asNotFoundError(err, ref); notFoundErr != nil {
 			return BundleMetadata{}, span.Error(notFoundErr)
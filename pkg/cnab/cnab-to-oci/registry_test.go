@@ -1,12 +1,25 @@
 package cnabtooci
 
 import (
+	"bytes"
+	"context"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
 	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -95,3 +108,55 @@ func TestAsNotFoundError(t *testing.T) {
 		require.Nil(t, result)
 	})
 }
+
+func TestRegistry_PushImageIndex(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	idx, err := random.Index(64, 1, 2)
+	require.NoError(t, err)
+	wantDigest, err := idx.Digest()
+	require.NoError(t, err)
+
+	layoutDir := filepath.Join(t.TempDir(), "invocation-image")
+	lp, err := layout.Write(layoutDir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, lp.AppendIndex(idx))
+
+	layoutDigest, err := GetImageLayoutDigest(layoutDir)
+	require.NoError(t, err)
+	assert.Equal(t, wantDigest.String(), layoutDigest.String())
+
+	r := NewRegistry(portercontext.NewTestContext(t).Context)
+	ref := cnab.MustParseOCIReference(host + "/mybuns:v1.0.0")
+	gotDigest, err := r.PushImageIndex(context.Background(), ref, layoutDir, RegistryOptions{InsecureRegistry: true})
+	require.NoError(t, err)
+	assert.Equal(t, wantDigest.String(), gotDigest.String())
+
+	// The image index should be preserved in the registry
+	pushed, err := crane.Digest(ref.String(), crane.Insecure)
+	require.NoError(t, err)
+	assert.Equal(t, wantDigest.String(), pushed)
+
+	manifest, err := crane.Manifest(ref.String(), crane.Insecure)
+	require.NoError(t, err)
+	pushedIndex, err := v1.ParseIndexManifest(bytes.NewReader(manifest))
+	require.NoError(t, err)
+	assert.Len(t, pushedIndex.Manifests, 2)
+}
+
+func TestRegistry_PushImageIndex_NotAnIndex(t *testing.T) {
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+
+	layoutDir := filepath.Join(t.TempDir(), "invocation-image")
+	lp, err := layout.Write(layoutDir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, lp.AppendImage(img))
+
+	r := NewRegistry(portercontext.NewTestContext(t).Context)
+	ref := cnab.MustParseOCIReference("localhost:5000/mybuns:v1.0.0")
+	_, err = r.PushImageIndex(context.Background(), ref, layoutDir, RegistryOptions{})
+	require.ErrorContains(t, err, "to contain an image index")
+}
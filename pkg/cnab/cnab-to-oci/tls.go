@@ -0,0 +1,246 @@
+package cnabtooci
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"github.com/carolynvs/aferox"
+	containerdRemotes "github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	dockerconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/registry"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// FindRegistryTLS returns the TLS settings configured for the registry.
+// Returns false when the registry does not have any TLS settings.
+func (o RegistryOptions) FindRegistryTLS(registry string) (config.RegistryTLS, bool, error) {
+	if len(o.RegistryTLS) == 0 {
+		return config.RegistryTLS{}, false, nil
+	}
+	if err := o.RegistryTLS.Validate(); err != nil {
+		return config.RegistryTLS{}, false, fmt.Errorf("invalid registry-tls configuration: %w", err)
+	}
+
+	tlsConfig, ok := o.RegistryTLS.Find(registry)
+	return tlsConfig, ok, nil
+}
+
+// IsInsecure determines if the registry may be connected to over plain http,
+// or with a certificate that cannot be verified.
+func (o RegistryOptions) IsInsecure(registry string) bool {
+	if o.InsecureRegistry {
+		return true
+	}
+	tlsConfig, ok, err := o.FindRegistryTLS(registry)
+	return err == nil && ok && tlsConfig.Insecure
+}
+
+// GetRegistryTransport returns a copy of the default http transport that uses the TLS settings for the registry.
+// The --insecure-registry flag applies to every registry, otherwise the TLS settings for the registry from the
+// configuration file are used.
+func GetRegistryTransport(fs aferox.Aferox, opts RegistryOptions, registry string) (*http.Transport, error) {
+	tlsConfig, _, err := opts.FindRegistryTLS(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var transport *http.Transport
+	if opts.IsInsecure(registry) {
+		transport = GetInsecureRegistryTransport()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	if tlsConfig.CACertFile != "" && !transport.TLSClientConfig.InsecureSkipVerify {
+		certB, err := fs.ReadFile(tlsConfig.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry-tls.ca-cert-file %s for the %s registry: %w", tlsConfig.CACertFile, registry, err)
+		}
+		pool, ok := newCertPool(certB)
+		if !ok {
+			return nil, fmt.Errorf("could not use the certificate in %s for the %s registry", tlsConfig.CACertFile, registry)
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if tlsConfig.ClientCertFile != "" {
+		certB, err := fs.ReadFile(tlsConfig.ClientCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry-tls.client-cert-file %s for the %s registry: %w", tlsConfig.ClientCertFile, registry, err)
+		}
+		keyB, err := fs.ReadFile(tlsConfig.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry-tls.client-key-file %s for the %s registry: %w", tlsConfig.ClientKeyFile, registry, err)
+		}
+		cert, err := tls.X509KeyPair(certB, keyB)
+		if err != nil {
+			return nil, fmt.Errorf("could not use the client certificate in %s for the %s registry: %w", tlsConfig.ClientCertFile, registry, err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return transport, nil
+}
+
+// GetCraneOptions returns the crane options used to connect to the registry, applying its TLS settings.
+// Requests that are rate limited by the registry are retried.
+func GetCraneOptions(fs aferox.Aferox, opts RegistryOptions, registry string) ([]crane.Option, error) {
+	_, hasTLS, err := opts.FindRegistryTLS(registry)
+	if err != nil {
+		return nil, err
+	}
+	insecure := opts.IsInsecure(registry)
+	if !insecure && !hasTLS {
+		return []crane.Option{crane.WithTransport(NewRetryTransport(remote.DefaultTransport))}, nil
+	}
+
+	transport, err := GetRegistryTransport(fs, opts, registry)
+	if err != nil {
+		return nil, err
+	}
+	result := []crane.Option{crane.WithTransport(NewRetryTransport(transport))}
+	if insecure {
+		result = append(result, crane.Insecure)
+	}
+	return result, nil
+}
+
+// newCertPool returns the system certificate pool with the PEM encoded certificates appended.
+func newCertPool(certB []byte) (*x509.CertPool, bool) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	ok := pool.AppendCertsFromPEM(certB)
+	return pool, ok
+}
+
+// createResolver creates a resolver that uses the docker config for credentials,
+// and the credential helpers for any of the registries that the user has not logged into.
+// Registries with a custom certificate authority, or client certificate, use a resolver
+// configured with the registry's TLS settings.
+func (r *Registry) createResolver(ctx context.Context, opts RegistryOptions, registries []string) (containerdRemotes.Resolver, error) {
+	cfg := dockerconfig.LoadDefaultConfigFile(r.Out)
+	r.addHelperCredentials(ctx, cfg, registries)
+	creds := dockerConfigCredentials(cfg)
+
+	tlsResolvers := make(map[string]containerdRemotes.Resolver)
+	for _, registry := range registries {
+		_, hasTLS, err := opts.FindRegistryTLS(registry)
+		if err != nil {
+			return nil, err
+		}
+		if !hasTLS && !opts.IsInsecure(registry) {
+			continue
+		}
+
+		transport, err := GetRegistryTransport(r.FileSystem, opts, registry)
+		if err != nil {
+			return nil, err
+		}
+		// Only fallback to plain http when the registry is insecure and does not use TLS
+		tlsResolvers[registry] = newHostResolver(transport, registry, opts.IsInsecure(registry), matchNoHosts, creds)
+	}
+
+	// Every other registry uses TLS, except for registries on localhost
+	resolver := newHostResolver(http.DefaultTransport.(*http.Transport).Clone(), "", false, docker.MatchLocalhost, creds)
+	if len(tlsResolvers) == 0 {
+		return resolver, nil
+	}
+	return tlsResolver{Resolver: resolver, registries: tlsResolvers}, nil
+}
+
+// dockerConfigCredentials returns the credentials for a registry host from the docker config.
+func dockerConfigCredentials(cfg *configfile.ConfigFile) func(host string) (string, string, error) {
+	return func(host string) (string, string, error) {
+		// The credentials for Docker Hub are stored under the legacy index server
+		if host == registry.DefaultV2Registry.Host {
+			host = registry.IndexServer
+		}
+		a, err := cfg.GetAuthConfig(host)
+		if err != nil {
+			return "", "", err
+		}
+		if a.IdentityToken != "" {
+			return "", a.IdentityToken, nil
+		}
+		return a.Username, a.Password, nil
+	}
+}
+
+// newHostResolver creates a resolver that connects to a single registry host with the specified transport.
+// Plain http is used for the hosts matched by plainHTTP, or when the host is insecure and does not use TLS.
+// Requests that are rate limited by the registry are retried.
+func newHostResolver(transport *http.Transport, host string, insecure bool, plainHTTP func(host string) (bool, error), creds func(host string) (string, string, error)) containerdRemotes.Resolver {
+	client := &http.Client{Transport: NewRetryTransport(transport)}
+
+	// Insecure registries may not use TLS at all, so check ahead of time which scheme to use
+	if insecure {
+		resp, err := client.Get(fmt.Sprintf("https://%s/v2/", host))
+		if err == nil {
+			resp.Body.Close()
+		} else {
+			plainHTTP = docker.MatchAllHosts
+		}
+	}
+
+	return docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(
+			docker.WithClient(client),
+			docker.WithAuthorizer(docker.NewDockerAuthorizer(docker.WithAuthClient(client), docker.WithAuthCreds(creds))),
+			docker.WithPlainHTTP(plainHTTP),
+		),
+	})
+}
+
+// matchNoHosts is a docker.MatchFunc that does not match any host.
+func matchNoHosts(string) (bool, error) {
+	return false, nil
+}
+
+var _ containerdRemotes.Resolver = tlsResolver{}
+
+// tlsResolver uses a dedicated resolver for the registries that are insecure or have TLS settings in the
+// configuration file, and the default resolver for every other registry.
+type tlsResolver struct {
+	containerdRemotes.Resolver
+
+	// registries are the resolvers for registries with TLS settings, keyed by the registry.
+	registries map[string]containerdRemotes.Resolver
+}
+
+// getResolver returns the resolver for the registry of the reference.
+func (r tlsResolver) getResolver(ref string) containerdRemotes.Resolver {
+	parsedRef, err := cnab.ParseOCIReference(ref)
+	if err != nil {
+		return r.Resolver
+	}
+	if resolver, ok := r.registries[parsedRef.Registry()]; ok {
+		return resolver
+	}
+	return r.Resolver
+}
+
+func (r tlsResolver) Resolve(ctx context.Context, ref string) (string, ocispec.Descriptor, error) {
+	return r.getResolver(ref).Resolve(ctx, ref)
+}
+
+func (r tlsResolver) Fetcher(ctx context.Context, ref string) (containerdRemotes.Fetcher, error) {
+	return r.getResolver(ref).Fetcher(ctx, ref)
+}
+
+func (r tlsResolver) Pusher(ctx context.Context, ref string) (containerdRemotes.Pusher, error) {
+	return r.getResolver(ref).Pusher(ctx, ref)
+}
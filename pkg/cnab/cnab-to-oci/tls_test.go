@@ -0,0 +1,199 @@
+package cnabtooci
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeServerCA saves the certificate of the test server so that it can be used as a certificate authority.
+func writeServerCA(t *testing.T, c *portercontext.TestContext, server *httptest.Server, path string) {
+	certB := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, c.FileSystem.WriteFile(path, certB, 0600))
+}
+
+// writeClientCert generates a self-signed client certificate and its private key.
+func writeClientCert(t *testing.T, c *portercontext.TestContext, certPath string, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, c.FileSystem.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600))
+	require.NoError(t, c.FileSystem.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func TestRegistryOptions_IsInsecure(t *testing.T) {
+	opts := RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{
+		{Registry: "localhost:5000", Insecure: true},
+		{Registry: "registry.example.com", CACertFile: "ca.pem"},
+	}}
+	assert.True(t, opts.IsInsecure("localhost:5000"))
+	assert.False(t, opts.IsInsecure("registry.example.com"))
+	assert.False(t, opts.IsInsecure("docker.io"))
+
+	opts.InsecureRegistry = true
+	assert.True(t, opts.IsInsecure("docker.io"), "--insecure-registry should apply to every registry")
+}
+
+func TestGetCraneOptions(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+
+	t.Run("no tls settings", func(t *testing.T) {
+		opts, err := GetCraneOptions(c.FileSystem, RegistryOptions{}, "docker.io")
+		require.NoError(t, err)
+		assert.Len(t, opts, 1, "expected a transport that retries rate limited requests")
+	})
+
+	t.Run("insecure registry", func(t *testing.T) {
+		regOpts := RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{{Registry: "localhost:5000", Insecure: true}}}
+		opts, err := GetCraneOptions(c.FileSystem, regOpts, "localhost:5000")
+		require.NoError(t, err)
+		assert.Len(t, opts, 2, "expected a transport and crane.Insecure")
+
+		opts, err = GetCraneOptions(c.FileSystem, regOpts, "docker.io")
+		require.NoError(t, err)
+		assert.Len(t, opts, 1, "the settings should only apply to the configured registry")
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		regOpts := RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{{Registry: "localhost:5000", ClientCertFile: "client.pem"}}}
+		_, err := GetCraneOptions(c.FileSystem, regOpts, "localhost:5000")
+		require.ErrorContains(t, err, "invalid registry-tls configuration")
+	})
+}
+
+func TestGetRegistryTransport(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	writeServerCA(t, c, server, "ca.pem")
+	writeClientCert(t, c, "client.pem", "client-key.pem")
+
+	get := func(t *testing.T, regTLS config.RegistryTLS) error {
+		transport, err := GetRegistryTransport(c.FileSystem, RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{regTLS}}, host)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(fmt.Sprintf("https://%s/v2/", host))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	t.Run("ca and client certificate", func(t *testing.T) {
+		err := get(t, config.RegistryTLS{Registry: host, CACertFile: "ca.pem", ClientCertFile: "client.pem", ClientKeyFile: "client-key.pem"})
+		require.NoError(t, err)
+	})
+
+	t.Run("insecure with client certificate", func(t *testing.T) {
+		err := get(t, config.RegistryTLS{Registry: host, Insecure: true, ClientCertFile: "client.pem", ClientKeyFile: "client-key.pem"})
+		require.NoError(t, err)
+	})
+
+	t.Run("missing client certificate", func(t *testing.T) {
+		err := get(t, config.RegistryTLS{Registry: host, CACertFile: "ca.pem"})
+		require.Error(t, err)
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		err := get(t, config.RegistryTLS{Registry: host, ClientCertFile: "client.pem", ClientKeyFile: "client-key.pem"})
+		require.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		_, err := GetRegistryTransport(c.FileSystem, RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{{Registry: host, CACertFile: "missing.pem"}}}, host)
+		require.ErrorContains(t, err, "invalid registry-tls.ca-cert-file missing.pem")
+
+		require.NoError(t, c.FileSystem.WriteFile("bad.pem", []byte("not a certificate"), 0600))
+		_, err = GetRegistryTransport(c.FileSystem, RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{{Registry: host, CACertFile: "bad.pem"}}}, host)
+		require.ErrorContains(t, err, "could not use the certificate in bad.pem")
+
+		_, err = GetRegistryTransport(c.FileSystem, RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{{Registry: host, ClientCertFile: "client.pem", ClientKeyFile: "bad.pem"}}}, host)
+		require.ErrorContains(t, err, "could not use the client certificate in client.pem")
+	})
+}
+
+func TestRegistry_createResolver_RegistryTLS(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+	r := NewRegistry(c.Context)
+	ctx := context.Background()
+
+	// Push over plain http, and then read the image from the registry over TLS
+	handler := registry.New()
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	writeServerCA(t, c, server, "ca.pem")
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, crane.Push(img, strings.TrimPrefix(plain.URL, "http://")+"/getporter/whalesay:latest", crane.Insecure))
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+	imgRef := host + "/getporter/whalesay:latest"
+
+	t.Run("trusted certificate", func(t *testing.T) {
+		opts := RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{{Registry: host, CACertFile: "ca.pem"}}}
+		resolver, err := r.createResolver(ctx, opts, []string{host})
+		require.NoError(t, err)
+
+		_, desc, err := resolver.Resolve(ctx, imgRef)
+		require.NoError(t, err)
+		assert.Equal(t, imgDigest.String(), desc.Digest.String())
+	})
+
+	t.Run("insecure registry", func(t *testing.T) {
+		opts := RegistryOptions{RegistryTLS: config.RegistryTLSConfigs{{Registry: host, Insecure: true}}}
+		resolver, err := r.createResolver(ctx, opts, []string{host})
+		require.NoError(t, err)
+
+		_, desc, err := resolver.Resolve(ctx, imgRef)
+		require.NoError(t, err)
+		assert.Equal(t, imgDigest.String(), desc.Digest.String())
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		resolver, err := r.createResolver(ctx, RegistryOptions{}, []string{host})
+		require.NoError(t, err)
+
+		_, _, err = resolver.Resolve(ctx, imgRef)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,112 @@
+package cnabtooci
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// DefaultParallelism is the number of images, and layers of each image,
+	// that are transferred at the same time when Parallelism is not set.
+	DefaultParallelism = 4
+
+	// fixupJobsBufferLength is the number of layers that cnab-to-oci queues up
+	// while copying an image, which is the default used by cnab-to-oci.
+	fixupJobsBufferLength = 50
+
+	// rateLimitRetries is the number of times a request that was rate limited
+	// by the registry is retried before the response is returned.
+	rateLimitRetries = 5
+
+	// maxRetryDelay is the longest that a request is delayed before it is
+	// retried, even when the registry asks us to wait longer.
+	maxRetryDelay = time.Minute
+)
+
+// rateLimitBackoff is how long to wait before retrying a rate limited request
+// when the registry does not specify a Retry-After header. The wait is doubled
+// after each retry.
+var rateLimitBackoff = time.Second
+
+// GetParallelism returns the maximum number of images, and layers of each
+// image, that are transferred at the same time.
+func (o RegistryOptions) GetParallelism() int {
+	if o.Parallelism < 1 {
+		return DefaultParallelism
+	}
+	return o.Parallelism
+}
+
+var _ http.RoundTripper = retryTransport{}
+
+// retryTransport retries requests that the registry rejected with
+// 429 Too Many Requests, waiting before each retry. Registries rate limit
+// clients that transfer many layers at the same time, and the limit is
+// usually lifted after a short wait.
+type retryTransport struct {
+	transport http.RoundTripper
+}
+
+// NewRetryTransport wraps the transport so that requests that are rate limited
+// by the registry are retried. Only requests without a body are retried, since
+// the body of an upload cannot be read again.
+func NewRetryTransport(transport http.RoundTripper) http.RoundTripper {
+	if _, ok := transport.(retryTransport); ok {
+		return transport
+	}
+	return retryTransport{transport: transport}
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := rateLimitBackoff
+	for retry := 0; ; retry++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		if retry >= rateLimitRetries || (req.Body != nil && req.Body != http.NoBody) {
+			return resp, nil
+		}
+
+		delay := getRetryDelay(resp, backoff)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		log := tracing.LoggerFromContext(req.Context())
+		log.Debugf("%s %s was rate limited by the registry, retrying in %s", req.Method, req.URL.Redacted(), delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// getRetryDelay returns how long to wait before retrying a rate limited request,
+// using the Retry-After header from the registry when it is set.
+func getRetryDelay(resp *http.Response, backoff time.Duration) time.Duration {
+	delay := backoff
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			delay = time.Duration(seconds) * time.Second
+		} else if when, err := http.ParseTime(retryAfter); err == nil {
+			delay = time.Until(when)
+		}
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
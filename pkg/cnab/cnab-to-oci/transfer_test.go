@@ -0,0 +1,160 @@
+package cnabtooci
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryOptions_GetParallelism(t *testing.T) {
+	assert.Equal(t, DefaultParallelism, RegistryOptions{}.GetParallelism())
+	assert.Equal(t, 8, RegistryOptions{Parallelism: 8}.GetParallelism())
+}
+
+func TestRetryTransport(t *testing.T) {
+	defer func(backoff time.Duration) { rateLimitBackoff = backoff }(rateLimitBackoff)
+	rateLimitBackoff = time.Millisecond
+
+	// Rate limit the first requests to the server
+	newServer := func(limit int) (*httptest.Server, *int) {
+		var requests int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests <= limit {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		return srv, &requests
+	}
+
+	t.Run("retry rate limited requests", func(t *testing.T) {
+		srv, requests := newServer(2)
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport)}
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 3, *requests)
+	})
+
+	t.Run("stop retrying", func(t *testing.T) {
+		srv, requests := newServer(100)
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport)}
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, rateLimitRetries+1, *requests)
+	})
+
+	t.Run("requests with a body are not retried", func(t *testing.T) {
+		srv, requests := newServer(1)
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport)}
+		resp, err := client.Post(srv.URL, "application/octet-stream", strings.NewReader("layer"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, 1, *requests)
+	})
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	assert.Equal(t, time.Second, getRetryDelay(resp, time.Second), "the backoff should be used when Retry-After is not set")
+
+	resp.Header.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, getRetryDelay(resp, time.Second))
+
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, maxRetryDelay, getRetryDelay(resp, time.Second), "the delay should be limited")
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	assert.Equal(t, time.Duration(0), getRetryDelay(resp, time.Second))
+}
+
+func TestRegistry_PushBundle_Parallelism(t *testing.T) {
+	// Track how many blobs are downloaded from the source registry at the same time.
+	// The bundle is copied to a different registry, the test registry does not support
+	// pushing a manifest to a repository while reading another manifest from it.
+	var lock sync.Mutex
+	var active, maxActive int
+	srcRegistry := registry.New()
+	src := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+			lock.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			lock.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			defer func() {
+				lock.Lock()
+				active--
+				lock.Unlock()
+			}()
+		}
+		srcRegistry.ServeHTTP(w, r)
+	}))
+	defer src.Close()
+	srcHost := strings.TrimPrefix(src.URL, "http://")
+	dest := httptest.NewServer(registry.New())
+	defer dest.Close()
+	destHost := strings.TrimPrefix(dest.URL, "http://")
+
+	pushImage := func(repo string) string {
+		img, err := random.Image(64, 1)
+		require.NoError(t, err)
+		ref := srcHost + "/" + repo + ":v1"
+		require.NoError(t, crane.Push(img, ref))
+		return ref
+	}
+	b := bundle.Bundle{
+		SchemaVersion: "v1.2.0",
+		Name:          "mybuns",
+		Version:       "0.1.0",
+		InvocationImages: []bundle.InvocationImage{
+			{BaseImage: bundle.BaseImage{Image: pushImage("src/installer"), ImageType: "docker"}},
+		},
+		Images: map[string]bundle.Image{
+			"web": {BaseImage: bundle.BaseImage{Image: pushImage("src/web"), ImageType: "docker"}},
+			"db":  {BaseImage: bundle.BaseImage{Image: pushImage("src/db"), ImageType: "docker"}},
+		},
+	}
+	destRef, err := cnab.ParseOCIReference(destHost + "/dest/mybuns:v0.1.0")
+	require.NoError(t, err)
+
+	r := NewRegistry(portercontext.NewTestContext(t).Context)
+	bunRef, err := r.PushBundle(context.Background(), cnab.BundleReference{Reference: destRef, Definition: cnab.NewBundle(b)}, RegistryOptions{Parallelism: 3})
+	require.NoError(t, err)
+
+	// Each image has a config and a single layer, so more than two blobs are only downloaded together when images are copied at the same time
+	assert.Greater(t, maxActive, 2, "the images should be copied at the same time")
+	require.Len(t, bunRef.RelocationMap, 3)
+	for _, img := range []bundle.BaseImage{bunRef.Definition.InvocationImages[0].BaseImage, bunRef.Definition.Images["web"].BaseImage, bunRef.Definition.Images["db"].BaseImage} {
+		assert.NotEmpty(t, img.Digest, "the bundle should be updated with the digest of %s", img.Image)
+		assert.Equal(t, destHost+"/dest/mybuns@"+img.Digest, bunRef.RelocationMap[img.Image])
+	}
+}
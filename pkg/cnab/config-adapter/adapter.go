@@ -8,6 +8,7 @@ import (
 
 	"get.porter.sh/porter/pkg/cnab"
 	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
+	depsv2 "get.porter.sh/porter/pkg/cnab/dependencies/v2"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/experimental"
 	"get.porter.sh/porter/pkg/manifest"
@@ -55,6 +56,7 @@ func (c *ManifestConverter) ToBundle(ctx context.Context) (cnab.ExtendedBundle,
 		Description:   c.Manifest.Description,
 		Version:       c.Manifest.Version,
 		Maintainers:   c.generateBundleMaintainers(),
+		License:       c.Manifest.License,
 		Custom:        make(map[string]interface{}, 1),
 	})
 	image := bundle.InvocationImage{
@@ -233,6 +235,36 @@ func (c *ManifestConverter) generateBundleParameters(ctx context.Context, defs *
 		addParam(p)
 	}
 
+	for _, p := range c.buildActionArgumentParameters() {
+		addParam(p)
+	}
+
+	return params
+}
+
+// buildActionArgumentParameters generates an internal parameter for each argument
+// of a custom action, which only applies to that action.
+func (c *ManifestConverter) buildActionArgumentParameters() []manifest.ParameterDefinition {
+	var params []manifest.ParameterDefinition
+	for action, def := range c.Manifest.CustomActionDefinitions {
+		for _, arg := range def.Arguments {
+			param := manifest.ParameterDefinition{
+				Name:    cnab.GetActionArgumentParameterName(action, arg.Name),
+				ApplyTo: []string{action},
+				Schema: definition.Schema{
+					ID:          cnab.ActionArgumentSchemaID,
+					Description: arg.Description,
+					Type:        "string",
+					Comment:     cnab.PorterInternal,
+				},
+			}
+			// Default optional arguments, even to an empty value, so that the parameter is not required
+			if !arg.Required {
+				param.Default = arg.Default
+			}
+			params = append(params, param)
+		}
+	}
 	return params
 }
 
@@ -340,7 +372,7 @@ func (c *ManifestConverter) buildDefaultPorterParameters() []manifest.ParameterD
 }
 
 func (c *ManifestConverter) buildDefaultPorterOutputs() []manifest.OutputDefinition {
-	return []manifest.OutputDefinition{
+	outputs := []manifest.OutputDefinition{
 		{
 			Name:    "porter-state",
 			IsState: true,
@@ -354,6 +386,25 @@ func (c *ManifestConverter) buildDefaultPorterOutputs() []manifest.OutputDefinit
 			},
 		},
 	}
+
+	// Only add the metrics output when requested, so that the bundle, and its
+	// digest, does not change for existing bundles
+	if c.Manifest.Metrics {
+		outputs = append(outputs, manifest.OutputDefinition{
+			Name: cnab.OutputPorterMetrics,
+			Path: "/cnab/app/outputs/" + cnab.OutputPorterMetrics,
+			Schema: definition.Schema{
+				ID:          "https://getporter.org/generated-bundle/#porter-metrics",
+				Description: "Records how long each step of the action took to execute. Porter internal output that should not be used by bundles.",
+				Type:        "string",
+				// Default to no metrics when the bundle is run by an older Porter runtime
+				Default: "[]",
+				Comment: cnab.PorterInternal,
+			},
+		})
+	}
+
+	return outputs
 }
 
 func (c *ManifestConverter) generateBundleCredentials() map[string]bundle.Credential {
@@ -414,7 +465,17 @@ func (c *ManifestConverter) generateDependencies() (interface{}, string, error)
 
 	// Check if they are using v1 of the dependencies spec or v2
 	if c.config.IsFeatureEnabled(experimental.FlagDependenciesV2) {
-		panic("the dependencies-v2 experimental flag was specified but is not yet implemented")
+		deps, err := c.generateDependenciesV2()
+		if err != nil {
+			return nil, "", err
+		}
+		return deps, cnab.DependenciesV2ExtensionKey, nil
+	}
+
+	for _, dep := range c.Manifest.Dependencies.Requires {
+		if dep.Sharing.Mode != "" {
+			return nil, "", fmt.Errorf("dependency %s specifies sharing, which requires the %s experimental feature", dep.Name, experimental.DependenciesV2)
+		}
 	}
 
 	deps, err := c.generateDependenciesV1()
@@ -457,6 +518,30 @@ func (c *ManifestConverter) generateDependenciesV1() (*depsv1.Dependencies, erro
 	return deps, nil
 }
 
+func (c *ManifestConverter) generateDependenciesV2() (*depsv2.Dependencies, error) {
+	deps := &depsv2.Dependencies{
+		Sequence: make([]string, 0, len(c.Manifest.Dependencies.Requires)),
+		Requires: make(map[string]depsv2.Dependency, len(c.Manifest.Dependencies.Requires)),
+	}
+
+	for _, dep := range c.Manifest.Dependencies.Requires {
+		dependencyRef := depsv2.Dependency{
+			Name:    dep.Name,
+			Bundle:  dep.Bundle.Reference,
+			Version: dep.Bundle.Version,
+			Sharing: depsv2.SharingCriteria{
+				Mode:  dep.Sharing.Mode,
+				Group: depsv2.SharingGroup{Name: dep.Sharing.Group.Name},
+			},
+			Parameters: dep.Parameters,
+		}
+		deps.Sequence = append(deps.Sequence, dep.Name)
+		deps.Requires[dep.Name] = dependencyRef
+	}
+
+	return deps, nil
+}
+
 func (c *ManifestConverter) generateParameterSources(b *cnab.ExtendedBundle) cnab.ParameterSources {
 	ps := cnab.ParameterSources{}
 
@@ -614,6 +699,15 @@ func (c *ManifestConverter) generateCustomExtensions(b *cnab.ExtendedBundle) (ma
 		customExtensions[key] = value
 	}
 
+	// Add the documentation url and annotations of the bundle
+	metadata := cnab.BundleMetadata{
+		Documentation: c.Manifest.Documentation,
+		Annotations:   c.Manifest.Annotations,
+	}
+	if !metadata.IsEmpty() {
+		customExtensions[cnab.BundleMetadataKey] = metadata
+	}
+
 	// Add the dependency extension
 	deps, depsExtKey, err := c.generateDependencies()
 	if err != nil {
@@ -644,6 +738,9 @@ func (c *ManifestConverter) generateRequiredExtensions(b cnab.ExtendedBundle) []
 	if b.HasDependenciesV1() {
 		requiredExtensions = append(requiredExtensions, cnab.DependenciesV1ExtensionKey)
 	}
+	if b.HasDependenciesV2() {
+		requiredExtensions = append(requiredExtensions, cnab.DependenciesV2ExtensionKey)
+	}
 
 	// Add the appropriate parameter sources key if applicable
 	if b.HasParameterSources() {
@@ -9,7 +9,9 @@ import (
 
 	"get.porter.sh/porter/pkg/cnab"
 	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
+	depsv2 "get.porter.sh/porter/pkg/cnab/dependencies/v2"
 	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/experimental"
 	"get.porter.sh/porter/pkg/manifest"
 	"get.porter.sh/porter/pkg/mixin"
 	"get.porter.sh/porter/pkg/pkgmgmt"
@@ -87,7 +89,7 @@ func TestManifestConverter_ToBundle(t *testing.T) {
 
 	assert.True(t, bun.HasDependenciesV1(), "DependenciesV1 was not populated")
 
-	assert.Len(t, bun.Outputs, 1, "expected one output for the bundle state")
+	assert.Len(t, bun.Outputs, 1, "expected one output for the bundle state, the step metrics output is only added when metrics are enabled")
 }
 
 func TestManifestConverter_generateBundleCredentials(t *testing.T) {
@@ -457,10 +459,11 @@ func TestManifestConverter_generateBundleOutputs(t *testing.T) {
 	}
 
 	a.Manifest.Outputs = outputDefinitions
+	a.Manifest.Metrics = true
 
 	defs := make(definition.Definitions, len(a.Manifest.Outputs))
 	outputs := a.generateBundleOutputs(ctx, &defs)
-	require.Len(t, defs, 6)
+	require.Len(t, defs, 7)
 
 	wantOutputDefinitions := map[string]bundle.Output{
 		"output1": {
@@ -495,6 +498,11 @@ func TestManifestConverter_generateBundleOutputs(t *testing.T) {
 			Definition:  "porter-state",
 			Path:        "/cnab/app/outputs/porter-state",
 		},
+		"porter-metrics": {
+			Description: "Records how long each step of the action took to execute. Porter internal output that should not be used by bundles.",
+			Definition:  "porter-metrics-output",
+			Path:        "/cnab/app/outputs/porter-metrics",
+		},
 	}
 
 	require.Equal(t, wantOutputDefinitions, outputs)
@@ -528,6 +536,13 @@ func TestManifestConverter_generateBundleOutputs(t *testing.T) {
 			Type:            "string",
 			ContentEncoding: "base64",
 		},
+		"porter-metrics-output": &definition.Schema{
+			ID:          "https://getporter.org/generated-bundle/#porter-metrics",
+			Comment:     "porter-internal",
+			Description: "Records how long each step of the action took to execute. Porter internal output that should not be used by bundles.",
+			Type:        "string",
+			Default:     "[]",
+		},
 	}
 
 	require.Equal(t, wantDefinitions, defs)
@@ -600,6 +615,59 @@ func TestManifestConverter_generateDependencies(t *testing.T) {
 	}
 }
 
+func TestManifestConverter_generateDependenciesV2(t *testing.T) {
+	t.Parallel()
+
+	c := config.NewTestConfig(t)
+	c.SetExperimentalFlags(experimental.FlagDependenciesV2)
+	c.TestContext.AddTestFile("testdata/porter-with-deps-v2.yaml", config.Name)
+
+	ctx := context.Background()
+	m, err := manifest.LoadManifestFrom(ctx, c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	a := NewManifestConverter(c.Config, m, nil, nil)
+
+	depsExt, depsExtKey, err := a.generateDependencies()
+	require.NoError(t, err)
+	require.Equal(t, cnab.DependenciesV2ExtensionKey, depsExtKey, "expected the v2 dependencies extension key")
+	require.IsType(t, &depsv2.Dependencies{}, depsExt, "expected a v2 dependencies extension section")
+	deps := depsExt.(*depsv2.Dependencies)
+	require.Equal(t, []string{"mysql", "wordpress"}, deps.Sequence, "incorrect sequence was generated")
+
+	assert.Equal(t, depsv2.Dependency{
+		Name:    "mysql",
+		Bundle:  "getporter/mysql",
+		Version: "^0.1.0",
+		Sharing: depsv2.SharingCriteria{
+			Mode:  depsv2.SharingModeGroup,
+			Group: depsv2.SharingGroup{Name: "myapp"},
+		},
+		Parameters: map[string]string{"database-name": "${ bundle.parameters.database }"},
+	}, deps.Requires["mysql"])
+	assert.Equal(t, depsv2.Dependency{
+		Name:       "wordpress",
+		Bundle:     "getporter/wordpress:v0.1.0",
+		Parameters: map[string]string{"db-host": "${ bundle.dependencies.mysql.outputs.host }"},
+	}, deps.Requires["wordpress"])
+}
+
+func TestManifestConverter_generateDependencies_SharingRequiresV2(t *testing.T) {
+	t.Parallel()
+
+	c := config.NewTestConfig(t)
+	c.TestContext.AddTestFile("testdata/porter-with-deps-v2.yaml", config.Name)
+
+	ctx := context.Background()
+	m, err := manifest.LoadManifestFrom(ctx, c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	a := NewManifestConverter(c.Config, m, nil, nil)
+
+	_, _, err = a.generateDependencies()
+	require.EqualError(t, err, "dependency mysql specifies sharing, which requires the dependencies-v2 experimental feature")
+}
+
 func TestManifestConverter_generateRequiredExtensions_Dependencies(t *testing.T) {
 	t.Parallel()
 
@@ -809,6 +877,38 @@ func TestManifestConverter_GenerateCustomActionDefinitions(t *testing.T) {
 	assert.True(t, zombieDef.Modifies, "expected the zombies custom action to default to modifying resources")
 }
 
+func TestManifestConverter_generateActionArgumentParameters(t *testing.T) {
+	t.Parallel()
+
+	c := config.NewTestConfig(t)
+	c.TestContext.AddTestFile("testdata/porter-with-action-arguments.yaml", config.Name)
+
+	ctx := context.Background()
+	m, err := manifest.LoadManifestFrom(ctx, c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	a := NewManifestConverter(c.Config, m, nil, nil)
+
+	bun, err := a.ToBundle(ctx)
+	require.NoError(t, err, "ToBundle failed")
+
+	require.Contains(t, bun.Parameters, "porter-backup-target-arg")
+	target := bun.Parameters["porter-backup-target-arg"]
+	assert.Equal(t, []string{"backup"}, target.ApplyTo)
+	assert.True(t, target.Required, "an argument declared as required should generate a required parameter")
+	assert.True(t, bun.IsInternalParameter("porter-backup-target-arg"), "the arguments of a custom action should be internal parameters")
+
+	require.Contains(t, bun.Parameters, "porter-backup-retention-arg")
+	retention := bun.Parameters["porter-backup-retention-arg"]
+	assert.False(t, retention.Required, "an optional argument should not generate a required parameter")
+
+	assert.Equal(t, []cnab.ActionArgument{
+		{Name: "retention", Description: "How long to keep the backup", Default: "7d", Parameter: "porter-backup-retention-arg"},
+		{Name: "target", Description: "Where to store the backup", Required: true, Parameter: "porter-backup-target-arg"},
+	}, bun.GetActionArguments("backup"))
+	assert.Empty(t, bun.GetActionArguments("install"))
+}
+
 func TestManifestConverter_generateDefaultAction(t *testing.T) {
 	t.Parallel()
 
@@ -916,6 +1016,32 @@ func TestManifestConverter_generatedMaintainers(t *testing.T) {
 	}
 }
 
+func TestManifestConverter_generateBundleMetadata(t *testing.T) {
+	t.Parallel()
+
+	c := config.NewTestConfig(t)
+	c.TestContext.AddTestFile("./testdata/porter-with-metadata.yaml", config.Name)
+
+	ctx := context.Background()
+	m, err := manifest.LoadManifestFrom(ctx, c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	a := NewManifestConverter(c.Config, m, nil, nil)
+
+	bun, err := a.ToBundle(ctx)
+	require.NoError(t, err, "ToBundle failed")
+	assert.Equal(t, "Apache-2.0", bun.License)
+
+	metadata, err := bun.GetBundleMetadata()
+	require.NoError(t, err, "GetBundleMetadata failed")
+	wantMetadata := cnab.BundleMetadata{
+		Documentation: "https://example.com/porter-hello",
+		Annotations:   map[string]string{"team": "platform", "tier": "1"},
+	}
+	assert.Equal(t, wantMetadata, metadata)
+	assert.NotContains(t, bun.RequiredExtensions, cnab.BundleMetadataKey, "the bundle metadata should not be a required extension")
+}
+
 func getMaintainerByName(source []bundle.Maintainer, name string) (bundle.Maintainer, error) {
 	for _, m := range source {
 		if m.Name == name {
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 
 	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/cnab"
@@ -36,6 +37,9 @@ type Stamp struct {
 	// Version and commit define the version of the Porter used when a bundle was built.
 	Version string `json:"version"`
 	Commit  string `json:"commit"`
+
+	// MinimumPorterVersion is the oldest version of Porter that can run the bundle.
+	MinimumPorterVersion string `json:"minimumPorterVersion,omitempty"`
 }
 
 // DecodeManifest base64 decodes the manifest stored in the stamp
@@ -71,6 +75,9 @@ func (s Stamp) WriteManifest(cxt *portercontext.Context, path string) error {
 type MixinRecord struct {
 	// Version of the mixin used in the bundle.
 	Version string `json:"version"`
+
+	// Prerequisites are the commands that the mixin requires on the host.
+	Prerequisites []string `json:"prerequisites,omitempty"`
 }
 
 func (c *ManifestConverter) GenerateStamp(ctx context.Context) (Stamp, error) {
@@ -79,18 +86,15 @@ func (c *ManifestConverter) GenerateStamp(ctx context.Context) (Stamp, error) {
 	stamp := Stamp{}
 
 	// Remember the original porter.yaml, base64 encoded to avoid canonical json shenanigans
-	rawManifest, err := manifest.ReadManifestData(c.config.Context, c.Manifest.ManifestPath)
+	rawManifest, err := manifest.ReadManifestData(c.config, c.Manifest.ManifestPath)
 	if err != nil {
 		return Stamp{}, err
 	}
 	stamp.EncodedManifest = base64.StdEncoding.EncodeToString(rawManifest)
 
 	stamp.Mixins = make(map[string]MixinRecord, len(c.Manifest.Mixins))
-	usedMixinsVersion := c.getUsedMixinsVersion()
-	for usedMixinName, usedMixinVersion := range usedMixinsVersion {
-		stamp.Mixins[usedMixinName] = MixinRecord{
-			Version: usedMixinVersion,
-		}
+	for usedMixinName, usedMixin := range c.getUsedMixins() {
+		stamp.Mixins[usedMixinName] = usedMixin
 	}
 
 	digest, err := c.DigestManifest()
@@ -105,6 +109,7 @@ func (c *ManifestConverter) GenerateStamp(ctx context.Context) (Stamp, error) {
 
 	stamp.Version = pkg.Version
 	stamp.Commit = pkg.Commit
+	stamp.MinimumPorterVersion = c.Manifest.MinimumPorterVersion
 
 	return stamp, nil
 }
@@ -122,9 +127,15 @@ func (c *ManifestConverter) DigestManifest() (string, error) {
 	v := pkg.Version
 	data = append(data, v...)
 
-	usedMixinsVersion := c.getUsedMixinsVersion()
-	for usedMixinName, usedMixinVersion := range usedMixinsVersion {
-		data = append(append(data, usedMixinName...), usedMixinVersion...)
+	// Sort the mixins so that the digest doesn't depend on map iteration order
+	usedMixins := c.getUsedMixins()
+	usedMixinNames := make([]string, 0, len(usedMixins))
+	for usedMixinName := range usedMixins {
+		usedMixinNames = append(usedMixinNames, usedMixinName)
+	}
+	sort.Strings(usedMixinNames)
+	for _, usedMixinName := range usedMixinNames {
+		data = append(append(data, usedMixinName...), usedMixins[usedMixinName].Version...)
 	}
 
 	digest := sha256.Sum256(data)
@@ -152,17 +163,21 @@ func LoadStamp(bun cnab.ExtendedBundle) (Stamp, error) {
 	return stamp, nil
 }
 
-// getUsedMixinsVersion compare the mixins defined in the manifest and the ones installed and then retrieve the mixin's version info
-func (c *ManifestConverter) getUsedMixinsVersion() map[string]string {
-	usedMixinsVersion := make(map[string]string)
+// getUsedMixins compare the mixins defined in the manifest and the ones installed and then retrieve the mixin's version info
+func (c *ManifestConverter) getUsedMixins() map[string]MixinRecord {
+	usedMixins := make(map[string]MixinRecord)
 
 	for _, usedMixin := range c.Manifest.Mixins {
 		for _, installedMixin := range c.InstalledMixins {
 			if usedMixin.Name == installedMixin.Name {
-				usedMixinsVersion[usedMixin.Name] = installedMixin.GetVersionInfo().Version
+				versionInfo := installedMixin.GetVersionInfo()
+				usedMixins[usedMixin.Name] = MixinRecord{
+					Version:       versionInfo.Version,
+					Prerequisites: versionInfo.Prerequisites,
+				}
 			}
 		}
 	}
 
-	return usedMixinsVersion
+	return usedMixins
 }
@@ -27,16 +27,18 @@ func TestConfig_GenerateStamp(t *testing.T) {
 	ctx := context.Background()
 	m, err := manifest.LoadManifestFrom(ctx, c.Config, config.Name)
 	require.NoError(t, err, "could not load manifest")
+	m.MinimumPorterVersion = "v1.0.0"
 
 	installedMixins := []mixin.Metadata{
-		{Name: "exec", VersionInfo: pkgmgmt.VersionInfo{Version: "v1.2.3"}},
+		{Name: "exec", VersionInfo: pkgmgmt.VersionInfo{Version: "v1.2.3", Prerequisites: []string{"bash"}}},
 	}
 
 	a := NewManifestConverter(c.Config, m, nil, installedMixins)
 	stamp, err := a.GenerateStamp(ctx)
 	require.NoError(t, err, "DigestManifest failed")
 	assert.Equal(t, simpleManifestDigest, stamp.ManifestDigest)
-	assert.Equal(t, map[string]MixinRecord{"exec": {Version: "v1.2.3"}}, stamp.Mixins, "Stamp.Mixins was not populated properly")
+	assert.Equal(t, map[string]MixinRecord{"exec": {Version: "v1.2.3", Prerequisites: []string{"bash"}}}, stamp.Mixins, "Stamp.Mixins was not populated properly")
+	assert.Equal(t, "v1.0.0", stamp.MinimumPorterVersion)
 	assert.Equal(t, pkg.Version, stamp.Version)
 	assert.Equal(t, pkg.Commit, stamp.Commit)
 
@@ -155,6 +157,31 @@ func TestConfig_DigestManifest(t *testing.T) {
 		require.NoError(t, err, "DigestManifest failed")
 		assert.NotEqual(t, newDigest, digest, "expected the digest to be different due to the updated pkg version")
 	})
+
+	t.Run("multiple mixins", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		c.TestContext.AddTestFileFromRoot("pkg/manifest/testdata/simple.porter.yaml", config.Name)
+
+		m, err := manifest.LoadManifestFrom(context.Background(), c.Config, config.Name)
+		require.NoError(t, err, "could not load manifest")
+		m.Mixins = []manifest.MixinDeclaration{{Name: "exec"}, {Name: "helm3"}, {Name: "az"}, {Name: "terraform"}}
+
+		installedMixins := []mixin.Metadata{
+			{Name: "exec", VersionInfo: pkgmgmt.VersionInfo{Version: "v1.2.3"}},
+			{Name: "helm3", VersionInfo: pkgmgmt.VersionInfo{Version: "v1.0.0"}},
+			{Name: "az", VersionInfo: pkgmgmt.VersionInfo{Version: "v0.1.0"}},
+			{Name: "terraform", VersionInfo: pkgmgmt.VersionInfo{Version: "v2.0.0"}},
+		}
+		a := NewManifestConverter(c.Config, m, nil, installedMixins)
+		digest, err := a.DigestManifest()
+		require.NoError(t, err, "DigestManifest failed")
+
+		for i := 0; i < 10; i++ {
+			newDigest, err := a.DigestManifest()
+			require.NoError(t, err, "DigestManifest failed")
+			require.Equal(t, digest, newDigest, "expected the digest to be the same regardless of the order of the mixins")
+		}
+	})
 }
 
 func TestConfig_GenerateStamp_IncludeVersion(t *testing.T) {
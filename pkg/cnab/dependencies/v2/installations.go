@@ -0,0 +1,16 @@
+package v2
+
+import (
+	"fmt"
+)
+
+// LabelSharingGroup is set on the installation of a shared dependency to the name
+// of its sharing group. An installation with this label, of the same bundle, satisfies
+// the dependency for every installation that declares the dependency with the group.
+const LabelSharingGroup = "sh.porter.sharingGroup"
+
+// BuildSharedInstallationName generates the name of the installation of a shared dependency,
+// when no installation in the sharing group exists yet.
+func BuildSharedInstallationName(group string, dependency string) string {
+	return fmt.Sprintf("%s-%s", group, dependency)
+}
@@ -0,0 +1,78 @@
+package v2
+
+// Dependencies describes the set of custom extension metadata associated with the
+// dependencies v2 extension, which is defined by Porter.
+type Dependencies struct {
+	// Sequence is a list to order the dependencies
+	Sequence []string `json:"sequence,omitempty" mapstructure:"sequence"`
+
+	// Requires is a list of bundles required by this bundle
+	Requires map[string]Dependency `json:"requires,omitempty" mapstructure:"requires"`
+}
+
+// ListBySequence returns the dependencies by the defined sequence,
+// if none is specified, they are unsorted.
+func (d Dependencies) ListBySequence() []Dependency {
+	deps := make([]Dependency, 0, len(d.Requires))
+	if len(d.Sequence) > 0 && len(d.Sequence) == len(d.Requires) {
+		for _, depName := range d.Sequence {
+			dep := d.Requires[depName]
+			dep.Name = depName
+			deps = append(deps, dep)
+		}
+	} else {
+		for depName, dep := range d.Requires {
+			dep.Name = depName
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Dependency describes a dependency on another bundle
+type Dependency struct {
+	// Name of the dependency
+	Name string `json:"name" mapstructure:"name"`
+
+	// Bundle is the location of the bundle in a registry, for example REGISTRY/NAME:TAG
+	Bundle string `json:"bundle" mapstructure:"bundle"`
+
+	// Version is a range of semantic versions, e.g. ^1.2.0, that the tag of the bundle must satisfy
+	Version string `json:"version,omitempty" mapstructure:"version"`
+
+	// Sharing specifies if the dependency may be satisfied by an existing installation
+	Sharing SharingCriteria `json:"sharing,omitempty" mapstructure:"sharing"`
+
+	// Parameters to pass to the dependency, keyed by the name of the parameter in the dependency.
+	// The values may reference the parameters of the bundle, ${bundle.parameters.NAME},
+	// or the outputs of another dependency, ${bundle.dependencies.DEPENDENCY.outputs.NAME}.
+	Parameters map[string]string `json:"parameters,omitempty" mapstructure:"parameters"`
+}
+
+const (
+	// SharingModeNone means that the dependency is always installed for the bundle.
+	SharingModeNone = "none"
+
+	// SharingModeGroup means that the dependency is shared by the installations in the sharing group.
+	SharingModeGroup = "group"
+)
+
+// SharingCriteria specifies how a dependency is shared with other installations.
+type SharingCriteria struct {
+	// Mode is either none or group. Defaults to none.
+	Mode string `json:"mode,omitempty" mapstructure:"mode"`
+
+	// Group of installations that share the dependency.
+	Group SharingGroup `json:"group,omitempty" mapstructure:"group"`
+}
+
+// SharingGroup is a set of installations that share a dependency.
+type SharingGroup struct {
+	// Name of the sharing group.
+	Name string `json:"name" mapstructure:"name"`
+}
+
+// IsShared determines if the dependency may be satisfied by an existing installation.
+func (s SharingCriteria) IsShared() bool {
+	return s.Mode == SharingModeGroup
+}
@@ -21,9 +21,10 @@ const (
 
 // DependenciesV1Extension represents the required extension to enable dependencies
 var DependenciesV1Extension = RequiredExtension{
-	Shorthand: DependenciesV1ExtensionShortHand,
-	Key:       DependenciesV1ExtensionKey,
-	Schema:    DependenciesV1Schema,
+	Shorthand:   DependenciesV1ExtensionShortHand,
+	Key:         DependenciesV1ExtensionKey,
+	Schema:      DependenciesV1Schema,
+	Description: "Installs the bundles that this bundle depends upon",
 	Reader: func(b ExtendedBundle) (interface{}, error) {
 		return b.DependencyV1Reader()
 	},
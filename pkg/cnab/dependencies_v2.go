@@ -0,0 +1,102 @@
+package cnab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	depsv2 "get.porter.sh/porter/pkg/cnab/dependencies/v2"
+)
+
+const (
+	// DependenciesV2ExtensionShortHand is the short suffix of the DependenciesV2ExtensionKey
+	DependenciesV2ExtensionShortHand = "dependencies@v2"
+
+	// DependenciesV2ExtensionKey represents the full key for the DependenciesV2Extension.
+	DependenciesV2ExtensionKey = PorterExtensionsPrefix + DependenciesV2ExtensionShortHand
+
+	// DependenciesV2Schema represents the schema for the DependenciesV2 Extension
+	DependenciesV2Schema = "https://porter.sh/extensions/dependencies/v2/schema.json"
+)
+
+// DependenciesV2Extension represents the required extension to enable dependencies
+// with version ranges, sharing and parameter wiring.
+var DependenciesV2Extension = RequiredExtension{
+	Shorthand:   DependenciesV2ExtensionShortHand,
+	Key:         DependenciesV2ExtensionKey,
+	Schema:      DependenciesV2Schema,
+	Description: "Installs or reuses the bundles that this bundle depends upon",
+	Reader: func(b ExtendedBundle) (interface{}, error) {
+		return b.DependencyV2Reader()
+	},
+}
+
+// ReadDependenciesV2 is a convenience method for returning a bonafide
+// Dependencies reference after reading from the applicable section from
+// the provided bundle
+func (b ExtendedBundle) ReadDependenciesV2() (depsv2.Dependencies, error) {
+	raw, err := b.DependencyV2Reader()
+	if err != nil {
+		return depsv2.Dependencies{}, err
+	}
+
+	deps, ok := raw.(depsv2.Dependencies)
+	if !ok {
+		return depsv2.Dependencies{}, errors.New("unable to read dependencies v2 extension data")
+	}
+
+	return deps, nil
+}
+
+// DependencyV2Reader is a Reader for the DependenciesV2Extension, which reads
+// from the applicable section in the provided bundle and returns the raw
+// data in the form of an interface
+func (b ExtendedBundle) DependencyV2Reader() (interface{}, error) {
+	data, ok := b.Custom[DependenciesV2ExtensionKey]
+	if !ok {
+		return nil, fmt.Errorf("attempted to read dependencies v2 from bundle but none are defined")
+	}
+
+	dataB, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal the untyped dependencies v2 extension data %q: %w", string(dataB), err)
+	}
+
+	deps := depsv2.Dependencies{}
+	err = json.Unmarshal(dataB, &deps)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal the dependencies v2 extension %q: %w", string(dataB), err)
+	}
+
+	return deps, nil
+}
+
+// SupportsDependenciesV2 checks if the bundle supports dependencies v2
+func (b ExtendedBundle) SupportsDependenciesV2() bool {
+	return b.SupportsExtension(DependenciesV2ExtensionKey)
+}
+
+// HasDependenciesV2 returns whether the bundle has dependencies v2 defined.
+func (b ExtendedBundle) HasDependenciesV2() bool {
+	_, ok := b.Custom[DependenciesV2ExtensionKey]
+	return ok
+}
+
+// GetDependencyV2 returns the dependencies v2 definition of a dependency, and
+// false when the bundle does not use dependencies v2 or the dependency is not defined.
+func (b ExtendedBundle) GetDependencyV2(alias string) (depsv2.Dependency, bool, error) {
+	if !b.HasDependenciesV2() {
+		return depsv2.Dependency{}, false, nil
+	}
+
+	deps, err := b.ReadDependenciesV2()
+	if err != nil {
+		return depsv2.Dependency{}, false, err
+	}
+
+	dep, ok := deps.Requires[alias]
+	if ok {
+		dep.Name = alias
+	}
+	return dep, ok, nil
+}
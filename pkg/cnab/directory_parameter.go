@@ -0,0 +1,154 @@
+package cnab
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// DirectoryParameterHeader identifies the decoded value of a file parameter
+// that contains a directory. The directory is stored as a gzipped tarball
+// following the header, and is extracted to the parameter's destination path in
+// the bundle before the bundle is executed.
+const DirectoryParameterHeader = "porter-directory+tgz\n"
+
+// IsDirectoryParameter determines if the decoded value of a file parameter
+// contains a directory created with EncodeDirectoryParameter.
+func IsDirectoryParameter(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(DirectoryParameterHeader))
+}
+
+// EncodeDirectoryParameter archives the contents of a directory so that it can be
+// passed to a bundle as the base64 encoded value of a file parameter.
+func EncodeDirectoryParameter(fs afero.Fs, dir string) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(DirectoryParameterHeader)
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+
+	err := afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		if !fi.Mode().IsRegular() && !fi.IsDir() {
+			// Symlinks and other special files are not supported because they may point outside the directory
+			return fmt.Errorf("unsupported file %s, only regular files and directories may be included in a directory parameter", path)
+		}
+
+		header, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return fmt.Errorf("error creating tar header for %s: %w", path, err)
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if fi.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("error writing tar header for %s: %w", path, err)
+		}
+
+		if fi.IsDir() {
+			return nil
+		}
+
+		f, err := fs.Open(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", path, err)
+		}
+		defer f.Close()
+
+		if _, err = io.Copy(tw, f); err != nil {
+			return fmt.Errorf("error archiving %s: %w", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to archive directory %s: %w", dir, err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("unable to archive directory %s: %w", dir, err)
+	}
+	if err := gzw.Close(); err != nil {
+		return "", fmt.Errorf("unable to compress directory %s: %w", dir, err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ExtractDirectoryParameter extracts the decoded value of a directory parameter,
+// created with EncodeDirectoryParameter, into the destination directory.
+func ExtractDirectoryParameter(fs afero.Fs, data []byte, dest string) error {
+	if !IsDirectoryParameter(data) {
+		return errors.New("the value is not a directory parameter")
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(data[len(DirectoryParameterHeader):]))
+	if err != nil {
+		return fmt.Errorf("unable to decompress directory: %w", err)
+	}
+	defer gzr.Close()
+
+	if err := fs.MkdirAll(dest, 0700); err != nil {
+		return fmt.Errorf("unable to create directory %s: %w", dest, err)
+	}
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read directory archive: %w", err)
+		}
+
+		// Do not allow entries to escape the destination directory
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %s in directory archive", header.Name)
+		}
+		target := filepath.Join(dest, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := fs.MkdirAll(target, os.FileMode(header.Mode)|0700); err != nil {
+				return fmt.Errorf("unable to create directory %s: %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := fs.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return fmt.Errorf("unable to create directory %s: %w", filepath.Dir(target), err)
+			}
+			f, err := fs.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode))
+			if err != nil {
+				return fmt.Errorf("unable to create file %s: %w", target, err)
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("unable to extract file %s: %w", target, err)
+			}
+		default:
+			return fmt.Errorf("unsupported entry %s in directory archive", header.Name)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cnab
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryParameter(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/config/app.yaml", []byte("name: app"), 0600))
+		require.NoError(t, afero.WriteFile(fs, "/config/certs/ca.pem", []byte("my-ca"), 0600))
+		require.NoError(t, fs.MkdirAll("/config/empty", 0700))
+
+		value, err := EncodeDirectoryParameter(fs, "/config")
+		require.NoError(t, err)
+
+		data, err := base64.StdEncoding.DecodeString(value)
+		require.NoError(t, err, "the encoded directory should be valid for a file parameter")
+		require.True(t, IsDirectoryParameter(data))
+
+		require.NoError(t, ExtractDirectoryParameter(fs, data, "/cnab/app/config"))
+
+		contents, err := afero.ReadFile(fs, "/cnab/app/config/app.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "name: app", string(contents))
+
+		contents, err = afero.ReadFile(fs, "/cnab/app/config/certs/ca.pem")
+		require.NoError(t, err)
+		assert.Equal(t, "my-ca", string(contents))
+
+		isDir, err := afero.IsDir(fs, "/cnab/app/config/empty")
+		require.NoError(t, err)
+		assert.True(t, isDir, "empty directories should be preserved")
+	})
+
+	t.Run("regular file", func(t *testing.T) {
+		assert.False(t, IsDirectoryParameter([]byte("porter-directory")))
+
+		err := ExtractDirectoryParameter(afero.NewMemMapFs(), []byte("some file contents"), "/cnab/app/config")
+		require.EqualError(t, err, "the value is not a directory parameter")
+	})
+}
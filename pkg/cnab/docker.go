@@ -19,10 +19,11 @@ const (
 
 // DockerExtension represents a required extension enabling access to the host Docker daemon
 var DockerExtension = RequiredExtension{
-	Shorthand: DockerExtensionShortHand,
-	Key:       DockerExtensionKey,
-	Schema:    "schema/io-cnab-docker.schema.json",
-	Reader:    DockerExtensionReader,
+	Shorthand:   DockerExtensionShortHand,
+	Key:         DockerExtensionKey,
+	Schema:      "schema/io-cnab-docker.schema.json",
+	Description: "Mounts the Docker socket from the host into the bundle",
+	Reader:      DockerExtensionReader,
 }
 
 // Docker describes the set of custom extension metadata associated with the Docker extension
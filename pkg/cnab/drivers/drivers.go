@@ -8,10 +8,10 @@ import (
 	"github.com/cnabio/cnab-go/driver/command"
 	"github.com/cnabio/cnab-go/driver/debug"
 	"github.com/cnabio/cnab-go/driver/docker"
-	"github.com/cnabio/cnab-go/driver/kubernetes"
 )
 
-// LookupDriver creates a driver by name.
+// LookupDriver creates a driver by name. The kubernetes driver is implemented
+// by Porter instead of cnab-go, use cnabprovider.LookupDriver to include it.
 //
 // This replaces cnab-go's lookup function because cnab-go uses global process
 // values, such as $PATH, instead of our context.
@@ -19,8 +19,6 @@ func LookupDriver(cxt *portercontext.Context, name string) (driver.Driver, error
 	switch name {
 	case "docker":
 		return &docker.Driver{}, nil
-	case "kubernetes", "k8s":
-		return &kubernetes.Driver{}, nil
 	case "debug":
 		return &debug.Driver{}, nil
 	default:
@@ -11,9 +11,10 @@ const (
 // FileParameterExtension represents a required extension that indicates that the bundle
 // requires support for parameters of type "file"
 var FileParameterExtension = RequiredExtension{
-	Shorthand: FileParameterExtensionShortHand,
-	Key:       FileParameterExtensionKey,
-	Reader:    FileParameterReader,
+	Shorthand:   FileParameterExtensionShortHand,
+	Key:         FileParameterExtensionKey,
+	Description: "Passes files from the host to the bundle as parameters",
+	Reader:      FileParameterReader,
 }
 
 // FileParameterReader is a Reader for the FileParameterExtension.
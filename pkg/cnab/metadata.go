@@ -0,0 +1,51 @@
+package cnab
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BundleMetadataKey is the key of the custom section of bundle.json that
+// holds the descriptive metadata of a bundle defined in porter.yaml, such as
+// its documentation url and annotations.
+const BundleMetadataKey = PorterExtensionsPrefix + "metadata"
+
+// BundleMetadata is descriptive metadata about a bundle that is not defined
+// by the CNAB spec. The maintainers and license of a bundle are stored in the
+// standard bundle.json fields instead.
+type BundleMetadata struct {
+	// Documentation is the url of the documentation for the bundle.
+	Documentation string `json:"documentation,omitempty"`
+
+	// Annotations are arbitrary key/value pairs describing the bundle.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// IsEmpty determines if any metadata is defined.
+func (m BundleMetadata) IsEmpty() bool {
+	return m.Documentation == "" && len(m.Annotations) == 0
+}
+
+// GetBundleMetadata reads the descriptive metadata from the custom section of
+// the bundle. An empty BundleMetadata is returned when the bundle does not
+// define any metadata.
+func (b ExtendedBundle) GetBundleMetadata() (BundleMetadata, error) {
+	data, ok := b.Custom[BundleMetadataKey]
+	if !ok {
+		return BundleMetadata{}, nil
+	}
+
+	dataB, err := json.Marshal(data)
+	if err != nil {
+		return BundleMetadata{}, fmt.Errorf("could not marshal the untyped %q extension data %q: %w",
+			BundleMetadataKey, string(dataB), err)
+	}
+
+	var metadata BundleMetadata
+	if err = json.Unmarshal(dataB, &metadata); err != nil {
+		return BundleMetadata{}, fmt.Errorf("could not unmarshal the %q extension %q: %w",
+			BundleMetadataKey, string(dataB), err)
+	}
+
+	return metadata, nil
+}
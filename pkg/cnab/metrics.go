@@ -0,0 +1,38 @@
+package cnab
+
+import "time"
+
+// OutputPorterMetrics is the name of the internal output where the Porter
+// runtime records how long each step of the action took to execute.
+const OutputPorterMetrics = "porter-metrics"
+
+// StepStatusTimedOut is the status of a step that was stopped because it, or
+// the action, did not complete in time.
+const StepStatusTimedOut = "timedout"
+
+// StepStatusSkipped is the status of a step that was not executed because its
+// when condition was false.
+const StepStatusSkipped = "skipped"
+
+// StepMetric records the execution of a single step of a bundle action.
+type StepMetric struct {
+	// Description of the step.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Mixin that executed the step.
+	Mixin string `json:"mixin" yaml:"mixin"`
+
+	// Started timestamp of the step.
+	Started time.Time `json:"started" yaml:"started"`
+
+	// Stopped timestamp of the step.
+	Stopped time.Time `json:"stopped" yaml:"stopped"`
+
+	// Status of the step, either StatusSucceeded, StatusFailed, StepStatusTimedOut or StepStatusSkipped.
+	Status string `json:"status" yaml:"status"`
+}
+
+// Duration of the step.
+func (m StepMetric) Duration() time.Duration {
+	return m.Stopped.Sub(m.Started)
+}
@@ -25,10 +25,11 @@ const (
 // ParameterSourcesExtension represents a required extension that specifies how
 // to default parameter values.
 var ParameterSourcesExtension = RequiredExtension{
-	Shorthand: ParameterSourcesExtensionShortHand,
-	Key:       ParameterSourcesExtensionKey,
-	Schema:    ParameterSourcesSchema,
-	Reader:    ParameterSourcesReader,
+	Shorthand:   ParameterSourcesExtensionShortHand,
+	Key:         ParameterSourcesExtensionKey,
+	Schema:      ParameterSourcesSchema,
+	Description: "Sets parameters from the outputs of the installation",
+	Reader:      ParameterSourcesReader,
 }
 
 // ParameterSources describes the set of custom extension metadata associated
@@ -0,0 +1,28 @@
+package cnab
+
+import (
+	"fmt"
+
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ValidateParameterValue validates the value of a parameter against its json schema,
+// including the schema of the items and properties of array and object parameters.
+// Every problem is reported, with the location of the invalid value, for example /nodes/0/size.
+func ValidateParameterValue(name string, def *definition.Schema, value interface{}) error {
+	valErrs, err := def.Validate(value)
+	if err != nil {
+		return fmt.Errorf("unable to validate the value of parameter %s: %w", name, err)
+	}
+
+	var result *multierror.Error
+	for _, valErr := range valErrs {
+		if valErr.Path == "" || valErr.Path == "/" {
+			result = multierror.Append(result, fmt.Errorf("invalid value for parameter %s: %s", name, valErr.Error))
+		} else {
+			result = multierror.Append(result, fmt.Errorf("invalid value for parameter %s at %s: %s", name, valErr.Path, valErr.Error))
+		}
+	}
+	return result.ErrorOrNil()
+}
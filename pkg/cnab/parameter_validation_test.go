@@ -0,0 +1,36 @@
+package cnab
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateParameterValue(t *testing.T) {
+	var def definition.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "size": {"type": "integer", "minimum": 1}
+    },
+    "required": ["size"]
+  }
+}`), &def))
+
+	err := ValidateParameterValue("nodes", &def, []interface{}{map[string]interface{}{"size": 3}})
+	require.NoError(t, err)
+
+	err = ValidateParameterValue("nodes", &def, []interface{}{
+		map[string]interface{}{"size": 0},
+		map[string]interface{}{},
+	})
+	require.ErrorContains(t, err, "invalid value for parameter nodes at /0/size: must be greater than or equal to 1")
+	require.ErrorContains(t, err, `invalid value for parameter nodes at /1: "size" value is required`)
+
+	err = ValidateParameterValue("nodes", &def, "oops")
+	require.ErrorContains(t, err, "invalid value for parameter nodes: type should be array, got string")
+}
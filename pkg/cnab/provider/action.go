@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"sort"
+	"strconv"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
 	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/metrics"
 	"get.porter.sh/porter/pkg/storage"
 	"get.porter.sh/porter/pkg/tracing"
 	cnabaction "github.com/cnabio/cnab-go/action"
@@ -42,23 +47,63 @@ type ActionArguments struct {
 	// Give the bundle privileged access to the docker daemon.
 	AllowDockerHostAccess bool
 
+	// DriverOptions are the --driver-opt settings for the docker driver, in the
+	// form KEY=VALUE. They are applied after the docker settings from the config file.
+	DriverOptions []string
+
 	// PersistLogs specifies if the invocation image output should be saved as an output.
 	PersistLogs bool
+
+	// LogFormat is the format of the output generated by the bundle: text or json.
+	LogFormat string
+
+	// Retries is the number of times a failed step is retried, overriding the
+	// retry policy of the bundle when set.
+	Retries int
+
+	// RetryBackoff is how long to wait before retrying a failed step, overriding
+	// the retry policy of the bundle when set.
+	RetryBackoff time.Duration
+
+	// Timeout is how long the action may run, overriding the timeout policy of
+	// the bundle when set.
+	Timeout time.Duration
+
+	// Out is where the output of the bundle is written. Defaults to the output of the runtime.
+	Out io.Writer
+
+	// Err is where the error output of the bundle is written. Defaults to the error output of the runtime.
+	Err io.Writer
+
+	// Run is an existing run that the execution is recorded on, instead of
+	// creating a new run, so that repeated executions of an action, such as
+	// the polls of a readiness check, are recorded as a single run. The run is
+	// created by the first execution, when its ID is not set, and each
+	// execution adds its result to the run.
+	Run *storage.Run
 }
 
 func (r *Runtime) ApplyConfig(ctx context.Context, args ActionArguments) cnabaction.OperationConfigs {
 	return cnabaction.OperationConfigs{
-		r.SetOutput(),
+		r.SetOutput(args),
 		r.AddFiles(ctx, args),
 		r.AddEnvironment(args),
 		r.AddRelocation(args),
+		r.AddRegistryMirror(),
+		r.AddTraceContext(ctx),
 	}
 }
 
-func (r *Runtime) SetOutput() cnabaction.OperationConfigFunc {
+func (r *Runtime) SetOutput(args ActionArguments) cnabaction.OperationConfigFunc {
 	return func(op *driver.Operation) error {
 		op.Out = r.Out
+		if args.Out != nil {
+			op.Out = args.Out
+		}
 		op.Err = r.Err
+		if args.Err != nil {
+			op.Err = args.Err
+		}
 		return nil
 	}
 }
@@ -93,6 +138,23 @@ func (r *Runtime) AddEnvironment(args ActionArguments) cnabaction.OperationConfi
 		// Pass the verbosity from porter's local config into the bundle
 		op.Environment[verbosityEnv] = r.Config.GetVerbosity().Level().String()
 
+		if args.LogFormat != "" {
+			op.Environment[config.EnvPorterLogFormat] = args.LogFormat
+		}
+
+		// Override the retry policy defined in the bundle
+		if args.Retries > 0 {
+			op.Environment[config.EnvPorterRetries] = strconv.Itoa(args.Retries)
+		}
+		if args.RetryBackoff > 0 {
+			op.Environment[config.EnvPorterRetryBackoff] = args.RetryBackoff.String()
+		}
+
+		// Override the timeout policy defined in the bundle
+		if args.Timeout > 0 {
+			op.Environment[config.EnvPorterTimeout] = args.Timeout.String()
+		}
+
 		// When a bundle is run in debug mode, the verbosity is automatically set to debug
 		if debugMode, _ := args.Params["porter-debug"].(bool); debugMode {
 			op.Environment[verbosityEnv] = zapcore.DebugLevel.String()
@@ -101,6 +163,41 @@ func (r *Runtime) AddEnvironment(args ActionArguments) cnabaction.OperationConfi
 	}
 }
 
+// AddTraceContext propagates the current trace into the invocation image, so
+// that Porter's runtime and the mixins continue the trace. When telemetry is
+// enabled, the settings used to connect to the collector are passed as well,
+// except for the headers and certificate, which may contain credentials or
+// refer to a file that is not available in the invocation image.
+func (r *Runtime) AddTraceContext(ctx context.Context) cnabaction.OperationConfigFunc {
+	return func(op *driver.Operation) error {
+		for key, value := range tracing.InjectEnvironment(ctx) {
+			op.Environment[key] = value
+		}
+
+		telemetry := r.Data.Telemetry
+		if !telemetry.Enabled {
+			return nil
+		}
+
+		op.Environment["PORTER_TELEMETRY_ENABLED"] = "true"
+		if telemetry.Insecure {
+			op.Environment["PORTER_TELEMETRY_INSECURE"] = "true"
+		}
+		settings := map[string]string{
+			"PORTER_TELEMETRY_ENDPOINT":    telemetry.Endpoint,
+			"PORTER_TELEMETRY_PROTOCOL":    telemetry.Protocol,
+			"PORTER_TELEMETRY_TIMEOUT":     telemetry.Timeout,
+			"PORTER_TELEMETRY_COMPRESSION": telemetry.Compression,
+		}
+		for key, value := range settings {
+			if value != "" {
+				op.Environment[key] = value
+			}
+		}
+		return nil
+	}
+}
+
 // AddRelocation operates on an ActionArguments and adds any provided relocation mapping
 // to the operation's files.
 func (r *Runtime) AddRelocation(args ActionArguments) cnabaction.OperationConfigFunc {
@@ -125,6 +222,31 @@ func (r *Runtime) AddRelocation(args ActionArguments) cnabaction.OperationConfig
 	}
 }
 
+// AddRegistryMirror operates on an ActionArguments and updates the operation to pull the
+// invocation image from the mirror configured for the image's registry.
+func (r *Runtime) AddRegistryMirror() cnabaction.OperationConfigFunc {
+	return func(op *driver.Operation) error {
+		if len(r.Data.RegistryMirrors) == 0 {
+			return nil
+		}
+
+		imgRef, err := cnab.ParseOCIReference(op.Image.Image)
+		if err != nil {
+			return fmt.Errorf("error parsing the invocation image %s as an OCI reference: %w", op.Image.Image, err)
+		}
+
+		regOpts := cnabtooci.RegistryOptions{Mirrors: r.Data.RegistryMirrors}
+		mirrorRef, ok, err := regOpts.FindMirror(imgRef)
+		if err != nil {
+			return err
+		}
+		if ok {
+			op.Image.Image = mirrorRef.Reference.String()
+		}
+		return nil
+	}
+}
+
 func (r *Runtime) Execute(ctx context.Context, args ActionArguments) error {
 	// Check if we've been asked to stop before executing long blocking calls
 	select {
@@ -147,12 +269,25 @@ func (r *Runtime) Execute(ctx context.Context, args ActionArguments) error {
 		if err != nil {
 			return log.Error(err)
 		}
-
-		currentRun, err := r.CreateRun(ctx, args, b)
+		exts, err := r.ProcessRequiredExtensions(b)
 		if err != nil {
 			return log.Error(err)
 		}
 
+		var currentRun storage.Run
+		newRun := args.Run == nil || args.Run.ID == ""
+		if newRun {
+			currentRun, err = r.CreateRun(ctx, args, b)
+			if err != nil {
+				return log.Error(err)
+			}
+			if args.Run != nil {
+				*args.Run = currentRun
+			}
+		} else {
+			currentRun = *args.Run
+		}
+
 		// Validate the action
 		if _, err := b.GetAction(currentRun.Action); err != nil {
 			return log.Error(fmt.Errorf("invalid action '%s' specified for bundle %s: %w", currentRun.Action, b.Name, err))
@@ -164,7 +299,7 @@ func (r *Runtime) Execute(ctx context.Context, args ActionArguments) error {
 		}
 
 		log.Debugf("Using runtime driver %s\n", args.Driver)
-		driver, err := r.newDriver(args.Driver, args)
+		driver, err := r.newDriver(ctx, args.Driver, args, exts)
 		if err != nil {
 			return log.Error(fmt.Errorf("unable to instantiate driver: %w", err))
 		}
@@ -172,27 +307,51 @@ func (r *Runtime) Execute(ctx context.Context, args ActionArguments) error {
 		a := cnabaction.New(driver)
 		a.SaveLogs = args.PersistLogs
 
-		if currentRun.ShouldRecord() {
+		if newRun && currentRun.ShouldRecord() {
 			err = r.SaveRun(ctx, args.Installation, currentRun, cnab.StatusRunning)
 			if err != nil {
 				return log.Error(fmt.Errorf("could not save the pending action's status, the bundle was not executed: %w", err))
 			}
 		}
 
+		// Trace running the invocation image in its own span, which the bundle continues
+		runCtx, runLog := log.StartSpanWithName("RunInvocationImage", attribute.String("driver", args.Driver))
+		opConfigs := r.ApplyConfig(runCtx, args)
+		var stream *logStream
+		if currentRun.ShouldRecord() && args.PersistLogs {
+			// Save the logs while the bundle runs, so that they can be followed from another process
+			stream = newLogStream(ctx, r.installations, currentRun)
+			opConfigs = append(opConfigs, stream.StreamLogs())
+		}
+
 		cnabClaim := currentRun.ToCNAB()
 		cnabCreds := creds.ToCNAB()
 		// The claim and credentials contain sensitive values. Only trace it in special dev builds (nothing is traced for release builds)
 		log.SetSensitiveAttributes(
 			tracing.ObjectAttribute("cnab-claim", cnabClaim),
 			tracing.ObjectAttribute("cnab-credentials", cnabCreds))
-		opResult, result, err := a.Run(cnabClaim, cnabCreds, r.ApplyConfig(ctx, args)...)
+		started := time.Now()
+		opResult, result, err := a.Run(cnabClaim, cnabCreds, opConfigs...)
+		stopped := time.Now()
+		runLog.EndSpan()
+		metrics.ObserveAction(args.Action, stopped.Sub(started), err != nil || result.Status == cnab.StatusFailed)
+
+		// Save the remaining logs before the final status of the run is recorded,
+		// so that anyone following the logs receives all of them
+		if stream != nil {
+			stream.Close()
+		}
 
 		if currentRun.ShouldRecord() {
 			if err != nil {
-				err = r.appendFailedResult(ctx, err, currentRun)
+				failedResult := currentRun.NewResult(cnab.StatusFailed)
+				recordExecutionMetrics(ctx, &failedResult, opResult, started, stopped)
+				err = r.appendFailedResult(ctx, err, failedResult)
 				return log.Error(fmt.Errorf("failed to record that %s for installation %s failed: %w", args.Action, args.Installation.Name, err))
 			}
-			return r.SaveOperationResult(ctx, opResult, args.Installation, currentRun, currentRun.NewResultFrom(result))
+			finalResult := currentRun.NewResultFrom(result)
+			recordExecutionMetrics(ctx, &finalResult, opResult, started, stopped)
+			return r.SaveOperationResult(ctx, opResult, args.Installation, currentRun, finalResult)
 		}
 
 		if err != nil {
@@ -300,11 +459,38 @@ func (r *Runtime) SaveOperationResult(ctx context.Context, opResult driver.Opera
 	return bigerr.ErrorOrNil()
 }
 
-// appendFailedResult creates a failed result from the operation error and accumulates
+// recordExecutionMetrics sets when the bundle was executed, and how long each
+// step of the action took, on the result. The step metrics are recorded on the
+// result, instead of as an output.
+func recordExecutionMetrics(ctx context.Context, result *storage.Result, opResult driver.OperationResult, started time.Time, stopped time.Time) {
+	log := tracing.LoggerFromContext(ctx)
+
+	result.Started = &started
+	result.Stopped = &stopped
+
+	metrics, ok := opResult.Outputs[cnab.OutputPorterMetrics]
+	if !ok {
+		return
+	}
+	delete(opResult.Outputs, cnab.OutputPorterMetrics)
+
+	if err := json.Unmarshal([]byte(metrics), &result.Steps); err != nil {
+		log.Warnf("Could not parse the step metrics generated by the bundle: %s", err)
+	}
+
+	for _, step := range result.Steps {
+		if step.Status == cnab.StepStatusTimedOut {
+			result.TimedOut = true
+			break
+		}
+	}
+}
+
+// appendFailedResult saves the failed result with the operation error and accumulates
 // the error(s).
-func (r *Runtime) appendFailedResult(ctx context.Context, opErr error, run storage.Run) error {
+func (r *Runtime) appendFailedResult(ctx context.Context, opErr error, result storage.Result) error {
 	saveResult := func() error {
-		result := run.NewResult(cnab.StatusFailed)
+		result.Message = opErr.Error()
 		return r.installations.InsertResult(ctx, result)
 	}
 
@@ -1,14 +1,19 @@
 package cnabprovider
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"testing"
 
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/storage"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-go/driver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestAddRelocation(t *testing.T) {
@@ -41,3 +46,108 @@ func TestAddRelocation(t *testing.T) {
 	assert.Equal(t, "my.registry/microservice@sha256:cca460afa270d4c527981ef9ca4989346c56cf9b20217dcea37df1ece8120687", op.Image.Image)
 
 }
+
+func TestAddRegistryMirror(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{name: "mirrored", image: "my.registry/microservice@sha256:cca460afa270d4c527981ef9ca4989346c56cf9b20217dcea37df1ece8120687", want: "mirror.example.com/microservice@sha256:cca460afa270d4c527981ef9ca4989346c56cf9b20217dcea37df1ece8120687"},
+		{name: "not mirrored", image: "gabrtv/microservice:v1.0.0", want: "gabrtv/microservice:v1.0.0"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := NewTestRuntime(t)
+			defer d.Close()
+			d.Data.RegistryMirrors = config.RegistryMirrors{{Registry: "my.registry", Mirror: "mirror.example.com"}}
+
+			op := &driver.Operation{Image: bundle.InvocationImage{BaseImage: bundle.BaseImage{Image: tc.image}}}
+			require.NoError(t, d.AddRegistryMirror()(op))
+			assert.Equal(t, tc.want, op.Image.Image)
+		})
+	}
+}
+
+func TestAddTraceContext(t *testing.T) {
+	t.Parallel()
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	t.Run("telemetry disabled", func(t *testing.T) {
+		d := NewTestRuntime(t)
+		defer d.Close()
+
+		op := &driver.Operation{Environment: map[string]string{}}
+		require.NoError(t, d.AddTraceContext(ctx)(op))
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", op.Environment["TRACEPARENT"])
+		assert.NotContains(t, op.Environment, "PORTER_TELEMETRY_ENABLED")
+	})
+
+	t.Run("telemetry enabled", func(t *testing.T) {
+		d := NewTestRuntime(t)
+		defer d.Close()
+		d.Data.Telemetry = config.TelemetryConfig{
+			Enabled:  true,
+			Endpoint: "collector:4317",
+			Protocol: "grpc",
+			Insecure: true,
+			Headers:  map[string]string{"api-key": "top-secret"},
+		}
+
+		op := &driver.Operation{Environment: map[string]string{}}
+		require.NoError(t, d.AddTraceContext(ctx)(op))
+		assert.Equal(t, "true", op.Environment["PORTER_TELEMETRY_ENABLED"])
+		assert.Equal(t, "true", op.Environment["PORTER_TELEMETRY_INSECURE"])
+		assert.Equal(t, "collector:4317", op.Environment["PORTER_TELEMETRY_ENDPOINT"])
+		assert.Equal(t, "grpc", op.Environment["PORTER_TELEMETRY_PROTOCOL"])
+		assert.NotContains(t, op.Environment, "PORTER_TELEMETRY_HEADERS", "credentials should not be passed to the invocation image")
+	})
+}
+
+func TestRuntime_Execute_ExistingRun(t *testing.T) {
+	ctx := context.Background()
+	r := NewTestRuntime(t)
+	defer r.Close()
+
+	bun := r.LoadTestBundle("testdata/bundle.json")
+	i := r.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "mybuns"))
+
+	run := &storage.Run{}
+	args := ActionArguments{
+		Action:          "zombies",
+		Installation:    i,
+		BundleReference: cnab.BundleReference{Definition: bun},
+		Params:          map[string]interface{}{"my-param": "hello"},
+		Run:             run,
+	}
+	require.NoError(t, r.Execute(ctx, args))
+	require.NotEmpty(t, run.ID, "the run should be created by the first execution")
+	runID := run.ID
+	require.NoError(t, r.Execute(ctx, args))
+	assert.Equal(t, runID, run.ID, "the run should be reused by the next execution")
+
+	runs, _, err := r.TestInstallations.ListRuns(ctx, "dev", "mybuns")
+	require.NoError(t, err)
+	require.Len(t, runs, 1, "both executions should be recorded on a single run")
+
+	results, err := r.TestInstallations.ListResults(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, results, 3, "expected the running result, and a result for each execution")
+	assert.Equal(t, cnab.StatusRunning, results[0].Status)
+	assert.Equal(t, cnab.StatusSucceeded, results[1].Status)
+	assert.Equal(t, cnab.StatusSucceeded, results[2].Status)
+}
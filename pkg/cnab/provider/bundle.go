@@ -27,5 +27,6 @@ func (r *Runtime) ProcessBundle(ctx context.Context, b cnab.ExtendedBundle) (cna
 		return b, fmt.Errorf("invalid bundle: %w", err)
 	}
 
-	return b, r.ProcessRequiredExtensions(b)
+	_, err = r.ProcessRequiredExtensions(b)
+	return b, err
 }
@@ -0,0 +1,231 @@
+package cnabprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/cnabio/cnab-go/driver"
+)
+
+const (
+	// SettingPullAlways is the driver setting that pulls the invocation image before every run.
+	SettingPullAlways = "PULL_ALWAYS"
+
+	// SettingCleanupContainers is the driver setting that determines if containers are removed after they complete.
+	SettingCleanupContainers = "CLEANUP_CONTAINERS"
+)
+
+// ContainerCLIDriver executes the invocation image of a bundle with a Docker
+// compatible CLI, such as podman, or nerdctl for containerd, so that bundles
+// can be run on machines that do not have a Docker daemon.
+type ContainerCLIDriver struct {
+	*portercontext.Context
+
+	// Name of the driver.
+	Name string
+
+	// Command is the CLI used to manage containers.
+	Command string
+
+	// PullAlways pulls the invocation image before it is run, even when it is
+	// already present.
+	PullAlways bool
+
+	// SkipCleanup keeps the container after the bundle completes.
+	SkipCleanup bool
+
+	// ctx of the action that runs the bundle, used to log warnings.
+	ctx context.Context
+}
+
+// NewPodmanDriver creates a driver that runs bundles with podman, including rootless podman.
+func NewPodmanDriver(cxt *portercontext.Context) *ContainerCLIDriver {
+	return &ContainerCLIDriver{Context: cxt, Name: DriverNamePodman, Command: "podman"}
+}
+
+// NewContainerdDriver creates a driver that runs bundles on containerd with nerdctl.
+func NewContainerdDriver(cxt *portercontext.Context) *ContainerCLIDriver {
+	return &ContainerCLIDriver{Context: cxt, Name: DriverNameContainerd, Command: "nerdctl"}
+}
+
+// Handles returns true for the image types that can be run by the driver.
+func (d *ContainerCLIDriver) Handles(imageType string) bool {
+	return imageType == driver.ImageTypeDocker || imageType == driver.ImageTypeOCI
+}
+
+// Config returns the settings of the driver that may be set with environment variables.
+func (d *ContainerCLIDriver) Config() map[string]string {
+	return map[string]string{
+		SettingPullAlways:        "If true, the invocation image is pulled before it is run. The supported values are true and false. Defaults to false.",
+		SettingCleanupContainers: "If true, the container is removed when the bundle completes. The supported values are true and false. Defaults to true.",
+	}
+}
+
+// SetConfig sets the configuration of the driver.
+func (d *ContainerCLIDriver) SetConfig(settings map[string]string) error {
+	if value, ok := settings[SettingPullAlways]; ok && value != "" {
+		pullAlways, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s setting %q, the supported values are true and false", SettingPullAlways, value)
+		}
+		d.PullAlways = pullAlways
+	}
+
+	if value, ok := settings[SettingCleanupContainers]; ok && value != "" {
+		cleanup, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s setting %q, the supported values are true and false", SettingCleanupContainers, value)
+		}
+		d.SkipCleanup = !cleanup
+	}
+
+	return nil
+}
+
+// Run executes the operation in a container and waits for it to complete.
+func (d *ContainerCLIDriver) Run(op *driver.Operation) (driver.OperationResult, error) {
+	ctx := context.Background()
+
+	tmpDir, err := d.FileSystem.TempDir("", "porter-"+d.Name)
+	if err != nil {
+		return driver.OperationResult{}, fmt.Errorf("error creating a temporary directory for the files of the invocation image: %w", err)
+	}
+	defer d.FileSystem.RemoveAll(tmpDir)
+
+	// The environment variables are passed to the CLI, and only their names are
+	// used in the arguments, so that sensitive values are not visible in the process list.
+	var env []string
+	for name, value := range op.Environment {
+		env = append(env, name+"="+value)
+	}
+	output, err := d.runCommand(ctx, env, d.buildCreateArgs(op)...)
+	if err != nil {
+		return driver.OperationResult{}, fmt.Errorf("could not create the container for the invocation image: %w", err)
+	}
+	containerID := strings.TrimSpace(string(output))
+	if !d.SkipCleanup {
+		defer func() {
+			if _, err := d.runCommand(context.Background(), nil, "rm", "--force", containerID); err != nil {
+				loggerFromContext(d.ctx).Warnf("could not remove container %s: %s", containerID, err)
+			}
+		}()
+	}
+
+	if len(op.Files) > 0 {
+		filesDir := filepath.Join(tmpDir, "files")
+		if err := d.writeFiles(filesDir, op.Files); err != nil {
+			return driver.OperationResult{}, err
+		}
+		// Copy the contents of the directory to the root of the container, merging with existing directories
+		if _, err := d.runCommand(ctx, nil, "cp", filesDir+"/.", containerID+":/"); err != nil {
+			return driver.OperationResult{}, fmt.Errorf("could not copy files to the container: %w", err)
+		}
+	}
+
+	cmd := d.NewCommand(ctx, d.Command, "start", "--attach", containerID)
+	cmd.Stdout = op.Out
+	cmd.Stderr = op.Err
+	runErr := cmd.Run()
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			runErr = fmt.Errorf("container exit code: %d", exitErr.ExitCode())
+		} else {
+			runErr = fmt.Errorf("could not start the container: %w", runErr)
+		}
+	}
+
+	// Collect the outputs even when the bundle failed, like the docker driver
+	result := driver.OperationResult{Outputs: map[string]string{}}
+	if len(op.Outputs) > 0 {
+		outputsDir := filepath.Join(tmpDir, "outputs")
+		if _, err := d.runCommand(ctx, nil, "cp", containerID+":"+invocationImageOutputsDir+"/.", outputsDir); err != nil {
+			if runErr != nil {
+				return result, runErr
+			}
+			return result, fmt.Errorf("could not copy the outputs from the container: %w", err)
+		}
+
+		result.Outputs, err = d.readOutputs(outputsDir, op.Outputs)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, runErr
+}
+
+// buildCreateArgs returns the arguments used to create the container for an operation.
+func (d *ContainerCLIDriver) buildCreateArgs(op *driver.Operation) []string {
+	args := []string{"create"}
+	if d.PullAlways {
+		args = append(args, "--pull", "always")
+	}
+	for _, name := range sortedKeys(op.Environment) {
+		args = append(args, "--env", name)
+	}
+	return append(args, getInvocationImageReference(op))
+}
+
+// writeFiles writes the files passed to the invocation image to dir, using
+// their path in the invocation image.
+func (d *ContainerCLIDriver) writeFiles(dir string, files map[string]string) error {
+	for path, contents := range files {
+		dest := filepath.Join(dir, filepath.FromSlash(path))
+		// The directories and files must be readable by the user of the invocation image
+		if err := d.FileSystem.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("error creating the directory for file %s: %w", path, err)
+		}
+		if err := d.FileSystem.WriteFile(dest, []byte(contents), 0644); err != nil {
+			return fmt.Errorf("error writing file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// readOutputs reads the outputs copied from the container to dir, keyed by the output name.
+func (d *ContainerCLIDriver) readOutputs(dir string, outputs map[string]string) (map[string]string, error) {
+	results := make(map[string]string, len(outputs))
+	for path, name := range outputs {
+		src := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(path, invocationImageOutputsDir+"/")))
+		exists, err := d.FileSystem.Exists(src)
+		if err != nil {
+			return nil, fmt.Errorf("error checking if output %s exists: %w", name, err)
+		}
+		// Outputs that were not generated are handled by the defaults of the bundle
+		if !exists {
+			continue
+		}
+
+		contents, err := d.FileSystem.ReadFile(src)
+		if err != nil {
+			return nil, fmt.Errorf("error reading output %s: %w", name, err)
+		}
+		results[name] = string(contents)
+	}
+	return results, nil
+}
+
+// runCommand executes the CLI and returns its standard output, including
+// the standard error of the CLI in the returned error when it fails.
+func (d *ContainerCLIDriver) runCommand(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := d.NewCommand(ctx, d.Command, args...)
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("could not run %s, make sure that it is installed and on the PATH: %w", d.Command, err)
+		}
+		return nil, fmt.Errorf("%s %s failed: %w\n%s", d.Command, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
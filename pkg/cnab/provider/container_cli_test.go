@@ -0,0 +1,114 @@
+package cnabprovider
+
+import (
+	"bytes"
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestContainerCLIOperation() *driver.Operation {
+	return &driver.Operation{
+		Installation: "myapp",
+		Action:       "install",
+		Image:        bundle.InvocationImage{BaseImage: bundle.BaseImage{Image: "example.com/myapp:v1", Digest: "sha256:abc"}},
+		Environment:  map[string]string{"PASSWORD": "secret", "CNAB_ACTION": "install"},
+		Files:        map[string]string{"/cnab/bundle.json": "{}"},
+		Out:          &bytes.Buffer{},
+		Err:          &bytes.Buffer{},
+	}
+}
+
+func TestLookupDriver_ContainerCLI(t *testing.T) {
+	t.Parallel()
+
+	c := portercontext.NewTestContext(t)
+
+	d, err := LookupDriver(c.Context, DriverNamePodman)
+	require.NoError(t, err)
+	require.IsType(t, &ContainerCLIDriver{}, d)
+	assert.Equal(t, "podman", d.(*ContainerCLIDriver).Command)
+
+	d, err = LookupDriver(c.Context, DriverNameContainerd)
+	require.NoError(t, err)
+	require.IsType(t, &ContainerCLIDriver{}, d)
+	assert.Equal(t, "nerdctl", d.(*ContainerCLIDriver).Command)
+}
+
+func TestContainerCLIDriver_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandOutputEnv, "abc123")
+
+		d := NewPodmanDriver(c.Context)
+		op := newTestContainerCLIOperation()
+		_, err := d.Run(op)
+		require.NoError(t, err)
+	})
+
+	t.Run("create fails", func(t *testing.T) {
+		t.Parallel()
+
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "125")
+		c.Setenv(test.ExpectedCommandErrorEnv, "image not known")
+
+		d := NewContainerdDriver(c.Context)
+		_, err := d.Run(newTestContainerCLIOperation())
+		require.ErrorContains(t, err, "could not create the container for the invocation image: nerdctl create failed")
+		require.ErrorContains(t, err, "image not known")
+	})
+}
+
+func TestContainerCLIDriver_buildCreateArgs(t *testing.T) {
+	t.Parallel()
+
+	c := portercontext.NewTestContext(t)
+	d := NewPodmanDriver(c.Context)
+	op := newTestContainerCLIOperation()
+
+	args := d.buildCreateArgs(op)
+	assert.Equal(t, []string{"create", "--env", "CNAB_ACTION", "--env", "PASSWORD", "example.com/myapp:v1@sha256:abc"}, args,
+		"the values of environment variables should not be included in the arguments")
+
+	require.NoError(t, d.SetConfig(map[string]string{SettingPullAlways: "true"}))
+	args = d.buildCreateArgs(op)
+	assert.Equal(t, []string{"create", "--pull", "always"}, args[:3])
+}
+
+func TestContainerCLIDriver_readOutputs(t *testing.T) {
+	t.Parallel()
+
+	c := portercontext.NewTestContext(t)
+	d := NewPodmanDriver(c.Context)
+	require.NoError(t, c.FileSystem.WriteFile("/tmp/outputs/host", []byte("db.example.com"), 0644))
+
+	outputs, err := d.readOutputs("/tmp/outputs", map[string]string{
+		"/cnab/app/outputs/host": "host",
+		"/cnab/app/outputs/port": "port",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"host": "db.example.com"}, outputs, "outputs that were not generated should be skipped")
+}
+
+func TestContainerCLIDriver_SetConfig(t *testing.T) {
+	t.Parallel()
+
+	c := portercontext.NewTestContext(t)
+	d := NewContainerdDriver(c.Context)
+
+	require.NoError(t, d.SetConfig(map[string]string{SettingCleanupContainers: "false"}))
+	assert.True(t, d.SkipCleanup)
+
+	err := d.SetConfig(map[string]string{SettingPullAlways: "sometimes"})
+	require.EqualError(t, err, `invalid PULL_ALWAYS setting "sometimes", the supported values are true and false`)
+}
@@ -10,6 +10,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+// ResolveCredentials resolves the credentials that would be passed to the
+// bundle when the action is executed, without executing it.
+func (r *Runtime) ResolveCredentials(ctx context.Context, args ActionArguments) (secrets.Set, error) {
+	return r.loadCredentials(ctx, args.BundleReference.Definition, args)
+}
+
 func (r *Runtime) loadCredentials(ctx context.Context, b cnab.ExtendedBundle, args ActionArguments) (secrets.Set, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
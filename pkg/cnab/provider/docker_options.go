@@ -0,0 +1,152 @@
+package cnabprovider
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-units"
+)
+
+const (
+	// DriverOptionCPUs is the --driver-opt that limits the number of CPUs used by the invocation container.
+	DriverOptionCPUs = "cpus"
+
+	// DriverOptionMemory is the --driver-opt that limits the memory used by the invocation container.
+	DriverOptionMemory = "memory"
+
+	// DriverOptionNetwork is the --driver-opt that sets the network mode of the invocation container.
+	DriverOptionNetwork = "network"
+
+	// DriverOptionMount is the --driver-opt that mounts a volume in the invocation container.
+	DriverOptionMount = "mount"
+
+	// DriverOptionEnv is the --driver-opt that passes an environment variable through to the invocation container.
+	DriverOptionEnv = "env"
+)
+
+// DockerOptions are the resource limits, mounts, network and environment
+// of the invocation container that is run by the docker driver.
+type DockerOptions struct {
+	// NanoCPUs is the CPU limit of the container in units of 10^-9 CPUs.
+	NanoCPUs int64
+
+	// Memory is the memory limit of the container in bytes.
+	Memory int64
+
+	// Network is the network mode of the container.
+	Network string
+
+	// Mounts are additional volumes mounted in the container.
+	Mounts []mount.Mount
+
+	// Env are the names of the environment variables passed through to the container.
+	Env []string
+}
+
+// ParseDockerOptions parses driver options in the form KEY=VALUE. When an
+// option that accepts a single value is repeated, the last value is used.
+func ParseDockerOptions(opts []string) (DockerOptions, error) {
+	var result DockerOptions
+	for _, opt := range opts {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok || value == "" {
+			return DockerOptions{}, fmt.Errorf("invalid --driver-opt %s, it must be in the form KEY=VALUE", opt)
+		}
+
+		switch key {
+		case DriverOptionCPUs:
+			cpus, err := strconv.ParseFloat(value, 64)
+			if err != nil || cpus <= 0 {
+				return DockerOptions{}, fmt.Errorf("invalid --driver-opt %s, the number of CPUs must be a positive number, for example 1.5", opt)
+			}
+			result.NanoCPUs = int64(cpus * 1e9)
+		case DriverOptionMemory:
+			memory, err := units.RAMInBytes(value)
+			if err != nil || memory <= 0 {
+				return DockerOptions{}, fmt.Errorf("invalid --driver-opt %s, the memory must be a positive size, for example 512m or 4g", opt)
+			}
+			result.Memory = memory
+		case DriverOptionNetwork:
+			result.Network = value
+		case DriverOptionMount:
+			m, err := parseDockerMount(value)
+			if err != nil {
+				return DockerOptions{}, fmt.Errorf("invalid --driver-opt %s: %w", opt, err)
+			}
+			result.Mounts = append(result.Mounts, m)
+		case DriverOptionEnv:
+			if strings.ContainsAny(value, "= ") {
+				return DockerOptions{}, fmt.Errorf("invalid --driver-opt %s, specify the name of an environment variable to pass through to the container", opt)
+			}
+			result.Env = append(result.Env, value)
+		default:
+			return DockerOptions{}, fmt.Errorf("invalid --driver-opt %s, allowed options are: %s, %s, %s, %s, %s",
+				opt, DriverOptionCPUs, DriverOptionMemory, DriverOptionNetwork, DriverOptionMount, DriverOptionEnv)
+		}
+	}
+	return result, nil
+}
+
+// parseDockerMount parses a mount in the form SOURCE:TARGET[:ro|rw]. An absolute
+// source is mounted from the host, otherwise the source is the name of a volume.
+func parseDockerMount(value string) (mount.Mount, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return mount.Mount{}, fmt.Errorf("the mount must be in the form SOURCE:TARGET[:ro]")
+	}
+	if !path.IsAbs(parts[1]) {
+		return mount.Mount{}, fmt.Errorf("the mount target %s must be an absolute path", parts[1])
+	}
+
+	m := mount.Mount{Type: mount.TypeVolume, Source: parts[0], Target: parts[1]}
+	if path.IsAbs(parts[0]) {
+		m.Type = mount.TypeBind
+	}
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "ro":
+			m.ReadOnly = true
+		case "rw":
+		default:
+			return mount.Mount{}, fmt.Errorf("invalid mount mode %s, allowed values are: ro, rw", parts[2])
+		}
+	}
+	return m, nil
+}
+
+// applyDockerOptions returns a configuration option for the docker driver that
+// applies the options to the invocation container.
+func (r *Runtime) applyDockerOptions(opts DockerOptions) func(cfg *container.Config, hostCfg *container.HostConfig) error {
+	return func(cfg *container.Config, hostCfg *container.HostConfig) error {
+		if opts.NanoCPUs > 0 {
+			hostCfg.NanoCPUs = opts.NanoCPUs
+		}
+		if opts.Memory > 0 {
+			hostCfg.Memory = opts.Memory
+		}
+		if opts.Network != "" {
+			hostCfg.NetworkMode = container.NetworkMode(opts.Network)
+		}
+		hostCfg.Mounts = append(hostCfg.Mounts, opts.Mounts...)
+
+		// Environment variables defined by the bundle take precedence over passthrough variables
+		defined := make(map[string]struct{}, len(cfg.Env))
+		for _, env := range cfg.Env {
+			name, _, _ := strings.Cut(env, "=")
+			defined[name] = struct{}{}
+		}
+		for _, name := range opts.Env {
+			if _, ok := defined[name]; ok {
+				continue
+			}
+			if value, ok := r.LookupEnv(name); ok {
+				cfg.Env = append(cfg.Env, name+"="+value)
+			}
+		}
+		return nil
+	}
+}
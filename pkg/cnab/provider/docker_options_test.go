@@ -0,0 +1,78 @@
+package cnabprovider
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDockerOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseDockerOptions([]string{
+			"cpus=1", "cpus=1.5", "memory=4g", "network=host",
+			"mount=/home/me/.terraform.d:/root/.terraform.d:ro", "mount=tfcache:/cache",
+			"env=TF_LOG", "env=HTTPS_PROXY",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, DockerOptions{
+			NanoCPUs: 1500000000,
+			Memory:   4 * 1024 * 1024 * 1024,
+			Network:  "host",
+			Mounts: []mount.Mount{
+				{Type: mount.TypeBind, Source: "/home/me/.terraform.d", Target: "/root/.terraform.d", ReadOnly: true},
+				{Type: mount.TypeVolume, Source: "tfcache", Target: "/cache"},
+			},
+			Env: []string{"TF_LOG", "HTTPS_PROXY"},
+		}, opts)
+	})
+
+	testcases := []struct {
+		opt     string
+		wantErr string
+	}{
+		{opt: "cpus", wantErr: "invalid --driver-opt cpus, it must be in the form KEY=VALUE"},
+		{opt: "cpus=-1", wantErr: "invalid --driver-opt cpus=-1, the number of CPUs must be a positive number, for example 1.5"},
+		{opt: "memory=lots", wantErr: "invalid --driver-opt memory=lots, the memory must be a positive size, for example 512m or 4g"},
+		{opt: "mount=/src", wantErr: "invalid --driver-opt mount=/src: the mount must be in the form SOURCE:TARGET[:ro]"},
+		{opt: "mount=/src:dest", wantErr: "invalid --driver-opt mount=/src:dest: the mount target dest must be an absolute path"},
+		{opt: "mount=/src:/dest:rx", wantErr: "invalid --driver-opt mount=/src:/dest:rx: invalid mount mode rx, allowed values are: ro, rw"},
+		{opt: "env=TF_LOG=debug", wantErr: "invalid --driver-opt env=TF_LOG=debug, specify the name of an environment variable to pass through to the container"},
+		{opt: "gpus=all", wantErr: "invalid --driver-opt gpus=all, allowed options are: cpus, memory, network, mount, env"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.opt, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseDockerOptions([]string{tc.opt})
+			require.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRuntime_applyDockerOptions(t *testing.T) {
+	t.Parallel()
+
+	r := NewTestRuntime(t)
+	defer r.Close()
+	r.Setenv("TF_LOG", "debug")
+	r.Setenv("CNAB_ACTION", "uninstall")
+
+	opts := DockerOptions{NanoCPUs: 2000000000, Memory: 1024, Network: "host", Env: []string{"TF_LOG", "CNAB_ACTION", "MISSING"}}
+	cfg := &container.Config{Env: []string{"CNAB_ACTION=install"}}
+	hostCfg := &container.HostConfig{}
+	require.NoError(t, r.applyDockerOptions(opts)(cfg, hostCfg))
+
+	assert.Equal(t, int64(2000000000), hostCfg.NanoCPUs)
+	assert.Equal(t, int64(1024), hostCfg.Memory)
+	assert.Equal(t, container.NetworkMode("host"), hostCfg.NetworkMode)
+	assert.Equal(t, []string{"CNAB_ACTION=install", "TF_LOG=debug"}, cfg.Env,
+		"variables defined by the bundle should not be overridden, and unset variables should be skipped")
+}
@@ -1,10 +1,16 @@
 package cnabprovider
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/cnab/drivers"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
 	"github.com/cnabio/cnab-go/driver"
 	"github.com/cnabio/cnab-go/driver/docker"
 	"github.com/docker/docker/api/types/container"
@@ -16,14 +22,42 @@ const (
 
 	// DriverNameDebug is the name of the CNAB debug driver.
 	DriverNameDebug = "debug"
+
+	// DriverNameKubernetes is the name of the driver that runs bundles as Kubernetes Jobs.
+	DriverNameKubernetes = "kubernetes"
+
+	// DriverNamePodman is the name of the driver that runs bundles with podman.
+	DriverNamePodman = "podman"
+
+	// DriverNameContainerd is the name of the driver that runs bundles on containerd with nerdctl.
+	DriverNameContainerd = "containerd"
+
+	// invocationImageOutputsDir is the directory where the invocation image writes its outputs.
+	invocationImageOutputsDir = "/cnab/app/outputs"
 )
 
-func (r *Runtime) newDriver(driverName string, args ActionArguments) (driver.Driver, error) {
+// LookupDriver creates a driver by name. The kubernetes, podman and containerd
+// drivers are implemented by Porter, and the other drivers are looked up with
+// drivers.LookupDriver.
+func LookupDriver(cxt *portercontext.Context, name string) (driver.Driver, error) {
+	switch name {
+	case DriverNameKubernetes, "k8s":
+		return NewKubernetesDriver(config.KubernetesConfig{}), nil
+	case DriverNamePodman:
+		return NewPodmanDriver(cxt), nil
+	case DriverNameContainerd:
+		return NewContainerdDriver(cxt), nil
+	default:
+		return drivers.LookupDriver(cxt, name)
+	}
+}
+
+func (r *Runtime) newDriver(ctx context.Context, driverName string, args ActionArguments, exts cnab.ProcessedExtensions) (driver.Driver, error) {
 	var driverImpl driver.Driver
 	var err error
 
 	// Pull applicable extension from list of processed extensions
-	dockerExt, dockerRequired, err := r.Extensions.GetDocker()
+	dockerExt, dockerRequired, err := exts.GetDocker()
 	if err != nil {
 		return nil, err
 	}
@@ -39,12 +73,36 @@ func (r *Runtime) newDriver(driverName string, args ActionArguments) (driver.Dri
 			return nil, fmt.Errorf("extension %q is required but allow-docker-host-access was not enabled",
 				cnab.DockerExtensionKey)
 		}
-		driverImpl, err = drivers.LookupDriver(r.Context, driverName)
+		driverImpl, err = LookupDriver(r.Context, driverName)
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	// Apply the resource limits, mounts, network and environment for the invocation container
+	if dockerDriver, ok := driverImpl.(*docker.Driver); ok {
+		opts, err := ParseDockerOptions(append(r.Data.Docker.GetDriverOptions(), args.DriverOptions...))
+		if err != nil {
+			return nil, err
+		}
+		dockerDriver.AddConfigurationOptions(r.applyDockerOptions(opts))
+	} else if len(args.DriverOptions) > 0 {
+		return nil, fmt.Errorf("--driver-opt is only supported by the %s driver", DriverNameDocker)
+	}
+
+	// Apply the kubernetes section of the config file, which may be overridden by the environment below
+	if k8sDriver, ok := driverImpl.(*KubernetesDriver); ok {
+		k8sDriver.KubernetesConfig = r.Data.Kubernetes
+	}
+
+	// Log the warnings of the drivers implemented by Porter with the action
+	switch d := driverImpl.(type) {
+	case *KubernetesDriver:
+		d.ctx = ctx
+	case *ContainerCLIDriver:
+		d.ctx = ctx
+	}
+
 	if configurable, ok := driverImpl.(driver.Configurable); ok {
 		driverCfg := make(map[string]string)
 		// Load any driver-specific config out of the environment
@@ -54,12 +112,23 @@ func (r *Runtime) newDriver(driverName string, args ActionArguments) (driver.Dri
 			}
 		}
 
-		configurable.SetConfig(driverCfg)
+		if err := configurable.SetConfig(driverCfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration for the %s driver: %w", driverName, err)
+		}
 	}
 
 	return driverImpl, nil
 }
 
+// loggerFromContext returns the logger of the action that runs a driver, or a
+// no-op logger when the driver is not run by an action.
+func loggerFromContext(ctx context.Context) tracing.TraceLogger {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tracing.LoggerFromContext(ctx)
+}
+
 func (r *Runtime) dockerDriverWithHostAccess(config cnab.Docker) (driver.Driver, error) {
 	d := &docker.Driver{}
 
@@ -78,3 +147,22 @@ func (r *Runtime) dockerDriverWithHostAccess(config cnab.Docker) (driver.Driver,
 
 	return driver.Driver(d), nil
 }
+
+// getInvocationImageReference returns the reference of the invocation image,
+// pinned to its digest when known.
+func getInvocationImageReference(op *driver.Operation) string {
+	if op.Image.Digest == "" || strings.Contains(op.Image.Image, "@") {
+		return op.Image.Image
+	}
+	return op.Image.Image + "@" + op.Image.Digest
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
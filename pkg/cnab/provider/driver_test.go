@@ -1,9 +1,11 @@
 package cnabprovider
 
 import (
+	"context"
 	"testing"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
 	"github.com/cnabio/cnab-go/driver/docker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,7 +20,7 @@ func TestNewDriver_Docker(t *testing.T) {
 		r := NewTestRuntime(t)
 		defer r.Close()
 
-		driver, err := r.newDriver(DriverNameDocker, ActionArguments{})
+		driver, err := r.newDriver(context.Background(), DriverNameDocker, ActionArguments{}, nil)
 
 		require.NoError(t, err)
 		assert.IsType(t, driver, &docker.Driver{})
@@ -37,7 +39,7 @@ func TestNewDriver_Docker(t *testing.T) {
 			AllowDockerHostAccess: true,
 		}
 
-		driver, err := r.newDriver(DriverNameDocker, args)
+		driver, err := r.newDriver(context.Background(), DriverNameDocker, args, nil)
 
 		require.NoError(t, err)
 		assert.IsType(t, driver, &docker.Driver{})
@@ -54,7 +56,7 @@ func TestNewDriver_Docker(t *testing.T) {
 			AllowDockerHostAccess: true,
 		}
 
-		_, err := r.newDriver("custom-driver", args)
+		_, err := r.newDriver(context.Background(), "custom-driver", args, nil)
 
 		assert.EqualError(t, err, "allow-docker-host-access was enabled, but the driver is custom-driver")
 	})
@@ -68,13 +70,13 @@ func TestNewDriver_Docker(t *testing.T) {
 
 		// Currently, toggling Privileged is the only config exposed to users
 		// Here we supply no override, so expect Privileged to be false
-		r.Extensions[cnab.DockerExtensionKey] = cnab.Docker{}
+		exts := cnab.ProcessedExtensions{cnab.DockerExtensionKey: cnab.Docker{}}
 		r.FileSystem.Create("/var/run/docker.sock")
 		args := ActionArguments{
 			AllowDockerHostAccess: true,
 		}
 
-		driver, err := r.newDriver(DriverNameDocker, args)
+		driver, err := r.newDriver(context.Background(), DriverNameDocker, args, exts)
 		require.NoError(t, err)
 		assert.IsType(t, driver, &docker.Driver{})
 
@@ -98,15 +100,15 @@ func TestNewDriver_Docker(t *testing.T) {
 
 		// Currently, toggling Privileged is the only config exposed to users
 		// Here we supply an override, so expect Privileged to be set to the override
-		r.Extensions[cnab.DockerExtensionKey] = cnab.Docker{
+		exts := cnab.ProcessedExtensions{cnab.DockerExtensionKey: cnab.Docker{
 			Privileged: true,
-		}
+		}}
 		r.FileSystem.Create("/var/run/docker.sock")
 		args := ActionArguments{
 			AllowDockerHostAccess: true,
 		}
 
-		driver, err := r.newDriver(DriverNameDocker, args)
+		driver, err := r.newDriver(context.Background(), DriverNameDocker, args, exts)
 		require.NoError(t, err)
 		assert.IsType(t, driver, &docker.Driver{})
 
@@ -121,3 +123,80 @@ func TestNewDriver_Docker(t *testing.T) {
 		require.Equal(t, true, containerHostCfg.Privileged)
 	})
 }
+
+func TestNewDriver_Kubernetes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("config file and environment", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewTestRuntime(t)
+		defer r.Close()
+
+		r.Data.Kubernetes = config.KubernetesConfig{Namespace: "dev", ServiceAccount: "porter"}
+		r.Setenv(SettingKubeNamespace, "test")
+
+		d, err := r.newDriver(context.Background(), "k8s", ActionArguments{}, nil)
+		require.NoError(t, err)
+		require.IsType(t, &KubernetesDriver{}, d)
+
+		k8sDriver := d.(*KubernetesDriver)
+		assert.Equal(t, "test", k8sDriver.Namespace, "the environment should override the config file")
+		assert.Equal(t, "porter", k8sDriver.ServiceAccount)
+	})
+
+	t.Run("invalid setting", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewTestRuntime(t)
+		defer r.Close()
+
+		r.Setenv(SettingCleanupJobs, "maybe")
+
+		_, err := r.newDriver(context.Background(), DriverNameKubernetes, ActionArguments{}, nil)
+		require.EqualError(t, err, `invalid configuration for the kubernetes driver: invalid CLEANUP_JOBS setting "maybe", the supported values are true and false`)
+	})
+}
+
+func TestNewDriver_DriverOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("config file and flags", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewTestRuntime(t)
+		defer r.Close()
+
+		r.Data.Docker = config.DockerConfig{CPUs: "2", Memory: "1g"}
+		d, err := r.newDriver(context.Background(), DriverNameDocker, ActionArguments{DriverOptions: []string{"memory=2g"}}, nil)
+		require.NoError(t, err)
+
+		dockerish := d.(*docker.Driver)
+		require.NoError(t, dockerish.ApplyConfigurationOptions())
+		hostCfg, err := dockerish.GetContainerHostConfig()
+		require.NoError(t, err)
+		assert.Equal(t, int64(2000000000), hostCfg.NanoCPUs)
+		assert.Equal(t, int64(2*1024*1024*1024), hostCfg.Memory, "the flag should override the config file")
+	})
+
+	t.Run("invalid config file", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewTestRuntime(t)
+		defer r.Close()
+
+		r.Data.Docker = config.DockerConfig{Memory: "lots"}
+		_, err := r.newDriver(context.Background(), DriverNameDocker, ActionArguments{}, nil)
+		require.ErrorContains(t, err, "invalid --driver-opt memory=lots")
+	})
+
+	t.Run("unsupported driver", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewTestRuntime(t)
+		defer r.Close()
+
+		_, err := r.newDriver(context.Background(), DriverNameDebug, ActionArguments{DriverOptions: []string{"cpus=1"}}, nil)
+		require.EqualError(t, err, "--driver-opt is only supported by the docker driver")
+	})
+}
@@ -0,0 +1,555 @@
+package cnabprovider
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/config"
+	"github.com/cnabio/cnab-go/driver"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// SettingKubeNamespace is the driver setting for the namespace where jobs are created.
+	SettingKubeNamespace = "KUBE_NAMESPACE"
+
+	// SettingKubeconfig is the driver setting for the path to the kubeconfig file.
+	SettingKubeconfig = "KUBECONFIG"
+
+	// SettingKubeContext is the driver setting for the kubeconfig context.
+	SettingKubeContext = "KUBE_CONTEXT"
+
+	// SettingInCluster is the driver setting to connect with the in-cluster service account.
+	SettingInCluster = "IN_CLUSTER"
+
+	// SettingServiceAccount is the driver setting for the service account that jobs run as.
+	SettingServiceAccount = "SERVICE_ACCOUNT"
+
+	// SettingLabels is the driver setting for the labels added to the resources created by the driver.
+	SettingLabels = "LABELS"
+
+	// SettingCleanupJobs is the driver setting that determines if jobs are removed after they complete.
+	SettingCleanupJobs = "CLEANUP_JOBS"
+
+	// kubernetesOutputMarker prefixes the lines printed by a job with the contents of an output.
+	kubernetesOutputMarker = "::porter-output::"
+
+	// kubernetesJobScript runs the invocation image, and then prints the outputs
+	// so that they can be collected from the logs of the job.
+	kubernetesJobScript = `/cnab/app/run
+status=$?
+for f in ` + invocationImageOutputsDir + `/*; do
+  [ -f "$f" ] || continue
+  printf '` + kubernetesOutputMarker + `%s ' "${f##*/}"
+  base64 "$f" | tr -d '\n'
+  echo
+done
+exit $status
+`
+
+	// kubernetesPollInterval is how often the status of a job is checked.
+	kubernetesPollInterval = 2 * time.Second
+
+	// serviceAccountNamespaceFile contains the namespace of the pod that Porter is running in.
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+var (
+	// invalidResourceNameChars are the characters that are not allowed in the name of a Kubernetes resource.
+	invalidResourceNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+	// fatalContainerWaitingReasons are the reasons that a container is waiting
+	// to start which prevent it from ever starting.
+	fatalContainerWaitingReasons = map[string]struct{}{
+		"ErrImagePull":               {},
+		"ImagePullBackOff":           {},
+		"InvalidImageName":           {},
+		"CreateContainerConfigError": {},
+		"CreateContainerError":       {},
+	}
+)
+
+// KubernetesDriver executes the invocation image of a bundle as a Kubernetes Job,
+// so that bundles can be run without a local Docker daemon.
+//
+// The parameters, credentials and files of the bundle are passed to the job in a
+// Secret. The logs of the job are streamed back to Porter, and the outputs of
+// the bundle are collected from the logs after the bundle completes.
+type KubernetesDriver struct {
+	config.KubernetesConfig
+
+	// client connects to the cluster. When it is not set, a client is created
+	// from the configuration.
+	client kubernetesClient
+
+	// pollInterval is how often the status of a job is checked.
+	pollInterval time.Duration
+
+	// ctx of the action that runs the bundle, used to log warnings.
+	ctx context.Context
+}
+
+// NewKubernetesDriver creates a driver that executes bundles as Kubernetes Jobs.
+func NewKubernetesDriver(cfg config.KubernetesConfig) *KubernetesDriver {
+	return &KubernetesDriver{
+		KubernetesConfig: cfg,
+		pollInterval:     kubernetesPollInterval,
+	}
+}
+
+// Handles returns true for the image types that can be run by the driver.
+func (d *KubernetesDriver) Handles(imageType string) bool {
+	return imageType == driver.ImageTypeDocker || imageType == driver.ImageTypeOCI
+}
+
+// Config returns the settings of the driver that may be set with environment
+// variables. They override the kubernetes section of the Porter config file.
+func (d *KubernetesDriver) Config() map[string]string {
+	return map[string]string{
+		SettingKubeNamespace:  "Kubernetes namespace in which to run the invocation image. Defaults to the namespace of the current context.",
+		SettingKubeconfig:     "Path to the kubeconfig file",
+		SettingKubeContext:    "Kubeconfig context used to connect to the cluster. Defaults to the current context.",
+		SettingInCluster:      "Connect to the cluster with the service account of the pod that Porter is running in. The supported values are true and false.",
+		SettingServiceAccount: "Kubernetes service account used by the invocation image. When empty, no service account token is mounted.",
+		SettingLabels:         "Labels to apply to the resources created by the driver, expressed as name value pairs separated by whitespace, e.g. 'A=B X=Y'.",
+		SettingCleanupJobs:    "If true, the job and secret are removed when the bundle completes. The supported values are true and false. Defaults to true.",
+	}
+}
+
+// SetConfig overrides the configuration of the driver with the specified settings.
+func (d *KubernetesDriver) SetConfig(settings map[string]string) error {
+	if value, ok := settings[SettingKubeNamespace]; ok && value != "" {
+		d.Namespace = value
+	}
+	if value, ok := settings[SettingKubeconfig]; ok && value != "" {
+		d.Kubeconfig = value
+	}
+	if value, ok := settings[SettingKubeContext]; ok && value != "" {
+		d.Context = value
+	}
+	if value, ok := settings[SettingServiceAccount]; ok && value != "" {
+		d.ServiceAccount = value
+	}
+
+	if value, ok := settings[SettingInCluster]; ok && value != "" {
+		inCluster, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s setting %q, the supported values are true and false", SettingInCluster, value)
+		}
+		d.InCluster = inCluster
+	}
+
+	if value, ok := settings[SettingCleanupJobs]; ok && value != "" {
+		cleanup, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s setting %q, the supported values are true and false", SettingCleanupJobs, value)
+		}
+		d.SkipCleanup = !cleanup
+	}
+
+	if value, ok := settings[SettingLabels]; ok && value != "" {
+		labels := make(map[string]string, len(d.Labels))
+		for k, v := range d.Labels {
+			labels[k] = v
+		}
+		for _, pair := range strings.Fields(value) {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("invalid %s setting %q, labels must be specified as NAME=VALUE", SettingLabels, pair)
+			}
+			labels[k] = v
+		}
+		d.Labels = labels
+	}
+
+	return nil
+}
+
+// Run executes the operation as a Kubernetes Job and waits for it to complete.
+func (d *KubernetesDriver) Run(op *driver.Operation) (driver.OperationResult, error) {
+	ctx := context.Background()
+	log := loggerFromContext(d.ctx)
+
+	client := d.client
+	if client == nil {
+		var err error
+		client, err = newKubernetesClientset(d.KubernetesConfig)
+		if err != nil {
+			return driver.OperationResult{}, err
+		}
+	}
+
+	secret, err := client.CreateSecret(ctx, d.buildSecret(op))
+	if err != nil {
+		return driver.OperationResult{}, fmt.Errorf("could not create the secret for the invocation image: %w", err)
+	}
+	if !d.SkipCleanup {
+		defer func() {
+			if err := client.DeleteSecret(context.Background(), secret.Name); err != nil {
+				log.Warnf("could not remove secret %s: %s", secret.Name, err)
+			}
+		}()
+	}
+
+	job, err := client.CreateJob(ctx, d.buildJob(op, secret.Name))
+	if err != nil {
+		return driver.OperationResult{}, fmt.Errorf("could not create the job for the invocation image: %w", err)
+	}
+	if !d.SkipCleanup {
+		defer func() {
+			if err := client.DeleteJob(context.Background(), job.Name); err != nil {
+				log.Warnf("could not remove job %s: %s", job.Name, err)
+			}
+		}()
+	}
+
+	pod, err := d.waitForPodStart(ctx, client, job.Name)
+	if err != nil {
+		return driver.OperationResult{}, err
+	}
+
+	logs, err := client.StreamLogs(ctx, pod.Name)
+	if err != nil {
+		return driver.OperationResult{}, fmt.Errorf("could not stream the logs of pod %s: %w", pod.Name, err)
+	}
+	defer logs.Close()
+
+	outputs, err := collectKubernetesOutputs(logs, op.Out)
+	if err != nil {
+		return driver.OperationResult{}, fmt.Errorf("could not read the logs of pod %s: %w", pod.Name, err)
+	}
+
+	result := driver.OperationResult{Outputs: make(map[string]string, len(op.Outputs))}
+	for path, name := range op.Outputs {
+		if value, ok := outputs[path]; ok {
+			result.Outputs[name] = value
+		}
+	}
+
+	pod, err = d.waitForPodCompletion(ctx, client, job.Name)
+	if err != nil {
+		return result, err
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		exitCode, message := getContainerTermination(pod)
+		return result, fmt.Errorf("job %s failed, container exit code: %d, message: %s", job.Name, exitCode, message)
+	}
+
+	return result, nil
+}
+
+// buildSecret creates the definition of the secret that holds the environment
+// variables and files passed to the invocation image.
+func (d *KubernetesDriver) buildSecret(op *driver.Operation) *corev1.Secret {
+	data := make(map[string]string, len(op.Environment)+len(op.Files))
+	for name, value := range op.Environment {
+		data[getKubernetesEnvKey(name)] = value
+	}
+	for i, path := range sortedKeys(op.Files) {
+		data[getKubernetesFileKey(i)] = op.Files[path]
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: d.buildObjectMeta(op),
+		Type:       corev1.SecretTypeOpaque,
+		StringData: data,
+	}
+}
+
+// buildJob creates the definition of the job that runs the invocation image.
+func (d *KubernetesDriver) buildJob(op *driver.Operation, secretName string) *batchv1.Job {
+	var env []corev1.EnvVar
+	for _, name := range sortedKeys(op.Environment) {
+		env = append(env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  getKubernetesEnvKey(name),
+				},
+			},
+		})
+	}
+
+	// Mount each file from the secret at its path in the invocation image
+	const filesVolume = "porter-files"
+	const outputsVolume = "porter-outputs"
+	var items []corev1.KeyToPath
+	mounts := []corev1.VolumeMount{{Name: outputsVolume, MountPath: invocationImageOutputsDir}}
+	for i, path := range sortedKeys(op.Files) {
+		key := getKubernetesFileKey(i)
+		items = append(items, corev1.KeyToPath{Key: key, Path: key})
+		mounts = append(mounts, corev1.VolumeMount{Name: filesVolume, MountPath: path, SubPath: key, ReadOnly: true})
+	}
+	volumes := []corev1.Volume{
+		{Name: outputsVolume, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	if len(items) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name:         filesVolume,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName, Items: items}},
+		})
+	}
+
+	meta := d.buildObjectMeta(op)
+	backoffLimit := int32(0)
+	automountToken := d.ServiceAccount != ""
+	return &batchv1.Job{
+		ObjectMeta: meta,
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: meta.Labels, Annotations: meta.Annotations},
+				Spec: corev1.PodSpec{
+					RestartPolicy:                corev1.RestartPolicyNever,
+					ServiceAccountName:           d.ServiceAccount,
+					AutomountServiceAccountToken: &automountToken,
+					Volumes:                      volumes,
+					Containers: []corev1.Container{
+						{
+							Name:            "invocation-image",
+							Image:           getInvocationImageReference(op),
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         []string{"/bin/sh", "-c", kubernetesJobScript},
+							Env:             env,
+							VolumeMounts:    mounts,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildObjectMeta creates the metadata shared by the resources created for an operation.
+func (d *KubernetesDriver) buildObjectMeta(op *driver.Operation) metav1.ObjectMeta {
+	labels := map[string]string{"app.kubernetes.io/managed-by": "porter"}
+	for k, v := range d.Labels {
+		labels[k] = v
+	}
+
+	return metav1.ObjectMeta{
+		GenerateName: getKubernetesResourcePrefix(op),
+		Labels:       labels,
+		Annotations: map[string]string{
+			"porter.sh/installation": op.Installation,
+			"porter.sh/action":       op.Action,
+			"porter.sh/revision":     op.Revision,
+		},
+	}
+}
+
+// waitForPodStart waits until the pod of a job has started, so that its logs are available.
+func (d *KubernetesDriver) waitForPodStart(ctx context.Context, client kubernetesClient, jobName string) (corev1.Pod, error) {
+	return d.waitForPod(ctx, client, jobName, func(pod corev1.Pod) bool {
+		return pod.Status.Phase != corev1.PodPending && pod.Status.Phase != ""
+	})
+}
+
+// waitForPodCompletion waits until the pod of a job has succeeded or failed.
+func (d *KubernetesDriver) waitForPodCompletion(ctx context.Context, client kubernetesClient, jobName string) (corev1.Pod, error) {
+	return d.waitForPod(ctx, client, jobName, func(pod corev1.Pod) bool {
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+	})
+}
+
+// waitForPod polls the pod of a job until the condition is met, or the pod
+// can never start.
+func (d *KubernetesDriver) waitForPod(ctx context.Context, client kubernetesClient, jobName string, done func(pod corev1.Pod) bool) (corev1.Pod, error) {
+	for {
+		pods, err := client.ListJobPods(ctx, jobName)
+		if err != nil {
+			return corev1.Pod{}, fmt.Errorf("could not list the pods of job %s: %w", jobName, err)
+		}
+
+		for _, pod := range pods {
+			if done(pod) {
+				return pod, nil
+			}
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.State.Waiting == nil {
+					continue
+				}
+				if _, ok := fatalContainerWaitingReasons[status.State.Waiting.Reason]; ok {
+					return corev1.Pod{}, fmt.Errorf("the invocation image could not be started in pod %s: %s %s", pod.Name, status.State.Waiting.Reason, status.State.Waiting.Message)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return corev1.Pod{}, ctx.Err()
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+// collectKubernetesOutputs copies the logs of a job to out, and returns the
+// outputs printed by the job, keyed by their path in the invocation image.
+func collectKubernetesOutputs(logs io.Reader, out io.Writer) (map[string]string, error) {
+	outputs := make(map[string]string)
+	r := bufio.NewReader(logs)
+	for {
+		line, readErr := r.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, readErr
+		}
+
+		if i := strings.Index(line, kubernetesOutputMarker); i >= 0 {
+			// The marker is appended to the last line of the logs when it did not end with a newline
+			if i > 0 {
+				fmt.Fprintln(out, line[:i])
+			}
+
+			name, encoded, _ := strings.Cut(strings.TrimSpace(line[i+len(kubernetesOutputMarker):]), " ")
+			value, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode output %s: %w", name, err)
+			}
+			outputs[invocationImageOutputsDir+"/"+name] = string(value)
+		} else if line != "" {
+			if _, err := io.WriteString(out, line); err != nil {
+				return nil, err
+			}
+		}
+
+		if readErr == io.EOF {
+			return outputs, nil
+		}
+	}
+}
+
+// getContainerTermination returns the exit code and message of the invocation image container.
+func getContainerTermination(pod corev1.Pod) (int32, string) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return status.State.Terminated.ExitCode, status.State.Terminated.Message
+		}
+	}
+	return 0, pod.Status.Message
+}
+
+// getKubernetesResourcePrefix returns the prefix of the names of the resources created for an operation.
+// Kubernetes appends a random suffix to the prefix.
+func getKubernetesResourcePrefix(op *driver.Operation) string {
+	name := invalidResourceNameChars.ReplaceAllString(strings.ToLower(op.Installation+"-"+op.Action), "-")
+	if len(name) > 40 {
+		name = name[:40]
+	}
+	return "porter-" + strings.Trim(name, "-") + "-"
+}
+
+// getKubernetesEnvKey returns the key in the secret that holds an environment variable.
+func getKubernetesEnvKey(name string) string {
+	return "env." + name
+}
+
+// getKubernetesFileKey returns the key in the secret that holds a file.
+func getKubernetesFileKey(index int) string {
+	return fmt.Sprintf("file.%d", index)
+}
+
+// kubernetesClient is the subset of the Kubernetes API used by the kubernetes driver.
+type kubernetesClient interface {
+	CreateSecret(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error)
+	DeleteSecret(ctx context.Context, name string) error
+	CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error)
+	DeleteJob(ctx context.Context, name string) error
+	ListJobPods(ctx context.Context, jobName string) ([]corev1.Pod, error)
+	StreamLogs(ctx context.Context, podName string) (io.ReadCloser, error)
+}
+
+var _ kubernetesClient = &kubernetesClientset{}
+
+// kubernetesClientset implements kubernetesClient for a namespace in a cluster.
+type kubernetesClientset struct {
+	clientset kubernetes.Interface
+	namespace string
+}
+
+// newKubernetesClientset connects to the cluster specified by the configuration.
+func newKubernetesClientset(cfg config.KubernetesConfig) (*kubernetesClientset, error) {
+	var restConfig *rest.Config
+	namespace := cfg.Namespace
+	if cfg.InCluster {
+		var err error
+		restConfig, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to the cluster with the in-cluster configuration: %w", err)
+		}
+		if namespace == "" {
+			if contents, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+				namespace = strings.TrimSpace(string(contents))
+			}
+		}
+	} else {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		rules.ExplicitPath = cfg.Kubeconfig
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: cfg.Context}
+		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+
+		var err error
+		restConfig, err = clientConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("could not load the kubeconfig: %w", err)
+		}
+		if namespace == "" {
+			namespace, _, err = clientConfig.Namespace()
+			if err != nil {
+				return nil, fmt.Errorf("could not determine the namespace from the kubeconfig: %w", err)
+			}
+		}
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create a kubernetes client: %w", err)
+	}
+	return &kubernetesClientset{clientset: clientset, namespace: namespace}, nil
+}
+
+func (c *kubernetesClientset) CreateSecret(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+	return c.clientset.CoreV1().Secrets(c.namespace).Create(ctx, secret, metav1.CreateOptions{})
+}
+
+func (c *kubernetesClientset) DeleteSecret(ctx context.Context, name string) error {
+	return c.clientset.CoreV1().Secrets(c.namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *kubernetesClientset) CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	return c.clientset.BatchV1().Jobs(c.namespace).Create(ctx, job, metav1.CreateOptions{})
+}
+
+func (c *kubernetesClientset) DeleteJob(ctx context.Context, name string) error {
+	// Remove the pods of the job along with the job
+	propagation := metav1.DeletePropagationBackground
+	return c.clientset.BatchV1().Jobs(c.namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+}
+
+func (c *kubernetesClientset) ListJobPods(ctx context.Context, jobName string) ([]corev1.Pod, error) {
+	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + jobName})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func (c *kubernetesClientset) StreamLogs(ctx context.Context, podName string) (io.ReadCloser, error) {
+	return c.clientset.CoreV1().Pods(c.namespace).GetLogs(podName, &corev1.PodLogOptions{Follow: true}).Stream(ctx)
+}
@@ -0,0 +1,243 @@
+package cnabprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// testKubernetesClient simulates a cluster where the pod of each job completes
+// with the configured logs and phase.
+type testKubernetesClient struct {
+	secrets map[string]*corev1.Secret
+	jobs    map[string]*batchv1.Job
+	logs    string
+	phase   corev1.PodPhase
+	waiting string
+}
+
+func newTestKubernetesClient(logs string, phase corev1.PodPhase) *testKubernetesClient {
+	return &testKubernetesClient{
+		secrets: make(map[string]*corev1.Secret),
+		jobs:    make(map[string]*batchv1.Job),
+		logs:    logs,
+		phase:   phase,
+	}
+}
+
+func (c *testKubernetesClient) CreateSecret(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+	secret.Name = secret.GenerateName + "secret"
+	c.secrets[secret.Name] = secret
+	return secret, nil
+}
+
+func (c *testKubernetesClient) DeleteSecret(ctx context.Context, name string) error {
+	delete(c.secrets, name)
+	return nil
+}
+
+func (c *testKubernetesClient) CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	job.Name = job.GenerateName + "job"
+	c.jobs[job.Name] = job
+	return job, nil
+}
+
+func (c *testKubernetesClient) DeleteJob(ctx context.Context, name string) error {
+	delete(c.jobs, name)
+	return nil
+}
+
+func (c *testKubernetesClient) ListJobPods(ctx context.Context, jobName string) ([]corev1.Pod, error) {
+	pod := corev1.Pod{Status: corev1.PodStatus{Phase: c.phase}}
+	pod.Name = jobName + "-pod"
+	if c.waiting != "" {
+		pod.Status.Phase = corev1.PodPending
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: c.waiting, Message: "oops"}}},
+		}
+	} else if c.phase == corev1.PodFailed {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Message: "boom"}}},
+		}
+	}
+	return []corev1.Pod{pod}, nil
+}
+
+func (c *testKubernetesClient) StreamLogs(ctx context.Context, podName string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(c.logs)), nil
+}
+
+func newTestKubernetesOperation() *driver.Operation {
+	return &driver.Operation{
+		Installation: "My_App",
+		Action:       "install",
+		Revision:     "01ABC",
+		Image:        bundle.InvocationImage{BaseImage: bundle.BaseImage{Image: "example.com/myapp:v1", Digest: "sha256:abc"}},
+		Environment:  map[string]string{"PASSWORD": "secret", "CNAB_ACTION": "install"},
+		Files:        map[string]string{"/cnab/bundle.json": "{}", "/cnab/app/porter.yaml": "name: myapp"},
+		Outputs:      map[string]string{"/cnab/app/outputs/host": "host", "/cnab/app/outputs/port": "port"},
+		Out:          &bytes.Buffer{},
+		Err:          &bytes.Buffer{},
+	}
+}
+
+func testKubernetesOutputLine(name string, value string) string {
+	return kubernetesOutputMarker + name + " " + base64.StdEncoding.EncodeToString([]byte(value)) + "\n"
+}
+
+func TestKubernetesDriver_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		logs := "installing myapp\ndone" + testKubernetesOutputLine("host", "db.example.com\n") + testKubernetesOutputLine("unknown", "ignored")
+		client := newTestKubernetesClient(logs, corev1.PodSucceeded)
+		d := NewKubernetesDriver(config.KubernetesConfig{})
+		d.client = client
+
+		op := newTestKubernetesOperation()
+		result, err := d.Run(op)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"host": "db.example.com\n"}, result.Outputs)
+		assert.Equal(t, "installing myapp\ndone\n", op.Out.(*bytes.Buffer).String())
+		assert.Empty(t, client.jobs, "the job should be removed")
+		assert.Empty(t, client.secrets, "the secret should be removed")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		client := newTestKubernetesClient("something went wrong\n"+testKubernetesOutputLine("port", "3306"), corev1.PodFailed)
+		d := NewKubernetesDriver(config.KubernetesConfig{SkipCleanup: true})
+		d.client = client
+
+		result, err := d.Run(newTestKubernetesOperation())
+		require.EqualError(t, err, "job porter-my-app-install-job failed, container exit code: 2, message: boom")
+		assert.Equal(t, map[string]string{"port": "3306"}, result.Outputs)
+		assert.Len(t, client.jobs, 1, "the job should be kept when cleanup is disabled")
+		assert.Len(t, client.secrets, 1, "the secret should be kept when cleanup is disabled")
+	})
+
+	t.Run("image cannot be pulled", func(t *testing.T) {
+		t.Parallel()
+
+		client := newTestKubernetesClient("", corev1.PodPending)
+		client.waiting = "ImagePullBackOff"
+		d := NewKubernetesDriver(config.KubernetesConfig{})
+		d.client = client
+
+		_, err := d.Run(newTestKubernetesOperation())
+		require.EqualError(t, err, "the invocation image could not be started in pod porter-my-app-install-job-pod: ImagePullBackOff oops")
+		assert.Empty(t, client.jobs, "the job should be removed")
+	})
+}
+
+func TestKubernetesDriver_buildJob(t *testing.T) {
+	t.Parallel()
+
+	d := NewKubernetesDriver(config.KubernetesConfig{Labels: map[string]string{"team": "dev"}})
+	op := newTestKubernetesOperation()
+
+	secret := d.buildSecret(op)
+	assert.Equal(t, "porter-my-app-install-", secret.GenerateName)
+	assert.Equal(t, map[string]string{
+		"env.CNAB_ACTION": "install",
+		"env.PASSWORD":    "secret",
+		"file.0":          "name: myapp",
+		"file.1":          "{}",
+	}, secret.StringData)
+
+	job := d.buildJob(op, "mysecret")
+	assert.Equal(t, map[string]string{"app.kubernetes.io/managed-by": "porter", "team": "dev"}, job.Labels)
+	assert.Equal(t, "My_App", job.Annotations["porter.sh/installation"])
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+
+	pod := job.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, pod.RestartPolicy)
+	assert.False(t, *pod.AutomountServiceAccountToken, "the service account token should not be mounted without a service account")
+	require.Len(t, pod.Containers, 1)
+	container := pod.Containers[0]
+	assert.Equal(t, "example.com/myapp:v1@sha256:abc", container.Image)
+	assert.Equal(t, []string{"/bin/sh", "-c", kubernetesJobScript}, container.Command)
+
+	require.Len(t, container.Env, 2)
+	assert.Equal(t, "CNAB_ACTION", container.Env[0].Name)
+	assert.Equal(t, "mysecret", container.Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "env.CNAB_ACTION", container.Env[0].ValueFrom.SecretKeyRef.Key)
+
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "porter-outputs", MountPath: "/cnab/app/outputs"},
+		{Name: "porter-files", MountPath: "/cnab/app/porter.yaml", SubPath: "file.0", ReadOnly: true},
+		{Name: "porter-files", MountPath: "/cnab/bundle.json", SubPath: "file.1", ReadOnly: true},
+	}, container.VolumeMounts)
+}
+
+func TestKubernetesDriver_SetConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("overrides the config file", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewKubernetesDriver(config.KubernetesConfig{Namespace: "dev", ServiceAccount: "porter", Labels: map[string]string{"team": "dev"}})
+		err := d.SetConfig(map[string]string{
+			SettingKubeNamespace: "test",
+			SettingCleanupJobs:   "false",
+			SettingLabels:        "env=test owner=me",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "test", d.Namespace)
+		assert.Equal(t, "porter", d.ServiceAccount)
+		assert.True(t, d.SkipCleanup)
+		assert.Equal(t, map[string]string{"team": "dev", "env": "test", "owner": "me"}, d.Labels)
+	})
+
+	t.Run("invalid bool", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewKubernetesDriver(config.KubernetesConfig{})
+		err := d.SetConfig(map[string]string{SettingInCluster: "yes please"})
+		require.EqualError(t, err, `invalid IN_CLUSTER setting "yes please", the supported values are true and false`)
+	})
+
+	t.Run("invalid labels", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewKubernetesDriver(config.KubernetesConfig{})
+		err := d.SetConfig(map[string]string{SettingLabels: "team"})
+		require.EqualError(t, err, `invalid LABELS setting "team", labels must be specified as NAME=VALUE`)
+	})
+}
+
+func TestCollectKubernetesOutputs(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	outputs, err := collectKubernetesOutputs(strings.NewReader("a\n\nb\n"+kubernetesOutputMarker+"bad !!!\n"), &out)
+	require.Error(t, err)
+	assert.Nil(t, outputs)
+	assert.Contains(t, err.Error(), "could not decode output bad")
+	assert.Equal(t, "a\n\nb\n", out.String())
+
+	out.Reset()
+	_, err = collectKubernetesOutputs(&failingReader{}, &out)
+	require.EqualError(t, err, "read failed")
+}
+
+type failingReader struct{}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
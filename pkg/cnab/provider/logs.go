@@ -0,0 +1,125 @@
+package cnabprovider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	cnabaction "github.com/cnabio/cnab-go/action"
+	"github.com/cnabio/cnab-go/driver"
+)
+
+const (
+	// logStreamFlushInterval is how often the logs of a running bundle are saved.
+	logStreamFlushInterval = time.Second
+
+	// logStreamMaxChunkSize is the amount of buffered logs that triggers a save
+	// before the flush interval has elapsed.
+	logStreamMaxChunkSize = 32 * 1024
+)
+
+// logStream saves the output of a running bundle to storage in chunks, so that
+// the logs can be followed with porter logs show --follow while the bundle is
+// executing.
+type logStream struct {
+	ctx           context.Context
+	installations storage.InstallationProvider
+	run           storage.Run
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	sequence int
+	failed   bool
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// newLogStream starts saving the logs written to the stream for the specified
+// run. Call Close to save any remaining logs.
+func newLogStream(ctx context.Context, installations storage.InstallationProvider, run storage.Run) *logStream {
+	s := &logStream{
+		ctx:           ctx,
+		installations: installations,
+		run:           run,
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(logStreamFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.flush()
+			}
+		}
+	}()
+
+	return s
+}
+
+// Write buffers the logs until the next time they are saved.
+func (s *logStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.buf.Write(p)
+	full := s.buf.Len() >= logStreamMaxChunkSize
+	s.mu.Unlock()
+
+	if full {
+		s.flush()
+	}
+	return len(p), nil
+}
+
+// Close stops the background flush and saves any remaining logs.
+func (s *logStream) Close() {
+	close(s.done)
+	<-s.stopped
+	s.flush()
+}
+
+func (s *logStream) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buf.Len() == 0 || s.failed {
+		return
+	}
+
+	s.sequence++
+	chunk := storage.LogChunk{
+		Namespace:    s.run.Namespace,
+		Installation: s.run.Installation,
+		RunID:        s.run.ID,
+		Sequence:     s.sequence,
+		Timestamp:    time.Now(),
+		Data:         s.buf.String(),
+	}
+	s.buf.Reset()
+
+	if err := s.installations.InsertLogChunk(s.ctx, chunk); err != nil {
+		// Streaming the logs is best effort, the bundle output is still
+		// persisted when the run completes.
+		log := tracing.LoggerFromContext(s.ctx)
+		log.Warnf("Could not save the logs for run %s, porter logs show --follow will not include the remaining logs: %s", s.run.ID, err)
+		s.failed = true
+	}
+}
+
+// StreamLogs copies the output of the bundle into the log stream.
+func (s *logStream) StreamLogs() cnabaction.OperationConfigFunc {
+	return func(op *driver.Operation) error {
+		op.Out = io.MultiWriter(op.Out, s)
+		op.Err = io.MultiWriter(op.Err, s)
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package cnabprovider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/cnabio/cnab-go/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogStream(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewTestRuntime(t)
+	defer r.Close()
+
+	i := r.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "mybuns"))
+	run := r.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+
+	var out, errOut bytes.Buffer
+	op := &driver.Operation{Out: &out, Err: &errOut}
+	stream := newLogStream(ctx, r.TestInstallations, run)
+	require.NoError(t, stream.StreamLogs()(op))
+
+	fmt.Fprintln(op.Out, "installing")
+	fmt.Fprintln(op.Err, "warning")
+	stream.Close()
+
+	assert.Equal(t, "installing\n", out.String(), "the logs should still be written to the original output")
+	assert.Equal(t, "warning\n", errOut.String(), "the logs should still be written to the original error output")
+
+	chunks, err := r.TestInstallations.ListLogChunks(ctx, run.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "installing\nwarning\n", chunks[0].Data)
+	assert.Equal(t, 1, chunks[0].Sequence)
+	assert.Equal(t, "dev", chunks[0].Namespace)
+	assert.Equal(t, "mybuns", chunks[0].Installation)
+
+	chunks, err = r.TestInstallations.ListLogChunks(ctx, run.ID, 1)
+	require.NoError(t, err)
+	assert.Empty(t, chunks)
+}
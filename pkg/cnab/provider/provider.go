@@ -4,10 +4,15 @@ import (
 	"context"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/secrets"
 )
 
 // CNABProvider is the interface Porter uses to communicate with the CNAB runtime
 type CNABProvider interface {
 	LoadBundle(bundleFile string) (cnab.ExtendedBundle, error)
 	Execute(ctx context.Context, arguments ActionArguments) error
+
+	// ResolveCredentials resolves the credentials that would be passed to the
+	// bundle when the action is executed.
+	ResolveCredentials(ctx context.Context, arguments ActionArguments) (secrets.Set, error)
 }
@@ -19,7 +19,6 @@ type Runtime struct {
 	secrets       secrets.Store
 	installations storage.InstallationProvider
 	sanitizer     *storage.Sanitizer
-	Extensions    cnab.ProcessedExtensions
 }
 
 func NewRuntime(c *config.Config, installations storage.InstallationProvider, credentials storage.CredentialSetProvider, secrets secrets.Store, sanitizer *storage.Sanitizer) *Runtime {
@@ -29,15 +28,16 @@ func NewRuntime(c *config.Config, installations storage.InstallationProvider, cr
 		credentials:   credentials,
 		secrets:       secrets,
 		sanitizer:     sanitizer,
-		Extensions:    cnab.ProcessedExtensions{},
 	}
 }
 
-func (r *Runtime) ProcessRequiredExtensions(b cnab.ExtendedBundle) error {
+// ProcessRequiredExtensions returns the configuration of the extensions required by the bundle.
+// The extensions are returned instead of saved on the Runtime, because bundles
+// may be executed at the same time, such as when dependencies run in parallel.
+func (r *Runtime) ProcessRequiredExtensions(b cnab.ExtendedBundle) (cnab.ProcessedExtensions, error) {
 	exts, err := b.ProcessRequiredExtensions()
 	if err != nil {
-		return fmt.Errorf("unable to process required extensions: %w", err)
+		return nil, fmt.Errorf("unable to process required extensions: %w", err)
 	}
-	r.Extensions = exts
-	return nil
+	return exts, nil
 }
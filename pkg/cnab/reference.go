@@ -226,6 +226,23 @@ func (r OCIReference) WithDigest(digest digest.Digest) (OCIReference, error) {
 	return OCIReference{Named: newRef}, nil
 }
 
+// WithRegistry creates a new reference using the same repository path, tag and digest on the specified registry.
+// Example: docker.io/getporter/mybuns:v0.1.1 with mirror.example.com returns mirror.example.com/getporter/mybuns:v0.1.1
+func (r OCIReference) WithRegistry(registry string) (OCIReference, error) {
+	if r.Named == nil {
+		return OCIReference{}, errors.New("OCIReference has not been initialized")
+	}
+
+	value := registry + "/" + reference.Path(r.Named)
+	if r.HasTag() {
+		value += ":" + r.Tag()
+	}
+	if r.HasDigest() {
+		value += "@" + r.Digest().String()
+	}
+	return ParseOCIReference(value)
+}
+
 // ParseRepositoryInfo returns additional metadata about the repository portion of the reference.
 func (r OCIReference) ParseRepositoryInfo() (*registry.RepositoryInfo, error) {
 	if r.Named == nil {
@@ -165,3 +165,23 @@ func TestOCIReference_WithDigest(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid digest")
 	})
 }
+
+func TestOCIReference_WithRegistry(t *testing.T) {
+	testcases := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "getporter/porter-hello:v0.1.0", want: "mirror.example.com:5000/getporter/porter-hello:v0.1.0"},
+		{ref: "nginx", want: "mirror.example.com:5000/library/nginx"},
+		{ref: "ghcr.io/getporter/porter-hello@sha256:a808aa4e3508d7129742eefda938249574447cce5403dc12d4cbbfe7f4f31e58", want: "mirror.example.com:5000/getporter/porter-hello@sha256:a808aa4e3508d7129742eefda938249574447cce5403dc12d4cbbfe7f4f31e58"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.ref, func(t *testing.T) {
+			ref := MustParseOCIReference(tc.ref)
+
+			result, err := ref.WithRegistry("mirror.example.com:5000")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, result.String())
+		})
+	}
+}
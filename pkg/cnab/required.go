@@ -7,13 +7,18 @@ type RequiredExtension struct {
 	Shorthand string
 	Key       string
 	Schema    string
-	Reader    func(b ExtendedBundle) (interface{}, error)
+
+	// Description explains what the extension allows the bundle to do.
+	Description string
+
+	Reader func(b ExtendedBundle) (interface{}, error)
 }
 
 // SupportedExtensions represent a listing of the current required extensions
 // that Porter supports
 var SupportedExtensions = []RequiredExtension{
 	DependenciesV1Extension,
+	DependenciesV2Extension,
 	DockerExtension,
 	FileParameterExtension,
 	ParameterSourcesExtension,
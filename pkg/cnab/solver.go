@@ -12,13 +12,32 @@ import (
 type DependencyLock struct {
 	Alias     string
 	Reference string
+
+	// SharingGroup is the name of the sharing group when the dependency may be
+	// satisfied by an existing installation. Only set with dependencies v2.
+	SharingGroup string
+
+	// Parameters passed to the dependency, which may reference the parameters of the
+	// bundle or the outputs of other dependencies. Only set with dependencies v2.
+	Parameters map[string]string
+}
+
+// IsShared determines if the dependency may be satisfied by an existing installation.
+func (l DependencyLock) IsShared() bool {
+	return l.SharingGroup != ""
 }
 
 // TODO: move this logic onto the new ExtendedBundle struct
 type DependencySolver struct {
+	// ListTags returns the tags of a repository. Defaults to listing the tags in the registry.
+	ListTags func(repository string) ([]string, error)
 }
 
 func (s *DependencySolver) ResolveDependencies(bun ExtendedBundle) ([]DependencyLock, error) {
+	if bun.HasDependenciesV2() {
+		return s.resolveDependenciesV2(bun)
+	}
+
 	if !bun.HasDependenciesV1() {
 		return nil, nil
 	}
@@ -48,6 +67,40 @@ func (s *DependencySolver) ResolveDependencies(bun ExtendedBundle) ([]Dependency
 	return q, nil
 }
 
+func (s *DependencySolver) resolveDependenciesV2(bun ExtendedBundle) ([]DependencyLock, error) {
+	rawDeps, err := bun.ReadDependenciesV2()
+	if err != nil {
+		return nil, fmt.Errorf("error executing dependencies for %s: %w", bun.Name, err)
+	}
+
+	orderedDeps := rawDeps.ListBySequence()
+	q := make([]DependencyLock, 0, len(orderedDeps))
+	for _, dep := range orderedDeps {
+		// Resolve the version the same way for both versions of the dependencies extension
+		// The version range determines if prereleases are allowed, e.g. ^1.0.0-0
+		depV1 := depsv1.Dependency{Name: dep.Name, Bundle: dep.Bundle}
+		if dep.Version != "" {
+			depV1.Version = &depsv1.DependencyVersion{Ranges: []string{dep.Version}, AllowPrereleases: true}
+		}
+		ref, err := s.ResolveVersion(dep.Name, depV1)
+		if err != nil {
+			return nil, err
+		}
+
+		lock := DependencyLock{
+			Alias:      dep.Name,
+			Reference:  ref.String(),
+			Parameters: dep.Parameters,
+		}
+		if dep.Sharing.IsShared() {
+			lock.SharingGroup = dep.Sharing.Group.Name
+		}
+		q = append(q, lock)
+	}
+
+	return q, nil
+}
+
 // ResolveVersion returns the bundle name, its version and any error.
 func (s *DependencySolver) ResolveVersion(name string, dep depsv1.Dependency) (OCIReference, error) {
 	ref, err := ParseOCIReference(dep.Bundle)
@@ -70,11 +123,70 @@ func (s *DependencySolver) ResolveVersion(name string, dep depsv1.Dependency) (O
 		return ref.WithTag(tag)
 	}
 
-	return OCIReference{}, fmt.Errorf("not implemented: dependency version range specified for %s: %w", name, err)
+	tag, err := s.determineTagInRange(name, dep)
+	if err != nil {
+		return OCIReference{}, err
+	}
+	return ref.WithTag(tag)
+}
+
+func (s *DependencySolver) listTags(repository string) ([]string, error) {
+	if s.ListTags != nil {
+		return s.ListTags(repository)
+	}
+	return crane.ListTags(repository)
+}
+
+// determineTagInRange returns the tag of the highest version of the bundle
+// that satisfies all of the version ranges of the dependency.
+func (s *DependencySolver) determineTagInRange(name string, dep depsv1.Dependency) (string, error) {
+	constraints := make([]*semver.Constraints, 0, len(dep.Version.Ranges))
+	for _, r := range dep.Version.Ranges {
+		c, err := semver.NewConstraint(r)
+		if err != nil {
+			return "", fmt.Errorf("invalid version range %q for dependency %s: %w", r, name, err)
+		}
+		constraints = append(constraints, c)
+	}
+
+	tags, err := s.listTags(dep.Bundle)
+	if err != nil {
+		return "", fmt.Errorf("error listing tags for %s: %w", dep.Bundle, err)
+	}
+
+	versions := make(semver.Collection, 0, len(tags))
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if !dep.Version.AllowPrereleases && version.Prerelease() != "" {
+			continue
+		}
+
+		satisfied := true
+		for _, c := range constraints {
+			if !c.Check(version) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			versions = append(versions, version)
+		}
+	}
+
+	if len(versions) == 0 {
+		return "", fmt.Errorf("none of the tags defined in the registry for %s satisfy the version range %v of dependency %s", dep.Bundle, dep.Version.Ranges, name)
+	}
+
+	sort.Sort(sort.Reverse(versions))
+
+	return versions[0].Original(), nil
 }
 
 func (s *DependencySolver) determineDefaultTag(dep depsv1.Dependency) (string, error) {
-	tags, err := crane.ListTags(dep.Bundle)
+	tags, err := s.listTags(dep.Bundle)
 	if err != nil {
 		return "", fmt.Errorf("error listing tags for %s: %w", dep.Bundle, err)
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
+	depsv2 "get.porter.sh/porter/pkg/cnab/dependencies/v2"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -59,9 +60,24 @@ func TestDependencySolver_ResolveVersion(t *testing.T) {
 		{name: "pinned version",
 			dep:         depsv1.Dependency{Bundle: "mysql:5.7"},
 			wantVersion: "5.7"},
-		{name: "unimplemented range",
-			dep:       depsv1.Dependency{Bundle: "mysql", Version: &depsv1.DependencyVersion{Ranges: []string{"1 - 1.5"}}},
-			wantError: "not implemented"},
+		{name: "version range",
+			dep:         depsv1.Dependency{Bundle: "mysql", Version: &depsv1.DependencyVersion{Ranges: []string{"1 - 1.5"}}},
+			wantVersion: "v1.5.0"},
+		{name: "multiple version ranges",
+			dep:         depsv1.Dependency{Bundle: "mysql", Version: &depsv1.DependencyVersion{Ranges: []string{"^1.0", "<1.3"}}},
+			wantVersion: "1.2.1"},
+		{name: "version range with prereleases",
+			dep:         depsv1.Dependency{Bundle: "mysql", Version: &depsv1.DependencyVersion{Ranges: []string{">= 2.0.0-0"}, AllowPrereleases: true}},
+			wantVersion: "2.0.0-beta1"},
+		{name: "version range excluding prereleases",
+			dep:         depsv1.Dependency{Bundle: "mysql", Version: &depsv1.DependencyVersion{Ranges: []string{">= 1.0"}}},
+			wantVersion: "1.6"},
+		{name: "unsatisfied version range",
+			dep:       depsv1.Dependency{Bundle: "mysql", Version: &depsv1.DependencyVersion{Ranges: []string{"^3.0"}}},
+			wantError: "none of the tags defined in the registry for mysql satisfy the version range [^3.0] of dependency mysql"},
+		{name: "invalid version range",
+			dep:       depsv1.Dependency{Bundle: "mysql", Version: &depsv1.DependencyVersion{Ranges: []string{"latest"}}},
+			wantError: `invalid version range "latest" for dependency mysql`},
 		{name: "default tag to latest",
 			dep:         depsv1.Dependency{Bundle: "getporterci/porter-test-only-latest"},
 			wantVersion: "latest"},
@@ -80,11 +96,16 @@ func TestDependencySolver_ResolveVersion(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
+		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			tc := tc
 
 			s := DependencySolver{}
+			if tc.dep.Bundle == "mysql" {
+				s.ListTags = func(repository string) ([]string, error) {
+					return []string{"latest", "1.0.0", "1.2.1", "v1.5.0", "1.6", "2.0.0-beta1", "notsemver"}, nil
+				}
+			}
 			version, err := s.ResolveVersion("mysql", tc.dep)
 
 			if tc.wantError != "" {
@@ -98,3 +119,42 @@ func TestDependencySolver_ResolveVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestDependencySolver_ResolveDependenciesV2(t *testing.T) {
+	t.Parallel()
+
+	bun := NewBundle(bundle.Bundle{
+		Custom: map[string]interface{}{
+			DependenciesV2ExtensionKey: depsv2.Dependencies{
+				Sequence: []string{"mysql", "app"},
+				Requires: map[string]depsv2.Dependency{
+					"mysql": {
+						Bundle:  "getporter/mysql",
+						Version: "~1.2",
+						Sharing: depsv2.SharingCriteria{Mode: depsv2.SharingModeGroup, Group: depsv2.SharingGroup{Name: "myapp"}},
+					},
+					"app": {
+						Bundle:     "getporter/app:v1.0.0",
+						Parameters: map[string]string{"connstr": "${ bundle.dependencies.mysql.outputs.connstr }"},
+					},
+				},
+			},
+		},
+	})
+
+	s := DependencySolver{
+		ListTags: func(repository string) ([]string, error) {
+			return []string{"1.1.0", "1.2.0", "1.2.3", "1.3.0"}, nil
+		},
+	}
+	locks, err := s.ResolveDependencies(bun)
+	require.NoError(t, err)
+
+	wantLocks := []DependencyLock{
+		{Alias: "mysql", Reference: "getporter/mysql:1.2.3", SharingGroup: "myapp"},
+		{Alias: "app", Reference: "getporter/app:v1.0.0", Parameters: map[string]string{"connstr": "${ bundle.dependencies.mysql.outputs.connstr }"}},
+	}
+	assert.Equal(t, wantLocks, locks)
+	assert.True(t, locks[0].IsShared(), "the mysql dependency should be shared")
+	assert.False(t, locks[1].IsShared(), "the app dependency should not be shared")
+}
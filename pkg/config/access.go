@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os/user"
+)
+
+const (
+	// AccessProviderLocal authorizes access with the policies defined in the configuration file.
+	AccessProviderLocal = "local"
+
+	// AccessProviderWebhook authorizes access by calling an external service.
+	AccessProviderWebhook = "webhook"
+
+	// AccessActionRead allows resources to be listed and retrieved.
+	AccessActionRead = "read"
+
+	// AccessActionWrite allows resources to be created and modified.
+	AccessActionWrite = "write"
+
+	// AccessActionDelete allows resources to be deleted.
+	AccessActionDelete = "delete"
+)
+
+// AccessConfig are settings related to which namespaces an identity may access
+// when several teams share the same storage. By default, all namespaces may be accessed.
+type AccessConfig struct {
+	// Provider that authorizes access to the resources in a namespace.
+	// Available values are: local, webhook. Defaults to local.
+	Provider string `mapstructure:"provider"`
+
+	// Identity of the user running Porter. Defaults to the name of the current user.
+	// Do not use directly, use AccessConfig.GetIdentity.
+	Identity string `mapstructure:"identity"`
+
+	// Policies define the namespaces each identity may access, and are used by the local provider.
+	Policies []AccessPolicy `mapstructure:"policies"`
+
+	// Webhook is the service that authorizes access, and is used by the webhook provider.
+	Webhook AccessWebhookConfig `mapstructure:"webhook"`
+}
+
+// AccessPolicy allows an identity to perform actions on the resources in a set of namespaces.
+type AccessPolicy struct {
+	// Identity that the policy applies to. Use * to apply the policy to all identities.
+	Identity string `mapstructure:"identity"`
+
+	// Namespaces that may be accessed. Patterns such as team-a-* are supported,
+	// use * for all namespaces and an empty string for only the global namespace.
+	Namespaces []string `mapstructure:"namespaces"`
+
+	// Actions that are allowed: read, write, delete. Defaults to all actions.
+	Actions []string `mapstructure:"actions"`
+}
+
+// AccessWebhookConfig are the settings for calling an external service to authorize access.
+type AccessWebhookConfig struct {
+	// URL that is sent a POST request with the identity, action, collection and
+	// namespace to authorize. Access is allowed when it responds with a 2xx status code.
+	URL string `mapstructure:"url"`
+
+	// Token is sent as a bearer token to the service.
+	// Use ${secret.NAME} or ${env.NAME} so that the token is not stored in the configuration file.
+	Token string `mapstructure:"token"`
+
+	// Timeout in seconds to wait for the service to respond. Defaults to 10 seconds.
+	Timeout int `mapstructure:"timeout"`
+}
+
+// GetProvider returns the configured access provider, defaulting to local.
+func (c AccessConfig) GetProvider() string {
+	if c.Provider == "" {
+		return AccessProviderLocal
+	}
+	return c.Provider
+}
+
+// IsEnabled determines if access to namespaces should be authorized.
+func (c AccessConfig) IsEnabled() bool {
+	if c.GetProvider() == AccessProviderWebhook {
+		return c.Webhook.URL != ""
+	}
+	return len(c.Policies) > 0
+}
+
+// GetIdentity returns the identity of the user running Porter, defaulting to
+// the name of the current user.
+func (c AccessConfig) GetIdentity() string {
+	if c.Identity != "" {
+		return c.Identity
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
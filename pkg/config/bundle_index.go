@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// BundleIndexTypeHTTP is a bundle index that is downloaded from a URL.
+	BundleIndexTypeHTTP = "http"
+
+	// BundleIndexTypeOCI is a bundle index that is published to a registry as an OCI artifact.
+	BundleIndexTypeOCI = "oci"
+
+	// BundleIndexTypeGit is a bundle index that is stored in a git repository.
+	BundleIndexTypeGit = "git"
+)
+
+// BundleIndex is a source of bundle listings that is searched by porter search.
+type BundleIndex struct {
+	// Name of the index, used to identify where a search result came from.
+	Name string `mapstructure:"name"`
+
+	// Type of the index. Available values are: http, oci, git.
+	Type string `mapstructure:"type"`
+
+	// URL of the index for the http type, or of the git repository for the git type.
+	URL string `mapstructure:"url"`
+
+	// Reference to the OCI artifact that contains the index, for the oci type.
+	Reference string `mapstructure:"reference"`
+
+	// Path to the index in the git repository, for the git type. Defaults to index.json.
+	Path string `mapstructure:"path"`
+
+	// Branch of the git repository that contains the index, for the git type.
+	// Defaults to the default branch of the repository.
+	Branch string `mapstructure:"branch"`
+}
+
+// GetPath returns the path to the index in a git repository, defaulting to index.json.
+func (i BundleIndex) GetPath() string {
+	if i.Path == "" {
+		return "index.json"
+	}
+	return i.Path
+}
+
+// Validate the bundle index configuration.
+func (i BundleIndex) Validate() error {
+	if i.Name == "" {
+		return errors.New("bundle-indexes.name is required")
+	}
+
+	switch i.Type {
+	case BundleIndexTypeHTTP, BundleIndexTypeGit:
+		if i.URL == "" {
+			return fmt.Errorf("bundle-indexes.url is required for the %s index", i.Name)
+		}
+	case BundleIndexTypeOCI:
+		if i.Reference == "" {
+			return fmt.Errorf("bundle-indexes.reference is required for the %s index", i.Name)
+		}
+	default:
+		return fmt.Errorf("invalid bundle-indexes.type %q for the %s index, allowed values are: %s, %s, %s", i.Type, i.Name, BundleIndexTypeHTTP, BundleIndexTypeOCI, BundleIndexTypeGit)
+	}
+	return nil
+}
+
+// BundleIndexes is the list of bundle indexes defined in the configuration file.
+type BundleIndexes []BundleIndex
+
+// Validate every bundle index, and that each index has a unique name.
+func (l BundleIndexes) Validate() error {
+	names := make(map[string]bool, len(l))
+	for _, i := range l {
+		if err := i.Validate(); err != nil {
+			return err
+		}
+		if names[i.Name] {
+			return fmt.Errorf("multiple bundle-indexes are named %s", i.Name)
+		}
+		names[i.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleIndex_GetPath(t *testing.T) {
+	assert.Equal(t, "index.json", BundleIndex{}.GetPath())
+	assert.Equal(t, "bundles/index.json", BundleIndex{Path: "bundles/index.json"}.GetPath())
+}
+
+func TestBundleIndexes_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		indexes BundleIndexes
+		wantErr string
+	}{
+		{name: "valid", indexes: BundleIndexes{
+			{Name: "community", Type: BundleIndexTypeHTTP, URL: "https://cdn.porter.sh/bundles/index.json"},
+			{Name: "internal", Type: BundleIndexTypeOCI, Reference: "example.com/bundle-index:latest"},
+			{Name: "team", Type: BundleIndexTypeGit, URL: "https://github.com/example/bundles.git", Branch: "main"},
+		}},
+		{name: "missing name", indexes: BundleIndexes{{Type: BundleIndexTypeHTTP, URL: "https://example.com"}}, wantErr: "bundle-indexes.name is required"},
+		{name: "invalid type", indexes: BundleIndexes{{Name: "team", Type: "svn"}}, wantErr: `invalid bundle-indexes.type "svn" for the team index`},
+		{name: "http missing url", indexes: BundleIndexes{{Name: "community", Type: BundleIndexTypeHTTP}}, wantErr: "bundle-indexes.url is required for the community index"},
+		{name: "git missing url", indexes: BundleIndexes{{Name: "team", Type: BundleIndexTypeGit}}, wantErr: "bundle-indexes.url is required for the team index"},
+		{name: "oci missing reference", indexes: BundleIndexes{{Name: "internal", Type: BundleIndexTypeOCI}}, wantErr: "bundle-indexes.reference is required for the internal index"},
+		{name: "duplicate name", indexes: BundleIndexes{
+			{Name: "community", Type: BundleIndexTypeHTTP, URL: "https://cdn.porter.sh/bundles/index.json"},
+			{Name: "community", Type: BundleIndexTypeOCI, Reference: "example.com/bundle-index:latest"},
+		}, wantErr: "multiple bundle-indexes are named community"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.indexes.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
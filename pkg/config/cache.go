@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/docker/go-units"
+)
+
+// CacheConfig are settings related to the local bundle cache in
+// PORTER_HOME/cache. By default, the cache grows without limit.
+type CacheConfig struct {
+	// MaxSize is the maximum size of the bundle cache, for example 500MB or 2GB.
+	// When the cache is larger, the least recently used bundles are evicted.
+	// An empty value does not limit the size of the cache.
+	// Do not use directly, use CacheConfig.GetMaxSize.
+	MaxSize string `mapstructure:"max-size"`
+}
+
+// GetMaxSize returns the maximum size of the bundle cache in bytes.
+// Zero is returned when the size of the cache is not limited.
+func (c CacheConfig) GetMaxSize() (int64, error) {
+	return ParseCacheSize(c.MaxSize)
+}
+
+// ParseCacheSize parses a size of the bundle cache, such as 500MB or 2GB, into
+// bytes. The units are powers of 1024 and the B suffix is optional.
+func ParseCacheSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	size, err := units.RAMInBytes(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max size %s: %w", value, err)
+	}
+	return size, nil
+}
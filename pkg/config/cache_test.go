@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCacheSize(t *testing.T) {
+	testcases := []struct {
+		value   string
+		want    int64
+		wantErr string
+	}{
+		{value: "", want: 0},
+		{value: "512", want: 512},
+		{value: "500MB", want: 500 * 1024 * 1024},
+		{value: "2g", want: 2 * 1024 * 1024 * 1024},
+		{value: "big", wantErr: "invalid max size big"},
+		{value: "-1GB", wantErr: "invalid max size -1GB"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.value, func(t *testing.T) {
+			size, err := ParseCacheSize(tc.value)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, size)
+		})
+	}
+}
@@ -55,6 +55,32 @@ const (
 	// invocation image, containing the name of the installation.
 	EnvPorterInstallationName = "PORTER_INSTALLATION_NAME"
 
+	// EnvPorterLogFormat is the name of the environment variable which is injected into the
+	// invocation image, containing the format of the logs generated by the bundle.
+	EnvPorterLogFormat = "PORTER_LOG_FORMAT"
+
+	// EnvPorterRetries is the name of the environment variable which is injected into the
+	// invocation image, containing how many times a failed step is retried. Overrides the
+	// retry policy defined in the bundle.
+	EnvPorterRetries = "PORTER_RETRIES"
+
+	// EnvPorterRetryBackoff is the name of the environment variable which is injected into the
+	// invocation image, containing how long to wait before retrying a failed step. Overrides the
+	// retry policy defined in the bundle.
+	EnvPorterRetryBackoff = "PORTER_RETRY_BACKOFF"
+
+	// EnvPorterTimeout is the name of the environment variable which is injected into the
+	// invocation image, containing how long the action may run. Overrides the timeout
+	// policy defined in the bundle.
+	EnvPorterTimeout = "PORTER_TIMEOUT"
+
+	// LogFormatText writes the output of the bundle as is.
+	LogFormatText = "text"
+
+	// LogFormatJSON writes the output of the bundle as JSON lines, with a record
+	// for each line of output.
+	LogFormatJSON = "json"
+
 	// DefaultVerbosity is the default value for the --verbosity flag.
 	DefaultVerbosity = "info"
 )
@@ -89,6 +115,10 @@ type Config struct {
 	// for example: secret.NAME, or env.NAME
 	templateVariables []string
 
+	// values of the secrets used in the config file, so that they can be masked
+	// when the configuration is printed
+	secretValues []string
+
 	// the populated viper instance that loaded the current configuration
 	viper *viper.Viper
 }
@@ -111,6 +141,7 @@ func (c *Config) NewLogConfiguration() portercontext.LogConfiguration {
 	return portercontext.LogConfiguration{
 		Verbosity:               c.GetVerbosity().Level(),
 		StructuredLogs:          c.Data.Logs.Structured,
+		JsonLogs:                c.Data.Logs.Format == LogFormatJson,
 		LogToFile:               c.Data.Logs.LogToFile,
 		LogDirectory:            filepath.Join(c.porterHome, "logs"),
 		LogLevel:                c.Data.Logs.Level.Level(),
@@ -316,7 +347,11 @@ func (c *Config) GetBuildDriver() string {
 }
 
 // GetVerbosity converts the user-specified verbosity flag into a LogLevel enum.
+// Only errors are printed to the console when --quiet is specified.
 func (c *Config) GetVerbosity() LogLevel {
+	if c.Data.Quiet {
+		return LogLevelError
+	}
 	return ParseLogLevel(c.Data.Verbosity)
 }
 
@@ -357,7 +392,38 @@ func (c *Config) loadFirstPass(ctx context.Context) (context.Context, error) {
 	templateData := map[string]interface{}{
 		"env": c.EnvironMap(),
 	}
-	return c.loadData(ctx, templateData)
+	ctx, err := c.loadData(ctx, templateData)
+	if err != nil {
+		return ctx, err
+	}
+
+	// An environment variable that is not set is rendered as an empty string,
+	// warn about it so that a missing variable is not mistaken for a bug.
+	// Use the logger configured by the config file.
+	ctxLog := tracing.LoggerFromContext(ctx)
+	for _, name := range c.findUnsetEnvironmentVariables() {
+		ctxLog.Warnf("The config file uses ${env.%s} but the %s environment variable is not set. Set the environment variable, or specify a default value with ${env.%s | default: \"VALUE\"}", name, name, name)
+	}
+
+	return ctx, nil
+}
+
+// findUnsetEnvironmentVariables returns the names of the environment variables
+// that are used in the config file without a default value, but are not set.
+func (c *Config) findUnsetEnvironmentVariables() []string {
+	var unset []string
+	for _, variable := range c.templateVariables {
+		name, hasFilter := parseTemplateVariable(variable)
+		envVar := strings.TrimPrefix(name, "env.")
+		if envVar == name || hasFilter {
+			continue
+		}
+
+		if _, ok := c.LookupEnv(envVar); !ok {
+			unset = append(unset, envVar)
+		}
+	}
+	return unset
 }
 
 func (c *Config) loadFinalPass(ctx context.Context, resolveSecret func(ctx context.Context, secretKey string) (string, error)) (context.Context, error) {
@@ -373,14 +439,12 @@ func (c *Config) loadFinalPass(ctx context.Context, resolveSecret func(ctx conte
 	for _, variable := range c.templateVariables {
 		err := func(variable string) error {
 			// Check if it's a secret variable, e.g. ${secret.NAME}
-			secretPrefix := "secret."
-			i := strings.Index(variable, secretPrefix)
-			if i == -1 {
+			name, _ := parseTemplateVariable(variable)
+			secretKey := strings.TrimPrefix(name, "secret.")
+			if secretKey == name {
 				return nil
 			}
 
-			secretKey := variable[len(secretPrefix):]
-
 			ctx, childLog := log.StartSpanWithName("resolveSecret", attribute.String("porter.config.secret.key", secretKey))
 			defer childLog.EndSpan()
 			secretValue, err := resolveSecret(ctx, secretKey)
@@ -396,6 +460,11 @@ func (c *Config) loadFinalPass(ctx context.Context, resolveSecret func(ctx conte
 		}
 	}
 
+	c.secretValues = make([]string, 0, len(secrets))
+	for _, value := range secrets {
+		c.secretValues = append(c.secretValues, value)
+	}
+
 	templateData := map[string]interface{}{
 		"env":    c.EnvironMap(),
 		"secret": secrets,
@@ -82,6 +82,15 @@ func TestConfigExperimentalFlags(t *testing.T) {
 	})
 }
 
+func TestConfig_GetVerbosity(t *testing.T) {
+	c := NewTestConfig(t)
+	c.Data.Verbosity = "debug"
+	require.Equal(t, LogLevelDebug, c.GetVerbosity())
+
+	c.Data.Quiet = true
+	require.Equal(t, LogLevelError, c.GetVerbosity(), "only errors should be printed when --quiet is specified")
+}
+
 func TestConfig_GetBuildDriver(t *testing.T) {
 	c := NewTestConfig(t)
 	c.Data.BuildDriver = "special"
@@ -110,3 +119,14 @@ func TestConfig_ExportRemoteConfigAsEnvironmentVariables(t *testing.T) {
 	}
 	assert.Equal(t, wantEnvVars, gotEnvVars)
 }
+
+func TestConfig_CheckNetworkAccess(t *testing.T) {
+	c := NewTestConfig(t)
+
+	require.NoError(t, c.CheckNetworkAccess("pull the bundle example.com/mybuns:v1.0.0"))
+
+	c.Data.Offline = true
+	err := c.CheckNetworkAccess("pull the bundle example.com/mybuns:v1.0.0")
+	require.ErrorIs(t, err, ErrOffline)
+	assert.Contains(t, err.Error(), "cannot pull the bundle example.com/mybuns:v1.0.0: network access is not allowed in offline mode")
+}
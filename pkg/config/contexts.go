@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvContext is the environment variable that selects the current context,
+// overriding current-context in the configuration file.
+const EnvContext = "PORTER_CONTEXT"
+
+// ContextConfig is a named set of configuration settings, such as the storage,
+// secrets plugin, default namespace and registry settings used in an
+// environment. The settings of the current context are layered over the rest
+// of the configuration file.
+type ContextConfig struct {
+	// Name of the context.
+	Name string `mapstructure:"name"`
+
+	// Settings of the context, using the same keys as the configuration file,
+	// for example default-storage or namespace.
+	Settings map[string]interface{} `mapstructure:",remain"`
+}
+
+// GetContext returns the context with the specified name.
+func (d Data) GetContext(name string) (ContextConfig, bool) {
+	for _, c := range d.Contexts {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ContextConfig{}, false
+}
+
+// applyContext layers the settings of the current context over the settings
+// from the configuration file. Environment variables and flags still take
+// precedence over the settings of the context.
+func applyContext(v *viper.Viper) error {
+	name := v.GetString("current-context")
+	if name == "" {
+		return nil
+	}
+
+	var contexts []ContextConfig
+	if err := v.UnmarshalKey("contexts", &contexts); err != nil {
+		return fmt.Errorf("error reading the contexts defined in the config file: %w", err)
+	}
+
+	names := make([]string, 0, len(contexts))
+	for _, c := range contexts {
+		if c.Name != name {
+			names = append(names, c.Name)
+			continue
+		}
+
+		settings := make(map[string]interface{}, len(c.Settings))
+		for key, value := range c.Settings {
+			// A context cannot select another context
+			if key == "contexts" || key == "current-context" {
+				continue
+			}
+			settings[key] = value
+		}
+		return v.MergeConfigMap(settings)
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("the current context %s is not defined in the config file because it does not define any contexts", name)
+	}
+	return fmt.Errorf("the current context %s is not defined in the config file, available contexts are: %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_CurrentContext(t *testing.T) {
+	testcases := []struct {
+		name            string
+		env             map[string]string
+		wantContext     string
+		wantNamespace   string
+		wantStorage     string
+		wantSecrets     string
+		wantMirrorCount int
+		wantErr         string
+	}{
+		{name: "config file", wantContext: "prod", wantNamespace: "prod", wantStorage: "proddb", wantSecrets: "vault", wantMirrorCount: 1},
+		{name: "env override", env: map[string]string{EnvContext: "dev"}, wantContext: "dev", wantNamespace: "dev", wantStorage: "devdb"},
+		{name: "env setting wins over context", env: map[string]string{"PORTER_NAMESPACE": "test"}, wantContext: "prod", wantNamespace: "test", wantStorage: "proddb", wantSecrets: "vault", wantMirrorCount: 1},
+		{name: "undefined context", env: map[string]string{EnvContext: "staging"}, wantErr: "the current context staging is not defined in the config file, available contexts are: dev, prod"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Do not run in parallel, it sets environment variables
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			c := NewTestConfig(t)
+			c.SetHomeDir("/home/myuser/.porter")
+			c.TestContext.AddTestFile("testdata/contexts.yaml", "/home/myuser/.porter/config.yaml")
+			c.DataLoader = LoadFromEnvironment()
+
+			_, err := c.Load(context.Background(), nil)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "/home/myuser/.porter/config.yaml", c.ConfigFilePath)
+			assert.Equal(t, tc.wantContext, c.Data.CurrentContext)
+			assert.Equal(t, tc.wantNamespace, c.Data.Namespace)
+			assert.Equal(t, tc.wantStorage, c.Data.DefaultStorage)
+			assert.Equal(t, tc.wantSecrets, c.Data.DefaultSecrets)
+			require.Len(t, c.Data.RegistryMirrors, tc.wantMirrorCount)
+			if tc.wantMirrorCount > 0 {
+				assert.Equal(t, "mirror.example.com", c.Data.RegistryMirrors[0].Mirror)
+			}
+			assert.Equal(t, "warn", c.Data.Verbosity, "settings that are not defined by the context should be kept")
+			require.Len(t, c.Data.Contexts, 2)
+			assert.Equal(t, "prod", c.Data.Contexts[1].Name)
+		})
+	}
+}
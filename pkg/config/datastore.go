@@ -27,7 +27,7 @@ type Data struct {
 	BuildDriver string `mapstructure:"build-driver"`
 
 	// RuntimeDriver is the driver to use when executing bundles.
-	// Available values are: docker, kubernetes.
+	// Available values are: docker, podman, containerd, kubernetes, debug.
 	// It is both a global variable and a command flag because some of our commands, like porter installation apply,
 	// do not expose all the bundle execution flags. This allows us to later manually use the global config value
 	// to ensure that the global config value works even for those commands.
@@ -67,15 +67,71 @@ type Data struct {
 	// Namespace is the default namespace for commands that do not override it with a flag.
 	Namespace string `mapstructure:"namespace"`
 
+	// CurrentContext is the name of the context whose settings are layered over
+	// the rest of the configuration file. It is overridden by PORTER_CONTEXT.
+	CurrentContext string `mapstructure:"current-context"`
+
+	// Contexts defined in the configuration file.
+	Contexts []ContextConfig `mapstructure:"contexts"`
+
 	// SecretsPlugin defined in the configuration file.
 	SecretsPlugin []SecretsPlugin `mapstructure:"secrets"`
 
+	// SecretsChain is an ordered list of sources that are tried when a secret
+	// is resolved, instead of only the default secrets plugin. A source that
+	// fails or does not respond in time is skipped.
+	SecretsChain []SecretsSource `mapstructure:"secrets-chain"`
+
 	// Logs are settings related to Porter's log files.
 	Logs LogConfig `mapstructure:"logs"`
 
 	// Telemetry are settings related to Porter's tracing with open telemetry.
 	Telemetry TelemetryConfig `mapstructure:"telemetry"`
 
+	// Encryption are settings related to encrypting sensitive data before it is saved to storage.
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+
+	// Access are settings related to which namespaces may be accessed when several teams share the same storage.
+	Access AccessConfig `mapstructure:"access"`
+
+	// Outputs are settings related to saving the value of large outputs outside of Porter's database.
+	Outputs OutputsConfig `mapstructure:"outputs"`
+
+	// History are settings related to how long the run history of an installation is kept.
+	History HistoryConfig `mapstructure:"history"`
+
+	// Cache are settings related to the local bundle cache.
+	Cache CacheConfig `mapstructure:"cache"`
+
+	// Signing are settings related to signing bundles when they are published and verifying their signatures.
+	Signing SigningConfig `mapstructure:"signing"`
+
+	// VerifySignatures requires bundles, and their invocation images, to have a
+	// trusted signature before they are executed.
+	VerifySignatures bool `mapstructure:"verify-signatures"`
+
+	// Scan are settings related to scanning bundle images for vulnerabilities.
+	Scan ScanConfig `mapstructure:"scan"`
+
+	// Docker are settings related to the invocation container run by the docker runtime driver.
+	Docker DockerConfig `mapstructure:"docker"`
+
+	// Kubernetes are settings related to executing bundles with the kubernetes runtime driver.
+	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
+
+	// AgentToken is the token that clients must present to the agent started
+	// with porter agent serve. Clients use the same setting when --remote is specified.
+	AgentToken string `mapstructure:"agent-token"`
+
+	// RegistryMirrors are mirrors used when pulling bundles and images from a registry.
+	RegistryMirrors RegistryMirrors `mapstructure:"registry-mirrors"`
+
+	// RegistryTLS are the TLS settings used when connecting to specific registries.
+	RegistryTLS RegistryTLSConfigs `mapstructure:"registry-tls"`
+
+	// BundleIndexes are searched by porter search, instead of the community bundle index.
+	BundleIndexes BundleIndexes `mapstructure:"bundle-indexes"`
+
 	// SchemaCheck specifies how strict Porter should be when comparing the
 	// schemaVersion field on a resource with the supported schemaVersion.
 	// Supported values are: exact, minor, major, none.
@@ -85,6 +141,19 @@ type Data struct {
 	// Use Logs.LogLevel if you want to change what is output to the logfile.
 	// Traces sent to an OpenTelemetry collector always include all levels of messages.
 	Verbosity string `mapstructure:"verbosity"`
+
+	// NoColor disables colored output in the console.
+	// Colors are also disabled when the NO_COLOR environment variable is set.
+	NoColor bool `mapstructure:"no-color"`
+
+	// Quiet suppresses informational messages, leaving only errors and the
+	// output requested from a command, so that it can be consumed by scripts.
+	Quiet bool `mapstructure:"quiet"`
+
+	// Offline prevents Porter from accessing registries and feeds, resolving
+	// bundles from the local bundle cache instead. Operations that need the
+	// network fail instead of silently reaching out to it.
+	Offline bool `mapstructure:"offline"`
 }
 
 // DefaultDataStore used when no config file is found.
@@ -92,7 +161,7 @@ func DefaultDataStore() Data {
 	return Data{
 		BuildDriver:          BuildDriverBuildkit,
 		RuntimeDriver:        RuntimeDriverDocker,
-		DefaultStoragePlugin: "mongodb-docker",
+		DefaultStoragePlugin: "bolt",
 		DefaultSecretsPlugin: "host",
 		Logs:                 LogConfig{Level: "info"},
 		Verbosity:            DefaultVerbosity,
@@ -0,0 +1,41 @@
+package config
+
+// DockerConfig are settings related to the invocation container that is run
+// by the docker runtime driver. The --driver-opt flag overrides these settings.
+type DockerConfig struct {
+	// CPUs is the number of CPUs that the container may use, for example 1.5.
+	CPUs string `mapstructure:"cpus"`
+
+	// Memory is the maximum amount of memory that the container may use, for example 4g.
+	Memory string `mapstructure:"memory"`
+
+	// Network is the network mode of the container, for example host, or the name of a docker network.
+	Network string `mapstructure:"network"`
+
+	// Mounts are additional volumes mounted in the container, in the form SOURCE:TARGET[:ro].
+	Mounts []string `mapstructure:"mounts"`
+
+	// Env are the names of environment variables that are passed through to the container.
+	Env []string `mapstructure:"env"`
+}
+
+// GetDriverOptions returns the settings in the KEY=VALUE format used by the --driver-opt flag.
+func (c DockerConfig) GetDriverOptions() []string {
+	var opts []string
+	if c.CPUs != "" {
+		opts = append(opts, "cpus="+c.CPUs)
+	}
+	if c.Memory != "" {
+		opts = append(opts, "memory="+c.Memory)
+	}
+	if c.Network != "" {
+		opts = append(opts, "network="+c.Network)
+	}
+	for _, m := range c.Mounts {
+		opts = append(opts, "mount="+m)
+	}
+	for _, e := range c.Env {
+		opts = append(opts, "env="+e)
+	}
+	return opts
+}
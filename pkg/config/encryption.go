@@ -0,0 +1,62 @@
+package config
+
+const (
+	// EncryptionProviderLocal wraps data keys with a key specified directly in the configuration file.
+	EncryptionProviderLocal = "local"
+
+	// EncryptionProviderVault wraps data keys with a key held by the HashiCorp Vault transit secrets engine.
+	EncryptionProviderVault = "vault"
+)
+
+// EncryptionConfig are settings related to how Porter encrypts sensitive data
+// before it is saved to storage.
+type EncryptionConfig struct {
+	// Provider that wraps the data keys used to encrypt sensitive data.
+	// Available values are: local, vault. Defaults to local.
+	Provider string `mapstructure:"provider"`
+
+	// Key is the key encryption key used by the local provider to wrap data keys.
+	// Use ${secret.NAME} or ${env.NAME} so that the key is not stored in the configuration file.
+	// Sensitive data is only encrypted when a key is specified.
+	Key string `mapstructure:"key"`
+
+	// Vault is the transit secrets engine that wraps data keys, and is used by the vault provider.
+	Vault EncryptionVaultConfig `mapstructure:"vault"`
+}
+
+// EncryptionVaultConfig are the settings for wrapping data keys with the
+// HashiCorp Vault transit secrets engine.
+type EncryptionVaultConfig struct {
+	// Address of the Vault server, e.g. https://vault.example.com:8200.
+	Address string `mapstructure:"address"`
+
+	// Token used to authenticate with Vault.
+	// Use ${secret.NAME} or ${env.NAME} so that the token is not stored in the configuration file.
+	Token string `mapstructure:"token"`
+
+	// Mount path of the transit secrets engine. Defaults to transit.
+	Mount string `mapstructure:"mount"`
+
+	// Key is the name of the transit key that wraps data keys.
+	// Sensitive data is only encrypted when a key is specified.
+	Key string `mapstructure:"key"`
+
+	// Timeout in seconds to wait for Vault to respond. Defaults to 10 seconds.
+	Timeout int `mapstructure:"timeout"`
+}
+
+// GetProvider returns the configured encryption provider, defaulting to local.
+func (c EncryptionConfig) GetProvider() string {
+	if c.Provider == "" {
+		return EncryptionProviderLocal
+	}
+	return c.Provider
+}
+
+// IsEnabled determines if sensitive data should be encrypted.
+func (c EncryptionConfig) IsEnabled() bool {
+	if c.GetProvider() == EncryptionProviderVault {
+		return c.Vault.Key != ""
+	}
+	return c.Key != ""
+}
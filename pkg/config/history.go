@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryConfig are settings related to how long Porter keeps the run history
+// of an installation. By default, all runs are kept.
+type HistoryConfig struct {
+	// MaxRuns is the maximum number of runs kept for each installation.
+	// Zero keeps all runs.
+	MaxRuns int `mapstructure:"max-runs"`
+
+	// MaxAge is the maximum age of the runs kept for each installation, for
+	// example 720h or 30d. An empty value keeps runs regardless of their age.
+	// Do not use directly, use HistoryConfig.GetMaxAge.
+	MaxAge string `mapstructure:"max-age"`
+}
+
+// IsEnabled determines if a retention policy is configured for run history.
+func (c HistoryConfig) IsEnabled() bool {
+	return c.MaxRuns > 0 || c.MaxAge != ""
+}
+
+// GetMaxAge returns the maximum age of the runs kept for each installation.
+// Zero is returned when runs are kept regardless of their age.
+func (c HistoryConfig) GetMaxAge() (time.Duration, error) {
+	return ParseRetentionAge(c.MaxAge)
+}
+
+// ParseRetentionAge parses a duration used to expire run history. In addition
+// to the units supported by time.ParseDuration, a number of days may be
+// specified with the d suffix, for example 30d.
+func ParseRetentionAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	var age time.Duration
+	if strings.HasSuffix(value, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid max age %s: %w", value, err)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		age, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid max age %s: %w", value, err)
+		}
+	}
+
+	if age < 0 {
+		return 0, fmt.Errorf("invalid max age %s: must not be negative", value)
+	}
+	return age, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetentionAge(t *testing.T) {
+	testcases := []struct {
+		value   string
+		want    time.Duration
+		wantErr string
+	}{
+		{value: "", want: 0},
+		{value: "36h", want: 36 * time.Hour},
+		{value: "30d", want: 30 * 24 * time.Hour},
+		{value: "tomorrow", wantErr: "invalid max age tomorrow"},
+		{value: "xd", wantErr: "invalid max age xd"},
+		{value: "-1h", wantErr: "must not be negative"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.value, func(t *testing.T) {
+			age, err := ParseRetentionAge(tc.value)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, age)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+// KubernetesConfig are settings related to how the kubernetes runtime driver
+// executes bundles as Kubernetes Jobs.
+type KubernetesConfig struct {
+	// Namespace where the jobs are created.
+	// Defaults to the namespace of the current kubeconfig context.
+	Namespace string `mapstructure:"namespace"`
+
+	// Kubeconfig is the path to the kubeconfig file used to connect to the cluster.
+	// Defaults to $KUBECONFIG, or ~/.kube/config.
+	Kubeconfig string `mapstructure:"kubeconfig"`
+
+	// Context is the kubeconfig context used to connect to the cluster.
+	// Defaults to the current context.
+	Context string `mapstructure:"context"`
+
+	// InCluster connects to the cluster with the service account of the pod
+	// that Porter is running in, instead of a kubeconfig file.
+	InCluster bool `mapstructure:"in-cluster"`
+
+	// ServiceAccount is the service account that the jobs run as.
+	// Defaults to the default service account of the namespace.
+	ServiceAccount string `mapstructure:"service-account"`
+
+	// Labels are added to the jobs, pods and secrets created by the driver.
+	Labels map[string]string `mapstructure:"labels"`
+
+	// SkipCleanup keeps the jobs and secrets created by the driver after the
+	// bundle completes, which is useful when troubleshooting a bundle.
+	SkipCleanup bool `mapstructure:"skip-cleanup"`
+}
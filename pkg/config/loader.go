@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -31,6 +32,9 @@ func LoadFromEnvironment() DataStoreLoaderFunc {
 		v.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
 		v.AutomaticEnv()
 
+		// Select a context without using the config file key as the name of the environment variable
+		v.BindEnv("current-context", EnvContext)
+
 		// Bind open telemetry environment variables
 		// See https://github.com/open-telemetry/opentelemetry-go/tree/main/exporters/otlp/otlptrace
 		v.BindEnv("telemetry.endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
@@ -77,22 +81,17 @@ func LoadFromViper(viperCfg func(v *viper.Viper)) DataStoreLoaderFunc {
 			viperCfg(v)
 		}
 
-		// Find the config file
-		v.AddConfigPath(home)
-
 		// Only read the config file if we are running as porter
 		// Skip it for internal plugins since we pass the resolved
 		// config directly to the plugins
+		var cfgFile string
 		if !cfg.IsInternalPlugin {
-			err = v.ReadInConfig()
+			cfgFile, err = findConfigFile(cfg, home)
 			if err != nil {
-				if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-					return log.Error(fmt.Errorf("error reading config file: %w", err))
-				}
+				return log.Error(err)
 			}
 		}
 
-		cfgFile := v.ConfigFileUsed()
 		if cfgFile != "" {
 			log.SetAttributes(attribute.String("porter.PORTER_CONFIG", cfgFile))
 
@@ -120,9 +119,16 @@ func LoadFromViper(viperCfg func(v *viper.Viper)) DataStoreLoaderFunc {
 				cfg.templateVariables = listTemplateVariables(tmpl)
 			}
 
+			// Parse the config file after it is rendered, so that templates can be used anywhere in the file
+			v.SetConfigType(strings.TrimPrefix(filepath.Ext(cfgFile), "."))
 			if err := v.ReadConfig(bytes.NewReader(finalCfg)); err != nil {
 				return log.Error(fmt.Errorf("error loading configuration file: %w", err))
 			}
+			cfg.ConfigFilePath = cfgFile
+		}
+
+		if err = applyContext(v); err != nil {
+			return log.Error(err)
 		}
 
 		if err = v.Unmarshal(&cfg.Data); err != nil {
@@ -134,6 +140,22 @@ func LoadFromViper(viperCfg func(v *viper.Viper)) DataStoreLoaderFunc {
 	}
 }
 
+// findConfigFile returns the path to the config file in the Porter home
+// directory, or an empty string when the config file does not exist.
+func findConfigFile(cfg *Config, home string) (string, error) {
+	for _, ext := range viper.SupportedExts {
+		cfgFile := filepath.Join(home, "config."+ext)
+		exists, err := cfg.FileSystem.Exists(cfgFile)
+		if err != nil {
+			return "", fmt.Errorf("error checking if the config file %s exists: %w", cfgFile, err)
+		}
+		if exists {
+			return cfgFile, nil
+		}
+	}
+	return "", nil
+}
+
 func listTemplateVariables(tmpl *liquid.Template) []string {
 	vars := map[string]struct{}{}
 	findTemplateVariables(tmpl.GetRoot(), vars)
@@ -147,6 +169,14 @@ func listTemplateVariables(tmpl *liquid.Template) []string {
 	return results
 }
 
+// parseTemplateVariable returns the name of the variable used by a template
+// expression, such as env.NAME for ${env.NAME | default: "VALUE"}, and whether
+// the expression applies a filter to the variable.
+func parseTemplateVariable(expr string) (string, bool) {
+	name, _, hasFilter := strings.Cut(expr, "|")
+	return strings.TrimSpace(name), hasFilter
+}
+
 // findTemplateVariables looks at the template's abstract syntax tree (AST)
 // and identifies which variables were used
 func findTemplateVariables(curNode render.Node, vars map[string]struct{}) {
@@ -74,3 +74,44 @@ func TestListTemplateVariables(t *testing.T) {
 	vars := listTemplateVariables(tmpl)
 	assert.Equal(t, []string{"env.var", "secrets.foo"}, vars)
 }
+
+func TestConfig_EnvironmentVariableTemplates(t *testing.T) {
+	c := NewTestConfig(t)
+	c.SetHomeDir("/home/myuser/.porter")
+	c.TestContext.AddTestFile("testdata/env.yaml", "/home/myuser/.porter/config.yaml")
+	c.DataLoader = LoadFromEnvironment()
+	c.Setenv("TEAM_NAMESPACE", "blue")
+	c.Setenv("DB_HOST", "db.example.com")
+
+	var resolvedSecrets []string
+	resolveTestSecrets := func(ctx context.Context, secretKey string) (string, error) {
+		resolvedSecrets = append(resolvedSecrets, secretKey)
+		return "topsecret", nil
+	}
+	_, err := c.Load(context.Background(), resolveTestSecrets)
+	require.NoError(t, err)
+
+	assert.Equal(t, "blue", c.Data.Namespace)
+	assert.Equal(t, "warn", c.Data.Verbosity, "the default value should be used when the environment variable is not set")
+	assert.Equal(t, "bluedb", c.Data.DefaultStorage)
+	require.Len(t, c.Data.StoragePlugins, 1)
+	assert.Equal(t, "bluedb", c.Data.StoragePlugins[0].Name)
+	assert.Equal(t, map[string]interface{}{"url": "mongodb://db.example.com:27017/blue"}, c.Data.StoragePlugins[0].Config)
+	assert.Equal(t, []string{"agent-token"}, resolvedSecrets, "the filter should not be included in the name of the secret")
+	assert.Equal(t, "topsecret", c.Data.AgentToken)
+	assert.Empty(t, c.findUnsetEnvironmentVariables())
+
+	// Unset variables without a default are reported
+	c.Unsetenv("DB_HOST")
+	assert.Equal(t, []string{"DB_HOST"}, c.findUnsetEnvironmentVariables())
+}
+
+func TestParseTemplateVariable(t *testing.T) {
+	name, hasFilter := parseTemplateVariable("env.NAME")
+	assert.Equal(t, "env.NAME", name)
+	assert.False(t, hasFilter)
+
+	name, hasFilter = parseTemplateVariable(`env.NAME | default: "VALUE"`)
+	assert.Equal(t, "env.NAME", name)
+	assert.True(t, hasFilter)
+}
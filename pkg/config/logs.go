@@ -11,6 +11,12 @@ const (
 	LogLevelInfo  LogLevel = "info"
 	LogLevelWarn  LogLevel = "warn"
 	LogLevelError LogLevel = "error"
+
+	// LogFormatPlaintext prints messages to the console as plain text.
+	LogFormatPlaintext = "plaintext"
+
+	// LogFormatJson prints messages to the console as json, one message per line.
+	LogFormatJson = "json"
 )
 
 // LogConfig are settings related to Porter's log files.
@@ -19,6 +25,9 @@ type LogConfig struct {
 	Structured bool     `mapstructure:"structured"`
 	LogToFile  bool     `mapstructure:"log-to-file"`
 	Level      LogLevel `mapstructure:"level"`
+
+	// Format of the messages printed to the console: plaintext or json.
+	Format string `mapstructure:"format"`
 }
 
 // TelemetryConfig specifies how to connect to an open telemetry collector.
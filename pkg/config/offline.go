@@ -0,0 +1,20 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOffline is returned when an operation needs network access, such as
+// pulling from a registry or downloading a feed, while Porter is in offline mode.
+var ErrOffline = errors.New("network access is not allowed in offline mode, remove --offline or set offline to false in the config file to allow it")
+
+// CheckNetworkAccess returns an error when Porter is in offline mode. The
+// operation describes what needs network access, for example "pull the bundle
+// example.com/mybuns:v1.0.0", and is included in the error.
+func (c *Config) CheckNetworkAccess(operation string) error {
+	if !c.Data.Offline {
+		return nil
+	}
+	return fmt.Errorf("cannot %s: %w", operation, ErrOffline)
+}
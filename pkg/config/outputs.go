@@ -0,0 +1,91 @@
+package config
+
+const (
+	// OutputStoreFilesystem saves large outputs to a directory.
+	OutputStoreFilesystem = "filesystem"
+
+	// OutputStoreS3 saves large outputs to an Amazon S3 bucket.
+	OutputStoreS3 = "s3"
+
+	// OutputStoreAzureBlob saves large outputs to an Azure Blob Storage container.
+	OutputStoreAzureBlob = "azureblob"
+
+	// DefaultOutputThreshold is the size in bytes above which outputs are saved
+	// to the large output store.
+	DefaultOutputThreshold = 1024 * 1024
+)
+
+// OutputsConfig are settings related to where the value of large outputs is
+// saved, so that they do not bloat Porter's database. By default, all outputs
+// are saved in Porter's database.
+type OutputsConfig struct {
+	// Store that saves the value of large outputs.
+	// Available values are: filesystem, s3, azureblob.
+	Store string `mapstructure:"store"`
+
+	// Threshold is the size in bytes above which the value of an output is
+	// saved to the store. Defaults to 1MiB.
+	// Do not use directly, use OutputsConfig.GetThreshold.
+	Threshold int `mapstructure:"threshold"`
+
+	// Path of the directory used by the filesystem store. Defaults to PORTER_HOME/outputs.
+	Path string `mapstructure:"path"`
+
+	// S3 are the settings used by the s3 store.
+	S3 S3OutputsConfig `mapstructure:"s3"`
+
+	// AzureBlob are the settings used by the azureblob store.
+	AzureBlob AzureBlobOutputsConfig `mapstructure:"azureblob"`
+}
+
+// S3OutputsConfig are the settings for saving large outputs to Amazon S3.
+type S3OutputsConfig struct {
+	// Bucket where the outputs are saved.
+	Bucket string `mapstructure:"bucket"`
+
+	// Prefix of the object keys, for example porter/outputs.
+	Prefix string `mapstructure:"prefix"`
+
+	// Region of the bucket. Defaults to the region of the AWS configuration.
+	Region string `mapstructure:"region"`
+
+	// Endpoint overrides the url of the S3 service, for example to use MinIO.
+	// Objects are addressed with a path, instead of a virtual host, when it is set.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Profile in the AWS shared configuration files to use.
+	Profile string `mapstructure:"profile"`
+}
+
+// AzureBlobOutputsConfig are the settings for saving large outputs to Azure Blob Storage.
+type AzureBlobOutputsConfig struct {
+	// Account is the name of the storage account.
+	Account string `mapstructure:"account"`
+
+	// Container where the outputs are saved.
+	Container string `mapstructure:"container"`
+
+	// Prefix of the blob names, for example porter/outputs.
+	Prefix string `mapstructure:"prefix"`
+
+	// SASToken is a shared access signature that allows blobs in the container to be read and written.
+	// Use ${secret.NAME} or ${env.NAME} so that the token is not stored in the configuration file.
+	SASToken string `mapstructure:"sas-token"`
+
+	// Endpoint overrides the url of the blob service, for example to use Azurite.
+	// Defaults to https://ACCOUNT.blob.core.windows.net.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// IsEnabled determines if large outputs are saved outside of Porter's database.
+func (c OutputsConfig) IsEnabled() bool {
+	return c.Store != ""
+}
+
+// GetThreshold returns the size in bytes above which outputs are saved to the store.
+func (c OutputsConfig) GetThreshold() int {
+	if c.Threshold <= 0 {
+		return DefaultOutputThreshold
+	}
+	return c.Threshold
+}
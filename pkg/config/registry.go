@@ -0,0 +1,155 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RegistryMirror configures Porter to pull bundles and images from a mirror of
+// a registry, instead of from the registry directly.
+type RegistryMirror struct {
+	// Registry that is mirrored, for example docker.io or ghcr.io.
+	Registry string `mapstructure:"registry"`
+
+	// Mirror is the host, and optional port, of the mirror, for example mirror.example.com:5000.
+	// Repositories are pulled from the mirror using the same repository path as the mirrored registry.
+	Mirror string `mapstructure:"mirror"`
+
+	// Insecure allows connecting to the mirror over plain http, or with a certificate that cannot be verified.
+	Insecure bool `mapstructure:"insecure"`
+
+	// CACertFile is the path to a PEM encoded certificate authority bundle used to verify the mirror's certificate.
+	CACertFile string `mapstructure:"ca-cert-file"`
+
+	// Username used to authenticate to the mirror.
+	// When it is not set, the credentials for the mirror in the docker config file are used.
+	Username string `mapstructure:"username"`
+
+	// Password used to authenticate to the mirror.
+	// Use a secret, such as ${secret.mirror-password}, so that the password is not stored in the config file.
+	Password string `mapstructure:"password"`
+}
+
+// Validate the registry mirror configuration.
+func (m RegistryMirror) Validate() error {
+	if m.Registry == "" {
+		return errors.New("registry-mirrors.registry is required")
+	}
+	if m.Mirror == "" {
+		return fmt.Errorf("registry-mirrors.mirror is required for the %s registry", m.Registry)
+	}
+	if strings.Contains(m.Mirror, "://") || strings.Contains(m.Mirror, "/") {
+		return fmt.Errorf("invalid registry-mirrors.mirror %s for the %s registry, the mirror must be a host and an optional port, for example mirror.example.com:5000", m.Mirror, m.Registry)
+	}
+	if m.Insecure && m.CACertFile != "" {
+		return fmt.Errorf("registry-mirrors.insecure and registry-mirrors.ca-cert-file cannot both be set for the %s registry", m.Registry)
+	}
+	return nil
+}
+
+// RegistryMirrors is the list of registry mirrors defined in the configuration file.
+type RegistryMirrors []RegistryMirror
+
+// Find returns the mirror configured for the specified registry.
+func (m RegistryMirrors) Find(registry string) (RegistryMirror, bool) {
+	registry = normalizeRegistry(registry)
+	for _, mirror := range m {
+		if normalizeRegistry(mirror.Registry) == registry {
+			return mirror, true
+		}
+	}
+	return RegistryMirror{}, false
+}
+
+// Validate every registry mirror, and that each registry has only a single mirror.
+func (m RegistryMirrors) Validate() error {
+	registries := make(map[string]bool, len(m))
+	for _, mirror := range m {
+		if err := mirror.Validate(); err != nil {
+			return err
+		}
+		registry := normalizeRegistry(mirror.Registry)
+		if registries[registry] {
+			return fmt.Errorf("multiple registry-mirrors are defined for the %s registry", mirror.Registry)
+		}
+		registries[registry] = true
+	}
+	return nil
+}
+
+// normalizeRegistry converts the various hosts used for Docker Hub into docker.io,
+// so that a mirror matches no matter how Docker Hub was specified.
+func normalizeRegistry(registry string) string {
+	registry = strings.ToLower(registry)
+	switch registry {
+	case "index.docker.io", "registry-1.docker.io":
+		return "docker.io"
+	}
+	return registry
+}
+
+// RegistryTLS configures how Porter connects to a registry that is secured with TLS.
+type RegistryTLS struct {
+	// Registry that the settings apply to, for example registry.example.com:5000.
+	Registry string `mapstructure:"registry"`
+
+	// Insecure allows connecting to the registry over plain http, or with a certificate that cannot be verified.
+	Insecure bool `mapstructure:"insecure"`
+
+	// CACertFile is the path to a PEM encoded certificate authority bundle used to verify the registry's certificate.
+	CACertFile string `mapstructure:"ca-cert-file"`
+
+	// ClientCertFile is the path to a PEM encoded client certificate presented to the registry.
+	ClientCertFile string `mapstructure:"client-cert-file"`
+
+	// ClientKeyFile is the path to the PEM encoded private key for the client certificate.
+	ClientKeyFile string `mapstructure:"client-key-file"`
+}
+
+// Validate the registry TLS configuration.
+func (t RegistryTLS) Validate() error {
+	if t.Registry == "" {
+		return errors.New("registry-tls.registry is required")
+	}
+	if strings.Contains(t.Registry, "://") || strings.Contains(t.Registry, "/") {
+		return fmt.Errorf("invalid registry-tls.registry %s, the registry must be a host and an optional port, for example registry.example.com:5000", t.Registry)
+	}
+	if t.Insecure && t.CACertFile != "" {
+		return fmt.Errorf("registry-tls.insecure and registry-tls.ca-cert-file cannot both be set for the %s registry", t.Registry)
+	}
+	if (t.ClientCertFile == "") != (t.ClientKeyFile == "") {
+		return fmt.Errorf("registry-tls.client-cert-file and registry-tls.client-key-file must be set together for the %s registry", t.Registry)
+	}
+	return nil
+}
+
+// RegistryTLSConfigs is the list of registry TLS settings defined in the configuration file.
+type RegistryTLSConfigs []RegistryTLS
+
+// Find returns the TLS settings configured for the specified registry.
+func (c RegistryTLSConfigs) Find(registry string) (RegistryTLS, bool) {
+	registry = normalizeRegistry(registry)
+	for _, t := range c {
+		if normalizeRegistry(t.Registry) == registry {
+			return t, true
+		}
+	}
+	return RegistryTLS{}, false
+}
+
+// Validate the TLS settings of every registry, and that each registry is only configured once.
+func (c RegistryTLSConfigs) Validate() error {
+	registries := make(map[string]bool, len(c))
+	for _, t := range c {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		registry := normalizeRegistry(t.Registry)
+		if registries[registry] {
+			return fmt.Errorf("multiple registry-tls entries are defined for the %s registry", t.Registry)
+		}
+		registries[registry] = true
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryMirrors_Find(t *testing.T) {
+	mirrors := RegistryMirrors{
+		{Registry: "docker.io", Mirror: "dockerhub.example.com"},
+		{Registry: "GHCR.io", Mirror: "ghcr.example.com:5000"},
+	}
+
+	testcases := []struct {
+		registry   string
+		wantMirror string
+	}{
+		{registry: "docker.io", wantMirror: "dockerhub.example.com"},
+		{registry: "index.docker.io", wantMirror: "dockerhub.example.com"},
+		{registry: "registry-1.docker.io", wantMirror: "dockerhub.example.com"},
+		{registry: "ghcr.io", wantMirror: "ghcr.example.com:5000"},
+		{registry: "quay.io"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.registry, func(t *testing.T) {
+			mirror, ok := mirrors.Find(tc.registry)
+			assert.Equal(t, tc.wantMirror != "", ok)
+			assert.Equal(t, tc.wantMirror, mirror.Mirror)
+		})
+	}
+}
+
+func TestRegistryMirrors_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mirrors RegistryMirrors
+		wantErr string
+	}{
+		{name: "valid", mirrors: RegistryMirrors{
+			{Registry: "docker.io", Mirror: "dockerhub.example.com", Username: "sally", Password: "topsecret"},
+			{Registry: "ghcr.io", Mirror: "localhost:5000", Insecure: true},
+		}},
+		{name: "missing registry", mirrors: RegistryMirrors{{Mirror: "dockerhub.example.com"}}, wantErr: "registry-mirrors.registry is required"},
+		{name: "missing mirror", mirrors: RegistryMirrors{{Registry: "docker.io"}}, wantErr: "registry-mirrors.mirror is required for the docker.io registry"},
+		{name: "mirror with scheme", mirrors: RegistryMirrors{{Registry: "docker.io", Mirror: "https://dockerhub.example.com"}}, wantErr: "the mirror must be a host and an optional port"},
+		{name: "mirror with path", mirrors: RegistryMirrors{{Registry: "docker.io", Mirror: "example.com/dockerhub"}}, wantErr: "the mirror must be a host and an optional port"},
+		{name: "insecure with ca", mirrors: RegistryMirrors{{Registry: "docker.io", Mirror: "dockerhub.example.com", Insecure: true, CACertFile: "ca.pem"}}, wantErr: "cannot both be set"},
+		{name: "duplicate registry", mirrors: RegistryMirrors{
+			{Registry: "docker.io", Mirror: "dockerhub.example.com"},
+			{Registry: "index.docker.io", Mirror: "other.example.com"},
+		}, wantErr: "multiple registry-mirrors are defined for the index.docker.io registry"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.mirrors.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRegistryTLSConfigs_Find(t *testing.T) {
+	configs := RegistryTLSConfigs{
+		{Registry: "docker.io", CACertFile: "dockerhub-ca.pem"},
+		{Registry: "Registry.example.com:5000", Insecure: true},
+	}
+
+	cfg, ok := configs.Find("index.docker.io")
+	require.True(t, ok)
+	assert.Equal(t, "dockerhub-ca.pem", cfg.CACertFile)
+
+	cfg, ok = configs.Find("registry.example.com:5000")
+	require.True(t, ok)
+	assert.True(t, cfg.Insecure)
+
+	_, ok = configs.Find("registry.example.com")
+	assert.False(t, ok, "the port is part of the registry")
+}
+
+func TestRegistryTLSConfigs_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		configs RegistryTLSConfigs
+		wantErr string
+	}{
+		{name: "valid", configs: RegistryTLSConfigs{
+			{Registry: "registry.example.com", CACertFile: "ca.pem", ClientCertFile: "client.pem", ClientKeyFile: "client-key.pem"},
+			{Registry: "localhost:5000", Insecure: true},
+		}},
+		{name: "missing registry", configs: RegistryTLSConfigs{{Insecure: true}}, wantErr: "registry-tls.registry is required"},
+		{name: "registry with scheme", configs: RegistryTLSConfigs{{Registry: "https://registry.example.com"}}, wantErr: "the registry must be a host and an optional port"},
+		{name: "insecure with ca", configs: RegistryTLSConfigs{{Registry: "registry.example.com", Insecure: true, CACertFile: "ca.pem"}}, wantErr: "cannot both be set"},
+		{name: "client cert without key", configs: RegistryTLSConfigs{{Registry: "registry.example.com", ClientCertFile: "client.pem"}}, wantErr: "must be set together"},
+		{name: "duplicate registry", configs: RegistryTLSConfigs{
+			{Registry: "registry.example.com", Insecure: true},
+			{Registry: "REGISTRY.example.com", CACertFile: "ca.pem"},
+		}, wantErr: "multiple registry-tls entries are defined for the REGISTRY.example.com registry"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.configs.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+const (
+	// ScannerTrivy scans images for vulnerabilities with the trivy CLI.
+	ScannerTrivy = "trivy"
+
+	// ScannerGrype scans images for vulnerabilities with the grype CLI.
+	ScannerGrype = "grype"
+)
+
+// ScanConfig are settings related to how Porter scans the images of a bundle
+// for vulnerabilities.
+type ScanConfig struct {
+	// Scanner used to scan images for vulnerabilities.
+	// Available values are: trivy, grype. Defaults to trivy.
+	Scanner string `mapstructure:"scanner"`
+}
+
+// GetScanner returns the configured scanner, defaulting to trivy.
+func (c ScanConfig) GetScanner() string {
+	if c.Scanner == "" {
+		return ScannerTrivy
+	}
+	return c.Scanner
+}
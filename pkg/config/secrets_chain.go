@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// SecretsSourceEnv resolves a secret from an environment variable named
+	// after the secret, for example db-password is read from DB_PASSWORD.
+	SecretsSourceEnv = "env"
+
+	// SecretsSourcePrompt asks the user for the value of a secret. It is
+	// skipped when Porter is not run from an interactive terminal.
+	SecretsSourcePrompt = "prompt"
+
+	// DefaultSecretsSourceTimeout is the amount of time to wait for a secrets
+	// plugin in the secrets chain before trying the next source.
+	DefaultSecretsSourceTimeout = 10 * time.Second
+)
+
+// SecretsSource is a source of secrets in the secrets chain.
+type SecretsSource struct {
+	// Name of the source, either a secrets plugin defined in the secrets
+	// section, or a built-in source: env or prompt.
+	Name string `mapstructure:"name"`
+
+	// Timeout is how long to wait for the source before trying the next source,
+	// for example 5s. Defaults to 10s. The prompt source waits for the user.
+	// Do not use directly, use SecretsSource.GetTimeout.
+	Timeout string `mapstructure:"timeout"`
+}
+
+// IsBuiltin determines if the source is implemented by Porter instead of a
+// secrets plugin.
+func (s SecretsSource) IsBuiltin() bool {
+	return s.Name == SecretsSourceEnv || s.Name == SecretsSourcePrompt
+}
+
+// GetTimeout returns how long to wait for the source before trying the next source.
+func (s SecretsSource) GetTimeout() (time.Duration, error) {
+	if s.Timeout == "" {
+		return DefaultSecretsSourceTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(s.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %s: %w", s.Timeout, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %s: must be greater than 0", s.Timeout)
+	}
+	return timeout, nil
+}
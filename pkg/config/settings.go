@@ -0,0 +1,222 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// MaskedValue replaces sensitive values when the configuration is printed.
+const MaskedValue = "******"
+
+// sensitiveSettings are settings whose value is always masked when the
+// configuration is printed.
+var sensitiveSettings = []string{
+	"agent-token",
+	"encryption.key",
+	"encryption.vault.token",
+	"access.webhook.token",
+	"outputs.azureblob.sas-token",
+	"registry-mirrors.password",
+}
+
+// GetSettings returns the settings defined in the configuration file, with
+// sensitive values masked. When resolved is true, the effective settings are
+// returned instead: template variables such as ${env.NAME} and ${secret.NAME}
+// are rendered, the current context is applied, and the values from
+// environment variables and defaults are included.
+func (c *Config) GetSettings(resolved bool) (map[string]interface{}, error) {
+	var settings map[string]interface{}
+	if resolved {
+		if c.viper == nil {
+			return nil, errors.New("the configuration has not been loaded")
+		}
+		settings = c.viper.AllSettings()
+	} else {
+		if c.ConfigFilePath == "" {
+			return map[string]interface{}{}, nil
+		}
+
+		contents, err := c.FileSystem.ReadFile(c.ConfigFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+
+		// Parse the config file without rendering templates, so that only what is in the file is returned
+		v := viper.New()
+		v.SetConfigType(strings.TrimPrefix(filepath.Ext(c.ConfigFilePath), "."))
+		if err = v.ReadConfig(bytes.NewReader(contents)); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s, use --resolved to print the configuration after its template variables are rendered: %w", c.ConfigFilePath, err)
+		}
+		settings = v.AllSettings()
+	}
+
+	return c.maskSettings("", settings).(map[string]interface{}), nil
+}
+
+// GetRedactedSettings returns the effective settings, like GetSettings(true),
+// but every value in the config of a storage or secrets plugin is masked.
+// Plugins define their own settings, which are often sourced from environment
+// variables, so they can't reliably be identified as sensitive. Use this when
+// the settings are shared, such as in a diagnostics bundle.
+func (c *Config) GetRedactedSettings() (map[string]interface{}, error) {
+	settings, err := c.GetSettings(true)
+	if err != nil {
+		return nil, err
+	}
+	return maskPluginConfig("", settings).(map[string]interface{}), nil
+}
+
+// maskPluginConfig returns a copy of the masked settings where every value in
+// the config of a plugin is replaced with MaskedValue.
+func maskPluginConfig(path string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			// The settings of a context use the same keys as the rest of the config file
+			if childPath == "contexts" {
+				childPath = ""
+			}
+			if childPath == "storage.config" || childPath == "secrets.config" {
+				masked[key] = maskAllSettings(child)
+				continue
+			}
+			masked[key] = maskPluginConfig(childPath, child)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, child := range v {
+			masked[i] = maskPluginConfig(path, child)
+		}
+		return masked
+	default:
+		return value
+	}
+}
+
+// maskAllSettings returns a copy of the settings where every value is replaced with MaskedValue.
+func maskAllSettings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			masked[key] = maskAllSettings(child)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, child := range v {
+			masked[i] = maskAllSettings(child)
+		}
+		return masked
+	case nil:
+		return nil
+	default:
+		return MaskedValue
+	}
+}
+
+// maskSettings returns a copy of the settings where sensitive values are
+// replaced with MaskedValue.
+func (c *Config) maskSettings(path string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			// The settings of a context use the same keys as the rest of the config file
+			if childPath == "contexts" {
+				childPath = ""
+			}
+			masked[key] = c.maskSettings(childPath, child)
+		}
+		return masked
+	case map[interface{}]interface{}:
+		// Lists in a yaml config file are parsed with this type of map, convert it so that it can be printed as json
+		converted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			converted[fmt.Sprint(key)] = child
+		}
+		return c.maskSettings(path, converted)
+	case map[string]string:
+		masked := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			masked[key] = c.maskSettings(path+"."+key, child)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, child := range v {
+			masked[i] = c.maskSettings(path, child)
+		}
+		return masked
+	case string:
+		return c.maskValue(path, v)
+	default:
+		return value
+	}
+}
+
+// maskValue masks the value of a sensitive setting, values that came from a
+// secret, and the password in a connection string.
+func (c *Config) maskValue(path string, value string) string {
+	if value == "" {
+		return value
+	}
+
+	if isSensitiveSetting(path) {
+		// References to secrets or environment variables do not reveal anything
+		if strings.HasPrefix(value, "${") {
+			return value
+		}
+		return MaskedValue
+	}
+
+	for _, secret := range c.secretValues {
+		if secret != "" && strings.Contains(value, secret) {
+			return MaskedValue
+		}
+	}
+
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return strings.Replace(u.Redacted(), ":xxxxx@", ":"+MaskedValue+"@", 1)
+		}
+	}
+
+	return value
+}
+
+// isSensitiveSetting determines if a setting may hold a credential, based on
+// its path in the configuration file.
+func isSensitiveSetting(path string) bool {
+	if contains(sensitiveSettings, path) || strings.HasPrefix(path, "telemetry.headers.") {
+		return true
+	}
+
+	// Plugins define their own settings, so guess based on the name of the setting
+	if !strings.HasPrefix(path, "storage.config.") && !strings.HasPrefix(path, "secrets.config.") {
+		return false
+	}
+	name := path[strings.LastIndex(path, ".")+1:]
+	for _, hint := range []string{"password", "secret", "token"} {
+		if strings.Contains(name, hint) {
+			return true
+		}
+	}
+	return false
+}
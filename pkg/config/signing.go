@@ -0,0 +1,42 @@
+package config
+
+const (
+	// SignerCosign signs and verifies bundles with the cosign CLI.
+	SignerCosign = "cosign"
+
+	// SignerNotation signs and verifies bundles with the notation CLI.
+	SignerNotation = "notation"
+)
+
+// SigningConfig are settings related to how Porter signs bundles when they are
+// published, and verifies their signatures.
+type SigningConfig struct {
+	// Signer used to sign bundles and verify their signatures.
+	// Available values are: cosign, notation. Defaults to cosign.
+	Signer string `mapstructure:"signer"`
+
+	// Key used to sign bundles.
+	// For cosign, this is the path or KMS URI of the private key. When it is not set, cosign uses keyless signing.
+	// For notation, this is the name of the signing key. When it is not set, notation uses its default key.
+	Key string `mapstructure:"key"`
+
+	// VerifyKey is the path or KMS URI of the public key used by cosign to verify signatures.
+	// Notation verifies signatures with its trust policy instead.
+	VerifyKey string `mapstructure:"verify-key"`
+
+	// CertificateIdentity is the identity expected in the signing certificate
+	// when cosign verifies a keyless signature.
+	CertificateIdentity string `mapstructure:"certificate-identity"`
+
+	// CertificateOIDCIssuer is the OIDC issuer expected in the signing
+	// certificate when cosign verifies a keyless signature.
+	CertificateOIDCIssuer string `mapstructure:"certificate-oidc-issuer"`
+}
+
+// GetSigner returns the configured signer, defaulting to cosign.
+func (c SigningConfig) GetSigner() string {
+	if c.Signer == "" {
+		return SignerCosign
+	}
+	return c.Signer
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"get.porter.sh/porter/pkg/schema"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Validate checks the loaded configuration for settings that Porter does not
+// recognize, values with the wrong data type and values that are not
+// allowed, such as an unknown verbosity or a default storage that is not
+// defined. All problems are returned together so that they can be fixed at once.
+// Flags are the names of the flags defined by Porter's commands, which may also
+// be set in the config file.
+func (c *Config) Validate(flags []string) error {
+	var problems *multierror.Error
+
+	if c.viper != nil {
+		// The settings are decoded again because problems found by the loader are not fatal
+		if err := decodeSettings(withoutFlags(c.viper.AllSettings(), flags), &Data{}, true); err != nil {
+			problems = multierror.Append(problems, err)
+		}
+	}
+
+	for _, name := range c.findUnsetEnvironmentVariables() {
+		problems = multierror.Append(problems, fmt.Errorf("the config file uses ${env.%s} but the %s environment variable is not set", name, name))
+	}
+
+	if err := c.Data.Validate(); err != nil {
+		problems = multierror.Append(problems, err)
+	}
+
+	seen := make(map[string]bool, len(c.Data.Contexts))
+	for i, ctx := range c.Data.Contexts {
+		if ctx.Name == "" {
+			problems = multierror.Append(problems, fmt.Errorf("contexts[%d]: name is required", i))
+			continue
+		}
+		if seen[ctx.Name] {
+			problems = multierror.Append(problems, fmt.Errorf("context %s is defined more than once", ctx.Name))
+		}
+		seen[ctx.Name] = true
+
+		for key := range ctx.Settings {
+			if key == "contexts" || key == "current-context" {
+				problems = multierror.Append(problems, fmt.Errorf("context %s: %s cannot be set in a context", ctx.Name, key))
+			}
+		}
+		if err := decodeSettings(withoutFlags(ctx.Settings, flags), &Data{}, true); err != nil {
+			for _, ctxErr := range err.(*multierror.Error).Errors {
+				problems = multierror.Append(problems, fmt.Errorf("context %s: %w", ctx.Name, ctxErr))
+			}
+		}
+	}
+
+	return problems.ErrorOrNil()
+}
+
+// Validate checks that the values of the settings are allowed.
+func (d Data) Validate() error {
+	var problems *multierror.Error
+
+	check := func(err error) {
+		if err != nil {
+			problems = multierror.Append(problems, err)
+		}
+	}
+
+	check(validateOneOf("verbosity", d.Verbosity, string(LogLevelDebug), string(LogLevelInfo), string(LogLevelWarn), "warning", string(LogLevelError)))
+	check(validateOneOf("logs.level", string(d.Logs.Level), string(LogLevelDebug), string(LogLevelInfo), string(LogLevelWarn), "warning", string(LogLevelError)))
+	check(validateOneOf("logs.format", d.Logs.Format, LogFormatPlaintext, LogFormatJson))
+	check(validateOneOf("schema-check", d.SchemaCheck,
+		string(schema.CheckStrategyExact), string(schema.CheckStrategyMinor), string(schema.CheckStrategyMajor), string(schema.CheckStrategyNone)))
+	check(validateOneOf("encryption.provider", d.Encryption.Provider, EncryptionProviderLocal, EncryptionProviderVault))
+	check(validateOneOf("access.provider", d.Access.Provider, AccessProviderLocal, AccessProviderWebhook))
+	check(validateOneOf("outputs.store", d.Outputs.Store, OutputStoreFilesystem, OutputStoreS3, OutputStoreAzureBlob))
+	check(validateOneOf("signing.signer", d.Signing.Signer, SignerCosign, SignerNotation))
+	check(validateOneOf("scan.scanner", d.Scan.Scanner, ScannerTrivy, ScannerGrype))
+
+	if _, err := d.History.GetMaxAge(); err != nil {
+		check(fmt.Errorf("invalid history.max-age: %w", err))
+	}
+	if _, err := d.Cache.GetMaxSize(); err != nil {
+		check(fmt.Errorf("invalid cache.max-size: %w", err))
+	}
+	check(d.RegistryMirrors.Validate())
+	check(d.RegistryTLS.Validate())
+	check(d.BundleIndexes.Validate())
+
+	storageNames := make([]string, 0, len(d.StoragePlugins))
+	for i, s := range d.StoragePlugins {
+		check(validatePluginConfig("storage", i, s.PluginConfig, storageNames))
+		storageNames = append(storageNames, s.Name)
+	}
+	if d.DefaultStorage != "" && !contains(storageNames, d.DefaultStorage) {
+		check(fmt.Errorf("default-storage %s is not defined in the storage section", d.DefaultStorage))
+	}
+
+	secretsNames := make([]string, 0, len(d.SecretsPlugin))
+	for i, s := range d.SecretsPlugin {
+		check(validatePluginConfig("secrets", i, s.PluginConfig, secretsNames))
+		secretsNames = append(secretsNames, s.Name)
+	}
+	if d.DefaultSecrets != "" && !contains(secretsNames, d.DefaultSecrets) {
+		check(fmt.Errorf("default-secrets %s is not defined in the secrets section", d.DefaultSecrets))
+	}
+
+	for i, source := range d.SecretsChain {
+		switch {
+		case source.Name == "":
+			check(fmt.Errorf("secrets-chain[%d]: name is required", i))
+			continue
+		case source.IsBuiltin():
+			if contains(secretsNames, source.Name) {
+				check(fmt.Errorf("secrets-chain %s: %s is a built-in source and cannot also be defined in the secrets section", source.Name, source.Name))
+			}
+		case !contains(secretsNames, source.Name):
+			check(fmt.Errorf("secrets-chain %s is not defined in the secrets section, or one of the built-in sources: %s, %s", source.Name, SecretsSourceEnv, SecretsSourcePrompt))
+		}
+		if _, err := source.GetTimeout(); err != nil {
+			check(fmt.Errorf("secrets-chain %s: %w", source.Name, err))
+		}
+	}
+
+	return problems.ErrorOrNil()
+}
+
+// GetData returns the settings of the context.
+func (c ContextConfig) GetData() (Data, error) {
+	var d Data
+	err := mapstructure.WeakDecode(c.Settings, &d)
+	return d, err
+}
+
+// validatePluginConfig checks a plugin stanza from the storage or secrets section.
+func validatePluginConfig(section string, index int, cfg PluginConfig, definedNames []string) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("%s[%d]: name is required", section, index)
+	}
+	if contains(definedNames, cfg.Name) {
+		return fmt.Errorf("%s %s is defined more than once", section, cfg.Name)
+	}
+	if cfg.PluginSubKey == "" {
+		return fmt.Errorf("%s %s: plugin is required", section, cfg.Name)
+	}
+	return nil
+}
+
+// validateOneOf checks that a setting is either unset or one of the allowed values.
+func validateOneOf(setting string, value string, allowed ...string) error {
+	if value == "" || contains(allowed, value) {
+		return nil
+	}
+	return fmt.Errorf("invalid %s %q, allowed values are: %s", setting, value, strings.Join(allowed, ", "))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeConfig decodes the plugin's configuration the same way that Porter's
+// plugins do, except that settings which do not match a field in result are
+// reported as an error.
+func (p PluginConfig) DecodeConfig(result interface{}) error {
+	// The configuration is sent to the plugin as json
+	b, err := json.Marshal(p.Config)
+	if err != nil {
+		return fmt.Errorf("could not marshal the plugin configuration: %w", err)
+	}
+	var settings map[string]interface{}
+	if err = json.Unmarshal(b, &settings); err != nil {
+		return fmt.Errorf("could not unmarshal the plugin configuration: %w", err)
+	}
+
+	return decodeSettings(settings, result, false)
+}
+
+// withoutFlags returns a copy of the settings without the settings that set
+// the value of a flag, which are not defined by Data.
+func withoutFlags(settings map[string]interface{}, flags []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if contains(flags, key) && !isSetting(key) {
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// isSetting determines if the key is one of the settings defined by Data.
+func isSetting(key string) bool {
+	t := reflect.TypeOf(Data{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+		if name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeSettings decodes settings the same way as the loader, except that
+// settings which do not match a field are reported as an error.
+// Each problem found by mapstructure is returned as a separate error.
+func decodeSettings(settings map[string]interface{}, result interface{}, weaklyTyped bool) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           result,
+		ErrorUnused:      true,
+		WeaklyTypedInput: weaklyTyped,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(settings)
+	if decodeErr, ok := err.(*mapstructure.Error); ok {
+		var problems *multierror.Error
+		for _, msg := range decodeErr.Errors {
+			problems = multierror.Append(problems, fmt.Errorf("%s", formatDecodeError(msg)))
+		}
+		return problems.ErrorOrNil()
+	}
+	return err
+}
+
+// formatDecodeError rewords the unknown key errors from mapstructure, which
+// otherwise refer to the root of the configuration as ”.
+func formatDecodeError(msg string) string {
+	const invalidKeys = " has invalid keys: "
+	i := strings.Index(msg, invalidKeys)
+	if i == -1 {
+		return msg
+	}
+
+	parent := strings.Trim(msg[:i], "'")
+	keys := strings.Split(msg[i+len(invalidKeys):], ", ")
+	for j, key := range keys {
+		if parent != "" {
+			keys[j] = parent + "." + key
+		}
+	}
+	if len(keys) == 1 {
+		return "unknown setting " + keys[0]
+	}
+	return "unknown settings " + strings.Join(keys, ", ")
+}
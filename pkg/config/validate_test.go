@@ -0,0 +1,94 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		c := NewTestConfig(t)
+		c.SetHomeDir("/home/myuser/.porter")
+		c.TestContext.AddTestFile("testdata/config.toml", "/home/myuser/.porter/config.toml")
+		c.DataLoader = LoadFromEnvironment()
+		c.Setenv("VAULT_TOKEN", "topsecret-token")
+
+		_, err := c.Load(context.Background(), nil)
+		require.NoError(t, err)
+		require.NoError(t, c.Validate(nil))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c := NewTestConfig(t)
+		c.SetHomeDir("/home/myuser/.porter")
+		c.TestContext.AddTestFile("testdata/invalid.yaml", "/home/myuser/.porter/config.yaml")
+		c.DataLoader = LoadFromEnvironment()
+
+		_, err := c.Load(context.Background(), nil)
+		require.NoError(t, err)
+
+		err = c.Validate([]string{"output"})
+		require.Error(t, err)
+		var problems []string
+		for _, problem := range err.(*multierror.Error).Errors {
+			problems = append(problems, problem.Error())
+		}
+		assert.Contains(t, problems, "unknown setting verbosty")
+		assert.Contains(t, problems, `invalid verbosity "loud", allowed values are: debug, info, warn, warning, error`)
+		assert.Contains(t, problems, `invalid logs.format "xml", allowed values are: plaintext, json`)
+		assert.Contains(t, problems, "default-storage mydb is not defined in the storage section")
+		assert.Contains(t, problems, "context prod: unknown setting namespaec")
+		assert.Contains(t, problems, `cannot parse 'outputs.threshold' as int: strconv.ParseInt: parsing "big": invalid syntax`)
+		assert.Len(t, problems, 7)
+	})
+}
+
+func TestPluginConfig_DecodeConfig(t *testing.T) {
+	var result struct {
+		URL     string `mapstructure:"url"`
+		Timeout int    `mapstructure:"timeout"`
+	}
+
+	cfg := PluginConfig{Config: map[string]interface{}{"url": "mongodb://localhost", "timeout": 10}}
+	require.NoError(t, cfg.DecodeConfig(&result))
+	assert.Equal(t, 10, result.Timeout)
+
+	cfg = PluginConfig{Config: map[string]interface{}{"url": "mongodb://localhost", "timout": 10, "timeout": "10"}}
+	err := cfg.DecodeConfig(&result)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown setting timout")
+	assert.Contains(t, err.Error(), "'timeout' expected type 'int', got unconvertible type 'string'")
+}
+
+func TestData_Validate_SecretsChain(t *testing.T) {
+	d := Data{
+		SecretsPlugin: []SecretsPlugin{
+			{PluginConfig: PluginConfig{Name: "vault", PluginSubKey: "hashicorp.vault"}},
+			{PluginConfig: PluginConfig{Name: "env", PluginSubKey: "host"}},
+		},
+		SecretsChain: []SecretsSource{
+			{Name: "vault", Timeout: "5s"},
+			{Name: "env"},
+			{Name: "prompt"},
+			{Name: "keyvault"},
+			{Name: "vault", Timeout: "soon"},
+			{},
+		},
+	}
+
+	err := d.Validate()
+	require.Error(t, err)
+	var problems []string
+	for _, problem := range err.(*multierror.Error).Errors {
+		problems = append(problems, problem.Error())
+	}
+	assert.Contains(t, problems, "secrets-chain env: env is a built-in source and cannot also be defined in the secrets section")
+	assert.Contains(t, problems, "secrets-chain keyvault is not defined in the secrets section, or one of the built-in sources: env, prompt")
+	assert.Contains(t, problems, `secrets-chain vault: invalid timeout soon: time: invalid duration "soon"`)
+	assert.Contains(t, problems, "secrets-chain[5]: name is required")
+	assert.Len(t, problems, 4)
+}
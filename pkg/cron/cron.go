@@ -0,0 +1,217 @@
+// Package cron parses standard five field cron expressions, e.g. "0 3 * * *",
+// and calculates when a schedule is next due.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// anyDayOfMonth and anyDayOfWeek are true when the field is an unrestricted *.
+	// When either is unrestricted, a day must match both fields. Otherwise a day
+	// matches when either field matches, as with vixie cron.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// field describes the allowed values of a field in a cron expression.
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Sunday may be specified as either 0 or 7
+	dayOfWeekField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// macros are the supported shorthand expressions.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse a cron expression with five fields: minute, hour, day of month, month
+// and day of week. Each field may be *, a value, a range (1-5), a step (*/15 or 1-30/5)
+// or a comma separated list of them. Months and days of the week may be specified
+// by their three letter names, e.g. jan or mon. The shorthand expressions
+// @yearly, @monthly, @weekly, @daily and @hourly are also supported.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: expected 5 fields but found %d", expr, len(fields))
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.dayOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if s.dayOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+
+	// Treat 7 as Sunday
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+	}
+	s.anyDayOfMonth = fields[2] == "*"
+	s.anyDayOfWeek = fields[4] == "*"
+
+	return s, nil
+}
+
+// parse a single field of a cron expression into a bitset of the matching values.
+func (f field) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepExpr)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q in the %s field", stepExpr, f.name)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangeExpr == "*":
+			start, end = f.min, f.max
+		case strings.Contains(rangeExpr, "-"):
+			startExpr, endExpr, _ := strings.Cut(rangeExpr, "-")
+			var err error
+			if start, err = f.parseValue(startExpr); err != nil {
+				return 0, err
+			}
+			if end, err = f.parseValue(endExpr); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in the %s field", rangeExpr, f.name)
+			}
+		default:
+			var err error
+			if start, err = f.parseValue(rangeExpr); err != nil {
+				return 0, err
+			}
+			end = start
+			if hasStep {
+				// A value with a step, e.g. 5/15, repeats until the end of the range
+				end = f.max
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a single value, either a number or a name, in a field.
+func (f field) parseValue(value string) (int, error) {
+	if n, ok := f.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in the %s field", value, f.name)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d in the %s field is out of range, must be between %d and %d", n, f.name, f.min, f.max)
+	}
+	return n, nil
+}
+
+// errNoMatch is returned when a schedule never matches, such as 0 0 30 2 *.
+var errNoMatch = errors.New("the cron expression does not match any time in the next five years")
+
+// Next returns the first time that the schedule is due after the specified time.
+// The time is evaluated in the location of the specified time.
+func (s Schedule) Next(after time.Time) (time.Time, error) {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, errNoMatch
+}
+
+// matchesDay determines if the day of the month or week of the time matches the schedule.
+func (s Schedule) matchesDay(t time.Time) bool {
+	domMatch := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dowMatch := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	testcases := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "* * * *", wantErr: "expected 5 fields but found 4"},
+		{expr: "60 * * * *", wantErr: "value 60 in the minute field is out of range"},
+		{expr: "* * * foo *", wantErr: `invalid value "foo" in the month field`},
+		{expr: "*/0 * * * *", wantErr: `invalid step "0" in the minute field`},
+		{expr: "* 5-1 * * *", wantErr: `invalid range "5-1" in the hour field`},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := Parse(tc.expr)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// Wednesday
+	start := time.Date(2022, time.June, 1, 10, 30, 15, 0, time.UTC)
+
+	testcases := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "0 3 * * *", want: time.Date(2022, time.June, 2, 3, 0, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2022, time.June, 1, 10, 45, 0, 0, time.UTC)},
+		{expr: "@hourly", want: time.Date(2022, time.June, 1, 11, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", want: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 9 * * mon-fri", want: time.Date(2022, time.June, 2, 9, 30, 0, 0, time.UTC)},
+		{expr: "0 12 * * 7", want: time.Date(2022, time.June, 5, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 15 jan,mar *", want: time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		// When both the day of month and week are restricted, either may match
+		{expr: "0 0 10 * fri", want: time.Date(2022, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1-2 * sat", want: time.Date(2022, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 */5 * thu", want: time.Date(2022, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		// A step in the day of month or week restricts the days
+		{expr: "0 0 */2 * *", want: time.Date(2022, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * */3", want: time.Date(2022, time.June, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.expr, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			require.NoError(t, err)
+
+			next, err := s.Next(start)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, next)
+		})
+	}
+
+	t.Run("never due", func(t *testing.T) {
+		s, err := Parse("0 0 30 2 *")
+		require.NoError(t, err)
+
+		_, err = s.Next(start)
+		require.Error(t, err)
+	})
+}
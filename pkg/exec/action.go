@@ -93,6 +93,7 @@ var (
 	_ builder.ExecutableStep      = Step{}
 	_ builder.StepWithOutputs     = Step{}
 	_ builder.HasEnvironmentVars  = Step{}
+	_ builder.HasTimeout          = Step{}
 )
 
 type Step struct {
@@ -109,6 +110,7 @@ type Instruction struct {
 	EnvironmentVars map[string]string `yaml:"envs,omitempty"`
 	Outputs         []Output          `yaml:"outputs,omitempty"`
 	SuppressOutput  bool              `yaml:"suppress-output,omitempty"`
+	Timeout         string            `yaml:"timeout,omitempty"`
 
 	// Allow the user to ignore some errors
 	builder.IgnoreErrorHandler `yaml:"ignoreError,omitempty"`
@@ -138,6 +140,10 @@ func (s Step) SuppressesOutput() bool {
 	return s.SuppressOutput
 }
 
+func (s Step) GetTimeout() string {
+	return s.Timeout
+}
+
 func (s Step) GetOutputs() []builder.Output {
 	outputs := make([]builder.Output, len(s.Outputs))
 	for i := range s.Outputs {
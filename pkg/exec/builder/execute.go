@@ -8,6 +8,7 @@ import (
 	"io"
 	"os/exec"
 	"strings"
+	"time"
 
 	"get.porter.sh/porter/pkg/runtime"
 	"get.porter.sh/porter/pkg/tracing"
@@ -52,6 +53,13 @@ type SuppressesOutput interface {
 	SuppressesOutput() bool
 }
 
+// HasTimeout is implemented by mixin commands that are stopped when they do
+// not complete in time.
+type HasTimeout interface {
+	// GetTimeout returns how long the command may run, for example 5m.
+	GetTimeout() string
+}
+
 // HasErrorHandling is implemented by mixin commands that want to handle errors
 // themselves, and possibly allow failed commands to either pass, or to improve
 // the displayed error message
@@ -142,6 +150,20 @@ func ExecuteStep(ctx context.Context, cfg runtime.RuntimeConfig, step Executable
 		}
 	}
 
+	// Stop the command when it does not complete in time
+	var timeout time.Duration
+	if stepWithTimeout, ok := step.(HasTimeout); ok && stepWithTimeout.GetTimeout() != "" {
+		var parseErr error
+		timeout, parseErr = time.ParseDuration(stepWithTimeout.GetTimeout())
+		if parseErr != nil {
+			return "", span.Error(fmt.Errorf("invalid timeout %q: %w", stepWithTimeout.GetTimeout(), parseErr))
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	cmd := cfg.NewCommand(ctx, step.GetCommand(), args...)
 
 	// ensure command is executed in the correct directory
@@ -179,6 +201,11 @@ func ExecuteStep(ctx context.Context, cfg runtime.RuntimeConfig, step Executable
 
 	err = cmd.Wait()
 
+	// A command that timed out cannot recover from the error
+	if err != nil && timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+		return "", span.Error(fmt.Errorf("command %s timed out after %s: %w", prettyCmd, timeout, err))
+	}
+
 	// Check if the command knows how to handle and recover from its own errors
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
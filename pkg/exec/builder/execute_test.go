@@ -66,6 +66,15 @@ func TestExecuteSingleStepAction(t *testing.T) {
 	assert.True(t, exists, "jsonpath output was not evaluated")
 }
 
+func TestExecuteStep_InvalidTimeout(t *testing.T) {
+	ctx := context.Background()
+	c := porterruntime.NewTestRuntimeConfig(t)
+
+	step := TestStep{Command: "foo", Timeout: "soon"}
+	_, err := ExecuteStep(ctx, c.RuntimeConfig, step)
+	require.ErrorContains(t, err, `invalid timeout "soon"`)
+}
+
 func Test_splitCommand(t *testing.T) {
 	t.Run("split space", func(t *testing.T) {
 		result := splitCommand([]string{"cmd", "--myarg", "val1 val2"})
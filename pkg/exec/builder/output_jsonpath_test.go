@@ -19,6 +19,11 @@ type TestStep struct {
 	Outputs          []Output
 	WorkingDirectory string
 	EnvironmentVars  map[string]string
+	Timeout          string
+}
+
+func (s TestStep) GetTimeout() string {
+	return s.Timeout
 }
 
 func (s TestStep) GetCommand() string {
@@ -0,0 +1,128 @@
+package linter
+
+import (
+	"fmt"
+	"sort"
+
+	"get.porter.sh/porter/pkg/yaml"
+	"github.com/carolynvs/aferox"
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// ConfigFileName is the name of the lint configuration file that is loaded
+	// from the same directory as the porter manifest.
+	ConfigFileName = "porter-lint.yaml"
+
+	// RuleOff disables a rule in the lint configuration file.
+	RuleOff = "off"
+
+	// FailOnNone never fails because of the lint results.
+	FailOnNone = "none"
+)
+
+// Config customizes which lint rules are run, and the level of their results.
+type Config struct {
+	// FailOn is the lowest level of a result that causes linting to fail:
+	// error, warning, info or none.
+	FailOn string `yaml:"failOn,omitempty"`
+
+	// Rules maps the code of a rule to the level of its results,
+	// or off to disable the rule.
+	Rules map[Code]string `yaml:"rules,omitempty"`
+}
+
+// LoadConfig reads the lint configuration from a file.
+func LoadConfig(fs aferox.Aferox, path string) (Config, error) {
+	var cfg Config
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("could not read the lint configuration file at %s: %w", path, err)
+	}
+
+	if err = yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("could not parse the lint configuration file at %s: %w", path, err)
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid lint configuration file at %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate the lint configuration.
+func (c Config) Validate() error {
+	var bigErr *multierror.Error
+
+	if c.FailOn != "" && c.FailOn != FailOnNone {
+		if _, err := ParseLevel(c.FailOn); err != nil {
+			bigErr = multierror.Append(bigErr, fmt.Errorf("invalid failOn: %w", err))
+		}
+	}
+
+	codes := make([]string, 0, len(c.Rules))
+	for code := range c.Rules {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		value := c.Rules[Code(code)]
+		if value == RuleOff {
+			continue
+		}
+		if _, err := ParseLevel(value); err != nil {
+			bigErr = multierror.Append(bigErr, fmt.Errorf("invalid level %s for rule %s, allowed values are: error, warning, info, off", value, code))
+		}
+	}
+
+	return bigErr.ErrorOrNil()
+}
+
+// IsEnabled determines if a rule should be run.
+func (c Config) IsEnabled(code Code) bool {
+	return c.Rules[code] != RuleOff
+}
+
+// Apply the configuration to a set of results, changing the level of
+// the results and removing results from disabled rules.
+func (c Config) Apply(results Results) Results {
+	if len(c.Rules) == 0 {
+		return results
+	}
+
+	applied := make(Results, 0, len(results))
+	for _, result := range results {
+		value, ok := c.Rules[result.Code]
+		if ok {
+			if value == RuleOff {
+				continue
+			}
+			if level, err := ParseLevel(value); err == nil {
+				result.Level = level
+			}
+		}
+		applied = append(applied, result)
+	}
+	return applied
+}
+
+// GetFailLevel returns the lowest level of a result that causes linting to fail.
+// Returns false when failOn is not set or is none.
+func (c Config) GetFailLevel() (Level, bool) {
+	return ParseFailOn(c.FailOn)
+}
+
+// ParseFailOn converts a fail on value, such as warning, to the lowest level of a result
+// that causes linting to fail. Returns false when the value is empty or none.
+func ParseFailOn(value string) (Level, bool) {
+	if value == "" || value == FailOnNone {
+		return 0, false
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		return 0, false
+	}
+	return level, true
+}
@@ -0,0 +1,81 @@
+package linter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cxt := portercontext.NewTestContext(t)
+		cxt.AddTestFile("testdata/porter-lint.yaml", ConfigFileName)
+
+		cfg, err := LoadConfig(cxt.FileSystem, ConfigFileName)
+		require.NoError(t, err)
+		assert.Equal(t, "warning", cfg.FailOn)
+		assert.Equal(t, map[Code]string{"porter-100": "off", "porter-101": "info", "exec-100": "warning"}, cfg.Rules)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cxt := portercontext.NewTestContext(t)
+		cxt.AddTestFile("testdata/porter-lint-invalid.yaml", ConfigFileName)
+
+		_, err := LoadConfig(cxt.FileSystem, ConfigFileName)
+		tests.RequireErrorContains(t, err, "invalid failOn: invalid level sometimes")
+		tests.RequireErrorContains(t, err, "invalid level loud for rule porter-100")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		cxt := portercontext.NewTestContext(t)
+
+		_, err := LoadConfig(cxt.FileSystem, ConfigFileName)
+		tests.RequireErrorContains(t, err, "could not read the lint configuration file")
+	})
+}
+
+func TestConfig_Apply(t *testing.T) {
+	cfg := Config{
+		Rules: map[Code]string{
+			"porter-100": RuleOff,
+			"exec-100":   "info",
+		},
+	}
+
+	assert.False(t, cfg.IsEnabled("porter-100"))
+	assert.True(t, cfg.IsEnabled("porter-101"))
+
+	results := cfg.Apply(Results{
+		{Code: "porter-100", Level: LevelWarning},
+		{Code: "exec-100", Level: LevelError},
+		{Code: "exec-101", Level: LevelError},
+	})
+	assert.Equal(t, Results{
+		{Code: "exec-100", Level: LevelInfo},
+		{Code: "exec-101", Level: LevelError},
+	}, results)
+}
+
+func TestConfig_GetFailLevel(t *testing.T) {
+	testcases := []struct {
+		failOn    string
+		wantLevel Level
+		wantOK    bool
+	}{
+		{"", 0, false},
+		{"none", 0, false},
+		{"error", LevelError, true},
+		{"warning", LevelWarning, true},
+		{"info", LevelInfo, true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.failOn, func(t *testing.T) {
+			level, ok := Config{FailOn: tc.failOn}.GetFailLevel()
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantLevel, level)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"get.porter.sh/porter/pkg/manifest"
@@ -23,10 +24,31 @@ func (l Level) String() string {
 		return "error"
 	case LevelWarning:
 		return "warning"
+	case LevelInfo:
+		return "info"
 	}
 	return ""
 }
 
+// AtLeast determines if the level is as severe, or more severe, than the specified level.
+func (l Level) AtLeast(threshold Level) bool {
+	return l <= threshold
+}
+
+// ParseLevel converts the name of a level, such as warning, to a Level.
+func ParseLevel(value string) (Level, error) {
+	switch strings.ToLower(value) {
+	case "error":
+		return LevelError, nil
+	case "warning":
+		return LevelWarning, nil
+	case "info":
+		return LevelInfo, nil
+	default:
+		return 0, fmt.Errorf("invalid level %s, allowed values are: error, warning, info", value)
+	}
+}
+
 // Code representing the problem identified by the linter
 // Recommended to use the pattern MIXIN-NUMBER so that you don't collide with
 // codes from another mixin or with Porter's codes.
@@ -42,6 +64,9 @@ const (
 	// LevelWarning indicates a lint result is a warning about a best practice or identifies a problem that is not
 	// guaranteed to break the build.
 	LevelWarning Level = 2
+
+	// LevelInfo indicates a lint result is a suggestion that does not indicate a problem with the bundle.
+	LevelInfo Level = 4
 )
 
 // Result is a single item identified by the linter.
@@ -82,8 +107,12 @@ func (r Result) String() string {
 	return buffer.String()
 }
 
-// Location identifies the offending mixin step within a manifest.
+// Location identifies the offending mixin step, or the offending section, within a manifest.
 type Location struct {
+	// Path to the offending section of the manifest, for results that are not about a mixin step,
+	// for example parameters.password.
+	Path string `json:",omitempty"`
+
 	// Action containing the step, e.g. Install.
 	Action string
 
@@ -111,6 +140,9 @@ type Location struct {
 }
 
 func (l Location) String() string {
+	if l.Path != "" {
+		return l.Path
+	}
 	return fmt.Sprintf("%s: %s step in the %s mixin (%s)",
 		l.Action, humanize.Ordinal(l.StepNumber), l.Mixin, l.StepDescription)
 }
@@ -120,7 +152,6 @@ type Results []Result
 
 func (r Results) String() string {
 	var buffer strings.Builder
-	// TODO: Sort, display errors first
 	for _, result := range r {
 		buffer.WriteString(result.String())
 	}
@@ -130,35 +161,66 @@ func (r Results) String() string {
 
 // HasError checks if any of the results is an error.
 func (r Results) HasError() bool {
+	return r.CountAtLeast(LevelError) > 0
+}
+
+// CountAtLeast returns the number of results that are as severe, or more severe, than the specified level.
+func (r Results) CountAtLeast(threshold Level) int {
+	count := 0
 	for _, result := range r {
-		if result.Level == LevelError {
-			return true
+		if result.Level.AtLeast(threshold) {
+			count++
 		}
 	}
-	return false
+	return count
+}
+
+// Sort the results so that the most severe results are first.
+func (r Results) Sort() {
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Level < r[j].Level
+	})
 }
 
-// Linter manages executing the lint command for all affected mixins and reporting
-// the results.
+// Linter manages executing porter's lint rules and the lint command for all affected mixins,
+// and reporting the results.
 type Linter struct {
 	*portercontext.Context
 	Mixins pkgmgmt.PackageManager
+
+	// Rules is the registry of lint rules. Porter's rules are run against the manifest,
+	// and the rules run by the mixins are added to the registry as their results are reported.
+	Rules *Registry
+
+	// Config changes the level of the results for a rule, or disables the rule.
+	Config Config
 }
 
 func New(cxt *portercontext.Context, mixins pkgmgmt.PackageManager) *Linter {
 	return &Linter{
 		Context: cxt,
 		Mixins:  mixins,
+		Rules:   NewRegistry(DefaultRules()...),
 	}
 }
 
 func (l *Linter) Lint(ctx context.Context, m *manifest.Manifest) (Results, error) {
-	// TODO: perform any porter level linting
-	// e.g. metadata, credentials, properties, outputs, dependencies, etc
-
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
 
+	if err := l.Config.Validate(); err != nil {
+		return nil, span.Error(err)
+	}
+
+	span.Debug("Running porter's lint rules...")
+	var results Results
+	for _, rule := range l.Rules.Rules() {
+		if !l.Config.IsEnabled(rule.Definition().Code) {
+			continue
+		}
+		results = append(results, rule.Lint(ctx, m)...)
+	}
+
 	span.Debug("Running linters for each mixin used in the manifest...")
 	q := query.New(l.Context, l.Mixins)
 	responses, err := q.Execute(ctx, "lint", query.NewManifestGenerator(m))
@@ -166,7 +228,6 @@ func (l *Linter) Lint(ctx context.Context, m *manifest.Manifest) (Results, error
 		return nil, span.Error(err)
 	}
 
-	var results Results
 	for _, response := range responses {
 		if response.Error != nil {
 			// Ignore mixins that do not support the lint command
@@ -182,8 +243,19 @@ func (l *Linter) Lint(ctx context.Context, m *manifest.Manifest) (Results, error
 			return nil, span.Error(fmt.Errorf("unable to parse lint response from mixin %s: %w", response.Name, err))
 		}
 
+		for _, result := range r {
+			l.Rules.Define(RuleDefinition{
+				Code:   result.Code,
+				Level:  result.Level,
+				Title:  result.Title,
+				URL:    result.URL,
+				Source: response.Name,
+			})
+		}
 		results = append(results, r...)
 	}
 
+	results = l.Config.Apply(results)
+	results.Sort()
 	return results, nil
 }
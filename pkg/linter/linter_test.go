@@ -73,3 +73,43 @@ func TestLinter_Lint(t *testing.T) {
 	})
 
 }
+
+func TestLinter_Lint_Config(t *testing.T) {
+	ctx := context.Background()
+	cxt := portercontext.NewTestContext(t)
+	mixins := mixin.NewTestMixinProvider()
+	l := New(cxt.Context, mixins)
+	l.Config = Config{
+		Rules: map[Code]string{
+			CodeImageNotPinned: RuleOff,
+			"exec-101":         "error",
+		},
+	}
+	m := &manifest.Manifest{
+		Mixins: []manifest.MixinDeclaration{{Name: "exec"}},
+		ImageMap: map[string]manifest.MappedImage{
+			"whalesay": {Repository: "getporter/whalesay", Tag: "latest"},
+		},
+		Parameters: manifest.ParameterDefinitions{
+			"logLevel": {Name: "logLevel", ApplyTo: []string{"instal"}},
+		},
+	}
+	mixins.LintResults = Results{
+		{Level: LevelInfo, Code: "exec-102", Title: "suggestion"},
+		{Level: LevelWarning, Code: "exec-101", Title: "warning stuff isn't working"},
+	}
+
+	results, err := l.Lint(ctx, m)
+	require.NoError(t, err, "Lint failed")
+
+	var codes []Code
+	for _, r := range results {
+		codes = append(codes, r.Code)
+	}
+	require.Equal(t, []Code{CodeUndefinedApplyTo, "exec-101", "exec-102"}, codes, "disabled rules should be skipped and results sorted by level")
+	require.Equal(t, LevelError, results[1].Level, "the level of exec-101 should be changed by the configuration")
+
+	def, ok := l.Rules.Find("exec-101")
+	require.True(t, ok, "rules reported by a mixin should be defined in the registry")
+	require.Equal(t, "exec", def.Source)
+}
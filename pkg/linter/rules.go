@@ -0,0 +1,252 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/manifest"
+)
+
+const (
+	// CodeImageNotPinned is the linter code for when an image referenced by the bundle is not pinned to a digest.
+	CodeImageNotPinned Code = "porter-100"
+
+	// CodeDependencyNotPinned is the linter code for when a dependency does not specify a version.
+	CodeDependencyNotPinned Code = "porter-101"
+
+	// CodeUndefinedApplyTo is the linter code for when applyTo references an action that the bundle does not define.
+	CodeUndefinedApplyTo Code = "porter-102"
+
+	// porterRuleSource is the source of the rules that are run by Porter.
+	porterRuleSource = "porter"
+)
+
+// RuleDefinition describes a lint rule.
+type RuleDefinition struct {
+	// Code uniquely identifying the rule.
+	Code Code
+
+	// Level of the results reported by the rule, unless it is changed in the lint configuration.
+	Level Level
+
+	// Title of the rule.
+	Title string
+
+	// URL that provides additional information about the rule.
+	URL string
+
+	// Source of the rule, either porter or the name of the mixin that runs the rule.
+	Source string
+}
+
+// Rule checks a manifest for a problem.
+type Rule interface {
+	// Definition describes the rule.
+	Definition() RuleDefinition
+
+	// Lint the manifest and return a result for each problem found.
+	Lint(ctx context.Context, m *manifest.Manifest) Results
+}
+
+// Registry of lint rules. Rules that Porter runs are registered with an implementation,
+// while rules that are run by a mixin are only defined so that they can be described in the results.
+type Registry struct {
+	rules       []Rule
+	definitions map[Code]RuleDefinition
+}
+
+// NewRegistry creates a registry with the specified rules.
+func NewRegistry(rules ...Rule) *Registry {
+	r := &Registry{definitions: make(map[Code]RuleDefinition, len(rules))}
+	for _, rule := range rules {
+		if err := r.Register(rule); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register a rule that is run by Porter.
+func (r *Registry) Register(rule Rule) error {
+	def := rule.Definition()
+	if _, ok := r.definitions[def.Code]; ok {
+		return fmt.Errorf("a lint rule with code %s is already registered", def.Code)
+	}
+	r.rules = append(r.rules, rule)
+	r.definitions[def.Code] = def
+	return nil
+}
+
+// Define a rule that is run by a mixin, when the rule is not already defined.
+func (r *Registry) Define(def RuleDefinition) {
+	if _, ok := r.definitions[def.Code]; ok {
+		return
+	}
+	r.definitions[def.Code] = def
+}
+
+// Rules returns the rules that are run by Porter.
+func (r *Registry) Rules() []Rule {
+	return r.rules
+}
+
+// Find the definition of a rule by its code.
+func (r *Registry) Find(code Code) (RuleDefinition, bool) {
+	def, ok := r.definitions[code]
+	return def, ok
+}
+
+// Definitions returns the definitions of every rule, sorted by code.
+func (r *Registry) Definitions() []RuleDefinition {
+	defs := make([]RuleDefinition, 0, len(r.definitions))
+	for _, def := range r.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Code < defs[j].Code
+	})
+	return defs
+}
+
+// DefaultRules returns the rules that Porter runs against every manifest.
+func DefaultRules() []Rule {
+	return []Rule{
+		imageNotPinnedRule{},
+		dependencyNotPinnedRule{},
+		undefinedApplyToRule{},
+	}
+}
+
+// newResult creates a result for a problem found by one of porter's rules.
+func newResult(def RuleDefinition, path string, message string) Result {
+	return Result{
+		Level:    def.Level,
+		Location: Location{Path: path},
+		Code:     def.Code,
+		Title:    def.Title,
+		Message:  message,
+		URL:      def.URL,
+	}
+}
+
+// imageNotPinnedRule warns when an image is referenced by tag, because the
+// image used by the bundle changes when the tag is updated.
+type imageNotPinnedRule struct{}
+
+func (imageNotPinnedRule) Definition() RuleDefinition {
+	return RuleDefinition{
+		Code:   CodeImageNotPinned,
+		Level:  LevelWarning,
+		Title:  "Best Practice: Pin images to a digest",
+		URL:    "https://getporter.org/reference/linter/#porter-100",
+		Source: porterRuleSource,
+	}
+}
+
+func (r imageNotPinnedRule) Lint(ctx context.Context, m *manifest.Manifest) Results {
+	var results Results
+	for _, name := range sortedKeys(m.ImageMap) {
+		img := m.ImageMap[name]
+		if img.Digest != "" {
+			continue
+		}
+		results = append(results, newResult(r.Definition(), "images."+name,
+			fmt.Sprintf("The image %s is not pinned to a digest, so the image used by the bundle changes when the tag is updated. Set the digest of the image.", img.Repository)))
+	}
+	return results
+}
+
+// dependencyNotPinnedRule warns when a dependency does not specify which version of the bundle to use.
+type dependencyNotPinnedRule struct{}
+
+func (dependencyNotPinnedRule) Definition() RuleDefinition {
+	return RuleDefinition{
+		Code:   CodeDependencyNotPinned,
+		Level:  LevelWarning,
+		Title:  "Best Practice: Specify the version of a dependency",
+		URL:    "https://getporter.org/reference/linter/#porter-101",
+		Source: porterRuleSource,
+	}
+}
+
+func (r dependencyNotPinnedRule) Lint(ctx context.Context, m *manifest.Manifest) Results {
+	var results Results
+	for _, dep := range m.Dependencies.Requires {
+		if dep == nil || dep.Bundle.Version != "" {
+			continue
+		}
+
+		ref, err := cnab.ParseOCIReference(dep.Bundle.Reference)
+		if err != nil {
+			// Invalid references are reported when the manifest is validated
+			continue
+		}
+		if ref.HasDigest() || (ref.HasTag() && ref.Tag() != "latest") {
+			continue
+		}
+		results = append(results, newResult(r.Definition(), "dependencies.requires."+dep.Name,
+			fmt.Sprintf("The dependency %s does not specify a version, so a different bundle may be installed each time. Add a tag or digest to the reference, or set bundle.version.", dep.Bundle.Reference)))
+	}
+	return results
+}
+
+// undefinedApplyToRule reports parameters, credentials and outputs that apply to an action
+// that the bundle does not define, usually because the name of the action is misspelled.
+type undefinedApplyToRule struct{}
+
+func (undefinedApplyToRule) Definition() RuleDefinition {
+	return RuleDefinition{
+		Code:   CodeUndefinedApplyTo,
+		Level:  LevelError,
+		Title:  "applyTo references an undefined action",
+		URL:    "https://getporter.org/reference/linter/#porter-102",
+		Source: porterRuleSource,
+	}
+}
+
+func (r undefinedApplyToRule) Lint(ctx context.Context, m *manifest.Manifest) Results {
+	actions := map[string]bool{
+		cnab.ActionInstall:   true,
+		cnab.ActionUpgrade:   true,
+		cnab.ActionUninstall: true,
+	}
+	for action := range m.CustomActions {
+		actions[action] = true
+	}
+	for action := range m.CustomActionDefinitions {
+		actions[action] = true
+	}
+
+	var results Results
+	check := func(path string, applyTo []string) {
+		for _, action := range applyTo {
+			if actions[action] {
+				continue
+			}
+			results = append(results, newResult(r.Definition(), path,
+				fmt.Sprintf("%s applies to the %s action, which is not defined by the bundle.", path, action)))
+		}
+	}
+
+	for _, name := range sortedKeys(m.Parameters) {
+		check("parameters."+name, m.Parameters[name].ApplyTo)
+	}
+	for _, name := range sortedKeys(m.Credentials) {
+		check("credentials."+name, m.Credentials[name].ApplyTo)
+	}
+	for _, name := range sortedKeys(m.Outputs) {
+		check("outputs."+name, m.Outputs[name].ApplyTo)
+	}
+	return results
+}
+
+func sortedKeys[T any](items map[string]T) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package linter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(DefaultRules()...)
+
+	err := r.Register(imageNotPinnedRule{})
+	require.EqualError(t, err, "a lint rule with code porter-100 is already registered")
+
+	r.Define(RuleDefinition{Code: "exec-100", Level: LevelError, Title: "bash -c argument missing wrapping quotes", Source: "exec"})
+	r.Define(RuleDefinition{Code: CodeImageNotPinned, Level: LevelError, Title: "redefined", Source: "exec"})
+
+	def, ok := r.Find(CodeImageNotPinned)
+	require.True(t, ok, "expected the porter rule to be defined")
+	assert.Equal(t, "porter", def.Source, "defining a rule should not replace an existing definition")
+
+	var codes []Code
+	for _, def := range r.Definitions() {
+		codes = append(codes, def.Code)
+	}
+	assert.Equal(t, []Code{"exec-100", CodeImageNotPinned, CodeDependencyNotPinned, CodeUndefinedApplyTo}, codes, "definitions should be sorted by code")
+	assert.Len(t, r.Rules(), 3, "only rules run by porter should be returned")
+}
+
+func TestImageNotPinnedRule(t *testing.T) {
+	m := &manifest.Manifest{
+		ImageMap: map[string]manifest.MappedImage{
+			"pinned":   {Repository: "getporter/whalesay", Digest: "sha256:8b92b7269f59e3ed824e811a1ff1ee64f0d44c0218efefada57a4bebc2d7ef6f"},
+			"unpinned": {Repository: "getporter/whalesay", Tag: "v1.0.0"},
+		},
+	}
+
+	results := imageNotPinnedRule{}.Lint(context.Background(), m)
+	require.Len(t, results, 1)
+	assert.Equal(t, CodeImageNotPinned, results[0].Code)
+	assert.Equal(t, LevelWarning, results[0].Level)
+	assert.Equal(t, "images.unpinned", results[0].Location.String())
+}
+
+func TestDependencyNotPinnedRule(t *testing.T) {
+	m := &manifest.Manifest{
+		Dependencies: manifest.Dependencies{
+			Requires: []*manifest.Dependency{
+				{Name: "tag", Bundle: manifest.BundleCriteria{Reference: "localhost:5000/mysql:v0.1.0"}},
+				{Name: "digest", Bundle: manifest.BundleCriteria{Reference: "localhost:5000/mysql@sha256:8b92b7269f59e3ed824e811a1ff1ee64f0d44c0218efefada57a4bebc2d7ef6f"}},
+				{Name: "version", Bundle: manifest.BundleCriteria{Reference: "localhost:5000/mysql", Version: "v0.1.x"}},
+				{Name: "latest", Bundle: manifest.BundleCriteria{Reference: "localhost:5000/mysql:latest"}},
+				{Name: "untagged", Bundle: manifest.BundleCriteria{Reference: "localhost:5000/mysql"}},
+			},
+		},
+	}
+
+	results := dependencyNotPinnedRule{}.Lint(context.Background(), m)
+	require.Len(t, results, 2)
+	assert.Equal(t, "dependencies.requires.latest", results[0].Location.Path)
+	assert.Equal(t, "dependencies.requires.untagged", results[1].Location.Path)
+}
+
+func TestUndefinedApplyToRule(t *testing.T) {
+	m := &manifest.Manifest{
+		CustomActions: map[string]manifest.Steps{"status": nil},
+		Parameters: manifest.ParameterDefinitions{
+			"logLevel": {Name: "logLevel", ApplyTo: []string{"install", "status"}},
+			"password": {Name: "password", ApplyTo: []string{"instal"}},
+		},
+		Credentials: manifest.CredentialDefinitions{
+			"token": {Name: "token", ApplyTo: []string{"uninstall"}},
+		},
+		Outputs: manifest.OutputDefinitions{
+			"conn": {Name: "conn", ApplyTo: []string{"upgrade", "stats"}},
+		},
+	}
+
+	results := undefinedApplyToRule{}.Lint(context.Background(), m)
+	require.Len(t, results, 2)
+	assert.Equal(t, LevelError, results[0].Level)
+	assert.Equal(t, "parameters.password applies to the instal action, which is not defined by the bundle.", results[0].Message)
+	assert.Equal(t, "outputs.conn applies to the stats action, which is not defined by the bundle.", results[1].Message)
+}
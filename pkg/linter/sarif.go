@@ -0,0 +1,156 @@
+package linter
+
+import (
+	"get.porter.sh/porter/pkg"
+)
+
+const (
+	// SarifSchema is the schema of the SARIF log generated by the linter.
+	SarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// SarifVersion is the version of the SARIF log generated by the linter.
+	SarifVersion = "2.1.0"
+)
+
+// SarifLog is a Static Analysis Results Interchange Format (SARIF) log, used by
+// code scanning tools to display the results of the linter.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+// SarifRun is a single run of the linter.
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+// SarifTool describes the linter.
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+// SarifDriver describes the linter and the rules that it runs.
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+// SarifRule describes a lint rule.
+type SarifRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     SarifMessage           `json:"shortDescription"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	DefaultConfiguration SarifRuleConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+// SarifRuleConfiguration is the default configuration of a lint rule.
+type SarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SarifMessage is text displayed to the user.
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+// SarifResult is a problem identified by the linter.
+type SarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations"`
+}
+
+// SarifLocation identifies where the problem was found.
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SarifPhysicalLocation identifies the file that contains the problem.
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+}
+
+// SarifArtifactLocation is the path to a file.
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SarifLogicalLocation identifies the section of the manifest that contains the problem.
+type SarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLevel converts a level to a SARIF level.
+func sarifLevel(l Level) string {
+	switch l {
+	case LevelError:
+		return "error"
+	case LevelWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// NewSarifLog converts the results of the linter to a SARIF log. The manifestPath
+// is the path to the linted manifest, relative to the root of the repository.
+func NewSarifLog(results Results, rules *Registry, manifestPath string) SarifLog {
+	driver := SarifDriver{
+		Name:           "porter",
+		InformationURI: "https://getporter.org",
+		Version:        pkg.Version,
+		Rules:          []SarifRule{},
+	}
+	for _, def := range rules.Definitions() {
+		rule := SarifRule{
+			ID:                   string(def.Code),
+			ShortDescription:     SarifMessage{Text: def.Title},
+			HelpURI:              def.URL,
+			DefaultConfiguration: SarifRuleConfiguration{Level: sarifLevel(def.Level)},
+		}
+		if def.Source != "" {
+			rule.Properties = map[string]interface{}{"source": def.Source}
+		}
+		driver.Rules = append(driver.Rules, rule)
+	}
+
+	run := SarifRun{
+		Tool:    SarifTool{Driver: driver},
+		Results: make([]SarifResult, 0, len(results)),
+	}
+	for _, result := range results {
+		message := result.Message
+		if message == "" {
+			message = result.Title
+		}
+		run.Results = append(run.Results, SarifResult{
+			RuleID:  string(result.Code),
+			Level:   sarifLevel(result.Level),
+			Message: SarifMessage{Text: message},
+			Locations: []SarifLocation{
+				{
+					PhysicalLocation: SarifPhysicalLocation{
+						ArtifactLocation: SarifArtifactLocation{URI: manifestPath},
+					},
+					LogicalLocations: []SarifLogicalLocation{
+						{FullyQualifiedName: result.Location.String()},
+					},
+				},
+			},
+		})
+	}
+
+	return SarifLog{
+		Schema:  SarifSchema,
+		Version: SarifVersion,
+		Runs:    []SarifRun{run},
+	}
+}
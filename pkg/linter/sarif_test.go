@@ -0,0 +1,49 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSarifLog(t *testing.T) {
+	r := NewRegistry(DefaultRules()...)
+	r.Define(RuleDefinition{Code: "exec-100", Level: LevelError, Title: "bash -c argument missing wrapping quotes", Source: "exec"})
+
+	results := Results{
+		{
+			Level:    LevelInfo,
+			Location: Location{Path: "images.whalesay"},
+			Code:     CodeImageNotPinned,
+			Title:    "Best Practice: Pin images to a digest",
+			Message:  "The image is not pinned",
+		},
+		{
+			Level:    LevelError,
+			Location: Location{Action: "install", Mixin: "exec", StepNumber: 2, StepDescription: "Install Hello World"},
+			Code:     "exec-100",
+			Title:    "bash -c argument missing wrapping quotes",
+		},
+	}
+
+	log := NewSarifLog(results, r, "porter.yaml")
+	assert.Equal(t, SarifSchema, log.Schema)
+	assert.Equal(t, SarifVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "porter", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 4)
+	assert.Equal(t, "exec-100", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "error", run.Tool.Driver.Rules[0].DefaultConfiguration.Level)
+	assert.Equal(t, map[string]interface{}{"source": "exec"}, run.Tool.Driver.Rules[0].Properties)
+
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "porter-100", run.Results[0].RuleID)
+	assert.Equal(t, "note", run.Results[0].Level, "info results should be converted to notes")
+	assert.Equal(t, "The image is not pinned", run.Results[0].Message.Text)
+	assert.Equal(t, "porter.yaml", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "images.whalesay", run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(t, "bash -c argument missing wrapping quotes", run.Results[1].Message.Text, "the title should be used when a result does not have a message")
+}
@@ -0,0 +1,57 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StepWhenField is the name of the field on a step that defines the condition
+// that determines if the step is executed.
+const StepWhenField = "when"
+
+// EvaluateCondition evaluates the condition of a step, after its templates have been rendered.
+// A condition is either a boolean value, such as true or false, or a comparison of two values,
+// such as prod == prod or prod != dev. A condition may be negated with !.
+// An empty condition, for example an output that was not set, is false.
+func EvaluateCondition(condition string) (bool, error) {
+	condition = strings.TrimSpace(condition)
+
+	for _, op := range []string{"==", "!="} {
+		left, right, ok := strings.Cut(condition, op)
+		if !ok {
+			continue
+		}
+		equal := unquoteConditionValue(left) == unquoteConditionValue(right)
+		if op == "==" {
+			return equal, nil
+		}
+		return !equal, nil
+	}
+
+	if strings.HasPrefix(condition, "!") {
+		result, err := EvaluateCondition(condition[1:])
+		return !result, err
+	}
+
+	value := unquoteConditionValue(condition)
+	if value == "" {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition %q, it must evaluate to true or false, or compare two values with == or !=", condition)
+	}
+	return result, nil
+}
+
+// unquoteConditionValue removes whitespace and matching quotes around a value in a condition.
+func unquoteConditionValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 {
+		if (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
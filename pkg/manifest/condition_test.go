@@ -0,0 +1,44 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	testcases := []struct {
+		condition string
+		want      bool
+		wantErr   string
+	}{
+		{condition: "true", want: true},
+		{condition: "false", want: false},
+		{condition: " TRUE ", want: true},
+		{condition: "1", want: true},
+		{condition: "", want: false},
+		{condition: `""`, want: false},
+		{condition: "!false", want: true},
+		{condition: "!true", want: false},
+		{condition: "prod == prod", want: true},
+		{condition: `prod == "prod"`, want: true},
+		{condition: "prod == dev", want: false},
+		{condition: "prod != dev", want: true},
+		{condition: "'prod' != prod", want: false},
+		{condition: " == ", want: true},
+		{condition: "maybe", wantErr: `invalid condition "maybe", it must evaluate to true or false, or compare two values with == or !=`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.condition, func(t *testing.T) {
+			got, err := EvaluateCondition(tc.condition)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
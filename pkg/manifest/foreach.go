@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// StepForeachField is the name of the field on a step that defines the list
+	// that the step iterates over.
+	StepForeachField = "foreach"
+
+	// ForeachTemplateVariable is the name of the template variable that holds the current
+	// iteration of a step that uses foreach, for example ${ foreach.item } and ${ foreach.index }.
+	ForeachTemplateVariable = "foreach"
+)
+
+// ParseForeachItems returns the items that a step iterates over, from the value of its foreach field
+// after its templates have been rendered. The value is either a list, or a json array, such as the
+// value of an array parameter. An empty value, for example an output that was not set, has no items.
+func ParseForeachItems(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+
+		var items []interface{}
+		if err := json.Unmarshal([]byte(v), &items); err != nil {
+			return nil, fmt.Errorf("invalid foreach value %q, it must be a list or a json array: %w", v, err)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("invalid foreach value type (%T), it must be a list or a json array", value)
+	}
+}
@@ -0,0 +1,28 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForeachItems(t *testing.T) {
+	items, err := ParseForeachItems([]interface{}{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, items)
+
+	items, err = ParseForeachItems(`["a", {"name": "b"}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", map[string]interface{}{"name": "b"}}, items)
+
+	items, err = ParseForeachItems(" ")
+	require.NoError(t, err)
+	assert.Empty(t, items, "an empty value should not have any items")
+
+	_, err = ParseForeachItems("a,b")
+	require.ErrorContains(t, err, `invalid foreach value "a,b", it must be a list or a json array`)
+
+	_, err = ParseForeachItems(5)
+	require.EqualError(t, err, "invalid foreach value type (int), it must be a list or a json array")
+}
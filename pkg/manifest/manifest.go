@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"path"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/config"
@@ -61,6 +64,18 @@ type Manifest struct {
 
 	Maintainers []MaintainerDefinition `yaml:"maintainers,omitempty"`
 
+	// License of the bundle, for example an SPDX license identifier such as Apache-2.0.
+	License string `yaml:"license,omitempty"`
+
+	// Documentation is the url of the documentation for the bundle.
+	Documentation string `yaml:"documentation,omitempty"`
+
+	// Annotations are arbitrary key/value pairs describing the bundle.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+
+	// MinimumPorterVersion is the oldest version of Porter that can run the bundle.
+	MinimumPorterVersion string `yaml:"minimumPorterVersion,omitempty"`
+
 	// Registry is the OCI registry and org/subdomain for the bundle
 	Registry string `yaml:"registry,omitempty"`
 
@@ -90,6 +105,21 @@ type Manifest struct {
 	CustomActions           map[string]Steps                  `yaml:"-"`
 	CustomActionDefinitions map[string]CustomActionDefinition `yaml:"customActions,omitempty"`
 
+	// Retry defines how failed steps are retried, keyed by the name of the action.
+	Retry RetryPolicies `yaml:"retry,omitempty"`
+
+	// Timeout defines how long an action may run, and how to clean up when
+	// it times out, keyed by the name of the action.
+	Timeout TimeoutPolicies `yaml:"timeout,omitempty"`
+
+	// Hooks are commands that Porter runs on the host before or after the bundle
+	// executes an action, keyed by the name of the hook, for example preInstall.
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// Metrics records how long each step of an action took to execute on the run.
+	// It adds an internal output to the bundle, so it is only enabled when requested.
+	Metrics bool `yaml:"metrics,omitempty"`
+
 	StateBag     StateBag              `yaml:"state,omitempty"`
 	Parameters   ParameterDefinitions  `yaml:"parameters,omitempty"`
 	Credentials  CredentialDefinitions `yaml:"credentials,omitempty"`
@@ -124,6 +154,12 @@ func (m *Manifest) Validate(cxt *portercontext.Context, strategy schema.CheckStr
 		result = multierror.Append(result, errors.New("no mixins declared"))
 	}
 
+	for _, mixin := range m.Mixins {
+		if err = mixin.Validate(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
 	if m.Install == nil {
 		result = multierror.Append(result, errors.New("no install action defined"))
 	}
@@ -147,6 +183,44 @@ func (m *Manifest) Validate(cxt *portercontext.Context, strategy schema.CheckStr
 		}
 	}
 
+	for actionName, def := range m.CustomActionDefinitions {
+		if len(def.Arguments) == 0 {
+			continue
+		}
+		if _, ok := m.CustomActions[actionName]; !ok {
+			result = multierror.Append(result, fmt.Errorf("arguments defined for custom action %s which is not defined by the bundle", actionName))
+			continue
+		}
+		err = def.validateArguments(actionName)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	for actionName, policy := range m.Retry {
+		err = m.validateRetryPolicy(actionName, policy)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	for actionName, policy := range m.Timeout {
+		err = m.validateTimeoutPolicy(actionName, policy)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	err = m.validateHooks()
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	err = m.StateBag.Validate(m)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+
 	for _, dep := range m.Dependencies.Requires {
 		err = dep.Validate(cxt)
 		if err != nil {
@@ -154,6 +228,11 @@ func (m *Manifest) Validate(cxt *portercontext.Context, strategy schema.CheckStr
 		}
 	}
 
+	err = m.validateDependencyParameters()
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+
 	for _, output := range m.Outputs {
 		err = output.Validate()
 		if err != nil {
@@ -178,6 +257,89 @@ func (m *Manifest) Validate(cxt *portercontext.Context, strategy schema.CheckStr
 	return result
 }
 
+// isActionDefined determines if the bundle defines the specified action.
+func (m *Manifest) isActionDefined(actionName string) bool {
+	switch actionName {
+	case cnab.ActionInstall, cnab.ActionUpgrade, cnab.ActionUninstall:
+		return true
+	default:
+		_, ok := m.CustomActions[actionName]
+		return ok
+	}
+}
+
+// GetSteps returns the steps that the bundle executes for the specified action.
+func (m *Manifest) GetSteps(actionName string) Steps {
+	switch actionName {
+	case cnab.ActionInstall:
+		return m.Install
+	case cnab.ActionUpgrade:
+		return m.Upgrade
+	case cnab.ActionUninstall:
+		return m.Uninstall
+	default:
+		return m.CustomActions[actionName]
+	}
+}
+
+func (m *Manifest) validateRetryPolicy(actionName string, policy RetryPolicy) error {
+	if !m.isActionDefined(actionName) {
+		return fmt.Errorf("retry policy defined for action %s which is not defined by the bundle", actionName)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid retry policy for action %s: %w", actionName, err)
+	}
+	return nil
+}
+
+func (m *Manifest) validateTimeoutPolicy(actionName string, policy TimeoutPolicy) error {
+	if !m.isActionDefined(actionName) {
+		return fmt.Errorf("timeout policy defined for action %s which is not defined by the bundle", actionName)
+	}
+
+	if _, err := policy.GetDuration(); err != nil {
+		return fmt.Errorf("invalid timeout policy for action %s: %w", actionName, err)
+	}
+
+	if err := policy.Cleanup.Validate(m); err != nil {
+		return fmt.Errorf("invalid timeout policy for action %s: invalid cleanup step: %w", actionName, err)
+	}
+	return nil
+}
+
+func (m *Manifest) validateHooks() error {
+	if len(m.Hooks) == 0 {
+		return nil
+	}
+
+	// Determine the hooks that may be defined for the actions of the bundle
+	hookNames := make(map[string]struct{}, 2*(3+len(m.CustomActions)))
+	actions := []string{cnab.ActionInstall, cnab.ActionUpgrade, cnab.ActionUninstall}
+	for action := range m.CustomActions {
+		actions = append(actions, action)
+	}
+	for _, action := range actions {
+		hookNames[GetHookName(HookStagePre, action)] = struct{}{}
+		hookNames[GetHookName(HookStagePost, action)] = struct{}{}
+	}
+
+	var result error
+	for hookName, hooks := range m.Hooks {
+		if _, ok := hookNames[hookName]; !ok {
+			result = multierror.Append(result, fmt.Errorf("invalid hook %s, hooks must be named pre or post followed by the name of an action defined by the bundle, for example preInstall", hookName))
+			continue
+		}
+
+		for i, hook := range hooks {
+			if err := hook.Validate(); err != nil {
+				result = multierror.Append(result, fmt.Errorf("invalid hook %s[%d]: %w", hookName, i, err))
+			}
+		}
+	}
+	return result
+}
+
 func (m *Manifest) validateMetadata(cxt *portercontext.Context, strategy schema.CheckStrategy) error {
 	if warnOnly, err := schema.ValidateSchemaVersion(strategy, SupportedSchemaVersions, m.SchemaVersion, DefaultSchemaVersion); err != nil {
 		if warnOnly {
@@ -209,6 +371,24 @@ func (m *Manifest) validateMetadata(cxt *portercontext.Context, strategy schema.
 		}
 		m.Version = v.String()
 	}
+
+	if m.Documentation != "" {
+		if u, err := url.Parse(m.Documentation); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("documentation %q is not a valid url", m.Documentation)
+		}
+	}
+
+	for key := range m.Annotations {
+		if strings.TrimSpace(key) == "" {
+			return errors.New("annotation keys must not be empty")
+		}
+	}
+
+	if m.MinimumPorterVersion != "" {
+		if _, err := semver.NewVersion(m.MinimumPorterVersion); err != nil {
+			return fmt.Errorf("minimumPorterVersion %q is not a valid semver value: %w", m.MinimumPorterVersion, err)
+		}
+	}
 	return nil
 }
 
@@ -380,7 +560,12 @@ func (pd *ParameterDefinition) Validate() error {
 			result = multierror.Append(result, fmt.Errorf("encountered error while validating parameter %s: %w", pdCopy.Name, err))
 		}
 		for _, schemaValidationErr := range schemaValidationErrs {
-			result = multierror.Append(result, fmt.Errorf("encountered an error validating the default value %v for parameter %q: %s", pdCopy.Default, pdCopy.Name, schemaValidationErr.Error))
+			msg := schemaValidationErr.Error
+			if schemaValidationErr.Path != "" && schemaValidationErr.Path != "/" {
+				// Identify the invalid value in an array or object parameter
+				msg = fmt.Sprintf("%s: %s", schemaValidationErr.Path, msg)
+			}
+			result = multierror.Append(result, fmt.Errorf("encountered an error validating the default value %v for parameter %q: %s", pdCopy.Default, pdCopy.Name, msg))
 		}
 	}
 
@@ -511,6 +696,11 @@ func (l Location) IsEmpty() bool {
 type MixinDeclaration struct {
 	Name   string
 	Config interface{}
+
+	// Version is a semver constraint for the version of the mixin required by
+	// the bundle, for example 1.0.x. The exact versions that are used are
+	// recorded in porter.lock by porter mixins ensure.
+	Version string
 }
 
 // UnmarshalYAML allows mixin declarations to either be a normal list of strings
@@ -521,6 +711,14 @@ type MixinDeclaration struct {
 //   - az:
 //     extensions:
 //   - iot
+//
+// or to require a version of the mixin, with optional config data
+//
+//	mixins:
+//	- name: terraform
+//	  version: 1.0.x
+//	  config:
+//	    clientVersion: 1.3.2
 func (m *MixinDeclaration) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	// First try to just read the mixin name
 	var mixinNameOnly string
@@ -540,7 +738,14 @@ func (m *MixinDeclaration) UnmarshalYAML(unmarshal func(interface{}) error) erro
 
 	if len(mixinWithConfig) == 0 {
 		return errors.New("mixin declaration was empty")
-	} else if len(mixinWithConfig) > 1 {
+	}
+
+	// Check if the mixin was declared with its name and version
+	if name, ok := mixinWithConfig["name"].(string); ok && isVersionedDeclaration(mixinWithConfig) {
+		return m.unmarshalVersionedDeclaration(name, mixinWithConfig)
+	}
+
+	if len(mixinWithConfig) > 1 {
 		return errors.New("mixin declaration contained more than one mixin")
 	}
 
@@ -552,6 +757,44 @@ func (m *MixinDeclaration) UnmarshalYAML(unmarshal func(interface{}) error) erro
 	return nil
 }
 
+// isVersionedDeclaration determines if a mixin declaration uses the name,
+// version and config fields, instead of the name of the mixin as the key.
+func isVersionedDeclaration(raw map[string]interface{}) bool {
+	if _, ok := raw["version"]; ok {
+		return true
+	}
+
+	for field := range raw {
+		if field != "name" && field != "config" {
+			return false
+		}
+	}
+	return true
+}
+
+// unmarshalVersionedDeclaration reads a mixin declaration that uses the
+// name, version and config fields.
+func (m *MixinDeclaration) unmarshalVersionedDeclaration(name string, raw map[string]interface{}) error {
+	m.Name = name
+	m.Config = raw["config"]
+	m.Version = ""
+
+	for field, value := range raw {
+		switch field {
+		case "name", "config":
+		case "version":
+			version, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid version for the %s mixin declaration, expected a string but got %T", name, value)
+			}
+			m.Version = version
+		default:
+			return fmt.Errorf("invalid field %s in the %s mixin declaration, allowed fields are: name, version, config", field, name)
+		}
+	}
+	return nil
+}
+
 // MarshalYAML allows mixin declarations to either be a normal list of strings
 // mixins:
 // - exec
@@ -560,7 +803,18 @@ func (m *MixinDeclaration) UnmarshalYAML(unmarshal func(interface{}) error) erro
 //   - az:
 //     extensions:
 //   - iot
+//
+// Mixins that require a version are declared with the name, version and config fields.
 func (m MixinDeclaration) MarshalYAML() (interface{}, error) {
+	if m.Version != "" {
+		raw := struct {
+			Name    string      `yaml:"name"`
+			Version string      `yaml:"version"`
+			Config  interface{} `yaml:"config,omitempty"`
+		}{m.Name, m.Version, m.Config}
+		return raw, nil
+	}
+
 	if m.Config == nil {
 		return m.Name, nil
 	}
@@ -571,6 +825,18 @@ func (m MixinDeclaration) MarshalYAML() (interface{}, error) {
 	return raw, nil
 }
 
+// Validate the mixin declaration.
+func (m MixinDeclaration) Validate() error {
+	if m.Version == "" {
+		return nil
+	}
+
+	if _, err := semver.NewConstraint(m.Version); err != nil {
+		return fmt.Errorf("invalid version %q for the %s mixin, expected a semver constraint such as 1.0.x: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
+
 type MappedImage struct {
 	Description string            `yaml:"description"`
 	ImageType   string            `yaml:"imageType"`
@@ -632,9 +898,32 @@ type Dependency struct {
 
 	Bundle BundleCriteria `yaml:"bundle"`
 
+	// Sharing specifies if the dependency may be satisfied by an existing installation.
+	// Requires the dependencies-v2 experimental feature.
+	Sharing DependencySharing `yaml:"sharing,omitempty"`
+
+	// Parameters to pass to the dependency. With the dependencies-v2 experimental feature,
+	// the values may reference the parameters of the bundle, ${ bundle.parameters.NAME },
+	// or the outputs of a dependency that is executed before it, ${ bundle.dependencies.DEPENDENCY.outputs.NAME }.
 	Parameters map[string]string `yaml:"parameters,omitempty"`
 }
 
+// DependencySharing specifies how a dependency is shared with other installations.
+type DependencySharing struct {
+	// Mode is either none, the default, or group, which shares the dependency
+	// with the other installations in the sharing group.
+	Mode string `yaml:"mode,omitempty"`
+
+	// Group of installations that share the dependency.
+	Group DependencySharingGroup `yaml:"group,omitempty"`
+}
+
+// DependencySharingGroup is a set of installations that share a dependency.
+type DependencySharingGroup struct {
+	// Name of the sharing group.
+	Name string `yaml:"name"`
+}
+
 type BundleCriteria struct {
 	// Reference is the full bundle reference for the dependency
 	// in the format REGISTRY/NAME:TAG
@@ -661,13 +950,252 @@ func (d *Dependency) Validate(cxt *portercontext.Context) error {
 		return fmt.Errorf("reference for dependency %q can only specify REGISTRY/NAME when version ranges are specified", d.Name)
 	}
 
+	if len(d.Bundle.Version) > 0 {
+		if _, err := semver.NewConstraint(d.Bundle.Version); err != nil {
+			return fmt.Errorf("invalid version range %q for dependency %q: %w", d.Bundle.Version, d.Name, err)
+		}
+	}
+
+	switch d.Sharing.Mode {
+	case "", "none":
+		if d.Sharing.Group.Name != "" {
+			return fmt.Errorf("dependency %q specifies a sharing group but the sharing mode is not group", d.Name)
+		}
+	case "group":
+		if d.Sharing.Group.Name == "" {
+			return fmt.Errorf("dependency %q must specify the name of the sharing group", d.Name)
+		}
+	default:
+		return fmt.Errorf("invalid sharing mode %q for dependency %q, allowed values are: none, group", d.Sharing.Mode, d.Name)
+	}
+
 	return nil
 }
 
+// validateDependencyParameters checks that the parameters passed to each dependency
+// only reference parameters of the bundle, or the outputs of a dependency that is
+// executed before it.
+func (m *Manifest) validateDependencyParameters() error {
+	var result error
+	previousDeps := make(map[string]struct{}, len(m.Dependencies.Requires))
+	for _, dep := range m.Dependencies.Requires {
+		for paramName, value := range dep.Parameters {
+			for _, ref := range ParseDependencyParameterReferences(value) {
+				if ref.Dependency == "" {
+					if _, ok := m.Parameters[ref.Name]; !ok {
+						result = multierror.Append(result, fmt.Errorf("invalid dependencies.%s.parameters.%s, parameter %s is not defined in the bundle", dep.Name, paramName, ref.Name))
+					}
+					continue
+				}
+				if _, ok := previousDeps[ref.Dependency]; !ok {
+					result = multierror.Append(result, fmt.Errorf("invalid dependencies.%s.parameters.%s, dependency %s must be declared before %s to use its outputs", dep.Name, paramName, ref.Dependency, dep.Name))
+				}
+			}
+		}
+		previousDeps[dep.Name] = struct{}{}
+	}
+	return result
+}
+
+// DependencyParameterReference is a reference to a parameter of the bundle, or an
+// output of a dependency, in the value of a parameter passed to a dependency.
+type DependencyParameterReference struct {
+	// Template that was matched, e.g. ${ bundle.parameters.NAME }
+	Template string
+
+	// Dependency that generates the output, empty for parameters of the bundle.
+	Dependency string
+
+	// Name of the parameter or output.
+	Name string
+}
+
+var dependencyParameterReferenceRegex = regexp.MustCompile(`\$\{\s*bundle\.(?:parameters\.([^\s}]+)|dependencies\.([^.\s}]+)\.outputs\.([^\s}]+))\s*\}`)
+
+// ParseDependencyParameterReferences returns the parameters and dependency outputs
+// referenced in the value of a parameter passed to a dependency.
+func ParseDependencyParameterReferences(value string) []DependencyParameterReference {
+	matches := dependencyParameterReferenceRegex.FindAllStringSubmatch(value, -1)
+	refs := make([]DependencyParameterReference, 0, len(matches))
+	for _, match := range matches {
+		if match[1] != "" {
+			refs = append(refs, DependencyParameterReference{Template: match[0], Name: match[1]})
+		} else {
+			refs = append(refs, DependencyParameterReference{Template: match[0], Dependency: match[2], Name: match[3]})
+		}
+	}
+	return refs
+}
+
 type CustomActionDefinition struct {
 	Description       string `yaml:"description,omitempty"`
 	ModifiesResources bool   `yaml:"modifies,omitempty"`
 	Stateless         bool   `yaml:"stateless,omitempty"`
+
+	// Arguments that may be passed to the action with porter invoke --arg NAME=VALUE.
+	// The values are available to the steps of the action as ${ bundle.arguments.NAME }.
+	Arguments []CustomActionArgument `yaml:"arguments,omitempty"`
+}
+
+// CustomActionArgument is an argument that may be passed to a custom action when it is invoked.
+type CustomActionArgument struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+
+	// Default value of the argument when it is not specified.
+	Default string `yaml:"default,omitempty"`
+
+	// Required indicates that the argument must be specified when the action is invoked.
+	Required bool `yaml:"required,omitempty"`
+}
+
+// validateArguments checks that the arguments of a custom action are named and unique.
+func (d CustomActionDefinition) validateArguments(actionName string) error {
+	var result error
+	names := make(map[string]struct{}, len(d.Arguments))
+	for _, arg := range d.Arguments {
+		if arg.Name == "" {
+			result = multierror.Append(result, fmt.Errorf("an argument of custom action %s is missing a name", actionName))
+			continue
+		}
+		if strings.ContainsAny(arg.Name, "=.# ") {
+			result = multierror.Append(result, fmt.Errorf("invalid argument name %s for custom action %s, it must not contain =, ., # or spaces", arg.Name, actionName))
+		}
+		if _, ok := names[arg.Name]; ok {
+			result = multierror.Append(result, fmt.Errorf("argument %s is declared more than once for custom action %s", arg.Name, actionName))
+		}
+		names[arg.Name] = struct{}{}
+		if arg.Required && arg.Default != "" {
+			result = multierror.Append(result, fmt.Errorf("argument %s of custom action %s is required and cannot have a default", arg.Name, actionName))
+		}
+	}
+	return result
+}
+
+// RetryPolicies maps the name of an action to how its failed steps are retried.
+type RetryPolicies map[string]RetryPolicy
+
+// RetryPolicy defines how failed steps of an action are retried, so that
+// transient failures do not fail the entire action.
+type RetryPolicy struct {
+	// Retries is the number of times a failed step is retried before the action fails.
+	Retries int `yaml:"retries,omitempty"`
+
+	// Backoff is how long to wait before retrying a failed step, for example 30s.
+	// The wait is doubled after each retry.
+	Backoff string `yaml:"backoff,omitempty"`
+}
+
+// DefaultRetryBackoff is how long to wait before retrying a failed step when
+// the retry policy does not specify a backoff.
+const DefaultRetryBackoff = 10 * time.Second
+
+func (p RetryPolicy) Validate() error {
+	if p.Retries < 0 {
+		return errors.New("retries must not be negative")
+	}
+
+	_, err := p.GetBackoff()
+	return err
+}
+
+// GetBackoff returns how long to wait before the first retry of a failed step.
+func (p RetryPolicy) GetBackoff() (time.Duration, error) {
+	if p.Backoff == "" {
+		return DefaultRetryBackoff, nil
+	}
+
+	backoff, err := time.ParseDuration(p.Backoff)
+	if err != nil {
+		return 0, fmt.Errorf("invalid backoff %q: %w", p.Backoff, err)
+	}
+	if backoff < 0 {
+		return 0, fmt.Errorf("invalid backoff %q: must not be negative", p.Backoff)
+	}
+	return backoff, nil
+}
+
+// TimeoutPolicies maps the name of an action to how long it may run.
+type TimeoutPolicies map[string]TimeoutPolicy
+
+// TimeoutPolicy defines how long an action may run, and the steps that clean up
+// after the action when it, or one of its steps, times out.
+type TimeoutPolicy struct {
+	// Duration that the action may run before it times out, for example 30m.
+	// When empty, the action does not time out, though its steps may define a timeout.
+	Duration string `yaml:"duration,omitempty"`
+
+	// Cleanup steps that are executed after the action times out.
+	Cleanup Steps `yaml:"cleanup,omitempty"`
+}
+
+// GetDuration returns how long the action may run, or 0 when it does not time out.
+func (p TimeoutPolicy) GetDuration() (time.Duration, error) {
+	return parseTimeout(p.Duration)
+}
+
+func parseTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", value, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must not be negative", value)
+	}
+	return timeout, nil
+}
+
+const (
+	// HookStagePre is the stage of hooks that run before an action.
+	HookStagePre = "pre"
+
+	// HookStagePost is the stage of hooks that run after an action.
+	HookStagePost = "post"
+)
+
+// Hooks maps the name of a hook, such as preInstall or postUpgrade, to the
+// commands that Porter runs on the host at that point.
+type Hooks map[string][]Hook
+
+// GetHooks returns the hooks that run at the specified stage, pre or post, of an action.
+func (h Hooks) GetHooks(stage string, action string) []Hook {
+	return h[GetHookName(stage, action)]
+}
+
+// GetHookName returns the name of the hook that runs at the specified stage of
+// an action, for example preInstall.
+func GetHookName(stage string, action string) string {
+	if action == "" {
+		return stage
+	}
+	return stage + strings.ToUpper(action[:1]) + action[1:]
+}
+
+// Hook is a command that Porter runs on the host, outside of the bundle, before
+// or after an action, for example to send a notification or run smoke tests.
+type Hook struct {
+	// Description of the hook, printed when the hook runs.
+	Description string `yaml:"description,omitempty"`
+
+	// Command to execute.
+	Command string `yaml:"command"`
+
+	// Arguments to pass to the command.
+	Arguments []string `yaml:"arguments,omitempty"`
+
+	// Env contains additional environment variables set for the command.
+	Env map[string]string `yaml:"env,omitempty"`
+}
+
+func (h Hook) Validate() error {
+	if h.Command == "" {
+		return errors.New("command is required")
+	}
+	return nil
 }
 
 // OutputDefinitions allows us to represent parameters as a list in the YAML
@@ -812,6 +1340,18 @@ func (s *Step) Validate(m *Manifest) error {
 		return err
 	}
 
+	if _, err := s.GetTimeout(); err != nil {
+		return err
+	}
+
+	if _, _, err := s.GetWhen(); err != nil {
+		return err
+	}
+
+	if _, _, err := s.GetForeach(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -836,6 +1376,74 @@ func (s *Step) GetDescription() (string, error) {
 	return desc, nil
 }
 
+// GetTimeout returns how long the step may run before it is stopped, or 0 when
+// the step does not define a timeout.
+func (s *Step) GetTimeout() (time.Duration, error) {
+	if s.Data == nil {
+		return 0, errors.New("empty step data")
+	}
+
+	mixinName := s.GetMixinName()
+	children, _ := s.Data[mixinName].(map[string]interface{})
+	t, ok := children["timeout"]
+	if !ok {
+		return 0, nil
+	}
+	timeout, ok := t.(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid timeout type (%T) for mixin step (%s)", t, mixinName)
+	}
+
+	return parseTimeout(timeout)
+}
+
+// GetWhen returns the condition that determines if the step is executed, and
+// if the step defines a condition. Steps without a condition are always executed.
+func (s *Step) GetWhen() (string, bool, error) {
+	if s.Data == nil {
+		return "", false, errors.New("empty step data")
+	}
+
+	mixinName := s.GetMixinName()
+	children, _ := s.Data[mixinName].(map[string]interface{})
+	w, ok := children[StepWhenField]
+	if !ok {
+		return "", false, nil
+	}
+
+	switch when := w.(type) {
+	case string:
+		return when, true, nil
+	case bool:
+		// A template that rendered a boolean parameter is parsed as a bool
+		return strconv.FormatBool(when), true, nil
+	default:
+		return "", false, fmt.Errorf("invalid when type (%T) for mixin step (%s)", w, mixinName)
+	}
+}
+
+// GetForeach returns the list that the step iterates over, and if the step defines
+// a list. The list is either a template, such as ${ bundle.parameters.regions }, or a yaml list.
+func (s *Step) GetForeach() (interface{}, bool, error) {
+	if s.Data == nil {
+		return nil, false, errors.New("empty step data")
+	}
+
+	mixinName := s.GetMixinName()
+	children, _ := s.Data[mixinName].(map[string]interface{})
+	f, ok := children[StepForeachField]
+	if !ok {
+		return nil, false, nil
+	}
+
+	switch f.(type) {
+	case string, []interface{}:
+		return f, true, nil
+	default:
+		return nil, false, fmt.Errorf("invalid foreach type (%T) for mixin step (%s)", f, mixinName)
+	}
+}
+
 func (s *Step) GetMixinName() string {
 	var mixinName string
 	for k := range s.Data {
@@ -1018,18 +1626,40 @@ func readFromURL(path string) ([]byte, error) {
 	return data, nil
 }
 
-func ReadManifestData(cxt *portercontext.Context, path string) ([]byte, error) {
-	if strings.HasPrefix(path, "http") {
+func readManifestData(cxt *portercontext.Context, path string) ([]byte, error) {
+	if isManifestURL(path) {
 		return readFromURL(path)
 	} else {
 		return readFromFile(cxt, path)
 	}
 }
 
+// ReadManifestData reads the contents of the manifest at the specified path or
+// URL. Reading the manifest from a URL is not allowed in offline mode.
+func ReadManifestData(config *config.Config, path string) ([]byte, error) {
+	if err := checkNetworkAccess(config, path); err != nil {
+		return nil, err
+	}
+	return readManifestData(config.Context, path)
+}
+
+func isManifestURL(path string) bool {
+	return strings.HasPrefix(path, "http")
+}
+
+// checkNetworkAccess returns an error when the manifest is read from a URL
+// while Porter is in offline mode.
+func checkNetworkAccess(config *config.Config, path string) error {
+	if !isManifestURL(path) {
+		return nil
+	}
+	return config.CheckNetworkAccess(fmt.Sprintf("read the manifest from %s", path))
+}
+
 // ReadManifest determines if specified path is a URL or a filepath.
 // After reading the data in the path it returns a Manifest and any errors
 func ReadManifest(cxt *portercontext.Context, path string) (*Manifest, error) {
-	data, err := ReadManifestData(cxt, path)
+	data, err := readManifestData(cxt, path)
 	if err != nil {
 		return nil, err
 	}
@@ -1090,7 +1720,8 @@ func (m *Manifest) scanManifestTemplating(data []byte) (templateScanResult, erro
 			continue
 		}
 
-		vars[tag.Name()] = struct{}{}
+		// Only track the variable, ignoring any template functions applied to it
+		vars[GetTemplateVariableName(tag.Name())] = struct{}{}
 	}
 
 	result := templateScanResult{
@@ -1110,6 +1741,10 @@ func LoadManifestFrom(ctx context.Context, config *config.Config, file string) (
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
 
+	if err := checkNetworkAccess(config, file); err != nil {
+		return nil, err
+	}
+
 	m, err := ReadManifest(config.Context, file)
 	if err != nil {
 		return nil, err
@@ -1210,3 +1845,50 @@ type StateVariable struct {
 	// Location defines where the state variable is located in the bundle.
 	Location `yaml:",inline"`
 }
+
+// Validate the state variables declared by the bundle.
+func (s StateBag) Validate(m *Manifest) error {
+	var result error
+
+	names := make(map[string]struct{}, len(s))
+	paths := make(map[string]string, len(s))
+	for _, variable := range s {
+		if variable.Name == "" {
+			result = multierror.Append(result, fmt.Errorf("state variable for path %q is missing a name", variable.Path))
+			continue
+		}
+		if _, ok := names[variable.Name]; ok {
+			result = multierror.Append(result, fmt.Errorf("state variable %s is declared more than once", variable.Name))
+		}
+		names[variable.Name] = struct{}{}
+
+		if strings.Contains(variable.Name, "/") {
+			result = multierror.Append(result, fmt.Errorf("invalid state variable name %s, it must not contain a slash", variable.Name))
+		}
+
+		if variable.Path == "" {
+			result = multierror.Append(result, fmt.Errorf("state variable %s is missing a path", variable.Name))
+			continue
+		}
+		statePath := path.Clean(variable.Path)
+		if other, ok := paths[statePath]; ok {
+			result = multierror.Append(result, fmt.Errorf("state variables %s and %s use the same path %s", other, variable.Name, variable.Path))
+		}
+		paths[statePath] = variable.Name
+
+		if variable.Mixin != "" {
+			mixinDeclared := false
+			for _, mixin := range m.Mixins {
+				if mixin.Name == variable.Mixin {
+					mixinDeclared = true
+					break
+				}
+			}
+			if !mixinDeclared {
+				result = multierror.Append(result, fmt.Errorf("state variable %s is managed by mixin (%s) which was not declared", variable.Name, variable.Mixin))
+			}
+		}
+	}
+
+	return result
+}
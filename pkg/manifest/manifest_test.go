@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/portercontext"
@@ -258,6 +259,227 @@ func TestManifest_Validate_Dockerfile(t *testing.T) {
 	assert.EqualError(t, err, "Dockerfile template cannot be named 'Dockerfile' because that is the filename generated during porter build")
 }
 
+func TestManifest_Validate_MinimumPorterVersion(t *testing.T) {
+	c := config.NewTestConfig(t)
+
+	c.TestContext.AddTestFile("testdata/simple.porter.yaml", config.Name)
+
+	m, err := LoadManifestFrom(context.Background(), c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	m.MinimumPorterVersion = "v1.0.0"
+	require.NoError(t, m.Validate(c.Context, schema.CheckStrategyNone))
+
+	m.MinimumPorterVersion = "latest"
+	err = m.Validate(c.Context, schema.CheckStrategyNone)
+	assert.ErrorContains(t, err, `minimumPorterVersion "latest" is not a valid semver value`)
+}
+
+func TestManifest_Validate_BundleMetadata(t *testing.T) {
+	c := config.NewTestConfig(t)
+
+	c.TestContext.AddTestFile("testdata/simple.porter.yaml", config.Name)
+
+	m, err := LoadManifestFrom(context.Background(), c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	m.License = "Apache-2.0"
+	m.Documentation = "https://example.com/docs"
+	m.Annotations = map[string]string{"team": "platform"}
+	require.NoError(t, m.Validate(c.Context, schema.CheckStrategyNone))
+
+	m.Documentation = "docs/README.md"
+	err = m.Validate(c.Context, schema.CheckStrategyNone)
+	assert.EqualError(t, err, `documentation "docs/README.md" is not a valid url`)
+
+	m.Documentation = ""
+	m.Annotations = map[string]string{" ": "platform"}
+	err = m.Validate(c.Context, schema.CheckStrategyNone)
+	assert.EqualError(t, err, "annotation keys must not be empty")
+}
+
+func TestManifest_Validate_Retry(t *testing.T) {
+	c := config.NewTestConfig(t)
+
+	c.TestContext.AddTestFile("testdata/porter-with-retry.yaml", config.Name)
+
+	m, err := LoadManifestFrom(context.Background(), c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	assert.Equal(t, RetryPolicies{
+		"install": {Retries: 3, Backoff: "30s"},
+		"status":  {Retries: 1},
+	}, m.Retry)
+	assert.NotContains(t, m.CustomActions, "retry", "the retry policies should not be treated as a custom action")
+	require.NoError(t, m.Validate(c.Context, schema.CheckStrategyNone))
+
+	t.Run("undefined action", func(t *testing.T) {
+		m.Retry = RetryPolicies{"missing": {Retries: 1}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, "retry policy defined for action missing which is not defined by the bundle")
+	})
+
+	t.Run("invalid backoff", func(t *testing.T) {
+		m.Retry = RetryPolicies{"install": {Retries: 1, Backoff: "soon"}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, `invalid retry policy for action install: invalid backoff "soon"`)
+	})
+
+	t.Run("negative retries", func(t *testing.T) {
+		m.Retry = RetryPolicies{"upgrade": {Retries: -1}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, "invalid retry policy for action upgrade: retries must not be negative")
+	})
+}
+
+func TestRetryPolicy_GetBackoff(t *testing.T) {
+	backoff, err := RetryPolicy{}.GetBackoff()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetryBackoff, backoff, "the backoff should default when it is not set")
+
+	backoff, err = RetryPolicy{Backoff: "1m30s"}.GetBackoff()
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, backoff)
+
+	_, err = RetryPolicy{Backoff: "-1s"}.GetBackoff()
+	require.EqualError(t, err, `invalid backoff "-1s": must not be negative`)
+}
+
+func TestManifest_Validate_Timeout(t *testing.T) {
+	c := config.NewTestConfig(t)
+
+	c.TestContext.AddTestFile("testdata/porter-with-timeout.yaml", config.Name)
+
+	m, err := LoadManifestFrom(context.Background(), c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	require.Contains(t, m.Timeout, "install")
+	assert.Equal(t, "10m", m.Timeout["install"].Duration)
+	require.Len(t, m.Timeout["install"].Cleanup, 1)
+	assert.NotContains(t, m.CustomActions, "timeout", "the timeout policies should not be treated as a custom action")
+	require.NoError(t, m.Validate(c.Context, schema.CheckStrategyNone))
+
+	timeout, err := m.Install[0].GetTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	timeout, err = m.Uninstall[0].GetTimeout()
+	require.NoError(t, err)
+	assert.Zero(t, timeout, "steps should not time out by default")
+
+	t.Run("undefined action", func(t *testing.T) {
+		m.Timeout = TimeoutPolicies{"missing": {Duration: "1m"}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, "timeout policy defined for action missing which is not defined by the bundle")
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		m.Timeout = TimeoutPolicies{"install": {Duration: "soon"}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, `invalid timeout policy for action install: invalid timeout "soon"`)
+	})
+
+	t.Run("invalid cleanup step", func(t *testing.T) {
+		m.Timeout = TimeoutPolicies{"install": {Duration: "1m", Cleanup: Steps{{Data: map[string]interface{}{"missing": map[string]interface{}{}}}}}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, "invalid timeout policy for action install: invalid cleanup step")
+	})
+}
+
+func TestStep_GetTimeout(t *testing.T) {
+	s := Step{Data: map[string]interface{}{"exec": map[string]interface{}{"timeout": "-5s"}}}
+	_, err := s.GetTimeout()
+	require.EqualError(t, err, `invalid timeout "-5s": must not be negative`)
+
+	s = Step{Data: map[string]interface{}{"exec": map[string]interface{}{"timeout": 5}}}
+	_, err = s.GetTimeout()
+	require.EqualError(t, err, "invalid timeout type (int) for mixin step (exec)")
+}
+
+func TestStep_GetWhen(t *testing.T) {
+	s := Step{Data: map[string]interface{}{"exec": map[string]interface{}{"when": "${ bundle.parameters.createDns }"}}}
+	when, ok, err := s.GetWhen()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "${ bundle.parameters.createDns }", when)
+
+	s = Step{Data: map[string]interface{}{"exec": map[string]interface{}{"when": false}}}
+	when, ok, err = s.GetWhen()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "false", when, "a rendered boolean parameter should be converted to a string")
+
+	s = Step{Data: map[string]interface{}{"exec": map[string]interface{}{}}}
+	_, ok, err = s.GetWhen()
+	require.NoError(t, err)
+	assert.False(t, ok, "steps should not have a condition by default")
+
+	s = Step{Data: map[string]interface{}{"exec": map[string]interface{}{"when": 5}}}
+	_, _, err = s.GetWhen()
+	require.EqualError(t, err, "invalid when type (int) for mixin step (exec)")
+}
+
+func TestStep_GetForeach(t *testing.T) {
+	s := Step{Data: map[string]interface{}{"exec": map[string]interface{}{"foreach": "${ bundle.parameters.regions }"}}}
+	foreach, ok, err := s.GetForeach()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "${ bundle.parameters.regions }", foreach)
+
+	s = Step{Data: map[string]interface{}{"exec": map[string]interface{}{"foreach": []interface{}{"eastus"}}}}
+	foreach, ok, err = s.GetForeach()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"eastus"}, foreach)
+
+	s = Step{Data: map[string]interface{}{"exec": map[string]interface{}{}}}
+	_, ok, err = s.GetForeach()
+	require.NoError(t, err)
+	assert.False(t, ok, "steps should not iterate by default")
+
+	s = Step{Data: map[string]interface{}{"exec": map[string]interface{}{"foreach": 5}}}
+	_, _, err = s.GetForeach()
+	require.EqualError(t, err, "invalid foreach type (int) for mixin step (exec)")
+}
+
+func TestManifest_Validate_Hooks(t *testing.T) {
+	c := config.NewTestConfig(t)
+
+	c.TestContext.AddTestFile("testdata/porter-with-hooks.yaml", config.Name)
+
+	m, err := LoadManifestFrom(context.Background(), c.Config, config.Name)
+	require.NoError(t, err, "could not load manifest")
+
+	assert.Equal(t, []Hook{
+		{Description: "Notify the team", Command: "./notify.sh", Arguments: []string{"starting"}},
+	}, m.Hooks.GetHooks(HookStagePre, "install"))
+	assert.Equal(t, []Hook{
+		{Command: "./smoke-test.sh", Env: map[string]string{"SMOKE_TEST_TIMEOUT": "5m"}},
+	}, m.Hooks.GetHooks(HookStagePost, "install"))
+	assert.Len(t, m.Hooks.GetHooks(HookStagePost, "status"), 1, "hooks should be supported for custom actions")
+	assert.Empty(t, m.Hooks.GetHooks(HookStagePre, "upgrade"))
+	assert.NotContains(t, m.CustomActions, "hooks", "the hooks should not be treated as a custom action")
+	require.NoError(t, m.Validate(c.Context, schema.CheckStrategyNone))
+
+	t.Run("undefined action", func(t *testing.T) {
+		m.Hooks = Hooks{"preMissing": {{Command: "echo"}}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, "invalid hook preMissing, hooks must be named pre or post followed by the name of an action defined by the bundle")
+	})
+
+	t.Run("missing command", func(t *testing.T) {
+		m.Hooks = Hooks{"postUpgrade": {{Description: "Notify the team"}}}
+		err = m.Validate(c.Context, schema.CheckStrategyNone)
+		require.ErrorContains(t, err, "invalid hook postUpgrade[0]: command is required")
+	})
+}
+
+func TestGetHookName(t *testing.T) {
+	assert.Equal(t, "preInstall", GetHookName(HookStagePre, "install"))
+	assert.Equal(t, "postUninstall", GetHookName(HookStagePost, "uninstall"))
+	assert.Equal(t, "postDry-run", GetHookName(HookStagePost, "dry-run"))
+}
+
 func TestManifest_Validate_WrongSchema(t *testing.T) {
 	c := config.NewTestConfig(t)
 
@@ -280,6 +502,18 @@ func TestReadManifest_URL(t *testing.T) {
 	assert.Equal(t, "hello", m.Name)
 }
 
+func TestLoadManifestFrom_URL_Offline(t *testing.T) {
+	c := config.NewTestConfig(t)
+	c.Data.Offline = true
+
+	_, err := LoadManifestFrom(context.Background(), c.Config, "http://fake-example-porter")
+	require.ErrorIs(t, err, config.ErrOffline)
+	assert.Contains(t, err.Error(), "cannot read the manifest from http://fake-example-porter")
+
+	_, err = ReadManifestData(c.Config, "http://fake-example-porter")
+	require.ErrorIs(t, err, config.ErrOffline)
+}
+
 func TestReadManifest_Validate_InvalidURL(t *testing.T) {
 	cxt := portercontext.NewTestContext(t)
 	_, err := ReadManifest(cxt.Context, "http://fake-example-porter")
@@ -445,6 +679,53 @@ func TestMixinDeclaration_UnmarshalYAML_Invalid(t *testing.T) {
 	assert.Contains(t, err.Error(), "mixin declaration contained more than one mixin")
 }
 
+func TestMixinDeclaration_UnmarshalYAML_Version(t *testing.T) {
+	cxt := portercontext.NewTestContext(t)
+	cxt.AddTestFile("testdata/mixin-with-version.yaml", config.Name)
+	m, err := ReadManifest(cxt.Context, config.Name)
+
+	require.NoError(t, err)
+	require.Len(t, m.Mixins, 3, "expected 3 mixins")
+	assert.Equal(t, MixinDeclaration{Name: "exec"}, m.Mixins[0])
+	assert.Equal(t, MixinDeclaration{Name: "terraform", Version: "1.0.x"}, m.Mixins[1])
+	assert.Equal(t, MixinDeclaration{Name: "helm3", Version: "^2.0.0", Config: map[string]interface{}{"clientVersion": "1.3.2"}}, m.Mixins[2])
+}
+
+func TestMixinDeclaration_UnmarshalYAML_InvalidField(t *testing.T) {
+	var m struct {
+		Mixins []MixinDeclaration
+	}
+	err := yaml.Unmarshal([]byte("mixins:\n- name: terraform\n  version: 1.0.x\n  extensions: [iot]\n"), &m)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid field extensions in the terraform mixin declaration")
+}
+
+func TestMixinDeclaration_Validate(t *testing.T) {
+	testcases := []struct {
+		version string
+		wantErr string
+	}{
+		{version: ""},
+		{version: "1.0.x"},
+		{version: ">= 1.2, < 2"},
+		{version: "v1.0.2"},
+		{version: "latest", wantErr: `invalid version "latest" for the terraform mixin`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.version, func(t *testing.T) {
+			m := MixinDeclaration{Name: "terraform", Version: tc.version}
+			err := m.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestCredentialsDefinition_UnmarshalYAML(t *testing.T) {
 	assertAllCredentialsRequired := func(t *testing.T, creds CredentialDefinitions) {
 		for _, cred := range creds {
@@ -485,6 +766,26 @@ func TestMixinDeclaration_MarshalYAML(t *testing.T) {
 	assert.Equal(t, string(wantYaml), string(gotYaml))
 }
 
+func TestMixinDeclaration_MarshalYAML_Version(t *testing.T) {
+	m := struct {
+		Mixins []MixinDeclaration
+	}{
+		[]MixinDeclaration{
+			{Name: "exec"},
+			{Name: "terraform", Version: "1.0.x"},
+			{Name: "helm3", Version: "^2.0.0", Config: map[string]interface{}{"clientVersion": "1.3.2"}},
+		},
+	}
+
+	gotYaml, err := yaml.Marshal(m)
+	require.NoError(t, err, "could not marshal data")
+
+	wantYaml, err := os.ReadFile("testdata/mixin-with-version.yaml")
+	require.NoError(t, err, "could not read testdata")
+
+	assert.Equal(t, string(wantYaml), string(gotYaml))
+}
+
 func TestValidateParameterDefinition_missingPath(t *testing.T) {
 	pd := ParameterDefinition{
 		Name: "myparam",
@@ -536,6 +837,25 @@ func TestValidateParameterDefinition_defaultFailsValidation(t *testing.T) {
 `)
 }
 
+func TestValidateParameterDefinition_nestedDefaultFailsValidation(t *testing.T) {
+	pd := ParameterDefinition{
+		Name: "nodes",
+		Schema: definition.Schema{
+			Type: "array",
+			Items: &definition.Schema{
+				Type: "object",
+				Properties: map[string]*definition.Schema{
+					"size": {Type: "integer"},
+				},
+			},
+			Default: []interface{}{map[string]interface{}{"size": "large"}},
+		},
+	}
+
+	err := pd.Validate()
+	require.ErrorContains(t, err, `encountered an error validating the default value [map[size:large]] for parameter "nodes": /0/size: type should be integer, got string`)
+}
+
 func TestValidateOutputDefinition_missingPath(t *testing.T) {
 	od := OutputDefinition{
 		Name: "myoutput",
@@ -661,6 +981,51 @@ func TestValidateImageMap(t *testing.T) {
 	})
 }
 
+func TestStateBag_Validate(t *testing.T) {
+	m := &Manifest{Mixins: []MixinDeclaration{{Name: "terraform"}}}
+
+	testcases := []struct {
+		name     string
+		stateBag StateBag
+		wantErr  string
+	}{
+		{name: "valid", stateBag: StateBag{
+			{Name: "tfstate", Mixin: "terraform", Location: Location{Path: "terraform/terraform.tfstate"}},
+			{Name: "plugins", Location: Location{Path: "terraform/.terraform"}},
+		}},
+		{name: "missing name", stateBag: StateBag{
+			{Location: Location{Path: "terraform/terraform.tfstate"}},
+		}, wantErr: `state variable for path "terraform/terraform.tfstate" is missing a name`},
+		{name: "invalid name", stateBag: StateBag{
+			{Name: "terraform/tfstate", Location: Location{Path: "terraform/terraform.tfstate"}},
+		}, wantErr: "invalid state variable name terraform/tfstate, it must not contain a slash"},
+		{name: "missing path", stateBag: StateBag{
+			{Name: "tfstate"},
+		}, wantErr: "state variable tfstate is missing a path"},
+		{name: "duplicate name", stateBag: StateBag{
+			{Name: "tfstate", Location: Location{Path: "terraform/terraform.tfstate"}},
+			{Name: "tfstate", Location: Location{Path: "terraform/terraform.tfvars.json"}},
+		}, wantErr: "state variable tfstate is declared more than once"},
+		{name: "duplicate path", stateBag: StateBag{
+			{Name: "tfstate", Location: Location{Path: "terraform/terraform.tfstate"}},
+			{Name: "backup", Location: Location{Path: "./terraform/terraform.tfstate"}},
+		}, wantErr: "state variables tfstate and backup use the same path ./terraform/terraform.tfstate"},
+		{name: "undeclared mixin", stateBag: StateBag{
+			{Name: "kubeconfig", Mixin: "kubernetes", Location: Location{Path: "kubeconfig"}},
+		}, wantErr: "state variable kubeconfig is managed by mixin (kubernetes) which was not declared"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.stateBag.Validate(m)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadManifestWithCustomData(t *testing.T) {
 	c := config.NewTestConfig(t)
 
@@ -847,3 +1212,96 @@ func TestManifest_getTemplatePrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestDependency_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		dep     Dependency
+		wantErr string
+	}{
+		{name: "version range", dep: Dependency{Name: "mysql", Bundle: BundleCriteria{Reference: "getporter/mysql", Version: "^1.2"}}},
+		{name: "invalid version range", dep: Dependency{Name: "mysql", Bundle: BundleCriteria{Reference: "getporter/mysql", Version: "one"}},
+			wantErr: `invalid version range "one" for dependency "mysql"`},
+		{name: "shared", dep: Dependency{Name: "mysql", Bundle: BundleCriteria{Reference: "getporter/mysql:v0.1.3"},
+			Sharing: DependencySharing{Mode: "group", Group: DependencySharingGroup{Name: "myapp"}}}},
+		{name: "missing sharing group", dep: Dependency{Name: "mysql", Bundle: BundleCriteria{Reference: "getporter/mysql:v0.1.3"},
+			Sharing: DependencySharing{Mode: "group"}}, wantErr: `dependency "mysql" must specify the name of the sharing group`},
+		{name: "sharing group without mode", dep: Dependency{Name: "mysql", Bundle: BundleCriteria{Reference: "getporter/mysql:v0.1.3"},
+			Sharing: DependencySharing{Group: DependencySharingGroup{Name: "myapp"}}}, wantErr: `dependency "mysql" specifies a sharing group but the sharing mode is not group`},
+		{name: "invalid sharing mode", dep: Dependency{Name: "mysql", Bundle: BundleCriteria{Reference: "getporter/mysql:v0.1.3"},
+			Sharing: DependencySharing{Mode: "global"}}, wantErr: `invalid sharing mode "global" for dependency "mysql"`},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dep.Validate(portercontext.NewTestContext(t).Context)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestManifest_validateDependencyParameters(t *testing.T) {
+	m := &Manifest{
+		Parameters: ParameterDefinitions{"database": {Name: "database"}},
+		Dependencies: Dependencies{Requires: []*Dependency{
+			{Name: "mysql", Parameters: map[string]string{"database-name": "${ bundle.parameters.database }"}},
+			{Name: "wordpress", Parameters: map[string]string{
+				"db-host": "${ bundle.dependencies.mysql.outputs.host }",
+				"db-port": "${ bundle.dependencies.redis.outputs.port }",
+				"db-user": "${ bundle.parameters.user }",
+			}},
+			{Name: "redis"},
+		}},
+	}
+
+	err := m.validateDependencyParameters()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid dependencies.wordpress.parameters.db-port, dependency redis must be declared before wordpress to use its outputs")
+	assert.Contains(t, err.Error(), "invalid dependencies.wordpress.parameters.db-user, parameter user is not defined in the bundle")
+	assert.NotContains(t, err.Error(), "db-host")
+	assert.NotContains(t, err.Error(), "database-name")
+}
+
+func TestParseDependencyParameterReferences(t *testing.T) {
+	refs := ParseDependencyParameterReferences("${bundle.dependencies.mysql.outputs.host}:${ bundle.parameters.port }")
+	assert.Equal(t, []DependencyParameterReference{
+		{Template: "${bundle.dependencies.mysql.outputs.host}", Dependency: "mysql", Name: "host"},
+		{Template: "${ bundle.parameters.port }", Name: "port"},
+	}, refs)
+
+	assert.Empty(t, ParseDependencyParameterReferences("localhost"))
+}
+
+func TestCustomActionDefinition_validateArguments(t *testing.T) {
+	testcases := []struct {
+		name    string
+		args    []CustomActionArgument
+		wantErr string
+	}{
+		{name: "valid", args: []CustomActionArgument{{Name: "target", Required: true}, {Name: "retention", Default: "7d"}}},
+		{name: "missing name", args: []CustomActionArgument{{Description: "Where to store the backup"}},
+			wantErr: "an argument of custom action backup is missing a name"},
+		{name: "invalid name", args: []CustomActionArgument{{Name: "backup.target"}},
+			wantErr: "invalid argument name backup.target for custom action backup, it must not contain =, ., # or spaces"},
+		{name: "duplicate name", args: []CustomActionArgument{{Name: "target"}, {Name: "target"}},
+			wantErr: "argument target is declared more than once for custom action backup"},
+		{name: "required with default", args: []CustomActionArgument{{Name: "target", Required: true, Default: "s3://mybucket"}},
+			wantErr: "argument target of custom action backup is required and cannot have a default"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			def := CustomActionDefinition{Arguments: tc.args}
+			err := def.validateArguments("backup")
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
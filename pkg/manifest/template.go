@@ -0,0 +1,418 @@
+package manifest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cbroglie/mustache"
+)
+
+// TemplateFunction transforms a value used in a template, for example
+// ${ bundle.parameters.name | upper }. The value is the result of the previous
+// function in the pipeline, and args are the arguments that follow the name of the function.
+type TemplateFunction struct {
+	// Args is the number of arguments that the function requires.
+	Args int
+
+	// Apply the function to a value.
+	Apply func(value interface{}, args []string) (interface{}, error)
+}
+
+// TemplateFunctions are the functions that can be applied to a value in the manifest
+// templating, using a pipe, for example ${ bundle.parameters.name | default "world" | upper }.
+var TemplateFunctions = map[string]TemplateFunction{
+	// default "VALUE" uses VALUE when the value is not set or is empty.
+	"default": {Args: 1, Apply: func(value interface{}, args []string) (interface{}, error) {
+		if isEmptyTemplateValue(value) {
+			return args[0], nil
+		}
+		return value, nil
+	}},
+	// trim removes leading and trailing whitespace.
+	"trim": {Apply: func(value interface{}, args []string) (interface{}, error) {
+		return strings.TrimSpace(templateValueToString(value)), nil
+	}},
+	// upper converts the value to upper case.
+	"upper": {Apply: func(value interface{}, args []string) (interface{}, error) {
+		return strings.ToUpper(templateValueToString(value)), nil
+	}},
+	// lower converts the value to lower case.
+	"lower": {Apply: func(value interface{}, args []string) (interface{}, error) {
+		return strings.ToLower(templateValueToString(value)), nil
+	}},
+	// quote wraps the value in double quotes, escaping any quotes in the value.
+	"quote": {Apply: func(value interface{}, args []string) (interface{}, error) {
+		return strconv.Quote(templateValueToString(value)), nil
+	}},
+	// b64enc base64 encodes the value.
+	"b64enc": {Apply: func(value interface{}, args []string) (interface{}, error) {
+		return base64.StdEncoding.EncodeToString([]byte(templateValueToString(value))), nil
+	}},
+	// b64dec decodes a base64 encoded value.
+	"b64dec": {Apply: func(value interface{}, args []string) (interface{}, error) {
+		decoded, err := base64.StdEncoding.DecodeString(templateValueToString(value))
+		if err != nil {
+			return nil, fmt.Errorf("value is not base64 encoded: %w", err)
+		}
+		return string(decoded), nil
+	}},
+	// toJson converts the value to json, for example to pass an object parameter to a command.
+	"toJson": {Apply: func(value interface{}, args []string) (interface{}, error) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert the value to json: %w", err)
+		}
+		return string(data), nil
+	}},
+	// regexReplace "PATTERN" "REPLACEMENT" replaces every match of the regular expression in the value.
+	"regexReplace": {Args: 2, Apply: func(value interface{}, args []string) (interface{}, error) {
+		re, err := regexp.Compile(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", args[0], err)
+		}
+		return re.ReplaceAllString(templateValueToString(value), args[1]), nil
+	}},
+}
+
+// UnresolvedValue is a template value that is only known when the bundle is run, such as
+// a credential or an output. It is rendered as the original template expression.
+type UnresolvedValue string
+
+// TemplateObject is an object value, such as the value of an object parameter, whose fields
+// can be used in a template, for example ${ bundle.parameters.config.logLevel }.
+// The object is rendered as json.
+type TemplateObject map[string]interface{}
+
+// String returns the object as json.
+func (o TemplateObject) String() string {
+	return templateValueToJson(o)
+}
+
+// TemplateArray is an array value, such as the value of an array parameter, whose items
+// can be used in a template, for example ${ bundle.parameters.nodes[0].size }.
+// The array is rendered as json.
+type TemplateArray []interface{}
+
+// String returns the array as json.
+func (a TemplateArray) String() string {
+	return templateValueToJson(a)
+}
+
+// NewTemplateValue converts a value unmarshaled from json into a value that can be
+// indexed in a template, converting nested objects and arrays.
+func NewTemplateValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		obj := make(TemplateObject, len(v))
+		for key, item := range v {
+			obj[key] = NewTemplateValue(item)
+		}
+		return obj
+	case []interface{}:
+		arr := make(TemplateArray, len(v))
+		for i, item := range v {
+			arr[i] = NewTemplateValue(item)
+		}
+		return arr
+	default:
+		return value
+	}
+}
+
+// NewTemplateParameterValue converts the value of a parameter into the value used in templates.
+// The json value of an array or object parameter is converted so that its items and fields
+// can be used in a template. Other values are used as is.
+func NewTemplateParameterValue(param ParameterDefinition, value string) interface{} {
+	if param.Type != "array" && param.Type != "object" {
+		return value
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		// Leave invalid values as is, they are reported when the parameter is validated
+		return value
+	}
+	return NewTemplateValue(parsed)
+}
+
+func templateValueToJson(value interface{}) string {
+	// The value was unmarshaled from json, so it can always be marshaled
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// templateFunctionPrefix is the prefix of the variables that hold the result of a template pipeline.
+const templateFunctionPrefix = "porterTemplateFunction"
+
+var (
+	// templatePipelineRegex matches a template tag that uses a pipe or an index, with porter's ${ } delimiters.
+	templatePipelineRegex = regexp.MustCompile(`\$\{([^{}]*[|\[][^{}]*)\}`)
+
+	// legacyTemplatePipelineRegex matches a template tag that uses a pipe or an index, with the mustache {{ }} delimiters.
+	legacyTemplatePipelineRegex = regexp.MustCompile(`\{\{([^{}]*[|\[][^{}]*)\}\}`)
+
+	// templateIndexRegex matches an index in a template variable, for example [0] in bundle.parameters.nodes[0].size.
+	templateIndexRegex = regexp.MustCompile(`^([^\[]*)\[(\d+)\]$`)
+)
+
+// GetTemplateVariableName returns the name of the variable used in a template tag, removing any
+// template functions and indexes, for example bundle.parameters.nodes[0].size | upper returns bundle.parameters.nodes.
+func GetTemplateVariableName(tag string) string {
+	name, _, _ := strings.Cut(tag, "|")
+	name, _, _ = strings.Cut(name, "[")
+	return strings.TrimSpace(name)
+}
+
+// RenderTemplate renders a template from the manifest with the specified data, applying
+// any template functions. The template must start with the delimiter prefix used by the manifest,
+// see GetTemplatePrefix. Values calculated from a sensitive value are returned so that they can be
+// masked, isSensitive determines if a value used in the template is sensitive and may be nil.
+func RenderTemplate(tmpl string, data map[string]interface{}, isSensitive func(value interface{}) bool) (string, []string, error) {
+	pipelineRegex := legacyTemplatePipelineRegex
+	if strings.HasPrefix(tmpl, TemplateDelimiterPrefix) {
+		pipelineRegex = templatePipelineRegex
+	}
+
+	// Calculate the result of each pipeline and index, and replace it with a variable holding the result
+	// so that the result is not rendered again as a template.
+	renderData := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		renderData[k] = v
+	}
+	var sensitive []string
+	var pipelineErr error
+	i := 0
+	tmpl = pipelineRegex.ReplaceAllStringFunc(tmpl, func(tag string) string {
+		if pipelineErr != nil {
+			return tag
+		}
+
+		expr := pipelineRegex.FindStringSubmatch(tag)[1]
+		value, sensitiveValue, err := evaluateTemplatePipeline(expr, data, isSensitive)
+		if err != nil {
+			pipelineErr = fmt.Errorf("error evaluating %s: %w", strings.TrimSpace(expr), err)
+			return tag
+		}
+		if _, ok := value.(UnresolvedValue); ok {
+			value = tag
+		}
+
+		result := templateValueToString(value)
+		if sensitiveValue {
+			sensitive = append(sensitive, result)
+		}
+
+		name := fmt.Sprintf("%s%d", templateFunctionPrefix, i)
+		i++
+		renderData[name] = result
+		if pipelineRegex == templatePipelineRegex {
+			return "${" + name + "}"
+		}
+		return "{{" + name + "}}"
+	})
+	if pipelineErr != nil {
+		return "", nil, pipelineErr
+	}
+
+	mustache.AllowMissingVariables = false
+	rendered, err := mustache.RenderRaw(tmpl, true, renderData)
+	if err != nil {
+		return "", nil, err
+	}
+	return rendered, sensitive, nil
+}
+
+// evaluateTemplatePipeline evaluates a template expression that uses template functions or indexes,
+// for example bundle.parameters.name | default "world" | upper, or bundle.parameters.nodes[0].size.
+func evaluateTemplatePipeline(expr string, data map[string]interface{}, isSensitive func(value interface{}) bool) (interface{}, bool, error) {
+	segments, err := splitTemplatePipeline(expr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	variable := strings.TrimSpace(segments[0])
+	path, err := lookupTemplatePath(data, variable)
+	if err != nil {
+		return nil, false, err
+	}
+	found := len(path) > 0 && len(path) == len(strings.Split(variable, "."))
+	var value interface{}
+	if found {
+		value = path[len(path)-1]
+	}
+	sensitive := false
+	for _, v := range path {
+		if _, ok := v.(UnresolvedValue); ok {
+			return v, false, nil
+		}
+		// A value in a sensitive object or array is sensitive too
+		if isSensitive != nil && isSensitive(v) {
+			sensitive = true
+		}
+	}
+
+	usesDefault := false
+	for _, segment := range segments[1:] {
+		args, err := splitTemplateArgs(segment)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(args) == 0 {
+			return nil, false, errors.New("missing the name of a template function after |")
+		}
+
+		name := args[0]
+		fn, ok := TemplateFunctions[name]
+		if !ok {
+			return nil, false, fmt.Errorf("unknown template function %s", name)
+		}
+		if len(args)-1 != fn.Args {
+			return nil, false, fmt.Errorf("the %s template function requires %d arguments but %d were specified", name, fn.Args, len(args)-1)
+		}
+		if name == "default" {
+			usesDefault = true
+		}
+
+		if value, err = fn.Apply(value, args[1:]); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if !found && !usesDefault {
+		return nil, false, fmt.Errorf("missing variable %q", variable)
+	}
+	return value, sensitive, nil
+}
+
+// splitTemplatePipeline splits a template expression on the pipes that are not in a quoted argument.
+func splitTemplatePipeline(expr string) ([]string, error) {
+	var segments []string
+	var current strings.Builder
+	inQuotes, escaped := false, false
+	for _, c := range expr {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && inQuotes:
+			escaped = true
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == '|' && !inQuotes:
+			segments = append(segments, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated quoted argument")
+	}
+	return append(segments, current.String()), nil
+}
+
+// splitTemplateArgs splits a template function call, for example regexReplace "[0-9]" "x",
+// into the function name and its arguments. Arguments are either a single word, or double quoted.
+func splitTemplateArgs(segment string) ([]string, error) {
+	var args []string
+	rest := strings.TrimSpace(segment)
+	for rest != "" {
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument %s: %w", rest, err)
+			}
+			arg, _ := strconv.Unquote(quoted)
+			args = append(args, arg)
+			rest = strings.TrimSpace(rest[len(quoted):])
+			continue
+		}
+
+		arg, remaining, _ := strings.Cut(rest, " ")
+		args = append(args, arg)
+		rest = strings.TrimSpace(remaining)
+	}
+	return args, nil
+}
+
+// lookupTemplatePath finds the value of a dotted variable name, such as bundle.parameters.name or
+// bundle.parameters.nodes[0].size, in the template data. The values of each part of the name are returned,
+// stopping at the first part that is not found.
+func lookupTemplatePath(data map[string]interface{}, name string) ([]interface{}, error) {
+	var path []interface{}
+	var current interface{} = data
+	for _, part := range strings.Split(name, ".") {
+		key, index := part, -1
+		if strings.Contains(part, "[") {
+			match := templateIndexRegex.FindStringSubmatch(part)
+			if match == nil {
+				return nil, fmt.Errorf("invalid index in %s, only a single numeric index such as [0] is supported", part)
+			}
+			key = match[1]
+			index, _ = strconv.Atoi(match[2])
+		}
+
+		value, ok := lookupTemplateKey(current, key)
+		if ok && index >= 0 {
+			value, ok = lookupTemplateIndex(value, index)
+		}
+		if !ok {
+			return path, nil
+		}
+		path = append(path, value)
+		current = value
+	}
+	return path, nil
+}
+
+// lookupTemplateKey finds the value of a key in a map.
+func lookupTemplateKey(value interface{}, key string) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	item := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+	if !item.IsValid() {
+		return nil, false
+	}
+	return item.Interface(), true
+}
+
+// lookupTemplateIndex finds the value at an index in an array.
+func lookupTemplateIndex(value interface{}, index int) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice || index >= v.Len() {
+		return nil, false
+	}
+	return v.Index(index).Interface(), true
+}
+
+// isEmptyTemplateValue determines if a value is not set, or is an empty string.
+func isEmptyTemplateValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if s, ok := value.(string); ok {
+		return s == ""
+	}
+	return false
+}
+
+// templateValueToString converts a value to the string that is rendered in the template.
+func templateValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case UnresolvedValue:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
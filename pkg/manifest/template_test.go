@@ -0,0 +1,178 @@
+package manifest
+
+import (
+	"fmt"
+	"testing"
+
+	"get.porter.sh/porter/tests"
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate_Functions(t *testing.T) {
+	data := map[string]interface{}{
+		"bundle": map[string]interface{}{
+			"parameters": map[string]interface{}{
+				"name":     " Porter ",
+				"empty":    "",
+				"password": "secret",
+				"template": "${ bundle.parameters.name }",
+			},
+			"custom": map[string]interface{}{
+				"config": map[string]interface{}{"replicas": 3},
+			},
+			"credentials": map[string]interface{}{
+				"token": UnresolvedValue("${ bundle.credentials.token }"),
+			},
+		},
+	}
+
+	testcases := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr string
+	}{
+		{name: "no functions", tmpl: "${ bundle.parameters.name }", want: " Porter "},
+		{name: "trim", tmpl: "${ bundle.parameters.name | trim }", want: "Porter"},
+		{name: "chained", tmpl: "${bundle.parameters.name|trim|upper}", want: "PORTER"},
+		{name: "lower", tmpl: "${ bundle.parameters.name | lower }", want: " porter "},
+		{name: "quote", tmpl: "${ bundle.parameters.name | trim | quote }", want: `"Porter"`},
+		{name: "default unset", tmpl: `${ bundle.parameters.missing | default "world" }`, want: "world"},
+		{name: "default empty", tmpl: `${ bundle.parameters.empty | default world }`, want: "world"},
+		{name: "default set", tmpl: `${ bundle.parameters.name | default "world" }`, want: " Porter "},
+		{name: "b64enc", tmpl: "${ bundle.parameters.password | b64enc }", want: "c2VjcmV0"},
+		{name: "b64dec", tmpl: "${ bundle.parameters.password | b64enc | b64dec }", want: "secret"},
+		{name: "toJson", tmpl: "${ bundle.custom.config | toJson }", want: `{"replicas":3}`},
+		{name: "regexReplace", tmpl: `${ bundle.parameters.name | trim | regexReplace "[aeiou]" "_" }`, want: "P_rt_r"},
+		{name: "quoted pipe", tmpl: `${ bundle.parameters.name | trim | regexReplace "o|r" "|" }`, want: "P||te|"},
+		{name: "unresolved", tmpl: "${ bundle.credentials.token | b64enc }", want: "${ bundle.credentials.token | b64enc }"},
+		{name: "result is not rendered", tmpl: "${ bundle.parameters.template | trim }", want: "${ bundle.parameters.name }"},
+		{name: "missing variable", tmpl: "${ bundle.parameters.missing | trim }", wantErr: `missing variable "bundle.parameters.missing"`},
+		{name: "unknown function", tmpl: "${ bundle.parameters.name | shout }", wantErr: "unknown template function shout"},
+		{name: "wrong arguments", tmpl: "${ bundle.parameters.name | default }", wantErr: "the default template function requires 1 arguments but 0 were specified"},
+		{name: "invalid regex", tmpl: `${ bundle.parameters.name | regexReplace "[" "" }`, wantErr: "invalid regular expression"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := RenderTemplate(TemplateDelimiterPrefix+tc.tmpl, data, nil)
+			if tc.wantErr != "" {
+				tests.RequireErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRenderTemplate_LegacyDelimiters(t *testing.T) {
+	data := map[string]interface{}{
+		"bundle": map[string]interface{}{
+			"parameters": map[string]interface{}{"name": "porter"},
+		},
+	}
+
+	got, _, err := RenderTemplate("hello {{ bundle.parameters.name | upper }}", data, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "hello PORTER", got)
+}
+
+func TestRenderTemplate_Sensitive(t *testing.T) {
+	data := map[string]interface{}{
+		"bundle": map[string]interface{}{
+			"parameters": map[string]interface{}{"password": "secret", "name": "porter"},
+		},
+	}
+	isSensitive := func(value interface{}) bool {
+		return value == "secret"
+	}
+
+	_, sensitive, err := RenderTemplate(TemplateDelimiterPrefix+"${ bundle.parameters.password | b64enc } ${ bundle.parameters.name | upper }", data, isSensitive)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c2VjcmV0"}, sensitive)
+}
+
+func TestRenderTemplate_StructuredValues(t *testing.T) {
+	nodes := NewTemplateParameterValue(ParameterDefinition{Name: "nodes", Schema: definition.Schema{Type: "array"}},
+		`[{"name": "primary", "size": 3, "zones": ["a", "b"]}, {"name": "secondary", "size": 1}]`)
+	config := NewTemplateParameterValue(ParameterDefinition{Name: "config", Schema: definition.Schema{Type: "object"}},
+		`{"logLevel": 11, "secret": "shh"}`)
+	data := map[string]interface{}{
+		"bundle": map[string]interface{}{
+			"parameters": map[string]interface{}{"nodes": nodes, "config": config},
+		},
+	}
+
+	testcases := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr string
+	}{
+		{name: "array", tmpl: "${ bundle.parameters.nodes }", want: `[{"name":"primary","size":3,"zones":["a","b"]},{"name":"secondary","size":1}]`},
+		{name: "object", tmpl: "${ bundle.parameters.config }", want: `{"logLevel":11,"secret":"shh"}`},
+		{name: "object field", tmpl: "${ bundle.parameters.config.logLevel }", want: "11"},
+		{name: "index", tmpl: "${ bundle.parameters.nodes[1].name }", want: "secondary"},
+		{name: "nested index", tmpl: "${ bundle.parameters.nodes[0].zones[1] }", want: "b"},
+		{name: "indexed object", tmpl: "${ bundle.parameters.nodes[1] }", want: `{"name":"secondary","size":1}`},
+		{name: "index with function", tmpl: "${ bundle.parameters.nodes[0].name | upper }", want: "PRIMARY"},
+		{name: "index out of range", tmpl: "${ bundle.parameters.nodes[2].name }", wantErr: `missing variable "bundle.parameters.nodes[2].name"`},
+		{name: "index out of range with default", tmpl: `${ bundle.parameters.nodes[2].name | default "none" }`, want: "none"},
+		{name: "invalid index", tmpl: "${ bundle.parameters.nodes[-1] }", wantErr: "invalid index in nodes[-1]"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := RenderTemplate(TemplateDelimiterPrefix+tc.tmpl, data, nil)
+			if tc.wantErr != "" {
+				tests.RequireErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("legacy delimiters", func(t *testing.T) {
+		got, _, err := RenderTemplate("{{ bundle.parameters.nodes[0].size }}", data, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "3", got)
+	})
+
+	t.Run("sensitive", func(t *testing.T) {
+		isSensitive := func(value interface{}) bool {
+			return fmt.Sprintf("%v", value) == config.(fmt.Stringer).String()
+		}
+		_, sensitive, err := RenderTemplate(TemplateDelimiterPrefix+"${ bundle.parameters.config.secret | upper }", data, isSensitive)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SHH"}, sensitive, "values calculated from a sensitive object should be sensitive")
+	})
+}
+
+func TestNewTemplateParameterValue(t *testing.T) {
+	param := ParameterDefinition{Name: "nodes", Schema: definition.Schema{Type: "array"}}
+	assert.Equal(t, TemplateArray{TemplateObject{"size": float64(1)}}, NewTemplateParameterValue(param, `[{"size": 1}]`))
+	assert.Equal(t, "not json", NewTemplateParameterValue(param, "not json"), "invalid values should be used as is")
+
+	param = ParameterDefinition{Name: "name", Schema: definition.Schema{Type: "string"}}
+	assert.Equal(t, `["a"]`, NewTemplateParameterValue(param, `["a"]`), "only array and object parameters should be converted")
+}
+
+func TestGetTemplateVariableName(t *testing.T) {
+	assert.Equal(t, "bundle.parameters.name", GetTemplateVariableName("bundle.parameters.name"))
+	assert.Equal(t, "bundle.parameters.name", GetTemplateVariableName(` bundle.parameters.name | default "a" | upper`))
+	assert.Equal(t, "bundle.parameters.nodes", GetTemplateVariableName("bundle.parameters.nodes[0].size"))
+}
+
+func TestScanManifestTemplating_Functions(t *testing.T) {
+	m := &Manifest{SchemaVersion: "1.0.0"}
+	result, err := m.scanManifestTemplating([]byte(`install:
+  - exec:
+      arguments:
+        - ${ bundle.parameters.name | default "world" | upper }
+        - ${ bundle.outputs.kubeconfig | b64enc }
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bundle.outputs.kubeconfig", "bundle.parameters.name"}, result.Variables)
+}
@@ -0,0 +1,365 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/schema"
+	"github.com/hashicorp/go-multierror"
+	"github.com/xeipuuv/gojsonschema"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// ValidationError is a problem found when validating a manifest, with the position
+// in the manifest where the problem was found, when it is known.
+type ValidationError struct {
+	// Path to the invalid section of the manifest, for example install.0.exec.
+	Path string `json:"path,omitempty"`
+
+	// Line in the manifest, starting from 1, or 0 when the position is not known.
+	Line int `json:"line,omitempty"`
+
+	// Column in the manifest, starting from 1, or 0 when the position is not known.
+	Column int `json:"column,omitempty"`
+
+	// Message describing the problem.
+	Message string `json:"message"`
+}
+
+// Error formats the validation error as LINE:COLUMN: PATH: MESSAGE, omitting unknown values.
+func (e ValidationError) Error() string {
+	var b strings.Builder
+	if e.Line > 0 {
+		b.WriteString(fmt.Sprintf("%d:%d: ", e.Line, e.Column))
+	}
+	if e.Path != "" {
+		b.WriteString(e.Path + ": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// ValidationErrors are the problems found when validating a manifest.
+type ValidationErrors []ValidationError
+
+// Sort the errors by their position in the manifest, with errors that do not have a position last.
+func (errs ValidationErrors) Sort() {
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errs[i], errs[j]
+		if (a.Line == 0) != (b.Line == 0) {
+			return a.Line != 0
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// yamlErrorLine matches the line number in an error returned by the yaml parser, e.g. yaml: line 5: did not find expected key
+var yamlErrorLine = regexp.MustCompile(`line (\d+):?`)
+
+// ValidateManifestData validates the manifest against the manifest json schema, and then checks
+// for problems that the schema cannot detect, such as templates that use undeclared parameters.
+// The position of each problem in the manifest is included when it can be determined.
+// An error is only returned when the manifest cannot be validated.
+func ValidateManifestData(cxt *portercontext.Context, data []byte, manifestSchema map[string]interface{}) (ValidationErrors, error) {
+	var root yaml3.Node
+	if err := yaml3.Unmarshal(data, &root); err != nil {
+		return ValidationErrors{newYamlValidationError(err)}, nil
+	}
+
+	var doc interface{}
+	if err := root.Decode(&doc); err != nil {
+		return ValidationErrors{newYamlValidationError(err)}, nil
+	}
+
+	loc := yamlLocator{root: &root}
+	var results ValidationErrors
+
+	schemaResults, err := validateManifestSchema(doc, manifestSchema, loc)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, schemaResults...)
+
+	m, err := UnmarshalManifest(cxt, data)
+	if err != nil {
+		results = append(results, newYamlValidationError(err))
+		results.Sort()
+		return results, nil
+	}
+	tmplResult, err := m.scanManifestTemplating(data)
+	if err != nil {
+		results = append(results, ValidationError{Message: err.Error()})
+		results.Sort()
+		return results, nil
+	}
+	m.TemplateVariables = tmplResult.Variables
+
+	results = append(results, m.validateSemantics(data, loc)...)
+
+	// Include the remaining problems found when porter loads the manifest, which do not have a position
+	if err = m.Validate(cxt, schema.CheckStrategyNone); err != nil {
+		reported := m.getUndeclaredMixinErrors()
+		var multiErr *multierror.Error
+		if errors.As(err, &multiErr) {
+			for _, e := range multiErr.Errors {
+				if !reported[e.Error()] {
+					results = append(results, ValidationError{Message: e.Error()})
+				}
+			}
+		} else if !reported[err.Error()] {
+			results = append(results, ValidationError{Message: err.Error()})
+		}
+	}
+
+	results.Sort()
+	return results, nil
+}
+
+// newYamlValidationError converts an error from the yaml parser into a validation error,
+// including the line where the error was found.
+func newYamlValidationError(err error) ValidationError {
+	result := ValidationError{Message: err.Error()}
+	if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+		result.Line, _ = strconv.Atoi(match[1])
+		result.Column = 1
+	}
+	return result
+}
+
+// validateManifestSchema validates the manifest against the json schema.
+func validateManifestSchema(doc interface{}, manifestSchema map[string]interface{}, loc yamlLocator) (ValidationErrors, error) {
+	if manifestSchema == nil {
+		return nil, nil
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(manifestSchema), gojsonschema.NewGoLoader(doc))
+	if err != nil {
+		return nil, fmt.Errorf("could not validate the manifest against the manifest schema: %w", err)
+	}
+
+	var results ValidationErrors
+	seen := map[string]bool{}
+	for _, e := range result.Errors() {
+		path := strings.TrimPrefix(strings.TrimPrefix(e.Context().String(), "(root)"), ".")
+		key := path + e.Description()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		line, col := loc.Find(path)
+		results = append(results, ValidationError{
+			Path:    path,
+			Line:    line,
+			Column:  col,
+			Message: e.Description(),
+		})
+	}
+	return results, nil
+}
+
+// validateSemantics checks for problems that cannot be detected by the manifest schema.
+func (m *Manifest) validateSemantics(data []byte, loc yamlLocator) ValidationErrors {
+	var results ValidationErrors
+
+	declaredMixins := make(map[string]bool, len(m.Mixins))
+	for _, mixin := range m.Mixins {
+		declaredMixins[mixin.Name] = true
+	}
+
+	for _, action := range m.getActionNames() {
+		descriptions := map[string]int{}
+		for i, step := range m.GetSteps(action) {
+			if step == nil || len(step.Data) != 1 {
+				continue
+			}
+
+			mixinName := step.GetMixinName()
+			stepPath := fmt.Sprintf("%s.%d.%s", action, i, mixinName)
+			if !declaredMixins[mixinName] {
+				line, col := loc.Find(stepPath)
+				results = append(results, ValidationError{
+					Path:    stepPath,
+					Line:    line,
+					Column:  col,
+					Message: fmt.Sprintf("the %s mixin is used by a step but is not declared in the mixins section", mixinName),
+				})
+			}
+
+			desc, err := step.GetDescription()
+			if err != nil || desc == "" {
+				continue
+			}
+			if first, ok := descriptions[desc]; ok {
+				descPath := stepPath + ".description"
+				line, col := loc.Find(descPath)
+				results = append(results, ValidationError{
+					Path:    descPath,
+					Line:    line,
+					Column:  col,
+					Message: fmt.Sprintf("duplicate step description %q, which is also used by %s.%d", desc, action, first),
+				})
+				continue
+			}
+			descriptions[desc] = i
+		}
+	}
+
+	for _, variable := range m.TemplateVariables {
+		if msg := m.checkTemplateVariable(variable); msg != "" {
+			line, col := findTemplateVariable(data, variable)
+			results = append(results, ValidationError{
+				Line:    line,
+				Column:  col,
+				Message: msg,
+			})
+		}
+	}
+
+	return results
+}
+
+// checkTemplateVariable returns a message when a template variable references
+// a parameter, credential, image or dependency that is not declared in the manifest.
+func (m *Manifest) checkTemplateVariable(variable string) string {
+	parts := strings.Split(variable, ".")
+	if len(parts) < 3 || parts[0] != "bundle" {
+		return ""
+	}
+
+	name := parts[2]
+	switch parts[1] {
+	case "parameters":
+		if _, ok := m.Parameters[name]; !ok {
+			return fmt.Sprintf("the template %s uses the %s parameter, which is not declared in the parameters section", variable, name)
+		}
+	case "credentials":
+		if _, ok := m.Credentials[name]; !ok {
+			return fmt.Sprintf("the template %s uses the %s credential, which is not declared in the credentials section", variable, name)
+		}
+	case "images":
+		if _, ok := m.ImageMap[name]; !ok {
+			return fmt.Sprintf("the template %s uses the %s image, which is not declared in the images section", variable, name)
+		}
+	case "dependencies":
+		for _, dep := range m.Dependencies.Requires {
+			if dep != nil && dep.Name == name {
+				return ""
+			}
+		}
+		return fmt.Sprintf("the template %s uses the %s dependency, which is not declared in the dependencies section", variable, name)
+	}
+	return ""
+}
+
+// getActionNames returns the names of the actions defined by the manifest, with the custom actions sorted by name.
+func (m *Manifest) getActionNames() []string {
+	actions := []string{cnab.ActionInstall, cnab.ActionUpgrade, cnab.ActionUninstall}
+	custom := make([]string, 0, len(m.CustomActions))
+	for action := range m.CustomActions {
+		custom = append(custom, action)
+	}
+	sort.Strings(custom)
+	return append(actions, custom...)
+}
+
+// getUndeclaredMixinErrors returns the messages of the errors that Validate returns for
+// steps that use an undeclared mixin, which are already reported with their position.
+func (m *Manifest) getUndeclaredMixinErrors() map[string]bool {
+	reported := map[string]bool{}
+	for _, action := range m.getActionNames() {
+		for _, step := range m.GetSteps(action) {
+			if step == nil || len(step.Data) != 1 {
+				continue
+			}
+			if err := step.Validate(m); err != nil {
+				reported[fmt.Errorf(invalidStepErrorFormat, action, err).Error()] = true
+				break
+			}
+		}
+	}
+	return reported
+}
+
+// findTemplateVariable returns the position of the first use of a template variable in the manifest.
+func findTemplateVariable(data []byte, variable string) (int, int) {
+	offset := 0
+	for {
+		i := bytes.Index(data[offset:], []byte(variable))
+		if i < 0 {
+			return 0, 0
+		}
+		i += offset
+		end := i + len(variable)
+		// Skip longer variables that start with the same name, e.g. bundle.parameters.name-suffix
+		if end < len(data) && isTemplateNameChar(data[end]) {
+			offset = end
+			continue
+		}
+
+		line := bytes.Count(data[:i], []byte("\n")) + 1
+		col := i - bytes.LastIndexByte(data[:i], '\n')
+		return line, col
+	}
+}
+
+func isTemplateNameChar(c byte) bool {
+	return c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// yamlLocator finds the position of a value in a yaml document.
+type yamlLocator struct {
+	root *yaml3.Node
+}
+
+// Find the line and column of the value at the path, for example install.0.exec.
+// When the path is not found, the position of the closest parent is returned.
+func (l yamlLocator) Find(path string) (int, int) {
+	node := l.root
+	if node == nil {
+		return 0, 0
+	}
+	if node.Kind == yaml3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line, col := node.Line, node.Column
+
+	if path == "" {
+		return line, col
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		var next *yaml3.Node
+		switch node.Kind {
+		case yaml3.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == part {
+					// Report the position of the key
+					line, col = node.Content[i].Line, node.Content[i].Column
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml3.SequenceNode:
+			if i, err := strconv.Atoi(part); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+				line, col = next.Line, next.Column
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line, col
+}
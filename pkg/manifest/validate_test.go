@@ -0,0 +1,67 @@
+package manifest
+
+import (
+	"os"
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testManifestSchema is a small part of the manifest schema, used to check
+// that schema validation errors are reported with their position.
+var testManifestSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"name"},
+	"properties": map[string]interface{}{
+		"name":        map[string]interface{}{"type": "string"},
+		"description": map[string]interface{}{"type": "string"},
+	},
+}
+
+func TestValidateManifestData(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		cxt := portercontext.NewTestContext(t)
+		data, err := os.ReadFile("testdata/simple.porter.yaml")
+		require.NoError(t, err)
+
+		results, err := ValidateManifestData(cxt.Context, data, testManifestSchema)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cxt := portercontext.NewTestContext(t)
+		data, err := os.ReadFile("testdata/validate/invalid.yaml")
+		require.NoError(t, err)
+
+		results, err := ValidateManifestData(cxt.Context, data, testManifestSchema)
+		require.NoError(t, err)
+
+		want := ValidationErrors{
+			{Path: "description", Line: 5, Column: 1, Message: "Invalid type. Expected: string, given: integer"},
+			{Line: 20, Column: 14, Message: "the template bundle.parameters.log uses the log parameter, which is not declared in the parameters section"},
+			{Path: "install.1.exec.description", Line: 22, Column: 7, Message: `duplicate step description "Install", which is also used by install.0`},
+			{Path: "uninstall.0.helm3", Line: 26, Column: 5, Message: "the helm3 mixin is used by a step but is not declared in the mixins section"},
+		}
+		assert.Equal(t, want, results)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		cxt := portercontext.NewTestContext(t)
+		data, err := os.ReadFile("testdata/validate/syntax-error.yaml")
+		require.NoError(t, err)
+
+		results, err := ValidateManifestData(cxt.Context, data, testManifestSchema)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, 4, results[0].Line)
+		assert.Contains(t, results[0].Message, "mapping values are not allowed in this context")
+	})
+}
+
+func TestValidationError_Error(t *testing.T) {
+	assert.Equal(t, "3:5: install.0.exec: oops", ValidationError{Path: "install.0.exec", Line: 3, Column: 5, Message: "oops"}.Error())
+	assert.Equal(t, "oops", ValidationError{Message: "oops"}.Error())
+}
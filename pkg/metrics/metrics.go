@@ -0,0 +1,139 @@
+// Package metrics collects Prometheus metrics about the actions, storage
+// calls and registry pulls made by Porter, so that they can be served by the
+// long-running commands, such as porter agent serve.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is the endpoint that serves the metrics.
+const Path = "/metrics"
+
+// Registry contains the metrics collected by Porter.
+var Registry = prometheus.NewRegistry()
+
+var (
+	actionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "porter_actions_total",
+		Help: "Number of bundle actions executed.",
+	}, []string{"action"})
+
+	actionsFailedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "porter_actions_failed_total",
+		Help: "Number of bundle actions that failed.",
+	}, []string{"action"})
+
+	actionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "porter_action_duration_seconds",
+		Help:    "How long bundle actions took to execute.",
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+	}, []string{"action"})
+
+	reconciliationsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "porter_reconciliations_total",
+		Help: "Number of times an installation was reconciled.",
+	})
+
+	reconciliationsFailedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "porter_reconciliations_failed_total",
+		Help: "Number of times reconciling an installation failed.",
+	})
+
+	storageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "porter_storage_operation_duration_seconds",
+		Help:    "How long calls to the storage plugin took.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "collection"})
+
+	registryPullDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "porter_registry_pull_duration_seconds",
+		Help:    "How long pulling a bundle from a registry took.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		actionsTotal,
+		actionsFailedTotal,
+		actionDuration,
+		reconciliationsTotal,
+		reconciliationsFailedTotal,
+		storageDuration,
+		registryPullDuration,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Serve the metrics on the specified address until the context is cancelled.
+func Serve(ctx context.Context, listen string) error {
+	// Listen before returning, so that a bad address is reported right away
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("could not serve metrics on %s: %w", listen, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 30 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			tracing.LoggerFromContext(ctx).Warnf("error serving metrics: %s", err)
+		}
+	}()
+	return nil
+}
+
+// ObserveAction records that a bundle action was executed.
+func ObserveAction(action string, duration time.Duration, failed bool) {
+	actionsTotal.WithLabelValues(action).Inc()
+	if failed {
+		actionsFailedTotal.WithLabelValues(action).Inc()
+	}
+	actionDuration.WithLabelValues(action).Observe(duration.Seconds())
+}
+
+// ObserveReconciliation records that an installation was reconciled.
+func ObserveReconciliation(failed bool) {
+	reconciliationsTotal.Inc()
+	if failed {
+		reconciliationsFailedTotal.Inc()
+	}
+}
+
+// ObserveStorageOperation records how long a call to the storage plugin took,
+// measured from start. Call it with defer when the operation starts.
+func ObserveStorageOperation(operation string, collection string, start time.Time) {
+	storageDuration.WithLabelValues(operation, collection).Observe(time.Since(start).Seconds())
+}
+
+// ObserveRegistryPull records how long pulling a bundle took, measured from
+// start. Call it with defer when the pull starts.
+func ObserveRegistryPull(start time.Time) {
+	registryPullDuration.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveAction(t *testing.T) {
+	total := testutil.ToFloat64(actionsTotal.WithLabelValues("test-action"))
+	failed := testutil.ToFloat64(actionsFailedTotal.WithLabelValues("test-action"))
+
+	ObserveAction("test-action", time.Second, false)
+	ObserveAction("test-action", time.Minute, true)
+
+	assert.Equal(t, total+2, testutil.ToFloat64(actionsTotal.WithLabelValues("test-action")))
+	assert.Equal(t, failed+1, testutil.ToFloat64(actionsFailedTotal.WithLabelValues("test-action")))
+}
+
+func TestObserveReconciliation(t *testing.T) {
+	total := testutil.ToFloat64(reconciliationsTotal)
+	failed := testutil.ToFloat64(reconciliationsFailedTotal)
+
+	ObserveReconciliation(true)
+	ObserveReconciliation(false)
+
+	assert.Equal(t, total+2, testutil.ToFloat64(reconciliationsTotal))
+	assert.Equal(t, failed+1, testutil.ToFloat64(reconciliationsFailedTotal))
+}
+
+func TestHandler(t *testing.T) {
+	ObserveStorageOperation("find", "installations", time.Now())
+	ObserveRegistryPull(time.Now())
+
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `porter_storage_operation_duration_seconds_count{collection="installations",operation="find"}`)
+	assert.Contains(t, string(body), "porter_registry_pull_duration_seconds_count")
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestLint(t *testing.T) {
+	problems, err := testutil.GatherAndLint(Registry)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
@@ -86,3 +86,10 @@ func (p *TestMixinProvider) GetSchema(ctx context.Context, name string) (string,
 	b, err := os.ReadFile(schemaFile)
 	return string(b), err
 }
+
+func (p *TestMixinProvider) GetProtocolVersions(ctx context.Context, name string) ([]int, error) {
+	if _, err := p.GetMetadata(ctx, name); err != nil {
+		return nil, err
+	}
+	return []int{1}, nil
+}
@@ -0,0 +1,139 @@
+package mixin
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/yaml"
+	"github.com/carolynvs/aferox"
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// LockFileName is the name of the file, in the same directory as the
+	// porter manifest, that records the exact versions of the mixins used by
+	// the bundle.
+	LockFileName = "porter.lock"
+
+	// LockFileSchemaVersion is the schema version of the lock files written by Porter.
+	LockFileSchemaVersion = "1.0.0"
+)
+
+// LockFile records the exact versions and digests of the mixins used by a
+// bundle, so that the bundle is built with the same mixins on every machine.
+type LockFile struct {
+	// SchemaVersion of the lock file.
+	SchemaVersion string `yaml:"schemaVersion"`
+
+	// Mixins that are locked, sorted by name.
+	Mixins []LockedMixin `yaml:"mixins"`
+}
+
+// LockedMixin is the version of a mixin that is recorded in a lock file.
+type LockedMixin struct {
+	// Name of the mixin.
+	Name string `yaml:"name"`
+
+	// Constraint is the version declared in the porter manifest when the
+	// mixin was locked, for example 1.0.x.
+	Constraint string `yaml:"constraint,omitempty"`
+
+	// Version of the mixin that was installed, for example v1.0.2.
+	Version string `yaml:"version"`
+
+	// ClientDigest is the digest of the mixin binary that is used by porter build.
+	ClientDigest digest.Digest `yaml:"clientDigest"`
+
+	// RuntimeDigest is the digest of the mixin binary that is copied into the
+	// invocation image.
+	RuntimeDigest digest.Digest `yaml:"runtimeDigest"`
+}
+
+// LoadLockFile reads a lock file. An empty lock file is returned when the file
+// does not exist.
+func LoadLockFile(fsys aferox.Aferox, path string) (LockFile, error) {
+	var lock LockFile
+
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return lock, nil
+		}
+		return lock, fmt.Errorf("could not read the mixin lock file at %s: %w", path, err)
+	}
+
+	if err = yaml.Unmarshal(data, &lock); err != nil {
+		return lock, fmt.Errorf("could not parse the mixin lock file at %s: %w", path, err)
+	}
+
+	return lock, nil
+}
+
+// Save writes the lock file, with the mixins sorted by name.
+func (l LockFile) Save(fsys aferox.Aferox, path string) error {
+	if l.SchemaVersion == "" {
+		l.SchemaVersion = LockFileSchemaVersion
+	}
+	l.Mixins = append([]LockedMixin(nil), l.Mixins...)
+	sort.Slice(l.Mixins, func(i, j int) bool {
+		return l.Mixins[i].Name < l.Mixins[j].Name
+	})
+
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("could not marshal the mixin lock file: %w", err)
+	}
+
+	data = append([]byte("# This file is generated by porter mixins ensure. Do not edit it by hand.\n"), data...)
+	if err = fsys.WriteFile(path, data, pkg.FileModeWritable); err != nil {
+		return fmt.Errorf("could not write the mixin lock file to %s: %w", path, err)
+	}
+	return nil
+}
+
+// Find the locked version of a mixin.
+func (l LockFile) Find(name string) (LockedMixin, bool) {
+	for _, m := range l.Mixins {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return LockedMixin{}, false
+}
+
+// GetDigests calculates the digests of the client and runtime binaries of a
+// mixin that is installed in the specified directory.
+func GetDigests(fsys aferox.Aferox, pkgDir string, name string) (clientDigest digest.Digest, runtimeDigest digest.Digest, err error) {
+	clientPath := filepath.Join(pkgDir, name) + pkgmgmt.FileExt
+	clientDigest, err = digestFile(fsys, clientPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	runtimePath := filepath.Join(pkgDir, "runtimes", name+"-runtime")
+	runtimeDigest, err = digestFile(fsys, runtimePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	return clientDigest, runtimeDigest, nil
+}
+
+func digestFile(fsys aferox.Aferox, path string) (digest.Digest, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("could not open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	d, err := digest.Canonical.FromReader(f)
+	if err != nil {
+		return "", fmt.Errorf("could not calculate the digest of %s: %w", path, err)
+	}
+	return d, nil
+}
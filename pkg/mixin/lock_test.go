@@ -0,0 +1,62 @@
+package mixin
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile_SaveAndLoad(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+
+	lock := LockFile{
+		Mixins: []LockedMixin{
+			{Name: "terraform", Constraint: "1.0.x", Version: "v1.0.2", ClientDigest: digest.FromString("terraform"), RuntimeDigest: digest.FromString("terraform-runtime")},
+			{Name: "exec", Version: "v1.0.0", ClientDigest: digest.FromString("exec"), RuntimeDigest: digest.FromString("exec-runtime")},
+		},
+	}
+	require.NoError(t, lock.Save(c.FileSystem, LockFileName))
+
+	data, err := c.FileSystem.ReadFile(LockFileName)
+	require.NoError(t, err)
+	c.CompareGoldenFile("testdata/porter.lock", string(data))
+
+	loaded, err := LoadLockFile(c.FileSystem, LockFileName)
+	require.NoError(t, err)
+	assert.Equal(t, LockFileSchemaVersion, loaded.SchemaVersion)
+	require.Len(t, loaded.Mixins, 2)
+	assert.Equal(t, "exec", loaded.Mixins[0].Name, "expected the mixins to be sorted by name")
+
+	terraform, ok := loaded.Find("terraform")
+	require.True(t, ok, "expected to find the terraform mixin")
+	assert.Equal(t, lock.Mixins[0], terraform)
+
+	_, ok = loaded.Find("helm3")
+	assert.False(t, ok, "expected the helm3 mixin to not be locked")
+}
+
+func TestLoadLockFile_Missing(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+
+	lock, err := LoadLockFile(c.FileSystem, LockFileName)
+	require.NoError(t, err)
+	assert.Empty(t, lock.Mixins)
+}
+
+func TestGetDigests(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+	pkgDir := "/home/myuser/.porter/mixins/terraform"
+	require.NoError(t, c.AddTestFileContents([]byte("client"), pkgDir+"/terraform"))
+	require.NoError(t, c.AddTestFileContents([]byte("runtime"), pkgDir+"/runtimes/terraform-runtime"))
+
+	clientDigest, runtimeDigest, err := GetDigests(c.FileSystem, pkgDir, "terraform")
+	require.NoError(t, err)
+	assert.Equal(t, digest.FromString("client"), clientDigest)
+	assert.Equal(t, digest.FromString("runtime"), runtimeDigest)
+
+	_, _, err = GetDigests(c.FileSystem, "/home/myuser/.porter/mixins/helm3", "helm3")
+	require.ErrorContains(t, err, "could not open /home/myuser/.porter/mixins/helm3/helm3")
+}
@@ -22,4 +22,8 @@ type MixinProvider interface {
 
 	// GetSchema requests the manifest schema from the mixin.
 	GetSchema(ctx context.Context, name string) (string, error)
+
+	// GetProtocolVersions returns the versions of the mixin protocol that the
+	// mixin supports.
+	GetProtocolVersions(ctx context.Context, name string) ([]int, error)
 }
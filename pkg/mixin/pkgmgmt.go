@@ -1,16 +1,11 @@
 package mixin
 
 import (
-	"bytes"
-	"context"
-	"io"
 	"os/exec"
 
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/pkgmgmt"
 	"get.porter.sh/porter/pkg/pkgmgmt/client"
-	"get.porter.sh/porter/pkg/tracing"
-	"go.uber.org/zap/zapcore"
 )
 
 const (
@@ -48,34 +43,6 @@ func (c *PackageManager) PreRunMixinCommandHandler(command string, cmd *exec.Cmd
 	}
 }
 
-func (c *PackageManager) GetSchema(ctx context.Context, name string) (string, error) {
-	log := tracing.LoggerFromContext(ctx)
-
-	mixinDir, err := c.GetPackageDir(name)
-	if err != nil {
-		return "", err
-	}
-
-	r := client.NewRunner(name, mixinDir, false)
-
-	// Copy the existing context and tweak to pipe the output differently
-	mixinSchema := &bytes.Buffer{}
-	mixinContext := *c.Context
-	mixinContext.Out = mixinSchema
-	if !log.ShouldLog(zapcore.DebugLevel) {
-		mixinContext.Err = io.Discard
-	}
-	r.Context = &mixinContext
-
-	cmd := pkgmgmt.CommandOptions{Command: "schema", PreRun: c.PreRun}
-	err = r.Run(ctx, cmd)
-	if err != nil {
-		return "", err
-	}
-
-	return mixinSchema.String(), nil
-}
-
 var _ pkgmgmt.PackageMetadata = Metadata{}
 
 // Metadata about an installed mixin.
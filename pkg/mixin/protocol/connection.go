@@ -0,0 +1,103 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/plugins"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+)
+
+var _ Client = &Connection{}
+
+// Connection to a mixin binary that supports version 2 of the mixin protocol.
+// The mixin runs until the connection is closed.
+type Connection struct {
+	// name of the mixin.
+	name string
+
+	// client manages the mixin process.
+	client *plugin.Client
+
+	// protocol is the connection to the mixin.
+	protocol Client
+}
+
+// Connect starts a binary of a mixin, either its client or runtime binary,
+// with the serve command and connects to it with version 2 of the mixin
+// protocol. An error is returned when the mixin does not support version 2 of
+// the protocol.
+func Connect(ctx context.Context, c *portercontext.Context, name string, binPath string) (*Connection, error) {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("mixin", name), attribute.String("path", binPath))
+	defer span.EndSpan()
+
+	var errbuf bytes.Buffer
+	conn := &Connection{name: name}
+	conn.client = plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: plugin.HandshakeConfig{
+			ProtocolVersion:  ProtocolVersion,
+			MagicCookieKey:   plugins.HandshakeConfig.MagicCookieKey,
+			MagicCookieValue: plugins.HandshakeConfig.MagicCookieValue,
+		},
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		Plugins: map[string]plugin.Plugin{
+			PluginInterface: Plugin{},
+		},
+		Cmd:    c.NewCommand(ctx, binPath, ServeCommand),
+		Logger: hclog.NewNullLogger(),
+		Stderr: &errbuf,
+		// Messages that the mixin prints instead of sending to the reporter
+		SyncStdout: c.Out,
+		SyncStderr: c.Err,
+		// Configure gRPC to propagate the span context so the mixin's traces
+		// show up under the current span
+		GRPCDialOptions: []grpc.DialOption{
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+		},
+	})
+
+	rpcClient, err := conn.client.Client(ctx)
+	if err != nil {
+		conn.client.Kill(ctx)
+		if errbuf.Len() > 0 {
+			err = fmt.Errorf("%w: mixin stderr was %s", err, errbuf.String())
+		}
+		return nil, fmt.Errorf("could not connect to the %s mixin: %w", name, err)
+	}
+
+	raw, err := rpcClient.Dispense(PluginInterface)
+	if err != nil {
+		conn.client.Kill(ctx)
+		return nil, span.Error(fmt.Errorf("could not connect to the %s mixin: %w", name, err))
+	}
+	conn.protocol = raw.(Client)
+
+	return conn, nil
+}
+
+// Execute a step with the mixin.
+func (c *Connection) Execute(ctx context.Context, req ExecuteRequest, reporter Reporter) (StepResult, error) {
+	return c.protocol.Execute(ctx, req, reporter)
+}
+
+// Schema returns the schema of the mixin.
+func (c *Connection) Schema(ctx context.Context, format string) (string, error) {
+	return c.protocol.Schema(ctx, format)
+}
+
+// Lint the steps that use the mixin.
+func (c *Connection) Lint(ctx context.Context, input map[string]interface{}) ([]LintResult, error) {
+	return c.protocol.Lint(ctx, input)
+}
+
+// Close the connection and stop the mixin.
+func (c *Connection) Close(ctx context.Context) {
+	c.client.Kill(ctx)
+}
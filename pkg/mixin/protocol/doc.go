@@ -0,0 +1,7 @@
+// Package protocol defines version 2 of the mixin protocol, where the runtime
+// binary of a mixin is a long-lived gRPC process that receives structured step
+// payloads, and returns typed outputs and rich errors to Porter.
+//
+// Mixins that only support version 1 of the protocol, where steps are piped
+// to the mixin as YAML on stdin, are still supported by the Porter runtime.
+package protocol
@@ -0,0 +1,302 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"get.porter.sh/porter/pkg/mixin/protocol/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var _ Client = &GClient{}
+
+// GClient is a gRPC implementation of the mixin protocol client.
+type GClient struct {
+	client proto.MixinProtocolClient
+}
+
+func NewClient(client proto.MixinProtocolClient) *GClient {
+	return &GClient{client}
+}
+
+func (m *GClient) Execute(ctx context.Context, req ExecuteRequest, reporter Reporter) (StepResult, error) {
+	step, err := toStruct(req.Step)
+	if err != nil {
+		return StepResult{}, fmt.Errorf("could not convert the step to a protobuf struct: %w", err)
+	}
+
+	stream, err := m.client.Execute(ctx, &proto.ExecuteRequest{Action: req.Action, Step: step})
+	if err != nil {
+		return StepResult{}, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return StepResult{}, errors.New("the mixin stopped before it returned the result of the step")
+		}
+		if err != nil {
+			if status.Code(err) == codes.Canceled && ctx.Err() != nil {
+				return StepResult{}, &Error{Code: ErrorCodeCanceled, Message: ctx.Err().Error()}
+			}
+			return StepResult{}, err
+		}
+
+		switch event := resp.Event.(type) {
+		case *proto.ExecuteResponse_Progress:
+			reporter.Progress(event.Progress.Message, int(event.Progress.Percent))
+		case *proto.ExecuteResponse_Log:
+			if event.Log.Error {
+				reporter.LogError(event.Log.Message)
+			} else {
+				reporter.Log(event.Log.Message)
+			}
+		case *proto.ExecuteResponse_Result:
+			return fromResult(event.Result)
+		}
+	}
+}
+
+func (m *GClient) Schema(ctx context.Context, format string) (string, error) {
+	resp, err := m.client.Schema(ctx, &proto.SchemaRequest{Format: format})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return resp.Schema, nil
+}
+
+func (m *GClient) Lint(ctx context.Context, input map[string]interface{}) ([]LintResult, error) {
+	in, err := toStruct(input)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert the lint input to a protobuf struct: %w", err)
+	}
+
+	resp, err := m.client.Lint(ctx, &proto.LintRequest{Input: in})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	results := make([]LintResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		result := LintResult{
+			Level:   int(r.Level),
+			Code:    r.Code,
+			Title:   r.Title,
+			Message: r.Message,
+			URL:     r.URL,
+		}
+		if r.Location != nil {
+			result.Location = LintLocation{
+				Path:            r.Location.Path,
+				Action:          r.Location.Action,
+				Mixin:           r.Location.Mixin,
+				StepNumber:      int(r.Location.StepNumber),
+				StepDescription: r.Location.StepDescription,
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// fromStatus converts the status returned by an optional call of the mixin
+// protocol into ErrUnimplemented when the mixin does not implement the call.
+func fromStatus(err error) error {
+	if status.Code(err) == codes.Unimplemented {
+		return ErrUnimplemented
+	}
+	return err
+}
+
+// GServer is a gRPC wrapper around a MixinProtocol implementation.
+type GServer struct {
+	impl MixinProtocol
+	proto.UnsafeMixinProtocolServer
+}
+
+func NewServer(impl MixinProtocol) *GServer {
+	return &GServer{impl: impl}
+}
+
+func (m *GServer) Execute(req *proto.ExecuteRequest, stream proto.MixinProtocol_ExecuteServer) error {
+	ctx := stream.Context()
+	r := &streamReporter{stream: stream}
+	result, err := m.impl.Execute(ctx, ExecuteRequest{Action: req.Action, Step: req.Step.AsMap()}, r)
+	if r.err != nil {
+		return r.err
+	}
+	if err != nil && ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	resp, err := toResult(result, err)
+	if err != nil {
+		return err
+	}
+	return stream.Send(&proto.ExecuteResponse{Event: &proto.ExecuteResponse_Result{Result: resp}})
+}
+
+func (m *GServer) Schema(ctx context.Context, req *proto.SchemaRequest) (*proto.SchemaResponse, error) {
+	impl, ok := m.impl.(SchemaProvider)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "the mixin does not provide its schema over the mixin protocol")
+	}
+
+	schema, err := impl.Schema(ctx, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SchemaResponse{Schema: schema}, nil
+}
+
+func (m *GServer) Lint(ctx context.Context, req *proto.LintRequest) (*proto.LintResponse, error) {
+	impl, ok := m.impl.(Linter)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "the mixin does not lint its steps over the mixin protocol")
+	}
+
+	results, err := impl.Lint(ctx, req.Input.AsMap())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.LintResponse{Results: make([]*proto.LintResult, 0, len(results))}
+	for _, r := range results {
+		resp.Results = append(resp.Results, &proto.LintResult{
+			Level: int32(r.Level),
+			Location: &proto.LintLocation{
+				Path:            r.Location.Path,
+				Action:          r.Location.Action,
+				Mixin:           r.Location.Mixin,
+				StepNumber:      int32(r.Location.StepNumber),
+				StepDescription: r.Location.StepDescription,
+			},
+			Code:    r.Code,
+			Title:   r.Title,
+			Message: r.Message,
+			URL:     r.URL,
+		})
+	}
+	return resp, nil
+}
+
+// streamReporter sends progress and log messages from a step to Porter.
+// It is safe to use from multiple goroutines.
+type streamReporter struct {
+	stream proto.MixinProtocol_ExecuteServer
+	lock   sync.Mutex
+
+	// err is the first error encountered sending a message to Porter.
+	err error
+}
+
+func (r *streamReporter) send(resp *proto.ExecuteResponse) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err == nil {
+		r.err = r.stream.Send(resp)
+	}
+}
+
+func (r *streamReporter) Progress(message string, percent int) {
+	r.send(&proto.ExecuteResponse{Event: &proto.ExecuteResponse_Progress{
+		Progress: &proto.Progress{Message: message, Percent: int32(percent)},
+	}})
+}
+
+func (r *streamReporter) Log(message string) {
+	r.send(&proto.ExecuteResponse{Event: &proto.ExecuteResponse_Log{
+		Log: &proto.Log{Message: message},
+	}})
+}
+
+func (r *streamReporter) LogError(message string) {
+	r.send(&proto.ExecuteResponse{Event: &proto.ExecuteResponse_Log{
+		Log: &proto.Log{Message: message, Error: true},
+	}})
+}
+
+// toStruct converts a step into a protobuf struct. The step is converted to
+// json first, so that values which are not supported by protobuf, such as
+// timestamps, are sent in the same format as they are printed.
+func toStruct(data map[string]interface{}) (*structpb.Struct, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonData map[string]interface{}
+	if err = json.Unmarshal(b, &jsonData); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(jsonData)
+}
+
+// toValue converts the value of an output into a protobuf value, using the
+// json representation of the value.
+func toValue(value interface{}) (*structpb.Value, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonValue interface{}
+	if err = json.Unmarshal(b, &jsonValue); err != nil {
+		return nil, err
+	}
+	return structpb.NewValue(jsonValue)
+}
+
+// toResult converts the result of a step into its protobuf representation.
+func toResult(result StepResult, stepErr error) (*proto.StepResult, error) {
+	resp := &proto.StepResult{}
+	if stepErr != nil {
+		mixinErr := &Error{}
+		if !errors.As(stepErr, &mixinErr) {
+			mixinErr = &Error{Code: ErrorCodeUnknown, Message: stepErr.Error()}
+		}
+		details, err := toStruct(mixinErr.Details)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert the details of the error to a protobuf struct: %w", err)
+		}
+		resp.Error = &proto.Error{Code: mixinErr.Code, Message: mixinErr.Message, Details: details}
+		return resp, nil
+	}
+
+	for _, output := range result.Outputs {
+		value, err := toValue(output.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert the value of output %s to a protobuf value: %w", output.Name, err)
+		}
+		resp.Outputs = append(resp.Outputs, &proto.Output{Name: output.Name, Value: value, Sensitive: output.Sensitive})
+	}
+	return resp, nil
+}
+
+// fromResult converts the protobuf representation of the result of a step.
+func fromResult(resp *proto.StepResult) (StepResult, error) {
+	if resp.Error != nil {
+		return StepResult{}, &Error{
+			Code:    resp.Error.Code,
+			Message: resp.Error.Message,
+			Details: resp.Error.Details.AsMap(),
+		}
+	}
+
+	result := StepResult{Outputs: make([]Output, 0, len(resp.Outputs))}
+	for _, output := range resp.Outputs {
+		result.Outputs = append(result.Outputs, Output{
+			Name:      output.Name,
+			Value:     output.Value.AsInterface(),
+			Sensitive: output.Sensitive,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,244 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"get.porter.sh/porter/pkg/mixin/protocol/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testMixin is a mixin that reports progress, logs the step, and then returns
+// the result or error configured on the test.
+type testMixin struct {
+	result StepResult
+	err    error
+	block  bool
+	req    ExecuteRequest
+}
+
+func (m *testMixin) Execute(ctx context.Context, req ExecuteRequest, reporter Reporter) (StepResult, error) {
+	m.req = req
+	reporter.Progress("Installing", 50)
+	reporter.Log("installing mysql")
+	reporter.LogError("mysql is slow today")
+	if m.block {
+		<-ctx.Done()
+		return StepResult{}, ctx.Err()
+	}
+	return m.result, m.err
+}
+
+// testReporter records the messages reported by a step.
+type testReporter struct {
+	progress []string
+	logs     []string
+	errors   []string
+}
+
+func (r *testReporter) Progress(message string, percent int) {
+	r.progress = append(r.progress, message)
+}
+
+func (r *testReporter) Log(message string) {
+	r.logs = append(r.logs, message)
+}
+
+func (r *testReporter) LogError(message string) {
+	r.errors = append(r.errors, message)
+}
+
+func newTestClient(t *testing.T, impl MixinProtocol) *GClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	proto.RegisterMixinProtocolServer(srv, NewServer(impl))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return NewClient(proto.NewMixinProtocolClient(conn))
+}
+
+func TestGRPC_Execute(t *testing.T) {
+	m := &testMixin{
+		result: StepResult{Outputs: []Output{
+			{Name: "host", Value: "mysql.local"},
+			{Name: "port", Value: 3306},
+			{Name: "config", Value: map[string]interface{}{"tls": true}},
+			{Name: "password", Value: "topsecret", Sensitive: true},
+		}},
+	}
+	client := newTestClient(t, m)
+
+	reporter := &testReporter{}
+	req := ExecuteRequest{
+		Action: "install",
+		Step:   map[string]interface{}{"description": "Install mysql", "arguments": []interface{}{"install"}},
+	}
+	result, err := client.Execute(context.Background(), req, reporter)
+	require.NoError(t, err)
+
+	assert.Equal(t, req, m.req, "the step was not sent to the mixin")
+	assert.Equal(t, []string{"Installing"}, reporter.progress)
+	assert.Equal(t, []string{"installing mysql"}, reporter.logs)
+	assert.Equal(t, []string{"mysql is slow today"}, reporter.errors)
+
+	wantOutputs := []Output{
+		{Name: "host", Value: "mysql.local"},
+		{Name: "port", Value: float64(3306)},
+		{Name: "config", Value: map[string]interface{}{"tls": true}},
+		{Name: "password", Value: "topsecret", Sensitive: true},
+	}
+	assert.Equal(t, wantOutputs, result.Outputs)
+}
+
+func TestGRPC_Execute_Error(t *testing.T) {
+	t.Run("rich error", func(t *testing.T) {
+		m := &testMixin{err: &Error{
+			Code:    "NotFound",
+			Message: "the chart was not found",
+			Details: map[string]interface{}{"chart": "mysql"},
+		}}
+		client := newTestClient(t, m)
+
+		_, err := client.Execute(context.Background(), ExecuteRequest{Action: "install"}, &testReporter{})
+		var mixinErr *Error
+		require.ErrorAs(t, err, &mixinErr)
+		assert.Equal(t, "NotFound", mixinErr.Code)
+		assert.Equal(t, "the chart was not found", mixinErr.Message)
+		assert.Equal(t, map[string]interface{}{"chart": "mysql"}, mixinErr.Details)
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		m := &testMixin{err: errors.New("oops")}
+		client := newTestClient(t, m)
+
+		_, err := client.Execute(context.Background(), ExecuteRequest{Action: "install"}, &testReporter{})
+		var mixinErr *Error
+		require.ErrorAs(t, err, &mixinErr)
+		assert.Equal(t, ErrorCodeUnknown, mixinErr.Code)
+		assert.Equal(t, "oops", mixinErr.Message)
+	})
+}
+
+func TestGRPC_Execute_Canceled(t *testing.T) {
+	m := &testMixin{block: true}
+	client := newTestClient(t, m)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reporter := &cancelingReporter{cancel: cancel}
+	_, err := client.Execute(ctx, ExecuteRequest{Action: "install"}, reporter)
+
+	var mixinErr *Error
+	require.ErrorAs(t, err, &mixinErr)
+	assert.Equal(t, ErrorCodeCanceled, mixinErr.Code)
+}
+
+// cancelingReporter cancels the step after the mixin reports an error message.
+type cancelingReporter struct {
+	testReporter
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReporter) LogError(message string) {
+	r.cancel()
+}
+
+func TestOutput_String(t *testing.T) {
+	testcases := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "string", value: "abc", want: "abc"},
+		{name: "nil", value: nil, want: ""},
+		{name: "number", value: float64(1.5), want: "1.5"},
+		{name: "bool", value: true, want: "true"},
+		{name: "object", value: map[string]interface{}{"a": "b"}, want: `{"a":"b"}`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Output{Name: "test", Value: tc.value}.String()
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// testLintMixin is a mixin that also provides its schema and lints its steps
+// over the mixin protocol.
+type testLintMixin struct {
+	testMixin
+	input map[string]interface{}
+}
+
+func (m *testLintMixin) Schema(ctx context.Context, format string) (string, error) {
+	return `{"format": "` + format + `"}`, nil
+}
+
+func (m *testLintMixin) Lint(ctx context.Context, input map[string]interface{}) ([]LintResult, error) {
+	m.input = input
+	return []LintResult{{
+		Level:    2,
+		Location: LintLocation{Action: "install", Mixin: "helm3", StepNumber: 1, StepDescription: "Install mysql"},
+		Code:     "helm3-100",
+		Title:    "Pin the chart version",
+	}}, nil
+}
+
+func TestGRPC_Schema(t *testing.T) {
+	t.Run("implemented", func(t *testing.T) {
+		client := newTestClient(t, &testLintMixin{})
+
+		schema, err := client.Schema(context.Background(), SchemaFormatJSONSchema)
+		require.NoError(t, err)
+		assert.Equal(t, `{"format": "jsonschema"}`, schema)
+	})
+
+	t.Run("unimplemented", func(t *testing.T) {
+		client := newTestClient(t, &testMixin{})
+
+		_, err := client.Schema(context.Background(), SchemaFormatJSONSchema)
+		require.ErrorIs(t, err, ErrUnimplemented)
+	})
+}
+
+func TestGRPC_Lint(t *testing.T) {
+	t.Run("implemented", func(t *testing.T) {
+		m := &testLintMixin{}
+		client := newTestClient(t, m)
+
+		input := map[string]interface{}{
+			"actions": map[string]interface{}{
+				"install": []interface{}{map[string]interface{}{"helm3": map[string]interface{}{"chart": "mysql"}}},
+			},
+		}
+		results, err := client.Lint(context.Background(), input)
+		require.NoError(t, err)
+
+		assert.Equal(t, input, m.input, "the input was not sent to the mixin")
+		require.Len(t, results, 1)
+		assert.Equal(t, "helm3-100", results[0].Code)
+		assert.Equal(t, 2, results[0].Level)
+		assert.Equal(t, LintLocation{Action: "install", Mixin: "helm3", StepNumber: 1, StepDescription: "Install mysql"}, results[0].Location)
+	})
+
+	t.Run("unimplemented", func(t *testing.T) {
+		client := newTestClient(t, &testMixin{})
+
+		_, err := client.Lint(context.Background(), nil)
+		require.ErrorIs(t, err, ErrUnimplemented)
+	})
+}
@@ -0,0 +1,32 @@
+package protocol
+
+import (
+	"context"
+
+	"get.porter.sh/porter/pkg/mixin/protocol/proto"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+var _ plugin.GRPCPlugin = Plugin{}
+
+// Plugin is the go-plugin wrapper of a mixin that supports version 2 of the
+// mixin protocol.
+type Plugin struct {
+	plugin.Plugin
+	impl MixinProtocol
+}
+
+// NewPlugin creates an instance of a mixin plugin.
+func NewPlugin(impl MixinProtocol) Plugin {
+	return Plugin{impl: impl}
+}
+
+func (p Plugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterMixinProtocolServer(s, NewServer(p.impl))
+	return nil
+}
+
+func (p Plugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return NewClient(proto.NewMixinProtocolClient(conn)), nil
+}
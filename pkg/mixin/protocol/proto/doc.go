@@ -0,0 +1,4 @@
+//go:generate protoc pkg/mixin/protocol/proto/mixin_protocol.proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative --proto_path=.
+
+// Package proto is the protobuf definition for the MixinProtocol
+package proto
@@ -0,0 +1,1171 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.19.4
+// source: pkg/mixin/protocol/proto/mixin_protocol.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExecuteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Action that is executed, for example install.
+	Action string `protobuf:"bytes,1,opt,name=Action,proto3" json:"Action,omitempty"`
+	// Step is the section of the step in the porter manifest that is handled by
+	// the mixin, with its template values resolved.
+	Step *structpb.Struct `protobuf:"bytes,2,opt,name=Step,proto3" json:"Step,omitempty"`
+}
+
+func (x *ExecuteRequest) Reset() {
+	*x = ExecuteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteRequest) ProtoMessage() {}
+
+func (x *ExecuteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExecuteRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetStep() *structpb.Struct {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
+type ExecuteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*ExecuteResponse_Progress
+	//	*ExecuteResponse_Log
+	//	*ExecuteResponse_Result
+	Event isExecuteResponse_Event `protobuf_oneof:"Event"`
+}
+
+func (x *ExecuteResponse) Reset() {
+	*x = ExecuteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteResponse) ProtoMessage() {}
+
+func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *ExecuteResponse) GetEvent() isExecuteResponse_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *ExecuteResponse) GetProgress() *Progress {
+	if x, ok := x.GetEvent().(*ExecuteResponse_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *ExecuteResponse) GetLog() *Log {
+	if x, ok := x.GetEvent().(*ExecuteResponse_Log); ok {
+		return x.Log
+	}
+	return nil
+}
+
+func (x *ExecuteResponse) GetResult() *StepResult {
+	if x, ok := x.GetEvent().(*ExecuteResponse_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+type isExecuteResponse_Event interface {
+	isExecuteResponse_Event()
+}
+
+type ExecuteResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=Progress,proto3,oneof"`
+}
+
+type ExecuteResponse_Log struct {
+	Log *Log `protobuf:"bytes,2,opt,name=Log,proto3,oneof"`
+}
+
+type ExecuteResponse_Result struct {
+	Result *StepResult `protobuf:"bytes,3,opt,name=Result,proto3,oneof"`
+}
+
+func (*ExecuteResponse_Progress) isExecuteResponse_Event() {}
+
+func (*ExecuteResponse_Log) isExecuteResponse_Event() {}
+
+func (*ExecuteResponse_Result) isExecuteResponse_Event() {}
+
+type Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	// Percent complete of the step, from 0 to 100. Set to -1 when unknown.
+	Percent int32 `protobuf:"varint,2,opt,name=Percent,proto3" json:"Percent,omitempty"`
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Progress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Progress) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+type Log struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	// Error is set when the message should be printed to stderr.
+	Error bool `protobuf:"varint,2,opt,name=Error,proto3" json:"Error,omitempty"`
+}
+
+func (x *Log) Reset() {
+	*x = Log{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Log) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Log) ProtoMessage() {}
+
+func (x *Log) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Log.ProtoReflect.Descriptor instead.
+func (*Log) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Log) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Log) GetError() bool {
+	if x != nil {
+		return x.Error
+	}
+	return false
+}
+
+type StepResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Outputs []*Output `protobuf:"bytes,1,rep,name=Outputs,proto3" json:"Outputs,omitempty"`
+	// Error is set when the step failed.
+	Error *Error `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+}
+
+func (x *StepResult) Reset() {
+	*x = StepResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StepResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepResult) ProtoMessage() {}
+
+func (x *StepResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepResult.ProtoReflect.Descriptor instead.
+func (*StepResult) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StepResult) GetOutputs() []*Output {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *StepResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type Output struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string          `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Value     *structpb.Value `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	Sensitive bool            `protobuf:"varint,3,opt,name=Sensitive,proto3" json:"Sensitive,omitempty"`
+}
+
+func (x *Output) Reset() {
+	*x = Output{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Output) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Output) ProtoMessage() {}
+
+func (x *Output) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Output.ProtoReflect.Descriptor instead.
+func (*Output) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Output) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Output) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Output) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
+type Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Code categorizes the error, for example NotFound.
+	Code    string           `protobuf:"bytes,1,opt,name=Code,proto3" json:"Code,omitempty"`
+	Message string           `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
+	Details *structpb.Struct `protobuf:"bytes,3,opt,name=Details,proto3" json:"Details,omitempty"`
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetDetails() *structpb.Struct {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type SchemaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Format of the schema, for example jsonschema.
+	Format string `protobuf:"bytes,1,opt,name=Format,proto3" json:"Format,omitempty"`
+}
+
+func (x *SchemaRequest) Reset() {
+	*x = SchemaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaRequest) ProtoMessage() {}
+
+func (x *SchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaRequest.ProtoReflect.Descriptor instead.
+func (*SchemaRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SchemaRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type SchemaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Schema string `protobuf:"bytes,1,opt,name=Schema,proto3" json:"Schema,omitempty"`
+}
+
+func (x *SchemaResponse) Reset() {
+	*x = SchemaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SchemaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaResponse) ProtoMessage() {}
+
+func (x *SchemaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaResponse.ProtoReflect.Descriptor instead.
+func (*SchemaResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SchemaResponse) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+type LintRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Input is the mixin configuration and the steps of each action that use the
+	// mixin, in the same format that is passed to the lint command.
+	Input *structpb.Struct `protobuf:"bytes,1,opt,name=Input,proto3" json:"Input,omitempty"`
+}
+
+func (x *LintRequest) Reset() {
+	*x = LintRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LintRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintRequest) ProtoMessage() {}
+
+func (x *LintRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintRequest.ProtoReflect.Descriptor instead.
+func (*LintRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LintRequest) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+type LintResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*LintResult `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+}
+
+func (x *LintResponse) Reset() {
+	*x = LintResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LintResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintResponse) ProtoMessage() {}
+
+func (x *LintResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintResponse.ProtoReflect.Descriptor instead.
+func (*LintResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LintResponse) GetResults() []*LintResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type LintResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Level of severity: 0 (error), 2 (warning) or 4 (info).
+	Level    int32         `protobuf:"varint,1,opt,name=Level,proto3" json:"Level,omitempty"`
+	Location *LintLocation `protobuf:"bytes,2,opt,name=Location,proto3" json:"Location,omitempty"`
+	Code     string        `protobuf:"bytes,3,opt,name=Code,proto3" json:"Code,omitempty"`
+	Title    string        `protobuf:"bytes,4,opt,name=Title,proto3" json:"Title,omitempty"`
+	Message  string        `protobuf:"bytes,5,opt,name=Message,proto3" json:"Message,omitempty"`
+	URL      string        `protobuf:"bytes,6,opt,name=URL,proto3" json:"URL,omitempty"`
+}
+
+func (x *LintResult) Reset() {
+	*x = LintResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LintResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintResult) ProtoMessage() {}
+
+func (x *LintResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintResult.ProtoReflect.Descriptor instead.
+func (*LintResult) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LintResult) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *LintResult) GetLocation() *LintLocation {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *LintResult) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *LintResult) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *LintResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LintResult) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+type LintLocation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path            string `protobuf:"bytes,1,opt,name=Path,proto3" json:"Path,omitempty"`
+	Action          string `protobuf:"bytes,2,opt,name=Action,proto3" json:"Action,omitempty"`
+	Mixin           string `protobuf:"bytes,3,opt,name=Mixin,proto3" json:"Mixin,omitempty"`
+	StepNumber      int32  `protobuf:"varint,4,opt,name=StepNumber,proto3" json:"StepNumber,omitempty"`
+	StepDescription string `protobuf:"bytes,5,opt,name=StepDescription,proto3" json:"StepDescription,omitempty"`
+}
+
+func (x *LintLocation) Reset() {
+	*x = LintLocation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LintLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintLocation) ProtoMessage() {}
+
+func (x *LintLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintLocation.ProtoReflect.Descriptor instead.
+func (*LintLocation) Descriptor() ([]byte, []int) {
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LintLocation) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LintLocation) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *LintLocation) GetMixin() string {
+	if x != nil {
+		return x.Mixin
+	}
+	return ""
+}
+
+func (x *LintLocation) GetStepNumber() int32 {
+	if x != nil {
+		return x.StepNumber
+	}
+	return 0
+}
+
+func (x *LintLocation) GetStepDescription() string {
+	if x != nil {
+		return x.StepDescription
+	}
+	return ""
+}
+
+var File_pkg_mixin_protocol_proto_mixin_protocol_proto protoreflect.FileDescriptor
+
+var file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDesc = []byte{
+	0x0a, 0x2d, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x69, 0x78, 0x69, 0x6e,
+	0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x06, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x55, 0x0a, 0x0e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x2b, 0x0a, 0x04, 0x53, 0x74, 0x65, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x04, 0x53, 0x74, 0x65, 0x70, 0x22, 0x99, 0x01, 0x0a,
+	0x0f, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2e, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x1f, 0x0a, 0x03, 0x4c, 0x6f, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x48, 0x00, 0x52, 0x03, 0x4c, 0x6f,
+	0x67, 0x12, 0x2c, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42,
+	0x07, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x3e, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0x35, 0x0a, 0x03, 0x4c, 0x6f, 0x67, 0x12,
+	0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22,
+	0x5b, 0x0a, 0x0a, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x28, 0x0a,
+	0x07, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x07,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x68, 0x0a, 0x06,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x65, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x53, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x22, 0x68, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x31, 0x0a,
+	0x07, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x07, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x22, 0x27, 0x0a, 0x0d, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x28, 0x0a, 0x0e, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x53,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x22, 0x3c, 0x0a, 0x0b, 0x4c, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2d, 0x0a, 0x05, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x05, 0x49, 0x6e, 0x70, 0x75,
+	0x74, 0x22, 0x3c, 0x0a, 0x0c, 0x4c, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2c, 0x0a, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x4c, 0x69, 0x6e, 0x74,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22,
+	0xaa, 0x01, 0x0a, 0x0a, 0x4c, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x12, 0x30, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e,
+	0x4c, 0x69, 0x6e, 0x74, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x4c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x69,
+	0x74, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x69, 0x74, 0x6c, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x55, 0x52,
+	0x4c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x55, 0x52, 0x4c, 0x22, 0x9a, 0x01, 0x0a,
+	0x0c, 0x4c, 0x69, 0x6e, 0x74, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x4d, 0x69, 0x78,
+	0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4d, 0x69, 0x78, 0x69, 0x6e, 0x12,
+	0x1e, 0x0a, 0x0a, 0x53, 0x74, 0x65, 0x70, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x53, 0x74, 0x65, 0x70, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x28, 0x0a, 0x0f, 0x53, 0x74, 0x65, 0x70, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x53, 0x74, 0x65, 0x70, 0x44, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x32, 0xb9, 0x01, 0x0a, 0x0d, 0x4d, 0x69,
+	0x78, 0x69, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x3c, 0x0a, 0x07, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e,
+	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17,
+	0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x37, 0x0a, 0x06, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x12, 0x15, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6d, 0x69, 0x78,
+	0x69, 0x6e, 0x73, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x31, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x74, 0x12, 0x13, 0x2e, 0x6d, 0x69, 0x78,
+	0x69, 0x6e, 0x73, 0x2e, 0x4c, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x14, 0x2e, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x73, 0x2e, 0x4c, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x65, 0x74, 0x2e, 0x70, 0x6f, 0x72,
+	0x74, 0x65, 0x72, 0x2e, 0x73, 0x68, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2f, 0x70, 0x6b,
+	0x67, 0x2f, 0x6d, 0x69, 0x78, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescOnce sync.Once
+	file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescData = file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDesc
+)
+
+func file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescGZIP() []byte {
+	file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescOnce.Do(func() {
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescData)
+	})
+	return file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDescData
+}
+
+var file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_pkg_mixin_protocol_proto_mixin_protocol_proto_goTypes = []interface{}{
+	(*ExecuteRequest)(nil),  // 0: mixins.ExecuteRequest
+	(*ExecuteResponse)(nil), // 1: mixins.ExecuteResponse
+	(*Progress)(nil),        // 2: mixins.Progress
+	(*Log)(nil),             // 3: mixins.Log
+	(*StepResult)(nil),      // 4: mixins.StepResult
+	(*Output)(nil),          // 5: mixins.Output
+	(*Error)(nil),           // 6: mixins.Error
+	(*SchemaRequest)(nil),   // 7: mixins.SchemaRequest
+	(*SchemaResponse)(nil),  // 8: mixins.SchemaResponse
+	(*LintRequest)(nil),     // 9: mixins.LintRequest
+	(*LintResponse)(nil),    // 10: mixins.LintResponse
+	(*LintResult)(nil),      // 11: mixins.LintResult
+	(*LintLocation)(nil),    // 12: mixins.LintLocation
+	(*structpb.Struct)(nil), // 13: google.protobuf.Struct
+	(*structpb.Value)(nil),  // 14: google.protobuf.Value
+}
+var file_pkg_mixin_protocol_proto_mixin_protocol_proto_depIdxs = []int32{
+	13, // 0: mixins.ExecuteRequest.Step:type_name -> google.protobuf.Struct
+	2,  // 1: mixins.ExecuteResponse.Progress:type_name -> mixins.Progress
+	3,  // 2: mixins.ExecuteResponse.Log:type_name -> mixins.Log
+	4,  // 3: mixins.ExecuteResponse.Result:type_name -> mixins.StepResult
+	5,  // 4: mixins.StepResult.Outputs:type_name -> mixins.Output
+	6,  // 5: mixins.StepResult.Error:type_name -> mixins.Error
+	14, // 6: mixins.Output.Value:type_name -> google.protobuf.Value
+	13, // 7: mixins.Error.Details:type_name -> google.protobuf.Struct
+	13, // 8: mixins.LintRequest.Input:type_name -> google.protobuf.Struct
+	11, // 9: mixins.LintResponse.Results:type_name -> mixins.LintResult
+	12, // 10: mixins.LintResult.Location:type_name -> mixins.LintLocation
+	0,  // 11: mixins.MixinProtocol.Execute:input_type -> mixins.ExecuteRequest
+	7,  // 12: mixins.MixinProtocol.Schema:input_type -> mixins.SchemaRequest
+	9,  // 13: mixins.MixinProtocol.Lint:input_type -> mixins.LintRequest
+	1,  // 14: mixins.MixinProtocol.Execute:output_type -> mixins.ExecuteResponse
+	8,  // 15: mixins.MixinProtocol.Schema:output_type -> mixins.SchemaResponse
+	10, // 16: mixins.MixinProtocol.Lint:output_type -> mixins.LintResponse
+	14, // [14:17] is the sub-list for method output_type
+	11, // [11:14] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_pkg_mixin_protocol_proto_mixin_protocol_proto_init() }
+func file_pkg_mixin_protocol_proto_mixin_protocol_proto_init() {
+	if File_pkg_mixin_protocol_proto_mixin_protocol_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Progress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Log); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StepResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Output); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Error); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SchemaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SchemaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LintRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LintResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LintResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LintLocation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*ExecuteResponse_Progress)(nil),
+		(*ExecuteResponse_Log)(nil),
+		(*ExecuteResponse_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_mixin_protocol_proto_mixin_protocol_proto_goTypes,
+		DependencyIndexes: file_pkg_mixin_protocol_proto_mixin_protocol_proto_depIdxs,
+		MessageInfos:      file_pkg_mixin_protocol_proto_mixin_protocol_proto_msgTypes,
+	}.Build()
+	File_pkg_mixin_protocol_proto_mixin_protocol_proto = out.File
+	file_pkg_mixin_protocol_proto_mixin_protocol_proto_rawDesc = nil
+	file_pkg_mixin_protocol_proto_mixin_protocol_proto_goTypes = nil
+	file_pkg_mixin_protocol_proto_mixin_protocol_proto_depIdxs = nil
+}
@@ -0,0 +1,206 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.19.4
+// source: pkg/mixin/protocol/proto/mixin_protocol.proto
+
+package proto
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MixinProtocolClient is the client API for MixinProtocol service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MixinProtocolClient interface {
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (MixinProtocol_ExecuteClient, error)
+	Schema(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error)
+	Lint(ctx context.Context, in *LintRequest, opts ...grpc.CallOption) (*LintResponse, error)
+}
+
+type mixinProtocolClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMixinProtocolClient(cc grpc.ClientConnInterface) MixinProtocolClient {
+	return &mixinProtocolClient{cc}
+}
+
+func (c *mixinProtocolClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (MixinProtocol_ExecuteClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MixinProtocol_ServiceDesc.Streams[0], "/mixins.MixinProtocol/Execute", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mixinProtocolExecuteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MixinProtocol_ExecuteClient interface {
+	Recv() (*ExecuteResponse, error)
+	grpc.ClientStream
+}
+
+type mixinProtocolExecuteClient struct {
+	grpc.ClientStream
+}
+
+func (x *mixinProtocolExecuteClient) Recv() (*ExecuteResponse, error) {
+	m := new(ExecuteResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mixinProtocolClient) Schema(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error) {
+	out := new(SchemaResponse)
+	err := c.cc.Invoke(ctx, "/mixins.MixinProtocol/Schema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mixinProtocolClient) Lint(ctx context.Context, in *LintRequest, opts ...grpc.CallOption) (*LintResponse, error) {
+	out := new(LintResponse)
+	err := c.cc.Invoke(ctx, "/mixins.MixinProtocol/Lint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MixinProtocolServer is the server API for MixinProtocol service.
+// All implementations must embed UnimplementedMixinProtocolServer
+// for forward compatibility
+type MixinProtocolServer interface {
+	Execute(*ExecuteRequest, MixinProtocol_ExecuteServer) error
+	Schema(context.Context, *SchemaRequest) (*SchemaResponse, error)
+	Lint(context.Context, *LintRequest) (*LintResponse, error)
+	mustEmbedUnimplementedMixinProtocolServer()
+}
+
+// UnimplementedMixinProtocolServer must be embedded to have forward compatible implementations.
+type UnimplementedMixinProtocolServer struct {
+}
+
+func (UnimplementedMixinProtocolServer) Execute(*ExecuteRequest, MixinProtocol_ExecuteServer) error {
+	return status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedMixinProtocolServer) Schema(context.Context, *SchemaRequest) (*SchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Schema not implemented")
+}
+func (UnimplementedMixinProtocolServer) Lint(context.Context, *LintRequest) (*LintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lint not implemented")
+}
+func (UnimplementedMixinProtocolServer) mustEmbedUnimplementedMixinProtocolServer() {}
+
+// UnsafeMixinProtocolServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MixinProtocolServer will
+// result in compilation errors.
+type UnsafeMixinProtocolServer interface {
+	mustEmbedUnimplementedMixinProtocolServer()
+}
+
+func RegisterMixinProtocolServer(s grpc.ServiceRegistrar, srv MixinProtocolServer) {
+	s.RegisterService(&MixinProtocol_ServiceDesc, srv)
+}
+
+func _MixinProtocol_Execute_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MixinProtocolServer).Execute(m, &mixinProtocolExecuteServer{stream})
+}
+
+type MixinProtocol_ExecuteServer interface {
+	Send(*ExecuteResponse) error
+	grpc.ServerStream
+}
+
+type mixinProtocolExecuteServer struct {
+	grpc.ServerStream
+}
+
+func (x *mixinProtocolExecuteServer) Send(m *ExecuteResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MixinProtocol_Schema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MixinProtocolServer).Schema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mixins.MixinProtocol/Schema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MixinProtocolServer).Schema(ctx, req.(*SchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MixinProtocol_Lint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MixinProtocolServer).Lint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mixins.MixinProtocol/Lint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MixinProtocolServer).Lint(ctx, req.(*LintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MixinProtocol_ServiceDesc is the grpc.ServiceDesc for MixinProtocol service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MixinProtocol_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mixins.MixinProtocol",
+	HandlerType: (*MixinProtocolServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Schema",
+			Handler:    _MixinProtocol_Schema_Handler,
+		},
+		{
+			MethodName: "Lint",
+			Handler:    _MixinProtocol_Lint_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Execute",
+			Handler:       _MixinProtocol_Execute_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/mixin/protocol/proto/mixin_protocol.proto",
+}
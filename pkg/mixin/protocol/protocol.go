@@ -0,0 +1,190 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// PluginInterface is the name of the mixin protocol that is dispensed
+	// to Porter when it connects to a mixin.
+	PluginInterface = "mixin"
+
+	// ProtocolVersion is the version of the mixin protocol defined in this package.
+	ProtocolVersion = 2
+
+	// ServeCommand is the command that Porter runs against the runtime binary
+	// of a mixin to start it as a long-lived gRPC process.
+	ServeCommand = "serve"
+
+	// ErrorCodeUnknown is the code of an error returned by a mixin that was
+	// not categorized.
+	ErrorCodeUnknown = "Unknown"
+
+	// ErrorCodeCanceled is the code of an error returned when the step was
+	// cancelled before it completed.
+	ErrorCodeCanceled = "Canceled"
+
+	// SchemaFormatJSONSchema is the format of a schema defined with json schema.
+	SchemaFormatJSONSchema = "jsonschema"
+)
+
+// ErrUnimplemented is returned when a mixin does not implement an optional
+// call of the mixin protocol, such as Schema or Lint.
+var ErrUnimplemented = errors.New("the mixin does not implement this call of the mixin protocol")
+
+// MixinProtocol is the interface that mixins implement to support version 2
+// of the mixin protocol.
+type MixinProtocol interface {
+	// Execute a step of an action. Progress and log messages are sent to the
+	// reporter while the step executes. The step should stop when the context
+	// is cancelled.
+	Execute(ctx context.Context, req ExecuteRequest, reporter Reporter) (StepResult, error)
+}
+
+// SchemaProvider is an optional interface that mixins implement to return the
+// schema of their steps over the mixin protocol.
+type SchemaProvider interface {
+	// Schema returns the schema of the mixin configuration and steps in the
+	// requested format, for example jsonschema.
+	Schema(ctx context.Context, format string) (string, error)
+}
+
+// Linter is an optional interface that mixins implement to lint their steps
+// over the mixin protocol.
+type Linter interface {
+	// Lint the mixin configuration and steps. The input has the same format as
+	// the input of the lint command.
+	Lint(ctx context.Context, input map[string]interface{}) ([]LintResult, error)
+}
+
+// Client is the set of calls that Porter makes to a mixin over the mixin
+// protocol. Optional calls that the mixin does not implement return
+// ErrUnimplemented.
+type Client interface {
+	MixinProtocol
+	SchemaProvider
+	Linter
+}
+
+// ExecuteRequest is the step that a mixin executes.
+type ExecuteRequest struct {
+	// Action that is executed, for example install.
+	Action string
+
+	// Step is the section of the step in the porter manifest that is handled
+	// by the mixin, with its template values resolved.
+	Step map[string]interface{}
+}
+
+// Reporter receives the progress and log messages of a step while it executes.
+type Reporter interface {
+	// Progress of the step. Percent is from 0 to 100, or -1 when unknown.
+	Progress(message string, percent int)
+
+	// Log a message to stdout.
+	Log(message string)
+
+	// LogError logs a message to stderr.
+	LogError(message string)
+}
+
+// StepResult is the result of a step that executed successfully.
+type StepResult struct {
+	// Outputs generated by the step.
+	Outputs []Output
+}
+
+// Output is a typed output generated by a step.
+type Output struct {
+	// Name of the output.
+	Name string
+
+	// Value of the output, which may be a string, number, bool, nil, or a
+	// map or slice of those types.
+	Value interface{}
+
+	// Sensitive outputs are masked in the logs.
+	Sensitive bool
+}
+
+// String returns the value of the output as it is stored by Porter. Strings
+// are returned as-is and other values are formatted as json.
+func (o Output) String() (string, error) {
+	switch v := o.Value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("could not format the value of output %s as json: %w", o.Name, err)
+		}
+		return string(data), nil
+	}
+}
+
+var _ error = &Error{}
+
+// Error is a rich error returned by a mixin when a step fails.
+type Error struct {
+	// Code categorizes the error, for example NotFound.
+	Code string
+
+	// Message describes the error.
+	Message string
+
+	// Details provides additional structured information about the error.
+	Details map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" || e.Code == ErrorCodeUnknown {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// LintResult is a problem identified by a mixin when it lints its steps. It is
+// formatted as json in the same way as the results of the lint command.
+type LintResult struct {
+	// Level of severity: 0 (error), 2 (warning) or 4 (info).
+	Level int
+
+	// Location of the problem in the manifest.
+	Location LintLocation
+
+	// Code uniquely identifying the type of problem, for example exec-100.
+	Code string
+
+	// Title to display (80 chars).
+	Title string
+
+	// Message explaining the problem.
+	Message string
+
+	// URL that provides additional assistance with this problem.
+	URL string
+}
+
+// LintLocation identifies the offending step, or section, of a manifest.
+type LintLocation struct {
+	// Path to the offending section of the manifest, for results that are not
+	// about a step.
+	Path string `json:",omitempty"`
+
+	// Action containing the step, e.g. install.
+	Action string
+
+	// Mixin name, e.g. exec.
+	Mixin string
+
+	// StepNumber is the position of the step, starting from 1, within the action.
+	StepNumber int
+
+	// StepDescription is the description of the step provided in the manifest.
+	StepDescription string
+}
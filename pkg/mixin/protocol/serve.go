@@ -0,0 +1,13 @@
+package protocol
+
+import (
+	"get.porter.sh/porter/pkg/plugins"
+	"get.porter.sh/porter/pkg/portercontext"
+)
+
+// Serve a mixin with version 2 of the mixin protocol. Mixins call Serve when
+// their runtime binary is run with the serve command, and it blocks until
+// Porter closes the connection.
+func Serve(c *portercontext.Context, impl MixinProtocol) {
+	plugins.Serve(c, PluginInterface, NewPlugin(impl), ProtocolVersion)
+}
@@ -0,0 +1,232 @@
+package mixin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/mixin/protocol"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/pkgmgmt/client"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"get.porter.sh/porter/pkg/yaml"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// ProtocolCacheFile is the name of the file in the mixin directory where Porter
+// caches the schema of the mixin, and if it supports version 2 of the mixin
+// protocol, so that the mixin is not queried every time that it is used.
+const ProtocolCacheFile = "protocol-cache.json"
+
+// protocolCache is what Porter learned about a mixin the last time that it
+// was queried.
+type protocolCache struct {
+	// BinaryModTime is the last modified time of the client binary that was queried.
+	BinaryModTime time.Time `json:"binaryModTime"`
+
+	// BinarySize is the size of the client binary that was queried.
+	BinarySize int64 `json:"binarySize"`
+
+	// Protocol indicates if the client binary supports version 2 of the mixin protocol.
+	Protocol bool `json:"protocol"`
+
+	// Schema of the mixin.
+	Schema string `json:"schema"`
+}
+
+// GetSchema requests the manifest schema from the mixin. The schema is cached
+// until the mixin is upgraded.
+func (c *PackageManager) GetSchema(ctx context.Context, name string) (string, error) {
+	cache, err := c.getProtocolCache(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return cache.Schema, nil
+}
+
+// GetProtocolVersions returns the versions of the mixin protocol that the
+// mixin supports. Every mixin supports version 1, where Porter runs commands
+// against the mixin binary.
+func (c *PackageManager) GetProtocolVersions(ctx context.Context, name string) ([]int, error) {
+	cache, err := c.getProtocolCache(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache.Protocol {
+		return []int{1, protocol.ProtocolVersion}, nil
+	}
+	return []int{1}, nil
+}
+
+// Run a command against the mixin. The lint command is sent over the mixin
+// protocol when the mixin supports it.
+func (c *PackageManager) Run(ctx context.Context, pkgContext *portercontext.Context, name string, commandOpts pkgmgmt.CommandOptions) error {
+	if commandOpts.Command == "lint" && !commandOpts.Runtime {
+		handled, err := c.lint(ctx, pkgContext, name, commandOpts)
+		if handled {
+			return err
+		}
+	}
+	return c.FileSystem.Run(ctx, pkgContext, name, commandOpts)
+}
+
+// lint the steps that use the mixin over the mixin protocol, printing the
+// results to stdout in the same format as the lint command. Returns false when
+// the mixin does not lint over the mixin protocol.
+func (c *PackageManager) lint(ctx context.Context, pkgContext *portercontext.Context, name string, commandOpts pkgmgmt.CommandOptions) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("mixin", name))
+	defer span.EndSpan()
+
+	cache, err := c.getProtocolCache(ctx, name)
+	if err != nil || !cache.Protocol {
+		return false, nil
+	}
+
+	var input map[string]interface{}
+	if err = yaml.Unmarshal([]byte(commandOpts.Input), &input); err != nil {
+		return true, span.Error(fmt.Errorf("could not parse the lint input for the %s mixin: %w", name, err))
+	}
+
+	conn, err := c.connect(ctx, name)
+	if err != nil {
+		span.Debugf("Using the lint command for the %s mixin: %s", name, err)
+		return false, nil
+	}
+	defer conn.Close(ctx)
+
+	results, err := conn.Lint(ctx, input)
+	if errors.Is(err, protocol.ErrUnimplemented) {
+		return false, nil
+	}
+	if err != nil {
+		return true, span.Error(err)
+	}
+
+	if results == nil {
+		results = []protocol.LintResult{}
+	}
+	return true, json.NewEncoder(pkgContext.Out).Encode(results)
+}
+
+// getProtocolCache returns what Porter knows about the mixin, querying the
+// mixin when it has not been queried before or was upgraded since.
+func (c *PackageManager) getProtocolCache(ctx context.Context, name string) (protocolCache, error) {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("mixin", name))
+	defer span.EndSpan()
+
+	mixinDir, err := c.GetPackageDir(name)
+	if err != nil {
+		return protocolCache{}, span.Error(err)
+	}
+
+	clientInfo, err := c.FileSystem.FileSystem.Stat(c.BuildClientPath(mixinDir, name))
+	if err != nil {
+		return protocolCache{}, span.Error(fmt.Errorf("could not stat the client binary of the %s mixin: %w", name, err))
+	}
+
+	cachePath := filepath.Join(mixinDir, ProtocolCacheFile)
+	var cache protocolCache
+	if data, err := c.FileSystem.FileSystem.ReadFile(cachePath); err == nil {
+		if err = json.Unmarshal(data, &cache); err == nil &&
+			cache.BinaryModTime.Equal(clientInfo.ModTime()) && cache.BinarySize == clientInfo.Size() {
+			return cache, nil
+		}
+	}
+
+	cache = protocolCache{BinaryModTime: clientInfo.ModTime(), BinarySize: clientInfo.Size()}
+	cache.Protocol, cache.Schema, err = c.querySchema(ctx, name)
+	if err != nil {
+		return protocolCache{}, span.Error(err)
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return protocolCache{}, span.Error(fmt.Errorf("could not marshal the protocol cache for the %s mixin: %w", name, err))
+	}
+	if err = c.FileSystem.FileSystem.WriteFile(cachePath, data, pkg.FileModeWritable); err != nil {
+		// The cache is an optimization, keep going without it
+		span.Debugf("could not save the protocol cache for the %s mixin: %s", name, err)
+	}
+	return cache, nil
+}
+
+// querySchema requests the schema from the mixin, first over the mixin
+// protocol, then with the schema command. Returns if the mixin supports the
+// mixin protocol, and its schema.
+func (c *PackageManager) querySchema(ctx context.Context, name string) (bool, string, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	conn, err := c.connect(ctx, name)
+	if err != nil {
+		log.Debugf("Using the schema command for the %s mixin: %s", name, err)
+		schema, err := c.runSchemaCommand(ctx, name)
+		return false, schema, err
+	}
+	defer conn.Close(ctx)
+
+	schema, err := conn.Schema(ctx, protocol.SchemaFormatJSONSchema)
+	if errors.Is(err, protocol.ErrUnimplemented) {
+		schema, err = c.runSchemaCommand(ctx, name)
+	}
+	return true, schema, err
+}
+
+// connect to the client binary of the mixin with the mixin protocol.
+func (c *PackageManager) connect(ctx context.Context, name string) (*protocol.Connection, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	mixinDir, err := c.GetPackageDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages printed by the mixin, instead of returned over the protocol,
+	// are only logged when debugging so that they do not corrupt the output.
+	mixinContext := *c.Context
+	mixinContext.Out = io.Discard
+	mixinContext.Err = io.Discard
+	if log.ShouldLog(zapcore.DebugLevel) {
+		mixinContext.Out = c.Context.Err
+		mixinContext.Err = c.Context.Err
+	}
+
+	return protocol.Connect(ctx, &mixinContext, name, c.BuildClientPath(mixinDir, name))
+}
+
+// runSchemaCommand requests the schema from the mixin with the schema command.
+func (c *PackageManager) runSchemaCommand(ctx context.Context, name string) (string, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	mixinDir, err := c.GetPackageDir(name)
+	if err != nil {
+		return "", err
+	}
+
+	r := client.NewRunner(name, mixinDir, false)
+
+	// Copy the existing context and tweak to pipe the output differently
+	mixinSchema := &bytes.Buffer{}
+	mixinContext := *c.Context
+	mixinContext.Out = mixinSchema
+	if !log.ShouldLog(zapcore.DebugLevel) {
+		mixinContext.Err = io.Discard
+	}
+	r.Context = &mixinContext
+
+	cmd := pkgmgmt.CommandOptions{Command: "schema", PreRun: c.PreRun}
+	err = r.Run(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	return mixinSchema.String(), nil
+}
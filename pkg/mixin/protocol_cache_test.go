@@ -0,0 +1,86 @@
+package mixin
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageManager_GetSchema_Cached(t *testing.T) {
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	home, _ := c.GetHomeDir()
+	mixinDir := filepath.Join(home, "mixins", "exec")
+	clientPath := filepath.Join(mixinDir, "exec")
+	require.NoError(t, c.FileSystem.WriteFile(clientPath, []byte("exec v1"), pkg.FileModeExecutable))
+
+	// The mixin does not support the mixin protocol, so its schema is requested with the schema command
+	c.Setenv(test.ExpectedCommandEnv, clientPath+" serve\n"+clientPath+" schema")
+	c.Setenv(test.ExpectedCommandOutputEnv, `{"version": 1}`)
+
+	p := NewPackageManager(c.Config)
+	gotSchema, err := p.GetSchema(ctx, "exec")
+	require.NoError(t, err)
+	assert.Equal(t, `{"version": 1}`+"\n", gotSchema)
+	exists, _ := c.FileSystem.Exists(filepath.Join(mixinDir, ProtocolCacheFile))
+	assert.True(t, exists, "the schema should be cached")
+
+	t.Run("cache hit", func(t *testing.T) {
+		c.Setenv(test.ExpectedCommandOutputEnv, `{"version": 2}`)
+
+		gotSchema, err := p.GetSchema(ctx, "exec")
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 1}`+"\n", gotSchema, "the cached schema should be returned without running the mixin")
+	})
+
+	t.Run("mixin upgraded", func(t *testing.T) {
+		require.NoError(t, c.FileSystem.WriteFile(clientPath, []byte("exec v2.0"), pkg.FileModeExecutable))
+		c.Setenv(test.ExpectedCommandOutputEnv, `{"version": 2}`)
+
+		gotSchema, err := p.GetSchema(ctx, "exec")
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 2}`+"\n", gotSchema, "the mixin should be queried again after it is upgraded")
+	})
+}
+
+func TestPackageManager_GetProtocolVersions(t *testing.T) {
+	testcases := []struct {
+		name     string
+		protocol bool
+		want     []int
+	}{
+		{name: "commands only", protocol: false, want: []int{1}},
+		{name: "mixin protocol", protocol: true, want: []int{1, 2}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := config.NewTestConfig(t)
+			home, _ := c.GetHomeDir()
+			mixinDir := filepath.Join(home, "mixins", "exec")
+			clientPath := filepath.Join(mixinDir, "exec")
+			require.NoError(t, c.FileSystem.WriteFile(clientPath, []byte("exec v1"), pkg.FileModeExecutable))
+			clientInfo, err := c.FileSystem.Stat(clientPath)
+			require.NoError(t, err)
+
+			// Populate the cache so that the mixin is not queried
+			cache := protocolCache{BinaryModTime: clientInfo.ModTime(), BinarySize: clientInfo.Size(), Protocol: tc.protocol}
+			data, err := json.Marshal(cache)
+			require.NoError(t, err)
+			require.NoError(t, c.FileSystem.WriteFile(filepath.Join(mixinDir, ProtocolCacheFile), data, pkg.FileModeWritable))
+
+			p := NewPackageManager(c.Config)
+			got, err := p.GetProtocolVersions(ctx, "exec")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -3,4 +3,14 @@ package query
 type BuildInput struct {
 	Config  interface{}            `yaml:"config,omitempty"`
 	Actions map[string]interface{} `yaml:"actions"`
+
+	// Secrets are the ids of the build secrets passed to porter build with --secret.
+	// Mixins can mount them in the Dockerfile lines that they generate
+	// with RUN --mount=type=secret,id=ID, without saving them in the image.
+	Secrets []string `yaml:"secrets,omitempty"`
+
+	// SSH are the ids of the SSH agent sockets or keys passed to porter build with --ssh.
+	// Mixins can mount them in the Dockerfile lines that they generate
+	// with RUN --mount=type=ssh,id=ID.
+	SSH []string `yaml:"ssh,omitempty"`
 }
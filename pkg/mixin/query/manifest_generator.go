@@ -11,6 +11,12 @@ import (
 // ManifestGenerator generates mixin input from the manifest contents associated with each mixin.
 type ManifestGenerator struct {
 	Manifest *manifest.Manifest
+
+	// Secrets are the ids of the build secrets available to the mixins when the bundle is built.
+	Secrets []string
+
+	// SSH are the ids of the SSH agent sockets or keys available to the mixins when the bundle is built.
+	SSH []string
 }
 
 func NewManifestGenerator(m *manifest.Manifest) *ManifestGenerator {
@@ -42,6 +48,8 @@ func (g ManifestGenerator) BuildInput(mixinName string) ([]byte, error) {
 func (g ManifestGenerator) buildInputForMixin(mixinName string) BuildInput {
 	input := BuildInput{
 		Actions: make(map[string]interface{}, 3),
+		Secrets: g.Secrets,
+		SSH:     g.SSH,
 	}
 
 	for _, mixinDecl := range g.Manifest.Mixins {
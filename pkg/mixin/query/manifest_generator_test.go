@@ -42,4 +42,12 @@ func TestManifestGenerator_BuildInput(t *testing.T) {
 		input := g.buildInputForMixin("az")
 		assert.Equal(t, map[string]interface{}{"extensions": []interface{}{"iot"}}, input.Config, "az mixin should have config")
 	})
+
+	t.Run("with build secrets", func(t *testing.T) {
+		g := ManifestGenerator{Manifest: m, Secrets: []string{"pip"}, SSH: []string{"default"}}
+		inputB, err := g.BuildInput("exec")
+		require.NoError(t, err)
+		assert.Contains(t, string(inputB), "secrets:\n  - pip\n", "the build secret ids should be passed to the mixin")
+		assert.Contains(t, string(inputB), "ssh:\n  - default\n", "the ssh ids should be passed to the mixin")
+	})
 }
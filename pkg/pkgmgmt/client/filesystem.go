@@ -150,3 +150,33 @@ func (fs *FileSystem) GetPackageDir(name string) (string, error) {
 func (fs *FileSystem) BuildClientPath(pkgDir string, name string) string {
 	return filepath.Join(pkgDir, name) + pkgmgmt.FileExt
 }
+
+// GetInstallInfo returns where an installed package was installed from, as
+// recorded in the package cache, and the location of its client binary. The
+// package was last installed when its client binary was written.
+func (fs *FileSystem) GetInstallInfo(name string) (pkgmgmt.InstallInfo, error) {
+	pkgDir, err := fs.GetPackageDir(name)
+	if err != nil {
+		return pkgmgmt.InstallInfo{}, err
+	}
+
+	info, err := fs.getPackageInfo(name)
+	if err != nil {
+		return pkgmgmt.InstallInfo{}, err
+	}
+
+	installInfo := pkgmgmt.InstallInfo{
+		FeedURL:    info.FeedURL,
+		URL:        info.URL,
+		Source:     info.Source,
+		BinaryPath: fs.BuildClientPath(pkgDir, name),
+	}
+
+	binaryInfo, err := fs.FileSystem.Stat(installInfo.BinaryPath)
+	if err != nil {
+		return pkgmgmt.InstallInfo{}, fmt.Errorf("could not stat the client binary of the %s %s: %w", name, fs.PackageType, err)
+	}
+	installInfo.InstalledAt = binaryInfo.ModTime()
+
+	return installInfo, nil
+}
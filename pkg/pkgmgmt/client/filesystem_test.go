@@ -3,7 +3,9 @@ package client
 import (
 	"testing"
 
+	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/pkgmgmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,3 +21,31 @@ func TestFileSystem_List(t *testing.T) {
 	assert.Equal(t, mixins[0], "exec")
 	assert.Equal(t, mixins[1], "testmixin")
 }
+
+func TestFileSystem_GetInstallInfo(t *testing.T) {
+	c := config.NewTestConfig(t)
+	p := NewFileSystem(c.Config, "mixins")
+
+	cacheJSON := `{"packages": [{"name": "exec", "URL": "https://cdn.porter.sh/mixins/atom.xml"}]}`
+	require.NoError(t, p.FileSystem.WriteFile("/home/myuser/.porter/mixins/cache.json", []byte(cacheJSON), pkg.FileModeWritable))
+
+	t.Run("installed from feed", func(t *testing.T) {
+		info, err := p.GetInstallInfo("exec")
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn.porter.sh/mixins/atom.xml", info.FeedURL)
+		assert.Equal(t, "/home/myuser/.porter/mixins/exec/exec"+pkgmgmt.FileExt, info.BinaryPath)
+		assert.False(t, info.InstalledAt.IsZero(), "the install time should be the last modified time of the binary")
+	})
+
+	t.Run("not in cache", func(t *testing.T) {
+		info, err := p.GetInstallInfo("testmixin")
+		require.NoError(t, err)
+		assert.Empty(t, info.FeedURL)
+		assert.Equal(t, "/home/myuser/.porter/mixins/testmixin/testmixin"+pkgmgmt.FileExt, info.BinaryPath)
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		_, err := p.GetInstallInfo("helm")
+		require.ErrorContains(t, err, "mixins helm not installed")
+	})
+}
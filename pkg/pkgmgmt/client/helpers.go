@@ -6,6 +6,7 @@ import (
 	"path"
 	"sync"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/pkgmgmt"
 	"get.porter.sh/porter/pkg/portercontext"
@@ -21,6 +22,13 @@ type TestPackageManager struct {
 	RunAssertions     []func(pkgContext *portercontext.Context, name string, commandOpts pkgmgmt.CommandOptions) error
 	InstallAssertions []func(installOpts pkgmgmt.InstallOptions) error
 
+	// LatestVersions is the latest version of each package in its feed.
+	LatestVersions map[string]string
+
+	// InstallInfo is where each package was installed from. Packages that are
+	// not in the map are reported as installed from a URL at the start of 2022.
+	InstallInfo map[string]pkgmgmt.InstallInfo
+
 	// called keeps track of which mixins/plugins were called
 	called sync.Map
 	lock   sync.Mutex
@@ -63,6 +71,24 @@ func (p *TestPackageManager) GetMetadata(ctx context.Context, name string) (pkgm
 	return nil, fmt.Errorf("%s %s not installed", p.PkgType, name)
 }
 
+func (p *TestPackageManager) GetInstallInfo(name string) (pkgmgmt.InstallInfo, error) {
+	if info, ok := p.InstallInfo[name]; ok {
+		return info, nil
+	}
+
+	for _, pkg := range p.Packages {
+		if pkg.GetName() == name {
+			pkgDir, _ := p.GetPackageDir(name)
+			return pkgmgmt.InstallInfo{
+				URL:         fmt.Sprintf("https://cdn.porter.sh/%s/%s/latest", p.PkgType, name),
+				InstalledAt: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				BinaryPath:  path.Join(pkgDir, name),
+			}, nil
+		}
+	}
+	return pkgmgmt.InstallInfo{}, fmt.Errorf("%s %s not installed", p.PkgType, name)
+}
+
 func (p *TestPackageManager) Install(ctx context.Context, opts pkgmgmt.InstallOptions) error {
 	for _, assert := range p.InstallAssertions {
 		err := assert(opts)
@@ -78,6 +104,29 @@ func (p *TestPackageManager) Uninstall(ctx context.Context, opts pkgmgmt.Uninsta
 	return nil
 }
 
+func (p *TestPackageManager) GetLatestVersion(ctx context.Context, name string, opts pkgmgmt.OutdatedOptions) (pkgmgmt.PackageVersions, error) {
+	meta, err := p.GetMetadata(ctx, name)
+	if err != nil {
+		return pkgmgmt.PackageVersions{}, err
+	}
+
+	latestVersion, ok := p.LatestVersions[name]
+	if !ok {
+		return pkgmgmt.PackageVersions{}, fmt.Errorf("the feed does not contain an entry for %s", name)
+	}
+
+	feedURL, err := opts.GetFeedURL("")
+	if err != nil {
+		return pkgmgmt.PackageVersions{}, err
+	}
+	return pkgmgmt.PackageVersions{
+		Name:           name,
+		CurrentVersion: meta.GetVersionInfo().Version,
+		LatestVersion:  latestVersion,
+		FeedURL:        feedURL.String(),
+	}, nil
+}
+
 func (p *TestPackageManager) Run(ctx context.Context, pkgContext *portercontext.Context, name string, commandOpts pkgmgmt.CommandOptions) error {
 	for _, assert := range p.RunAssertions {
 		p.recordCalled(name)
@@ -12,16 +12,25 @@ import (
 	"runtime"
 
 	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
 	"get.porter.sh/porter/pkg/pkgmgmt"
 	"get.porter.sh/porter/pkg/pkgmgmt/feed"
+	"get.porter.sh/porter/pkg/pkgmgmt/oci"
 	"get.porter.sh/porter/pkg/tracing"
 )
 
 const PackageCacheJSON string = "cache.json"
 
 func (fs *FileSystem) Install(ctx context.Context, opts pkgmgmt.InstallOptions) error {
-	var err error
-	if opts.FeedURL != "" {
+	err := fs.CheckNetworkAccess(fmt.Sprintf("install the %s %s", opts.PackageType, opts.Name))
+	if err != nil {
+		return err
+	}
+
+	if opts.Source != "" {
+		err = fs.InstallFromOCI(ctx, opts)
+	} else if opts.FeedURL != "" {
 		err = fs.InstallFromFeedURL(ctx, opts)
 	} else {
 		err = fs.InstallFromURL(ctx, opts)
@@ -63,7 +72,7 @@ func (fs *FileSystem) savePackageInfo(ctx context.Context, opts pkgmgmt.InstallO
 			return nil
 		}
 	}
-	updatedPkgList := append(pkgDataJSON.Packages, PackageInfo{Name: opts.Name, FeedURL: opts.FeedURL, URL: opts.URL})
+	updatedPkgList := append(pkgDataJSON.Packages, PackageInfo{Name: opts.Name, FeedURL: opts.FeedURL, URL: opts.URL, Source: opts.Source})
 	pkgDataJSON.Packages = updatedPkgList
 	updatedPkgInfo, err := json.MarshalIndent(&pkgDataJSON, "", "  ")
 	if err != nil {
@@ -81,6 +90,7 @@ type PackageInfo struct {
 	Name    string `json:"name"`
 	FeedURL string `json:"URL,omitempty"`
 	URL     string `json:"url,omitempty"`
+	Source  string `json:"source,omitempty"`
 }
 
 type packages struct {
@@ -113,41 +123,113 @@ func (fs *FileSystem) installFromURLFor(ctx context.Context, opts pkgmgmt.Instal
 func (fs *FileSystem) InstallFromFeedURL(ctx context.Context, opts pkgmgmt.InstallOptions) error {
 	log := tracing.LoggerFromContext(ctx)
 
-	feedUrl := opts.GetParsedFeedURL()
+	searchFeed, err := fs.loadFeed(ctx, opts.GetParsedFeedURL())
+	if err != nil {
+		return err
+	}
+
+	result := searchFeed.Search(opts.Name, opts.Version)
+	if result == nil {
+		return log.Error(fmt.Errorf("the feed at %s does not contain an entry for %s @ %s", opts.FeedURL, opts.Name, opts.Version))
+	}
+
+	clientUrl := result.FindDownloadURL(ctx, runtime.GOOS, runtime.GOARCH)
+	if clientUrl == nil {
+		return log.Error(fmt.Errorf("%s @ %s did not publish a download for %s/%s", opts.Name, opts.Version, runtime.GOOS, runtime.GOARCH))
+	}
+
+	runtimeUrl := result.FindDownloadURL(ctx, "linux", "amd64")
+	if runtimeUrl == nil {
+		return log.Error(fmt.Errorf("%s @ %s did not publish a download for linux/amd64", opts.Name, opts.Version))
+	}
+
+	return fs.downloadPackage(ctx, opts.Name, *clientUrl, *runtimeUrl)
+}
+
+// loadFeed downloads and parses the atom feed at the specified url.
+func (fs *FileSystem) loadFeed(ctx context.Context, feedUrl url.URL) (*feed.MixinFeed, error) {
+	log := tracing.LoggerFromContext(ctx)
+
 	tmpDir, err := fs.FileSystem.TempDir("", "porter")
 	if err != nil {
-		return log.Error(fmt.Errorf("error creating temp directory: %w", err))
+		return nil, log.Error(fmt.Errorf("error creating temp directory: %w", err))
 	}
 	defer fs.FileSystem.RemoveAll(tmpDir)
 	feedPath := filepath.Join(tmpDir, "atom.xml")
 
 	err = fs.downloadFile(ctx, feedUrl, feedPath, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	searchFeed := feed.NewMixinFeed(fs.Context)
 	err = searchFeed.Load(ctx, feedPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	result := searchFeed.Search(opts.Name, opts.Version)
-	if result == nil {
-		return log.Error(fmt.Errorf("the feed at %s does not contain an entry for %s @ %s", opts.FeedURL, opts.Name, opts.Version))
+	return searchFeed, nil
+}
+
+func (fs *FileSystem) InstallFromOCI(ctx context.Context, opts pkgmgmt.InstallOptions) error {
+	log := tracing.LoggerFromContext(ctx)
+
+	ref, err := cnab.ParseOCIReference(oci.TrimSource(opts.Source))
+	if err != nil {
+		return log.Error(fmt.Errorf("invalid source %s: %w", opts.Source, err))
+	}
+	regOpts := cnabtooci.RegistryOptions{
+		InsecureRegistry: opts.InsecureRegistry,
+		RegistryTLS:      fs.Data.RegistryTLS,
+	}
+	craneOpts, err := cnabtooci.GetCraneOptions(fs.FileSystem, regOpts, ref.Registry())
+	if err != nil {
+		return log.Error(err)
 	}
 
-	clientUrl := result.FindDownloadURL(ctx, runtime.GOOS, runtime.GOARCH)
-	if clientUrl == nil {
-		return log.Error(fmt.Errorf("%s @ %s did not publish a download for %s/%s", opts.Name, opts.Version, runtime.GOOS, runtime.GOARCH))
+	artifact, err := oci.Pull(ctx, opts.PackageType, opts.Source, craneOpts...)
+	if err != nil {
+		return log.Error(err)
+	}
+	if artifact.Name != opts.Name {
+		return log.Error(fmt.Errorf("%s contains the %s %s, not %s", opts.Source, artifact.Name, opts.PackageType, opts.Name))
 	}
+	log.Debugf("Installing %s @ %s from %s@%s", opts.Name, artifact.Version, ref.Repository(), artifact.Digest)
 
-	runtimeUrl := result.FindDownloadURL(ctx, "linux", "amd64")
-	if runtimeUrl == nil {
-		return log.Error(fmt.Errorf("%s @ %s did not publish a download for linux/amd64", opts.Name, opts.Version))
+	clientBinary, ok := artifact.FindBinary(runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return log.Error(fmt.Errorf("%s @ %s did not publish a binary for %s/%s", opts.Name, artifact.Version, runtime.GOOS, runtime.GOARCH))
 	}
 
-	return fs.downloadPackage(ctx, opts.Name, *clientUrl, *runtimeUrl)
+	runtimeBinary, ok := artifact.FindBinary("linux", "amd64")
+	if !ok {
+		return log.Error(fmt.Errorf("%s @ %s did not publish a binary for linux/amd64", opts.Name, artifact.Version))
+	}
+
+	parentDir, err := fs.GetPackagesDir()
+	if err != nil {
+		return err
+	}
+	pkgDir := filepath.Join(parentDir, opts.Name)
+
+	clientPath := fs.BuildClientPath(pkgDir, opts.Name)
+	err = fs.writePackageFile(ctx, clientPath, true, func(w io.Writer) error {
+		return artifact.WriteBinary(clientBinary, w)
+	})
+	if err != nil {
+		return err
+	}
+
+	runtimePath := filepath.Join(pkgDir, "runtimes", opts.Name+"-runtime")
+	err = fs.writePackageFile(ctx, runtimePath, true, func(w io.Writer) error {
+		return artifact.WriteBinary(runtimeBinary, w)
+	})
+	if err != nil {
+		fs.FileSystem.RemoveAll(pkgDir) // If the runtime download fails, cleanup the package so it's not half installed
+		return err
+	}
+
+	return nil
 }
 
 func (fs *FileSystem) downloadPackage(ctx context.Context, name string, clientUrl url.URL, runtimeUrl url.URL) error {
@@ -177,6 +259,10 @@ func (fs *FileSystem) downloadFile(ctx context.Context, url url.URL, destPath st
 	log := tracing.LoggerFromContext(ctx)
 	log.Debugf("Downloading %s to %s\n", url.String(), destPath)
 
+	if err := fs.CheckNetworkAccess(fmt.Sprintf("download %s", url.String())); err != nil {
+		return err
+	}
+
 	req, err := http.NewRequest(http.MethodGet, url.String(), nil)
 	if err != nil {
 		return log.Error(fmt.Errorf("error creating web request to %s: %w", url.String(), err))
@@ -193,6 +279,17 @@ func (fs *FileSystem) downloadFile(ctx context.Context, url url.URL, destPath st
 	}
 	defer resp.Body.Close()
 
+	return fs.writePackageFile(ctx, destPath, executable, func(w io.Writer) error {
+		_, err := io.Copy(w, resp.Body)
+		return err
+	})
+}
+
+// writePackageFile creates a file for a package, and writes its contents
+// with the write function. The file is removed when it cannot be written.
+func (fs *FileSystem) writePackageFile(ctx context.Context, destPath string, executable bool, write func(w io.Writer) error) error {
+	log := tracing.LoggerFromContext(ctx)
+
 	// Ensure the parent directories exist
 	parentDir := filepath.Dir(destPath)
 	parentDirExists, err := fs.FileSystem.DirExists(parentDir)
@@ -226,7 +323,7 @@ func (fs *FileSystem) downloadFile(ctx context.Context, url url.URL, destPath st
 		}
 	}
 
-	_, err = io.Copy(destFile, resp.Body)
+	err = write(destFile)
 	if err != nil {
 		cleanup()
 		return log.Error(fmt.Errorf("error writing the file to %s: %w", destPath, err))
@@ -15,7 +15,10 @@ import (
 	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/pkgmgmt/oci"
 	"get.porter.sh/porter/tests"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -121,6 +124,63 @@ func TestFileSystem_InstallFromFeedUrl(t *testing.T) {
 	assert.True(t, runtimeExists)
 }
 
+func TestFileSystem_InstallFromOCI(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+
+	c := config.NewTestConfig(t)
+	p := NewFileSystem(c.Config, "packages")
+
+	// publish a fake package with binaries for this machine and the invocation image
+	require.NoError(t, c.FileSystem.WriteFile("/bin/mypkg-client", []byte("i am the client"), pkg.FileModeExecutable))
+	require.NoError(t, c.FileSystem.WriteFile("/bin/mypkg-linux-amd64", []byte("i am the runtime"), pkg.FileModeExecutable))
+	testPkg := oci.Package{
+		Type:    "mixin",
+		Name:    "mypkg",
+		Version: "v1.2.3",
+		Binaries: []oci.Binary{
+			{OS: runtime.GOOS, Arch: runtime.GOARCH, Path: "/bin/mypkg-client"},
+			{OS: "linux", Arch: "amd64", Path: "/bin/mypkg-linux-amd64"},
+		},
+	}
+	if runtime.GOOS == "linux" && runtime.GOARCH == "amd64" {
+		testPkg.Binaries = testPkg.Binaries[1:]
+	}
+	source := oci.SourcePrefix + strings.TrimPrefix(srv.URL, "http://") + "/mixins/mypkg:v1.2.3"
+	_, err := oci.Push(context.Background(), c.FileSystem, testPkg, source, crane.Insecure)
+	require.NoError(t, err)
+
+	t.Run("installed", func(t *testing.T) {
+		opts := pkgmgmt.InstallOptions{
+			PackageType:      "mixin",
+			Source:           source,
+			InsecureRegistry: true,
+		}
+		require.NoError(t, opts.Validate([]string{"mypkg"}), "Validate failed")
+
+		err := p.Install(context.Background(), opts)
+		require.NoError(t, err)
+
+		runtimeContents, err := p.FileSystem.ReadFile("/home/myuser/.porter/packages/mypkg/runtimes/mypkg-runtime")
+		require.NoError(t, err)
+		assert.Equal(t, "i am the runtime", string(runtimeContents))
+		clientExists, _ := p.FileSystem.Exists(p.BuildClientPath("/home/myuser/.porter/packages/mypkg", "mypkg"))
+		assert.True(t, clientExists)
+	})
+
+	t.Run("wrong package", func(t *testing.T) {
+		opts := pkgmgmt.InstallOptions{
+			PackageType:      "mixin",
+			Source:           source,
+			InsecureRegistry: true,
+		}
+		require.NoError(t, opts.Validate([]string{"otherpkg"}), "Validate failed")
+
+		err := p.Install(context.Background(), opts)
+		tests.RequireErrorContains(t, err, "contains the mypkg mixin, not otherpkg")
+	})
+}
+
 func TestFileSystem_Install_RollbackMissingRuntime(t *testing.T) {
 	// serve out a fake package
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -200,3 +260,31 @@ func TestFileSystem_Install_PackageInfoSavedWhenNoFileExists(t *testing.T) {
 	assert.Equal(t, name, pkgData.Name)
 	assert.Equal(t, packageURL, pkgData.URL)
 }
+
+func TestFileSystem_Install_Offline(t *testing.T) {
+	requested := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer ts.Close()
+
+	c := config.NewTestConfig(t)
+	c.Data.Offline = true
+	p := NewFileSystem(c.Config, "packages")
+
+	opts := pkgmgmt.InstallOptions{
+		PackageType: "mixin",
+		Version:     "latest",
+		FeedURL:     ts.URL + "/atom.xml",
+	}
+	require.NoError(t, opts.Validate([]string{"mypkg"}), "Validate failed")
+
+	err := p.Install(context.Background(), opts)
+	require.ErrorIs(t, err, config.ErrOffline)
+	assert.Contains(t, err.Error(), "cannot install the mixin mypkg")
+	assert.False(t, requested, "the feed should not be downloaded in offline mode")
+
+	_, _, err = p.findLatestVersion(context.Background(), "mypkg", pkgmgmt.OutdatedOptions{FeedURL: ts.URL + "/atom.xml", PackageType: "mixin"})
+	require.ErrorIs(t, err, config.ErrOffline)
+	assert.False(t, requested, "the feed should not be downloaded in offline mode")
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (fs *FileSystem) GetLatestVersion(ctx context.Context, name string, opts pkgmgmt.OutdatedOptions) (pkgmgmt.PackageVersions, error) {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("package.type", fs.PackageType), attribute.String("package.name", name))
+	defer span.EndSpan()
+
+	meta, err := fs.GetMetadata(ctx, name)
+	if err != nil {
+		return pkgmgmt.PackageVersions{}, span.Error(err)
+	}
+	versions := pkgmgmt.PackageVersions{
+		Name:           name,
+		CurrentVersion: meta.GetVersionInfo().Version,
+	}
+
+	versions.LatestVersion, versions.FeedURL, err = fs.findLatestVersion(ctx, name, opts)
+	if err != nil {
+		return pkgmgmt.PackageVersions{}, span.Error(err)
+	}
+
+	return versions, nil
+}
+
+// findLatestVersion searches the feed of a package for its latest version.
+// Returns the latest version and the feed that was searched.
+func (fs *FileSystem) findLatestVersion(ctx context.Context, name string, opts pkgmgmt.OutdatedOptions) (string, string, error) {
+	info, err := fs.getPackageInfo(name)
+	if err != nil {
+		return "", "", err
+	}
+	if opts.FeedURL == "" && info.Source != "" {
+		return "", "", fmt.Errorf("the %s %s was installed from %s and is not published in a feed", name, fs.PackageType, info.Source)
+	}
+
+	feedUrl, err := opts.GetFeedURL(info.FeedURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	searchFeed, err := fs.loadFeed(ctx, feedUrl)
+	if err != nil {
+		return "", "", err
+	}
+
+	result := searchFeed.Search(name, "latest")
+	if result == nil {
+		return "", "", fmt.Errorf("the feed at %s does not contain an entry for %s", feedUrl.String(), name)
+	}
+	return result.Version, feedUrl.String(), nil
+}
+
+// getPackageInfo returns where a package was installed from, as recorded in
+// the package cache. An empty PackageInfo is returned when the package is not
+// in the cache.
+func (fs *FileSystem) getPackageInfo(name string) (PackageInfo, error) {
+	parentDir, err := fs.GetPackagesDir()
+	if err != nil {
+		return PackageInfo{}, err
+	}
+
+	cacheJSONPath := filepath.Join(parentDir, PackageCacheJSON)
+	exists, _ := fs.FileSystem.Exists(cacheJSONPath)
+	if !exists {
+		return PackageInfo{}, nil
+	}
+
+	cacheContentsB, err := fs.FileSystem.ReadFile(cacheJSONPath)
+	if err != nil {
+		return PackageInfo{}, fmt.Errorf("error reading package %s cache.json: %w", fs.PackageType, err)
+	}
+	if len(cacheContentsB) == 0 {
+		return PackageInfo{}, nil
+	}
+
+	var pkgDataJSON packages
+	if err = json.Unmarshal(cacheContentsB, &pkgDataJSON); err != nil {
+		return PackageInfo{}, fmt.Errorf("error unmarshalling from %s package cache.json: %w", fs.PackageType, err)
+	}
+	for _, pkg := range pkgDataJSON.Packages {
+		if pkg.Name == name {
+			return pkg, nil
+		}
+	}
+	return PackageInfo{}, nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystem_FindLatestVersion(t *testing.T) {
+	feed, err := os.ReadFile("../feed/testdata/atom.xml")
+	require.NoError(t, err)
+
+	// serve out a fake feed
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, string(feed))
+	}))
+	defer ts.Close()
+
+	c := config.NewTestConfig(t)
+	p := NewFileSystem(c.Config, "packages")
+
+	cacheJSON := fmt.Sprintf(`{"packages": [
+  {"name": "helm", "URL": "%s/atom.xml"},
+  {"name": "terraform", "source": "oci://example.com/mixins/terraform:v1.0.0"}
+]}`, ts.URL)
+	require.NoError(t, p.FileSystem.WriteFile("/home/myuser/.porter/packages/cache.json", []byte(cacheJSON), pkg.FileModeWritable))
+
+	t.Run("installed feed", func(t *testing.T) {
+		opts := pkgmgmt.OutdatedOptions{PackageType: "mixin"}
+		require.NoError(t, opts.Validate())
+
+		version, feedURL, err := p.findLatestVersion(context.Background(), "helm", opts)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.2.4", version)
+		assert.Equal(t, ts.URL+"/atom.xml", feedURL)
+	})
+
+	t.Run("feed url specified", func(t *testing.T) {
+		opts := pkgmgmt.OutdatedOptions{PackageType: "mixin", FeedURL: ts.URL + "/other.xml"}
+		require.NoError(t, opts.Validate())
+
+		version, feedURL, err := p.findLatestVersion(context.Background(), "exec", opts)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.2.3", version)
+		assert.Equal(t, ts.URL+"/other.xml", feedURL)
+	})
+
+	t.Run("installed from source", func(t *testing.T) {
+		opts := pkgmgmt.OutdatedOptions{PackageType: "mixin"}
+		require.NoError(t, opts.Validate())
+
+		_, _, err := p.findLatestVersion(context.Background(), "terraform", opts)
+		tests.RequireErrorContains(t, err, "was installed from oci://example.com/mixins/terraform:v1.0.0 and is not published in a feed")
+	})
+
+	t.Run("not in feed", func(t *testing.T) {
+		opts := pkgmgmt.OutdatedOptions{PackageType: "mixin", FeedURL: ts.URL + "/atom.xml"}
+		require.NoError(t, opts.Validate())
+
+		_, _, err := p.findLatestVersion(context.Background(), "missing", opts)
+		tests.RequireErrorContains(t, err, "does not contain an entry for missing")
+	})
+}
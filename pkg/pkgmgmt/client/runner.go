@@ -91,12 +91,20 @@ func (r *Runner) Run(ctx context.Context, commandOpts pkgmgmt.CommandOptions) er
 	prettyCmd := fmt.Sprintf("%s%s", cmd.Dir, strings.Join(cmd.Args, " "))
 	span.SetAttributes(attribute.String("command", prettyCmd))
 
-	err := cmd.Start()
+	// When running a bundle, stop any processes started by the package when
+	// the step is cancelled or times out
+	wait := cmd.Wait
+	var err error
+	if commandOpts.Runtime {
+		wait, err = portercontext.StartProcessGroup(ctx, cmd)
+	} else {
+		err = cmd.Start()
+	}
 	if err != nil {
 		return span.Error(fmt.Errorf("could not start package command %s: %w", prettyCmd, err))
 	}
 
-	err = cmd.Wait()
+	err = wait()
 	if err != nil {
 		// Include stderr in the error, otherwise it just includes the exit code
 		err = fmt.Errorf("package command failed %s\n%s", prettyCmd, cmdStderr)
@@ -61,6 +61,29 @@ func (feed *MixinFeed) Search(mixin string, version string) *MixinFileset {
 		if latestVersion != nil {
 			return versions[latestVersion.Original()]
 		}
+		return nil
+	}
+
+	// Return the highest version of the requested mixin that satisfies the version constraint, such as 1.0.x
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return nil
+	}
+	var matchedVersion *semver.Version
+	for version := range versions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if !constraint.Check(v) {
+			continue
+		}
+		if matchedVersion == nil || v.GreaterThan(matchedVersion) {
+			matchedVersion = v
+		}
+	}
+	if matchedVersion != nil {
+		return versions[matchedVersion.Original()]
 	}
 
 	return nil
@@ -74,6 +74,42 @@ func TestMixinFeed_Search_Canary(t *testing.T) {
 	assert.Equal(t, "v2-canary", result.Version)
 }
 
+func TestMixinFeed_Search_Constraint(t *testing.T) {
+	tc := portercontext.NewTestContext(t)
+	f := NewMixinFeed(tc.Context)
+
+	f.Index["helm"] = make(map[string]*MixinFileset)
+	for _, version := range []string{"canary", "v1.0.1", "v1.0.2", "v1.1.0", "v2.0.0-alpha.1"} {
+		f.Index["helm"][version] = &MixinFileset{
+			Mixin:   "helm",
+			Version: version,
+		}
+	}
+
+	testcases := []struct {
+		constraint  string
+		wantVersion string
+	}{
+		{constraint: "1.0.x", wantVersion: "v1.0.2"},
+		{constraint: "^1.0.0", wantVersion: "v1.1.0"},
+		{constraint: "1.0.1", wantVersion: "v1.0.1"},
+		{constraint: ">= 2.0.0-alpha", wantVersion: "v2.0.0-alpha.1"},
+		{constraint: "3.x"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			result := f.Search("helm", tc.constraint)
+			if tc.wantVersion == "" {
+				assert.Nil(t, result)
+				return
+			}
+			require.NotNil(t, result)
+			assert.Equal(t, tc.wantVersion, result.Version)
+		})
+	}
+}
+
 func TestMixinFileset_FindDownloadURL(t *testing.T) {
 	t.Run("darwin/arm64 fallback to amd64", func(t *testing.T) {
 		link, _ := url.Parse("https://example.com/mymixin-darwin-amd64")
@@ -6,6 +6,9 @@ import (
 	"net/url"
 	"path"
 	"strings"
+
+	"get.porter.sh/porter/pkg/pkgmgmt/oci"
+	"github.com/google/go-containerregistry/pkg/name"
 )
 
 type InstallOptions struct {
@@ -18,6 +21,15 @@ type InstallOptions struct {
 	parsedURL     *url.URL
 	parsedFeedURL *url.URL
 
+	// Source is an OCI reference to the package in a registry, for example
+	// oci://ghcr.io/getporter/mixins/helm3:v1.0.0.
+	// The package is installed from the registry instead of a URL or feed.
+	Source string
+
+	// InsecureRegistry allows installing from a Source in an unsecured
+	// registry or one without verifiable certificates.
+	InsecureRegistry bool
+
 	PackageType string
 }
 
@@ -59,6 +71,10 @@ func (o *InstallOptions) Validate(args []string) error {
 		return err
 	}
 
+	if o.Source != "" {
+		return o.validateSource()
+	}
+
 	err = o.validateFeedURL()
 	if err != nil {
 		return err
@@ -74,6 +90,29 @@ func (o *InstallOptions) Validate(args []string) error {
 	return nil
 }
 
+// validateSource checks that the source is an OCI reference, which cannot be
+// combined with the other ways to install a package.
+func (o *InstallOptions) validateSource() error {
+	if !strings.HasPrefix(o.Source, oci.SourcePrefix) {
+		return fmt.Errorf("invalid --source %s, the source must be an OCI reference that starts with %s", o.Source, oci.SourcePrefix)
+	}
+	if _, err := name.ParseReference(oci.TrimSource(o.Source)); err != nil {
+		return fmt.Errorf("invalid --source %s: %w", o.Source, err)
+	}
+
+	if o.URL != "" || o.FeedURL != "" {
+		return errors.New("--source cannot be used with --url or --feed-url")
+	}
+
+	// The version is selected with the tag or digest of the source
+	if o.Version != "" && o.Version != "latest" {
+		return errors.New("--version cannot be used with --source, specify the version with the tag or digest of the source instead")
+	}
+	o.Version = ""
+
+	return nil
+}
+
 func (o *InstallOptions) validateURL() error {
 	if o.URL == "" {
 		return nil
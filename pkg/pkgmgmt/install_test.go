@@ -159,3 +159,33 @@ func TestInstallOptions_Validate(t *testing.T) {
 		assert.Contains(t, err.Error(), `invalid package type "oops"`)
 	})
 }
+
+func TestInstallOptions_ValidateSource(t *testing.T) {
+	testcases := []struct {
+		name      string
+		opts      InstallOptions
+		wantError string
+	}{
+		{name: "valid", opts: InstallOptions{Source: "oci://ghcr.io/org/mixins/mypkg:v1.2.3", Version: "latest"}},
+		{name: "missing prefix", opts: InstallOptions{Source: "ghcr.io/org/mixins/mypkg:v1.2.3"}, wantError: "the source must be an OCI reference that starts with oci://"},
+		{name: "invalid reference", opts: InstallOptions{Source: "oci://ghcr.io/org/MIXINS/mypkg"}, wantError: "invalid --source"},
+		{name: "url", opts: InstallOptions{Source: "oci://ghcr.io/org/mixins/mypkg:v1.2.3", URL: "https://example.com"}, wantError: "--source cannot be used with --url or --feed-url"},
+		{name: "feed url", opts: InstallOptions{Source: "oci://ghcr.io/org/mixins/mypkg:v1.2.3", FeedURL: "https://example.com/atom.xml"}, wantError: "--source cannot be used with --url or --feed-url"},
+		{name: "version", opts: InstallOptions{Source: "oci://ghcr.io/org/mixins/mypkg:v1.2.3", Version: "v1.2.3"}, wantError: "--version cannot be used with --source"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := tc.opts
+			opts.PackageType = "mixin"
+			err := opts.Validate([]string{"mypkg"})
+			if tc.wantError != "" {
+				require.ErrorContains(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, opts.FeedURL, "the feed url should not be defaulted when installing from a source")
+			assert.Empty(t, opts.Version, "the version is selected by the source")
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package pkgmgmt
 
+import "time"
+
 var _ PackageMetadata = Metadata{}
 
 // Metadata about an installed package.
@@ -26,6 +28,29 @@ type VersionInfo struct {
 	Version string `json:"version"`
 	Commit  string `json:"commit"`
 	Author  string `json:"author,omitempty"`
+
+	// Prerequisites are the commands that must be available on the host for
+	// the package to work, for example a mixin that shells out to kubectl.
+	Prerequisites []string `json:"prerequisites,omitempty" yaml:"prerequisites,omitempty"`
+}
+
+// InstallInfo describes where an installed package was installed from, and
+// where it is installed.
+type InstallInfo struct {
+	// FeedURL is the atom feed that the package was installed from.
+	FeedURL string `json:"feedURL,omitempty" yaml:"feedURL,omitempty"`
+
+	// URL is the location of the binaries that the package was installed from.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Source is the OCI reference that the package was installed from.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// InstalledAt is when the package was last installed or upgraded.
+	InstalledAt time.Time `json:"installedAt" yaml:"installedAt"`
+
+	// BinaryPath is the location of the client binary of the package.
+	BinaryPath string `json:"binaryPath" yaml:"binaryPath"`
 }
 
 // PackageMetadata is a common interface for packages managed by Porter.
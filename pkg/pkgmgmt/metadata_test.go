@@ -0,0 +1,23 @@
+package pkgmgmt
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionInfo_MarshalYaml(t *testing.T) {
+	t.Run("no prerequisites", func(t *testing.T) {
+		b, err := yaml.Marshal(VersionInfo{Version: "v1.0.0", Commit: "abc123", Author: "Porter Authors"})
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "prerequisites", "empty prerequisites should be omitted")
+	})
+
+	t.Run("prerequisites", func(t *testing.T) {
+		b, err := yaml.Marshal(VersionInfo{Version: "v1.0.0", Prerequisites: []string{"kubectl"}})
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "prerequisites:\n  - kubectl\n")
+	})
+}
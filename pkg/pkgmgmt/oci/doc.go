@@ -0,0 +1,3 @@
+// Package oci publishes and installs Porter packages (mixins or plugins) as
+// OCI artifacts in a registry.
+package oci
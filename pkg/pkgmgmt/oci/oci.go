@@ -0,0 +1,220 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/carolynvs/aferox"
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// SourcePrefix is the prefix of a package source that is an OCI
+	// reference, for example oci://ghcr.io/getporter/mixins/helm3:v1.0.0.
+	SourcePrefix = "oci://"
+
+	// BinaryMediaType is the media type of the layers of a package artifact,
+	// each layer is the package binary for a platform.
+	BinaryMediaType types.MediaType = "application/vnd.porter.package.binary.v1"
+
+	// AnnotationOS is the annotation on a binary layer with its operating system.
+	AnnotationOS = "sh.porter.package.os"
+
+	// AnnotationArch is the annotation on a binary layer with its architecture.
+	AnnotationArch = "sh.porter.package.arch"
+)
+
+// ConfigMediaType returns the media type of the config of a package artifact,
+// which identifies the type of package, for example a mixin.
+func ConfigMediaType(pkgType string) types.MediaType {
+	return types.MediaType(fmt.Sprintf("application/vnd.porter.%s.config.v1+json", pkgType))
+}
+
+// TrimSource returns the OCI reference from a package source, which may
+// start with oci://.
+func TrimSource(source string) string {
+	return strings.TrimPrefix(source, SourcePrefix)
+}
+
+// Package is a package, a mixin or plugin, that is published to a registry.
+type Package struct {
+	// Type of the package: mixin or plugin.
+	Type string
+
+	// Name of the package.
+	Name string
+
+	// Version of the package.
+	Version string
+
+	// Binaries of the package for each platform that it supports.
+	Binaries []Binary
+}
+
+// Binary is the binary of a package for a platform.
+type Binary struct {
+	// OS of the binary, for example linux.
+	OS string
+
+	// Arch of the binary, for example amd64.
+	Arch string
+
+	// Path to the binary, used when a package is published.
+	Path string
+
+	// Descriptor of the binary in the package artifact, set when a package is pulled.
+	Descriptor v1.Descriptor
+}
+
+// Push publishes the package to a registry as an OCI artifact, with a layer
+// for each binary. Returns the digest of the artifact.
+func Push(ctx context.Context, fs aferox.Aferox, pkg Package, reference string, craneOpts ...crane.Option) (digest.Digest, error) {
+	img := empty.Image
+	for _, bin := range pkg.Binaries {
+		data, err := fs.ReadFile(bin.Path)
+		if err != nil {
+			return "", fmt.Errorf("error reading the %s/%s binary of the %s %s: %w", bin.OS, bin.Arch, pkg.Name, pkg.Type, err)
+		}
+
+		img, err = mutate.Append(img, mutate.Addendum{
+			Layer: static.NewLayer(data, BinaryMediaType),
+			Annotations: map[string]string{
+				ocispec.AnnotationTitle: fmt.Sprintf("%s-%s-%s", pkg.Name, bin.OS, bin.Arch),
+				AnnotationOS:            bin.OS,
+				AnnotationArch:          bin.Arch,
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("error creating the %s %s artifact: %w", pkg.Name, pkg.Type, err)
+		}
+	}
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, ConfigMediaType(pkg.Type))
+	img = mutate.Annotations(img, map[string]string{
+		ocispec.AnnotationTitle:   pkg.Name,
+		ocispec.AnnotationVersion: pkg.Version,
+	}).(v1.Image)
+
+	opts := append([]crane.Option{crane.WithContext(ctx)}, craneOpts...)
+	if err := crane.Push(img, TrimSource(reference), opts...); err != nil {
+		return "", fmt.Errorf("error publishing the %s %s to %s: %w", pkg.Name, pkg.Type, reference, err)
+	}
+
+	d, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return digest.Digest(d.String()), nil
+}
+
+// Artifact is a package that was pulled from a registry.
+type Artifact struct {
+	Package
+
+	// Reference of the artifact.
+	Reference string
+
+	// Digest of the artifact manifest.
+	Digest digest.Digest
+
+	img v1.Image
+}
+
+// Pull the manifest of a package artifact from a registry, verifying that it
+// is the expected type of package. The binaries are not downloaded until
+// requested with Artifact.WriteBinary.
+func Pull(ctx context.Context, pkgType string, reference string, craneOpts ...crane.Option) (Artifact, error) {
+	reference = TrimSource(reference)
+	opts := append([]crane.Option{crane.WithContext(ctx)}, craneOpts...)
+	img, err := crane.Pull(reference, opts...)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("unable to pull the %s from %s: %w", pkgType, reference, err)
+	}
+
+	m, err := img.Manifest()
+	if err != nil {
+		return Artifact{}, fmt.Errorf("unable to read the manifest of %s: %w", reference, err)
+	}
+	if m.Config.MediaType != ConfigMediaType(pkgType) {
+		return Artifact{}, fmt.Errorf("%s is not a %s, its config has the media type %s instead of %s", reference, pkgType, m.Config.MediaType, ConfigMediaType(pkgType))
+	}
+
+	d, err := img.Digest()
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	a := Artifact{
+		Package: Package{
+			Type:    pkgType,
+			Name:    m.Annotations[ocispec.AnnotationTitle],
+			Version: m.Annotations[ocispec.AnnotationVersion],
+		},
+		Reference: reference,
+		Digest:    digest.Digest(d.String()),
+		img:       img,
+	}
+	for _, layer := range m.Layers {
+		if layer.MediaType != BinaryMediaType {
+			continue
+		}
+		a.Binaries = append(a.Binaries, Binary{
+			OS:         layer.Annotations[AnnotationOS],
+			Arch:       layer.Annotations[AnnotationArch],
+			Descriptor: layer,
+		})
+	}
+	return a, nil
+}
+
+// FindBinary returns the binary of the package for a platform.
+func (a Artifact) FindBinary(os string, arch string) (Binary, bool) {
+	for _, bin := range a.Binaries {
+		if bin.OS == os && bin.Arch == arch {
+			return bin, true
+		}
+	}
+
+	// Until we have full support for M1 chipsets, rely on rossetta functionality in macos and use the amd64 binary
+	if os == "darwin" && arch == "arm64" {
+		return a.FindBinary("darwin", "amd64")
+	}
+
+	return Binary{}, false
+}
+
+// WriteBinary downloads a binary of the package, and verifies that it matches
+// the digest in the artifact manifest.
+func (a Artifact) WriteBinary(bin Binary, w io.Writer) error {
+	layer, err := a.img.LayerByDigest(bin.Descriptor.Digest)
+	if err != nil {
+		return fmt.Errorf("unable to find the %s/%s binary in %s: %w", bin.OS, bin.Arch, a.Reference, err)
+	}
+
+	// The binary is stored as-is, so the "compressed" content is the binary
+	rc, err := layer.Compressed()
+	if err != nil {
+		return fmt.Errorf("unable to download the %s/%s binary from %s: %w", bin.OS, bin.Arch, a.Reference, err)
+	}
+	defer rc.Close()
+
+	wantDigest := digest.Digest(bin.Descriptor.Digest.String())
+	verifier := wantDigest.Verifier()
+	n, err := io.Copy(io.MultiWriter(w, verifier), rc)
+	if err != nil {
+		return fmt.Errorf("unable to download the %s/%s binary from %s: %w", bin.OS, bin.Arch, a.Reference, err)
+	}
+	if n != bin.Descriptor.Size || !verifier.Verified() {
+		return fmt.Errorf("the %s/%s binary downloaded from %s does not match its digest %s", bin.OS, bin.Arch, a.Reference, wantDigest)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carolynvs/aferox"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pushTestPackage(t *testing.T) (string, Package) {
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+
+	fs := aferox.NewAferox("/", afero.NewMemMapFs())
+	require.NoError(t, fs.WriteFile("/bin/mymixin-linux-amd64", []byte("linux binary"), 0755))
+	require.NoError(t, fs.WriteFile("/bin/mymixin-darwin-amd64", []byte("darwin binary"), 0755))
+
+	pkg := Package{
+		Type:    "mixin",
+		Name:    "mymixin",
+		Version: "v1.2.3",
+		Binaries: []Binary{
+			{OS: "linux", Arch: "amd64", Path: "/bin/mymixin-linux-amd64"},
+			{OS: "darwin", Arch: "amd64", Path: "/bin/mymixin-darwin-amd64"},
+		},
+	}
+	ref := SourcePrefix + strings.TrimPrefix(srv.URL, "http://") + "/mixins/mymixin:v1.2.3"
+	_, err := Push(context.Background(), fs, pkg, ref, crane.Insecure)
+	require.NoError(t, err)
+	return ref, pkg
+}
+
+func TestPushPull(t *testing.T) {
+	ctx := context.Background()
+	ref, pkg := pushTestPackage(t)
+
+	a, err := Pull(ctx, "mixin", ref, crane.Insecure)
+	require.NoError(t, err)
+	assert.Equal(t, pkg.Name, a.Name)
+	assert.Equal(t, pkg.Version, a.Version)
+	assert.NotEmpty(t, a.Digest)
+	require.Len(t, a.Binaries, 2)
+
+	bin, ok := a.FindBinary("linux", "amd64")
+	require.True(t, ok, "expected a linux/amd64 binary")
+	var buf bytes.Buffer
+	require.NoError(t, a.WriteBinary(bin, &buf))
+	assert.Equal(t, "linux binary", buf.String())
+
+	bin, ok = a.FindBinary("darwin", "arm64")
+	require.True(t, ok, "expected darwin/arm64 to fall back to darwin/amd64")
+	buf.Reset()
+	require.NoError(t, a.WriteBinary(bin, &buf))
+	assert.Equal(t, "darwin binary", buf.String())
+
+	_, ok = a.FindBinary("windows", "amd64")
+	assert.False(t, ok, "expected no windows/amd64 binary")
+}
+
+func TestPull_WrongPackageType(t *testing.T) {
+	ref, _ := pushTestPackage(t)
+
+	_, err := Pull(context.Background(), "plugin", ref, crane.Insecure)
+	require.ErrorContains(t, err, "is not a plugin")
+}
+
+func TestArtifact_WriteBinary_DigestMismatch(t *testing.T) {
+	ref, _ := pushTestPackage(t)
+
+	a, err := Pull(context.Background(), "mixin", ref, crane.Insecure)
+	require.NoError(t, err)
+	bin, ok := a.FindBinary("linux", "amd64")
+	require.True(t, ok, "expected a linux/amd64 binary")
+
+	bin.Descriptor.Size++
+	err = a.WriteBinary(bin, &bytes.Buffer{})
+	require.ErrorContains(t, err, "does not match its digest")
+}
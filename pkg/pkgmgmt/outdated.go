@@ -0,0 +1,98 @@
+package pkgmgmt
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// OutdatedOptions are the options for comparing the installed packages to the
+// latest versions published in their feeds.
+type OutdatedOptions struct {
+	PackageDownloadOptions
+
+	// FeedURL is the atom feed that is searched for the latest version of the
+	// packages. Defaults to the feed from which each package was installed, or
+	// the official Porter feed.
+	FeedURL       string
+	parsedFeedURL *url.URL
+
+	PackageType string
+}
+
+func (o *OutdatedOptions) Validate() error {
+	if o.PackageType != "mixin" && o.PackageType != "plugin" {
+		return fmt.Errorf("invalid package type %q. Please report this as a bug to Porter!", o.PackageType)
+	}
+
+	err := o.PackageDownloadOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	if o.FeedURL != "" {
+		o.parsedFeedURL, err = url.Parse(o.FeedURL)
+		if err != nil {
+			return fmt.Errorf("invalid --feed-url %s: %w", o.FeedURL, err)
+		}
+	}
+
+	return nil
+}
+
+// GetFeedURL returns the feed that is searched for the latest version of a
+// package, given the feed from which it was installed, which may be empty.
+func (o *OutdatedOptions) GetFeedURL(installedFeedURL string) (url.URL, error) {
+	if o.parsedFeedURL != nil {
+		return *o.parsedFeedURL, nil
+	}
+
+	if installedFeedURL != "" {
+		feedURL, err := url.Parse(installedFeedURL)
+		if err != nil {
+			return url.URL{}, fmt.Errorf("invalid feed url %s: %w", installedFeedURL, err)
+		}
+		return *feedURL, nil
+	}
+
+	mirror := o.GetMirror()
+	mirror.Path = path.Join(mirror.Path, o.PackageType+"s", "atom.xml")
+	return mirror, nil
+}
+
+// PackageVersions compares the installed version of a package to the latest
+// version published in its feed.
+type PackageVersions struct {
+	// Name of the package.
+	Name string `json:"name"`
+
+	// CurrentVersion is the version of the package that is installed.
+	CurrentVersion string `json:"currentVersion"`
+
+	// LatestVersion is the latest version of the package in its feed.
+	LatestVersion string `json:"latestVersion"`
+
+	// FeedURL is the atom feed that published the latest version.
+	FeedURL string `json:"feedURL"`
+}
+
+// IsOutdated determines if the latest version of the package is newer than
+// the installed version. Versions that are not semver, such as canary builds,
+// are outdated when they are not the latest version.
+func (v PackageVersions) IsOutdated() bool {
+	if v.LatestVersion == "" {
+		return false
+	}
+
+	current, err := semver.NewVersion(v.CurrentVersion)
+	if err != nil {
+		return v.CurrentVersion != v.LatestVersion
+	}
+	latest, err := semver.NewVersion(v.LatestVersion)
+	if err != nil {
+		return v.CurrentVersion != v.LatestVersion
+	}
+	return latest.GreaterThan(current)
+}
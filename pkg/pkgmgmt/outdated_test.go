@@ -0,0 +1,65 @@
+package pkgmgmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutdatedOptions_GetFeedURL(t *testing.T) {
+	t.Run("default feed", func(t *testing.T) {
+		opts := OutdatedOptions{PackageType: "mixin", PackageDownloadOptions: PackageDownloadOptions{Mirror: "https://example.com/mirror"}}
+		require.NoError(t, opts.Validate())
+
+		feedURL, err := opts.GetFeedURL("")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/mirror/mixins/atom.xml", feedURL.String())
+	})
+
+	t.Run("installed feed", func(t *testing.T) {
+		opts := OutdatedOptions{PackageType: "mixin"}
+		require.NoError(t, opts.Validate())
+
+		feedURL, err := opts.GetFeedURL("https://example.com/atom.xml")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/atom.xml", feedURL.String())
+	})
+
+	t.Run("feed url specified", func(t *testing.T) {
+		opts := OutdatedOptions{PackageType: "plugin", FeedURL: "https://example.com/plugins.xml"}
+		require.NoError(t, opts.Validate())
+
+		feedURL, err := opts.GetFeedURL("https://example.com/atom.xml")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/plugins.xml", feedURL.String())
+	})
+
+	t.Run("invalid package type", func(t *testing.T) {
+		opts := OutdatedOptions{PackageType: "oops"}
+		err := opts.Validate()
+		require.ErrorContains(t, err, `invalid package type "oops"`)
+	})
+}
+
+func TestPackageVersions_IsOutdated(t *testing.T) {
+	testcases := []struct {
+		current  string
+		latest   string
+		outdated bool
+	}{
+		{current: "v1.0.0", latest: "v1.1.0", outdated: true},
+		{current: "v1.1.0", latest: "v1.1.0", outdated: false},
+		{current: "v1.2.0-beta.1", latest: "v1.1.0", outdated: false},
+		{current: "v1.0", latest: "v1.0.1", outdated: true},
+		{current: "canary", latest: "v1.1.0", outdated: true},
+		{current: "v1.0.0", latest: "", outdated: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.current+"-"+tc.latest, func(t *testing.T) {
+			v := PackageVersions{Name: "mypkg", CurrentVersion: tc.current, LatestVersion: tc.latest}
+			assert.Equal(t, tc.outdated, v.IsOutdated())
+		})
+	}
+}
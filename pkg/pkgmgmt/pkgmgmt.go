@@ -14,9 +14,17 @@ type PackageManager interface {
 	List() ([]string, error)
 	GetPackageDir(name string) (string, error)
 	GetMetadata(ctx context.Context, name string) (PackageMetadata, error)
+
+	// GetInstallInfo returns where an installed package was installed from,
+	// and where it is installed.
+	GetInstallInfo(name string) (InstallInfo, error)
 	Install(ctx context.Context, opts InstallOptions) error
 	Uninstall(ctx context.Context, opts UninstallOptions) error
 
+	// GetLatestVersion compares the installed version of a package to the
+	// latest version that is published in its feed.
+	GetLatestVersion(ctx context.Context, name string, opts OutdatedOptions) (PackageVersions, error)
+
 	// Run a command against the installed package.
 	Run(ctx context.Context, pkgContext *portercontext.Context, name string, commandOpts CommandOptions) error
 }
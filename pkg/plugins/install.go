@@ -31,6 +31,10 @@ func (o *InstallOptions) Validate(args []string, cxt *portercontext.Context) err
 			return fmt.Errorf("plugin URL should not be specified when --file is provided")
 		}
 
+		if o.Source != "" {
+			return fmt.Errorf("plugin source should not be specified when --file is provided")
+		}
+
 		// version should not be set to anything other than the default value
 		if o.Version != "" && o.Version != "latest" {
 			return fmt.Errorf("plugin version %s should not be specified when --file is provided", o.Version)
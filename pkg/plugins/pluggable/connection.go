@@ -186,6 +186,25 @@ func (c *PluginConnection) Start(ctx context.Context, pluginCfg io.Reader) error
 	return nil
 }
 
+// Key returns the fully-qualified key of the plugin.
+func (c *PluginConnection) Key() plugins.PluginKey {
+	return c.key
+}
+
+// NegotiatedVersion returns the version of the plugin protocol agreed upon
+// with the plugin when the connection was started.
+func (c *PluginConnection) NegotiatedVersion() int {
+	if c.client == nil {
+		return 0
+	}
+	return c.client.NegotiatedVersion()
+}
+
+// IsRunning returns whether the plugin process was started and has not exited.
+func (c *PluginConnection) IsRunning() bool {
+	return c.client != nil && !c.client.Exited()
+}
+
 // GetClient returns the raw connection to the pluginProtocol.
 // This value should be cast to the plugin protocol interface,
 // such as plugins.StorageProtocol or plugins.SecretsProtocol.
@@ -206,11 +225,13 @@ func (c *PluginConnection) Close(ctx context.Context) error {
 		ctx, cancel := context.WithTimeout(ctx, getPluginStopTimeout())
 		defer cancel()
 
-		// Stop the plugin process
-		done := make(chan bool)
+		// Stop the plugin process. The client is captured because c.client is
+		// cleared below, while Kill may still be running.
+		client := c.client
+		done := make(chan bool, 1)
 		go func() {
 			// beware, this can block or deadlock
-			c.client.Kill(ctx)
+			client.Kill(ctx)
 			done <- true
 		}()
 		select {
@@ -220,7 +241,7 @@ func (c *PluginConnection) Close(ctx context.Context) error {
 		case <-ctx.Done():
 			// Stop being nice, cleanup the plugin process without any waiting or blocking
 			span.Debugf("killing the plugin process: %s", ctx.Err())
-			c.client.HardKill()
+			client.HardKill()
 		}
 
 		// Stop processing logs from the plugin and wait for the log collection routine to complete
@@ -55,16 +55,31 @@ func NewPluginLoader(c *config.Config) *PluginLoader {
 // the typed interface, a cleanup function to stop the plugin when finished communicating with it,
 // and an error if the plugin could not be loaded.
 func (l *PluginLoader) Load(ctx context.Context, pluginType PluginTypeConfig) (*PluginConnection, error) {
+	return l.LoadNamed(ctx, pluginType, pluginType.GetDefaultPluggable(l.config))
+}
+
+// LoadNamed loads the plugin defined with the specified name in the Porter
+// config file, for example the storage named "devdb", instead of the default.
+// When name is empty, the default plugin for the type of plugin is loaded.
+func (l *PluginLoader) LoadNamed(ctx context.Context, pluginType PluginTypeConfig, name string) (*PluginConnection, error) {
 	ctx, span := tracing.StartSpan(ctx,
 		attribute.String("plugin-interface", pluginType.Interface),
 		attribute.String("requested-protocol-version", fmt.Sprintf("%v", pluginType.ProtocolVersion)))
 	defer span.EndSpan()
 
-	err := l.selectPlugin(ctx, pluginType)
+	err := l.selectPlugin(ctx, pluginType, name)
 	if err != nil {
 		return nil, err
 	}
 
+	return l.startPlugin(ctx, pluginType)
+}
+
+// startPlugin starts the selected plugin.
+func (l *PluginLoader) startPlugin(ctx context.Context, pluginType PluginTypeConfig) (*PluginConnection, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
 	// quick check to detect that we are running as porter, and not a plugin already
 	if l.config.IsInternalPlugin {
 		err := fmt.Errorf("the internal plugin %s tried to load the %s plugin. Report this error to https://github.com/getporter/porter", l.config.InternalPluginKey, l.selectedPluginKey)
@@ -86,8 +101,10 @@ func (l *PluginLoader) Load(ctx context.Context, pluginType PluginTypeConfig) (*
 	return conn, nil
 }
 
-// selectPlugin picks the plugin to use and loads its configuration.
-func (l *PluginLoader) selectPlugin(ctx context.Context, cfg PluginTypeConfig) error {
+// selectPlugin picks the plugin to use and loads its configuration. The name
+// is the plugin defined in the Porter config file, when empty the default
+// plugin for the type of plugin is selected.
+func (l *PluginLoader) selectPlugin(ctx context.Context, cfg PluginTypeConfig, name string) error {
 	//lint:ignore SA4006 ignore unused ctx for now.
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
@@ -97,11 +114,10 @@ func (l *PluginLoader) selectPlugin(ctx context.Context, cfg PluginTypeConfig) e
 
 	var pluginKey string
 
-	defaultStore := cfg.GetDefaultPluggable(l.config)
-	if defaultStore != "" {
-		span.SetAttributes(attribute.String("default-plugin", defaultStore))
+	if name != "" {
+		span.SetAttributes(attribute.String("default-plugin", name))
 
-		is, err := cfg.GetPluggable(l.config, defaultStore)
+		is, err := cfg.GetPluggable(l.config, name)
 		if err != nil {
 			return span.Error(err)
 		}
@@ -30,7 +30,7 @@ func TestPluginLoader_SelectPlugin(t *testing.T) {
 	t.Run("internal plugin", func(t *testing.T) {
 		c.Data.DefaultStoragePlugin = "mongodb-docker"
 
-		err := l.selectPlugin(context.Background(), pluginCfg)
+		err := l.selectPlugin(context.Background(), pluginCfg, pluginCfg.GetDefaultPluggable(c.Config))
 		require.NoError(t, err, "error selecting plugin")
 
 		assert.Equal(t, &plugins.PluginKey{Binary: "porter", Implementation: "mongodb-docker", IsInternal: true}, l.selectedPluginKey)
@@ -40,7 +40,7 @@ func TestPluginLoader_SelectPlugin(t *testing.T) {
 	t.Run("external plugin", func(t *testing.T) {
 		c.Data.DefaultStoragePlugin = "azure.blob"
 
-		err := l.selectPlugin(context.Background(), pluginCfg)
+		err := l.selectPlugin(context.Background(), pluginCfg, pluginCfg.GetDefaultPluggable(c.Config))
 		require.NoError(t, err, "error selecting plugin")
 
 		assert.Equal(t, &plugins.PluginKey{Binary: "azure", Implementation: "blob", IsInternal: false}, l.selectedPluginKey)
@@ -61,12 +61,26 @@ func TestPluginLoader_SelectPlugin(t *testing.T) {
 			},
 		}
 
-		err := l.selectPlugin(context.Background(), pluginCfg)
+		err := l.selectPlugin(context.Background(), pluginCfg, pluginCfg.GetDefaultPluggable(c.Config))
 		require.NoError(t, err, "error selecting plugin")
 
 		assert.Equal(t, &plugins.PluginKey{Binary: "azure", Implementation: "blob", IsInternal: false}, l.selectedPluginKey)
 		assert.Equal(t, c.Data.StoragePlugins[0].Config, l.selectedPluginConfig)
 	})
+
+	t.Run("named plugin", func(t *testing.T) {
+		c.Data.DefaultStorage = "azure"
+		c.Data.StoragePlugins = []config.StoragePlugin{
+			{PluginConfig: config.PluginConfig{Name: "azure", PluginSubKey: "azure.blob"}},
+			{PluginConfig: config.PluginConfig{Name: "devdb", PluginSubKey: "mongodb", Config: map[string]interface{}{"url": "mongodb://localhost"}}},
+		}
+
+		err := l.selectPlugin(context.Background(), pluginCfg, "devdb")
+		require.NoError(t, err, "error selecting plugin")
+
+		assert.Equal(t, &plugins.PluginKey{Binary: "porter", Implementation: "mongodb", IsInternal: true}, l.selectedPluginKey)
+		assert.Equal(t, c.Data.StoragePlugins[1].Config, l.selectedPluginConfig)
+	})
 }
 
 func TestPluginLoader_IdentifyRecursiveLoad(t *testing.T) {
@@ -0,0 +1,114 @@
+package pluggable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PluginPool keeps plugins running for the life of a Porter command, so that
+// each plugin is started once no matter how many requests are made to it.
+// Requests from multiple callers, including concurrent ones, are multiplexed
+// over the same connection to the plugin.
+//
+// Connections returned by the pool are shared and must not be closed by the
+// caller. Call Close to stop the plugins when Porter is finished with them.
+type PluginPool struct {
+	// config is the Porter configuration
+	config *config.Config
+
+	// mu serializes starting and stopping plugins.
+	mu sync.Mutex
+
+	// conns are the running plugins, indexed by the plugin key and its configuration.
+	conns map[string]*PluginConnection
+}
+
+func NewPluginPool(c *config.Config) *PluginPool {
+	return &PluginPool{
+		config: c,
+		conns:  make(map[string]*PluginConnection),
+	}
+}
+
+// Get returns a connection to the default plugin for the type of plugin,
+// starting the plugin when it is not already running.
+func (p *PluginPool) Get(ctx context.Context, pluginType PluginTypeConfig) (*PluginConnection, error) {
+	return p.GetNamed(ctx, pluginType, pluginType.GetDefaultPluggable(p.config))
+}
+
+// GetNamed returns a connection to the plugin defined with the specified name
+// in the Porter config file, starting the plugin when it is not already
+// running. When name is empty, the default plugin for the type of plugin is used.
+func (p *PluginPool) GetNamed(ctx context.Context, pluginType PluginTypeConfig, name string) (*PluginConnection, error) {
+	ctx, span := tracing.StartSpan(ctx,
+		attribute.String("plugin-interface", pluginType.Interface))
+	defer span.EndSpan()
+
+	l := NewPluginLoader(p.config)
+	if err := l.selectPlugin(ctx, pluginType, name); err != nil {
+		return nil, err
+	}
+
+	poolKey, err := getPoolKey(l)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn, ok := p.conns[poolKey]; ok {
+		if conn.IsRunning() {
+			span.Debug("Reusing running plugin", attribute.String("plugin-key", conn.String()))
+			return conn, nil
+		}
+
+		// The plugin stopped unexpectedly, clean up and start it again
+		span.Debugf("Restarting the %s plugin because it is no longer running", conn)
+		conn.Close(ctx)
+		delete(p.conns, poolKey)
+	}
+
+	conn, err := l.startPlugin(ctx, pluginType)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[poolKey] = conn
+
+	return conn, nil
+}
+
+// getPoolKey identifies the plugin selected by the loader. The same plugin may
+// be defined more than once with different configuration, for example two
+// storage entries that use the mongodb plugin, so the configuration is part
+// of the key.
+func getPoolKey(l *PluginLoader) (string, error) {
+	pluginCfg, err := json.Marshal(l.selectedPluginConfig)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal plugin config for %s: %w", l.selectedPluginKey, err)
+	}
+	return l.selectedPluginKey.String() + "\x00" + string(pluginCfg), nil
+}
+
+// Close stops all the plugins started by the pool.
+func (p *PluginPool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var bigErr *multierror.Error
+	for poolKey, conn := range p.conns {
+		if err := conn.Close(ctx); err != nil {
+			bigErr = multierror.Append(bigErr, fmt.Errorf("error stopping the %s plugin: %w", conn, err))
+		}
+		delete(p.conns, poolKey)
+	}
+
+	return bigErr.ErrorOrNil()
+}
@@ -0,0 +1,69 @@
+package pluggable
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/tests"
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginPool_GetNamed(t *testing.T) {
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	c.Data.StoragePlugins = []config.StoragePlugin{
+		{PluginConfig: config.PluginConfig{Name: "devdb", PluginSubKey: "mongodb", Config: map[string]interface{}{"url": "mongodb://localhost:27017"}}},
+		{PluginConfig: config.PluginConfig{Name: "testdb", PluginSubKey: "mongodb", Config: map[string]interface{}{"url": "mongodb://localhost:27018"}}},
+	}
+
+	pluginCfg := PluginTypeConfig{
+		Interface: "storage",
+		GetDefaultPluggable: func(c *config.Config) string {
+			return c.Data.DefaultStorage
+		},
+		GetPluggable: func(c *config.Config, name string) (Entry, error) {
+			return c.GetStorage(name)
+		},
+		GetDefaultPlugin: func(c *config.Config) string {
+			return c.Data.DefaultStoragePlugin
+		},
+	}
+
+	selectedPoolKey := func(name string) string {
+		l := NewPluginLoader(c.Config)
+		require.NoError(t, l.selectPlugin(ctx, pluginCfg, name))
+		key, err := getPoolKey(l)
+		require.NoError(t, err)
+		return key
+	}
+
+	t.Run("reuses running plugin", func(t *testing.T) {
+		pool := NewPluginPool(c.Config)
+		running := &PluginConnection{client: &plugin.Client{}}
+		pool.conns[selectedPoolKey("devdb")] = running
+
+		conn, err := pool.GetNamed(ctx, pluginCfg, "devdb")
+		require.NoError(t, err)
+		assert.Same(t, running, conn, "the running plugin should be reused")
+	})
+
+	t.Run("plugin config is part of the key", func(t *testing.T) {
+		assert.NotEqual(t, selectedPoolKey("devdb"), selectedPoolKey("testdb"),
+			"plugins with different configuration should not share a connection")
+	})
+
+	t.Run("failed plugin is not pooled", func(t *testing.T) {
+		c.IsInternalPlugin = true
+		c.InternalPluginKey = "filesystem"
+		defer func() { c.IsInternalPlugin = false }()
+
+		pool := NewPluginPool(c.Config)
+		_, err := pool.GetNamed(ctx, pluginCfg, "devdb")
+		tests.RequireErrorContains(t, err, "tried to load the storage.porter.mongodb plugin")
+		assert.Empty(t, pool.conns)
+		require.NoError(t, pool.Close(ctx))
+	})
+}
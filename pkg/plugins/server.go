@@ -36,6 +36,8 @@ func ServeMany(c *portercontext.Context, pluginMap map[int]plugin.PluginSet) {
 					otelgrpc.UnaryServerInterceptor(),
 					makeLogUnaryHandler(c),
 					makePanicHandler()),
+				grpc.ChainStreamInterceptor(
+					otelgrpc.StreamServerInterceptor()),
 			)
 			return grpc.NewServer(opts...)
 		},
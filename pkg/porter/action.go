@@ -15,6 +15,10 @@ func (p *Porter) ExecuteAction(ctx context.Context, installation storage.Install
 		return err
 	}
 
+	if err = p.enforceSignatureVerification(ctx, deperator); err != nil {
+		return err
+	}
+
 	err = deperator.Execute(ctx)
 	if err != nil {
 		return err
@@ -25,5 +29,7 @@ func (p *Porter) ExecuteAction(ctx context.Context, installation storage.Install
 		return err
 	}
 
-	return p.CNAB.Execute(ctx, actionArgs)
+	err = p.executeWithHooks(ctx, action.GetOptions(), actionArgs)
+	p.enforceHistoryRetention(ctx, installation)
+	return err
 }
@@ -0,0 +1,258 @@
+package porter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// RemoteActionOptions are the options for sending a bundle action to an agent
+// started with porter agent serve, instead of executing the bundle locally.
+type RemoteActionOptions struct {
+	// Remote is the address of the agent, for example https://porter.example.com:8080.
+	// The https scheme is used when the address does not include a scheme.
+	Remote string
+
+	AgentActionRequest
+}
+
+// Validate that the action can be sent to the agent.
+func (o *RemoteActionOptions) Validate() error {
+	if _, err := parseRemoteAddress(o.Remote); err != nil {
+		return err
+	}
+	return o.AgentActionRequest.Validate()
+}
+
+// parseRemoteAddress returns the base url of the agent from the --remote flag.
+func parseRemoteAddress(remote string) (*url.URL, error) {
+	if !strings.Contains(remote, "://") {
+		remote = "https://" + remote
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --remote %s: %w", remote, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid --remote %s, the supported schemes are http and https", remote)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid --remote %s, the host is required", remote)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+// agentClient sends requests to an agent started with porter agent serve.
+type agentClient struct {
+	baseURL *url.URL
+	token   string
+	client  *http.Client
+}
+
+func (p *Porter) newAgentClient(remote string) (*agentClient, error) {
+	baseURL, err := parseRemoteAddress(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Data.AgentToken == "" {
+		return nil, errors.New("a token is required to connect to the agent, set agent-token in the Porter config file or the PORTER_AGENT_TOKEN environment variable")
+	}
+
+	return &agentClient{baseURL: baseURL, token: p.Data.AgentToken, client: http.DefaultClient}, nil
+}
+
+// do sends a request to the agent and returns the response when it succeeds.
+func (c *agentClient) do(ctx context.Context, method string, path string, query url.Values, body interface{}) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("error serializing the request to the agent: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the request to the agent: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the agent at %s: %w", c.baseURL, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("the agent at %s returned %s: %s", c.baseURL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// ExecuteRemoteAction sends a bundle action to the agent, and prints its output
+// as the action is executed.
+func (p *Porter) ExecuteRemoteAction(ctx context.Context, opts RemoteActionOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	client, err := p.newAgentClient(opts.Remote)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	resp, err := client.do(ctx, http.MethodPost, agentActionsPath, nil, opts.AgentActionRequest)
+	if err != nil {
+		return log.Error(err)
+	}
+	defer resp.Body.Close()
+
+	return log.Error(p.printAgentEvents(resp.Body))
+}
+
+// PrintRemoteInstallations prints the installations managed by the agent.
+func (p *Porter) PrintRemoteInstallations(ctx context.Context, remote string, opts ListOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	client, err := p.newAgentClient(remote)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	query := url.Values{}
+	if opts.Namespace != "" {
+		query.Set("namespace", opts.Namespace)
+	}
+	if opts.AllNamespaces {
+		query.Set("all-namespaces", "true")
+	}
+	if opts.Name != "" {
+		query.Set("name", opts.Name)
+	}
+	for _, label := range opts.Labels {
+		query.Add("label", label)
+	}
+	for _, annotation := range opts.Annotations {
+		query.Add("annotation", annotation)
+	}
+	if opts.Skip > 0 {
+		query.Set("skip", strconv.FormatInt(opts.Skip, 10))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(opts.Limit, 10))
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.FormatInt(opts.Page, 10))
+	}
+	if opts.Continue != "" {
+		query.Set("continue", opts.Continue)
+	}
+
+	resp, err := client.do(ctx, http.MethodGet, agentInstallationsPath, query, nil)
+	if err != nil {
+		return log.Error(err)
+	}
+	defer resp.Body.Close()
+
+	var installations DisplayInstallations
+	if err := json.NewDecoder(resp.Body).Decode(&installations); err != nil {
+		return log.Error(fmt.Errorf("could not parse the installations returned by the agent: %w", err))
+	}
+
+	return p.printDisplayInstallations(installations, opts)
+}
+
+// ShowRemoteInstallationLogs prints the logs of an installation that was executed by the agent.
+func (p *Porter) ShowRemoteInstallationLogs(ctx context.Context, remote string, opts *LogsShowOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	if opts.Name == "" && opts.RunID == "" {
+		return log.Error(errors.New("either --installation or --run is required when the logs are retrieved from the agent"))
+	}
+
+	client, err := p.newAgentClient(remote)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	query := url.Values{}
+	if opts.Namespace != "" {
+		query.Set("namespace", opts.Namespace)
+	}
+	if opts.Name != "" {
+		query.Set("installation", opts.Name)
+	}
+	if opts.RunID != "" {
+		query.Set("run", opts.RunID)
+	}
+	if opts.Follow {
+		query.Set("follow", "true")
+	}
+
+	resp, err := client.do(ctx, http.MethodGet, agentLogsPath, query, nil)
+	if err != nil {
+		return log.Error(err)
+	}
+	defer resp.Body.Close()
+
+	return log.Error(p.printAgentEvents(resp.Body))
+}
+
+// printAgentEvents prints the output streamed by the agent, and returns the
+// error reported by the agent when the command failed.
+func (p *Porter) printAgentEvents(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var event AgentEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return fmt.Errorf("could not parse the output of the agent: %w", err)
+		}
+
+		if event.Stream == "stderr" {
+			fmt.Fprint(p.Err, event.Output)
+		} else {
+			fmt.Fprint(p.Out, event.Output)
+		}
+
+		if event.Done {
+			if event.Error != "" {
+				return errors.New(event.Error)
+			}
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading the output of the agent: %w", err)
+	}
+
+	return errors.New("the connection to the agent was closed before the command completed")
+}
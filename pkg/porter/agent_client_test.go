@@ -0,0 +1,155 @@
+package porter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemoteAddress(t *testing.T) {
+	testcases := []struct {
+		remote  string
+		want    string
+		wantErr string
+	}{
+		{remote: "porter.example.com:8080", want: "https://porter.example.com:8080"},
+		{remote: "http://localhost:8080/", want: "http://localhost:8080"},
+		{remote: "https://example.com/porter/", want: "https://example.com/porter"},
+		{remote: "ftp://example.com", wantErr: "invalid --remote ftp://example.com, the supported schemes are http and https"},
+		{remote: "", wantErr: "invalid --remote https://, the host is required"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.remote, func(t *testing.T) {
+			u, err := parseRemoteAddress(tc.remote)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, u.String())
+			} else {
+				require.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPorter_ExecuteRemoteAction(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		agent, srv := newTestAgentServer(t)
+		agent.Setenv(test.ExpectedCommandEnv, "porter installations uninstall myapp --namespace=dev")
+		agent.Setenv(test.ExpectedCommandOutputEnv, "uninstalling myapp")
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.AgentToken = "secret"
+
+		opts := RemoteActionOptions{Remote: srv.URL}
+		opts.Action = "uninstall"
+		opts.Args = []string{"myapp", "--namespace=dev"}
+		require.NoError(t, opts.Validate())
+
+		err := p.ExecuteRemoteAction(context.Background(), opts)
+		require.NoError(t, err)
+		assert.Equal(t, "uninstalling myapp\n", p.TestConfig.TestContext.GetOutput())
+	})
+
+	t.Run("action failed", func(t *testing.T) {
+		agent, srv := newTestAgentServer(t)
+		agent.Setenv(test.ExpectedCommandExitCodeEnv, "2")
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.AgentToken = "secret"
+
+		opts := RemoteActionOptions{Remote: srv.URL}
+		opts.Action = "install"
+		err := p.ExecuteRemoteAction(context.Background(), opts)
+		require.EqualError(t, err, "porter installations install failed with exit code 2")
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, srv := newTestAgentServer(t)
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.AgentToken = "wrong"
+
+		opts := RemoteActionOptions{Remote: srv.URL}
+		opts.Action = "install"
+		err := p.ExecuteRemoteAction(context.Background(), opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "returned 401 Unauthorized: invalid token")
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := RemoteActionOptions{Remote: "porter.example.com"}
+		opts.Action = "install"
+		err := p.ExecuteRemoteAction(context.Background(), opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "a token is required to connect to the agent")
+	})
+}
+
+func TestPorter_PrintRemoteInstallations(t *testing.T) {
+	agent, srv := newTestAgentServer(t)
+	agent.Setenv(test.ExpectedCommandEnv, "porter installations list --output=json --all-namespaces=true")
+	agent.Setenv(test.ExpectedCommandOutputEnv, `[{"name":"myapp","namespace":"dev","_calculated":{"displayInstallationState":"installed"}}]`)
+
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.Data.AgentToken = "secret"
+
+	opts := ListOptions{AllNamespaces: true}
+	opts.Format = printer.FormatPlaintext
+	err := p.PrintRemoteInstallations(context.Background(), srv.URL, opts)
+	require.NoError(t, err)
+
+	output := p.TestConfig.TestContext.GetOutput()
+	assert.Contains(t, output, "NAMESPACE")
+	assert.Regexp(t, `dev\s+myapp\s+installed`, output)
+}
+
+func TestPorter_ShowRemoteInstallationLogs(t *testing.T) {
+	t.Run("run", func(t *testing.T) {
+		agent, srv := newTestAgentServer(t)
+		agent.Setenv(test.ExpectedCommandEnv, "porter installations logs show --run=01ABC")
+		agent.Setenv(test.ExpectedCommandOutputEnv, "hello world")
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.AgentToken = "secret"
+
+		err := p.ShowRemoteInstallationLogs(context.Background(), srv.URL, &LogsShowOptions{RunID: "01ABC"})
+		require.NoError(t, err)
+		assert.Equal(t, "hello world\n", p.TestConfig.TestContext.GetOutput())
+	})
+
+	t.Run("installation required", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		err := p.ShowRemoteInstallationLogs(context.Background(), "porter.example.com", &LogsShowOptions{})
+		require.EqualError(t, err, "either --installation or --run is required when the logs are retrieved from the agent")
+	})
+}
+
+func TestPorter_printAgentEvents(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	err := p.printAgentEvents(strings.NewReader(`{"stream":"stdout","output":"partial"}` + "\n"))
+	require.EqualError(t, err, "the connection to the agent was closed before the command completed")
+	assert.Equal(t, "partial", p.TestConfig.TestContext.GetOutput())
+
+	err = p.printAgentEvents(strings.NewReader("oops\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not parse the output of the agent")
+}
@@ -0,0 +1,332 @@
+package porter
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/metrics"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// agentActionsPath is the endpoint of the agent that executes bundle actions.
+	agentActionsPath = "/v1/actions"
+
+	// agentInstallationsPath is the endpoint of the agent that lists installations.
+	agentInstallationsPath = "/v1/installations"
+
+	// agentLogsPath is the endpoint of the agent that streams the logs of a run.
+	agentLogsPath = "/v1/logs"
+
+	// agentHealthPath is the endpoint used to check that the agent is running, it does not require a token.
+	agentHealthPath = "/healthz"
+)
+
+// remoteDisallowedFlags are the flags of a bundle action that refer to files,
+// which are on the machine of the client and not the agent.
+var remoteDisallowedFlags = []string{"file", "cnab-file", "relocation-mapping", "remote"}
+
+// AgentServeOptions are the options for running Porter as a remote execution agent.
+type AgentServeOptions struct {
+	// Listen is the address that the agent listens on, for example :8080.
+	Listen string
+
+	// TLSCertFile is the path to the certificate used to serve the agent over TLS.
+	TLSCertFile string
+
+	// TLSKeyFile is the path to the private key of the certificate.
+	TLSKeyFile string
+}
+
+func (o *AgentServeOptions) Validate() error {
+	if o.Listen == "" {
+		return errors.New("--listen is required")
+	}
+
+	if (o.TLSCertFile == "") != (o.TLSKeyFile == "") {
+		return errors.New("--tls-cert and --tls-key must be specified together")
+	}
+
+	return nil
+}
+
+// AgentActionRequest is sent to the agent to execute a bundle action.
+type AgentActionRequest struct {
+	// Action is the bundle action to execute: install, upgrade, uninstall or invoke.
+	Action string `json:"action"`
+
+	// Args are the arguments and flags of the porter command for the action,
+	// for example [myapp --reference=ghcr.io/getporter/examples/porter-hello:v0.2.0].
+	// Flags must be specified in the form --NAME=VALUE.
+	Args []string `json:"args,omitempty"`
+}
+
+// Validate that the agent can execute the requested action.
+func (r AgentActionRequest) Validate() error {
+	switch r.Action {
+	case cnab.ActionInstall, cnab.ActionUpgrade, cnab.ActionUninstall, "invoke":
+	default:
+		return fmt.Errorf("unsupported action %q, the agent can execute the install, upgrade, uninstall and invoke actions", r.Action)
+	}
+
+	return validateRemoteArgs(r.Args)
+}
+
+// validateRemoteArgs checks that the arguments of an action can be executed
+// by the agent. Flags must use their long name, so that the flags that refer
+// to files on the client can be reliably detected.
+func validateRemoteArgs(args []string) error {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !strings.HasPrefix(arg, "--") || name == "" {
+			return fmt.Errorf("invalid argument %q, flags must be specified in the form --NAME=VALUE", arg)
+		}
+
+		for _, disallowed := range remoteDisallowedFlags {
+			if name == disallowed {
+				return fmt.Errorf("--%s is not supported when the action is executed remotely", name)
+			}
+		}
+	}
+	return nil
+}
+
+// AgentEvent is a line of the newline delimited JSON stream returned by the agent
+// while it executes a command.
+type AgentEvent struct {
+	// Stream that the output was written to: stdout or stderr.
+	Stream string `json:"stream,omitempty"`
+
+	// Output of the command.
+	Output string `json:"output,omitempty"`
+
+	// Error is set when the command failed.
+	Error string `json:"error,omitempty"`
+
+	// Done is set on the last event of the stream.
+	Done bool `json:"done,omitempty"`
+}
+
+// ServeAgent runs Porter as a long-running agent that executes bundle actions,
+// lists installations and streams logs on behalf of remote clients, until the
+// context is cancelled. Each request is executed by the porter CLI on the
+// agent, using its configuration, credentials and runtime driver.
+func (p *Porter) ServeAgent(ctx context.Context, opts AgentServeOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	if p.Data.AgentToken == "" {
+		return log.Error(errors.New("the agent requires a token, set agent-token in the Porter config file or the PORTER_AGENT_TOKEN environment variable"))
+	}
+
+	porterPath, err := p.GetPorterPath(ctx)
+	if err != nil {
+		return log.Error(fmt.Errorf("could not determine the path to porter: %w", err))
+	}
+
+	srv := &http.Server{
+		Addr:              opts.Listen,
+		Handler:           p.newAgentHandler(ctx, porterPath),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if opts.TLSCertFile == "" {
+		log.Warn("TLS is not configured, the token is sent unencrypted. Use --tls-cert and --tls-key unless the agent is behind a proxy that terminates TLS.")
+	}
+	fmt.Fprintf(p.Err, "Porter agent listening on %s\n", opts.Listen)
+
+	if opts.TLSCertFile != "" {
+		err = srv.ListenAndServeTLS(opts.TLSCertFile, opts.TLSKeyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return log.Error(fmt.Errorf("error running the agent: %w", err))
+	}
+	return nil
+}
+
+// newAgentHandler returns the http handler for the agent API. Actions are
+// executed with the context of the agent, and not the request, so that an
+// action is not interrupted when the client disconnects.
+func (p *Porter) newAgentHandler(ctx context.Context, porterPath string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(agentHealthPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+
+	// Metrics do not include sensitive data, so like the health check they do not require a token
+	mux.Handle(metrics.Path, metrics.Handler())
+
+	mux.HandleFunc(agentActionsPath, p.authorizeAgentRequest(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+		var req AgentActionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		args := append([]string{"installations", req.Action}, req.Args...)
+		start := time.Now()
+		err := p.streamAgentCommand(ctx, w, porterPath, args)
+		metrics.ObserveAction(req.Action, time.Since(start), err != nil)
+	}))
+
+	mux.HandleFunc(agentInstallationsPath, p.authorizeAgentRequest(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		args := []string{"installations", "list", "--output=json"}
+		args = appendQueryFlags(args, query, "namespace", "all-namespaces", "name", "label", "annotation", "skip", "limit", "page", "continue")
+
+		var stdout, stderr strings.Builder
+		cmd := p.NewCommand(r.Context(), porterPath, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			http.Error(w, fmt.Sprintf("could not list installations: %s", strings.TrimSpace(stderr.String())), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, stdout.String())
+	}))
+
+	mux.HandleFunc(agentLogsPath, p.authorizeAgentRequest(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		args := []string{"installations", "logs", "show"}
+		args = appendQueryFlags(args, query, "namespace", "installation", "run", "follow")
+
+		// Stop following the logs when the client disconnects
+		p.streamAgentCommand(r.Context(), w, porterPath, args)
+	}))
+
+	return mux
+}
+
+// authorizeAgentRequest only calls the handler for requests with the expected
+// method that present the token of the agent.
+func (p *Porter) authorizeAgentRequest(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(p.Data.AgentToken)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, fmt.Sprintf("method %s is not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// appendQueryFlags converts the query parameters of a request to flags of a
+// porter command, in the order of the names.
+func appendQueryFlags(args []string, query map[string][]string, names ...string) []string {
+	for _, name := range names {
+		for _, value := range query[name] {
+			args = append(args, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	return args
+}
+
+// streamAgentCommand runs porter with the specified arguments, and streams
+// its output to the client as newline delimited JSON events. The error
+// returned by the command is reported to the client, and returned.
+func (p *Porter) streamAgentCommand(ctx context.Context, w http.ResponseWriter, porterPath string, args []string) error {
+	// Run the command in an empty directory, so that a bundle in the working
+	// directory of the agent is never used in place of the requested bundle
+	dir, err := p.FileSystem.TempDir("", "porter-agent")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error creating a working directory for the command: %s", err), http.StatusInternalServerError)
+		return err
+	}
+	defer p.FileSystem.RemoveAll(dir)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	events := &agentEventWriter{w: w}
+	if flusher, ok := w.(http.Flusher); ok {
+		events.flusher = flusher
+	}
+
+	cmd := p.NewCommand(ctx, porterPath, args...)
+	cmd.Dir = dir
+	cmd.Stdout = events.stream("stdout")
+	cmd.Stderr = events.stream("stderr")
+	err = cmd.Run()
+
+	done := AgentEvent{Done: true}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			done.Error = fmt.Sprintf("porter %s failed with exit code %d", strings.Join(args[:2], " "), exitErr.ExitCode())
+		} else {
+			done.Error = fmt.Sprintf("could not run porter: %s", err)
+		}
+	}
+	events.write(done)
+	return err
+}
+
+// agentEventWriter writes the output of a command as events, serializing
+// writes from its stdout and stderr.
+type agentEventWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	flusher http.Flusher
+}
+
+func (e *agentEventWriter) write(event AgentEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// The command keeps running when the client disconnects, so errors writing to the client are ignored
+	json.NewEncoder(e.w).Encode(event)
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+}
+
+// stream returns a writer that converts writes to events for the named stream.
+func (e *agentEventWriter) stream(name string) io.Writer {
+	return agentStreamWriter{events: e, stream: name}
+}
+
+type agentStreamWriter struct {
+	events *agentEventWriter
+	stream string
+}
+
+func (s agentStreamWriter) Write(p []byte) (int, error) {
+	s.events.write(AgentEvent{Stream: s.stream, Output: string(p)})
+	return len(p), nil
+}
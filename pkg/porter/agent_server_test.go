@@ -0,0 +1,188 @@
+package porter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/metrics"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentServeOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		opts    AgentServeOptions
+		wantErr string
+	}{
+		{name: "defaults", opts: AgentServeOptions{Listen: ":8080"}},
+		{name: "tls", opts: AgentServeOptions{Listen: ":8443", TLSCertFile: "agent.crt", TLSKeyFile: "agent.key"}},
+		{name: "missing listen", opts: AgentServeOptions{}, wantErr: "--listen is required"},
+		{name: "missing key", opts: AgentServeOptions{Listen: ":8443", TLSCertFile: "agent.crt"}, wantErr: "--tls-cert and --tls-key must be specified together"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAgentActionRequest_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		req     AgentActionRequest
+		wantErr string
+	}{
+		{name: "install", req: AgentActionRequest{Action: "install", Args: []string{"myapp", "--reference=example.com/myapp:v1.0.0", "--param=name=a=b"}}},
+		{name: "invoke", req: AgentActionRequest{Action: "invoke", Args: []string{"myapp", "--action=logs"}}},
+		{name: "unsupported action", req: AgentActionRequest{Action: "delete"}, wantErr: `unsupported action "delete", the agent can execute the install, upgrade, uninstall and invoke actions`},
+		{name: "shorthand flag", req: AgentActionRequest{Action: "install", Args: []string{"-f", "porter.yaml"}}, wantErr: `invalid argument "-f", flags must be specified in the form --NAME=VALUE`},
+		{name: "empty flag", req: AgentActionRequest{Action: "install", Args: []string{"--", "--file=porter.yaml"}}, wantErr: `invalid argument "--", flags must be specified in the form --NAME=VALUE`},
+		{name: "local file", req: AgentActionRequest{Action: "upgrade", Args: []string{"--file=porter.yaml"}}, wantErr: "--file is not supported when the action is executed remotely"},
+		{name: "nested remote", req: AgentActionRequest{Action: "upgrade", Args: []string{"--remote=example.com"}}, wantErr: "--remote is not supported when the action is executed remotely"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.req.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func newTestAgentServer(t *testing.T) (*TestPorter, *httptest.Server) {
+	p := NewTestPorter(t)
+	t.Cleanup(func() { p.Close() })
+	// Commands are run in a temporary directory, which must exist on the host
+	p.TestConfig.TestContext.UseFilesystem()
+	p.NewCommand = p.TestConfig.TestContext.NewTestCommand
+	p.Data.AgentToken = "secret"
+
+	srv := httptest.NewServer(p.newAgentHandler(context.Background(), "porter"))
+	t.Cleanup(srv.Close)
+	return p, srv
+}
+
+func sendTestAgentRequest(t *testing.T, method string, url string, token string, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(respBody)
+}
+
+func TestAgentHandler_Authorization(t *testing.T) {
+	_, srv := newTestAgentServer(t)
+
+	resp, body := sendTestAgentRequest(t, http.MethodGet, srv.URL+agentHealthPath, "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "the health check should not require a token")
+	assert.Equal(t, "ok\n", body)
+
+	resp, body = sendTestAgentRequest(t, http.MethodGet, srv.URL+agentInstallationsPath, "", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "invalid token\n", body)
+
+	resp, _ = sendTestAgentRequest(t, http.MethodPost, srv.URL+agentActionsPath, "wrong", `{"action":"install"}`)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = sendTestAgentRequest(t, http.MethodGet, srv.URL+agentActionsPath, "secret", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestAgentHandler_Actions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		p, srv := newTestAgentServer(t)
+		p.Setenv(test.ExpectedCommandEnv, "porter installations install myapp --reference=example.com/myapp:v1.0.0")
+		p.Setenv(test.ExpectedCommandOutputEnv, "installing myapp")
+
+		resp, body := sendTestAgentRequest(t, http.MethodPost, srv.URL+agentActionsPath, "secret",
+			`{"action":"install","args":["myapp","--reference=example.com/myapp:v1.0.0"]}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+		assert.Equal(t, `{"stream":"stdout","output":"installing myapp\n"}`+"\n"+`{"done":true}`+"\n", body)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		p, srv := newTestAgentServer(t)
+		p.Setenv(test.ExpectedCommandErrorEnv, "the installation is locked")
+		p.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+
+		resp, body := sendTestAgentRequest(t, http.MethodPost, srv.URL+agentActionsPath, "secret", `{"action":"upgrade","args":["myapp"]}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Equal(t, `{"stream":"stderr","output":"the installation is locked\n"}`+"\n"+
+			`{"error":"porter installations upgrade failed with exit code 1","done":true}`+"\n", body)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, srv := newTestAgentServer(t)
+
+		resp, body := sendTestAgentRequest(t, http.MethodPost, srv.URL+agentActionsPath, "secret", `{"action":"install","args":["--file=porter.yaml"]}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "--file is not supported when the action is executed remotely\n", body)
+
+		resp, body = sendTestAgentRequest(t, http.MethodPost, srv.URL+agentActionsPath, "secret", `not json`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, body, "invalid request")
+	})
+}
+
+func TestAgentHandler_Installations(t *testing.T) {
+	p, srv := newTestAgentServer(t)
+	p.Setenv(test.ExpectedCommandEnv, "porter installations list --output=json --namespace=dev --label=app=web --label=env=test")
+	p.Setenv(test.ExpectedCommandOutputEnv, `[{"name":"myapp","namespace":"dev"}]`)
+
+	resp, body := sendTestAgentRequest(t, http.MethodGet, srv.URL+agentInstallationsPath+"?namespace=dev&label=app%3Dweb&label=env%3Dtest", "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `[{"name":"myapp","namespace":"dev"}]`+"\n", body)
+}
+
+func TestAgentHandler_Logs(t *testing.T) {
+	p, srv := newTestAgentServer(t)
+	p.Setenv(test.ExpectedCommandEnv, "porter installations logs show --namespace=dev --installation=myapp --follow=true")
+	p.Setenv(test.ExpectedCommandOutputEnv, "hello world")
+
+	resp, body := sendTestAgentRequest(t, http.MethodGet, srv.URL+agentLogsPath+"?installation=myapp&namespace=dev&follow=true", "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, `{"stream":"stdout","output":"hello world\n"}`+"\n"+`{"done":true}`+"\n", body)
+}
+
+func TestAgentHandler_Metrics(t *testing.T) {
+	p, srv := newTestAgentServer(t)
+	p.Setenv(test.ExpectedCommandErrorEnv, "the installation is locked")
+	p.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+
+	resp, body := sendTestAgentRequest(t, http.MethodPost, srv.URL+agentActionsPath, "secret", `{"action":"uninstall","args":["myapp"]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	resp, body = sendTestAgentRequest(t, http.MethodGet, srv.URL+metrics.Path, "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, "the metrics should not require a token")
+	assert.Contains(t, body, `porter_actions_total{action="uninstall"}`)
+	assert.Contains(t, body, `porter_actions_failed_total{action="uninstall"}`)
+	assert.Contains(t, body, `porter_action_duration_seconds_count{action="uninstall"}`)
+}
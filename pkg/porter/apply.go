@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"get.porter.sh/porter/pkg/encoding"
+	"get.porter.sh/porter/pkg/metrics"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
 	"get.porter.sh/porter/pkg/storage"
@@ -23,6 +25,17 @@ type ApplyOptions struct {
 
 	// DryRun only checks if the changes would trigger a bundle run
 	DryRun bool
+
+	// Watch keeps reconciling the file with the installation until the command
+	// is stopped, instead of applying the file once.
+	Watch bool
+
+	// Interval is how often the file is reconciled when Watch is set.
+	Interval time.Duration
+
+	// MetricsListen is the address where Prometheus metrics are served when
+	// Watch is set. Metrics are not served when it is empty.
+	MetricsListen string
 }
 
 const ApplyDefaultFormat = printer.FormatPlaintext
@@ -47,18 +60,112 @@ func (o *ApplyOptions) Validate(cxt *portercontext.Context, args []string) error
 		return fmt.Errorf("invalid file argument %s, must be a file not a directory", o.File)
 	}
 
+	if o.Watch {
+		if o.Interval <= 0 {
+			return errors.New("--interval must be greater than 0")
+		}
+		if o.Force {
+			return errors.New("--force cannot be used with --watch, because the bundle would be executed at every interval")
+		}
+	} else if o.MetricsListen != "" {
+		return errors.New("--metrics-listen can only be used with --watch")
+	}
+
 	return nil
 }
 
 func (p *Porter) InstallationApply(ctx context.Context, opts ApplyOptions) error {
+	if opts.Watch {
+		return p.WatchInstallationApply(ctx, opts)
+	}
+
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
 
+	installation, exists, err := p.loadInstallationFromFile(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		fmt.Fprintf(p.Err, "Updating %s installation\n", installation)
+	} else {
+		log.Info("Creating a new installation", attribute.String("installation", installation.String()))
+	}
+
+	reconcileOpts := ReconcileOptions{
+		Namespace:    installation.Namespace,
+		Name:         installation.Name,
+		Installation: installation,
+		Force:        opts.Force,
+		DryRun:       opts.DryRun,
+	}
+	return p.ReconcileInstallation(ctx, reconcileOpts)
+}
+
+// WatchInstallationApply reconciles the installation file with the installation
+// at every interval, until the context is cancelled. The file is read again and
+// the bundle is pulled again each time, so that the installation is upgraded
+// when the file changes, or when the tag of the bundle is moved to a new digest.
+// Failures are logged and retried at the next interval.
+func (p *Porter) WatchInstallationApply(ctx context.Context, opts ApplyOptions) error {
+	log := tracing.LoggerFromContext(ctx)
+	if opts.MetricsListen != "" {
+		if err := metrics.Serve(ctx, opts.MetricsListen); err != nil {
+			return log.Error(err)
+		}
+		log.Infof("Serving metrics on %s%s", opts.MetricsListen, metrics.Path)
+	}
+
+	log.Infof("Reconciling %s every %s", opts.File, opts.Interval)
+
+	for {
+		err := p.reconcileInstallationFile(ctx, opts)
+		metrics.ObserveReconciliation(err != nil)
+		if err != nil {
+			// Keep watching so that a fix to the file, or a transient failure, is picked up at the next interval
+			log.Warnf("%s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// reconcileInstallationFile applies the installation file once for WatchInstallationApply.
+func (p *Porter) reconcileInstallationFile(ctx context.Context, opts ApplyOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	installation, _, err := p.loadInstallationFromFile(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	reconcileOpts := ReconcileOptions{
+		Namespace:     installation.Namespace,
+		Name:          installation.Name,
+		Installation:  installation,
+		DryRun:        opts.DryRun,
+		RefreshBundle: true,
+	}
+	return p.ReconcileInstallation(ctx, reconcileOpts)
+}
+
+// loadInstallationFromFile reads an installation file and applies it to the
+// stored installation, returning the resulting installation and if it already
+// exists. The installation is not saved.
+func (p *Porter) loadInstallationFromFile(ctx context.Context, opts ApplyOptions) (storage.Installation, bool, error) {
+	log := tracing.LoggerFromContext(ctx)
+
 	log.Debugf("Reading input file %s", opts.File)
 
 	namespace, err := p.getNamespaceFromFile(opts)
 	if err != nil {
-		return err
+		return storage.Installation{}, false, err
 	}
 
 	if log.ShouldLog(zapcore.DebugLevel) {
@@ -69,41 +176,30 @@ func (p *Porter) InstallationApply(ctx context.Context, opts ApplyOptions) error
 
 	var input DisplayInstallation
 	if err := encoding.UnmarshalFile(p.FileSystem, opts.File, &input); err != nil {
-		return fmt.Errorf("unable to parse %s as an installation document: %w", opts.File, err)
+		return storage.Installation{}, false, fmt.Errorf("unable to parse %s as an installation document: %w", opts.File, err)
 	}
 	input.Namespace = namespace
 	inputInstallation, err := input.ConvertToInstallation()
 	if err != nil {
-		return err
+		return storage.Installation{}, false, err
 	}
 
 	installation, err := p.Installations.GetInstallation(ctx, inputInstallation.Namespace, inputInstallation.Name)
 	if err != nil {
 		if !errors.Is(err, storage.ErrNotFound{}) {
-			return fmt.Errorf("could not query for an existing installation document for %s: %w", inputInstallation, err)
+			return storage.Installation{}, false, fmt.Errorf("could not query for an existing installation document for %s: %w", inputInstallation, err)
 		}
 
 		// Create a new installation
 		installation = storage.NewInstallation(input.Namespace, input.Name)
 		installation.Apply(inputInstallation.InstallationSpec)
-
-		log.Info("Creating a new installation", attribute.String("installation", installation.String()))
-	} else {
-		// Apply the specified changes to the installation
-		installation.Apply(inputInstallation.InstallationSpec)
-		if err := installation.Validate(); err != nil {
-			return err
-		}
-
-		fmt.Fprintf(p.Err, "Updating %s installation\n", installation)
+		return installation, false, nil
 	}
 
-	reconcileOpts := ReconcileOptions{
-		Namespace:    input.Namespace,
-		Name:         input.Name,
-		Installation: installation,
-		Force:        opts.Force,
-		DryRun:       opts.DryRun,
+	// Apply the specified changes to the installation
+	installation.Apply(inputInstallation.InstallationSpec)
+	if err := installation.Validate(); err != nil {
+		return storage.Installation{}, false, err
 	}
-	return p.ReconcileInstallation(ctx, reconcileOpts)
+	return installation, true, nil
 }
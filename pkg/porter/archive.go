@@ -2,14 +2,15 @@ package porter
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -21,17 +22,55 @@ import (
 	"get.porter.sh/porter/pkg/tracing"
 	"github.com/carolynvs/aferox"
 	"github.com/cnabio/cnab-go/bundle"
-	"github.com/cnabio/cnab-go/imagestore"
-	"github.com/cnabio/cnab-go/imagestore/construction"
 	"github.com/cnabio/cnab-to-oci/relocation"
-	"github.com/spf13/afero"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
 	"go.opentelemetry.io/otel/attribute"
 )
 
+const (
+	// ArchiveCompressionGzip compresses the archive with gzip.
+	ArchiveCompressionGzip = "gzip"
+
+	// ArchiveCompressionZstd compresses the archive with zstd.
+	ArchiveCompressionZstd = "zstd"
+
+	// ArchiveCompressionNone writes an uncompressed tar archive.
+	ArchiveCompressionNone = "none"
+)
+
+// archiveOmittedImagesFile is the name of the file in the archive that lists the referenced images
+// that were not included in the archive, keyed by the image name defined in the bundle.
+const archiveOmittedImagesFile = "omitted-images.json"
+
+// archiveChecksumsFile is the name of the file in the archive that records the checksum of every other file in the archive.
+const archiveChecksumsFile = "checksums.json"
+
+// ArchiveCompressions is the list of supported compression algorithms for a bundle archive.
+var ArchiveCompressions = []string{ArchiveCompressionGzip, ArchiveCompressionZstd, ArchiveCompressionNone}
+
 // ArchiveOptions defines the valid options for performing an archive operation
 type ArchiveOptions struct {
 	BundleReferenceOptions
 	ArchiveFile string
+
+	// Compression is the algorithm used to compress the archive. Defaults to gzip.
+	Compression string
+
+	// ExcludeReferencedImages omits all the images referenced by the bundle from the archive,
+	// producing a thin archive with only the bundle and its invocation image.
+	ExcludeReferencedImages bool
+
+	// Verify the integrity of the archive after it is created.
+	Verify bool
+
+	// ExcludeImages is a list of glob patterns of referenced images to omit from the archive.
+	// A pattern is matched against both the image name defined in the bundle and its image reference.
+	ExcludeImages []string
+
+	// Parallelism is the maximum number of images and layers downloaded at the same time.
+	Parallelism int
 }
 
 // Validate performs validation on the publish options
@@ -47,12 +86,68 @@ func (o *ArchiveOptions) Validate(ctx context.Context, args []string, p *Porter)
 	if o.Reference == "" {
 		return errors.New("must provide a value for --reference of the form REGISTRY/bundle:tag")
 	}
+
+	if o.Compression == "" {
+		o.Compression = ArchiveCompressionGzip
+	}
+	if err := validateArchiveCompression(o.Compression); err != nil {
+		return err
+	}
+
+	for _, pattern := range o.ExcludeImages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --exclude-image pattern %s: %w", pattern, err)
+		}
+	}
+
+	if err := validateParallelism(o.Parallelism); err != nil {
+		return err
+	}
+
 	return o.BundleReferenceOptions.Validate(ctx, args, p)
 }
 
-// Archive is a composite function that generates a CNAB thick bundle. It will pull the invocation image, and
-// any referenced images locally (if needed), export them to individual layers, generate a bundle.json and
-// then generate a gzipped tar archive containing the bundle.json and the images
+func validateArchiveCompression(compression string) error {
+	for _, allowed := range ArchiveCompressions {
+		if compression == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --compression %s, allowed values are: %s", compression, strings.Join(ArchiveCompressions, ", "))
+}
+
+// getOmittedImages returns the referenced images that should not be included in the archive,
+// keyed by the image name defined in the bundle.
+func (o *ArchiveOptions) getOmittedImages(bun cnab.ExtendedBundle) map[string]string {
+	omitted := make(map[string]string)
+	for name, img := range bun.Images {
+		if o.isImageExcluded(name, img.Image) {
+			omitted[name] = img.Image
+		}
+	}
+	return omitted
+}
+
+func (o *ArchiveOptions) isImageExcluded(name string, img string) bool {
+	if o.ExcludeReferencedImages {
+		return true
+	}
+
+	for _, pattern := range o.ExcludeImages {
+		// The patterns were validated already, so we can ignore the error
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, img); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Archive is a composite function that generates a CNAB thick bundle. It will copy the invocation image, and
+// any referenced images, from the registry into a compressed tar archive containing the bundle.json and the images.
+// Image layers are downloaded in parallel into the content store in PORTER_HOME/content before they are written to the archive.
 func (p *Porter) Archive(ctx context.Context, opts ArchiveOptions) error {
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
@@ -67,229 +162,253 @@ func (p *Porter) Archive(ctx context.Context, opts ArchiveOptions) error {
 		return log.Error(err)
 	}
 
-	// This allows you to export thin or thick bundles, we only support generating "thick" archives
-	ctor, err := construction.NewConstructor(false)
+	dest, err := p.Config.FileSystem.OpenFile(opts.ArchiveFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, pkg.FileModeWritable)
 	if err != nil {
 		return log.Error(err)
 	}
+	defer dest.Close()
 
-	dest, err := p.Config.FileSystem.OpenFile(opts.ArchiveFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, pkg.FileModeWritable)
-	if err != nil {
-		return log.Error(err)
+	craneOpts := []crane.Option{crane.WithContext(ctx)}
+	regOpts := cnabtooci.RegistryOptions{
+		InsecureRegistry: opts.InsecureRegistry,
+		Mirrors:          p.Data.RegistryMirrors,
+		RegistryTLS:      p.Data.RegistryTLS,
+		Parallelism:      opts.Parallelism,
+	}
+
+	omittedImages := opts.getOmittedImages(bundleRef.Definition)
+	for _, name := range sortedKeys(omittedImages) {
+		log.Infof("Omitting image %s (%s) from the archive", name, omittedImages[name])
 	}
 
+	// The size of the archive is not known until the images are streamed into it
+	progress := p.NewUI().NewProgressBar(fmt.Sprintf("Writing %s", opts.ArchiveFile), 0)
 	exp := &exporter{
-		fs:                    p.Config.FileSystem,
-		out:                   p.Config.Out,
-		logs:                  p.Config.Out,
-		bundle:                bundleRef.Definition,
-		relocationMap:         bundleRef.RelocationMap,
-		destination:           dest,
-		imageStoreConstructor: ctor,
-		insecureRegistry:      opts.InsecureRegistry,
-	}
-	if err := exp.export(ctx); err != nil {
+		bundle:        bundleRef.Definition,
+		relocationMap: bundleRef.RelocationMap,
+		omittedImages: omittedImages,
+		destination:   io.MultiWriter(dest, progress),
+		compression:   opts.Compression,
+		craneOpts:     craneOpts,
+		fs:            p.FileSystem,
+		regOpts:       regOpts,
+		contentStore:  p.ContentStore,
+	}
+	err = exp.export(ctx)
+	progress.Finish()
+	if err != nil {
+		// Do not leave a partially written archive behind
+		dest.Close()
+		p.Config.FileSystem.Remove(opts.ArchiveFile)
 		return log.Error(err)
 	}
 
+	if opts.Verify {
+		return p.VerifyArchive(ctx, ArchiveVerifyOptions{ArchiveFile: opts.ArchiveFile})
+	}
 	return nil
 }
 
 type exporter struct {
-	fs                    aferox.Aferox
-	out                   io.Writer
-	logs                  io.Writer
-	bundle                cnab.ExtendedBundle
-	relocationMap         relocation.ImageRelocationMap
-	destination           io.Writer
-	imageStoreConstructor imagestore.Constructor
-	imageStore            imagestore.Store
-	insecureRegistry      bool
+	bundle        cnab.ExtendedBundle
+	relocationMap relocation.ImageRelocationMap
+	omittedImages map[string]string
+	destination   io.Writer
+	compression   string
+	imageStore    archiveImageStore
+	craneOpts     []crane.Option
+	fs            aferox.Aferox
+	regOpts       cnabtooci.RegistryOptions
+	contentStore  *cnabtooci.ContentStore
+}
+
+// archiveImageStore adds images to the archive.
+type archiveImageStore interface {
+	// Add copies the image into the archive and returns its content digest.
+	Add(img string) (contentDigest string, err error)
 }
 
+// export writes the bundle and its images directly to the destination as a compressed tar archive.
+// Image layers are downloaded in parallel into the content store, and then copied into the archive.
+// When the parallelism is 1, layers are streamed from the registry into the archive one at a time instead.
 func (ex *exporter) export(ctx context.Context) error {
-	ctx, log := tracing.StartSpan(ctx)
+	ctx, log := tracing.StartSpan(ctx, attribute.String("compression", ex.compression))
 	defer log.EndSpan()
 
-	name := ex.bundle.Name + "-" + ex.bundle.Version
-	archiveDir, err := ex.createArchiveFolder(name)
-	if err != nil {
-		return fmt.Errorf("can not create archive folder: %w", err)
-	}
-	defer ex.fs.RemoveAll(archiveDir)
-
-	bundleFile, err := ex.fs.OpenFile(filepath.Join(archiveDir, "bundle.json"), os.O_RDWR|os.O_CREATE, pkg.FileModeWritable)
+	compressor, err := newArchiveCompressor(ex.destination, ex.compression)
 	if err != nil {
 		return err
 	}
-	defer bundleFile.Close()
-	_, err = ex.bundle.WriteTo(bundleFile)
-	if err != nil {
+	tw := newArchiveTarWriter(compressor)
+
+	bundleData := &bytes.Buffer{}
+	if _, err = ex.bundle.WriteTo(bundleData); err != nil {
 		return fmt.Errorf("unable to write bundle.json in archive: %w", err)
 	}
-
 	reloData, err := json.Marshal(ex.relocationMap)
 	if err != nil {
 		return err
 	}
-	err = ex.fs.WriteFile(filepath.Join(archiveDir, "relocation-mapping.json"), reloData, pkg.FileModeWritable)
-	if err != nil {
+
+	if err = tw.writeDir("./"); err != nil {
+		return err
+	}
+	if err = tw.writeFile("./bundle.json", bundleData.Bytes()); err != nil {
+		return fmt.Errorf("unable to write bundle.json in archive: %w", err)
+	}
+	if err = tw.writeFile("./relocation-mapping.json", reloData); err != nil {
 		return fmt.Errorf("unable to write relocation-mapping.json in archive: %w", err)
 	}
 
-	var transport *http.Transport
-	if ex.insecureRegistry {
-		transport = cnabtooci.GetInsecureRegistryTransport()
-	} else {
-		transport = http.DefaultTransport.(*http.Transport)
+	// Record which images were left out so that publishing the archive knows not to look for them
+	if len(ex.omittedImages) > 0 {
+		omittedData, err := json.Marshal(ex.omittedImages)
+		if err != nil {
+			return err
+		}
+		if err = tw.writeFile("./"+archiveOmittedImagesFile, omittedData); err != nil {
+			return fmt.Errorf("unable to write %s in archive: %w", archiveOmittedImagesFile, err)
+		}
 	}
 
-	ex.imageStore, err = ex.imageStoreConstructor(
-		imagestore.WithArchiveDir(archiveDir),
-		imagestore.WithLogs(ex.logs),
-		imagestore.WithTransport(transport))
-	if err != nil {
-		return fmt.Errorf("error creating artifacts: %s", err)
+	layout := newArchiveLayoutWriter(ctx, tw, ex.fs, ex.regOpts, ex.craneOpts...)
+	layout.contentStore = ex.contentStore
+	if ex.imageStore == nil {
+		ex.imageStore = layout
+	}
+	if err = layout.writeDirs(); err != nil {
+		return fmt.Errorf("error creating archive: %w", err)
 	}
 
-	if err := ex.prepareArtifacts(ex.bundle); err != nil {
-		return fmt.Errorf("error preparing bundle artifact: %s", err)
+	if err = layout.prefetch(ex.getImageLocations(ex.bundle)); err != nil {
+		return fmt.Errorf("error downloading the bundle images: %w", err)
+	}
+	if err = ex.prepareArtifacts(ex.bundle); err != nil {
+		return fmt.Errorf("error preparing bundle artifact: %w", err)
 	}
 
-	rc, err := ex.CustomTar(ctx, archiveDir)
-	if err != nil {
+	if err = layout.Close(); err != nil {
 		return fmt.Errorf("error creating archive: %w", err)
 	}
-	defer rc.Close()
+	if err = tw.writeChecksums(); err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return fmt.Errorf("error creating archive: %w", err)
+	}
+	return compressor.Close()
+}
+
+// newArchiveCompressor wraps the destination with the requested compression.
+func newArchiveCompressor(w io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case ArchiveCompressionGzip, "":
+		return gzip.NewWriter(w), nil
+	case ArchiveCompressionZstd:
+		return zstd.NewWriter(w)
+	case ArchiveCompressionNone:
+		return nopWriteCloser{w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive compression %s", compression)
+	}
+}
 
-	_, err = io.Copy(ex.destination, rc)
-	return err
+type nopWriteCloser struct {
+	io.Writer
 }
 
-func (ex *exporter) createTarHeader(ctx context.Context, path string, file string, fileInfo os.FileInfo) (*tar.Header, error) {
-	log := tracing.LoggerFromContext(ctx)
+func (nopWriteCloser) Close() error {
+	return nil
+}
 
+// newTarHeader creates a tar header that does not depend upon the current user or time,
+// so that archiving the same bundle always results in the same archive.
+func newTarHeader(name string, typeflag byte, size int64) *tar.Header {
 	header := &tar.Header{
+		Name:       name,
+		Typeflag:   typeflag,
+		Size:       size,
 		ModTime:    time.Unix(0, 0),
 		AccessTime: time.Unix(0, 0),
 		ChangeTime: time.Unix(0, 0),
 		Uid:        0,
 		Gid:        0,
 	}
-
-	switch {
-	case fileInfo.Mode().IsDir():
-		header.Typeflag = tar.TypeDir
+	if typeflag == tar.TypeDir {
 		header.Mode = 0755
-	case fileInfo.Mode().IsRegular():
-		header.Typeflag = tar.TypeReg
+	} else {
 		header.Mode = 0644
-		header.Size = fileInfo.Size()
-	default:
-		log.Debug("Skipping header creation. Not a file/dir", attribute.String("createTarHeader.file", file))
-		return nil, nil
 	}
+	return header
+}
 
-	// ensure header has relative file path prepended with '.'
-	relativeFilePathName := file
-
-	if filepath.IsAbs(path) {
-		relativePath, err := filepath.Rel(path, file)
+// archiveTarWriter writes files to a tar archive and records the checksum of each file that is written.
+type archiveTarWriter struct {
+	*tar.Writer
 
-		if err != nil {
-			return nil, err
-		}
+	// checksums of the files written to the archive, keyed by the path of the file in the archive.
+	checksums map[string]digest.Digest
+}
 
-		if relativePath != "." {
-			relativeFilePathName = fmt.Sprintf(".%s%s", string(filepath.Separator), relativePath)
-		} else {
-			relativeFilePathName = relativePath
-		}
+func newArchiveTarWriter(w io.Writer) *archiveTarWriter {
+	return &archiveTarWriter{
+		Writer:    tar.NewWriter(w),
+		checksums: make(map[string]digest.Digest),
 	}
+}
 
-	header.Name = filepath.ToSlash(relativeFilePathName)
-
-	// directories must be suffixed with '/'
-	if fileInfo.Mode().IsDir() && !strings.HasSuffix(header.Name, "/") {
-		header.Name += "/"
+// writeDir adds a directory to the archive. Directory names must be suffixed with '/'.
+func (tw *archiveTarWriter) writeDir(name string) error {
+	if !strings.HasSuffix(name, "/") {
+		name += "/"
 	}
-
-	log.Debug("Created tar header", attribute.String("createTarHeader.headerName", header.Name))
-
-	return header, nil
+	if err := tw.WriteHeader(newTarHeader(name, tar.TypeDir, 0)); err != nil {
+		return fmt.Errorf("failed to write header for path %s: %w", name, err)
+	}
+	return nil
 }
 
-func (ex *exporter) CustomTar(ctx context.Context, srcPath string) (io.ReadCloser, error) {
-	pipeReader, pipeWriter := io.Pipe()
-
-	gzipWriter := gzip.NewWriter(pipeWriter)
-	tarWriter := tar.NewWriter(gzipWriter)
-
-	cleanSrcPath := filepath.Clean(srcPath)
-
-	go func() {
-		ctx, log := tracing.StartSpanWithName(ctx, "CustomTar.Walk")
-
-		defer func() {
-			if err := tarWriter.Close(); err != nil {
-				log.Warnf("Can't close tar writer: %s", err)
-			}
-			if err := gzipWriter.Close(); err != nil {
-				log.Warnf("Can't close gzip writer: %s\n", err)
-			}
-			if err := pipeWriter.Close(); err != nil {
-				log.Warnf("Can't close pipe writer: %s\n", err)
-			}
-			log.EndSpan()
-		}()
-
-		walker := func(path string, finfo os.FileInfo, err error) error {
-			if err != nil {
-				return fmt.Errorf("walk invoked with error: %w", err)
-			}
-
-			ctx, log := tracing.StartSpanWithName(ctx, "CustomTar.ProcessPath", attribute.String("customTar.path", path))
-			defer log.EndSpan()
-
-			hdr, err := ex.createTarHeader(ctx, srcPath, path, finfo)
-			if err != nil {
-				return fmt.Errorf("failed to create tar header for path %s: %w", path, err)
-			}
-
-			// if header is nil then it's not a regular file nor directory
-			if hdr == nil {
-				return nil
-			}
-
-			if err := tarWriter.WriteHeader(hdr); err != nil {
-				return fmt.Errorf("failed to write header for path %s: %w", path, err)
-			}
-
-			// if path is a dir, nothing more to do
-			if finfo.Mode().IsDir() {
-				return nil
-			}
-
-			// add file to tar
-			sourceFile, err := os.Open(path)
-			if err != nil {
-				return fmt.Errorf("failed to open %s: %w", path, err)
-			}
-
-			defer sourceFile.Close()
-			_, err = io.Copy(tarWriter, sourceFile)
-			if err != nil {
-				return fmt.Errorf("failed to copy %s: %w", path, err)
-			}
+// writeFile adds a file with the specified contents to the archive.
+func (tw *archiveTarWriter) writeFile(name string, data []byte) error {
+	return tw.writeStream(name, int64(len(data)), bytes.NewReader(data))
+}
 
-			return nil
-		}
+// writeStream adds a file to the archive, copying its contents from the reader.
+// The size must be known ahead of time because it is recorded in the file header.
+func (tw *archiveTarWriter) writeStream(name string, size int64, r io.Reader) error {
+	if err := tw.WriteHeader(newTarHeader(name, tar.TypeReg, size)); err != nil {
+		return fmt.Errorf("failed to write header for path %s: %w", name, err)
+	}
+	digester := digest.Canonical.Digester()
+	n, err := io.Copy(io.MultiWriter(tw, digester.Hash()), r)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s: %w", name, err)
+	}
+	if n != size {
+		return fmt.Errorf("failed to copy %s: expected %d bytes but only %d were read", name, size, n)
+	}
+	tw.checksums[normalizeArchivePath(name)] = digester.Digest()
+	return nil
+}
 
-		// build tar
-		filepath.Walk(cleanSrcPath, walker)
-	}()
+// writeChecksums adds the checksum manifest to the archive, which records the checksum of every file in the archive.
+// It must be the last file written to the archive.
+func (tw *archiveTarWriter) writeChecksums() error {
+	data, err := json.MarshalIndent(tw.checksums, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = tw.writeFile("./"+archiveChecksumsFile, data); err != nil {
+		return fmt.Errorf("unable to write %s in archive: %w", archiveChecksumsFile, err)
+	}
+	// The checksum manifest does not include itself
+	delete(tw.checksums, archiveChecksumsFile)
+	return nil
+}
 
-	return pipeReader, nil
+// normalizeArchivePath returns the path of a file in the archive, without the leading ./
+func normalizeArchivePath(name string) string {
+	return strings.TrimPrefix(path.Clean(name), "./")
 }
 
 // prepareArtifacts pulls all images, verifies their digests and
@@ -301,6 +420,9 @@ func (ex *exporter) prepareArtifacts(bun cnab.ExtendedBundle) error {
 	}
 	sort.Strings(imageKeys)
 	for _, k := range imageKeys {
+		if _, omitted := ex.omittedImages[k]; omitted {
+			continue
+		}
 		if err := ex.addImage(bun.Images[k].BaseImage); err != nil {
 			return err
 		}
@@ -315,6 +437,26 @@ func (ex *exporter) prepareArtifacts(bun cnab.ExtendedBundle) error {
 	return nil
 }
 
+// getImageLocations returns the location of every image that is included in the archive.
+func (ex *exporter) getImageLocations(bun cnab.ExtendedBundle) []string {
+	var images []string
+	for name, img := range bun.Images {
+		if _, omitted := ex.omittedImages[name]; omitted {
+			continue
+		}
+		if location, ok := ex.relocationMap[img.Image]; ok {
+			images = append(images, location)
+		}
+	}
+	for _, img := range bun.InvocationImages {
+		if location, ok := ex.relocationMap[img.Image]; ok {
+			images = append(images, location)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
+
 // addImage pulls an image using relocation map, adds it to the artifacts/ directory, and verifies its digest
 func (ex *exporter) addImage(base bundle.BaseImage) error {
 	if ex.relocationMap == nil {
@@ -331,23 +473,6 @@ func (ex *exporter) addImage(base bundle.BaseImage) error {
 	return checkDigest(base, dig)
 }
 
-// createArchiveFolder set up a temporary directory for storing all data needed to archive a bundle.
-// It sanitizes the name and make sure only the current user has full permission to it.
-// If the name contains a path separator, all path separators will be replaced with "-".
-func (ex *exporter) createArchiveFolder(name string) (string, error) {
-	cleanedPath := strings.ReplaceAll(afero.UnicodeSanitize(name), string(os.PathSeparator), "-")
-	archiveDir, err := ex.fs.TempDir("", cleanedPath)
-	if err != nil {
-		return "", fmt.Errorf("can not create a temporary archive folder: %w", err)
-	}
-
-	err = ex.fs.Chmod(archiveDir, pkg.FileModeDirectory)
-	if err != nil {
-		return "", fmt.Errorf("can not change permission for the temporary archive folder: %w", err)
-	}
-	return archiveDir, nil
-}
-
 // checkDigest compares the content digest of the given image to the given content digest and returns an error if they
 // are both non-empty and do not match
 func checkDigest(image bundle.BaseImage, dig string) error {
@@ -0,0 +1,533 @@
+package porter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/carolynvs/aferox"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/opencontainers/go-digest"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// archiveLayoutDir is the directory in the archive that contains the OCI image layout with the bundle images.
+	archiveLayoutDir = "./artifacts/layout/"
+
+	// annotationRefName is the OCI annotation used to identify an image in an OCI image layout.
+	annotationRefName = "org.opencontainers.image.ref.name"
+)
+
+// archiveLayoutWriter streams images from a registry into an OCI image layout inside a tar archive.
+// Blobs are copied straight from the registry into the archive, and each blob is only written once
+// even when it is shared between images.
+type archiveLayoutWriter struct {
+	ctx       context.Context
+	tw        *archiveTarWriter
+	craneOpts []crane.Option
+
+	// fs is used to read the certificates of the registries and registry mirrors.
+	fs aferox.Aferox
+
+	// regOpts are the TLS settings for each registry, and the mirrors used instead of the mirrored registry when retrieving images.
+	regOpts cnabtooci.RegistryOptions
+
+	// contentStore saves the layers that are downloaded, so that they are reused
+	// when they are archived again. Layers are always downloaded when it is nil.
+	contentStore *cnabtooci.ContentStore
+
+	// parallelism is the maximum number of images resolved, and layers downloaded, at the same time
+	// by prefetch.
+	parallelism int
+
+	// descriptors are the images resolved by prefetch, keyed by the image reference.
+	descriptors map[string]*remote.Descriptor
+
+	// dirs tracks which directories have been added to the archive.
+	dirs map[string]bool
+
+	// blobs tracks which blobs have been added to the archive.
+	blobs map[v1.Hash]bool
+
+	// manifests are the images included in the layout's index.json.
+	manifests []v1.Descriptor
+}
+
+func newArchiveLayoutWriter(ctx context.Context, tw *archiveTarWriter, fs aferox.Aferox, regOpts cnabtooci.RegistryOptions, opts ...crane.Option) *archiveLayoutWriter {
+	return &archiveLayoutWriter{
+		ctx:         ctx,
+		tw:          tw,
+		craneOpts:   opts,
+		fs:          fs,
+		regOpts:     regOpts,
+		parallelism: regOpts.GetParallelism(),
+		dirs:        make(map[string]bool),
+		blobs:       make(map[v1.Hash]bool),
+		descriptors: make(map[string]*remote.Descriptor),
+	}
+}
+
+// writeDirs adds the directories of the OCI image layout to the archive.
+func (w *archiveLayoutWriter) writeDirs() error {
+	for _, dir := range []string{"./artifacts/", archiveLayoutDir, archiveLayoutDir + "blobs/"} {
+		if err := w.writeDir(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *archiveLayoutWriter) writeDir(dir string) error {
+	if w.dirs[dir] {
+		return nil
+	}
+	if err := w.tw.writeDir(dir); err != nil {
+		return err
+	}
+	w.dirs[dir] = true
+	return nil
+}
+
+// Add streams the image, and any images it references, from the registry into the archive.
+// Returns the content digest of the image.
+func (w *archiveLayoutWriter) Add(img string) (string, error) {
+	ctx, log := tracing.StartSpan(w.ctx, attribute.String("image", img))
+	defer log.EndSpan()
+
+	desc, ok := w.descriptors[img]
+	if !ok {
+		var err error
+		desc, err = w.getDescriptor(ctx, img)
+		if err != nil {
+			return "", log.Error(err)
+		}
+	}
+
+	switch {
+	case desc.MediaType.IsIndex():
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return "", log.Errorf("error reading image index %s: %w", img, err)
+		}
+		err = w.writeIndex(idx)
+		if err != nil {
+			return "", log.Errorf("error adding image index %s to the archive: %w", img, err)
+		}
+	case desc.MediaType.IsImage():
+		image, err := desc.Image()
+		if err != nil {
+			return "", log.Errorf("error reading image %s: %w", img, err)
+		}
+		if err = w.writeImage(image); err != nil {
+			return "", log.Errorf("error adding image %s to the archive: %w", img, err)
+		}
+	default:
+		return "", log.Errorf("cannot archive %s because it has an unsupported media type %s", img, desc.MediaType)
+	}
+
+	w.addManifest(img, desc.Descriptor)
+	return desc.Digest.String(), nil
+}
+
+// getDescriptor retrieves the image from the registry mirror, or its registry.
+func (w *archiveLayoutWriter) getDescriptor(ctx context.Context, img string) (*remote.Descriptor, error) {
+	craneOpts, err := w.getCraneOptions(img)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := name.ParseReference(img, craneOpts.Name...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s as an image reference: %w", img, err)
+	}
+
+	desc, err := w.getFromMirror(ctx, img)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		desc, err = remote.Get(ref, craneOpts.Remote...)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving image %s: %w", img, err)
+		}
+	}
+	return desc, nil
+}
+
+// prefetch resolves the images, and downloads their layers into the content store, with up to
+// parallelism images and layers transferred at the same time. The layers are then copied from the
+// content store into the archive, which must be written one file at a time.
+func (w *archiveLayoutWriter) prefetch(images []string) error {
+	// Layers can only be downloaded ahead of time when there is somewhere to keep them
+	if w.contentStore == nil || w.parallelism < 2 {
+		return nil
+	}
+
+	ctx, log := tracing.StartSpan(w.ctx, attribute.Int("parallelism", w.parallelism))
+	defer log.EndSpan()
+
+	var lock sync.Mutex
+	layers := make(map[v1.Hash]v1.Layer)
+	g := new(errgroup.Group)
+	g.SetLimit(w.parallelism)
+	for _, img := range images {
+		img := img
+		g.Go(func() error {
+			desc, err := w.getDescriptor(ctx, img)
+			if err != nil {
+				return err
+			}
+			imgLayers, err := getDescriptorLayers(desc)
+			if err != nil {
+				return fmt.Errorf("error reading the layers of image %s: %w", img, err)
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			w.descriptors[img] = desc
+			for d, layer := range imgLayers {
+				layers[d] = layer
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return log.Error(err)
+	}
+
+	log.Debugf("Downloading %d layers", len(layers))
+	g = new(errgroup.Group)
+	g.SetLimit(w.parallelism)
+	for d, layer := range layers {
+		d, layer := d, layer
+		g.Go(func() error {
+			return w.prefetchLayer(d, layer)
+		})
+	}
+	return log.Error(g.Wait())
+}
+
+// prefetchLayer downloads the layer into the content store, unless it is already in the store.
+func (w *archiveLayoutWriter) prefetchLayer(d v1.Hash, layer v1.Layer) error {
+	blobDigest := digest.Digest(d.String())
+	r, found, err := w.contentStore.Open(blobDigest)
+	if err != nil {
+		return err
+	}
+	if !found {
+		r, err = w.contentStore.Fetch(blobDigest, layer.Compressed)
+		if err != nil {
+			return fmt.Errorf("error downloading layer %s: %w", d, err)
+		}
+		if _, err = io.Copy(io.Discard, r); err != nil {
+			r.Close()
+			return fmt.Errorf("error downloading layer %s: %w", d, err)
+		}
+	}
+	return r.Close()
+}
+
+// getDescriptorLayers returns the distributable layers of the image, or of every image in the image index.
+func getDescriptorLayers(desc *remote.Descriptor) (map[v1.Hash]v1.Layer, error) {
+	layers := make(map[v1.Hash]v1.Layer)
+	switch {
+	case desc.MediaType.IsIndex():
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return nil, err
+		}
+		return layers, addIndexLayers(idx, layers)
+	case desc.MediaType.IsImage():
+		img, err := desc.Image()
+		if err != nil {
+			return nil, err
+		}
+		return layers, addImageLayers(img, layers)
+	default:
+		// Unsupported media types are reported when the image is added to the archive
+		return layers, nil
+	}
+}
+
+func addIndexLayers(idx v1.ImageIndex, layers map[v1.Hash]v1.Layer) error {
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+
+	for _, child := range m.Manifests {
+		switch {
+		case child.MediaType.IsIndex():
+			childIdx, err := idx.ImageIndex(child.Digest)
+			if err != nil {
+				return err
+			}
+			if err = addIndexLayers(childIdx, layers); err != nil {
+				return err
+			}
+		case child.MediaType.IsImage():
+			childImg, err := idx.Image(child.Digest)
+			if err != nil {
+				return err
+			}
+			if err = addImageLayers(childImg, layers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func addImageLayers(img v1.Image, layers map[v1.Hash]v1.Layer) error {
+	imgLayers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	for _, layer := range imgLayers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return err
+		}
+		if !mediaType.IsDistributable() {
+			continue
+		}
+
+		d, err := layer.Digest()
+		if err != nil {
+			return err
+		}
+		layers[d] = layer
+	}
+	return nil
+}
+
+// getCraneOptions returns the crane options used to retrieve the image, applying the TLS settings for its registry.
+func (w *archiveLayoutWriter) getCraneOptions(img string) (crane.Options, error) {
+	imgRef, err := cnab.ParseOCIReference(img)
+	if err != nil {
+		return crane.Options{}, fmt.Errorf("error parsing %s as an OCI reference: %w", img, err)
+	}
+	regOpts, err := cnabtooci.GetCraneOptions(w.fs, w.regOpts, imgRef.Registry())
+	if err != nil {
+		return crane.Options{}, err
+	}
+
+	opts := make([]crane.Option, 0, len(w.craneOpts)+len(regOpts))
+	opts = append(opts, w.craneOpts...)
+	return crane.GetOptions(append(opts, regOpts...)...), nil
+}
+
+// getFromMirror retrieves the image from the mirror configured for its registry.
+// Returns nil when the registry is not mirrored, or the image could not be retrieved from the mirror.
+func (w *archiveLayoutWriter) getFromMirror(ctx context.Context, img string) (*remote.Descriptor, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	imgRef, err := cnab.ParseOCIReference(img)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s as an OCI reference: %w", img, err)
+	}
+	mirrorRef, ok, err := w.regOpts.FindMirror(imgRef)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	opts, err := cnabtooci.GetMirrorCraneOptions(w.fs, mirrorRef.Mirror)
+	if err != nil {
+		return nil, err
+	}
+	craneOpts := crane.GetOptions(append([]crane.Option{crane.WithContext(w.ctx)}, opts...)...)
+
+	ref, err := name.ParseReference(mirrorRef.Reference.String(), craneOpts.Name...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s as an image reference: %w", mirrorRef.Reference, err)
+	}
+	desc, err := remote.Get(ref, craneOpts.Remote...)
+	if err != nil {
+		log.Warnf("Unable to retrieve image %s from the registry mirror %s, retrieving it from %s instead: %s", img, mirrorRef.Mirror.Mirror, imgRef.Registry(), err)
+		return nil, nil
+	}
+	return desc, nil
+}
+
+// addManifest records the image in the layout's index.json so that it can be found by name when the archive is imported.
+func (w *archiveLayoutWriter) addManifest(img string, desc v1.Descriptor) {
+	for _, m := range w.manifests {
+		if m.Annotations[annotationRefName] == img && m.Digest == desc.Digest {
+			return
+		}
+	}
+
+	w.manifests = append(w.manifests, v1.Descriptor{
+		MediaType:   desc.MediaType,
+		Size:        desc.Size,
+		Digest:      desc.Digest,
+		Annotations: map[string]string{annotationRefName: img},
+	})
+}
+
+// writeIndex adds an image index, and all of the images that it references, to the archive.
+func (w *archiveLayoutWriter) writeIndex(idx v1.ImageIndex) error {
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+
+	for _, child := range m.Manifests {
+		switch {
+		case child.MediaType.IsIndex():
+			childIdx, err := idx.ImageIndex(child.Digest)
+			if err != nil {
+				return err
+			}
+			if err = w.writeIndex(childIdx); err != nil {
+				return err
+			}
+		case child.MediaType.IsImage():
+			childImg, err := idx.Image(child.Digest)
+			if err != nil {
+				return err
+			}
+			if err = w.writeImage(childImg); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported media type %s for manifest %s", child.MediaType, child.Digest)
+		}
+	}
+
+	d, err := idx.Digest()
+	if err != nil {
+		return err
+	}
+	raw, err := idx.RawManifest()
+	if err != nil {
+		return err
+	}
+	return w.writeBlobData(d, raw)
+}
+
+// writeImage adds the layers, config and manifest of the image to the archive.
+func (w *archiveLayoutWriter) writeImage(img v1.Image) error {
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return err
+		}
+		// Foreign layers are not pushed to the registry, so we cannot include them in the archive
+		if !mediaType.IsDistributable() {
+			continue
+		}
+
+		d, err := layer.Digest()
+		if err != nil {
+			return err
+		}
+		size, err := layer.Size()
+		if err != nil {
+			return err
+		}
+		if err = w.writeBlob(d, size, w.openLayer(d, layer)); err != nil {
+			return err
+		}
+	}
+
+	configDigest, err := img.ConfigName()
+	if err != nil {
+		return err
+	}
+	config, err := img.RawConfigFile()
+	if err != nil {
+		return err
+	}
+	if err = w.writeBlobData(configDigest, config); err != nil {
+		return err
+	}
+
+	d, err := img.Digest()
+	if err != nil {
+		return err
+	}
+	raw, err := img.RawManifest()
+	if err != nil {
+		return err
+	}
+	return w.writeBlobData(d, raw)
+}
+
+// openLayer returns a function that opens the compressed layer, reading it
+// from the content store when the layer was already downloaded.
+func (w *archiveLayoutWriter) openLayer(d v1.Hash, layer v1.Layer) func() (io.ReadCloser, error) {
+	if w.contentStore == nil {
+		return layer.Compressed
+	}
+	return func() (io.ReadCloser, error) {
+		return w.contentStore.Fetch(digest.Digest(d.String()), layer.Compressed)
+	}
+}
+
+func (w *archiveLayoutWriter) writeBlobData(d v1.Hash, data []byte) error {
+	return w.writeBlob(d, int64(len(data)), func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// writeBlob copies the blob into the archive, unless it has already been added.
+// The blob is only opened after it is determined that it must be written.
+func (w *archiveLayoutWriter) writeBlob(d v1.Hash, size int64, open func() (io.ReadCloser, error)) error {
+	if w.blobs[d] {
+		return nil
+	}
+
+	algDir := archiveLayoutDir + "blobs/" + d.Algorithm + "/"
+	if err := w.writeDir(algDir); err != nil {
+		return err
+	}
+
+	r, err := open()
+	if err != nil {
+		return fmt.Errorf("error reading blob %s: %w", d, err)
+	}
+	defer r.Close()
+
+	if err = w.tw.writeStream(algDir+d.Hex, size, r); err != nil {
+		return err
+	}
+	w.blobs[d] = true
+	return nil
+}
+
+// Close completes the OCI image layout by writing the index.json and oci-layout files.
+func (w *archiveLayoutWriter) Close() error {
+	index := v1.IndexManifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIImageIndex,
+		Manifests:     w.manifests,
+	}
+	if index.Manifests == nil {
+		index.Manifests = []v1.Descriptor{}
+	}
+	indexData, err := json.MarshalIndent(index, "", "   ")
+	if err != nil {
+		return err
+	}
+	if err = w.tw.writeFile(archiveLayoutDir+"index.json", indexData); err != nil {
+		return err
+	}
+
+	return w.tw.writeFile(archiveLayoutDir+"oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`))
+}
@@ -1,15 +1,36 @@
 package porter
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
-	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/tests"
+	"github.com/carolynvs/aferox"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-to-oci/relocation"
-	"github.com/cnabio/image-relocation/pkg/image"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,44 +53,410 @@ func TestArchive_Validate(t *testing.T) {
 	defer p.Close()
 
 	testcases := []struct {
-		name      string
-		args      []string
-		reference string
-		wantError string
+		name        string
+		args        []string
+		reference   string
+		compression string
+		wantError   string
 	}{
-		{"no arg", nil, "", "destination file is required"},
-		{"no tag", []string{"/path/to/file"}, "", "must provide a value for --reference of the form REGISTRY/bundle:tag"},
-		{"too many args", []string{"/path/to/file", "moar args!"}, "myreg/mybuns:v0.1.0", "only one positional argument may be specified, the archive file name, but multiple were received: [/path/to/file moar args!]"},
-		{"just right", []string{"/path/to/file"}, "myreg/mybuns:v0.1.0", ""},
+		{"no arg", nil, "", "", "destination file is required"},
+		{"no tag", []string{"/path/to/file"}, "", "", "must provide a value for --reference of the form REGISTRY/bundle:tag"},
+		{"too many args", []string{"/path/to/file", "moar args!"}, "myreg/mybuns:v0.1.0", "", "only one positional argument may be specified, the archive file name, but multiple were received: [/path/to/file moar args!]"},
+		{"just right", []string{"/path/to/file"}, "myreg/mybuns:v0.1.0", "", ""},
+		{"zstd", []string{"/path/to/file"}, "myreg/mybuns:v0.1.0", "zstd", ""},
+		{"uncompressed", []string{"/path/to/file"}, "myreg/mybuns:v0.1.0", "none", ""},
+		{"invalid compression", []string{"/path/to/file"}, "myreg/mybuns:v0.1.0", "bzip2", "invalid --compression bzip2, allowed values are: gzip, zstd, none"},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			opts := ArchiveOptions{}
 			opts.Reference = tc.reference
+			opts.Compression = tc.compression
 
 			err := opts.Validate(context.Background(), tc.args, p.Porter)
 			if tc.wantError != "" {
 				require.EqualError(t, err, tc.wantError)
 			} else {
 				require.NoError(t, err, "expected no validation error to occur")
+				if tc.compression == "" {
+					assert.Equal(t, ArchiveCompressionGzip, opts.Compression, "expected the archive to default to gzip")
+				}
 			}
 		})
 	}
 }
 
-func TestArchive_ArchiveDirectory(t *testing.T) {
+func TestArchive_Validate_ExcludeImages(t *testing.T) {
 	p := NewTestPorter(t)
 	defer p.Close()
-	ex := exporter{
-		fs: p.FileSystem,
+
+	opts := ArchiveOptions{ExcludeImages: []string{"training-data*", "["}}
+	opts.Reference = "myreg/mybuns:v0.1.0"
+	err := opts.Validate(context.Background(), []string{"/path/to/file"}, p.Porter)
+	require.EqualError(t, err, "invalid --exclude-image pattern [: syntax error in pattern")
+}
+
+func TestArchive_GetOmittedImages(t *testing.T) {
+	b := cnab.NewBundle(bundle.Bundle{
+		Images: map[string]bundle.Image{
+			"whalesay":       {BaseImage: bundle.BaseImage{Image: "example.com/whalesay:latest"}},
+			"training-data":  {BaseImage: bundle.BaseImage{Image: "example.com/datasets/training:v1"}},
+			"inference-data": {BaseImage: bundle.BaseImage{Image: "example.com/datasets/inference:v1"}},
+		},
+	})
+
+	testcases := []struct {
+		name        string
+		opts        ArchiveOptions
+		wantOmitted map[string]string
+	}{
+		{name: "include all images", opts: ArchiveOptions{}, wantOmitted: map[string]string{}},
+		{name: "exclude all images", opts: ArchiveOptions{ExcludeReferencedImages: true}, wantOmitted: map[string]string{
+			"whalesay":       "example.com/whalesay:latest",
+			"training-data":  "example.com/datasets/training:v1",
+			"inference-data": "example.com/datasets/inference:v1",
+		}},
+		{name: "exclude by name", opts: ArchiveOptions{ExcludeImages: []string{"training-*"}}, wantOmitted: map[string]string{
+			"training-data": "example.com/datasets/training:v1",
+		}},
+		{name: "exclude by reference", opts: ArchiveOptions{ExcludeImages: []string{"example.com/datasets/*"}}, wantOmitted: map[string]string{
+			"training-data":  "example.com/datasets/training:v1",
+			"inference-data": "example.com/datasets/inference:v1",
+		}},
+		{name: "no match", opts: ArchiveOptions{ExcludeImages: []string{"missing"}}, wantOmitted: map[string]string{}},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantOmitted, tc.opts.getOmittedImages(b))
+		})
+	}
+}
+
+func TestArchive_Export(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	// Push an image with a layer shared with the bundle's invocation image, to validate that blobs are only written once
+	invImg, err := random.Image(1024, 2)
+	require.NoError(t, err)
+	invImgRef := host + "/mybuns-installer@" + mustDigest(t, invImg)
+	require.NoError(t, crane.Push(invImg, invImgRef, crane.Insecure))
+	layers, err := invImg.Layers()
+	require.NoError(t, err)
+	extraLayer, err := random.Layer(512, types.DockerLayer)
+	require.NoError(t, err)
+	img, err := mutate.AppendLayers(invImg, extraLayer)
+	require.NoError(t, err)
+	imgRef := host + "/whalesay@" + mustDigest(t, img)
+	require.NoError(t, crane.Push(img, imgRef, crane.Insecure))
+
+	b := cnab.NewBundle(bundle.Bundle{
+		Name:    "mybuns",
+		Version: "0.1.0",
+		InvocationImages: []bundle.InvocationImage{
+			{BaseImage: bundle.BaseImage{Image: "example.com/mybuns-installer:v0.1.0", Digest: mustDigest(t, invImg)}},
+		},
+		Images: map[string]bundle.Image{
+			"whalesay": {BaseImage: bundle.BaseImage{Image: "example.com/whalesay:latest", Digest: mustDigest(t, img)}},
+		},
+	})
+	relocationMap := relocation.ImageRelocationMap{
+		"example.com/mybuns-installer:v0.1.0": invImgRef,
+		"example.com/whalesay:latest":         imgRef,
+	}
+
+	for _, compression := range ArchiveCompressions {
+		compression := compression
+		t.Run(compression, func(t *testing.T) {
+			var dest bytes.Buffer
+			ex := exporter{
+				bundle:        b,
+				relocationMap: relocationMap,
+				destination:   &dest,
+				compression:   compression,
+				craneOpts:     []crane.Option{crane.Insecure},
+			}
+			require.NoError(t, ex.export(context.Background()))
+
+			result, err := verifyArchive(bytes.NewReader(dest.Bytes()))
+			require.NoError(t, err, "the archive failed verification")
+			assert.True(t, result.hasChecksums, "expected the archive to include a checksum manifest")
+
+			files := readArchive(t, &dest, compression)
+
+			gotBundle, err := bundle.Unmarshal(files["./bundle.json"])
+			require.NoError(t, err, "bundle.json was not included in the archive")
+			assert.Equal(t, "mybuns", gotBundle.Name)
+
+			var gotRelocationMap relocation.ImageRelocationMap
+			require.NoError(t, json.Unmarshal(files["./relocation-mapping.json"], &gotRelocationMap), "relocation-mapping.json was not included in the archive")
+			assert.Equal(t, relocationMap, gotRelocationMap)
+
+			assert.Contains(t, files, "./artifacts/layout/oci-layout")
+			assert.NotContains(t, files, "./omitted-images.json", "no images were omitted")
+			var index v1.IndexManifest
+			require.NoError(t, json.Unmarshal(files["./artifacts/layout/index.json"], &index), "index.json was not included in the archive")
+			require.Len(t, index.Manifests, 2)
+			assert.Equal(t, imgRef, index.Manifests[0].Annotations[annotationRefName], "expected the referenced images to be added first")
+			assert.Equal(t, invImgRef, index.Manifests[1].Annotations[annotationRefName])
+
+			// Every layer should be in the archive, along with the manifest and config for each image
+			for _, layer := range append(layers, extraLayer) {
+				d, err := layer.Digest()
+				require.NoError(t, err)
+				assert.Contains(t, files, "./artifacts/layout/blobs/sha256/"+d.Hex)
+			}
+			for _, i := range []v1.Image{invImg, img} {
+				d, err := i.Digest()
+				require.NoError(t, err)
+				assert.Contains(t, files, "./artifacts/layout/blobs/sha256/"+d.Hex)
+				configDigest, err := i.ConfigName()
+				require.NoError(t, err)
+				assert.Contains(t, files, "./artifacts/layout/blobs/sha256/"+configDigest.Hex)
+			}
+		})
 	}
 
-	dir, err := ex.createArchiveFolder("examples/test-bundle-0.2.0")
+	t.Run("omitted images", func(t *testing.T) {
+		var dest bytes.Buffer
+		ex := exporter{
+			bundle:        b,
+			relocationMap: relocationMap,
+			omittedImages: map[string]string{"whalesay": "example.com/whalesay:latest"},
+			destination:   &dest,
+			craneOpts:     []crane.Option{crane.Insecure},
+		}
+		require.NoError(t, ex.export(context.Background()))
+
+		files := readArchive(t, &dest, ArchiveCompressionGzip)
+
+		var omitted map[string]string
+		require.NoError(t, json.Unmarshal(files["./omitted-images.json"], &omitted), "omitted-images.json was not included in the archive")
+		assert.Equal(t, ex.omittedImages, omitted)
+
+		var index v1.IndexManifest
+		require.NoError(t, json.Unmarshal(files["./artifacts/layout/index.json"], &index))
+		require.Len(t, index.Manifests, 1, "expected only the invocation image in the archive")
+		assert.Equal(t, invImgRef, index.Manifests[0].Annotations[annotationRefName])
+
+		d, err := extraLayer.Digest()
+		require.NoError(t, err)
+		assert.NotContains(t, files, "./artifacts/layout/blobs/sha256/"+d.Hex, "the omitted image's layers should not be in the archive")
+	})
+
+	t.Run("registry mirror", func(t *testing.T) {
+		// The images are only available through the mirror
+		mirroredRelocationMap := relocation.ImageRelocationMap{
+			"example.com/mybuns-installer:v0.1.0": "registry.invalid/mybuns-installer@" + mustDigest(t, invImg),
+			"example.com/whalesay:latest":         "registry.invalid/whalesay@" + mustDigest(t, img),
+		}
+		var dest bytes.Buffer
+		ex := exporter{
+			bundle:        b,
+			relocationMap: mirroredRelocationMap,
+			destination:   &dest,
+			fs:            aferox.NewAferox("/", afero.NewMemMapFs()),
+			regOpts:       cnabtooci.RegistryOptions{Mirrors: config.RegistryMirrors{{Registry: "registry.invalid", Mirror: host, Insecure: true}}},
+		}
+		require.NoError(t, ex.export(context.Background()))
+
+		files := readArchive(t, &dest, ArchiveCompressionGzip)
+		var index v1.IndexManifest
+		require.NoError(t, json.Unmarshal(files["./artifacts/layout/index.json"], &index))
+		require.Len(t, index.Manifests, 2)
+		assert.Equal(t, mirroredRelocationMap["example.com/whalesay:latest"], index.Manifests[0].Annotations[annotationRefName], "the images should be named with their original reference")
+		assert.Equal(t, mirroredRelocationMap["example.com/mybuns-installer:v0.1.0"], index.Manifests[1].Annotations[annotationRefName])
+	})
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		b := cnab.NewBundle(bundle.Bundle{
+			Name:    "mybuns",
+			Version: "0.1.0",
+			InvocationImages: []bundle.InvocationImage{
+				{BaseImage: bundle.BaseImage{Image: "example.com/mybuns-installer:v0.1.0", Digest: mustDigest(t, img)}},
+			},
+		})
+		ex := exporter{
+			bundle:        b,
+			relocationMap: relocationMap,
+			destination:   io.Discard,
+			craneOpts:     []crane.Option{crane.Insecure},
+		}
+		err := ex.export(context.Background())
+		tests.RequireErrorContains(t, err, "content digest mismatch")
+	})
+}
+
+// readArchive returns the contents of every file in the archive, keyed by the file name.
+func readArchive(t *testing.T, r io.Reader, compression string) map[string][]byte {
+	switch compression {
+	case ArchiveCompressionGzip:
+		gzr, err := gzip.NewReader(r)
+		require.NoError(t, err, "expected a gzip archive")
+		r = gzr
+	case ArchiveCompressionZstd:
+		zr, err := zstd.NewReader(r)
+		require.NoError(t, err, "expected a zstd archive")
+		defer zr.Close()
+		r = zr
+	}
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err, "invalid tar archive")
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		assert.Equal(t, int64(0), hdr.ModTime.Unix(), "expected the file modification time to be stable")
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = data
+	}
+	return files
+}
+
+func mustDigest(t *testing.T, img v1.Image) string {
+	d, err := img.Digest()
+	require.NoError(t, err)
+	return d.String()
+}
+
+func TestArchive_Export_ContentStore(t *testing.T) {
+	// Count the blobs that are downloaded from the registry
+	var blobPulls atomic.Int32
+	reg := registry.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+			blobPulls.Add(1)
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	img, err := random.Image(1024, 2)
 	require.NoError(t, err)
-	require.Contains(t, dir, "examples-test-bundle-0.2.0")
+	imgRef := host + "/mybuns-installer@" + mustDigest(t, img)
+	require.NoError(t, crane.Push(img, imgRef, crane.Insecure))
 
-	tests.AssertDirectoryPermissionsEqual(t, dir, pkg.FileModeDirectory)
+	b := cnab.NewBundle(bundle.Bundle{
+		Name:    "mybuns",
+		Version: "0.1.0",
+		InvocationImages: []bundle.InvocationImage{
+			{BaseImage: bundle.BaseImage{Image: "example.com/mybuns-installer:v0.1.0", Digest: mustDigest(t, img)}},
+		},
+	})
+	relocationMap := relocation.ImageRelocationMap{"example.com/mybuns-installer:v0.1.0": imgRef}
+
+	c := config.NewTestConfig(t)
+	store := cnabtooci.NewContentStore(c.Config)
+	export := func() {
+		var dest bytes.Buffer
+		ex := exporter{
+			bundle:        b,
+			relocationMap: relocationMap,
+			destination:   &dest,
+			craneOpts:     []crane.Option{crane.Insecure},
+			contentStore:  store,
+		}
+		require.NoError(t, ex.export(context.Background()))
+		_, err := verifyArchive(bytes.NewReader(dest.Bytes()))
+		require.NoError(t, err, "the archive failed verification")
+	}
+
+	export()
+	assert.Equal(t, int32(3), blobPulls.Load(), "expected the config and both layers to be downloaded")
+
+	// Only the config is downloaded again, the layers are read from the content store
+	blobPulls.Store(0)
+	export()
+	assert.Equal(t, int32(1), blobPulls.Load(), "expected the layers to be reused from the content store")
+}
+
+func TestArchive_Export_Parallelism(t *testing.T) {
+	// Track how many layers are downloaded from the registry at the same time
+	var lock sync.Mutex
+	var active, maxActive int
+	reg := registry.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+			lock.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			lock.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			defer func() {
+				lock.Lock()
+				active--
+				lock.Unlock()
+			}()
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	pushImage := func(repo string) (string, string) {
+		img, err := random.Image(1024, 2)
+		require.NoError(t, err)
+		imgDigest := mustDigest(t, img)
+		imgRef := host + "/" + repo + "@" + imgDigest
+		require.NoError(t, crane.Push(img, imgRef, crane.Insecure))
+		return imgRef, imgDigest
+	}
+	installerRef, installerDigest := pushImage("mybuns-installer")
+	webRef, webDigest := pushImage("web")
+	b := cnab.NewBundle(bundle.Bundle{
+		Name:    "mybuns",
+		Version: "0.1.0",
+		InvocationImages: []bundle.InvocationImage{
+			{BaseImage: bundle.BaseImage{Image: "example.com/mybuns-installer:v0.1.0", Digest: installerDigest}},
+		},
+		Images: map[string]bundle.Image{
+			"web": {BaseImage: bundle.BaseImage{Image: "example.com/web:v1", Digest: webDigest}},
+		},
+	})
+	relocationMap := relocation.ImageRelocationMap{
+		"example.com/mybuns-installer:v0.1.0": installerRef,
+		"example.com/web:v1":                  webRef,
+	}
+
+	export := func(parallelism int) {
+		lock.Lock()
+		maxActive = 0
+		lock.Unlock()
+
+		c := config.NewTestConfig(t)
+		var dest bytes.Buffer
+		ex := exporter{
+			bundle:        b,
+			relocationMap: relocationMap,
+			destination:   &dest,
+			craneOpts:     []crane.Option{crane.Insecure},
+			regOpts:       cnabtooci.RegistryOptions{Parallelism: parallelism},
+			contentStore:  cnabtooci.NewContentStore(c.Config),
+		}
+		require.NoError(t, ex.export(context.Background()))
+		_, err := verifyArchive(bytes.NewReader(dest.Bytes()))
+		require.NoError(t, err, "the archive failed verification")
+	}
+
+	export(4)
+	assert.Greater(t, maxActive, 1, "expected the layers to be downloaded at the same time")
+
+	export(1)
+	assert.Equal(t, 1, maxActive, "expected the layers to be downloaded one at a time")
 }
 
 func TestArchive_AddImage(t *testing.T) {
@@ -154,10 +541,6 @@ func (m mockCollectingImageStore) Add(img string) (contentDigest string, err err
 	return "digest", nil
 }
 
-func (m mockCollectingImageStore) Push(dig image.Digest, src image.Name, dst image.Name) error {
-	return nil
-}
-
 type mockImageStore struct {
 	t        *testing.T
 	expected string
@@ -167,7 +550,3 @@ func (m mockImageStore) Add(img string) (contentDigest string, err error) {
 	require.Equal(m.t, m.expected, img)
 	return "digest", nil
 }
-
-func (m mockImageStore) Push(dig image.Digest, src image.Name, dst image.Name) error {
-	return nil
-}
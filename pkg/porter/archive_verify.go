@@ -0,0 +1,217 @@
+package porter
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ArchiveVerifyOptions are the options for verifying the integrity of a bundle archive.
+type ArchiveVerifyOptions struct {
+	ArchiveFile string
+}
+
+// Validate the options for verifying a bundle archive.
+func (o *ArchiveVerifyOptions) Validate(args []string, p *Porter) error {
+	if len(args) < 1 || args[0] == "" {
+		return errors.New("the archive file is required")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only one positional argument may be specified, the archive file name, but multiple were received: %s", args)
+	}
+	o.ArchiveFile = args[0]
+
+	if _, err := p.FileSystem.Stat(o.ArchiveFile); err != nil {
+		return fmt.Errorf("unable to access archive %s: %w", o.ArchiveFile, err)
+	}
+	return nil
+}
+
+// VerifyArchive reads the entire bundle archive and validates the checksum of every file in the archive,
+// and the digest of every image blob, so that a corrupted archive is detected before it is published.
+func (p *Porter) VerifyArchive(ctx context.Context, opts ArchiveVerifyOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	f, err := p.FileSystem.Open(opts.ArchiveFile)
+	if err != nil {
+		return log.Errorf("error opening archive %s: %w", opts.ArchiveFile, err)
+	}
+	defer f.Close()
+
+	result, err := verifyArchive(f)
+	if err != nil {
+		return log.Errorf("archive %s failed verification: %w", opts.ArchiveFile, err)
+	}
+
+	if !result.hasChecksums {
+		log.Warnf("The archive %s does not contain a checksum manifest, so only the image blobs were verified. Re-create the archive with a newer version of porter to verify every file.", opts.ArchiveFile)
+	}
+	p.printInfof("Verified %d files in %s\n", result.filesVerified, opts.ArchiveFile)
+	return nil
+}
+
+// archiveVerification is the result of verifying a bundle archive.
+type archiveVerification struct {
+	// filesVerified is the number of files with a checksum or digest that matched.
+	filesVerified int
+
+	// hasChecksums indicates if the archive contained a checksum manifest.
+	hasChecksums bool
+}
+
+// verifyArchive reads the archive, validating that every image blob matches its digest, and that every file
+// matches the checksum recorded in the archive's checksum manifest.
+// All problems found are returned together, so that they can be fixed at once.
+func verifyArchive(r io.Reader) (archiveVerification, error) {
+	var result archiveVerification
+
+	decompressed, err := newArchiveDecompressor(r)
+	if err != nil {
+		return result, err
+	}
+	defer decompressed.Close()
+
+	var checksumsData []byte
+	actual := make(map[string]digest.Digest)
+	var problems []string
+
+	tr := tar.NewReader(decompressed)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("error reading the archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := normalizeArchivePath(hdr.Name)
+		if name == archiveChecksumsFile {
+			if checksumsData, err = io.ReadAll(tr); err != nil {
+				return result, fmt.Errorf("error reading %s: %w", archiveChecksumsFile, err)
+			}
+			continue
+		}
+
+		digester := digest.Canonical.Digester()
+		if _, err = io.Copy(digester.Hash(), tr); err != nil {
+			return result, fmt.Errorf("error reading %s: %w", name, err)
+		}
+		actual[name] = digester.Digest()
+
+		// Image blobs are content addressable, so their digest is validated even without a checksum manifest
+		if alg, hex, ok := parseBlobPath(name); ok && alg == digest.Canonical {
+			if hex != actual[name].Encoded() {
+				problems = append(problems, fmt.Sprintf("blob %s has digest %s", name, actual[name]))
+			}
+		}
+	}
+
+	if checksumsData == nil {
+		// Older archives do not have a checksum manifest, fallback to only validating the blobs
+		if len(problems) > 0 {
+			return result, errors.New(strings.Join(problems, "\n"))
+		}
+		for name := range actual {
+			if _, _, ok := parseBlobPath(name); ok {
+				result.filesVerified++
+			}
+		}
+		return result, nil
+	}
+
+	result.hasChecksums = true
+	var expected map[string]digest.Digest
+	if err = json.Unmarshal(checksumsData, &expected); err != nil {
+		return result, fmt.Errorf("error parsing %s: %w", archiveChecksumsFile, err)
+	}
+
+	for _, name := range sortedDigestKeys(expected) {
+		got, ok := actual[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is missing", name))
+			continue
+		}
+		if got != expected[name] {
+			problems = append(problems, fmt.Sprintf("%s has checksum %s but the checksum should be %s", name, got, expected[name]))
+		}
+	}
+	for _, name := range sortedDigestKeys(actual) {
+		if _, ok := expected[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s is not listed in %s", name, archiveChecksumsFile))
+		}
+	}
+
+	if len(problems) > 0 {
+		return result, errors.New(strings.Join(problems, "\n"))
+	}
+	result.filesVerified = len(actual)
+	return result, nil
+}
+
+// parseBlobPath returns the algorithm and encoded digest of a blob in the archive's OCI image layout.
+func parseBlobPath(name string) (digest.Algorithm, string, bool) {
+	blobsDir := normalizeArchivePath(archiveLayoutDir) + "/blobs/"
+	if !strings.HasPrefix(name, blobsDir) {
+		return "", "", false
+	}
+	alg, hex := path.Split(strings.TrimPrefix(name, blobsDir))
+	return digest.Algorithm(strings.TrimSuffix(alg, "/")), hex, true
+}
+
+func sortedDigestKeys(m map[string]digest.Digest) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newArchiveDecompressor detects the compression used by the archive and returns a reader for the uncompressed tar.
+func newArchiveDecompressor(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error reading the archive: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the gzip archive: %w", err)
+		}
+		return gzr, nil
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the zstd archive: %w", err)
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return io.NopCloser(br), nil
+	}
+}
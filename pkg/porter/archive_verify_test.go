@@ -0,0 +1,125 @@
+package porter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/tests"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestArchive creates an archive with a bundle.json and a single blob.
+// The modify function is called before the checksum manifest is written, so that the archive can be tampered with.
+func writeTestArchive(t *testing.T, compression string, withChecksums bool, modify func(tw *archiveTarWriter)) []byte {
+	var buf bytes.Buffer
+	compressor, err := newArchiveCompressor(&buf, compression)
+	require.NoError(t, err)
+	tw := newArchiveTarWriter(compressor)
+
+	require.NoError(t, tw.writeDir("./"))
+	require.NoError(t, tw.writeFile("./bundle.json", []byte(`{"name":"mybuns"}`)))
+	blob := []byte("layer")
+	require.NoError(t, tw.writeDir(archiveLayoutDir+"blobs/sha256/"))
+	require.NoError(t, tw.writeFile(archiveLayoutDir+"blobs/sha256/"+digest.FromBytes(blob).Encoded(), blob))
+	if modify != nil {
+		modify(tw)
+	}
+	if withChecksums {
+		require.NoError(t, tw.writeChecksums())
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, compressor.Close())
+	return buf.Bytes()
+}
+
+func TestArchive_VerifyArchive(t *testing.T) {
+	for _, compression := range ArchiveCompressions {
+		compression := compression
+		t.Run(compression, func(t *testing.T) {
+			result, err := verifyArchive(bytes.NewReader(writeTestArchive(t, compression, true, nil)))
+			require.NoError(t, err)
+			assert.True(t, result.hasChecksums)
+			assert.Equal(t, 2, result.filesVerified)
+		})
+	}
+
+	t.Run("corrupted file", func(t *testing.T) {
+		archive := writeTestArchive(t, ArchiveCompressionNone, true, nil)
+		archive = bytes.Replace(archive, []byte(`"mybuns"`), []byte(`"mybun5"`), 1)
+
+		_, err := verifyArchive(bytes.NewReader(archive))
+		tests.RequireErrorContains(t, err, "bundle.json has checksum")
+	})
+
+	t.Run("corrupted blob", func(t *testing.T) {
+		blob := []byte("other")
+		archive := writeTestArchive(t, ArchiveCompressionGzip, false, func(tw *archiveTarWriter) {
+			require.NoError(t, tw.writeFile(archiveLayoutDir+"blobs/sha256/"+digest.FromString("layer2").Encoded(), blob))
+		})
+
+		_, err := verifyArchive(bytes.NewReader(archive))
+		tests.RequireErrorContains(t, err, "blob artifacts/layout/blobs/sha256/"+digest.FromString("layer2").Encoded()+" has digest "+digest.FromBytes(blob).String())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		archive := writeTestArchive(t, ArchiveCompressionGzip, true, func(tw *archiveTarWriter) {
+			tw.checksums["relocation-mapping.json"] = digest.FromString("{}")
+		})
+
+		_, err := verifyArchive(bytes.NewReader(archive))
+		tests.RequireErrorContains(t, err, "relocation-mapping.json is missing")
+	})
+
+	t.Run("unexpected file", func(t *testing.T) {
+		archive := writeTestArchive(t, ArchiveCompressionGzip, true, func(tw *archiveTarWriter) {
+			require.NoError(t, tw.writeFile("./extra.txt", []byte("surprise")))
+			delete(tw.checksums, "extra.txt")
+		})
+
+		_, err := verifyArchive(bytes.NewReader(archive))
+		tests.RequireErrorContains(t, err, "extra.txt is not listed in checksums.json")
+	})
+
+	t.Run("no checksum manifest", func(t *testing.T) {
+		result, err := verifyArchive(bytes.NewReader(writeTestArchive(t, ArchiveCompressionGzip, false, nil)))
+		require.NoError(t, err)
+		assert.False(t, result.hasChecksums)
+		assert.Equal(t, 1, result.filesVerified, "only the blobs should be verified")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		archive := writeTestArchive(t, ArchiveCompressionGzip, true, nil)
+
+		_, err := verifyArchive(bytes.NewReader(archive[:len(archive)/2]))
+		require.Error(t, err)
+	})
+}
+
+func TestPorter_VerifyArchive(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	require.NoError(t, p.FileSystem.WriteFile("mybuns.tgz", writeTestArchive(t, ArchiveCompressionGzip, true, nil), pkg.FileModeWritable))
+
+	opts := ArchiveVerifyOptions{}
+	require.NoError(t, opts.Validate([]string{"mybuns.tgz"}, p.Porter))
+	require.NoError(t, p.VerifyArchive(context.Background(), opts))
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Verified 2 files in mybuns.tgz")
+}
+
+func TestArchiveVerifyOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := ArchiveVerifyOptions{}
+	err := opts.Validate(nil, p.Porter)
+	require.EqualError(t, err, "the archive file is required")
+
+	err = opts.Validate([]string{"missing.tgz"}, p.Porter)
+	tests.RequireErrorContains(t, err, "unable to access archive missing.tgz")
+}
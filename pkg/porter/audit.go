@@ -0,0 +1,109 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	dtprinter "github.com/carolynvs/datetime-printer"
+)
+
+// AuditListOptions represent options for Porter's audit list command
+type AuditListOptions struct {
+	printer.PrintOptions
+	AllNamespaces bool
+	Namespace     string
+
+	// Resource to list events for, formatted as TYPE or TYPE/NAME, for example creds/mycreds.
+	Resource string
+
+	Skip  int64
+	Limit int64
+
+	resourceType string
+	name         string
+}
+
+// Validate the options provided to Porter's audit list command
+func (o *AuditListOptions) Validate() error {
+	if o.Resource != "" {
+		resourceType, name, _ := strings.Cut(o.Resource, "/")
+		var err error
+		if o.resourceType, err = storage.ParseAuditResourceType(resourceType); err != nil {
+			return fmt.Errorf("invalid --resource %s: %w", o.Resource, err)
+		}
+		if strings.Contains(name, "/") {
+			return fmt.Errorf("invalid --resource %s, the resource must be formatted as TYPE/NAME. Use --namespace to specify the namespace of the resource", o.Resource)
+		}
+		o.name = name
+	}
+
+	return o.ParseFormat()
+}
+
+// GetNamespace returns the namespace of the resources, * when all namespaces are included.
+func (o AuditListOptions) GetNamespace() string {
+	if o.AllNamespaces {
+		return "*"
+	}
+	return o.Namespace
+}
+
+// ListAuditEvents lists the events recorded when resources were modified, most recent first.
+func (p *Porter) ListAuditEvents(ctx context.Context, opts AuditListOptions) ([]storage.AuditEvent, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	events, err := p.Audit.ListAuditEvents(ctx, storage.AuditQuery{
+		Namespace:    opts.GetNamespace(),
+		ResourceType: opts.resourceType,
+		Name:         opts.name,
+		Skip:         opts.Skip,
+		Limit:        opts.Limit,
+	})
+	if err != nil {
+		return nil, span.Error(fmt.Errorf("could not list the audit events: %w", err))
+	}
+	return events, nil
+}
+
+// PrintAuditEvents prints the events recorded when resources were modified.
+func (p *Porter) PrintAuditEvents(ctx context.Context, opts AuditListOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	events, err := p.ListAuditEvents(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, events)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, events)
+	case printer.FormatPlaintext:
+		// have every row use the same "now" starting ... NOW!
+		now := time.Now()
+		tp := dtprinter.DateTimePrinter{
+			Now: func() time.Time { return now },
+		}
+
+		printEventRow :=
+			func(v interface{}) []string {
+				e, ok := v.(storage.AuditEvent)
+				if !ok {
+					return nil
+				}
+				return []string{tp.Format(e.Timestamp), e.Identity, e.Action, e.Resource(), strings.Join(e.Changes, ", "), e.Command}
+			}
+		return printer.PrintTable(p.Out, events, printEventRow,
+			"TIMESTAMP", "IDENTITY", "ACTION", "RESOURCE", "CHANGES", "COMMAND")
+	default:
+		return span.Error(fmt.Errorf("invalid format: %s", opts.Format))
+	}
+}
@@ -0,0 +1,58 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditListOptions_Validate(t *testing.T) {
+	opts := AuditListOptions{Resource: "creds/mycreds"}
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, storage.AuditResourceCredentialSet, opts.resourceType)
+	assert.Equal(t, "mycreds", opts.name)
+
+	opts = AuditListOptions{Resource: "installation"}
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, storage.AuditResourceInstallation, opts.resourceType)
+	assert.Empty(t, opts.name)
+
+	opts = AuditListOptions{Resource: "creds/dev/mycreds"}
+	require.ErrorContains(t, opts.Validate(), "Use --namespace to specify the namespace of the resource")
+
+	opts = AuditListOptions{Resource: "bundle/mybuns"}
+	require.ErrorContains(t, opts.Validate(), "invalid resource type bundle")
+}
+
+func TestPorter_PrintAuditEvents(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := storage.WithCommand(p.RootContext, "porter credentials apply")
+	p.Data.Access.Identity = "alice"
+
+	creds := storage.NewCredentialStore(storage.NewAuditingStore(p.Config, p.TestStore), p.TestSecrets)
+	cs := storage.NewCredentialSet("", "mycreds")
+	require.NoError(t, creds.InsertCredentialSet(ctx, cs))
+	cs.Labels = map[string]string{"team": "red"}
+	require.NoError(t, creds.UpdateCredentialSet(ctx, cs))
+	require.NoError(t, creds.InsertCredentialSet(ctx, storage.NewCredentialSet("", "othercreds")))
+
+	opts := AuditListOptions{Resource: "creds/mycreds", PrintOptions: printer.PrintOptions{Format: printer.FormatPlaintext}}
+	require.NoError(t, opts.Validate())
+	events, err := p.ListAuditEvents(ctx, opts)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, storage.AuditActionUpdate, events[0].Action)
+	assert.Equal(t, storage.AuditActionCreate, events[1].Action)
+
+	require.NoError(t, p.PrintAuditEvents(ctx, opts))
+	output := p.TestConfig.TestContext.GetOutput()
+	assert.Contains(t, output, "CHANGES")
+	assert.Contains(t, output, "alice")
+	assert.Contains(t, output, "credentialset/mycreds")
+	assert.Contains(t, output, "porter credentials apply")
+	assert.NotContains(t, output, "othercreds")
+}
@@ -11,12 +11,15 @@ import (
 	"get.porter.sh/porter/pkg/cnab"
 	configadapter "get.porter.sh/porter/pkg/cnab/config-adapter"
 	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/linter"
 	"get.porter.sh/porter/pkg/manifest"
 	"get.porter.sh/porter/pkg/mixin"
 	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/sbom"
 	"get.porter.sh/porter/pkg/storage"
 	"get.porter.sh/porter/pkg/tracing"
 	"github.com/Masterminds/semver/v3"
+	"github.com/containerd/containerd/platforms"
 	"github.com/opencontainers/go-digest"
 	"golang.org/x/sync/errgroup"
 )
@@ -37,6 +40,12 @@ type BuildOptions struct {
 
 	// parsedCustoms is the parsed set of custom inputs from Customs.
 	parsedCustoms map[string]string
+
+	// SBOM indicates if a software bill of materials should be generated for the invocation image.
+	SBOM bool
+
+	// SBOMFormat is the format of the generated SBOM: spdx or cyclonedx.
+	SBOMFormat string
 }
 
 const BuildDriverDefault = config.BuildDriverBuildkit
@@ -64,6 +73,16 @@ func (o *BuildOptions) Validate(p *Porter) error {
 	// This would be less awkward if we didn't do an automatic build during publish
 	p.Data.BuildDriver = o.Driver
 
+	for _, platform := range o.Platforms {
+		if _, err := platforms.Parse(platform); err != nil {
+			return fmt.Errorf("invalid --platform value %s: %w", platform, err)
+		}
+	}
+
+	if err := o.validateSBOM(); err != nil {
+		return err
+	}
+
 	err := o.parseCustomInputs()
 	if err != nil {
 		return err
@@ -72,6 +91,27 @@ func (o *BuildOptions) Validate(p *Porter) error {
 	return o.bundleFileOptions.Validate(p.Context)
 }
 
+func (o *BuildOptions) validateSBOM() error {
+	if !o.SBOM {
+		if o.SBOMFormat != "" {
+			return errors.New("--sbom-format can only be used with --sbom")
+		}
+		return nil
+	}
+
+	if o.IsMultiPlatform() {
+		return errors.New("--sbom cannot be used when building the invocation image for multiple platforms")
+	}
+
+	if o.SBOMFormat == "" {
+		o.SBOMFormat = sbom.DefaultFormat
+	}
+	if err := sbom.ValidateFormat(o.SBOMFormat); err != nil {
+		return fmt.Errorf("invalid --sbom-format value: %w", err)
+	}
+	return nil
+}
+
 func stringSliceContains(allowedValues []string, value string) bool {
 	for _, allowed := range allowedValues {
 		if value == allowed {
@@ -119,6 +159,10 @@ func (p *Porter) Build(ctx context.Context, opts BuildOptions) error {
 	// the digest logic (to dictate auto-rebuild)
 	m.ManifestPath = opts.File
 
+	if err := p.checkMixinLock(ctx, m, opts.File); err != nil {
+		return span.Error(err)
+	}
+
 	if !opts.NoLint {
 		if err := p.preLint(ctx, opts.File); err != nil {
 			return err
@@ -131,18 +175,26 @@ func (p *Porter) Build(ctx context.Context, opts BuildOptions) error {
 	// bundle.json will *not* be correct until the image is actually pushed
 	// to a registry.  The bundle.json will need to be updated after publishing
 	// and provided just-in-time during bundle execution.
+	// The invocation image build prints its own progress, so the spinner only
+	// covers generating the bundle and the build context
+	spinner := p.NewUI().StartSpinner(fmt.Sprintf("Generating bundle %s", m.Name))
 	if err := p.buildBundle(ctx, m, ""); err != nil {
+		spinner.Stop(err)
 		return span.Error(fmt.Errorf("unable to build bundle: %w", err))
 	}
 
 	generator := build.NewDockerfileGenerator(p.Config, m, p.Templates, p.Mixins)
+	generator.BuildOptions = opts.BuildImageOptions
 
 	if err := generator.PrepareFilesystem(); err != nil {
+		spinner.Stop(err)
 		return span.Error(fmt.Errorf("unable to copy run script, runtimes or mixins: %s", err))
 	}
 	if err := generator.GenerateDockerFile(ctx); err != nil {
+		spinner.Stop(err)
 		return span.Error(fmt.Errorf("unable to generate Dockerfile: %s", err))
 	}
+	spinner.Stop(nil)
 
 	builder := p.GetBuilder(ctx)
 
@@ -151,6 +203,12 @@ func (p *Porter) Build(ctx context.Context, opts BuildOptions) error {
 		return span.Error(fmt.Errorf("unable to build CNAB invocation image: %w", err))
 	}
 
+	if opts.SBOM {
+		if err = p.generateSBOM(ctx, m.Image, opts.SBOMFormat); err != nil {
+			return span.Error(err)
+		}
+	}
+
 	return nil
 }
 
@@ -165,7 +223,7 @@ func (p *Porter) preLint(ctx context.Context, file string) error {
 		return err
 	}
 
-	results, err := p.Lint(ctx, lintOpts)
+	l, results, err := p.lint(ctx, lintOpts)
 	if err != nil {
 		return err
 	}
@@ -174,8 +232,13 @@ func (p *Porter) preLint(ctx context.Context, file string) error {
 		fmt.Fprintln(p.Out, results.String())
 	}
 
-	if results.HasError() {
-		// An error was found during linting, stop and let the user correct it
+	// Stop the build on errors, unless the lint configuration file changes when lint fails
+	failLevel, ok := linter.LevelError, true
+	if l.Config.FailOn != "" {
+		failLevel, ok = l.Config.GetFailLevel()
+	}
+	if ok && results.CountAtLeast(failLevel) > 0 {
+		// A problem was found during linting, stop and let the user correct it
 		return errors.New("lint errors were detected. Rerun with --no-lint ignore the errors")
 	}
 
@@ -239,6 +239,13 @@ func TestBuildOptions_Validate(t *testing.T) {
 		name:      "invalid driver",
 		opts:      BuildOptions{Driver: "missing-driver"},
 		wantError: `invalid --driver value missing-driver`,
+	}, {
+		name: "valid platforms",
+		opts: BuildOptions{BuildImageOptions: build.BuildImageOptions{Platforms: []string{"linux/amd64", "linux/arm64/v8"}}},
+	}, {
+		name:      "invalid platform",
+		opts:      BuildOptions{BuildImageOptions: build.BuildImageOptions{Platforms: []string{"linux/amd64", "linux/arm64/v8/extra"}}},
+		wantError: `invalid --platform value linux/arm64/v8/extra: "linux/arm64/v8/extra": cannot parse platform specifier: invalid argument`,
 	}}
 
 	for _, tc := range testcases {
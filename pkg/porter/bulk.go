@@ -0,0 +1,269 @@
+package porter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// BulkOptions select multiple installations in a namespace, by their labels,
+// so that an action is run against each of them.
+type BulkOptions struct {
+	// All runs the action against every installation in the namespace that
+	// matches Selector, instead of a single named installation.
+	All bool
+
+	// Selector is the unparsed list of KEY=VALUE labels that an installation
+	// must have to be selected.
+	Selector []string
+
+	// MaxConcurrency is the maximum number of installations that are run at
+	// the same time. Defaults to running one installation at a time.
+	MaxConcurrency int
+
+	// Confirmed allows a destructive action to run against every installation
+	// in the namespace, when no selector is specified.
+	Confirmed bool
+}
+
+// IsBulk determines if the action should be run against multiple installations.
+func (o BulkOptions) IsBulk() bool {
+	return o.All
+}
+
+// Validate the bulk options. The installation name may not be specified when
+// running an action against multiple installations.
+func (o BulkOptions) Validate(args []string) error {
+	if !o.All {
+		if len(o.Selector) > 0 {
+			return errors.New("--selector can only be used with --all")
+		}
+		return nil
+	}
+
+	if len(args) > 0 {
+		return fmt.Errorf("an installation name cannot be specified with --all, but %s was received", args)
+	}
+
+	if o.MaxConcurrency < 0 {
+		return errors.New("--max-concurrency must not be negative")
+	}
+
+	return nil
+}
+
+// validateDestructive validates the bulk options of an action that removes
+// installations, such as uninstall. Selecting every installation in the
+// namespace must be confirmed, so that it isn't done by accident.
+func (o BulkOptions) validateDestructive(action string) error {
+	if o.All && len(o.Selector) == 0 && !o.Confirmed {
+		return fmt.Errorf("--all without --selector will %s every installation in the namespace, specify --selector or confirm with --yes", action)
+	}
+	return nil
+}
+
+// validateBulk validates the bundle execution options for an action that runs
+// against multiple installations. The bundle is resolved separately for each
+// installation, so only a bundle reference may be used to change the bundle.
+func (o *BundleExecutionOptions) validateBulk(p *Porter) error {
+	if o.File != "" || o.CNABFile != "" {
+		return errors.New("--file and --cnab-file cannot be used with --all, use --reference instead")
+	}
+
+	if o.Reference != "" {
+		o.ReferenceSet = true
+		if err := o.BundlePullOptions.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.WaitForLock < 0 {
+		return errors.New("--wait-for-lock must not be negative")
+	}
+
+	if err := o.validateLogFormat(); err != nil {
+		return err
+	}
+
+	if err := o.validateRetries(); err != nil {
+		return err
+	}
+
+	if err := o.validateTimeout(); err != nil {
+		return err
+	}
+
+	o.defaultDriver(p)
+	return o.validateDriver(p.Context)
+}
+
+// forInstallation returns a copy of the bundle execution options that targets
+// the specified installation, so that the state resolved while running the
+// action is not shared between installations.
+func (o *BundleExecutionOptions) forInstallation(name string) *BundleExecutionOptions {
+	refOpts := *o.BundleReferenceOptions
+	refOpts.Name = name
+	refOpts.bundleRef = nil
+
+	execOpts := *o
+	execOpts.BundleReferenceOptions = &refOpts
+	execOpts.depParams = nil
+	execOpts.finalParams = nil
+	return &execOpts
+}
+
+// BulkActionResult is the outcome of running an action against one of the selected installations.
+type BulkActionResult struct {
+	// Installation is the namespace/name of the installation.
+	Installation string
+
+	// Error returned by the action, nil when it succeeded.
+	Error error
+}
+
+// executeBulkAction runs an action against each installation selected by the
+// bulk options, with at most MaxConcurrency installations running at the same
+// time, and then prints a report of the results.
+//
+// Each installation is run with its own copy of Porter, which buffers its output
+// so that the output of installations running at the same time is not interleaved.
+// The output of an installation is printed once its action completes.
+func (p *Porter) executeBulkAction(ctx context.Context, namespace string, action string, bulk BulkOptions, run func(ctx context.Context, worker *Porter, name string) error) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	installations, err := p.selectBulkInstallations(ctx, namespace, bulk)
+	if err != nil {
+		return span.Error(err)
+	}
+	if len(installations) == 0 {
+		p.printInfof("No installations matched the selector\n")
+		return nil
+	}
+
+	span.Infof("Running %s against %d installation(s)", action, len(installations))
+
+	results := make([]BulkActionResult, len(installations))
+	maxConcurrency := bulk.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	limiter := make(chan struct{}, maxConcurrency)
+	var outputMu sync.Mutex
+	var wg sync.WaitGroup
+	for i, installation := range installations {
+		wg.Add(1)
+		go func(i int, installation storage.Installation) {
+			defer wg.Done()
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			var output bytes.Buffer
+			worker := p.forBulkWorker(&output)
+			results[i] = BulkActionResult{
+				Installation: installation.String(),
+				Error:        run(ctx, worker, installation.Name),
+			}
+
+			outputMu.Lock()
+			defer outputMu.Unlock()
+			if output.Len() > 0 {
+				fmt.Fprintf(p.Out, "==> %s\n", installation)
+				_, _ = output.WriteTo(p.Out)
+			}
+		}(i, installation)
+	}
+	wg.Wait()
+
+	return p.printBulkActionResults(action, results)
+}
+
+// forBulkWorker returns a copy of Porter that writes its output to the specified
+// writer. The stores, encryptor and runtime are shared with the original and are
+// safe for concurrent use.
+func (p *Porter) forBulkWorker(out io.Writer) *Porter {
+	workerCxt := *p.Config.Context
+	workerCxt.Out = &syncWriter{w: out}
+	workerCxt.Err = workerCxt.Out
+
+	workerCfg := *p.Config
+	workerCfg.Context = &workerCxt
+
+	worker := *p
+	worker.Config = &workerCfg
+	return &worker
+}
+
+// syncWriter serializes writes to a writer that is shared by the
+// goroutines of a worker, such as a bundle and its parallel dependencies.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// selectBulkInstallations lists the installations in the namespace that match
+// the selector. Installations that were uninstalled are not selected.
+func (p *Porter) selectBulkInstallations(ctx context.Context, namespace string, bulk BulkOptions) ([]storage.Installation, error) {
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{
+		Namespace: namespace,
+		Labels:    parseLabels(bulk.Selector),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list installations: %w", err)
+	}
+
+	selected := make([]storage.Installation, 0, len(installations))
+	for _, installation := range installations {
+		if installation.IsUninstalled() {
+			continue
+		}
+		selected = append(selected, installation)
+	}
+	return selected, nil
+}
+
+// printBulkActionResults prints a report of the results of a bulk action and
+// returns an error when the action failed for any installation.
+func (p *Porter) printBulkActionResults(action string, results []BulkActionResult) error {
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+
+	fmt.Fprintln(p.Out)
+	err := printer.PrintTable(p.Out, results,
+		func(v interface{}) []string {
+			result, ok := v.(BulkActionResult)
+			if !ok {
+				return nil
+			}
+			if result.Error != nil {
+				return []string{result.Installation, "failed", result.Error.Error()}
+			}
+			return []string{result.Installation, "succeeded", ""}
+		},
+		"INSTALLATION", "RESULT", "ERROR")
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%s failed for %d of %d installation(s)", action, failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		opts    BulkOptions
+		args    []string
+		wantErr string
+	}{
+		{name: "single installation", opts: BulkOptions{}, args: []string{"mysql"}},
+		{name: "all", opts: BulkOptions{All: true, Selector: []string{"app=web"}, MaxConcurrency: 2}},
+		{name: "selector without all", opts: BulkOptions{Selector: []string{"app=web"}}, wantErr: "--selector can only be used with --all"},
+		{name: "all with name", opts: BulkOptions{All: true}, args: []string{"mysql"}, wantErr: "an installation name cannot be specified with --all"},
+		{name: "negative concurrency", opts: BulkOptions{All: true, MaxConcurrency: -1}, wantErr: "--max-concurrency must not be negative"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate(tc.args)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUninstallOptions_ValidateBulk(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := NewUninstallOptions()
+	opts.All = true
+	opts.Driver = DebugDriver
+	require.EqualError(t, opts.Validate(context.Background(), nil, p.Porter),
+		"--all without --selector will uninstall every installation in the namespace, specify --selector or confirm with --yes")
+
+	opts.Selector = []string{"env=test"}
+	require.NoError(t, opts.Validate(context.Background(), nil, p.Porter), "a selector should not require confirmation")
+
+	opts.Selector = nil
+	opts.Confirmed = true
+	require.NoError(t, opts.Validate(context.Background(), nil, p.Porter), "--yes should confirm uninstalling every installation")
+}
+
+func TestUpgradeOptions_ValidateBulk(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := NewUpgradeOptions()
+	opts.All = true
+	opts.Driver = DebugDriver
+	require.NoError(t, opts.Validate(context.Background(), nil, p.Porter), "a bundle should not be required with --all")
+
+	opts = NewUpgradeOptions()
+	opts.All = true
+	opts.CNABFile = "bundle.json"
+	require.EqualError(t, opts.Validate(context.Background(), nil, p.Porter), "--file and --cnab-file cannot be used with --all, use --reference instead")
+}
+
+func TestBundleExecutionOptions_forInstallation(t *testing.T) {
+	opts := NewBundleExecutionOptions()
+	opts.Namespace = "dev"
+	opts.Params = []string{"color=blue"}
+	opts.finalParams = map[string]interface{}{"color": "blue"}
+
+	copied := opts.forInstallation("web")
+	assert.Equal(t, "web", copied.Name)
+	assert.Equal(t, "dev", copied.Namespace)
+	assert.Equal(t, []string{"color=blue"}, copied.Params)
+	assert.Nil(t, copied.finalParams, "resolved parameters should not be shared between installations")
+	assert.Empty(t, opts.Name, "the original options should not be modified")
+}
+
+func TestPorter_executeBulkAction(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	ctx := context.Background()
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "web1"), func(i *storage.Installation) {
+		i.Labels = map[string]string{"app": "web"}
+	})
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "web2"), func(i *storage.Installation) {
+		i.Labels = map[string]string{"app": "web"}
+	})
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "db"), func(i *storage.Installation) {
+		i.Labels = map[string]string{"app": "db"}
+	})
+
+	var mu sync.Mutex
+	var ran []string
+	bulk := BulkOptions{All: true, Selector: []string{"app=web"}, MaxConcurrency: 2}
+	err := p.executeBulkAction(ctx, "dev", "upgrade", bulk, func(ctx context.Context, worker *Porter, name string) error {
+		mu.Lock()
+		ran = append(ran, name)
+		mu.Unlock()
+
+		// Write output from both workers at the same time, it should not be interleaved
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(worker.Out, "%s line %d\n", name, i)
+			time.Sleep(10 * time.Millisecond)
+		}
+		if name == "web2" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	require.EqualError(t, err, "upgrade failed for 1 of 2 installation(s)")
+
+	sort.Strings(ran)
+	assert.Equal(t, []string{"web1", "web2"}, ran, "only the installations matching the selector should be run")
+	output := p.TestConfig.TestContext.GetOutput()
+	assert.Contains(t, output, "==> dev/web1\nweb1 line 0\nweb1 line 1\nweb1 line 2\n", "the output of each installation should be printed together")
+	assert.Contains(t, output, "==> dev/web2\nweb2 line 0\nweb2 line 1\nweb2 line 2\n", "the output of each installation should be printed together")
+	assert.Contains(t, output, "boom")
+}
@@ -0,0 +1,182 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/bundleindex"
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// BundleIndexGenerateOptions are the options for generating a bundle index from the bundles in a registry.
+type BundleIndexGenerateOptions struct {
+	// Namespace is the registry, optionally followed by a repository prefix, that contains the bundles,
+	// for example localhost:5000/getporter.
+	Namespace string
+
+	// Output is the path where the index is written.
+	Output string
+
+	// Publish is the reference where the index is pushed as an OCI artifact.
+	Publish string
+
+	// IncludePrereleases lists the latest prerelease version of a bundle when it is newer than the latest release.
+	IncludePrereleases bool
+
+	// InsecureRegistry allows connecting to an unsecured registry.
+	InsecureRegistry bool
+}
+
+// Validate the bundle index generate options.
+func (o *BundleIndexGenerateOptions) Validate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("a registry namespace must be specified, for example localhost:5000/getporter")
+	} else if len(args) > 1 {
+		return fmt.Errorf("only one positional argument may be specified, the registry namespace, but multiple were received: %s", args)
+	}
+	o.Namespace = strings.TrimSuffix(args[0], "/")
+
+	if _, err := name.NewRegistry(o.registry()); err != nil {
+		return fmt.Errorf("invalid registry namespace %s: %w", o.Namespace, err)
+	}
+
+	if o.Publish != "" {
+		if _, err := cnab.ParseOCIReference(o.Publish); err != nil {
+			return fmt.Errorf("invalid --publish reference %s: %w", o.Publish, err)
+		}
+	}
+
+	return nil
+}
+
+// registry returns the registry host from the namespace.
+func (o *BundleIndexGenerateOptions) registry() string {
+	registry, _, _ := strings.Cut(o.Namespace, "/")
+	return registry
+}
+
+// repositoryPrefix returns the repository path from the namespace, without the registry host.
+func (o *BundleIndexGenerateOptions) repositoryPrefix() string {
+	_, prefix, _ := strings.Cut(o.Namespace, "/")
+	return prefix
+}
+
+// inNamespace determines if a repository in the registry is in the namespace.
+func (o *BundleIndexGenerateOptions) inNamespace(repo string) bool {
+	prefix := o.repositoryPrefix()
+	return prefix == "" || repo == prefix || strings.HasPrefix(repo, prefix+"/")
+}
+
+// GenerateBundleIndex lists the latest version of every bundle in a registry namespace in a bundle index,
+// then writes the index to a file, publishes it to a registry, or prints it.
+func (p *Porter) GenerateBundleIndex(ctx context.Context, opts BundleIndexGenerateOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	regOpts := cnabtooci.RegistryOptions{
+		InsecureRegistry: opts.InsecureRegistry,
+		RegistryTLS:      p.Data.RegistryTLS,
+	}
+
+	registry := opts.registry()
+	repos, err := p.Registry.ListRepositories(ctx, registry, regOpts)
+	if err != nil {
+		return log.Error(err)
+	}
+	sort.Strings(repos)
+
+	var listings []bundleindex.Listing
+	for _, repo := range repos {
+		if !opts.inNamespace(repo) {
+			continue
+		}
+
+		listing, ok, err := p.getBundleIndexListing(ctx, registry+"/"+repo, opts, regOpts)
+		if err != nil {
+			return log.Error(err)
+		}
+		if ok {
+			listings = append(listings, listing)
+		}
+	}
+
+	idx := bundleindex.NewIndex(listings)
+	data, err := idx.Marshal()
+	if err != nil {
+		return log.Error(err)
+	}
+
+	if opts.Output == "" && opts.Publish == "" {
+		fmt.Fprintln(p.Out, string(data))
+		return nil
+	}
+
+	if opts.Output != "" {
+		if err = p.FileSystem.WriteFile(opts.Output, data, pkg.FileModeWritable); err != nil {
+			return log.Error(fmt.Errorf("error writing the bundle index to %s: %w", opts.Output, err))
+		}
+		p.printInfof("Wrote a bundle index with %d bundles to %s\n", len(idx.Bundles), opts.Output)
+	}
+
+	if opts.Publish != "" {
+		ref, err := cnab.ParseOCIReference(opts.Publish)
+		if err != nil {
+			return log.Error(err)
+		}
+		craneOpts, err := cnabtooci.GetCraneOptions(p.FileSystem, regOpts, ref.Registry())
+		if err != nil {
+			return log.Error(err)
+		}
+		digest, err := bundleindex.Push(ctx, idx, ref.String(), craneOpts...)
+		if err != nil {
+			return log.Error(err)
+		}
+		p.printInfof("Published a bundle index with %d bundles to %s@%s\n", len(idx.Bundles), ref.Repository(), digest)
+	}
+
+	return nil
+}
+
+// getBundleIndexListing creates a listing for the latest version of the bundle in a repository.
+// Repositories that do not have a version tag, or that do not contain a bundle, are skipped.
+func (p *Porter) getBundleIndexListing(ctx context.Context, repo string, opts BundleIndexGenerateOptions, regOpts cnabtooci.RegistryOptions) (bundleindex.Listing, bool, error) {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	ref, err := cnab.ParseOCIReference(repo)
+	if err != nil {
+		return bundleindex.Listing{}, false, fmt.Errorf("invalid repository %s: %w", repo, err)
+	}
+
+	tags, err := p.Registry.ListTags(ctx, ref, regOpts)
+	if err != nil {
+		return bundleindex.Listing{}, false, err
+	}
+
+	tag, ok := bundleindex.LatestVersionTag(tags, opts.IncludePrereleases)
+	if !ok {
+		log.Debugf("Skipping %s because it does not have a version tag", repo)
+		return bundleindex.Listing{}, false, nil
+	}
+
+	ref, err = ref.WithTag(tag)
+	if err != nil {
+		return bundleindex.Listing{}, false, err
+	}
+
+	bunRef, err := p.Registry.PullBundle(ctx, ref, regOpts)
+	if err != nil {
+		log.Debugf("Skipping %s because it is not a bundle: %s", ref, err.Error())
+		return bundleindex.Listing{}, false, nil
+	}
+
+	log.Infof("Listing %s", ref)
+	return bundleindex.NewListing(ref, bunRef.Definition), true, nil
+}
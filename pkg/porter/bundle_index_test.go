@@ -0,0 +1,135 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"get.porter.sh/porter/pkg/bundleindex"
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleIndexGenerateOptions_Validate(t *testing.T) {
+	t.Run("namespace", func(t *testing.T) {
+		opts := BundleIndexGenerateOptions{}
+		require.NoError(t, opts.Validate([]string{"localhost:5000/getporter/"}))
+		assert.Equal(t, "localhost:5000/getporter", opts.Namespace)
+		assert.Equal(t, "localhost:5000", opts.registry())
+		assert.Equal(t, "getporter", opts.repositoryPrefix())
+	})
+
+	t.Run("registry", func(t *testing.T) {
+		opts := BundleIndexGenerateOptions{}
+		require.NoError(t, opts.Validate([]string{"localhost:5000"}))
+		assert.Equal(t, "localhost:5000", opts.registry())
+		assert.Empty(t, opts.repositoryPrefix())
+	})
+
+	t.Run("missing namespace", func(t *testing.T) {
+		opts := BundleIndexGenerateOptions{}
+		require.ErrorContains(t, opts.Validate(nil), "a registry namespace must be specified")
+	})
+
+	t.Run("invalid publish reference", func(t *testing.T) {
+		opts := BundleIndexGenerateOptions{Publish: "example.com/INVALID"}
+		require.ErrorContains(t, opts.Validate([]string{"example.com/bundles"}), "invalid --publish reference")
+	})
+}
+
+func TestBundleIndexGenerateOptions_inNamespace(t *testing.T) {
+	opts := BundleIndexGenerateOptions{Namespace: "example.com/getporter"}
+	assert.True(t, opts.inNamespace("getporter"))
+	assert.True(t, opts.inNamespace("getporter/mysql"))
+	assert.False(t, opts.inNamespace("getporter-archive/mysql"))
+	assert.False(t, opts.inNamespace("mysql"))
+
+	opts = BundleIndexGenerateOptions{Namespace: "example.com"}
+	assert.True(t, opts.inNamespace("mysql"))
+}
+
+func TestPorter_GenerateBundleIndex(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestRegistry.MockListRepositories = func(ctx context.Context, registry string, opts cnabtooci.RegistryOptions) ([]string, error) {
+		assert.Equal(t, "example.com", registry)
+		assert.True(t, opts.InsecureRegistry)
+		return []string{"getporter/wordpress", "getporter/mysql", "getporter/nginx", "getporter/untagged", "other/redis"}, nil
+	}
+	p.TestRegistry.MockListTags = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) ([]string, error) {
+		switch ref.Repository() {
+		case "example.com/getporter/mysql":
+			return []string{"v0.1.0", "v0.2.0", "v0.3.0-rc.1", "latest"}, nil
+		case "example.com/getporter/untagged":
+			return []string{"latest"}, nil
+		default:
+			return []string{"v1.0.0"}, nil
+		}
+	}
+	p.TestRegistry.MockPullBundle = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+		var bun bundle.Bundle
+		switch ref.Repository() {
+		case "example.com/getporter/mysql":
+			bun = bundle.Bundle{Name: "mysql", Version: ref.Tag()[1:], Description: "A MySQL database", Keywords: []string{"database"}}
+		case "example.com/getporter/wordpress":
+			bun = bundle.Bundle{Name: "wordpress", Version: "1.0.0"}
+		default:
+			return cnab.BundleReference{}, errors.New("not a bundle")
+		}
+		return cnab.BundleReference{Reference: ref, Definition: cnab.NewBundle(bun)}, nil
+	}
+
+	opts := BundleIndexGenerateOptions{InsecureRegistry: true, Output: "index.json"}
+	require.NoError(t, opts.Validate([]string{"example.com/getporter"}))
+	require.NoError(t, p.GenerateBundleIndex(ctx, opts))
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Wrote a bundle index with 2 bundles to index.json")
+
+	data, err := p.FileSystem.ReadFile("index.json")
+	require.NoError(t, err)
+	idx, err := bundleindex.ParseIndex(data)
+	require.NoError(t, err)
+	assert.Equal(t, []bundleindex.Listing{
+		{Name: "mysql", Description: "A MySQL database", Version: "0.2.0", Reference: "example.com/getporter/mysql:v0.2.0", Keywords: []string{"database"}},
+		{Name: "wordpress", Version: "1.0.0", Reference: "example.com/getporter/wordpress:v1.0.0"},
+	}, idx.Bundles)
+}
+
+func TestPorter_GenerateBundleIndex_Publish(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.TestRegistry.MockListRepositories = func(ctx context.Context, registry string, opts cnabtooci.RegistryOptions) ([]string, error) {
+		return []string{"mysql"}, nil
+	}
+	p.TestRegistry.MockListTags = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) ([]string, error) {
+		return []string{"v1.0.0"}, nil
+	}
+	p.TestRegistry.MockPullBundle = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+		return cnab.BundleReference{Reference: ref, Definition: cnab.NewBundle(bundle.Bundle{Name: "mysql", Version: "1.0.0"})}, nil
+	}
+
+	indexRef := u.Host + "/bundle-index:latest"
+	opts := BundleIndexGenerateOptions{InsecureRegistry: true, Publish: indexRef}
+	require.NoError(t, opts.Validate([]string{u.Host}))
+	require.NoError(t, p.GenerateBundleIndex(ctx, opts))
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Published a bundle index with 1 bundles to "+u.Host+"/bundle-index@sha256:")
+
+	idx, err := bundleindex.NewOCISource("internal", indexRef, crane.Insecure).Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, idx.Bundles, 1)
+	assert.Equal(t, u.Host+"/mysql:v1.0.0", idx.Bundles[0].Reference)
+}
@@ -0,0 +1,216 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/cache"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/tracing"
+	dtprinter "github.com/carolynvs/datetime-printer"
+	"github.com/docker/go-units"
+)
+
+// CacheListOptions represent options for Porter's cache list command
+type CacheListOptions struct {
+	printer.PrintOptions
+}
+
+// Validate the options provided to Porter's cache list command
+func (o *CacheListOptions) Validate() error {
+	return o.ParseFormat()
+}
+
+// CachePruneOptions represent options for Porter's cache prune command
+type CachePruneOptions struct {
+	// MaxSize is the maximum size of the cache, for example 500MB or 2GB.
+	// Defaults to cache.max-size in the Porter configuration file.
+	MaxSize string
+
+	// DryRun lists the bundles that would be removed without removing them.
+	DryRun bool
+
+	// maxSize is the parsed MaxSize.
+	maxSize int64
+}
+
+// Validate the options provided to Porter's cache prune command
+func (o *CachePruneOptions) Validate() error {
+	var err error
+	o.maxSize, err = config.ParseCacheSize(o.MaxSize)
+	if err != nil {
+		return fmt.Errorf("invalid --max-size: %w", err)
+	}
+	return nil
+}
+
+// DisplayCachedBundle is a bundle in the local bundle cache.
+type DisplayCachedBundle struct {
+	// Reference of the cached bundle.
+	Reference string `json:"reference" yaml:"reference" toml:"reference"`
+
+	// Digest of the cached bundle.
+	Digest string `json:"digest" yaml:"digest" toml:"digest"`
+
+	// Size of the cached files in bytes.
+	Size int64 `json:"size" yaml:"size" toml:"size"`
+
+	// LastUsed is when the bundle was cached, or last resolved from the cache.
+	LastUsed time.Time `json:"lastUsed" yaml:"lastUsed" toml:"lastUsed"`
+}
+
+// NewDisplayCachedBundle converts a cache entry into its display representation.
+func NewDisplayCachedBundle(entry cache.Entry) DisplayCachedBundle {
+	return DisplayCachedBundle{
+		Reference: entry.Reference.String(),
+		Digest:    entry.Digest.String(),
+		Size:      entry.Size,
+		LastUsed:  entry.LastUsed,
+	}
+}
+
+// ListCachedBundles lists the bundles in the local bundle cache, starting with
+// the most recently used bundle.
+func (p *Porter) ListCachedBundles(ctx context.Context) ([]DisplayCachedBundle, error) {
+	_, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	entries, err := p.Cache.ListBundles()
+	if err != nil {
+		return nil, span.Error(fmt.Errorf("could not list the cached bundles: %w", err))
+	}
+
+	results := make([]DisplayCachedBundle, len(entries))
+	for i, entry := range entries {
+		results[i] = NewDisplayCachedBundle(entry)
+	}
+	return results, nil
+}
+
+// PrintCachedBundles prints the bundles in the local bundle cache, and the total size of the cache.
+func (p *Porter) PrintCachedBundles(ctx context.Context, opts CacheListOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	bundles, err := p.ListCachedBundles(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, bundles)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, bundles)
+	case printer.FormatPlaintext:
+		// have every row use the same "now" starting ... NOW!
+		now := time.Now()
+		tp := dtprinter.DateTimePrinter{
+			Now: func() time.Time { return now },
+		}
+
+		var total int64
+		for _, b := range bundles {
+			total += b.Size
+		}
+
+		printCachedBundleRow :=
+			func(v interface{}) []string {
+				b, ok := v.(DisplayCachedBundle)
+				if !ok {
+					return nil
+				}
+				return []string{b.Reference, b.Digest, units.BytesSize(float64(b.Size)), tp.Format(b.LastUsed)}
+			}
+		err = printer.PrintTable(p.Out, bundles, printCachedBundleRow,
+			"REFERENCE", "DIGEST", "SIZE", "LAST USED")
+		if err != nil {
+			return span.Error(err)
+		}
+		contentSize, err := p.ContentStore.Size()
+		if err != nil {
+			return span.Error(err)
+		}
+		p.printInfof("\n%d cached bundle(s) using %s, and %s of cached image layers\n",
+			len(bundles), units.BytesSize(float64(total)), units.BytesSize(float64(contentSize)))
+		return nil
+	default:
+		return span.Error(fmt.Errorf("invalid format: %s", opts.Format))
+	}
+}
+
+// PruneCache removes the least recently used bundles from the local bundle
+// cache until it is no larger than the maximum size.
+func (p *Porter) PruneCache(ctx context.Context, opts CachePruneOptions) error {
+	_, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	// The flag overrides the maximum size from the config file
+	maxSize, err := p.Config.Data.Cache.GetMaxSize()
+	if err != nil {
+		return span.Error(fmt.Errorf("invalid cache.max-size in the Porter configuration file: %w", err))
+	}
+	if opts.maxSize > 0 {
+		maxSize = opts.maxSize
+	}
+	if maxSize == 0 {
+		return span.Error(errors.New("no maximum size was specified, set --max-size or define cache.max-size in the Porter configuration file"))
+	}
+
+	entries, err := p.Cache.ListBundles()
+	if err != nil {
+		return span.Error(fmt.Errorf("could not list the cached bundles: %w", err))
+	}
+
+	evicted := cache.SelectEvictions(entries, maxSize)
+	verb := "Removed"
+	if opts.DryRun {
+		verb = "Would remove"
+	}
+	var freed int64
+	for _, entry := range evicted {
+		if !opts.DryRun {
+			if err = p.Cache.RemoveBundle(entry.Reference); err != nil {
+				return span.Error(err)
+			}
+		}
+		freed += entry.Size
+		fmt.Fprintf(p.Out, "%s cached bundle %s (%s)\n", verb, entry.Reference, units.BytesSize(float64(entry.Size)))
+	}
+	fmt.Fprintf(p.Out, "%s %d bundle(s), freeing %s\n", verb, len(evicted), units.BytesSize(float64(freed)))
+	return nil
+}
+
+// ClearCache removes every bundle from the local bundle cache, and the image
+// layers saved to the content store.
+func (p *Porter) ClearCache(ctx context.Context) error {
+	_, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	entries, err := p.Cache.ListBundles()
+	if err != nil {
+		return span.Error(fmt.Errorf("could not list the cached bundles: %w", err))
+	}
+	var freed int64
+	for _, entry := range entries {
+		freed += entry.Size
+	}
+
+	contentSize, err := p.ContentStore.Size()
+	if err != nil {
+		return span.Error(err)
+	}
+	freed += contentSize
+
+	if err = p.Cache.Clear(); err != nil {
+		return span.Error(err)
+	}
+	if err = p.ContentStore.Clear(); err != nil {
+		return span.Error(err)
+	}
+	p.printInfof("Removed %d bundle(s) and the cached image layers, freeing %s\n", len(entries), units.BytesSize(float64(freed)))
+	return nil
+}
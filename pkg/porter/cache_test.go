@@ -0,0 +1,118 @@
+package porter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheTestBundles stores bundles in the cache, the first bundle being the most recently used.
+func cacheTestBundles(t *testing.T, p *TestPorter, refs ...string) {
+	cacheDir, err := p.Cache.GetCacheDir()
+	require.NoError(t, err)
+
+	now := time.Now()
+	for i, ref := range refs {
+		cb, err := p.Cache.StoreBundle(context.Background(), cnab.BundleReference{Reference: cnab.MustParseOCIReference(ref)})
+		require.NoError(t, err, "StoreBundle failed")
+
+		cb.SetCacheDir(cacheDir)
+		lastUsed := now.Add(-time.Duration(i) * time.Hour)
+		require.NoError(t, p.FileSystem.Chtimes(cb.BuildMetadataPath(), lastUsed, lastUsed))
+	}
+}
+
+func TestPorter_PrintCachedBundles(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	cacheTestBundles(t, p, "example.com/mysql:v0.2.0", "example.com/wordpress:v1.0.0")
+
+	opts := CacheListOptions{}
+	opts.RawFormat = "plaintext"
+	require.NoError(t, opts.Validate())
+	require.NoError(t, p.PrintCachedBundles(context.Background(), opts))
+
+	output := p.TestConfig.TestContext.GetOutput()
+	assert.Regexp(t, `(?s)REFERENCE\s+DIGEST\s+SIZE\s+LAST USED.*example.com/mysql:v0.2.0.*example.com/wordpress:v1.0.0`, output)
+	assert.Contains(t, output, "2 cached bundle(s) using")
+	assert.Contains(t, output, "0B of cached image layers")
+}
+
+func TestPorter_PruneCache(t *testing.T) {
+	t.Run("no max size", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := CachePruneOptions{}
+		require.NoError(t, opts.Validate())
+		require.ErrorContains(t, p.PruneCache(context.Background(), opts), "no maximum size was specified")
+	})
+
+	t.Run("invalid max size", func(t *testing.T) {
+		opts := CachePruneOptions{MaxSize: "big"}
+		require.ErrorContains(t, opts.Validate(), "invalid --max-size")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		cacheTestBundles(t, p, "example.com/mysql:v0.2.0", "example.com/wordpress:v1.0.0")
+
+		opts := CachePruneOptions{MaxSize: "1", DryRun: true}
+		require.NoError(t, opts.Validate())
+		require.NoError(t, p.PruneCache(context.Background(), opts))
+
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Would remove cached bundle example.com/wordpress:v1.0.0")
+		refs, err := p.Cache.ListReferences()
+		require.NoError(t, err)
+		assert.Len(t, refs, 2, "no bundles should be removed in a dry run")
+	})
+
+	t.Run("least recently used", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		cacheTestBundles(t, p, "example.com/mysql:v0.2.0", "example.com/wordpress:v1.0.0")
+		p.Data.Cache.MaxSize = "1"
+
+		opts := CachePruneOptions{}
+		require.NoError(t, opts.Validate())
+		require.NoError(t, p.PruneCache(context.Background(), opts))
+
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Removed 1 bundle(s)")
+		refs, err := p.Cache.ListReferences()
+		require.NoError(t, err)
+		require.Len(t, refs, 1)
+		assert.Equal(t, "example.com/mysql:v0.2.0", refs[0].String(), "the most recently used bundle should be kept")
+	})
+}
+
+func TestPorter_ClearCache(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	cacheTestBundles(t, p, "example.com/mysql:v0.2.0", "example.com/wordpress:v1.0.0")
+	layer := []byte("layer")
+	rc, err := p.ContentStore.Fetch(digest.FromBytes(layer), func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(layer)), nil
+	})
+	require.NoError(t, err)
+	_, err = io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+
+	require.NoError(t, p.ClearCache(context.Background()))
+
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Removed 2 bundle(s) and the cached image layers")
+	refs, err := p.Cache.ListReferences()
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+	size, err := p.ContentStore.Size()
+	require.NoError(t, err)
+	assert.Zero(t, size, "the content store should have been cleared")
+}
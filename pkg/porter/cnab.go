@@ -30,7 +30,8 @@ type bundleFileOptions struct {
 	// CNABFile is the path to the bundle.json file. Cannot be specified at the same time as the porter manifest or a tag.
 	CNABFile string
 
-	// RelocationMapping is the path to the relocation-mapping.json file, if one exists. Populated only for published bundles
+	// RelocationMapping is the path to the relocation-mapping.json file, if one exists. Populated for published bundles,
+	// or when specified with --relocation-mapping, for example with the file written by porter copy --relocation-output.
 	RelocationMapping string
 
 	// ReferenceSet indicates whether a bundle reference is present, to determine whether or not to default bundle files
@@ -43,6 +44,15 @@ type bundleFileOptions struct {
 func (o *bundleFileOptions) Validate(cxt *portercontext.Context) error {
 	var err error
 
+	if o.RelocationMapping != "" {
+		// Resolve the path before changing to the build context directory below
+		relocationMapping := cxt.FileSystem.Abs(o.RelocationMapping)
+		if _, err = cxt.FileSystem.Stat(relocationMapping); err != nil {
+			return fmt.Errorf("unable to access --relocation-mapping %s: %w", o.RelocationMapping, err)
+		}
+		o.RelocationMapping = relocationMapping
+	}
+
 	if o.ReferenceSet {
 		return nil
 	}
@@ -97,6 +97,24 @@ func TestSharedOptions_validateBundleJson(t *testing.T) {
 	}
 }
 
+func TestSharedOptions_validateRelocationMapping(t *testing.T) {
+	cxt := portercontext.NewTestContext(t)
+	cxt.FileSystem.Create("relocations/mybuns.json")
+
+	t.Run("file exists", func(t *testing.T) {
+		opts := bundleFileOptions{RelocationMapping: "relocations/mybuns.json", ReferenceSet: true}
+		require.NoError(t, opts.Validate(cxt.Context))
+		assert.Equal(t, "/relocations/mybuns.json", opts.RelocationMapping, "the path should be resolved to an absolute path")
+	})
+
+	t.Run("file does not exist", func(t *testing.T) {
+		opts := bundleFileOptions{RelocationMapping: "missing.json", ReferenceSet: true}
+		err := opts.Validate(cxt.Context)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to access --relocation-mapping missing.json")
+	})
+}
+
 func Test_bundleFileOptions(t *testing.T) {
 	testcases := []struct {
 		name         string
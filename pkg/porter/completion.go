@@ -0,0 +1,70 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/storage"
+)
+
+// CompleteInstallationNames returns the names of the installations in the
+// namespace that start with toComplete, for shell completion.
+func (p *Porter) CompleteInstallationNames(ctx context.Context, namespace string, toComplete string) ([]string, error) {
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{Namespace: namespace})
+	if err != nil {
+		return nil, fmt.Errorf("could not list installations: %w", err)
+	}
+
+	names := make([]string, len(installations))
+	for i, inst := range installations {
+		names[i] = inst.Name
+	}
+	return filterCompletions(names, toComplete), nil
+}
+
+// CompleteCredentialSetNames returns the names of the credential sets in the
+// namespace that start with toComplete, for shell completion.
+func (p *Porter) CompleteCredentialSetNames(ctx context.Context, namespace string, toComplete string) ([]string, error) {
+	sets, err := p.Credentials.ListCredentialSets(ctx, storage.ListOptions{Namespace: namespace})
+	if err != nil {
+		return nil, fmt.Errorf("could not list credential sets: %w", err)
+	}
+
+	names := make([]string, len(sets))
+	for i, cs := range sets {
+		names[i] = cs.Name
+	}
+	return filterCompletions(names, toComplete), nil
+}
+
+// CompleteReferences returns the references of recently used bundles that
+// start with toComplete, for shell completion. The references are read
+// from the bundle cache, starting with the most recently pulled bundle.
+func (p *Porter) CompleteReferences(toComplete string) ([]string, error) {
+	refs, err := p.Cache.ListReferences()
+	if err != nil {
+		return nil, fmt.Errorf("could not list the cached bundles: %w", err)
+	}
+
+	var results []string
+	for _, ref := range refs {
+		if strings.HasPrefix(ref.String(), toComplete) {
+			results = append(results, ref.String())
+		}
+	}
+	return results, nil
+}
+
+// filterCompletions returns the sorted values that start with toComplete.
+func filterCompletions(values []string, toComplete string) []string {
+	var results []string
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) {
+			results = append(results, value)
+		}
+	}
+	sort.Strings(results)
+	return results
+}
@@ -0,0 +1,75 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_CompleteInstallationNames(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("", "shared-mysql"))
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "wordpress"))
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "mysql"))
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "wordpress-canary"))
+
+	names, err := p.CompleteInstallationNames(ctx, "dev", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mysql", "wordpress", "wordpress-canary"}, names)
+
+	names, err = p.CompleteInstallationNames(ctx, "dev", "word")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wordpress", "wordpress-canary"}, names)
+}
+
+func TestPorter_CompleteCredentialSetNames(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestCredentials.InsertCredentialSet(ctx, storage.NewCredentialSet("", "shared-mysql"))
+	p.TestCredentials.InsertCredentialSet(ctx, storage.NewCredentialSet("dev", "azure"))
+	p.TestCredentials.InsertCredentialSet(ctx, storage.NewCredentialSet("dev", "aws"))
+	p.TestCredentials.InsertCredentialSet(ctx, storage.NewCredentialSet("dev", "kubernetes"))
+
+	names, err := p.CompleteCredentialSetNames(ctx, "dev", "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws", "azure"}, names)
+}
+
+func TestPorter_CompleteReferences(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	for _, ref := range []string{"ghcr.io/getporter/examples/porter-hello:v0.2.0", "localhost:5000/mybuns:v1.0.0"} {
+		bunRef := cnab.BundleReference{
+			Reference:  cnab.MustParseOCIReference(ref),
+			Definition: cnab.NewBundle(bundle.Bundle{Name: "mybuns"}),
+		}
+		_, err := p.Cache.StoreBundle(context.Background(), bunRef)
+		require.NoError(t, err)
+	}
+
+	refs, err := p.CompleteReferences("ghcr.io/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghcr.io/getporter/examples/porter-hello:v0.2.0"}, refs)
+
+	refs, err = p.CompleteReferences("")
+	require.NoError(t, err)
+	assert.Len(t, refs, 2)
+}
+
+func TestFilterCompletions(t *testing.T) {
+	got := filterCompletions([]string{"wordpress", "mysql", "wordpress-canary"}, "word")
+	assert.Equal(t, []string{"wordpress", "wordpress-canary"}, got)
+
+	assert.Empty(t, filterCompletions([]string{"mysql"}, "word"))
+}
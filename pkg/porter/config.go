@@ -0,0 +1,196 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	cnabprovider "get.porter.sh/porter/pkg/cnab/provider"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/plugins"
+	"get.porter.sh/porter/pkg/printer"
+	secretsplugins "get.porter.sh/porter/pkg/secrets/plugins"
+	storageplugins "get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ConfigValidateOptions represent options for Porter's config validate command
+type ConfigValidateOptions struct {
+	// Flags are the names of the flags defined by Porter's commands.
+	// Any flag may be set in the config file.
+	Flags []string
+}
+
+// ConfigViewOptions represent options for Porter's config view command
+type ConfigViewOptions struct {
+	printer.PrintOptions
+
+	// Resolved prints the effective configuration, instead of the settings in the config file.
+	Resolved bool
+}
+
+// Validate the options provided to Porter's config view command
+func (o *ConfigViewOptions) Validate() error {
+	return o.ParseFormat()
+}
+
+// ViewConfig prints the configuration, with sensitive values masked.
+func (p *Porter) ViewConfig(ctx context.Context, opts ConfigViewOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	settings, err := p.Config.GetSettings(opts.Resolved)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, settings)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, settings)
+	default:
+		return span.Error(fmt.Errorf("invalid format: %s", opts.Format))
+	}
+}
+
+// ValidateConfig checks the configuration for settings that are not
+// recognized, have the wrong data type or are not allowed, and checks the
+// configuration of each storage and secrets plugin against the plugin.
+func (p *Porter) ValidateConfig(ctx context.Context, opts ConfigValidateOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	var problems *multierror.Error
+	if err := p.Config.Validate(opts.Flags); err != nil {
+		problems = multierror.Append(problems, err)
+	}
+
+	if p.Data.RuntimeDriver != "" {
+		if _, err := cnabprovider.LookupDriver(p.Context, p.Data.RuntimeDriver); err != nil {
+			problems = multierror.Append(problems, fmt.Errorf("invalid runtime-driver: %w", err))
+		}
+	}
+
+	if err := p.validatePlugins(ctx, "", p.Data); err != nil {
+		problems = multierror.Append(problems, err)
+	}
+
+	// The current context is already applied to p.Data, check the plugins defined by the other contexts
+	for _, c := range p.Data.Contexts {
+		if c.Name == p.Data.CurrentContext {
+			continue
+		}
+
+		// Problems with the data types of the settings were reported above
+		if d, err := c.GetData(); err == nil {
+			if err = p.validatePlugins(ctx, fmt.Sprintf("context %s: ", c.Name), d); err != nil {
+				problems = multierror.Append(problems, err)
+			}
+		}
+	}
+
+	if err := problems.ErrorOrNil(); err != nil {
+		return span.Error(fmt.Errorf("the configuration is not valid: %w", err))
+	}
+
+	if p.ConfigFilePath == "" {
+		p.printInfof("A config file was not found, the default configuration is valid\n")
+	} else {
+		p.printInfof("The config file %s is valid\n", p.ConfigFilePath)
+	}
+	return nil
+}
+
+// validatePlugins checks the plugins defined in the storage and secrets sections.
+func (p *Porter) validatePlugins(ctx context.Context, prefix string, d config.Data) error {
+	var problems *multierror.Error
+
+	for _, s := range d.StoragePlugins {
+		if err := p.validatePlugin(ctx, storageplugins.PluginInterface, s.PluginConfig); err != nil {
+			problems = appendProblems(problems, prefix+"storage "+s.Name, err)
+		}
+	}
+	if d.DefaultStoragePlugin != "" {
+		if err := p.validatePlugin(ctx, storageplugins.PluginInterface, config.PluginConfig{PluginSubKey: d.DefaultStoragePlugin}); err != nil {
+			problems = appendProblems(problems, prefix+"default-storage-plugin", err)
+		}
+	}
+
+	for _, s := range d.SecretsPlugin {
+		if err := p.validatePlugin(ctx, secretsplugins.PluginInterface, s.PluginConfig); err != nil {
+			problems = appendProblems(problems, prefix+"secrets "+s.Name, err)
+		}
+	}
+	if d.DefaultSecretsPlugin != "" {
+		if err := p.validatePlugin(ctx, secretsplugins.PluginInterface, config.PluginConfig{PluginSubKey: d.DefaultSecretsPlugin}); err != nil {
+			problems = appendProblems(problems, prefix+"default-secrets-plugin", err)
+		}
+	}
+
+	return problems.ErrorOrNil()
+}
+
+// appendProblems adds each problem found in err to problems, prefixed with what was checked.
+func appendProblems(problems *multierror.Error, prefix string, err error) *multierror.Error {
+	if merr, ok := err.(*multierror.Error); ok {
+		for _, problem := range merr.Errors {
+			problems = multierror.Append(problems, fmt.Errorf("%s: %w", prefix, problem))
+		}
+		return problems
+	}
+	return multierror.Append(problems, fmt.Errorf("%s: %w", prefix, err))
+}
+
+// validatePlugin checks that the plugin is available, and when the plugin is
+// provided by Porter, that its configuration matches the plugin's configuration.
+func (p *Porter) validatePlugin(ctx context.Context, pluginInterface string, cfg config.PluginConfig) error {
+	if cfg.PluginSubKey == "" {
+		// Reported when the configuration is validated
+		return nil
+	}
+
+	key, err := plugins.ParsePluginKey(cfg.PluginSubKey)
+	if err != nil {
+		return err
+	}
+	key.Interface = pluginInterface
+
+	if !key.IsInternal {
+		metadata, err := p.Plugins.GetMetadata(ctx, key.Binary)
+		if err != nil {
+			return fmt.Errorf("the %s plugin is not installed, install it with porter plugins install %s: %w", key.Binary, key.Binary, err)
+		}
+		if pluginMetadata, ok := metadata.(*plugins.Metadata); ok {
+			for _, impl := range pluginMetadata.Implementations {
+				if impl.Type == key.Interface && impl.Name == key.Implementation {
+					return nil
+				}
+			}
+			return fmt.Errorf("the %s plugin does not provide a %s plugin named %s", key.Binary, key.Interface, key.Implementation)
+		}
+		return nil
+	}
+
+	internalPlugin, ok := internalPlugins[key.String()]
+	if !ok {
+		var available []string
+		for name, internalPlugin := range internalPlugins {
+			if internalPlugin.Interface == pluginInterface {
+				available = append(available, name[strings.LastIndex(name, ".")+1:])
+			}
+		}
+		sort.Strings(available)
+		return fmt.Errorf("porter does not provide a %s plugin named %s, available plugins are: %s", key.Interface, key.Implementation, strings.Join(available, ", "))
+	}
+
+	if internalPlugin.NewConfig == nil {
+		if len(cfg.Config) > 0 {
+			return fmt.Errorf("the %s plugin does not have any configuration, remove the config section", cfg.PluginSubKey)
+		}
+		return nil
+	}
+	return cfg.DecodeConfig(internalPlugin.NewConfig())
+}
@@ -0,0 +1,97 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_ValidateConfig(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		p.Data.DefaultStorage = "devdb"
+		p.Data.StoragePlugins = []config.StoragePlugin{
+			{PluginConfig: config.PluginConfig{Name: "devdb", PluginSubKey: "mongodb", Config: map[string]interface{}{"url": "mongodb://localhost:27017", "timeout": 10}}},
+			{PluginConfig: config.PluginConfig{Name: "blob", PluginSubKey: "plugin1.blob"}},
+		}
+
+		require.NoError(t, p.ValidateConfig(p.RootContext, ConfigValidateOptions{}))
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "the default configuration is valid")
+	})
+
+	t.Run("invalid plugins", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		p.Data.StoragePlugins = []config.StoragePlugin{
+			{PluginConfig: config.PluginConfig{Name: "devdb", PluginSubKey: "mongodb", Config: map[string]interface{}{"url": "mongodb://localhost:27017", "timout": 10}}},
+			{PluginConfig: config.PluginConfig{Name: "s3", PluginSubKey: "plugin1.s3"}},
+			{PluginConfig: config.PluginConfig{Name: "missing", PluginSubKey: "missing.blob"}},
+			{PluginConfig: config.PluginConfig{Name: "mysql", PluginSubKey: "mysql"}},
+		}
+		p.Data.SecretsPlugin = []config.SecretsPlugin{
+			{PluginConfig: config.PluginConfig{Name: "host", PluginSubKey: "host", Config: map[string]interface{}{"path": "/tmp"}}},
+		}
+
+		err := p.ValidateConfig(p.RootContext, ConfigValidateOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "storage devdb: unknown setting timout")
+		assert.Contains(t, err.Error(), "storage s3: the plugin1 plugin does not provide a storage plugin named s3")
+		assert.Contains(t, err.Error(), "storage missing: the missing plugin is not installed")
+		assert.Contains(t, err.Error(), "storage mysql: porter does not provide a storage plugin named mysql, available plugins are: bolt, dynamodb, mongodb, mongodb-docker, postgres")
+		assert.Contains(t, err.Error(), "secrets host: the host plugin does not have any configuration")
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		home, err := p.GetHomeDir()
+		require.NoError(t, err)
+		p.TestConfig.TestContext.AddTestFileFromRoot("pkg/config/testdata/invalid.yaml", home+"/config.yaml")
+		p.DataLoader = config.LoadFromFilesystem()
+		_, err = p.Config.Load(p.RootContext, nil)
+		require.NoError(t, err)
+
+		err = p.ValidateConfig(p.RootContext, ConfigValidateOptions{Flags: []string{"output"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the configuration is not valid: 8 errors occurred")
+		assert.Contains(t, err.Error(), "unknown setting verbosty")
+		assert.Contains(t, err.Error(), "storage devdb: unknown setting timout")
+	})
+}
+
+func TestPorter_ViewConfig(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	home, err := p.GetHomeDir()
+	require.NoError(t, err)
+	p.TestConfig.TestContext.AddTestFileFromRoot("pkg/config/testdata/invalid.yaml", home+"/config.yaml")
+	p.DataLoader = config.LoadFromFilesystem()
+	resolveSecret := func(ctx context.Context, secretKey string) (string, error) {
+		return "hunter2", nil
+	}
+	_, err = p.Config.Load(p.RootContext, resolveSecret)
+	require.NoError(t, err)
+
+	opts := ConfigViewOptions{PrintOptions: printer.PrintOptions{Format: printer.FormatYaml}, Resolved: true}
+	require.NoError(t, p.ViewConfig(p.RootContext, opts))
+
+	got := p.TestConfig.TestContext.GetOutput()
+	assert.Contains(t, got, "verbosity: loud")
+	assert.Contains(t, got, "url: '******'", "values that came from a secret should be masked")
+	assert.NotContains(t, got, "hunter2")
+	assert.Contains(t, got, "default-secrets-plugin: host")
+
+	// Lists of settings from a yaml config file can be printed as json
+	opts.Format = printer.FormatJson
+	require.NoError(t, p.ViewConfig(p.RootContext, opts))
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), `"plugin": "mongodb"`)
+}
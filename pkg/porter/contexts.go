@@ -0,0 +1,159 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/tracing"
+	"get.porter.sh/porter/pkg/yaml"
+)
+
+// ContextListOptions represent options for Porter's config list-contexts command
+type ContextListOptions struct {
+	printer.PrintOptions
+}
+
+// Validate the options provided to Porter's config list-contexts command
+func (o *ContextListOptions) Validate() error {
+	return o.ParseFormat()
+}
+
+// UseContextOptions represent options for Porter's config use-context command
+type UseContextOptions struct {
+	// Name of the context to select.
+	Name string
+}
+
+// Validate the args provided to Porter's config use-context command
+func (o *UseContextOptions) Validate(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("the name of the context must be specified")
+	case 1:
+		o.Name = args[0]
+		return nil
+	default:
+		return fmt.Errorf("only one positional argument may be specified, the context name, but multiple were received: %s", args)
+	}
+}
+
+// DisplayContext represents a context defined in the Porter configuration file.
+type DisplayContext struct {
+	Name           string `json:"name" yaml:"name"`
+	Current        bool   `json:"current" yaml:"current"`
+	Namespace      string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	DefaultStorage string `json:"defaultStorage,omitempty" yaml:"defaultStorage,omitempty"`
+	DefaultSecrets string `json:"defaultSecrets,omitempty" yaml:"defaultSecrets,omitempty"`
+}
+
+// NewDisplayContext converts a context from the configuration file into its display representation.
+func NewDisplayContext(c config.ContextConfig, current string) DisplayContext {
+	setting := func(key string) string {
+		value, _ := c.Settings[key].(string)
+		return value
+	}
+	return DisplayContext{
+		Name:           c.Name,
+		Current:        c.Name == current,
+		Namespace:      setting("namespace"),
+		DefaultStorage: setting("default-storage"),
+		DefaultSecrets: setting("default-secrets"),
+	}
+}
+
+// ListContexts returns the contexts defined in the Porter configuration file.
+func (p *Porter) ListContexts(ctx context.Context) []DisplayContext {
+	contexts := make([]DisplayContext, 0, len(p.Data.Contexts))
+	for _, c := range p.Data.Contexts {
+		contexts = append(contexts, NewDisplayContext(c, p.Data.CurrentContext))
+	}
+	return contexts
+}
+
+// PrintContexts prints the contexts defined in the Porter configuration file.
+func (p *Porter) PrintContexts(ctx context.Context, opts ContextListOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	contexts := p.ListContexts(ctx)
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, contexts)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, contexts)
+	case printer.FormatPlaintext:
+		printContextRow :=
+			func(v interface{}) []string {
+				c, ok := v.(DisplayContext)
+				if !ok {
+					return nil
+				}
+				var current string
+				if c.Current {
+					current = "*"
+				}
+				return []string{current, c.Name, c.Namespace, c.DefaultStorage, c.DefaultSecrets}
+			}
+		return printer.PrintTable(p.Out, contexts, printContextRow,
+			"CURRENT", "NAME", "NAMESPACE", "STORAGE", "SECRETS")
+	default:
+		return span.Error(fmt.Errorf("invalid format: %s", opts.Format))
+	}
+}
+
+// PrintCurrentContext prints the name of the current context.
+func (p *Porter) PrintCurrentContext(ctx context.Context) error {
+	if p.Data.CurrentContext == "" {
+		return errors.New("a context is not selected, select one with porter config use-context")
+	}
+
+	fmt.Fprintln(p.Out, p.Data.CurrentContext)
+	return nil
+}
+
+// UseContext selects the context whose settings are layered over the rest of
+// the configuration file, saving it as current-context in the configuration file.
+func (p *Porter) UseContext(ctx context.Context, opts UseContextOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if _, ok := p.Data.GetContext(opts.Name); !ok {
+		names := make([]string, 0, len(p.Data.Contexts))
+		for _, c := range p.Data.Contexts {
+			names = append(names, c.Name)
+		}
+		if len(names) == 0 {
+			return span.Error(fmt.Errorf("context %s is not defined because the config file does not define any contexts", opts.Name))
+		}
+		return span.Error(fmt.Errorf("context %s is not defined in the config file, available contexts are: %s", opts.Name, strings.Join(names, ", ")))
+	}
+
+	// Edit the yaml so that comments and formatting in the config file are preserved
+	cfgFile := p.ConfigFilePath
+	if ext := filepath.Ext(cfgFile); ext != ".yaml" && ext != ".yml" {
+		return span.Error(fmt.Errorf("porter config use-context only supports a config.yaml file, set current-context in %s instead, or set the %s environment variable", cfgFile, config.EnvContext))
+	}
+
+	e := yaml.NewEditor(p.Context)
+	if err := e.ReadFile(cfgFile); err != nil {
+		return span.Error(fmt.Errorf("could not read the config file: %w", err))
+	}
+	if err := e.SetValue("current-context", opts.Name); err != nil {
+		return span.Error(fmt.Errorf("could not set the current context: %w", err))
+	}
+	if err := e.WriteFile(cfgFile); err != nil {
+		return span.Error(fmt.Errorf("could not save the config file: %w", err))
+	}
+
+	if override := p.Getenv(config.EnvContext); override != "" && override != opts.Name {
+		span.Warnf("The %s environment variable is set, and selects the %s context instead", config.EnvContext, override)
+	}
+	p.printInfof("Switched to context %s\n", opts.Name)
+	return nil
+}
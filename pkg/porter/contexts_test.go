@@ -0,0 +1,64 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_UseContext(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	home, err := p.GetHomeDir()
+	require.NoError(t, err)
+	p.TestConfig.TestContext.AddTestFileFromRoot("pkg/config/testdata/contexts.yaml", home+"/config.yaml")
+	p.DataLoader = config.LoadFromFilesystem()
+	_, err = p.Config.Load(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, "prod", p.Data.CurrentContext)
+
+	err = p.UseContext(ctx, UseContextOptions{Name: "staging"})
+	require.EqualError(t, err, "context staging is not defined in the config file, available contexts are: dev, prod")
+
+	err = p.UseContext(ctx, UseContextOptions{Name: "dev"})
+	require.NoError(t, err)
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Switched to context dev")
+
+	contents, err := p.FileSystem.ReadFile(home + "/config.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "current-context: dev")
+	assert.Contains(t, string(contents), "# Settings shared by every context", "comments in the config file should be preserved")
+
+	// Reload the config file to apply the context
+	_, err = p.Config.Load(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "dev", p.Data.CurrentContext)
+	assert.Equal(t, "devdb", p.Data.DefaultStorage)
+}
+
+func TestPorter_PrintContexts(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	p.Data.CurrentContext = "prod"
+	p.Data.Contexts = []config.ContextConfig{
+		{Name: "dev"},
+		{Name: "prod", Settings: map[string]interface{}{"namespace": "prod", "default-storage": "proddb", "default-secrets": "vault"}},
+	}
+
+	opts := ContextListOptions{PrintOptions: printer.PrintOptions{Format: printer.FormatPlaintext}}
+	require.NoError(t, p.PrintContexts(ctx, opts))
+
+	got := p.TestConfig.TestContext.GetOutput()
+	assert.Contains(t, got, "CURRENT")
+	assert.Regexp(t, `\*\s+prod\s+prod\s+proddb\s+vault`, got)
+
+	require.NoError(t, p.PrintCurrentContext(ctx))
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "prod\n")
+}
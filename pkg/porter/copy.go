@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"get.porter.sh/porter/pkg/cnab"
@@ -13,12 +14,23 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 )
 
+// DefaultParallelism is the number of images, and layers of each image, that are
+// transferred at the same time when archiving, copying or publishing a bundle.
+const DefaultParallelism = cnabtooci.DefaultParallelism
+
 type CopyOpts struct {
 	Source           string
 	sourceRef        cnab.OCIReference
 	Destination      string
 	InsecureRegistry bool
 	Force            bool
+
+	// RelocationOutput is the path to a file where the relocation mapping of the copied bundle is written.
+	// The file can be passed to porter install --relocation-mapping.
+	RelocationOutput string
+
+	// Parallelism is the maximum number of images, and layers of each image, copied at the same time.
+	Parallelism int
 }
 
 // Validate performs validation logic on the options specified for a bundle copy
@@ -36,6 +48,10 @@ func (c *CopyOpts) Validate(cfg *config.Config) error {
 		return errors.New("--destination must be tagged reference when --source is digested reference")
 	}
 
+	if err = validateParallelism(c.Parallelism); err != nil {
+		return err
+	}
+
 	// Apply the global config for force overwrite
 	if !c.Force && cfg.Data.ForceOverwrite {
 		c.Force = true
@@ -44,6 +60,14 @@ func (c *CopyOpts) Validate(cfg *config.Config) error {
 	return nil
 }
 
+// validateParallelism validates the flag that limits how many images and layers are transferred at the same time.
+func validateParallelism(parallelism int) error {
+	if parallelism < 0 {
+		return errors.New("--parallelism must not be negative")
+	}
+	return nil
+}
+
 func isCopyReferenceOnly(dest string) bool {
 	ref, err := cnab.ParseOCIReference(dest)
 	if err != nil {
@@ -76,6 +100,9 @@ func (p *Porter) CopyBundle(ctx context.Context, opts *CopyOpts) error {
 
 	regOpts := cnabtooci.RegistryOptions{
 		InsecureRegistry: opts.InsecureRegistry,
+		Mirrors:          p.Data.RegistryMirrors,
+		RegistryTLS:      p.Data.RegistryTLS,
+		Parallelism:      opts.Parallelism,
 	}
 
 	// Before we attempt to push, check if it already exists in the destination registry
@@ -91,16 +118,71 @@ func (p *Porter) CopyBundle(ctx context.Context, opts *CopyOpts) error {
 	}
 
 	span.Infof("Beginning bundle copy to %s. This may take some time.", destinationRef)
+	spinner := p.NewUI().StartSpinner(fmt.Sprintf("Copying %s to %s", opts.sourceRef, destinationRef))
 	bunRef, err := p.Registry.PullBundle(ctx, opts.sourceRef, regOpts)
 	if err != nil {
+		spinner.Stop(err)
 		return span.Error(fmt.Errorf("unable to pull bundle before copying: %w", err))
 	}
 
 	bunRef.Reference = destinationRef
 
-	_, err = p.Registry.PushBundle(ctx, bunRef, regOpts)
+	bunRef, err = p.Registry.PushBundle(ctx, bunRef, regOpts)
+	spinner.Stop(err)
 	if err != nil {
 		return span.Error(fmt.Errorf("unable to copy bundle to new location: %w", err))
 	}
+
+	// Every image should now be referenced by digest in the destination registry,
+	// so that the bundle can be used without access to the source registry
+	if err = validateCopiedImages(bunRef); err != nil {
+		return span.Error(err)
+	}
+
+	if opts.RelocationOutput != "" {
+		if err = p.writeRelocationMapFile(opts.RelocationOutput, bunRef.RelocationMap); err != nil {
+			return span.Error(err)
+		}
+		p.printInfof("Wrote the relocation mapping for %s to %s\n", destinationRef, opts.RelocationOutput)
+	}
+	return nil
+}
+
+// validateCopiedImages checks that every image referenced by the copied bundle is relocated
+// to the destination repository and pinned to a digest.
+func validateCopiedImages(bunRef cnab.BundleReference) error {
+	var images []string
+	for _, invImg := range bunRef.Definition.InvocationImages {
+		images = append(images, invImg.Image)
+	}
+	for _, img := range bunRef.Definition.Images {
+		images = append(images, img.Image)
+	}
+	sort.Strings(images)
+
+	destRepo := bunRef.Reference.Repository()
+	var problems []string
+	for _, img := range images {
+		relocated, ok := bunRef.RelocationMap[img]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s was not copied", img))
+			continue
+		}
+
+		relocatedRef, err := cnab.ParseOCIReference(relocated)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s was copied to an invalid location %s: %s", img, relocated, err))
+			continue
+		}
+		if !relocatedRef.HasDigest() {
+			problems = append(problems, fmt.Sprintf("%s was copied to %s, which is not pinned to a digest", img, relocated))
+		} else if relocatedRef.Repository() != destRepo {
+			problems = append(problems, fmt.Sprintf("%s was copied to %s, which is not in the destination repository %s", img, relocated, destRepo))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the bundle was copied to %s but not all of its images were relocated:\n%s", bunRef.Reference, strings.Join(problems, "\n"))
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package porter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -9,6 +10,8 @@ import (
 	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/tests"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-to-oci/relocation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -110,6 +113,16 @@ func TestValidateCopyArgs(t *testing.T) {
 			true,
 			"--destination must be tagged reference when --source is digested reference",
 		},
+		{
+			"negative parallelism",
+			CopyOpts{
+				Source:      "deislabs/mybuns:v0.1.0",
+				Destination: "blah.acr.io",
+				Parallelism: -1,
+			},
+			true,
+			"--parallelism must not be negative",
+		},
 	}
 
 	for _, test := range tests {
@@ -227,3 +240,76 @@ func TestCopy_ForceOverwrite(t *testing.T) {
 		})
 	}
 }
+
+func TestCopy_RelocationOutput(t *testing.T) {
+	const imgDigest = "sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687"
+
+	testcases := []struct {
+		name          string
+		relocationMap relocation.ImageRelocationMap
+		wantErr       string
+	}{
+		{name: "all images copied by digest", relocationMap: relocation.ImageRelocationMap{
+			"example1.com/mybuns-installer:v0.1.0": "example2.com/mybuns@" + imgDigest,
+			"example1.com/whalesay:latest":         "example2.com/mybuns@" + imgDigest,
+		}},
+		{name: "image not copied", relocationMap: relocation.ImageRelocationMap{
+			"example1.com/mybuns-installer:v0.1.0": "example2.com/mybuns@" + imgDigest,
+		}, wantErr: "example1.com/whalesay:latest was not copied"},
+		{name: "image not pinned to a digest", relocationMap: relocation.ImageRelocationMap{
+			"example1.com/mybuns-installer:v0.1.0": "example2.com/mybuns@" + imgDigest,
+			"example1.com/whalesay:latest":         "example2.com/mybuns:latest",
+		}, wantErr: "example1.com/whalesay:latest was copied to example2.com/mybuns:latest, which is not pinned to a digest"},
+		{name: "image not in destination repository", relocationMap: relocation.ImageRelocationMap{
+			"example1.com/mybuns-installer:v0.1.0": "example2.com/mybuns@" + imgDigest,
+			"example1.com/whalesay:latest":         "example1.com/whalesay@" + imgDigest,
+		}, wantErr: "which is not in the destination repository example2.com/mybuns"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			p := NewTestPorter(t)
+			defer p.Close()
+
+			p.TestRegistry.MockPullBundle = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+				return cnab.BundleReference{
+					Reference: ref,
+					Definition: cnab.NewBundle(bundle.Bundle{
+						InvocationImages: []bundle.InvocationImage{{BaseImage: bundle.BaseImage{Image: "example1.com/mybuns-installer:v0.1.0"}}},
+						Images:           map[string]bundle.Image{"whalesay": {BaseImage: bundle.BaseImage{Image: "example1.com/whalesay:latest"}}},
+					}),
+				}, nil
+			}
+			p.TestRegistry.MockPushBundle = func(ctx context.Context, ref cnab.BundleReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+				ref.RelocationMap = tc.relocationMap
+				return ref, nil
+			}
+
+			opts := &CopyOpts{
+				Source:           "example1.com/mybuns:v0.1.0",
+				Destination:      "example2.com/mybuns:v0.1.0",
+				Force:            true,
+				RelocationOutput: "relocations/mybuns.json",
+			}
+			require.NoError(t, opts.Validate(p.Config))
+
+			err := p.CopyBundle(ctx, opts)
+			if tc.wantErr != "" {
+				tests.RequireErrorContains(t, err, tc.wantErr)
+				exists, _ := p.FileSystem.Exists(opts.RelocationOutput)
+				assert.False(t, exists, "the relocation mapping should not be written when the copy is incomplete")
+				return
+			}
+
+			require.NoError(t, err)
+			data, err := p.FileSystem.ReadFile(opts.RelocationOutput)
+			require.NoError(t, err, "the relocation mapping was not written")
+			var got relocation.ImageRelocationMap
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, tc.relocationMap, got)
+			assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Wrote the relocation mapping for example2.com/mybuns:v0.1.0 to relocations/mybuns.json")
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/secrets"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/cnabio/cnab-go/secrets/host"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// OperatorAPIVersion is the api version of the custom resources defined by the Porter Operator.
+const OperatorAPIVersion = "getporter.org/v1"
+
+var (
+	CRDAllowedFormats = []printer.Format{printer.FormatYaml, printer.FormatJson}
+	CRDDefaultFormat  = printer.FormatYaml
+
+	// invalidKubernetesNameChars matches the characters that are not allowed in
+	// the name of a Kubernetes resource.
+	invalidKubernetesNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+// CRDOptions are the options for converting a resource managed by Porter into
+// a custom resource of the Porter Operator.
+type CRDOptions struct {
+	printer.PrintOptions
+
+	// Name of the resource.
+	Name string
+
+	// Namespace in which the resource is defined.
+	Namespace string
+
+	// KubernetesNamespace is the Kubernetes namespace in which the custom
+	// resource is created. When it is empty, the namespace is omitted and
+	// selected when the resource is applied.
+	KubernetesNamespace string
+}
+
+// Validate the options for the to-crd commands.
+func (o *CRDOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("the name of the resource must be specified, but %d arguments were received", len(args))
+	}
+	o.Name = args[0]
+
+	if o.KubernetesNamespace != "" {
+		if errs := validation.IsDNS1123Label(o.KubernetesNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid --k8s-namespace %s: %s", o.KubernetesNamespace, strings.Join(errs, ", "))
+		}
+	}
+
+	return o.PrintOptions.Validate(CRDDefaultFormat, CRDAllowedFormats)
+}
+
+// OperatorResource is a custom resource of the Porter Operator.
+type OperatorResource struct {
+	APIVersion string               `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string               `json:"kind" yaml:"kind"`
+	Metadata   OperatorResourceMeta `json:"metadata" yaml:"metadata"`
+	Spec       interface{}          `json:"spec" yaml:"spec"`
+}
+
+// OperatorResourceMeta is the Kubernetes metadata of a custom resource.
+type OperatorResourceMeta struct {
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// OperatorInstallationSpec is the spec of an Installation custom resource.
+type OperatorInstallationSpec struct {
+	SchemaVersion  string                    `json:"schemaVersion" yaml:"schemaVersion"`
+	Name           string                    `json:"name" yaml:"name"`
+	Namespace      string                    `json:"namespace" yaml:"namespace"`
+	Uninstalled    bool                      `json:"uninstalled,omitempty" yaml:"uninstalled,omitempty"`
+	Bundle         storage.OCIReferenceParts `json:"bundle" yaml:"bundle"`
+	Labels         map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	CredentialSets []string                  `json:"credentialSets,omitempty" yaml:"credentialSets,omitempty"`
+	ParameterSets  []string                  `json:"parameterSets,omitempty" yaml:"parameterSets,omitempty"`
+	Parameters     map[string]string         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// OperatorCredentialSetSpec is the spec of a CredentialSet custom resource.
+type OperatorCredentialSetSpec struct {
+	SchemaVersion string             `json:"schemaVersion" yaml:"schemaVersion"`
+	Name          string             `json:"name" yaml:"name"`
+	Namespace     string             `json:"namespace" yaml:"namespace"`
+	Labels        map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Credentials   []secrets.Strategy `json:"credentials" yaml:"credentials"`
+}
+
+// OperatorParameterSetSpec is the spec of a ParameterSet custom resource.
+type OperatorParameterSetSpec struct {
+	SchemaVersion string             `json:"schemaVersion" yaml:"schemaVersion"`
+	Name          string             `json:"name" yaml:"name"`
+	Namespace     string             `json:"namespace" yaml:"namespace"`
+	Labels        map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Parameters    []secrets.Strategy `json:"parameters" yaml:"parameters"`
+}
+
+// InstallationToCRD prints an installation as an Installation custom resource
+// of the Porter Operator.
+func (p *Porter) InstallationToCRD(ctx context.Context, opts CRDOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	inst, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	spec := OperatorInstallationSpec{
+		SchemaVersion:  string(inst.SchemaVersion),
+		Name:           inst.Name,
+		Namespace:      inst.Namespace,
+		Uninstalled:    inst.Uninstalled,
+		Bundle:         inst.Bundle,
+		Labels:         inst.Labels,
+		CredentialSets: inst.CredentialSets,
+		ParameterSets:  inst.ParameterSets,
+	}
+
+	// The operator only accepts plaintext parameter values on the installation.
+	// Sensitive parameters are stored in a secret, and must be moved to a parameter set.
+	for _, param := range inst.Parameters.Parameters {
+		if param.Source.Key != host.SourceValue {
+			span.Warnf("Parameter %s is resolved from %s and was not included. Define it in a parameter set that resolves it from a Kubernetes secret instead.", param.Name, param.Source.Key)
+			continue
+		}
+		if spec.Parameters == nil {
+			spec.Parameters = make(map[string]string, len(inst.Parameters.Parameters))
+		}
+		spec.Parameters[param.Name] = param.Source.Value
+	}
+
+	return p.printOperatorResource(opts, "Installation", inst.Name, spec)
+}
+
+// CredentialSetToCRD prints a credential set as a CredentialSet custom
+// resource of the Porter Operator.
+func (p *Porter) CredentialSetToCRD(ctx context.Context, opts CRDOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cs, err := p.Credentials.GetCredentialSet(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	// The operator resolves credentials from Kubernetes secrets
+	p.warnUnsupportedOperatorSources(ctx, "credential", cs.Credentials, secrets.SourceSecret)
+
+	spec := OperatorCredentialSetSpec{
+		SchemaVersion: string(cs.SchemaVersion),
+		Name:          cs.Name,
+		Namespace:     cs.Namespace,
+		Labels:        cs.Labels,
+		Credentials:   cs.Credentials,
+	}
+	return p.printOperatorResource(opts, "CredentialSet", cs.Name, spec)
+}
+
+// ParameterSetToCRD prints a parameter set as a ParameterSet custom resource
+// of the Porter Operator.
+func (p *Porter) ParameterSetToCRD(ctx context.Context, opts CRDOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	ps, err := p.Parameters.GetParameterSet(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	// The operator resolves parameters from a value or a Kubernetes secret
+	p.warnUnsupportedOperatorSources(ctx, "parameter", ps.Parameters, secrets.SourceSecret, host.SourceValue)
+	if len(ps.Inherits) > 0 {
+		span.Warnf("Parameter set %s inherits from %s, which is not supported by the operator. Add the inherited parameters to the parameter set, or to the parameterSets of the installation, instead.",
+			ps.Name, strings.Join(ps.Inherits, ", "))
+	}
+
+	spec := OperatorParameterSetSpec{
+		SchemaVersion: string(ps.SchemaVersion),
+		Name:          ps.Name,
+		Namespace:     ps.Namespace,
+		Labels:        ps.Labels,
+		Parameters:    ps.Parameters,
+	}
+	return p.printOperatorResource(opts, "ParameterSet", ps.Name, spec)
+}
+
+// warnUnsupportedOperatorSources warns about the values that are resolved
+// from a source that is not available to the operator, such as a file or an
+// environment variable on the local machine.
+func (p *Porter) warnUnsupportedOperatorSources(ctx context.Context, valueType string, strategies []secrets.Strategy, supportedSources ...string) {
+	log := tracing.LoggerFromContext(ctx)
+	for _, strategy := range strategies {
+		supported := false
+		for _, source := range supportedSources {
+			if strategy.Source.Key == source {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			log.Warnf("The %s %s is resolved from %s, which is not supported by the operator. Change it to use one of the following sources: %s.",
+				valueType, strategy.Name, strategy.Source.Key, strings.Join(supportedSources, ", "))
+		}
+	}
+}
+
+func (p *Porter) printOperatorResource(opts CRDOptions, kind string, name string, spec interface{}) error {
+	resource := OperatorResource{
+		APIVersion: OperatorAPIVersion,
+		Kind:       kind,
+		Metadata: OperatorResourceMeta{
+			Name:      toKubernetesName(name),
+			Namespace: opts.KubernetesNamespace,
+		},
+		Spec: spec,
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, resource)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, resource)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// toKubernetesName converts the name of a resource managed by Porter into a
+// valid name for a Kubernetes resource. The original name is kept in the spec
+// of the custom resource.
+func toKubernetesName(name string) string {
+	name = invalidKubernetesNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.TrimFunc(name, func(r rune) bool {
+		return r == '-' || r == '.'
+	})
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength], "-.")
+	}
+	return name
+}
@@ -0,0 +1,111 @@
+package porter
+
+import (
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/secrets"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRDOptions_Validate(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		opts := CRDOptions{}
+		require.NoError(t, opts.Validate([]string{"mybuns"}))
+		assert.Equal(t, "mybuns", opts.Name)
+		assert.Equal(t, printer.FormatYaml, opts.Format)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		opts := CRDOptions{}
+		err := opts.Validate(nil)
+		require.EqualError(t, err, "the name of the resource must be specified, but 0 arguments were received")
+	})
+
+	t.Run("invalid kubernetes namespace", func(t *testing.T) {
+		opts := CRDOptions{KubernetesNamespace: "Porter_Dev"}
+		err := opts.Validate([]string{"mybuns"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --k8s-namespace Porter_Dev")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		opts := CRDOptions{}
+		opts.RawFormat = "plaintext"
+		err := opts.Validate([]string{"mybuns"})
+		require.Error(t, err)
+	})
+}
+
+func TestToKubernetesName(t *testing.T) {
+	testcases := map[string]string{
+		"mybuns":           "mybuns",
+		"My_Buns":          "my-buns",
+		"-wordpress.":      "wordpress",
+		"app@v1 (staging)": "app-v1-staging",
+	}
+	for name, want := range testcases {
+		assert.Equal(t, want, toKubernetesName(name), name)
+	}
+
+	assert.Len(t, toKubernetesName(strings.Repeat("a", 300)), 253)
+}
+
+func TestPorter_InstallationToCRD(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	i := storage.NewInstallation("dev", "mybuns")
+	i.Bundle = storage.OCIReferenceParts{Repository: "example.com/mybuns", Version: "1.0.0"}
+	i.CredentialSets = []string{"mycreds"}
+	i.Parameters.Parameters = []secrets.Strategy{
+		storage.ValueStrategy("logLevel", "debug"),
+		{Name: "password", Source: secrets.Source{Key: secrets.SourceSecret, Value: "mypassword"}},
+	}
+	p.TestInstallations.CreateInstallation(i)
+
+	opts := CRDOptions{Name: "mybuns", Namespace: "dev", KubernetesNamespace: "porter-dev"}
+	opts.Format = printer.FormatYaml
+	err := p.InstallationToCRD(p.RootContext, opts)
+	require.NoError(t, err)
+
+	output := p.TestConfig.TestContext.GetOutput()
+	assert.Contains(t, output, "apiVersion: getporter.org/v1\nkind: Installation\nmetadata:\n  name: mybuns\n  namespace: porter-dev\n")
+	assert.Contains(t, output, "repository: example.com/mybuns")
+	assert.Contains(t, output, "parameters:\n    logLevel: debug\n")
+	assert.NotContains(t, output, "password", "parameters stored in a secret should not be included")
+	assert.Contains(t, p.TestConfig.TestContext.GetError(), "Parameter password is resolved from secret and was not included")
+}
+
+func TestPorter_CredentialSetToCRD(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.TestCredentials.AddTestCredentialsDirectory("testdata/test-creds")
+
+	opts := CRDOptions{Name: "kool-kreds", Namespace: "dev", KubernetesNamespace: "porter-dev"}
+	opts.Format = printer.FormatYaml
+	err := p.CredentialSetToCRD(p.RootContext, opts)
+	require.NoError(t, err)
+
+	test.CompareGoldenFile(t, "testdata/credentials/kool-kreds-crd.yaml", p.TestConfig.TestContext.GetOutput())
+	assert.Contains(t, p.TestConfig.TestContext.GetError(), "The credential kool-envvar is resolved from env, which is not supported by the operator")
+}
+
+func TestPorter_warnUnsupportedOperatorSources(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	params := []secrets.Strategy{
+		storage.ValueStrategy("logLevel", "debug"),
+		{Name: "password", Source: secrets.Source{Key: secrets.SourceSecret, Value: "mypassword"}},
+		{Name: "kubeconfig", Source: secrets.Source{Key: "path", Value: "/home/me/.kube/config"}},
+	}
+	p.warnUnsupportedOperatorSources(p.RootContext, "parameter", params, secrets.SourceSecret, "value")
+
+	assert.Equal(t, "The parameter kubeconfig is resolved from path, which is not supported by the operator. Change it to use one of the following sources: secret, value.\n",
+		p.TestConfig.TestContext.GetError())
+}
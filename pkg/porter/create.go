@@ -10,7 +10,7 @@ import (
 )
 
 func (p *Porter) Create() error {
-	fmt.Fprintln(p.Out, "creating porter configuration in the current directory")
+	p.printInfof("creating porter configuration in the current directory\n")
 
 	err := p.CopyTemplate(p.Templates.GetManifest, config.Name)
 	if err != nil {
@@ -33,13 +33,12 @@ type CredentialEditOptions struct {
 
 // ListCredentials lists saved credential sets.
 func (p *Porter) ListCredentials(ctx context.Context, opts ListOptions) ([]storage.CredentialSet, error) {
-	return p.Credentials.ListCredentialSets(ctx, storage.ListOptions{
-		Namespace: opts.GetNamespace(),
-		Name:      opts.Name,
-		Labels:    opts.ParseLabels(),
-		Skip:      opts.Skip,
-		Limit:     opts.Limit,
-	})
+	query, err := opts.toStorageListOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Credentials.ListCredentialSets(ctx, query)
 }
 
 // PrintCredentials prints saved credential sets.
@@ -52,31 +51,80 @@ func (p *Porter) PrintCredentials(ctx context.Context, opts ListOptions) error {
 		return err
 	}
 
+	// Sort by the csv values so that timestamps are compared, instead of relative times
+	csvRow :=
+		func(v interface{}) []string {
+			cr, ok := v.(storage.CredentialSet)
+			if !ok {
+				return nil
+			}
+			return getCredentialSetRow(cr, formatCsvTime, true)
+		}
+	if err := opts.SortRows(creds, csvRow, getCredentialSetHeaders(true)...); err != nil {
+		return span.Error(err)
+	}
+
+	page := make([]storage.ContinueToken, len(creds))
+	for i, item := range creds {
+		page[i] = storage.NewContinueToken(item.Namespace, item.Name)
+	}
+
 	switch opts.Format {
 	case printer.FormatJson:
-		return printer.PrintJson(p.Out, creds)
+		err = printer.PrintJson(p.Out, creds)
 	case printer.FormatYaml:
-		return printer.PrintYaml(p.Out, creds)
-	case printer.FormatPlaintext:
+		err = printer.PrintYaml(p.Out, creds)
+	case printer.FormatPlaintext, printer.FormatWide:
 		// have every row use the same "now" starting ... NOW!
 		now := time.Now()
 		tp := dtprinter.DateTimePrinter{
 			Now: func() time.Time { return now },
 		}
 
+		// Selected columns may include those only printed in the wide output
+		wide := opts.Format == printer.FormatWide || opts.HasColumns()
 		printCredRow :=
 			func(v interface{}) []string {
 				cr, ok := v.(storage.CredentialSet)
 				if !ok {
 					return nil
 				}
-				return []string{cr.Namespace, cr.Name, tp.Format(cr.Status.Modified)}
+				return getCredentialSetRow(cr, tp.Format, wide)
 			}
-		return printer.PrintTable(p.Out, creds, printCredRow,
-			"NAMESPACE", "NAME", "MODIFIED")
+		err = opts.PrintTable(p.Out, creds, printCredRow, getCredentialSetHeaders(wide)...)
+	case printer.FormatCsv:
+		err = opts.PrintCsv(p.Out, creds, csvRow, getCredentialSetHeaders(true)...)
 	default:
 		return span.Error(fmt.Errorf("invalid format: %s", opts.Format))
 	}
+	if err != nil {
+		return span.Error(err)
+	}
+
+	p.printContinueToken(opts, page)
+	return nil
+}
+
+// getCredentialSetHeaders returns the column headers used when listing credential sets.
+// The wide output adds the labels and the names of the credentials in the set.
+func getCredentialSetHeaders(wide bool) []string {
+	headers := []string{"NAMESPACE", "NAME", "MODIFIED"}
+	if wide {
+		headers = append(headers, "LABELS", "CREDENTIALS")
+	}
+	return headers
+}
+
+func getCredentialSetRow(cr storage.CredentialSet, formatTime func(time.Time) string, wide bool) []string {
+	row := []string{cr.Namespace, cr.Name, formatTime(cr.Status.Modified)}
+	if wide {
+		names := make([]string, 0, len(cr.Credentials))
+		for _, item := range cr.Credentials {
+			names = append(names, item.Name)
+		}
+		row = append(row, formatLabels(cr.Labels), strings.Join(names, ","))
+	}
+	return row
 }
 
 // CredentialsOptions are the set of options available to Porter.GenerateCredentials
@@ -158,10 +206,13 @@ func (p *Porter) GenerateCredentials(ctx context.Context, opts CredentialOptions
 
 // Validate validates the args provided to Porter's credential show command
 func (o *CredentialShowOptions) Validate(args []string) error {
-	if err := validateCredentialName(args); err != nil {
-		return err
+	// The user is asked to pick the credential set when the name is not specified
+	if len(args) > 0 {
+		if err := validateCredentialName(args); err != nil {
+			return err
+		}
+		o.Name = args[0]
 	}
-	o.Name = args[0]
 	return o.ParseFormat()
 }
 
@@ -227,6 +278,14 @@ func (p *Porter) ShowCredential(ctx context.Context, opts CredentialShowOptions)
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
 
+	if opts.Name == "" {
+		name, err := p.pickCredentialSetName(ctx, opts.Namespace)
+		if err != nil {
+			return span.Error(err)
+		}
+		opts.Name = name
+	}
+
 	cs, err := p.Credentials.GetCredentialSet(ctx, opts.Namespace, opts.Name)
 	if err != nil {
 		return err
@@ -306,6 +365,9 @@ func (p *Porter) ShowCredential(ctx context.Context, opts CredentialShowOptions)
 type CredentialDeleteOptions struct {
 	Name      string
 	Namespace string
+
+	// Force deletes the credential set from a protected namespace.
+	Force bool
 }
 
 // DeleteCredential deletes the credential set corresponding to the provided
@@ -317,6 +379,9 @@ func (p *Porter) DeleteCredential(ctx context.Context, opts CredentialDeleteOpti
 	)
 	defer span.EndSpan()
 
+	if opts.Force {
+		ctx = storage.WithForce(ctx)
+	}
 	err := p.Credentials.RemoveCredentialSet(ctx, opts.Namespace, opts.Name)
 	if errors.Is(err, storage.ErrNotFound{}) {
 		span.Debug("nothing to remove, credential already does not exist")
@@ -10,6 +10,7 @@ import (
 
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/secrets"
 	"get.porter.sh/porter/pkg/storage"
 	"get.porter.sh/porter/pkg/test"
 	"github.com/stretchr/testify/assert"
@@ -387,6 +388,29 @@ func TestApplyOptions_Validate(t *testing.T) {
 		require.Contains(t, err.Error(), "only one file argument may be specified")
 	})
 
+	t.Run("watch", func(t *testing.T) {
+		tc := portercontext.NewTestContext(t)
+		tc.AddTestFileFromRoot("tests/testdata/creds/mybuns.yaml", "mybuns.yaml")
+		opts := ApplyOptions{Watch: true, Interval: time.Minute}
+		err := opts.Validate(tc.Context, []string{"mybuns.yaml"})
+		require.NoError(t, err)
+	})
+
+	t.Run("watch without an interval", func(t *testing.T) {
+		tc := portercontext.NewTestContext(t)
+		tc.AddTestFileFromRoot("tests/testdata/creds/mybuns.yaml", "mybuns.yaml")
+		opts := ApplyOptions{Watch: true}
+		err := opts.Validate(tc.Context, []string{"mybuns.yaml"})
+		require.EqualError(t, err, "--interval must be greater than 0")
+	})
+
+	t.Run("watch with force", func(t *testing.T) {
+		tc := portercontext.NewTestContext(t)
+		tc.AddTestFileFromRoot("tests/testdata/creds/mybuns.yaml", "mybuns.yaml")
+		opts := ApplyOptions{Watch: true, Interval: time.Minute, Force: true}
+		err := opts.Validate(tc.Context, []string{"mybuns.yaml"})
+		require.EqualError(t, err, "--force cannot be used with --watch, because the bundle would be executed at every interval")
+	})
 }
 
 func TestCredentialsCreateOptions_Validate(t *testing.T) {
@@ -520,3 +544,16 @@ func TestCredentialsCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCredentialSetRow(t *testing.T) {
+	cs := storage.NewCredentialSet("dev", "mycreds",
+		secrets.Strategy{Name: "username"},
+		secrets.Strategy{Name: "password"})
+	cs.Labels = map[string]string{"env": "dev"}
+	cs.Status.Modified = now
+
+	assert.Equal(t, []string{"dev", "mycreds", "2020-04-18T01:02:03Z"},
+		getCredentialSetRow(cs, formatCsvTime, false))
+	assert.Equal(t, []string{"dev", "mycreds", "2020-04-18T01:02:03Z", "env=dev", "username,password"},
+		getCredentialSetRow(cs, formatCsvTime, true))
+}
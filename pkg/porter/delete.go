@@ -7,9 +7,15 @@ import (
 
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
 )
 
-const installationDeleteTmpl = "deleting installation records for %s...\n"
+const (
+	installationDeleteTmpl = "deleting installation records for %s...\n"
+
+	installationOrphansDeletedTmpl = "removed %d run, result and output records for %s\n"
+)
 
 var (
 	// ErrUnsafeInstallationDelete warns the user that deletion of an unsuccessfully uninstalled installation is unsafe
@@ -23,6 +29,13 @@ var (
 type DeleteOptions struct {
 	installationOptions
 	Force bool
+
+	// AllowProtected allows the installation to be deleted from a protected namespace.
+	AllowProtected bool
+
+	// CleanupOrphans removes the runs, results and outputs left behind for the
+	// installation, even when the installation itself no longer exists.
+	CleanupOrphans bool
 }
 
 // Validate prepares for an installation delete action and validates the args/options.
@@ -45,6 +58,10 @@ func (p *Porter) DeleteInstallation(ctx context.Context, opts DeleteOptions) err
 
 	installation, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
 	if err != nil {
+		// The installation was already removed, but its records may remain, e.g. when a previous delete was interrupted
+		if opts.CleanupOrphans && errors.Is(err, storage.ErrNotFound{}) {
+			return p.deleteOrphanedInstallationRecords(ctx, opts)
+		}
 		return fmt.Errorf("unable to read status for installation %s: %w", opts.Name, err)
 	}
 
@@ -52,6 +69,35 @@ func (p *Porter) DeleteInstallation(ctx context.Context, opts DeleteOptions) err
 		return ErrUnsafeInstallationDeleteRetryForce
 	}
 
+	// A run that is stuck in the running state may have been abandoned, e.g. when porter was killed, so --force deletes it anyway
+	if installation.Status.ResultStatus == cnab.StatusRunning || installation.Status.ResultStatus == cnab.StatusPending {
+		tracing.LoggerFromContext(ctx).Warnf("The last %s of installation %s is still %s. Any resources created by the bundle are not cleaned up.",
+			installation.Status.Action, installation, installation.Status.ResultStatus)
+	}
+
+	if opts.AllowProtected {
+		// Allow the installation to be deleted from a protected namespace
+		ctx = storage.WithForce(ctx)
+	}
+
 	fmt.Fprintf(p.Out, installationDeleteTmpl, opts.Name)
+	if opts.CleanupOrphans {
+		if err = p.deleteOrphanedInstallationRecords(ctx, opts); err != nil {
+			return err
+		}
+	}
 	return p.Installations.RemoveInstallation(ctx, opts.Namespace, opts.Name)
 }
+
+// deleteOrphanedInstallationRecords removes the runs, results and outputs of an installation,
+// including results and outputs that only reference the installation through a run.
+func (p *Porter) deleteOrphanedInstallationRecords(ctx context.Context, opts DeleteOptions) error {
+	installation := storage.InstallationSpec{Namespace: opts.Namespace, Name: opts.Name}
+	count, err := p.Installations.RemoveInstallationRecords(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return fmt.Errorf("could not remove the records of installation %s: %w", installation, err)
+	}
+
+	fmt.Fprintf(p.Out, installationOrphansDeletedTmpl, count, installation)
+	return nil
+}
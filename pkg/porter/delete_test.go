@@ -84,3 +84,70 @@ func TestDeleteInstallation(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteInstallation_ProtectedNamespace(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	require.NoError(t, p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "prod", Protected: true}))
+	i := p.TestInstallations.CreateInstallation(storage.NewInstallation("prod", "test"))
+	c := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+	p.TestInstallations.CreateResult(c.NewResult(cnab.StatusSucceeded))
+
+	opts := DeleteOptions{Force: true}
+	opts.Namespace = "prod"
+	opts.Name = "test"
+	err := p.DeleteInstallation(ctx, opts)
+	require.ErrorIs(t, err, storage.ErrNamespaceProtected{}, "--force should not override the protection of the namespace")
+	assert.Contains(t, err.Error(), "unless the command is run with the --allow-protected flag")
+
+	opts.AllowProtected = true
+	require.NoError(t, p.DeleteInstallation(ctx, opts))
+	_, err = p.Installations.GetInstallation(ctx, "prod", "test")
+	require.ErrorIs(t, err, storage.ErrNotFound{})
+}
+
+func TestDeleteInstallation_CleanupOrphans(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("running installation", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		i := p.TestInstallations.CreateInstallation(storage.NewInstallation("", "test"))
+		run := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+		result := p.TestInstallations.CreateResult(run.NewResult(cnab.StatusRunning))
+		i.ApplyResult(run, result)
+		require.NoError(t, p.Installations.UpdateInstallation(ctx, i))
+
+		opts := DeleteOptions{Force: true, CleanupOrphans: true}
+		opts.Name = "test"
+		require.NoError(t, p.DeleteInstallation(p.RootContext, opts))
+		assert.Contains(t, p.TestConfig.TestContext.GetError(), "The last install of installation /test is still running")
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "removed 2 run, result and output records for /test")
+
+		_, err := p.Installations.GetInstallation(ctx, "", "test")
+		require.ErrorIs(t, err, storage.ErrNotFound{})
+		_, err = p.Installations.GetRun(ctx, run.ID)
+		require.ErrorIs(t, err, storage.ErrNotFound{})
+	})
+
+	t.Run("installation already deleted", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		i := storage.NewInstallation("", "test")
+		run := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+		result := p.TestInstallations.CreateResult(run.NewResult(cnab.StatusSucceeded))
+		p.TestInstallations.CreateOutput(result.NewOutput("foo", []byte("bar")))
+
+		opts := DeleteOptions{CleanupOrphans: true}
+		opts.Name = "test"
+		require.NoError(t, p.DeleteInstallation(ctx, opts))
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "removed 3 run, result and output records for /test")
+
+		_, err := p.Installations.GetRun(ctx, run.ID)
+		require.ErrorIs(t, err, storage.ErrNotFound{})
+	})
+}
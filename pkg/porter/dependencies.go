@@ -7,7 +7,9 @@ import (
 	"strings"
 
 	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
 	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
+	depsv2 "get.porter.sh/porter/pkg/cnab/dependencies/v2"
 	cnabprovider "get.porter.sh/porter/pkg/cnab/provider"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/manifest"
@@ -17,6 +19,10 @@ import (
 	"github.com/hashicorp/go-multierror"
 )
 
+// LabelParentInstallation is set on the installation of a dependency to the
+// namespace/name of the installation that depends upon it.
+const LabelParentInstallation = "sh.porter.parentInstallation"
+
 type dependencyExecutioner struct {
 	*config.Config
 	porter *Porter
@@ -36,8 +42,10 @@ type dependencyExecutioner struct {
 
 func newDependencyExecutioner(p *Porter, installation storage.Installation, action BundleAction) *dependencyExecutioner {
 	resolver := BundleResolver{
-		Cache:    p.Cache,
-		Registry: p.Registry,
+		Cache:       p.Cache,
+		Registry:    p.Registry,
+		Mirrors:     p.Data.RegistryMirrors,
+		RegistryTLS: p.Data.RegistryTLS,
 	}
 	return &dependencyExecutioner{
 		porter:             p,
@@ -56,6 +64,13 @@ type queuedDependency struct {
 	BundleReference cnab.BundleReference
 	Parameters      map[string]string
 
+	// Installation of the dependency, populated by Prepare.
+	Installation storage.Installation
+
+	// Existing is true when a shared dependency is satisfied by an existing
+	// installation in its sharing group, and the dependency is not executed.
+	Existing bool
+
 	// cache of the CNAB file contents
 	cnabFileContents []byte
 }
@@ -93,6 +108,10 @@ func (e *dependencyExecutioner) Execute(ctx context.Context) error {
 		return span.Error(errors.New("Prepare must be called before Execute"))
 	}
 
+	if e.parentOpts.DependencyParallelism > 1 {
+		return e.executeDependenciesInParallel(ctx, e.parentOpts.DependencyParallelism, e.executeDependency)
+	}
+
 	// executeDependency the requested action against all the dependencies
 	for _, dep := range e.deps {
 		err := e.executeDependency(ctx, dep)
@@ -127,6 +146,21 @@ func (e *dependencyExecutioner) PrepareRootActionArguments(ctx context.Context)
 	return args, nil
 }
 
+// newDependencySolver creates a solver that lists the tags of dependencies with
+// the registry used by Porter, so that offline mode and the registry settings,
+// such as mirrors and credentials, are respected.
+func (p *Porter) newDependencySolver(ctx context.Context, regOpts cnabtooci.RegistryOptions) *cnab.DependencySolver {
+	return &cnab.DependencySolver{
+		ListTags: func(repository string) ([]string, error) {
+			ref, err := cnab.ParseOCIReference(repository)
+			if err != nil {
+				return nil, fmt.Errorf("invalid repository %s: %w", repository, err)
+			}
+			return p.Registry.ListTags(ctx, ref, regOpts)
+		},
+	}
+}
+
 func (e *dependencyExecutioner) identifyDependencies(ctx context.Context) error {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
@@ -158,7 +192,7 @@ func (e *dependencyExecutioner) identifyDependencies(ctx context.Context) error
 		return span.Error(errors.New("identifyDependencies failed to load the bundle because no bundle was specified. Please report this bug to https://github.com/getporter/porter/issues/new/choose"))
 	}
 
-	solver := &cnab.DependencySolver{}
+	solver := e.porter.newDependencySolver(ctx, cnabtooci.RegistryOptions{InsecureRegistry: e.parentOpts.InsecureRegistry})
 	locks, err := solver.ResolveDependencies(bun)
 	if err != nil {
 		return span.Error(err)
@@ -219,31 +253,31 @@ func (e *dependencyExecutioner) prepareDependency(ctx context.Context, dep *queu
 	//   - name: DEP
 	//     parameters:
 	//       PARAM: VALUE
-	// TODO: When we redo dependencies, we need to remove this dependency on the bundle being a porter bundle with a manifest
-	// Yes, right now the way this works means this feature is Porter only
-	m := &manifest.Manifest{}
-	if e.parentOpts.File != "" {
-		var err error
-		m, err = manifest.LoadManifestFrom(ctx, e.Config, e.parentOpts.File)
+	// With dependencies v2, the parameters are defined in the bundle and are wired up when the dependency is executed.
+	// Otherwise we rely upon the bundle being a porter bundle with a manifest.
+	wiring := dep.DependencyLock.Parameters
+	if wiring == nil && e.parentOpts.File != "" {
+		m, err := manifest.LoadManifestFrom(ctx, e.Config, e.parentOpts.File)
 		if err != nil {
 			return err
 		}
+		for _, manifestDep := range m.Dependencies.Requires {
+			if manifestDep.Name == dep.Alias {
+				wiring = manifestDep.Parameters
+			}
+		}
 	}
 
-	for _, manifestDep := range m.Dependencies.Requires {
-		if manifestDep.Name == dep.Alias {
-			for paramName, value := range manifestDep.Parameters {
-				// Make sure the parameter is defined in the bundle
-				if _, ok := depParams[paramName]; !ok {
-					return fmt.Errorf("invalid dependencies.%s.parameters entry, %s is not a parameter defined in that bundle", dep.Alias, paramName)
-				}
+	for paramName, value := range wiring {
+		// Make sure the parameter is defined in the bundle
+		if _, ok := depParams[paramName]; !ok {
+			return fmt.Errorf("invalid dependencies.%s.parameters entry, %s is not a parameter defined in that bundle", dep.Alias, paramName)
+		}
 
-				if dep.Parameters == nil {
-					dep.Parameters = make(map[string]string, 1)
-				}
-				dep.Parameters[paramName] = value
-			}
+		if dep.Parameters == nil {
+			dep.Parameters = make(map[string]string, 1)
 		}
+		dep.Parameters[paramName] = value
 	}
 
 	// Handle any parameter overrides for the dependency defined on the command line
@@ -266,9 +300,151 @@ func (e *dependencyExecutioner) prepareDependency(ctx context.Context, dep *queu
 		}
 	}
 
+	dep.Installation, dep.Existing, err = e.getDependencyInstallation(ctx, dep)
+	if err != nil {
+		return span.Error(fmt.Errorf("error determining the installation for dependency %s: %w", dep.Alias, err))
+	}
+
 	return nil
 }
 
+// getDependencyInstallation returns the installation of a dependency. A shared dependency
+// is satisfied by an installed installation of the same bundle in its sharing group,
+// when one exists, which is returned with existing set to true.
+func (e *dependencyExecutioner) getDependencyInstallation(ctx context.Context, dep *queuedDependency) (storage.Installation, bool, error) {
+	depName := depsv1.BuildPrerequisiteInstallationName(e.parentOpts.Name, dep.Alias)
+	if dep.IsShared() {
+		shared, ok, err := e.porter.findSharedDependency(ctx, e.parentOpts.Namespace, dep.SharingGroup, dep.BundleReference.Reference)
+		if err != nil {
+			return storage.Installation{}, false, err
+		}
+		if ok {
+			return shared, true, nil
+		}
+		depName = depsv2.BuildSharedInstallationName(dep.SharingGroup, dep.Alias)
+	}
+
+	depInstallation, err := e.Installations.GetInstallation(ctx, e.parentOpts.Namespace, depName)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound{}) {
+			return storage.Installation{}, false, err
+		}
+		depInstallation = storage.NewInstallation(e.parentOpts.Namespace, depName)
+		depInstallation.SetLabel(LabelParentInstallation, e.parentArgs.Installation.String())
+		// For now, assume it's okay to give the dependency the same credentials as the parent
+		depInstallation.CredentialSets = e.parentInstallation.CredentialSets
+	}
+	if dep.IsShared() {
+		depInstallation.SetLabel(depsv2.LabelSharingGroup, dep.SharingGroup)
+	}
+	depInstallation.TrackBundle(dep.BundleReference.Reference)
+	return depInstallation, false, nil
+}
+
+// getDependencyInstallationName returns the name of the installation of a dependency of the bundle.
+func (p *Porter) getDependencyInstallationName(ctx context.Context, bun cnab.ExtendedBundle, installation storage.Installation, alias string) (string, error) {
+	dep, ok, err := bun.GetDependencyV2(alias)
+	if err != nil {
+		return "", err
+	}
+	if !ok || !dep.Sharing.IsShared() {
+		return depsv1.BuildPrerequisiteInstallationName(installation.Name, alias), nil
+	}
+
+	ref, err := cnab.ParseOCIReference(dep.Bundle)
+	if err != nil {
+		return "", fmt.Errorf("error parsing dependency (%s) bundle %q as OCI reference: %w", alias, dep.Bundle, err)
+	}
+	shared, ok, err := p.findSharedDependency(ctx, installation.Namespace, dep.Sharing.Group.Name, ref)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return shared.Name, nil
+	}
+	return depsv2.BuildSharedInstallationName(dep.Sharing.Group.Name, alias), nil
+}
+
+// findSharedDependency looks for an installation of the bundle in the sharing group
+// that has been installed, and has not been uninstalled.
+func (p *Porter) findSharedDependency(ctx context.Context, namespace string, group string, bundleRef cnab.OCIReference) (storage.Installation, bool, error) {
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{
+		Namespace: namespace,
+		Labels:    map[string]string{depsv2.LabelSharingGroup: group},
+	})
+	if err != nil {
+		return storage.Installation{}, false, fmt.Errorf("could not list the installations in sharing group %s: %w", group, err)
+	}
+
+	for _, installation := range installations {
+		if installation.Bundle.Repository != bundleRef.Repository() {
+			continue
+		}
+		if installation.Status.Installed != nil && installation.Status.Uninstalled == nil {
+			return installation, true, nil
+		}
+	}
+	return storage.Installation{}, false, nil
+}
+
+// resolveDependencyParameters renders the references to the parameters of the bundle, and to
+// the outputs of the dependencies that have already been executed, in the parameters passed to
+// the dependency. When allowUnresolved is true, references to outputs that are not available
+// yet, for example when planning an action, are left as is.
+func (e *dependencyExecutioner) resolveDependencyParameters(ctx context.Context, dep *queuedDependency, allowUnresolved bool) (map[string]string, error) {
+	resolved := make(map[string]string, len(dep.Parameters))
+	for paramName, value := range dep.Parameters {
+		for _, ref := range manifest.ParseDependencyParameterReferences(value) {
+			var refValue string
+			if ref.Dependency == "" {
+				if v, ok := e.parentArgs.Params[ref.Name]; ok {
+					refValue = fmt.Sprintf("%v", v)
+				}
+			} else {
+				output, err := e.getDependencyOutput(ctx, ref.Dependency, ref.Name)
+				if err != nil {
+					if allowUnresolved {
+						continue
+					}
+					return nil, fmt.Errorf("could not set parameter %s of dependency %s: %w", paramName, dep.Alias, err)
+				}
+				refValue = output
+			}
+			value = strings.ReplaceAll(value, ref.Template, refValue)
+		}
+		resolved[paramName] = value
+	}
+	return resolved, nil
+}
+
+// getDependencyOutput returns the last value of an output generated by a dependency of the bundle.
+func (e *dependencyExecutioner) getDependencyOutput(ctx context.Context, alias string, outputName string) (string, error) {
+	var depInstallation *storage.Installation
+	for _, dep := range e.deps {
+		if dep.Alias == alias {
+			depInstallation = &dep.Installation
+			break
+		}
+	}
+	if depInstallation == nil {
+		return "", fmt.Errorf("dependency %s is not defined", alias)
+	}
+
+	output, err := e.Installations.GetLastOutput(ctx, depInstallation.Namespace, depInstallation.Name, outputName)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound{}) {
+			return "", fmt.Errorf("dependency %s (installation %s) has no output named %s", alias, depInstallation, outputName)
+		}
+		return "", err
+	}
+
+	output, err = e.porter.Sanitizer.RestoreOutput(ctx, output)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve output %s of dependency %s: %w", outputName, alias, err)
+	}
+	return string(output.Value), nil
+}
+
 func (e *dependencyExecutioner) executeDependency(ctx context.Context, dep *queuedDependency) error {
 	// TODO(carolynvs): We should really switch up how the deperator works so that
 	// even the root bundle uses the execution engine here. This would set up how
@@ -277,23 +453,33 @@ func (e *dependencyExecutioner) executeDependency(ctx context.Context, dep *queu
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
 
-	depName := depsv1.BuildPrerequisiteInstallationName(e.parentOpts.Name, dep.Alias)
-	depInstallation, err := e.Installations.GetInstallation(ctx, e.parentOpts.Namespace, depName)
+	// Shared dependencies may be used by other installations, and are not modified by the bundle
+	if dep.Existing {
+		span.Infof("Using the existing installation %s in sharing group %s for dependency %s", dep.Installation, dep.SharingGroup, dep.Alias)
+		return nil
+	}
+	if dep.IsShared() && e.parentArgs.Action == cnab.ActionUninstall {
+		span.Infof("Skipping uninstall of dependency %s because it is shared by sharing group %s", dep.Alias, dep.SharingGroup)
+		return nil
+	}
+
+	depInstallation := dep.Installation
+	_, err := e.Installations.GetInstallation(ctx, depInstallation.Namespace, depInstallation.Name)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound{}) {
-			depInstallation = storage.NewInstallation(e.parentOpts.Namespace, depName)
-			depInstallation.SetLabel("sh.porter.parentInstallation", e.parentArgs.Installation.String())
-			// For now, assume it's okay to give the dependency the same credentials as the parent
-			depInstallation.CredentialSets = e.parentInstallation.CredentialSets
-			if err = e.Installations.InsertInstallation(ctx, depInstallation); err != nil {
-				return err
-			}
-		} else {
+		if !errors.Is(err, storage.ErrNotFound{}) {
+			return err
+		}
+		if err = e.Installations.InsertInstallation(ctx, depInstallation); err != nil {
 			return err
 		}
 	}
 
-	finalParams, err := e.porter.finalizeParameters(ctx, depInstallation, dep.BundleReference.Definition, e.parentArgs.Action, dep.Parameters)
+	depParams, err := e.resolveDependencyParameters(ctx, dep, false)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	finalParams, err := e.porter.finalizeParameters(ctx, depInstallation, dep.BundleReference.Definition, e.parentArgs.Action, depParams)
 	if err != nil {
 		return span.Error(fmt.Errorf("error resolving parameters for dependency %s: %w", dep.Alias, err))
 	}
@@ -304,8 +490,15 @@ func (e *dependencyExecutioner) executeDependency(ctx context.Context, dep *queu
 		Installation:          depInstallation,
 		Driver:                e.parentArgs.Driver,
 		AllowDockerHostAccess: e.parentOpts.AllowDockerHostAccess,
+		DriverOptions:         e.parentArgs.DriverOptions,
 		Params:                finalParams,
 		PersistLogs:           e.parentArgs.PersistLogs,
+		LogFormat:             e.parentArgs.LogFormat,
+		Retries:               e.parentArgs.Retries,
+		RetryBackoff:          e.parentArgs.RetryBackoff,
+		Timeout:               e.parentArgs.Timeout,
+		Out:                   e.parentArgs.Out,
+		Err:                   e.parentArgs.Err,
 	}
 
 	// Determine if we're working with UninstallOptions, to inform deletion and
@@ -0,0 +1,154 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-multierror"
+)
+
+// dependencyStatus is the progress of a dependency that is executed in parallel with other dependencies.
+type dependencyStatus int
+
+const (
+	dependencyPending dependencyStatus = iota
+	dependencyRunning
+	dependencySucceeded
+	dependencyFailed
+)
+
+// dependencyResult is the outcome of executing a dependency.
+type dependencyResult struct {
+	alias string
+	err   error
+}
+
+// getDependencyPrerequisites returns the aliases of the dependencies that must be
+// executed before each dependency. A dependency that uses the outputs of another
+// dependency is executed after it, and is uninstalled before it.
+func (e *dependencyExecutioner) getDependencyPrerequisites() map[string][]string {
+	declared := make(map[string]struct{}, len(e.deps))
+	for _, dep := range e.deps {
+		declared[dep.Alias] = struct{}{}
+	}
+
+	prereqs := make(map[string][]string, len(e.deps))
+	addPrerequisite := func(alias string, prereq string) {
+		for _, existing := range prereqs[alias] {
+			if existing == prereq {
+				return
+			}
+		}
+		prereqs[alias] = append(prereqs[alias], prereq)
+	}
+
+	for _, dep := range e.deps {
+		for _, value := range dep.Parameters {
+			for _, ref := range manifest.ParseDependencyParameterReferences(value) {
+				if ref.Dependency == "" || ref.Dependency == dep.Alias {
+					continue
+				}
+				if _, ok := declared[ref.Dependency]; !ok {
+					continue
+				}
+
+				if e.parentArgs.Action == cnab.ActionUninstall {
+					addPrerequisite(ref.Dependency, dep.Alias)
+				} else {
+					addPrerequisite(dep.Alias, ref.Dependency)
+				}
+			}
+		}
+	}
+
+	for alias := range prereqs {
+		sort.Strings(prereqs[alias])
+	}
+	return prereqs
+}
+
+// executeDependenciesInParallel executes up to limit dependencies at the same time, starting
+// each dependency once its prerequisites have succeeded. When a dependency fails, the
+// dependencies that are already running are allowed to complete, and no new dependencies
+// are started.
+func (e *dependencyExecutioner) executeDependenciesInParallel(ctx context.Context, limit int, execute func(context.Context, *queuedDependency) error) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	prereqs := e.getDependencyPrerequisites()
+	status := make(map[string]dependencyStatus, len(e.deps))
+	results := make(chan dependencyResult)
+
+	var execErrs error
+	running, completed := 0, 0
+	for {
+		// Start the dependencies that are ready, in the order they are declared
+		for _, dep := range e.deps {
+			if execErrs != nil || running >= limit {
+				break
+			}
+			if status[dep.Alias] != dependencyPending || !prerequisitesSucceeded(prereqs[dep.Alias], status) {
+				continue
+			}
+
+			status[dep.Alias] = dependencyRunning
+			running++
+			go func(dep *queuedDependency) {
+				results <- dependencyResult{alias: dep.Alias, err: execute(ctx, dep)}
+			}(dep)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+		completed++
+		if result.err != nil {
+			status[result.alias] = dependencyFailed
+			execErrs = multierror.Append(execErrs, result.err)
+			span.Infof("[%d/%d] Dependency %s failed", completed, len(e.deps), result.alias)
+		} else {
+			status[result.alias] = dependencySucceeded
+			span.Infof("[%d/%d] Dependency %s succeeded", completed, len(e.deps), result.alias)
+		}
+	}
+
+	var succeeded, failed int
+	var notExecuted []string
+	for _, dep := range e.deps {
+		switch status[dep.Alias] {
+		case dependencySucceeded:
+			succeeded++
+		case dependencyFailed:
+			failed++
+		default:
+			notExecuted = append(notExecuted, dep.Alias)
+		}
+	}
+	span.Infof("Executed dependencies: %d succeeded, %d failed, %d not executed", succeeded, failed, len(notExecuted))
+
+	if execErrs != nil {
+		return span.Error(execErrs)
+	}
+	if len(notExecuted) > 0 {
+		return span.Error(fmt.Errorf("could not determine the order to execute dependencies %s because they use each other's outputs", strings.Join(notExecuted, ", ")))
+	}
+	return nil
+}
+
+// prerequisitesSucceeded returns true when all the prerequisites of a dependency have succeeded.
+func prerequisitesSucceeded(prereqs []string, status map[string]dependencyStatus) bool {
+	for _, prereq := range prereqs {
+		if status[prereq] != dependencySucceeded {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabprovider "get.porter.sh/porter/pkg/cnab/provider"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newParallelTestExecutioner(action string, deps ...*queuedDependency) *dependencyExecutioner {
+	return &dependencyExecutioner{
+		parentArgs: cnabprovider.ActionArguments{Action: action},
+		deps:       deps,
+	}
+}
+
+func newParallelTestDependency(alias string, params map[string]string) *queuedDependency {
+	return &queuedDependency{
+		DependencyLock: cnab.DependencyLock{Alias: alias},
+		Parameters:     params,
+	}
+}
+
+func TestDependencyExecutioner_getDependencyPrerequisites(t *testing.T) {
+	deps := []*queuedDependency{
+		newParallelTestDependency("mysql", map[string]string{"database-name": "${ bundle.parameters.database }"}),
+		newParallelTestDependency("redis", nil),
+		newParallelTestDependency("wordpress", map[string]string{
+			"db-host":    "${ bundle.dependencies.mysql.outputs.host }",
+			"db-port":    "${ bundle.dependencies.mysql.outputs.port }",
+			"cache-host": "${ bundle.dependencies.redis.outputs.host }",
+			"other":      "${ bundle.dependencies.missing.outputs.host }",
+		}),
+	}
+
+	t.Run("install", func(t *testing.T) {
+		e := newParallelTestExecutioner(cnab.ActionInstall, deps...)
+		assert.Equal(t, map[string][]string{"wordpress": {"mysql", "redis"}}, e.getDependencyPrerequisites())
+	})
+
+	t.Run("uninstall", func(t *testing.T) {
+		e := newParallelTestExecutioner(cnab.ActionUninstall, deps...)
+		assert.Equal(t, map[string][]string{"mysql": {"wordpress"}, "redis": {"wordpress"}}, e.getDependencyPrerequisites())
+	})
+}
+
+func TestDependencyExecutioner_executeDependenciesInParallel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("respects prerequisites and the limit", func(t *testing.T) {
+		e := newParallelTestExecutioner(cnab.ActionInstall,
+			newParallelTestDependency("mysql", nil),
+			newParallelTestDependency("redis", nil),
+			newParallelTestDependency("storage", nil),
+			newParallelTestDependency("wordpress", map[string]string{"db-host": "${ bundle.dependencies.mysql.outputs.host }"}),
+		)
+
+		var mu sync.Mutex
+		var order []string
+		running, maxRunning := 0, 0
+		execute := func(ctx context.Context, dep *queuedDependency) error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			order = append(order, dep.Alias)
+			mu.Unlock()
+			return nil
+		}
+
+		err := e.executeDependenciesInParallel(ctx, 2, execute)
+		require.NoError(t, err)
+		assert.Equal(t, 2, maxRunning, "expected at most 2 dependencies to run at the same time")
+		require.Len(t, order, 4)
+		assert.Greater(t, indexOf(order, "wordpress"), indexOf(order, "mysql"), "wordpress should be executed after mysql")
+	})
+
+	t.Run("stops starting dependencies after a failure", func(t *testing.T) {
+		e := newParallelTestExecutioner(cnab.ActionInstall,
+			newParallelTestDependency("mysql", nil),
+			newParallelTestDependency("wordpress", map[string]string{"db-host": "${ bundle.dependencies.mysql.outputs.host }"}),
+		)
+
+		var executed []string
+		execute := func(ctx context.Context, dep *queuedDependency) error {
+			executed = append(executed, dep.Alias)
+			if dep.Alias == "mysql" {
+				return errors.New("error executing dependency mysql: oops")
+			}
+			return nil
+		}
+
+		err := e.executeDependenciesInParallel(ctx, 2, execute)
+		require.ErrorContains(t, err, "error executing dependency mysql: oops")
+		assert.Equal(t, []string{"mysql"}, executed)
+	})
+
+	t.Run("circular outputs", func(t *testing.T) {
+		e := newParallelTestExecutioner(cnab.ActionInstall,
+			newParallelTestDependency("a", map[string]string{"p": "${ bundle.dependencies.b.outputs.o }"}),
+			newParallelTestDependency("b", map[string]string{"p": "${ bundle.dependencies.a.outputs.o }"}),
+		)
+
+		err := e.executeDependenciesInParallel(ctx, 2, func(ctx context.Context, dep *queuedDependency) error { return nil })
+		require.EqualError(t, err, "could not determine the order to execute dependencies a, b because they use each other's outputs")
+	})
+}
+
+func TestDependencyExecutioner_executeDependency_Parallel(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+	require.NoError(t, p.Storage.WriteSchema(ctx), "failed to set the storage schema")
+
+	// Encrypt sensitive parameters, so that the dependencies share the encryptor.
+	// Wrapping the data key is slow, so that the dependencies request it at the same time.
+	p.Config.Data.Encryption.Key = "my-secret-key"
+	p.Encryptor.Wrapper = slowKeyWrapper{storage.NewLocalKeyWrapper("my-secret-key")}
+	p.CNAB = &cnabprovider.TestRuntime{
+		Runtime: cnabprovider.NewRuntime(p.Config, p.TestInstallations, p.TestCredentials, p.TestSecrets, p.Sanitizer),
+	}
+
+	sensitive := true
+	bun := cnab.NewBundle(bundle.Bundle{
+		SchemaVersion: cnab.BundleSchemaVersion(),
+		Name:          "mydb",
+		Version:       "0.1.0",
+		InvocationImages: []bundle.InvocationImage{
+			{BaseImage: bundle.BaseImage{Image: "example.com/mydb:v0.1.0", ImageType: "docker"}},
+		},
+		Definitions: definition.Definitions{
+			"secret": &definition.Schema{Type: "string", WriteOnly: &sensitive},
+		},
+		Parameters: map[string]bundle.Parameter{
+			"password": {Definition: "secret", Destination: &bundle.Location{EnvironmentVariable: "PASSWORD"}},
+		},
+	})
+
+	parent := storage.NewInstallation("", "myapp")
+	e := newDependencyExecutioner(p.Porter, parent, InstallOptions{BundleExecutionOptions: NewBundleExecutionOptions()})
+	e.parentArgs = cnabprovider.ActionArguments{Action: cnab.ActionInstall}
+	for i := 0; i < 4; i++ {
+		alias := fmt.Sprintf("db%d", i)
+		dep := newParallelTestDependency(alias, map[string]string{"password": "top secret"})
+		dep.BundleReference = cnab.BundleReference{Definition: bun}
+		dep.Installation = storage.NewInstallation("", "myapp-"+alias)
+		dep.Installation.Bundle.Repository = "example.com/mydb"
+		e.deps = append(e.deps, dep)
+	}
+
+	// Run with -race to detect unsafe access to the state shared by the dependencies
+	require.NoError(t, e.executeDependenciesInParallel(ctx, 4, e.executeDependency))
+
+	var keys []storage.DataKey
+	require.NoError(t, p.TestStore.Find(ctx, storage.CollectionDataKeys, storage.FindOptions{}, &keys))
+	assert.Len(t, keys, 1, "the dependencies should share a single data key")
+
+	for _, dep := range e.deps {
+		run, err := p.Installations.GetLastRun(ctx, dep.Installation.Namespace, dep.Installation.Name)
+		require.NoError(t, err, "dependency %s was not executed", dep.Alias)
+		require.Len(t, run.Parameters.Parameters, 1)
+		assert.Equal(t, storage.SourceEncrypted, run.Parameters.Parameters[0].Source.Key, "the password of dependency %s should be encrypted", dep.Alias)
+	}
+}
+
+// slowKeyWrapper delays wrapping a data key.
+type slowKeyWrapper struct {
+	storage.KeyWrapper
+}
+
+func (w slowKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	time.Sleep(50 * time.Millisecond)
+	return w.KeyWrapper.WrapKey(ctx, dataKey)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,100 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyExecutioner_getDependencyOutput(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+	require.NoError(t, p.Storage.WriteSchema(ctx), "failed to set the storage schema")
+	p.Data.Encryption.Key = "my-secret-key"
+
+	dep := newParallelTestDependency("mysql", nil)
+	dep.Installation = p.TestInstallations.CreateInstallation(storage.NewInstallation("", "myapp-mysql"))
+	run := p.TestInstallations.CreateRun(dep.Installation.NewRun(cnab.ActionInstall))
+	result := p.TestInstallations.CreateResult(run.NewResult(cnab.StatusSucceeded))
+
+	p.TestInstallations.CreateOutput(result.NewOutput("host", []byte("mysql.example.com")))
+
+	// Encrypted outputs are saved with the ciphertext as the value, and without a key in the secret store
+	encrypted, err := p.Encryptor.Encrypt(ctx, "top secret")
+	require.NoError(t, err)
+	p.TestInstallations.CreateOutput(result.NewOutput("password", []byte(encrypted)), func(o *storage.Output) { o.Encrypted = true })
+
+	e := newDependencyExecutioner(p.Porter, storage.NewInstallation("", "myapp"), InstallOptions{BundleExecutionOptions: NewBundleExecutionOptions()})
+	e.deps = []*queuedDependency{dep}
+
+	host, err := e.getDependencyOutput(ctx, "mysql", "host")
+	require.NoError(t, err)
+	assert.Equal(t, "mysql.example.com", host)
+
+	password, err := e.getDependencyOutput(ctx, "mysql", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "top secret", password, "the encrypted output should be decrypted")
+
+	_, err = e.getDependencyOutput(ctx, "mysql", "missing")
+	require.ErrorContains(t, err, "dependency mysql (installation /myapp-mysql) has no output named missing")
+}
+
+func TestPorter_newDependencySolver(t *testing.T) {
+	bun := cnab.NewBundle(bundle.Bundle{
+		Custom: map[string]interface{}{
+			cnab.DependenciesV1ExtensionKey: depsv1.Dependencies{
+				Requires: map[string]depsv1.Dependency{
+					"mysql": {
+						Name:    "mysql",
+						Bundle:  "localhost:5000/mysql",
+						Version: &depsv1.DependencyVersion{Ranges: []string{"1.x"}},
+					},
+				},
+			},
+		},
+	})
+
+	t.Run("lists tags with the registry", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		var listed []string
+		p.TestRegistry.MockListTags = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) ([]string, error) {
+			listed = append(listed, ref.Repository())
+			assert.True(t, opts.InsecureRegistry, "the registry options should be passed to the registry")
+			return []string{"v1.0.0", "v1.2.0", "v2.0.0"}, nil
+		}
+
+		solver := p.newDependencySolver(p.RootContext, cnabtooci.RegistryOptions{InsecureRegistry: true})
+		locks, err := solver.ResolveDependencies(bun)
+		require.NoError(t, err)
+		require.Len(t, locks, 1)
+		assert.Equal(t, "localhost:5000/mysql:v1.2.0", locks[0].Reference)
+		assert.Equal(t, []string{"localhost:5000/mysql"}, listed)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.Offline = true
+
+		p.TestRegistry.MockListTags = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) ([]string, error) {
+			t.Fatal("the registry should not be contacted in offline mode")
+			return nil, nil
+		}
+
+		solver := p.newDependencySolver(p.RootContext, cnabtooci.RegistryOptions{})
+		_, err := solver.ResolveDependencies(bun)
+		require.ErrorIs(t, err, config.ErrOffline)
+		assert.Contains(t, err.Error(), "cannot list the tags of localhost:5000/mysql")
+	})
+}
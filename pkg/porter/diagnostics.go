@@ -0,0 +1,301 @@
+package porter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/encoding"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// diagnosticsDir is the directory in the diagnostics tarball that contains the collected files.
+	diagnosticsDir = "porter-diagnostics"
+
+	// diagnosticsMaxLogFiles is the number of Porter's most recent log files that are collected.
+	diagnosticsMaxLogFiles = 10
+)
+
+// DiagnosticsOptions are the options for collecting diagnostics with porter diagnostics.
+type DiagnosticsOptions struct {
+	// File is the path of the tarball that is created.
+	File string
+
+	// Runs is the number of installations, most recently modified first, whose
+	// last run logs are collected.
+	Runs int
+}
+
+// Validate the options provided to Porter's diagnostics command.
+func (o *DiagnosticsOptions) Validate() error {
+	if o.Runs < 0 {
+		return errors.New("--runs must be 0 or greater")
+	}
+
+	if o.File == "" {
+		o.File = fmt.Sprintf("porter-diagnostics-%s.tgz", time.Now().UTC().Format("20060102-150405"))
+	}
+	return nil
+}
+
+// DiagnosticCheck is the result of checking that Porter can use one of its dependencies.
+type DiagnosticCheck struct {
+	// Name of the check.
+	Name string `json:"name"`
+
+	// Passed is true when the check succeeded.
+	Passed bool `json:"passed"`
+
+	// Duration of the check.
+	Duration string `json:"duration"`
+
+	// Error is the reason that the check failed.
+	Error string `json:"error,omitempty"`
+}
+
+// diagnosticsFile is a file collected for the diagnostics tarball.
+type diagnosticsFile struct {
+	Name     string
+	Contents []byte
+}
+
+// CollectDiagnostics gathers information about Porter and its environment into
+// a tarball that can be attached to a bug report: version and system
+// information, the configuration with sensitive values masked, the installed
+// mixins and plugins, the results of connectivity checks, Porter's recent log
+// files, and the logs of the most recent runs.
+// Collection is best effort, problems are recorded in errors.txt in the tarball.
+func (p *Porter) CollectDiagnostics(ctx context.Context, opts DiagnosticsOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("file", opts.File))
+	defer span.EndSpan()
+
+	var files []diagnosticsFile
+	var problems []string
+	add := func(name string, contents []byte) {
+		files = append(files, diagnosticsFile{Name: name, Contents: contents})
+	}
+	addData := func(name string, format string, data interface{}) {
+		contents, err := encoding.Marshal(format, data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("could not write %s: %s", name, err))
+			return
+		}
+		add(name, contents)
+	}
+	report := func(what string, err error) {
+		span.Debugf("could not collect %s: %s", what, err)
+		problems = append(problems, fmt.Sprintf("could not collect %s: %s", what, err))
+	}
+
+	sysInfo := SystemDebugInfo{
+		Version: pkgmgmt.Metadata{
+			Name: "porter",
+			VersionInfo: pkgmgmt.VersionInfo{
+				Version: pkg.Version,
+				Commit:  pkg.Commit,
+			},
+		},
+		SysInfo: *getSystemInfo(),
+	}
+	if mixins, err := p.ListMixins(ctx); err != nil {
+		report("the installed mixins", err)
+	} else {
+		sysInfo.Mixins = mixins
+	}
+	addData("version.json", "json", sysInfo)
+
+	if installedPlugins, err := p.ListPlugins(ctx); err != nil {
+		report("the installed plugins", err)
+	} else {
+		addData("plugins.json", "json", installedPlugins)
+	}
+
+	if settings, err := p.Config.GetRedactedSettings(); err != nil {
+		report("the configuration", err)
+	} else {
+		addData("config.yaml", "yaml", settings)
+	}
+
+	addData("checks.json", "json", p.runDiagnosticChecks(ctx))
+
+	logFiles, err := p.collectLogFiles()
+	if err != nil {
+		report("Porter's log files", err)
+	}
+	files = append(files, logFiles...)
+
+	runLogs, err := p.collectRunLogs(ctx, opts.Runs)
+	if err != nil {
+		report("the logs of recent runs", err)
+	}
+	files = append(files, runLogs...)
+
+	if len(problems) > 0 {
+		add("errors.txt", []byte(strings.Join(problems, "\n")+"\n"))
+	}
+
+	if err := p.writeDiagnosticsTarball(opts.File, files); err != nil {
+		return span.Error(err)
+	}
+
+	p.printInfof("Wrote diagnostics to %s\n", opts.File)
+	p.printInfof("Sensitive configuration values are masked, but review the logs in the tarball before sharing it, because bundles may print sensitive data.\n")
+	return nil
+}
+
+// runDiagnosticChecks checks that Porter can connect to its dependencies.
+func (p *Porter) runDiagnosticChecks(ctx context.Context) []DiagnosticCheck {
+	check := func(name string, run func() error) DiagnosticCheck {
+		start := time.Now()
+		err := run()
+		result := DiagnosticCheck{
+			Name:     name,
+			Passed:   err == nil,
+			Duration: time.Since(start).Round(time.Millisecond).String(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	}
+
+	return []DiagnosticCheck{
+		check("storage", func() error {
+			_, err := p.Installations.ListInstallations(ctx, storage.ListOptions{Namespace: "*", Limit: 1})
+			return err
+		}),
+	}
+}
+
+// collectLogFiles returns Porter's most recent log files, which are written
+// when logs.log-to-file is set in the config file.
+func (p *Porter) collectLogFiles() ([]diagnosticsFile, error) {
+	home, err := p.GetHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	logDir := filepath.Join(home, "logs")
+
+	exists, err := p.FileSystem.DirExists(logDir)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	entries, err := p.FileSystem.ReadDir(logDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Most recent first
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().After(entries[j].ModTime())
+	})
+
+	var files []diagnosticsFile
+	for _, entry := range entries {
+		if len(files) == diagnosticsMaxLogFiles {
+			break
+		}
+		if entry.IsDir() {
+			continue
+		}
+
+		contents, err := p.FileSystem.ReadFile(filepath.Join(logDir, entry.Name()))
+		if err != nil {
+			return files, err
+		}
+		files = append(files, diagnosticsFile{Name: path.Join("logs", entry.Name()), Contents: contents})
+	}
+	return files, nil
+}
+
+// collectRunLogs returns the logs of the last run of the installations that
+// were most recently modified.
+func (p *Porter) collectRunLogs(ctx context.Context, limit int) ([]diagnosticsFile, error) {
+	if limit == 0 {
+		return nil, nil
+	}
+
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{Namespace: "*"})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(installations, func(i, j int) bool {
+		return installations[i].Status.Modified.After(installations[j].Status.Modified)
+	})
+
+	var files []diagnosticsFile
+	for _, installation := range installations {
+		if len(files) == limit {
+			break
+		}
+		if installation.Status.RunID == "" {
+			continue
+		}
+
+		logs, ok, err := p.Installations.GetLogs(ctx, installation.Status.RunID)
+		if err != nil {
+			return files, fmt.Errorf("could not get the logs of run %s of installation %s: %w", installation.Status.RunID, installation, err)
+		}
+		if !ok {
+			continue
+		}
+
+		namespace := installation.Namespace
+		if namespace == "" {
+			namespace = "global"
+		}
+		name := path.Join("runs", namespace, installation.Name, installation.Status.RunID+".log")
+		files = append(files, diagnosticsFile{Name: name, Contents: []byte(logs)})
+	}
+	return files, nil
+}
+
+// writeDiagnosticsTarball writes the collected files to a gzipped tarball.
+func (p *Porter) writeDiagnosticsTarball(file string, files []diagnosticsFile) error {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+
+	modified := time.Now()
+	for _, f := range files {
+		header := &tar.Header{
+			Name:    path.Join(diagnosticsDir, f.Name),
+			Mode:    int64(pkg.FileModeWritable),
+			Size:    int64(len(f.Contents)),
+			ModTime: modified,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("error writing %s to the diagnostics tarball: %w", f.Name, err)
+		}
+		if _, err := tw.Write(f.Contents); err != nil {
+			return fmt.Errorf("error writing %s to the diagnostics tarball: %w", f.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("error closing the diagnostics tarball: %w", err)
+	}
+	if err := gzw.Close(); err != nil {
+		return fmt.Errorf("error compressing the diagnostics tarball: %w", err)
+	}
+
+	if err := p.FileSystem.WriteFile(file, buf.Bytes(), pkg.FileModeWritable); err != nil {
+		return fmt.Errorf("error writing the diagnostics tarball to %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package porter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticsOptions_Validate(t *testing.T) {
+	opts := DiagnosticsOptions{}
+	require.NoError(t, opts.Validate())
+	assert.Regexp(t, `^porter-diagnostics-\d{8}-\d{6}\.tgz$`, opts.File, "the file should be defaulted")
+
+	opts = DiagnosticsOptions{Runs: -1}
+	require.EqualError(t, opts.Validate(), "--runs must be 0 or greater")
+}
+
+func TestPorter_CollectDiagnostics(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	home, err := p.GetHomeDir()
+	require.NoError(t, err)
+	configFile := `agent-token: top-secret
+secrets:
+  - name: vault
+    plugin: hashicorp.vault
+    config:
+      apikey: ${env.VAULT_API_KEY}
+`
+	p.Setenv("VAULT_API_KEY", "env-sourced-secret")
+	require.NoError(t, p.TestConfig.TestContext.AddTestFileContents([]byte(configFile), home+"/config.yaml"))
+	require.NoError(t, p.TestConfig.TestContext.AddTestFileContents([]byte(`{"msg":"a thing happened"}`), home+"/logs/0.json"))
+	p.DataLoader = config.LoadFromFilesystem()
+	_, err = p.Config.Load(p.RootContext, nil)
+	require.NoError(t, err)
+
+	i := p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "mybuns"))
+	run := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+	result := p.TestInstallations.CreateResult(run.NewResult(cnab.StatusSucceeded), func(r *storage.Result) {
+		r.OutputMetadata.SetGeneratedByBundle(cnab.OutputInvocationImageLogs, false)
+	})
+	p.TestInstallations.CreateOutput(result.NewOutput(cnab.OutputInvocationImageLogs, []byte("installing mybuns")))
+	i.ApplyResult(run, result)
+	require.NoError(t, p.Installations.UpdateInstallation(p.RootContext, i))
+
+	opts := DiagnosticsOptions{File: "diagnostics.tgz", Runs: 10}
+	require.NoError(t, p.CollectDiagnostics(p.RootContext, opts))
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Wrote diagnostics to diagnostics.tgz")
+
+	contents, err := p.FileSystem.ReadFile("diagnostics.tgz")
+	require.NoError(t, err)
+	files := readTestTarball(t, contents)
+
+	assert.Contains(t, files, "porter-diagnostics/version.json")
+	assert.Contains(t, files, "porter-diagnostics/plugins.json")
+	assert.Contains(t, files["porter-diagnostics/config.yaml"], "agent-token: '******'")
+	assert.NotContains(t, files, "porter-diagnostics/errors.txt", "no problems should have been found")
+	assert.Equal(t, `{"msg":"a thing happened"}`, files["porter-diagnostics/logs/0.json"])
+	assert.Equal(t, "installing mybuns", files["porter-diagnostics/runs/dev/mybuns/"+run.ID+".log"])
+
+	var checks []DiagnosticCheck
+	require.NoError(t, json.Unmarshal([]byte(files["porter-diagnostics/checks.json"]), &checks))
+	require.Len(t, checks, 1)
+	assert.Equal(t, "storage", checks[0].Name)
+	assert.True(t, checks[0].Passed, "the storage check should pass: %s", checks[0].Error)
+
+	for name, contents := range files {
+		assert.NotContains(t, contents, "top-secret", "%s should not contain the agent token", name)
+		assert.NotContains(t, contents, "env-sourced-secret", "%s should not contain the plugin config from the environment", name)
+	}
+
+	t.Run("exclude runs", func(t *testing.T) {
+		opts := DiagnosticsOptions{File: "no-runs.tgz", Runs: 0}
+		require.NoError(t, p.CollectDiagnostics(p.RootContext, opts))
+
+		contents, err := p.FileSystem.ReadFile("no-runs.tgz")
+		require.NoError(t, err)
+		for name := range readTestTarball(t, contents) {
+			assert.NotContains(t, name, "porter-diagnostics/runs/")
+		}
+	})
+}
+
+// readTestTarball returns the contents of each file in a gzipped tarball.
+func readTestTarball(t *testing.T, contents []byte) map[string]string {
+	gzr, err := gzip.NewReader(bytes.NewReader(contents))
+	require.NoError(t, err)
+	tr := tar.NewReader(gzr)
+
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(data)
+	}
+	return files
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
 	configadapter "get.porter.sh/porter/pkg/cnab/config-adapter"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
@@ -19,6 +20,10 @@ type ExplainOpts struct {
 	printer.PrintOptions
 
 	Action string
+
+	// Check validates that the current host satisfies the requirements of the
+	// bundle, instead of explaining the bundle.
+	Check bool
 }
 
 // PrintableBundle holds a subset of pertinent values to be explained from a bundle
@@ -33,6 +38,43 @@ type PrintableBundle struct {
 	Actions       []PrintableAction     `json:"customActions,omitempty" yaml:"customActions,omitempty"`
 	Dependencies  []PrintableDependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 	Mixins        []string              `json:"mixins" yaml:"mixins"`
+
+	PrintableBundleMetadata `yaml:",inline"`
+
+	// MinimumPorterVersion is the oldest version of Porter that can run the bundle.
+	MinimumPorterVersion string `json:"minimumPorterVersion,omitempty" yaml:"minimumPorterVersion,omitempty"`
+
+	// RequiredExtensions are the CNAB extensions that the bundle runtime must support.
+	RequiredExtensions []PrintableRequiredExtension `json:"requiredExtensions,omitempty" yaml:"requiredExtensions,omitempty"`
+
+	// Prerequisites are the commands that the mixins used by the bundle require on the host.
+	Prerequisites []PrintablePrerequisite `json:"prerequisites,omitempty" yaml:"prerequisites,omitempty"`
+}
+
+// PrintableBundleMetadata is the descriptive metadata of a bundle: who maintains
+// it, how it is licensed, where it is documented and its annotations.
+type PrintableBundleMetadata struct {
+	Maintainers   []PrintableMaintainer `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+	License       string                `json:"license,omitempty" yaml:"license,omitempty"`
+	Documentation string                `json:"documentation,omitempty" yaml:"documentation,omitempty"`
+	Annotations   map[string]string     `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+type PrintableMaintainer struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+type PrintableRequiredExtension struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Supported   bool   `json:"supported" yaml:"supported"`
+}
+
+type PrintablePrerequisite struct {
+	Command string `json:"command" yaml:"command"`
+	Mixin   string `json:"mixin" yaml:"mixin"`
 }
 
 type PrintableCredential struct {
@@ -80,6 +122,12 @@ func (s SortPrintableOutput) Swap(i, j int) {
 type PrintableDependency struct {
 	Alias     string `json:"alias" yaml:"alias"`
 	Reference string `json:"reference" yaml:"reference"`
+
+	// SharingGroup is the sharing group of a dependency that may be satisfied by an existing installation.
+	SharingGroup string `json:"sharingGroup,omitempty" yaml:"sharingGroup,omitempty"`
+
+	// Parameters passed to the dependency, which may reference parameters of the bundle and the outputs of other dependencies.
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 }
 
 type PrintableParameter struct {
@@ -114,6 +162,15 @@ type PrintableAction struct {
 	Stateless bool `json:"stateless" yaml:"stateless"`
 	// Description describes the action as a user-readable string
 	Description string `json:"description" yaml:"description"`
+	// Arguments that may be passed to the action with porter invoke --arg NAME=VALUE
+	Arguments []PrintableActionArgument `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+}
+
+type PrintableActionArgument struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Default     string `json:"default,omitempty" yaml:"default,omitempty"`
+	Required    bool   `json:"required" yaml:"required"`
 }
 
 type SortPrintableAction []PrintableAction
@@ -162,10 +219,15 @@ func (p *Porter) Explain(ctx context.Context, o ExplainOpts) error {
 		return err
 	}
 
-	pb, err := generatePrintable(bundleRef.Definition, o.Action)
+	solver := p.newDependencySolver(ctx, cnabtooci.RegistryOptions{InsecureRegistry: o.InsecureRegistry})
+	pb, err := generatePrintable(bundleRef.Definition, o.Action, solver)
 	if err != nil {
 		return fmt.Errorf("unable to print bundle: %w", err)
 	}
+
+	if o.Check {
+		return p.checkBundleRequirements(o, pb, bundleRef.Definition)
+	}
 	return p.printBundleExplain(o, pb, bundleRef.Definition)
 }
 
@@ -182,7 +244,7 @@ func (p *Porter) printBundleExplain(o ExplainOpts, pb *PrintableBundle, bun cnab
 	}
 }
 
-func generatePrintable(bun cnab.ExtendedBundle, action string) (*PrintableBundle, error) {
+func generatePrintable(bun cnab.ExtendedBundle, action string, solver *cnab.DependencySolver) (*PrintableBundle, error) {
 	var stamp configadapter.Stamp
 
 	stamp, err := configadapter.LoadStamp(bun)
@@ -190,7 +252,6 @@ func generatePrintable(bun cnab.ExtendedBundle, action string) (*PrintableBundle
 		stamp = configadapter.Stamp{}
 	}
 
-	solver := &cnab.DependencySolver{}
 	deps, err := solver.ResolveDependencies(bun)
 	if err != nil {
 		return nil, fmt.Errorf("error resolving bundle dependencies: %w", err)
@@ -208,6 +269,12 @@ func generatePrintable(bun cnab.ExtendedBundle, action string) (*PrintableBundle
 		Dependencies:  make([]PrintableDependency, 0, len(deps)),
 		Mixins:        make([]string, 0, len(stamp.Mixins)),
 	}
+	pb.MinimumPorterVersion = stamp.MinimumPorterVersion
+
+	pb.PrintableBundleMetadata, err = generatePrintableMetadata(bun)
+	if err != nil {
+		return nil, err
+	}
 
 	for a, v := range bun.Actions {
 		pa := PrintableAction{}
@@ -215,6 +282,14 @@ func generatePrintable(bun cnab.ExtendedBundle, action string) (*PrintableBundle
 		pa.Description = v.Description
 		pa.Modifies = v.Modifies
 		pa.Stateless = v.Stateless
+		for _, arg := range bun.GetActionArguments(a) {
+			pa.Arguments = append(pa.Arguments, PrintableActionArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Default:     arg.Default,
+				Required:    arg.Required,
+			})
+		}
 		pb.Actions = append(pb.Actions, pa)
 	}
 	sort.Sort(SortPrintableAction(pb.Actions))
@@ -288,6 +363,8 @@ func generatePrintable(bun cnab.ExtendedBundle, action string) (*PrintableBundle
 		pd := PrintableDependency{}
 		pd.Alias = dep.Alias
 		pd.Reference = dep.Reference
+		pd.SharingGroup = dep.SharingGroup
+		pd.Parameters = dep.Parameters
 
 		pb.Dependencies = append(pb.Dependencies, pd)
 	}
@@ -298,9 +375,45 @@ func generatePrintable(bun cnab.ExtendedBundle, action string) (*PrintableBundle
 	}
 	sort.Strings(pb.Mixins)
 
+	for _, ext := range bun.RequiredExtensions {
+		pe := PrintableRequiredExtension{Name: ext}
+		if supported, err := cnab.GetSupportedExtension(ext); err == nil {
+			pe.Description = supported.Description
+			pe.Supported = true
+		} else {
+			pe.Description = "Not supported by this version of Porter"
+		}
+		pb.RequiredExtensions = append(pb.RequiredExtensions, pe)
+	}
+
+	for _, mixin := range pb.Mixins {
+		for _, cmd := range stamp.Mixins[mixin].Prerequisites {
+			pb.Prerequisites = append(pb.Prerequisites, PrintablePrerequisite{Command: cmd, Mixin: mixin})
+		}
+	}
+
 	return &pb, nil
 }
 
+// generatePrintableMetadata reads the maintainers, license, documentation url
+// and annotations of the bundle.
+func generatePrintableMetadata(bun cnab.ExtendedBundle) (PrintableBundleMetadata, error) {
+	metadata, err := bun.GetBundleMetadata()
+	if err != nil {
+		return PrintableBundleMetadata{}, err
+	}
+
+	pm := PrintableBundleMetadata{
+		License:       bun.License,
+		Documentation: metadata.Documentation,
+		Annotations:   metadata.Annotations,
+	}
+	for _, m := range bun.Maintainers {
+		pm.Maintainers = append(pm.Maintainers, PrintableMaintainer{Name: m.Name, Email: m.Email, URL: m.URL})
+	}
+	return pm, nil
+}
+
 // shouldIncludeInExplainOutput determine if a scoped item such as a credential, parameter or output
 // should be included in the explain output.
 func shouldIncludeInExplainOutput(scoped bundle.Scoped, action string) bool {
@@ -326,13 +439,21 @@ func (p *Porter) printBundleExplainTable(bun *PrintableBundle, bundleReference s
 	if bun.PorterVersion != "" {
 		fmt.Fprintf(p.Out, "Porter Version: %s\n", bun.PorterVersion)
 	}
+	if bun.MinimumPorterVersion != "" {
+		fmt.Fprintf(p.Out, "Minimum Porter Version: %s\n", bun.MinimumPorterVersion)
+	}
+	p.printBundleMetadataHeader(bun.PrintableBundleMetadata)
 	fmt.Fprintln(p.Out, "")
 
+	p.printBundleMetadataBlock(bun.PrintableBundleMetadata)
+
 	p.printCredentialsExplainBlock(bun)
 	p.printParametersExplainBlock(bun)
 	p.printOutputsExplainBlock(bun)
 	p.printActionsExplainBlock(bun)
 	p.printDependenciesExplainBlock(bun)
+	p.printRequiredExtensionsExplainBlock(bun)
+	p.printPrerequisitesExplainBlock(bun)
 
 	if extendedBundle.IsPorterBundle() && len(bun.Mixins) > 0 {
 		fmt.Fprintf(p.Out, "This bundle uses the following tools: %s.\n", strings.Join(bun.Mixins, ", "))
@@ -348,6 +469,57 @@ func (p *Porter) printBundleExplainTable(bun *PrintableBundle, bundleReference s
 	return nil
 }
 
+// printBundleMetadataHeader prints the license and documentation url of the bundle, when set.
+func (p *Porter) printBundleMetadataHeader(metadata PrintableBundleMetadata) {
+	if metadata.License != "" {
+		fmt.Fprintf(p.Out, "License: %s\n", metadata.License)
+	}
+	if metadata.Documentation != "" {
+		fmt.Fprintf(p.Out, "Documentation: %s\n", metadata.Documentation)
+	}
+}
+
+// printBundleMetadataBlock prints the maintainers and annotations of the bundle, when set.
+func (p *Porter) printBundleMetadataBlock(metadata PrintableBundleMetadata) error {
+	if len(metadata.Maintainers) > 0 {
+		fmt.Fprintln(p.Out, "Maintainers:")
+		printMaintainerRow :=
+			func(v interface{}) []string {
+				m, ok := v.(PrintableMaintainer)
+				if !ok {
+					return nil
+				}
+				return []string{m.Name, m.Email, m.URL}
+			}
+		err := printer.PrintTable(p.Out, metadata.Maintainers, printMaintainerRow, "Name", "Email", "URL")
+		if err != nil {
+			return fmt.Errorf("unable to print maintainers table: %w", err)
+		}
+		fmt.Fprintln(p.Out, "") // force a blank line after this block
+	}
+
+	if len(metadata.Annotations) > 0 {
+		keys := make([]string, 0, len(metadata.Annotations))
+		for k := range metadata.Annotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		// Print the annotations without wrapping the values
+		fmt.Fprintln(p.Out, "Annotations:")
+		table := printer.NewTableSection(p.Out)
+		table.SetAutoWrapText(false)
+		table.SetHeader([]string{"Key", "Value"})
+		for _, k := range keys {
+			table.Append([]string{k, metadata.Annotations[k]})
+		}
+		table.Render()
+		fmt.Fprintln(p.Out, "") // force a blank line after this block
+	}
+
+	return nil
+}
+
 func (p *Porter) printCredentialsExplainBlock(bun *PrintableBundle) error {
 	if len(bun.Credentials) == 0 {
 		return nil
@@ -451,7 +623,36 @@ func (p *Porter) printActionsExplainTable(bun *PrintableBundle) error {
 			}
 			return []string{a.Name, a.Description, strconv.FormatBool(a.Modifies), strconv.FormatBool(a.Stateless)}
 		}
-	return printer.PrintTable(p.Out, bun.Actions, printActionRow, "Name", "Description", "Modifies Installation", "Stateless")
+	err := printer.PrintTable(p.Out, bun.Actions, printActionRow, "Name", "Description", "Modifies Installation", "Stateless")
+	if err != nil {
+		return err
+	}
+
+	type actionArgument struct {
+		Action string
+		PrintableActionArgument
+	}
+	var args []actionArgument
+	for _, a := range bun.Actions {
+		for _, arg := range a.Arguments {
+			args = append(args, actionArgument{Action: a.Name, PrintableActionArgument: arg})
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(p.Out, "")
+	fmt.Fprintln(p.Out, "Action Arguments:")
+	printArgumentRow :=
+		func(v interface{}) []string {
+			a, ok := v.(actionArgument)
+			if !ok {
+				return nil
+			}
+			return []string{a.Action, a.Name, a.Description, a.Default, strconv.FormatBool(a.Required)}
+		}
+	return printer.PrintTable(p.Out, args, printArgumentRow, "Action", "Name", "Description", "Default", "Required")
 }
 
 // Dependencies
@@ -477,9 +678,79 @@ func (p *Porter) printDependenciesExplainTable(bun *PrintableBundle) error {
 			if !ok {
 				return nil
 			}
-			return []string{o.Alias, o.Reference}
+			return []string{o.Alias, o.Reference, o.SharingGroup}
+		}
+	err := printer.PrintTable(p.Out, bun.Dependencies, printDependencyRow, "Alias", "Reference", "Sharing Group")
+	if err != nil {
+		return err
+	}
+
+	// Print how the parameters of the dependencies are wired up, without wrapping the templates
+	var rows [][]string
+	for _, dep := range bun.Dependencies {
+		names := make([]string, 0, len(dep.Parameters))
+		for name := range dep.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			rows = append(rows, []string{dep.Alias, name, dep.Parameters[name]})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(p.Out, "")
+	fmt.Fprintln(p.Out, "Dependency Parameters:")
+	table := printer.NewTableSection(p.Out)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"Dependency", "Parameter", "Value"})
+	table.AppendBulk(rows)
+	table.Render()
+	return nil
+}
+
+func (p *Porter) printRequiredExtensionsExplainBlock(bun *PrintableBundle) error {
+	if len(bun.RequiredExtensions) == 0 {
+		return nil
+	}
+
+	// Print the descriptions without wrapping them
+	fmt.Fprintln(p.Out, "Required Extensions:")
+	table := printer.NewTableSection(p.Out)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"Name", "Description"})
+	for _, ext := range bun.RequiredExtensions {
+		table.Append([]string{ext.Name, ext.Description})
+	}
+	table.Render()
+
+	fmt.Fprintln(p.Out, "") // force a blank line after this block
+	return nil
+}
+
+func (p *Porter) printPrerequisitesExplainBlock(bun *PrintableBundle) error {
+	if len(bun.Prerequisites) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(p.Out, "Host Prerequisites:")
+	printPrerequisiteRow :=
+		func(v interface{}) []string {
+			pr, ok := v.(PrintablePrerequisite)
+			if !ok {
+				return nil
+			}
+			return []string{pr.Command, pr.Mixin}
 		}
-	return printer.PrintTable(p.Out, bun.Dependencies, printDependencyRow, "Alias", "Reference")
+	err := printer.PrintTable(p.Out, bun.Prerequisites, printPrerequisiteRow, "Command", "Required By")
+	if err != nil {
+		return fmt.Errorf("unable to print host prerequisites table: %w", err)
+	}
+
+	fmt.Fprintln(p.Out, "") // force a blank line after this block
+	return nil
 }
 
 func (p *Porter) printInstallationInstructionBlock(bun *PrintableBundle, bundleReference string, extendedBundle cnab.ExtendedBundle) error {
@@ -0,0 +1,173 @@
+package porter
+
+import (
+	"fmt"
+	"strings"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	// RequirementOK indicates that the host satisfies the requirement.
+	RequirementOK = "ok"
+
+	// RequirementWarning indicates that the host may not satisfy the
+	// requirement, or that it needs additional flags when the bundle is run.
+	RequirementWarning = "warning"
+
+	// RequirementError indicates that the host does not satisfy the requirement.
+	RequirementError = "error"
+)
+
+// RequirementCheck is the result of checking that the current host satisfies
+// a requirement of a bundle, with porter explain --check.
+type RequirementCheck struct {
+	// Requirement that was checked, for example extension io.cnab.docker.
+	Requirement string `json:"requirement" yaml:"requirement"`
+
+	// Status of the check: ok, warning or error.
+	Status string `json:"status" yaml:"status"`
+
+	// Message explains a warning or an error.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// checkBundleRequirements validates that the current host satisfies the
+// minimum Porter version, required extensions and host prerequisites of the
+// bundle, and prints the results. Returns an error when a requirement is not
+// satisfied.
+func (p *Porter) checkBundleRequirements(o ExplainOpts, pb *PrintableBundle, bun cnab.ExtendedBundle) error {
+	results := p.checkRequirements(pb, bun)
+
+	if err := p.printRequirementChecks(o, results); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, result := range results {
+		if result.Status == RequirementError {
+			failed = append(failed, result.Requirement)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("the host does not satisfy %d requirement(s) of the bundle: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (p *Porter) checkRequirements(pb *PrintableBundle, bun cnab.ExtendedBundle) []RequirementCheck {
+	results := []RequirementCheck{}
+
+	if pb.MinimumPorterVersion != "" {
+		results = append(results, checkPorterVersion(pb.MinimumPorterVersion, pkg.Version))
+	}
+
+	for _, ext := range pb.RequiredExtensions {
+		results = append(results, p.checkRequiredExtension(ext, bun))
+	}
+
+	for _, prereq := range pb.Prerequisites {
+		result := RequirementCheck{
+			Requirement: "command " + prereq.Command,
+			Status:      RequirementOK,
+		}
+		if _, ok := p.LookPath(prereq.Command); !ok {
+			result.Status = RequirementError
+			result.Message = fmt.Sprintf("%s was not found on the PATH, it is required by the %s mixin", prereq.Command, prereq.Mixin)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// checkPorterVersion compares the version of Porter with the minimum version required by the bundle.
+func checkPorterVersion(minimum string, current string) RequirementCheck {
+	result := RequirementCheck{
+		Requirement: "porter " + minimum,
+		Status:      RequirementOK,
+	}
+
+	minVersion, err := semver.NewVersion(minimum)
+	if err != nil {
+		result.Status = RequirementError
+		result.Message = fmt.Sprintf("the minimum Porter version %q is not a valid semver value: %s", minimum, err)
+		return result
+	}
+
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		// Development builds of Porter do not have a release version to compare
+		result.Status = RequirementWarning
+		result.Message = fmt.Sprintf("could not compare the Porter version %q with the minimum version", current)
+		return result
+	}
+
+	if currentVersion.LessThan(minVersion) {
+		result.Status = RequirementError
+		result.Message = fmt.Sprintf("the bundle requires Porter %s or higher but the current version is %s", minimum, current)
+	}
+	return result
+}
+
+// checkRequiredExtension validates that Porter, and the configured runtime, support a required extension.
+func (p *Porter) checkRequiredExtension(ext PrintableRequiredExtension, bun cnab.ExtendedBundle) RequirementCheck {
+	result := RequirementCheck{
+		Requirement: "extension " + ext.Name,
+		Status:      RequirementOK,
+	}
+
+	if !ext.Supported {
+		result.Status = RequirementError
+		result.Message = "the extension is not supported by this version of Porter"
+		return result
+	}
+
+	supported, _ := cnab.GetSupportedExtension(ext.Name)
+	if _, err := supported.Reader(bun); err != nil {
+		result.Status = RequirementError
+		result.Message = fmt.Sprintf("invalid extension configuration: %s", err)
+		return result
+	}
+
+	if supported.Key == cnab.DockerExtensionKey {
+		if p.Data.RuntimeDriver != "" && p.Data.RuntimeDriver != config.RuntimeDriverDocker {
+			result.Status = RequirementError
+			result.Message = fmt.Sprintf("the bundle requires access to the Docker host, which is not available with the %s runtime driver", p.Data.RuntimeDriver)
+		} else if !p.Data.AllowDockerHostAccess {
+			result.Status = RequirementWarning
+			result.Message = "pass --allow-docker-host-access when running the bundle"
+		}
+	}
+
+	return result
+}
+
+func (p *Porter) printRequirementChecks(o ExplainOpts, results []RequirementCheck) error {
+	switch o.Format {
+	case printer.FormatPlaintext:
+		if len(results) == 0 {
+			fmt.Fprintln(p.Out, "The bundle does not declare any requirements for the host.")
+			return nil
+		}
+		// Print the requirements and messages without wrapping them
+		table := printer.NewTableSection(p.Out)
+		table.SetAutoWrapText(false)
+		table.SetHeader([]string{"Requirement", "Status", "Message"})
+		for _, r := range results {
+			table.Append([]string{r.Requirement, r.Status, r.Message})
+		}
+		table.Render()
+		return nil
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, results)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, results)
+	default:
+		return fmt.Errorf("invalid format: %s", o.Format)
+	}
+}
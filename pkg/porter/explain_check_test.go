@@ -0,0 +1,95 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPorterVersion(t *testing.T) {
+	testcases := []struct {
+		name       string
+		minimum    string
+		current    string
+		wantStatus string
+	}{
+		{name: "newer", minimum: "v1.0.0", current: "v1.2.0", wantStatus: RequirementOK},
+		{name: "same", minimum: "v1.0.0", current: "v1.0.0", wantStatus: RequirementOK},
+		{name: "older", minimum: "v1.2.0", current: "v1.0.0", wantStatus: RequirementError},
+		{name: "dev build", minimum: "v1.0.0", current: "dev", wantStatus: RequirementWarning},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result := checkPorterVersion(tc.minimum, tc.current)
+			assert.Equal(t, tc.wantStatus, result.Status, result.Message)
+		})
+	}
+}
+
+func TestExplain_Check(t *testing.T) {
+	origVersion := pkg.Version
+	pkg.Version = "v1.1.0"
+	defer func() { pkg.Version = origVersion }()
+
+	// addCommands puts empty executables for the commands on the PATH
+	addCommands := func(t *testing.T, p *TestPorter, cmds ...string) {
+		p.Setenv("PATH", "/usr/local/bin")
+		for _, cmd := range cmds {
+			require.NoError(t, p.FileSystem.WriteFile("/usr/local/bin/"+cmd, nil, pkg.FileModeExecutable))
+		}
+	}
+
+	loadRequirements := func(t *testing.T, p *TestPorter) ExplainOpts {
+		p.TestConfig.TestContext.AddTestFile("testdata/explain/bundle-requirements.json", "bundle-requirements.json")
+		opts := ExplainOpts{Check: true}
+		opts.CNABFile = "bundle-requirements.json"
+		opts.Format = printer.FormatJson
+		return opts
+	}
+
+	t.Run("missing prerequisites", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		opts := loadRequirements(t, p)
+
+		addCommands(t, p, "helm")
+
+		err := p.Explain(context.Background(), opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the host does not satisfy 1 requirement(s) of the bundle: command kubectl")
+	})
+
+	t.Run("kubernetes driver", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		opts := loadRequirements(t, p)
+
+		p.Data.RuntimeDriver = config.RuntimeDriverKubernetes
+		addCommands(t, p, "helm", "kubectl")
+
+		err := p.Explain(context.Background(), opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "extension io.cnab.docker")
+	})
+
+	t.Run("satisfied", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		opts := loadRequirements(t, p)
+		opts.Format = printer.FormatPlaintext
+
+		p.Data.AllowDockerHostAccess = true
+		addCommands(t, p, "helm", "kubectl")
+
+		err := p.Explain(context.Background(), opts)
+		require.NoError(t, err)
+		p.CompareGoldenFile("testdata/explain/expected-check-output.txt", p.TestConfig.TestContext.GetOutput())
+	})
+}
@@ -0,0 +1,485 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// BundleChangeAdded indicates that an item is only defined by the new version of the bundle.
+	BundleChangeAdded = "added"
+
+	// BundleChangeRemoved indicates that an item is only defined by the old version of the bundle.
+	BundleChangeRemoved = "removed"
+
+	// BundleChangeModified indicates that an item is defined by both versions of the bundle, with different values.
+	BundleChangeModified = "modified"
+)
+
+// ExplainDiffOptions are the options for comparing two versions of a bundle.
+type ExplainDiffOptions struct {
+	printer.PrintOptions
+
+	// OldReference is the bundle that is being upgraded from.
+	OldReference string
+
+	// NewReference is the bundle that is being upgraded to.
+	NewReference string
+
+	// InsecureRegistry allows pulling the bundles from an unsecured registry.
+	InsecureRegistry bool
+
+	// Force pulls the bundles from the registry, even when they are already in the cache.
+	Force bool
+}
+
+// Validate the explain diff options.
+func (o *ExplainDiffOptions) Validate(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("two positional arguments must be specified, the old and new bundle references, but %d were received: %s", len(args), args)
+	}
+	o.OldReference = args[0]
+	o.NewReference = args[1]
+
+	for _, ref := range []string{o.OldReference, o.NewReference} {
+		if _, err := cnab.ParseOCIReference(ref); err != nil {
+			return fmt.Errorf("invalid bundle reference %s, specified value should be of the form REGISTRY/bundle:tag: %w", ref, err)
+		}
+	}
+
+	return o.PrintOptions.Validate(printer.FormatPlaintext, []printer.Format{printer.FormatPlaintext, printer.FormatJson, printer.FormatYaml})
+}
+
+// pullOptions returns the options for pulling one of the compared bundles.
+func (o *ExplainDiffOptions) pullOptions(ref string) BundlePullOptions {
+	return BundlePullOptions{
+		Reference:        ref,
+		InsecureRegistry: o.InsecureRegistry,
+		Force:            o.Force,
+	}
+}
+
+// BundleVersionDiff lists the differences between two versions of a bundle.
+type BundleVersionDiff struct {
+	Old         BundleVersionInfo `json:"old" yaml:"old"`
+	New         BundleVersionInfo `json:"new" yaml:"new"`
+	Parameters  []BundleItemDiff  `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Credentials []BundleItemDiff  `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	Outputs     []BundleItemDiff  `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Images      []BundleItemDiff  `json:"images,omitempty" yaml:"images,omitempty"`
+	Actions     []BundleItemDiff  `json:"customActions,omitempty" yaml:"customActions,omitempty"`
+}
+
+// BundleVersionInfo identifies one of the compared bundles.
+type BundleVersionInfo struct {
+	Reference string `json:"reference" yaml:"reference"`
+	Name      string `json:"name" yaml:"name"`
+	Version   string `json:"version" yaml:"version"`
+}
+
+// BundleItemDiff is an item, such as a parameter, that was added, removed or changed.
+type BundleItemDiff struct {
+	Name   string `json:"name" yaml:"name"`
+	Change string `json:"change" yaml:"change"`
+
+	// Fields are the values of the item that changed. For an added or removed item,
+	// the fields are the values of the item in the version where it is defined.
+	Fields []BundleFieldDiff `json:"fields,omitempty" yaml:"fields,omitempty"`
+
+	// Breaking indicates that the change may require changes to existing installations
+	// or to the commands used to upgrade them, for example a new required parameter.
+	Breaking bool `json:"breaking" yaml:"breaking"`
+}
+
+// BundleFieldDiff is a value of an item that changed.
+type BundleFieldDiff struct {
+	Field string `json:"field" yaml:"field"`
+	Old   string `json:"old,omitempty" yaml:"old,omitempty"`
+	New   string `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// findField returns the change to the named field.
+func (e BundleItemDiff) findField(field string) (BundleFieldDiff, bool) {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return f, true
+		}
+	}
+	return BundleFieldDiff{}, false
+}
+
+// IsEmpty determines if the bundles have no differences.
+func (d BundleVersionDiff) IsEmpty() bool {
+	return len(d.Parameters) == 0 && len(d.Credentials) == 0 && len(d.Outputs) == 0 &&
+		len(d.Images) == 0 && len(d.Actions) == 0
+}
+
+// CountBreaking returns the number of changes that may break an upgrade.
+func (d BundleVersionDiff) CountBreaking() int {
+	count := 0
+	for _, entries := range [][]BundleItemDiff{d.Parameters, d.Credentials, d.Outputs, d.Images, d.Actions} {
+		for _, e := range entries {
+			if e.Breaking {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ExplainDiff compares two versions of a bundle and prints the differences
+// in their parameters, credentials, outputs, images and custom actions.
+func (p *Porter) ExplainDiff(ctx context.Context, opts ExplainDiffOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	oldBundle, err := p.PullBundle(ctx, opts.pullOptions(opts.OldReference))
+	if err != nil {
+		return log.Error(fmt.Errorf("unable to pull bundle %s: %w", opts.OldReference, err))
+	}
+	newBundle, err := p.PullBundle(ctx, opts.pullOptions(opts.NewReference))
+	if err != nil {
+		return log.Error(fmt.Errorf("unable to pull bundle %s: %w", opts.NewReference, err))
+	}
+
+	solver := p.newDependencySolver(ctx, cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry})
+	diff, err := generateBundleDiff(oldBundle.BundleReference, newBundle.BundleReference, solver)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	return p.printBundleDiff(opts, diff)
+}
+
+// diffItem is an item of a bundle, with the values that are compared in the order they are printed.
+type diffItem struct {
+	name   string
+	fields []BundleFieldDiff
+}
+
+// value returns the value of the named field.
+func (i diffItem) value(field string) string {
+	for _, f := range i.fields {
+		if f.Field == field {
+			return f.New
+		}
+	}
+	return ""
+}
+
+func newDiffItem(name string, fields ...string) diffItem {
+	item := diffItem{name: name, fields: make([]BundleFieldDiff, 0, len(fields)/2)}
+	for i := 0; i+1 < len(fields); i += 2 {
+		item.fields = append(item.fields, BundleFieldDiff{Field: fields[i], New: fields[i+1]})
+	}
+	return item
+}
+
+// breakingFunc determines if a change to an item may break an upgrade.
+// The old or new item is nil when the item was added or removed.
+type breakingFunc func(entry BundleItemDiff, oldItem *diffItem, newItem *diffItem) bool
+
+// diffItems compares the items from the old and new versions of a bundle, by name.
+func diffItems(oldItems []diffItem, newItems []diffItem, isBreaking breakingFunc) []BundleItemDiff {
+	oldByName := make(map[string]diffItem, len(oldItems))
+	for _, item := range oldItems {
+		oldByName[item.name] = item
+	}
+	newByName := make(map[string]diffItem, len(newItems))
+	for _, item := range newItems {
+		newByName[item.name] = item
+	}
+
+	var entries []BundleItemDiff
+	for _, oldItem := range oldItems {
+		oldItem := oldItem
+		newItem, ok := newByName[oldItem.name]
+		if !ok {
+			entry := BundleItemDiff{Name: oldItem.name, Change: BundleChangeRemoved}
+			for _, f := range oldItem.fields {
+				entry.Fields = append(entry.Fields, BundleFieldDiff{Field: f.Field, Old: f.New})
+			}
+			entry.Breaking = isBreaking(entry, &oldItem, nil)
+			entries = append(entries, entry)
+			continue
+		}
+
+		entry := BundleItemDiff{Name: oldItem.name, Change: BundleChangeModified}
+		for _, f := range newItem.fields {
+			if oldValue := oldItem.value(f.Field); oldValue != f.New {
+				entry.Fields = append(entry.Fields, BundleFieldDiff{Field: f.Field, Old: oldValue, New: f.New})
+			}
+		}
+		if len(entry.Fields) > 0 {
+			entry.Breaking = isBreaking(entry, &oldItem, &newItem)
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, newItem := range newItems {
+		newItem := newItem
+		if _, ok := oldByName[newItem.name]; ok {
+			continue
+		}
+		entry := BundleItemDiff{Name: newItem.name, Change: BundleChangeAdded, Fields: newItem.fields}
+		entry.Breaking = isBreaking(entry, nil, &newItem)
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// generateBundleDiff compares the parameters, credentials, outputs, images and custom actions of two bundles.
+func generateBundleDiff(oldRef cnab.BundleReference, newRef cnab.BundleReference, solver *cnab.DependencySolver) (BundleVersionDiff, error) {
+	oldBundle, err := generatePrintable(oldRef.Definition, "", solver)
+	if err != nil {
+		return BundleVersionDiff{}, fmt.Errorf("unable to explain bundle %s: %w", oldRef.Reference, err)
+	}
+	newBundle, err := generatePrintable(newRef.Definition, "", solver)
+	if err != nil {
+		return BundleVersionDiff{}, fmt.Errorf("unable to explain bundle %s: %w", newRef.Reference, err)
+	}
+
+	diff := BundleVersionDiff{
+		Old: BundleVersionInfo{Reference: oldRef.Reference.String(), Name: oldBundle.Name, Version: oldBundle.Version},
+		New: BundleVersionInfo{Reference: newRef.Reference.String(), Name: newBundle.Name, Version: newBundle.Version},
+	}
+
+	diff.Parameters = diffItems(parameterDiffItems(oldBundle), parameterDiffItems(newBundle), isBreakingParameterChange)
+	diff.Credentials = diffItems(credentialDiffItems(oldBundle), credentialDiffItems(newBundle), isBreakingCredentialChange)
+	diff.Outputs = diffItems(outputDiffItems(oldBundle), outputDiffItems(newBundle), isBreakingOutputChange)
+	diff.Images = diffItems(imageDiffItems(oldRef.Definition), imageDiffItems(newRef.Definition), isBreakingImageChange)
+	diff.Actions = diffItems(actionDiffItems(oldBundle), actionDiffItems(newBundle), isBreakingActionChange)
+
+	return diff, nil
+}
+
+func parameterDiffItems(pb *PrintableBundle) []diffItem {
+	items := make([]diffItem, 0, len(pb.Parameters))
+	for _, p := range pb.Parameters {
+		var defaultValue string
+		if p.Default != nil {
+			defaultValue = fmt.Sprintf("%v", p.Default)
+		}
+		items = append(items, newDiffItem(p.Name,
+			"type", fmt.Sprintf("%v", p.Type),
+			"default", defaultValue,
+			"required", strconv.FormatBool(p.Required),
+			"sensitive", strconv.FormatBool(p.Sensitive),
+			"applyTo", p.ApplyTo))
+	}
+	return items
+}
+
+// isBreakingParameterChange flags parameters that existing installations may no longer accept,
+// and new required parameters that must be specified when the installation is upgraded.
+func isBreakingParameterChange(entry BundleItemDiff, oldItem *diffItem, newItem *diffItem) bool {
+	switch entry.Change {
+	case BundleChangeRemoved:
+		return true
+	case BundleChangeAdded:
+		return newItem.value("required") == "true" && newItem.value("default") == ""
+	default:
+		if _, ok := entry.findField("type"); ok {
+			return true
+		}
+		if required, ok := entry.findField("required"); ok && required.New == "true" {
+			return newItem.value("default") == ""
+		}
+		return false
+	}
+}
+
+func credentialDiffItems(pb *PrintableBundle) []diffItem {
+	items := make([]diffItem, 0, len(pb.Credentials))
+	for _, c := range pb.Credentials {
+		items = append(items, newDiffItem(c.Name,
+			"required", strconv.FormatBool(c.Required),
+			"applyTo", c.ApplyTo))
+	}
+	return items
+}
+
+// isBreakingCredentialChange flags new required credentials that must be added to the
+// credential sets used to upgrade existing installations.
+func isBreakingCredentialChange(entry BundleItemDiff, oldItem *diffItem, newItem *diffItem) bool {
+	switch entry.Change {
+	case BundleChangeAdded:
+		return newItem.value("required") == "true"
+	case BundleChangeModified:
+		required, ok := entry.findField("required")
+		return ok && required.New == "true"
+	default:
+		return false
+	}
+}
+
+func outputDiffItems(pb *PrintableBundle) []diffItem {
+	items := make([]diffItem, 0, len(pb.Outputs))
+	for _, o := range pb.Outputs {
+		items = append(items, newDiffItem(o.Name,
+			"type", fmt.Sprintf("%v", o.Type),
+			"applyTo", o.ApplyTo))
+	}
+	return items
+}
+
+// isBreakingOutputChange flags outputs that consumers of the installation may no longer be able to use.
+func isBreakingOutputChange(entry BundleItemDiff, oldItem *diffItem, newItem *diffItem) bool {
+	switch entry.Change {
+	case BundleChangeRemoved:
+		return true
+	case BundleChangeModified:
+		_, ok := entry.findField("type")
+		return ok
+	default:
+		return false
+	}
+}
+
+// imageDiffItems lists the invocation images and the images referenced by the bundle.
+func imageDiffItems(bun cnab.ExtendedBundle) []diffItem {
+	items := make([]diffItem, 0, len(bun.InvocationImages)+len(bun.Images))
+	for i, img := range bun.InvocationImages {
+		name := "invocation image"
+		if len(bun.InvocationImages) > 1 {
+			name = fmt.Sprintf("invocation image %d", i)
+		}
+		items = append(items, newDiffItem(name,
+			"image", img.Image,
+			"digest", img.Digest))
+	}
+
+	for name, img := range bun.Images {
+		items = append(items, newDiffItem(name,
+			"image", img.Image,
+			"digest", img.Digest))
+	}
+	return items
+}
+
+func isBreakingImageChange(entry BundleItemDiff, oldItem *diffItem, newItem *diffItem) bool {
+	return false
+}
+
+func actionDiffItems(pb *PrintableBundle) []diffItem {
+	items := make([]diffItem, 0, len(pb.Actions))
+	for _, a := range pb.Actions {
+		items = append(items, newDiffItem(a.Name,
+			"modifies", strconv.FormatBool(a.Modifies),
+			"stateless", strconv.FormatBool(a.Stateless)))
+	}
+	return items
+}
+
+// isBreakingActionChange flags custom actions that can no longer be run on existing installations.
+func isBreakingActionChange(entry BundleItemDiff, oldItem *diffItem, newItem *diffItem) bool {
+	return entry.Change == BundleChangeRemoved
+}
+
+func (p *Porter) printBundleDiff(opts ExplainDiffOptions, diff BundleVersionDiff) error {
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, diff)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, diff)
+	case printer.FormatPlaintext:
+		return p.printBundleDiffTable(diff)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+func (p *Porter) printBundleDiffTable(diff BundleVersionDiff) error {
+	fmt.Fprintf(p.Out, "Old: %s %s (%s)\n", diff.Old.Name, diff.Old.Version, diff.Old.Reference)
+	fmt.Fprintf(p.Out, "New: %s %s (%s)\n", diff.New.Name, diff.New.Version, diff.New.Reference)
+	fmt.Fprintln(p.Out, "")
+
+	if diff.IsEmpty() {
+		fmt.Fprintln(p.Out, "The bundles have the same parameters, credentials, outputs, images and custom actions.")
+		return nil
+	}
+
+	sections := []struct {
+		title   string
+		entries []BundleItemDiff
+	}{
+		{"Parameters", diff.Parameters},
+		{"Credentials", diff.Credentials},
+		{"Outputs", diff.Outputs},
+		{"Images", diff.Images},
+		{"Custom Actions", diff.Actions},
+	}
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(p.Out, "%s:\n", section.title)
+		if err := p.printDiffEntriesTable(section.entries); err != nil {
+			return fmt.Errorf("unable to print %s table: %w", strings.ToLower(section.title), err)
+		}
+		fmt.Fprintln(p.Out, "") // force a blank line after this block
+	}
+
+	if count := diff.CountBreaking(); count > 0 {
+		fmt.Fprintf(p.Out, "🚨 %d of the changes may break upgrading existing installations, review the changes marked as breaking before upgrading.\n", count)
+	}
+	return nil
+}
+
+func (p *Porter) printDiffEntriesTable(entries []BundleItemDiff) error {
+	printDiffRow :=
+		func(v interface{}) []string {
+			e, ok := v.(BundleItemDiff)
+			if !ok {
+				return nil
+			}
+
+			var breaking string
+			if e.Breaking {
+				breaking = "yes"
+			}
+			return []string{e.Name, e.Change, formatDiffFields(e), breaking}
+		}
+	return printer.PrintTable(p.Out, entries, printDiffRow, "Name", "Change", "Details", "Breaking")
+}
+
+// formatDiffFields summarizes the fields of a diff entry, for example "type: string -> integer".
+// Empty and false values are omitted from the summary of an added or removed item.
+func formatDiffFields(e BundleItemDiff) string {
+	details := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		switch e.Change {
+		case BundleChangeAdded:
+			if f.New != "" && f.New != "false" {
+				details = append(details, fmt.Sprintf("%s: %s", f.Field, f.New))
+			}
+		case BundleChangeRemoved:
+			if f.Old != "" && f.Old != "false" {
+				details = append(details, fmt.Sprintf("%s: %s", f.Field, f.Old))
+			}
+		default:
+			details = append(details, fmt.Sprintf("%s: %s -> %s", f.Field, formatDiffValue(f.Old), formatDiffValue(f.New)))
+		}
+	}
+	return strings.Join(details, ", ")
+}
+
+func formatDiffValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
@@ -0,0 +1,159 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cache"
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	diffOldRef = "example.com/mybuns:v0.1.0"
+	diffNewRef = "example.com/mybuns:v0.2.0"
+)
+
+// setupExplainDiff caches the old and new versions of the bundle that are compared by the tests.
+func setupExplainDiff(t *testing.T, p *TestPorter) {
+	bundles := map[string]string{
+		diffOldRef: "testdata/explain/diff-old-bundle.json",
+		diffNewRef: "testdata/explain/diff-new-bundle.json",
+	}
+	for _, path := range bundles {
+		p.TestConfig.TestContext.AddTestFile(path, path)
+	}
+
+	p.TestCache.FindBundleMock = func(ref cnab.OCIReference) (cache.CachedBundle, bool, error) {
+		path, ok := bundles[ref.String()]
+		if !ok {
+			return cache.CachedBundle{}, false, nil
+		}
+		bun, err := p.CNAB.LoadBundle(path)
+		require.NoError(t, err)
+		return cache.CachedBundle{BundleReference: cnab.BundleReference{Reference: ref, Definition: bun}}, true, nil
+	}
+}
+
+func TestExplainDiffOptions_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		opts := ExplainDiffOptions{}
+		require.NoError(t, opts.Validate([]string{diffOldRef, diffNewRef}))
+		assert.Equal(t, diffOldRef, opts.OldReference)
+		assert.Equal(t, diffNewRef, opts.NewReference)
+	})
+
+	t.Run("missing reference", func(t *testing.T) {
+		opts := ExplainDiffOptions{}
+		require.ErrorContains(t, opts.Validate([]string{diffOldRef}), "two positional arguments must be specified")
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		opts := ExplainDiffOptions{}
+		require.ErrorContains(t, opts.Validate([]string{diffOldRef, "example.com/MYBUNS"}), "invalid bundle reference example.com/MYBUNS")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		opts := ExplainDiffOptions{}
+		opts.RawFormat = "vpml"
+		require.ErrorContains(t, opts.Validate([]string{diffOldRef, diffNewRef}), "invalid format: vpml")
+	})
+}
+
+func TestPorter_ExplainDiff(t *testing.T) {
+	testcases := []struct {
+		format     string
+		goldenFile string
+	}{
+		{format: "plaintext", goldenFile: "testdata/explain/expected-diff-output.txt"},
+		{format: "json", goldenFile: "testdata/explain/expected-diff-output.json"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.format, func(t *testing.T) {
+			p := NewTestPorter(t)
+			defer p.Close()
+			setupExplainDiff(t, p)
+
+			opts := ExplainDiffOptions{}
+			opts.RawFormat = tc.format
+			require.NoError(t, opts.Validate([]string{diffOldRef, diffNewRef}))
+			require.NoError(t, p.ExplainDiff(p.RootContext, opts))
+			p.CompareGoldenFile(tc.goldenFile, p.TestConfig.TestContext.GetOutput())
+		})
+	}
+
+	t.Run("same bundle", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		setupExplainDiff(t, p)
+
+		opts := ExplainDiffOptions{}
+		require.NoError(t, opts.Validate([]string{diffOldRef, diffOldRef}))
+		require.NoError(t, p.ExplainDiff(p.RootContext, opts))
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "The bundles have the same parameters, credentials, outputs, images and custom actions.")
+	})
+}
+
+func Test_generateBundleDiff(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	setupExplainDiff(t, p)
+
+	oldBundle, _, err := p.Cache.FindBundle(context.Background(), cnab.MustParseOCIReference(diffOldRef))
+	require.NoError(t, err)
+	newBundle, _, err := p.Cache.FindBundle(context.Background(), cnab.MustParseOCIReference(diffNewRef))
+	require.NoError(t, err)
+
+	diff, err := generateBundleDiff(oldBundle.BundleReference, newBundle.BundleReference, &cnab.DependencySolver{})
+	require.NoError(t, err)
+
+	summarize := func(entries []BundleItemDiff) map[string]string {
+		got := make(map[string]string, len(entries))
+		for _, e := range entries {
+			got[e.Name] = e.Change
+			if e.Breaking {
+				got[e.Name] += " (breaking)"
+			}
+		}
+		return got
+	}
+
+	assert.Equal(t, map[string]string{
+		"namespace": "removed (breaking)",
+		"region":    "modified (breaking)",
+		"replicas":  "added (breaking)",
+		"tier":      "added",
+	}, summarize(diff.Parameters), "unexpected parameter changes")
+
+	assert.Equal(t, map[string]string{
+		"github-token": "added",
+		"token":        "modified (breaking)",
+	}, summarize(diff.Credentials), "unexpected credential changes")
+
+	assert.Equal(t, map[string]string{
+		"port": "removed (breaking)",
+		"url":  "added",
+	}, summarize(diff.Outputs), "unexpected output changes")
+
+	assert.Equal(t, map[string]string{
+		"invocation image": "modified",
+		"nginx":            "modified",
+		"redis":            "added",
+	}, summarize(diff.Images), "unexpected image changes")
+
+	assert.Equal(t, map[string]string{
+		"backup":  "removed (breaking)",
+		"restore": "added",
+		"status":  "modified",
+	}, summarize(diff.Actions), "unexpected custom action changes")
+
+	assert.Equal(t, 6, diff.CountBreaking())
+
+	region := diff.Parameters[1]
+	require.Equal(t, "region", region.Name)
+	assert.Equal(t, []BundleFieldDiff{
+		{Field: "type", Old: "string", New: "integer"},
+		{Field: "default", Old: "mars", New: "3"},
+	}, region.Fields)
+}
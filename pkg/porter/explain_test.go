@@ -54,7 +54,7 @@ func TestExplain_generateTable(t *testing.T) {
 	b, err := p.CNAB.LoadBundle("params-bundle.json")
 	require.NoError(t, err)
 
-	pb, err := generatePrintable(b, "")
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	opts := ExplainOpts{}
 	opts.RawFormat = "plaintext"
@@ -77,7 +77,7 @@ func TestExplain_generateTableRequireDockerHostAccess(t *testing.T) {
 	b, err := p.CNAB.LoadBundle("bundle-docker.json")
 	require.NoError(t, err)
 
-	pb, err := generatePrintable(b, "")
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	opts := ExplainOpts{}
 	opts.RawFormat = "plaintext"
@@ -99,7 +99,7 @@ func TestExplain_generateJSON(t *testing.T) {
 	b, err := p.CNAB.LoadBundle("params-bundle.json")
 	require.NoError(t, err)
 
-	pb, err := generatePrintable(b, "")
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	opts := ExplainOpts{}
 	opts.RawFormat = "json"
@@ -121,7 +121,7 @@ func TestExplain_generateYAML(t *testing.T) {
 	b, err := p.CNAB.LoadBundle("params-bundle.json")
 	require.NoError(t, err)
 
-	pb, err := generatePrintable(b, "")
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 
 	opts := ExplainOpts{}
@@ -169,7 +169,7 @@ func TestExplain_generatePrintableBundleParams(t *testing.T) {
 		},
 	})
 
-	pb, err := generatePrintable(bun, "")
+	pb, err := generatePrintable(bun, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 
 	require.Equal(t, 2, len(pb.Parameters), "expected 2 parameters")
@@ -221,7 +221,7 @@ func TestExplain_generatePrintableBundleParamsWithAction(t *testing.T) {
 	})
 
 	t.Run("action applies", func(t *testing.T) {
-		pb, err := generatePrintable(bun, "install")
+		pb, err := generatePrintable(bun, "install", &cnab.DependencySolver{})
 		require.NoError(t, err)
 
 		require.Equal(t, 2, len(pb.Parameters), "expected 2 parameters")
@@ -236,7 +236,7 @@ func TestExplain_generatePrintableBundleParamsWithAction(t *testing.T) {
 	})
 
 	t.Run("action does not apply", func(t *testing.T) {
-		pb, err := generatePrintable(bun, "upgrade")
+		pb, err := generatePrintable(bun, "upgrade", &cnab.DependencySolver{})
 		require.NoError(t, err)
 
 		require.Equal(t, 1, len(pb.Parameters), "expected only 1 parameter since debug parameter doesn't apply to upgrade command")
@@ -244,7 +244,7 @@ func TestExplain_generatePrintableBundleParamsWithAction(t *testing.T) {
 	})
 
 	t.Run("all actions", func(t *testing.T) {
-		pb, err := generatePrintable(bun, "")
+		pb, err := generatePrintable(bun, "", &cnab.DependencySolver{})
 		require.NoError(t, err)
 
 		require.Equal(t, 2, len(pb.Parameters), "expected 2 parameters")
@@ -285,7 +285,7 @@ func TestExplain_generatePrintableBundleOutputs(t *testing.T) {
 		},
 	})
 
-	pb, err := generatePrintable(bun, "")
+	pb, err := generatePrintable(bun, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 
 	require.Equal(t, 2, len(pb.Outputs), "expected someoutput to be included because the action is unset")
@@ -304,12 +304,12 @@ func TestExplain_generatePrintableBundleOutputs(t *testing.T) {
 	assert.Equal(t, 0, len(pb.Actions))
 
 	// Check outputs for install action
-	pb, err = generatePrintable(bun, "install")
+	pb, err = generatePrintable(bun, "install", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	assert.Equal(t, 2, len(pb.Outputs), "expected someoutput to be included")
 
 	// Check outputs for upgrade action action (someoutput doesn't apply)
-	pb, err = generatePrintable(bun, "upgrade")
+	pb, err = generatePrintable(bun, "upgrade", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(pb.Outputs), "expected someoutput to be excluded by its applyTo")
 }
@@ -336,7 +336,7 @@ func TestExplain_generatePrintableBundleCreds(t *testing.T) {
 	})
 
 	t.Run("action applies", func(t *testing.T) {
-		pb, err := generatePrintable(bun, "install")
+		pb, err := generatePrintable(bun, "install", &cnab.DependencySolver{})
 		require.NoError(t, err)
 
 		require.Equal(t, 2, len(pb.Credentials), "expected 2 credentials")
@@ -351,7 +351,7 @@ func TestExplain_generatePrintableBundleCreds(t *testing.T) {
 	})
 
 	t.Run("action does not apply", func(t *testing.T) {
-		pb, err := generatePrintable(bun, "upgrade")
+		pb, err := generatePrintable(bun, "upgrade", &cnab.DependencySolver{})
 		require.NoError(t, err)
 
 		require.Equal(t, 1, len(pb.Credentials), "expected only 1 credential since kubeconfig credential doesn't apply to upgrade command")
@@ -359,7 +359,7 @@ func TestExplain_generatePrintableBundleCreds(t *testing.T) {
 	})
 
 	t.Run("all actions", func(t *testing.T) {
-		pb, err := generatePrintable(bun, "")
+		pb, err := generatePrintable(bun, "", &cnab.DependencySolver{})
 		require.NoError(t, err)
 
 		require.Equal(t, 2, len(pb.Credentials), "expected 2 credentials")
@@ -391,7 +391,7 @@ func TestExplain_generatePrintableBundlePorterVersion(t *testing.T) {
 		},
 	})
 
-	pb, err := generatePrintable(bun, "")
+	pb, err := generatePrintable(bun, "", &cnab.DependencySolver{})
 	assert.NoError(t, err)
 
 	assert.Equal(t, "v0.30.0", pb.PorterVersion)
@@ -407,7 +407,7 @@ func TestExplain_generatePrintableBundlePorterVersionNonPorterBundle(t *testing.
 		},
 	})
 
-	pb, err := generatePrintable(bun, "")
+	pb, err := generatePrintable(bun, "", &cnab.DependencySolver{})
 	assert.NoError(t, err)
 
 	assert.Equal(t, "", pb.PorterVersion)
@@ -442,7 +442,7 @@ func TestExplain_generatePrintableBundleDependencies(t *testing.T) {
 		},
 	})
 
-	pd, err := generatePrintable(bun, "")
+	pd, err := generatePrintable(bun, "", &cnab.DependencySolver{})
 	assert.NoError(t, err)
 	assert.Equal(t, 3, len(pd.Dependencies))
 	assert.Equal(t, 0, len(pd.Parameters))
@@ -460,7 +460,7 @@ func TestExplain_generateJSONForDependencies(t *testing.T) {
 	b, err := p.CNAB.LoadBundle("dependencies-bundle.json")
 	require.NoError(t, err)
 
-	pb, err := generatePrintable(b, "")
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	opts := ExplainOpts{}
 	opts.RawFormat = "json"
@@ -475,6 +475,53 @@ func TestExplain_generateJSONForDependencies(t *testing.T) {
 	p.CompareGoldenFile("testdata/explain/expected-json-dependencies-output.json", gotOutput)
 }
 
+func TestExplain_generateTableForDependenciesV2(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/explain/dependencies-v2-bundle.json", "dependencies-v2-bundle.json")
+	b, err := p.CNAB.LoadBundle("dependencies-v2-bundle.json")
+	require.NoError(t, err)
+
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
+	require.NoError(t, err)
+	opts := ExplainOpts{}
+	opts.RawFormat = "plaintext"
+
+	err = opts.Validate([]string{}, p.Context)
+	require.NoError(t, err)
+
+	err = p.printBundleExplain(opts, pb, b)
+	assert.NoError(t, err)
+	gotOutput := p.TestConfig.TestContext.GetOutput()
+
+	p.CompareGoldenFile("testdata/explain/expected-table-dependencies-v2-output.txt", gotOutput)
+}
+
+func TestExplain_generateTableForActionArguments(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/explain/action-arguments-bundle.json", "action-arguments-bundle.json")
+	b, err := p.CNAB.LoadBundle("action-arguments-bundle.json")
+	require.NoError(t, err)
+
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
+	require.NoError(t, err)
+	require.Len(t, pb.Parameters, 0, "the arguments of custom actions should not be listed as parameters")
+	opts := ExplainOpts{}
+	opts.RawFormat = "plaintext"
+
+	err = opts.Validate([]string{}, p.Context)
+	require.NoError(t, err)
+
+	err = p.printBundleExplain(opts, pb, b)
+	assert.NoError(t, err)
+	gotOutput := p.TestConfig.TestContext.GetOutput()
+
+	p.CompareGoldenFile("testdata/explain/expected-table-action-arguments-output.txt", gotOutput)
+}
+
 func TestExplain_generateTableNonPorterBundle(t *testing.T) {
 	p := NewTestPorter(t)
 	defer p.Close()
@@ -483,7 +530,7 @@ func TestExplain_generateTableNonPorterBundle(t *testing.T) {
 	b, err := p.CNAB.LoadBundle("params-bundle.json")
 	require.NoError(t, err)
 
-	pb, err := generatePrintable(b, "")
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	opts := ExplainOpts{}
 	opts.RawFormat = "plaintext"
@@ -506,7 +553,7 @@ func TestExplain_generateTableBundleWithNoMixins(t *testing.T) {
 	b, err := p.CNAB.LoadBundle("params-bundle.json")
 	require.NoError(t, err)
 
-	pb, err := generatePrintable(b, "")
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
 	require.NoError(t, err)
 	opts := ExplainOpts{}
 	opts.RawFormat = "plaintext"
@@ -520,3 +567,93 @@ func TestExplain_generateTableBundleWithNoMixins(t *testing.T) {
 	gotOutput := p.TestConfig.TestContext.GetOutput()
 	test.CompareGoldenFile(t, "testdata/explain/expected-table-output-no-mixins.txt", gotOutput)
 }
+
+func TestExplain_generatePrintableBundleRequirements(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/explain/bundle-requirements.json", "bundle-requirements.json")
+	b, err := p.CNAB.LoadBundle("bundle-requirements.json")
+	require.NoError(t, err)
+
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1.0.0", pb.MinimumPorterVersion)
+	wantExtensions := []PrintableRequiredExtension{
+		{Name: "io.cnab.docker", Description: "Mounts the Docker socket from the host into the bundle", Supported: true},
+		{Name: "sh.porter.file-parameters", Description: "Passes files from the host to the bundle as parameters", Supported: true},
+	}
+	assert.Equal(t, wantExtensions, pb.RequiredExtensions)
+	wantPrereqs := []PrintablePrerequisite{
+		{Command: "helm", Mixin: "helm3"},
+		{Command: "kubectl", Mixin: "helm3"},
+	}
+	assert.Equal(t, wantPrereqs, pb.Prerequisites)
+}
+
+func TestExplain_generateTableRequirements(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/explain/bundle-requirements.json", "bundle-requirements.json")
+	b, err := p.CNAB.LoadBundle("bundle-requirements.json")
+	require.NoError(t, err)
+
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
+	require.NoError(t, err)
+	opts := ExplainOpts{}
+	opts.RawFormat = "plaintext"
+
+	err = opts.Validate([]string{}, p.Context)
+	require.NoError(t, err)
+
+	err = p.printBundleExplain(opts, pb, b)
+	assert.NoError(t, err)
+	gotOutput := p.TestConfig.TestContext.GetOutput()
+	p.CompareGoldenFile("testdata/explain/expected-table-output-requirements.txt", gotOutput)
+}
+
+func TestExplain_generateTableBundleMetadata(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/explain/bundle-metadata.json", "bundle-metadata.json")
+	b, err := p.CNAB.LoadBundle("bundle-metadata.json")
+	require.NoError(t, err)
+
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
+	require.NoError(t, err)
+	opts := ExplainOpts{}
+	opts.RawFormat = "plaintext"
+
+	err = opts.Validate([]string{}, p.Context)
+	require.NoError(t, err)
+
+	err = p.printBundleExplain(opts, pb, b)
+	assert.NoError(t, err)
+	gotOutput := p.TestConfig.TestContext.GetOutput()
+	p.CompareGoldenFile("testdata/explain/expected-table-output-metadata.txt", gotOutput)
+}
+
+func TestExplain_generateYAMLBundleMetadata(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/explain/bundle-metadata.json", "bundle-metadata.json")
+	b, err := p.CNAB.LoadBundle("bundle-metadata.json")
+	require.NoError(t, err)
+
+	pb, err := generatePrintable(b, "", &cnab.DependencySolver{})
+	require.NoError(t, err)
+	opts := ExplainOpts{}
+	opts.RawFormat = "yaml"
+
+	err = opts.Validate([]string{}, p.Context)
+	require.NoError(t, err)
+
+	err = p.printBundleExplain(opts, pb, b)
+	assert.NoError(t, err)
+	gotOutput := p.TestConfig.TestContext.GetOutput()
+	p.CompareGoldenFile("testdata/explain/expected-yaml-output-metadata.yaml", gotOutput)
+}
@@ -77,9 +77,13 @@ func NewTestPorter(t *testing.T) *TestPorter {
 	p.Installations = testInstallations
 	p.Credentials = testCredentials
 	p.Parameters = testParameters
+	p.Audit = storage.NewAuditStore(testStore)
+	testParameters.Encryptor = p.Encryptor
+	testParameters.LargeOutputs = storage.NewLargeOutputs(tc.Config)
+	testInstallations.LargeOutputs = testParameters.LargeOutputs
 	p.Secrets = testSecrets
 	p.CNAB = cnabprovider.NewTestRuntimeFor(tc, testInstallations, testCredentials, testParameters, testSecrets)
-	p.Registry = testRegistry
+	p.Registry = cnabtooci.NewOfflineRegistry(tc.Config, testRegistry)
 
 	tp := TestPorter{
 		Porter:            p,
@@ -94,6 +98,7 @@ func NewTestPorter(t *testing.T) *TestPorter {
 		TestSanitizer:     storage.NewSanitizer(testParameters, testSecrets),
 		RepoRoot:          tc.TestContext.FindRepoRoot(),
 	}
+	tp.TestSanitizer.UseLargeOutputs(storage.NewLargeOutputs(tc.Config))
 
 	// Start a tracing span for the test, so that we can capture logs
 	tp.RootContext, tp.RootSpan = p.StartRootSpan(context.Background(), t.Name())
@@ -0,0 +1,199 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// historyRetention is the policy that determines which runs of an installation are kept.
+type historyRetention struct {
+	// MaxRuns is the maximum number of runs to keep. Zero keeps all runs.
+	MaxRuns int
+
+	// MaxAge is the maximum age of the runs to keep. Zero keeps runs regardless of their age.
+	MaxAge time.Duration
+}
+
+// IsEnabled determines if the policy removes any runs.
+func (r historyRetention) IsEnabled() bool {
+	return r.MaxRuns > 0 || r.MaxAge > 0
+}
+
+// getHistoryRetention returns the retention policy defined in the Porter configuration file.
+func (p *Porter) getHistoryRetention() (historyRetention, error) {
+	maxAge, err := p.Config.Data.History.GetMaxAge()
+	if err != nil {
+		return historyRetention{}, fmt.Errorf("invalid history.max-age in the Porter configuration file: %w", err)
+	}
+	return historyRetention{MaxRuns: p.Config.Data.History.MaxRuns, MaxAge: maxAge}, nil
+}
+
+// PruneHistoryOptions represent options for Porter's installation prune-history command
+type PruneHistoryOptions struct {
+	installationOptions
+
+	// MaxRuns is the maximum number of runs to keep.
+	// Defaults to history.max-runs in the Porter configuration file.
+	MaxRuns int
+
+	// MaxAge is the maximum age of the runs to keep, for example 720h or 30d.
+	// Defaults to history.max-age in the Porter configuration file.
+	MaxAge string
+
+	// DryRun lists the runs that would be removed without removing them.
+	DryRun bool
+
+	// maxAge is the parsed MaxAge.
+	maxAge time.Duration
+}
+
+// Validate prepares for the installation prune-history command and validates the args/options.
+func (o *PruneHistoryOptions) Validate(args []string, cxt *portercontext.Context) error {
+	// Ensure only one argument exists (installation name) if args length non-zero
+	err := o.installationOptions.validateInstallationName(args)
+	if err != nil {
+		return err
+	}
+
+	if o.MaxRuns < 0 {
+		return errors.New("--max-runs must not be negative")
+	}
+
+	o.maxAge, err = config.ParseRetentionAge(o.MaxAge)
+	if err != nil {
+		return fmt.Errorf("invalid --max-age: %w", err)
+	}
+
+	return o.installationOptions.defaultBundleFiles(cxt)
+}
+
+// PruneInstallationHistory removes the runs of an installation, along with their
+// results, outputs and logs, that are not kept by the retention policy.
+func (p *Porter) PruneInstallationHistory(ctx context.Context, opts PruneHistoryOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	err := p.applyDefaultOptions(ctx, &opts.installationOptions)
+	if err != nil {
+		return err
+	}
+
+	// Flags override the retention policy from the config file
+	policy, err := p.getHistoryRetention()
+	if err != nil {
+		return span.Error(err)
+	}
+	if opts.MaxRuns > 0 {
+		policy.MaxRuns = opts.MaxRuns
+	}
+	if opts.maxAge > 0 {
+		policy.MaxAge = opts.maxAge
+	}
+	if !policy.IsEnabled() {
+		return span.Error(errors.New("no retention policy was specified, set --max-runs or --max-age, or define history.max-runs or history.max-age in the Porter configuration file"))
+	}
+
+	installation, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(fmt.Errorf("could not retrieve installation %s/%s: %w", opts.Namespace, opts.Name, err))
+	}
+
+	pruned, err := p.pruneRuns(ctx, installation, policy, opts.DryRun)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	verb := "Removed"
+	if opts.DryRun {
+		verb = "Would remove"
+	}
+	for _, run := range pruned {
+		fmt.Fprintf(p.Out, "%s run %s (%s on %s)\n", verb, run.ID, run.Action, run.Created.Format(time.RFC3339))
+	}
+	fmt.Fprintf(p.Out, "%s %d run(s) from installation %s\n", verb, len(pruned), installation)
+	return nil
+}
+
+// enforceHistoryRetention applies the retention policy from the Porter
+// configuration file after the bundle is executed. Failing to prune the history
+// does not fail the action, so errors are only logged.
+func (p *Porter) enforceHistoryRetention(ctx context.Context, installation storage.Installation) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	policy, err := p.getHistoryRetention()
+	if err != nil {
+		span.Warnf("Skipping pruning the run history of installation %s: %s", installation, err)
+		return
+	}
+	if !policy.IsEnabled() {
+		return
+	}
+
+	pruned, err := p.pruneRuns(ctx, installation, policy, false)
+	if err != nil {
+		span.Warnf("Could not prune the run history of installation %s: %s", installation, err)
+		return
+	}
+	if len(pruned) > 0 {
+		span.Debugf("Removed %d run(s) from installation %s", len(pruned), installation)
+	}
+}
+
+// pruneRuns removes the runs of an installation that are not kept by the
+// retention policy, returning the runs that were (or in a dry run, would be) removed.
+func (p *Porter) pruneRuns(ctx context.Context, installation storage.Installation, policy historyRetention, dryRun bool) ([]storage.Run, error) {
+	runs, _, err := p.Installations.ListRuns(ctx, installation.Namespace, installation.Name)
+	if err != nil {
+		return nil, fmt.Errorf("could not list runs for installation %s: %w", installation, err)
+	}
+
+	// Keep the runs that the installation's status and outputs depend upon
+	keep := map[string]bool{installation.Status.RunID: true}
+	lastOutputs, err := p.Installations.GetLastOutputs(ctx, installation.Namespace, installation.Name)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve the outputs for installation %s: %w", installation, err)
+	}
+	for _, output := range lastOutputs.Value() {
+		keep[output.RunID] = true
+	}
+
+	pruned := selectRunsToPrune(runs, keep, policy, time.Now())
+	if dryRun {
+		return pruned, nil
+	}
+
+	for _, run := range pruned {
+		if err = p.Installations.RemoveRun(ctx, run.ID); err != nil {
+			return nil, fmt.Errorf("could not remove run %s from installation %s: %w", run.ID, installation, err)
+		}
+	}
+	return pruned, nil
+}
+
+// selectRunsToPrune returns the runs that are not kept by the retention policy.
+// The runs must be sorted from oldest to newest. The most recent run, and any
+// runs in keep, are always kept.
+func selectRunsToPrune(runs []storage.Run, keep map[string]bool, policy historyRetention, now time.Time) []storage.Run {
+	var pruned []storage.Run
+	for i, run := range runs {
+		newerRuns := len(runs) - 1 - i
+		if newerRuns == 0 || keep[run.ID] {
+			continue
+		}
+
+		tooMany := policy.MaxRuns > 0 && newerRuns >= policy.MaxRuns
+		tooOld := policy.MaxAge > 0 && now.Sub(run.Created) > policy.MaxAge
+		if tooMany || tooOld {
+			pruned = append(pruned, run)
+		}
+	}
+	return pruned
+}
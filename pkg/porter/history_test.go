@@ -0,0 +1,63 @@
+package porter
+
+import (
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneHistoryOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := PruneHistoryOptions{MaxAge: "30d"}
+	require.NoError(t, opts.Validate([]string{"wordpress"}, p.Context))
+	assert.Equal(t, "wordpress", opts.Name)
+	assert.Equal(t, 30*24*time.Hour, opts.maxAge)
+
+	opts = PruneHistoryOptions{MaxRuns: -1}
+	require.EqualError(t, opts.Validate([]string{"wordpress"}, p.Context), "--max-runs must not be negative")
+
+	opts = PruneHistoryOptions{MaxAge: "soon"}
+	require.ErrorContains(t, opts.Validate([]string{"wordpress"}, p.Context), "invalid --max-age")
+}
+
+func TestSelectRunsToPrune(t *testing.T) {
+	now := time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+	runs := []storage.Run{
+		{ID: "1", Created: now.Add(-72 * time.Hour)},
+		{ID: "2", Created: now.Add(-48 * time.Hour)},
+		{ID: "3", Created: now.Add(-24 * time.Hour)},
+		{ID: "4", Created: now.Add(-1 * time.Hour)},
+	}
+	runIDs := func(runs []storage.Run) []string {
+		var ids []string
+		for _, run := range runs {
+			ids = append(ids, run.ID)
+		}
+		return ids
+	}
+
+	t.Run("max runs", func(t *testing.T) {
+		pruned := selectRunsToPrune(runs, nil, historyRetention{MaxRuns: 2}, now)
+		assert.Equal(t, []string{"1", "2"}, runIDs(pruned))
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		pruned := selectRunsToPrune(runs, nil, historyRetention{MaxAge: 36 * time.Hour}, now)
+		assert.Equal(t, []string{"1", "2"}, runIDs(pruned))
+	})
+
+	t.Run("keeps runs with outputs", func(t *testing.T) {
+		pruned := selectRunsToPrune(runs, map[string]bool{"1": true}, historyRetention{MaxRuns: 1}, now)
+		assert.Equal(t, []string{"2", "3"}, runIDs(pruned))
+	})
+
+	t.Run("keeps the last run", func(t *testing.T) {
+		pruned := selectRunsToPrune(runs, nil, historyRetention{MaxAge: time.Minute}, now)
+		assert.Equal(t, []string{"1", "2", "3"}, runIDs(pruned))
+	})
+}
@@ -0,0 +1,131 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/cnab"
+	configadapter "get.porter.sh/porter/pkg/cnab/config-adapter"
+	cnabprovider "get.porter.sh/porter/pkg/cnab/provider"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/tracing"
+	"get.porter.sh/porter/pkg/yaml"
+)
+
+// Environment variables set for the hooks defined by a bundle.
+const (
+	// EnvHookAction is the name of the action that the hook is run for.
+	EnvHookAction = "PORTER_HOOK_ACTION"
+
+	// EnvHookInstallation is the name of the installation.
+	EnvHookInstallation = "PORTER_HOOK_INSTALLATION"
+
+	// EnvHookNamespace is the namespace of the installation.
+	EnvHookNamespace = "PORTER_HOOK_NAMESPACE"
+
+	// EnvHookBundle is the reference of the bundle.
+	EnvHookBundle = "PORTER_HOOK_BUNDLE"
+
+	// EnvHookStatus is the status of the action, set only for post hooks: succeeded or failed.
+	EnvHookStatus = "PORTER_HOOK_STATUS"
+)
+
+// executeWithHooks runs the action against the root bundle, along with the
+// hooks defined by the bundle for the action. Pre hooks must succeed for the
+// action to run. Post hooks run after the action completes, even when it failed.
+func (p *Porter) executeWithHooks(ctx context.Context, opts *BundleExecutionOptions, args cnabprovider.ActionArguments) error {
+	hooks, err := p.getBundleHooks(args.BundleReference.Definition)
+	if err != nil {
+		return err
+	}
+
+	preHooks := hooks.GetHooks(manifest.HookStagePre, args.Action)
+	postHooks := hooks.GetHooks(manifest.HookStagePost, args.Action)
+	if (len(preHooks) > 0 || len(postHooks) > 0) && !opts.AllowHooks {
+		log := tracing.LoggerFromContext(ctx)
+		log.Warnf("Skipping the hooks defined by the bundle for the %s action. Use --allow-hooks to run the commands defined by the bundle on this host.", args.Action)
+		return p.CNAB.Execute(ctx, args)
+	}
+
+	if err = p.runHooks(ctx, manifest.HookStagePre, preHooks, args, ""); err != nil {
+		return err
+	}
+
+	err = p.CNAB.Execute(ctx, args)
+
+	status := cnab.StatusSucceeded
+	if err != nil {
+		status = cnab.StatusFailed
+	}
+	if hookErr := p.runHooks(ctx, manifest.HookStagePost, postHooks, args, status); hookErr != nil && err == nil {
+		return hookErr
+	}
+	return err
+}
+
+// getBundleHooks returns the hooks defined in the porter.yaml embedded in the bundle.
+func (p *Porter) getBundleHooks(bun cnab.ExtendedBundle) (manifest.Hooks, error) {
+	if !bun.IsPorterBundle() {
+		return nil, nil
+	}
+
+	stamp, err := configadapter.LoadStamp(bun)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bundles built by older versions of Porter may not have an embedded manifest
+	if stamp.EncodedManifest == "" {
+		return nil, nil
+	}
+
+	data, err := stamp.DecodeManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only read the hooks, so that the rest of the manifest is not validated again
+	var m struct {
+		Hooks manifest.Hooks `yaml:"hooks,omitempty"`
+	}
+	if err = yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("could not read the hooks defined by the bundle: %w", err)
+	}
+	return m.Hooks, nil
+}
+
+// runHooks executes the hooks on the host, stopping at the first hook that fails.
+func (p *Porter) runHooks(ctx context.Context, stage string, hooks []manifest.Hook, args cnabprovider.ActionArguments, status string) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	hookName := manifest.GetHookName(stage, args.Action)
+	for i, hook := range hooks {
+		description := hook.Description
+		if description == "" {
+			description = hook.Command
+		}
+		p.printInfof("Running %s hook: %s\n", hookName, description)
+
+		cmd := p.NewCommand(ctx, hook.Command, hook.Arguments...)
+		cmd.Stdout = p.Out
+		cmd.Stderr = p.Err
+		cmd.Env = append(cmd.Env,
+			EnvHookAction+"="+args.Action,
+			EnvHookInstallation+"="+args.Installation.Name,
+			EnvHookNamespace+"="+args.Installation.Namespace,
+			EnvHookBundle+"="+args.BundleReference.Reference.String(),
+		)
+		if status != "" {
+			cmd.Env = append(cmd.Env, EnvHookStatus+"="+status)
+		}
+		for k, v := range hook.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+
+		if err := cmd.Run(); err != nil {
+			return log.Error(fmt.Errorf("hook %s[%d] failed: %w", hookName, i, err))
+		}
+	}
+	return nil
+}
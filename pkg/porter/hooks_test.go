@@ -0,0 +1,75 @@
+package porter
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabprovider "get.porter.sh/porter/pkg/cnab/provider"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_getBundleHooks(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	t.Run("not a porter bundle", func(t *testing.T) {
+		hooks, err := p.getBundleHooks(cnab.NewBundle(bundle.Bundle{}))
+		require.NoError(t, err)
+		assert.Empty(t, hooks)
+	})
+
+	t.Run("hooks in the embedded manifest", func(t *testing.T) {
+		porterYaml := "name: hello\nhooks:\n  preInstall:\n  - command: ./notify.sh\n    arguments:\n    - starting\n"
+		bun := cnab.NewBundle(bundle.Bundle{
+			Custom: map[string]interface{}{
+				config.CustomPorterKey: map[string]interface{}{
+					"manifest": base64.StdEncoding.EncodeToString([]byte(porterYaml)),
+				},
+			},
+		})
+
+		hooks, err := p.getBundleHooks(bun)
+		require.NoError(t, err)
+		assert.Equal(t, []manifest.Hook{{Command: "./notify.sh", Arguments: []string{"starting"}}},
+			hooks.GetHooks(manifest.HookStagePre, cnab.ActionInstall))
+	})
+}
+
+func TestPorter_runHooks(t *testing.T) {
+	ctx := context.Background()
+	args := cnabprovider.ActionArguments{
+		Action:       cnab.ActionInstall,
+		Installation: storage.NewInstallation("dev", "mysql"),
+	}
+	hooks := []manifest.Hook{
+		{Description: "Notify the team", Command: "./notify.sh", Arguments: []string{"starting"}},
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Setenv(test.ExpectedCommandEnv, "./notify.sh starting")
+
+		err := p.runHooks(ctx, manifest.HookStagePre, hooks, args, "")
+		require.NoError(t, err)
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Running preInstall hook: Notify the team")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Setenv(test.ExpectedCommandEnv, "./notify.sh starting")
+		p.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+
+		err := p.runHooks(ctx, manifest.HookStagePre, hooks, args, "")
+		require.ErrorContains(t, err, "hook preInstall[0] failed")
+	})
+}
@@ -2,10 +2,13 @@ package porter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/sbom"
 	"github.com/cnabio/cnab-go/bundle"
 )
 
@@ -15,6 +18,8 @@ type InspectableBundle struct {
 	Version          string                     `json:"version" yaml:"version"`
 	InvocationImages []PrintableInvocationImage `json:"invocationImages" yaml:"invocationImages"`
 	Images           []PrintableImage           `json:"images,omitempty" yaml:"images,omitempty"`
+
+	PrintableBundleMetadata `yaml:",inline"`
 }
 
 type PrintableInvocationImage struct {
@@ -28,12 +33,50 @@ type PrintableImage struct {
 	Original string `json:"originalImage" yaml:"originalImage"`
 }
 
-func (p *Porter) Inspect(ctx context.Context, o ExplainOpts) error {
+// InspectOptions are the options for inspecting a bundle.
+type InspectOptions struct {
+	ExplainOpts
+
+	// SBOM prints the software bill of materials attached to the invocation
+	// image of a published bundle, instead of inspecting the bundle.
+	SBOM bool
+
+	// SBOMFormat is the format of the SBOM to print: spdx or cyclonedx. By
+	// default, the first SBOM found is printed.
+	SBOMFormat string
+}
+
+// Validate the inspect options.
+func (o *InspectOptions) Validate(args []string, pctx *portercontext.Context) error {
+	if err := o.ExplainOpts.Validate(args, pctx); err != nil {
+		return err
+	}
+
+	if o.SBOMFormat != "" {
+		if !o.SBOM {
+			return errors.New("--sbom-format can only be used with --sbom")
+		}
+		if err := sbom.ValidateFormat(o.SBOMFormat); err != nil {
+			return fmt.Errorf("invalid --sbom-format value: %w", err)
+		}
+	}
+
+	if o.SBOM && o.Reference == "" {
+		return errors.New("--sbom requires a bundle reference, the SBOM is retrieved from the registry where the bundle was published")
+	}
+	return nil
+}
+
+func (p *Porter) Inspect(ctx context.Context, o InspectOptions) error {
 	bundleRef, err := o.GetBundleReference(ctx, p)
 	if err != nil {
 		return err
 	}
 
+	if o.SBOM {
+		return p.printSBOM(ctx, bundleRef, o)
+	}
+
 	ib, err := generateInspectableBundle(bundleRef)
 	if err != nil {
 		return fmt.Errorf("unable to inspect bundle: %w", err)
@@ -47,6 +90,11 @@ func generateInspectableBundle(bundleRef cnab.BundleReference) (*InspectableBund
 		Description: bundleRef.Definition.Description,
 		Version:     bundleRef.Definition.Version,
 	}
+	metadata, err := generatePrintableMetadata(bundleRef.Definition)
+	if err != nil {
+		return nil, err
+	}
+	ib.PrintableBundleMetadata = metadata
 	ib.InvocationImages, ib.Images = handleInspectRelocate(bundleRef)
 	return ib, nil
 }
@@ -78,7 +126,7 @@ func handleInspectRelocate(bundleRef cnab.BundleReference) ([]PrintableInvocatio
 	return invoImages, images
 }
 
-func (p *Porter) printBundleInspect(o ExplainOpts, ib *InspectableBundle) error {
+func (p *Porter) printBundleInspect(o InspectOptions, ib *InspectableBundle) error {
 	switch o.Format {
 	case printer.FormatJson:
 		return printer.PrintJson(p.Out, ib)
@@ -95,8 +143,10 @@ func (p *Porter) printBundleInspectTable(bun *InspectableBundle) error {
 	fmt.Fprintf(p.Out, "Name: %s\n", bun.Name)
 	fmt.Fprintf(p.Out, "Description: %s\n", bun.Description)
 	fmt.Fprintf(p.Out, "Version: %s\n", bun.Version)
+	p.printBundleMetadataHeader(bun.PrintableBundleMetadata)
 	fmt.Fprintln(p.Out, "")
 
+	p.printBundleMetadataBlock(bun.PrintableBundleMetadata)
 	p.printInvocationImageInspectBlock(bun)
 	p.printImagesInspectBlock(bun)
 	return nil
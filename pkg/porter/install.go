@@ -16,6 +16,7 @@ var _ BundleAction = NewInstallOptions()
 // Porter handles defaulting any missing values.
 type InstallOptions struct {
 	*BundleExecutionOptions
+	WaitOptions
 
 	// Labels to apply to the installation.
 	Labels []string
@@ -27,6 +28,10 @@ func (o InstallOptions) Validate(ctx context.Context, args []string, p *Porter)
 		return err
 	}
 
+	if err = o.WaitOptions.Validate(); err != nil {
+		return err
+	}
+
 	// Install requires special logic because the bundle must always be specified, including a name isn't enough.
 	// So we have a slight repeat of the logic performed in by the generic bundle action args
 	if o.File == "" && o.CNABFile == "" && o.Reference == "" {
@@ -60,6 +65,15 @@ func (p *Porter) InstallBundle(ctx context.Context, opts InstallOptions) error {
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
 
+	// A dry run does not modify the installation, so it does not need the lock
+	if !opts.DryRun {
+		unlock, err := p.lockInstallation(ctx, opts.Namespace, opts.Name, opts.GetAction(), opts.WaitForLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
 	i, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
 	if err == nil {
 		// Validate that we are not overwriting an existing installation
@@ -83,13 +97,27 @@ func (p *Porter) InstallBundle(ctx context.Context, opts InstallOptions) error {
 		return err
 	}
 
+	if opts.DryRun {
+		plan, err := p.planAction(ctx, i, opts)
+		if err != nil {
+			return err
+		}
+		p.printExecutionPlan(plan)
+		return nil
+	}
+
 	err = p.Installations.UpsertInstallation(ctx, i)
 	if err != nil {
 		return fmt.Errorf("error saving installation record: %w", err)
 	}
 
 	// Run install using the updated installation record
-	return p.ExecuteAction(ctx, i, opts)
+	err = p.ExecuteAction(ctx, i, opts)
+	if err != nil || !opts.Wait {
+		return err
+	}
+
+	return p.waitForReady(ctx, opts.BundleExecutionOptions, opts.WaitTimeout)
 }
 
 func (p *Porter) sanitizeInstallation(ctx context.Context, inst *storage.Installation, bun cnab.ExtendedBundle) error {
@@ -0,0 +1,394 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Reasons that an installation is, or is not, in sync with its desired state.
+const (
+	DiffReasonPaused                = "the installation is paused"
+	DiffReasonUninstalled           = "the installation is uninstalled"
+	DiffReasonUninstallRequested    = "installation.uninstalled is true"
+	DiffReasonUninstallNotInstalled = "installation.uninstalled is true but the installation doesn't exist yet"
+	DiffReasonNotInstalled          = "the installation has not completed successfully yet"
+	DiffReasonLastRunMissing        = "the last run for the installation wasn't recorded"
+	DiffReasonBundleChanged         = "the bundle definition has changed"
+	DiffReasonParametersChanged     = "the parameters have changed"
+	DiffReasonCredentialSetsChanged = "the credential set names have changed"
+)
+
+// InstallationDiff describes the differences between the desired state of an
+// installation and the state of the installation the last time it was modified.
+type InstallationDiff struct {
+	// Installation is the namespace/name of the installation.
+	Installation string `json:"installation" yaml:"installation"`
+
+	// InSync indicates that the installation is up-to-date and reconciling it
+	// does not execute the bundle.
+	InSync bool `json:"inSync" yaml:"inSync"`
+
+	// Action that is executed to bring the installation in sync.
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+
+	// Reasons explaining why the installation is, or is not, in sync.
+	Reasons []string `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+
+	// Bundle is set when the bundle definition has changed.
+	Bundle *BundleDiff `json:"bundle,omitempty" yaml:"bundle,omitempty"`
+
+	// Parameters that have changed.
+	Parameters []ParameterDiff `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	// CredentialSets is set when the names of the associated credential sets have changed.
+	CredentialSets *CredentialSetsDiff `json:"credentialSets,omitempty" yaml:"credentialSets,omitempty"`
+
+	// parametersDiff is a text representation of the parameter changes for logging.
+	parametersDiff string
+}
+
+// BundleDiff describes a change to the bundle used by an installation.
+type BundleDiff struct {
+	CurrentReference  string `json:"currentReference" yaml:"currentReference"`
+	CurrentDigest     string `json:"currentDigest" yaml:"currentDigest"`
+	ProposedReference string `json:"proposedReference" yaml:"proposedReference"`
+	ProposedDigest    string `json:"proposedDigest" yaml:"proposedDigest"`
+}
+
+// CredentialSetsDiff describes a change to the credential sets used by an installation.
+type CredentialSetsDiff struct {
+	Current  []string `json:"current" yaml:"current"`
+	Proposed []string `json:"proposed" yaml:"proposed"`
+}
+
+// addReason records why the installation is, or is not, in sync.
+func (d *InstallationDiff) addReason(reason string) {
+	d.Reasons = append(d.Reasons, reason)
+}
+
+// DiffInstallation compares the desired state of the installation with the state
+// of the installation the last time it was modified. This is used both to report
+// drift and to determine if reconciling an installation should execute the bundle.
+func (p *Porter) DiffInstallation(ctx context.Context, i storage.Installation, lastRun *storage.Run, action BundleAction) (InstallationDiff, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	diff := InstallationDiff{Installation: i.String(), Action: action.GetAction()}
+
+	// Paused installations are not reconciled until they are resumed
+	if i.Status.Suspended {
+		diff.InSync = true
+		diff.addReason(DiffReasonPaused)
+		return diff, nil
+	}
+
+	// Has it been uninstalled? If so, we don't ever reconcile it again
+	if i.IsUninstalled() {
+		diff.InSync = true
+		diff.addReason(DiffReasonUninstalled)
+		return diff, nil
+	}
+
+	// Should we uninstall it?
+	if i.Uninstalled {
+		// Only try to uninstall if it's been installed before
+		if i.IsInstalled() {
+			diff.addReason(DiffReasonUninstallRequested)
+			return diff, nil
+		}
+
+		// Otherwise ignore this installation
+		diff.InSync = true
+		diff.addReason(DiffReasonUninstallNotInstalled)
+		return diff, nil
+	} else {
+		// Should we install it?
+		if !i.IsInstalled() {
+			diff.addReason(DiffReasonNotInstalled)
+			return diff, nil
+		}
+	}
+
+	// We want to upgrade but we don't have values to compare against
+	// This shouldn't happen but check just in case
+	if lastRun == nil {
+		diff.addReason(DiffReasonLastRunMissing)
+		return diff, nil
+	}
+
+	// Figure out if we need to upgrade
+	opts := action.GetOptions()
+
+	newRef, err := opts.GetBundleReference(ctx, p)
+	if err != nil {
+		return InstallationDiff{}, span.Error(err)
+	}
+
+	// Has the bundle definition changed?
+	if lastRun.BundleDigest != newRef.Digest.String() {
+		diff.addReason(DiffReasonBundleChanged)
+		diff.Bundle = &BundleDiff{
+			CurrentReference:  lastRun.BundleReference,
+			CurrentDigest:     lastRun.BundleDigest,
+			ProposedReference: newRef.Reference.String(),
+			ProposedDigest:    newRef.Digest.String(),
+		}
+	}
+
+	// Convert parameters to a string to compare them. This avoids problems comparing
+	// values that may be equal but have different types due to how the parameter
+	// value was loaded.
+	b := newRef.Definition
+	prepParameterForComparison := func(bun cnab.ExtendedBundle, paramName string, rawValue interface{}) (string, error) {
+		typedValue, err := bun.ConvertParameterValue(paramName, rawValue)
+		if err != nil {
+			return "", err
+		}
+		return bun.WriteParameterToString(paramName, typedValue)
+	}
+	skipParameter := func(paramName string) bool {
+		if b.IsInternalParameter(paramName) {
+			return true
+		}
+
+		// The parameter was removed from the new bundle, it is reported with the bundle change
+		_, ok := b.Parameters[paramName]
+		return !ok && diff.Bundle != nil
+	}
+
+	lastRunBundle := cnab.NewBundle(lastRun.Bundle)
+	lastRunParams, err := p.Sanitizer.RestoreParameterSet(ctx, lastRun.Parameters, lastRunBundle)
+	if err != nil {
+		return InstallationDiff{}, span.Error(err)
+	}
+
+	// Old values are converted using the bundle they were recorded against, so
+	// that changing the type of a parameter in the new bundle does not break the
+	// comparison. When that bundle isn't available, and the value can't be
+	// converted to the new type, compare the raw string and report the type change.
+	oldParams := make(map[string]string, len(lastRunParams))
+	oldParamTypes := make(map[string]string)
+	for paramName, rawValue := range lastRunParams {
+		if skipParameter(paramName) {
+			continue
+		}
+
+		if oldDef, ok := lastRunBundle.Parameters[paramName]; ok {
+			stringValue, err := prepParameterForComparison(lastRunBundle, paramName, rawValue)
+			if err != nil {
+				return InstallationDiff{}, span.Error(fmt.Errorf("error prepping old parameters for comparision: %w", err))
+			}
+			oldParams[paramName] = stringValue
+
+			if newDef, ok := b.Parameters[paramName]; ok {
+				oldType := getParameterDefinitionType(lastRunBundle, oldDef)
+				if newType := getParameterDefinitionType(b, newDef); oldType != newType {
+					oldParamTypes[paramName] = oldType
+				}
+			}
+			continue
+		}
+
+		stringValue, err := prepParameterForComparison(b, paramName, rawValue)
+		if err != nil {
+			span.Debugf("could not convert the old value of parameter %s to its new type, comparing the raw values: %s", paramName, err.Error())
+			stringValue = fmt.Sprintf("%v", rawValue)
+			oldParamTypes[paramName] = "unknown"
+		}
+		oldParams[paramName] = stringValue
+	}
+
+	newParams := make(map[string]string, len(opts.GetParameters()))
+	for paramName, rawValue := range opts.GetParameters() {
+		if skipParameter(paramName) {
+			continue
+		}
+
+		stringValue, err := prepParameterForComparison(b, paramName, rawValue)
+		if err != nil {
+			return InstallationDiff{}, span.Error(fmt.Errorf("error prepping current parameters for comparision: %w", err))
+		}
+		newParams[paramName] = stringValue
+	}
+
+	if !cmp.Equal(oldParams, newParams) || len(oldParamTypes) > 0 {
+		diff.addReason(DiffReasonParametersChanged)
+		diff.Parameters = diffParameterValues(b, toParameterValues(oldParams), toParameterValues(newParams))
+		diff.Parameters = addParameterTypeChanges(b, diff.Parameters, oldParamTypes, oldParams, newParams)
+		diff.parametersDiff = cmp.Diff(oldParams, newParams)
+	}
+
+	// Check only if the names of the associated credential sets have changed
+	// This is a "good enough for now" decision that can be revisited if we
+	// get use cases for needing to diff the actual credentials.
+	sort.Strings(lastRun.CredentialSets)
+	sort.Strings(i.CredentialSets)
+	if !cmp.Equal(lastRun.CredentialSets, i.CredentialSets) {
+		diff.addReason(DiffReasonCredentialSetsChanged)
+		diff.CredentialSets = &CredentialSetsDiff{
+			Current:  lastRun.CredentialSets,
+			Proposed: i.CredentialSets,
+		}
+	}
+
+	diff.InSync = len(diff.Reasons) == 0
+	return diff, nil
+}
+
+// getParameterDefinitionType returns the type of a parameter in the specified bundle.
+func getParameterDefinitionType(bun cnab.ExtendedBundle, param bundle.Parameter) string {
+	if def, ok := bun.Definitions[param.Definition]; ok {
+		return bun.GetParameterType(def)
+	}
+	return "unknown"
+}
+
+// addParameterTypeChanges records the previous type of parameters whose type
+// changed between the last run and the next run. Parameters with an equivalent
+// value, but a different type, are included as a modified parameter.
+func addParameterTypeChanges(bun cnab.ExtendedBundle, diffs []ParameterDiff, oldTypes map[string]string, oldParams map[string]string, newParams map[string]string) []ParameterDiff {
+	if len(oldTypes) == 0 {
+		return diffs
+	}
+
+	for name, oldType := range oldTypes {
+		found := false
+		for i := range diffs {
+			if diffs[i].Name == name {
+				diffs[i].CurrentType = oldType
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		param, ok := bun.Parameters[name]
+		if !ok {
+			continue
+		}
+		d := ParameterDiff{
+			Name:        name,
+			Type:        getParameterDefinitionType(bun, param),
+			CurrentType: oldType,
+			Sensitive:   bun.IsSensitiveParameter(name),
+			Change:      ParameterChangeModified,
+		}
+		if !d.Sensitive {
+			d.Current = oldParams[name]
+			d.Proposed = newParams[name]
+		}
+		diffs = append(diffs, d)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+func toParameterValues(params map[string]string) map[string]interface{} {
+	values := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		values[k] = v
+	}
+	return values
+}
+
+// InstallationDiffOptions represent options for Porter's installation diff command
+type InstallationDiffOptions struct {
+	printer.PrintOptions
+
+	// Namespace of the installation. The namespace in the file, if set, takes precedence.
+	Namespace string
+
+	// File containing the desired state of the installation.
+	File string
+}
+
+// Validate the args provided to the installation diff command
+func (o *InstallationDiffOptions) Validate(cxt *portercontext.Context, args []string) error {
+	applyOpts := ApplyOptions{}
+	if err := applyOpts.Validate(cxt, args); err != nil {
+		return err
+	}
+	o.File = applyOpts.File
+
+	return o.PrintOptions.Validate(printer.FormatPlaintext, []printer.Format{printer.FormatPlaintext, printer.FormatJson, printer.FormatYaml})
+}
+
+// DiffInstallationFile compares an installation file, as used by porter installation apply,
+// with the stored installation and the parameters recorded from its last run.
+func (p *Porter) DiffInstallationFile(ctx context.Context, opts InstallationDiffOptions) (InstallationDiff, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	installation, _, err := p.loadInstallationFromFile(ctx, ApplyOptions{Namespace: opts.Namespace, File: opts.File})
+	if err != nil {
+		return InstallationDiff{}, span.Error(err)
+	}
+
+	lastRun, actionOpts, err := p.prepareReconcile(ctx, &installation, false)
+	if err != nil {
+		return InstallationDiff{}, span.Error(err)
+	}
+
+	return p.DiffInstallation(ctx, installation, lastRun, actionOpts)
+}
+
+// PrintInstallationDiff prints the differences between an installation file and the stored installation.
+func (p *Porter) PrintInstallationDiff(ctx context.Context, opts InstallationDiffOptions) error {
+	diff, err := p.DiffInstallationFile(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, diff)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, diff)
+	case printer.FormatPlaintext:
+		if diff.InSync {
+			fmt.Fprintf(p.Out, "Installation %s is up-to-date\n", diff.Installation)
+		} else {
+			fmt.Fprintf(p.Out, "Installation %s is out-of-sync, applying it runs the %s action\n", diff.Installation, diff.Action)
+		}
+		for _, reason := range diff.Reasons {
+			fmt.Fprintf(p.Out, "  - %s\n", reason)
+		}
+
+		if diff.Bundle != nil {
+			fmt.Fprintln(p.Out)
+			fmt.Fprintln(p.Out, "Bundle:")
+			fmt.Fprintf(p.Out, "  Current:  %s %s\n", diff.Bundle.CurrentReference, diff.Bundle.CurrentDigest)
+			fmt.Fprintf(p.Out, "  Proposed: %s %s\n", diff.Bundle.ProposedReference, diff.Bundle.ProposedDigest)
+		}
+
+		if diff.CredentialSets != nil {
+			fmt.Fprintln(p.Out)
+			fmt.Fprintln(p.Out, "Credential Sets:")
+			fmt.Fprintf(p.Out, "  Current:  %v\n", diff.CredentialSets.Current)
+			fmt.Fprintf(p.Out, "  Proposed: %v\n", diff.CredentialSets.Proposed)
+		}
+
+		if len(diff.Parameters) > 0 {
+			fmt.Fprintln(p.Out)
+			fmt.Fprintln(p.Out, "Parameters:")
+			return printParameterDiffTable(p.Out, diff.Parameters)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
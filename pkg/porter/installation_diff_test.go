@@ -0,0 +1,177 @@
+package porter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationDiffOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/paramset.json", "mybuns.yaml")
+
+	opts := InstallationDiffOptions{}
+	opts.RawFormat = "json"
+	require.NoError(t, opts.Validate(p.Context, []string{"mybuns.yaml"}))
+	assert.Equal(t, "mybuns.yaml", opts.File)
+
+	opts = InstallationDiffOptions{}
+	require.EqualError(t, opts.Validate(p.Context, nil), "a file argument is required")
+}
+
+func TestPorter_DiffInstallation(t *testing.T) {
+	cxt := portercontext.New()
+	bun, err := cnab.LoadBundle(cxt, filepath.Join("testdata/bundle.json"))
+	require.NoError(t, err)
+	now := time.Now()
+
+	t.Run("paused", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		i := storage.NewInstallation("dev", "mybuns")
+		i.Status.Suspended = true
+
+		diff, err := p.DiffInstallation(p.RootContext, i, nil, NewInstallOptions())
+		require.NoError(t, err)
+		assert.True(t, diff.InSync)
+		assert.Equal(t, []string{DiffReasonPaused}, diff.Reasons)
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		i := storage.NewInstallation("dev", "mybuns")
+
+		diff, err := p.DiffInstallation(p.RootContext, i, nil, NewInstallOptions())
+		require.NoError(t, err)
+		assert.False(t, diff.InSync)
+		assert.Equal(t, cnab.ActionInstall, diff.Action)
+		assert.Equal(t, []string{DiffReasonNotInstalled}, diff.Reasons)
+	})
+
+	t.Run("bundle and credential sets changed", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		i := storage.NewInstallation("dev", "mybuns")
+		i.Status.Installed = &now
+		i.CredentialSets = []string{"newcreds"}
+		run := storage.Run{
+			BundleReference: "example.com/mybuns:v0.1.0",
+			BundleDigest:    "olddigest",
+			CredentialSets:  []string{"oldcreds"},
+			Parameters:      storage.NewInternalParameterSet(i.Namespace, i.Name, storage.ValueStrategy("my-second-param", "spring-music-demo")),
+		}
+		upgradeOpts := NewUpgradeOptions()
+		upgradeOpts.bundleRef = &cnab.BundleReference{Definition: bun, Digest: "newdigest"}
+		require.NoError(t, p.applyActionOptionsToInstallation(p.RootContext, upgradeOpts, &i))
+
+		diff, err := p.DiffInstallation(p.RootContext, i, &run, upgradeOpts)
+		require.NoError(t, err)
+		assert.False(t, diff.InSync)
+		assert.Equal(t, cnab.ActionUpgrade, diff.Action)
+		assert.Equal(t, []string{DiffReasonBundleChanged, DiffReasonCredentialSetsChanged}, diff.Reasons)
+		require.NotNil(t, diff.Bundle)
+		assert.Equal(t, "olddigest", diff.Bundle.CurrentDigest)
+		assert.Equal(t, "newdigest", diff.Bundle.ProposedDigest)
+		require.NotNil(t, diff.CredentialSets)
+		assert.Equal(t, []string{"oldcreds"}, diff.CredentialSets.Current)
+		assert.Equal(t, []string{"newcreds"}, diff.CredentialSets.Proposed)
+	})
+
+	t.Run("parameters changed", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		i := storage.NewInstallation("dev", "mybuns")
+		i.Status.Installed = &now
+		run := storage.Run{
+			Parameters: storage.NewInternalParameterSet(i.Namespace, i.Name, storage.ValueStrategy("my-second-param", "oldvalue")),
+		}
+		upgradeOpts := NewUpgradeOptions()
+		upgradeOpts.bundleRef = &cnab.BundleReference{Definition: bun}
+		require.NoError(t, p.applyActionOptionsToInstallation(p.RootContext, upgradeOpts, &i))
+
+		diff, err := p.DiffInstallation(p.RootContext, i, &run, upgradeOpts)
+		require.NoError(t, err)
+		assert.False(t, diff.InSync)
+		assert.Equal(t, []string{DiffReasonParametersChanged}, diff.Reasons)
+		require.Len(t, diff.Parameters, 1)
+		assert.Equal(t, "my-second-param", diff.Parameters[0].Name)
+		assert.Equal(t, ParameterChangeModified, diff.Parameters[0].Change)
+		assert.True(t, diff.Parameters[0].Sensitive)
+		assert.Nil(t, diff.Parameters[0].Current, "sensitive values should not be included in the diff")
+	})
+
+	t.Run("parameter type changed", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		newBundle := func(paramType string) bundle.Bundle {
+			return bundle.Bundle{
+				Definitions: definition.Definitions{
+					"replicas": &definition.Schema{Type: paramType},
+				},
+				Parameters: map[string]bundle.Parameter{
+					"replicas": {Definition: "replicas"},
+				},
+			}
+		}
+
+		i := storage.NewInstallation("dev", "mybuns")
+		i.Status.Installed = &now
+		upgradeOpts := NewUpgradeOptions()
+		upgradeOpts.Params = []string{"replicas=3"}
+		upgradeOpts.bundleRef = &cnab.BundleReference{Definition: cnab.NewBundle(newBundle("integer")), Digest: "newdigest"}
+		require.NoError(t, p.applyActionOptionsToInstallation(p.RootContext, upgradeOpts, &i))
+
+		t.Run("old bundle available", func(t *testing.T) {
+			run := storage.Run{
+				Bundle:       newBundle("string"),
+				BundleDigest: "olddigest",
+				Parameters:   storage.NewInternalParameterSet(i.Namespace, i.Name, storage.ValueStrategy("replicas", "3")),
+			}
+
+			diff, err := p.DiffInstallation(p.RootContext, i, &run, upgradeOpts)
+			require.NoError(t, err)
+			assert.False(t, diff.InSync)
+			assert.Equal(t, []string{DiffReasonBundleChanged, DiffReasonParametersChanged}, diff.Reasons)
+			require.Len(t, diff.Parameters, 1)
+			assert.Equal(t, "replicas", diff.Parameters[0].Name)
+			assert.Equal(t, ParameterChangeModified, diff.Parameters[0].Change)
+			assert.Equal(t, "string", diff.Parameters[0].CurrentType)
+			assert.Equal(t, "integer", diff.Parameters[0].Type)
+			assert.Equal(t, "3", diff.Parameters[0].Current)
+			assert.Equal(t, "3", diff.Parameters[0].Proposed)
+		})
+
+		t.Run("old bundle missing", func(t *testing.T) {
+			run := storage.Run{
+				BundleDigest: "olddigest",
+				Parameters:   storage.NewInternalParameterSet(i.Namespace, i.Name, storage.ValueStrategy("replicas", "three")),
+			}
+
+			diff, err := p.DiffInstallation(p.RootContext, i, &run, upgradeOpts)
+			require.NoError(t, err)
+			assert.False(t, diff.InSync)
+			assert.Equal(t, []string{DiffReasonBundleChanged, DiffReasonParametersChanged}, diff.Reasons)
+			require.Len(t, diff.Parameters, 1)
+			assert.Equal(t, "replicas", diff.Parameters[0].Name)
+			assert.Equal(t, "unknown", diff.Parameters[0].CurrentType)
+			assert.Equal(t, "three", diff.Parameters[0].Current)
+			assert.Equal(t, "3", diff.Parameters[0].Proposed)
+		})
+	})
+}
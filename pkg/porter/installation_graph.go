@@ -0,0 +1,380 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
+	depsv2 "get.porter.sh/porter/pkg/cnab/dependencies/v2"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// GraphEdgeDependency is an edge from an installation to the installation of one of its bundle's dependencies.
+	GraphEdgeDependency = "dependency"
+
+	// GraphEdgeOutput is an edge from an installation to an installation whose
+	// output is used as the value of one of its parameters.
+	GraphEdgeOutput = "output"
+
+	// graphNodeMissing is the status of a node that is referenced by another installation but does not exist.
+	graphNodeMissing = "missing"
+)
+
+// InstallationGraph represents the relationships between installations.
+type InstallationGraph struct {
+	Nodes []InstallationGraphNode `json:"nodes" yaml:"nodes"`
+	Edges []InstallationGraphEdge `json:"edges" yaml:"edges"`
+}
+
+// InstallationGraphNode is an installation in the graph.
+type InstallationGraphNode struct {
+	// ID of the node, the namespace/name of the installation.
+	ID        string `json:"id" yaml:"id"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Name      string `json:"name" yaml:"name"`
+
+	// Status of the installation, or missing when the installation does not exist.
+	Status string `json:"status" yaml:"status"`
+}
+
+// InstallationGraphEdge indicates that the From installation depends upon the To installation.
+type InstallationGraphEdge struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+
+	// Type of relationship: dependency or output.
+	Type string `json:"type" yaml:"type"`
+
+	// Label describes the relationship, for example which output is used by which parameter.
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+// InstallationGraphOptions represent options for Porter's installation graph command
+type InstallationGraphOptions struct {
+	printer.PrintOptions
+
+	// Namespace of the installations to include.
+	Namespace string
+
+	// AllNamespaces includes installations in all namespaces.
+	AllNamespaces bool
+
+	// Name of an installation, when set only installations related to it are included.
+	Name string
+}
+
+// Validate the installation graph command options.
+func (o *InstallationGraphOptions) Validate(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("only one positional argument may be specified, the installation name, but multiple were received: %s", args)
+	}
+	if len(args) == 1 {
+		o.Name = args[0]
+	}
+
+	return o.PrintOptions.Validate(printer.FormatDot, []printer.Format{printer.FormatDot, printer.FormatMermaid, printer.FormatJson, printer.FormatYaml})
+}
+
+func (o InstallationGraphOptions) getNamespace() string {
+	if o.AllNamespaces {
+		return "*"
+	}
+	return o.Namespace
+}
+
+// GetInstallationGraph builds a graph of the relationships between installations,
+// from the dependencies of their bundles and the dependency outputs used as parameters.
+func (p *Porter) GetInstallationGraph(ctx context.Context, opts InstallationGraphOptions) (InstallationGraph, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{Namespace: opts.getNamespace()})
+	if err != nil {
+		return InstallationGraph{}, span.Error(fmt.Errorf("could not list installations: %w", err))
+	}
+
+	// Use the bundle from the last run to determine the parameters sourced from dependency outputs
+	bundles := make(map[string]cnab.ExtendedBundle, len(installations))
+	for _, installation := range installations {
+		run, err := p.Installations.GetLastRun(ctx, installation.Namespace, installation.Name)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound{}) {
+				continue
+			}
+			return InstallationGraph{}, span.Error(fmt.Errorf("could not retrieve the last run of installation %s: %w", installation, err))
+		}
+		bundles[installation.String()] = cnab.NewBundle(run.Bundle)
+	}
+
+	graph, err := buildInstallationGraph(installations, bundles)
+	if err != nil {
+		return InstallationGraph{}, span.Error(err)
+	}
+
+	if opts.Name != "" {
+		root := storage.InstallationSpec{Namespace: opts.Namespace, Name: opts.Name}.String()
+		if _, ok := graph.findNode(root); !ok {
+			return InstallationGraph{}, span.Error(fmt.Errorf("installation %s not found", root))
+		}
+		graph = graph.relatedTo(root)
+	}
+
+	return graph, nil
+}
+
+// PrintInstallationGraph prints the relationships between installations.
+func (p *Porter) PrintInstallationGraph(ctx context.Context, opts InstallationGraphOptions) error {
+	graph, err := p.GetInstallationGraph(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatDot:
+		return graph.WriteDot(p.Out)
+	case printer.FormatMermaid:
+		return graph.WriteMermaid(p.Out)
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, graph)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, graph)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// buildInstallationGraph determines the relationships between the installations.
+// The bundles are the last bundle run by each installation, keyed by namespace/name.
+func buildInstallationGraph(installations []storage.Installation, bundles map[string]cnab.ExtendedBundle) (InstallationGraph, error) {
+	var graph InstallationGraph
+	for _, installation := range installations {
+		graph.Nodes = append(graph.Nodes, InstallationGraphNode{
+			ID:        installation.String(),
+			Namespace: installation.Namespace,
+			Name:      installation.Name,
+			Status:    getDisplayInstallationStatus(installation),
+		})
+	}
+
+	for _, installation := range installations {
+		// Dependencies are labeled with the installation that depends upon them
+		if parent, ok := installation.Labels[LabelParentInstallation]; ok {
+			graph.addEdge(InstallationGraphEdge{From: parent, To: installation.String(), Type: GraphEdgeDependency})
+		}
+
+		bun, ok := bundles[installation.String()]
+		if !ok {
+			continue
+		}
+
+		// Shared dependencies are labeled with the installation that first installed them,
+		// connect them to every installation that uses them
+		if bun.HasDependenciesV2() {
+			deps, err := bun.ReadDependenciesV2()
+			if err != nil {
+				return InstallationGraph{}, fmt.Errorf("could not read the dependencies of installation %s: %w", installation, err)
+			}
+			for _, dep := range deps.ListBySequence() {
+				if !dep.Sharing.IsShared() {
+					continue
+				}
+				depID := getDependencyInstallationID(installations, bun, installation, dep.Name)
+				if depInstallation, ok := findInstallation(installations, depID); ok && depInstallation.Labels[LabelParentInstallation] == installation.String() {
+					// The edge was already added from the label
+					continue
+				}
+				graph.addEdge(InstallationGraphEdge{From: installation.String(), To: depID, Type: GraphEdgeDependency})
+			}
+		}
+
+		if !bun.HasParameterSources() {
+			continue
+		}
+
+		sources, err := bun.ReadParameterSources()
+		if err != nil {
+			return InstallationGraph{}, fmt.Errorf("could not read the parameter sources of installation %s: %w", installation, err)
+		}
+		for _, paramName := range sortedParameterSourceNames(sources) {
+			for _, source := range sources[paramName].ListSourcesByPriority() {
+				depOutput, ok := source.(cnab.DependencyOutputParameterSource)
+				if !ok {
+					continue
+				}
+
+				graph.addEdge(InstallationGraphEdge{
+					From:  installation.String(),
+					To:    getDependencyInstallationID(installations, bun, installation, depOutput.Dependency),
+					Type:  GraphEdgeOutput,
+					Label: fmt.Sprintf("%s -> %s", depOutput.OutputName, paramName),
+				})
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// getDependencyInstallationID returns the namespace/name of the installation of a dependency.
+// A shared dependency is the installation of the same bundle in its sharing group.
+func getDependencyInstallationID(installations []storage.Installation, bun cnab.ExtendedBundle, installation storage.Installation, alias string) string {
+	dep := storage.InstallationSpec{
+		Namespace: installation.Namespace,
+		Name:      depsv1.BuildPrerequisiteInstallationName(installation.Name, alias),
+	}
+
+	depDef, ok, _ := bun.GetDependencyV2(alias)
+	if ok && depDef.Sharing.IsShared() {
+		dep.Name = depsv2.BuildSharedInstallationName(depDef.Sharing.Group.Name, alias)
+		if ref, err := cnab.ParseOCIReference(depDef.Bundle); err == nil {
+			for _, candidate := range installations {
+				if candidate.Namespace == installation.Namespace &&
+					candidate.Labels[depsv2.LabelSharingGroup] == depDef.Sharing.Group.Name &&
+					candidate.Bundle.Repository == ref.Repository() {
+					return candidate.String()
+				}
+			}
+		}
+	}
+	return dep.String()
+}
+
+// findInstallation returns the installation with the specified namespace/name.
+func findInstallation(installations []storage.Installation, id string) (storage.Installation, bool) {
+	for _, installation := range installations {
+		if installation.String() == id {
+			return installation, true
+		}
+	}
+	return storage.Installation{}, false
+}
+
+func sortedParameterSourceNames(sources cnab.ParameterSources) []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addEdge adds an edge to the graph, and a node for each end of the edge that
+// is not an installation in the graph.
+func (g *InstallationGraph) addEdge(edge InstallationGraphEdge) {
+	for _, id := range []string{edge.From, edge.To} {
+		if _, ok := g.findNode(id); !ok {
+			namespace, name, _ := strings.Cut(id, "/")
+			g.Nodes = append(g.Nodes, InstallationGraphNode{ID: id, Namespace: namespace, Name: name, Status: graphNodeMissing})
+		}
+	}
+	g.Edges = append(g.Edges, edge)
+}
+
+func (g InstallationGraph) findNode(id string) (InstallationGraphNode, bool) {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node, true
+		}
+	}
+	return InstallationGraphNode{}, false
+}
+
+// relatedTo returns the subgraph of the installations that the specified
+// installation depends upon, and the installations that depend upon it,
+// either directly or transitively.
+func (g InstallationGraph) relatedTo(id string) InstallationGraph {
+	included := map[string]bool{id: true}
+
+	// Walk the edges in each direction separately, so that siblings that share
+	// a dependency with the installation are not included.
+	walk := func(next func(edge InstallationGraphEdge) (string, string)) {
+		queue := []string{id}
+		visited := map[string]bool{id: true}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, edge := range g.Edges {
+				from, to := next(edge)
+				if from == current && !visited[to] {
+					visited[to] = true
+					included[to] = true
+					queue = append(queue, to)
+				}
+			}
+		}
+	}
+	walk(func(edge InstallationGraphEdge) (string, string) { return edge.From, edge.To })
+	walk(func(edge InstallationGraphEdge) (string, string) { return edge.To, edge.From })
+
+	var subgraph InstallationGraph
+	for _, node := range g.Nodes {
+		if included[node.ID] {
+			subgraph.Nodes = append(subgraph.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if included[edge.From] && included[edge.To] {
+			subgraph.Edges = append(subgraph.Edges, edge)
+		}
+	}
+	return subgraph
+}
+
+// edgeLabel is the text displayed on an edge when the graph is rendered.
+func (e InstallationGraphEdge) edgeLabel() string {
+	if e.Label == "" {
+		return e.Type
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Label)
+}
+
+// WriteDot renders the graph in the Graphviz DOT language.
+func (g InstallationGraph) WriteDot(out io.Writer) error {
+	quote := func(value string) string {
+		return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	}
+
+	var buf strings.Builder
+	buf.WriteString("digraph installations {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&buf, "  %s [label=%s];\n", quote(node.ID), quote(fmt.Sprintf("%s\\n(%s)", node.ID, node.Status)))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&buf, "  %s -> %s [label=%s];\n", quote(edge.From), quote(edge.To), quote(edge.edgeLabel()))
+	}
+	buf.WriteString("}\n")
+
+	_, err := io.WriteString(out, buf.String())
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart.
+func (g InstallationGraph) WriteMermaid(out io.Writer) error {
+	escape := func(value string) string {
+		return strings.ReplaceAll(value, `"`, "#quot;")
+	}
+
+	// Mermaid node ids cannot contain slashes, so use the index of the node instead
+	ids := make(map[string]string, len(g.Nodes))
+	var buf strings.Builder
+	buf.WriteString("graph LR\n")
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&buf, "  %s[\"%s (%s)\"]\n", ids[node.ID], escape(node.ID), node.Status)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&buf, "  %s -->|\"%s\"| %s\n", ids[edge.From], escape(edge.edgeLabel()), ids[edge.To])
+	}
+
+	_, err := io.WriteString(out, buf.String())
+	return err
+}
@@ -0,0 +1,100 @@
+package porter
+
+import (
+	"bytes"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationGraphOptions_Validate(t *testing.T) {
+	opts := InstallationGraphOptions{}
+	require.NoError(t, opts.Validate([]string{"myapp"}))
+	assert.Equal(t, "myapp", opts.Name)
+	assert.Equal(t, printer.FormatDot, opts.Format)
+
+	opts = InstallationGraphOptions{}
+	require.ErrorContains(t, opts.Validate([]string{"a", "b"}), "only one positional argument may be specified")
+
+	opts = InstallationGraphOptions{}
+	opts.RawFormat = "plaintext"
+	require.ErrorContains(t, opts.Validate(nil), "invalid format")
+}
+
+func buildTestInstallationGraph(t *testing.T) InstallationGraph {
+	app := storage.NewInstallation("dev", "myapp")
+	db := storage.NewInstallation("dev", "myapp-mysql")
+	db.SetLabel(LabelParentInstallation, "dev/myapp")
+	other := storage.NewInstallation("dev", "other")
+
+	ps := cnab.ParameterSources{}
+	ps.SetParameterFromDependencyOutput("connstr", "mysql", "connection-string")
+	ps.SetParameterFromDependencyOutput("cache", "redis", "endpoint")
+	bundles := map[string]cnab.ExtendedBundle{
+		"dev/myapp": cnab.NewBundle(bundle.Bundle{
+			Custom: map[string]interface{}{cnab.ParameterSourcesExtensionKey: ps},
+		}),
+	}
+
+	graph, err := buildInstallationGraph([]storage.Installation{app, db, other}, bundles)
+	require.NoError(t, err)
+	return graph
+}
+
+func TestBuildInstallationGraph(t *testing.T) {
+	graph := buildTestInstallationGraph(t)
+
+	require.Len(t, graph.Nodes, 4)
+	missing, ok := graph.findNode("dev/myapp-redis")
+	require.True(t, ok, "the redis dependency should be included even though the installation does not exist")
+	assert.Equal(t, graphNodeMissing, missing.Status)
+
+	assert.Equal(t, []InstallationGraphEdge{
+		{From: "dev/myapp", To: "dev/myapp-redis", Type: GraphEdgeOutput, Label: "endpoint -> cache"},
+		{From: "dev/myapp", To: "dev/myapp-mysql", Type: GraphEdgeOutput, Label: "connection-string -> connstr"},
+		{From: "dev/myapp", To: "dev/myapp-mysql", Type: GraphEdgeDependency},
+	}, graph.Edges)
+
+	related := graph.relatedTo("dev/myapp-mysql")
+	var ids []string
+	for _, node := range related.Nodes {
+		ids = append(ids, node.ID)
+	}
+	assert.Equal(t, []string{"dev/myapp", "dev/myapp-mysql"}, ids, "siblings and unrelated installations should not be included")
+	assert.Len(t, related.Edges, 2)
+}
+
+func TestInstallationGraph_Write(t *testing.T) {
+	graph := InstallationGraph{
+		Nodes: []InstallationGraphNode{
+			{ID: "dev/myapp", Status: "succeeded"},
+			{ID: "dev/myapp-mysql", Status: "installed"},
+		},
+		Edges: []InstallationGraphEdge{
+			{From: "dev/myapp", To: "dev/myapp-mysql", Type: GraphEdgeOutput, Label: `"quoted"`},
+		},
+	}
+
+	var dot bytes.Buffer
+	require.NoError(t, graph.WriteDot(&dot))
+	assert.Equal(t, `digraph installations {
+  rankdir=LR;
+  "dev/myapp" [label="dev/myapp\n(succeeded)"];
+  "dev/myapp-mysql" [label="dev/myapp-mysql\n(installed)"];
+  "dev/myapp" -> "dev/myapp-mysql" [label="output: \"quoted\""];
+}
+`, dot.String())
+
+	var mermaid bytes.Buffer
+	require.NoError(t, graph.WriteMermaid(&mermaid))
+	assert.Equal(t, `graph LR
+  n0["dev/myapp (succeeded)"]
+  n1["dev/myapp-mysql (installed)"]
+  n0 -->|"output: #quot;quoted#quot;"| n1
+`, mermaid.String())
+}
@@ -0,0 +1,339 @@
+package porter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ImportClaimOptions represent options for Porter's installation import command
+type ImportClaimOptions struct {
+	// Namespace in which to create the installation.
+	Namespace string
+
+	// Name of the installation. Defaults to the installation name in the claims.
+	Name string
+
+	// File containing the claims to import.
+	File string
+}
+
+// Validate the installation import command options.
+func (o *ImportClaimOptions) Validate(args []string, cxt *portercontext.Context) error {
+	if len(args) != 1 {
+		return fmt.Errorf("a claim file must be specified, but %d arguments were received", len(args))
+	}
+	o.File = args[0]
+
+	exists, err := cxt.FileSystem.Exists(o.File)
+	if err != nil {
+		return fmt.Errorf("error checking if the claim file %s exists: %w", o.File, err)
+	}
+	if !exists {
+		return fmt.Errorf("claim file %s does not exist", o.File)
+	}
+
+	if strings.Contains(o.Name, "/") {
+		return fmt.Errorf("invalid --name %s, the name of an installation cannot contain a slash", o.Name)
+	}
+	return nil
+}
+
+// importedClaim is a CNAB claim produced by another tool, such as cnab-go or
+// duffle. The CNAB claim specification stores results separately from the
+// claim, so they may be included with the claim in the results field. The
+// results and outputs fields of older claims are also supported.
+type importedClaim struct {
+	ID              string                 `json:"id"`
+	Installation    string                 `json:"installation"`
+	Revision        string                 `json:"revision"`
+	Created         time.Time              `json:"created"`
+	Action          string                 `json:"action"`
+	Bundle          bundle.Bundle          `json:"bundle"`
+	BundleReference string                 `json:"bundleReference,omitempty"`
+	Parameters      map[string]interface{} `json:"parameters,omitempty"`
+	Custom          interface{}            `json:"custom,omitempty"`
+
+	// Results of running the claim's action.
+	Results []cnab.Result `json:"results,omitempty"`
+
+	// Result of the claim's action, used by older claims that embed a single result.
+	Result *importedClaimResult `json:"result,omitempty"`
+
+	// Outputs generated by the claim's action, keyed by the output name.
+	Outputs map[string]interface{} `json:"outputs,omitempty"`
+}
+
+// importedClaimResult is the embedded result of an older claim.
+type importedClaimResult struct {
+	Message string `json:"message,omitempty"`
+	Status  string `json:"status"`
+}
+
+// parseImportedClaims reads either a single claim, or an array of claims, sorted by when they were created.
+func parseImportedClaims(data []byte) ([]importedClaim, error) {
+	var claims []importedClaim
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &claims); err != nil {
+			return nil, fmt.Errorf("error parsing the claims: %w", err)
+		}
+	} else {
+		var c importedClaim
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("error parsing the claim: %w", err)
+		}
+		claims = append(claims, c)
+	}
+
+	if len(claims) == 0 {
+		return nil, errors.New("the file does not contain any claims")
+	}
+
+	for i, c := range claims {
+		if c.Action == "" {
+			return nil, fmt.Errorf("claim %d is invalid: the action is required", i+1)
+		}
+		if c.Installation != claims[0].Installation {
+			return nil, fmt.Errorf("all claims must be for the same installation, but found both %s and %s", claims[0].Installation, c.Installation)
+		}
+	}
+
+	sort.SliceStable(claims, func(i, j int) bool {
+		return claims[i].Created.Before(claims[j].Created)
+	})
+	return claims, nil
+}
+
+// installationName returns the name of the installation from a claim. Porter
+// includes the namespace in the installation name of the claims it generates,
+// e.g. dev/mysql, which is removed.
+func (c importedClaim) installationName() string {
+	if i := strings.LastIndex(c.Installation, "/"); i >= 0 {
+		return c.Installation[i+1:]
+	}
+	return c.Installation
+}
+
+// toResults converts the results of the claim into results of the run.
+func (c importedClaim) toResults(run storage.Run) []storage.Result {
+	var results []storage.Result
+	for _, src := range c.Results {
+		result := run.NewResultFrom(src)
+		if result.ID == "" {
+			result.ID = cnab.NewULID()
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Created.Before(results[j].Created)
+	})
+
+	if len(results) == 0 && c.Result != nil {
+		result := run.NewResult(c.Result.Status)
+		result.Created = run.Created
+		result.Message = c.Result.Message
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// toOutputs converts the outputs of the claim into outputs of the result.
+func (c importedClaim) toOutputs(result storage.Result) ([]storage.Output, error) {
+	names := make([]string, 0, len(c.Outputs))
+	for name := range c.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	outputs := make([]storage.Output, 0, len(names))
+	for _, name := range names {
+		var value []byte
+		switch v := c.Outputs[name].(type) {
+		case string:
+			value = []byte(v)
+		default:
+			var err error
+			if value, err = json.Marshal(v); err != nil {
+				return nil, fmt.Errorf("invalid value for output %s: %w", name, err)
+			}
+		}
+		outputs = append(outputs, result.NewOutput(name, value))
+	}
+	return outputs, nil
+}
+
+// importedInstallation is an installation, and its history, converted from claims.
+type importedInstallation struct {
+	Installation storage.Installation
+	Runs         []storage.Run
+	Results      []storage.Result
+	Outputs      []storage.Output
+}
+
+// ImportClaims creates an installation from claims generated by another CNAB tool,
+// converting each claim into a run, so that the history of the installation is kept.
+func (p *Porter) ImportClaims(ctx context.Context, opts ImportClaimOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	data, err := p.FileSystem.ReadFile(opts.File)
+	if err != nil {
+		return span.Error(fmt.Errorf("could not read claim file %s: %w", opts.File, err))
+	}
+
+	claims, err := parseImportedClaims(data)
+	if err != nil {
+		return span.Error(fmt.Errorf("invalid claim file %s: %w", opts.File, err))
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = claims[0].installationName()
+	}
+	if name == "" {
+		return span.Error(errors.New("the claims do not specify an installation name, use --name to set it"))
+	}
+
+	_, err = p.Installations.GetInstallation(ctx, opts.Namespace, name)
+	if err == nil {
+		return span.Error(fmt.Errorf("installation %s/%s already exists", opts.Namespace, name))
+	}
+	if !errors.Is(err, storage.ErrNotFound{}) {
+		return span.Error(err)
+	}
+
+	imported, err := p.convertImportedClaims(ctx, opts.Namespace, name, claims)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	if err = p.saveImportedInstallation(ctx, imported); err != nil {
+		return span.Error(err)
+	}
+
+	span.Infof("Imported installation %s with %d run(s)", imported.Installation, len(imported.Runs))
+	return nil
+}
+
+// convertImportedClaims converts the claims into an installation and its runs,
+// results and outputs. Sensitive parameters and outputs are saved to the
+// secret store.
+func (p *Porter) convertImportedClaims(ctx context.Context, namespace string, name string, claims []importedClaim) (importedInstallation, error) {
+	inst := storage.NewInstallation(namespace, name)
+	inst.Status = storage.InstallationStatus{}
+	imported := importedInstallation{}
+
+	for _, c := range claims {
+		run := inst.NewRun(c.Action)
+		if c.ID != "" {
+			run.ID = c.ID
+		}
+		if c.Revision != "" {
+			run.Revision = c.Revision
+		}
+		if !c.Created.IsZero() {
+			run.Created = c.Created
+		}
+		run.Bundle = c.Bundle
+		run.BundleReference = c.BundleReference
+		run.Custom = c.Custom
+		run.Parameters = inst.NewInternalParameterSet()
+
+		bun := cnab.NewBundle(c.Bundle)
+		params, err := p.Sanitizer.CleanRawParameters(ctx, c.Parameters, bun, run.ID)
+		if err != nil {
+			return importedInstallation{}, fmt.Errorf("could not import the parameters of claim %s: %w", run.ID, err)
+		}
+		run.Parameters.Parameters = params
+
+		// The installation was created by the first claim, and last modified by the most recent claim
+		if inst.Status.Created.IsZero() {
+			inst.Status.Created = run.Created
+		}
+		inst.Status.Modified = run.Created
+
+		results := c.toResults(run)
+		for _, result := range results {
+			inst.ApplyResult(run, result)
+		}
+		if action, err := run.Bundle.GetAction(run.Action); err == nil && action.Modifies {
+			inst.Parameters.Parameters = run.Parameters.Parameters
+			if run.BundleReference != "" {
+				if ref, err := cnab.ParseOCIReference(run.BundleReference); err == nil {
+					inst.Bundle = storage.OCIReferenceParts{}
+					inst.TrackBundle(ref)
+				}
+			}
+		}
+
+		// Outputs are associated with the final result of the run
+		if len(results) > 0 {
+			outputs, err := c.toOutputs(results[len(results)-1])
+			if err != nil {
+				return importedInstallation{}, fmt.Errorf("could not import the outputs of claim %s: %w", run.ID, err)
+			}
+			for _, output := range outputs {
+				output, err = p.Sanitizer.CleanOutput(ctx, output, bun)
+				if err != nil {
+					return importedInstallation{}, fmt.Errorf("could not import output %s of claim %s: %w", output.Name, run.ID, err)
+				}
+				imported.Outputs = append(imported.Outputs, output)
+			}
+		}
+
+		imported.Runs = append(imported.Runs, run)
+		imported.Results = append(imported.Results, results...)
+	}
+
+	imported.Installation = inst
+	return imported, nil
+}
+
+// saveImportedInstallation saves the installation and its history. When a
+// document cannot be saved, the installation is removed so that it is not
+// partially imported.
+func (p *Porter) saveImportedInstallation(ctx context.Context, imported importedInstallation) error {
+	inst := imported.Installation
+	if err := p.Installations.InsertInstallation(ctx, inst); err != nil {
+		return fmt.Errorf("could not save installation %s: %w", inst, err)
+	}
+
+	cleanup := func(saveErr error) error {
+		var bigErr *multierror.Error
+		bigErr = multierror.Append(bigErr, saveErr)
+		if err := p.Installations.RemoveInstallation(ctx, inst.Namespace, inst.Name); err != nil {
+			bigErr = multierror.Append(bigErr, fmt.Errorf("could not remove the partially imported installation %s: %w", inst, err))
+		}
+		return bigErr.ErrorOrNil()
+	}
+
+	for _, run := range imported.Runs {
+		if err := p.Installations.InsertRun(ctx, run); err != nil {
+			return cleanup(fmt.Errorf("could not save run %s: %w", run.ID, err))
+		}
+	}
+	for _, result := range imported.Results {
+		if err := p.Installations.InsertResult(ctx, result); err != nil {
+			return cleanup(fmt.Errorf("could not save result %s: %w", result.ID, err))
+		}
+	}
+	for _, output := range imported.Outputs {
+		if err := p.Installations.InsertOutput(ctx, output); err != nil {
+			return cleanup(fmt.Errorf("could not save output %s: %w", output.Name, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package porter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportClaimOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.TestConfig.TestContext.AddTestFile("testdata/import/claims.json", "claims.json")
+
+	opts := ImportClaimOptions{}
+	require.NoError(t, opts.Validate([]string{"claims.json"}, p.Context))
+	assert.Equal(t, "claims.json", opts.File)
+
+	opts = ImportClaimOptions{}
+	require.EqualError(t, opts.Validate([]string{"missing.json"}, p.Context), "claim file missing.json does not exist")
+
+	opts = ImportClaimOptions{Name: "dev/mysql"}
+	require.ErrorContains(t, opts.Validate([]string{"claims.json"}, p.Context), "cannot contain a slash")
+}
+
+func TestParseImportedClaims(t *testing.T) {
+	c, err := parseImportedClaims([]byte(`{"installation": "dev/mysql", "action": "install"}`))
+	require.NoError(t, err)
+	require.Len(t, c, 1)
+	assert.Equal(t, "mysql", c[0].installationName(), "the namespace should be removed from claims generated by porter")
+
+	_, err = parseImportedClaims([]byte(`[{"installation": "mysql", "action": "install"}, {"installation": "redis", "action": "install"}]`))
+	require.ErrorContains(t, err, "all claims must be for the same installation")
+
+	_, err = parseImportedClaims([]byte(`{"installation": "mysql"}`))
+	require.ErrorContains(t, err, "the action is required")
+
+	_, err = parseImportedClaims([]byte(`[]`))
+	require.ErrorContains(t, err, "does not contain any claims")
+}
+
+func TestPorter_ConvertImportedClaims(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	data, err := os.ReadFile("testdata/import/claims.json")
+	require.NoError(t, err)
+	claims, err := parseImportedClaims(data)
+	require.NoError(t, err)
+
+	imported, err := p.convertImportedClaims(context.Background(), "dev", "mysql", claims)
+	require.NoError(t, err)
+
+	// The claims are imported in the order they were created
+	require.Len(t, imported.Runs, 2)
+	assert.Equal(t, cnab.ActionInstall, imported.Runs[0].Action)
+	assert.Equal(t, cnab.ActionUpgrade, imported.Runs[1].Action)
+	assert.Equal(t, "01GR2PVRCBGKGSGZE3F2AQDTQT", imported.Runs[1].ID, "the claim id should be used as the run id")
+	assert.Equal(t, map[string]interface{}{"port": 3307}, imported.Runs[1].TypedParameterValues())
+
+	require.Len(t, imported.Results, 3)
+	assert.Equal(t, "installed by duffle", imported.Results[0].Message, "the embedded result of an older claim should be imported")
+	assert.Equal(t, imported.Runs[0].ID, imported.Results[0].RunID)
+
+	require.Len(t, imported.Outputs, 1)
+	assert.Equal(t, "connstr", imported.Outputs[0].Name)
+	assert.Equal(t, "mysql://localhost:3307", string(imported.Outputs[0].Value))
+	assert.Equal(t, "01GR2PVRCBGKGSGZE3F5R0HEFC", imported.Outputs[0].ResultID, "outputs should be associated with the final result")
+
+	inst := imported.Installation
+	assert.Equal(t, "dev/mysql", inst.String())
+	assert.True(t, inst.IsInstalled())
+	assert.Equal(t, cnab.ActionUpgrade, inst.Status.Action)
+	assert.Equal(t, cnab.StatusSucceeded, inst.Status.ResultStatus)
+	assert.Equal(t, "example/mysql", inst.Bundle.Repository)
+	assert.Equal(t, "0.2.0", inst.Bundle.Version)
+	assert.Equal(t, claims[0].Created, inst.Status.Created)
+	assert.Equal(t, claims[1].Created, inst.Status.Modified)
+}
@@ -14,8 +14,11 @@ import (
 	"get.porter.sh/porter/pkg/secrets/plugins/filesystem"
 	"get.porter.sh/porter/pkg/secrets/plugins/host"
 	storageplugins "get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/plugins/bolt"
+	"get.porter.sh/porter/pkg/storage/plugins/dynamodb"
 	"get.porter.sh/porter/pkg/storage/plugins/mongodb"
 	"get.porter.sh/porter/pkg/storage/plugins/mongodb_docker"
+	"get.porter.sh/porter/pkg/storage/plugins/postgres"
 	"get.porter.sh/porter/pkg/tracing"
 	"github.com/hashicorp/go-plugin"
 )
@@ -105,6 +108,11 @@ type InternalPlugin struct {
 	Interface       string
 	ProtocolVersion int
 	Create          func(c *config.Config, pluginCfg interface{}) (plugin.Plugin, error)
+
+	// NewConfig returns a pointer to the configuration of the plugin, used to
+	// validate the plugin configuration in the config file. It is nil when the
+	// plugin does not have any configuration.
+	NewConfig func() interface{}
 }
 
 // A long running plugin needs to setup a connection or other resources first,
@@ -129,12 +137,35 @@ func getInternalPlugins() map[string]InternalPlugin {
 				return filesystem.NewPlugin(c, pluginCfg), nil
 			},
 		},
+		bolt.PluginKey: {
+			Interface:       storageplugins.PluginInterface,
+			ProtocolVersion: storageplugins.PluginProtocolVersion,
+			Create: func(c *config.Config, pluginCfg interface{}) (plugin.Plugin, error) {
+				return bolt.NewPlugin(c, pluginCfg)
+			},
+			NewConfig: func() interface{} {
+				return &bolt.PluginConfig{}
+			},
+		},
+		dynamodb.PluginKey: {
+			Interface:       storageplugins.PluginInterface,
+			ProtocolVersion: storageplugins.PluginProtocolVersion,
+			Create: func(c *config.Config, pluginCfg interface{}) (plugin.Plugin, error) {
+				return dynamodb.NewPlugin(c.Context, pluginCfg)
+			},
+			NewConfig: func() interface{} {
+				return &dynamodb.PluginConfig{}
+			},
+		},
 		mongodb.PluginKey: {
 			Interface:       storageplugins.PluginInterface,
 			ProtocolVersion: storageplugins.PluginProtocolVersion,
 			Create: func(c *config.Config, pluginCfg interface{}) (plugin.Plugin, error) {
 				return mongodb.NewPlugin(c.Context, pluginCfg)
 			},
+			NewConfig: func() interface{} {
+				return &mongodb.PluginConfig{}
+			},
 		},
 		mongodb_docker.PluginKey: {
 			Interface:       storageplugins.PluginInterface,
@@ -142,6 +173,19 @@ func getInternalPlugins() map[string]InternalPlugin {
 			Create: func(c *config.Config, pluginCfg interface{}) (plugin.Plugin, error) {
 				return mongodb_docker.NewPlugin(c.Context, pluginCfg)
 			},
+			NewConfig: func() interface{} {
+				return &mongodb_docker.PluginConfig{}
+			},
+		},
+		postgres.PluginKey: {
+			Interface:       storageplugins.PluginInterface,
+			ProtocolVersion: storageplugins.PluginProtocolVersion,
+			Create: func(c *config.Config, pluginCfg interface{}) (plugin.Plugin, error) {
+				return postgres.NewPlugin(c.Context, pluginCfg)
+			},
+			NewConfig: func() interface{} {
+				return &postgres.PluginConfig{}
+			},
 		},
 	}
 }
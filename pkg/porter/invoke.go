@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
+	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/storage"
 )
 
@@ -14,9 +17,13 @@ var _ BundleAction = NewInvokeOptions()
 // Porter handles defaulting any missing values.
 type InvokeOptions struct {
 	*BundleExecutionOptions
+	BulkOptions
 
 	// Action name to invoke
 	Action string
+
+	// Arguments is the unparsed list of NAME=VALUE arguments passed to the custom action.
+	Arguments []string
 }
 
 func NewInvokeOptions() InvokeOptions {
@@ -38,12 +45,38 @@ func (o InvokeOptions) Validate(ctx context.Context, args []string, p *Porter) e
 		return errors.New("--action is required")
 	}
 
+	if _, err := storage.ParseVariableAssignments(o.Arguments); err != nil {
+		return fmt.Errorf("invalid --arg: %w", err)
+	}
+
+	if err := o.BulkOptions.Validate(args); err != nil {
+		return err
+	}
+	if o.IsBulk() {
+		return o.BundleExecutionOptions.validateBulk(p)
+	}
+
 	return o.BundleExecutionOptions.Validate(ctx, args, p)
 }
 
 // InvokeBundle accepts a set of pre-validated InvokeOptions and uses
 // them to upgrade a bundle.
 func (p *Porter) InvokeBundle(ctx context.Context, opts InvokeOptions) error {
+	if opts.IsBulk() {
+		return p.executeBulkAction(ctx, opts.Namespace, opts.GetAction(), opts.BulkOptions, func(ctx context.Context, worker *Porter, name string) error {
+			installationOpts := opts
+			installationOpts.BundleExecutionOptions = opts.forInstallation(name)
+			installationOpts.BulkOptions = BulkOptions{}
+			return worker.InvokeBundle(ctx, installationOpts)
+		})
+	}
+
+	unlock, err := p.lockInstallation(ctx, opts.Namespace, opts.Name, opts.GetAction(), opts.WaitForLock)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Figure out which bundle/installation we are working with
 	bundleRef, err := opts.GetBundleReference(ctx, p)
 	if err != nil {
@@ -73,3 +106,38 @@ func (p *Porter) InvokeBundle(ctx context.Context, opts InvokeOptions) error {
 
 	return p.ExecuteAction(ctx, installation, opts)
 }
+
+// resolveArguments validates the arguments passed to the custom action against the arguments
+// that it declares, and returns the value of the internal parameter used to pass each argument
+// to the bundle.
+func (o InvokeOptions) resolveArguments(bun cnab.ExtendedBundle) (map[string]string, error) {
+	values, err := storage.ParseVariableAssignments(o.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --arg: %w", err)
+	}
+
+	declared := bun.GetActionArguments(o.Action)
+	params := make(map[string]string, len(declared))
+	for _, arg := range declared {
+		value, ok := values[arg.Name]
+		if !ok {
+			if arg.Required {
+				return nil, fmt.Errorf("the %s action requires the argument %s, specify it with --arg %s=VALUE", o.Action, arg.Name, arg.Name)
+			}
+			continue
+		}
+		params[arg.Parameter] = value
+		delete(values, arg.Name)
+	}
+
+	if len(values) > 0 {
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("invalid --arg %s, the %s action does not declare an argument with that name", strings.Join(names, ", "), o.Action)
+	}
+
+	return params, nil
+}
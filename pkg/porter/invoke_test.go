@@ -19,3 +19,46 @@ func TestInvokeOptions_Validate_ActionRequired(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "--action is required")
 }
+
+func TestInvokeOptions_resolveArguments(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/explain/action-arguments-bundle.json", "bundle.json")
+	bun, err := p.CNAB.LoadBundle("bundle.json")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name       string
+		args       []string
+		wantParams map[string]string
+		wantErr    string
+	}{
+		{name: "required argument", args: []string{"target=s3://mybucket"},
+			wantParams: map[string]string{"porter-backup-target-arg": "s3://mybucket"}},
+		{name: "all arguments", args: []string{"target=s3://mybucket", "retention=30d"},
+			wantParams: map[string]string{"porter-backup-target-arg": "s3://mybucket", "porter-backup-retention-arg": "30d"}},
+		{name: "missing required argument", args: []string{"retention=30d"},
+			wantErr: "the backup action requires the argument target, specify it with --arg target=VALUE"},
+		{name: "undeclared argument", args: []string{"target=s3://mybucket", "region=eastus"},
+			wantErr: "invalid --arg region, the backup action does not declare an argument with that name"},
+		{name: "invalid format", args: []string{"target"},
+			wantErr: "invalid --arg: invalid parameter (target), must be in name=value format"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			opts := NewInvokeOptions()
+			opts.Action = "backup"
+			opts.Arguments = tc.args
+
+			params, err := opts.resolveArguments(bun)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantParams, params)
+		})
+	}
+}
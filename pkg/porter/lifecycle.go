@@ -4,11 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"get.porter.sh/porter/pkg/cache"
 	"get.porter.sh/porter/pkg/cnab"
-	"get.porter.sh/porter/pkg/cnab/drivers"
 	cnabprovider "get.porter.sh/porter/pkg/cnab/provider"
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/encoding"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/storage"
@@ -41,6 +42,14 @@ type BundleExecutionOptions struct {
 	// AllowDockerHostAccess grants the bundle access to the Docker socket.
 	AllowDockerHostAccess bool
 
+	// AllowHooks runs the pre and post hooks defined by the bundle on the host.
+	AllowHooks bool
+
+	// DryRun resolves the bundle, its dependencies, parameters and credentials,
+	// and prints what would be executed without saving the installation or
+	// running the bundle.
+	DryRun bool
+
 	// DebugMode indicates if the bundle should be run in debug mode.
 	DebugMode bool
 
@@ -59,6 +68,34 @@ type BundleExecutionOptions struct {
 	// Driver is the CNAB-compliant driver used to run bundle actions.
 	Driver string
 
+	// DriverOptions are the unparsed list of KEY=VALUE settings for the
+	// invocation container run by the docker driver, such as resource limits.
+	DriverOptions []string
+
+	// WaitForLock is how long to wait for another action against the installation
+	// to complete. Defaults to failing immediately when the installation is locked.
+	WaitForLock time.Duration
+
+	// LogFormat is the format of the output generated by the bundle: text or json.
+	LogFormat string
+
+	// Retries is the number of times a failed step is retried. Overrides the
+	// retry policy defined in the bundle when set.
+	Retries int
+
+	// RetryBackoff is how long to wait before retrying a failed step, doubled
+	// after each retry. Overrides the retry policy defined in the bundle when set.
+	RetryBackoff time.Duration
+
+	// Timeout is how long the action may run before it is stopped. Overrides the
+	// timeout policy defined in the bundle when set.
+	Timeout time.Duration
+
+	// DependencyParallelism is the maximum number of dependencies that are executed
+	// at the same time. Dependencies that use the outputs of another dependency are
+	// executed after it. Defaults to executing the dependencies one at a time.
+	DependencyParallelism int
+
 	// parameters that are intended for dependencies
 	// This is legacy support for v1 of dependencies where you could pass a parameter to a dependency directly using special formatting
 	// Example: --param mysql#username=admin
@@ -68,6 +105,10 @@ type BundleExecutionOptions struct {
 	// A cache of the final resolved set of parameters that are passed to the bundle
 	// Do not use directly, use GetParameters instead.
 	finalParams map[string]interface{}
+
+	// run is an existing run that the action is recorded on, instead of a new
+	// run, so that the polls of a readiness check are recorded as a single run.
+	run *storage.Run
 }
 
 func NewBundleExecutionOptions() *BundleExecutionOptions {
@@ -91,6 +132,26 @@ func (o *BundleExecutionOptions) GetParameters() map[string]interface{} {
 }
 
 func (o *BundleExecutionOptions) Validate(ctx context.Context, args []string, p *Porter) error {
+	if o.WaitForLock < 0 {
+		return errors.New("--wait-for-lock must not be negative")
+	}
+
+	if err := o.validateLogFormat(); err != nil {
+		return err
+	}
+
+	if err := o.validateRetries(); err != nil {
+		return err
+	}
+
+	if err := o.validateTimeout(); err != nil {
+		return err
+	}
+
+	if err := o.validateDependencyParallelism(); err != nil {
+		return err
+	}
+
 	if err := o.BundleReferenceOptions.Validate(ctx, args, p); err != nil {
 		return err
 	}
@@ -100,6 +161,10 @@ func (o *BundleExecutionOptions) Validate(ctx context.Context, args []string, p
 		return err
 	}
 
+	if err := o.validateDriverOptions(p.Data.Docker); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -129,10 +194,61 @@ func (o *BundleExecutionOptions) defaultDriver(p *Porter) {
 
 // validateDriver validates that the provided driver is supported by Porter
 func (o *BundleExecutionOptions) validateDriver(cxt *portercontext.Context) error {
-	_, err := drivers.LookupDriver(cxt, o.Driver)
+	_, err := cnabprovider.LookupDriver(cxt, o.Driver)
 	return err
 }
 
+// validateDriverOptions validates the --driver-opt flags, combined with the docker
+// settings from the config file when the docker driver is used.
+func (o *BundleExecutionOptions) validateDriverOptions(cfg config.DockerConfig) error {
+	if o.Driver != cnabprovider.DriverNameDocker {
+		if len(o.DriverOptions) > 0 {
+			return fmt.Errorf("--driver-opt is only supported by the %s driver", cnabprovider.DriverNameDocker)
+		}
+		return nil
+	}
+
+	_, err := cnabprovider.ParseDockerOptions(append(cfg.GetDriverOptions(), o.DriverOptions...))
+	return err
+}
+
+// validateLogFormat validates that the bundle output may be generated in the requested format.
+func (o *BundleExecutionOptions) validateLogFormat() error {
+	switch o.LogFormat {
+	case "", config.LogFormatText, config.LogFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --log-format %s, allowed values are: %s, %s", o.LogFormat, config.LogFormatText, config.LogFormatJSON)
+	}
+}
+
+// validateRetries validates the flags that override the retry policy of the bundle.
+func (o *BundleExecutionOptions) validateRetries() error {
+	if o.Retries < 0 {
+		return errors.New("--retries must not be negative")
+	}
+	if o.RetryBackoff < 0 {
+		return errors.New("--retry-backoff must not be negative")
+	}
+	return nil
+}
+
+// validateTimeout validates the flag that overrides the timeout policy of the bundle.
+func (o *BundleExecutionOptions) validateTimeout() error {
+	if o.Timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	return nil
+}
+
+// validateDependencyParallelism validates the flag that limits how many dependencies are executed at the same time.
+func (o *BundleExecutionOptions) validateDependencyParallelism() error {
+	if o.DependencyParallelism < 0 {
+		return errors.New("--dependency-parallelism must not be negative")
+	}
+	return nil
+}
+
 // BundleReferenceOptions are the set of options available for commands that accept a bundle reference
 type BundleReferenceOptions struct {
 	installationOptions
@@ -288,7 +404,15 @@ func (p *Porter) BuildActionArgs(ctx context.Context, installation storage.Insta
 		Params:                opts.GetParameters(),
 		Driver:                opts.Driver,
 		AllowDockerHostAccess: opts.AllowDockerHostAccess,
+		DriverOptions:         opts.DriverOptions,
 		PersistLogs:           !opts.NoLogs,
+		LogFormat:             opts.LogFormat,
+		Retries:               opts.Retries,
+		RetryBackoff:          opts.RetryBackoff,
+		Timeout:               opts.Timeout,
+		Out:                   p.Out,
+		Err:                   p.Err,
+		Run:                   opts.run,
 	}
 
 	return args, nil
@@ -307,7 +431,11 @@ func (p *Porter) prepullBundleByReference(ctx context.Context, opts *BundleRefer
 		return cache.CachedBundle{}, err
 	}
 
-	opts.RelocationMapping = cachedBundle.RelocationFilePath
+	// Use the relocation mapping of the published bundle, unless one was specified with --relocation-mapping,
+	// which is then applied on top of the published relocation mapping
+	if opts.RelocationMapping == "" {
+		opts.RelocationMapping = cachedBundle.RelocationFilePath
+	}
 
 	if opts.Name == "" {
 		opts.Name = cachedBundle.Definition.Name
@@ -3,10 +3,12 @@ package porter
 import (
 	"context"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/cnab"
 	configadapter "get.porter.sh/porter/pkg/cnab/config-adapter"
+	cnabprovider "get.porter.sh/porter/pkg/cnab/provider"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/manifest"
 	"get.porter.sh/porter/pkg/storage"
@@ -246,6 +248,81 @@ func TestBundleExecutionOptions_defaultDriver(t *testing.T) {
 
 }
 
+func TestBundleExecutionOptions_validateLogFormat(t *testing.T) {
+	testcases := []struct {
+		logFormat string
+		wantError string
+	}{
+		{logFormat: ""},
+		{logFormat: "text"},
+		{logFormat: "json"},
+		{logFormat: "yaml", wantError: "invalid --log-format yaml, allowed values are: text, json"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.logFormat, func(t *testing.T) {
+			opts := NewBundleExecutionOptions()
+			opts.LogFormat = tc.logFormat
+
+			err := opts.validateLogFormat()
+			if tc.wantError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.wantError)
+			}
+		})
+	}
+}
+
+func TestBundleExecutionOptions_validateRetries(t *testing.T) {
+	opts := NewBundleExecutionOptions()
+	opts.Retries = 3
+	opts.RetryBackoff = 30 * time.Second
+	require.NoError(t, opts.validateRetries())
+
+	opts.Retries = -1
+	require.EqualError(t, opts.validateRetries(), "--retries must not be negative")
+
+	opts.Retries = 0
+	opts.RetryBackoff = -time.Second
+	require.EqualError(t, opts.validateRetries(), "--retry-backoff must not be negative")
+}
+
+func TestBundleExecutionOptions_validateTimeout(t *testing.T) {
+	opts := NewBundleExecutionOptions()
+	opts.Timeout = 10 * time.Minute
+	require.NoError(t, opts.validateTimeout())
+
+	opts.Timeout = -time.Second
+	require.EqualError(t, opts.validateTimeout(), "--timeout must not be negative")
+}
+
+func TestBundleExecutionOptions_validateDependencyParallelism(t *testing.T) {
+	opts := NewBundleExecutionOptions()
+	opts.DependencyParallelism = 4
+	require.NoError(t, opts.validateDependencyParallelism())
+
+	opts.DependencyParallelism = -1
+	require.EqualError(t, opts.validateDependencyParallelism(), "--dependency-parallelism must not be negative")
+}
+
+func TestBundleExecutionOptions_validateDriverOptions(t *testing.T) {
+	opts := NewBundleExecutionOptions()
+	opts.Driver = cnabprovider.DriverNameDocker
+	opts.DriverOptions = []string{"cpus=2", "mount=/tmp:/tmp:ro"}
+	require.NoError(t, opts.validateDriverOptions(config.DockerConfig{Memory: "4g"}))
+
+	err := opts.validateDriverOptions(config.DockerConfig{Network: "host", Env: []string{"A=B"}})
+	require.EqualError(t, err, "invalid --driver-opt env=A=B, specify the name of an environment variable to pass through to the container")
+
+	opts.Driver = cnabprovider.DriverNameKubernetes
+	require.EqualError(t, opts.validateDriverOptions(config.DockerConfig{}), "--driver-opt is only supported by the docker driver")
+
+	opts.DriverOptions = nil
+	require.NoError(t, opts.validateDriverOptions(config.DockerConfig{Memory: "lots"}), "the docker settings in the config file should be ignored by other drivers")
+}
+
 func TestBundleExecutionOptions_ParseParamSets(t *testing.T) {
 	p := NewTestPorter(t)
 	defer p.Close()
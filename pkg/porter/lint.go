@@ -3,6 +3,7 @@ package porter
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/linter"
@@ -16,10 +17,18 @@ type LintOptions struct {
 
 	// File path to the porter manifest. Defaults to the bundle in the current directory.
 	File string
+
+	// Config is the path to the lint configuration file.
+	// Defaults to porter-lint.yaml in the same directory as the porter manifest, when present.
+	Config string
+
+	// FailOn is the lowest level of a result that causes lint to fail: error, warning, info or none.
+	// Defaults to the failOn setting in the lint configuration file.
+	FailOn string
 }
 
 var (
-	LintAllowFormats   = printer.Formats{printer.FormatPlaintext, printer.FormatJson}
+	LintAllowFormats   = printer.Formats{printer.FormatPlaintext, printer.FormatJson, printer.FormatSarif}
 	LintDefaultFormats = printer.FormatPlaintext
 )
 
@@ -29,7 +38,38 @@ func (o *LintOptions) Validate(cxt *portercontext.Context) error {
 		return err
 	}
 
-	return o.validateFile(cxt)
+	if o.FailOn != "" && o.FailOn != linter.FailOnNone {
+		if _, err = linter.ParseLevel(o.FailOn); err != nil {
+			return fmt.Errorf("invalid --fail-on %s, allowed values are: error, warning, info, none", o.FailOn)
+		}
+	}
+
+	if err = o.validateFile(cxt); err != nil {
+		return err
+	}
+
+	return o.validateConfig(cxt)
+}
+
+func (o *LintOptions) validateConfig(cxt *portercontext.Context) error {
+	if o.Config != "" {
+		if _, err := cxt.FileSystem.Stat(o.Config); err != nil {
+			return fmt.Errorf("unable to access --config %s: %w", o.Config, err)
+		}
+		return nil
+	}
+
+	// Use the lint configuration file next to the manifest, when it exists
+	defaultConfig := filepath.Join(filepath.Dir(o.File), linter.ConfigFileName)
+	configExists, err := cxt.FileSystem.Exists(defaultConfig)
+	if err != nil {
+		return fmt.Errorf("could not check if the lint configuration file %s exists: %w", defaultConfig, err)
+	}
+	if configExists {
+		o.Config = defaultConfig
+	}
+
+	return nil
 }
 
 func (o *LintOptions) validateFile(cxt *portercontext.Context) error {
@@ -55,35 +95,65 @@ func (o *LintOptions) validateFile(cxt *portercontext.Context) error {
 // Lint porter.yaml for any problems and report the results.
 // This calls the mixins to analyze their sections of the manifest.
 func (p *Porter) Lint(ctx context.Context, opts LintOptions) (linter.Results, error) {
+	_, results, err := p.lint(ctx, opts)
+	return results, err
+}
+
+// lint the manifest with the lint configuration, returning the linter so
+// that the rules that were run can be described.
+func (p *Porter) lint(ctx context.Context, opts LintOptions) (*linter.Linter, linter.Results, error) {
 	manifest, err := manifest.LoadManifestFrom(ctx, p.Config, opts.File)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	l := linter.New(p.Context, p.Mixins)
-	return l.Lint(ctx, manifest)
+	if opts.Config != "" {
+		l.Config, err = linter.LoadConfig(p.FileSystem, opts.Config)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	results, err := l.Lint(ctx, manifest)
+	return l, results, err
 }
 
 // PrintLintResults lints the manifest and prints the results to the attached output.
+// Returns an error when a result is at or above the fail on level.
 func (p *Porter) PrintLintResults(ctx context.Context, opts LintOptions) error {
-	results, err := p.Lint(ctx, opts)
+	l, results, err := p.lint(ctx, opts)
 	if err != nil {
 		return err
 	}
 
-	if results.String() != "" {
-		switch opts.Format {
-		case printer.FormatPlaintext:
+	switch opts.Format {
+	case printer.FormatPlaintext:
+		if results.String() != "" {
 			fmt.Fprintln(p.Out, results.String())
-		case printer.FormatJson:
+		}
+	case printer.FormatJson:
+		if results.String() != "" {
 			printer.PrintJson(p.Out, results)
-		default:
-			return fmt.Errorf("invalid format: %s", opts.Format)
+		}
+	case printer.FormatSarif:
+		printer.PrintJson(p.Out, linter.NewSarifLog(results, l.Rules, filepath.ToSlash(opts.File)))
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+
+	failOn := l.Config.FailOn
+	if opts.FailOn != "" {
+		failOn = opts.FailOn
+	}
+	if failLevel, ok := linter.ParseFailOn(failOn); ok {
+		if count := results.CountAtLeast(failLevel); count > 0 {
+			return fmt.Errorf("lint found %d results with a level of %s or higher", count, failLevel)
 		}
 	}
 
 	if !results.HasError() && opts.Format == printer.FormatPlaintext {
-		fmt.Fprintln(p.Out, "✨ Bundle validation was successful!")
+		p.printInfof("✨ Bundle validation was successful!\n")
 	}
 
 	return nil
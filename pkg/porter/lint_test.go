@@ -2,6 +2,7 @@ package porter
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -182,3 +183,136 @@ exec:
 		})
 	}
 }
+
+func TestPorter_PrintLintResults_Sarif(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+
+	mixins := p.Mixins.(*mixin.TestMixinProvider)
+	mixins.LintResults = linter.Results{
+		{
+			Level:    linter.LevelWarning,
+			Location: linter.Location{Action: "install", Mixin: "exec", StepNumber: 2, StepDescription: "Install Hello World"},
+			Code:     "exec-100",
+			Title:    "bash -c argument missing wrapping quotes",
+			URL:      "https://getporter.org/best-practices/exec-mixin/#quoting-escaping-bash-and-yaml",
+		},
+	}
+
+	var opts LintOptions
+	opts.RawFormat = "sarif"
+	require.NoError(t, opts.Validate(p.Context), "Validate failed")
+
+	err := p.PrintLintResults(context.Background(), opts)
+	require.NoError(t, err, "PrintLintResults failed")
+
+	var log linter.SarifLog
+	require.NoError(t, json.Unmarshal([]byte(p.TestConfig.TestContext.GetOutput()), &log), "the output should be a sarif log")
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Results, 1)
+	assert.Equal(t, "exec-100", log.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "warning", log.Runs[0].Results[0].Level)
+	assert.Equal(t, "porter.yaml", log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestPorter_PrintLintResults_FailOn(t *testing.T) {
+	warning := linter.Result{
+		Level: linter.LevelWarning,
+		Code:  "exec-101",
+		Title: "warning stuff isn't working",
+	}
+
+	testcases := []struct {
+		name       string
+		failOn     string
+		configFile string
+		wantErr    string
+	}{
+		{name: "default", failOn: ""},
+		{name: "below threshold", failOn: "error"},
+		{name: "at threshold", failOn: "warning", wantErr: "lint found 1 results with a level of warning or higher"},
+		{name: "config file", configFile: "testdata/lint/porter-lint.yaml", wantErr: "lint found 1 results with a level of warning or higher"},
+		{name: "flag overrides config file", failOn: "none", configFile: "testdata/lint/porter-lint.yaml"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTestPorter(t)
+			defer p.Close()
+
+			p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+			if tc.configFile != "" {
+				p.TestConfig.TestContext.AddTestFile(tc.configFile, linter.ConfigFileName)
+			}
+
+			mixins := p.Mixins.(*mixin.TestMixinProvider)
+			mixins.LintResults = linter.Results{warning}
+
+			opts := LintOptions{FailOn: tc.failOn}
+			require.NoError(t, opts.Validate(p.Context), "Validate failed")
+
+			err := p.PrintLintResults(context.Background(), opts)
+			if tc.wantErr == "" {
+				require.NoError(t, err, "PrintLintResults failed")
+			} else {
+				tests.RequireErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPorter_Lint_ConfigFile(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+	p.TestConfig.TestContext.AddTestFile("testdata/lint/porter-lint.yaml", "lint/custom.yaml")
+
+	mixins := p.Mixins.(*mixin.TestMixinProvider)
+	mixins.LintResults = linter.Results{
+		{Level: linter.LevelError, Code: "exec-100"},
+		{Level: linter.LevelWarning, Code: "exec-101"},
+	}
+
+	opts := LintOptions{Config: "lint/custom.yaml"}
+	require.NoError(t, opts.Validate(p.Context), "Validate failed")
+
+	results, err := p.Lint(context.Background(), opts)
+	require.NoError(t, err, "Lint failed")
+	require.Len(t, results, 1, "the disabled rule should be removed from the results")
+	assert.Equal(t, linter.Code("exec-101"), results[0].Code)
+}
+
+func TestLintOptions_Validate(t *testing.T) {
+	t.Run("invalid fail-on", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+
+		opts := LintOptions{FailOn: "sometimes"}
+		err := opts.Validate(p.Context)
+		tests.RequireErrorContains(t, err, "invalid --fail-on sometimes")
+	})
+
+	t.Run("missing config", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+
+		opts := LintOptions{Config: "missing.yaml"}
+		err := opts.Validate(p.Context)
+		tests.RequireErrorContains(t, err, "unable to access --config missing.yaml")
+	})
+
+	t.Run("default config", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+		p.TestConfig.TestContext.AddTestFile("testdata/lint/porter-lint.yaml", linter.ConfigFileName)
+
+		var opts LintOptions
+		require.NoError(t, opts.Validate(p.Context))
+		assert.Equal(t, linter.ConfigFileName, opts.Config)
+	})
+}
@@ -2,6 +2,7 @@ package porter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -24,21 +25,119 @@ const (
 	StatusInstalling   = "installing"
 	StatusUninstalling = "uninstalling"
 	StatusUpgrading    = "upgrading"
+	StatusPaused       = "paused"
+)
+
+var (
+	// ListAllowedFormats are the output formats supported by Porter's list commands.
+	ListAllowedFormats = []printer.Format{printer.FormatPlaintext, printer.FormatWide, printer.FormatCsv, printer.FormatJson, printer.FormatYaml}
+
+	// ListDefaultFormat is the default output format of Porter's list commands.
+	ListDefaultFormat = printer.FormatPlaintext
 )
 
 // ListOptions represent generic options for use by Porter's list commands
 type ListOptions struct {
 	printer.PrintOptions
+	printer.ColumnOptions
 	AllNamespaces bool
 	Namespace     string
 	Name          string
 	Labels        []string
+	Annotations   []string
 	Skip          int64
 	Limit         int64
+
+	// Page of results to return, starting at 1, when Limit is set.
+	Page int64
+
+	// Continue is the token printed with the previous page of results.
+	Continue string
 }
 
 func (o *ListOptions) Validate() error {
-	return o.ParseFormat()
+	if err := o.PrintOptions.Validate(ListDefaultFormat, ListAllowedFormats); err != nil {
+		return err
+	}
+	if err := o.validatePagination(); err != nil {
+		return err
+	}
+	return validateColumnOptions(o.PrintOptions, o.ColumnOptions)
+}
+
+func (o ListOptions) validatePagination() error {
+	if o.Page < 0 {
+		return fmt.Errorf("invalid --page %d, the page cannot be negative", o.Page)
+	}
+	if o.Page > 0 && o.Limit <= 0 {
+		return errors.New("--limit is required when --page is specified")
+	}
+	if o.Page > 0 && o.Continue != "" {
+		return errors.New("--page and --continue cannot be used together")
+	}
+	if o.Continue != "" {
+		if _, err := storage.ParseContinueToken(o.Continue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toStorageListOptions builds the query for the requested page of results,
+// so that filtering and pagination is done by the storage plugin instead of
+// loading every document into memory.
+func (o ListOptions) toStorageListOptions() (storage.ListOptions, error) {
+	query := storage.ListOptions{
+		Namespace: o.GetNamespace(),
+		Name:      o.Name,
+		Labels:    o.ParseLabels(),
+		Skip:      o.Skip,
+		Limit:     o.Limit,
+
+		BundleAnnotations: o.ParseAnnotations(),
+	}
+
+	if o.Page > 1 {
+		query.Skip += (o.Page - 1) * o.Limit
+	}
+
+	if o.Continue != "" {
+		token, err := storage.ParseContinueToken(o.Continue)
+		if err != nil {
+			return storage.ListOptions{}, err
+		}
+		query.Continue = &token
+	}
+
+	return query, nil
+}
+
+// printContinueToken prints how to request the next page of results, when
+// the page is full and more results may be available. The hint is printed to
+// stderr so that it does not change the json, yaml or csv output.
+func (p *Porter) printContinueToken(opts ListOptions, page []storage.ContinueToken) {
+	if opts.Limit <= 0 || int64(len(page)) < opts.Limit {
+		return
+	}
+
+	// The results are stored sorted by namespace and then name, so the next
+	// page starts after the last document in that order
+	last := page[0]
+	for _, token := range page[1:] {
+		if token.Namespace > last.Namespace || (token.Namespace == last.Namespace && token.Name > last.Name) {
+			last = token
+		}
+	}
+	fmt.Fprintf(p.Err, "More results may be available, request the next page with --continue %s\n", last)
+}
+
+// validateColumnOptions checks that columns are only selected for the
+// tabular output formats. Sorting is supported by every format.
+func validateColumnOptions(printOpts printer.PrintOptions, columnOpts printer.ColumnOptions) error {
+	if columnOpts.HasColumns() && (printOpts.Format == printer.FormatJson || printOpts.Format == printer.FormatYaml) {
+		return fmt.Errorf("--columns is not supported by the %s format, only by plaintext, wide and csv", printOpts.Format)
+	}
+	return nil
 }
 
 func (o ListOptions) GetNamespace() string {
@@ -52,6 +151,11 @@ func (o ListOptions) ParseLabels() map[string]string {
 	return parseLabels(o.Labels)
 }
 
+// ParseAnnotations parses the bundle annotations used to filter the installations.
+func (o ListOptions) ParseAnnotations() map[string]string {
+	return parseLabels(o.Annotations)
+}
+
 func parseLabels(raw []string) map[string]string {
 	if len(raw) == 0 {
 		return nil
@@ -108,6 +212,9 @@ type DisplayInstallation struct {
 	// ParameterSets that should be included when the bundle is reconciled.
 	ParameterSets []string `json:"parameterSets,omitempty" yaml:"parameterSets,omitempty" toml:"parameterSets,omitempty"`
 
+	// Schedule runs an action against the installation on a recurring basis.
+	Schedule *storage.InstallationSchedule `json:"schedule,omitempty" yaml:"schedule,omitempty" toml:"schedule,omitempty"`
+
 	// Status of the installation.
 	Status                      storage.InstallationStatus `json:"status,omitempty" yaml:"status,omitempty" toml:"status,omitempty"`
 	DisplayInstallationMetadata `json:"_calculated" yaml:"_calculated"`
@@ -120,8 +227,15 @@ type DisplayInstallationMetadata struct {
 	// It is either "installed", "uninstalled", or "defined".
 	DisplayInstallationState string `json:"displayInstallationState,omitempty" yaml:"displayInstallationState,omitempty" toml:"displayInstallationState,omitempty"`
 	// DisplayInstallationStatus is the latest status of the installation.
-	// It is either "succeeded, "failed", "installing", "uninstalling", "upgrading", or "running <custom action>"
+	// It is either "succeeded, "failed", "installing", "uninstalling", "upgrading", "paused", or "running <custom action>"
 	DisplayInstallationStatus string `json:"displayInstallationStatus,omitempty" yaml:"displayInstallationStatus,omitempty" toml:"displayInstallationStatus,omitempty"`
+
+	// LastRun summarizes the most recent run of the installation.
+	LastRun *DisplayRunSummary `json:"lastRun,omitempty" yaml:"lastRun,omitempty" toml:"lastRun,omitempty"`
+
+	// Runs is the recent run history of the installation, most recent first.
+	// Only populated when requested with porter installation show --runs.
+	Runs DisplayRuns `json:"runs,omitempty" yaml:"runs,omitempty" toml:"runs,omitempty"`
 }
 
 func NewDisplayInstallation(installation storage.Installation) DisplayInstallation {
@@ -138,6 +252,7 @@ func NewDisplayInstallation(installation storage.Installation) DisplayInstallati
 		Labels:         installation.Labels,
 		CredentialSets: installation.CredentialSets,
 		ParameterSets:  installation.ParameterSets,
+		Schedule:       installation.Schedule,
 		Status:         installation.Status,
 		DisplayInstallationMetadata: DisplayInstallationMetadata{
 			DisplayInstallationState:  getDisplayInstallationState(installation),
@@ -163,6 +278,7 @@ func (d DisplayInstallation) ConvertToInstallation() (storage.Installation, erro
 			Labels:         d.Labels,
 			CredentialSets: d.CredentialSets,
 			ParameterSets:  d.ParameterSets,
+			Schedule:       d.Schedule,
 		},
 		Status: d.Status,
 	}
@@ -239,13 +355,12 @@ func (p *Porter) ListInstallations(ctx context.Context, opts ListOptions) (Displ
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
 
-	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{
-		Namespace: opts.GetNamespace(),
-		Name:      opts.Name,
-		Labels:    opts.ParseLabels(),
-		Skip:      opts.Skip,
-		Limit:     opts.Limit,
-	})
+	query, err := opts.toStorageListOptions()
+	if err != nil {
+		return nil, log.Error(err)
+	}
+
+	installations, err := p.Installations.ListInstallations(ctx, query)
 	if err != nil {
 		return nil, log.Error(fmt.Errorf("could not list installations: %w", err))
 	}
@@ -267,31 +382,104 @@ func (p *Porter) PrintInstallations(ctx context.Context, opts ListOptions) error
 		return err
 	}
 
+	return p.printDisplayInstallations(displayInstallations, opts)
+}
+
+// printDisplayInstallations prints the installations in the format requested by the list options.
+func (p *Porter) printDisplayInstallations(displayInstallations DisplayInstallations, opts ListOptions) error {
+	// Sort by the csv values so that timestamps are compared, instead of relative times
+	csvRow :=
+		func(v interface{}) []string {
+			cl, ok := v.(DisplayInstallation)
+			if !ok {
+				return nil
+			}
+			return getDisplayInstallationRow(cl, formatCsvTime, true)
+		}
+	if err := opts.SortRows(displayInstallations, csvRow, getDisplayInstallationHeaders(true)...); err != nil {
+		return err
+	}
+
+	page := make([]storage.ContinueToken, len(displayInstallations))
+	for i, di := range displayInstallations {
+		page[i] = storage.NewContinueToken(di.Namespace, di.Name)
+	}
+
+	var err error
 	switch opts.Format {
 	case printer.FormatJson:
-		return printer.PrintJson(p.Out, displayInstallations)
+		err = printer.PrintJson(p.Out, displayInstallations)
 	case printer.FormatYaml:
-		return printer.PrintYaml(p.Out, displayInstallations)
-	case printer.FormatPlaintext:
+		err = printer.PrintYaml(p.Out, displayInstallations)
+	case printer.FormatPlaintext, printer.FormatWide:
 		// have every row use the same "now" starting ... NOW!
 		now := time.Now()
 		tp := dtprinter.DateTimePrinter{
 			Now: func() time.Time { return now },
 		}
 
+		// Selected columns may include those only printed in the wide output
+		wide := opts.Format == printer.FormatWide || opts.HasColumns()
+		ui := p.newUI(p.Out)
 		row :=
 			func(v interface{}) []string {
 				cl, ok := v.(DisplayInstallation)
 				if !ok {
 					return nil
 				}
-				return []string{cl.Namespace, cl.Name, cl.Status.BundleVersion, cl.DisplayInstallationState, cl.DisplayInstallationStatus, tp.Format(cl.Status.Modified)}
+				cl.DisplayInstallationStatus = ui.FormatStatus(cl.DisplayInstallationStatus)
+				return getDisplayInstallationRow(cl, tp.Format, wide)
 			}
-		return printer.PrintTable(p.Out, displayInstallations, row,
-			"NAMESPACE", "NAME", "VERSION", "STATE", "STATUS", "MODIFIED")
+		err = opts.PrintTable(p.Out, displayInstallations, row, getDisplayInstallationHeaders(wide)...)
+	case printer.FormatCsv:
+		err = opts.PrintCsv(p.Out, displayInstallations, csvRow, getDisplayInstallationHeaders(true)...)
 	default:
 		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
+	if err != nil {
+		return err
+	}
+
+	p.printContinueToken(opts, page)
+	return nil
+}
+
+// getDisplayInstallationHeaders returns the column headers used when listing installations.
+// The wide output adds the bundle reference and labels of the installation.
+func getDisplayInstallationHeaders(wide bool) []string {
+	headers := []string{"NAMESPACE", "NAME", "VERSION", "STATE", "STATUS", "MODIFIED"}
+	if wide {
+		headers = append(headers, "BUNDLE", "LABELS")
+	}
+	return headers
+}
+
+func getDisplayInstallationRow(cl DisplayInstallation, formatTime func(time.Time) string, wide bool) []string {
+	row := []string{cl.Namespace, cl.Name, cl.Status.BundleVersion, cl.DisplayInstallationState, cl.DisplayInstallationStatus, formatTime(cl.Status.Modified)}
+	if wide {
+		row = append(row, cl.Status.BundleReference, formatLabels(cl.Labels))
+	}
+	return row
+}
+
+// formatLabels prints labels as a comma separated list of KEY=VALUE, sorted by key.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// formatCsvTime prints a timestamp in csv output. Unlike the table output,
+// the timestamp is not relative to the current time so that it can be
+// imported into other tools.
+func formatCsvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
 }
 
 func getDisplayInstallationState(installation storage.Installation) string {
@@ -305,6 +493,10 @@ func getDisplayInstallationState(installation storage.Installation) string {
 }
 
 func getDisplayInstallationStatus(installation storage.Installation) string {
+	if installation.Status.Suspended {
+		return StatusPaused
+	}
+
 	var status string
 
 	switch installation.Status.ResultStatus {
@@ -267,4 +267,191 @@ func TestPorter_getDisplayInstallationStatus(t *testing.T) {
 	installation.Status.Action = "customaction"
 	displayInstallationStatus = getDisplayInstallationStatus(installation)
 	require.Equal(t, "running customaction", displayInstallationStatus)
+
+	installation.Status.Suspended = true
+	displayInstallationStatus = getDisplayInstallationStatus(installation)
+	require.Equal(t, StatusPaused, displayInstallationStatus)
+}
+
+func TestListOptions_Validate_Formats(t *testing.T) {
+	for _, format := range []string{"", "plaintext", "wide", "csv", "json", "yaml"} {
+		opts := ListOptions{PrintOptions: printer.PrintOptions{RawFormat: format}}
+		assert.NoError(t, opts.Validate(), "format %q should be supported", format)
+	}
+
+	opts := ListOptions{PrintOptions: printer.PrintOptions{RawFormat: "dot"}}
+	assert.EqualError(t, opts.Validate(), "invalid format: dot")
+}
+
+func TestPorter_printDisplayInstallations_Csv(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	inst := storage.NewInstallation("dev", "mywordpress")
+	inst.Labels = map[string]string{"team": "platform", "env": "dev"}
+	inst.Status.BundleReference = "example.com/wordpress:v1.2.3"
+	inst.Status.BundleVersion = "v1.2.3"
+	inst.Status.Modified = now
+	di := NewDisplayInstallation(inst)
+
+	opts := ListOptions{PrintOptions: printer.PrintOptions{Format: printer.FormatCsv}}
+	err := p.printDisplayInstallations(DisplayInstallations{di}, opts)
+	require.NoError(t, err)
+
+	want := `NAMESPACE,NAME,VERSION,STATE,STATUS,MODIFIED,BUNDLE,LABELS
+dev,mywordpress,v1.2.3,defined,,2020-04-18T01:02:03Z,example.com/wordpress:v1.2.3,"env=dev,team=platform"
+`
+	assert.Equal(t, want, p.TestConfig.TestContext.GetOutput())
+}
+
+func TestGetDisplayInstallationRow(t *testing.T) {
+	di := DisplayInstallation{
+		Namespace: "dev",
+		Name:      "mywordpress",
+		Labels:    map[string]string{"team": "platform"},
+		Status: storage.InstallationStatus{
+			BundleReference: "example.com/wordpress:v1.2.3",
+			BundleVersion:   "v1.2.3",
+			Modified:        now,
+		},
+	}
+	di.DisplayInstallationState = StateInstalled
+	di.DisplayInstallationStatus = cnab.StatusSucceeded
+
+	formatTime := func(time.Time) string { return "now" }
+
+	assert.Equal(t, []string{"dev", "mywordpress", "v1.2.3", "installed", "succeeded", "now"},
+		getDisplayInstallationRow(di, formatTime, false))
+	assert.Equal(t, []string{"dev", "mywordpress", "v1.2.3", "installed", "succeeded", "now", "example.com/wordpress:v1.2.3", "team=platform"},
+		getDisplayInstallationRow(di, formatTime, true))
+	assert.Len(t, getDisplayInstallationHeaders(true), 8)
+}
+
+func TestListOptions_Validate_Columns(t *testing.T) {
+	opts := ListOptions{PrintOptions: printer.PrintOptions{RawFormat: "csv"}}
+	opts.Columns = []string{"name", "modified"}
+	require.NoError(t, opts.Validate())
+
+	opts = ListOptions{PrintOptions: printer.PrintOptions{RawFormat: "json"}}
+	opts.SortBy = "modified"
+	require.NoError(t, opts.Validate(), "sorting should be supported by every format")
+
+	opts.Columns = []string{"name"}
+	assert.EqualError(t, opts.Validate(), "--columns is not supported by the json format, only by plaintext, wide and csv")
+}
+
+func TestPorter_printDisplayInstallations_ColumnsAndSort(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	older := storage.NewInstallation("dev", "mysql")
+	older.Labels = map[string]string{"team": "data"}
+	older.Status.Modified = now
+	newer := storage.NewInstallation("dev", "mywordpress")
+	newer.Labels = map[string]string{"team": "platform"}
+	newer.Status.Modified = now.Add(time.Hour)
+
+	opts := ListOptions{PrintOptions: printer.PrintOptions{Format: printer.FormatCsv}}
+	opts.Columns = []string{"name", "labels", "modified"}
+	opts.SortBy = "modified"
+	installations := DisplayInstallations{NewDisplayInstallation(newer), NewDisplayInstallation(older)}
+	err := p.printDisplayInstallations(installations, opts)
+	require.NoError(t, err)
+
+	want := `NAME,LABELS,MODIFIED
+mysql,team=data,2020-04-18T01:02:03Z
+mywordpress,team=platform,2020-04-18T02:02:03Z
+`
+	assert.Equal(t, want, p.TestConfig.TestContext.GetOutput())
+}
+
+func TestListOptions_Validate_Pagination(t *testing.T) {
+	testcases := []struct {
+		name    string
+		opts    ListOptions
+		wantErr string
+	}{
+		{name: "page with limit", opts: ListOptions{Page: 2, Limit: 10}},
+		{name: "continue", opts: ListOptions{Continue: storage.NewContinueToken("dev", "mysql").String(), Limit: 10}},
+		{name: "negative page", opts: ListOptions{Page: -1, Limit: 10}, wantErr: "invalid --page -1, the page cannot be negative"},
+		{name: "page without limit", opts: ListOptions{Page: 2}, wantErr: "--limit is required when --page is specified"},
+		{name: "page and continue", opts: ListOptions{Page: 2, Limit: 10, Continue: "abc"}, wantErr: "--page and --continue cannot be used together"},
+		{name: "invalid continue", opts: ListOptions{Continue: "abc"}, wantErr: `invalid continue token "abc"`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestListOptions_toStorageListOptions(t *testing.T) {
+	t.Run("page", func(t *testing.T) {
+		opts := ListOptions{Namespace: "dev", Skip: 5, Limit: 10, Page: 3}
+		query, err := opts.toStorageListOptions()
+		require.NoError(t, err)
+		assert.Equal(t, int64(25), query.Skip, "the earlier pages should be skipped after the initial skip")
+		assert.Equal(t, int64(10), query.Limit)
+		assert.Nil(t, query.Continue)
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		token := storage.NewContinueToken("dev", "mysql")
+		opts := ListOptions{Namespace: "dev", Limit: 10, Continue: token.String()}
+		query, err := opts.toStorageListOptions()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), query.Skip)
+		assert.Equal(t, &token, query.Continue)
+	})
+}
+
+func TestPorter_printContinueToken(t *testing.T) {
+	page := []storage.ContinueToken{
+		storage.NewContinueToken("dev", "wordpress"),
+		storage.NewContinueToken("prod", "aks"),
+		storage.NewContinueToken("dev", "mysql"),
+	}
+
+	t.Run("full page", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		p.printContinueToken(ListOptions{Limit: 3}, page)
+		want := "More results may be available, request the next page with --continue " + storage.NewContinueToken("prod", "aks").String() + "\n"
+		assert.Equal(t, want, p.TestConfig.TestContext.GetError())
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		p.printContinueToken(ListOptions{Limit: 10}, page)
+		assert.Empty(t, p.TestConfig.TestContext.GetError())
+	})
+
+	t.Run("printed after the results", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		di := NewDisplayInstallation(storage.NewInstallation("dev", "wordpress"))
+		opts := ListOptions{PrintOptions: printer.PrintOptions{Format: printer.FormatJson}, Limit: 1}
+		require.NoError(t, p.printDisplayInstallations(DisplayInstallations{di}, opts))
+		assert.Contains(t, p.TestConfig.TestContext.GetError(), "request the next page with --continue")
+	})
+
+	t.Run("not printed when printing fails", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		di := NewDisplayInstallation(storage.NewInstallation("dev", "wordpress"))
+		opts := ListOptions{PrintOptions: printer.PrintOptions{Format: "ini"}, Limit: 1}
+		require.ErrorContains(t, p.printDisplayInstallations(DisplayInstallations{di}, opts), "invalid format: ini")
+		assert.Empty(t, p.TestConfig.TestContext.GetError())
+	})
 }
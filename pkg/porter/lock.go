@@ -0,0 +1,111 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// installationLockLease is how long a lock on an installation is held
+	// before it expires, unless it is refreshed by the porter process that holds it.
+	installationLockLease = time.Minute
+
+	// installationLockPollInterval is how often to check if a lock was released
+	// when waiting for a lock.
+	installationLockPollInterval = 2 * time.Second
+)
+
+// lockInstallation acquires an advisory lock on the installation, so that
+// concurrent bundle actions cannot interleave. When the installation is locked
+// by another process, wait for up to waitForLock for the lock to be released.
+// The lock is refreshed in the background until the returned unlock function
+// is called.
+func (p *Porter) lockInstallation(ctx context.Context, namespace string, name string, action string, waitForLock time.Duration) (func(), error) {
+	lock, err := p.acquireInstallationLock(ctx, namespace, name, action, waitForLock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the lock from expiring while the bundle runs
+	log := tracing.LoggerFromContext(ctx)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(installationLockLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				refreshed := lock
+				refreshed.Expires = time.Now().Add(installationLockLease)
+				if err := p.Installations.RefreshInstallationLock(ctx, refreshed); err != nil {
+					log.Warnf("Could not refresh the lock on installation %s: %s", lock.ID, err)
+					continue
+				}
+				lock = refreshed
+			}
+		}
+	}()
+
+	unlock := func() {
+		close(done)
+		<-stopped
+
+		// Release the lock even when the action was cancelled
+		if err := p.Installations.ReleaseInstallationLock(context.Background(), lock); err != nil {
+			log.Warnf("Could not release the lock on installation %s: %s", lock.ID, err)
+		}
+	}
+	return unlock, nil
+}
+
+// acquireInstallationLock tries to lock the installation until the lock is
+// acquired, or waitForLock has elapsed.
+func (p *Porter) acquireInstallationLock(ctx context.Context, namespace string, name string, action string, waitForLock time.Duration) (storage.InstallationLock, error) {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	lock := storage.NewInstallationLock(namespace, name, action, installationLockLease)
+	deadline := time.Now().Add(waitForLock)
+	waiting := false
+	for {
+		err := p.Installations.AcquireInstallationLock(ctx, lock)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, storage.ErrInstallationLocked{}) {
+			return storage.InstallationLock{}, log.Error(err)
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if waitForLock > 0 {
+				return storage.InstallationLock{}, log.Error(fmt.Errorf("timed out after %s waiting for the lock: %w", waitForLock, err))
+			}
+			return storage.InstallationLock{}, log.Error(fmt.Errorf("%w. Use --wait-for-lock to wait for it to complete", err))
+		}
+
+		if !waiting {
+			log.Infof("Waiting up to %s for the lock on installation %s: %s", waitForLock, lock.ID, err)
+			waiting = true
+		}
+
+		wait := installationLockPollInterval
+		if remaining < wait {
+			wait = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return storage.InstallationLock{}, log.Error(ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package porter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_lockInstallation(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := context.Background()
+
+	unlock, err := p.lockInstallation(ctx, "dev", "mysql", cnab.ActionUpgrade, 0)
+	require.NoError(t, err, "lockInstallation failed")
+
+	t.Run("fail fast", func(t *testing.T) {
+		_, err := p.lockInstallation(ctx, "dev", "mysql", cnab.ActionUpgrade, 0)
+		require.ErrorIs(t, err, storage.ErrInstallationLocked{})
+		assert.Contains(t, err.Error(), "Use --wait-for-lock")
+	})
+
+	t.Run("wait times out", func(t *testing.T) {
+		_, err := p.lockInstallation(ctx, "dev", "mysql", cnab.ActionUpgrade, 10*time.Millisecond)
+		require.ErrorIs(t, err, storage.ErrInstallationLocked{})
+		assert.Contains(t, err.Error(), "timed out after 10ms waiting for the lock")
+	})
+
+	t.Run("wait for release", func(t *testing.T) {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			unlock()
+		}()
+
+		unlockAgain, err := p.lockInstallation(ctx, "dev", "mysql", cnab.ActionUpgrade, 10*time.Second)
+		require.NoError(t, err, "the lock should be acquired once it is released")
+		unlockAgain()
+	})
+}
+
+func TestBundleExecutionOptions_Validate_WaitForLock(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := NewUpgradeOptions()
+	opts.Name = "mysql"
+	opts.WaitForLock = -time.Second
+	err := opts.Validate(context.Background(), []string{"mysql"}, p.Porter)
+	require.EqualError(t, err, "--wait-for-lock must not be negative")
+}
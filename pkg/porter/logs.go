@@ -4,14 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
 )
 
+// logsFollowPollInterval is how often to check for new logs when following
+// the logs of a running bundle.
+const logsFollowPollInterval = time.Second
+
 // LogsShowOptions represent options for an installation logs show command
 type LogsShowOptions struct {
 	installationOptions
 	RunID string
+
+	// Follow the logs of the run until it completes.
+	Follow bool
 }
 
 // Installation name passed to the command.
@@ -41,6 +51,10 @@ func (o *LogsShowOptions) Validate(cxt *portercontext.Context) error {
 
 // ShowInstallationLogs shows logs for an installation, according to the provided options.
 func (p *Porter) ShowInstallationLogs(ctx context.Context, opts *LogsShowOptions) error {
+	if opts.Follow {
+		return p.FollowInstallationLogs(ctx, opts)
+	}
+
 	logs, ok, err := p.GetInstallationLogs(ctx, opts)
 	if err != nil {
 		return err
@@ -68,3 +82,83 @@ func (p *Porter) GetInstallationLogs(ctx context.Context, opts *LogsShowOptions)
 
 	return p.Installations.GetLastLogs(ctx, opts.Namespace, installation)
 }
+
+// FollowInstallationLogs prints the logs of a run as they are saved by the
+// bundle, until the run completes. When --run is not specified, the most
+// recent run of the installation is followed.
+func (p *Porter) FollowInstallationLogs(ctx context.Context, opts *LogsShowOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	err := p.applyDefaultOptions(ctx, &opts.installationOptions)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	runID := opts.RunID
+	if runID == "" {
+		run, err := p.Installations.GetLastRun(ctx, opts.Namespace, opts.Name)
+		if err != nil {
+			return log.Error(fmt.Errorf("could not retrieve the last run of installation %s/%s: %w", opts.Namespace, opts.Name, err))
+		}
+		runID = run.ID
+	}
+
+	var sequence int
+	for {
+		// Check if the run completed before retrieving the logs, the remaining
+		// logs are saved before the final status of the run
+		completed, err := p.isRunCompleted(ctx, runID)
+		if err != nil {
+			return log.Error(err)
+		}
+
+		chunks, err := p.Installations.ListLogChunks(ctx, runID, sequence)
+		if err != nil {
+			return log.Error(fmt.Errorf("could not retrieve the logs for run %s: %w", runID, err))
+		}
+		for _, chunk := range chunks {
+			fmt.Fprint(p.Out, chunk.Data)
+			sequence = chunk.Sequence
+		}
+
+		if completed {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(logsFollowPollInterval):
+		}
+	}
+
+	if sequence > 0 {
+		return nil
+	}
+
+	// The logs of runs from older versions of porter were only saved when the run completed
+	logs, ok, err := p.Installations.GetLogs(ctx, runID)
+	if err != nil {
+		return log.Error(err)
+	}
+	if !ok {
+		return errors.New("no logs found")
+	}
+	fmt.Fprintln(p.Out, logs)
+	return nil
+}
+
+// isRunCompleted determines if the most recent result of a run has a final status.
+func (p *Porter) isRunCompleted(ctx context.Context, runID string) (bool, error) {
+	results, err := p.Installations.ListResults(ctx, runID)
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve the status of run %s: %w", runID, err)
+	}
+	if len(results) == 0 {
+		return false, nil
+	}
+
+	status := results[len(results)-1].Status
+	return status != cnab.StatusRunning && status != cnab.StatusPending, nil
+}
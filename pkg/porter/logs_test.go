@@ -3,6 +3,7 @@ package porter
 import (
 	"context"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/portercontext"
@@ -101,3 +102,49 @@ func TestPorter_ShowInstallationLogs(t *testing.T) {
 		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), testLogs)
 	})
 }
+
+func TestPorter_FollowInstallationLogs(t *testing.T) {
+	t.Run("running", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		ctx := context.Background()
+		i := p.TestInstallations.CreateInstallation(storage.NewInstallation("", "test"))
+		c := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+		p.TestInstallations.CreateResult(c.NewResult(cnab.StatusRunning))
+		require.NoError(t, p.Installations.InsertLogChunk(ctx, storage.LogChunk{RunID: c.ID, Installation: "test", Sequence: 1, Data: "installing\n"}))
+
+		// Complete the run after the first chunk of logs was printed
+		go func() {
+			time.Sleep(logsFollowPollInterval / 2)
+			_ = p.Installations.InsertLogChunk(ctx, storage.LogChunk{RunID: c.ID, Installation: "test", Sequence: 2, Data: "done\n"})
+			_ = p.Installations.InsertResult(ctx, c.NewResult(cnab.StatusSucceeded))
+		}()
+
+		opts := LogsShowOptions{Follow: true}
+		opts.Name = "test"
+		err := p.ShowInstallationLogs(ctx, &opts)
+		require.NoError(t, err, "ShowInstallationLogs failed")
+
+		assert.Equal(t, "installing\ndone\n", p.TestConfig.TestContext.GetOutput())
+	})
+
+	t.Run("completed before log streaming", func(t *testing.T) {
+		const testLogs = "some mighty fine logs"
+
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		i := p.TestInstallations.CreateInstallation(storage.NewInstallation("", "test"))
+		c := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+		r := p.TestInstallations.CreateResult(c.NewResult(cnab.StatusSucceeded))
+		p.TestInstallations.CreateOutput(r.NewOutput(cnab.OutputInvocationImageLogs, []byte(testLogs)))
+
+		opts := LogsShowOptions{Follow: true}
+		opts.RunID = c.ID
+		err := p.ShowInstallationLogs(context.Background(), &opts)
+		require.NoError(t, err, "ShowInstallationLogs failed")
+
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), testLogs)
+	})
+}
@@ -0,0 +1,98 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// ManifestValidateOptions are the options for validating a porter manifest.
+type ManifestValidateOptions struct {
+	printer.PrintOptions
+
+	// File path to the porter manifest. Defaults to the bundle in the current directory.
+	File string
+}
+
+// Validate the manifest validate options.
+func (o *ManifestValidateOptions) Validate(cxt *portercontext.Context) error {
+	err := o.PrintOptions.Validate(printer.FormatPlaintext, []printer.Format{printer.FormatPlaintext, printer.FormatJson})
+	if err != nil {
+		return err
+	}
+
+	if o.File == "" {
+		o.File = config.Name
+	}
+
+	if _, err := cxt.FileSystem.Stat(o.File); err != nil {
+		return fmt.Errorf("unable to access --file %s: %w", o.File, err)
+	}
+
+	return nil
+}
+
+// ValidateManifest checks the manifest against the manifest schema, including the schemas
+// of the mixins used by the bundle, and for problems that the schema does not detect.
+// The manifest is not built, so Docker is not required.
+func (p *Porter) ValidateManifest(ctx context.Context, opts ManifestValidateOptions) (manifest.ValidationErrors, error) {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	data, err := manifest.ReadManifestData(p.Config, opts.File)
+	if err != nil {
+		return nil, log.Error(err)
+	}
+
+	manifestSchema, err := p.GetManifestSchema(ctx)
+	if err != nil {
+		// Still check for the problems that the schema does not detect
+		log.Warnf("Skipping validation against the manifest schema because it could not be loaded: %s", err.Error())
+		manifestSchema = nil
+	}
+
+	results, err := manifest.ValidateManifestData(p.Context, data, manifestSchema)
+	return results, log.Error(err)
+}
+
+// PrintManifestValidationResults validates the manifest and prints any problems found.
+// Returns an error when the manifest is invalid.
+func (p *Porter) PrintManifestValidationResults(ctx context.Context, opts ManifestValidateOptions) error {
+	results, err := p.ValidateManifest(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatPlaintext:
+		for _, result := range results {
+			if result.Line > 0 {
+				fmt.Fprintf(p.Out, "%s:%s\n", opts.File, result.Error())
+			} else {
+				fmt.Fprintf(p.Out, "%s: %s\n", opts.File, result.Error())
+			}
+		}
+		if len(results) == 0 {
+			p.printInfof("✨ %s is valid\n", opts.File)
+		}
+	case printer.FormatJson:
+		if results == nil {
+			results = manifest.ValidationErrors{}
+		}
+		if err = printer.PrintJson(p.Out, results); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+
+	if len(results) > 0 {
+		return fmt.Errorf("%s is invalid, %d problems were found", opts.File, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestValidateOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := ManifestValidateOptions{}
+	err := opts.Validate(p.Context)
+	tests.RequireErrorContains(t, err, "unable to access --file porter.yaml")
+
+	p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+	opts = ManifestValidateOptions{}
+	require.NoError(t, opts.Validate(p.Context))
+	assert.Equal(t, "porter.yaml", opts.File)
+	assert.Equal(t, printer.FormatPlaintext, opts.Format)
+}
+
+func TestPorter_PrintManifestValidationResults(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFile("testdata/porter.yaml", "porter.yaml")
+
+		opts := ManifestValidateOptions{}
+		require.NoError(t, opts.Validate(p.Context))
+
+		err := p.PrintManifestValidationResults(p.RootContext, opts)
+		require.NoError(t, err)
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "✨ porter.yaml is valid")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFileFromRoot("pkg/manifest/testdata/validate/invalid.yaml", "porter.yaml")
+
+		opts := ManifestValidateOptions{}
+		require.NoError(t, opts.Validate(p.Context))
+
+		err := p.PrintManifestValidationResults(p.RootContext, opts)
+		tests.RequireErrorContains(t, err, "porter.yaml is invalid")
+
+		gotOutput := p.TestConfig.TestContext.GetOutput()
+		assert.Contains(t, gotOutput, "porter.yaml:20:14: the template bundle.parameters.log uses the log parameter, which is not declared in the parameters section")
+		assert.Contains(t, gotOutput, "porter.yaml:26:5: uninstall.0.helm3: the helm3 mixin is used by a step but is not declared in the mixins section")
+	})
+}
@@ -1,12 +1,9 @@
 package porter
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 
 	"get.porter.sh/porter/pkg/mixin"
@@ -14,10 +11,7 @@ import (
 	"get.porter.sh/porter/pkg/pkgmgmt/feed"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
-)
-
-const (
-	SkeletorRepo = "https://github.com/getporter/skeletor"
+	"get.porter.sh/porter/pkg/tracing"
 )
 
 // PrintMixinsOptions represent options for the PrintMixins function
@@ -51,6 +45,81 @@ func (p *Porter) PrintMixins(ctx context.Context, opts PrintMixinsOptions) error
 	}
 }
 
+// ShowMixinOptions represent options for showing a particular mixin.
+type ShowMixinOptions struct {
+	printer.PrintOptions
+	Name string
+}
+
+func (o *ShowMixinOptions) Validate(args []string) error {
+	var err error
+	o.Name, err = validatePackageName(args)
+	if err != nil {
+		return err
+	}
+
+	return o.ParseFormat()
+}
+
+// DisplayMixin is the details of an installed mixin printed by porter mixins show.
+type DisplayMixin struct {
+	mixin.Metadata      `yaml:",inline"`
+	pkgmgmt.InstallInfo `yaml:",inline"`
+
+	// ProtocolVersions are the versions of the mixin protocol supported by the mixin.
+	ProtocolVersions []int `json:"protocolVersions" yaml:"protocolVersions"`
+}
+
+func (p *Porter) ShowMixin(ctx context.Context, opts ShowMixinOptions) error {
+	m, err := p.GetMixinDetails(ctx, opts.Name)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatPlaintext:
+		printPackageDetails(p.Out, m.Metadata, m.InstallInfo)
+		fmt.Fprintf(p.Out, "Protocol Versions: %s\n", formatProtocolVersions(m.ProtocolVersions))
+		return nil
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, m)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, m)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// GetMixinDetails returns the metadata of an installed mixin, where it was
+// installed from, and the mixin protocol versions that it supports.
+func (p *Porter) GetMixinDetails(ctx context.Context, name string) (DisplayMixin, error) {
+	m, err := p.Mixins.GetMetadata(ctx, name)
+	if err != nil {
+		return DisplayMixin{}, err
+	}
+
+	meta, ok := m.(*mixin.Metadata)
+	if !ok {
+		return DisplayMixin{}, fmt.Errorf("could not cast mixin %s to mixin.Metadata", name)
+	}
+
+	info, err := p.Mixins.GetInstallInfo(name)
+	if err != nil {
+		return DisplayMixin{}, err
+	}
+
+	protocolVersions, err := p.Mixins.GetProtocolVersions(ctx, name)
+	if err != nil {
+		return DisplayMixin{}, err
+	}
+
+	return DisplayMixin{
+		Metadata:         *meta,
+		InstallInfo:      info,
+		ProtocolVersions: protocolVersions,
+	}, nil
+}
+
 func (p *Porter) ListMixins(ctx context.Context) ([]mixin.Metadata, error) {
 	// List out what is installed on the file system
 	names, err := p.Mixins.List()
@@ -63,7 +132,7 @@ func (p *Porter) ListMixins(ctx context.Context) ([]mixin.Metadata, error) {
 	for i, name := range names {
 		m, err := p.Mixins.GetMetadata(ctx, name)
 		if err != nil {
-			fmt.Fprintf(p.Err, "could not get version from mixin %s: %s\n ", name, err.Error())
+			tracing.LoggerFromContext(ctx).Warnf("Could not get version from mixin %s: %s", name, err.Error())
 			continue
 		}
 
@@ -86,7 +155,7 @@ func (p *Porter) InstallMixin(ctx context.Context, opts mixin.InstallOptions) er
 	}
 
 	v := mixin.GetVersionInfo()
-	fmt.Fprintf(p.Out, "installed %s mixin %s (%s)\n", opts.Name, v.Version, v.Commit)
+	p.printInfof("installed %s mixin %s (%s)\n", opts.Name, v.Version, v.Commit)
 
 	return nil
 }
@@ -97,7 +166,7 @@ func (p *Porter) UninstallMixin(ctx context.Context, opts pkgmgmt.UninstallOptio
 		return err
 	}
 
-	fmt.Fprintf(p.Out, "Uninstalled %s mixin", opts.Name)
+	p.printInfof("Uninstalled %s mixin", opts.Name)
 
 	return nil
 }
@@ -122,6 +191,7 @@ type MixinsCreateOptions struct {
 	MixinName      string
 	AuthorName     string
 	AuthorUsername string
+	ModulePath     string
 	DirPath        string
 }
 
@@ -136,80 +206,19 @@ func (o *MixinsCreateOptions) Validate(args []string, cxt *portercontext.Context
 
 	o.MixinName = args[0]
 
-	if o.AuthorName == "" {
-		return errors.New("must provide a value for flag --author")
-	}
-
-	if o.AuthorUsername == "" {
-		return errors.New("must provide a value for flag --username")
-	}
-
-	if o.DirPath == "" {
-		o.DirPath = cxt.Getwd()
-	}
-
-	if _, err := cxt.FileSystem.Stat(o.DirPath); err != nil {
-		return fmt.Errorf("invalid --dir: %s: %w", o.DirPath, err)
-	}
-
-	return nil
+	return validateScaffoldOptions(cxt, "mixin", o.MixinName, o.AuthorName, o.AuthorUsername, &o.ModulePath, &o.DirPath)
 }
 
+// CreateMixin generates a new mixin project, with the commands called by
+// Porter, unit tests, and magefile targets to build, install and publish it.
 func (p *Porter) CreateMixin(opts MixinsCreateOptions) error {
-	skeletorDestPath := opts.DirPath + "/" + opts.MixinName
-
-	if err := exec.Command("git", "clone", SkeletorRepo, skeletorDestPath).Run(); err != nil {
-		return fmt.Errorf("failed cloning skeletor repo: %w", err)
-	}
-
-	err := os.Rename(skeletorDestPath+"/cmd/skeletor", skeletorDestPath+"/cmd/"+opts.MixinName)
-	if err != nil {
-		return err
-	}
-
-	err = os.Rename(skeletorDestPath+"/pkg/skeletor", skeletorDestPath+"/pkg/"+opts.MixinName)
-	if err != nil {
+	dest := filepath.Join(opts.DirPath, opts.MixinName)
+	data := newScaffoldData(opts.MixinName, opts.AuthorName, opts.ModulePath)
+	if err := p.renderScaffold(dest, data, "mixin"); err != nil {
 		return err
 	}
 
-	replacementList := map[string]string{
-		"get.porter.sh/mixin/skeletor":       fmt.Sprintf("github.com/%s/%s", opts.AuthorUsername, opts.MixinName),
-		"PKG = get.porter.sh/mixin/$(MIXIN)": fmt.Sprintf("PKG = github.com/%s/%s", opts.AuthorUsername, opts.MixinName),
-		"skeletor":                           opts.MixinName,
-		"YOURNAME":                           opts.AuthorName,
-	}
-
-	for replaced, replacement := range replacementList {
-		err := replaceStringInDir(skeletorDestPath, replaced, replacement)
-		if err != nil {
-			return err
-		}
-	}
-
 	fmt.Fprintf(p.Out, "Created %s mixin\n", opts.MixinName)
 
 	return nil
 }
-
-// replaceStringInDir walks through all the file in a designated directory and replace any occurence of a string with a particular replacement
-// while skipping specifically directory .git and file README.md
-func replaceStringInDir(dir, replaced, replacement string) error {
-	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() && info.Name() == ".git" {
-			return filepath.SkipDir
-		}
-		if !info.IsDir() && info.Name() != "README.md" {
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return err
-			}
-
-			err = os.WriteFile(path, bytes.Replace(content, []byte(replaced), []byte(replacement), -1), info.Mode().Perm())
-			if err != nil {
-				return err
-			}
-		}
-
-		return nil
-	})
-}
@@ -0,0 +1,278 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/mixin"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/Masterminds/semver/v3"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// EnsureMixinsOptions are the options for installing the mixins declared in
+// a porter manifest with porter mixins ensure.
+type EnsureMixinsOptions struct {
+	pkgmgmt.PackageDownloadOptions
+
+	// File path to the porter manifest. Defaults to the bundle in the current directory.
+	File string
+
+	// FeedURL is the atom feed from which mixins are installed. Defaults to
+	// the official Porter mixin feed.
+	FeedURL string
+
+	// Update ignores the versions recorded in porter.lock and selects the
+	// versions of the mixins again from the versions declared in the manifest.
+	Update bool
+}
+
+// Validate the options provided to porter mixins ensure.
+func (o *EnsureMixinsOptions) Validate(cxt *portercontext.Context) error {
+	if err := o.PackageDownloadOptions.Validate(); err != nil {
+		return err
+	}
+
+	if o.FeedURL != "" {
+		if _, err := url.Parse(o.FeedURL); err != nil {
+			return fmt.Errorf("invalid --feed-url %s: %w", o.FeedURL, err)
+		}
+	}
+
+	if o.File == "" {
+		o.File = config.Name
+	}
+	if _, err := cxt.FileSystem.Stat(o.File); err != nil {
+		return fmt.Errorf("unable to access --file %s: %w", o.File, err)
+	}
+
+	return nil
+}
+
+// getMixinLockFile returns the path to the lock file for a porter manifest.
+func getMixinLockFile(manifestPath string) string {
+	return filepath.Join(filepath.Dir(manifestPath), mixin.LockFileName)
+}
+
+// EnsureMixins installs the mixins declared in the porter manifest and records
+// their exact versions and digests in porter.lock. When porter.lock exists,
+// the versions that it records are installed instead, so that the bundle is
+// built with the same mixins on every machine.
+func (p *Porter) EnsureMixins(ctx context.Context, opts EnsureMixinsOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("file", opts.File), attribute.Bool("update", opts.Update))
+	defer span.EndSpan()
+
+	m, err := manifest.LoadManifestFrom(ctx, p.Config, opts.File)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	lockPath := getMixinLockFile(opts.File)
+	var lock mixin.LockFile
+	if !opts.Update {
+		lock, err = mixin.LoadLockFile(p.FileSystem, lockPath)
+		if err != nil {
+			return span.Error(err)
+		}
+	}
+
+	newLock := mixin.LockFile{SchemaVersion: mixin.LockFileSchemaVersion}
+	for _, decl := range m.Mixins {
+		locked, err := p.ensureMixin(ctx, opts, decl, lock)
+		if err != nil {
+			return span.Error(err)
+		}
+		newLock.Mixins = append(newLock.Mixins, locked)
+	}
+
+	if err = newLock.Save(p.FileSystem, lockPath); err != nil {
+		return span.Error(err)
+	}
+	fmt.Fprintf(p.Out, "Wrote the mixin versions to %s\n", lockPath)
+	return nil
+}
+
+// ensureMixin installs the version of the mixin recorded in the lock file, or
+// when the mixin is not locked, a version that satisfies the declared version.
+func (p *Porter) ensureMixin(ctx context.Context, opts EnsureMixinsOptions, decl manifest.MixinDeclaration, lock mixin.LockFile) (mixin.LockedMixin, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	installedVersion := p.getInstalledMixinVersion(ctx, decl.Name)
+
+	// Use the version from the lock file, unless the declared version changed after it was locked
+	if locked, ok := lock.Find(decl.Name); ok && locked.Constraint == decl.Version {
+		if installedVersion == locked.Version {
+			// Reinstall the mixin when the installed binaries cannot be verified
+			if matches, err := p.mixinMatchesLock(locked); err == nil && matches {
+				log.Debugf("%s mixin %s is already installed", decl.Name, locked.Version)
+				return locked, nil
+			}
+		}
+
+		if err := p.installMixinVersion(ctx, opts, decl.Name, locked.Version); err != nil {
+			return mixin.LockedMixin{}, err
+		}
+
+		matches, err := p.mixinMatchesLock(locked)
+		if err != nil {
+			return mixin.LockedMixin{}, err
+		}
+		if !matches {
+			return mixin.LockedMixin{}, fmt.Errorf("the %s mixin %s that was installed does not match the digests recorded in %s. Run porter mixins ensure --update to lock the mixin again", decl.Name, locked.Version, mixin.LockFileName)
+		}
+		return locked, nil
+	}
+
+	satisfied, err := mixinVersionSatisfies(decl.Version, installedVersion)
+	if err != nil {
+		return mixin.LockedMixin{}, err
+	}
+	if satisfied {
+		log.Debugf("%s mixin %s is already installed", decl.Name, installedVersion)
+	} else {
+		version := decl.Version
+		if version == "" {
+			version = "latest"
+		}
+		if err := p.installMixinVersion(ctx, opts, decl.Name, version); err != nil {
+			return mixin.LockedMixin{}, err
+		}
+		installedVersion = p.getInstalledMixinVersion(ctx, decl.Name)
+	}
+
+	locked := mixin.LockedMixin{
+		Name:       decl.Name,
+		Constraint: decl.Version,
+		Version:    installedVersion,
+	}
+	pkgDir, err := p.Mixins.GetPackageDir(decl.Name)
+	if err != nil {
+		return mixin.LockedMixin{}, err
+	}
+	locked.ClientDigest, locked.RuntimeDigest, err = mixin.GetDigests(p.FileSystem, pkgDir, decl.Name)
+	if err != nil {
+		return mixin.LockedMixin{}, fmt.Errorf("could not calculate the digests of the %s mixin: %w", decl.Name, err)
+	}
+	return locked, nil
+}
+
+// installMixinVersion installs a version of a mixin, which may be a version
+// constraint, from the mixin feed.
+func (p *Porter) installMixinVersion(ctx context.Context, opts EnsureMixinsOptions, name string, version string) error {
+	installOpts := mixin.InstallOptions{
+		InstallOptions: pkgmgmt.InstallOptions{
+			PackageDownloadOptions: opts.PackageDownloadOptions,
+			FeedURL:                opts.FeedURL,
+			Version:                version,
+		},
+	}
+	if err := installOpts.Validate([]string{name}); err != nil {
+		return err
+	}
+
+	if err := p.InstallMixin(ctx, installOpts); err != nil {
+		return fmt.Errorf("could not install the %s mixin %s: %w", name, version, err)
+	}
+	return nil
+}
+
+// getInstalledMixinVersion returns the version of an installed mixin, or an
+// empty string when the mixin is not installed.
+func (p *Porter) getInstalledMixinVersion(ctx context.Context, name string) string {
+	meta, err := p.Mixins.GetMetadata(ctx, name)
+	if err != nil {
+		return ""
+	}
+	return meta.GetVersionInfo().Version
+}
+
+// mixinMatchesLock determines if the binaries of an installed mixin have the
+// digests recorded in the lock file.
+func (p *Porter) mixinMatchesLock(locked mixin.LockedMixin) (bool, error) {
+	pkgDir, err := p.Mixins.GetPackageDir(locked.Name)
+	if err != nil {
+		return false, err
+	}
+
+	clientDigest, runtimeDigest, err := mixin.GetDigests(p.FileSystem, pkgDir, locked.Name)
+	if err != nil {
+		return false, fmt.Errorf("could not calculate the digests of the %s mixin: %w", locked.Name, err)
+	}
+	return clientDigest == locked.ClientDigest && runtimeDigest == locked.RuntimeDigest, nil
+}
+
+// mixinVersionSatisfies determines if an installed version of a mixin
+// satisfies the version declared in the manifest. Any installed version
+// satisfies a declaration without a version.
+func mixinVersionSatisfies(constraint string, installedVersion string) (bool, error) {
+	if installedVersion == "" {
+		return false, nil
+	}
+	if constraint == "" {
+		return true, nil
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid mixin version %q: %w", constraint, err)
+	}
+	v, err := semver.NewVersion(installedVersion)
+	if err != nil {
+		// Versions such as canary builds cannot be compared, install a version that satisfies the constraint
+		return false, nil
+	}
+	return c.Check(v), nil
+}
+
+// checkMixinLock warns when the mixins that are installed do not match the
+// versions recorded in porter.lock, so that the bundle may not be built the
+// same way as on other machines.
+func (p *Porter) checkMixinLock(ctx context.Context, m *manifest.Manifest, manifestPath string) error {
+	log := tracing.LoggerFromContext(ctx)
+
+	lockPath := getMixinLockFile(manifestPath)
+	exists, err := p.FileSystem.Exists(lockPath)
+	if err != nil || !exists {
+		return err
+	}
+
+	lock, err := mixin.LoadLockFile(p.FileSystem, lockPath)
+	if err != nil {
+		return err
+	}
+
+	for _, decl := range m.Mixins {
+		locked, ok := lock.Find(decl.Name)
+		if !ok || locked.Constraint != decl.Version {
+			log.Warnf("The %s mixin declared in %s is not locked in %s. Run porter mixins ensure to update the lock file", decl.Name, manifestPath, lockPath)
+			continue
+		}
+
+		installedVersion := p.getInstalledMixinVersion(ctx, decl.Name)
+		if installedVersion == "" {
+			log.Warnf("The %s mixin is not installed but %s is locked in %s. Run porter mixins ensure to install the locked version", decl.Name, locked.Version, lockPath)
+			continue
+		}
+		if installedVersion != locked.Version {
+			log.Warnf("The %s mixin %s is installed but %s is locked in %s. Run porter mixins ensure to install the locked version", decl.Name, installedVersion, locked.Version, lockPath)
+			continue
+		}
+
+		matches, err := p.mixinMatchesLock(locked)
+		if err != nil {
+			log.Warnf("Could not verify the %s mixin against %s: %s", decl.Name, lockPath, err)
+			continue
+		}
+		if !matches {
+			log.Warnf("The %s mixin %s that is installed does not match the digests recorded in %s. Run porter mixins ensure to install the locked version", decl.Name, locked.Version, lockPath)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package porter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/mixin"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEnsureMixins prepares a bundle that declares the exec mixin and
+// testmixin 0.1.x, with the binaries of the installed mixins on the file system.
+// The versions that are installed by porter mixins ensure are returned.
+func setupEnsureMixins(t *testing.T, p *TestPorter) *[]string {
+	p.TestConfig.TestContext.AddTestFile("testdata/mixins/porter-with-versions.yaml", config.Name)
+	for _, name := range []string{"exec", "testmixin"} {
+		writeTestMixinBinaries(t, p, name, name)
+	}
+
+	testMixins := p.Mixins.(*mixin.TestMixinProvider)
+	var installed []string
+	testMixins.InstallAssertions = append(testMixins.InstallAssertions, func(opts pkgmgmt.InstallOptions) error {
+		installed = append(installed, opts.Name+"@"+opts.Version)
+		return nil
+	})
+	return &installed
+}
+
+// writeTestMixinBinaries writes fake client and runtime binaries for an installed mixin.
+func writeTestMixinBinaries(t *testing.T, p *TestPorter, name string, contents string) {
+	pkgDir, err := p.Mixins.GetPackageDir(name)
+	require.NoError(t, err)
+	require.NoError(t, p.TestConfig.TestContext.AddTestFileContents([]byte(contents), filepath.Join(pkgDir, name)))
+	require.NoError(t, p.TestConfig.TestContext.AddTestFileContents([]byte(contents+"-runtime"), filepath.Join(pkgDir, "runtimes", name+"-runtime")))
+}
+
+// setTestMixinVersion changes the version of an installed test mixin.
+func setTestMixinVersion(p *TestPorter, name string, version string) {
+	testMixins := p.Mixins.(*mixin.TestMixinProvider)
+	for _, pkg := range testMixins.Packages {
+		if meta := pkg.(*mixin.Metadata); meta.Name == name {
+			meta.VersionInfo.Version = version
+		}
+	}
+}
+
+func TestPorter_EnsureMixins(t *testing.T) {
+	t.Parallel()
+
+	t.Run("installed versions are locked", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		installed := setupEnsureMixins(t, p)
+
+		opts := EnsureMixinsOptions{}
+		require.NoError(t, opts.Validate(p.Context))
+		require.NoError(t, p.EnsureMixins(p.RootContext, opts))
+
+		assert.Empty(t, *installed, "the installed mixins satisfy the declared versions and should not be installed again")
+		lock, err := p.FileSystem.ReadFile(mixin.LockFileName)
+		require.NoError(t, err)
+		p.CompareGoldenFile("testdata/mixins/porter.lock", string(lock))
+	})
+
+	t.Run("declared version is installed", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		installed := setupEnsureMixins(t, p)
+		setTestMixinVersion(p, "testmixin", "v0.2.0")
+
+		testMixins := p.Mixins.(*mixin.TestMixinProvider)
+		testMixins.InstallAssertions = append(testMixins.InstallAssertions, func(opts pkgmgmt.InstallOptions) error {
+			setTestMixinVersion(p, opts.Name, "v0.1.3")
+			return nil
+		})
+
+		opts := EnsureMixinsOptions{}
+		require.NoError(t, opts.Validate(p.Context))
+		require.NoError(t, p.EnsureMixins(p.RootContext, opts))
+
+		assert.Equal(t, []string{"testmixin@0.1.x"}, *installed)
+		lock, err := mixin.LoadLockFile(p.FileSystem, mixin.LockFileName)
+		require.NoError(t, err)
+		locked, ok := lock.Find("testmixin")
+		require.True(t, ok, "expected testmixin to be locked")
+		assert.Equal(t, "0.1.x", locked.Constraint)
+		assert.Equal(t, "v0.1.3", locked.Version)
+		assert.Equal(t, digest.FromString("testmixin-runtime"), locked.RuntimeDigest)
+	})
+
+	t.Run("locked version is installed", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		installed := setupEnsureMixins(t, p)
+		p.TestConfig.TestContext.AddTestFile("testdata/mixins/porter.lock", mixin.LockFileName)
+		setTestMixinVersion(p, "testmixin", "v0.1.5")
+
+		testMixins := p.Mixins.(*mixin.TestMixinProvider)
+		testMixins.InstallAssertions = append(testMixins.InstallAssertions, func(opts pkgmgmt.InstallOptions) error {
+			setTestMixinVersion(p, opts.Name, opts.Version)
+			return nil
+		})
+
+		opts := EnsureMixinsOptions{}
+		require.NoError(t, opts.Validate(p.Context))
+		require.NoError(t, p.EnsureMixins(p.RootContext, opts))
+
+		assert.Equal(t, []string{"testmixin@v0.1.0"}, *installed, "the version recorded in the lock file should be installed")
+		lock, err := p.FileSystem.ReadFile(mixin.LockFileName)
+		require.NoError(t, err)
+		p.CompareGoldenFile("testdata/mixins/porter.lock", string(lock))
+	})
+
+	t.Run("update ignores the lock file", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		installed := setupEnsureMixins(t, p)
+		p.TestConfig.TestContext.AddTestFile("testdata/mixins/porter.lock", mixin.LockFileName)
+		setTestMixinVersion(p, "testmixin", "v0.1.5")
+
+		opts := EnsureMixinsOptions{Update: true}
+		require.NoError(t, opts.Validate(p.Context))
+		require.NoError(t, p.EnsureMixins(p.RootContext, opts))
+
+		assert.Empty(t, *installed, "the installed mixins satisfy the declared versions and should not be installed again")
+		lock, err := mixin.LoadLockFile(p.FileSystem, mixin.LockFileName)
+		require.NoError(t, err)
+		locked, ok := lock.Find("testmixin")
+		require.True(t, ok, "expected testmixin to be locked")
+		assert.Equal(t, "v0.1.5", locked.Version)
+	})
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		setupEnsureMixins(t, p)
+		p.TestConfig.TestContext.AddTestFile("testdata/mixins/porter.lock", mixin.LockFileName)
+		writeTestMixinBinaries(t, p, "testmixin", "modified")
+
+		opts := EnsureMixinsOptions{}
+		require.NoError(t, opts.Validate(p.Context))
+		err := p.EnsureMixins(p.RootContext, opts)
+		require.ErrorContains(t, err, "the testmixin mixin v0.1.0 that was installed does not match the digests recorded in porter.lock")
+	})
+}
+
+func TestEnsureMixinsOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := EnsureMixinsOptions{}
+	err := opts.Validate(p.Context)
+	require.ErrorContains(t, err, "unable to access --file porter.yaml")
+}
+
+func TestPorter_checkMixinLock(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	setupEnsureMixins(t, p)
+	p.TestConfig.TestContext.AddTestFile("testdata/mixins/porter.lock", mixin.LockFileName)
+	setTestMixinVersion(p, "testmixin", "v0.1.5")
+
+	m, err := manifest.LoadManifestFrom(p.RootContext, p.Config, config.Name)
+	require.NoError(t, err)
+	require.NoError(t, p.checkMixinLock(p.RootContext, m, config.Name))
+
+	gotStderr := p.TestConfig.TestContext.GetError()
+	assert.Contains(t, gotStderr, "The testmixin mixin v0.1.5 is installed but v0.1.0 is locked in porter.lock")
+	assert.NotContains(t, gotStderr, "The exec mixin")
+}
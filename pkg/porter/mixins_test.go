@@ -3,6 +3,7 @@ package porter
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"get.porter.sh/porter/pkg/mixin"
@@ -73,11 +74,12 @@ func TestPorter_CreateMixin(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	opts := MixinsCreateOptions{
-		MixinName:      "MyMixin",
 		AuthorName:     "Author Name",
 		AuthorUsername: "username",
 		DirPath:        tempDir,
 	}
+	require.NoError(t, opts.Validate([]string{"MyMixin"}, p.Context))
+	assert.Equal(t, "github.com/username/MyMixin", opts.ModulePath, "the module path should default to the GitHub repository of the author")
 
 	err = p.CreateMixin(opts)
 	require.NoError(t, err)
@@ -85,4 +87,129 @@ func TestPorter_CreateMixin(t *testing.T) {
 	wantOutput := "Created MyMixin mixin\n"
 	gotOutput := p.TestConfig.TestContext.GetOutput()
 	assert.Contains(t, wantOutput, gotOutput)
+
+	mixinDir := filepath.Join(tempDir, "MyMixin")
+	for _, f := range []string{"go.mod", ".gitignore", "magefile.go", "atom-template.xml", "cmd/MyMixin/main.go", "pkg/mymixin/mixin.go", "pkg/mymixin/schema/schema.json", "pkg/mymixin/mixin_test.go"} {
+		exists, _ := p.FileSystem.Exists(filepath.Join(mixinDir, f))
+		assert.True(t, exists, "expected %s to be generated", f)
+	}
+
+	gotMain, err := p.FileSystem.ReadFile(filepath.Join(mixinDir, "cmd/MyMixin/main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gotMain), `"github.com/username/MyMixin/pkg/mymixin"`)
+
+	gotFeed, err := p.FileSystem.ReadFile(filepath.Join(mixinDir, "atom-template.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gotFeed), "<name>Author Name</name>")
+	assert.Contains(t, string(gotFeed), "{{#Entries}}", "the mustache template of the feed should not be rendered")
+
+	t.Run("directory exists", func(t *testing.T) {
+		err = p.CreateMixin(opts)
+		require.ErrorContains(t, err, "the directory already exists")
+	})
+}
+
+func TestMixinsCreateOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name       string
+		args       []string
+		opts       MixinsCreateOptions
+		wantModule string
+		wantError  string
+	}{
+		{name: "username", args: []string{"mymixin"}, opts: MixinsCreateOptions{AuthorName: "me", AuthorUsername: "me"}, wantModule: "github.com/me/mymixin"},
+		{name: "module", args: []string{"my-mixin"}, opts: MixinsCreateOptions{AuthorName: "me", ModulePath: "example.com/my-mixin"}, wantModule: "example.com/my-mixin"},
+		{name: "no name", args: []string{}, wantError: "mixin name is required"},
+		{name: "invalid name", args: []string{"my.mixin"}, opts: MixinsCreateOptions{AuthorName: "me", AuthorUsername: "me"}, wantError: `invalid mixin name "my.mixin"`},
+		{name: "no author", args: []string{"mymixin"}, opts: MixinsCreateOptions{AuthorUsername: "me"}, wantError: "must provide a value for flag --author"},
+		{name: "no username or module", args: []string{"mymixin"}, opts: MixinsCreateOptions{AuthorName: "me"}, wantError: "must provide a value for flag --username or --module"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTestPorter(t)
+
+			err := tc.opts.Validate(tc.args, p.Context)
+			if tc.wantError != "" {
+				require.ErrorContains(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantModule, tc.opts.ModulePath)
+		})
+	}
+}
+
+func TestPorter_ShowMixin(t *testing.T) {
+	t.Run("plaintext", func(t *testing.T) {
+		ctx := context.Background()
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := ShowMixinOptions{Name: "exec"}
+		opts.Format = printer.FormatPlaintext
+		err := p.ShowMixin(ctx, opts)
+		require.NoError(t, err, "ShowMixin failed")
+
+		expected := `Name: exec
+Version: v1.0
+Commit: abc123
+Author: Porter Authors
+Installed: 2022-01-01
+URL: https://cdn.porter.sh/mixins/exec/latest
+Binary Path: /home/myuser/.porter/mixins/exec/exec
+Protocol Versions: 1
+`
+		assert.Equal(t, expected, p.TestConfig.TestContext.GetOutput())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ctx := context.Background()
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := ShowMixinOptions{Name: "exec"}
+		opts.Format = printer.FormatJson
+		err := p.ShowMixin(ctx, opts)
+		require.NoError(t, err, "ShowMixin failed")
+
+		expected := `{
+  "name": "exec",
+  "version": "v1.0",
+  "commit": "abc123",
+  "author": "Porter Authors",
+  "url": "https://cdn.porter.sh/mixins/exec/latest",
+  "installedAt": "2022-01-01T00:00:00Z",
+  "binaryPath": "/home/myuser/.porter/mixins/exec/exec",
+  "protocolVersions": [
+    1
+  ]
+}
+`
+		assert.Equal(t, expected, p.TestConfig.TestContext.GetOutput())
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		ctx := context.Background()
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := ShowMixinOptions{Name: "helm3"}
+		opts.Format = printer.FormatPlaintext
+		err := p.ShowMixin(ctx, opts)
+		require.EqualError(t, err, "mixins helm3 not installed")
+	})
+}
+
+func TestShowMixinOptions_Validate(t *testing.T) {
+	opts := ShowMixinOptions{}
+	opts.RawFormat = "plaintext"
+	require.NoError(t, opts.Validate([]string{"Exec"}))
+	assert.Equal(t, "exec", opts.Name)
+
+	err := opts.Validate(nil)
+	assert.EqualError(t, err, "no name was specified")
+
+	err = opts.Validate([]string{"exec", "helm3"})
+	assert.EqualError(t, err, "only one positional argument may be specified, the name, but multiple were received: [exec helm3]")
 }
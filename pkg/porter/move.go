@@ -0,0 +1,221 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-multierror"
+)
+
+// MoveOptions represent options for Porter's installation move command
+type MoveOptions struct {
+	installationOptions
+
+	// TargetNamespace is the namespace to move the installation to.
+	TargetNamespace string
+
+	// TargetNamespaceSet indicates that TargetNamespace was specified, since an
+	// empty namespace is the global namespace. Defaults to the current namespace.
+	TargetNamespaceSet bool
+
+	// NewName of the installation. Defaults to the current name.
+	NewName string
+}
+
+// Validate prepares for the installation move command and validates the args/options.
+func (o *MoveOptions) Validate(args []string, cxt *portercontext.Context) error {
+	// Ensure only one argument exists (installation name) if args length non-zero
+	err := o.installationOptions.validateInstallationName(args)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(o.NewName, "/") {
+		return fmt.Errorf("invalid --new-name %s, the name of an installation cannot contain a slash", o.NewName)
+	}
+	if strings.Contains(o.TargetNamespace, "/") || o.TargetNamespace == "*" {
+		return fmt.Errorf("invalid --target-namespace %s", o.TargetNamespace)
+	}
+	if !o.TargetNamespaceSet && o.NewName == "" {
+		return errors.New("either --target-namespace or --new-name must be specified")
+	}
+
+	return o.installationOptions.defaultBundleFiles(cxt)
+}
+
+// MoveInstallation renames an installation and/or moves it to another namespace,
+// along with its runs, results and outputs, without executing the bundle. If the
+// installation of a dependency cannot be moved, the installations that were
+// already moved are restored.
+func (p *Porter) MoveInstallation(ctx context.Context, opts MoveOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	err := p.applyDefaultOptions(ctx, &opts.installationOptions)
+	if err != nil {
+		return err
+	}
+
+	newNamespace := opts.Namespace
+	if opts.TargetNamespaceSet {
+		newNamespace = opts.TargetNamespace
+	}
+	newName := opts.Name
+	if opts.NewName != "" {
+		newName = opts.NewName
+	}
+	if newNamespace == opts.Namespace && newName == opts.Name {
+		return span.Error(fmt.Errorf("installation %s/%s is already in namespace %q with that name", opts.Namespace, opts.Name, newNamespace))
+	}
+
+	installation, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(fmt.Errorf("could not retrieve installation %s/%s: %w", opts.Namespace, opts.Name, err))
+	}
+
+	// Moving the installation while the bundle is running would split its records between the old and new name
+	if installation.Status.ResultStatus == cnab.StatusRunning || installation.Status.ResultStatus == cnab.StatusPending {
+		return span.Error(fmt.Errorf("cannot move installation %s while the %s action is running", installation, installation.Status.Action))
+	}
+
+	// Dependencies are named after the installation that depends upon them, so they are moved along with it
+	depMoves, err := p.planDependencyMoves(ctx, installation, newNamespace, newName)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	// Keep track of how to undo each move, so that a failure does not leave the
+	// installation and its dependencies split between the old and new names
+	var undo []func() error
+	rollback := func(moveErr error) error {
+		var bigErr *multierror.Error
+		bigErr = multierror.Append(bigErr, moveErr)
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				bigErr = multierror.Append(bigErr, err)
+			}
+		}
+		return bigErr.ErrorOrNil()
+	}
+
+	moved, err := p.Installations.MoveInstallation(ctx, installation.Namespace, installation.Name, newNamespace, newName)
+	if err != nil {
+		return span.Error(fmt.Errorf("could not move installation %s to %s/%s: %w", installation, newNamespace, newName, err))
+	}
+	undo = append(undo, p.undoMoveInstallation(ctx, installation, moved))
+
+	moved.Status.Modified = time.Now()
+	if err = p.Installations.UpdateInstallation(ctx, moved); err != nil {
+		return span.Error(rollback(fmt.Errorf("could not save installation %s: %w", moved, err)))
+	}
+
+	for _, depMove := range depMoves {
+		undoDep, err := p.moveDependencyInstallation(ctx, depMove, moved)
+		if undoDep != nil {
+			undo = append(undo, undoDep)
+		}
+		if err != nil {
+			return span.Error(rollback(err))
+		}
+	}
+
+	span.Infof("Moved installation %s to %s", installation, moved)
+	return nil
+}
+
+// dependencyMove is the new namespace and name of the installation of a dependency.
+type dependencyMove struct {
+	Installation storage.Installation
+	Namespace    string
+	Name         string
+}
+
+// planDependencyMoves determines where the installations created for the dependencies
+// of an installation are moved. Installations of shared dependencies are used by other
+// installations in the namespace, so the installation can't be moved to another
+// namespace while it has shared dependencies.
+func (p *Porter) planDependencyMoves(ctx context.Context, parent storage.Installation, newNamespace string, newName string) ([]dependencyMove, error) {
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{Namespace: parent.Namespace})
+	if err != nil {
+		return nil, fmt.Errorf("could not list the dependencies of installation %s: %w", parent, err)
+	}
+
+	var moves []dependencyMove
+	for _, dep := range installations {
+		if dep.Labels[LabelParentInstallation] != parent.String() {
+			continue
+		}
+
+		if dep.Status.ResultStatus == cnab.StatusRunning || dep.Status.ResultStatus == cnab.StatusPending {
+			return nil, fmt.Errorf("cannot move installation %s while the %s action of its dependency %s is running", parent, dep.Status.Action, dep)
+		}
+
+		move := dependencyMove{Installation: dep, Namespace: newNamespace, Name: dep.Name}
+		if alias, ok := strings.CutPrefix(dep.Name, parent.Name+"-"); ok {
+			move.Name = depsv1.BuildPrerequisiteInstallationName(newName, alias)
+		} else if newNamespace != parent.Namespace {
+			return nil, fmt.Errorf("cannot move installation %s to namespace %q because its shared dependency %s is used by other installations in namespace %q", parent, newNamespace, dep, parent.Namespace)
+		} else {
+			// Shared dependencies are named after their sharing group, only the label is updated
+			move.Namespace = dep.Namespace
+		}
+
+		if move.Namespace != dep.Namespace || move.Name != dep.Name {
+			_, err := p.Installations.GetInstallation(ctx, move.Namespace, move.Name)
+			if err == nil {
+				return nil, fmt.Errorf("cannot move dependency %s of installation %s because installation %s/%s already exists", dep, parent, move.Namespace, move.Name)
+			}
+			if !errors.Is(err, storage.ErrNotFound{}) {
+				return nil, err
+			}
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// moveDependencyInstallation moves the installation of a dependency, so that its name
+// and namespace match its moved parent, and labels it with the new name of its parent.
+// Returns how to undo the changes that were made, even when the move failed.
+func (p *Porter) moveDependencyInstallation(ctx context.Context, depMove dependencyMove, parent storage.Installation) (func() error, error) {
+	dep := depMove.Installation
+	if depMove.Namespace != dep.Namespace || depMove.Name != dep.Name {
+		moved, err := p.Installations.MoveInstallation(ctx, dep.Namespace, dep.Name, depMove.Namespace, depMove.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not move dependency installation %s to %s/%s: %w", dep, depMove.Namespace, depMove.Name, err)
+		}
+		dep = moved
+		dep.Status.Modified = time.Now()
+	}
+	undo := p.undoMoveInstallation(ctx, depMove.Installation, dep)
+
+	dep.SetLabel(LabelParentInstallation, parent.String())
+	if err := p.Installations.UpdateInstallation(ctx, dep); err != nil {
+		return undo, fmt.Errorf("could not update dependency installation %s: %w", dep, err)
+	}
+	return undo, nil
+}
+
+// undoMoveInstallation returns a function that moves an installation back to its
+// original namespace and name, and restores the original installation document.
+func (p *Porter) undoMoveInstallation(ctx context.Context, original storage.Installation, moved storage.Installation) func() error {
+	return func() error {
+		if moved.Namespace != original.Namespace || moved.Name != original.Name {
+			if _, err := p.Installations.MoveInstallation(ctx, moved.Namespace, moved.Name, original.Namespace, original.Name); err != nil {
+				return fmt.Errorf("could not restore installation %s: %w", original, err)
+			}
+		}
+		if err := p.Installations.UpdateInstallation(ctx, original); err != nil {
+			return fmt.Errorf("could not restore installation %s: %w", original, err)
+		}
+		return nil
+	}
+}
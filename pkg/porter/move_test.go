@@ -0,0 +1,142 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingMoveInstallations fails to move the installation with the specified name.
+type failingMoveInstallations struct {
+	storage.InstallationProvider
+	name string
+}
+
+func (i failingMoveInstallations) MoveInstallation(ctx context.Context, namespace string, name string, newNamespace string, newName string) (storage.Installation, error) {
+	if name == i.name {
+		return storage.Installation{}, errors.New("move failed")
+	}
+	return i.InstallationProvider.MoveInstallation(ctx, namespace, name, newNamespace, newName)
+}
+
+func TestMoveOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := MoveOptions{NewName: "mysql2"}
+	require.NoError(t, opts.Validate([]string{"mysql"}, p.Context))
+	assert.Equal(t, "mysql", opts.Name)
+
+	opts = MoveOptions{}
+	require.EqualError(t, opts.Validate([]string{"mysql"}, p.Context), "either --target-namespace or --new-name must be specified")
+
+	opts = MoveOptions{TargetNamespaceSet: true}
+	require.NoError(t, opts.Validate([]string{"mysql"}, p.Context), "moving to the global namespace should be allowed")
+
+	opts = MoveOptions{NewName: "dev/mysql"}
+	require.ErrorContains(t, opts.Validate([]string{"mysql"}, p.Context), "cannot contain a slash")
+}
+
+func TestPorter_MoveInstallation(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) *TestPorter {
+		p := NewTestPorter(t)
+
+		i := p.TestInstallations.CreateInstallation(storage.NewInstallation("dev", "mysql"))
+		run := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+		result := p.TestInstallations.CreateResult(run.NewResult(cnab.StatusSucceeded))
+		p.TestInstallations.CreateOutput(result.NewOutput("connstr", []byte("mysql://dev")))
+		i.ApplyResult(run, result)
+		require.NoError(t, p.TestInstallations.UpdateInstallation(ctx, i))
+
+		dep := storage.NewInstallation("dev", "mysql-db")
+		dep.SetLabel(LabelParentInstallation, "dev/mysql")
+		dep = p.TestInstallations.CreateInstallation(dep)
+		depRun := p.TestInstallations.CreateRun(dep.NewRun(cnab.ActionInstall))
+		depResult := p.TestInstallations.CreateResult(depRun.NewResult(cnab.StatusSucceeded))
+		p.TestInstallations.CreateOutput(depResult.NewOutput("password", []byte("db-password")))
+		return p
+	}
+
+	t.Run("moves dependencies", func(t *testing.T) {
+		p := setup(t)
+		defer p.Close()
+
+		opts := MoveOptions{TargetNamespace: "prod", TargetNamespaceSet: true, NewName: "mysql2"}
+		opts.Namespace = "dev"
+		opts.Name = "mysql"
+		require.NoError(t, p.MoveInstallation(ctx, opts))
+
+		moved, err := p.Installations.GetInstallation(ctx, "prod", "mysql2")
+		require.NoError(t, err)
+		assert.Equal(t, "mysql2", moved.Name)
+
+		output, err := p.Installations.GetLastOutput(ctx, "prod", "mysql2", "connstr")
+		require.NoError(t, err)
+		assert.Equal(t, "mysql://dev", string(output.Value))
+
+		_, err = p.Installations.GetInstallation(ctx, "dev", "mysql-db")
+		require.ErrorIs(t, err, storage.ErrNotFound{}, "the dependency should be moved with its parent")
+
+		dep, err := p.Installations.GetInstallation(ctx, "prod", "mysql2-db")
+		require.NoError(t, err, "the dependency should be renamed after its parent")
+		assert.Equal(t, "prod/mysql2", dep.Labels[LabelParentInstallation], "the dependency should reference the new name of its parent")
+
+		output, err = p.Installations.GetLastOutput(ctx, "prod", "mysql2-db", "password")
+		require.NoError(t, err)
+		assert.Equal(t, "db-password", string(output.Value))
+	})
+
+	t.Run("refuses to move shared dependencies", func(t *testing.T) {
+		p := setup(t)
+		defer p.Close()
+
+		shared := storage.NewInstallation("dev", "mygroup-redis")
+		shared.SetLabel(LabelParentInstallation, "dev/mysql")
+		p.TestInstallations.CreateInstallation(shared)
+
+		opts := MoveOptions{TargetNamespace: "prod", TargetNamespaceSet: true}
+		opts.Namespace = "dev"
+		opts.Name = "mysql"
+		err := p.MoveInstallation(ctx, opts)
+		require.ErrorContains(t, err, `cannot move installation dev/mysql to namespace "prod" because its shared dependency dev/mygroup-redis is used by other installations`)
+
+		// Nothing is moved
+		_, err = p.Installations.GetInstallation(ctx, "dev", "mysql")
+		require.NoError(t, err)
+		dep, err := p.Installations.GetInstallation(ctx, "dev", "mysql-db")
+		require.NoError(t, err)
+		assert.Equal(t, "dev/mysql", dep.Labels[LabelParentInstallation])
+	})
+	t.Run("restores the installation when a dependency cannot be moved", func(t *testing.T) {
+		p := setup(t)
+		defer p.Close()
+		p.Installations = failingMoveInstallations{InstallationProvider: p.Installations, name: "mysql-db"}
+
+		opts := MoveOptions{TargetNamespace: "prod", TargetNamespaceSet: true, NewName: "mysql2"}
+		opts.Namespace = "dev"
+		opts.Name = "mysql"
+		err := p.MoveInstallation(ctx, opts)
+		require.ErrorContains(t, err, "could not move dependency installation dev/mysql-db to prod/mysql2-db: move failed")
+
+		_, err = p.Installations.GetInstallation(ctx, "prod", "mysql2")
+		require.ErrorIs(t, err, storage.ErrNotFound{}, "the move of the installation should be undone")
+		i, err := p.Installations.GetInstallation(ctx, "dev", "mysql")
+		require.NoError(t, err, "the installation should be restored")
+		assert.Equal(t, cnab.StatusSucceeded, i.Status.ResultStatus)
+
+		output, err := p.Installations.GetLastOutput(ctx, "dev", "mysql", "connstr")
+		require.NoError(t, err, "the outputs should be restored")
+		assert.Equal(t, "mysql://dev", string(output.Value))
+
+		dep, err := p.Installations.GetInstallation(ctx, "dev", "mysql-db")
+		require.NoError(t, err)
+		assert.Equal(t, "dev/mysql", dep.Labels[LabelParentInstallation])
+	})
+}
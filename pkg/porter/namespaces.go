@@ -0,0 +1,341 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// NamespaceListOptions represent options for Porter's namespaces list command
+type NamespaceListOptions struct {
+	printer.PrintOptions
+	Name   string
+	Labels []string
+}
+
+func (o *NamespaceListOptions) Validate() error {
+	return o.ParseFormat()
+}
+
+func (o NamespaceListOptions) ParseLabels() map[string]string {
+	return parseLabels(o.Labels)
+}
+
+// NamespaceCreateOptions represent options for Porter's namespaces create command
+type NamespaceCreateOptions struct {
+	Name             string
+	Description      string
+	Labels           []string
+	Protected        bool
+	MaxInstallations int64
+}
+
+// Validate the args provided to Porter's namespaces create command
+func (o *NamespaceCreateOptions) Validate(args []string) error {
+	if err := validateNamespaceName(args); err != nil {
+		return err
+	}
+	o.Name = args[0]
+	return nil
+}
+
+// NamespaceUpdateOptions represent options for Porter's namespaces update command.
+// Only the fields whose flag was set are changed.
+type NamespaceUpdateOptions struct {
+	Name string
+
+	Description    string
+	DescriptionSet bool
+
+	// Labels are added to the namespace, replacing the value of existing labels.
+	Labels []string
+
+	Protected    bool
+	ProtectedSet bool
+
+	MaxInstallations    int64
+	MaxInstallationsSet bool
+}
+
+// Validate the args provided to Porter's namespaces update command
+func (o *NamespaceUpdateOptions) Validate(args []string) error {
+	if err := validateNamespaceName(args); err != nil {
+		return err
+	}
+	o.Name = args[0]
+	return nil
+}
+
+// NamespaceDeleteOptions represent options for Porter's namespaces delete command
+type NamespaceDeleteOptions struct {
+	Name string
+}
+
+// Validate the args provided to Porter's namespaces delete command
+func (o *NamespaceDeleteOptions) Validate(args []string) error {
+	if err := validateNamespaceName(args); err != nil {
+		return err
+	}
+	o.Name = args[0]
+	return nil
+}
+
+func validateNamespaceName(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no namespace name was specified")
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("only one positional argument may be specified, the namespace name, but multiple were received: %s", args)
+	}
+}
+
+// DisplayNamespace is a namespace along with the number of resources defined in it.
+type DisplayNamespace struct {
+	storage.Namespace `yaml:",inline"`
+
+	// Installations is the number of installations in the namespace.
+	Installations int64 `json:"installations" yaml:"installations" toml:"installations"`
+
+	// CredentialSets is the number of credential sets in the namespace.
+	CredentialSets int64 `json:"credentialSets" yaml:"credentialSets" toml:"credentialSets"`
+
+	// ParameterSets is the number of parameter sets in the namespace.
+	ParameterSets int64 `json:"parameterSets" yaml:"parameterSets" toml:"parameterSets"`
+}
+
+// IsEmpty determines if there are no resources defined in the namespace.
+func (n DisplayNamespace) IsEmpty() bool {
+	return n.Installations == 0 && n.CredentialSets == 0 && n.ParameterSets == 0
+}
+
+// ListNamespaces lists the namespaces that were created, and the namespaces
+// that resources are defined in, but that were not created.
+func (p *Porter) ListNamespaces(ctx context.Context, opts NamespaceListOptions) ([]DisplayNamespace, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	labels := opts.ParseLabels()
+	namespaces, err := p.Namespaces.ListNamespaces(ctx, storage.ListOptions{
+		Name:   opts.Name,
+		Labels: labels,
+	})
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	counts, err := p.countResourcesByNamespace(ctx)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	results := make([]DisplayNamespace, 0, len(namespaces))
+	created := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		created[ns.Name] = true
+		results = append(results, counts.display(ns))
+	}
+
+	// Namespaces without metadata have no labels to match
+	if len(labels) == 0 {
+		nameFilter, err := regexp.Compile(opts.Name)
+		if err != nil {
+			return nil, span.Error(fmt.Errorf("invalid --name filter %s: %w", opts.Name, err))
+		}
+
+		for _, name := range counts.names() {
+			if name == "" || created[name] || !nameFilter.MatchString(name) {
+				continue
+			}
+			results = append(results, counts.display(storage.Namespace{Name: name}))
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
+
+// PrintNamespaces prints the namespaces.
+func (p *Porter) PrintNamespaces(ctx context.Context, opts NamespaceListOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	namespaces, err := p.ListNamespaces(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, namespaces)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, namespaces)
+	case printer.FormatPlaintext:
+		printNamespaceRow :=
+			func(v interface{}) []string {
+				ns, ok := v.(DisplayNamespace)
+				if !ok {
+					return nil
+				}
+				return []string{ns.Name, ns.Description, strconv.FormatInt(ns.Installations, 10),
+					strconv.FormatInt(ns.CredentialSets, 10), strconv.FormatInt(ns.ParameterSets, 10)}
+			}
+		return printer.PrintTable(p.Out, namespaces, printNamespaceRow,
+			"NAME", "DESCRIPTION", "INSTALLATIONS", "CREDENTIAL SETS", "PARAMETER SETS")
+	default:
+		return span.Error(fmt.Errorf("invalid format: %s", opts.Format))
+	}
+}
+
+// CreateNamespace saves the metadata of a new namespace.
+func (p *Porter) CreateNamespace(ctx context.Context, opts NamespaceCreateOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("namespace", opts.Name))
+	defer span.EndSpan()
+
+	ns := storage.NewNamespace(opts.Name)
+	ns.Description = opts.Description
+	ns.Labels = parseLabels(opts.Labels)
+	ns.Protected = opts.Protected
+	ns.Limits.MaxInstallations = opts.MaxInstallations
+	if err := ns.Validate(); err != nil {
+		return span.Error(err)
+	}
+
+	_, err := p.Namespaces.GetNamespace(ctx, ns.Name)
+	if err == nil {
+		return span.Error(fmt.Errorf("namespace %s already exists", ns.Name))
+	} else if !errors.Is(err, storage.ErrNotFound{}) {
+		return span.Error(err)
+	}
+
+	if err = p.Namespaces.InsertNamespace(ctx, ns); err != nil {
+		return span.Error(fmt.Errorf("unable to create namespace %s: %w", ns.Name, err))
+	}
+	return nil
+}
+
+// UpdateNamespace changes the metadata and policies of a namespace.
+func (p *Porter) UpdateNamespace(ctx context.Context, opts NamespaceUpdateOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("namespace", opts.Name))
+	defer span.EndSpan()
+
+	ns, err := p.Namespaces.GetNamespace(ctx, opts.Name)
+	if errors.Is(err, storage.ErrNotFound{}) {
+		return span.Error(fmt.Errorf("namespace %s does not exist, use porter namespaces create to create it", opts.Name))
+	} else if err != nil {
+		return span.Error(err)
+	}
+
+	if opts.DescriptionSet {
+		ns.Description = opts.Description
+	}
+	if labels := parseLabels(opts.Labels); len(labels) > 0 {
+		if ns.Labels == nil {
+			ns.Labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			ns.Labels[k] = v
+		}
+	}
+	if opts.ProtectedSet {
+		ns.Protected = opts.Protected
+	}
+	if opts.MaxInstallationsSet {
+		ns.Limits.MaxInstallations = opts.MaxInstallations
+	}
+	if err = ns.Validate(); err != nil {
+		return span.Error(err)
+	}
+
+	ns.Status.Modified = time.Now()
+	if err = p.Namespaces.UpdateNamespace(ctx, ns); err != nil {
+		return span.Error(fmt.Errorf("unable to update namespace %s: %w", ns.Name, err))
+	}
+	return nil
+}
+
+// DeleteNamespace removes the metadata of a namespace. The namespace must not
+// have any installations, credential sets or parameter sets.
+func (p *Porter) DeleteNamespace(ctx context.Context, opts NamespaceDeleteOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("namespace", opts.Name))
+	defer span.EndSpan()
+
+	counts, err := p.countResourcesByNamespace(ctx)
+	if err != nil {
+		return span.Error(err)
+	}
+	if ns := counts.display(storage.Namespace{Name: opts.Name}); !ns.IsEmpty() {
+		return span.Error(fmt.Errorf("namespace %s is not empty, it has %d installations, %d credential sets and %d parameter sets. Delete them before deleting the namespace",
+			opts.Name, ns.Installations, ns.CredentialSets, ns.ParameterSets))
+	}
+
+	err = p.Namespaces.RemoveNamespace(ctx, opts.Name)
+	if errors.Is(err, storage.ErrNotFound{}) {
+		span.Debug("nothing to remove, namespace already does not exist")
+		return nil
+	}
+	if err != nil {
+		return span.Error(fmt.Errorf("unable to delete namespace %s: %w", opts.Name, err))
+	}
+	return nil
+}
+
+// namespaceCounts is the number of resources of each type, by namespace.
+type namespaceCounts struct {
+	installations  map[string]int64
+	credentialSets map[string]int64
+	parameterSets  map[string]int64
+}
+
+func (p *Porter) countResourcesByNamespace(ctx context.Context) (namespaceCounts, error) {
+	var counts namespaceCounts
+	var err error
+	if counts.installations, err = p.Namespaces.CountByNamespace(ctx, storage.CollectionInstallations); err != nil {
+		return counts, fmt.Errorf("could not count the installations in each namespace: %w", err)
+	}
+	if counts.credentialSets, err = p.Namespaces.CountByNamespace(ctx, storage.CollectionCredentials); err != nil {
+		return counts, fmt.Errorf("could not count the credential sets in each namespace: %w", err)
+	}
+	if counts.parameterSets, err = p.Namespaces.CountByNamespace(ctx, storage.CollectionParameters); err != nil {
+		return counts, fmt.Errorf("could not count the parameter sets in each namespace: %w", err)
+	}
+	return counts, nil
+}
+
+func (c namespaceCounts) display(ns storage.Namespace) DisplayNamespace {
+	return DisplayNamespace{
+		Namespace:      ns,
+		Installations:  c.installations[ns.Name],
+		CredentialSets: c.credentialSets[ns.Name],
+		ParameterSets:  c.parameterSets[ns.Name],
+	}
+}
+
+// names returns the namespaces that have resources.
+func (c namespaceCounts) names() []string {
+	set := make(map[string]bool)
+	for _, counts := range []map[string]int64{c.installations, c.credentialSets, c.parameterSets} {
+		for name := range counts {
+			set[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
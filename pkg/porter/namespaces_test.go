@@ -0,0 +1,138 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_CreateNamespace(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	opts := NamespaceCreateOptions{Description: "Development environment", Labels: []string{"team=red"}}
+	require.NoError(t, opts.Validate([]string{"dev"}))
+	require.NoError(t, p.CreateNamespace(ctx, opts))
+
+	ns, err := p.Namespaces.GetNamespace(ctx, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "Development environment", ns.Description)
+	assert.Equal(t, map[string]string{"team": "red"}, ns.Labels)
+
+	err = p.CreateNamespace(ctx, opts)
+	require.EqualError(t, err, "namespace dev already exists")
+
+	err = p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "dev/test"})
+	require.ErrorContains(t, err, "invalid namespace name")
+}
+
+func TestPorter_ListNamespaces(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	require.NoError(t, p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "prod", Labels: []string{"team=red"}}))
+	require.NoError(t, p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "empty"}))
+	p.TestInstallations.CreateInstallation(storage.NewInstallation("prod", "mysql"))
+	require.NoError(t, p.Credentials.InsertCredentialSet(ctx, storage.NewCredentialSet("dev", "github")))
+	require.NoError(t, p.Credentials.InsertCredentialSet(ctx, storage.NewCredentialSet("", "aws")))
+
+	t.Run("all", func(t *testing.T) {
+		namespaces, err := p.ListNamespaces(ctx, NamespaceListOptions{})
+		require.NoError(t, err)
+		require.Len(t, namespaces, 3, "namespaces in use should be listed even when they were not created, but not the global namespace")
+
+		assert.Equal(t, "dev", namespaces[0].Name)
+		assert.Equal(t, int64(1), namespaces[0].CredentialSets)
+		assert.Equal(t, "empty", namespaces[1].Name)
+		assert.True(t, namespaces[1].IsEmpty())
+		assert.Equal(t, "prod", namespaces[2].Name)
+		assert.Equal(t, int64(1), namespaces[2].Installations)
+	})
+
+	t.Run("filter by label", func(t *testing.T) {
+		namespaces, err := p.ListNamespaces(ctx, NamespaceListOptions{Labels: []string{"team=red"}})
+		require.NoError(t, err)
+		require.Len(t, namespaces, 1)
+		assert.Equal(t, "prod", namespaces[0].Name)
+	})
+
+	t.Run("filter by name", func(t *testing.T) {
+		namespaces, err := p.ListNamespaces(ctx, NamespaceListOptions{Name: "de"})
+		require.NoError(t, err)
+		require.Len(t, namespaces, 1)
+		assert.Equal(t, "dev", namespaces[0].Name)
+	})
+
+	t.Run("print", func(t *testing.T) {
+		opts := NamespaceListOptions{PrintOptions: printer.PrintOptions{Format: printer.FormatPlaintext}}
+		require.NoError(t, p.PrintNamespaces(ctx, opts))
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "CREDENTIAL SETS")
+	})
+}
+
+func TestPorter_DeleteNamespace(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	require.NoError(t, p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "dev"}))
+	require.NoError(t, p.Credentials.InsertCredentialSet(ctx, storage.NewCredentialSet("dev", "github")))
+
+	err := p.DeleteNamespace(ctx, NamespaceDeleteOptions{Name: "dev"})
+	require.EqualError(t, err, "namespace dev is not empty, it has 0 installations, 1 credential sets and 0 parameter sets. Delete them before deleting the namespace")
+
+	require.NoError(t, p.Credentials.RemoveCredentialSet(ctx, "dev", "github"))
+	require.NoError(t, p.DeleteNamespace(ctx, NamespaceDeleteOptions{Name: "dev"}))
+
+	_, err = p.Namespaces.GetNamespace(ctx, "dev")
+	require.ErrorIs(t, err, storage.ErrNotFound{})
+
+	require.NoError(t, p.DeleteNamespace(ctx, NamespaceDeleteOptions{Name: "dev"}), "deleting a namespace that does not exist should not fail")
+}
+
+func TestPorter_UpdateNamespace(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	err := p.UpdateNamespace(ctx, NamespaceUpdateOptions{Name: "dev", Protected: true, ProtectedSet: true})
+	require.EqualError(t, err, "namespace dev does not exist, use porter namespaces create to create it")
+
+	require.NoError(t, p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "dev", Description: "Development environment", Labels: []string{"team=red"}}))
+	opts := NamespaceUpdateOptions{Labels: []string{"env=test"}, Protected: true, ProtectedSet: true, MaxInstallations: 5, MaxInstallationsSet: true}
+	require.NoError(t, opts.Validate([]string{"dev"}))
+	require.NoError(t, p.UpdateNamespace(ctx, opts))
+
+	ns, err := p.Namespaces.GetNamespace(ctx, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "Development environment", ns.Description, "the description should not change when it was not specified")
+	assert.Equal(t, map[string]string{"team": "red", "env": "test"}, ns.Labels)
+	assert.True(t, ns.Protected)
+	assert.Equal(t, int64(5), ns.Limits.MaxInstallations)
+
+	err = p.UpdateNamespace(ctx, NamespaceUpdateOptions{Name: "dev", MaxInstallations: -1, MaxInstallationsSet: true})
+	require.ErrorContains(t, err, "the limit cannot be negative")
+}
+
+func TestPorter_ProtectedNamespace(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	require.NoError(t, p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "prod", Protected: true}))
+	require.NoError(t, p.Credentials.InsertCredentialSet(ctx, storage.NewCredentialSet("prod", "github")))
+
+	opts := CredentialDeleteOptions{Name: "github", Namespace: "prod"}
+	err := p.DeleteCredential(ctx, opts)
+	require.ErrorIs(t, err, storage.ErrNamespaceProtected{})
+
+	opts.Force = true
+	require.NoError(t, p.DeleteCredential(ctx, opts))
+	_, err = p.Credentials.GetCredentialSet(ctx, "prod", "github")
+	require.ErrorIs(t, err, storage.ErrNotFound{})
+}
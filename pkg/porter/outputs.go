@@ -4,24 +4,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
 
+	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
 	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
 )
 
 // OutputShowOptions represent options for a bundle output show command
 type OutputShowOptions struct {
 	installationOptions
 	Output string
+
+	// Reveal the value of a sensitive output.
+	Reveal bool
 }
 
 // OutputListOptions represent options for a bundle output list command
 type OutputListOptions struct {
 	installationOptions
 	printer.PrintOptions
+
+	// Reveal the values of sensitive outputs.
+	Reveal bool
+}
+
+// OutputDownloadOptions represent options for a bundle output download command
+type OutputDownloadOptions struct {
+	installationOptions
+
+	// Directory where the outputs are written.
+	Directory string
+
+	// Outputs to download. Defaults to all outputs.
+	Outputs []string
 }
 
 // Validate validates the provided args, using the provided context,
@@ -65,6 +85,35 @@ func (o *OutputListOptions) Validate(args []string, cxt *portercontext.Context)
 	return o.ParseFormat()
 }
 
+// Validate validates the provided args, using the provided context,
+// setting attributes of OutputDownloadOptions as applicable
+func (o *OutputDownloadOptions) Validate(args []string, cxt *portercontext.Context) error {
+	switch len(args) {
+	case 0:
+		return errors.New("a destination directory must be provided")
+	case 1:
+		o.Directory = args[0]
+	default:
+		return fmt.Errorf("only one positional argument may be specified, the destination directory, but multiple were received: %s", args)
+	}
+
+	if exists, _ := cxt.FileSystem.Exists(o.Directory); exists {
+		if isDir, _ := cxt.FileSystem.IsDir(o.Directory); !isDir {
+			return fmt.Errorf("invalid destination %s, must be a directory not a file", o.Directory)
+		}
+	}
+
+	// If not provided, attempt to derive installation name from context
+	if o.installationOptions.Name == "" {
+		err := o.installationOptions.defaultBundleFiles(cxt)
+		if err != nil {
+			return errors.New("installation name must be provided via [--installation|-i INSTALLATION]")
+		}
+	}
+
+	return nil
+}
+
 // ShowBundleOutput shows a bundle output value, according to the provided options
 func (p *Porter) ShowBundleOutput(ctx context.Context, opts *OutputShowOptions) error {
 	err := p.applyDefaultOptions(ctx, &opts.installationOptions)
@@ -72,12 +121,27 @@ func (p *Porter) ShowBundleOutput(ctx context.Context, opts *OutputShowOptions)
 		return err
 	}
 
-	output, err := p.ReadBundleOutput(ctx, opts.Output, opts.Name, opts.Namespace)
+	o, err := p.Installations.GetLastOutput(ctx, opts.Namespace, opts.Name, opts.Output)
+	if err != nil {
+		return fmt.Errorf("unable to read output '%s' for installation '%s/%s': %w", opts.Output, opts.Namespace, opts.Name, err)
+	}
+
+	if !opts.Reveal {
+		run, err := p.Installations.GetRun(ctx, o.RunID)
+		if err != nil {
+			return fmt.Errorf("unable to read output '%s' for installation '%s/%s': %w", opts.Output, opts.Namespace, opts.Name, err)
+		}
+		if sensitive, _ := cnab.NewBundle(run.Bundle).IsOutputSensitive(o.Name); sensitive {
+			return fmt.Errorf("output '%s' is sensitive, use --reveal to print its value", opts.Output)
+		}
+	}
+
+	o, err = p.Sanitizer.RestoreOutput(ctx, o)
 	if err != nil {
 		return fmt.Errorf("unable to read output '%s' for installation '%s/%s': %w", opts.Output, opts.Namespace, opts.Name, err)
 	}
 
-	fmt.Fprintln(p.Out, output)
+	fmt.Fprintln(p.Out, string(o.Value))
 	return nil
 }
 
@@ -138,8 +202,12 @@ func (p *Porter) ListBundleOutputs(ctx context.Context, opts *OutputListOptions)
 	bun := cnab.NewBundle(c.Bundle)
 
 	displayOutputs := NewDisplayValuesFromOutputs(bun, resolved)
-	if err != nil {
-		return nil, err
+	if !opts.Reveal {
+		for i, output := range displayOutputs {
+			if output.Sensitive {
+				displayOutputs[i].Value = nil
+			}
+		}
 	}
 
 	return displayOutputs, nil
@@ -157,12 +225,82 @@ func (p *Porter) PrintBundleOutputs(ctx context.Context, opts OutputListOptions)
 	case printer.FormatYaml:
 		return printer.PrintYaml(p.Out, outputs)
 	case printer.FormatPlaintext:
+		if opts.Reveal {
+			// Print the values of sensitive outputs instead of masking them
+			for i := range outputs {
+				outputs[i].Sensitive = false
+			}
+		}
 		return p.printDisplayValuesTable(outputs)
 	default:
 		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
 }
 
+// DownloadBundleOutputs writes the latest value of each installation output to a
+// file named after the output in the destination directory.
+func (p *Porter) DownloadBundleOutputs(ctx context.Context, opts OutputDownloadOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	err := p.applyDefaultOptions(ctx, &opts.installationOptions)
+	if err != nil {
+		return err
+	}
+
+	outputs, err := p.Installations.GetLastOutputs(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	resolved, err := p.Sanitizer.RestoreOutputs(ctx, outputs)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	run, err := p.Installations.GetLastRun(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(err)
+	}
+	bun := cnab.NewBundle(run.Bundle)
+
+	selected := make(map[string]bool, len(opts.Outputs))
+	for _, name := range opts.Outputs {
+		if _, ok := resolved.GetByName(name); !ok {
+			return span.Error(fmt.Errorf("output %s not found for installation %s/%s", name, opts.Namespace, opts.Name))
+		}
+		selected[name] = true
+	}
+
+	if err = p.FileSystem.MkdirAll(opts.Directory, pkg.FileModeDirectory); err != nil {
+		return span.Error(fmt.Errorf("could not create destination directory %s: %w", opts.Directory, err))
+	}
+
+	for _, output := range resolved.Value() {
+		if len(selected) > 0 && !selected[output.Name] {
+			continue
+		}
+
+		// Skip outputs not defined in the bundle, e.g. io.cnab.outputs.invocationImageLogs
+		if _, ok := output.GetSchema(bun); !ok || bun.IsInternalOutput(output.Name) {
+			continue
+		}
+
+		if output.Name != filepath.Base(output.Name) || output.Name == ".." {
+			return span.Error(fmt.Errorf("output %s cannot be written to a file because its name is not a valid file name", output.Name))
+		}
+
+		dest := filepath.Join(opts.Directory, output.Name)
+		// Outputs may contain sensitive data, so only allow the current user access to the file
+		if err = p.FileSystem.WriteFile(dest, output.Value, 0600); err != nil {
+			return span.Error(fmt.Errorf("could not write output %s to %s: %w", output.Name, dest, err))
+		}
+		fmt.Fprintf(p.Out, "Wrote output %s to %s\n", output.Name, dest)
+	}
+
+	return nil
+}
+
 // ReadBundleOutput reads a bundle output from an installation
 func (p *Porter) ReadBundleOutput(ctx context.Context, outputName, installation, namespace string) (string, error) {
 	o, err := p.Installations.GetLastOutput(ctx, namespace, installation, outputName)
@@ -6,11 +6,14 @@ import (
 	"os"
 	"testing"
 
+	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/printer"
 	"get.porter.sh/porter/pkg/storage"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,11 +57,14 @@ func TestPorter_PrintBundleOutputs(t *testing.T) {
 	testcases := []struct {
 		name           string
 		format         printer.Format
+		reveal         bool
 		expectedOutput string
 	}{
 		{name: "text", format: printer.FormatPlaintext, expectedOutput: "testdata/outputs/show-expected-output.txt"},
 		{name: "json", format: printer.FormatJson, expectedOutput: "testdata/outputs/show-expected-output.json"},
 		{name: "yaml", format: printer.FormatYaml, expectedOutput: "testdata/outputs/show-expected-output.yaml"},
+		{name: "text reveal", format: printer.FormatPlaintext, reveal: true, expectedOutput: "testdata/outputs/show-expected-output-reveal.txt"},
+		{name: "json reveal", format: printer.FormatJson, reveal: true, expectedOutput: "testdata/outputs/show-expected-output-reveal.json"},
 	}
 
 	for _, tc := range testcases {
@@ -124,6 +130,7 @@ func TestPorter_PrintBundleOutputs(t *testing.T) {
 				PrintOptions: printer.PrintOptions{
 					Format: tc.format,
 				},
+				Reveal: tc.reveal,
 			}
 			err := p.PrintBundleOutputs(context.Background(), opts)
 			require.NoError(t, err, "could not print bundle outputs")
@@ -132,3 +139,151 @@ func TestPorter_PrintBundleOutputs(t *testing.T) {
 		})
 	}
 }
+
+func TestOutputDownloadOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := OutputDownloadOptions{}
+	opts.Name = "test"
+	require.NoError(t, opts.Validate([]string{"outputs"}, p.Context))
+	require.Equal(t, "outputs", opts.Directory)
+
+	err := opts.Validate(nil, p.Context)
+	require.EqualError(t, err, "a destination directory must be provided")
+
+	require.NoError(t, p.FileSystem.WriteFile("somefile", []byte("contents"), pkg.FileModeWritable))
+	err = opts.Validate([]string{"somefile"}, p.Context)
+	require.EqualError(t, err, "invalid destination somefile, must be a directory not a file")
+}
+
+func TestPorter_DownloadBundleOutputs(t *testing.T) {
+	t.Parallel()
+
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	b := bundle.Bundle{
+		Definitions: definition.Definitions{
+			"kubeconfig": &definition.Schema{
+				Type: "string",
+			},
+			"archive": &definition.Schema{
+				Type:            "string",
+				ContentEncoding: "base64",
+			},
+			"porter-state": &definition.Schema{
+				Type:    "string",
+				Comment: "porter-internal", // This output should be skipped because it's internal
+			},
+		},
+		Outputs: map[string]bundle.Output{
+			"kubeconfig":   {Definition: "kubeconfig"},
+			"archive":      {Definition: "archive"},
+			"porter-state": {Definition: "porter-state"},
+		},
+	}
+
+	extB := cnab.NewBundle(b)
+	i := p.TestInstallations.CreateInstallation(storage.NewInstallation("", "test"))
+	c := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall), func(r *storage.Run) {
+		r.Bundle = b
+	})
+	r := p.TestInstallations.CreateResult(c.NewResult(cnab.StatusSucceeded))
+	archive := []byte{0x1f, 0x8b, 0x00, 0xff}
+	p.CreateOutput(r.NewOutput("kubeconfig", []byte("apiVersion: v1")), extB)
+	p.CreateOutput(r.NewOutput("archive", archive), extB)
+	p.CreateOutput(r.NewOutput("porter-state", []byte("porter-state.tgz contents")), extB)
+
+	opts := OutputDownloadOptions{Directory: "/outputs"}
+	opts.Name = "test"
+	err := p.DownloadBundleOutputs(context.Background(), opts)
+	require.NoError(t, err)
+
+	got, err := p.FileSystem.ReadFile("/outputs/kubeconfig")
+	require.NoError(t, err)
+	require.Equal(t, "apiVersion: v1", string(got))
+
+	got, err = p.FileSystem.ReadFile("/outputs/archive")
+	require.NoError(t, err)
+	require.Equal(t, archive, got, "binary outputs should be written unmodified")
+
+	exists, _ := p.FileSystem.Exists("/outputs/porter-state")
+	require.False(t, exists, "internal outputs should not be downloaded")
+}
+
+func TestPorter_ReadBundleOutput_LargeOutput(t *testing.T) {
+	t.Parallel()
+
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.Data.Outputs.Store = config.OutputStoreFilesystem
+	p.Data.Outputs.Path = "/large-outputs"
+	p.Data.Outputs.Threshold = 10
+
+	b := bundle.Bundle{
+		Definitions: definition.Definitions{
+			"kubeconfig": &definition.Schema{Type: "string"},
+		},
+		Outputs: map[string]bundle.Output{
+			"kubeconfig": {Definition: "kubeconfig"},
+		},
+	}
+
+	i := p.TestInstallations.CreateInstallation(storage.NewInstallation("", "test"))
+	c := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall), func(r *storage.Run) {
+		r.Bundle = b
+	})
+	r := p.TestInstallations.CreateResult(c.NewResult(cnab.StatusSucceeded))
+	o := p.CreateOutput(r.NewOutput("kubeconfig", []byte("apiVersion: v1\nkind: Config")), cnab.NewBundle(b))
+	require.Nil(t, o.Value, "the value of the large output should not be stored in the database")
+	require.NotEmpty(t, o.Location, "the location of the large output should be stored in the database")
+
+	got, err := p.ReadBundleOutput(context.Background(), "kubeconfig", "test", "")
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1\nkind: Config", got)
+}
+
+func TestPorter_ShowBundleOutput_Sensitive(t *testing.T) {
+	t.Parallel()
+
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	writeOnly := true
+	b := bundle.Bundle{
+		Definitions: definition.Definitions{
+			"password": &definition.Schema{Type: "string", WriteOnly: &writeOnly},
+			"username": &definition.Schema{Type: "string"},
+		},
+		Outputs: map[string]bundle.Output{
+			"password": {Definition: "password"},
+			"username": {Definition: "username"},
+		},
+	}
+
+	extB := cnab.NewBundle(b)
+	i := p.TestInstallations.CreateInstallation(storage.NewInstallation("", "test"))
+	c := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall), func(r *storage.Run) {
+		r.Bundle = b
+	})
+	r := p.TestInstallations.CreateResult(c.NewResult(cnab.StatusSucceeded))
+	p.CreateOutput(r.NewOutput("password", []byte("top secret")), extB)
+	p.CreateOutput(r.NewOutput("username", []byte("admin")), extB)
+
+	opts := OutputShowOptions{installationOptions: installationOptions{Name: "test"}, Output: "username"}
+	err := p.ShowBundleOutput(context.Background(), &opts)
+	require.NoError(t, err)
+	assert.Equal(t, "admin\n", p.TestConfig.TestContext.GetOutput())
+
+	opts.Output = "password"
+	err = p.ShowBundleOutput(context.Background(), &opts)
+	require.EqualError(t, err, "output 'password' is sensitive, use --reveal to print its value")
+	assert.NotContains(t, p.TestConfig.TestContext.GetOutput(), "top secret")
+
+	opts.Reveal = true
+	err = p.ShowBundleOutput(context.Background(), &opts)
+	require.NoError(t, err)
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "top secret\n")
+}
@@ -50,6 +50,10 @@ func (o *SearchOptions) validatePackageName(args []string) error {
 
 // SearchPackages searches the provided package list according to the provided options
 func (p *Porter) SearchPackages(opts SearchOptions) error {
+	if err := p.CheckNetworkAccess(fmt.Sprintf("search for %ss", opts.Type)); err != nil {
+		return err
+	}
+
 	url := pkgmgmt.GetPackageListURL(opts.GetMirror(), opts.Type)
 	list, err := pkgmgmt.GetPackageListings(url)
 	if err != nil {
@@ -0,0 +1,200 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OutdatedPackagesOptions are the options for porter mixins outdated and
+// porter plugins outdated.
+type OutdatedPackagesOptions struct {
+	printer.PrintOptions
+	pkgmgmt.OutdatedOptions
+}
+
+// Validate the options provided to porter mixins outdated and porter plugins outdated.
+func (o *OutdatedPackagesOptions) Validate() error {
+	if err := o.OutdatedOptions.Validate(); err != nil {
+		return err
+	}
+
+	return o.ParseFormat()
+}
+
+// UpgradePackagesOptions are the options for porter mixins upgrade and porter
+// plugins upgrade.
+type UpgradePackagesOptions struct {
+	pkgmgmt.OutdatedOptions
+
+	// Names of the packages to upgrade.
+	Names []string
+
+	// All upgrades every outdated package.
+	All bool
+}
+
+// Validate the options provided to porter mixins upgrade and porter plugins upgrade.
+func (o *UpgradePackagesOptions) Validate(args []string) error {
+	if err := o.OutdatedOptions.Validate(); err != nil {
+		return err
+	}
+
+	if o.All && len(args) > 0 {
+		return errors.New("--all cannot be used when names are specified")
+	}
+	if !o.All && len(args) == 0 {
+		return fmt.Errorf("specify the names of the %ss to upgrade, or --all to upgrade every outdated %s", o.PackageType, o.PackageType)
+	}
+
+	o.Names = make([]string, len(args))
+	for i, arg := range args {
+		o.Names[i] = strings.ToLower(arg)
+	}
+	return nil
+}
+
+// getPackageManager returns the package manager for a type of package: mixin or plugin.
+func (p *Porter) getPackageManager(pkgType string) (pkgmgmt.PackageManager, error) {
+	switch pkgType {
+	case "mixin":
+		return p.Mixins, nil
+	case "plugin":
+		return p.Plugins, nil
+	default:
+		return nil, fmt.Errorf("unsupported package type: %s", pkgType)
+	}
+}
+
+// ListOutdatedPackages compares the installed mixins or plugins to the latest
+// versions in their feeds, and returns the packages that have a newer version.
+// When names are specified, only those packages are checked.
+func (p *Porter) ListOutdatedPackages(ctx context.Context, opts pkgmgmt.OutdatedOptions, names ...string) ([]pkgmgmt.PackageVersions, error) {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("package.type", opts.PackageType), attribute.StringSlice("names", names))
+	defer span.EndSpan()
+
+	pkgMgr, err := p.getPackageManager(opts.PackageType)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	installed, err := pkgMgr.List()
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	sort.Strings(installed)
+
+	if len(names) > 0 {
+		for _, name := range names {
+			if !stringSliceContains(installed, name) {
+				return nil, span.Error(fmt.Errorf("the %s %s is not installed", name, opts.PackageType))
+			}
+		}
+		installed = names
+	}
+
+	var outdated []pkgmgmt.PackageVersions
+	for _, name := range installed {
+		versions, err := pkgMgr.GetLatestVersion(ctx, name, opts)
+		if err != nil {
+			if len(names) > 0 {
+				return nil, span.Error(err)
+			}
+			span.Warnf("Could not check if the %s %s is outdated: %s", name, opts.PackageType, err)
+			continue
+		}
+
+		if versions.IsOutdated() {
+			outdated = append(outdated, versions)
+		}
+	}
+
+	return outdated, nil
+}
+
+// PrintOutdatedPackages prints the installed mixins or plugins that have a
+// newer version in their feeds.
+func (p *Porter) PrintOutdatedPackages(ctx context.Context, opts OutdatedPackagesOptions) error {
+	outdated, err := p.ListOutdatedPackages(ctx, opts.OutdatedOptions)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatPlaintext:
+		if len(outdated) == 0 {
+			fmt.Fprintf(p.Out, "All %ss are up-to-date\n", opts.PackageType)
+			return nil
+		}
+		return p.printPackageVersionsTable(outdated)
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, outdated)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, outdated)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+func (p *Porter) printPackageVersionsTable(versions []pkgmgmt.PackageVersions) error {
+	printRow :=
+		func(v interface{}) []string {
+			pv, ok := v.(pkgmgmt.PackageVersions)
+			if !ok {
+				return nil
+			}
+			return []string{pv.Name, pv.CurrentVersion, pv.LatestVersion}
+		}
+	return printer.PrintTable(p.Out, versions, printRow, "Name", "Current", "Latest")
+}
+
+// UpgradePackages installs the latest version of outdated mixins or plugins
+// from their feeds.
+func (p *Porter) UpgradePackages(ctx context.Context, opts UpgradePackagesOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("package.type", opts.PackageType), attribute.Bool("all", opts.All))
+	defer span.EndSpan()
+
+	pkgMgr, err := p.getPackageManager(opts.PackageType)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	outdated, err := p.ListOutdatedPackages(ctx, opts.OutdatedOptions, opts.Names...)
+	if err != nil {
+		return span.Error(err)
+	}
+	if len(outdated) == 0 {
+		fmt.Fprintf(p.Out, "All %ss are up-to-date\n", opts.PackageType)
+		return nil
+	}
+
+	if err = p.printPackageVersionsTable(outdated); err != nil {
+		return span.Error(err)
+	}
+
+	for _, pv := range outdated {
+		installOpts := pkgmgmt.InstallOptions{
+			PackageDownloadOptions: opts.PackageDownloadOptions,
+			FeedURL:                pv.FeedURL,
+			Version:                pv.LatestVersion,
+			PackageType:            opts.PackageType,
+		}
+		if err = installOpts.Validate([]string{pv.Name}); err != nil {
+			return span.Error(err)
+		}
+
+		if err = pkgMgr.Install(ctx, installOpts); err != nil {
+			return span.Error(fmt.Errorf("could not upgrade the %s %s to %s: %w", pv.Name, opts.PackageType, pv.LatestVersion, err))
+		}
+		fmt.Fprintf(p.Out, "Upgraded %s %s %s → %s\n", pv.Name, opts.PackageType, pv.CurrentVersion, pv.LatestVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/mixin"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/pkgmgmt/client"
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_PrintOutdatedPackages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("outdated mixins", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Mixins.(*mixin.TestMixinProvider).LatestVersions = map[string]string{
+			"exec":      "v1.1.0",
+			"testmixin": "v0.1.0",
+		}
+
+		opts := OutdatedPackagesOptions{}
+		opts.PackageType = "mixin"
+		require.NoError(t, opts.Validate())
+		require.NoError(t, p.PrintOutdatedPackages(p.RootContext, opts))
+
+		output := p.TestConfig.TestContext.GetOutput()
+		assert.Contains(t, output, "exec")
+		assert.Contains(t, output, "v1.1.0")
+		assert.NotContains(t, output, "testmixin", "up-to-date mixins should not be listed")
+	})
+
+	t.Run("up-to-date plugins", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Plugins.(*client.TestPackageManager).LatestVersions = map[string]string{
+			"plugin1": "v1.0",
+			"plugin2": "v1.0",
+		}
+
+		opts := OutdatedPackagesOptions{}
+		opts.PackageType = "plugin"
+		require.NoError(t, opts.Validate())
+		require.NoError(t, p.PrintOutdatedPackages(p.RootContext, opts))
+
+		output := p.TestConfig.TestContext.GetOutput()
+		assert.Contains(t, output, "All plugins are up-to-date")
+		assert.Contains(t, p.TestConfig.TestContext.GetError(), "Could not check if the unknown plugin is outdated")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Mixins.(*mixin.TestMixinProvider).LatestVersions = map[string]string{
+			"exec":      "v1.1.0",
+			"testmixin": "v0.2.0",
+		}
+
+		opts := OutdatedPackagesOptions{}
+		opts.PackageType = "mixin"
+		opts.RawFormat = string(printer.FormatJson)
+		require.NoError(t, opts.Validate())
+		require.NoError(t, p.PrintOutdatedPackages(p.RootContext, opts))
+
+		output := p.TestConfig.TestContext.GetOutput()
+		assert.Contains(t, output, `"currentVersion": "v0.1.0"`)
+		assert.Contains(t, output, `"latestVersion": "v0.2.0"`)
+	})
+}
+
+func TestUpgradePackagesOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name      string
+		args      []string
+		all       bool
+		wantError string
+	}{
+		{name: "all", all: true},
+		{name: "names", args: []string{"Exec", "testmixin"}},
+		{name: "all and names", args: []string{"exec"}, all: true, wantError: "--all cannot be used when names are specified"},
+		{name: "nothing", wantError: "specify the names of the mixins to upgrade, or --all"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := UpgradePackagesOptions{All: tc.all}
+			opts.PackageType = "mixin"
+			err := opts.Validate(tc.args)
+			if tc.wantError != "" {
+				require.ErrorContains(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			if len(tc.args) > 0 {
+				assert.Equal(t, []string{"exec", "testmixin"}, opts.Names)
+			}
+		})
+	}
+}
+
+func TestPorter_UpgradePackages(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*TestPorter, *[]string) {
+		p := NewTestPorter(t)
+		testMixins := p.Mixins.(*mixin.TestMixinProvider)
+		testMixins.LatestVersions = map[string]string{
+			"exec":      "v1.1.0",
+			"testmixin": "v0.2.0",
+		}
+
+		var installed []string
+		testMixins.InstallAssertions = append(testMixins.InstallAssertions, func(opts pkgmgmt.InstallOptions) error {
+			installed = append(installed, opts.Name+"@"+opts.Version)
+			return nil
+		})
+		return p, &installed
+	}
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		p, installed := setup(t)
+		defer p.Close()
+
+		opts := UpgradePackagesOptions{All: true}
+		opts.PackageType = "mixin"
+		require.NoError(t, opts.Validate(nil))
+		require.NoError(t, p.UpgradePackages(p.RootContext, opts))
+
+		assert.Equal(t, []string{"exec@v1.1.0", "testmixin@v0.2.0"}, *installed)
+		output := p.TestConfig.TestContext.GetOutput()
+		assert.Contains(t, output, "Upgraded exec mixin v1.0 → v1.1.0")
+		assert.Contains(t, output, "Upgraded testmixin mixin v0.1.0 → v0.2.0")
+	})
+
+	t.Run("by name", func(t *testing.T) {
+		t.Parallel()
+
+		p, installed := setup(t)
+		defer p.Close()
+
+		opts := UpgradePackagesOptions{}
+		opts.PackageType = "mixin"
+		require.NoError(t, opts.Validate([]string{"testmixin"}))
+		require.NoError(t, p.UpgradePackages(p.RootContext, opts))
+
+		assert.Equal(t, []string{"testmixin@v0.2.0"}, *installed)
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		t.Parallel()
+
+		p, _ := setup(t)
+		defer p.Close()
+
+		opts := UpgradePackagesOptions{}
+		opts.PackageType = "mixin"
+		require.NoError(t, opts.Validate([]string{"helm3"}))
+		err := p.UpgradePackages(p.RootContext, opts)
+		require.ErrorContains(t, err, "the helm3 mixin is not installed")
+	})
+}
@@ -0,0 +1,157 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/pkgmgmt/oci"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PublishPackageOptions are the options for publishing a mixin or plugin to
+// a registry as an OCI artifact.
+type PublishPackageOptions struct {
+	// Type of the package: mixin or plugin.
+	Type string
+
+	// Name of the package.
+	Name string
+
+	// Version of the package. Defaults to the tag of the reference.
+	Version string
+
+	// Dir is the directory that contains the binaries of the package, named
+	// NAME-GOOS-GOARCH[FILE_EXT]. Defaults to the current directory.
+	Dir string
+
+	// Reference to which the package is published, for example
+	// oci://ghcr.io/getporter/mixins/helm3:v1.0.0.
+	Reference string
+
+	// InsecureRegistry allows publishing to an unsecured registry.
+	InsecureRegistry bool
+
+	parsedRef cnab.OCIReference
+}
+
+// Validate the options provided to porter mixins publish and porter plugins publish.
+func (o *PublishPackageOptions) Validate(args []string, cxt *portercontext.Context) error {
+	if o.Type != "mixin" && o.Type != "plugin" {
+		return fmt.Errorf("unsupported package type: %s", o.Type)
+	}
+
+	switch len(args) {
+	case 0:
+		return errors.New("no name was specified")
+	case 1:
+		o.Name = strings.ToLower(args[0])
+	default:
+		return fmt.Errorf("only one positional argument may be specified, the name, but multiple were received: %s", args)
+	}
+
+	if o.Reference == "" {
+		return errors.New("--reference is required")
+	}
+	ref, err := cnab.ParseOCIReference(oci.TrimSource(o.Reference))
+	if err != nil {
+		return fmt.Errorf("invalid --reference %s: %w", o.Reference, err)
+	}
+	o.parsedRef = ref
+
+	if o.Version == "" {
+		if !ref.HasTag() {
+			return errors.New("--version is required when --reference does not have a tag")
+		}
+		o.Version = ref.Tag()
+	}
+
+	if o.Dir == "" {
+		o.Dir = "."
+	}
+	if _, err := cxt.FileSystem.Stat(o.Dir); err != nil {
+		return fmt.Errorf("unable to access --dir %s: %w", o.Dir, err)
+	}
+
+	return nil
+}
+
+// PublishPackage publishes the binaries of a mixin or plugin to a registry as
+// an OCI artifact, so that it can be installed with --source.
+func (p *Porter) PublishPackage(ctx context.Context, opts PublishPackageOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("name", opts.Name), attribute.String("reference", opts.Reference))
+	defer span.EndSpan()
+
+	binaries, err := p.findPackageBinaries(opts.Dir, opts.Name)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	hasRuntime := false
+	for _, bin := range binaries {
+		if bin.OS == "linux" && bin.Arch == "amd64" {
+			hasRuntime = true
+		}
+	}
+	if !hasRuntime {
+		return span.Error(fmt.Errorf("%s-linux-amd64 was not found in %s, it is required because it is used in the invocation image", opts.Name, opts.Dir))
+	}
+
+	regOpts := cnabtooci.RegistryOptions{
+		InsecureRegistry: opts.InsecureRegistry,
+		RegistryTLS:      p.Data.RegistryTLS,
+	}
+	craneOpts, err := cnabtooci.GetCraneOptions(p.FileSystem, regOpts, opts.parsedRef.Registry())
+	if err != nil {
+		return span.Error(err)
+	}
+
+	pkg := oci.Package{
+		Type:     opts.Type,
+		Name:     opts.Name,
+		Version:  opts.Version,
+		Binaries: binaries,
+	}
+	digest, err := oci.Push(ctx, p.FileSystem, pkg, opts.parsedRef.String(), craneOpts...)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	fmt.Fprintf(p.Out, "Published the %s %s %s to %s@%s\n", opts.Name, opts.Type, opts.Version, opts.parsedRef.Repository(), digest)
+	return nil
+}
+
+// findPackageBinaries returns the binaries of a package in a directory, which
+// are named NAME-GOOS-GOARCH[FILE_EXT].
+func (p *Porter) findPackageBinaries(dir string, name string) ([]oci.Binary, error) {
+	binaryRegex := regexp.MustCompile(fmt.Sprintf(`^%s-(linux|windows|darwin)-(amd64|arm64)(\.exe)?$`, regexp.QuoteMeta(name)))
+
+	entries, err := p.FileSystem.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not list the files in %s: %w", dir, err)
+	}
+
+	var binaries []oci.Binary
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := binaryRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		binaries = append(binaries, oci.Binary{
+			OS:   matches[1],
+			Arch: matches[2],
+			Path: filepath.Join(dir, entry.Name()),
+		})
+	}
+	return binaries, nil
+}
@@ -0,0 +1,85 @@
+package porter
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/pkgmgmt/oci"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishPackageOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name        string
+		args        []string
+		opts        PublishPackageOptions
+		wantVersion string
+		wantError   string
+	}{
+		{name: "version from tag", args: []string{"mymixin"}, opts: PublishPackageOptions{Reference: "oci://example.com/mixins/mymixin:v1.2.3"}, wantVersion: "v1.2.3"},
+		{name: "version specified", args: []string{"mymixin"}, opts: PublishPackageOptions{Reference: "example.com/mixins/mymixin:canary", Version: "v1.3.0-canary"}, wantVersion: "v1.3.0-canary"},
+		{name: "no name", opts: PublishPackageOptions{Reference: "example.com/mixins/mymixin:v1.2.3"}, wantError: "no name was specified"},
+		{name: "no reference", args: []string{"mymixin"}, wantError: "--reference is required"},
+		{name: "no version", args: []string{"mymixin"}, opts: PublishPackageOptions{Reference: "example.com/mixins/mymixin@sha256:276b44be3f478b4c8d1f99c1925386d45a878a853f22436ece5589f32e9df384"}, wantError: "--version is required"},
+		{name: "missing dir", args: []string{"mymixin"}, opts: PublishPackageOptions{Reference: "example.com/mixins/mymixin:v1.2.3", Dir: "missing"}, wantError: "unable to access --dir missing"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTestPorter(t)
+			defer p.Close()
+
+			opts := tc.opts
+			opts.Type = "mixin"
+			err := opts.Validate(tc.args, p.Context)
+			if tc.wantError != "" {
+				require.ErrorContains(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantVersion, opts.Version)
+		})
+	}
+}
+
+func TestPorter_PublishPackage(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	ref := strings.TrimPrefix(srv.URL, "http://") + "/mixins/mymixin:v1.2.3"
+
+	t.Run("published", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		require.NoError(t, p.FileSystem.WriteFile("bin/mymixin-linux-amd64", []byte("linux"), 0755))
+		require.NoError(t, p.FileSystem.WriteFile("bin/mymixin-windows-amd64.exe", []byte("windows"), 0755))
+		require.NoError(t, p.FileSystem.WriteFile("bin/othermixin-linux-amd64", []byte("other"), 0755))
+
+		opts := PublishPackageOptions{Type: "mixin", Dir: "bin", Reference: ref, InsecureRegistry: true}
+		require.NoError(t, opts.Validate([]string{"mymixin"}, p.Context))
+		require.NoError(t, p.PublishPackage(p.RootContext, opts))
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Published the mymixin mixin v1.2.3")
+
+		a, err := oci.Pull(p.RootContext, "mixin", ref, crane.Insecure)
+		require.NoError(t, err)
+		assert.Equal(t, "mymixin", a.Name)
+		assert.Equal(t, "v1.2.3", a.Version)
+		require.Len(t, a.Binaries, 2)
+		_, ok := a.FindBinary("windows", "amd64")
+		assert.True(t, ok, "expected the windows binary to be published")
+	})
+
+	t.Run("missing runtime", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		require.NoError(t, p.FileSystem.WriteFile("bin/mymixin-darwin-amd64", []byte("darwin"), 0755))
+
+		opts := PublishPackageOptions{Type: "mixin", Dir: "bin", Reference: ref, InsecureRegistry: true}
+		require.NoError(t, opts.Validate([]string{"mymixin"}, p.Context))
+		err := p.PublishPackage(p.RootContext, opts)
+		require.ErrorContains(t, err, "mymixin-linux-amd64 was not found in bin")
+	})
+}
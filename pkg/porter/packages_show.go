@@ -0,0 +1,74 @@
+package porter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	dtprinter "github.com/carolynvs/datetime-printer"
+	"github.com/olekukonko/tablewriter"
+)
+
+// validatePackageName grabs the name of a mixin or plugin from the first
+// positional argument.
+func validatePackageName(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return "", fmt.Errorf("no name was specified")
+	case 1:
+		return strings.ToLower(args[0]), nil
+	default:
+		return "", fmt.Errorf("only one positional argument may be specified, the name, but multiple were received: %s", args)
+	}
+}
+
+// printPackageDetails prints the metadata of an installed mixin or plugin,
+// and where it was installed from, for porter mixins show and porter plugins show.
+func printPackageDetails(out io.Writer, meta pkgmgmt.Metadata, info pkgmgmt.InstallInfo) {
+	// Set up human friendly time formatter
+	now := time.Now()
+	tp := dtprinter.DateTimePrinter{
+		Now: func() time.Time { return now },
+	}
+
+	fmt.Fprintf(out, "Name: %s\n", meta.Name)
+	fmt.Fprintf(out, "Version: %s\n", meta.Version)
+	fmt.Fprintf(out, "Commit: %s\n", meta.Commit)
+	fmt.Fprintf(out, "Author: %s\n", meta.Author)
+	fmt.Fprintf(out, "Installed: %s\n", tp.Format(info.InstalledAt))
+	if info.FeedURL != "" {
+		fmt.Fprintf(out, "Feed URL: %s\n", info.FeedURL)
+	}
+	if info.URL != "" {
+		fmt.Fprintf(out, "URL: %s\n", info.URL)
+	}
+	if info.Source != "" {
+		fmt.Fprintf(out, "Source: %s\n", info.Source)
+	}
+	fmt.Fprintf(out, "Binary Path: %s\n", info.BinaryPath)
+}
+
+// newDetailsTable creates a table for the nested data of a show command,
+// decorated differently from the tables printed by the printer package.
+func newDetailsTable(out io.Writer) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorders(tablewriter.Border{Left: false, Right: false, Bottom: false, Top: true})
+	table.SetAutoFormatHeaders(false)
+	return table
+}
+
+// formatProtocolVersions formats a list of protocol versions for display.
+func formatProtocolVersions(versions []int) string {
+	values := make([]string, len(versions))
+	for i, v := range versions {
+		values[i] = strconv.Itoa(v)
+	}
+	return strings.Join(values, ", ")
+}
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
-	depsv1 "get.porter.sh/porter/pkg/cnab/dependencies/v1"
 	"get.porter.sh/porter/pkg/editor"
 	"get.porter.sh/porter/pkg/encoding"
 	"get.porter.sh/porter/pkg/generator"
@@ -42,13 +41,12 @@ type ParameterEditOptions struct {
 
 // ListParameters lists saved parameter sets.
 func (p *Porter) ListParameters(ctx context.Context, opts ListOptions) ([]storage.ParameterSet, error) {
-	return p.Parameters.ListParameterSets(ctx, storage.ListOptions{
-		Namespace: opts.GetNamespace(),
-		Name:      opts.Name,
-		Labels:    opts.ParseLabels(),
-		Skip:      opts.Skip,
-		Limit:     opts.Limit,
-	})
+	query, err := opts.toStorageListOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Parameters.ListParameterSets(ctx, query)
 }
 
 // PrintParameters prints saved parameter sets.
@@ -58,31 +56,80 @@ func (p *Porter) PrintParameters(ctx context.Context, opts ListOptions) error {
 		return err
 	}
 
+	// Sort by the csv values so that timestamps are compared, instead of relative times
+	csvRow :=
+		func(v interface{}) []string {
+			cr, ok := v.(storage.ParameterSet)
+			if !ok {
+				return nil
+			}
+			return getParameterSetRow(cr, formatCsvTime, true)
+		}
+	if err := opts.SortRows(params, csvRow, getParameterSetHeaders(true)...); err != nil {
+		return err
+	}
+
+	page := make([]storage.ContinueToken, len(params))
+	for i, item := range params {
+		page[i] = storage.NewContinueToken(item.Namespace, item.Name)
+	}
+
 	switch opts.Format {
 	case printer.FormatJson:
-		return printer.PrintJson(p.Out, params)
+		err = printer.PrintJson(p.Out, params)
 	case printer.FormatYaml:
-		return printer.PrintYaml(p.Out, params)
-	case printer.FormatPlaintext:
+		err = printer.PrintYaml(p.Out, params)
+	case printer.FormatPlaintext, printer.FormatWide:
 		// have every row use the same "now" starting ... NOW!
 		now := time.Now()
 		tp := dtprinter.DateTimePrinter{
 			Now: func() time.Time { return now },
 		}
 
+		// Selected columns may include those only printed in the wide output
+		wide := opts.Format == printer.FormatWide || opts.HasColumns()
 		printParamRow :=
 			func(v interface{}) []string {
 				cr, ok := v.(storage.ParameterSet)
 				if !ok {
 					return nil
 				}
-				return []string{cr.Namespace, cr.Name, tp.Format(cr.Status.Modified)}
+				return getParameterSetRow(cr, tp.Format, wide)
 			}
-		return printer.PrintTable(p.Out, params, printParamRow,
-			"NAMESPACE", "NAME", "MODIFIED")
+		err = opts.PrintTable(p.Out, params, printParamRow, getParameterSetHeaders(wide)...)
+	case printer.FormatCsv:
+		err = opts.PrintCsv(p.Out, params, csvRow, getParameterSetHeaders(true)...)
 	default:
 		return fmt.Errorf("invalid format: %s", opts.Format)
 	}
+	if err != nil {
+		return err
+	}
+
+	p.printContinueToken(opts, page)
+	return nil
+}
+
+// getParameterSetHeaders returns the column headers used when listing parameter sets.
+// The wide output adds the labels and the names of the parameters in the set.
+func getParameterSetHeaders(wide bool) []string {
+	headers := []string{"NAMESPACE", "NAME", "MODIFIED"}
+	if wide {
+		headers = append(headers, "LABELS", "PARAMETERS")
+	}
+	return headers
+}
+
+func getParameterSetRow(cr storage.ParameterSet, formatTime func(time.Time) string, wide bool) []string {
+	row := []string{cr.Namespace, cr.Name, formatTime(cr.Status.Modified)}
+	if wide {
+		names := make([]string, 0, len(cr.Parameters))
+		for _, item := range cr.Parameters {
+			names = append(names, item.Name)
+		}
+		row = append(row, formatLabels(cr.Labels), strings.Join(names, ","))
+	}
+	return row
 }
 
 // ParameterOptions represent generic/base options for a Porter parameters command
@@ -139,7 +186,7 @@ func (p *Porter) GenerateParameters(ctx context.Context, opts ParameterOptions)
 		},
 		Bundle: bundleRef.Definition,
 	}
-	fmt.Fprintf(p.Out, "Generating new parameter set %s from bundle %s\n", genOpts.Name, bundleRef.Definition.Name)
+	p.printInfof("Generating new parameter set %s from bundle %s\n", genOpts.Name, bundleRef.Definition.Name)
 	numExternalParams := 0
 
 	for name := range bundleRef.Definition.Parameters {
@@ -148,7 +195,7 @@ func (p *Porter) GenerateParameters(ctx context.Context, opts ParameterOptions)
 		}
 	}
 
-	fmt.Fprintf(p.Out, "==> %d parameter(s) declared for bundle %s\n", numExternalParams, bundleRef.Definition.Name)
+	p.printInfof("==> %d parameter(s) declared for bundle %s\n", numExternalParams, bundleRef.Definition.Name)
 
 	pset, err := genOpts.GenerateParameters()
 	if err != nil {
@@ -184,8 +231,11 @@ func (o *ParameterEditOptions) Validate(args []string) error {
 	return nil
 }
 
-// EditParameter edits the parameters of the provided name.
-func (p *Porter) EditParameter(ctx context.Context, opts ParameterEditOptions) error {
+// EditParameterSet edits the parameter set of the provided name.
+func (p *Porter) EditParameterSet(ctx context.Context, opts ParameterEditOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
 	paramSet, err := p.Parameters.GetParameterSet(ctx, opts.Namespace, opts.Name)
 	if err != nil {
 		return err
@@ -193,34 +243,50 @@ func (p *Porter) EditParameter(ctx context.Context, opts ParameterEditOptions) e
 
 	contents, err := encoding.MarshalYaml(paramSet)
 	if err != nil {
-		return fmt.Errorf("unable to load parameter set: %w", err)
+		return span.Error(fmt.Errorf("unable to load parameter set: %w", err))
 	}
 
 	editor := editor.New(p.Context, fmt.Sprintf("porter-%s.yaml", paramSet.Name), contents)
 	output, err := editor.Run(ctx)
 	if err != nil {
-		return fmt.Errorf("unable to open editor to edit parameter set: %w", err)
+		return span.Error(fmt.Errorf("unable to open editor to edit parameter set: %w", err))
 	}
 
 	err = encoding.UnmarshalYaml(output, &paramSet)
 	if err != nil {
-		return fmt.Errorf("unable to process parameter set: %w", err)
+		return span.Error(fmt.Errorf("unable to process parameter set: %w", err))
 	}
 
-	err = p.Parameters.Validate(ctx, paramSet)
+	err = p.validateEditedParameterSet(ctx, paramSet)
 	if err != nil {
-		return fmt.Errorf("parameter set is invalid: %w", err)
+		return span.Error(fmt.Errorf("parameter set is invalid: %w", err))
 	}
 
 	paramSet.Status.Modified = time.Now()
 	err = p.Parameters.UpdateParameterSet(ctx, paramSet)
 	if err != nil {
-		return fmt.Errorf("unable to save parameter set: %w", err)
+		return span.Error(fmt.Errorf("unable to save parameter set: %w", err))
 	}
 
 	return nil
 }
 
+// validateEditedParameterSet validates a parameter set before it is saved,
+// including that the parameter sets that it inherits exist and do not
+// inherit from the edited parameter set.
+func (p *Porter) validateEditedParameterSet(ctx context.Context, paramSet storage.ParameterSet) error {
+	if err := p.Parameters.Validate(ctx, paramSet); err != nil {
+		return err
+	}
+
+	for _, parent := range paramSet.Inherits {
+		if _, err := p.resolveParameterSetInheritance(ctx, paramSet.Namespace, parent, []string{paramSet.Name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type DisplayParameterSet struct {
 	// SchemaType helps when we export the definition so editors can detect the type of document, it's not used by porter.
 	SchemaType           string `json:"schemaType" yaml:"schemaType"`
@@ -275,6 +341,11 @@ func (p *Porter) ShowParameter(ctx context.Context, opts ParameterShowOptions) e
 		fmt.Fprintf(p.Out, "Created: %s\n", tp.Format(paramSet.Status.Created))
 		fmt.Fprintf(p.Out, "Modified: %s\n\n", tp.Format(paramSet.Status.Modified))
 
+		// Print inherited parameter sets, if any
+		if len(paramSet.Inherits) > 0 {
+			fmt.Fprintf(p.Out, "Inherits: %s\n\n", strings.Join(paramSet.Inherits, ", "))
+		}
+
 		// Print labels, if any
 		if len(paramSet.Labels) > 0 {
 			fmt.Fprintln(p.Out, "Labels:")
@@ -301,6 +372,9 @@ func (p *Porter) ShowParameter(ctx context.Context, opts ParameterShowOptions) e
 type ParameterDeleteOptions struct {
 	Name      string
 	Namespace string
+
+	// Force deletes the parameter set from a protected namespace.
+	Force bool
 }
 
 // DeleteParameter deletes the parameter set corresponding to the provided
@@ -309,6 +383,9 @@ func (p *Porter) DeleteParameter(ctx context.Context, opts ParameterDeleteOption
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
 
+	if opts.Force {
+		ctx = storage.WithForce(ctx)
+	}
 	err := p.Parameters.RemoveParameterSet(ctx, opts.Namespace, opts.Name)
 	if errors.Is(err, storage.ErrNotFound{}) {
 		span.Debug("Cannot remove parameter set because it already doesn't exist")
@@ -346,21 +423,7 @@ func (p *Porter) loadParameterSets(ctx context.Context, bun cnab.ExtendedBundle,
 	resolvedParameters := secrets.Set{}
 
 	for _, name := range params {
-		// Try to get the params in the local namespace first, fallback to the global creds
-		query := storage.FindOptions{
-			Sort: []string{"-namespace"},
-			Filter: bson.M{
-				"name": name,
-				"$or": []bson.M{
-					{"namespace": ""},
-					{"namespace": namespace},
-				},
-			},
-		}
-		store := p.Parameters.GetDataStore()
-
-		var pset storage.ParameterSet
-		err := store.FindOne(ctx, storage.CollectionParameters, query, &pset)
+		pset, err := p.getInheritedParameterSet(ctx, namespace, name)
 		if err != nil {
 			return nil, err
 		}
@@ -400,6 +463,79 @@ func (p *Porter) loadParameterSets(ctx context.Context, bun cnab.ExtendedBundle,
 	return resolvedParameters, nil
 }
 
+// findParameterSet retrieves a parameter set by name, looking in the specified
+// namespace first and then falling back to the global namespace.
+func (p *Porter) findParameterSet(ctx context.Context, namespace string, name string) (storage.ParameterSet, error) {
+	query := storage.FindOptions{
+		Sort: []string{"-namespace"},
+		Filter: bson.M{
+			"name": name,
+			"$or": []bson.M{
+				{"namespace": ""},
+				{"namespace": namespace},
+			},
+		},
+	}
+	store := p.Parameters.GetDataStore()
+
+	var pset storage.ParameterSet
+	err := store.FindOne(ctx, storage.CollectionParameters, query, &pset)
+	return pset, err
+}
+
+// getInheritedParameterSet retrieves a parameter set and merges in the parameters
+// from the parameter sets that it inherits from. Parent parameter sets are applied
+// in the order they are declared, and the parameter set's own parameters are applied last.
+func (p *Porter) getInheritedParameterSet(ctx context.Context, namespace string, name string) (storage.ParameterSet, error) {
+	return p.resolveParameterSetInheritance(ctx, namespace, name, nil)
+}
+
+func (p *Porter) resolveParameterSetInheritance(ctx context.Context, namespace string, name string, chain []string) (storage.ParameterSet, error) {
+	for _, ancestor := range chain {
+		if ancestor == name {
+			return storage.ParameterSet{}, fmt.Errorf("parameter set inheritance cycle detected: %s -> %s", strings.Join(chain, " -> "), name)
+		}
+	}
+	chain = append(chain, name)
+
+	pset, err := p.findParameterSet(ctx, namespace, name)
+	if err != nil {
+		if len(chain) > 1 {
+			return storage.ParameterSet{}, fmt.Errorf("could not find parameter set %s inherited by %s: %w", name, chain[len(chain)-2], err)
+		}
+		return storage.ParameterSet{}, err
+	}
+
+	if len(pset.Inherits) == 0 {
+		return pset, nil
+	}
+
+	var merged []secrets.Strategy
+	indices := make(map[string]int)
+	mergeParams := func(params []secrets.Strategy) {
+		for _, param := range params {
+			if i, ok := indices[param.Name]; ok {
+				merged[i] = param
+				continue
+			}
+			indices[param.Name] = len(merged)
+			merged = append(merged, param)
+		}
+	}
+
+	for _, parentName := range pset.Inherits {
+		parent, err := p.resolveParameterSetInheritance(ctx, namespace, parentName, chain)
+		if err != nil {
+			return storage.ParameterSet{}, err
+		}
+		mergeParams(parent.Parameters)
+	}
+	mergeParams(pset.Parameters)
+
+	pset.Parameters = merged
+	return pset, nil
+}
+
 type DisplayValue struct {
 	Name      string      `json:"name" yaml:"name"`
 	Type      string      `json:"type" yaml:"type"`
@@ -589,6 +725,11 @@ func (p *Porter) finalizeParameters(ctx context.Context, installation storage.In
 			if err != nil {
 				return nil, fmt.Errorf("unable to convert parameter's %s value %s to the destination parameter type %s: %w", key, unconverted, def.Type, err)
 			}
+
+			// Report every problem with nested values, such as the items of an array parameter
+			if err = cnab.ValidateParameterValue(key, def, value); err != nil {
+				return nil, err
+			}
 			typedParams[key] = value
 		} else {
 			// bundle dependency parameters can be any type, not sure we have a solid way to do a typed conversion
@@ -603,7 +744,16 @@ func (p *Porter) finalizeParameters(ctx context.Context, installation storage.In
 func (p *Porter) getUnconvertedValueFromRaw(b cnab.ExtendedBundle, def *definition.Schema, key, rawValue string) (string, error) {
 	// the parameter value (via rawValue) may represent a file on the local filesystem
 	if b.IsFileType(def) {
-		if _, err := p.FileSystem.Stat(rawValue); err == nil {
+		if fi, err := p.FileSystem.Stat(rawValue); err == nil {
+			// A directory is archived and extracted to the parameter's destination path in the bundle
+			if fi.IsDir() {
+				value, err := cnab.EncodeDirectoryParameter(p.FileSystem, rawValue)
+				if err != nil {
+					return "", fmt.Errorf("unable to read directory parameter %s at %s: %w", key, rawValue, err)
+				}
+				return value, nil
+			}
+
 			bytes, err := p.FileSystem.ReadFile(rawValue)
 			if err != nil {
 				return "", fmt.Errorf("unable to read file parameter %s at %s: %w", key, rawValue, err)
@@ -641,7 +791,10 @@ func (p *Porter) resolveParameterSources(ctx context.Context, bun cnab.ExtendedB
 				outputName = source.OutputName
 			case cnab.DependencyOutputParameterSource:
 				// TODO(carolynvs): does this need to take namespace into account
-				installationName = depsv1.BuildPrerequisiteInstallationName(installation.Name, source.Dependency)
+				installationName, err = p.getDependencyInstallationName(ctx, bun, installation, source.Dependency)
+				if err != nil {
+					return nil, span.Error(err)
+				}
 				outputName = source.OutputName
 			}
 
@@ -656,12 +809,9 @@ func (p *Porter) resolveParameterSources(ctx context.Context, bun cnab.ExtendedB
 				return nil, span.Error(fmt.Errorf("could not set parameter %s from output %s of %s: %w", parameterName, outputName, installation, err))
 			}
 
-			if output.Key != "" {
-				resolved, err := p.Sanitizer.RestoreOutput(ctx, output)
-				if err != nil {
-					return nil, span.Error(fmt.Errorf("could not resolve %s's output %s: %w", installation, outputName, err))
-				}
-				output = resolved
+			output, err = p.Sanitizer.RestoreOutput(ctx, output)
+			if err != nil {
+				return nil, span.Error(fmt.Errorf("could not resolve %s's output %s: %w", installation, outputName, err))
 			}
 
 			param, ok := bun.Parameters[parameterName]
@@ -857,11 +1007,24 @@ func (p *Porter) applyActionOptionsToInstallation(ctx context.Context, ba Bundle
 		}
 	}
 
+	//
+	// 7. Apply the arguments of a custom action, which only apply to this run and are not saved on the installation
+	//
+	if invokeOpts, ok := ba.(InvokeOptions); ok {
+		args, err := invokeOpts.resolveArguments(bun)
+		if err != nil {
+			return err
+		}
+		for k, v := range args {
+			resolvedParams[k] = v
+		}
+	}
+
 	// This contains resolved sensitive values, so only trace it in special dev builds (nothing is traced for release builds)
 	span.SetSensitiveAttributes(tracing.ObjectAttribute("user-specified-parameters", resolvedParams))
 
 	//
-	// 7. When a parameter is not specified, fallback to a parameter source or default
+	// 8. When a parameter is not specified, fallback to a parameter source or default
 	//
 	finalParams, err := p.finalizeParameters(ctx, *inst, bun, ba.GetAction(), resolvedParams)
 	if err != nil {
@@ -874,9 +1037,12 @@ func (p *Porter) applyActionOptionsToInstallation(ctx context.Context, ba Bundle
 	// Remember the final set of parameters so we don't have to resolve them more than once
 	o.finalParams = finalParams
 
-	// Ensure we aren't storing any secrets on the installation resource
-	if err = p.sanitizeInstallation(ctx, inst, bundleRef.Definition); err != nil {
-		return err
+	// Ensure we aren't storing any secrets on the installation resource.
+	// A dry run does not save the installation, so there is nothing to sanitize.
+	if !o.DryRun {
+		if err = p.sanitizeInstallation(ctx, inst, bundleRef.Definition); err != nil {
+			return err
+		}
 	}
 
 	// re-validate the installation since we modified it here
@@ -0,0 +1,258 @@
+package porter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// ParameterChangeAdded indicates that a parameter is set on the next run but was not set on the last run.
+	ParameterChangeAdded = "added"
+
+	// ParameterChangeRemoved indicates that a parameter was set on the last run but is not set on the next run.
+	ParameterChangeRemoved = "removed"
+
+	// ParameterChangeModified indicates that the value of a parameter is different on the next run.
+	ParameterChangeModified = "modified"
+)
+
+// ParameterDiffOptions represent options for Porter's parameters diff command
+type ParameterDiffOptions struct {
+	printer.PrintOptions
+
+	// Name of the installation.
+	Name string
+
+	// Namespace of the installation.
+	Namespace string
+
+	// ParameterSets to compare against the installation. Defaults to the
+	// parameter sets already associated with the installation.
+	ParameterSets []string
+
+	// Params is the unparsed list of NAME=VALUE parameter overrides. Defaults to
+	// the parameter overrides already associated with the installation.
+	Params []string
+}
+
+// Validate the args provided to the parameters diff command
+func (o *ParameterDiffOptions) Validate(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no installation name was specified")
+	case 1:
+		o.Name = args[0]
+	default:
+		return fmt.Errorf("only one positional argument may be specified, the installation name, but multiple were received: %s", args)
+	}
+
+	return o.PrintOptions.Validate(printer.FormatPlaintext, []printer.Format{printer.FormatPlaintext, printer.FormatJson, printer.FormatYaml})
+}
+
+// ParameterDiff describes how a single parameter would change on the next run
+// of an installation.
+type ParameterDiff struct {
+	Name      string      `json:"name" yaml:"name"`
+	Type      string      `json:"type" yaml:"type"`
+	Sensitive bool        `json:"sensitive" yaml:"sensitive"`
+	Change    string      `json:"change" yaml:"change"`
+	Current   interface{} `json:"current,omitempty" yaml:"current,omitempty"`
+	Proposed  interface{} `json:"proposed,omitempty" yaml:"proposed,omitempty"`
+
+	// CurrentType is set when the type of the parameter changed since the last run.
+	CurrentType string `json:"currentType,omitempty" yaml:"currentType,omitempty"`
+}
+
+// DiffParameters compares the parameters used during the last run of an
+// installation with the parameters that would be used on the next upgrade.
+func (p *Porter) DiffParameters(ctx context.Context, opts ParameterDiffOptions) ([]ParameterDiff, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	inst, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return nil, span.Error(fmt.Errorf("could not retrieve installation %s/%s: %w", opts.Namespace, opts.Name, err))
+	}
+
+	run, err := p.Installations.GetLastRun(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound{}) {
+			return nil, span.Error(fmt.Errorf("installation %s has not been run yet so there are no recorded parameters to compare against", inst))
+		}
+		return nil, span.Error(err)
+	}
+	bun := cnab.NewBundle(run.Bundle)
+
+	current, err := p.Sanitizer.RestoreParameterSet(ctx, run.Parameters, bun)
+	if err != nil {
+		return nil, span.Error(fmt.Errorf("could not resolve the parameters from the last run of %s: %w", inst, err))
+	}
+
+	psets := inst.ParameterSets
+	if len(opts.ParameterSets) > 0 {
+		psets = opts.ParameterSets
+	}
+	resolved, err := p.loadParameterSets(ctx, bun, opts.Namespace, psets)
+	if err != nil {
+		return nil, span.Error(fmt.Errorf("unable to process provided parameter sets: %w", err))
+	}
+
+	var overrides map[string]string
+	if len(opts.Params) > 0 {
+		overrides, err = storage.ParseVariableAssignments(opts.Params)
+	} else {
+		overrides, err = p.Parameters.ResolveAll(ctx, inst.Parameters)
+	}
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	for k, v := range overrides {
+		// Parameters for dependencies are not tracked on the installation's run
+		if strings.Contains(k, "#") {
+			continue
+		}
+		resolved[k] = v
+	}
+
+	proposed, err := p.finalizeParameters(ctx, inst, bun, cnab.ActionUpgrade, resolved)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	return diffParameterValues(bun, current, proposed), nil
+}
+
+// diffParameterValues compares two sets of parameter values, ignoring internal
+// parameters, and returns the differences sorted by parameter name.
+func diffParameterValues(bun cnab.ExtendedBundle, current map[string]interface{}, proposed map[string]interface{}) []ParameterDiff {
+	names := make(map[string]struct{}, len(current)+len(proposed))
+	for name := range current {
+		names[name] = struct{}{}
+	}
+	for name := range proposed {
+		names[name] = struct{}{}
+	}
+
+	diffs := make([]ParameterDiff, 0, len(names))
+	for name := range names {
+		param, ok := bun.Parameters[name]
+		if !ok || bun.IsInternalParameter(name) {
+			continue
+		}
+
+		diff := ParameterDiff{Name: name, Type: "unknown", Sensitive: bun.IsSensitiveParameter(name)}
+		if def, ok := bun.Definitions[param.Definition]; ok {
+			diff.Type = bun.GetParameterType(def)
+		}
+
+		currentValue, hasCurrent := current[name]
+		proposedValue, hasProposed := proposed[name]
+		switch {
+		case hasCurrent && !hasProposed:
+			diff.Change = ParameterChangeRemoved
+		case !hasCurrent && hasProposed:
+			diff.Change = ParameterChangeAdded
+		case !parameterValuesEqual(currentValue, proposedValue):
+			diff.Change = ParameterChangeModified
+		default:
+			continue
+		}
+
+		if !diff.Sensitive {
+			diff.Current = currentValue
+			diff.Proposed = proposedValue
+		}
+		diffs = append(diffs, diff)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+// parameterValuesEqual compares parameter values by their json representation
+// so that equivalent values with different go types, e.g. int and float64, match.
+func parameterValuesEqual(a interface{}, b interface{}) bool {
+	if s, ok := a.([]byte); ok {
+		a = string(s)
+	}
+	if s, ok := b.([]byte); ok {
+		b = string(s)
+	}
+
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	if aErr != nil || bErr != nil {
+		return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+	}
+	return string(aData) == string(bData)
+}
+
+// PrintParametersDiff prints the parameters that would change on the next run of an installation.
+func (p *Porter) PrintParametersDiff(ctx context.Context, opts ParameterDiffOptions) error {
+	diffs, err := p.DiffParameters(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, diffs)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, diffs)
+	case printer.FormatPlaintext:
+		if len(diffs) == 0 {
+			fmt.Fprintln(p.Out, "No parameter changes detected")
+			return nil
+		}
+
+		return printParameterDiffTable(p.Out, diffs)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+// printParameterDiffTable prints a table of parameter changes, masking sensitive values.
+func printParameterDiffTable(out io.Writer, diffs []ParameterDiff) error {
+	printValue := func(value interface{}) string {
+		if value == nil {
+			return ""
+		}
+		return DisplayValue{Value: value}.PrintValue()
+	}
+	printDiffRow :=
+		func(v interface{}) []string {
+			d, ok := v.(ParameterDiff)
+			if !ok {
+				return nil
+			}
+			current, proposed := printValue(d.Current), printValue(d.Proposed)
+			if d.Sensitive {
+				// Sensitive values are never included in the diff, only indicate which side has a value
+				if d.Change != ParameterChangeAdded {
+					current = "******"
+				}
+				if d.Change != ParameterChangeRemoved {
+					proposed = "******"
+				}
+			}
+			paramType := d.Type
+			if d.CurrentType != "" {
+				paramType = fmt.Sprintf("%s -> %s", d.CurrentType, d.Type)
+			}
+			return []string{d.Name, paramType, d.Change, current, proposed}
+		}
+	return printer.PrintTable(out, diffs, printDiffRow,
+		"NAME", "TYPE", "CHANGE", "CURRENT", "PROPOSED")
+}
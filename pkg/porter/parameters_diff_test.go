@@ -0,0 +1,88 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterDiffOptions_Validate(t *testing.T) {
+	t.Run("no name", func(t *testing.T) {
+		opts := ParameterDiffOptions{}
+		err := opts.Validate(nil)
+		require.EqualError(t, err, "no installation name was specified")
+	})
+
+	t.Run("too many names", func(t *testing.T) {
+		opts := ParameterDiffOptions{}
+		err := opts.Validate([]string{"a", "b"})
+		require.ErrorContains(t, err, "only one positional argument may be specified")
+	})
+
+	t.Run("defaults format", func(t *testing.T) {
+		opts := ParameterDiffOptions{}
+		err := opts.Validate([]string{"mysql"})
+		require.NoError(t, err)
+		assert.Equal(t, "mysql", opts.Name)
+		assert.Equal(t, printer.FormatPlaintext, opts.Format)
+	})
+}
+
+func TestDiffParameterValues(t *testing.T) {
+	writeOnly := true
+	bun := cnab.NewBundle(bundle.Bundle{
+		Definitions: definition.Definitions{
+			"string":    &definition.Schema{Type: "string"},
+			"integer":   &definition.Schema{Type: "integer"},
+			"sensitive": &definition.Schema{Type: "string", WriteOnly: &writeOnly},
+			"internal":  &definition.Schema{Type: "string", Comment: cnab.PorterInternal},
+		},
+		Parameters: map[string]bundle.Parameter{
+			"name":      {Definition: "string"},
+			"replicas":  {Definition: "integer"},
+			"region":    {Definition: "string"},
+			"zone":      {Definition: "string"},
+			"password":  {Definition: "sensitive"},
+			"debug":     {Definition: "internal"},
+			"unchanged": {Definition: "string"},
+		},
+	})
+
+	current := map[string]interface{}{
+		"name":      "mysql",
+		"replicas":  float64(1),
+		"zone":      "a",
+		"password":  "oldpassword",
+		"debug":     "false",
+		"unchanged": "same",
+	}
+	proposed := map[string]interface{}{
+		"name":      "mysql",
+		"replicas":  3,
+		"region":    "eastus",
+		"password":  "newpassword",
+		"debug":     "true",
+		"unchanged": "same",
+	}
+
+	diffs := diffParameterValues(bun, current, proposed)
+	want := []ParameterDiff{
+		{Name: "password", Type: "string", Sensitive: true, Change: ParameterChangeModified},
+		{Name: "region", Type: "string", Change: ParameterChangeAdded, Proposed: "eastus"},
+		{Name: "replicas", Type: "integer", Change: ParameterChangeModified, Current: float64(1), Proposed: 3},
+		{Name: "zone", Type: "string", Change: ParameterChangeRemoved, Current: "a"},
+	}
+	assert.Equal(t, want, diffs)
+}
+
+func TestParameterValuesEqual(t *testing.T) {
+	assert.True(t, parameterValuesEqual(1, float64(1)), "numbers of different types should be equal")
+	assert.True(t, parameterValuesEqual([]byte("abc"), "abc"), "bytes should be compared as strings")
+	assert.True(t, parameterValuesEqual(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1.0}))
+	assert.False(t, parameterValuesEqual(true, "true"), "booleans and strings should not be equal")
+}
@@ -0,0 +1,350 @@
+package porter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/encoding"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// ParameterImportOptions represent options for Porter's parameters import command
+type ParameterImportOptions struct {
+	ParameterOptions
+
+	// EnvFile is the path to a .env file containing NAME=VALUE pairs to import.
+	EnvFile string
+
+	// ValuesFile is the path to a Helm values file to import.
+	ValuesFile string
+
+	// Mappings is the unparsed list of KEY=PARAMETER mappings from keys in the
+	// imported file to bundle parameters.
+	Mappings []string
+
+	// parsedMappings is the parsed set of Mappings.
+	parsedMappings map[string]string
+}
+
+// Validate prepares for the parameters import command and validates the options.
+func (o *ParameterImportOptions) Validate(ctx context.Context, args []string, p *Porter) error {
+	if o.EnvFile == "" && o.ValuesFile == "" {
+		return errors.New("either --from-env-file or --from-values must be specified")
+	}
+	if o.EnvFile != "" && o.ValuesFile != "" {
+		return errors.New("cannot specify both --from-env-file and --from-values")
+	}
+
+	mappings, err := storage.ParseVariableAssignments(o.Mappings)
+	if err != nil {
+		return fmt.Errorf("invalid --map: %w", err)
+	}
+	o.parsedMappings = mappings
+
+	return o.ParameterOptions.Validate(ctx, args, p)
+}
+
+// ParameterImportResult describes the outcome of importing parameters into a parameter set.
+type ParameterImportResult struct {
+	// Imported is the set of bundle parameter names that were set in the parameter set.
+	Imported []string
+
+	// Skipped is the set of keys in the imported file that did not match a bundle parameter.
+	Skipped []string
+}
+
+// ImportParameters creates or updates a parameter set from the values in a .env
+// or Helm values file, matching keys in the file to the bundle's parameters.
+func (p *Porter) ImportParameters(ctx context.Context, opts ParameterImportOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	bundleRef, err := opts.GetBundleReference(ctx, p)
+	if err != nil {
+		return span.Error(err)
+	}
+	bun := bundleRef.Definition
+
+	values, err := p.readImportedParameters(bun, opts)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	mapped, err := mapImportedParameters(bun, values, opts.parsedMappings)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	result := ParameterImportResult{}
+	for key := range values {
+		if _, ok := mapped[key]; !ok {
+			result.Skipped = append(result.Skipped, key)
+		}
+	}
+	sort.Strings(result.Skipped)
+
+	name := opts.Name
+	if name == "" {
+		name = bun.Name
+	}
+
+	pset, err := p.Parameters.GetParameterSet(ctx, opts.Namespace, name)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound{}) {
+			return span.Error(fmt.Errorf("could not retrieve parameter set %s: %w", name, err))
+		}
+		pset = storage.NewParameterSet(opts.Namespace, name)
+		pset.Status.Created = time.Now()
+	}
+	for k, v := range opts.ParseLabels() {
+		if pset.Labels == nil {
+			pset.Labels = make(map[string]string)
+		}
+		pset.Labels[k] = v
+	}
+
+	for _, key := range sortedKeys(mapped) {
+		paramName := mapped[key]
+		strategy := storage.ValueStrategy(paramName, values[key])
+		replaced := false
+		for i, param := range pset.Parameters {
+			if param.Name == paramName {
+				pset.Parameters[i] = strategy
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			pset.Parameters = append(pset.Parameters, strategy)
+		}
+		result.Imported = append(result.Imported, paramName)
+
+		if bun.IsSensitiveParameter(paramName) {
+			span.Warnf("Parameter %s is sensitive and was imported as a plaintext value. Edit the parameter set to resolve it from a secret instead.", paramName)
+		}
+	}
+
+	if err = pset.Validate(); err != nil {
+		return span.Error(fmt.Errorf("invalid parameter set: %w", err))
+	}
+	if err = p.Parameters.Validate(ctx, pset); err != nil {
+		return span.Error(fmt.Errorf("parameter set is invalid: %w", err))
+	}
+
+	pset.Status.Modified = time.Now()
+	if err = p.Parameters.UpsertParameterSet(ctx, pset); err != nil {
+		return span.Error(fmt.Errorf("unable to save parameter set: %w", err))
+	}
+
+	fmt.Fprintf(p.Out, "Imported %d parameter(s) into parameter set %s\n", len(result.Imported), pset)
+	for _, key := range result.Skipped {
+		fmt.Fprintf(p.Out, "  - skipped %s: no matching bundle parameter\n", key)
+	}
+	return nil
+}
+
+// readImportedParameters reads the file specified by the import options into a set of KEY=VALUE pairs.
+func (p *Porter) readImportedParameters(bun cnab.ExtendedBundle, opts ParameterImportOptions) (map[string]string, error) {
+	if opts.EnvFile != "" {
+		contents, err := p.FileSystem.ReadFile(opts.EnvFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", opts.EnvFile, err)
+		}
+		values, err := parseEnvFile(contents)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", opts.EnvFile, err)
+		}
+		return values, nil
+	}
+
+	contents, err := p.FileSystem.ReadFile(opts.ValuesFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", opts.ValuesFile, err)
+	}
+	var helmValues map[string]interface{}
+	if err = encoding.UnmarshalYaml(contents, &helmValues); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", opts.ValuesFile, err)
+	}
+	values, err := flattenHelmValues(bun, helmValues, opts.parsedMappings)
+	if err != nil {
+		return nil, fmt.Errorf("could not process %s: %w", opts.ValuesFile, err)
+	}
+	return values, nil
+}
+
+// parseEnvFile parses the contents of a .env file into a set of NAME=VALUE pairs.
+// Blank lines, comments and an optional export prefix are ignored, and values may be quoted.
+func parseEnvFile(contents []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid line %d, must be in NAME=VALUE format", lineNumber)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value on line %d: %w", lineNumber, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			// Trim trailing comments from unquoted values
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// flattenHelmValues flattens a Helm values file into dotted keys, e.g. image.tag.
+// A nested object is kept whole when its key matches a bundle parameter.
+func flattenHelmValues(bun cnab.ExtendedBundle, helmValues map[string]interface{}, mappings map[string]string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	var walk func(prefix string, node map[string]interface{}) error
+	walk = func(prefix string, node map[string]interface{}) error {
+		for key, value := range node {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			if child, ok := value.(map[string]interface{}); ok {
+				if _, isParam := matchImportedParameter(bun, key, mappings); !isParam {
+					if err := walk(key, child); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			strValue, err := formatImportedValue(value)
+			if err != nil {
+				return fmt.Errorf("could not convert the value of %s: %w", key, err)
+			}
+			values[key] = strValue
+		}
+		return nil
+	}
+	if err := walk("", helmValues); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// mapImportedParameters matches each key to a bundle parameter, returning
+// a map of key to parameter name for each key that matched.
+func mapImportedParameters(bun cnab.ExtendedBundle, values map[string]string, mappings map[string]string) (map[string]string, error) {
+	for key, paramName := range mappings {
+		if _, ok := values[key]; !ok {
+			return nil, fmt.Errorf("invalid --map %s=%s, %s was not found in the imported file", key, paramName, key)
+		}
+		if _, ok := bun.Parameters[paramName]; !ok {
+			return nil, fmt.Errorf("invalid --map %s=%s, parameter %s is not defined in the bundle", key, paramName, paramName)
+		}
+	}
+
+	mapped := make(map[string]string)
+	owners := make(map[string]string)
+	for _, key := range sortedKeys(values) {
+		paramName, ok := matchImportedParameter(bun, key, mappings)
+		if !ok {
+			continue
+		}
+		if owner, taken := owners[paramName]; taken {
+			return nil, fmt.Errorf("both %s and %s match parameter %s, use --map to select which key to import", owner, key, paramName)
+		}
+		owners[paramName] = key
+		mapped[key] = paramName
+	}
+	return mapped, nil
+}
+
+// matchImportedParameter finds the bundle parameter for a key in an imported file.
+// Explicit mappings take precedence, followed by the parameter name, the
+// parameter name ignoring case and separators, and then the environment
+// variable to which the parameter is injected.
+func matchImportedParameter(bun cnab.ExtendedBundle, key string, mappings map[string]string) (string, bool) {
+	if paramName, ok := mappings[key]; ok {
+		return paramName, true
+	}
+
+	if _, ok := bun.Parameters[key]; ok && !bun.IsInternalParameter(key) {
+		return key, true
+	}
+
+	normalize := func(value string) string {
+		return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(value))
+	}
+	normalizedKey := normalize(key)
+	paramNames := make([]string, 0, len(bun.Parameters))
+	for paramName := range bun.Parameters {
+		paramNames = append(paramNames, paramName)
+	}
+	sort.Strings(paramNames)
+	for _, paramName := range paramNames {
+		if bun.IsInternalParameter(paramName) {
+			continue
+		}
+		if normalize(paramName) == normalizedKey {
+			return paramName, true
+		}
+		param := bun.Parameters[paramName]
+		if param.Destination != nil && param.Destination.EnvironmentVariable == key {
+			return paramName, true
+		}
+	}
+	return "", false
+}
+
+// formatImportedValue converts a value from an imported file to a parameter value.
+// Objects and arrays are represented as json.
+func formatImportedValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		return string(data), err
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
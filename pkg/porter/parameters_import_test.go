@@ -0,0 +1,141 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterImportOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		opts    ParameterImportOptions
+		wantErr string
+	}{
+		{name: "no file", opts: ParameterImportOptions{}, wantErr: "either --from-env-file or --from-values must be specified"},
+		{name: "both files", opts: ParameterImportOptions{EnvFile: ".env", ValuesFile: "values.yaml"}, wantErr: "cannot specify both --from-env-file and --from-values"},
+		{name: "invalid map", opts: ParameterImportOptions{EnvFile: ".env", Mappings: []string{"DB_PASS"}}, wantErr: "invalid --map"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTestPorter(t)
+			defer p.Close()
+
+			err := tc.opts.Validate(p.RootContext, []string{"mypset"}, p.Porter)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	contents := []byte(`# database settings
+DB_HOST=localhost
+export DB_PORT = 5432
+DB_PASS="s3cr3t \"quoted\""
+DB_NAME='wordpress'
+DB_USER=admin # the admin user
+
+EMPTY=
+`)
+
+	values, err := parseEnvFile(contents)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_HOST": "localhost",
+		"DB_PORT": "5432",
+		"DB_PASS": `s3cr3t "quoted"`,
+		"DB_NAME": "wordpress",
+		"DB_USER": "admin",
+		"EMPTY":   "",
+	}, values)
+
+	_, err = parseEnvFile([]byte("DB_HOST"))
+	require.EqualError(t, err, "invalid line 1, must be in NAME=VALUE format")
+}
+
+func TestImportParameters_Mapping(t *testing.T) {
+	bun := cnab.NewBundle(bundle.Bundle{
+		Parameters: map[string]bundle.Parameter{
+			"db-host":           {Definition: "string"},
+			"mysql-password":    {Definition: "string", Destination: &bundle.Location{EnvironmentVariable: "MYSQL_PASSWORD"}},
+			"wordpress-version": {Definition: "string"},
+			"resources":         {Definition: "object"},
+			"porter-debug":      {Definition: "internal"},
+		},
+		Definitions: definition.Definitions{
+			"internal": &definition.Schema{Type: "string", Comment: cnab.PorterInternal},
+		},
+	})
+
+	t.Run("env file", func(t *testing.T) {
+		values := map[string]string{
+			"DB_HOST":        "localhost",
+			"MYSQL_PASSWORD": "s3cr3t",
+			"VERSION":        "6.0",
+			"PORTER_DEBUG":   "true",
+			"UNKNOWN":        "ignored",
+		}
+		mapped, err := mapImportedParameters(bun, values, map[string]string{"VERSION": "wordpress-version"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"DB_HOST":        "db-host",
+			"MYSQL_PASSWORD": "mysql-password",
+			"VERSION":        "wordpress-version",
+		}, mapped, "internal parameters and unknown keys should not be imported")
+	})
+
+	t.Run("helm values", func(t *testing.T) {
+		helmValues := map[string]interface{}{
+			"db": map[string]interface{}{
+				"host": "localhost",
+			},
+			"image": map[string]interface{}{
+				"tag":        "6.0",
+				"pullPolicy": "Always",
+			},
+			"resources": map[string]interface{}{
+				"cpu": 2,
+			},
+		}
+		mappings := map[string]string{"image.tag": "wordpress-version"}
+
+		values, err := flattenHelmValues(bun, helmValues, mappings)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"db.host":          "localhost",
+			"image.tag":        "6.0",
+			"image.pullPolicy": "Always",
+			"resources":        `{"cpu":2}`,
+		}, values, "objects that match a parameter should not be flattened")
+
+		mapped, err := mapImportedParameters(bun, values, mappings)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"db.host":   "db-host",
+			"image.tag": "wordpress-version",
+			"resources": "resources",
+		}, mapped)
+	})
+
+	t.Run("invalid mapping", func(t *testing.T) {
+		values := map[string]string{"VERSION": "6.0"}
+
+		_, err := mapImportedParameters(bun, values, map[string]string{"VERSION": "missing"})
+		require.EqualError(t, err, "invalid --map VERSION=missing, parameter missing is not defined in the bundle")
+
+		_, err = mapImportedParameters(bun, values, map[string]string{"TAG": "wordpress-version"})
+		require.EqualError(t, err, "invalid --map TAG=wordpress-version, TAG was not found in the imported file")
+	})
+
+	t.Run("ambiguous keys", func(t *testing.T) {
+		values := map[string]string{"DB_HOST": "localhost", "db-host": "remote"}
+
+		_, err := mapImportedParameters(bun, values, nil)
+		require.EqualError(t, err, "both DB_HOST and db-host match parameter db-host, use --map to select which key to import")
+	})
+}
@@ -2,11 +2,15 @@ package porter
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/printer"
 	"get.porter.sh/porter/pkg/secrets"
 	"get.porter.sh/porter/pkg/storage"
@@ -136,6 +140,43 @@ func Test_loadParameters_definitionNotDefined(t *testing.T) {
 	require.EqualError(t, err, "definition foo not defined in bundle")
 }
 
+func Test_loadParameters_nestedSchema(t *testing.T) {
+	t.Parallel()
+
+	r := NewTestPorter(t)
+	defer r.Close()
+
+	minSize := float64(1)
+	b := cnab.NewBundle(bundle.Bundle{
+		Definitions: definition.Definitions{
+			"nodes": &definition.Schema{
+				Type: "array",
+				Items: &definition.Schema{
+					Type: "object",
+					Properties: map[string]*definition.Schema{
+						"size": {Type: "integer", Minimum: &minSize},
+					},
+					Required: []string{"size"},
+				},
+			},
+		},
+		Parameters: map[string]bundle.Parameter{
+			"nodes": {
+				Definition: "nodes",
+			},
+		},
+	})
+
+	i := storage.Installation{}
+	params, err := r.finalizeParameters(context.Background(), i, b, "action", map[string]string{"nodes": `[{"size": 3}]`})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"size": float64(3)}}, params["nodes"])
+
+	_, err = r.finalizeParameters(context.Background(), i, b, "action", map[string]string{"nodes": `[{"size": 0}, {}]`})
+	require.ErrorContains(t, err, "invalid value for parameter nodes at /0/size: must be greater than or equal to 1")
+	require.ErrorContains(t, err, `invalid value for parameter nodes at /1: "size" value is required`)
+}
+
 func Test_loadParameters_applyTo(t *testing.T) {
 	t.Parallel()
 
@@ -293,6 +334,49 @@ func Test_loadParameters_fileParameter(t *testing.T) {
 	require.Equal(t, "SGVsbG8gV29ybGQh", params["foo"], "expected param 'foo' to be the base64-encoded file contents")
 }
 
+func Test_loadParameters_directoryParameter(t *testing.T) {
+	t.Parallel()
+
+	r := NewTestPorter(t)
+	defer r.Close()
+
+	r.TestConfig.TestContext.AddTestFile("testdata/file-param", "/path/to/dir/file-param")
+
+	b := cnab.NewBundle(bundle.Bundle{
+		RequiredExtensions: []string{
+			cnab.FileParameterExtensionKey,
+		},
+		Definitions: definition.Definitions{
+			"foo": &definition.Schema{
+				Type:            "string",
+				ContentEncoding: "base64",
+			},
+		},
+		Parameters: map[string]bundle.Parameter{
+			"foo": {
+				Definition: "foo",
+				Required:   true,
+				Destination: &bundle.Location{
+					Path: "/tmp/foo",
+				},
+			},
+		},
+	})
+
+	overrides := map[string]string{
+		"foo": "/path/to/dir",
+	}
+
+	i := storage.Installation{}
+	params, err := r.finalizeParameters(context.Background(), i, b, "action", overrides)
+	require.NoError(t, err)
+
+	require.IsType(t, "", params["foo"])
+	data, err := base64.StdEncoding.DecodeString(params["foo"].(string))
+	require.NoError(t, err, "expected param 'foo' to be base64-encoded")
+	require.True(t, cnab.IsDirectoryParameter(data), "expected param 'foo' to contain the archived directory")
+}
+
 func Test_loadParameters_ParameterSourcePrecedence(t *testing.T) {
 	t.Parallel()
 
@@ -622,6 +706,185 @@ func TestRuntime_ResolveParameterSources(t *testing.T) {
 	assert.Equal(t, want, got, "resolved incorrect parameter values")
 }
 
+func TestRuntime_ResolveParameterSources_LargeOutput(t *testing.T) {
+	t.Parallel()
+
+	r := NewTestPorter(t)
+	defer r.Close()
+	ctx := r.RootContext
+
+	r.Data.Outputs.Store = config.OutputStoreFilesystem
+	r.Data.Outputs.Path = "/outputs"
+	r.Data.Outputs.Threshold = 10
+
+	r.TestConfig.TestContext.AddTestFile("testdata/bundle-with-param-sources.json", "bundle.json")
+	bun, err := cnab.LoadBundle(r.Context, "bundle.json")
+	require.NoError(t, err, "ProcessBundle failed")
+
+	i := r.TestInstallations.CreateInstallation(storage.NewInstallation("", "mybun"))
+	c := r.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall), func(r *storage.Run) { r.Bundle = bun.Bundle })
+	cr := r.TestInstallations.CreateResult(c.NewResult(cnab.StatusSucceeded))
+
+	// The output is over the threshold, so its value is saved to the large output store
+	output, err := r.Sanitizer.CleanOutput(ctx, cr.NewOutput("bar", []byte("a value over the threshold")), bun)
+	require.NoError(t, err)
+	require.NotEmpty(t, output.Location, "the output should be saved to the large output store")
+	r.TestInstallations.CreateOutput(output)
+
+	got, err := r.resolveParameterSources(ctx, bun, i)
+	require.NoError(t, err, "resolveParameterSources failed")
+	assert.Equal(t, "a value over the threshold", got["bar"])
+}
+
+func TestRuntime_ResolveParameterSources_EncryptedOutput(t *testing.T) {
+	t.Parallel()
+
+	r := NewTestPorter(t)
+	defer r.Close()
+	ctx := r.RootContext
+	require.NoError(t, r.Storage.WriteSchema(ctx), "failed to set the storage schema")
+	r.Data.Encryption.Key = "my-secret-key"
+
+	r.TestConfig.TestContext.AddTestFile("testdata/bundle-with-param-sources.json", "bundle.json")
+	bun, err := cnab.LoadBundle(r.Context, "bundle.json")
+	require.NoError(t, err, "ProcessBundle failed")
+
+	i := r.TestInstallations.CreateInstallation(storage.NewInstallation("", "mybun"))
+	c := r.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall), func(r *storage.Run) { r.Bundle = bun.Bundle })
+	cr := r.TestInstallations.CreateResult(c.NewResult(cnab.StatusSucceeded))
+
+	// Encrypted outputs are saved with the ciphertext as the value, and without a key in the secret store
+	encrypted, err := r.Encryptor.Encrypt(ctx, "bar value")
+	require.NoError(t, err)
+	r.TestInstallations.CreateOutput(cr.NewOutput("bar", []byte(encrypted)), func(o *storage.Output) { o.Encrypted = true })
+
+	got, err := r.resolveParameterSources(ctx, bun, i)
+	require.NoError(t, err, "resolveParameterSources failed")
+	assert.Equal(t, "bar value", got["bar"])
+}
+
+func TestPorter_loadParameterSets_Inherits(t *testing.T) {
+	t.Parallel()
+
+	secretStrategy := func(name string, secret string) secrets.Strategy {
+		return secrets.Strategy{Name: name, Source: secrets.Source{Key: secrets.SourceSecret, Value: secret}}
+	}
+
+	t.Run("merge parents", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		ctx := context.Background()
+
+		p.TestParameters.AddSecret("base-region", "eastus")
+		p.TestParameters.AddSecret("base-size", "small")
+		p.TestParameters.AddSecret("prod-size", "large")
+		p.TestParameters.AddSecret("override-replicas", "3")
+
+		base := storage.NewParameterSet("", "base",
+			secretStrategy("region", "base-region"),
+			secretStrategy("size", "base-size"))
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, base))
+
+		prod := storage.NewParameterSet("dev", "prod", secretStrategy("size", "prod-size"))
+		prod.Inherits = []string{"base"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, prod))
+
+		override := storage.NewParameterSet("dev", "override", secretStrategy("replicas", "override-replicas"))
+		override.Inherits = []string{"prod"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, override))
+
+		got, err := p.loadParameterSets(ctx, cnab.ExtendedBundle{}, "dev", []string{"override"})
+		require.NoError(t, err)
+		want := secrets.Set{
+			"region":   "eastus",
+			"size":     "large",
+			"replicas": "3",
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		ctx := context.Background()
+
+		ps := storage.NewParameterSet("dev", "orphan")
+		ps.Inherits = []string{"missing"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, ps))
+
+		_, err := p.loadParameterSets(ctx, cnab.ExtendedBundle{}, "dev", []string{"orphan"})
+		require.ErrorIs(t, err, storage.ErrNotFound{})
+		require.Contains(t, err.Error(), "could not find parameter set missing inherited by orphan")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewTestPorter(t)
+		defer p.Close()
+		ctx := context.Background()
+
+		a := storage.NewParameterSet("dev", "a")
+		a.Inherits = []string{"b"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, a))
+
+		b := storage.NewParameterSet("dev", "b")
+		b.Inherits = []string{"c"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, b))
+
+		c := storage.NewParameterSet("", "c")
+		c.Inherits = []string{"a"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, c))
+
+		_, err := p.loadParameterSets(ctx, cnab.ExtendedBundle{}, "dev", []string{"a"})
+		require.EqualError(t, err, "parameter set inheritance cycle detected: a -> b -> c -> a")
+	})
+}
+
+func TestParametersEdit(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		ctx := context.Background()
+
+		p.Setenv("SHELL", "bash")
+		p.Setenv("EDITOR", "vi")
+		p.Setenv(test.ExpectedCommandEnv, "bash -c vi "+filepath.Join(os.TempDir(), "porter-mypset.yaml"))
+
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, storage.NewParameterSet("dev", "mypset")))
+
+		opts := ParameterEditOptions{Namespace: "dev", Name: "mypset"}
+		err := p.EditParameterSet(ctx, opts)
+		require.NoError(t, err, "no error should have existed")
+	})
+
+	t.Run("inheritance cycle", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		ctx := context.Background()
+
+		p.Setenv("SHELL", "bash")
+		p.Setenv("EDITOR", "vi")
+		p.Setenv(test.ExpectedCommandEnv, "bash -c vi "+filepath.Join(os.TempDir(), "porter-a.yaml"))
+
+		a := storage.NewParameterSet("dev", "a")
+		a.Inherits = []string{"b"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, a))
+
+		b := storage.NewParameterSet("dev", "b")
+		b.Inherits = []string{"a"}
+		require.NoError(t, p.TestParameters.InsertParameterSet(ctx, b))
+
+		opts := ParameterEditOptions{Namespace: "dev", Name: "a"}
+		err := p.EditParameterSet(ctx, opts)
+		require.EqualError(t, err, "parameter set is invalid: parameter set inheritance cycle detected: a -> b -> a")
+	})
+}
+
 func TestShowParameters_NotFound(t *testing.T) {
 	p := NewTestPorter(t)
 	defer p.Close()
@@ -0,0 +1,74 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// PauseOptions represent options for Porter's installation pause and resume commands
+type PauseOptions struct {
+	installationOptions
+}
+
+// Validate prepares for an installation pause or resume action and validates the args/options.
+func (o *PauseOptions) Validate(args []string, cxt *portercontext.Context) error {
+	// Ensure only one argument exists (installation name) if args length non-zero
+	err := o.installationOptions.validateInstallationName(args)
+	if err != nil {
+		return err
+	}
+
+	return o.installationOptions.defaultBundleFiles(cxt)
+}
+
+// PauseInstallation suspends reconciliation of an installation, so that
+// applying changes to the installation does not execute the bundle.
+func (p *Porter) PauseInstallation(ctx context.Context, opts PauseOptions) error {
+	return p.setInstallationSuspended(ctx, opts, true)
+}
+
+// ResumeInstallation resumes reconciliation of a paused installation.
+func (p *Porter) ResumeInstallation(ctx context.Context, opts PauseOptions) error {
+	return p.setInstallationSuspended(ctx, opts, false)
+}
+
+func (p *Porter) setInstallationSuspended(ctx context.Context, opts PauseOptions, suspended bool) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	err := p.applyDefaultOptions(ctx, &opts.installationOptions)
+	if err != nil {
+		return err
+	}
+
+	installation, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return span.Error(fmt.Errorf("could not retrieve installation %s/%s: %w", opts.Namespace, opts.Name, err))
+	}
+
+	if installation.Status.Suspended == suspended {
+		if suspended {
+			span.Infof("Installation %s is already paused", installation)
+		} else {
+			span.Infof("Installation %s is not paused", installation)
+		}
+		return nil
+	}
+
+	installation.Status.Suspended = suspended
+	installation.Status.Modified = time.Now()
+	if err = p.Installations.UpdateInstallation(ctx, installation); err != nil {
+		return span.Error(fmt.Errorf("could not save installation %s: %w", installation, err))
+	}
+
+	if suspended {
+		span.Infof("Paused installation %s", installation)
+	} else {
+		span.Infof("Resumed installation %s", installation)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"get.porter.sh/porter/pkg/storage"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+// pickerPageSize is the number of options displayed at once by the picker.
+const pickerPageSize = 10
+
+// pickFunc asks the user to pick one of the options.
+type pickFunc func(message string, options []string) (string, error)
+
+// canPick determines if Porter can ask the user to pick the name of a
+// resource, which requires an interactive terminal.
+func (p *Porter) canPick() bool {
+	return p.pick != nil || (isTerminal(p.In) && isTerminal(p.Out))
+}
+
+// pickName asks the user to pick the name of a resource.
+func (p *Porter) pickName(kind string, names []string) (string, error) {
+	if len(names) == 0 {
+		return "", fmt.Errorf("there are no %ss to choose from", kind)
+	}
+
+	pick := p.pick
+	if pick == nil {
+		pick = surveyPick
+	}
+
+	sort.Strings(names)
+	return pick(fmt.Sprintf("Select a %s:", kind), names)
+}
+
+// pickCredentialSetName asks the user to pick a credential set in the
+// namespace, when the name was not specified. An error is returned when
+// Porter is not running in an interactive terminal.
+func (p *Porter) pickCredentialSetName(ctx context.Context, namespace string) (string, error) {
+	if !p.canPick() {
+		return "", errors.New("no credential name was specified")
+	}
+
+	sets, err := p.Credentials.ListCredentialSets(ctx, storage.ListOptions{Namespace: namespace})
+	if err != nil {
+		return "", fmt.Errorf("could not list the credential sets to choose from: %w", err)
+	}
+
+	names := make([]string, len(sets))
+	for i, cs := range sets {
+		names[i] = cs.Name
+	}
+	return p.pickName("credential set", names)
+}
+
+// pickInstallationName asks the user to pick an installation in the
+// namespace, when the name was not specified. An error is returned when
+// Porter is not running in an interactive terminal.
+func (p *Porter) pickInstallationName(ctx context.Context, namespace string) (string, error) {
+	if !p.canPick() {
+		return "", errors.New("no installation name was specified")
+	}
+
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{Namespace: namespace})
+	if err != nil {
+		return "", fmt.Errorf("could not list the installations to choose from: %w", err)
+	}
+
+	names := make([]string, len(installations))
+	for i, inst := range installations {
+		names[i] = inst.Name
+	}
+	return p.pickName("installation", names)
+}
+
+// surveyPick prompts the user to pick one of the options, filtering the
+// options with a fuzzy search as the user types.
+func surveyPick(message string, options []string) (string, error) {
+	var answer string
+	prompt := &survey.Select{
+		Message:  message,
+		Options:  options,
+		PageSize: pickerPageSize,
+		FilterFn: fuzzyFilter,
+	}
+	if err := survey.AskOne(prompt, &answer, nil); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+// fuzzyFilter returns the options that match the filter, see fuzzyMatch.
+func fuzzyFilter(filter string, options []string) []string {
+	var matches []string
+	for _, option := range options {
+		if fuzzyMatch(filter, option) {
+			matches = append(matches, option)
+		}
+	}
+	return matches
+}
+
+// fuzzyMatch determines if the characters of the filter appear in the value
+// in the same order, ignoring case and whitespace. For example, "wp" matches
+// "wordpress".
+func fuzzyMatch(filter string, value string) bool {
+	var remaining []rune
+	for _, r := range strings.ToLower(filter) {
+		if !unicode.IsSpace(r) {
+			remaining = append(remaining, r)
+		}
+	}
+
+	for _, r := range strings.ToLower(value) {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
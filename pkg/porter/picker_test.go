@@ -0,0 +1,78 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	testcases := []struct {
+		filter string
+		value  string
+		want   bool
+	}{
+		{"", "wordpress", true},
+		{"wp", "wordpress", true},
+		{"WP", "wordpress", true},
+		{"word press", "wordpress", true},
+		{"pw", "wordpress", false},
+		{"mysqlx", "mysql", false},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.want, fuzzyMatch(tc.filter, tc.value), "unexpected result matching %q with %q", tc.filter, tc.value)
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	got := fuzzyFilter("db", []string{"mydb", "wordpress", "dashboard", "azure-db"})
+	assert.Equal(t, []string{"mydb", "dashboard", "azure-db"}, got)
+}
+
+func TestPorter_pickName(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	var gotMessage string
+	var gotOptions []string
+	p.pick = func(message string, options []string) (string, error) {
+		gotMessage = message
+		gotOptions = options
+		return options[0], nil
+	}
+
+	name, err := p.pickName("installation", []string{"wordpress", "mysql"})
+	require.NoError(t, err)
+	assert.Equal(t, "mysql", name)
+	assert.Equal(t, "Select a installation:", gotMessage)
+	assert.Equal(t, []string{"mysql", "wordpress"}, gotOptions, "the options should be sorted")
+
+	_, err = p.pickName("installation", nil)
+	assert.EqualError(t, err, "there are no installations to choose from")
+}
+
+func TestPorter_ShowCredential_NameNotSpecified(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := CredentialShowOptions{}
+	opts.RawFormat = string(printer.FormatPlaintext)
+	require.NoError(t, opts.Validate(nil), "the name should be optional")
+
+	// The test output is not a terminal, so the user cannot be prompted
+	err := p.ShowCredential(context.Background(), opts)
+	assert.EqualError(t, err, "no credential name was specified")
+}
+
+func TestPorter_ShowInstallation_NameNotSpecified(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	// The test output is not a terminal, so the user cannot be prompted
+	err := p.ShowInstallation(context.Background(), ShowOptions{})
+	assert.EqualError(t, err, "no installation name was specified")
+}
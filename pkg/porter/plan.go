@@ -0,0 +1,271 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"get.porter.sh/porter/pkg/cnab"
+	configadapter "get.porter.sh/porter/pkg/cnab/config-adapter"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// ExecutionPlan describes the bundles that an action would execute, in the
+// order that they would be executed, without executing them.
+type ExecutionPlan struct {
+	// Namespace of the installation.
+	Namespace string `json:"namespace" yaml:"namespace"`
+
+	// Installation name.
+	Installation string `json:"installation" yaml:"installation"`
+
+	// Action to execute, e.g. install or upgrade.
+	Action string `json:"action" yaml:"action"`
+
+	// Actions that would be executed, first for each dependency and then for the bundle.
+	Actions []PlannedAction `json:"actions" yaml:"actions"`
+}
+
+// PlannedAction is an action that would be executed against a single bundle.
+type PlannedAction struct {
+	// Installation that the action is executed against.
+	Installation string `json:"installation" yaml:"installation"`
+
+	// Dependency is the alias of the dependency, when the bundle is a dependency of the installation.
+	Dependency string `json:"dependency,omitempty" yaml:"dependency,omitempty"`
+
+	// SharingGroup is the sharing group of a dependency that may be satisfied by an existing installation.
+	SharingGroup string `json:"sharingGroup,omitempty" yaml:"sharingGroup,omitempty"`
+
+	// Skipped explains why the action would not be executed, for example because
+	// a shared dependency is satisfied by an existing installation.
+	Skipped string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+
+	// Bundle reference.
+	Bundle string `json:"bundle" yaml:"bundle"`
+
+	// Images that would be pulled to execute the bundle.
+	Images []string `json:"images" yaml:"images"`
+
+	// Parameters passed to the bundle, with sensitive values masked.
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	// Credentials passed to the bundle.
+	Credentials []string `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+
+	// Steps of the action, in the order that they are executed. Only available
+	// for bundles built by Porter.
+	Steps []PlannedStep `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+// PlannedStep is a step that would be executed by the bundle.
+type PlannedStep struct {
+	// Description of the step.
+	Description string `json:"description" yaml:"description"`
+
+	// Mixin that executes the step.
+	Mixin string `json:"mixin" yaml:"mixin"`
+}
+
+// planAction resolves the bundle, its dependencies, parameters and credentials
+// for the action, and returns what would be executed without executing it.
+func (p *Porter) planAction(ctx context.Context, installation storage.Installation, action BundleAction) (ExecutionPlan, error) {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	deperator := newDependencyExecutioner(p, installation, action)
+	if err := deperator.Prepare(ctx); err != nil {
+		return ExecutionPlan{}, err
+	}
+
+	plan := ExecutionPlan{
+		Namespace:    installation.Namespace,
+		Installation: installation.Name,
+		Action:       action.GetAction(),
+		Actions:      make([]PlannedAction, 0, len(deperator.deps)+1),
+	}
+
+	for _, dep := range deperator.deps {
+		planned, err := p.planBundle(ctx, dep.BundleReference, action.GetAction())
+		if err != nil {
+			return ExecutionPlan{}, log.Error(fmt.Errorf("could not plan the %s action for dependency %s: %w", action.GetAction(), dep.Alias, err))
+		}
+		planned.Installation = dep.Installation.Name
+		planned.Dependency = dep.Alias
+		planned.SharingGroup = dep.SharingGroup
+		if dep.Existing {
+			planned.Skipped = fmt.Sprintf("the dependency is satisfied by the existing installation %s in sharing group %s", dep.Installation.Name, dep.SharingGroup)
+		} else if dep.IsShared() && action.GetAction() == cnab.ActionUninstall {
+			planned.Skipped = fmt.Sprintf("the dependency is shared by sharing group %s", dep.SharingGroup)
+		}
+
+		// Outputs of dependencies that have not been executed yet are shown as templates
+		depParams, err := deperator.resolveDependencyParameters(ctx, dep, true)
+		if err != nil {
+			return ExecutionPlan{}, err
+		}
+		depBun := dep.BundleReference.Definition
+		for name, value := range depParams {
+			if planned.Parameters == nil {
+				planned.Parameters = make(map[string]string, len(depParams))
+			}
+			if depBun.IsSensitiveParameter(name) {
+				value = "******"
+			}
+			planned.Parameters[name] = value
+		}
+		plan.Actions = append(plan.Actions, planned)
+	}
+
+	args, err := deperator.PrepareRootActionArguments(ctx)
+	if err != nil {
+		return ExecutionPlan{}, err
+	}
+
+	planned, err := p.planBundle(ctx, args.BundleReference, action.GetAction())
+	if err != nil {
+		return ExecutionPlan{}, log.Error(fmt.Errorf("could not plan the %s action: %w", action.GetAction(), err))
+	}
+	planned.Installation = installation.Name
+
+	bun := args.BundleReference.Definition
+	planned.Parameters = make(map[string]string, len(args.Params))
+	for name, value := range args.Params {
+		if bun.IsInternalParameter(name) {
+			continue
+		}
+		if bun.IsSensitiveParameter(name) {
+			planned.Parameters[name] = "******"
+		} else {
+			planned.Parameters[name] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	creds, err := p.CNAB.ResolveCredentials(ctx, args)
+	if err != nil {
+		return ExecutionPlan{}, log.Error(fmt.Errorf("could not resolve the credentials: %w", err))
+	}
+	planned.Credentials = make([]string, 0, len(creds))
+	for name := range creds {
+		planned.Credentials = append(planned.Credentials, name)
+	}
+	sort.Strings(planned.Credentials)
+
+	plan.Actions = append(plan.Actions, planned)
+	return plan, nil
+}
+
+// planBundle determines the images and steps that a bundle executes for an action.
+func (p *Porter) planBundle(ctx context.Context, bundleRef cnab.BundleReference, action string) (PlannedAction, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	bun := bundleRef.Definition
+	planned := PlannedAction{
+		Bundle: bundleRef.Reference.String(),
+		Images: make([]string, 0, len(bun.InvocationImages)+len(bun.Images)),
+	}
+
+	relocate := func(image string) string {
+		if relocated, ok := bundleRef.RelocationMap[image]; ok {
+			return relocated
+		}
+		return image
+	}
+	for _, ii := range bun.InvocationImages {
+		planned.Images = append(planned.Images, relocate(ii.Image))
+	}
+	imageNames := make([]string, 0, len(bun.Images))
+	for name := range bun.Images {
+		imageNames = append(imageNames, name)
+	}
+	sort.Strings(imageNames)
+	for _, name := range imageNames {
+		planned.Images = append(planned.Images, relocate(bun.Images[name].Image))
+	}
+
+	// The steps are only known for bundles built by Porter, which embed their porter.yaml
+	if !bun.IsPorterBundle() {
+		return planned, nil
+	}
+	stamp, err := configadapter.LoadStamp(bun)
+	if err != nil {
+		return PlannedAction{}, err
+	}
+	data, err := stamp.DecodeManifest()
+	if err != nil {
+		log.Debugf("Could not determine the steps of the bundle: %s", err)
+		return planned, nil
+	}
+	m, err := manifest.UnmarshalManifest(p.Context, data)
+	if err != nil {
+		return PlannedAction{}, err
+	}
+
+	for _, step := range m.GetSteps(action) {
+		if step == nil {
+			continue
+		}
+		description, _ := step.GetDescription()
+		planned.Steps = append(planned.Steps, PlannedStep{
+			Description: description,
+			Mixin:       step.GetMixinName(),
+		})
+	}
+	return planned, nil
+}
+
+// printExecutionPlan prints what an action would execute.
+func (p *Porter) printExecutionPlan(plan ExecutionPlan) {
+	fmt.Fprintf(p.Out, "Dry run of %s for installation %s/%s, nothing was executed.\n", plan.Action, plan.Namespace, plan.Installation)
+
+	for i, planned := range plan.Actions {
+		fmt.Fprintln(p.Out)
+		if planned.Dependency != "" {
+			fmt.Fprintf(p.Out, "%d. %s dependency %s (installation %s)\n", i+1, plan.Action, planned.Dependency, planned.Installation)
+		} else {
+			fmt.Fprintf(p.Out, "%d. %s installation %s\n", i+1, plan.Action, planned.Installation)
+		}
+		fmt.Fprintf(p.Out, "   Bundle: %s\n", planned.Bundle)
+		if planned.SharingGroup != "" {
+			fmt.Fprintf(p.Out, "   Sharing Group: %s\n", planned.SharingGroup)
+		}
+		if planned.Skipped != "" {
+			fmt.Fprintf(p.Out, "   Skipped: %s\n", planned.Skipped)
+			continue
+		}
+
+		fmt.Fprintln(p.Out, "   Images:")
+		for _, image := range planned.Images {
+			fmt.Fprintf(p.Out, "     - %s\n", image)
+		}
+
+		if len(planned.Parameters) > 0 {
+			names := make([]string, 0, len(planned.Parameters))
+			for name := range planned.Parameters {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			fmt.Fprintln(p.Out, "   Parameters:")
+			for _, name := range names {
+				fmt.Fprintf(p.Out, "     %s: %s\n", name, planned.Parameters[name])
+			}
+		}
+
+		if len(planned.Credentials) > 0 {
+			fmt.Fprintln(p.Out, "   Credentials:")
+			for _, name := range planned.Credentials {
+				fmt.Fprintf(p.Out, "     - %s\n", name)
+			}
+		}
+
+		if len(planned.Steps) > 0 {
+			fmt.Fprintln(p.Out, "   Steps:")
+			for j, step := range planned.Steps {
+				fmt.Fprintf(p.Out, "     %d. %s (%s)\n", j+1, step.Description, step.Mixin)
+			}
+		}
+	}
+}
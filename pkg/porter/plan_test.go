@@ -0,0 +1,120 @@
+package porter
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_planBundle(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := context.Background()
+
+	porterYaml := `name: mysql
+install:
+  - helm3:
+      description: Install MySQL
+  - exec:
+      description: Seed the database
+upgrade:
+  - helm3:
+      description: Upgrade MySQL
+`
+	ref := cnab.MustParseOCIReference("example.com/mysql:v1.0.0")
+	bundleRef := cnab.BundleReference{
+		Reference: ref,
+		Definition: cnab.NewBundle(bundle.Bundle{
+			InvocationImages: []bundle.InvocationImage{
+				{BaseImage: bundle.BaseImage{Image: "example.com/mysql-installer:v1.0.0"}},
+			},
+			Images: map[string]bundle.Image{
+				"mysql": {BaseImage: bundle.BaseImage{Image: "docker.io/library/mysql:5.7"}},
+			},
+			Custom: map[string]interface{}{
+				config.CustomPorterKey: map[string]interface{}{
+					"manifest": base64.StdEncoding.EncodeToString([]byte(porterYaml)),
+				},
+			},
+		}),
+		RelocationMap: map[string]string{
+			"docker.io/library/mysql:5.7": "example.com/mysql@sha256:9f6b9c5f2a1b2a1a9f6b9c5f2a1b2a1a9f6b9c5f2a1b2a1a9f6b9c5f2a1b2a1a",
+		},
+	}
+
+	planned, err := p.planBundle(ctx, bundleRef, cnab.ActionInstall)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/mysql:v1.0.0", planned.Bundle)
+	assert.Equal(t, []string{
+		"example.com/mysql-installer:v1.0.0",
+		"example.com/mysql@sha256:9f6b9c5f2a1b2a1a9f6b9c5f2a1b2a1a9f6b9c5f2a1b2a1a9f6b9c5f2a1b2a1a",
+	}, planned.Images, "the relocated images should be planned")
+	assert.Equal(t, []PlannedStep{
+		{Description: "Install MySQL", Mixin: "helm3"},
+		{Description: "Seed the database", Mixin: "exec"},
+	}, planned.Steps)
+
+	t.Run("not a porter bundle", func(t *testing.T) {
+		bundleRef := cnab.BundleReference{Reference: ref, Definition: cnab.NewBundle(bundle.Bundle{})}
+		planned, err := p.planBundle(ctx, bundleRef, cnab.ActionInstall)
+		require.NoError(t, err)
+		assert.Empty(t, planned.Steps, "the steps are unknown for bundles that were not built by Porter")
+	})
+}
+
+func TestPorter_printExecutionPlan(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.printExecutionPlan(ExecutionPlan{
+		Namespace:    "dev",
+		Installation: "wordpress",
+		Action:       cnab.ActionInstall,
+		Actions: []PlannedAction{
+			{
+				Installation: "wordpress-mysql",
+				Dependency:   "mysql",
+				Bundle:       "example.com/mysql:v1.0.0",
+				Images:       []string{"example.com/mysql-installer:v1.0.0"},
+				Steps:        []PlannedStep{{Description: "Install MySQL", Mixin: "helm3"}},
+			},
+			{
+				Installation: "wordpress",
+				Bundle:       "example.com/wordpress:v1.0.0",
+				Images:       []string{"example.com/wordpress-installer:v1.0.0"},
+				Parameters:   map[string]string{"password": "******", "replicas": "2"},
+				Credentials:  []string{"kubeconfig"},
+				Steps:        []PlannedStep{{Description: "Install WordPress", Mixin: "helm3"}},
+			},
+		},
+	})
+
+	want := `Dry run of install for installation dev/wordpress, nothing was executed.
+
+1. install dependency mysql (installation wordpress-mysql)
+   Bundle: example.com/mysql:v1.0.0
+   Images:
+     - example.com/mysql-installer:v1.0.0
+   Steps:
+     1. Install MySQL (helm3)
+
+2. install installation wordpress
+   Bundle: example.com/wordpress:v1.0.0
+   Images:
+     - example.com/wordpress-installer:v1.0.0
+   Parameters:
+     password: ******
+     replicas: 2
+   Credentials:
+     - kubeconfig
+   Steps:
+     1. Install WordPress (helm3)
+`
+	assert.Equal(t, want, p.TestConfig.TestContext.GetOutput())
+}
@@ -2,16 +2,20 @@ package porter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"path/filepath"
+	"strconv"
 
 	"get.porter.sh/porter/pkg/encoding"
 	"get.porter.sh/porter/pkg/pkgmgmt"
 	"get.porter.sh/porter/pkg/plugins"
+	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
+	secretsplugins "get.porter.sh/porter/pkg/secrets/plugins"
+	storageplugins "get.porter.sh/porter/pkg/storage/plugins"
 	"get.porter.sh/porter/pkg/tracing"
-	"github.com/olekukonko/tablewriter"
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap/zapcore"
 )
@@ -28,7 +32,8 @@ type ShowPluginOptions struct {
 }
 
 func (o *ShowPluginOptions) Validate(args []string) error {
-	err := o.validateName(args)
+	var err error
+	o.Name, err = validatePackageName(args)
 	if err != nil {
 		return err
 	}
@@ -36,18 +41,14 @@ func (o *ShowPluginOptions) Validate(args []string) error {
 	return o.ParseFormat()
 }
 
-// validateName grabs the name from the first positional argument.
-func (o *ShowPluginOptions) validateName(args []string) error {
-	switch len(args) {
-	case 0:
-		return fmt.Errorf("no name was specified")
-	case 1:
-		o.Name = strings.ToLower(args[0])
-		return nil
-	default:
-		return fmt.Errorf("only one positional argument may be specified, the name, but multiple were received: %s", args)
+// DisplayPlugin is the details of an installed plugin printed by porter plugins show.
+type DisplayPlugin struct {
+	plugins.Metadata    `yaml:",inline"`
+	pkgmgmt.InstallInfo `yaml:",inline"`
 
-	}
+	// ProtocolVersions is the version of the plugin protocol that Porter uses
+	// for each type of plugin implemented by the plugin, e.g. storage.
+	ProtocolVersions map[string]int `json:"protocolVersions" yaml:"protocolVersions"`
 }
 
 func (p *Porter) PrintPlugins(ctx context.Context, opts PrintPluginsOptions) error {
@@ -101,32 +102,21 @@ func (p *Porter) ListPlugins(ctx context.Context) ([]plugins.Metadata, error) {
 }
 
 func (p *Porter) ShowPlugin(ctx context.Context, opts ShowPluginOptions) error {
-	plugin, err := p.GetPlugin(ctx, opts.Name)
+	plugin, err := p.GetPluginDetails(ctx, opts.Name)
 	if err != nil {
 		return err
 	}
 
 	switch opts.Format {
 	case printer.FormatPlaintext:
-		// Build and configure our tablewriter
-		// TODO: make this a function and reuse it in printer/table.go
-		table := tablewriter.NewWriter(p.Out)
-		table.SetCenterSeparator("")
-		table.SetColumnSeparator("")
-		table.SetAlignment(tablewriter.ALIGN_LEFT)
-		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-		table.SetBorders(tablewriter.Border{Left: false, Right: false, Bottom: false, Top: true})
-		table.SetAutoFormatHeaders(false)
-
 		// First, print the plugin metadata
-		fmt.Fprintf(p.Out, "Name: %s\n", plugin.Name)
-		fmt.Fprintf(p.Out, "Version: %s\n", plugin.Version)
-		fmt.Fprintf(p.Out, "Commit: %s\n", plugin.Commit)
-		fmt.Fprintf(p.Out, "Author: %s\n\n", plugin.Author)
+		printPackageDetails(p.Out, plugin.Metadata.Metadata, plugin.InstallInfo)
+		fmt.Fprintln(p.Out)
 
-		table.SetHeader([]string{"Type", "Implementation"})
+		table := newDetailsTable(p.Out)
+		table.SetHeader([]string{"Type", "Implementation", "Protocol Version"})
 		for _, row := range plugin.Implementations {
-			table.Append([]string{row.Type, row.Name})
+			table.Append([]string{row.Type, row.Name, strconv.Itoa(plugin.ProtocolVersions[row.Type])})
 		}
 		table.Render()
 		return nil
@@ -140,6 +130,36 @@ func (p *Porter) ShowPlugin(ctx context.Context, opts ShowPluginOptions) error {
 	}
 }
 
+// GetPluginDetails returns the metadata of an installed plugin, where it was
+// installed from, and the plugin protocol versions used to communicate with it.
+func (p *Porter) GetPluginDetails(ctx context.Context, name string) (DisplayPlugin, error) {
+	plugin, err := p.GetPlugin(ctx, name)
+	if err != nil {
+		return DisplayPlugin{}, err
+	}
+
+	info, err := p.Plugins.GetInstallInfo(name)
+	if err != nil {
+		return DisplayPlugin{}, err
+	}
+
+	protocolVersions := make(map[string]int)
+	for _, impl := range plugin.Implementations {
+		switch impl.Type {
+		case secretsplugins.PluginInterface:
+			protocolVersions[impl.Type] = secretsplugins.PluginProtocolVersion
+		case storageplugins.PluginInterface:
+			protocolVersions[impl.Type] = storageplugins.PluginProtocolVersion
+		}
+	}
+
+	return DisplayPlugin{
+		Metadata:         *plugin,
+		InstallInfo:      info,
+		ProtocolVersions: protocolVersions,
+	}, nil
+}
+
 func (p *Porter) GetPlugin(ctx context.Context, name string) (*plugins.Metadata, error) {
 	meta, err := p.Plugins.GetMetadata(ctx, name)
 	if err != nil {
@@ -174,7 +194,7 @@ func (p *Porter) InstallPlugin(ctx context.Context, opts plugins.InstallOptions)
 		}
 
 		v := plugin.GetVersionInfo()
-		fmt.Fprintf(p.Out, "installed %s plugin %s (%s)\n", opt.Name, v.Version, v.Commit)
+		p.printInfof("installed %s plugin %s (%s)\n", opt.Name, v.Version, v.Commit)
 	}
 
 	return nil
@@ -186,7 +206,7 @@ func (p *Porter) UninstallPlugin(ctx context.Context, opts pkgmgmt.UninstallOpti
 		return err
 	}
 
-	fmt.Fprintf(p.Out, "Uninstalled %s plugin", opts.Name)
+	p.printInfof("Uninstalled %s plugin", opts.Name)
 
 	return nil
 }
@@ -217,12 +237,15 @@ func (p *Porter) getPluginInstallOptions(ctx context.Context, opts plugins.Insta
 		for _, config := range sortedCfgs.Values() {
 			// if user specified a feed url or mirror using the flags, it will become
 			// the default value and apply to empty values parsed from the provided file
-			if config.FeedURL == "" {
+			if config.FeedURL == "" && config.Source == "" {
 				config.FeedURL = opts.FeedURL
 			}
 			if config.Mirror == "" {
 				config.Mirror = opts.Mirror
 			}
+			if !config.InsecureRegistry {
+				config.InsecureRegistry = opts.InsecureRegistry
+			}
 
 			if err := config.Validate([]string{config.Name}); err != nil {
 				return nil, err
@@ -237,3 +260,53 @@ func (p *Porter) getPluginInstallOptions(ctx context.Context, opts plugins.Insta
 	installConfigs = append(installConfigs, opts.InstallOptions)
 	return installConfigs, nil
 }
+
+// PluginsCreateOptions represent options for Porter's plugin create command
+type PluginsCreateOptions struct {
+	PluginName     string
+	PluginType     string
+	AuthorName     string
+	AuthorUsername string
+	ModulePath     string
+	DirPath        string
+}
+
+func (o *PluginsCreateOptions) Validate(args []string, cxt *portercontext.Context) error {
+	if len(args) < 1 || args[0] == "" {
+		return errors.New("plugin name is required")
+	}
+
+	if len(args) > 1 {
+		return fmt.Errorf("only one positional argument may be specified, the plugin name, but multiple were received: %s", args)
+	}
+
+	o.PluginName = args[0]
+
+	switch o.PluginType {
+	case secretsplugins.PluginInterface, storageplugins.PluginInterface:
+	case "":
+		return errors.New("must provide a value for flag --type")
+	default:
+		return fmt.Errorf("invalid --type %s, allowed values are: %s, %s", o.PluginType, secretsplugins.PluginInterface, storageplugins.PluginInterface)
+	}
+
+	return validateScaffoldOptions(cxt, "plugin", o.PluginName, o.AuthorName, o.AuthorUsername, &o.ModulePath, &o.DirPath)
+}
+
+// CreatePlugin generates a new secrets or storage plugin project, with an
+// example implementation, unit tests, and magefile targets to build, install
+// and publish it.
+func (p *Porter) CreatePlugin(opts PluginsCreateOptions) error {
+	dest := filepath.Join(opts.DirPath, opts.PluginName)
+	data := newScaffoldData(opts.PluginName, opts.AuthorName, opts.ModulePath)
+	data.Type = opts.PluginType
+	data.Implementation = data.Package
+	data.Key = fmt.Sprintf("%s.%s", data.Name, data.Implementation)
+	if err := p.renderScaffold(dest, data, "plugin", "plugin-"+opts.PluginType); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(p.Out, "Created %s %s plugin\n", opts.PluginName, opts.PluginType)
+
+	return nil
+}
@@ -0,0 +1,336 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/plugins"
+	"get.porter.sh/porter/pkg/plugins/pluggable"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/secrets"
+	secretsplugins "get.porter.sh/porter/pkg/secrets/plugins"
+	secretspluginstore "get.porter.sh/porter/pkg/secrets/pluginstore"
+	"get.porter.sh/porter/pkg/storage"
+	storageplugins "get.porter.sh/porter/pkg/storage/plugins"
+	storagepluginstore "get.porter.sh/porter/pkg/storage/pluginstore"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// PluginHealthOK indicates that the plugin started and passed its connectivity test.
+	PluginHealthOK = "ok"
+
+	// PluginHealthWarning indicates that the plugin works, but has a problem
+	// that should be fixed, such as metadata that does not match its configuration.
+	PluginHealthWarning = "warning"
+
+	// PluginHealthError indicates that the plugin could not be used.
+	PluginHealthError = "error"
+
+	// DefaultPluginDoctorTimeout is how long a plugin has to respond to its
+	// connectivity test.
+	DefaultPluginDoctorTimeout = 10 * time.Second
+
+	// doctorSecretName is the name of the secret resolved to check the
+	// connectivity of secrets plugins. It is not expected to exist.
+	doctorSecretName = "porter-plugins-doctor"
+)
+
+// PluginsDoctorOptions are the options for checking the configured plugins
+// with porter plugins doctor.
+type PluginsDoctorOptions struct {
+	printer.PrintOptions
+
+	// Timeout is how long each plugin has to respond to its connectivity test.
+	Timeout time.Duration
+}
+
+// Validate the options provided to Porter's plugins doctor command.
+func (o *PluginsDoctorOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be greater than 0")
+	}
+	return o.ParseFormat()
+}
+
+// PluginHealth is the result of checking a plugin configured in the Porter
+// config file.
+type PluginHealth struct {
+	// Name of the plugin configuration in the Porter config file. Empty when the
+	// plugin is selected with default-storage-plugin or default-secrets-plugin.
+	Name string `json:"name,omitempty"`
+
+	// Type of plugin, for example storage.
+	Type string `json:"type"`
+
+	// Key of the plugin implementation, for example storage.porter.mongodb.
+	Key string `json:"key"`
+
+	// Default is true when Porter uses the plugin for its type of plugin.
+	Default bool `json:"default"`
+
+	// Version of the plugin binary.
+	Version string `json:"version,omitempty"`
+
+	// ProtocolVersion is the version of the plugin protocol negotiated with the plugin.
+	ProtocolVersion int `json:"protocolVersion,omitempty"`
+
+	// Handshake is how long it took to start the plugin and connect to it.
+	Handshake string `json:"handshake,omitempty"`
+
+	// Latency is how long the plugin took to respond to its connectivity test.
+	Latency string `json:"latency,omitempty"`
+
+	// Status of the plugin: ok, warning or error.
+	Status string `json:"status"`
+
+	// Message explains a warning or an error.
+	Message string `json:"message,omitempty"`
+}
+
+// pluginClient is a running plugin, see pluggable.PluginConnection.
+type pluginClient interface {
+	Key() plugins.PluginKey
+	NegotiatedVersion() int
+	GetClient() interface{}
+	Close(ctx context.Context) error
+}
+
+// pluginConnector starts the plugin with the specified name in the Porter
+// config file, or the default plugin when the name is empty.
+type pluginConnector func(ctx context.Context, pluginType pluggable.PluginTypeConfig, name string) (pluginClient, error)
+
+// configuredPlugin is a plugin that is checked by porter plugins doctor.
+type configuredPlugin struct {
+	name       string
+	isDefault  bool
+	pluginType pluggable.PluginTypeConfig
+	test       func(ctx context.Context, client interface{}) error
+}
+
+// DoctorPlugins starts each plugin configured in the Porter config file,
+// runs a connectivity test against it, and prints the latency and any
+// problems found, such as a plugin that does not support the plugin protocol
+// used by this version of Porter. Returns an error when a plugin cannot be used.
+func (p *Porter) DoctorPlugins(ctx context.Context, opts PluginsDoctorOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	connect := func(ctx context.Context, pluginType pluggable.PluginTypeConfig, name string) (pluginClient, error) {
+		l := pluggable.NewPluginLoader(p.Config)
+		return l.LoadNamed(ctx, pluginType, name)
+	}
+	results := p.doctorPlugins(ctx, connect, opts.Timeout)
+
+	if err := p.printPluginHealth(opts, results); err != nil {
+		return span.Error(err)
+	}
+
+	var failed []string
+	for _, result := range results {
+		if result.Status == PluginHealthError {
+			failed = append(failed, result.Key)
+		}
+	}
+	if len(failed) > 0 {
+		return span.Error(fmt.Errorf("%d plugin(s) failed the health check: %s", len(failed), strings.Join(failed, ", ")))
+	}
+	return nil
+}
+
+func (p *Porter) doctorPlugins(ctx context.Context, connect pluginConnector, timeout time.Duration) []PluginHealth {
+	configured := p.listConfiguredPlugins()
+	results := make([]PluginHealth, 0, len(configured))
+	for _, cp := range configured {
+		results = append(results, p.doctorPlugin(ctx, connect, cp, timeout))
+	}
+	return results
+}
+
+// listConfiguredPlugins returns the plugins used by Porter, followed by the
+// other plugins defined in the Porter config file.
+func (p *Porter) listConfiguredPlugins() []configuredPlugin {
+	storageType := storagepluginstore.NewStoragePluginConfig()
+	secretsType := secretspluginstore.NewSecretsPluginConfig()
+
+	configured := []configuredPlugin{
+		{name: p.Data.DefaultStorage, isDefault: true, pluginType: storageType, test: testStoragePlugin},
+		{name: p.Data.DefaultSecrets, isDefault: true, pluginType: secretsType, test: testSecretsPlugin},
+	}
+	for _, sp := range p.Data.StoragePlugins {
+		if sp.Name != p.Data.DefaultStorage {
+			configured = append(configured, configuredPlugin{name: sp.Name, pluginType: storageType, test: testStoragePlugin})
+		}
+	}
+	for _, sp := range p.Data.SecretsPlugin {
+		if sp.Name != p.Data.DefaultSecrets {
+			configured = append(configured, configuredPlugin{name: sp.Name, pluginType: secretsType, test: testSecretsPlugin})
+		}
+	}
+	return configured
+}
+
+// doctorPlugin starts the plugin and runs its connectivity test.
+func (p *Porter) doctorPlugin(ctx context.Context, connect pluginConnector, cp configuredPlugin, timeout time.Duration) PluginHealth {
+	ctx, span := tracing.StartSpan(ctx,
+		attribute.String("plugin-interface", cp.pluginType.Interface),
+		attribute.String("plugin-name", cp.name))
+	defer span.EndSpan()
+
+	result := PluginHealth{
+		Name:    cp.name,
+		Type:    cp.pluginType.Interface,
+		Default: cp.isDefault,
+		Status:  PluginHealthOK,
+	}
+
+	start := time.Now()
+	conn, err := connect(ctx, cp.pluginType, cp.name)
+	result.Handshake = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		result.Key = p.describePluginKey(cp)
+		result.Status = PluginHealthError
+		result.Message = err.Error()
+		if strings.Contains(err.Error(), "Incompatible API version") {
+			result.Message = fmt.Sprintf("protocol version mismatch: this version of Porter requires version %d of the %s plugin protocol, upgrade the plugin: %s",
+				cp.pluginType.ProtocolVersion, cp.pluginType.Interface, err)
+		}
+		return result
+	}
+	defer conn.Close(ctx)
+
+	key := conn.Key()
+	result.Key = key.String()
+	result.ProtocolVersion = conn.NegotiatedVersion()
+
+	var warnings []string
+	if key.IsInternal {
+		result.Version = pkg.Version
+	} else if meta, err := p.GetPlugin(ctx, key.Binary); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not determine the version of the %s plugin: %s", key.Binary, err))
+	} else {
+		result.Version = meta.Version
+		if !providesImplementation(meta, key) {
+			warnings = append(warnings, fmt.Sprintf("the %s plugin does not list the %s %s implementation, check that the plugin key in the config file is correct", key.Binary, key.Implementation, key.Interface))
+		}
+	}
+
+	testCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start = time.Now()
+	err = cp.test(testCtx, conn.GetClient())
+	result.Latency = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		if errors.Is(testCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("the plugin did not respond within %s: %w", timeout, err)
+		}
+		result.Status = PluginHealthError
+		result.Message = fmt.Sprintf("connectivity test failed: %s", err)
+		return result
+	}
+
+	if len(warnings) > 0 {
+		result.Status = PluginHealthWarning
+		result.Message = strings.Join(warnings, "; ")
+	}
+	return result
+}
+
+// describePluginKey returns the key of the plugin that Porter tried to
+// start, for plugins that could not be started.
+func (p *Porter) describePluginKey(cp configuredPlugin) string {
+	subKey := cp.pluginType.GetDefaultPlugin(p.Config)
+	if cp.name != "" {
+		entry, err := cp.pluginType.GetPluggable(p.Config, cp.name)
+		if err != nil {
+			return cp.pluginType.Interface
+		}
+		subKey = entry.GetPluginSubKey()
+	}
+
+	key, err := plugins.ParsePluginKey(subKey)
+	if err != nil {
+		return cp.pluginType.Interface + "." + subKey
+	}
+	key.Interface = cp.pluginType.Interface
+	return key.String()
+}
+
+// providesImplementation checks if the plugin binary declares the
+// implementation selected by the plugin key.
+func providesImplementation(meta *plugins.Metadata, key plugins.PluginKey) bool {
+	for _, impl := range meta.Implementations {
+		if impl.Type == key.Interface && impl.Name == key.Implementation {
+			return true
+		}
+	}
+	return false
+}
+
+// testStoragePlugin checks that the storage plugin can query its database.
+func testStoragePlugin(ctx context.Context, client interface{}) error {
+	store, ok := client.(storageplugins.StorageProtocol)
+	if !ok {
+		return fmt.Errorf("the interface (%T) exposed by the plugin was not plugins.StorageProtocol", client)
+	}
+
+	_, err := store.Count(ctx, storageplugins.CountOptions{Collection: storage.CollectionInstallations})
+	return err
+}
+
+// testSecretsPlugin checks that the secrets plugin responds to requests to
+// resolve a secret. The secret is not expected to exist, so errors returned
+// by the plugin are ignored, only failures to reach the plugin are reported.
+func testSecretsPlugin(ctx context.Context, client interface{}) error {
+	store, ok := client.(secretsplugins.SecretsProtocol)
+	if !ok {
+		return fmt.Errorf("the interface (%T) exposed by the plugin was not plugins.SecretsProtocol", client)
+	}
+
+	_, err := store.Resolve(ctx, secrets.SourceSecret, doctorSecretName)
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (p *Porter) printPluginHealth(opts PluginsDoctorOptions, results []PluginHealth) error {
+	switch opts.Format {
+	case printer.FormatPlaintext:
+		printRow :=
+			func(v interface{}) []string {
+				r, ok := v.(PluginHealth)
+				if !ok {
+					return nil
+				}
+				name := r.Name
+				if r.Default {
+					name = strings.TrimSpace(name + " (default)")
+				}
+				protocol := ""
+				if r.ProtocolVersion > 0 {
+					protocol = strconv.Itoa(r.ProtocolVersion)
+				}
+				return []string{name, r.Type, r.Key, r.Version, protocol, r.Handshake, r.Latency, r.Status, r.Message}
+			}
+		return printer.PrintTable(p.Out, results, printRow, "Name", "Type", "Key", "Version", "Protocol", "Handshake", "Latency", "Status", "Message")
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, results)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, results)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
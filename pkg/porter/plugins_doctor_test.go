@@ -0,0 +1,196 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/plugins"
+	"get.porter.sh/porter/pkg/plugins/pluggable"
+	secretsplugins "get.porter.sh/porter/pkg/secrets/plugins"
+	storageplugins "get.porter.sh/porter/pkg/storage/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testPluginClient is a running plugin for the plugins doctor tests.
+type testPluginClient struct {
+	key    plugins.PluginKey
+	client interface{}
+	closed bool
+}
+
+func (c *testPluginClient) Key() plugins.PluginKey {
+	return c.key
+}
+
+func (c *testPluginClient) NegotiatedVersion() int {
+	return 2
+}
+
+func (c *testPluginClient) GetClient() interface{} {
+	return c.client
+}
+
+func (c *testPluginClient) Close(ctx context.Context) error {
+	c.closed = true
+	return nil
+}
+
+// testDoctorStorage is a storage plugin that responds to Count.
+type testDoctorStorage struct {
+	storageplugins.StorageProtocol
+	err   error
+	block bool
+}
+
+func (s testDoctorStorage) Count(ctx context.Context, opts storageplugins.CountOptions) (int64, error) {
+	if s.block {
+		<-ctx.Done()
+		return 0, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+	}
+	return 0, s.err
+}
+
+// testDoctorSecrets is a secrets plugin that responds to Resolve.
+type testDoctorSecrets struct {
+	secretsplugins.SecretsProtocol
+	err error
+}
+
+func (s testDoctorSecrets) Resolve(ctx context.Context, keyName string, keyValue string) (string, error) {
+	return "", s.err
+}
+
+func TestPorter_DoctorPlugins(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	p.Data.DefaultStorage = "devdb"
+	p.Data.StoragePlugins = []config.StoragePlugin{
+		{PluginConfig: config.PluginConfig{Name: "devdb", PluginSubKey: "plugin1.mongo"}},
+		{PluginConfig: config.PluginConfig{Name: "slowdb", PluginSubKey: "plugin1.blob"}},
+	}
+	p.Data.SecretsPlugin = []config.SecretsPlugin{
+		{PluginConfig: config.PluginConfig{Name: "vault", PluginSubKey: "plugin2.vault"}},
+		{PluginConfig: config.PluginConfig{Name: "old", PluginSubKey: "azure.keyvault"}},
+		{PluginConfig: config.PluginConfig{Name: "down", PluginSubKey: "plugin2.down"}},
+	}
+
+	var conns []*testPluginClient
+	connect := func(ctx context.Context, pluginType pluggable.PluginTypeConfig, name string) (pluginClient, error) {
+		conn := &testPluginClient{}
+		switch name {
+		case "devdb":
+			conn.key = plugins.PluginKey{Interface: "storage", Binary: "plugin1", Implementation: "mongo"}
+			conn.client = testDoctorStorage{}
+		case "slowdb":
+			conn.key = plugins.PluginKey{Interface: "storage", Binary: "plugin1", Implementation: "blob"}
+			conn.client = testDoctorStorage{block: true}
+		case "":
+			conn.key = plugins.PluginKey{Interface: "secrets", Binary: "porter", Implementation: "host", IsInternal: true}
+			conn.client = testDoctorSecrets{err: errors.New("invalid credential source: secret")}
+		case "vault":
+			conn.key = plugins.PluginKey{Interface: "secrets", Binary: "plugin2", Implementation: "vault"}
+			conn.client = testDoctorSecrets{}
+		case "old":
+			return nil, errors.New("could not connect to the secrets.azure.keyvault plugin: Incompatible API version with plugin. Plugin version: 1, Client versions: [2]")
+		case "down":
+			conn.key = plugins.PluginKey{Interface: "secrets", Binary: "plugin2", Implementation: "down"}
+			conn.client = testDoctorSecrets{err: status.Error(codes.Unavailable, "connection refused")}
+		default:
+			return nil, fmt.Errorf("unexpected plugin %s", name)
+		}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+
+	results := p.doctorPlugins(ctx, connect, 10*time.Millisecond)
+	require.Len(t, results, 6)
+	for _, conn := range conns {
+		assert.True(t, conn.closed, "the %s plugin should be stopped", conn.key)
+	}
+
+	t.Run("default storage", func(t *testing.T) {
+		r := results[0]
+		assert.Equal(t, "devdb", r.Name)
+		assert.True(t, r.Default)
+		assert.Equal(t, "storage.plugin1.mongo", r.Key)
+		assert.Equal(t, "v1.0", r.Version)
+		assert.Equal(t, 2, r.ProtocolVersion)
+		assert.Equal(t, PluginHealthOK, r.Status, r.Message)
+		assert.NotEmpty(t, r.Handshake)
+		assert.NotEmpty(t, r.Latency)
+	})
+
+	t.Run("default secrets plugin", func(t *testing.T) {
+		r := results[1]
+		assert.Empty(t, r.Name)
+		assert.True(t, r.Default)
+		assert.Equal(t, "secrets.porter.host", r.Key)
+		assert.Equal(t, pkg.Version, r.Version)
+		assert.Equal(t, PluginHealthOK, r.Status, "errors returned by the plugin when resolving the missing secret should be ignored")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r := results[2]
+		assert.Equal(t, "slowdb", r.Name)
+		assert.False(t, r.Default)
+		assert.Equal(t, PluginHealthError, r.Status)
+		assert.Contains(t, r.Message, "the plugin did not respond within 10ms")
+	})
+
+	t.Run("implementation not listed", func(t *testing.T) {
+		r := results[3]
+		assert.Equal(t, "vault", r.Name)
+		assert.Equal(t, PluginHealthWarning, r.Status)
+		assert.Contains(t, r.Message, "the plugin2 plugin does not list the vault secrets implementation")
+	})
+
+	t.Run("protocol version mismatch", func(t *testing.T) {
+		r := results[4]
+		assert.Equal(t, "old", r.Name)
+		assert.Equal(t, "secrets.azure.keyvault", r.Key)
+		assert.Equal(t, PluginHealthError, r.Status)
+		assert.Contains(t, r.Message, "protocol version mismatch: this version of Porter requires version 2 of the secrets plugin protocol")
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		r := results[5]
+		assert.Equal(t, "down", r.Name)
+		assert.Equal(t, PluginHealthError, r.Status)
+		assert.Contains(t, r.Message, "connectivity test failed")
+		assert.Contains(t, r.Message, "connection refused")
+	})
+}
+
+func TestPorter_printPluginHealth(t *testing.T) {
+	p := NewTestPorter(t)
+	results := []PluginHealth{
+		{Name: "devdb", Type: "storage", Key: "storage.porter.mongodb", Default: true, Version: "v1.0.0", Handshake: "15ms", Latency: "2ms", Status: PluginHealthOK},
+		{Type: "secrets", Key: "secrets.azure.keyvault", Default: true, Handshake: "5ms", Status: PluginHealthError, Message: "protocol version mismatch"},
+	}
+
+	opts := PluginsDoctorOptions{Timeout: DefaultPluginDoctorTimeout}
+	require.NoError(t, opts.Validate())
+	require.NoError(t, p.printPluginHealth(opts, results))
+
+	gotOutput := p.TestConfig.TestContext.GetOutput()
+	assert.Contains(t, gotOutput, "devdb (default)")
+	assert.Contains(t, gotOutput, "storage.porter.mongodb")
+	assert.Contains(t, gotOutput, "protocol version mismatch")
+}
+
+func TestPluginsDoctorOptions_Validate(t *testing.T) {
+	opts := PluginsDoctorOptions{}
+	require.ErrorContains(t, opts.Validate(), "--timeout must be greater than 0")
+
+	opts.Timeout = time.Second
+	opts.RawFormat = "xml"
+	require.ErrorContains(t, opts.Validate(), "invalid format: xml")
+}
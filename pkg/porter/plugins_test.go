@@ -159,12 +159,15 @@ func TestPorter_ShowPlugin(t *testing.T) {
 Version: v1.0
 Commit: abc123
 Author: Porter Authors
-
----------------------------
-  Type     Implementation  
----------------------------
-  storage  blob            
-  storage  mongo           
+Installed: 2022-01-01
+URL: https://cdn.porter.sh/plugins/plugin1/latest
+Binary Path: /home/myuser/.porter/plugins/plugin1/plugin1
+
+---------------------------------------------
+  Type     Implementation  Protocol Version  
+---------------------------------------------
+  storage  blob            3                 
+  storage  mongo           3                 
 `
 		actual := p.TestConfig.TestContext.GetOutput()
 		assert.Equal(t, expected, actual)
@@ -190,6 +193,11 @@ implementations:
     name: blob
   - type: storage
     name: mongo
+url: https://cdn.porter.sh/plugins/plugin1/latest
+installedAt: 2022-01-01T00:00:00Z
+binaryPath: /home/myuser/.porter/plugins/plugin1/plugin1
+protocolVersions:
+  storage: 3
 `
 		actual := p.TestConfig.TestContext.GetOutput()
 		assert.Equal(t, expected, actual)
@@ -220,7 +228,13 @@ implementations:
       "type": "storage",
       "implementation": "mongo"
     }
-  ]
+  ],
+  "url": "https://cdn.porter.sh/plugins/plugin1/latest",
+  "installedAt": "2022-01-01T00:00:00Z",
+  "binaryPath": "/home/myuser/.porter/plugins/plugin1/plugin1",
+  "protocolVersions": {
+    "storage": 3
+  }
 }
 `
 		actual := p.TestConfig.TestContext.GetOutput()
@@ -389,3 +403,79 @@ func TestPorter_UninstallPlugin(t *testing.T) {
 	gotoutput := p.TestConfig.TestContext.GetOutput()
 	assert.Contains(t, wantOutput, gotoutput)
 }
+
+func TestPorter_CreatePlugin(t *testing.T) {
+	testcases := []struct {
+		pluginType string
+		wantConfig string
+	}{
+		{pluginType: "secrets", wantConfig: `Dir string`},
+		{pluginType: "storage", wantConfig: `URL string`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.pluginType, func(t *testing.T) {
+			p := NewTestPorter(t)
+
+			opts := PluginsCreateOptions{
+				PluginType: tc.pluginType,
+				AuthorName: "Author Name",
+				ModulePath: "example.com/plugins/my-plugin",
+				DirPath:    "/src",
+			}
+			require.NoError(t, p.FileSystem.MkdirAll("/src", 0700))
+			require.NoError(t, opts.Validate([]string{"my-plugin"}, p.Context))
+
+			err := p.CreatePlugin(opts)
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("Created my-plugin %s plugin\n", tc.pluginType), p.TestConfig.TestContext.GetOutput())
+
+			pluginDir := "/src/my-plugin"
+			for _, f := range []string{"go.mod", ".gitignore", "magefile.go", "README.md", "cmd/my-plugin/main.go", "pkg/myplugin/run.go", "pkg/myplugin/version.go", "pkg/myplugin/store_test.go"} {
+				exists, _ := p.FileSystem.Exists(filepath.Join(pluginDir, f))
+				assert.True(t, exists, "expected %s to be generated", f)
+			}
+
+			gotPlugin, err := p.FileSystem.ReadFile(filepath.Join(pluginDir, "pkg/myplugin/plugin.go"))
+			require.NoError(t, err)
+			assert.Contains(t, string(gotPlugin), `PluginKey = PluginInterface + ".my-plugin.myplugin"`)
+			assert.Contains(t, string(gotPlugin), fmt.Sprintf(`"get.porter.sh/porter/pkg/%s/pluginstore"`, tc.pluginType))
+
+			gotConfig, err := p.FileSystem.ReadFile(filepath.Join(pluginDir, "pkg/myplugin/config.go"))
+			require.NoError(t, err)
+			assert.Contains(t, string(gotConfig), tc.wantConfig)
+
+			gotReadme, err := p.FileSystem.ReadFile(filepath.Join(pluginDir, "README.md"))
+			require.NoError(t, err)
+			assert.Contains(t, string(gotReadme), fmt.Sprintf("default-%s: \"mystore\"", tc.pluginType))
+			assert.Contains(t, string(gotReadme), `plugin: "my-plugin.myplugin"`)
+		})
+	}
+}
+
+func TestPluginsCreateOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name      string
+		opts      PluginsCreateOptions
+		wantError string
+	}{
+		{name: "valid", opts: PluginsCreateOptions{PluginType: "secrets", AuthorName: "me", AuthorUsername: "me"}},
+		{name: "no type", opts: PluginsCreateOptions{AuthorName: "me", AuthorUsername: "me"}, wantError: "must provide a value for flag --type"},
+		{name: "invalid type", opts: PluginsCreateOptions{PluginType: "credentials", AuthorName: "me", AuthorUsername: "me"}, wantError: "invalid --type credentials"},
+		{name: "no author", opts: PluginsCreateOptions{PluginType: "storage", AuthorUsername: "me"}, wantError: "must provide a value for flag --author"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTestPorter(t)
+
+			err := tc.opts.Validate([]string{"myplugin"}, p.Context)
+			if tc.wantError != "" {
+				require.ErrorContains(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "github.com/me/myplugin", tc.opts.ModulePath)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/mixin"
 	"get.porter.sh/porter/pkg/plugins"
+	"get.porter.sh/porter/pkg/plugins/pluggable"
 	"get.porter.sh/porter/pkg/secrets"
 	secretsplugin "get.porter.sh/porter/pkg/secrets/pluginstore"
 	"get.porter.sh/porter/pkg/storage"
@@ -35,10 +36,13 @@ type Porter struct {
 	builder build.Builder
 
 	Cache         cache.BundleCache
+	ContentStore  *cnabtooci.ContentStore
 	Credentials   storage.CredentialSetProvider
 	Parameters    storage.ParameterSetProvider
 	Sanitizer     *storage.Sanitizer
 	Installations storage.InstallationProvider
+	Namespaces    storage.NamespaceProvider
+	Audit         storage.AuditProvider
 	Registry      cnabtooci.RegistryProvider
 	Templates     *templates.Templates
 	Mixins        mixin.MixinProvider
@@ -46,40 +50,71 @@ type Porter struct {
 	CNAB          cnabprovider.CNABProvider
 	Secrets       secrets.Store
 	Storage       storage.Provider
+	Encryptor     *storage.Encryptor
+
+	// pluginPool keeps the storage and secrets plugins running until Porter is closed.
+	pluginPool *pluggable.PluginPool
+
+	// pick asks the user to pick the name of a resource. Defaults to an
+	// interactive prompt in the terminal, and is replaced in tests.
+	pick pickFunc
 }
 
 // New porter client, initialized with useful defaults.
 func New() *Porter {
 	c := config.New()
-	storage := storage.NewPluginAdapter(storageplugin.NewStore(c))
-	secretStorage := secrets.NewPluginAdapter(secretsplugin.NewStore(c))
-	return NewFor(c, storage, secretStorage)
+	pool := pluggable.NewPluginPool(c)
+	storage := storage.NewPluginAdapter(storageplugin.NewStore(c, pool))
+	secretStorage := secrets.NewPluginAdapter(secretsplugin.NewStore(c, pool))
+	p := NewFor(c, storage, secretStorage)
+	p.pluginPool = pool
+	return p
 }
 
 func NewFor(c *config.Config, store storage.Store, secretStorage secrets.Store) *Porter {
 	cache := cache.New(c)
 
 	storageManager := migrations.NewManager(c, store)
-	installationStorage := storage.NewInstallationStore(storageManager)
-	credStorage := storage.NewCredentialStore(storageManager, secretStorage)
-	paramStorage := storage.NewParameterStore(storageManager, secretStorage)
+	policyStore := storage.NewNamespacePolicyStore(storageManager)
+	auditingStore := storage.NewAuditingStore(c, policyStore)
+	authStore := storage.NewAuthorizingStore(c, auditingStore)
+	installationStorage := storage.NewInstallationStore(authStore)
+	credStorage := storage.NewCredentialStore(authStore, secretStorage)
+	paramStorage := storage.NewParameterStore(authStore, secretStorage)
+	namespaceStorage := storage.NewNamespaceStore(storageManager)
+	auditStorage := storage.NewAuditStore(authStore)
 	sanitizerService := storage.NewSanitizer(paramStorage, secretStorage)
+	encryptor := storage.NewEncryptor(c, storageManager)
+	largeOutputs := storage.NewLargeOutputs(c)
+	paramStorage.Encryptor = encryptor
+	paramStorage.LargeOutputs = largeOutputs
+	installationStorage.LargeOutputs = largeOutputs
+	sanitizerService.UseEncryption(encryptor)
+	sanitizerService.UseLargeOutputs(largeOutputs)
 	storageManager.Initialize(sanitizerService) // we have a bit of a dependency problem here that it would be great to figure out eventually
 
+	contentStore := cnabtooci.NewContentStore(c)
+	registry := cnabtooci.NewRegistry(c.Context)
+	registry.ContentStore = contentStore
+
 	return &Porter{
 		Config:        c,
 		Cache:         cache,
+		ContentStore:  contentStore,
 		Storage:       storageManager,
 		Installations: installationStorage,
 		Credentials:   credStorage,
 		Parameters:    paramStorage,
+		Namespaces:    namespaceStorage,
+		Audit:         auditStorage,
 		Secrets:       secretStorage,
-		Registry:      cnabtooci.NewRegistry(c.Context),
+		Registry:      cnabtooci.NewOfflineRegistry(c, registry),
 		Templates:     templates.NewTemplates(c),
 		Mixins:        mixin.NewPackageManager(c),
 		Plugins:       plugins.NewPackageManager(c),
 		CNAB:          cnabprovider.NewRuntime(c, installationStorage, credStorage, secretStorage, sanitizerService),
 		Sanitizer:     sanitizerService,
+		Encryptor:     encryptor,
 	}
 }
 
@@ -128,6 +163,13 @@ func (p *Porter) Close() error {
 		bigErr = multierror.Append(bigErr, err)
 	}
 
+	if p.pluginPool != nil {
+		err = p.pluginPool.Close(context.Background())
+		if err != nil {
+			bigErr = multierror.Append(bigErr, err)
+		}
+	}
+
 	err = p.Config.Close()
 	if err != nil {
 		bigErr = multierror.Append(bigErr, err)
@@ -1,13 +1,17 @@
 package porter
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/build"
 	"get.porter.sh/porter/pkg/cnab"
 	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
@@ -21,6 +25,8 @@ import (
 	"github.com/cnabio/image-relocation/pkg/registry"
 	"github.com/cnabio/image-relocation/pkg/registry/ggcr"
 	"github.com/opencontainers/go-digest"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
 )
 
 // PublishOptions are options that may be specified when publishing a bundle.
@@ -31,10 +37,39 @@ type PublishOptions struct {
 	Tag         string
 	Registry    string
 	ArchiveFile string
+
+	// Sign the bundle and its invocation images after they are pushed.
+	Sign bool
+
+	// References are the destinations to publish the bundle to, in addition to Reference.
+	// Multiple destinations are only supported when publishing from an archive.
+	References []string
+
+	// DestinationsFile is the path to a file with the references to publish the bundle to, one per line.
+	// Multiple destinations are only supported when publishing from an archive.
+	DestinationsFile string
+
+	// RelocationMapDir is the directory where the relocation mapping for each destination is written
+	// when publishing from an archive.
+	RelocationMapDir string
+
+	// Parallelism is the maximum number of images, and layers of each image, pushed at the same time.
+	Parallelism int
+
+	// destinations are the parsed references to publish the bundle to.
+	destinations []cnab.OCIReference
 }
 
 // Validate performs validation on the publish options
 func (o *PublishOptions) Validate(cfg *config.Config) error {
+	if err := o.validateDestinations(cfg); err != nil {
+		return err
+	}
+
+	if err := validateParallelism(o.Parallelism); err != nil {
+		return err
+	}
+
 	if o.ArchiveFile != "" {
 		// Verify the archive file can be accessed
 		if _, err := cfg.FileSystem.Stat(o.ArchiveFile); err != nil {
@@ -72,6 +107,69 @@ func (o *PublishOptions) Validate(cfg *config.Config) error {
 	return nil
 }
 
+// validateDestinations combines the references specified with --reference and --destinations-file
+// into the list of destinations to publish the bundle to.
+func (o *PublishOptions) validateDestinations(cfg *config.Config) error {
+	var refs []string
+	if o.Reference != "" {
+		refs = append(refs, o.Reference)
+	}
+	refs = append(refs, o.References...)
+
+	if o.DestinationsFile != "" {
+		data, err := cfg.FileSystem.ReadFile(o.DestinationsFile)
+		if err != nil {
+			return fmt.Errorf("unable to read --destinations-file %s: %w", o.DestinationsFile, err)
+		}
+		refs = append(refs, parseDestinationsFile(data)...)
+	}
+
+	o.destinations = nil
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		parsedRef, err := cnab.ParseOCIReference(ref)
+		if err != nil {
+			return fmt.Errorf("invalid destination %s, specified value should be of the form REGISTRY/bundle:tag: %w", ref, err)
+		}
+		o.destinations = append(o.destinations, parsedRef)
+	}
+
+	if len(o.destinations) > 1 && o.ArchiveFile == "" {
+		return errors.New("publishing to multiple destinations is only supported when publishing from an archive with --archive")
+	}
+	if o.Reference == "" && len(o.destinations) > 0 {
+		o.Reference = o.destinations[0].String()
+	}
+	return nil
+}
+
+// parseDestinationsFile returns the references listed in a destinations file.
+// Each line is a reference, and blank lines and lines starting with # are ignored.
+func parseDestinationsFile(data []byte) []string {
+	var refs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	return refs
+}
+
+// GetDestinations returns the references to publish the bundle to.
+func (o *PublishOptions) GetDestinations() []cnab.OCIReference {
+	if len(o.destinations) == 0 {
+		return []cnab.OCIReference{o.GetReference()}
+	}
+	return o.destinations
+}
+
 // validateTag checks to make sure the supplied tag is of the expected form.
 // A previous iteration of this flag was used to designate an entire bundle
 // reference.  If we detect this attempted use, we return an error and
@@ -155,7 +253,15 @@ func (p *Porter) publishFromFile(ctx context.Context, opts PublishOptions) error
 		return log.Errorf("unable to set invocation image name and reference: %w", err)
 	}
 
-	if origInvImg != m.Image {
+	// Invocation images built for multiple platforms are saved as an OCI image
+	// layout instead of in the local image cache, and are pushed as an image index
+	imageLayout := filepath.Join(opts.Dir, build.LOCAL_IMAGE_LAYOUT)
+	isMultiPlatform, err := p.FileSystem.DirExists(imageLayout)
+	if err != nil {
+		return log.Errorf("error checking for the invocation image layout %s: %w", imageLayout, err)
+	}
+
+	if origInvImg != m.Image && !isMultiPlatform {
 		// Tag it so that it will be known/found by Docker for publishing
 		builder := p.GetBuilder(ctx)
 		if err := builder.TagInvocationImage(ctx, origInvImg, m.Image); err != nil {
@@ -180,6 +286,8 @@ func (p *Porter) publishFromFile(ctx context.Context, opts PublishOptions) error
 
 	regOpts := cnabtooci.RegistryOptions{
 		InsecureRegistry: opts.InsecureRegistry,
+		RegistryTLS:      p.Data.RegistryTLS,
+		Parallelism:      opts.Parallelism,
 	}
 
 	// Before we attempt to push, check if any of the bundle exists already.
@@ -192,15 +300,32 @@ func (p *Porter) publishFromFile(ctx context.Context, opts PublishOptions) error
 				return log.Errorf("Publish stopped because detection of %s in the destination registry failed. To overwrite it, repeat the command with --force specified: %w", bundleRef, err)
 			}
 		} else {
+			upToDate, err := p.isPublishedBundleUpToDate(ctx, m, bundleRef.Reference, imgRef, imageLayout, isMultiPlatform, regOpts)
+			if err != nil {
+				return err
+			}
+			if upToDate {
+				fmt.Fprintf(p.Out, "Bundle %s is up to date\n", bundleRef.Reference)
+				return nil
+			}
 			return log.Errorf("Publish stopped because %s already exists in the destination registry. To overwrite it, repeat the command with --force specified.", bundleRef)
 		}
 	}
 
-	bundleRef.Digest, err = p.Registry.PushImage(ctx, imgRef, regOpts)
+	if isMultiPlatform {
+		bundleRef.Digest, err = p.Registry.PushImageIndex(ctx, imgRef, imageLayout, regOpts)
+	} else {
+		bundleRef.Digest, err = p.Registry.PushImage(ctx, imgRef, regOpts)
+	}
 	if err != nil {
 		return log.Errorf("unable to push CNAB invocation image %q: %w", m.Image, err)
 	}
 
+	imgDigestRef, err := referenceByDigest(imgRef, bundleRef.Digest)
+	if err != nil {
+		return log.Error(err)
+	}
+
 	bundleRef.Definition, err = p.rewriteBundleWithInvocationImageDigest(ctx, m, bundleRef.Digest)
 	if err != nil {
 		return err
@@ -211,12 +336,81 @@ func (p *Porter) publishFromFile(ctx context.Context, opts PublishOptions) error
 		return err
 	}
 
+	if err = p.attachSBOM(ctx, opts.Dir, imgDigestRef, regOpts); err != nil {
+		return err
+	}
+
+	if opts.Sign {
+		if err = p.signBundle(ctx, bundleRef, regOpts); err != nil {
+			return err
+		}
+	}
+
 	// Perhaps we have a cached version of a bundle with the same reference, previously pulled
 	// If so, replace it, as it is most likely out-of-date per this publish
-	err = p.refreshCachedBundle(bundleRef)
+	err = p.refreshCachedBundle(ctx, bundleRef)
 	return log.Error(err)
 }
 
+// isPublishedBundleUpToDate determines if the bundle in the destination registry
+// is identical to the bundle that would be published: it references the same
+// invocation image and has the same definition. When it is, there is nothing to push.
+func (p *Porter) isPublishedBundleUpToDate(ctx context.Context, m *manifest.Manifest, bundleRef cnab.OCIReference, imgRef cnab.OCIReference, imageLayout string, isMultiPlatform bool, regOpts cnabtooci.RegistryOptions) (bool, error) {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	// Determine the digest that the invocation image has once pushed, without pushing it
+	var imgDigest digest.Digest
+	if isMultiPlatform {
+		var err error
+		imgDigest, err = cnabtooci.GetImageLayoutDigest(imageLayout)
+		if err != nil {
+			return false, log.Error(err)
+		}
+	} else {
+		cachedImg, err := p.Registry.GetCachedImage(ctx, imgRef)
+		if err != nil {
+			if errors.Is(err, cnabtooci.ErrNotFound{}) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		// The image only has a digest for the repository once it has been pushed to it
+		imgDigest, err = cachedImg.Digest()
+		if err != nil {
+			log.Debugf("Invocation image %s has not been pushed to the destination registry: %s", imgRef, err)
+			return false, nil
+		}
+	}
+
+	published, err := p.Registry.PullBundle(ctx, bundleRef, regOpts)
+	if err != nil {
+		return false, err
+	}
+	publishedImages := published.Definition.InvocationImages
+	if len(publishedImages) != 1 || publishedImages[0].Digest != imgDigest.String() {
+		log.Debugf("The invocation image of %s in the destination registry is different from %s@%s", bundleRef, imgRef, imgDigest)
+		return false, nil
+	}
+
+	// Generate the bundle as it would be published, referencing the invocation image by its digest
+	publishManifest := *m
+	bun, err := p.rewriteBundleWithInvocationImageDigest(ctx, &publishManifest, imgDigest)
+	if err != nil {
+		return false, err
+	}
+
+	var want, got bytes.Buffer
+	if _, err = bun.WriteTo(&want); err != nil {
+		return false, log.Errorf("error writing the bundle definition: %w", err)
+	}
+	if _, err = published.Definition.WriteTo(&got); err != nil {
+		return false, log.Errorf("error writing the bundle definition of %s: %w", bundleRef, err)
+	}
+	return bytes.Equal(want.Bytes(), got.Bytes()), nil
+}
+
 // publishFromArchive (re-)publishes a bundle, provided by the archive file, using the provided tag.
 //
 // After the bundle is extracted from the archive, we iterate through all of the images (invocation
@@ -227,24 +421,29 @@ func (p *Porter) publishFromFile(ctx context.Context, opts PublishOptions) error
 // Finally, we update the relocation map in the original bundle, based
 // on the newly copied images, and then push the bundle using the provided tag.
 // (Currently we use the docker/cnab-to-oci library for this logic.)
+//
+// When multiple destinations are specified, the archive is extracted once and
+// then published to each destination in turn.
 func (p *Porter) publishFromArchive(ctx context.Context, opts PublishOptions) error {
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
 
-	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry}
+	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry, RegistryTLS: p.Data.RegistryTLS, Parallelism: opts.Parallelism}
+	destinations := opts.GetDestinations()
 
 	// Before we attempt to push, check if any of the bundle exists already.
 	// If force was not specified, we shouldn't push any of the bundle since
 	// the bundle and images must be pushed as a unit.
-	ref := opts.GetReference()
 	if !opts.Force {
-		_, err := p.Registry.GetBundleMetadata(ctx, ref, regOpts)
-		if err != nil {
-			if !errors.Is(err, cnabtooci.ErrNotFound{}) {
-				return log.Errorf("Publish stopped because detection of %s in the destination registry failed. To overwrite it, repeat the command with --force specified: %w", ref, err)
+		for _, ref := range destinations {
+			_, err := p.Registry.GetBundleMetadata(ctx, ref, regOpts)
+			if err != nil {
+				if !errors.Is(err, cnabtooci.ErrNotFound{}) {
+					return log.Errorf("Publish stopped because detection of %s in the destination registry failed. To overwrite it, repeat the command with --force specified: %w", ref, err)
+				}
+			} else {
+				return log.Errorf("Publish stopped because %s already exists in the destination registry. To overwrite it, repeat the command with --force specified.", ref)
 			}
-		} else {
-			return log.Errorf("Publish stopped because %s already exists in the destination registry. To overwrite it, repeat the command with --force specified.", ref)
 		}
 	}
 
@@ -255,58 +454,133 @@ func (p *Porter) publishFromArchive(ctx context.Context, opts PublishOptions) er
 	}
 	defer p.FileSystem.RemoveAll(tmpDir)
 
-	bundleRef, err := p.extractBundle(ctx, tmpDir, source)
+	archivedBundle, err := p.extractBundle(ctx, tmpDir, source)
 	if err != nil {
 		return err
 	}
 
-	bundleRef.Reference = ref
-
-	log.Infof("Beginning bundle publish to %s. This may take some time.", opts.Reference)
-
-	// Use the ggcr client to read the extracted OCI Layout
 	extractedDir := filepath.Join(tmpDir, strings.TrimSuffix(filepath.Base(source), ".tgz"))
-	var clientOpts []ggcr.Option
-	if opts.InsecureRegistry {
-		skipTLS := cnabtooci.GetInsecureRegistryTransport()
-		clientOpts = append(clientOpts, ggcr.WithTransport(skipTLS))
-	}
-	client := ggcr.NewRegistryClient(clientOpts...)
-	layout, err := client.ReadLayout(filepath.Join(extractedDir, "artifacts/layout"))
+	omittedImages, err := p.readOmittedImages(extractedDir)
 	if err != nil {
-		return log.Errorf("failed to parse OCI Layout from archive %s: %w", opts.ArchiveFile, err)
+		return log.Errorf("failed to load %s from archive %s: %w", archiveOmittedImagesFile, opts.ArchiveFile, err)
 	}
 
-	// Push updated images (renamed based on provided bundle tag) with same digests
-	// then update the bundle with new values (image name, digest)
-	for _, invImg := range bundleRef.Definition.InvocationImages {
-		relocMap, err := p.relocateImage(bundleRef.RelocationMap, layout, invImg.Image, opts.Reference)
+	// The archive is only extracted once, and then pushed to each destination
+	for _, ref := range destinations {
+		// Use the ggcr client to read the extracted OCI Layout, with the TLS settings of the destination registry
+		transport, err := cnabtooci.GetRegistryTransport(p.FileSystem, regOpts, ref.Registry())
 		if err != nil {
 			return log.Error(err)
 		}
+		client := ggcr.NewRegistryClient(ggcr.WithTransport(transport))
+		layout, err := client.ReadLayout(filepath.Join(extractedDir, "artifacts/layout"))
+		if err != nil {
+			return log.Errorf("failed to parse OCI Layout from archive %s: %w", opts.ArchiveFile, err)
+		}
 
-		bundleRef.RelocationMap = relocMap
-	}
-	for _, img := range bundleRef.Definition.Images {
-		relocMap, err := p.relocateImage(bundleRef.RelocationMap, layout, img.Image, opts.Reference)
+		bundleRef, err := p.publishArchivedBundle(ctx, opts, archivedBundle, ref, layout, omittedImages)
 		if err != nil {
-			return log.Error(err)
+			return err
 		}
 
-		bundleRef.RelocationMap = relocMap
+		if opts.RelocationMapDir != "" {
+			if err = p.writeRelocationMap(opts.RelocationMapDir, bundleRef); err != nil {
+				return log.Error(err)
+			}
+		}
 	}
 
-	bundleRef, err = p.Registry.PushBundle(ctx, bundleRef, regOpts)
+	return nil
+}
+
+// publishArchivedBundle pushes the images in the extracted archive, and then the bundle, to the destination reference.
+func (p *Porter) publishArchivedBundle(ctx context.Context, opts PublishOptions, archivedBundle cnab.BundleReference, ref cnab.OCIReference, layout registry.Layout, omittedImages map[string]string) (cnab.BundleReference, error) {
+	ctx, log := tracing.StartSpan(ctx, attribute.String("reference", ref.String()))
+	defer log.EndSpan()
+
+	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry, RegistryTLS: p.Data.RegistryTLS, Parallelism: opts.Parallelism}
+
+	// Each destination starts from the relocation mapping in the archive
+	bundleRef := cnab.BundleReference{
+		Reference:     ref,
+		Definition:    archivedBundle.Definition,
+		RelocationMap: make(relocation.ImageRelocationMap, len(archivedBundle.RelocationMap)),
+	}
+	for k, v := range archivedBundle.RelocationMap {
+		bundleRef.RelocationMap[k] = v
+	}
+
+	log.Infof("Beginning bundle publish to %s. This may take some time.", ref)
+
+	// Push updated images (renamed based on provided bundle tag) with same digests
+	// then update the bundle with new values (image name, digest)
+	var images []string
+	for _, invImg := range bundleRef.Definition.InvocationImages {
+		images = append(images, invImg.Image)
+	}
+	for name, img := range bundleRef.Definition.Images {
+		if _, omitted := omittedImages[name]; omitted {
+			// The image was not included in the archive, so the bundle continues to reference its original location
+			log.Warnf("Skipping image %s because it was omitted from the archive. The bundle continues to reference %s, which must be available to the bundle when it is run", name, img.Image)
+			continue
+		}
+		images = append(images, img.Image)
+	}
+	if err := p.relocateImages(bundleRef.RelocationMap, layout, images, ref.String(), regOpts.GetParallelism()); err != nil {
+		return cnab.BundleReference{}, log.Error(err)
+	}
+
+	bundleRef, err := p.Registry.PushBundle(ctx, bundleRef, regOpts)
 	if err != nil {
-		return err
+		return cnab.BundleReference{}, err
+	}
+
+	if opts.Sign {
+		if err = p.signBundle(ctx, bundleRef, regOpts); err != nil {
+			return cnab.BundleReference{}, err
+		}
 	}
 
 	// Perhaps we have a cached version of a bundle with the same tag, previously pulled
 	// If so, replace it, as it is most likely out-of-date per this publish
-	err = p.refreshCachedBundle(bundleRef)
-	return log.Error(err)
+	err = p.refreshCachedBundle(ctx, bundleRef)
+	return bundleRef, log.Error(err)
 }
 
+// writeRelocationMap saves the relocation mapping of a published bundle to a file in the specified directory.
+// The file is named after the bundle reference, so that each destination has its own relocation mapping.
+func (p *Porter) writeRelocationMap(dir string, bundleRef cnab.BundleReference) error {
+	fileName := relocationMapFileNameReplacer.Replace(bundleRef.Reference.String()) + ".json"
+	dest := filepath.Join(dir, fileName)
+	if err := p.writeRelocationMapFile(dest, bundleRef.RelocationMap); err != nil {
+		return fmt.Errorf("error writing the relocation mapping for %s: %w", bundleRef.Reference, err)
+	}
+
+	fmt.Fprintf(p.Out, "Wrote the relocation mapping for %s to %s\n", bundleRef.Reference, dest)
+	return nil
+}
+
+// writeRelocationMapFile saves a relocation mapping to a file, creating the parent directory if necessary.
+// The file is in the same format as the relocation-mapping.json file mounted into the bundle at runtime.
+func (p *Porter) writeRelocationMapFile(dest string, relocationMap relocation.ImageRelocationMap) error {
+	if err := p.FileSystem.MkdirAll(filepath.Dir(dest), pkg.FileModeDirectory); err != nil {
+		return fmt.Errorf("error creating directory for the relocation mapping %s: %w", dest, err)
+	}
+
+	data, err := json.MarshalIndent(relocationMap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling the relocation mapping: %w", err)
+	}
+
+	if err = p.FileSystem.WriteFile(dest, data, pkg.FileModeWritable); err != nil {
+		return fmt.Errorf("error writing the relocation mapping to %s: %w", dest, err)
+	}
+	return nil
+}
+
+// relocationMapFileNameReplacer converts a bundle reference into a valid file name.
+var relocationMapFileNameReplacer = strings.NewReplacer("/", "_", ":", "_", "@", "_")
+
 // extractBundle extracts a bundle using the provided opts and returns the extracted bundle
 func (p *Porter) extractBundle(ctx context.Context, tmpDir, source string) (cnab.BundleReference, error) {
 	//lint:ignore SA4006 ignore unused ctx for now
@@ -338,6 +612,25 @@ func (p *Porter) extractBundle(ctx context.Context, tmpDir, source string) (cnab
 	return cnab.BundleReference{Definition: cnab.ExtendedBundle{Bundle: *bun}, RelocationMap: reloMap}, nil
 }
 
+// readOmittedImages returns the referenced images that were not included in the extracted archive,
+// keyed by the image name defined in the bundle.
+func (p *Porter) readOmittedImages(extractedDir string) (map[string]string, error) {
+	data, err := p.FileSystem.ReadFile(filepath.Join(extractedDir, archiveOmittedImagesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// All images were included in the archive
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var omitted map[string]string
+	if err = json.Unmarshal(data, &omitted); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", archiveOmittedImagesFile, err)
+	}
+	return omitted, nil
+}
+
 // pushUpdatedImage uses the provided layout to find the provided origImg,
 // gathers the pre-existing digest and then pushes this digest using the newImgName
 func pushUpdatedImage(layout registry.Layout, origImg string, newImgName image.Name) (image.Digest, error) {
@@ -415,6 +708,37 @@ func (p *Porter) rewriteBundleWithInvocationImageDigest(ctx context.Context, m *
 	return bun, nil
 }
 
+// relocateImages pushes the images from the archived OCI layout to the repository of the bundle,
+// with up to parallelism images pushed at the same time, and updates the relocation map.
+func (p *Porter) relocateImages(relocationMap relocation.ImageRelocationMap, layout registry.Layout, images []string, newReference string, parallelism int) error {
+	var lock sync.Mutex
+	g := new(errgroup.Group)
+	g.SetLimit(parallelism)
+	for _, img := range images {
+		img := img
+		g.Go(func() error {
+			// Each image is relocated with its own mapping, so that the images do not modify the same map at the same time
+			imageRelocationMap := make(relocation.ImageRelocationMap, 1)
+			lock.Lock()
+			if relocated, ok := relocationMap[img]; ok {
+				imageRelocationMap[img] = relocated
+			}
+			lock.Unlock()
+
+			imageRelocationMap, err := p.relocateImage(imageRelocationMap, layout, img, newReference)
+			if err != nil {
+				return err
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			relocationMap[img] = imageRelocationMap[img]
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
 func (p *Porter) relocateImage(relocationMap relocation.ImageRelocationMap, layout registry.Layout, originImg string, newReference string) (relocation.ImageRelocationMap, error) {
 	newImgName, err := getNewImageNameFromBundleReference(originImg, newReference)
 	if err != nil {
@@ -458,11 +782,11 @@ func (p *Porter) rewriteImageWithDigest(InvocationImage string, imgDigest string
 }
 
 // refreshCachedBundle will store a bundle anew, if a bundle with the same tag is found in the cache
-func (p *Porter) refreshCachedBundle(bundleRef cnab.BundleReference) error {
-	if _, found, _ := p.Cache.FindBundle(bundleRef.Reference); found {
-		_, err := p.Cache.StoreBundle(bundleRef)
+func (p *Porter) refreshCachedBundle(ctx context.Context, bundleRef cnab.BundleReference) error {
+	if _, found, _ := p.Cache.FindBundle(ctx, bundleRef.Reference); found {
+		_, err := p.Cache.StoreBundle(ctx, bundleRef)
 		if err != nil {
-			fmt.Fprintf(p.Err, "warning: unable to update cache for bundle %s: %s\n", bundleRef.Reference, err)
+			tracing.LoggerFromContext(ctx).Warnf("Unable to update cache for bundle %s: %s", bundleRef.Reference, err)
 		}
 	}
 	return nil
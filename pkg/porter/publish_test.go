@@ -2,18 +2,24 @@ package porter
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/build"
 	"get.porter.sh/porter/pkg/cache"
 	"get.porter.sh/porter/pkg/cnab"
 	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/tests"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-to-oci/relocation"
 	"github.com/cnabio/image-relocation/pkg/image"
 	"github.com/cnabio/image-relocation/pkg/registry"
+	"github.com/docker/docker/api/types"
+	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -62,6 +68,64 @@ func TestPublish_Validate_ArchivePath(t *testing.T) {
 	require.NoError(t, err, "validating should not have failed")
 }
 
+func TestPublish_Validate_Destinations(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	require.NoError(t, p.FileSystem.WriteFile("mybuns.tgz", []byte("mybuns"), pkg.FileModeWritable))
+	require.NoError(t, p.FileSystem.WriteFile("destinations.txt", []byte(`
+# air-gapped registries
+reg2.example.com/mybuns:v0.1.0
+
+reg3.example.com/mybuns:v0.1.0
+reg1.example.com/mybuns:v0.1.0
+`), pkg.FileModeWritable))
+
+	t.Run("multiple references", func(t *testing.T) {
+		opts := PublishOptions{
+			ArchiveFile: "mybuns.tgz",
+			References:  []string{"reg1.example.com/mybuns:v0.1.0", "reg2.example.com/mybuns:v0.1.0"},
+		}
+		require.NoError(t, opts.Validate(p.Config))
+		assert.Equal(t, "reg1.example.com/mybuns:v0.1.0", opts.Reference, "the first destination should be used as the reference")
+		assert.Equal(t, []cnab.OCIReference{
+			cnab.MustParseOCIReference("reg1.example.com/mybuns:v0.1.0"),
+			cnab.MustParseOCIReference("reg2.example.com/mybuns:v0.1.0"),
+		}, opts.GetDestinations())
+	})
+
+	t.Run("destinations file", func(t *testing.T) {
+		opts := PublishOptions{
+			ArchiveFile:      "mybuns.tgz",
+			References:       []string{"reg1.example.com/mybuns:v0.1.0"},
+			DestinationsFile: "destinations.txt",
+		}
+		require.NoError(t, opts.Validate(p.Config))
+		assert.Equal(t, []cnab.OCIReference{
+			cnab.MustParseOCIReference("reg1.example.com/mybuns:v0.1.0"),
+			cnab.MustParseOCIReference("reg2.example.com/mybuns:v0.1.0"),
+			cnab.MustParseOCIReference("reg3.example.com/mybuns:v0.1.0"),
+		}, opts.GetDestinations(), "expected duplicate destinations to be removed")
+	})
+
+	t.Run("missing destinations file", func(t *testing.T) {
+		opts := PublishOptions{ArchiveFile: "mybuns.tgz", DestinationsFile: "missing.txt"}
+		err := opts.Validate(p.Config)
+		tests.RequireErrorContains(t, err, "unable to read --destinations-file missing.txt")
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		opts := PublishOptions{ArchiveFile: "mybuns.tgz", References: []string{"reg1.example.com/mybuns:v0.1.0", "INVALID"}}
+		err := opts.Validate(p.Config)
+		tests.RequireErrorContains(t, err, "invalid destination INVALID")
+	})
+
+	t.Run("multiple destinations without an archive", func(t *testing.T) {
+		opts := PublishOptions{References: []string{"reg1.example.com/mybuns:v0.1.0", "reg2.example.com/mybuns:v0.1.0"}}
+		err := opts.Validate(p.Config)
+		require.EqualError(t, err, "publishing to multiple destinations is only supported when publishing from an archive with --archive")
+	})
+}
+
 func TestPublish_validateTag(t *testing.T) {
 	t.Run("tag is a Docker tag", func(t *testing.T) {
 		opts := PublishOptions{
@@ -203,6 +267,51 @@ func (m mockRegistryLayout) Find(n image.Name) (image.Digest, error) {
 	return m.expectedDigest, nil
 }
 
+func TestPublish_PublishArchivedBundle_MultipleDestinations(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	imgDigest, err := image.NewDigest("sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687")
+	require.NoError(t, err)
+	archivedBundle := cnab.BundleReference{
+		Definition: cnab.NewBundle(bundle.Bundle{
+			InvocationImages: []bundle.InvocationImage{{BaseImage: bundle.BaseImage{Image: "myorg/mybuns-installer:v0.1.0"}}},
+			Images: map[string]bundle.Image{
+				"training-data": {BaseImage: bundle.BaseImage{Image: "example.com/datasets/training:v1"}},
+			},
+		}),
+		RelocationMap: relocation.ImageRelocationMap{"myorg/mybuns-installer:v0.1.0": "example.com/mybuns@sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687"},
+	}
+	omittedImages := map[string]string{"training-data": "example.com/datasets/training:v1"}
+
+	var pushed []string
+	p.TestRegistry.MockPushBundle = func(ctx context.Context, ref cnab.BundleReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+		pushed = append(pushed, ref.Reference.String())
+		return ref, nil
+	}
+
+	opts := PublishOptions{RelocationMapDir: "relocations"}
+	for _, dest := range []string{"reg1.example.com/mybuns:v0.1.0", "reg2.example.com/mybuns:v0.1.0"} {
+		bundleRef, err := p.publishArchivedBundle(ctx, opts, archivedBundle, cnab.MustParseOCIReference(dest), mockRegistryLayout{expectedDigest: imgDigest}, omittedImages)
+		require.NoError(t, err)
+		require.NoError(t, p.writeRelocationMap(opts.RelocationMapDir, bundleRef))
+	}
+	assert.Equal(t, []string{"reg1.example.com/mybuns:v0.1.0", "reg2.example.com/mybuns:v0.1.0"}, pushed)
+
+	// Each destination should have its own relocation mapping
+	data, err := p.FileSystem.ReadFile("relocations/reg2.example.com_mybuns_v0.1.0.json")
+	require.NoError(t, err)
+	var relocationMap relocation.ImageRelocationMap
+	require.NoError(t, json.Unmarshal(data, &relocationMap))
+	assert.Equal(t, relocation.ImageRelocationMap{
+		"myorg/mybuns-installer:v0.1.0": "reg2.example.com/mybuns@sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687",
+	}, relocationMap, "the omitted image should not be relocated")
+	assert.Equal(t, "example.com/mybuns@sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687",
+		archivedBundle.RelocationMap["myorg/mybuns-installer:v0.1.0"], "the relocation mapping from the archive should not be modified")
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Wrote the relocation mapping for reg1.example.com/mybuns:v0.1.0")
+}
+
 func TestPublish_RefreshCachedBundle(t *testing.T) {
 	p := NewTestPorter(t)
 	defer p.Close()
@@ -213,11 +322,11 @@ func TestPublish_RefreshCachedBundle(t *testing.T) {
 	}
 
 	// No-Op; bundle does not yet exist in cache
-	err := p.refreshCachedBundle(bundleRef)
+	err := p.refreshCachedBundle(p.RootContext, bundleRef)
 	require.NoError(t, err, "should have not errored out if bundle does not yet exist in cache")
 
 	// Save bundle in cache
-	cachedBundle, err := p.Cache.StoreBundle(bundleRef)
+	cachedBundle, err := p.Cache.StoreBundle(context.Background(), bundleRef)
 	require.NoError(t, err, "should have successfully stored bundle")
 
 	// Get file mod time
@@ -226,7 +335,7 @@ func TestPublish_RefreshCachedBundle(t *testing.T) {
 	origBunPathTime := file.ModTime()
 
 	// Should refresh cache
-	err = p.refreshCachedBundle(bundleRef)
+	err = p.refreshCachedBundle(p.RootContext, bundleRef)
 	require.NoError(t, err, "should have successfully updated the cache")
 
 	// Get file mod time
@@ -257,11 +366,11 @@ func TestPublish_RefreshCachedBundle_OnlyWarning(t *testing.T) {
 		return cache.CachedBundle{}, errors.New("error trying to store bundle")
 	}
 
-	err := p.refreshCachedBundle(bundleRef)
+	err := p.refreshCachedBundle(p.RootContext, bundleRef)
 	require.NoError(t, err, "should have not errored out even if cache.StoreBundle does")
 
 	gotStderr := p.TestConfig.TestContext.GetError()
-	require.Equal(t, "warning: unable to update cache for bundle myreg/mybuns: error trying to store bundle\n", gotStderr)
+	require.Equal(t, "Unable to update cache for bundle myreg/mybuns: error trying to store bundle\n", gotStderr)
 }
 
 func TestPublish_RewriteImageWithDigest(t *testing.T) {
@@ -274,6 +383,20 @@ func TestPublish_RewriteImageWithDigest(t *testing.T) {
 	assert.Equal(t, "example/mybuns@sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687", digestedImg)
 }
 
+func TestPublish_ReadOmittedImages(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	omitted, err := p.readOmittedImages("/archive")
+	require.NoError(t, err, "an archive without omitted images should not be an error")
+	assert.Empty(t, omitted)
+
+	require.NoError(t, p.FileSystem.WriteFile("/archive/omitted-images.json", []byte(`{"training-data":"example.com/datasets/training:v1"}`), pkg.FileModeWritable))
+	omitted, err = p.readOmittedImages("/archive")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"training-data": "example.com/datasets/training:v1"}, omitted)
+}
+
 func TestPublish_ForceOverwrite(t *testing.T) {
 	t.Parallel()
 
@@ -324,3 +447,129 @@ func TestPublish_ForceOverwrite(t *testing.T) {
 		})
 	}
 }
+
+func TestPublish_MultiPlatformImage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	p.TestConfig.TestContext.AddTestFile("./testdata/porter.yaml", config.Name)
+
+	buildOpts := BuildOptions{}
+	buildOpts.Platforms = []string{"linux/amd64", "linux/arm64"}
+	require.NoError(t, buildOpts.Validate(p.Porter))
+	require.NoError(t, p.Build(ctx, buildOpts))
+
+	// Simulate the OCI image layout generated when building for multiple platforms
+	imageLayout := filepath.Join(p.BundleDir, build.LOCAL_IMAGE_LAYOUT)
+	require.NoError(t, p.FileSystem.MkdirAll(imageLayout, pkg.FileModeDirectory))
+
+	p.TestRegistry.MockGetCachedImage = func(ctx context.Context, ref cnab.OCIReference) (cnabtooci.ImageSummary, error) {
+		return cnabtooci.ImageSummary{}, errors.New("the local image cache should not be used for a multi-platform image")
+	}
+	p.TestRegistry.MockPushImage = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (digest.Digest, error) {
+		return "", errors.New("the image should be pushed as an image index")
+	}
+	var pushedLayout string
+	p.TestRegistry.MockPushImageIndex = func(ctx context.Context, ref cnab.OCIReference, layoutPath string, opts cnabtooci.RegistryOptions) (digest.Digest, error) {
+		pushedLayout = layoutPath
+		return "sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687", nil
+	}
+	var pushedBundle cnab.BundleReference
+	p.TestRegistry.MockPushBundle = func(ctx context.Context, ref cnab.BundleReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+		pushedBundle = ref
+		return ref, nil
+	}
+
+	opts := PublishOptions{}
+	require.NoError(t, opts.Validate(p.Config))
+	require.NoError(t, p.Publish(ctx, opts))
+
+	assert.Equal(t, filepath.Join(opts.Dir, build.LOCAL_IMAGE_LAYOUT), pushedLayout, "the image index should be pushed from the OCI image layout")
+	require.Len(t, pushedBundle.Definition.InvocationImages, 1)
+	assert.Equal(t, "sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687", pushedBundle.Definition.InvocationImages[0].Digest,
+		"the bundle should reference the digest of the image index")
+}
+
+func TestPublish_UpToDate(t *testing.T) {
+	t.Parallel()
+
+	const imgDigest = "sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687"
+
+	testcases := []struct {
+		name    string
+		modify  func(bun *cnab.ExtendedBundle)
+		wantErr string
+	}{
+		{name: "identical bundle"},
+		{name: "different bundle", modify: func(bun *cnab.ExtendedBundle) {
+			bun.Description = "an older version of the bundle"
+		}, wantErr: "already exists in the destination registry"},
+		{name: "different invocation image", modify: func(bun *cnab.ExtendedBundle) {
+			bun.InvocationImages[0].Digest = "sha256:75c495e5ce9c428d482973d72e3ce9925e1db304a97946c9aa0b540d7537e041"
+		}, wantErr: "already exists in the destination registry"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			p := NewTestPorter(t)
+			defer p.Close()
+
+			p.TestConfig.TestContext.AddTestFile("./testdata/porter.yaml", config.Name)
+
+			// Publish the bundle and remember what was pushed
+			var published cnab.BundleReference
+			p.TestRegistry.MockPushImage = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (digest.Digest, error) {
+				return imgDigest, nil
+			}
+			p.TestRegistry.MockPushBundle = func(ctx context.Context, ref cnab.BundleReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+				published = ref
+				return ref, nil
+			}
+			opts := PublishOptions{}
+			require.NoError(t, opts.Validate(p.Config))
+			require.NoError(t, p.Publish(ctx, opts))
+
+			// Publish it again, now that it exists in the registry
+			p.TestRegistry.MockGetBundleMetadata = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (cnabtooci.BundleMetadata, error) {
+				return cnabtooci.BundleMetadata{BundleReference: published}, nil
+			}
+			p.TestRegistry.MockPullBundle = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+				bun := cnab.NewBundle(published.Definition.Bundle)
+				bun.InvocationImages = append([]bundle.InvocationImage(nil), published.Definition.InvocationImages...)
+				if tc.modify != nil {
+					tc.modify(&bun)
+				}
+				return cnab.BundleReference{Reference: ref, Definition: bun}, nil
+			}
+			p.TestRegistry.MockGetCachedImage = func(ctx context.Context, ref cnab.OCIReference) (cnabtooci.ImageSummary, error) {
+				return cnabtooci.NewImageSummary(ref.String(), types.ImageInspect{
+					ID:          "test",
+					RepoDigests: []string{ref.Repository() + "@" + imgDigest},
+				})
+			}
+			p.TestRegistry.MockPushImage = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (digest.Digest, error) {
+				return "", errors.New("the invocation image should not be pushed again")
+			}
+			p.TestRegistry.MockPushBundle = func(ctx context.Context, ref cnab.BundleReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+				return cnab.BundleReference{}, errors.New("the bundle should not be pushed again")
+			}
+
+			opts = PublishOptions{}
+			require.NoError(t, opts.Validate(p.Config))
+			err := p.Publish(ctx, opts)
+			if tc.wantErr != "" {
+				tests.RequireErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "is up to date")
+		})
+	}
+}
@@ -43,8 +43,14 @@ func (b *BundlePullOptions) validateReference() error {
 // pulled and stored in the cache. The path to the cached bundle is returned.
 func (p *Porter) PullBundle(ctx context.Context, opts BundlePullOptions) (cache.CachedBundle, error) {
 	resolver := BundleResolver{
-		Cache:    p.Cache,
-		Registry: p.Registry,
+		Cache:       p.Cache,
+		Registry:    p.Registry,
+		Mirrors:     p.Data.RegistryMirrors,
+		RegistryTLS: p.Data.RegistryTLS,
 	}
-	return resolver.Resolve(ctx, opts)
+
+	spinner := p.NewUI().StartSpinner(fmt.Sprintf("Pulling %s", opts.Reference))
+	cachedBundle, err := resolver.Resolve(ctx, opts)
+	spinner.Stop(err)
+	return cachedBundle, err
 }
@@ -4,9 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 
-	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/storage"
 	"get.porter.sh/porter/pkg/tracing"
 	"get.porter.sh/porter/pkg/yaml"
@@ -24,6 +22,10 @@ type ReconcileOptions struct {
 
 	// DryRun only checks if the changes would trigger a bundle run
 	DryRun bool
+
+	// RefreshBundle pulls the bundle again instead of using the cached bundle,
+	// so that a tag that was moved to a new digest is detected.
+	RefreshBundle bool
 }
 
 // ReconcileInstallation compares the desired state of an installation
@@ -36,46 +38,17 @@ func (p *Porter) ReconcileInstallation(ctx context.Context, opts ReconcileOption
 	ctx, log := tracing.StartSpan(ctx)
 	log.Debugf("Reconciling %s/%s installation", opts.Namespace, opts.Name)
 
-	// Get the last run of the installation, if available
-	var lastRun *storage.Run
-	r, err := p.Installations.GetLastRun(ctx, opts.Namespace, opts.Name)
-	neverRun := errors.Is(err, storage.ErrNotFound{})
-	if err != nil && !neverRun {
-		return err
-	}
-	if !neverRun {
-		lastRun = &r
-	}
-
-	ref, ok, err := opts.Installation.Bundle.GetBundleReference()
-	if err != nil {
-		return log.Error(err)
-	}
-	if !ok {
-		instYaml, _ := yaml.Marshal(opts.Installation)
-		return log.Error(fmt.Errorf("the installation does not define a valid bundle reference.\n%s", instYaml))
-	}
-
-	// Configure the bundle action that we should execute IF IT'S OUT OF SYNC
-	var actionOpts BundleAction
-	if opts.Installation.IsInstalled() {
-		if opts.Installation.Uninstalled {
-			actionOpts = NewUninstallOptions()
-		} else {
-			actionOpts = NewUpgradeOptions()
+	if opts.Installation.Status.Suspended {
+		log.Infof("The installation is paused and will not be reconciled until it is resumed with porter installations resume %s", opts.Name)
+		if opts.DryRun {
+			return nil
 		}
-	} else {
-		actionOpts = NewInstallOptions()
+		// Save the desired state so that it is applied when the installation is resumed
+		return p.Installations.UpsertInstallation(ctx, opts.Installation)
 	}
 
-	lifecycleOpts := actionOpts.GetOptions()
-	lifecycleOpts.Reference = ref.String()
-	lifecycleOpts.Name = opts.Name
-	lifecycleOpts.Namespace = opts.Namespace
-	lifecycleOpts.CredentialIdentifiers = opts.Installation.CredentialSets
-	lifecycleOpts.ParameterSets = opts.Installation.ParameterSets
-
-	if err = p.applyActionOptionsToInstallation(ctx, actionOpts, &opts.Installation); err != nil {
+	lastRun, actionOpts, err := p.prepareReconcile(ctx, &opts.Installation, opts.RefreshBundle)
+	if err != nil {
 		return err
 	}
 
@@ -102,131 +75,107 @@ func (p *Porter) ReconcileInstallation(ctx context.Context, opts ReconcileOption
 	if opts.DryRun {
 		log.Info("Skipping bundle execution because --dry-run was specified")
 		return nil
-	} else {
-		if err = p.Installations.UpsertInstallation(ctx, opts.Installation); err != nil {
-			return err
-		}
+	}
+
+	unlock, err := p.lockInstallation(ctx, opts.Installation.Namespace, opts.Installation.Name, actionOpts.GetAction(), actionOpts.GetOptions().WaitForLock)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err = p.Installations.UpsertInstallation(ctx, opts.Installation); err != nil {
+		return err
 	}
 
 	return p.ExecuteAction(ctx, opts.Installation, actionOpts)
 }
 
-// IsInstallationInSync determines if the desired state of the installation matches
-// the state of the installation the last time it was modified.
-func (p *Porter) IsInstallationInSync(ctx context.Context, i storage.Installation, lastRun *storage.Run, action BundleAction) (bool, error) {
-	ctx, log := tracing.StartSpan(ctx)
-	defer log.EndSpan()
+// prepareReconcile retrieves the last run of the installation and configures
+// the bundle action that brings the installation in sync with its desired state.
+// When refreshBundle is true, the bundle is pulled again instead of using the cache.
+func (p *Porter) prepareReconcile(ctx context.Context, installation *storage.Installation, refreshBundle bool) (*storage.Run, BundleAction, error) {
+	log := tracing.LoggerFromContext(ctx)
 
-	// Only print out info messages if we are triggering a bundle run. Otherwise, keep the explanations in debug output.
+	// Get the last run of the installation, if available
+	var lastRun *storage.Run
+	r, err := p.Installations.GetLastRun(ctx, installation.Namespace, installation.Name)
+	neverRun := errors.Is(err, storage.ErrNotFound{})
+	if err != nil && !neverRun {
+		return nil, nil, err
+	}
+	if !neverRun {
+		lastRun = &r
+	}
 
-	// Has it been uninstalled? If so, we don't ever reconcile it again
-	if i.IsUninstalled() {
-		log.Info("Ignoring because the installation is uninstalled")
-		return true, nil
+	ref, ok, err := installation.Bundle.GetBundleReference()
+	if err != nil {
+		return nil, nil, log.Error(err)
+	}
+	if !ok {
+		instYaml, _ := yaml.Marshal(installation)
+		return nil, nil, log.Error(fmt.Errorf("the installation does not define a valid bundle reference.\n%s", instYaml))
 	}
 
-	// Should we uninstall it?
-	if i.Uninstalled {
-		// Only try to uninstall if it's been installed before
-		if i.IsInstalled() {
-			log.Info("Triggering because installation.uninstalled is true")
-			return false, nil
+	// Configure the bundle action that we should execute IF IT'S OUT OF SYNC
+	var actionOpts BundleAction
+	if installation.IsInstalled() {
+		if installation.Uninstalled {
+			actionOpts = NewUninstallOptions()
+		} else {
+			actionOpts = NewUpgradeOptions()
 		}
-
-		// Otherwise ignore this installation
-		log.Info("Ignoring because installation.uninstalled is true but the installation doesn't exist yet")
-		return true, nil
 	} else {
-		// Should we install it?
-		if !i.IsInstalled() {
-			log.Info("Triggering because the installation has not completed successfully yet")
-			return false, nil
-		}
-	}
-
-	// We want to upgrade but we don't have values to compare against
-	// This shouldn't happen but check just in case
-	if lastRun == nil {
-		log.Info("Triggering because the last run for the installation wasn't recorded")
-		return false, nil
+		actionOpts = NewInstallOptions()
 	}
 
-	// Figure out if we need to upgrade
-	opts := action.GetOptions()
+	lifecycleOpts := actionOpts.GetOptions()
+	lifecycleOpts.Reference = ref.String()
+	lifecycleOpts.Force = refreshBundle
+	lifecycleOpts.Name = installation.Name
+	lifecycleOpts.Namespace = installation.Namespace
+	lifecycleOpts.CredentialIdentifiers = installation.CredentialSets
+	lifecycleOpts.ParameterSets = installation.ParameterSets
 
-	newRef, err := opts.GetBundleReference(ctx, p)
-	if err != nil {
-		return false, err
+	if err = p.applyActionOptionsToInstallation(ctx, actionOpts, installation); err != nil {
+		return nil, nil, err
 	}
 
-	// Has the bundle definition changed?
-	if lastRun.BundleDigest != newRef.Digest.String() {
-		log.Info("Triggering because the bundle definition has changed",
-			attribute.String("oldReference", lastRun.BundleReference),
-			attribute.String("oldDigest", lastRun.BundleDigest),
-			attribute.String("newReference", newRef.Reference.String()),
-			attribute.String("newDigest", newRef.Digest.String()))
-		return false, nil
-	}
-
-	// Convert parameters to a string to compare them. This avoids problems comparing
-	// values that may be equal but have different types due to how the parameter
-	// value was loaded.
-	b := newRef.Definition
-	prepParametersForComparison := func(params map[string]interface{}) (map[string]string, error) {
-		compParams := make(map[string]string, len(params))
-		for paramName, rawValue := range params {
-			if b.IsInternalParameter(paramName) {
-				continue
-			}
-
-			typedValue, err := b.ConvertParameterValue(paramName, rawValue)
-			if err != nil {
-				return nil, err
-			}
-
-			stringValue, err := b.WriteParameterToString(paramName, typedValue)
-			if err != nil {
-				return nil, err
-			}
-
-			compParams[paramName] = stringValue
-		}
-		return compParams, nil
-	}
+	return lastRun, actionOpts, nil
+}
 
-	lastRunParams, err := p.Sanitizer.RestoreParameterSet(ctx, lastRun.Parameters, cnab.NewBundle(lastRun.Bundle))
-	if err != nil {
-		return false, err
-	}
+// IsInstallationInSync determines if the desired state of the installation matches
+// the state of the installation the last time it was modified.
+func (p *Porter) IsInstallationInSync(ctx context.Context, i storage.Installation, lastRun *storage.Run, action BundleAction) (bool, error) {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
 
-	oldParams, err := prepParametersForComparison(lastRunParams)
+	diff, err := p.DiffInstallation(ctx, i, lastRun, action)
 	if err != nil {
-		return false, fmt.Errorf("error prepping old parameters for comparision: %w", err)
+		return false, err
 	}
 
-	newParams, err := prepParametersForComparison(opts.GetParameters())
-	if err != nil {
-		return false, fmt.Errorf("error prepping current parameters for comparision: %w", err)
-	}
+	// Only print out info messages if we are triggering a bundle run. Otherwise, keep the explanations in debug output.
+	for _, reason := range diff.Reasons {
+		if diff.InSync {
+			log.Info("Ignoring because " + reason)
+			continue
+		}
 
-	if !cmp.Equal(oldParams, newParams) {
-		diff := cmp.Diff(oldParams, newParams)
-		log.Info("Triggering because the parameters have changed",
-			attribute.String("diff", diff))
-		return false, nil
+		var attrs []attribute.KeyValue
+		switch reason {
+		case DiffReasonBundleChanged:
+			attrs = append(attrs,
+				attribute.String("oldReference", diff.Bundle.CurrentReference),
+				attribute.String("oldDigest", diff.Bundle.CurrentDigest),
+				attribute.String("newReference", diff.Bundle.ProposedReference),
+				attribute.String("newDigest", diff.Bundle.ProposedDigest))
+		case DiffReasonParametersChanged:
+			attrs = append(attrs, attribute.String("diff", diff.parametersDiff))
+		case DiffReasonCredentialSetsChanged:
+			attrs = append(attrs, attribute.String("diff", cmp.Diff(diff.CredentialSets.Current, diff.CredentialSets.Proposed)))
+		}
+		log.Info("Triggering because "+reason, attrs...)
 	}
 
-	// Check only if the names of the associated credential sets have changed
-	// This is a "good enough for now" decision that can be revisited if we
-	// get use cases for needing to diff the actual credentials.
-	sort.Strings(lastRun.CredentialSets)
-	sort.Strings(i.CredentialSets)
-	if !cmp.Equal(lastRun.CredentialSets, i.CredentialSets) {
-		diff := cmp.Diff(lastRun.CredentialSets, i.CredentialSets)
-		log.Info("Triggering because the credential set names have changed",
-			attribute.String("diff", diff))
-		return false, nil
-	}
-	return true, nil
+	return diff.InSync, nil
 }
@@ -191,3 +191,32 @@ func TestPorter_IsInstallationInSync(t *testing.T) {
 		assert.Contains(t, p.TestConfig.TestContext.GetError(), "Ignoring because the installation is uninstalled")
 	})
 }
+
+func TestPorter_ReconcileInstallation_Suspended(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	i := storage.NewInstallation("", "mybuns")
+	i.Bundle = storage.OCIReferenceParts{Repository: "ghcr.io/getporter/examples/porter-hello", Version: "0.2.0"}
+	i.Status.Suspended = true
+
+	opts := ReconcileOptions{Name: i.Name, Installation: i, DryRun: true}
+	err := p.ReconcileInstallation(p.RootContext, opts)
+	require.NoError(t, err)
+	assert.Contains(t, p.TestConfig.TestContext.GetError(), "The installation is paused and will not be reconciled")
+}
+
+func TestPorter_WatchInstallationApply(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.TestConfig.TestContext.AddTestFileContents([]byte("name: [oops"), "myapp.yaml")
+
+	// The watch stops at the first interval after the context is cancelled
+	ctx, cancel := context.WithCancel(p.RootContext)
+	cancel()
+
+	opts := ApplyOptions{File: "myapp.yaml", Watch: true, Interval: time.Hour}
+	err := p.InstallationApply(ctx, opts)
+	require.NoError(t, err, "failures should be logged instead of stopping the watch")
+	assert.Contains(t, p.TestConfig.TestContext.GetError(), "invalid file 'myapp.yaml'")
+}
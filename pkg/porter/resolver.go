@@ -2,16 +2,24 @@ package porter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"get.porter.sh/porter/pkg/cache"
 	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/tracing"
 )
 
 type BundleResolver struct {
 	Cache    cache.BundleCache
 	Registry cnabtooci.RegistryProvider
+
+	// Mirrors are used instead of the mirrored registry when pulling bundles.
+	Mirrors config.RegistryMirrors
+
+	// RegistryTLS are the TLS settings for specific registries.
+	RegistryTLS config.RegistryTLSConfigs
 }
 
 // Resolves a bundle from the cache, or pulls it and caches it
@@ -20,7 +28,7 @@ func (r *BundleResolver) Resolve(ctx context.Context, opts BundlePullOptions) (c
 	log := tracing.LoggerFromContext(ctx)
 
 	if !opts.Force {
-		cachedBundle, ok, err := r.Cache.FindBundle(opts.GetReference())
+		cachedBundle, ok, err := r.Cache.FindBundle(ctx, opts.GetReference())
 		if err != nil {
 			return cache.CachedBundle{}, log.Error(fmt.Errorf("unable to load bundle %s from cache: %w", opts.Reference, err))
 		}
@@ -30,13 +38,16 @@ func (r *BundleResolver) Resolve(ctx context.Context, opts BundlePullOptions) (c
 		}
 	}
 
-	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry}
+	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry, Mirrors: r.Mirrors, RegistryTLS: r.RegistryTLS}
 	bundleRef, err := r.Registry.PullBundle(ctx, opts.GetReference(), regOpts)
 	if err != nil {
+		if errors.Is(err, config.ErrOffline) && !opts.Force {
+			return cache.CachedBundle{}, fmt.Errorf("bundle %s is not in the local bundle cache: %w", opts.Reference, err)
+		}
 		return cache.CachedBundle{}, err
 	}
 
-	cb, err := r.Cache.StoreBundle(bundleRef)
+	cb, err := r.Cache.StoreBundle(ctx, bundleRef)
 	if err != nil {
 		return cache.CachedBundle{}, log.Errorf("error storing the bundle %s in the Porter bundle cache: %w", bundleRef, err)
 	}
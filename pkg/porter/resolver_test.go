@@ -102,3 +102,41 @@ func TestBundleResolver_Resolve_CacheMiss(t *testing.T) {
 	assert.True(t, cacheSearched, "The cache should be searched when force is not specified")
 	assert.True(t, pulled, "The bundle should have been pulled because the bundle was not in the cache")
 }
+
+func TestBundleResolver_Resolve_Offline(t *testing.T) {
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	tc.Data.Offline = true
+	testReg := cnabtooci.NewTestRegistry()
+	testCache := cache.NewTestCache(cache.New(tc.Config))
+	resolver := BundleResolver{
+		Cache:    testCache,
+		Registry: cnabtooci.NewOfflineRegistry(tc.Config, testReg),
+	}
+
+	cached := false
+	testCache.FindBundleMock = func(ref cnab.OCIReference) (cache.CachedBundle, bool, error) {
+		return cache.CachedBundle{BundleReference: cnab.BundleReference{Reference: ref}}, cached, nil
+	}
+
+	pulled := false
+	testReg.MockPullBundle = func(ctx context.Context, ref cnab.OCIReference, options cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+		pulled = true
+		return cnab.BundleReference{Reference: ref}, nil
+	}
+
+	t.Run("cache miss", func(t *testing.T) {
+		opts := BundlePullOptions{Reference: "ghcr.io/getporter/examples/porter-hello:v0.2.0"}
+		_, err := resolver.Resolve(ctx, opts)
+		require.ErrorIs(t, err, config.ErrOffline)
+		assert.Contains(t, err.Error(), "bundle ghcr.io/getporter/examples/porter-hello:v0.2.0 is not in the local bundle cache")
+		assert.False(t, pulled, "The bundle should NOT be pulled in offline mode")
+	})
+
+	t.Run("cache hit", func(t *testing.T) {
+		cached = true
+		opts := BundlePullOptions{Reference: "ghcr.io/getporter/examples/porter-hello:v0.2.0"}
+		_, err := resolver.Resolve(ctx, opts)
+		require.NoError(t, err, "The bundle should be resolved from the cache in offline mode")
+	})
+}
@@ -2,11 +2,16 @@ package porter
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"sort"
 	"time"
 
+	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
 	dtprinter "github.com/carolynvs/datetime-printer"
 )
 
@@ -14,6 +19,7 @@ import (
 type RunListOptions struct {
 	installationOptions
 	printer.PrintOptions
+	printer.ColumnOptions
 }
 
 // Validate prepares for the list installation runs action and validates the args/options.
@@ -29,7 +35,12 @@ func (so *RunListOptions) Validate(args []string, cxt *portercontext.Context) er
 		return err
 	}
 
-	return so.PrintOptions.Validate(ShowDefaultFormat, ShowAllowedFormats)
+	err = so.PrintOptions.Validate(ListDefaultFormat, ListAllowedFormats)
+	if err != nil {
+		return err
+	}
+
+	return validateColumnOptions(so.PrintOptions, so.ColumnOptions)
 }
 
 type DisplayRuns []DisplayRun
@@ -60,28 +71,31 @@ func (p *Porter) ListInstallationRuns(ctx context.Context, opts RunListOptions)
 	}
 
 	for _, run := range runs {
-		results := runResults[run.ID]
+		displayRuns = append(displayRuns, newDisplayRunWithResults(run, runResults[run.ID]))
+	}
+
+	return displayRuns, nil
+}
 
-		displayRun := NewDisplayRun(run)
+// newDisplayRunWithResults creates a DisplayRun with the status and timing of the run from its results.
+func newDisplayRunWithResults(run storage.Run, results []storage.Result) DisplayRun {
+	displayRun := NewDisplayRun(run)
 
-		if len(results) > 0 {
-			displayRun.Status = results[len(results)-1].Status
+	if len(results) > 0 {
+		displayRun.Status = results[len(results)-1].Status
 
-			switch len(results) {
-			case 2:
-				displayRun.Started = results[0].Created
-				displayRun.Stopped = &results[1].Created
-			case 1:
-				displayRun.Started = results[0].Created
-			default:
-				displayRun.Stopped = &results[len(results)-1].Created
-			}
+		switch len(results) {
+		case 2:
+			displayRun.Started = results[0].Created
+			displayRun.Stopped = &results[1].Created
+		case 1:
+			displayRun.Started = results[0].Created
+		default:
+			displayRun.Stopped = &results[len(results)-1].Created
 		}
-
-		displayRuns = append(displayRuns, displayRun)
 	}
 
-	return displayRuns, nil
+	return displayRun
 }
 
 func (p *Porter) PrintInstallationRuns(ctx context.Context, opts RunListOptions) error {
@@ -92,33 +106,253 @@ func (p *Porter) PrintInstallationRuns(ctx context.Context, opts RunListOptions)
 
 	sort.Sort(sort.Reverse(displayRuns))
 
+	// Sort by the csv values so that timestamps are compared, instead of relative times
+	csvRow :=
+		func(v interface{}) []string {
+			a, ok := v.(DisplayRun)
+			if !ok {
+				return nil
+			}
+			return getDisplayRunRow(a, formatCsvTime, true)
+		}
+	if err := opts.SortRows(displayRuns, csvRow, getDisplayRunHeaders(true)...); err != nil {
+		return err
+	}
+
 	switch opts.Format {
 	case printer.FormatJson:
 		return printer.PrintJson(p.Out, displayRuns)
 	case printer.FormatYaml:
 		return printer.PrintYaml(p.Out, displayRuns)
-	case printer.FormatPlaintext:
-		now := time.Now()
-		tp := dtprinter.DateTimePrinter{
-			Now: func() time.Time { return now },
-		}
+	case printer.FormatPlaintext, printer.FormatWide:
+		return p.printDisplayRunsTable(displayRuns, opts.Format == printer.FormatWide, opts.ColumnOptions)
+	case printer.FormatCsv:
+		return opts.PrintCsv(p.Out, displayRuns, csvRow, getDisplayRunHeaders(true)...)
+	}
+
+	return nil
+}
 
-		row :=
-			func(v interface{}) []string {
-				a, ok := v.(DisplayRun)
-				if !ok {
-					return nil
-				}
+// printDisplayRunsTable prints the selected columns of the runs of an installation as a table.
+func (p *Porter) printDisplayRunsTable(displayRuns DisplayRuns, wide bool, columns printer.ColumnOptions) error {
+	// Selected columns may include those only printed in the wide output
+	wide = wide || columns.HasColumns()
 
-				stopped := ""
-				if a.Stopped != nil {
-					stopped = tp.Format(*a.Stopped)
-				}
+	now := time.Now()
+	tp := dtprinter.DateTimePrinter{
+		Now: func() time.Time { return now },
+	}
 
-				return []string{a.ID, a.Action, tp.Format(a.Started), stopped, a.Status}
+	ui := p.newUI(p.Out)
+	row :=
+		func(v interface{}) []string {
+			a, ok := v.(DisplayRun)
+			if !ok {
+				return nil
 			}
-		return printer.PrintTable(p.Out, displayRuns, row, "Run ID", "Action", "Started", "Stopped", "Status")
+			a.Status = ui.FormatStatus(a.Status)
+			return getDisplayRunRow(a, tp.Format, wide)
+		}
+	return columns.PrintTable(p.Out, displayRuns, row, getDisplayRunHeaders(wide)...)
+}
+
+// getDisplayRunHeaders returns the column headers used when listing runs.
+// The wide output adds the bundle reference and version used by the run.
+func getDisplayRunHeaders(wide bool) []string {
+	headers := []string{"Run ID", "Action", "Started", "Stopped", "Status"}
+	if wide {
+		headers = append(headers, "Bundle", "Version")
+	}
+	return headers
+}
+
+func getDisplayRunRow(a DisplayRun, formatTime func(time.Time) string, wide bool) []string {
+	stopped := ""
+	if a.Stopped != nil {
+		stopped = formatTime(*a.Stopped)
 	}
 
-	return nil
+	row := []string{a.ID, a.Action, formatTime(a.Started), stopped, a.Status}
+	if wide {
+		row = append(row, a.Bundle, a.Version)
+	}
+	return row
+}
+
+// RunShowOptions represent options for showing a run of an installation
+type RunShowOptions struct {
+	printer.PrintOptions
+
+	// RunID is the id of the run to show.
+	RunID string
+}
+
+// Validate prepares for the show installation run action and validates the args/options.
+func (o *RunShowOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly one positional argument, the run id, but %d were received", len(args))
+	}
+	o.RunID = args[0]
+
+	return o.PrintOptions.Validate(ShowDefaultFormat, ShowAllowedFormats)
+}
+
+// DisplayRunDetails is a run of an installation, including how long each
+// step of the bundle took to execute.
+type DisplayRunDetails struct {
+	DisplayRunSummary `yaml:",inline"`
+
+	Namespace    string `json:"namespace" yaml:"namespace"`
+	Installation string `json:"installation" yaml:"installation"`
+	Bundle       string `json:"bundle,omitempty" yaml:"bundle,omitempty"`
+
+	// ExecutionDuration is how long the bundle took to execute, excluding the
+	// time spent preparing to run the bundle.
+	ExecutionDuration string `json:"executionDuration,omitempty" yaml:"executionDuration,omitempty"`
+
+	// TimedOut indicates that the bundle was stopped because the action, or one
+	// of its steps, did not complete in time.
+	TimedOut bool `json:"timedOut,omitempty" yaml:"timedOut,omitempty"`
+
+	// Steps executed by the bundle, in the order that they were executed.
+	Steps []DisplayStepMetric `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+// DisplayStepMetric is how long a step of the bundle took to execute.
+type DisplayStepMetric struct {
+	cnab.StepMetric `yaml:",inline"`
+
+	// Duration of the step.
+	Duration string `json:"duration" yaml:"duration"`
+
+	// Percent of the time spent executing all the steps that was spent on this step.
+	Percent float64 `json:"percent" yaml:"percent"`
+}
+
+// GetInstallationRun retrieves a run of an installation, along with the
+// timing of its steps.
+func (p *Porter) GetInstallationRun(ctx context.Context, opts RunShowOptions) (DisplayRunDetails, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	run, err := p.Installations.GetRun(ctx, opts.RunID)
+	if err != nil {
+		return DisplayRunDetails{}, span.Error(fmt.Errorf("could not retrieve run %s: %w", opts.RunID, err))
+	}
+
+	results, err := p.Installations.ListResults(ctx, run.ID)
+	if err != nil {
+		return DisplayRunDetails{}, span.Error(fmt.Errorf("could not list the results of run %s: %w", run.ID, err))
+	}
+
+	details := newDisplayRunDetails(run, results)
+	if err = p.setRunErrorFromLogs(ctx, &details.DisplayRunSummary); err != nil {
+		return DisplayRunDetails{}, span.Error(err)
+	}
+	return details, nil
+}
+
+// newDisplayRunDetails creates a DisplayRunDetails from the run and its
+// results, which must be sorted from oldest to newest. The step metrics are
+// recorded on the final result of the run.
+func newDisplayRunDetails(run storage.Run, results []storage.Result) DisplayRunDetails {
+	details := DisplayRunDetails{
+		DisplayRunSummary: newDisplayRunSummary(run, results),
+		Namespace:         run.Namespace,
+		Installation:      run.Installation,
+		Bundle:            run.BundleReference,
+	}
+	if len(results) == 0 {
+		return details
+	}
+
+	last := results[len(results)-1]
+	details.TimedOut = last.TimedOut
+	if last.Started != nil && last.Stopped != nil {
+		details.ExecutionDuration = last.Stopped.Sub(*last.Started).Round(time.Second).String()
+	}
+
+	var total time.Duration
+	for _, step := range last.Steps {
+		total += step.Duration()
+	}
+	for _, step := range last.Steps {
+		displayStep := DisplayStepMetric{
+			StepMetric: step,
+			Duration:   step.Duration().Round(time.Millisecond).String(),
+		}
+		if total > 0 {
+			// Round to one decimal place
+			displayStep.Percent = math.Round(float64(step.Duration())/float64(total)*1000) / 10
+		}
+		details.Steps = append(details.Steps, displayStep)
+	}
+
+	return details
+}
+
+// PrintInstallationRun prints a run of an installation with a breakdown of
+// how long each step took to execute.
+func (p *Porter) PrintInstallationRun(ctx context.Context, opts RunShowOptions) error {
+	details, err := p.GetInstallationRun(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, details)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, details)
+	case printer.FormatPlaintext:
+		return p.printDisplayRunDetails(details)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+func (p *Porter) printDisplayRunDetails(details DisplayRunDetails) error {
+	now := time.Now()
+	tp := dtprinter.DateTimePrinter{
+		Now: func() time.Time { return now },
+	}
+
+	fmt.Fprintf(p.Out, "Run ID: %s\n", details.ID)
+	fmt.Fprintf(p.Out, "Installation: %s\n", storage.InstallationSpec{Namespace: details.Namespace, Name: details.Installation})
+	fmt.Fprintf(p.Out, "Bundle: %s\n", details.Bundle)
+	fmt.Fprintf(p.Out, "Action: %s\n", details.Action)
+	if details.TimedOut {
+		fmt.Fprintf(p.Out, "Status: %s (timed out)\n", details.Status)
+	} else {
+		fmt.Fprintf(p.Out, "Status: %s\n", details.Status)
+	}
+	fmt.Fprintf(p.Out, "Started: %s\n", tp.Format(details.Started))
+	if details.Stopped != nil {
+		fmt.Fprintf(p.Out, "Stopped: %s\n", tp.Format(*details.Stopped))
+	}
+	if details.Duration != "" {
+		fmt.Fprintf(p.Out, "Duration: %s\n", details.Duration)
+	}
+	if details.ExecutionDuration != "" {
+		fmt.Fprintf(p.Out, "Execution Duration: %s\n", details.ExecutionDuration)
+	}
+	if details.Error != "" {
+		fmt.Fprintf(p.Out, "Error: %s\n", details.Error)
+	}
+
+	if len(details.Steps) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(p.Out)
+	fmt.Fprintln(p.Out, "Steps:")
+	row :=
+		func(v interface{}) []string {
+			step, ok := v.(DisplayStepMetric)
+			if !ok {
+				return nil
+			}
+			return []string{step.Description, step.Mixin, step.Duration, fmt.Sprintf("%.1f%%", step.Percent), step.Status}
+		}
+	return printer.PrintTable(p.Out, details.Steps, row, "Step", "Mixin", "Duration", "Percent", "Status")
 }
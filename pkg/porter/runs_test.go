@@ -104,3 +104,81 @@ func TestPorter_PrintInstallationRunsOutput(t *testing.T) {
 
 	}
 }
+
+func TestRunShowOptions_Validate(t *testing.T) {
+	opts := RunShowOptions{}
+	require.NoError(t, opts.Validate([]string{"abc123"}))
+	assert.Equal(t, "abc123", opts.RunID)
+	assert.Equal(t, printer.FormatPlaintext, opts.Format)
+
+	opts = RunShowOptions{}
+	require.EqualError(t, opts.Validate(nil), "expected exactly one positional argument, the run id, but 0 were received")
+}
+
+func TestNewDisplayRunDetails(t *testing.T) {
+	i := storage.NewInstallation("dev", "mysql")
+	run := i.NewRun(cnab.ActionInstall)
+	run.ID = "1"
+	run.Created = now
+	run.BundleReference = "example.com/mysql:v1.0.0"
+
+	running := run.NewResult(cnab.StatusRunning)
+	running.Created = now
+	succeeded := run.NewResult(cnab.StatusSucceeded)
+	succeeded.Created = now.Add(time.Minute)
+	started := now.Add(5 * time.Second)
+	stopped := now.Add(55 * time.Second)
+	succeeded.Started = &started
+	succeeded.Stopped = &stopped
+	succeeded.Steps = []cnab.StepMetric{
+		{Description: "Create database", Mixin: "helm3", Started: started, Stopped: started.Add(30 * time.Second), Status: cnab.StatusSucceeded},
+		{Description: "Seed data", Mixin: "exec", Started: started.Add(30 * time.Second), Stopped: stopped, Status: cnab.StatusSucceeded},
+	}
+
+	details := newDisplayRunDetails(run, []storage.Result{running, succeeded})
+	assert.Equal(t, "dev", details.Namespace)
+	assert.Equal(t, "mysql", details.Installation)
+	assert.Equal(t, "example.com/mysql:v1.0.0", details.Bundle)
+	assert.Equal(t, cnab.StatusSucceeded, details.Status)
+	assert.Equal(t, "1m0s", details.Duration)
+	assert.Equal(t, "50s", details.ExecutionDuration)
+
+	require.Len(t, details.Steps, 2)
+	assert.Equal(t, "Create database", details.Steps[0].Description)
+	assert.Equal(t, "30s", details.Steps[0].Duration)
+	assert.Equal(t, 60.0, details.Steps[0].Percent)
+	assert.Equal(t, "20s", details.Steps[1].Duration)
+	assert.Equal(t, 40.0, details.Steps[1].Percent)
+
+	t.Run("timed out", func(t *testing.T) {
+		failed := run.NewResult(cnab.StatusFailed)
+		failed.TimedOut = true
+		details := newDisplayRunDetails(run, []storage.Result{running, failed})
+		assert.Equal(t, cnab.StatusFailed, details.Status)
+		assert.True(t, details.TimedOut)
+	})
+
+	t.Run("no step metrics", func(t *testing.T) {
+		details := newDisplayRunDetails(run, []storage.Result{running})
+		assert.Empty(t, details.Steps)
+		assert.Empty(t, details.ExecutionDuration)
+	})
+}
+
+func TestGetDisplayRunRow(t *testing.T) {
+	stopped := now.Add(time.Minute)
+	run := DisplayRun{
+		ID:      "01FZVC5AVP8Z7A78CSCP1EJ604",
+		Bundle:  "example.com/wordpress:v1.2.3",
+		Version: "v1.2.3",
+		Action:  cnab.ActionInstall,
+		Started: now,
+		Stopped: &stopped,
+		Status:  cnab.StatusSucceeded,
+	}
+
+	assert.Equal(t, []string{"01FZVC5AVP8Z7A78CSCP1EJ604", "install", "2020-04-18T01:02:03Z", "2020-04-18T01:03:03Z", "succeeded"},
+		getDisplayRunRow(run, formatCsvTime, false))
+	assert.Equal(t, []string{"01FZVC5AVP8Z7A78CSCP1EJ604", "install", "2020-04-18T01:02:03Z", "2020-04-18T01:03:03Z", "succeeded", "example.com/wordpress:v1.2.3", "v1.2.3"},
+		getDisplayRunRow(run, formatCsvTime, true))
+}
@@ -0,0 +1,106 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"get.porter.sh/porter/pkg/build"
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/sbom"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// annotationCreated is the OCI annotation for when an artifact was created.
+const annotationCreated = "org.opencontainers.image.created"
+
+// generateSBOM generates a software bill of materials for the invocation image,
+// and saves it in the .cnab directory so that it is attached to the image when
+// the bundle is published.
+func (p *Porter) generateSBOM(ctx context.Context, image string, format string) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	p.printInfof("Generating %s SBOM for %s...\n", format, image)
+	dest := filepath.Join(build.LOCAL_CNAB, sbom.GetFileName(format))
+	return sbom.NewGenerator(p.Context).Generate(ctx, image, format, dest)
+}
+
+// attachSBOM attaches the SBOMs generated when the bundle was built, to the
+// published invocation image. The image reference should include its digest.
+func (p *Porter) attachSBOM(ctx context.Context, dir string, imgRef cnab.OCIReference, regOpts cnabtooci.RegistryOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	for _, format := range sbom.AllowedFormats {
+		sbomFile := filepath.Join(dir, build.LOCAL_CNAB, sbom.GetFileName(format))
+		exists, err := p.FileSystem.Exists(sbomFile)
+		if err != nil {
+			return log.Errorf("error checking for the SBOM %s: %w", sbomFile, err)
+		}
+		if !exists {
+			continue
+		}
+
+		content, err := p.FileSystem.ReadFile(sbomFile)
+		if err != nil {
+			return log.Errorf("error reading the SBOM %s: %w", sbomFile, err)
+		}
+
+		artifact := cnabtooci.Artifact{
+			ArtifactType: sbom.GetArtifactType(format),
+			Content:      content,
+			Annotations:  map[string]string{annotationCreated: time.Now().UTC().Format(time.RFC3339)},
+		}
+		if _, err = p.Registry.PushReferrer(ctx, imgRef, artifact, regOpts); err != nil {
+			return log.Errorf("unable to attach the %s SBOM to %s: %w", format, imgRef, err)
+		}
+		fmt.Fprintf(p.Out, "Attached the %s SBOM to %s\n", format, imgRef)
+	}
+	return nil
+}
+
+// printSBOM prints the SBOM attached to the invocation images of a published bundle.
+func (p *Porter) printSBOM(ctx context.Context, bundleRef cnab.BundleReference, opts InspectOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	imgRefs, err := getInvocationImageReferences(bundleRef)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	formats := sbom.AllowedFormats
+	if opts.SBOMFormat != "" {
+		formats = []string{opts.SBOMFormat}
+	}
+
+	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry, RegistryTLS: p.Data.RegistryTLS}
+	for _, imgRef := range imgRefs {
+		artifact, err := p.pullSBOM(ctx, imgRef, formats, regOpts)
+		if err != nil {
+			return log.Error(err)
+		}
+		fmt.Fprintln(p.Out, string(artifact.Content))
+	}
+	return nil
+}
+
+// pullSBOM returns the first SBOM attached to the image, in order of the
+// specified formats.
+func (p *Porter) pullSBOM(ctx context.Context, imgRef cnab.OCIReference, formats []string, regOpts cnabtooci.RegistryOptions) (cnabtooci.Artifact, error) {
+	for _, format := range formats {
+		artifact, err := p.Registry.PullReferrer(ctx, imgRef, sbom.GetArtifactType(format), regOpts)
+		if err != nil {
+			if errors.Is(err, cnabtooci.ErrNotFound{}) {
+				continue
+			}
+			return cnabtooci.Artifact{}, fmt.Errorf("unable to retrieve the %s SBOM for %s: %w", format, imgRef, err)
+		}
+		return artifact, nil
+	}
+	return cnabtooci.Artifact{}, fmt.Errorf("no SBOM is attached to the invocation image %s. Build the bundle with porter build --sbom and publish it to generate one", imgRef)
+}
@@ -0,0 +1,188 @@
+package porter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/build"
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/sbom"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/docker/docker/api/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOptions_validateSBOM(t *testing.T) {
+	testcases := []struct {
+		name       string
+		opts       BuildOptions
+		wantFormat string
+		wantErr    string
+	}{
+		{name: "no sbom"},
+		{name: "default format", opts: BuildOptions{SBOM: true}, wantFormat: sbom.FormatSPDX},
+		{name: "cyclonedx", opts: BuildOptions{SBOM: true, SBOMFormat: sbom.FormatCycloneDX}, wantFormat: sbom.FormatCycloneDX},
+		{name: "invalid format", opts: BuildOptions{SBOM: true, SBOMFormat: "syft-json"}, wantErr: "invalid --sbom-format value"},
+		{name: "format without sbom", opts: BuildOptions{SBOMFormat: sbom.FormatSPDX}, wantErr: "--sbom-format can only be used with --sbom"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.validateSBOM()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantFormat, tc.opts.SBOMFormat)
+		})
+	}
+
+	t.Run("multiple platforms", func(t *testing.T) {
+		opts := BuildOptions{SBOM: true}
+		opts.Platforms = []string{"linux/amd64", "linux/arm64"}
+		require.ErrorContains(t, opts.validateSBOM(), "--sbom cannot be used when building the invocation image for multiple platforms")
+	})
+}
+
+func TestPorter_Build_SBOM(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("generated", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFile("./testdata/porter.yaml", config.Name)
+
+		opts := BuildOptions{SBOM: true}
+		require.NoError(t, opts.Validate(p.Porter))
+		require.NoError(t, p.Build(ctx, opts))
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Generating spdx SBOM for localhost:5000/porter-hello")
+	})
+
+	t.Run("syft fails", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFile("./testdata/porter.yaml", config.Name)
+		p.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+
+		opts := BuildOptions{SBOM: true}
+		require.NoError(t, opts.Validate(p.Porter))
+		err := p.Build(ctx, opts)
+		require.ErrorContains(t, err, "could not generate the SBOM")
+	})
+}
+
+func TestPublish_SBOM(t *testing.T) {
+	t.Parallel()
+
+	const imgDigest = "sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687"
+
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.TestConfig.TestContext.AddTestFile("./testdata/porter.yaml", config.Name)
+
+	buildOpts := BuildOptions{}
+	require.NoError(t, buildOpts.Validate(p.Porter))
+	require.NoError(t, p.Build(ctx, buildOpts))
+
+	// Simulate the SBOM generated by porter build --sbom
+	sbomFile := filepath.Join(p.BundleDir, build.LOCAL_CNAB, sbom.GetFileName(sbom.FormatSPDX))
+	require.NoError(t, p.FileSystem.WriteFile(sbomFile, []byte(`{"spdxVersion":"SPDX-2.3"}`), pkg.FileModeWritable))
+
+	// The invocation image is in the local cache, so that the bundle is not rebuilt
+	p.TestRegistry.MockGetCachedImage = func(ctx context.Context, ref cnab.OCIReference) (cnabtooci.ImageSummary, error) {
+		return cnabtooci.NewImageSummary(ref.String(), types.ImageInspect{ID: "test"})
+	}
+	p.TestRegistry.MockPushImage = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (digest.Digest, error) {
+		return imgDigest, nil
+	}
+	var attachedTo cnab.OCIReference
+	var attached cnabtooci.Artifact
+	p.TestRegistry.MockPushReferrer = func(ctx context.Context, subject cnab.OCIReference, artifact cnabtooci.Artifact, opts cnabtooci.RegistryOptions) (digest.Digest, error) {
+		attachedTo = subject
+		attached = artifact
+		return digest.FromBytes(artifact.Content), nil
+	}
+
+	opts := PublishOptions{}
+	require.NoError(t, opts.Validate(p.Config))
+	require.NoError(t, p.Publish(ctx, opts))
+
+	assert.Equal(t, "localhost:5000/porter-hello@"+imgDigest, attachedTo.String(), "the SBOM should be attached to the invocation image by digest")
+	assert.Equal(t, sbom.ArtifactTypeSPDX, attached.ArtifactType)
+	assert.Equal(t, `{"spdxVersion":"SPDX-2.3"}`, string(attached.Content))
+	assert.Contains(t, attached.Annotations, annotationCreated)
+}
+
+func TestInspectOptions_Validate_SBOM(t *testing.T) {
+	t.Run("sbom", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := InspectOptions{SBOM: true, SBOMFormat: sbom.FormatCycloneDX}
+		require.NoError(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}, p.Context))
+	})
+
+	t.Run("sbom without a reference", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.TestConfig.TestContext.AddTestFile("./testdata/porter.yaml", config.Name)
+
+		opts := InspectOptions{SBOM: true}
+		require.ErrorContains(t, opts.Validate(nil, p.Context), "--sbom requires a bundle reference")
+	})
+
+	t.Run("format without sbom", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := InspectOptions{SBOMFormat: sbom.FormatSPDX}
+		require.ErrorContains(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}, p.Context), "--sbom-format can only be used with --sbom")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := InspectOptions{SBOM: true, SBOMFormat: "syft-json"}
+		require.ErrorContains(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}, p.Context), "invalid --sbom-format value")
+	})
+}
+
+func TestPorter_printSBOM(t *testing.T) {
+	ctx := context.Background()
+	bundleRef := newSignedBundleReference()
+
+	t.Run("first sbom found", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		var pulledFrom cnab.OCIReference
+		p.TestRegistry.MockPullReferrer = func(ctx context.Context, subject cnab.OCIReference, artifactType string, opts cnabtooci.RegistryOptions) (cnabtooci.Artifact, error) {
+			pulledFrom = subject
+			if artifactType != sbom.ArtifactTypeCycloneDX {
+				return cnabtooci.Artifact{}, cnabtooci.ErrNotFound{Reference: subject}
+			}
+			return cnabtooci.Artifact{ArtifactType: artifactType, Content: []byte(`{"bomFormat":"CycloneDX"}`)}, nil
+		}
+
+		require.NoError(t, p.printSBOM(ctx, bundleRef, InspectOptions{}))
+		assert.Equal(t, "example.com/mybuns@"+testImageDigest, pulledFrom.String())
+		assert.Equal(t, "{\"bomFormat\":\"CycloneDX\"}\n", p.TestConfig.TestContext.GetOutput())
+	})
+
+	t.Run("format not found", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		err := p.printSBOM(ctx, bundleRef, InspectOptions{SBOMFormat: sbom.FormatSPDX})
+		require.ErrorContains(t, err, "no SBOM is attached to the invocation image example.com/mybuns@"+testImageDigest)
+	})
+}
@@ -0,0 +1,155 @@
+package porter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/portercontext"
+)
+
+// validScaffoldName matches the names of mixins and plugins that can be used
+// as the name of a binary and, with dashes removed, a Go package.
+var validScaffoldName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
+
+// scaffoldData is the data available to the templates of a new mixin or
+// plugin project.
+type scaffoldData struct {
+	// Name of the mixin or plugin binary.
+	Name string
+
+	// Package is the name of the Go package that implements the mixin or plugin.
+	Package string
+
+	// Author of the mixin or plugin.
+	Author string
+
+	// Module is the path of the Go module of the project.
+	Module string
+
+	// Type of plugin: secrets or storage.
+	Type string
+
+	// Implementation is the name of the plugin implementation.
+	Implementation string
+
+	// Key that selects the plugin in the Porter config file.
+	Key string
+}
+
+func newScaffoldData(name string, author string, module string) scaffoldData {
+	pkgName := strings.ToLower(strings.ReplaceAll(name, "-", ""))
+	return scaffoldData{
+		Name:    name,
+		Package: pkgName,
+		Author:  author,
+		Module:  module,
+	}
+}
+
+// validateScaffoldOptions checks the options shared by the mixin and plugin
+// create commands, and defaults the module path and the destination directory.
+func validateScaffoldOptions(cxt *portercontext.Context, kind string, name string, author string, username string, module *string, dir *string) error {
+	if !validScaffoldName.MatchString(name) {
+		return fmt.Errorf("invalid %s name %q, it must start with a letter and contain only letters, numbers and dashes", kind, name)
+	}
+
+	if author == "" {
+		return errors.New("must provide a value for flag --author")
+	}
+
+	if *module == "" {
+		if username == "" {
+			return errors.New("must provide a value for flag --username or --module")
+		}
+		*module = fmt.Sprintf("github.com/%s/%s", username, name)
+	}
+
+	if *dir == "" {
+		*dir = cxt.Getwd()
+	}
+
+	if _, err := cxt.FileSystem.Stat(*dir); err != nil {
+		return fmt.Errorf("invalid --dir: %s: %w", *dir, err)
+	}
+
+	return nil
+}
+
+// renderScaffold writes a new project to dest from the named scaffold
+// templates. Files in later scaffolds take precedence over earlier ones.
+func (p *Porter) renderScaffold(dest string, data scaffoldData, scaffolds ...string) error {
+	if exists, _ := p.FileSystem.Exists(dest); exists {
+		return fmt.Errorf("could not create %s: the directory already exists", dest)
+	}
+
+	funcs := template.FuncMap{"upper": strings.ToUpper}
+	for _, name := range scaffolds {
+		tmplFS, err := p.Templates.GetScaffold(name)
+		if err != nil {
+			return err
+		}
+
+		err = fs.WalkDir(tmplFS, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			src, err := fs.ReadFile(tmplFS, path)
+			if err != nil {
+				return fmt.Errorf("could not read the %s scaffold template %s: %w", name, path, err)
+			}
+
+			// Use different delimiters so that the templates can contain
+			// mustache and Go templates, such as the atom feed template.
+			tmpl, err := template.New(path).Delims("[[", "]]").Funcs(funcs).Option("missingkey=error").Parse(string(src))
+			if err != nil {
+				return fmt.Errorf("could not parse the %s scaffold template %s: %w", name, path, err)
+			}
+
+			var buf bytes.Buffer
+			if err = tmpl.Execute(&buf, data); err != nil {
+				return fmt.Errorf("could not render the %s scaffold template %s: %w", name, path, err)
+			}
+
+			destPath := filepath.Join(dest, scaffoldPath(path, data))
+			if err = p.FileSystem.MkdirAll(filepath.Dir(destPath), pkg.FileModeDirectory); err != nil {
+				return err
+			}
+			if err = p.FileSystem.WriteFile(destPath, buf.Bytes(), pkg.FileModeWritable); err != nil {
+				return fmt.Errorf("failed to write template to %s: %w", destPath, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scaffoldPath returns the path of the file generated from a scaffold
+// template. The NAME and PACKAGE directories are named after the mixin or
+// plugin, and hidden files are stored without their leading dot so that they
+// can be embedded.
+func scaffoldPath(path string, data scaffoldData) string {
+	parts := strings.Split(strings.TrimSuffix(path, ".tmpl"), "/")
+	for i, part := range parts {
+		switch part {
+		case "NAME":
+			parts[i] = data.Name
+		case "PACKAGE":
+			parts[i] = data.Package
+		case "gitignore":
+			parts[i] = ".gitignore"
+		}
+	}
+	return filepath.Join(parts...)
+}
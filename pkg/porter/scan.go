@@ -0,0 +1,226 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/scan"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/opencontainers/go-digest"
+)
+
+// ScanOptions are the options for scanning the images of a bundle for vulnerabilities.
+type ScanOptions struct {
+	BundlePullOptions
+	printer.PrintOptions
+
+	// Scanner used to scan the images, defaults to the scanner in the configuration file.
+	Scanner string
+
+	// FailOn is the severity at which vulnerabilities cause the scan to fail.
+	FailOn string
+
+	// failOn is the parsed severity threshold from FailOn.
+	failOn scan.Severity
+}
+
+// Validate the scan options.
+func (o *ScanOptions) Validate(args []string, p *Porter) error {
+	// Allow reference to be specified as a positional argument, or using --reference
+	if len(args) == 1 {
+		o.Reference = args[0]
+	} else if len(args) > 1 {
+		return fmt.Errorf("only one positional argument may be specified, the bundle reference, but multiple were received: %s", args)
+	}
+
+	if o.Reference == "" {
+		return errors.New("a bundle reference must be specified, either as an argument or with --reference")
+	}
+
+	if o.Scanner == "" {
+		o.Scanner = p.Data.Scan.GetScanner()
+	}
+
+	if o.FailOn != "" {
+		severity, err := scan.ParseSeverity(o.FailOn)
+		if err != nil {
+			return fmt.Errorf("invalid --fail-on value: %w", err)
+		}
+		o.failOn = severity
+	}
+
+	if err := o.PrintOptions.Validate(printer.FormatPlaintext, []printer.Format{printer.FormatPlaintext, printer.FormatJson, printer.FormatYaml}); err != nil {
+		return err
+	}
+
+	return o.BundlePullOptions.Validate()
+}
+
+// ImageScanResult is the result of scanning an image referenced by a bundle.
+type ImageScanResult struct {
+	// Image that was scanned.
+	Image string `json:"image" yaml:"image"`
+
+	// Vulnerabilities found in the image, ordered from the most to the least severe.
+	Vulnerabilities []scan.Vulnerability `json:"vulnerabilities" yaml:"vulnerabilities"`
+}
+
+// Scan the invocation images and images referenced by a bundle for vulnerabilities.
+func (p *Porter) Scan(ctx context.Context, opts ScanOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	scanner, err := scan.NewScanner(p.Context, opts.Scanner)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	cachedBundle, err := p.PullBundle(ctx, opts.BundlePullOptions)
+	if err != nil {
+		return err
+	}
+
+	images, err := getScannedImages(cachedBundle.BundleReference)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	scanOpts := scan.Options{InsecureRegistry: opts.InsecureRegistry}
+	results := make([]ImageScanResult, 0, len(images))
+	for _, image := range images {
+		log.Infof("Scanning %s with %s...", image, opts.Scanner)
+		vulns, err := scanner.Scan(ctx, image, scanOpts)
+		if err != nil {
+			return log.Error(fmt.Errorf("could not scan %s: %w", image, err))
+		}
+
+		sort.SliceStable(vulns, func(i, j int) bool {
+			if vulns[i].Severity != vulns[j].Severity {
+				return !vulns[j].Severity.AtLeast(vulns[i].Severity)
+			}
+			return vulns[i].ID < vulns[j].ID
+		})
+		results = append(results, ImageScanResult{Image: image.String(), Vulnerabilities: vulns})
+	}
+
+	if err = p.printScanResults(opts, results); err != nil {
+		return err
+	}
+
+	if opts.failOn != "" {
+		var count int
+		for _, result := range results {
+			for _, v := range result.Vulnerabilities {
+				if v.Severity.AtLeast(opts.failOn) {
+					count++
+				}
+			}
+		}
+		if count > 0 {
+			return log.Error(fmt.Errorf("found %d vulnerabilities with severity %s or higher", count, opts.failOn))
+		}
+	}
+	return nil
+}
+
+// getScannedImages returns the invocation images and the images referenced by the
+// bundle, by digest when it is known, taking into account where the images were relocated.
+func getScannedImages(bundleRef cnab.BundleReference) ([]cnab.OCIReference, error) {
+	images, err := getInvocationImageReferences(bundleRef)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(bundleRef.Definition.Images))
+	for name := range bundleRef.Definition.Images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]bool, len(images))
+	for _, image := range images {
+		seen[image.String()] = true
+	}
+	for _, name := range names {
+		img := bundleRef.Definition.Images[name]
+		image := img.Image
+		if relocated, ok := bundleRef.RelocationMap[image]; ok {
+			image = relocated
+		}
+
+		ref, err := cnab.ParseOCIReference(image)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image reference %s for image %s: %w", image, name, err)
+		}
+		if !ref.HasDigest() && img.Digest != "" {
+			ref, err = referenceByDigest(ref, digest.Digest(img.Digest))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if seen[ref.String()] {
+			continue
+		}
+		seen[ref.String()] = true
+		images = append(images, ref)
+	}
+	return images, nil
+}
+
+func (p *Porter) printScanResults(opts ScanOptions, results []ImageScanResult) error {
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, results)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, results)
+	case printer.FormatPlaintext:
+		return p.printScanResultsTable(results)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
+
+func (p *Porter) printScanResultsTable(results []ImageScanResult) error {
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(p.Out)
+		}
+		fmt.Fprintf(p.Out, "Image: %s\n", result.Image)
+		if len(result.Vulnerabilities) == 0 {
+			fmt.Fprintln(p.Out, "No vulnerabilities found")
+			continue
+		}
+
+		counts := make(map[scan.Severity]int, len(scan.Severities))
+		for _, v := range result.Vulnerabilities {
+			counts[v.Severity]++
+		}
+		fmt.Fprintf(p.Out, "Total: %d (", len(result.Vulnerabilities))
+		for j := len(scan.Severities) - 1; j >= 0; j-- {
+			severity := scan.Severities[j]
+			fmt.Fprintf(p.Out, "%s: %d", severity, counts[severity])
+			if j > 0 {
+				fmt.Fprint(p.Out, ", ")
+			}
+		}
+		fmt.Fprintln(p.Out, ")")
+
+		printVulnerabilityRow :=
+			func(v interface{}) []string {
+				vuln, ok := v.(scan.Vulnerability)
+				if !ok {
+					return nil
+				}
+				return []string{vuln.ID, string(vuln.Severity), vuln.Package, vuln.InstalledVersion, vuln.FixedVersion, vuln.Title}
+			}
+		if err := printer.PrintTable(p.Out, result.Vulnerabilities, printVulnerabilityRow, "ID", "Severity", "Package", "Installed", "Fixed", "Title"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTrivyReport = `{"Results":[{"Target":"debian","Vulnerabilities":[` +
+	`{"VulnerabilityID":"CVE-2022-0001","PkgName":"bash","InstalledVersion":"5.1","Severity":"LOW"},` +
+	`{"VulnerabilityID":"CVE-2022-0002","PkgName":"openssl","InstalledVersion":"1.1.1n","FixedVersion":"1.1.1o","Severity":"HIGH","Title":"openssl: bad things"}]}]}`
+
+func newScannedBundleReference() cnab.BundleReference {
+	bundleRef := newSignedBundleReference()
+	bundleRef.Definition.Images = map[string]bundle.Image{
+		"nginx": {BaseImage: bundle.BaseImage{Image: "nginx:1.23", Digest: testBundleDigest}},
+	}
+	return bundleRef
+}
+
+func TestScanOptions_Validate(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := ScanOptions{}
+		require.NoError(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}, p.Porter))
+		assert.Equal(t, "example.com/mybuns:v0.1.0", opts.Reference)
+		assert.Equal(t, config.ScannerTrivy, opts.Scanner)
+		assert.Equal(t, printer.FormatPlaintext, opts.Format)
+	})
+
+	t.Run("scanner from config", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.Scan.Scanner = config.ScannerGrype
+
+		opts := ScanOptions{}
+		require.NoError(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}, p.Porter))
+		assert.Equal(t, config.ScannerGrype, opts.Scanner)
+	})
+
+	t.Run("missing reference", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := ScanOptions{}
+		require.ErrorContains(t, opts.Validate(nil, p.Porter), "a bundle reference must be specified")
+	})
+
+	t.Run("invalid severity", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := ScanOptions{FailOn: "severe"}
+		require.ErrorContains(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}, p.Porter), "invalid --fail-on value")
+	})
+}
+
+func Test_getScannedImages(t *testing.T) {
+	images, err := getScannedImages(newScannedBundleReference())
+	require.NoError(t, err)
+
+	require.Len(t, images, 2)
+	assert.Equal(t, "example.com/mybuns@"+testImageDigest, images[0].String())
+	assert.Equal(t, "nginx@"+testBundleDigest, images[1].String())
+}
+
+func TestPorter_Scan(t *testing.T) {
+	ctx := context.Background()
+
+	testcases := []struct {
+		name       string
+		failOn     string
+		format     string
+		wantOutput []string
+		wantErr    string
+	}{
+		{name: "table", wantOutput: []string{
+			"Image: example.com/mybuns@" + testImageDigest,
+			"Total: 2 (CRITICAL: 0, HIGH: 1, MEDIUM: 0, LOW: 1, UNKNOWN: 0)",
+			"CVE-2022-0002",
+		}},
+		{name: "json", format: "json", wantOutput: []string{`"id": "CVE-2022-0002"`, `"severity": "HIGH"`}},
+		{name: "below the threshold", failOn: "critical"},
+		{name: "above the threshold", failOn: "high", wantErr: "found 2 vulnerabilities with severity HIGH or higher"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTestPorter(t)
+			defer p.Close()
+			p.TestRegistry.MockPullBundle = func(ctx context.Context, ref cnab.OCIReference, opts cnabtooci.RegistryOptions) (cnab.BundleReference, error) {
+				bundleRef := newScannedBundleReference()
+				bundleRef.Reference = ref
+				return bundleRef, nil
+			}
+			p.Setenv(test.ExpectedCommandEnv,
+				"trivy image --format json --quiet example.com/mybuns@"+testImageDigest+"\n"+
+					"trivy image --format json --quiet nginx@"+testBundleDigest)
+			p.Setenv(test.ExpectedCommandOutputEnv, testTrivyReport)
+
+			opts := ScanOptions{FailOn: tc.failOn}
+			opts.RawFormat = tc.format
+			require.NoError(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}, p.Porter))
+
+			err := p.Scan(ctx, opts)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			output := p.TestConfig.TestContext.GetOutput()
+			for _, want := range tc.wantOutput {
+				assert.Contains(t, output, want)
+			}
+		})
+	}
+}
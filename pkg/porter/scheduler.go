@@ -0,0 +1,189 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-multierror"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SchedulerOptions represent options for Porter's scheduler run command
+type SchedulerOptions struct {
+	// Namespace in which to run scheduled actions.
+	Namespace string
+
+	// AllNamespaces runs scheduled actions for installations in all namespaces.
+	AllNamespaces bool
+
+	// Interval between checks for scheduled actions that are due.
+	Interval time.Duration
+
+	// Once checks for scheduled actions that are due a single time and then exits,
+	// instead of running until the command is stopped.
+	Once bool
+}
+
+// Validate the scheduler options.
+func (o *SchedulerOptions) Validate() error {
+	if !o.Once && o.Interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+	return nil
+}
+
+func (o SchedulerOptions) getNamespace() string {
+	if o.AllNamespaces {
+		return "*"
+	}
+	return o.Namespace
+}
+
+// RunScheduler runs the scheduled actions defined on installations when they
+// are due, until the context is cancelled.
+func (p *Porter) RunScheduler(ctx context.Context, opts SchedulerOptions) error {
+	log := tracing.LoggerFromContext(ctx)
+
+	if !opts.Once {
+		log.Infof("Checking for scheduled actions every %s", opts.Interval)
+	}
+
+	for {
+		err := p.RunDueScheduledActions(ctx, opts.getNamespace(), time.Now())
+		if opts.Once {
+			return err
+		}
+		if err != nil {
+			// Keep running so that one failing installation does not stop the others from being scheduled
+			log.Warnf("%s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// RunDueScheduledActions runs the scheduled action of each installation in the
+// namespace that is due at the specified time.
+func (p *Porter) RunDueScheduledActions(ctx context.Context, namespace string, now time.Time) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	installations, err := p.Installations.FindInstallations(ctx, storage.FindOptions{
+		Sort:   []string{"namespace", "name"},
+		Filter: scheduledInstallationsFilter(namespace),
+	})
+	if err != nil {
+		return span.Error(fmt.Errorf("could not list scheduled installations: %w", err))
+	}
+
+	var bigErr *multierror.Error
+	for _, installation := range installations {
+		due, err := isScheduledActionDue(installation, now)
+		if err != nil {
+			bigErr = multierror.Append(bigErr, fmt.Errorf("could not schedule installation %s: %w", installation, err))
+			continue
+		}
+		if !due {
+			continue
+		}
+
+		span.Infof("Running the scheduled %s action on installation %s", installation.Schedule.Action, installation)
+		if err = p.runScheduledAction(ctx, installation, now); err != nil {
+			bigErr = multierror.Append(bigErr, fmt.Errorf("scheduled %s action on installation %s failed: %w", installation.Schedule.Action, installation, err))
+		}
+	}
+
+	return bigErr.ErrorOrNil()
+}
+
+// scheduledInstallationsFilter selects the installations in the namespace that define a schedule.
+func scheduledInstallationsFilter(namespace string) bson.M {
+	filter := bson.M{
+		"schedule": bson.M{"$exists": true},
+	}
+	if namespace != "*" {
+		filter["namespace"] = namespace
+	}
+	return filter
+}
+
+// isScheduledActionDue determines if the scheduled action of an installation
+// should be run at the specified time. Installations that are paused, or that are
+// not installed, are never due. A schedule that was missed, for example because
+// the scheduler was not running, is run once when it is next checked.
+func isScheduledActionDue(installation storage.Installation, now time.Time) (bool, error) {
+	if installation.Schedule == nil || installation.Status.Suspended || !installation.IsInstalled() {
+		return false, nil
+	}
+
+	// The schedule starts from when the action was last run, or when the installation was last modified
+	since := installation.Status.Modified
+	if installation.Status.LastScheduled != nil {
+		since = *installation.Status.LastScheduled
+	}
+
+	next, err := installation.Schedule.Next(since.In(now.Location()))
+	if err != nil {
+		return false, err
+	}
+	return !next.After(now), nil
+}
+
+// runScheduledAction records that the scheduled action was started, so that it
+// is not repeated if the action fails, and then runs the action.
+func (p *Porter) runScheduledAction(ctx context.Context, installation storage.Installation, now time.Time) error {
+	installation.Status.LastScheduled = &now
+	if err := p.Installations.UpdateInstallation(ctx, installation); err != nil {
+		return fmt.Errorf("could not save the scheduled run time: %w", err)
+	}
+
+	ref, ok, err := installation.Bundle.GetBundleReference()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("the installation does not define a valid bundle reference")
+	}
+
+	// Run the action using the desired state of the installation
+	execOpts := NewBundleExecutionOptions()
+	execOpts.Reference = ref.String()
+	execOpts.Name = installation.Name
+	execOpts.Namespace = installation.Namespace
+	execOpts.CredentialIdentifiers = installation.CredentialSets
+	execOpts.ParameterSets = installation.ParameterSets
+
+	switch installation.Schedule.Action {
+	case cnab.ActionUpgrade:
+		opts := NewUpgradeOptions()
+		opts.BundleExecutionOptions = execOpts
+		if err := opts.Validate(ctx, nil, p); err != nil {
+			return err
+		}
+		return p.UpgradeBundle(ctx, opts)
+	case cnab.ActionUninstall:
+		opts := NewUninstallOptions()
+		opts.BundleExecutionOptions = execOpts
+		if err := opts.Validate(ctx, nil, p); err != nil {
+			return err
+		}
+		return p.UninstallBundle(ctx, opts)
+	default:
+		opts := NewInvokeOptions()
+		opts.BundleExecutionOptions = execOpts
+		opts.Action = installation.Schedule.Action
+		if err := opts.Validate(ctx, nil, p); err != nil {
+			return err
+		}
+		return p.InvokeBundle(ctx, opts)
+	}
+}
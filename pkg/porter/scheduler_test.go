@@ -0,0 +1,70 @@
+package porter
+
+import (
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerOptions_Validate(t *testing.T) {
+	opts := SchedulerOptions{Interval: time.Minute}
+	require.NoError(t, opts.Validate())
+
+	opts = SchedulerOptions{Interval: time.Millisecond}
+	require.EqualError(t, opts.Validate(), "--interval must be at least 1s")
+
+	opts = SchedulerOptions{Once: true}
+	require.NoError(t, opts.Validate(), "the interval is not used with --once")
+}
+
+func TestIsScheduledActionDue(t *testing.T) {
+	now := time.Date(2022, time.June, 2, 3, 0, 30, 0, time.UTC)
+	modified := time.Date(2022, time.June, 1, 12, 0, 0, 0, time.UTC)
+	installed := modified
+
+	newInstallation := func(transform func(i *storage.Installation)) storage.Installation {
+		i := storage.NewInstallation("dev", "mysql")
+		i.Schedule = &storage.InstallationSchedule{Action: "upgrade", Cron: "0 3 * * *"}
+		i.Status.Modified = modified
+		i.Status.Installed = &installed
+		if transform != nil {
+			transform(&i)
+		}
+		return i
+	}
+
+	testcases := []struct {
+		name         string
+		installation storage.Installation
+		wantDue      bool
+	}{
+		{name: "due", installation: newInstallation(nil), wantDue: true},
+		{name: "not scheduled", installation: newInstallation(func(i *storage.Installation) {
+			i.Schedule = nil
+		})},
+		{name: "already run", installation: newInstallation(func(i *storage.Installation) {
+			lastScheduled := now.Add(-10 * time.Second)
+			i.Status.LastScheduled = &lastScheduled
+		})},
+		{name: "missed schedule", installation: newInstallation(func(i *storage.Installation) {
+			lastScheduled := now.Add(-72 * time.Hour)
+			i.Status.LastScheduled = &lastScheduled
+		}), wantDue: true},
+		{name: "paused", installation: newInstallation(func(i *storage.Installation) {
+			i.Status.Suspended = true
+		})},
+		{name: "not installed", installation: newInstallation(func(i *storage.Installation) {
+			i.Status.Installed = nil
+		})},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			due, err := isScheduledActionDue(tc.installation, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantDue, due)
+		})
+	}
+}
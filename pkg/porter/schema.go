@@ -92,14 +92,16 @@ func (p *Porter) injectMixinSchemas(ctx context.Context, manifestSchema jsonSche
 	// - exec
 	// - helm3:
 	//     clientVersion: 1.2.3
+	// - name: terraform
+	//   version: 1.0.x
 	mixinDeclSchema, ok := mixinItemSchema["oneOf"].([]interface{})
 	if !ok {
 		return nil, span.Error(fmt.Errorf("root porter manifest schema has invalid properties.mixins.items.oneOf type, expected []interface{} but got %T", mixinItemSchema["oneOf"]))
 	}
 
-	// The first item is an enum of all the mixin names
-	if len(mixinDeclSchema) > 1 {
-		return nil, span.Errorf("root porter manifest schema has invalid properties.mixins.items.oneOf, expected a string type to list the names of all the mixins")
+	// The first item is an enum of all the mixin names, followed by the declaration of a mixin with a version
+	if len(mixinDeclSchema) != 2 {
+		return nil, span.Errorf("root porter manifest schema has invalid properties.mixins.items.oneOf, expected a string type to list the names of all the mixins and an object type to declare a mixin with a version")
 	}
 	mixinNameDecl, ok := mixinDeclSchema[0].(jsonSchema)
 	if !ok {
@@ -120,6 +122,16 @@ func (p *Porter) injectMixinSchemas(ctx context.Context, manifestSchema jsonSche
 		actionSchemas[action] = actionSchema
 	}
 
+	// Fields that Porter handles for every mixin step, such as when and foreach
+	stepFields, err := jsonpath.Get("$.definitions.step.properties", manifestSchema)
+	if err != nil {
+		return nil, span.Error(fmt.Errorf("root porter manifest schema is missing definitions.step.properties: %w", err))
+	}
+	stepFieldsSchema, ok := stepFields.(jsonSchema)
+	if !ok {
+		return nil, span.Error(fmt.Errorf("root porter manifest schema has invalid definitions.step.properties type, expected map[string]interface{} but got %T", stepFields))
+	}
+
 	mixins, err := p.Mixins.List()
 	if err != nil {
 		return nil, span.Error(err)
@@ -154,6 +166,9 @@ func (p *Porter) injectMixinSchemas(ctx context.Context, manifestSchema jsonSche
 			mixinDeclSchema = append(mixinDeclSchema, jsonObject{"$ref": mixinConfigRef})
 		}
 
+		// Allow the fields that Porter handles on every step to be used with the mixin's steps
+		addStepFields(mixin, mixinSchemaMap, stepFieldsSchema)
+
 		// embed the entire mixin schema in the root
 		manifestSchema["mixin."+mixin] = mixinSchemaMap
 
@@ -204,6 +219,51 @@ func (p *Porter) injectMixinSchemas(ctx context.Context, manifestSchema jsonSche
 	return manifestSchema, span.Error(err)
 }
 
+// addStepFields references the fields defined in the root schema's step definition
+// from each of the mixin's step definitions, so that they are accepted for every mixin
+// without each mixin declaring them. Fields that the mixin already defines are left as is.
+func addStepFields(mixin string, mixinSchema jsonSchema, stepFields jsonSchema) {
+	definitions, ok := mixinSchema["definitions"].(jsonSchema)
+	if !ok {
+		return
+	}
+
+	refPrefix := fmt.Sprintf("#/mixin.%s/definitions/", mixin)
+	for _, stepName := range []string{"installStep", "upgradeStep", "uninstallStep", "invokeStep"} {
+		stepSchema, ok := definitions[stepName].(jsonSchema)
+		if !ok {
+			continue
+		}
+		stepProperties, ok := stepSchema["properties"].(jsonSchema)
+		if !ok {
+			continue
+		}
+		mixinStepSchema, ok := stepProperties[mixin].(jsonSchema)
+		if !ok {
+			continue
+		}
+
+		// Mixins usually define their step once and reference it from each action
+		if ref, ok := mixinStepSchema["$ref"].(string); ok {
+			mixinStepSchema, ok = definitions[strings.TrimPrefix(ref, refPrefix)].(jsonSchema)
+			if !ok || !strings.HasPrefix(ref, refPrefix) {
+				continue
+			}
+		}
+
+		mixinStepProperties, ok := mixinStepSchema["properties"].(jsonSchema)
+		if !ok {
+			continue
+		}
+		for field := range stepFields {
+			if _, ok := mixinStepProperties[field]; ok {
+				continue
+			}
+			mixinStepProperties[field] = jsonObject{"$ref": "#/definitions/step/properties/" + field}
+		}
+	}
+}
+
 func (p *Porter) GetReplacementSchema() (jsonSchema, error) {
 	home, err := p.GetHomeDir()
 	if err != nil {
@@ -2,9 +2,15 @@ package porter
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"get.porter.sh/porter/pkg/yaml"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 func TestPorter_PrintManifestSchema(t *testing.T) {
@@ -17,3 +23,49 @@ func TestPorter_PrintManifestSchema(t *testing.T) {
 
 	p.CompareGoldenFile("testdata/schema.json", p.TestConfig.TestContext.GetOutput())
 }
+
+func TestPorter_ManifestSchema_StepFields(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	schemaMap, err := p.GetManifestSchema(ctx)
+	require.NoError(t, err)
+
+	// Use the local copy of the schema referenced by the manifest schema so that the test does not need network access
+	jsonSchema, err := os.ReadFile(filepath.Join(p.RepoRoot, "docs/static/schema/json-schema.json"))
+	require.NoError(t, err, "failed to read json-schema.json")
+	sl := gojsonschema.NewSchemaLoader()
+	require.NoError(t, sl.AddSchema("https://getporter.org/schema/json-schema.json", gojsonschema.NewBytesLoader(jsonSchema)))
+	manifestSchema, err := sl.Compile(gojsonschema.NewGoLoader(schemaMap))
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "valid", path: "testdata/schema/porter-with-step-fields.yaml"},
+		{name: "invalid timeout", path: "testdata/schema/porter-with-invalid-step-fields.yaml", wantErr: "install.0.testmixin.timeout: Invalid type. Expected: string, given: integer"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			testManifest, err := os.ReadFile(tc.path)
+			require.NoError(t, err, "failed to read %s", tc.path)
+
+			m := make(map[string]interface{})
+			err = yaml.Unmarshal(testManifest, &m)
+			require.NoError(t, err, "failed to unmarshal %s", tc.path)
+
+			result, err := manifestSchema.Validate(gojsonschema.NewGoLoader(m))
+			require.NoError(t, err)
+
+			if tc.wantErr == "" {
+				assert.Empty(t, result.Errors(), "expected %s to validate against the manifest schema", tc.path)
+			} else {
+				assert.Contains(t, fmt.Sprintf("%v", result.Errors()), tc.wantErr)
+			}
+		})
+	}
+}
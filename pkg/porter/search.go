@@ -0,0 +1,179 @@
+package porter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"get.porter.sh/porter/pkg/bundleindex"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/go-multierror"
+)
+
+// CommunityBundleIndex is the name of the bundle index that is searched when
+// no bundle indexes are defined in the configuration file.
+const CommunityBundleIndex = "community"
+
+// BundleSearchOptions are the options for searching bundle indexes.
+type BundleSearchOptions struct {
+	printer.PrintOptions
+	pkgmgmt.PackageDownloadOptions
+
+	// Query is the search terms, every term must match a bundle.
+	Query string
+
+	// Indexes limits the search to the named bundle indexes.
+	Indexes []string
+
+	// InsecureRegistry allows connecting to an unsecured registry when loading an oci index.
+	InsecureRegistry bool
+}
+
+// Validate the bundle search options.
+func (o *BundleSearchOptions) Validate(args []string, p *Porter) error {
+	o.Query = strings.Join(args, " ")
+
+	if err := p.Data.BundleIndexes.Validate(); err != nil {
+		return err
+	}
+
+	for _, indexName := range o.Indexes {
+		if _, ok := o.findIndex(p.Data.BundleIndexes, indexName); !ok {
+			return fmt.Errorf("invalid --index %s, the bundle index is not defined in the configuration file", indexName)
+		}
+	}
+
+	if err := o.PackageDownloadOptions.Validate(); err != nil {
+		return err
+	}
+
+	return o.PrintOptions.Validate(printer.FormatPlaintext, []printer.Format{printer.FormatPlaintext, printer.FormatJson, printer.FormatYaml})
+}
+
+// findIndex returns the named bundle index, including the default community index
+// when no indexes are configured.
+func (o *BundleSearchOptions) findIndex(indexes config.BundleIndexes, name string) (config.BundleIndex, bool) {
+	for _, idx := range o.getIndexes(indexes) {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+	return config.BundleIndex{}, false
+}
+
+// getIndexes returns the configured bundle indexes, or the community index when none are configured.
+func (o *BundleSearchOptions) getIndexes(indexes config.BundleIndexes) config.BundleIndexes {
+	if len(indexes) > 0 {
+		return indexes
+	}
+
+	return config.BundleIndexes{
+		{
+			Name: CommunityBundleIndex,
+			Type: config.BundleIndexTypeHTTP,
+			URL:  pkgmgmt.GetPackageListURL(o.GetMirror(), "bundle"),
+		},
+	}
+}
+
+// SearchBundles searches the bundle indexes for bundles that match the query and prints the results.
+func (p *Porter) SearchBundles(ctx context.Context, opts BundleSearchOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	if err := p.CheckNetworkAccess("search the bundle indexes"); err != nil {
+		return log.Error(err)
+	}
+
+	sources, err := p.getBundleIndexSources(opts)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	var loaded []bundleindex.SourceIndex
+	var loadErr *multierror.Error
+	for _, source := range sources {
+		idx, err := source.Load(ctx)
+		if err != nil {
+			log.Warnf("Skipping the %s bundle index: %s", source.Name(), err.Error())
+			loadErr = multierror.Append(loadErr, err)
+			continue
+		}
+		loaded = append(loaded, bundleindex.SourceIndex{Source: source.Name(), Index: idx})
+	}
+	if len(loaded) == 0 && loadErr != nil {
+		return log.Error(fmt.Errorf("could not load any bundle index: %w", loadErr))
+	}
+
+	results := bundleindex.Search(loaded, opts.Query)
+	if len(results) == 0 && opts.Query != "" {
+		return log.Error(fmt.Errorf("no bundles found that match %q", opts.Query))
+	}
+
+	return p.printBundleSearchResults(opts, results)
+}
+
+// getBundleIndexSources creates a source for each bundle index that should be searched.
+func (p *Porter) getBundleIndexSources(opts BundleSearchOptions) ([]bundleindex.Source, error) {
+	indexes := opts.getIndexes(p.Data.BundleIndexes)
+	if len(opts.Indexes) > 0 {
+		indexes = make(config.BundleIndexes, 0, len(opts.Indexes))
+		for _, indexName := range opts.Indexes {
+			idx, _ := opts.findIndex(p.Data.BundleIndexes, indexName)
+			indexes = append(indexes, idx)
+		}
+	}
+
+	regOpts := cnabtooci.RegistryOptions{
+		InsecureRegistry: opts.InsecureRegistry,
+		RegistryTLS:      p.Data.RegistryTLS,
+	}
+	sources := make([]bundleindex.Source, 0, len(indexes))
+	for _, idx := range indexes {
+		var craneOpts []crane.Option
+		if idx.Type == config.BundleIndexTypeOCI {
+			ref, err := name.ParseReference(idx.Reference)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bundle-indexes.reference %s for the %s index: %w", idx.Reference, idx.Name, err)
+			}
+			craneOpts, err = cnabtooci.GetCraneOptions(p.FileSystem, regOpts, ref.Context().RegistryStr())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		source, err := bundleindex.NewSource(p.Context, idx, craneOpts...)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+func (p *Porter) printBundleSearchResults(opts BundleSearchOptions, results []bundleindex.Result) error {
+	switch opts.Format {
+	case printer.FormatPlaintext:
+		printResultRow := func(v interface{}) []string {
+			r, ok := v.(bundleindex.Result)
+			if !ok {
+				return nil
+			}
+			return []string{r.Name, r.Version, r.Description, r.Reference, r.Source}
+		}
+		return printer.PrintTable(p.Out, results, printResultRow,
+			"Name", "Version", "Description", "Reference", "Index")
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, results)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, results)
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
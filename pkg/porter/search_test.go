@@ -0,0 +1,134 @@
+package porter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleSearchOptions_Validate(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		opts := BundleSearchOptions{}
+		require.NoError(t, opts.Validate([]string{"mysql", "operator"}, p.Porter))
+		assert.Equal(t, "mysql operator", opts.Query)
+		assert.Equal(t, printer.FormatPlaintext, opts.Format)
+
+		indexes := opts.getIndexes(p.Data.BundleIndexes)
+		require.Len(t, indexes, 1)
+		assert.Equal(t, CommunityBundleIndex, indexes[0].Name)
+		assert.Equal(t, "https://cdn.porter.sh/bundles/index.json", indexes[0].URL)
+	})
+
+	t.Run("unknown index", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.BundleIndexes = config.BundleIndexes{{Name: "internal", Type: config.BundleIndexTypeOCI, Reference: "example.com/bundle-index:latest"}}
+
+		opts := BundleSearchOptions{Indexes: []string{"community"}}
+		require.ErrorContains(t, opts.Validate(nil, p.Porter), "invalid --index community")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.BundleIndexes = config.BundleIndexes{{Name: "internal", Type: config.BundleIndexTypeOCI}}
+
+		opts := BundleSearchOptions{}
+		require.ErrorContains(t, opts.Validate(nil, p.Porter), "bundle-indexes.reference is required")
+	})
+}
+
+func TestPorter_SearchBundles(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/community.json":
+			w.Write([]byte(`{"schemaVersion":"1.0.0","bundles":[
+				{"name":"mysql","version":"0.2.0","description":"A MySQL database","reference":"example.com/mysql:v0.2.0"},
+				{"name":"wordpress","version":"1.0.0","description":"A blog backed by mysql","reference":"example.com/wordpress:v1.0.0"}]}`))
+		case "/internal.json":
+			w.Write([]byte(`{"schemaVersion":"1.0.0","bundles":[
+				{"name":"mysql-operator","version":"1.0.0","reference":"internal.example.com/mysql-operator:v1.0.0"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	indexes := config.BundleIndexes{
+		{Name: "community", Type: config.BundleIndexTypeHTTP, URL: srv.URL + "/community.json"},
+		{Name: "internal", Type: config.BundleIndexTypeHTTP, URL: srv.URL + "/internal.json"},
+		{Name: "missing", Type: config.BundleIndexTypeHTTP, URL: srv.URL + "/missing.json"},
+	}
+
+	t.Run("merged results", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.BundleIndexes = indexes
+
+		opts := BundleSearchOptions{}
+		require.NoError(t, opts.Validate([]string{"mysql"}, p.Porter))
+		require.NoError(t, p.SearchBundles(p.RootContext, opts))
+
+		assert.Contains(t, p.TestConfig.TestContext.GetError(), "Skipping the missing bundle index")
+		output := p.TestConfig.TestContext.GetOutput()
+		assert.Regexp(t, `(?s)mysql\s+0.2.0.*community.*mysql-operator\s+1.0.0.*internal.*wordpress`, output)
+	})
+
+	t.Run("selected index", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.BundleIndexes = indexes
+
+		opts := BundleSearchOptions{Indexes: []string{"internal"}}
+		opts.RawFormat = "json"
+		require.NoError(t, opts.Validate(nil, p.Porter))
+		require.NoError(t, p.SearchBundles(ctx, opts))
+
+		output := p.TestConfig.TestContext.GetOutput()
+		assert.Contains(t, output, `"name": "mysql-operator"`)
+		assert.Contains(t, output, `"source": "internal"`)
+		assert.NotContains(t, output, "wordpress")
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.BundleIndexes = indexes
+
+		opts := BundleSearchOptions{}
+		require.NoError(t, opts.Validate([]string{"redis"}, p.Porter))
+		require.ErrorContains(t, p.SearchBundles(ctx, opts), `no bundles found that match "redis"`)
+	})
+
+	t.Run("every index fails", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.BundleIndexes = indexes[2:]
+
+		opts := BundleSearchOptions{}
+		require.NoError(t, opts.Validate(nil, p.Porter))
+		require.ErrorContains(t, p.SearchBundles(ctx, opts), "could not load any bundle index")
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.BundleIndexes = indexes
+		p.Data.Offline = true
+
+		opts := BundleSearchOptions{}
+		require.NoError(t, opts.Validate(nil, p.Porter))
+		require.ErrorIs(t, p.SearchBundles(ctx, opts), config.ErrOffline)
+		assert.Empty(t, p.TestConfig.TestContext.GetOutput())
+	})
+}
@@ -2,8 +2,11 @@ package porter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
@@ -22,6 +25,9 @@ var (
 type ShowOptions struct {
 	installationOptions
 	printer.PrintOptions
+
+	// Runs is the number of recent runs to include in the run history.
+	Runs int
 }
 
 // Validate prepares for a show bundle action and validates the args/options.
@@ -37,6 +43,10 @@ func (so *ShowOptions) Validate(args []string, cxt *portercontext.Context) error
 		return err
 	}
 
+	if so.Runs < 0 {
+		return errors.New("--runs must not be negative")
+	}
+
 	return so.PrintOptions.Validate(ShowDefaultFormat, ShowAllowedFormats)
 }
 
@@ -67,6 +77,19 @@ func (p *Porter) GetInstallation(ctx context.Context, opts ShowOptions) (storage
 // ShowInstallation shows a bundle installation, along with any
 // associated outputs
 func (p *Porter) ShowInstallation(ctx context.Context, opts ShowOptions) error {
+	// Ask the user to pick the installation when the name is not specified,
+	// and it cannot be determined from the bundle in the current directory
+	if err := p.applyDefaultOptions(ctx, &opts.installationOptions); err != nil {
+		return err
+	}
+	if opts.Name == "" {
+		name, err := p.pickInstallationName(ctx, opts.Namespace)
+		if err != nil {
+			return err
+		}
+		opts.Name = name
+	}
+
 	installation, run, err := p.GetInstallation(ctx, opts)
 	if err != nil {
 		return err
@@ -77,6 +100,21 @@ func (p *Porter) ShowInstallation(ctx context.Context, opts ShowOptions) error {
 		return err
 	}
 
+	if run != nil {
+		summary, err := p.getRunSummary(ctx, *run)
+		if err != nil {
+			return err
+		}
+		displayInstallation.LastRun = &summary
+	}
+
+	if opts.Runs > 0 {
+		displayInstallation.Runs, err = p.getRecentRuns(ctx, installation, opts.Runs)
+		if err != nil {
+			return err
+		}
+	}
+
 	switch opts.Format {
 	case printer.FormatJson:
 		return printer.PrintJson(p.Out, displayInstallation)
@@ -155,7 +193,7 @@ func (p *Porter) ShowInstallation(ctx context.Context, opts ShowOptions) error {
 		}
 
 		// Print the status (it may not be present if it's newly created using apply)
-		if installation.Status != (storage.InstallationStatus{}) {
+		if !reflect.DeepEqual(installation.Status, storage.InstallationStatus{}) {
 			fmt.Fprintln(p.Out)
 			fmt.Fprintln(p.Out, "Status:")
 			fmt.Fprintf(p.Out, "  Reference: %s\n", displayInstallation.Status.BundleReference)
@@ -163,6 +201,46 @@ func (p *Porter) ShowInstallation(ctx context.Context, opts ShowOptions) error {
 			fmt.Fprintf(p.Out, "  Last Action: %s\n", displayInstallation.Status.Action)
 			fmt.Fprintf(p.Out, "  Status: %s\n", displayInstallation.Status.ResultStatus)
 			fmt.Fprintf(p.Out, "  Digest: %s\n", displayInstallation.Status.BundleDigest)
+			if len(displayInstallation.Status.BundleAnnotations) > 0 {
+				fmt.Fprintln(p.Out, "  Bundle Annotations:")
+
+				// Print annotations in alphabetical order
+				annotations := make([]string, 0, len(displayInstallation.Status.BundleAnnotations))
+				for k, v := range displayInstallation.Status.BundleAnnotations {
+					annotations = append(annotations, fmt.Sprintf("%s: %s", k, v))
+				}
+				sort.Strings(annotations)
+
+				for _, annotation := range annotations {
+					fmt.Fprintf(p.Out, "    %s\n", annotation)
+				}
+			}
+		}
+
+		// Print the most recent run, if any
+		if lastRun := displayInstallation.LastRun; lastRun != nil {
+			fmt.Fprintln(p.Out)
+			fmt.Fprintln(p.Out, "Last Run:")
+			fmt.Fprintf(p.Out, "  ID: %s\n", lastRun.ID)
+			fmt.Fprintf(p.Out, "  Action: %s\n", lastRun.Action)
+			fmt.Fprintf(p.Out, "  Status: %s\n", lastRun.Status)
+			fmt.Fprintf(p.Out, "  Started: %s\n", tp.Format(lastRun.Started))
+			if lastRun.Duration != "" {
+				fmt.Fprintf(p.Out, "  Duration: %s\n", lastRun.Duration)
+			}
+			if lastRun.Error != "" {
+				fmt.Fprintf(p.Out, "  Error: %s\n", lastRun.Error)
+			}
+		}
+
+		// Print the run history, if requested
+		if len(displayInstallation.Runs) > 0 {
+			fmt.Fprintln(p.Out)
+			fmt.Fprintln(p.Out, "Recent Runs:")
+			err = p.printDisplayRunsTable(displayInstallation.Runs, false, printer.ColumnOptions{})
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -191,3 +269,101 @@ func (p *Porter) NewDisplayInstallationWithSecrets(ctx context.Context, installa
 
 	return displayInstallation, nil
 }
+
+// DisplayRunSummary summarizes a run of an installation, so that a failed run
+// can be troubleshot without looking up the runs of the installation.
+type DisplayRunSummary struct {
+	ID      string     `json:"id" yaml:"id"`
+	Action  string     `json:"action" yaml:"action"`
+	Status  string     `json:"status" yaml:"status"`
+	Started time.Time  `json:"started" yaml:"started"`
+	Stopped *time.Time `json:"stopped,omitempty" yaml:"stopped,omitempty"`
+
+	// Duration of the run, only set once the run has completed.
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+
+	// Error message of a failed run.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// getRunSummary summarizes the status of a run from its results. When a failed
+// run did not record an error message, the last line of its logs is used instead.
+func (p *Porter) getRunSummary(ctx context.Context, run storage.Run) (DisplayRunSummary, error) {
+	results, err := p.Installations.ListResults(ctx, run.ID)
+	if err != nil {
+		return DisplayRunSummary{}, fmt.Errorf("could not list the results of run %s: %w", run.ID, err)
+	}
+
+	summary := newDisplayRunSummary(run, results)
+	if err = p.setRunErrorFromLogs(ctx, &summary); err != nil {
+		return DisplayRunSummary{}, err
+	}
+	return summary, nil
+}
+
+// setRunErrorFromLogs uses the last line of the logs as the error message of a
+// failed run that did not record an error message.
+func (p *Porter) setRunErrorFromLogs(ctx context.Context, summary *DisplayRunSummary) error {
+	if summary.Status != cnab.StatusFailed || summary.Error != "" {
+		return nil
+	}
+
+	logs, ok, err := p.Installations.GetLogs(ctx, summary.ID)
+	if err != nil {
+		return fmt.Errorf("could not retrieve the logs of run %s: %w", summary.ID, err)
+	}
+	if ok {
+		summary.Error = lastLogLine(logs)
+	}
+	return nil
+}
+
+// newDisplayRunSummary summarizes a run from its results, which must be sorted from oldest to newest.
+func newDisplayRunSummary(run storage.Run, results []storage.Result) DisplayRunSummary {
+	summary := DisplayRunSummary{
+		ID:      run.ID,
+		Action:  run.Action,
+		Started: run.Created,
+	}
+	if len(results) == 0 {
+		return summary
+	}
+
+	last := results[len(results)-1]
+	summary.Status = last.Status
+	switch last.Status {
+	case cnab.StatusSucceeded, cnab.StatusFailed, cnab.StatusCanceled:
+		stopped := last.Created
+		summary.Stopped = &stopped
+		summary.Duration = stopped.Sub(summary.Started).Round(time.Second).String()
+	}
+	if last.Status == cnab.StatusFailed {
+		summary.Error = strings.TrimSpace(last.Message)
+	}
+
+	return summary
+}
+
+// lastLogLine returns the last line of the logs that is not blank.
+func lastLogLine(logs string) string {
+	lines := strings.Split(strings.TrimSpace(logs), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// getRecentRuns returns the most recent runs of an installation, most recent first.
+func (p *Porter) getRecentRuns(ctx context.Context, installation storage.Installation, limit int) (DisplayRuns, error) {
+	runs, runResults, err := p.Installations.ListRuns(ctx, installation.Namespace, installation.Name)
+	if err != nil {
+		return nil, fmt.Errorf("could not list runs for installation %s: %w", installation, err)
+	}
+
+	if len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+
+	displayRuns := make(DisplayRuns, 0, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		displayRuns = append(displayRuns, newDisplayRunWithResults(runs[i], runResults[runs[i].ID]))
+	}
+	return displayRuns, nil
+}
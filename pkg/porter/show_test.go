@@ -3,6 +3,7 @@ package porter
 import (
 	"context"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/printer"
@@ -10,6 +11,7 @@ import (
 	"get.porter.sh/porter/pkg/storage"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -223,3 +225,38 @@ func TestPorter_ShowInstallationWithoutRecordedRun(t *testing.T) {
 	p.CompareGoldenFile("testdata/show/bundle-never-run.txt", p.TestConfig.TestContext.GetOutput())
 
 }
+
+func TestShowOptions_ValidateRuns(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := ShowOptions{Runs: -1}
+	require.EqualError(t, opts.Validate([]string{"mywordpress"}, p.Context), "--runs must not be negative")
+}
+
+func TestNewDisplayRunSummary(t *testing.T) {
+	i := storage.NewInstallation("dev", "mywordpress")
+	run := i.NewRun(cnab.ActionUpgrade)
+	run.Created = now
+
+	running := run.NewResult(cnab.StatusRunning)
+	running.Created = now
+	summary := newDisplayRunSummary(run, []storage.Result{running})
+	assert.Equal(t, cnab.StatusRunning, summary.Status)
+	assert.Nil(t, summary.Stopped, "a run that is still running should not have stopped")
+	assert.Empty(t, summary.Duration)
+
+	failed := run.NewResult(cnab.StatusFailed)
+	failed.Created = now.Add(90 * time.Second)
+	failed.Message = "container exited with code 1\n"
+	summary = newDisplayRunSummary(run, []storage.Result{running, failed})
+	assert.Equal(t, cnab.StatusFailed, summary.Status)
+	require.NotNil(t, summary.Stopped)
+	assert.Equal(t, "1m30s", summary.Duration)
+	assert.Equal(t, "container exited with code 1", summary.Error)
+}
+
+func TestLastLogLine(t *testing.T) {
+	assert.Equal(t, "Error: unable to connect", lastLogLine("Installing...\nError: unable to connect\n\n"))
+	assert.Equal(t, "", lastLogLine(""))
+}
@@ -0,0 +1,177 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/signing"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/opencontainers/go-digest"
+)
+
+// VerifyOptions are the options for verifying the signatures of a published bundle.
+type VerifyOptions struct {
+	BundlePullOptions
+}
+
+// Validate the verify options.
+func (o *VerifyOptions) Validate(args []string) error {
+	// Allow reference to be specified as a positional argument, or using --reference
+	if len(args) == 1 {
+		o.Reference = args[0]
+	} else if len(args) > 1 {
+		return fmt.Errorf("only one positional argument may be specified, the bundle reference, but multiple were received: %s", args)
+	}
+
+	if o.Reference == "" {
+		return errors.New("a bundle reference must be specified, either as an argument or with --reference")
+	}
+	return o.BundlePullOptions.Validate()
+}
+
+// Verify that a published bundle and its invocation images have trusted signatures.
+func (p *Porter) Verify(ctx context.Context, opts VerifyOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	cachedBundle, err := p.PullBundle(ctx, opts.BundlePullOptions)
+	if err != nil {
+		return err
+	}
+
+	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: opts.InsecureRegistry, RegistryTLS: p.Data.RegistryTLS}
+	return p.verifyBundleSignatures(ctx, cachedBundle.BundleReference, regOpts)
+}
+
+// signBundle signs a published bundle and its invocation images.
+func (p *Porter) signBundle(ctx context.Context, bundleRef cnab.BundleReference, regOpts cnabtooci.RegistryOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	signer, err := signing.NewSigner(p.Config)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	refs, err := getSignedReferences(bundleRef)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	signOpts := signing.Options{InsecureRegistry: regOpts.InsecureRegistry}
+	for _, ref := range refs {
+		p.printInfof("Signing %s\n", ref)
+		if err = signer.Sign(ctx, ref, signOpts); err != nil {
+			return log.Error(fmt.Errorf("could not sign %s: %w", ref, err))
+		}
+	}
+	return nil
+}
+
+// verifyBundleSignatures verifies that a bundle and its invocation images have trusted signatures.
+func (p *Porter) verifyBundleSignatures(ctx context.Context, bundleRef cnab.BundleReference, regOpts cnabtooci.RegistryOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	signer, err := signing.NewSigner(p.Config)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	refs, err := getSignedReferences(bundleRef)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	verifyOpts := signing.Options{InsecureRegistry: regOpts.InsecureRegistry}
+	for _, ref := range refs {
+		if err = signer.Verify(ctx, ref, verifyOpts); err != nil {
+			return log.Error(fmt.Errorf("%s does not have a trusted signature: %w", ref, err))
+		}
+		fmt.Fprintf(p.Out, "Verified the signature of %s\n", ref)
+	}
+	return nil
+}
+
+// enforceSignatureVerification refuses to execute bundles that do not have a
+// trusted signature when verify-signatures is enabled in the configuration file.
+func (p *Porter) enforceSignatureVerification(ctx context.Context, deperator *dependencyExecutioner) error {
+	if !p.Data.VerifySignatures {
+		return nil
+	}
+
+	regOpts := cnabtooci.RegistryOptions{InsecureRegistry: deperator.parentOpts.InsecureRegistry, RegistryTLS: p.Data.RegistryTLS}
+	for _, dep := range deperator.deps {
+		if err := p.verifyBundleSignatures(ctx, dep.BundleReference, regOpts); err != nil {
+			return fmt.Errorf("refusing to use dependency %s because verify-signatures is enabled: %w", dep.Alias, err)
+		}
+	}
+
+	if err := p.verifyBundleSignatures(ctx, deperator.parentArgs.BundleReference, regOpts); err != nil {
+		return fmt.Errorf("refusing to use the bundle because verify-signatures is enabled: %w", err)
+	}
+	return nil
+}
+
+// getSignedReferences returns the references, by digest, of the bundle and its
+// invocation images, taking into account where the images were relocated.
+func getSignedReferences(bundleRef cnab.BundleReference) ([]cnab.OCIReference, error) {
+	if bundleRef.Reference.Named == nil || bundleRef.Digest == "" {
+		return nil, errors.New("only bundles pulled from or published to a registry have signatures, use --reference to specify the bundle")
+	}
+
+	bundleDigestRef, err := referenceByDigest(bundleRef.Reference, bundleRef.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	imgRefs, err := getInvocationImageReferences(bundleRef)
+	if err != nil {
+		return nil, err
+	}
+	return append([]cnab.OCIReference{bundleDigestRef}, imgRefs...), nil
+}
+
+// getInvocationImageReferences returns the references, by digest, of the
+// invocation images of the bundle, taking into account where the images were relocated.
+func getInvocationImageReferences(bundleRef cnab.BundleReference) ([]cnab.OCIReference, error) {
+	refs := make([]cnab.OCIReference, 0, len(bundleRef.Definition.InvocationImages))
+	for _, invImg := range bundleRef.Definition.InvocationImages {
+		image := invImg.Image
+		if relocated, ok := bundleRef.RelocationMap[image]; ok {
+			image = relocated
+		}
+
+		imgRef, err := cnab.ParseOCIReference(image)
+		if err != nil {
+			return nil, fmt.Errorf("invalid invocation image reference %s: %w", image, err)
+		}
+
+		imgDigest := invImg.Digest
+		if imgRef.HasDigest() {
+			imgDigest = imgRef.Digest().String()
+		}
+		if imgDigest == "" {
+			return nil, fmt.Errorf("invocation image %s does not have a digest", image)
+		}
+
+		imgDigestRef, err := referenceByDigest(imgRef, digest.Digest(imgDigest))
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, imgDigestRef)
+	}
+	return refs, nil
+}
+
+// referenceByDigest returns the reference to the digest in the repository, without a tag.
+func referenceByDigest(ref cnab.OCIReference, d digest.Digest) (cnab.OCIReference, error) {
+	digestRef, err := cnab.ParseOCIReference(ref.Repository() + "@" + d.String())
+	if err != nil {
+		return cnab.OCIReference{}, fmt.Errorf("could not reference %s by digest %s: %w", ref, d, err)
+	}
+	return digestRef, nil
+}
@@ -0,0 +1,137 @@
+package porter
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	cnabtooci "get.porter.sh/porter/pkg/cnab/cnab-to-oci"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBundleDigest = "sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687"
+	testImageDigest  = "sha256:1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b"
+)
+
+func newSignedBundleReference() cnab.BundleReference {
+	return cnab.BundleReference{
+		Reference: cnab.MustParseOCIReference("example.com/mybuns:v0.1.0"),
+		Digest:    testBundleDigest,
+		Definition: cnab.NewBundle(bundle.Bundle{
+			InvocationImages: []bundle.InvocationImage{
+				{BaseImage: bundle.BaseImage{Image: "docker.io/getporter/mybuns-installer:v0.1.0", Digest: testImageDigest}},
+			},
+		}),
+		RelocationMap: map[string]string{
+			"docker.io/getporter/mybuns-installer:v0.1.0": "example.com/mybuns@" + testImageDigest,
+		},
+	}
+}
+
+func TestVerifyOptions_Validate(t *testing.T) {
+	t.Run("reference argument", func(t *testing.T) {
+		opts := VerifyOptions{}
+		require.NoError(t, opts.Validate([]string{"example.com/mybuns:v0.1.0"}))
+		assert.Equal(t, "example.com/mybuns:v0.1.0", opts.Reference)
+	})
+
+	t.Run("reference flag", func(t *testing.T) {
+		opts := VerifyOptions{}
+		opts.Reference = "example.com/mybuns:v0.1.0"
+		require.NoError(t, opts.Validate(nil))
+	})
+
+	t.Run("missing reference", func(t *testing.T) {
+		opts := VerifyOptions{}
+		require.ErrorContains(t, opts.Validate(nil), "a bundle reference must be specified")
+	})
+
+	t.Run("too many arguments", func(t *testing.T) {
+		opts := VerifyOptions{}
+		require.ErrorContains(t, opts.Validate([]string{"a", "b"}), "only one positional argument may be specified")
+	})
+}
+
+func Test_getSignedReferences(t *testing.T) {
+	t.Run("relocated invocation image", func(t *testing.T) {
+		refs, err := getSignedReferences(newSignedBundleReference())
+		require.NoError(t, err)
+
+		require.Len(t, refs, 2)
+		assert.Equal(t, "example.com/mybuns@"+testBundleDigest, refs[0].String())
+		assert.Equal(t, "example.com/mybuns@"+testImageDigest, refs[1].String())
+	})
+
+	t.Run("invocation image digest", func(t *testing.T) {
+		bundleRef := newSignedBundleReference()
+		bundleRef.RelocationMap = nil
+
+		refs, err := getSignedReferences(bundleRef)
+		require.NoError(t, err)
+
+		require.Len(t, refs, 2)
+		assert.Equal(t, "getporter/mybuns-installer@"+testImageDigest, refs[1].String())
+	})
+
+	t.Run("invocation image without a digest", func(t *testing.T) {
+		bundleRef := newSignedBundleReference()
+		bundleRef.RelocationMap = nil
+		bundleRef.Definition.InvocationImages[0].Digest = ""
+
+		_, err := getSignedReferences(bundleRef)
+		require.ErrorContains(t, err, "does not have a digest")
+	})
+
+	t.Run("not from a registry", func(t *testing.T) {
+		bundleRef := newSignedBundleReference()
+		bundleRef.Digest = ""
+
+		_, err := getSignedReferences(bundleRef)
+		require.ErrorContains(t, err, "only bundles pulled from or published to a registry have signatures")
+	})
+}
+
+func TestPorter_signBundle(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.Data.Signing.Key = "cosign.key"
+	p.Setenv(test.ExpectedCommandEnv,
+		"cosign sign --yes --key cosign.key example.com/mybuns@"+testBundleDigest+"\n"+
+			"cosign sign --yes --key cosign.key example.com/mybuns@"+testImageDigest)
+
+	err := p.signBundle(ctx, newSignedBundleReference(), cnabtooci.RegistryOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Signing example.com/mybuns@"+testBundleDigest)
+}
+
+func TestPorter_verifyBundleSignatures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("trusted", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.Signing.VerifyKey = "cosign.pub"
+		p.Setenv(test.ExpectedCommandEnv,
+			"cosign verify --key cosign.pub --allow-insecure-registry example.com/mybuns@"+testBundleDigest+"\n"+
+				"cosign verify --key cosign.pub --allow-insecure-registry example.com/mybuns@"+testImageDigest)
+
+		err := p.verifyBundleSignatures(ctx, newSignedBundleReference(), cnabtooci.RegistryOptions{InsecureRegistry: true})
+		require.NoError(t, err)
+		assert.Contains(t, p.TestConfig.TestContext.GetOutput(), "Verified the signature of example.com/mybuns@"+testImageDigest)
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.Signing.VerifyKey = "cosign.pub"
+		p.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+
+		err := p.verifyBundleSignatures(ctx, newSignedBundleReference(), cnabtooci.RegistryOptions{})
+		require.ErrorContains(t, err, "example.com/mybuns@"+testBundleDigest+" does not have a trusted signature")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"get.porter.sh/porter/pkg/build"
@@ -25,11 +26,11 @@ func (p *Porter) ensureLocalBundleIsUpToDate(ctx context.Context, opts bundleFil
 
 	upToDate, err := p.IsBundleUpToDate(ctx, opts)
 	if err != nil {
-		fmt.Fprintln(p.Err, "warning", err)
+		log.Warnf("Could not determine if the bundle is up-to-date, rebuilding it: %s", err)
 	}
 
 	if !upToDate {
-		fmt.Fprintln(p.Out, "Building bundle ===>")
+		p.printInfof("Building bundle ===>\n")
 		// opts.File is non-empty, which overrides opts.CNABFile if set
 		// (which may be if a cached bundle is fetched e.g. when running an action)
 		opts.CNABFile = ""
@@ -70,6 +71,11 @@ func (p *Porter) IsBundleUpToDate(ctx context.Context, opts bundleFileOptions) (
 			return false, span.Error(fmt.Errorf("could not marshal data from %s: %w", opts.CNABFile, err))
 		}
 
+		// Invocation images built for multiple platforms are saved as an OCI
+		// image layout next to the bundle.json instead of in the local image cache.
+		imageLayout := filepath.Join(filepath.Dir(opts.CNABFile), filepath.Base(build.LOCAL_IMAGE_LAYOUT))
+		isMultiPlatform, _ := p.FileSystem.DirExists(imageLayout)
+
 		// Check whether invocation images exist in host registry.
 		for _, invocationImage := range bun.InvocationImages {
 			// if the invovationImage is built before using a random string tag,
@@ -83,6 +89,10 @@ func (p *Porter) IsBundleUpToDate(ctx context.Context, opts bundleFileOptions) (
 				return false, span.Errorf("error parsing %s as an OCI image reference: %w", invocationImage.Image, err)
 			}
 
+			if isMultiPlatform {
+				continue
+			}
+
 			cachedImg, err := p.Registry.GetCachedImage(ctx, imgRef)
 			if err != nil {
 				return false, err
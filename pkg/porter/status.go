@@ -0,0 +1,297 @@
+package porter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/printer"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+	dtprinter "github.com/carolynvs/datetime-printer"
+)
+
+// Phases of an installation reported by porter installation status.
+const (
+	// PhaseDefined is an installation that has not been installed yet.
+	PhaseDefined = "defined"
+
+	// PhaseRunning is an installation with an action that is in progress.
+	PhaseRunning = "running"
+
+	// PhaseInstalled is an installation whose last action succeeded.
+	PhaseInstalled = "installed"
+
+	// PhaseFailed is an installation whose last action failed.
+	PhaseFailed = "failed"
+
+	// PhaseUninstalled is an installation that was uninstalled.
+	PhaseUninstalled = "uninstalled"
+
+	// PhasePaused is an installation that is paused and not reconciled.
+	PhasePaused = "paused"
+)
+
+// InstallationStatusSchemaVersion is the version of the InstallationStatusReport document.
+// Increment it when the document changes in a way that is not backwards compatible.
+const InstallationStatusSchemaVersion = "1.0.0"
+
+// InstallationStatusReport is a machine-readable summary of the status of an
+// installation, intended for automation such as CI gates. Unlike the output of
+// porter installation show, the fields are always present.
+type InstallationStatusReport struct {
+	// SchemaType is always InstallationStatus.
+	SchemaType string `json:"schemaType" yaml:"schemaType"`
+
+	// SchemaVersion of the document.
+	SchemaVersion string `json:"schemaVersion" yaml:"schemaVersion"`
+
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Name      string `json:"name" yaml:"name"`
+
+	// Phase of the installation: defined, running, installed, failed, uninstalled or paused.
+	Phase string `json:"phase" yaml:"phase"`
+
+	// LastAction is the action of the most recent run.
+	LastAction string `json:"lastAction" yaml:"lastAction"`
+
+	// LastRunID is the id of the most recent run.
+	LastRunID string `json:"lastRunId" yaml:"lastRunId"`
+
+	// LastResult is the status of the most recent run.
+	LastResult string `json:"lastResult" yaml:"lastResult"`
+
+	// LastSucceededAction is the action of the most recent run that succeeded.
+	LastSucceededAction string `json:"lastSucceededAction" yaml:"lastSucceededAction"`
+
+	// LastSucceededRunID is the id of the most recent run that succeeded.
+	LastSucceededRunID string `json:"lastSucceededRunId" yaml:"lastSucceededRunId"`
+
+	// LastSucceeded is when the most recent run that succeeded completed.
+	LastSucceeded *time.Time `json:"lastSucceeded" yaml:"lastSucceeded"`
+
+	// Drifted indicates that the desired state of the installation does not match
+	// the last run, so reconciling the installation would execute the bundle.
+	Drifted bool `json:"drifted" yaml:"drifted"`
+
+	// DriftReasons explain why the installation has drifted.
+	DriftReasons []string `json:"driftReasons" yaml:"driftReasons"`
+
+	// OutputsDigest is a digest of the most recent value of each output, that
+	// changes when any output changes. Empty when the installation has no outputs.
+	OutputsDigest string `json:"outputsDigest" yaml:"outputsDigest"`
+
+	// BundleReference of the bundle that last altered the installation.
+	BundleReference string `json:"bundleReference" yaml:"bundleReference"`
+
+	// BundleDigest of the bundle that last altered the installation.
+	BundleDigest string `json:"bundleDigest" yaml:"bundleDigest"`
+
+	// Modified timestamp of the installation.
+	Modified time.Time `json:"modified" yaml:"modified"`
+}
+
+// StatusOptions represent options for Porter's installation status command
+type StatusOptions struct {
+	installationOptions
+	printer.PrintOptions
+}
+
+// Validate prepares for the installation status command and validates the args/options.
+func (o *StatusOptions) Validate(args []string, cxt *portercontext.Context) error {
+	// Ensure only one argument exists (installation name) if args length non-zero
+	err := o.installationOptions.validateInstallationName(args)
+	if err != nil {
+		return err
+	}
+
+	err = o.installationOptions.defaultBundleFiles(cxt)
+	if err != nil {
+		return err
+	}
+
+	return o.PrintOptions.Validate(ShowDefaultFormat, ShowAllowedFormats)
+}
+
+// GetInstallationStatus builds a machine-readable status report for an installation.
+func (p *Porter) GetInstallationStatus(ctx context.Context, opts StatusOptions) (InstallationStatusReport, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	err := p.applyDefaultOptions(ctx, &opts.installationOptions)
+	if err != nil {
+		return InstallationStatusReport{}, err
+	}
+
+	installation, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return InstallationStatusReport{}, span.Error(fmt.Errorf("could not retrieve installation %s/%s: %w", opts.Namespace, opts.Name, err))
+	}
+
+	runs, runResults, err := p.Installations.ListRuns(ctx, installation.Namespace, installation.Name)
+	if err != nil {
+		return InstallationStatusReport{}, span.Error(fmt.Errorf("could not list runs for installation %s: %w", installation, err))
+	}
+
+	outputs, err := p.Installations.GetLastOutputs(ctx, installation.Namespace, installation.Name)
+	if err != nil {
+		return InstallationStatusReport{}, span.Error(fmt.Errorf("could not retrieve the outputs for installation %s: %w", installation, err))
+	}
+
+	report := newInstallationStatusReport(installation, runs, runResults, outputs)
+
+	driftReasons, err := p.getInstallationDrift(ctx, installation)
+	if err != nil {
+		return InstallationStatusReport{}, span.Error(fmt.Errorf("could not determine if installation %s has drifted: %w", installation, err))
+	}
+	report.Drifted = len(driftReasons) > 0
+	report.DriftReasons = append(report.DriftReasons, driftReasons...)
+
+	return report, nil
+}
+
+// getInstallationDrift returns the reasons that reconciling the installation would
+// execute the bundle. Drift is not reported for installations that are paused or
+// uninstalled, because they are not reconciled, or that do not define a bundle
+// reference, for example when they were installed from a local bundle.
+func (p *Porter) getInstallationDrift(ctx context.Context, installation storage.Installation) ([]string, error) {
+	if installation.Status.Suspended || installation.IsUninstalled() {
+		return nil, nil
+	}
+	if _, ok, _ := installation.Bundle.GetBundleReference(); !ok {
+		return nil, nil
+	}
+
+	lastRun, action, err := p.prepareReconcile(ctx, &installation, false)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := p.DiffInstallation(ctx, installation, lastRun, action)
+	if err != nil {
+		return nil, err
+	}
+	if diff.InSync {
+		return nil, nil
+	}
+	return diff.Reasons, nil
+}
+
+// newInstallationStatusReport summarizes the status of an installation from its
+// runs, which must be sorted from oldest to newest, and its most recent outputs.
+func newInstallationStatusReport(installation storage.Installation, runs []storage.Run, runResults map[string][]storage.Result, outputs storage.Outputs) InstallationStatusReport {
+	report := InstallationStatusReport{
+		SchemaType:      "InstallationStatus",
+		SchemaVersion:   InstallationStatusSchemaVersion,
+		Namespace:       installation.Namespace,
+		Name:            installation.Name,
+		Phase:           getInstallationPhase(installation),
+		DriftReasons:    []string{},
+		OutputsDigest:   digestOutputs(outputs),
+		BundleReference: installation.Status.BundleReference,
+		BundleDigest:    installation.Status.BundleDigest,
+		Modified:        installation.Status.Modified,
+	}
+
+	if len(runs) > 0 {
+		lastRun := runs[len(runs)-1]
+		report.LastAction = lastRun.Action
+		report.LastRunID = lastRun.ID
+		if results := runResults[lastRun.ID]; len(results) > 0 {
+			report.LastResult = results[len(results)-1].Status
+		}
+	}
+
+	for i := len(runs) - 1; i >= 0; i-- {
+		results := runResults[runs[i].ID]
+		if len(results) == 0 {
+			continue
+		}
+		lastResult := results[len(results)-1]
+		if lastResult.Status == cnab.StatusSucceeded {
+			report.LastSucceededAction = runs[i].Action
+			report.LastSucceededRunID = runs[i].ID
+			report.LastSucceeded = &lastResult.Created
+			break
+		}
+	}
+
+	return report
+}
+
+// getInstallationPhase determines the phase of the installation from the last
+// action that modified it.
+func getInstallationPhase(installation storage.Installation) string {
+	switch {
+	case installation.Status.Suspended:
+		return PhasePaused
+	case installation.Status.ResultStatus == cnab.StatusRunning || installation.Status.ResultStatus == cnab.StatusPending:
+		return PhaseRunning
+	case installation.Status.ResultStatus == cnab.StatusFailed:
+		return PhaseFailed
+	case installation.IsUninstalled():
+		return PhaseUninstalled
+	case installation.IsInstalled():
+		return PhaseInstalled
+	default:
+		return PhaseDefined
+	}
+}
+
+// digestOutputs calculates a digest of the outputs from their names and values.
+// Sensitive outputs are stored as references to a secret, or encrypted, so the
+// digest does not include their values.
+func digestOutputs(outputs storage.Outputs) string {
+	if outputs.Len() == 0 {
+		return ""
+	}
+
+	// The outputs are sorted by name, so the digest is stable
+	h := sha256.New()
+	for _, output := range outputs.Value() {
+		fmt.Fprintf(h, "%s=%d:", output.Name, len(output.Value))
+		h.Write(output.Value)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// PrintInstallationStatus prints the status of an installation.
+func (p *Porter) PrintInstallationStatus(ctx context.Context, opts StatusOptions) error {
+	report, err := p.GetInstallationStatus(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case printer.FormatJson:
+		return printer.PrintJson(p.Out, report)
+	case printer.FormatYaml:
+		return printer.PrintYaml(p.Out, report)
+	case printer.FormatPlaintext:
+		now := time.Now()
+		tp := dtprinter.DateTimePrinter{
+			Now: func() time.Time { return now },
+		}
+
+		fmt.Fprintf(p.Out, "Installation: %s/%s\n", report.Namespace, report.Name)
+		fmt.Fprintf(p.Out, "Phase: %s\n", report.Phase)
+		fmt.Fprintf(p.Out, "Last Action: %s\n", report.LastAction)
+		fmt.Fprintf(p.Out, "Last Result: %s\n", report.LastResult)
+		fmt.Fprintf(p.Out, "Last Succeeded Action: %s\n", report.LastSucceededAction)
+		if report.LastSucceeded != nil {
+			fmt.Fprintf(p.Out, "Last Succeeded: %s\n", tp.Format(*report.LastSucceeded))
+		}
+		fmt.Fprintf(p.Out, "Drifted: %t\n", report.Drifted)
+		for _, reason := range report.DriftReasons {
+			fmt.Fprintf(p.Out, "  - %s\n", reason)
+		}
+		fmt.Fprintf(p.Out, "Outputs Digest: %s\n", report.OutputsDigest)
+		return nil
+	default:
+		return fmt.Errorf("invalid format: %s", opts.Format)
+	}
+}
@@ -0,0 +1,73 @@
+package porter
+
+import (
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInstallationPhase(t *testing.T) {
+	later := now.Add(time.Hour)
+	testcases := []struct {
+		name   string
+		status storage.InstallationStatus
+		want   string
+	}{
+		{name: "never run", want: PhaseDefined},
+		{name: "running", status: storage.InstallationStatus{ResultStatus: cnab.StatusRunning}, want: PhaseRunning},
+		{name: "failed", status: storage.InstallationStatus{Installed: &now, ResultStatus: cnab.StatusFailed}, want: PhaseFailed},
+		{name: "installed", status: storage.InstallationStatus{Installed: &now, ResultStatus: cnab.StatusSucceeded}, want: PhaseInstalled},
+		{name: "uninstalled", status: storage.InstallationStatus{Installed: &now, Uninstalled: &later, ResultStatus: cnab.StatusSucceeded}, want: PhaseUninstalled},
+		{name: "paused", status: storage.InstallationStatus{Installed: &now, Suspended: true, ResultStatus: cnab.StatusFailed}, want: PhasePaused},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			i := storage.NewInstallation("dev", "mywordpress")
+			i.Status = tc.status
+			assert.Equal(t, tc.want, getInstallationPhase(i))
+		})
+	}
+}
+
+func TestNewInstallationStatusReport(t *testing.T) {
+	i := storage.NewInstallation("dev", "mywordpress")
+	install := i.NewRun(cnab.ActionInstall)
+	install.ID = "1"
+	upgrade := i.NewRun(cnab.ActionUpgrade)
+	upgrade.ID = "2"
+
+	installResult := install.NewResult(cnab.StatusSucceeded)
+	installResult.Created = now
+	runResults := map[string][]storage.Result{
+		"1": {installResult},
+		"2": {upgrade.NewResult(cnab.StatusFailed)},
+	}
+	outputs := storage.NewOutputs([]storage.Output{
+		installResult.NewOutput("password", []byte("secret")),
+		installResult.NewOutput("host", []byte("localhost")),
+	})
+
+	report := newInstallationStatusReport(i, []storage.Run{install, upgrade}, runResults, outputs)
+	assert.Equal(t, "InstallationStatus", report.SchemaType)
+	assert.Equal(t, cnab.ActionUpgrade, report.LastAction)
+	assert.Equal(t, cnab.StatusFailed, report.LastResult)
+	assert.Equal(t, cnab.ActionInstall, report.LastSucceededAction)
+	assert.Equal(t, "1", report.LastSucceededRunID)
+	require.NotNil(t, report.LastSucceeded)
+	assert.Equal(t, now, *report.LastSucceeded)
+	assert.NotNil(t, report.DriftReasons, "drift reasons should always be present in the document")
+	assert.Contains(t, report.OutputsDigest, "sha256:")
+
+	// The digest changes when an output changes
+	changed := storage.NewOutputs([]storage.Output{
+		installResult.NewOutput("password", []byte("secret")),
+		installResult.NewOutput("host", []byte("example.com")),
+	})
+	assert.NotEqual(t, report.OutputsDigest, digestOutputs(changed))
+	assert.Empty(t, digestOutputs(storage.Outputs{}))
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/secrets"
 	"get.porter.sh/porter/pkg/storage"
 	"get.porter.sh/porter/pkg/tracing"
 	"github.com/hashicorp/go-multierror"
@@ -41,6 +42,129 @@ func (p *Porter) MigrateStorage(ctx context.Context, opts MigrateStorageOptions)
 	return p.Storage.Migrate(ctx, migrateOpts)
 }
 
+// RewrapStorage rotates the data key used to encrypt sensitive data, re-encrypting
+// the existing values on installations, runs and outputs with the new data key.
+// Once every value is re-encrypted, the previous data keys are removed.
+func (p *Porter) RewrapStorage(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if !p.Encryptor.IsEnabled() {
+		return span.Error(storage.ErrEncryptionNotConfigured)
+	}
+
+	key, err := p.Encryptor.RotateDataKey(ctx)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	rewrap := func(params []secrets.Strategy) (bool, error) {
+		changed := false
+		for i, param := range params {
+			if param.Source.Key != storage.SourceEncrypted {
+				continue
+			}
+
+			value, err := p.Encryptor.Decrypt(ctx, param.Source.Value)
+			if err != nil {
+				return false, fmt.Errorf("could not decrypt parameter %s: %w", param.Name, err)
+			}
+
+			params[i].Source.Value, err = p.Encryptor.Encrypt(ctx, value)
+			if err != nil {
+				return false, fmt.Errorf("could not encrypt parameter %s: %w", param.Name, err)
+			}
+			changed = true
+		}
+		return changed, nil
+	}
+
+	installations, err := p.Installations.ListInstallations(ctx, storage.ListOptions{Namespace: "*"})
+	if err != nil {
+		return span.Error(fmt.Errorf("could not list installations: %w", err))
+	}
+
+	rewrapOutputs := func(results []storage.Result) (int, error) {
+		count := 0
+		for _, result := range results {
+			outputs, err := p.Installations.ListOutputs(ctx, result.ID)
+			if err != nil {
+				return 0, fmt.Errorf("could not list outputs for result %s: %w", result.ID, err)
+			}
+
+			for _, output := range outputs {
+				if !output.Encrypted {
+					continue
+				}
+
+				value, err := p.Encryptor.Decrypt(ctx, string(output.Value))
+				if err != nil {
+					return 0, fmt.Errorf("could not decrypt output %s: %w", output.Name, err)
+				}
+				encrypted, err := p.Encryptor.Encrypt(ctx, value)
+				if err != nil {
+					return 0, fmt.Errorf("could not encrypt output %s: %w", output.Name, err)
+				}
+				output.Value = []byte(encrypted)
+				if err = p.Installations.UpdateOutput(ctx, output); err != nil {
+					return 0, fmt.Errorf("could not save output %s: %w", output.Name, err)
+				}
+				count++
+			}
+		}
+		return count, nil
+	}
+
+	var installationCount, runCount, outputCount int
+	for _, inst := range installations {
+		changed, err := rewrap(inst.Parameters.Parameters)
+		if err != nil {
+			return span.Error(fmt.Errorf("could not rewrap installation %s: %w", inst, err))
+		}
+		if changed {
+			if err = p.Installations.UpdateInstallation(ctx, inst); err != nil {
+				return span.Error(fmt.Errorf("could not save installation %s: %w", inst, err))
+			}
+			installationCount++
+		}
+
+		runs, results, err := p.Installations.ListRuns(ctx, inst.Namespace, inst.Name)
+		if err != nil {
+			return span.Error(fmt.Errorf("could not list runs for installation %s: %w", inst, err))
+		}
+
+		for _, run := range runs {
+			changedParams, err := rewrap(run.Parameters.Parameters)
+			if err != nil {
+				return span.Error(fmt.Errorf("could not rewrap run %s: %w", run.ID, err))
+			}
+			changedOverrides, err := rewrap(run.ParameterOverrides.Parameters)
+			if err != nil {
+				return span.Error(fmt.Errorf("could not rewrap run %s: %w", run.ID, err))
+			}
+			if changedParams || changedOverrides {
+				if err = p.Installations.UpsertRun(ctx, run); err != nil {
+					return span.Error(fmt.Errorf("could not save run %s: %w", run.ID, err))
+				}
+				runCount++
+			}
+
+			count, err := rewrapOutputs(results[run.ID])
+			if err != nil {
+				return span.Error(fmt.Errorf("could not rewrap the outputs of run %s: %w", run.ID, err))
+			}
+			outputCount += count
+		}
+	}
+
+	if err = p.Encryptor.RemoveDataKeys(ctx); err != nil {
+		return span.Error(fmt.Errorf("could not remove the previous data keys: %w", err))
+	}
+
+	span.Infof("Re-encrypted %d installation(s), %d run(s) and %d output(s) with data key %s", installationCount, runCount, outputCount, key.ID)
+	return nil
+}
+
 func (p *Porter) FixPermissions(ctx context.Context) error {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
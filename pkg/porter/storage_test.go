@@ -0,0 +1,85 @@
+package porter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorter_RewrapStorage(t *testing.T) {
+	ctx := context.Background()
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.Config.Data.Encryption.Key = "my-secret-key"
+
+	sensitive := true
+	bun := cnab.NewBundle(bundle.Bundle{
+		Definitions: definition.Definitions{
+			"secret": &definition.Schema{Type: "string", WriteOnly: &sensitive},
+		},
+		Parameters: map[string]bundle.Parameter{
+			"password": {Definition: "secret"},
+		},
+		Outputs: map[string]bundle.Output{
+			"token": {Definition: "secret"},
+		},
+	})
+
+	// Save an installation, run and output with values encrypted by the first data key
+	i := storage.NewInstallation("", "mybuns")
+	params, err := p.Sanitizer.CleanRawParameters(ctx, map[string]interface{}{"password": "top secret"}, bun, i.ID)
+	require.NoError(t, err)
+	i.Parameters = storage.NewParameterSet("", "mybuns", params...)
+	p.TestInstallations.CreateInstallation(i)
+
+	run := i.NewRun(cnab.ActionInstall)
+	run.Parameters = i.Parameters
+	run = p.TestInstallations.CreateRun(run)
+	result := p.TestInstallations.CreateResult(run.NewResult(cnab.StatusSucceeded))
+	output, err := p.Sanitizer.CleanOutput(ctx, result.NewOutput("token", []byte("top secret")), bun)
+	require.NoError(t, err)
+	p.TestInstallations.CreateOutput(output)
+
+	var oldKey storage.DataKey
+	require.NoError(t, p.TestStore.FindOne(ctx, storage.CollectionDataKeys, storage.FindOptions{}, &oldKey))
+
+	require.NoError(t, p.RewrapStorage(ctx))
+
+	var keys []storage.DataKey
+	require.NoError(t, p.TestStore.Find(ctx, storage.CollectionDataKeys, storage.FindOptions{}, &keys))
+	require.Len(t, keys, 1, "the previous data key should be removed")
+	newKey := keys[0]
+	require.NotEqual(t, oldKey.ID, newKey.ID, "a new data key should be generated")
+
+	// Every value is encrypted with the new data key, and is restored by the sanitizer
+	assertRewrapped := func(pset storage.ParameterSet) {
+		require.Len(t, pset.Parameters, 1)
+		assert.True(t, strings.HasPrefix(pset.Parameters[0].Source.Value, newKey.ID+":"), "the parameter should be encrypted with the new data key")
+		resolved, err := p.Sanitizer.RestoreParameterSet(ctx, pset, bun)
+		require.NoError(t, err)
+		assert.Equal(t, "top secret", resolved["password"])
+	}
+
+	gotInstallation, err := p.Installations.GetInstallation(ctx, "", "mybuns")
+	require.NoError(t, err)
+	assertRewrapped(gotInstallation.Parameters)
+
+	gotRun, err := p.Installations.GetRun(ctx, run.ID)
+	require.NoError(t, err)
+	assertRewrapped(gotRun.Parameters)
+
+	outputs, err := p.Installations.ListOutputs(ctx, result.ID)
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	assert.True(t, strings.HasPrefix(string(outputs[0].Value), newKey.ID+":"), "the output should be encrypted with the new data key")
+	gotOutput, err := p.Sanitizer.RestoreOutput(ctx, outputs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "top secret", string(gotOutput.Value))
+}
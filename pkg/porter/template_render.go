@@ -0,0 +1,211 @@
+package porter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// TemplateRenderOptions are the options for rendering the templating in a porter manifest.
+type TemplateRenderOptions struct {
+	// File path to the porter manifest. Defaults to the bundle in the current directory.
+	File string
+
+	// Action determines which parameters apply when the manifest is rendered. Defaults to install.
+	Action string
+
+	// Name of the installation. Defaults to the name of the bundle.
+	Name string
+
+	// Namespace of the installation. Defaults to the namespace set in the porter configuration.
+	Namespace string
+
+	// Params are the parameter values used to render the manifest, in the format NAME=VALUE.
+	Params []string
+
+	parsedParams map[string]string
+}
+
+// Validate the template render options.
+func (o *TemplateRenderOptions) Validate(cxt *portercontext.Context) error {
+	if o.File == "" {
+		o.File = config.Name
+	}
+	if _, err := cxt.FileSystem.Stat(o.File); err != nil {
+		return fmt.Errorf("unable to access --file %s: %w", o.File, err)
+	}
+
+	if o.Action == "" {
+		o.Action = cnab.ActionInstall
+	}
+
+	params, err := storage.ParseVariableAssignments(o.Params)
+	if err != nil {
+		return err
+	}
+	o.parsedParams = params
+
+	return nil
+}
+
+// RenderManifestTemplate prints the manifest with its templating resolved for the specified
+// parameter values, so that templates can be debugged without building and running the bundle.
+// Values that are only known when the bundle is run, such as credentials and outputs,
+// are left as template expressions.
+func (p *Porter) RenderManifestTemplate(ctx context.Context, opts TemplateRenderOptions) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	m, err := manifest.LoadManifestFrom(ctx, p.Config, opts.File)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	switch opts.Action {
+	case cnab.ActionInstall, cnab.ActionUpgrade, cnab.ActionUninstall:
+	default:
+		if _, ok := m.CustomActions[opts.Action]; !ok {
+			return log.Error(fmt.Errorf("invalid --action %s, the bundle does not define the action", opts.Action))
+		}
+	}
+
+	for name := range opts.parsedParams {
+		if _, ok := m.Parameters[name]; !ok {
+			return log.Error(fmt.Errorf("invalid --param %s, the parameter is not defined by the bundle", name))
+		}
+	}
+
+	data, err := manifest.ReadManifestData(p.Config, opts.File)
+	if err != nil {
+		return log.Error(err)
+	}
+
+	sourceData := p.buildTemplateRenderData(m, opts)
+	rendered, _, err := manifest.RenderTemplate(m.GetTemplatePrefix()+string(data), sourceData, nil)
+	if err != nil {
+		return log.Error(fmt.Errorf("unable to render the manifest template: %w", err))
+	}
+
+	fmt.Fprint(p.Out, rendered)
+	return nil
+}
+
+// buildTemplateRenderData creates the data available to the manifest templating,
+// using the same structure as when the bundle is run.
+func (p *Porter) buildTemplateRenderData(m *manifest.Manifest, opts TemplateRenderOptions) map[string]interface{} {
+	data := make(map[string]interface{})
+
+	name := opts.Name
+	if name == "" {
+		name = m.Name
+	}
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = p.Data.Namespace
+	}
+	data["installation"] = map[string]interface{}{
+		"name":      name,
+		"namespace": namespace,
+	}
+
+	bun := map[string]interface{}{
+		"name":           m.Name,
+		"version":        m.Version,
+		"description":    m.Description,
+		"installerImage": m.Image,
+		"custom":         m.Custom,
+	}
+	data["bundle"] = bun
+
+	env := p.EnvironMap()
+	data["env"] = env
+
+	params := make(map[string]interface{})
+	bun["parameters"] = params
+	for _, param := range m.Parameters {
+		if !param.AppliesTo(opts.Action) {
+			continue
+		}
+		if value, ok := opts.parsedParams[param.Name]; ok {
+			params[param.Name] = manifest.NewTemplateParameterValue(param, value)
+		} else if param.Destination.Path != "" {
+			// File parameters resolve to the path where the file is written
+			params[param.Name] = param.Destination.Path
+		} else if param.Default != nil {
+			params[param.Name] = manifest.NewTemplateParameterValue(param, formatTemplateParameterValue(param.Default))
+		}
+	}
+
+	images := make(map[string]interface{}, len(m.ImageMap))
+	bun["images"] = images
+	for alias, img := range m.ImageMap {
+		images[alias] = map[string]string{
+			"description": img.Description,
+			"imageType":   img.ImageType,
+			"repository":  img.Repository,
+			"digest":      img.Digest,
+			"size":        fmt.Sprintf("%d", img.Size),
+			"mediaType":   img.MediaType,
+			"tag":         img.Tag,
+		}
+	}
+
+	// Credentials, outputs, dependencies and the iterations of foreach steps are only known when the bundle is run
+	prefix := m.GetTemplatePrefix()
+	for _, variable := range m.TemplateVariables {
+		parts := strings.Split(variable, ".")
+		unresolved := parts[0] == manifest.ForeachTemplateVariable
+		if len(parts) >= 3 && parts[0] == "bundle" {
+			switch parts[1] {
+			case "credentials", "outputs", "dependencies":
+				unresolved = true
+			}
+		}
+		if !unresolved {
+			continue
+		}
+
+		expr := "{{ " + variable + " }}"
+		if prefix == manifest.TemplateDelimiterPrefix {
+			expr = "${ " + variable + " }"
+		}
+		setTemplateRenderValue(data, parts, manifest.UnresolvedValue(expr))
+	}
+
+	return data
+}
+
+// formatTemplateParameterValue converts the default value of a parameter to the
+// value used when the bundle is run, where parameters are passed as strings.
+func formatTemplateParameterValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// setTemplateRenderValue sets the value at the path in the template data, creating any missing parents.
+func setTemplateRenderValue(data map[string]interface{}, path []string, value interface{}) {
+	current := data
+	for _, part := range path[:len(path)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[path[len(path)-1]] = value
+}
@@ -0,0 +1,55 @@
+package porter
+
+import (
+	"testing"
+
+	"get.porter.sh/porter/tests"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRenderOptions_Validate(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	p.TestConfig.TestContext.AddTestFile("testdata/templates/porter.yaml", "porter.yaml")
+
+	opts := TemplateRenderOptions{Params: []string{"name"}}
+	err := opts.Validate(p.Context)
+	tests.RequireErrorContains(t, err, "invalid parameter (name), must be in name=value format")
+
+	opts = TemplateRenderOptions{Params: []string{"name=porter"}}
+	require.NoError(t, opts.Validate(p.Context))
+	require.Equal(t, "porter.yaml", opts.File)
+	require.Equal(t, "install", opts.Action)
+}
+
+func TestPorter_RenderManifestTemplate(t *testing.T) {
+	testcases := []struct {
+		name     string
+		opts     TemplateRenderOptions
+		wantFile string
+		wantErr  string
+	}{
+		{name: "defaults", opts: TemplateRenderOptions{}, wantFile: "testdata/templates/rendered-defaults.yaml"},
+		{name: "params", opts: TemplateRenderOptions{Name: "mybuns", Params: []string{"name=Hello", "password=topsecret"}}, wantFile: "testdata/templates/rendered-params.yaml"},
+		{name: "unknown param", opts: TemplateRenderOptions{Params: []string{"oops=1"}}, wantErr: "invalid --param oops"},
+		{name: "unknown action", opts: TemplateRenderOptions{Action: "zombies"}, wantErr: "invalid --action zombies"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTestPorter(t)
+			defer p.Close()
+			p.TestConfig.TestContext.AddTestFile("testdata/templates/porter.yaml", "porter.yaml")
+
+			opts := tc.opts
+			require.NoError(t, opts.Validate(p.Context))
+
+			err := p.RenderManifestTemplate(p.RootContext, opts)
+			if tc.wantErr != "" {
+				tests.RequireErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			p.CompareGoldenFile(tc.wantFile, p.TestConfig.TestContext.GetOutput())
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package porter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/docker/go-units"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	// EnvNoColor is the standard environment variable that disables colored output, see https://no-color.org.
+	EnvNoColor = "NO_COLOR"
+
+	ansiReset     = "\033[0m"
+	ansiRed       = "\033[31m"
+	ansiGreen     = "\033[32m"
+	ansiYellow    = "\033[33m"
+	ansiClearLine = "\r\033[K"
+
+	// uiRefreshInterval is how often spinners and progress bars are redrawn.
+	uiRefreshInterval = 100 * time.Millisecond
+
+	progressBarWidth = 30
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// UI prints feedback for long-running commands to the console, such as
+// spinners, colored statuses and progress bars. Spinners and progress bars are
+// only printed when the output is a terminal, so that they do not clutter logs
+// and piped output, and are not printed with --quiet. Colors are also
+// disabled with --no-color or NO_COLOR.
+type UI struct {
+	out io.Writer
+
+	// interactive indicates that the output is a terminal that supports
+	// redrawing the current line.
+	interactive bool
+
+	// color indicates that the output supports colors.
+	color bool
+}
+
+// NewUI creates a UI that writes to stderr, so that it does not change the
+// output of a command that is consumed by other tools.
+func (p *Porter) NewUI() *UI {
+	return p.newUI(p.Err)
+}
+
+// newUI creates a UI that writes to out, detecting if it is a terminal that
+// supports colors.
+func (p *Porter) newUI(out io.Writer) *UI {
+	interactive := isTerminal(out) && p.Getenv("TERM") != "dumb" && !p.Data.Quiet
+	return &UI{
+		out:         out,
+		interactive: interactive,
+		color:       interactive && !p.Data.NoColor && p.Getenv(EnvNoColor) == "",
+	}
+}
+
+// printInfof prints an informational message, such as the progress of a
+// command, that is not part of the output requested by the user. Nothing is
+// printed when --quiet is specified.
+func (p *Porter) printInfof(format string, args ...interface{}) {
+	if p.Data.Quiet {
+		return
+	}
+	fmt.Fprintf(p.Out, format, args...)
+}
+
+// isTerminal determines if the stream, such as stdin or stdout, is a terminal.
+func isTerminal(stream interface{}) bool {
+	f, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Success formats a message as successful.
+func (u *UI) Success(msg string) string {
+	return u.colorize(ansiGreen, msg)
+}
+
+// Failure formats a message as failed.
+func (u *UI) Failure(msg string) string {
+	return u.colorize(ansiRed, msg)
+}
+
+// Warning formats a message as a warning.
+func (u *UI) Warning(msg string) string {
+	return u.colorize(ansiYellow, msg)
+}
+
+// FormatStatus colors the status of an installation or run: green when it
+// succeeded, red when it failed and yellow while it is in progress.
+func (u *UI) FormatStatus(status string) string {
+	switch status {
+	case cnab.StatusSucceeded:
+		return u.Success(status)
+	case cnab.StatusFailed:
+		return u.Failure(status)
+	case cnab.StatusRunning, cnab.StatusPending, StatusInstalling, StatusUpgrading, StatusUninstalling:
+		return u.Warning(status)
+	default:
+		return status
+	}
+}
+
+func (u *UI) colorize(color string, msg string) string {
+	if !u.color || msg == "" {
+		return msg
+	}
+	return color + msg + ansiReset
+}
+
+// Spinner is displayed while waiting on a step that does not report its progress,
+// such as pulling a bundle.
+type Spinner struct {
+	ui      *UI
+	message string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// StartSpinner displays a spinner next to the message until Stop is called.
+func (u *UI) StartSpinner(message string) *Spinner {
+	s := &Spinner{ui: u, message: message}
+	if !u.interactive {
+		return s
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return s
+}
+
+func (s *Spinner) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(uiRefreshInterval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		fmt.Fprintf(s.ui.out, "%s%s %s", ansiClearLine, spinnerFrames[i%len(spinnerFrames)], s.message)
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop the spinner and print whether the step succeeded, based on the error
+// returned by the step. Stop may be called more than once.
+func (s *Spinner) Stop(err error) {
+	if s.stop == nil {
+		return
+	}
+
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+
+	if err != nil {
+		fmt.Fprintf(s.ui.out, "%s%s %s\n", ansiClearLine, s.ui.Failure("✗"), s.message)
+	} else {
+		fmt.Fprintf(s.ui.out, "%s%s %s\n", ansiClearLine, s.ui.Success("✓"), s.message)
+	}
+}
+
+// ProgressBar displays the number of bytes processed by a step, such as
+// writing an archive. Write data to the progress bar to record it.
+type ProgressBar struct {
+	ui       *UI
+	message  string
+	total    int64
+	current  int64
+	rendered time.Time
+	mu       sync.Mutex
+}
+
+// NewProgressBar creates a progress bar for a step that processes total bytes.
+// When the total is not known ahead of time, use 0 and only the number of
+// bytes processed is displayed.
+func (u *UI) NewProgressBar(message string, total int64) *ProgressBar {
+	return &ProgressBar{ui: u, message: message, total: total}
+}
+
+// Write records that the data was processed, so that the progress bar can be
+// used with io.MultiWriter or io.TeeReader.
+func (b *ProgressBar) Write(data []byte) (int, error) {
+	b.Add(int64(len(data)))
+	return len(data), nil
+}
+
+// Add records that n bytes were processed.
+func (b *ProgressBar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.current += n
+	if b.ui.interactive && time.Since(b.rendered) >= uiRefreshInterval {
+		b.render()
+	}
+}
+
+// Finish displays the final byte count of the progress bar.
+func (b *ProgressBar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.ui.interactive {
+		return
+	}
+	b.render()
+	fmt.Fprintln(b.ui.out)
+}
+
+func (b *ProgressBar) render() {
+	b.rendered = time.Now()
+
+	if b.total <= 0 {
+		fmt.Fprintf(b.ui.out, "%s%s %s", ansiClearLine, b.message, units.HumanSize(float64(b.current)))
+		return
+	}
+
+	current := b.current
+	if current > b.total {
+		current = b.total
+	}
+	filled := int(current * progressBarWidth / b.total)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	percent := current * 100 / b.total
+	fmt.Fprintf(b.ui.out, "%s%s [%s] %3d%% %s/%s", ansiClearLine, b.message, bar, percent,
+		units.HumanSize(float64(current)), units.HumanSize(float64(b.total)))
+}
@@ -0,0 +1,123 @@
+package porter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPorter_NewUI(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	// The test output is not a terminal
+	ui := p.NewUI()
+	assert.False(t, ui.interactive, "the ui should not be interactive when the output is not a terminal")
+	assert.False(t, ui.color, "colors should be disabled when the output is not a terminal")
+}
+
+func TestUI_FormatStatus(t *testing.T) {
+	t.Run("color", func(t *testing.T) {
+		ui := &UI{color: true}
+		assert.Equal(t, "\033[32msucceeded\033[0m", ui.FormatStatus(cnab.StatusSucceeded))
+		assert.Equal(t, "\033[31mfailed\033[0m", ui.FormatStatus(cnab.StatusFailed))
+		assert.Equal(t, "\033[33minstalling\033[0m", ui.FormatStatus(StatusInstalling))
+		assert.Equal(t, "unknown", ui.FormatStatus(cnab.StatusUnknown))
+		assert.Equal(t, "", ui.FormatStatus(""))
+	})
+
+	t.Run("no color", func(t *testing.T) {
+		ui := &UI{}
+		assert.Equal(t, "succeeded", ui.FormatStatus(cnab.StatusSucceeded))
+		assert.Equal(t, "failed", ui.FormatStatus(cnab.StatusFailed))
+	})
+}
+
+func TestUI_Spinner(t *testing.T) {
+	t.Run("interactive", func(t *testing.T) {
+		var out bytes.Buffer
+		ui := &UI{out: &out, interactive: true}
+
+		ui.StartSpinner("Pulling example.com/mybuns:v1").Stop(nil)
+		assert.Contains(t, out.String(), "✓ Pulling example.com/mybuns:v1\n")
+
+		out.Reset()
+		s := ui.StartSpinner("Pulling example.com/mybuns:v2")
+		s.Stop(errors.New("not found"))
+		s.Stop(nil)
+		assert.Contains(t, out.String(), "✗ Pulling example.com/mybuns:v2\n")
+		assert.NotContains(t, out.String(), "✓", "stopping the spinner again should not print anything")
+	})
+
+	t.Run("not interactive", func(t *testing.T) {
+		var out bytes.Buffer
+		ui := &UI{out: &out}
+
+		ui.StartSpinner("Pulling example.com/mybuns:v1").Stop(nil)
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestUI_ProgressBar(t *testing.T) {
+	t.Run("known total", func(t *testing.T) {
+		var out bytes.Buffer
+		ui := &UI{out: &out, interactive: true}
+
+		bar := ui.NewProgressBar("Writing mybuns.tgz", 2000)
+		bar.Write(make([]byte, 1000))
+		bar.Finish()
+		assert.Equal(t, ansiClearLine+"Writing mybuns.tgz [===============               ]  50% 1kB/2kB\n", lastLine(out.String()))
+	})
+
+	t.Run("unknown total", func(t *testing.T) {
+		var out bytes.Buffer
+		ui := &UI{out: &out, interactive: true}
+
+		bar := ui.NewProgressBar("Writing mybuns.tgz", 0)
+		bar.Add(1500)
+		bar.Finish()
+		assert.Equal(t, ansiClearLine+"Writing mybuns.tgz 1.5kB\n", lastLine(out.String()))
+	})
+
+	t.Run("not interactive", func(t *testing.T) {
+		var out bytes.Buffer
+		ui := &UI{out: &out}
+
+		bar := ui.NewProgressBar("Writing mybuns.tgz", 0)
+		bar.Add(1500)
+		bar.Finish()
+		assert.Empty(t, out.String())
+	})
+}
+
+// lastLine returns the last redraw of the current line.
+func lastLine(output string) string {
+	i := strings.LastIndex(output, ansiClearLine)
+	if i < 0 {
+		return output
+	}
+	return output[i:]
+}
+
+func TestPorter_printInfof(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+
+		p.printInfof("Signing %s\n", "example.com/mybuns:v1")
+		assert.Equal(t, "Signing example.com/mybuns:v1\n", p.TestConfig.TestContext.GetOutput())
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		p := NewTestPorter(t)
+		defer p.Close()
+		p.Data.Quiet = true
+
+		p.printInfof("Signing %s\n", "example.com/mybuns:v1")
+		assert.Empty(t, p.TestConfig.TestContext.GetOutput())
+	})
+}
@@ -22,6 +22,7 @@ var ErrUnsafeInstallationDeleteRetryForceDelete = fmt.Errorf("%s; if you are sur
 type UninstallOptions struct {
 	*BundleExecutionOptions
 	UninstallDeleteOptions
+	BulkOptions
 }
 
 func NewUninstallOptions() UninstallOptions {
@@ -38,10 +39,27 @@ func (o UninstallOptions) GetActionVerb() string {
 	return "uninstalling"
 }
 
+func (o UninstallOptions) Validate(ctx context.Context, args []string, p *Porter) error {
+	if err := o.BulkOptions.Validate(args); err != nil {
+		return err
+	}
+	if err := o.BulkOptions.validateDestructive("uninstall"); err != nil {
+		return err
+	}
+	if o.IsBulk() {
+		return o.BundleExecutionOptions.validateBulk(p)
+	}
+
+	return o.BundleExecutionOptions.Validate(ctx, args, p)
+}
+
 // UninstallDeleteOptions supply options for deletion on uninstall
 type UninstallDeleteOptions struct {
 	Delete      bool
 	ForceDelete bool
+
+	// AllowProtected allows the installation to be deleted from a protected namespace.
+	AllowProtected bool
 }
 
 func (opts *UninstallDeleteOptions) shouldDelete() bool {
@@ -71,9 +89,37 @@ func (opts *UninstallDeleteOptions) handleUninstallErrs(out io.Writer, err error
 // UninstallBundle accepts a set of pre-validated UninstallOptions and uses
 // them to uninstall a bundle.
 func (p *Porter) UninstallBundle(ctx context.Context, opts UninstallOptions) error {
+	if opts.IsBulk() {
+		return p.executeBulkAction(ctx, opts.Namespace, opts.GetAction(), opts.BulkOptions, func(ctx context.Context, worker *Porter, name string) error {
+			installationOpts := opts
+			installationOpts.BundleExecutionOptions = opts.forInstallation(name)
+			installationOpts.BulkOptions = BulkOptions{}
+			return worker.UninstallBundle(ctx, installationOpts)
+		})
+	}
+
 	ctx, log := tracing.StartSpan(ctx)
 	defer log.EndSpan()
 
+	if opts.AllowProtected {
+		// Allow the installation and its dependencies to be deleted from a protected namespace
+		ctx = storage.WithForce(ctx)
+	}
+
+	// Check that the installation can be deleted before uninstalling it, so that
+	// it isn't left uninstalled in a protected namespace with its records intact
+	if opts.shouldDelete() {
+		if err := p.Namespaces.CheckProtected(ctx, storage.CollectionInstallations, opts.Namespace); err != nil {
+			return log.Error(err)
+		}
+	}
+
+	unlock, err := p.lockInstallation(ctx, opts.Namespace, opts.Name, opts.GetAction(), opts.WaitForLock)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	installation, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
 	if err != nil {
 		return fmt.Errorf("could not find installation %s/%s: %w", opts.Namespace, opts.Name, err)
@@ -96,7 +142,8 @@ func (p *Porter) UninstallBundle(ctx context.Context, opts UninstallOptions) err
 	}
 
 	log.Infof("%s bundle", opts.GetActionVerb())
-	err = p.CNAB.Execute(ctx, actionArgs)
+	err = p.executeWithHooks(ctx, opts.GetOptions(), actionArgs)
+	p.enforceHistoryRetention(ctx, installation)
 
 	var uninstallErrs error
 	if err != nil {
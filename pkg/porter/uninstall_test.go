@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"testing"
 
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/storage"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +56,32 @@ func TestPorter_HandleUninstallErrs(t *testing.T) {
 		})
 	}
 }
+
+func TestPorter_UninstallBundle_ProtectedNamespace(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+	ctx := p.RootContext
+
+	require.NoError(t, p.CreateNamespace(ctx, NamespaceCreateOptions{Name: "prod", Protected: true}))
+	i := p.TestInstallations.CreateInstallation(storage.NewInstallation("prod", "mybuns"))
+	run := p.TestInstallations.CreateRun(i.NewRun(cnab.ActionInstall))
+	p.TestInstallations.CreateResult(run.NewResult(cnab.StatusSucceeded))
+
+	for _, deleteOpts := range []UninstallDeleteOptions{{Delete: true}, {ForceDelete: true}} {
+		opts := NewUninstallOptions()
+		opts.Namespace = "prod"
+		opts.Name = "mybuns"
+		opts.UninstallDeleteOptions = deleteOpts
+
+		err := p.UninstallBundle(ctx, opts)
+		require.ErrorIs(t, err, storage.ErrNamespaceProtected{})
+		assert.Contains(t, err.Error(), "unless the command is run with the --allow-protected flag")
+	}
+
+	// The bundle should not be executed when the installation cannot be deleted afterwards
+	runs, _, err := p.Installations.ListRuns(ctx, "prod", "mybuns")
+	require.NoError(t, err)
+	assert.Len(t, runs, 1, "the uninstall action should not have been executed")
+	_, err = p.Installations.GetInstallation(ctx, "prod", "mybuns")
+	require.NoError(t, err, "the installation should not be deleted")
+}
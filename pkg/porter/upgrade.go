@@ -15,6 +15,8 @@ var _ BundleAction = NewUpgradeOptions()
 // Porter handles defaulting any missing values.
 type UpgradeOptions struct {
 	*BundleExecutionOptions
+	BulkOptions
+	WaitOptions
 
 	// Version of the bundle to upgrade to
 	Version string
@@ -27,6 +29,14 @@ func NewUpgradeOptions() *UpgradeOptions {
 }
 
 func (o *UpgradeOptions) Validate(ctx context.Context, args []string, p *Porter) error {
+	if err := o.BulkOptions.Validate(args); err != nil {
+		return err
+	}
+
+	if err := o.WaitOptions.Validate(); err != nil {
+		return err
+	}
+
 	if o.Version != "" && o.Reference != "" {
 		return errors.New("either --version or --reference may be set, but not both")
 	}
@@ -40,6 +50,10 @@ func (o *UpgradeOptions) Validate(ctx context.Context, args []string, p *Porter)
 		o.Version = v.String()
 	}
 
+	if o.IsBulk() {
+		return o.BundleExecutionOptions.validateBulk(p)
+	}
+
 	return o.BundleExecutionOptions.Validate(ctx, args, p)
 }
 
@@ -54,6 +68,24 @@ func (o *UpgradeOptions) GetActionVerb() string {
 // UpgradeBundle accepts a set of pre-validated UpgradeOptions and uses
 // them to upgrade a bundle.
 func (p *Porter) UpgradeBundle(ctx context.Context, opts *UpgradeOptions) error {
+	if opts.IsBulk() {
+		return p.executeBulkAction(ctx, opts.Namespace, opts.GetAction(), opts.BulkOptions, func(ctx context.Context, worker *Porter, name string) error {
+			installationOpts := *opts
+			installationOpts.BundleExecutionOptions = opts.forInstallation(name)
+			installationOpts.BulkOptions = BulkOptions{}
+			return worker.UpgradeBundle(ctx, &installationOpts)
+		})
+	}
+
+	// A dry run does not modify the installation, so it does not need the lock
+	if !opts.DryRun {
+		unlock, err := p.lockInstallation(ctx, opts.Namespace, opts.Name, opts.GetAction(), opts.WaitForLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
 	// Sync any changes specified by the user to the installation before running upgrade
 	i, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
 	if err != nil {
@@ -73,9 +105,11 @@ func (p *Porter) UpgradeBundle(ctx context.Context, opts *UpgradeOptions) error
 		return err
 	}
 
-	err = p.Installations.UpdateInstallation(ctx, i)
-	if err != nil {
-		return err
+	if !opts.DryRun {
+		err = p.Installations.UpdateInstallation(ctx, i)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Re-resolve the bundle after we have figured out the version we are upgrading to
@@ -84,5 +118,19 @@ func (p *Porter) UpgradeBundle(ctx context.Context, opts *UpgradeOptions) error
 		return err
 	}
 
-	return p.ExecuteAction(ctx, i, opts)
+	if opts.DryRun {
+		plan, err := p.planAction(ctx, i, opts)
+		if err != nil {
+			return err
+		}
+		p.printExecutionPlan(plan)
+		return nil
+	}
+
+	err = p.ExecuteAction(ctx, i, opts)
+	if err != nil || !opts.Wait {
+		return err
+	}
+
+	return p.waitForReady(ctx, opts.BundleExecutionOptions, opts.WaitTimeout)
 }
@@ -0,0 +1,153 @@
+package porter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/storage"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// ReadinessAction is the custom action that is invoked to determine if an
+	// installation is ready, when waiting for readiness.
+	ReadinessAction = "status"
+
+	// ReadinessOutput is the output generated by the readiness action that
+	// reports if the installation is ready: true or false. When the bundle does
+	// not define the output, the installation is ready once the readiness
+	// action succeeds.
+	ReadinessOutput = "ready"
+
+	// DefaultWaitTimeout is how long to wait for an installation to be ready
+	// when --wait-timeout is not specified.
+	DefaultWaitTimeout = 10 * time.Minute
+
+	// readinessPollInterval is how often the readiness action is invoked.
+	readinessPollInterval = 10 * time.Second
+)
+
+// WaitOptions control waiting for an installation to be ready after an action.
+type WaitOptions struct {
+	// Wait for the installation to be ready after the action succeeds.
+	Wait bool
+
+	// WaitTimeout is how long to wait for the installation to be ready.
+	WaitTimeout time.Duration
+}
+
+// Validate the wait options.
+func (o WaitOptions) Validate() error {
+	if o.WaitTimeout < 0 {
+		return errors.New("--wait-timeout must not be negative")
+	}
+	if o.WaitTimeout > 0 && !o.Wait {
+		return errors.New("--wait-timeout can only be used with --wait")
+	}
+	return nil
+}
+
+// waitForReady repeatedly invokes the readiness action of the bundle until it
+// reports that the installation is ready, or the timeout has elapsed.
+func (p *Porter) waitForReady(ctx context.Context, opts *BundleExecutionOptions, timeout time.Duration) error {
+	ctx, log := tracing.StartSpan(ctx)
+	defer log.EndSpan()
+
+	bundleRef, err := opts.GetBundleReference(ctx, p)
+	if err != nil {
+		return err
+	}
+	bun := bundleRef.Definition
+	if _, ok := bun.Actions[ReadinessAction]; !ok {
+		log.Warnf("Not waiting for installation %s/%s to be ready because the bundle does not define a %s action", opts.Namespace, opts.Name, ReadinessAction)
+		return nil
+	}
+
+	checkOutput := false
+	if outputDef, ok := bun.Outputs[ReadinessOutput]; ok && outputDef.AppliesTo(ReadinessAction) {
+		checkOutput = true
+	}
+
+	if timeout == 0 {
+		timeout = DefaultWaitTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	log.Infof("Waiting up to %s for installation %s/%s to be ready", timeout, opts.Namespace, opts.Name)
+
+	// Record every poll on the same run, instead of a run per poll
+	run := &storage.Run{}
+	var lastErr error
+	for poll := 1; ; poll++ {
+		ready, err := p.checkReadiness(ctx, opts, run, checkOutput)
+		if err != nil {
+			lastErr = err
+		} else if ready {
+			log.Infof("Installation %s/%s is ready", opts.Namespace, opts.Name)
+			return nil
+		} else {
+			lastErr = fmt.Errorf("the %s output was not true", ReadinessOutput)
+		}
+		log.Infof("Installation %s/%s is not ready after %d readiness check(s): %s", opts.Namespace, opts.Name, poll, lastErr)
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return log.Error(fmt.Errorf("timed out after %s waiting for installation %s/%s to be ready: %w", timeout, opts.Namespace, opts.Name, lastErr))
+		}
+
+		wait := readinessPollInterval
+		if remaining < wait {
+			wait = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return log.Error(ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// checkReadiness invokes the readiness action against the installation, and
+// reports if the installation is ready. The action is recorded on the specified
+// run, which is created by the first check.
+func (p *Porter) checkReadiness(ctx context.Context, opts *BundleExecutionOptions, run *storage.Run, checkOutput bool) (bool, error) {
+	installation, err := p.Installations.GetInstallation(ctx, opts.Namespace, opts.Name)
+	if err != nil {
+		return false, fmt.Errorf("could not find installation %s/%s: %w", opts.Namespace, opts.Name, err)
+	}
+
+	// Reuse the parameters and credentials saved on the installation by the
+	// action, instead of resolving the command-line flags again.
+	execOpts := *opts
+	execOpts.Params = nil
+	execOpts.ParameterSets = nil
+	execOpts.CredentialIdentifiers = nil
+	execOpts.run = run
+	statusOpts := InvokeOptions{
+		BundleExecutionOptions: &execOpts,
+		Action:                 ReadinessAction,
+	}
+
+	if err = p.applyActionOptionsToInstallation(ctx, statusOpts, &installation); err != nil {
+		return false, err
+	}
+
+	if err = p.ExecuteAction(ctx, installation, statusOpts); err != nil {
+		return false, err
+	}
+
+	if !checkOutput {
+		return true, nil
+	}
+
+	ready, err := p.ReadBundleOutput(ctx, ReadinessOutput, opts.Name, opts.Namespace)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound{}) {
+			return false, nil
+		}
+		return false, err
+	}
+	return strings.EqualFold(strings.TrimSpace(ready), "true"), nil
+}
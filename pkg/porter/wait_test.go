@@ -0,0 +1,49 @@
+package porter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitOptions_Validate(t *testing.T) {
+	testcases := []struct {
+		name      string
+		opts      WaitOptions
+		wantError string
+	}{
+		{name: "no wait"},
+		{name: "wait", opts: WaitOptions{Wait: true}},
+		{name: "wait with timeout", opts: WaitOptions{Wait: true, WaitTimeout: time.Minute}},
+		{name: "negative timeout", opts: WaitOptions{Wait: true, WaitTimeout: -time.Minute}, wantError: "--wait-timeout must not be negative"},
+		{name: "timeout without wait", opts: WaitOptions{WaitTimeout: time.Minute}, wantError: "--wait-timeout can only be used with --wait"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.wantError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.wantError)
+			}
+		})
+	}
+}
+
+func TestPorter_waitForReady_NoReadinessAction(t *testing.T) {
+	p := NewTestPorter(t)
+	defer p.Close()
+
+	opts := NewInstallOptions()
+	opts.Namespace = "dev"
+	opts.Name = "mysql"
+	opts.bundleRef = &cnab.BundleReference{Definition: cnab.NewBundle(bundle.Bundle{Name: "mysql"})}
+
+	err := p.waitForReady(context.Background(), opts.BundleExecutionOptions, time.Minute)
+	require.NoError(t, err, "waiting should be skipped when the bundle does not define a status action")
+}
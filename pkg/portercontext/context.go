@@ -115,8 +115,11 @@ func New() *Context {
 }
 
 // StartRootSpan creates the root tracing span for the porter application.
-// This should only be done once.
+// This should only be done once. When porter was started by another process
+// that propagated its trace with the TRACEPARENT environment variable, such as
+// porter running inside of the invocation image, the span continues that trace.
 func (c *Context) StartRootSpan(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, tracing.RootTraceLogger) {
+	ctx = tracing.ExtractEnvironment(ctx, c.Getenv)
 	childCtx, span := c.tracer.Start(ctx, op)
 	attrs = append(attrs, attribute.String("correlation-id", c.correlationId))
 	span.SetAttributes(attrs...)
@@ -143,8 +146,13 @@ type LogConfiguration struct {
 	LogDirectory string
 
 	// LogLevel is the threshold for writing messages to the log file.
-	LogLevel                zapcore.Level
-	StructuredLogs          bool
+	LogLevel       zapcore.Level
+	StructuredLogs bool
+
+	// JsonLogs prints messages to the console as json, one message per line,
+	// so that they can be parsed by automation.
+	JsonLogs bool
+
 	TelemetryEnabled        bool
 	TelemetryEndpoint       string
 	TelemetryProtocol       string
@@ -213,6 +221,11 @@ func (c *Context) configureLoggingWith(ctx context.Context, baseLogger zapcore.C
 func (c *Context) makeConsoleLogger() zapcore.Core {
 	encoding := c.makeLogEncoding()
 
+	// json logs always include the timestamp and log level, and are never colored
+	if c.logCfg.JsonLogs {
+		return zapcore.NewCore(zapcore.NewJSONEncoder(encoding), zapcore.AddSync(c.Err), c.logCfg.Verbosity)
+	}
+
 	stderr := c.Err
 	if f, ok := stderr.(*os.File); ok {
 		if isatty.IsTerminal(f.Fd()) {
@@ -305,6 +318,12 @@ func (c *Context) CommandContext(ctx context.Context, name string, arg ...string
 	cmd := exec.CommandContext(ctx, name, arg...)
 	cmd.Dir = c.Getwd()
 	cmd.Env = c.Environ()
+
+	// Propagate the current trace, so that the command can continue it.
+	// When a key is duplicated, the last value is used.
+	for key, value := range tracing.InjectEnvironment(ctx) {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
 	return cmd
 }
 
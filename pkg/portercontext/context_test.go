@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -55,3 +56,23 @@ func TestContext_LogToFile(t *testing.T) {
 	// Compare the human readable logs sent to stderr
 	c.CompareGoldenFile("testdata/expected-output.txt", c.GetError())
 }
+
+func TestContext_JsonLogs(t *testing.T) {
+	c := NewTestContext(t)
+	c.timestampLogs = false // turn off timestamps so we can compare more easily
+	c.ConfigureLogging(context.Background(), LogConfiguration{
+		Verbosity: zapcore.InfoLevel,
+		JsonLogs:  true,
+	})
+	_, log := c.StartRootSpan(context.Background(), t.Name())
+	log.Debug("a detail that is not printed")
+	log.Info("a thing happened", attribute.String("installation", "mybuns"))
+	log.Error(errors.New("a bad thing happened"))
+	log.EndSpan()
+	c.Close()
+
+	want := `{"level":"info","ts":"","msg":"a thing happened","installation":"mybuns"}
+{"level":"error","ts":"","msg":"a bad thing happened"}
+`
+	assert.Equal(t, want, c.GetError())
+}
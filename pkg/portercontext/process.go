@@ -0,0 +1,32 @@
+package portercontext
+
+import (
+	"context"
+	"os/exec"
+)
+
+// StartProcessGroup starts the command in a new process group, so that when the
+// context is done, the command and any processes that it started are killed.
+// Call the returned function to wait for the command to complete, instead of
+// cmd.Wait.
+func StartProcessGroup(ctx context.Context, cmd *exec.Cmd) (func() error, error) {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-done:
+		}
+	}()
+
+	wait := func() error {
+		defer close(done)
+		return cmd.Wait()
+	}
+	return wait, nil
+}
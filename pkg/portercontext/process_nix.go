@@ -0,0 +1,23 @@
+//go:build !windows
+
+package portercontext
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	// A negative pid signals every process in the group
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package portercontext
+
+import (
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	// Windows doesn't have process groups that can be signaled, only the command is killed
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Kill()
+}
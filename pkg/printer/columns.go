@@ -0,0 +1,149 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ColumnOptions customize the columns, and the order of the rows, of the
+// tabular output formats: plaintext, wide and csv.
+type ColumnOptions struct {
+	// Columns to print, in the order specified. All columns are printed when empty.
+	Columns []string
+
+	// SortBy is the name of the column used to sort the rows.
+	SortBy string
+}
+
+// HasColumns determines if specific columns were selected.
+func (o ColumnOptions) HasColumns() bool {
+	return len(o.Columns) > 0
+}
+
+// SortRows sorts a dataset in place by the SortBy column. The rows are
+// compared using the values returned by getRow, which should be formatted so
+// that they sort lexically, for example timestamps in RFC3339 instead of
+// relative times. The rows are left as-is when SortBy is not set.
+func (o ColumnOptions) SortRows(v interface{}, getRow func(row interface{}) []string, headers ...string) error {
+	if o.SortBy == "" {
+		return nil
+	}
+
+	if reflect.TypeOf(v).Kind() != reflect.Slice {
+		return fmt.Errorf("invalid data passed to SortRows, must be a slice but got %T", v)
+	}
+
+	i, err := findColumn(o.SortBy, headers)
+	if err != nil {
+		return fmt.Errorf("invalid --sort-by value: %w", err)
+	}
+
+	rows := reflect.ValueOf(v)
+	keys := make([]string, rows.Len())
+	for j := range keys {
+		keys[j] = getCell(getRow(rows.Index(j).Interface()), i)
+	}
+
+	sort.Stable(sortableRows{keys: keys, swap: reflect.Swapper(v)})
+	return nil
+}
+
+// SelectColumns returns the selected headers, and a function that returns
+// the selected columns of a row. The headers and getRow are returned unchanged
+// when Columns is not set.
+func (o ColumnOptions) SelectColumns(getRow func(row interface{}) []string, headers ...string) (func(row interface{}) []string, []string, error) {
+	if !o.HasColumns() {
+		return getRow, headers, nil
+	}
+
+	indices := make([]int, len(o.Columns))
+	selectedHeaders := make([]string, len(o.Columns))
+	for i, column := range o.Columns {
+		index, err := findColumn(column, headers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --columns value: %w", err)
+		}
+		indices[i] = index
+		selectedHeaders[i] = headers[index]
+	}
+
+	selectRow := func(row interface{}) []string {
+		values := getRow(row)
+		selected := make([]string, len(indices))
+		for i, index := range indices {
+			selected[i] = getCell(values, index)
+		}
+		return selected
+	}
+	return selectRow, selectedHeaders, nil
+}
+
+// PrintTable outputs the selected columns of a dataset in tabular format.
+func (o ColumnOptions) PrintTable(out io.Writer, v interface{}, getRow func(row interface{}) []string, headers ...string) error {
+	getRow, headers, err := o.SelectColumns(getRow, headers...)
+	if err != nil {
+		return err
+	}
+	return PrintTable(out, v, getRow, headers...)
+}
+
+// PrintCsv outputs the selected columns of a dataset as comma separated values.
+func (o ColumnOptions) PrintCsv(out io.Writer, v interface{}, getRow func(row interface{}) []string, headers ...string) error {
+	getRow, headers, err := o.SelectColumns(getRow, headers...)
+	if err != nil {
+		return err
+	}
+	return PrintCsv(out, v, getRow, headers...)
+}
+
+// findColumn returns the index of the header matching the column name. The
+// comparison ignores case, spaces, dashes and underscores, so that the
+// "Run ID" header can be selected with run-id.
+func findColumn(name string, headers []string) (int, error) {
+	for i, header := range headers {
+		if normalizeColumnName(header) == normalizeColumnName(name) {
+			return i, nil
+		}
+	}
+
+	allowed := make([]string, len(headers))
+	for i, header := range headers {
+		allowed[i] = strings.ToLower(strings.ReplaceAll(header, " ", "-"))
+	}
+	return -1, fmt.Errorf("unknown column %q, allowed values are: %s", name, strings.Join(allowed, ", "))
+}
+
+func normalizeColumnName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name)
+}
+
+func getCell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// sortableRows sorts a dataset by the keys of its rows, keeping the keys in
+// sync with the dataset as rows are swapped.
+type sortableRows struct {
+	keys []string
+	swap func(i, j int)
+}
+
+func (s sortableRows) Len() int {
+	return len(s.keys)
+}
+
+func (s sortableRows) Less(i, j int) bool {
+	return s.keys[i] < s.keys[j]
+}
+
+func (s sortableRows) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.swap(i, j)
+}
@@ -0,0 +1,72 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnOptions_SortRows(t *testing.T) {
+	v := []testType{
+		{A: "foo", B: "2"},
+		{A: "bar", B: "3"},
+		{A: "baz", B: "1"},
+	}
+
+	opts := ColumnOptions{SortBy: "b"}
+	err := opts.SortRows(v, printTestType, "A", "B")
+
+	require.NoError(t, err)
+	assert.Equal(t, []testType{{A: "baz", B: "1"}, {A: "foo", B: "2"}, {A: "bar", B: "3"}}, v)
+}
+
+func TestColumnOptions_SortRows_UnknownColumn(t *testing.T) {
+	v := []testType{{A: "foo", B: "bar"}}
+
+	opts := ColumnOptions{SortBy: "c"}
+	err := opts.SortRows(v, printTestType, "A", "B")
+
+	assert.EqualError(t, err, `invalid --sort-by value: unknown column "c", allowed values are: a, b`)
+}
+
+func TestColumnOptions_PrintCsv(t *testing.T) {
+	v := []testType{
+		{A: "foo", B: "bar"},
+		{A: "baz", B: "qux"},
+	}
+
+	b := &bytes.Buffer{}
+
+	opts := ColumnOptions{Columns: []string{"b", "A"}}
+	err := opts.PrintCsv(b, v, printTestType, "A", "B")
+
+	require.NoError(t, err)
+	assert.Equal(t, "B,A\nbar,foo\nqux,baz\n", b.String())
+}
+
+func TestColumnOptions_SelectColumns(t *testing.T) {
+	getRow := func(interface{}) []string { return []string{"01FZVC5AVP8Z7A78CSCP1EJ604", "install"} }
+
+	t.Run("all columns", func(t *testing.T) {
+		opts := ColumnOptions{}
+		_, headers, err := opts.SelectColumns(getRow, "Run ID", "Action")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Run ID", "Action"}, headers)
+	})
+
+	t.Run("headers with spaces", func(t *testing.T) {
+		opts := ColumnOptions{Columns: []string{"run-id"}}
+		selectRow, headers, err := opts.SelectColumns(getRow, "Run ID", "Action")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Run ID"}, headers)
+		assert.Equal(t, []string{"01FZVC5AVP8Z7A78CSCP1EJ604"}, selectRow(nil))
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		opts := ColumnOptions{Columns: []string{"status"}}
+		_, _, err := opts.SelectColumns(getRow, "Run ID", "Action")
+		assert.EqualError(t, err, `invalid --columns value: unknown column "status", allowed values are: run-id, action`)
+	})
+}
@@ -0,0 +1,33 @@
+package printer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// PrintCsv outputs a dataset as comma separated values, with the headers as
+// the first record when they are specified.
+func PrintCsv(out io.Writer, v interface{}, getRow func(row interface{}) []string, headers ...string) error {
+	if reflect.TypeOf(v).Kind() != reflect.Slice {
+		return fmt.Errorf("invalid data passed to PrintCsv, must be a slice but got %T", v)
+	}
+
+	rows := reflect.ValueOf(v)
+
+	w := csv.NewWriter(out)
+	if len(headers) > 0 {
+		if err := w.Write(headers); err != nil {
+			return fmt.Errorf("could not write csv headers: %w", err)
+		}
+	}
+	for i := 0; i < rows.Len(); i++ {
+		if err := w.Write(getRow(rows.Index(i).Interface())); err != nil {
+			return fmt.Errorf("could not write csv record: %w", err)
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
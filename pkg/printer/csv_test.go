@@ -0,0 +1,43 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintCsv(t *testing.T) {
+	v := []testType{
+		{A: "foo", B: "a value, with a comma"},
+		{A: "baz", B: `a "quoted" value`},
+		{A: 123, B: true},
+	}
+
+	b := &bytes.Buffer{}
+
+	err := PrintCsv(b, v, printTestType, "A", "B")
+
+	require.NoError(t, err)
+	want := "A,B\nfoo,\"a value, with a comma\"\nbaz,\"a \"\"quoted\"\" value\"\n123,true\n"
+	assert.Equal(t, want, b.String())
+}
+
+func TestPrintCsv_WithoutHeaders(t *testing.T) {
+	v := []testType{
+		{A: "foo", B: "bar"},
+	}
+
+	b := &bytes.Buffer{}
+
+	err := PrintCsv(b, v, printTestType)
+
+	require.NoError(t, err)
+	assert.Equal(t, "foo,bar\n", b.String())
+}
+
+func TestPrintCsv_NotSlice(t *testing.T) {
+	err := PrintCsv(&bytes.Buffer{}, testType{}, printTestType)
+	assert.EqualError(t, err, "invalid data passed to PrintCsv, must be a slice but got printer.testType")
+}
@@ -11,6 +11,16 @@ const (
 	FormatJson      Format = "json"
 	FormatYaml      Format = "yaml"
 	FormatPlaintext Format = "plaintext"
+	FormatDot       Format = "dot"
+	FormatMermaid   Format = "mermaid"
+	FormatSarif     Format = "sarif"
+
+	// FormatCsv prints comma separated values, for importing into other tools.
+	FormatCsv Format = "csv"
+
+	// FormatWide prints a table with additional columns that are omitted from
+	// the plaintext table.
+	FormatWide Format = "wide"
 )
 
 type Formats []Format
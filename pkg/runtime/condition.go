@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/manifest"
+)
+
+// errStepSkipped is recorded for a step that was not executed because its when condition is false.
+var errStepSkipped = errors.New("the when condition of the step is false")
+
+// shouldExecuteStep evaluates the when condition of a step against the parameters
+// and the outputs of the previous steps. Steps without a condition are always executed.
+func (r *PorterRuntime) shouldExecuteStep(ctx context.Context, stepPath string, step *manifest.Step) (bool, error) {
+	if _, hasWhen, err := step.GetWhen(); err != nil || !hasWhen {
+		return true, err
+	}
+
+	// Render the templates in the condition
+	if err := r.RuntimeManifest.resolveStepAt(ctx, stepPath, step); err != nil {
+		return false, fmt.Errorf("unable to resolve step: %w", err)
+	}
+
+	when, _, err := step.GetWhen()
+	if err != nil {
+		return false, err
+	}
+
+	execute, err := manifest.EvaluateCondition(when)
+	if err != nil {
+		return false, fmt.Errorf("invalid when condition for step %s: %w", stepPath, err)
+	}
+	return execute, nil
+}
+
+// printSkippedStep prints that a step was not executed, and why.
+func (r *PorterRuntime) printSkippedStep(description string, reason string) {
+	if description == "" {
+		description = "step"
+	}
+	if r.config.LogFormat == config.LogFormatJSON {
+		restoreLogs := r.useJSONLogs(description, "")
+		defer restoreLogs()
+	}
+	fmt.Fprintf(r.config.Out, "Skipping %s because %s\n", description, reason)
+}
@@ -0,0 +1,72 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorterRuntime_shouldExecuteStep(t *testing.T) {
+	ctx := context.Background()
+	r := NewTestPorterRuntime(t)
+	r.TestContext.Setenv("CREATE_DNS", "false")
+	r.TestContext.Setenv("ENV", "prod")
+
+	mContent := `schemaVersion: 1.0.0
+parameters:
+- name: create_dns
+  type: boolean
+- name: env
+
+install:
+- exec:
+    description: "No condition"
+    command: echo
+- exec:
+    description: "Create DNS records"
+    command: echo
+    when: ${ bundle.parameters.create_dns }
+- exec:
+    description: "Production only"
+    command: echo
+    when: ${ bundle.parameters.env } == prod
+- exec:
+    description: "Uses an output"
+    command: echo
+    when: ${ bundle.outputs.created }
+- exec:
+    description: "Invalid condition"
+    command: echo
+    when: maybe
+`
+	r.RuntimeManifest = runtimeManifestFromStepYaml(t, r.TestContext, mContent)
+	require.NoError(t, r.RuntimeManifest.ApplyStepOutputs(map[string]string{"created": "true"}))
+
+	testcases := []struct {
+		name    string
+		index   int
+		want    bool
+		wantErr string
+	}{
+		{name: "no condition", index: 0, want: true},
+		{name: "false parameter", index: 1, want: false},
+		{name: "comparison", index: 2, want: true},
+		{name: "previous step output", index: 3, want: true},
+		{name: "invalid condition", index: 4, wantErr: `invalid when condition for step install[4]: invalid condition "maybe"`},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			step := r.RuntimeManifest.Install[tc.index]
+			execute, err := r.shouldExecuteStep(ctx, fmt.Sprintf("install[%d]", tc.index), step)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, execute)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/portercontext"
@@ -14,6 +15,21 @@ type RuntimeConfig struct {
 
 	// DebugMode indicates if the bundle is running in debug mode.
 	DebugMode bool
+
+	// LogFormat is the format of the output generated by the bundle: text or json.
+	LogFormat string
+
+	// Retries is the number of times a failed step is retried, overriding the
+	// retry policy of the bundle when set.
+	Retries int
+
+	// RetryBackoff is how long to wait before retrying a failed step, overriding
+	// the retry policy of the bundle when set.
+	RetryBackoff time.Duration
+
+	// Timeout is how long the action may run, overriding the timeout policy of
+	// the bundle when set.
+	Timeout time.Duration
 }
 
 // NewConfig returns an initialized RuntimeConfig
@@ -24,9 +40,20 @@ func NewConfig() RuntimeConfig {
 // NewConfigFor returns an initialized RuntimeConfig using the specified context.
 func NewConfigFor(porterCtx *portercontext.Context) RuntimeConfig {
 	debug, _ := strconv.ParseBool(porterCtx.Getenv("PORTER_DEBUG"))
+	logFormat := porterCtx.Getenv(config.EnvPorterLogFormat)
+	if logFormat == "" {
+		logFormat = config.LogFormatText
+	}
+	retries, _ := strconv.Atoi(porterCtx.Getenv(config.EnvPorterRetries))
+	retryBackoff, _ := time.ParseDuration(porterCtx.Getenv(config.EnvPorterRetryBackoff))
+	timeout, _ := time.ParseDuration(porterCtx.Getenv(config.EnvPorterTimeout))
 	return RuntimeConfig{
-		Context:   porterCtx,
-		DebugMode: debug,
+		Context:      porterCtx,
+		DebugMode:    debug,
+		LogFormat:    logFormat,
+		Retries:      retries,
+		RetryBackoff: retryBackoff,
+		Timeout:      timeout,
 	}
 }
 
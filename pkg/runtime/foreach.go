@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+// stepIteration is a single iteration of a step that uses foreach.
+type stepIteration struct {
+	// Index of the item in the list, starting from 0.
+	Index int
+
+	// Item from the list that the step iterates over.
+	Item interface{}
+
+	// Sensitive is true when the list was calculated from a sensitive value.
+	Sensitive bool
+}
+
+// templateData returns the data available to the templates of the step for the iteration.
+func (i *stepIteration) templateData() map[string]interface{} {
+	return map[string]interface{}{
+		"index": i.Index,
+		"item":  i.Item,
+	}
+}
+
+// getStepIterations returns the iterations of a step that uses foreach. A step that
+// does not use foreach has a single nil iteration, and is executed once.
+func (m *RuntimeManifest) getStepIterations(ctx context.Context, stepPath string, step *manifest.Step) ([]*stepIteration, error) {
+	log := tracing.LoggerFromContext(ctx)
+
+	value, hasForeach, err := step.GetForeach()
+	if err != nil {
+		return nil, err
+	}
+	if !hasForeach {
+		return []*stepIteration{nil}, nil
+	}
+
+	// Only render the foreach field, the rest of the step may use the current iteration
+	sensitive := false
+	if tmpl, ok := value.(string); ok {
+		sourceData, err := m.buildSourceData()
+		if err != nil {
+			return nil, log.Error(fmt.Errorf("unable to build step template data: %w", err))
+		}
+
+		rendered, sensitiveValues, err := manifest.RenderTemplate(m.GetTemplatePrefix()+tmpl, sourceData, m.isSensitiveValue)
+		if err != nil {
+			return nil, log.Error(fmt.Errorf("unable to render the foreach template of step %s: %w", stepPath, err))
+		}
+		sensitive = len(sensitiveValues) > 0 || m.isSensitiveValue(rendered)
+		value = rendered
+	}
+
+	items, err := manifest.ParseForeachItems(value)
+	if err != nil {
+		return nil, log.Error(fmt.Errorf("invalid foreach for step %s: %w", stepPath, err))
+	}
+
+	iterations := make([]*stepIteration, len(items))
+	for i, item := range items {
+		iterations[i] = &stepIteration{Index: i, Item: item, Sensitive: sensitive}
+	}
+	return iterations, nil
+}
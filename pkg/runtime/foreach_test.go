@@ -0,0 +1,113 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeManifest_getStepIterations(t *testing.T) {
+	ctx := context.Background()
+	r := NewTestPorterRuntime(t)
+	r.TestContext.Setenv("REGIONS", `["eastus","westus"]`)
+	r.TestContext.Setenv("TOKENS", `["abc"]`)
+
+	mContent := `schemaVersion: 1.0.0
+parameters:
+- name: regions
+  type: array
+- name: tokens
+  type: array
+  sensitive: true
+
+install:
+- exec:
+    description: "No foreach"
+    command: echo
+- exec:
+    description: "Create ${ foreach.item } resources"
+    command: echo
+    arguments:
+    - ${ foreach.index }
+    foreach: ${ bundle.parameters.regions }
+- exec:
+    description: "Literal list"
+    command: echo
+    foreach:
+    - name: a
+    - name: b
+- exec:
+    description: "Empty output"
+    command: echo
+    foreach: ${ bundle.outputs.missing }
+- exec:
+    description: "Sensitive list"
+    command: echo
+    arguments:
+    - ${ foreach.item }
+    foreach: ${ bundle.parameters.tokens }
+- exec:
+    description: "Not a list"
+    command: echo
+    foreach: eastus
+`
+	rm := runtimeManifestFromStepYaml(t, r.TestContext, mContent)
+	require.NoError(t, rm.ApplyStepOutputs(map[string]string{"missing": ""}))
+
+	t.Run("no foreach", func(t *testing.T) {
+		iterations, err := rm.getStepIterations(ctx, "install[0]", rm.Install[0])
+		require.NoError(t, err)
+		require.Len(t, iterations, 1)
+		assert.Nil(t, iterations[0], "a step without foreach should be executed once")
+	})
+
+	t.Run("array parameter", func(t *testing.T) {
+		step := rm.Install[1]
+		iterations, err := rm.getStepIterations(ctx, "install[1]", step)
+		require.NoError(t, err)
+		require.Len(t, iterations, 2)
+		assert.Equal(t, "westus", iterations[1].Item)
+
+		rm.iteration = iterations[1]
+		defer func() { rm.iteration = nil }()
+		require.NoError(t, rm.ResolveStep(ctx, 1, step))
+		description, err := step.GetDescription()
+		require.NoError(t, err)
+		assert.Equal(t, "Create westus resources", description)
+		args := step.Data["exec"].(map[string]interface{})["arguments"].([]interface{})
+		assert.Equal(t, []interface{}{1}, args)
+	})
+
+	t.Run("literal list", func(t *testing.T) {
+		iterations, err := rm.getStepIterations(ctx, "install[2]", rm.Install[2])
+		require.NoError(t, err)
+		require.Len(t, iterations, 2)
+		assert.Equal(t, map[string]interface{}{"name": "b"}, iterations[1].Item)
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		iterations, err := rm.getStepIterations(ctx, "install[3]", rm.Install[3])
+		require.NoError(t, err)
+		assert.Empty(t, iterations)
+	})
+
+	t.Run("sensitive list", func(t *testing.T) {
+		step := rm.Install[4]
+		iterations, err := rm.getStepIterations(ctx, "install[4]", step)
+		require.NoError(t, err)
+		require.Len(t, iterations, 1)
+		assert.True(t, iterations[0].Sensitive)
+
+		rm.iteration = iterations[0]
+		defer func() { rm.iteration = nil }()
+		require.NoError(t, rm.ResolveStep(ctx, 4, step))
+		assert.Contains(t, rm.GetSensitiveValues(), "abc", "items from a sensitive list should be masked")
+	})
+
+	t.Run("not a list", func(t *testing.T) {
+		_, err := rm.getStepIterations(ctx, "install[5]", rm.Install[5])
+		require.ErrorContains(t, err, `invalid foreach for step install[5]: invalid foreach value "eastus"`)
+	})
+}
@@ -0,0 +1,114 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogRecord is a line of output from a bundle when the log format is json.
+type LogRecord struct {
+	// Timestamp when the line was written.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Stream that the line was written to: stdout or stderr.
+	Stream string `json:"stream"`
+
+	// Step is the description of the step that generated the output.
+	Step string `json:"step,omitempty"`
+
+	// Mixin that executed the step.
+	Mixin string `json:"mixin,omitempty"`
+
+	// Message is the line of output, without the trailing newline.
+	Message string `json:"message"`
+}
+
+// jsonLogWriter writes each line of output as a LogRecord on a single line,
+// so that the logs can be indexed by log aggregation systems.
+type jsonLogWriter struct {
+	out    io.Writer
+	stream string
+	step   string
+	mixin  string
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newJSONLogWriter(out io.Writer, stream string, step string, mixin string) *jsonLogWriter {
+	return &jsonLogWriter{
+		out:    out,
+		stream: stream,
+		step:   step,
+		mixin:  mixin,
+	}
+}
+
+// Write buffers partial lines, and writes a record for each complete line.
+func (w *jsonLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.buf.Next(i + 1))
+		if err := w.writeRecord(line[:i]); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes a record for any remaining output that did not end with a newline.
+func (w *jsonLogWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	line := w.buf.String()
+	w.buf.Reset()
+	return w.writeRecord(line)
+}
+
+func (w *jsonLogWriter) writeRecord(line string) error {
+	record := LogRecord{
+		Timestamp: time.Now().UTC(),
+		Stream:    w.stream,
+		Step:      w.step,
+		Mixin:     w.mixin,
+		Message:   strings.TrimSuffix(line, "\r"),
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = w.out.Write(append(b, '\n'))
+	return err
+}
+
+// useJSONLogs writes the output of the runtime and mixins as JSON lines, until
+// the returned function is called.
+func (r *PorterRuntime) useJSONLogs(step string, mixin string) func() {
+	out, errOut := r.config.Out, r.config.Err
+	jsonOut := newJSONLogWriter(out, "stdout", step, mixin)
+	jsonErr := newJSONLogWriter(errOut, "stderr", step, mixin)
+	r.config.Out = jsonOut
+	r.config.Err = jsonErr
+
+	return func() {
+		jsonOut.Flush()
+		jsonErr.Flush()
+		r.config.Out = out
+		r.config.Err = errOut
+	}
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLogWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := newJSONLogWriter(&out, "stderr", "Install MySQL", "helm3")
+
+	fmt.Fprint(w, "first line\nsecond ")
+	fmt.Fprint(w, "line\r\nno newline")
+	require.NoError(t, w.Flush())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3, "expected a record for each line of output")
+
+	var messages []string
+	for _, line := range lines {
+		var record LogRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record), "each line should be a json record")
+		assert.Equal(t, "stderr", record.Stream)
+		assert.Equal(t, "Install MySQL", record.Step)
+		assert.Equal(t, "helm3", record.Mixin)
+		assert.False(t, record.Timestamp.IsZero(), "the record should have a timestamp")
+		messages = append(messages, record.Message)
+	}
+	assert.Equal(t, []string{"first line", "second line", "no newline"}, messages)
+}
+
+func TestPorterRuntime_useJSONLogs(t *testing.T) {
+	r := NewTestPorterRuntime(t)
+
+	restoreLogs := r.useJSONLogs("Install MySQL", "helm3")
+	fmt.Fprintln(r.config.Out, "installing")
+	restoreLogs()
+	fmt.Fprintln(r.config.Out, "done")
+
+	output := r.TestContext.GetOutput()
+	assert.Contains(t, output, `"stream":"stdout","step":"Install MySQL","mixin":"helm3","message":"installing"`)
+	assert.True(t, strings.HasSuffix(output, "\ndone\n"), "the output should not be json after the logs are restored")
+}
@@ -0,0 +1,142 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/mixin/protocol"
+	"get.porter.sh/porter/pkg/pkgmgmt"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"get.porter.sh/porter/pkg/yaml"
+)
+
+// mixinConnection executes steps with a mixin.
+type mixinConnection interface {
+	protocol.MixinProtocol
+
+	// Close the connection and stop the mixin.
+	Close(ctx context.Context)
+}
+
+// getMixinConnection returns a connection to a mixin, connecting to it the
+// first time that it is used during the action. Mixins that support version 2
+// of the mixin protocol are started once and reused for every step, other
+// mixins are executed once per step with the legacy protocol.
+func (r *PorterRuntime) getMixinConnection(ctx context.Context, name string) mixinConnection {
+	if conn, ok := r.mixinConnections[name]; ok {
+		return conn
+	}
+
+	ctx, span := tracing.StartSpan(ctx, attribute.String("mixin", name))
+	defer span.EndSpan()
+
+	var conn mixinConnection = &legacyMixin{r: r, name: name}
+	runtimePath, err := r.getMixinRuntimePath(name)
+	if err == nil {
+		// The mixin must outlive the step that started it, so that it is
+		// available to the following steps, including cleanup steps executed
+		// after the step timed out.
+		var v2 *protocol.Connection
+		v2, err = protocol.Connect(context.WithoutCancel(ctx), r.config.Context, name, runtimePath)
+		if err == nil {
+			conn = v2
+		}
+	}
+	if err != nil {
+		span.Debugf("Using the legacy mixin protocol for the %s mixin: %s", name, err)
+	}
+
+	if r.mixinConnections == nil {
+		r.mixinConnections = make(map[string]mixinConnection)
+	}
+	r.mixinConnections[name] = conn
+	return conn
+}
+
+// getMixinRuntimePath returns the path to the runtime binary of a mixin.
+func (r *PorterRuntime) getMixinRuntimePath(name string) (string, error) {
+	pkgDir, err := r.mixins.GetPackageDir(name)
+	if err != nil {
+		return "", err
+	}
+
+	runtimePath := filepath.Join(pkgDir, "runtimes", name+"-runtime"+pkgmgmt.FileExt)
+	if exists, _ := r.config.FileSystem.Exists(runtimePath); !exists {
+		return "", fmt.Errorf("the runtime binary for the %s mixin was not found at %s", name, runtimePath)
+	}
+	return runtimePath, nil
+}
+
+// closeMixinConnections stops the mixins that were started during the action.
+func (r *PorterRuntime) closeMixinConnections(ctx context.Context) {
+	for name, conn := range r.mixinConnections {
+		conn.Close(ctx)
+		delete(r.mixinConnections, name)
+	}
+}
+
+var _ mixinConnection = &legacyMixin{}
+
+// legacyMixin executes steps with mixins that do not support version 2 of the
+// mixin protocol. The mixin is executed once per step, the step is passed on
+// stdin, and outputs are read from files written by the mixin.
+type legacyMixin struct {
+	r    *PorterRuntime
+	name string
+}
+
+func (m *legacyMixin) Execute(ctx context.Context, req protocol.ExecuteRequest, _ protocol.Reporter) (protocol.StepResult, error) {
+	input := &ActionInput{
+		action: m.r.RuntimeManifest.Action,
+		Steps:  []*manifest.Step{{Data: map[string]interface{}{m.name: req.Step}}},
+	}
+	inputBytes, _ := yaml.Marshal(input)
+	cmd := pkgmgmt.CommandOptions{
+		Command: req.Action,
+		Input:   string(inputBytes),
+		Runtime: true,
+	}
+	if err := m.r.mixins.Run(ctx, m.r.config.Context, m.name, cmd); err != nil {
+		return protocol.StepResult{}, err
+	}
+
+	outputs, err := m.r.readMixinOutputs()
+	if err != nil {
+		return protocol.StepResult{}, fmt.Errorf("could not read step outputs: %w", err)
+	}
+
+	result := protocol.StepResult{Outputs: make([]protocol.Output, 0, len(outputs))}
+	for name, value := range outputs {
+		result.Outputs = append(result.Outputs, protocol.Output{Name: name, Value: value})
+	}
+	return result, nil
+}
+
+func (m *legacyMixin) Close(context.Context) {}
+
+var _ protocol.Reporter = &stepReporter{}
+
+// stepReporter prints the progress and log messages of a step.
+type stepReporter struct {
+	r *PorterRuntime
+}
+
+func (s *stepReporter) Progress(message string, percent int) {
+	if percent >= 0 {
+		fmt.Fprintf(s.r.config.Out, "[%3d%%] %s\n", percent, message)
+	} else {
+		fmt.Fprintln(s.r.config.Out, message)
+	}
+}
+
+func (s *stepReporter) Log(message string) {
+	fmt.Fprintln(s.r.config.Out, strings.TrimSuffix(message, "\n"))
+}
+
+func (s *stepReporter) LogError(message string) {
+	fmt.Fprintln(s.r.config.Err, strings.TrimSuffix(message, "\n"))
+}
@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/mixin/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMixinConnection is a mixin that supports version 2 of the mixin protocol.
+type testMixinConnection struct {
+	req    protocol.ExecuteRequest
+	result protocol.StepResult
+	err    error
+	closed bool
+}
+
+func (c *testMixinConnection) Execute(ctx context.Context, req protocol.ExecuteRequest, reporter protocol.Reporter) (protocol.StepResult, error) {
+	c.req = req
+	reporter.Progress("Installing mysql", 50)
+	reporter.Log("installed mysql with password topsecret")
+	return c.result, c.err
+}
+
+func (c *testMixinConnection) Close(ctx context.Context) {
+	c.closed = true
+}
+
+func TestPorterRuntime_executeStep_Protocol(t *testing.T) {
+	ctx := context.Background()
+	mContent := `schemaVersion: 1.0.0
+install:
+- exec:
+    description: Install mysql
+    when: "true"
+`
+
+	t.Run("typed outputs", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = runtimeManifestFromStepYaml(t, r.TestContext, mContent)
+		step := r.RuntimeManifest.Install[0]
+		conn := &testMixinConnection{result: protocol.StepResult{Outputs: []protocol.Output{
+			{Name: "host", Value: "mysql.local"},
+			{Name: "port", Value: float64(3306)},
+			{Name: "password", Value: "topsecret", Sensitive: true},
+		}}}
+		r.mixinConnections = map[string]mixinConnection{"exec": conn}
+
+		err := r.executeStep(ctx, "install[0]", step)
+		require.NoError(t, err)
+
+		assert.Equal(t, "install", conn.req.Action)
+		assert.Equal(t, map[string]interface{}{"description": "Install mysql"}, conn.req.Step,
+			"the fields evaluated by Porter should not be sent to the mixin")
+		assert.Equal(t, map[string]string{"host": "mysql.local", "port": "3306", "password": "topsecret"}, r.RuntimeManifest.outputs)
+
+		r.closeMixinConnections(ctx)
+		assert.True(t, conn.closed, "the mixin should be stopped at the end of the action")
+	})
+
+	t.Run("sensitive outputs are masked", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = runtimeManifestFromStepYaml(t, r.TestContext, mContent)
+		step := r.RuntimeManifest.Install[0]
+		r.mixinConnections = map[string]mixinConnection{"exec": &testMixinConnection{
+			result: protocol.StepResult{Outputs: []protocol.Output{{Name: "password", Value: "topsecret", Sensitive: true}}},
+		}}
+
+		require.NoError(t, r.executeStep(ctx, "install[0]", step))
+		gotOutput := r.TestContext.GetOutput()
+		assert.Contains(t, gotOutput, "[ 50%] Installing mysql")
+
+		// The value of a sensitive output is masked in the logs of the following steps
+		require.NoError(t, r.executeStep(ctx, "install[0]", step))
+		gotOutput = r.TestContext.GetOutput()[len(gotOutput):]
+		assert.Contains(t, gotOutput, "installed mysql with password *******")
+		assert.NotContains(t, gotOutput, "topsecret")
+	})
+
+	t.Run("rich error", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = runtimeManifestFromStepYaml(t, r.TestContext, mContent)
+		step := r.RuntimeManifest.Install[0]
+		r.mixinConnections = map[string]mixinConnection{"exec": &testMixinConnection{
+			err: &protocol.Error{Code: "NotFound", Message: "the chart was not found"},
+		}}
+
+		err := r.executeStep(ctx, "install[0]", step)
+		require.ErrorContains(t, err, "mixin execution failed: NotFound: the chart was not found")
+
+		var mixinErr *protocol.Error
+		require.ErrorAs(t, err, &mixinErr)
+		assert.Equal(t, "NotFound", mixinErr.Code)
+	})
+}
+
+func TestPorterRuntime_getMixinConnection_Legacy(t *testing.T) {
+	r := NewTestPorterRuntime(t)
+
+	conn := r.getMixinConnection(context.Background(), "exec")
+	require.IsType(t, &legacyMixin{}, conn, "mixins without a runtime binary that supports the protocol should use the legacy protocol")
+	assert.Same(t, conn, r.getMixinConnection(context.Background(), "exec"), "the connection should be reused for the rest of the action")
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// getRetryPolicy returns how many times a failed step of the current action is
+// retried, and how long to wait before the first retry. The retry policy
+// defined in the bundle is overridden by the --retries and --retry-backoff flags.
+func (r *PorterRuntime) getRetryPolicy() (int, time.Duration, error) {
+	policy := r.RuntimeManifest.Retry[r.RuntimeManifest.Action]
+	backoff, err := policy.GetBackoff()
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid retry policy for action %s: %w", r.RuntimeManifest.Action, err)
+	}
+
+	retries := policy.Retries
+	if r.config.Retries > 0 {
+		retries = r.config.Retries
+	}
+	if r.config.RetryBackoff > 0 {
+		backoff = r.config.RetryBackoff
+	}
+	return retries, backoff, nil
+}
+
+// retryStep executes a step, and retries it when it fails, doubling how long
+// to wait between each retry.
+func (r *PorterRuntime) retryStep(ctx context.Context, description string, retries int, backoff time.Duration, execute func() error) error {
+	if description == "" {
+		description = "step"
+	}
+
+	wait := backoff
+	for retry := 1; ; retry++ {
+		err := execute()
+		if err == nil || retry > retries || ctx.Err() != nil {
+			return err
+		}
+
+		fmt.Fprintf(r.config.Err, "%s failed, retrying in %s (retry %d of %d): %s\n", description, wait, retry, retries, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorterRuntime_getRetryPolicy(t *testing.T) {
+	m := &manifest.Manifest{
+		Retry: manifest.RetryPolicies{
+			cnab.ActionInstall: {Retries: 3, Backoff: "30s"},
+		},
+	}
+
+	t.Run("bundle policy", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = r.NewRuntimeManifest(cnab.ActionInstall, m)
+
+		retries, backoff, err := r.getRetryPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, 3, retries)
+		assert.Equal(t, 30*time.Second, backoff)
+	})
+
+	t.Run("no policy for the action", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = r.NewRuntimeManifest(cnab.ActionUpgrade, m)
+
+		retries, backoff, err := r.getRetryPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, 0, retries, "failed steps should not be retried by default")
+		assert.Equal(t, manifest.DefaultRetryBackoff, backoff)
+	})
+
+	t.Run("overridden by flags", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.config.Retries = 5
+		r.config.RetryBackoff = time.Second
+		r.RuntimeManifest = r.NewRuntimeManifest(cnab.ActionInstall, m)
+
+		retries, backoff, err := r.getRetryPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, 5, retries)
+		assert.Equal(t, time.Second, backoff)
+	})
+}
+
+func TestPorterRuntime_retryStep(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("succeeds after retry", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+
+		var attempts int
+		err := r.retryStep(ctx, "Install MySQL", 3, time.Millisecond, func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("registry unavailable")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Contains(t, r.TestContext.GetError(), "Install MySQL failed, retrying in 1ms (retry 1 of 3): registry unavailable")
+		assert.Contains(t, r.TestContext.GetError(), "Install MySQL failed, retrying in 2ms (retry 2 of 3): registry unavailable", "the backoff should double after each retry")
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+
+		var attempts int
+		err := r.retryStep(ctx, "", 2, time.Millisecond, func() error {
+			attempts++
+			return errors.New("throttled")
+		})
+		require.EqualError(t, err, "throttled")
+		assert.Equal(t, 3, attempts, "the step should be executed once, and then retried twice")
+	})
+
+	t.Run("no retries", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+
+		var attempts int
+		err := r.retryStep(ctx, "", 0, time.Millisecond, func() error {
+			attempts++
+			return errors.New("throttled")
+		})
+		require.EqualError(t, err, "throttled")
+		assert.Equal(t, 1, attempts)
+		assert.Empty(t, r.TestContext.GetError())
+	})
+}
@@ -3,16 +3,18 @@ package runtime
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"get.porter.sh/porter/pkg"
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/manifest"
+	"get.porter.sh/porter/pkg/mixin/protocol"
 	"get.porter.sh/porter/pkg/pkgmgmt"
 	"get.porter.sh/porter/pkg/portercontext"
-	"get.porter.sh/porter/pkg/yaml"
 	"github.com/cnabio/cnab-to-oci/relocation"
 	"github.com/hashicorp/go-multierror"
 )
@@ -22,6 +24,12 @@ type PorterRuntime struct {
 	config          RuntimeConfig
 	mixins          pkgmgmt.PackageManager
 	RuntimeManifest *RuntimeManifest
+
+	// stepMetrics records how long each step took to execute.
+	stepMetrics []cnab.StepMetric
+
+	// mixinConnections are the mixins used by the current action, by name.
+	mixinConnections map[string]mixinConnection
 }
 
 func NewPorterRuntime(runtimeCfg RuntimeConfig, mixins pkgmgmt.PackageManager) *PorterRuntime {
@@ -31,14 +39,32 @@ func NewPorterRuntime(runtimeCfg RuntimeConfig, mixins pkgmgmt.PackageManager) *
 	}
 }
 
-func (r *PorterRuntime) Execute(ctx context.Context, rm *RuntimeManifest) error {
+func (r *PorterRuntime) Execute(ctx context.Context, rm *RuntimeManifest) (err error) {
 	r.RuntimeManifest = rm
+	r.stepMetrics = nil
+	defer r.closeMixinConnections(ctx)
+
+	// Always record the step metrics when they are enabled, even when the
+	// action fails, because the output is required by the bundle
+	defer func() {
+		if !rm.Metrics {
+			return
+		}
+		if metricsErr := r.writeStepMetrics(); metricsErr != nil {
+			err = multierror.Append(err, metricsErr).ErrorOrNil()
+		}
+	}()
 
 	installationName := r.config.Getenv(config.EnvInstallationName)
 	bundleName := r.config.Getenv(config.EnvBundleName)
+	restoreLogs := func() {}
+	if r.config.LogFormat == config.LogFormatJSON {
+		restoreLogs = r.useJSONLogs("", "")
+	}
 	fmt.Fprintf(r.config.Out, "executing %s action from %s (installation: %s)\n", r.RuntimeManifest.Action, bundleName, installationName)
+	restoreLogs()
 
-	err := r.RuntimeManifest.Validate()
+	err = r.RuntimeManifest.Validate()
 	if err != nil {
 		return err
 	}
@@ -71,14 +97,82 @@ func (r *PorterRuntime) Execute(ctx context.Context, rm *RuntimeManifest) error
 		return fmt.Errorf("could not create outputs directory %s: %w", portercontext.MixinOutputsDir, err)
 	}
 
+	retries, retryBackoff, err := r.getRetryPolicy()
+	if err != nil {
+		return err
+	}
+
+	actionTimeout, cleanupSteps, err := r.getTimeoutPolicy()
+	if err != nil {
+		return err
+	}
+	actionCtx := ctx
+	if actionTimeout > 0 {
+		var cancel context.CancelFunc
+		actionCtx, cancel = context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+	}
+
 	var bigErr *multierror.Error
+steps:
 	for stepIndex, step := range r.RuntimeManifest.GetSteps() {
-		err = r.executeStep(ctx, stepIndex, step)
+		if step == nil {
+			continue
+		}
+
+		stepPath := fmt.Sprintf("%s[%d]", r.RuntimeManifest.Action, stepIndex)
+		iterations, err := r.RuntimeManifest.getStepIterations(actionCtx, stepPath, step)
 		if err != nil {
+			r.recordStepMetric(step, time.Now(), err)
 			bigErr = multierror.Append(bigErr, err)
 			break
 		}
+		if len(iterations) == 0 {
+			description, _ := step.GetDescription()
+			r.printSkippedStep(description, "its foreach list is empty")
+			r.recordStepMetric(step, time.Now(), errStepSkipped)
+			continue
+		}
+
+		// Steps that do not use foreach have a single nil iteration
+		for _, iteration := range iterations {
+			r.RuntimeManifest.iteration = iteration
+
+			started := time.Now()
+			execute, err := r.shouldExecuteStep(actionCtx, stepPath, step)
+			if err != nil {
+				r.recordStepMetric(step, started, err)
+				bigErr = multierror.Append(bigErr, err)
+				break steps
+			}
+			description, _ := step.GetDescription()
+			if !execute {
+				r.printSkippedStep(description, "its when condition is false")
+				r.recordStepMetric(step, started, errStepSkipped)
+				continue
+			}
+
+			err = r.retryStep(actionCtx, description, retries, retryBackoff, func() error {
+				return r.executeStepWithTimeout(actionCtx, stepPath, step)
+			})
+			if err != nil && actionCtx.Err() == context.DeadlineExceeded {
+				err = fmt.Errorf("%w: the %s action did not complete within %s: %s", ErrTimedOut, r.RuntimeManifest.Action, actionTimeout, err)
+			}
+			r.recordStepMetric(step, started, err)
+			if err != nil {
+				bigErr = multierror.Append(bigErr, err)
+				if errors.Is(err, ErrTimedOut) {
+					r.RuntimeManifest.iteration = nil
+					// Use the original context, the action context has already timed out
+					if cleanupErr := r.executeCleanupSteps(ctx, cleanupSteps); cleanupErr != nil {
+						bigErr = multierror.Append(bigErr, cleanupErr)
+					}
+				}
+				break steps
+			}
+		}
 	}
+	r.RuntimeManifest.iteration = nil
 
 	err = r.RuntimeManifest.Finalize(ctx)
 	if err != nil {
@@ -88,16 +182,21 @@ func (r *PorterRuntime) Execute(ctx context.Context, rm *RuntimeManifest) error
 	return bigErr.ErrorOrNil()
 }
 
-func (r *PorterRuntime) executeStep(ctx context.Context, stepIndex int, step *manifest.Step) error {
+func (r *PorterRuntime) executeStep(ctx context.Context, stepPath string, step *manifest.Step) error {
 	if step == nil {
 		return nil
 	}
-	err := r.RuntimeManifest.ResolveStep(ctx, stepIndex, step)
+	err := r.RuntimeManifest.resolveStepAt(ctx, stepPath, step)
 	if err != nil {
 		return fmt.Errorf("unable to resolve step: %w", err)
 	}
 
 	description, _ := step.GetDescription()
+	if r.config.LogFormat == config.LogFormatJSON {
+		// Label the output of the step with the step and mixin that generated it
+		restoreLogs := r.useJSONLogs(description, step.GetMixinName())
+		defer restoreLogs()
+	}
 	if len(description) > 0 {
 		fmt.Fprintln(r.config.Out, description)
 	}
@@ -105,25 +204,30 @@ func (r *PorterRuntime) executeStep(ctx context.Context, stepIndex int, step *ma
 	// Hand over values needing masking in config output streams
 	r.config.Context.SetSensitiveValues(r.RuntimeManifest.GetSensitiveValues())
 
-	input := &ActionInput{
-		action: r.RuntimeManifest.Action,
-		Steps:  []*manifest.Step{step},
+	mixinName := step.GetMixinName()
+	stepData, _ := removePorterStepFields(step).Data[mixinName].(map[string]interface{})
+	req := protocol.ExecuteRequest{
+		Action: string(r.RuntimeManifest.Action),
+		Step:   stepData,
 	}
-	inputBytes, _ := yaml.Marshal(input)
-	cmd := pkgmgmt.CommandOptions{
-		Command: string(r.RuntimeManifest.Action),
-		Input:   string(inputBytes),
-		Runtime: true,
-	}
-	err = r.mixins.Run(ctx, r.config.Context, step.GetMixinName(), cmd)
+	result, err := r.getMixinConnection(ctx, mixinName).Execute(ctx, req, &stepReporter{r: r})
 	if err != nil {
 		return fmt.Errorf("mixin execution failed: %w", err)
 	}
 
-	outputs, err := r.readMixinOutputs()
-	if err != nil {
-		return fmt.Errorf("could not read step outputs: %w", err)
+	outputs := make(map[string]string, len(result.Outputs))
+	var sensitiveOutputs []string
+	for _, output := range result.Outputs {
+		value, err := output.String()
+		if err != nil {
+			return err
+		}
+		outputs[output.Name] = value
+		if output.Sensitive && value != "" {
+			sensitiveOutputs = append(sensitiveOutputs, value)
+		}
 	}
+	r.config.Context.SetSensitiveValues(sensitiveOutputs)
 
 	err = r.RuntimeManifest.ApplyStepOutputs(outputs)
 	if err != nil {
@@ -134,6 +238,72 @@ func (r *PorterRuntime) executeStep(ctx context.Context, stepIndex int, step *ma
 	return r.applyStepOutputsToBundle(outputs)
 }
 
+// porterStepFields are the fields of a step that are evaluated by Porter, and are not passed to the mixin.
+var porterStepFields = []string{manifest.StepWhenField, manifest.StepForeachField}
+
+// removePorterStepFields returns a copy of the step without the fields that are
+// evaluated by Porter, such as its when condition.
+func removePorterStepFields(step *manifest.Step) *manifest.Step {
+	mixinName := step.GetMixinName()
+	children, ok := step.Data[mixinName].(map[string]interface{})
+	if !ok {
+		return step
+	}
+
+	stepData := make(map[string]interface{}, len(children))
+	for k, v := range children {
+		stepData[k] = v
+	}
+	for _, field := range porterStepFields {
+		delete(stepData, field)
+	}
+	return &manifest.Step{Data: map[string]interface{}{mixinName: stepData}}
+}
+
+// recordStepMetric records how long a step took to execute.
+func (r *PorterRuntime) recordStepMetric(step *manifest.Step, started time.Time, err error) {
+	if step == nil {
+		return
+	}
+
+	description, _ := step.GetDescription()
+	metric := cnab.StepMetric{
+		Description: description,
+		Mixin:       step.GetMixinName(),
+		Started:     started,
+		Stopped:     time.Now(),
+		Status:      cnab.StatusSucceeded,
+	}
+	if errors.Is(err, errStepSkipped) {
+		metric.Status = cnab.StepStatusSkipped
+	} else if errors.Is(err, ErrTimedOut) {
+		metric.Status = cnab.StepStatusTimedOut
+	} else if err != nil {
+		metric.Status = cnab.StatusFailed
+	}
+	r.stepMetrics = append(r.stepMetrics, metric)
+}
+
+// writeStepMetrics writes the step metrics to the porter-metrics bundle output,
+// so that Porter can record them on the result of the run.
+func (r *PorterRuntime) writeStepMetrics() error {
+	metrics := r.stepMetrics
+	if metrics == nil {
+		metrics = []cnab.StepMetric{}
+	}
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		return fmt.Errorf("could not marshal the step metrics: %w", err)
+	}
+
+	outpath := filepath.Join(config.BundleOutputsDir, cnab.OutputPorterMetrics)
+	if err = r.config.FileSystem.WriteFile(outpath, data, pkg.FileModeWritable); err != nil {
+		return fmt.Errorf("unable to write output file %s: %w", outpath, err)
+	}
+	return nil
+}
+
 // applyStepOutputsToBundle writes the provided step outputs to the proper location
 // in the bundle execution environment.
 func (r *PorterRuntime) applyStepOutputsToBundle(outputs map[string]string) error {
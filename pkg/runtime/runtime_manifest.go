@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -19,7 +20,6 @@ import (
 	"get.porter.sh/porter/pkg/manifest"
 	"get.porter.sh/porter/pkg/tracing"
 	"get.porter.sh/porter/pkg/yaml"
-	"github.com/cbroglie/mustache"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-to-oci/relocation"
 	"github.com/hashicorp/go-multierror"
@@ -51,6 +51,10 @@ type RuntimeManifest struct {
 	steps           manifest.Steps
 	outputs         map[string]string
 	sensitiveValues []string
+
+	// iteration is the current iteration of a step that uses foreach, or nil
+	// when the step does not use foreach.
+	iteration *stepIteration
 }
 
 func NewRuntimeManifest(cfg RuntimeConfig, action string, manifest *manifest.Manifest) *RuntimeManifest {
@@ -182,6 +186,37 @@ func (m *RuntimeManifest) setSensitiveValue(val string) {
 	}
 }
 
+// setSensitiveTemplateValue marks a value used in templates as sensitive. The items and fields of array
+// and object values are sensitive too, because they may be used on their own in a template.
+// Array and object values are rendered as json, which may be formatted differently than the original value.
+func (m *RuntimeManifest) setSensitiveTemplateValue(value interface{}) {
+	switch v := value.(type) {
+	case manifest.TemplateObject:
+		m.setSensitiveValue(v.String())
+		for _, item := range v {
+			m.setSensitiveTemplateValue(item)
+		}
+	case manifest.TemplateArray:
+		m.setSensitiveValue(v.String())
+		for _, item := range v {
+			m.setSensitiveTemplateValue(item)
+		}
+	case string:
+		m.setSensitiveValue(v)
+	}
+}
+
+// isSensitiveValue determines if a value used in a template is sensitive.
+func (m *RuntimeManifest) isSensitiveValue(value interface{}) bool {
+	val := fmt.Sprintf("%v", value)
+	for _, item := range m.sensitiveValues {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *RuntimeManifest) GetSteps() manifest.Steps {
 	return m.steps
 }
@@ -271,10 +306,23 @@ func (m *RuntimeManifest) buildSourceData() (map[string]interface{}, error) {
 
 		pe := param.Name
 		val := m.resolveParameter(param)
+		// Array and object parameters may be indexed in templates
+		tmplVal := manifest.NewTemplateParameterValue(param, val)
 		if param.Sensitive {
 			m.setSensitiveValue(val)
+			m.setSensitiveTemplateValue(tmplVal)
+		}
+		params[pe] = tmplVal
+	}
+
+	// bundle.arguments.NAME are the arguments passed to the custom action with porter invoke --arg
+	args := make(map[string]interface{})
+	bun["arguments"] = args
+	if actionDef, ok := m.CustomActionDefinitions[m.Action]; ok {
+		for _, arg := range actionDef.Arguments {
+			paramName := cnab.GetActionArgumentParameterName(m.Action, arg.Name)
+			args[arg.Name] = m.config.Getenv(manifest.ParamToEnvVar(paramName))
 		}
-		params[pe] = val
 	}
 
 	creds := make(map[string]interface{})
@@ -414,12 +462,25 @@ func (m *RuntimeManifest) buildSourceData() (map[string]interface{}, error) {
 		}
 		images[alias] = img
 	}
+
+	if m.iteration != nil {
+		data[manifest.ForeachTemplateVariable] = m.iteration.templateData()
+		if item, ok := m.iteration.Item.(string); ok && m.iteration.Sensitive {
+			m.setSensitiveValue(item)
+		}
+	}
+
 	return data, nil
 }
 
 // ResolveStep will walk through the Step's data and resolve any placeholder
 // data using the definitions in the manifest, like parameters or credentials.
 func (m *RuntimeManifest) ResolveStep(ctx context.Context, stepIndex int, step *manifest.Step) error {
+	return m.resolveStepAt(ctx, fmt.Sprintf("%s[%d]", m.Action, stepIndex), step)
+}
+
+// resolveStepAt resolves the step defined at the specified path in the manifest.
+func (m *RuntimeManifest) resolveStepAt(ctx context.Context, stepPath string, step *manifest.Step) error {
 	log := tracing.LoggerFromContext(ctx)
 
 	// Refresh our template data
@@ -429,7 +490,6 @@ func (m *RuntimeManifest) ResolveStep(ctx context.Context, stepIndex int, step *
 	}
 
 	// Get the original yaml for the current step
-	stepPath := fmt.Sprintf("%s[%d]", m.Action, stepIndex)
 	stepTemplate, err := m.getStepTemplate(stepPath)
 	if err != nil {
 		return log.Error(fmt.Errorf("unable to retrieve original yaml for step %s: %w", stepPath, err))
@@ -439,11 +499,14 @@ func (m *RuntimeManifest) ResolveStep(ctx context.Context, stepIndex int, step *
 	//fmt.Fprintf(m.Err, "=== Step Data ===\n%v\n", sourceData)
 	m.debugf(log, "=== Step Template ===\n%v\n", stepTemplate)
 
-	mustache.AllowMissingVariables = false
-	rendered, err := mustache.RenderRaw(stepTemplate, true, sourceData)
+	rendered, sensitiveValues, err := manifest.RenderTemplate(stepTemplate, sourceData, m.isSensitiveValue)
 	if err != nil {
 		return log.Errorf("unable to render step template %s: %w", stepTemplate, err)
 	}
+	// Values calculated from sensitive values with template functions are sensitive too
+	for _, val := range sensitiveValues {
+		m.setSensitiveValue(val)
+	}
 
 	// TODO: add back logging step data after we have a solid way to censor it in https://github.com/getporter/porter/issues/2256
 	//fmt.Fprintf(m.Err, "=== Rendered Step ===\n%s\n", rendered)
@@ -503,6 +566,17 @@ func (m *RuntimeManifest) Initialize(ctx context.Context) error {
 				return fmt.Errorf("unable to decode parameter %s: %w", paramName, err)
 			}
 
+			// A directory is extracted in place of the file containing the parameter value
+			if cnab.IsDirectoryParameter(decoded) {
+				if err := m.config.FileSystem.Remove(param.Destination.Path); err != nil {
+					return fmt.Errorf("unable to remove encoded parameter %s: %w", paramName, err)
+				}
+				if err := cnab.ExtractDirectoryParameter(m.config.FileSystem, decoded, param.Destination.Path); err != nil {
+					return fmt.Errorf("unable to extract directory parameter %s: %w", paramName, err)
+				}
+				continue
+			}
+
 			err = m.config.FileSystem.WriteFile(param.Destination.Path, decoded, pkg.FileModeWritable)
 			if err != nil {
 				return fmt.Errorf("unable to write decoded parameter %s: %w", paramName, err)
@@ -553,12 +627,51 @@ func (m *RuntimeManifest) unpackStateBag(ctx context.Context) error {
 		stateFiles[s.Name] = s.Path
 	}
 
+	// getStateDestination returns the path in the bundle for an entry in the state archive.
+	// Entries are named porter-state/NAME for files, and porter-state/NAME/RELPATH for
+	// the contents of a directory.
+	getStateDestination := func(header *tar.Header) (string, bool, error) {
+		entry := strings.TrimSuffix(strings.TrimPrefix(header.Name, "porter-state/"), "/")
+		name, relPath, _ := strings.Cut(entry, "/")
+		dest, ok := stateFiles[name]
+		if !ok {
+			return "", false, nil
+		}
+		if relPath == "" {
+			return dest, true, nil
+		}
+
+		// Do not allow entries to escape the state directory
+		relPath = filepath.Clean(filepath.FromSlash(relPath))
+		if filepath.IsAbs(relPath) || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return "", false, fmt.Errorf("invalid path %s in the bundle state", header.Name)
+		}
+		return filepath.Join(dest, relPath), true, nil
+	}
+
 	unpackStateFile := func(tr *tar.Reader, header *tar.Header) error {
-		name := strings.TrimPrefix(header.Name, "porter-state/")
-		dest := stateFiles[name]
-		m.debugf(log, "  - %s -> %s", name, dest)
+		dest, ok, err := getStateDestination(header)
+		if err != nil {
+			return log.Error(err)
+		}
+		if !ok {
+			m.debugf(log, "  - skipping %s, it is not declared in the bundle state", header.Name)
+			return nil
+		}
+		m.debugf(log, "  - %s -> %s", header.Name, dest)
 
-		f, err := os.OpenFile(dest, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+		if header.Typeflag == tar.TypeDir {
+			if err := m.config.FileSystem.MkdirAll(dest, os.FileMode(header.Mode)|0700); err != nil {
+				return log.Error(fmt.Errorf("error creating state directory %s: %w", dest, err))
+			}
+			return nil
+		}
+
+		if err := m.config.FileSystem.MkdirAll(filepath.Dir(dest), pkg.FileModeDirectory); err != nil {
+			return log.Error(fmt.Errorf("error creating directory for state file %s: %w", dest, err))
+		}
+
+		f, err := m.config.FileSystem.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode))
 		if err != nil {
 			return log.Error(fmt.Errorf("error creating state file %s: %w", dest, err))
 		}
@@ -576,6 +689,7 @@ func (m *RuntimeManifest) unpackStateBag(ctx context.Context) error {
 	if err != nil {
 		return log.Error(fmt.Errorf("could not open statefile at %s: %w", statePath, err))
 	}
+	defer stateArchive.Close()
 
 	gzr, err := gzip.NewReader(stateArchive)
 	if err != nil {
@@ -590,15 +704,19 @@ func (m *RuntimeManifest) unpackStateBag(ctx context.Context) error {
 	tr := tar.NewReader(gzr)
 	for {
 		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
-		} else if header.Typeflag != tar.TypeReg {
+			return log.Error(fmt.Errorf("could not read the statefile: %w", err))
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeDir {
 			continue
 		}
 
-		unpackStateFile(tr, header)
+		if err := unpackStateFile(tr, header); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -625,36 +743,70 @@ func (m *RuntimeManifest) packStateBag(ctx context.Context) error {
 	log := tracing.LoggerFromContext(ctx)
 
 	m.debugf(log, "Packing bundle state...")
-	packStateFile := func(tw *tar.Writer, s manifest.StateVariable) error {
-		fi, err := m.config.FileSystem.Stat(s.Path)
-		if os.IsNotExist(err) {
-			return nil
+	packStateEntry := func(tw *tar.Writer, s manifest.StateVariable, filePath string, fi os.FileInfo, name string) error {
+		if !fi.Mode().IsRegular() && !fi.IsDir() {
+			return log.Error(fmt.Errorf("unsupported state file %s for variable %s, only regular files and directories may be saved in the bundle state", filePath, s.Name))
 		}
 
-		m.debugf(log, "  - %s", s.Path)
-		header, err := tar.FileInfoHeader(fi, fi.Name())
+		header, err := tar.FileInfoHeader(fi, "")
 		if err != nil {
-			return log.Error(fmt.Errorf("error creating tar header for state variable %s from path %s: %w", s.Name, s.Path, err))
+			return log.Error(fmt.Errorf("error creating tar header for state variable %s from path %s: %w", s.Name, filePath, err))
+		}
+		header.Name = name
+		if fi.IsDir() {
+			header.Name += "/"
 		}
-		header.Name = filepath.Join("porter-state", s.Name)
 
 		if err := tw.WriteHeader(header); err != nil {
 			return log.Error(fmt.Errorf("error writing tar header for state variable %s: %w", s.Name, err))
 		}
 
-		f, err := os.Open(s.Path)
+		if fi.IsDir() {
+			return nil
+		}
+
+		f, err := m.config.FileSystem.Open(filePath)
 		if err != nil {
-			return log.Error(fmt.Errorf("error reading state file %s for variable %s: %w", s.Path, s.Name, err))
+			return log.Error(fmt.Errorf("error reading state file %s for variable %s: %w", filePath, s.Name, err))
 		}
+		defer f.Close()
 
 		_, err = io.Copy(tw, f)
 		if err != nil {
-			return log.Error(fmt.Errorf("error archiving state file %s for variable %s: %w", s.Path, s.Name, err))
+			return log.Error(fmt.Errorf("error archiving state file %s for variable %s: %w", filePath, s.Name, err))
 		}
 
 		return nil
 	}
 
+	packStateFile := func(tw *tar.Writer, s manifest.StateVariable) error {
+		fi, err := m.config.FileSystem.Stat(s.Path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return log.Error(fmt.Errorf("error reading state file %s for variable %s: %w", s.Path, s.Name, err))
+		}
+
+		m.debugf(log, "  - %s", s.Path)
+		name := path.Join("porter-state", s.Name)
+		if !fi.IsDir() {
+			return packStateEntry(tw, s, s.Path, fi, name)
+		}
+
+		// Save the contents of the directory under porter-state/NAME
+		return m.config.FileSystem.Walk(s.Path, func(filePath string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			relPath, err := filepath.Rel(s.Path, filePath)
+			if err != nil {
+				return err
+			}
+			return packStateEntry(tw, s, filePath, fi, path.Join(name, filepath.ToSlash(relPath)))
+		})
+	}
+
 	// Save directly to the final output location since we've already collected outputs at this point
 	stateArchive, err := m.config.FileSystem.Create("/cnab/app/outputs/porter-state")
 	if err != nil {
@@ -124,6 +124,93 @@ state:
 	}
 }
 
+func TestStateBag_PackUnpack(t *testing.T) {
+	ctx := context.Background()
+	pCtx := portercontext.NewTestContext(t)
+
+	mContent := `schemaVersion: 1.0.0
+install:
+- mymixin:
+    Parameters:
+      Thing: value
+state:
+- name: tfstate
+  path: /cnab/app/terraform/terraform.tfstate
+- name: plugins
+  path: /cnab/app/terraform/.terraform
+- name: missing
+  path: /cnab/app/missing.json
+`
+	rm := runtimeManifestFromStepYaml(t, pCtx, mContent)
+	require.NoError(t, pCtx.FileSystem.WriteFile("/cnab/app/terraform/terraform.tfstate", []byte("tfstate"), pkg.FileModeWritable))
+	require.NoError(t, pCtx.FileSystem.WriteFile("/cnab/app/terraform/.terraform/providers/azurerm", []byte("azurerm"), pkg.FileModeWritable))
+	require.NoError(t, pCtx.FileSystem.WriteFile("/cnab/app/terraform/.terraform/terraform.lock", []byte("lock"), pkg.FileModeWritable))
+
+	require.NoError(t, rm.packStateBag(ctx))
+
+	// Simulate the next run of the bundle, where the state is injected into a fresh bundle
+	state, err := pCtx.FileSystem.ReadFile("/cnab/app/outputs/porter-state")
+	require.NoError(t, err)
+	require.NoError(t, pCtx.FileSystem.RemoveAll("/cnab/app/terraform"))
+	require.NoError(t, pCtx.FileSystem.WriteFile("/porter/state.tgz", state, pkg.FileModeWritable))
+
+	require.NoError(t, rm.unpackStateBag(ctx))
+
+	assertStateFile(t, pCtx, "/cnab/app/terraform/terraform.tfstate", "tfstate")
+	assertStateFile(t, pCtx, "/cnab/app/terraform/.terraform/providers/azurerm", "azurerm")
+	assertStateFile(t, pCtx, "/cnab/app/terraform/.terraform/terraform.lock", "lock")
+	exists, _ := pCtx.FileSystem.Exists("/cnab/app/missing.json")
+	assert.False(t, exists, "state files that did not exist when the state was saved should not be created")
+}
+
+func assertStateFile(t *testing.T, pCtx *portercontext.TestContext, path string, want string) {
+	got, err := pCtx.FileSystem.ReadFile(path)
+	require.NoError(t, err, "state file %s was not unpacked", path)
+	assert.Equal(t, want, string(got), "invalid contents for state file %s", path)
+}
+
+func TestInitialize_DirectoryParameter(t *testing.T) {
+	ctx := context.Background()
+	pCtx := portercontext.NewTestContext(t)
+
+	mContent := `schemaVersion: 1.0.0
+parameters:
+- name: config
+  type: file
+  path: /cnab/app/config
+
+install:
+- mymixin:
+    Parameters:
+      Thing: ${ bundle.parameters.config }
+`
+	rm := runtimeManifestFromStepYaml(t, pCtx, mContent)
+	rm.bundle = cnab.NewBundle(bundle.Bundle{
+		RequiredExtensions: []string{cnab.FileParameterExtensionKey},
+		Parameters: map[string]bundle.Parameter{
+			"config": {
+				Definition:  "config",
+				Destination: &bundle.Location{Path: "/cnab/app/config"},
+			},
+		},
+		Definitions: map[string]*definition.Schema{
+			"config": {Type: "string", ContentEncoding: "base64"},
+		},
+	})
+
+	require.NoError(t, pCtx.FileSystem.WriteFile("/src/settings.json", []byte("{}"), pkg.FileModeWritable))
+	value, err := cnab.EncodeDirectoryParameter(pCtx.FileSystem, "/src")
+	require.NoError(t, err)
+	require.NoError(t, pCtx.FileSystem.WriteFile("/cnab/app/config", []byte(value), pkg.FileModeWritable))
+
+	err = rm.Initialize(ctx)
+	require.NoError(t, err)
+
+	contents, err := pCtx.FileSystem.ReadFile("/cnab/app/config/settings.json")
+	require.NoError(t, err, "the directory parameter should be extracted to the destination path")
+	assert.Equal(t, "{}", string(contents))
+}
+
 func TestResolvePathParam(t *testing.T) {
 	ctx := context.Background()
 	pCtx := portercontext.NewTestContext(t)
@@ -308,6 +395,85 @@ install:
 	assert.Equal(t, []string{"deliciou$dubonnet"}, rm.GetSensitiveValues())
 }
 
+func TestResolveStep_TemplateFunctions(t *testing.T) {
+	ctx := context.Background()
+	pCtx := portercontext.NewTestContext(t)
+	pCtx.Setenv("SENSITIVE_PARAM", "deliciou$dubonnet")
+	pCtx.Setenv("REGULAR_PARAM", "  Regular Param  ")
+
+	mContent := `schemaVersion: 1.0.0
+parameters:
+- name: sensitive_param
+  sensitive: true
+- name: regular_param
+- name: unset_param
+
+install:
+- mymixin:
+    Arguments:
+    - ${ bundle.parameters.sensitive_param | b64enc }
+    - ${ bundle.parameters.regular_param | trim | lower | regexReplace " " "-" }
+    - ${ bundle.parameters.unset_param | default "fallback" }
+    - ${ bundle.parameters.regular_param | toJson }
+`
+	rm := runtimeManifestFromStepYaml(t, pCtx, mContent)
+	s := rm.Install[0]
+
+	err := rm.ResolveStep(ctx, 0, s)
+	require.NoError(t, err)
+
+	mixin := s.Data["mymixin"].(map[string]interface{})
+	args := mixin["Arguments"].([]interface{})
+	require.Len(t, args, 4)
+	assert.Equal(t, "ZGVsaWNpb3UkZHVib25uZXQ=", args[0])
+	assert.Equal(t, "regular-param", args[1])
+	assert.Equal(t, "fallback", args[2])
+	assert.Equal(t, "  Regular Param  ", args[3], "toJson should quote the value so that it is a valid yaml string")
+
+	// Values calculated from sensitive values must be masked too
+	assert.Equal(t, []string{"deliciou$dubonnet", "ZGVsaWNpb3UkZHVib25uZXQ="}, rm.GetSensitiveValues())
+}
+
+func TestResolveStep_StructuredParameters(t *testing.T) {
+	ctx := context.Background()
+	pCtx := portercontext.NewTestContext(t)
+	pCtx.Setenv("NODES", `[{"name": "primary", "size": 3}, {"name": "secondary", "size": 1}]`)
+	pCtx.Setenv("CONFIG", `{"logLevel": 11, "password": "topsecret"}`)
+
+	mContent := `schemaVersion: 1.0.0
+parameters:
+- name: nodes
+  type: array
+- name: config
+  type: object
+  sensitive: true
+
+install:
+- mymixin:
+    Arguments:
+    - ${ bundle.parameters.nodes[1].name }
+    - ${ bundle.parameters.nodes[0].size }
+    - ${ bundle.parameters.config.password }
+    Nodes: ${ bundle.parameters.nodes }
+`
+	rm := runtimeManifestFromStepYaml(t, pCtx, mContent)
+	s := rm.Install[0]
+
+	err := rm.ResolveStep(ctx, 0, s)
+	require.NoError(t, err)
+
+	mixin := s.Data["mymixin"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"secondary", 3, "topsecret"}, mixin["Arguments"])
+	wantNodes := []interface{}{
+		map[string]interface{}{"name": "primary", "size": 3},
+		map[string]interface{}{"name": "secondary", "size": 1},
+	}
+	assert.Equal(t, wantNodes, mixin["Nodes"], "array parameters should be passed to the mixin as structured data")
+
+	// The fields of a sensitive object must be masked when they are used on their own
+	assert.Contains(t, rm.GetSensitiveValues(), "topsecret")
+}
+
 func TestResolveCredential(t *testing.T) {
 	ctx := context.Background()
 	pCtx := portercontext.NewTestContext(t)
@@ -1040,6 +1206,42 @@ install:
 	assert.Equal(t, "true", mixin["notabool"], "a quoted boolean should render as a string")
 }
 
+func TestResolveActionArguments(t *testing.T) {
+	ctx := context.Background()
+	pCtx := portercontext.NewTestContext(t)
+	pCtx.Setenv("PORTER_BACKUP_TARGET_ARG", "s3://mybucket")
+	pCtx.Setenv("PORTER_BACKUP_RETENTION_ARG", "7d")
+
+	mContent := `schemaVersion: 1.0.0
+customActions:
+  backup:
+    arguments:
+      - name: target
+        required: true
+      - name: retention
+        default: 7d
+
+backup:
+- mymixin:
+    target: ${ bundle.arguments.target }
+    retention: ${ bundle.arguments.retention }
+`
+	require.NoError(t, pCtx.FileSystem.WriteFile("/cnab/app/porter.yaml", []byte(mContent), pkg.FileModeWritable))
+	m, err := manifest.ReadManifest(pCtx.Context, "/cnab/app/porter.yaml")
+	require.NoError(t, err, "ReadManifest failed")
+	rm := NewRuntimeManifest(NewConfigFor(pCtx.Context), "backup", m)
+	s := rm.CustomActions["backup"][0]
+
+	err = rm.ResolveStep(ctx, 0, s)
+	require.NoError(t, err)
+
+	require.IsType(t, map[string]interface{}{}, s.Data["mymixin"], "Data.mymixin has the wrong type")
+	mixin := s.Data["mymixin"].(map[string]interface{})
+
+	assert.Equal(t, "s3://mybucket", mixin["target"], "bundle.arguments.target was not rendered")
+	assert.Equal(t, "7d", mixin["retention"], "bundle.arguments.retention was not rendered")
+}
+
 func TestResolveEnvironmentVariable(t *testing.T) {
 	ctx := context.Background()
 	pCtx := portercontext.NewTestContext(t)
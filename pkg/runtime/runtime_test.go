@@ -2,9 +2,12 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
 	"get.porter.sh/porter/pkg/config"
@@ -61,6 +64,44 @@ BAZ`,
 	assert.Equal(t, wantOutputs, gotOutputs)
 }
 
+func TestPorterRuntime_writeStepMetrics(t *testing.T) {
+	r := NewTestPorterRuntime(t)
+
+	t.Run("no steps", func(t *testing.T) {
+		require.NoError(t, r.writeStepMetrics())
+
+		data, err := r.config.FileSystem.ReadFile(filepath.Join(config.BundleOutputsDir, cnab.OutputPorterMetrics))
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(data))
+	})
+
+	t.Run("steps", func(t *testing.T) {
+		step := &manifest.Step{Data: map[string]interface{}{
+			"exec": map[string]interface{}{"description": "Say hello"},
+		}}
+		started := time.Now().Add(-time.Minute)
+		r.recordStepMetric(step, started, nil)
+		r.recordStepMetric(step, time.Now(), errors.New("oops"))
+		r.recordStepMetric(step, time.Now(), fmt.Errorf("%w: the step did not complete within 1s", ErrTimedOut))
+		r.recordStepMetric(step, time.Now(), errStepSkipped)
+		require.NoError(t, r.writeStepMetrics())
+
+		data, err := r.config.FileSystem.ReadFile(filepath.Join(config.BundleOutputsDir, cnab.OutputPorterMetrics))
+		require.NoError(t, err)
+		var metrics []cnab.StepMetric
+		require.NoError(t, json.Unmarshal(data, &metrics))
+
+		require.Len(t, metrics, 4)
+		assert.Equal(t, "Say hello", metrics[0].Description)
+		assert.Equal(t, "exec", metrics[0].Mixin)
+		assert.Equal(t, cnab.StatusSucceeded, metrics[0].Status)
+		assert.GreaterOrEqual(t, metrics[0].Duration(), time.Minute)
+		assert.Equal(t, cnab.StatusFailed, metrics[1].Status)
+		assert.Equal(t, cnab.StepStatusTimedOut, metrics[2].Status)
+		assert.Equal(t, cnab.StepStatusSkipped, metrics[3].Status)
+	})
+}
+
 func TestPorterRuntime_ApplyStepOutputsToBundle_None(t *testing.T) {
 	r := NewTestPorterRuntime(t)
 	m := &manifest.Manifest{Name: "mybun"}
@@ -321,3 +362,13 @@ func TestLoadImageMappingFilesGoodFiles(t *testing.T) {
 	assert.NotEmpty(t, reloMap)
 	assert.Equal(t, "mysql", bun.Name)
 }
+
+func TestRemovePorterStepFields(t *testing.T) {
+	step := &manifest.Step{Data: map[string]interface{}{
+		"exec": map[string]interface{}{"description": "Say hello", "when": "true", "foreach": "[1]"},
+	}}
+
+	got := removePorterStepFields(step)
+	assert.Equal(t, map[string]interface{}{"exec": map[string]interface{}{"description": "Say hello"}}, got.Data)
+	assert.Contains(t, step.Data["exec"], "when", "the original step should not be modified")
+}
@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/manifest"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ErrTimedOut indicates that a step was stopped because it, or the action, did
+// not complete in time.
+var ErrTimedOut = errors.New("timed out")
+
+// getTimeoutPolicy returns how long the current action may run, or 0 when it
+// does not time out, and the steps to execute when it times out. The timeout
+// defined in the bundle is overridden by the --timeout flag.
+func (r *PorterRuntime) getTimeoutPolicy() (time.Duration, manifest.Steps, error) {
+	policy := r.RuntimeManifest.Timeout[r.RuntimeManifest.Action]
+	timeout, err := policy.GetDuration()
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid timeout policy for action %s: %w", r.RuntimeManifest.Action, err)
+	}
+
+	if r.config.Timeout > 0 {
+		timeout = r.config.Timeout
+	}
+	return timeout, policy.Cleanup, nil
+}
+
+// executeStepWithTimeout executes the step, stopping it when it does not
+// complete within the timeout defined on the step.
+func (r *PorterRuntime) executeStepWithTimeout(ctx context.Context, stepPath string, step *manifest.Step) error {
+	timeout, err := step.GetTimeout()
+	if err != nil {
+		return err
+	}
+
+	stepCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err = r.executeStep(stepCtx, stepPath, step)
+	if err != nil && ctx.Err() == nil && stepCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w: the step did not complete within %s: %s", ErrTimedOut, timeout, err)
+	}
+	return err
+}
+
+// executeCleanupSteps executes the cleanup steps of the current action after it
+// timed out. Every cleanup step is executed, even when a previous cleanup step failed.
+func (r *PorterRuntime) executeCleanupSteps(ctx context.Context, steps manifest.Steps) error {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(r.config.Err, "The %s action timed out, executing its cleanup steps\n", r.RuntimeManifest.Action)
+
+	var bigErr *multierror.Error
+	for stepIndex, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		started := time.Now()
+		stepPath := fmt.Sprintf("timeout.%s.cleanup[%d]", r.RuntimeManifest.Action, stepIndex)
+		err := r.executeStepWithTimeout(ctx, stepPath, step)
+		r.recordStepMetric(step, started, err)
+		if err != nil {
+			bigErr = multierror.Append(bigErr, fmt.Errorf("cleanup step failed: %w", err))
+		}
+	}
+	return bigErr.ErrorOrNil()
+}
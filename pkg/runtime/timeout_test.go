@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorterRuntime_getTimeoutPolicy(t *testing.T) {
+	cleanup := manifest.Steps{{Data: map[string]interface{}{"exec": map[string]interface{}{"description": "Clean up"}}}}
+	m := &manifest.Manifest{
+		Timeout: manifest.TimeoutPolicies{
+			cnab.ActionInstall: {Duration: "10m", Cleanup: cleanup},
+		},
+	}
+
+	t.Run("bundle policy", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = r.NewRuntimeManifest(cnab.ActionInstall, m)
+
+		timeout, steps, err := r.getTimeoutPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Minute, timeout)
+		assert.Equal(t, cleanup, steps)
+	})
+
+	t.Run("no policy for the action", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = r.NewRuntimeManifest(cnab.ActionUpgrade, m)
+
+		timeout, steps, err := r.getTimeoutPolicy()
+		require.NoError(t, err)
+		assert.Zero(t, timeout, "actions should not time out by default")
+		assert.Empty(t, steps)
+	})
+
+	t.Run("overridden by flags", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.config.Timeout = time.Minute
+		r.RuntimeManifest = r.NewRuntimeManifest(cnab.ActionInstall, m)
+
+		timeout, steps, err := r.getTimeoutPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, timeout)
+		assert.Equal(t, cleanup, steps, "the cleanup steps defined in the bundle should still be used")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		r := NewTestPorterRuntime(t)
+		r.RuntimeManifest = r.NewRuntimeManifest(cnab.ActionInstall, &manifest.Manifest{
+			Timeout: manifest.TimeoutPolicies{cnab.ActionInstall: {Duration: "soon"}},
+		})
+
+		_, _, err := r.getTimeoutPolicy()
+		require.ErrorContains(t, err, "invalid timeout policy for action install")
+	})
+}
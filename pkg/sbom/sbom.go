@@ -0,0 +1,105 @@
+// Package sbom generates software bills of materials (SBOM) for invocation
+// images. The SBOM is generated by the syft CLI, which must be installed and on
+// the PATH.
+package sbom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// FormatSPDX is an SBOM in the SPDX JSON format.
+	FormatSPDX = "spdx"
+
+	// FormatCycloneDX is an SBOM in the CycloneDX JSON format.
+	FormatCycloneDX = "cyclonedx"
+
+	// DefaultFormat is the SBOM format used when one is not specified.
+	DefaultFormat = FormatSPDX
+
+	// ArtifactTypeSPDX is the media type of an SBOM in the SPDX JSON format.
+	ArtifactTypeSPDX = "application/spdx+json"
+
+	// ArtifactTypeCycloneDX is the media type of an SBOM in the CycloneDX JSON format.
+	ArtifactTypeCycloneDX = "application/vnd.cyclonedx+json"
+)
+
+// AllowedFormats are the supported SBOM formats.
+var AllowedFormats = []string{FormatSPDX, FormatCycloneDX}
+
+// ValidateFormat checks that the SBOM format is supported.
+func ValidateFormat(format string) error {
+	for _, allowed := range AllowedFormats {
+		if format == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid SBOM format %s, allowed values are: %s", format, strings.Join(AllowedFormats, ", "))
+}
+
+// GetArtifactType returns the media type of an SBOM in the specified format,
+// which identifies the SBOM when it is attached to an image in a registry.
+func GetArtifactType(format string) string {
+	if format == FormatCycloneDX {
+		return ArtifactTypeCycloneDX
+	}
+	return ArtifactTypeSPDX
+}
+
+// GetFileName returns the name of the file that an SBOM in the specified format is saved to.
+func GetFileName(format string) string {
+	if format == FormatCycloneDX {
+		return "sbom.cdx.json"
+	}
+	return "sbom.spdx.json"
+}
+
+// getSyftOutput returns the syft output format for an SBOM in the specified format.
+func getSyftOutput(format string) string {
+	if format == FormatCycloneDX {
+		return "cyclonedx-json"
+	}
+	return "spdx-json"
+}
+
+// Generator generates SBOMs with the syft CLI.
+type Generator struct {
+	*portercontext.Context
+}
+
+// NewGenerator creates a Generator.
+func NewGenerator(c *portercontext.Context) *Generator {
+	return &Generator{Context: c}
+}
+
+// Generate an SBOM for an image in the local Docker image cache, saving it to
+// the destination file in the specified format.
+func (g *Generator) Generate(ctx context.Context, image string, format string, dest string) error {
+	ctx, log := tracing.StartSpan(ctx, attribute.String("image", image), attribute.String("format", format))
+	defer log.EndSpan()
+
+	if err := ValidateFormat(format); err != nil {
+		return log.Error(err)
+	}
+
+	args := []string{"docker:" + image, "--output", fmt.Sprintf("%s=%s", getSyftOutput(format), dest)}
+	cmd := g.NewCommand(ctx, "syft", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return log.Errorf("could not run syft to generate the SBOM, make sure that it is installed and on the PATH: %w", err)
+		}
+		return log.Errorf("could not generate the SBOM for %s: %w\n%s", image, err, strings.TrimSpace(string(output)))
+	}
+
+	log.Debug(string(output))
+	return nil
+}
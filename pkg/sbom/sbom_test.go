@@ -0,0 +1,65 @@
+package sbom
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFormat(t *testing.T) {
+	require.NoError(t, ValidateFormat(FormatSPDX))
+	require.NoError(t, ValidateFormat(FormatCycloneDX))
+	require.ErrorContains(t, ValidateFormat("syft-json"), "invalid SBOM format syft-json")
+}
+
+func TestGetArtifactType(t *testing.T) {
+	assert.Equal(t, ArtifactTypeSPDX, GetArtifactType(FormatSPDX))
+	assert.Equal(t, ArtifactTypeCycloneDX, GetArtifactType(FormatCycloneDX))
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	ctx := context.Background()
+
+	testcases := []struct {
+		name    string
+		format  string
+		wantCmd string
+		wantErr string
+	}{
+		{name: "spdx", format: FormatSPDX,
+			wantCmd: "syft docker:example.com/mybuns:v0.1.0 --output spdx-json=.cnab/sbom.spdx.json"},
+		{name: "cyclonedx", format: FormatCycloneDX,
+			wantCmd: "syft docker:example.com/mybuns:v0.1.0 --output cyclonedx-json=.cnab/sbom.cdx.json"},
+		{name: "invalid format", format: "syft-json", wantErr: "invalid SBOM format"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := portercontext.NewTestContext(t)
+			c.Setenv(test.ExpectedCommandEnv, tc.wantCmd)
+
+			g := NewGenerator(c.Context)
+			err := g.Generate(ctx, "example.com/mybuns:v0.1.0", tc.format, ".cnab/"+GetFileName(tc.format))
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("syft fails", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+		c.Setenv(test.ExpectedCommandErrorEnv, "could not fetch image")
+
+		g := NewGenerator(c.Context)
+		err := g.Generate(ctx, "example.com/mybuns:v0.1.0", FormatSPDX, ".cnab/sbom.spdx.json")
+		require.ErrorContains(t, err, "could not generate the SBOM for example.com/mybuns:v0.1.0")
+		require.ErrorContains(t, err, "could not fetch image")
+	})
+}
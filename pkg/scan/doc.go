@@ -0,0 +1,3 @@
+// Package scan scans the images referenced by a bundle for vulnerabilities.
+// Scanning is delegated to pluggable scanners, such as the trivy and grype CLIs.
+package scan
@@ -0,0 +1,72 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+)
+
+var _ Scanner = &GrypeScanner{}
+
+// GrypeScanner scans images for vulnerabilities with the grype CLI.
+type GrypeScanner struct {
+	*portercontext.Context
+}
+
+// NewGrypeScanner creates a GrypeScanner.
+func NewGrypeScanner(c *portercontext.Context) *GrypeScanner {
+	return &GrypeScanner{Context: c}
+}
+
+// grypeReport is the subset of the grype JSON report that is used by Porter.
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID          string `json:"id"`
+			Severity    string `json:"severity"`
+			Description string `json:"description"`
+			Fix         struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+		} `json:"vulnerability"`
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifact"`
+	} `json:"matches"`
+}
+
+// Scan the image with grype, pulling it directly from the registry.
+func (s *GrypeScanner) Scan(ctx context.Context, image cnab.OCIReference, opts Options) ([]Vulnerability, error) {
+	var env []string
+	if opts.InsecureRegistry {
+		env = []string{"GRYPE_REGISTRY_INSECURE_SKIP_TLS_VERIFY=true", "GRYPE_REGISTRY_INSECURE_USE_HTTP=true"}
+	}
+
+	output, err := runScanner(ctx, s.Context, env, "grype", "registry:"+image.String(), "--output", "json", "--quiet")
+	if err != nil {
+		return nil, err
+	}
+
+	var report grypeReport
+	if err = json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("error parsing the grype report for %s: %w", image, err)
+	}
+
+	vulns := make([]Vulnerability, 0, len(report.Matches))
+	for _, match := range report.Matches {
+		vulns = append(vulns, Vulnerability{
+			ID:               match.Vulnerability.ID,
+			Package:          match.Artifact.Name,
+			InstalledVersion: match.Artifact.Version,
+			FixedVersion:     strings.Join(match.Vulnerability.Fix.Versions, ", "),
+			Severity:         normalizeSeverity(match.Vulnerability.Severity),
+			Title:            match.Vulnerability.Description,
+		})
+	}
+	return vulns, nil
+}
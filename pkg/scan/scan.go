@@ -0,0 +1,83 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Scanner scans images for vulnerabilities.
+type Scanner interface {
+	// Scan the image in the registry for vulnerabilities.
+	Scan(ctx context.Context, image cnab.OCIReference, opts Options) ([]Vulnerability, error)
+}
+
+// Options for scanning an image.
+type Options struct {
+	// InsecureRegistry allows connecting to an unsecured registry or one without verifiable certificates.
+	InsecureRegistry bool
+}
+
+// Vulnerability found in a package installed in an image.
+type Vulnerability struct {
+	// ID of the vulnerability, e.g. CVE-2022-1234.
+	ID string `json:"id" yaml:"id"`
+
+	// Package that has the vulnerability.
+	Package string `json:"package" yaml:"package"`
+
+	// InstalledVersion is the version of the package that is installed in the image.
+	InstalledVersion string `json:"installedVersion" yaml:"installedVersion"`
+
+	// FixedVersion is the version of the package that fixes the vulnerability, when a fix is available.
+	FixedVersion string `json:"fixedVersion,omitempty" yaml:"fixedVersion,omitempty"`
+
+	// Severity of the vulnerability.
+	Severity Severity `json:"severity" yaml:"severity"`
+
+	// Title summarizes the vulnerability.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+}
+
+// NewScanner creates the scanner with the specified name: trivy or grype.
+func NewScanner(c *portercontext.Context, name string) (Scanner, error) {
+	switch name {
+	case config.ScannerTrivy:
+		return NewTrivyScanner(c), nil
+	case config.ScannerGrype:
+		return NewGrypeScanner(c), nil
+	default:
+		return nil, fmt.Errorf("invalid scanner %s, allowed values are: %s, %s", name, config.ScannerTrivy, config.ScannerGrype)
+	}
+}
+
+// runScanner executes a scanner CLI and returns its standard output, including
+// the standard error of the scanner in the returned error when it fails.
+func runScanner(ctx context.Context, c *portercontext.Context, env []string, name string, args ...string) ([]byte, error) {
+	ctx, log := tracing.StartSpan(ctx, attribute.String("scanner", name), attribute.String("args", strings.Join(args, " ")))
+	defer log.EndSpan()
+
+	var stderr bytes.Buffer
+	cmd := c.NewCommand(ctx, name, args...)
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, log.Errorf("could not run %s, make sure that it is installed and on the PATH: %w", name, err)
+		}
+		return nil, log.Errorf("%s failed: %w\n%s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	log.Debug(stderr.String())
+	return output, nil
+}
@@ -0,0 +1,89 @@
+package scan
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testImage = "example.com/mybuns@sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687"
+
+func TestNewScanner(t *testing.T) {
+	c := portercontext.NewTestContext(t)
+
+	scanner, err := NewScanner(c.Context, config.ScannerTrivy)
+	require.NoError(t, err)
+	assert.IsType(t, &TrivyScanner{}, scanner)
+
+	scanner, err = NewScanner(c.Context, config.ScannerGrype)
+	require.NoError(t, err)
+	assert.IsType(t, &GrypeScanner{}, scanner)
+
+	_, err = NewScanner(c.Context, "clair")
+	require.ErrorContains(t, err, "invalid scanner clair")
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := ParseSeverity("high")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityHigh, s)
+
+	_, err = ParseSeverity("severe")
+	require.ErrorContains(t, err, "invalid severity severe, allowed values are: unknown, low, medium, high, critical")
+}
+
+func TestSeverity_AtLeast(t *testing.T) {
+	assert.True(t, SeverityCritical.AtLeast(SeverityHigh))
+	assert.True(t, SeverityHigh.AtLeast(SeverityHigh))
+	assert.False(t, SeverityMedium.AtLeast(SeverityHigh))
+	assert.True(t, SeverityUnknown.AtLeast(SeverityUnknown))
+}
+
+func TestTrivyScanner_Scan(t *testing.T) {
+	ctx := context.Background()
+	ref := cnab.MustParseOCIReference(testImage)
+
+	t.Run("vulnerabilities found", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandEnv, "trivy image --format json --quiet --insecure "+testImage)
+		c.Setenv(test.ExpectedCommandOutputEnv, `{"SchemaVersion":2,"Results":[{"Target":"debian","Vulnerabilities":[{"VulnerabilityID":"CVE-2022-1234","PkgName":"openssl","InstalledVersion":"1.1.1n","FixedVersion":"1.1.1o","Severity":"HIGH","Title":"openssl: bad things"}]},{"Target":"usr/bin/porter"}]}`)
+
+		vulns, err := NewTrivyScanner(c.Context).Scan(ctx, ref, Options{InsecureRegistry: true})
+		require.NoError(t, err)
+		assert.Equal(t, []Vulnerability{
+			{ID: "CVE-2022-1234", Package: "openssl", InstalledVersion: "1.1.1n", FixedVersion: "1.1.1o", Severity: SeverityHigh, Title: "openssl: bad things"},
+		}, vulns)
+	})
+
+	t.Run("trivy fails", func(t *testing.T) {
+		c := portercontext.NewTestContext(t)
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+		c.Setenv(test.ExpectedCommandErrorEnv, "unable to find the specified image")
+
+		_, err := NewTrivyScanner(c.Context).Scan(ctx, ref, Options{})
+		require.ErrorContains(t, err, "trivy failed")
+		require.ErrorContains(t, err, "unable to find the specified image")
+	})
+}
+
+func TestGrypeScanner_Scan(t *testing.T) {
+	ctx := context.Background()
+	ref := cnab.MustParseOCIReference(testImage)
+
+	c := portercontext.NewTestContext(t)
+	c.Setenv(test.ExpectedCommandEnv, "grype registry:"+testImage+" --output json --quiet")
+	c.Setenv(test.ExpectedCommandOutputEnv, `{"matches":[{"vulnerability":{"id":"CVE-2022-1234","severity":"Critical","description":"bad things","fix":{"versions":["1.1.1o"]}},"artifact":{"name":"openssl","version":"1.1.1n"}},{"vulnerability":{"id":"CVE-2022-5678","severity":"Negligible"},"artifact":{"name":"bash","version":"5.1"}}]}`)
+
+	vulns, err := NewGrypeScanner(c.Context).Scan(ctx, ref, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []Vulnerability{
+		{ID: "CVE-2022-1234", Package: "openssl", InstalledVersion: "1.1.1n", FixedVersion: "1.1.1o", Severity: SeverityCritical, Title: "bad things"},
+		{ID: "CVE-2022-5678", Package: "bash", InstalledVersion: "5.1", Severity: SeverityLow},
+	}, vulns)
+}
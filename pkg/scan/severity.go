@@ -0,0 +1,63 @@
+package scan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity of a vulnerability.
+type Severity string
+
+const (
+	SeverityUnknown  Severity = "UNKNOWN"
+	SeverityLow      Severity = "LOW"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityHigh     Severity = "HIGH"
+	SeverityCritical Severity = "CRITICAL"
+)
+
+// Severities in ascending order.
+var Severities = []Severity{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// ParseSeverity converts a severity, in any case, to a Severity.
+func ParseSeverity(value string) (Severity, error) {
+	s := Severity(strings.ToUpper(value))
+	for _, severity := range Severities {
+		if s == severity {
+			return s, nil
+		}
+	}
+
+	allowed := make([]string, len(Severities))
+	for i, severity := range Severities {
+		allowed[i] = strings.ToLower(string(severity))
+	}
+	return "", fmt.Errorf("invalid severity %s, allowed values are: %s", value, strings.Join(allowed, ", "))
+}
+
+// normalizeSeverity converts the severity reported by a scanner to a Severity,
+// treating severities that are not recognized as unknown.
+func normalizeSeverity(value string) Severity {
+	if strings.EqualFold(value, "negligible") {
+		return SeverityLow
+	}
+	if s, err := ParseSeverity(value); err == nil {
+		return s
+	}
+	return SeverityUnknown
+}
+
+// rank returns the position of the severity in Severities.
+func (s Severity) rank() int {
+	for i, severity := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return 0
+}
+
+// AtLeast indicates if the severity is the same as, or higher than, the threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
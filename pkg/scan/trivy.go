@@ -0,0 +1,71 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/portercontext"
+)
+
+var _ Scanner = &TrivyScanner{}
+
+// TrivyScanner scans images for vulnerabilities with the trivy CLI.
+type TrivyScanner struct {
+	*portercontext.Context
+}
+
+// NewTrivyScanner creates a TrivyScanner.
+func NewTrivyScanner(c *portercontext.Context) *TrivyScanner {
+	return &TrivyScanner{Context: c}
+}
+
+// trivyReport is the subset of the trivy JSON report that is used by Porter.
+type trivyReport struct {
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// Scan the image with trivy image.
+func (s *TrivyScanner) Scan(ctx context.Context, image cnab.OCIReference, opts Options) ([]Vulnerability, error) {
+	args := []string{"image", "--format", "json", "--quiet"}
+	if opts.InsecureRegistry {
+		args = append(args, "--insecure")
+	}
+	args = append(args, image.String())
+
+	output, err := runScanner(ctx, s.Context, nil, "trivy", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var report trivyReport
+	if err = json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("error parsing the trivy report for %s: %w", image, err)
+	}
+
+	var vulns []Vulnerability
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			vulns = append(vulns, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         normalizeSeverity(v.Severity),
+				Title:            v.Title,
+			})
+		}
+	}
+	return vulns, nil
+}
@@ -0,0 +1,156 @@
+package pluginstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/secrets"
+	"get.porter.sh/porter/pkg/secrets/plugins"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mattn/go-isatty"
+	"go.opentelemetry.io/otel/attribute"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+// promptFunc asks the user for the value of the named secret.
+type promptFunc func(name string) (string, error)
+
+// usesChain determines if the secret should be resolved with the secrets chain.
+func (s *Store) usesChain(keyName string) bool {
+	return keyName == secrets.SourceSecret && len(s.Data.SecretsChain) > 0
+}
+
+// resolveChain tries each source in the secrets chain in order, and returns
+// the value from the first source that resolves the secret. A source that
+// fails, or does not respond before its timeout, is skipped so that one
+// unavailable backend does not prevent the secret from being resolved.
+func (s *Store) resolveChain(ctx context.Context, name string) (string, error) {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("secret", name))
+	defer span.EndSpan()
+
+	var bigErr *multierror.Error
+	for _, source := range s.Data.SecretsChain {
+		value, err := s.resolveFromSource(ctx, source, name)
+		if err == nil {
+			span.Debugf("Resolved secret %s from the %s source", name, source.Name)
+			return value, nil
+		}
+
+		span.Debugf("Could not resolve secret %s from the %s source, trying the next source: %s", name, source.Name, err)
+		bigErr = multierror.Append(bigErr, fmt.Errorf("%s: %w", source.Name, err))
+	}
+
+	return "", span.Error(fmt.Errorf("could not resolve secret %s from any source in the secrets chain: %w", name, bigErr.ErrorOrNil()))
+}
+
+// resolveFromSource resolves the secret from a single source in the secrets chain.
+func (s *Store) resolveFromSource(ctx context.Context, source config.SecretsSource, name string) (string, error) {
+	switch source.Name {
+	case config.SecretsSourceEnv:
+		envVar := secretEnvVar(name)
+		value, ok := s.LookupEnv(envVar)
+		if !ok {
+			return "", fmt.Errorf("the %s environment variable is not set", envVar)
+		}
+		return value, nil
+	case config.SecretsSourcePrompt:
+		return s.promptSecret(name)
+	}
+
+	// Do not wait again for a plugin that could not be reached
+	s.chainMu.Lock()
+	unavailableErr := s.unavailableSources[source.Name]
+	s.chainMu.Unlock()
+	if unavailableErr != nil {
+		return "", unavailableErr
+	}
+
+	timeout, err := source.GetTimeout()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := s.pool.GetNamed(ctx, NewSecretsPluginConfig(), source.Name)
+	if err != nil {
+		return "", s.markUnavailable(source, checkSourceTimeout(ctx, timeout, err))
+	}
+
+	store, ok := conn.GetClient().(plugins.SecretsProtocol)
+	if !ok {
+		return "", fmt.Errorf("the interface (%T) exposed by the %s plugin was not plugins.SecretsProtocol", conn.GetClient(), conn)
+	}
+
+	value, err := store.Resolve(ctx, secrets.SourceSecret, name)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", s.markUnavailable(source, checkSourceTimeout(ctx, timeout, err))
+		}
+		return "", err
+	}
+	return value, nil
+}
+
+// markUnavailable remembers that a plugin in the secrets chain could not be
+// reached, so that it is skipped when the remaining secrets are resolved.
+func (s *Store) markUnavailable(source config.SecretsSource, err error) error {
+	s.chainMu.Lock()
+	defer s.chainMu.Unlock()
+
+	if s.unavailableSources == nil {
+		s.unavailableSources = make(map[string]error)
+	}
+	s.unavailableSources[source.Name] = err
+	return err
+}
+
+// checkSourceTimeout replaces the error returned by a source that did not
+// respond in time with a clearer message.
+func checkSourceTimeout(ctx context.Context, timeout time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("the source did not respond within %s", timeout)
+	}
+	return err
+}
+
+// promptSecret asks the user for the value of the secret, when Porter is run
+// from an interactive terminal.
+func (s *Store) promptSecret(name string) (string, error) {
+	if s.prompt != nil {
+		return s.prompt(name)
+	}
+
+	in, ok := s.In.(*os.File)
+	if !ok || !isatty.IsTerminal(in.Fd()) {
+		return "", errors.New("skipped because porter is not running in an interactive terminal")
+	}
+
+	var value string
+	prompt := &survey.Password{Message: fmt.Sprintf("Enter the value of the secret %s", name)}
+	if err := survey.AskOne(prompt, &value, nil); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// secretEnvVar returns the name of the environment variable used by the env
+// source for a secret, for example db-password is read from DB_PASSWORD.
+func secretEnvVar(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
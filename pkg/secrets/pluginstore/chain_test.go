@@ -0,0 +1,76 @@
+package pluginstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/plugins/pluggable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_ResolveChain(t *testing.T) {
+	ctx := context.Background()
+
+	newTestStore := func(t *testing.T, chain ...config.SecretsSource) (*config.TestConfig, *Store) {
+		c := config.NewTestConfig(t)
+		c.Data.SecretsPlugin = []config.SecretsPlugin{
+			{PluginConfig: config.PluginConfig{Name: "vault", PluginSubKey: "missing.vault"}},
+		}
+		c.Data.SecretsChain = chain
+
+		pool := pluggable.NewPluginPool(c.Config)
+		t.Cleanup(func() { pool.Close(ctx) })
+		s := NewStore(c.Config, pool)
+		s.prompt = func(name string) (string, error) {
+			return "", errors.New("skipped because porter is not running in an interactive terminal")
+		}
+		return c, s
+	}
+
+	t.Run("falls back to the next source", func(t *testing.T) {
+		c, s := newTestStore(t,
+			config.SecretsSource{Name: "vault", Timeout: "1s"},
+			config.SecretsSource{Name: config.SecretsSourceEnv})
+		c.Setenv("DB_PASSWORD", "topsecret")
+
+		value, err := s.Resolve(ctx, "secret", "db-password")
+		require.NoError(t, err)
+		assert.Equal(t, "topsecret", value)
+		assert.Contains(t, s.unavailableSources, "vault", "the plugin that could not be started should not be tried again")
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		_, s := newTestStore(t,
+			config.SecretsSource{Name: config.SecretsSourceEnv},
+			config.SecretsSource{Name: config.SecretsSourcePrompt})
+		s.prompt = func(name string) (string, error) {
+			return "entered-" + name, nil
+		}
+
+		value, err := s.Resolve(ctx, "secret", "db-password")
+		require.NoError(t, err)
+		assert.Equal(t, "entered-db-password", value)
+	})
+
+	t.Run("all sources fail", func(t *testing.T) {
+		_, s := newTestStore(t,
+			config.SecretsSource{Name: "vault", Timeout: "1s"},
+			config.SecretsSource{Name: config.SecretsSourceEnv},
+			config.SecretsSource{Name: config.SecretsSourcePrompt})
+
+		_, err := s.Resolve(ctx, "secret", "db-password")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not resolve secret db-password from any source in the secrets chain")
+		assert.Contains(t, err.Error(), "vault: ")
+		assert.Contains(t, err.Error(), "env: the DB_PASSWORD environment variable is not set")
+		assert.Contains(t, err.Error(), "prompt: skipped because porter is not running in an interactive terminal")
+	})
+}
+
+func TestSecretEnvVar(t *testing.T) {
+	assert.Equal(t, "DB_PASSWORD", secretEnvVar("db-password"))
+	assert.Equal(t, "AZURE_CLIENT_SECRET2", secretEnvVar("azure.client_secret2"))
+}
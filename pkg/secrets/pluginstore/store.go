@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/plugins/pluggable"
@@ -17,16 +18,31 @@ var _ plugins.SecretsProtocol = &Store{}
 // plugin based on Porter's config and implements the plugins.SecretsProtocol interface
 // using the backing plugin.
 //
-// Connects just-in-time, but you must call Close to release resources.
+// Connects just-in-time using plugins from the pool, which is responsible for
+// stopping the plugin. The store is safe for concurrent use.
 type Store struct {
 	*config.Config
+	pool *pluggable.PluginPool
+
+	// mu ensures that the plugin is only requested from the pool once.
+	mu     sync.Mutex
 	plugin plugins.SecretsProtocol
-	conn   *pluggable.PluginConnection
+
+	// chainMu protects unavailableSources.
+	chainMu sync.Mutex
+
+	// unavailableSources are the plugins in the secrets chain that could not
+	// be reached, and the reason why, so that they are not waited on again.
+	unavailableSources map[string]error
+
+	// prompt overrides how the prompt source in the secrets chain asks for a secret.
+	prompt promptFunc
 }
 
-func NewStore(c *config.Config) *Store {
+func NewStore(c *config.Config, pool *pluggable.PluginPool) *Store {
 	return &Store{
 		Config: c,
+		pool:   pool,
 	}
 }
 
@@ -52,6 +68,10 @@ func (s *Store) Resolve(ctx context.Context, keyName string, keyValue string) (s
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
 
+	if s.usesChain(keyName) {
+		return s.resolveChain(ctx, keyValue)
+	}
+
 	if err := s.Connect(ctx); err != nil {
 		return "", err
 	}
@@ -86,6 +106,9 @@ If you are just testing out Porter, and are not working with production secrets,
 // The plugin itself is responsible for ensuring it was called.
 // Close is called automatically when the plugin is used by Porter.
 func (s *Store) Connect(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.plugin != nil {
 		return nil
 	}
@@ -95,16 +118,13 @@ func (s *Store) Connect(ctx context.Context) error {
 
 	pluginType := NewSecretsPluginConfig()
 
-	l := pluggable.NewPluginLoader(s.Config)
-	conn, err := l.Load(ctx, pluginType)
+	conn, err := s.pool.Get(ctx, pluginType)
 	if err != nil {
 		return span.Error(err)
 	}
-	s.conn = conn
 
 	store, ok := conn.GetClient().(plugins.SecretsProtocol)
 	if !ok {
-		conn.Close(ctx)
 		return span.Error(fmt.Errorf("the interface (%T) exposed by the %s plugin was not plugins.SecretsProtocol", conn.GetClient(), conn))
 	}
 	s.plugin = store
@@ -112,10 +132,12 @@ func (s *Store) Connect(ctx context.Context) error {
 	return nil
 }
 
+// Close releases the store's connection to the plugin. The plugin keeps
+// running until the pool is closed.
 func (s *Store) Close() error {
-	if s.conn != nil {
-		s.conn.Close(context.Background())
-		s.conn = nil
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin = nil
 	return nil
 }
@@ -57,20 +57,48 @@ type Strategy struct {
 	Value string `json:"-" yaml:"-"`
 }
 
+const (
+	// SourceOutput is the source key for a value that is resolved from the
+	// last value of an output of another installation.
+	SourceOutput = "output"
+
+	// SourceInstallation is the key that identifies the installation that
+	// generated an output, in the form NAMESPACE/NAME or NAME.
+	// It is only used together with SourceOutput.
+	SourceInstallation = "installation"
+)
+
 // Source represents a strategy for loading a value from local host.
 type Source struct {
 	Key   string
 	Value string
+
+	// Installation that generated the output, when the Key is SourceOutput.
+	Installation string
 }
 
 func (s Source) MarshalRaw() interface{} {
 	if s.Key == "" {
 		return nil
 	}
+	if s.Key == SourceOutput {
+		return map[string]interface{}{SourceInstallation: s.Installation, s.Key: s.Value}
+	}
 	return map[string]interface{}{s.Key: s.Value}
 }
 
 func (s *Source) UnmarshalRaw(raw map[string]interface{}) error {
+	s.Installation = ""
+	if installation, ok := raw[SourceInstallation]; ok {
+		if _, ok := raw[SourceOutput]; !ok || len(raw) != 2 {
+			return fmt.Errorf("the %s source may only be specified together with the %s source", SourceInstallation, SourceOutput)
+		}
+		s.Key = SourceOutput
+		s.Value = fmt.Sprintf("%v", raw[SourceOutput])
+		s.Installation = fmt.Sprintf("%v", installation)
+		return nil
+	}
+
 	switch len(raw) {
 	case 0:
 		s.Key = ""
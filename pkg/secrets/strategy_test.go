@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestSet_Merge(t *testing.T) {
@@ -28,3 +30,17 @@ func TestSet_Merge(t *testing.T) {
 	err = set.Merge(Set{"second": "bis"})
 	is.EqualError(err, `ambiguous value resolution: "second" is already present in base sets, cannot merge`)
 }
+
+func TestSource_InstallationOutput(t *testing.T) {
+	var s Strategy
+	err := yaml.Unmarshal([]byte("name: vnet\nsource:\n  installation: infra/network\n  output: vnet_id\n"), &s)
+	require.NoError(t, err)
+	assert.Equal(t, Source{Key: SourceOutput, Value: "vnet_id", Installation: "infra/network"}, s.Source)
+
+	data, err := yaml.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, "name: vnet\nsource:\n    installation: infra/network\n    output: vnet_id\n", string(data))
+
+	err = yaml.Unmarshal([]byte("name: vnet\nsource:\n  installation: infra/network\n  value: vnet_id\n"), &s)
+	require.EqualError(t, err, "the installation source may only be specified together with the output source")
+}
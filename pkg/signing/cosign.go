@@ -0,0 +1,61 @@
+package signing
+
+import (
+	"context"
+	"errors"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+)
+
+var _ Signer = &CosignSigner{}
+
+// CosignSigner signs artifacts and verifies their signatures with the cosign CLI.
+type CosignSigner struct {
+	*portercontext.Context
+	config config.SigningConfig
+}
+
+// NewCosignSigner creates a signer that uses the cosign CLI.
+func NewCosignSigner(c *portercontext.Context, cfg config.SigningConfig) *CosignSigner {
+	return &CosignSigner{
+		Context: c,
+		config:  cfg,
+	}
+}
+
+// Sign the artifact with the configured key, or with keyless signing when a key is not configured.
+func (s *CosignSigner) Sign(ctx context.Context, ref cnab.OCIReference, opts Options) error {
+	args := []string{"sign", "--yes"}
+	if s.config.Key != "" {
+		args = append(args, "--key", s.config.Key)
+	}
+	if opts.InsecureRegistry {
+		args = append(args, "--allow-insecure-registry")
+	}
+	args = append(args, ref.String())
+
+	return runSigner(ctx, s.Context, "cosign", args...)
+}
+
+// Verify the signature of the artifact with the configured public key, or the
+// expected certificate identity for keyless signatures.
+func (s *CosignSigner) Verify(ctx context.Context, ref cnab.OCIReference, opts Options) error {
+	args := []string{"verify"}
+	if s.config.VerifyKey != "" {
+		args = append(args, "--key", s.config.VerifyKey)
+	} else if s.config.CertificateIdentity != "" && s.config.CertificateOIDCIssuer != "" {
+		args = append(args,
+			"--certificate-identity", s.config.CertificateIdentity,
+			"--certificate-oidc-issuer", s.config.CertificateOIDCIssuer)
+	} else {
+		return errors.New("signing.verify-key, or signing.certificate-identity and signing.certificate-oidc-issuer, must be set in the configuration file to verify signatures with cosign")
+	}
+	if opts.InsecureRegistry {
+		args = append(args, "--allow-insecure-registry")
+	}
+	args = append(args, ref.String())
+
+	return runSigner(ctx, s.Context, "cosign", args...)
+}
@@ -0,0 +1,4 @@
+// Package signing signs bundles and their invocation images when they are
+// published, and verifies their signatures before they are used. Signing is
+// delegated to pluggable signers, such as the cosign and notation CLIs.
+package signing
@@ -0,0 +1,50 @@
+package signing
+
+import (
+	"context"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+)
+
+var _ Signer = &NotationSigner{}
+
+// NotationSigner signs artifacts and verifies their signatures with the notation CLI.
+type NotationSigner struct {
+	*portercontext.Context
+	config config.SigningConfig
+}
+
+// NewNotationSigner creates a signer that uses the notation CLI.
+func NewNotationSigner(c *portercontext.Context, cfg config.SigningConfig) *NotationSigner {
+	return &NotationSigner{
+		Context: c,
+		config:  cfg,
+	}
+}
+
+// Sign the artifact with the configured key, or notation's default key when a key is not configured.
+func (s *NotationSigner) Sign(ctx context.Context, ref cnab.OCIReference, opts Options) error {
+	args := []string{"sign"}
+	if s.config.Key != "" {
+		args = append(args, "--key", s.config.Key)
+	}
+	if opts.InsecureRegistry {
+		args = append(args, "--insecure-registry")
+	}
+	args = append(args, ref.String())
+
+	return runSigner(ctx, s.Context, "notation", args...)
+}
+
+// Verify the signature of the artifact using notation's trust policy.
+func (s *NotationSigner) Verify(ctx context.Context, ref cnab.OCIReference, opts Options) error {
+	args := []string{"verify"}
+	if opts.InsecureRegistry {
+		args = append(args, "--insecure-registry")
+	}
+	args = append(args, ref.String())
+
+	return runSigner(ctx, s.Context, "notation", args...)
+}
@@ -0,0 +1,63 @@
+package signing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Signer signs OCI artifacts, such as bundles and invocation images, and
+// verifies their signatures.
+type Signer interface {
+	// Sign the artifact. The reference should include the digest of the artifact.
+	Sign(ctx context.Context, ref cnab.OCIReference, opts Options) error
+
+	// Verify that the artifact has a trusted signature.
+	// The reference should include the digest of the artifact.
+	Verify(ctx context.Context, ref cnab.OCIReference, opts Options) error
+}
+
+// Options for signing an artifact or verifying its signature.
+type Options struct {
+	// InsecureRegistry allows connecting to an unsecured registry or one without verifiable certificates.
+	InsecureRegistry bool
+}
+
+// NewSigner creates the signer selected by the signing configuration.
+func NewSigner(c *config.Config) (Signer, error) {
+	cfg := c.Data.Signing
+	switch cfg.GetSigner() {
+	case config.SignerCosign:
+		return NewCosignSigner(c.Context, cfg), nil
+	case config.SignerNotation:
+		return NewNotationSigner(c.Context, cfg), nil
+	default:
+		return nil, fmt.Errorf("invalid signing.signer value %s, allowed values are: %s, %s", cfg.Signer, config.SignerCosign, config.SignerNotation)
+	}
+}
+
+// runSigner executes a signer CLI, including its output in the returned error when it fails.
+func runSigner(ctx context.Context, c *portercontext.Context, name string, args ...string) error {
+	ctx, log := tracing.StartSpan(ctx, attribute.String("signer", name), attribute.String("args", strings.Join(args, " ")))
+	defer log.EndSpan()
+
+	cmd := c.NewCommand(ctx, name, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return log.Errorf("could not run %s, make sure that it is installed and on the PATH: %w", name, err)
+		}
+		return log.Errorf("%s %s failed: %w\n%s", name, args[0], err, strings.TrimSpace(string(output)))
+	}
+
+	log.Debug(string(output))
+	return nil
+}
@@ -0,0 +1,116 @@
+package signing
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRef = "example.com/mybuns@sha256:6b5a28ccbb76f12ce771a23757880c6083234255c5ba191fca1c5db1f71c1687"
+
+func TestNewSigner(t *testing.T) {
+	testcases := []struct {
+		signer   string
+		wantType Signer
+		wantErr  string
+	}{
+		{signer: "", wantType: &CosignSigner{}},
+		{signer: config.SignerCosign, wantType: &CosignSigner{}},
+		{signer: config.SignerNotation, wantType: &NotationSigner{}},
+		{signer: "gpg", wantErr: "invalid signing.signer value gpg"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.signer, func(t *testing.T) {
+			c := config.NewTestConfig(t)
+			c.Data.Signing.Signer = tc.signer
+
+			signer, err := NewSigner(c.Config)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tc.wantType, signer)
+		})
+	}
+}
+
+func TestCosignSigner(t *testing.T) {
+	ctx := context.Background()
+	ref := cnab.MustParseOCIReference(testRef)
+
+	testcases := []struct {
+		name    string
+		config  config.SigningConfig
+		opts    Options
+		verify  bool
+		wantCmd string
+		wantErr string
+	}{
+		{name: "sign with key", config: config.SigningConfig{Key: "cosign.key"},
+			wantCmd: "cosign sign --yes --key cosign.key " + testRef},
+		{name: "keyless sign on insecure registry", opts: Options{InsecureRegistry: true},
+			wantCmd: "cosign sign --yes --allow-insecure-registry " + testRef},
+		{name: "verify with key", config: config.SigningConfig{VerifyKey: "cosign.pub"}, verify: true,
+			wantCmd: "cosign verify --key cosign.pub " + testRef},
+		{name: "verify keyless", config: config.SigningConfig{CertificateIdentity: "me@example.com", CertificateOIDCIssuer: "https://accounts.example.com"}, verify: true,
+			wantCmd: "cosign verify --certificate-identity me@example.com --certificate-oidc-issuer https://accounts.example.com " + testRef},
+		{name: "verify without key", verify: true,
+			wantErr: "signing.verify-key, or signing.certificate-identity and signing.certificate-oidc-issuer, must be set"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := config.NewTestConfig(t)
+			c.Setenv(test.ExpectedCommandEnv, tc.wantCmd)
+			s := NewCosignSigner(c.Context, tc.config)
+
+			var err error
+			if tc.verify {
+				err = s.Verify(ctx, ref, tc.opts)
+			} else {
+				err = s.Sign(ctx, ref, tc.opts)
+			}
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNotationSigner(t *testing.T) {
+	ctx := context.Background()
+	ref := cnab.MustParseOCIReference(testRef)
+
+	t.Run("sign", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		c.Setenv(test.ExpectedCommandEnv, "notation sign --key release "+testRef)
+		s := NewNotationSigner(c.Context, config.SigningConfig{Key: "release"})
+		require.NoError(t, s.Sign(ctx, ref, Options{}))
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		c.Setenv(test.ExpectedCommandEnv, "notation verify --insecure-registry "+testRef)
+		s := NewNotationSigner(c.Context, config.SigningConfig{})
+		require.NoError(t, s.Verify(ctx, ref, Options{InsecureRegistry: true}))
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		c := config.NewTestConfig(t)
+		c.Setenv(test.ExpectedCommandErrorEnv, "signature verification failed")
+		c.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+		s := NewNotationSigner(c.Context, config.SigningConfig{})
+		err := s.Verify(ctx, ref, Options{})
+		require.ErrorContains(t, err, "notation verify failed")
+		require.ErrorContains(t, err, "signature verification failed")
+	})
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+)
+
+const (
+	// AuditActionCreate is recorded when a resource is created.
+	AuditActionCreate = "create"
+
+	// AuditActionUpdate is recorded when a resource is modified.
+	AuditActionUpdate = "update"
+
+	// AuditActionDelete is recorded when a resource is deleted.
+	AuditActionDelete = "delete"
+
+	// AuditResourceInstallation is the resource type of installations.
+	AuditResourceInstallation = "installation"
+
+	// AuditResourceCredentialSet is the resource type of credential sets.
+	AuditResourceCredentialSet = "credentialset"
+
+	// AuditResourceParameterSet is the resource type of parameter sets.
+	AuditResourceParameterSet = "parameterset"
+)
+
+var _ Document = AuditEvent{}
+
+// AuditEvent records who modified a resource, when and how.
+type AuditEvent struct {
+	// ID of the event.
+	ID string `json:"_id" yaml:"id" toml:"id"`
+
+	// Timestamp when the resource was modified.
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp" toml:"timestamp"`
+
+	// Identity of the user that modified the resource.
+	Identity string `json:"identity" yaml:"identity" toml:"identity"`
+
+	// Command that modified the resource, for example porter credentials apply.
+	// The arguments of the command are not recorded because they may contain sensitive data.
+	Command string `json:"command,omitempty" yaml:"command,omitempty" toml:"command,omitempty"`
+
+	// Action performed on the resource: create, update or delete.
+	Action string `json:"action" yaml:"action" toml:"action"`
+
+	// ResourceType of the modified resource: installation, credentialset or parameterset.
+	ResourceType string `json:"resourceType" yaml:"resourceType" toml:"resourceType"`
+
+	// Namespace of the modified resource.
+	Namespace string `json:"namespace" yaml:"namespace" toml:"namespace"`
+
+	// Name of the modified resource.
+	Name string `json:"name" yaml:"name" toml:"name"`
+
+	// Changes are the fields of the resource that were modified by an update.
+	// Only the names of the fields are recorded, not their values.
+	Changes []string `json:"changes,omitempty" yaml:"changes,omitempty" toml:"changes,omitempty"`
+}
+
+// NewAuditEvent creates an audit event for an action on a resource.
+func NewAuditEvent(action string, resourceType string, namespace string, name string) AuditEvent {
+	return AuditEvent{
+		ID:           cnab.NewULID(),
+		Timestamp:    time.Now(),
+		Action:       action,
+		ResourceType: resourceType,
+		Namespace:    namespace,
+		Name:         name,
+	}
+}
+
+func (e AuditEvent) DefaultDocumentFilter() map[string]interface{} {
+	return map[string]interface{}{"_id": e.ID}
+}
+
+// Resource returns the modified resource, for example credentialset/dev/mycreds.
+func (e AuditEvent) Resource() string {
+	if e.Namespace == "" {
+		return e.ResourceType + "/" + e.Name
+	}
+	return e.ResourceType + "/" + e.Namespace + "/" + e.Name
+}
+
+// ParseAuditResourceType converts the type of resource from a command, such as
+// creds or installations, to the resource type recorded in audit events.
+func ParseAuditResourceType(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "installation", "installations", "inst":
+		return AuditResourceInstallation, nil
+	case "credentialset", "credentialsets", "credentials", "credential", "creds", "cred", "cs":
+		return AuditResourceCredentialSet, nil
+	case "parameterset", "parametersets", "parameters", "parameter", "params", "param", "ps":
+		return AuditResourceParameterSet, nil
+	default:
+		return "", fmt.Errorf("invalid resource type %s, allowed values are: installation, credentialset, parameterset", value)
+	}
+}
+
+// auditResourceType returns the type of the resources stored in a collection,
+// or an empty string when changes to the collection are not audited.
+func auditResourceType(collection string) string {
+	switch collection {
+	case CollectionInstallations:
+		return AuditResourceInstallation
+	case CollectionCredentials:
+		return AuditResourceCredentialSet
+	case CollectionParameters:
+		return AuditResourceParameterSet
+	default:
+		return ""
+	}
+}
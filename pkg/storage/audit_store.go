@@ -0,0 +1,280 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CollectionAuditEvents stores the audit events recorded when resources are modified.
+const CollectionAuditEvents = "audit"
+
+var (
+	_ AuditProvider = &AuditStore{}
+	_ Store         = &AuditingStore{}
+)
+
+// AuditProvider is Porter's interface for querying audit events.
+type AuditProvider interface {
+	// ListAuditEvents returns the audit events that match the query, most recent first.
+	ListAuditEvents(ctx context.Context, query AuditQuery) ([]AuditEvent, error)
+}
+
+// AuditQuery selects which audit events are listed.
+type AuditQuery struct {
+	// Namespace of the modified resources. Use * to query all namespaces.
+	Namespace string
+
+	// ResourceType of the modified resources. All resource types are returned when empty.
+	ResourceType string
+
+	// Name of the modified resource. All resources are returned when empty.
+	Name string
+
+	// Skip the specified number of events.
+	Skip int64
+
+	// Limit the number of events returned.
+	Limit int64
+}
+
+type contextKeyCommand struct{}
+
+// WithCommand returns a context that records the command being executed,
+// for example porter installation apply, in audit events.
+func WithCommand(ctx context.Context, command string) context.Context {
+	return context.WithValue(ctx, contextKeyCommand{}, command)
+}
+
+func getCommand(ctx context.Context) string {
+	command, _ := ctx.Value(contextKeyCommand{}).(string)
+	return command
+}
+
+// AuditStore provides access to the audit events recorded by AuditingStore.
+type AuditStore struct {
+	Documents Store
+}
+
+func NewAuditStore(storage Store) *AuditStore {
+	return &AuditStore{
+		Documents: storage,
+	}
+}
+
+// AuditingStore wraps a Store and records an audit event each time an
+// installation, credential set or parameter set is created, updated or deleted.
+type AuditingStore struct {
+	Store
+	config *config.Config
+}
+
+// NewAuditingStore wraps a Store, recording audit events when resources are modified.
+func NewAuditingStore(c *config.Config, store Store) *AuditingStore {
+	return &AuditingStore{config: c, Store: store}
+}
+
+// EnsureAuditIndices creates indices on the audit collection.
+func EnsureAuditIndices(ctx context.Context, store Store) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	span.Debug("Initializing audit collection indices")
+	indices := EnsureIndexOptions{
+		Indices: []Index{
+			// query the events for a resource, most recent first
+			{Collection: CollectionAuditEvents, Keys: []string{"namespace", "resourceType", "name", "-_id"}},
+		},
+	}
+	err := store.EnsureIndex(ctx, indices)
+	return span.Error(err)
+}
+
+func (s AuditStore) ListAuditEvents(ctx context.Context, query AuditQuery) ([]AuditEvent, error) {
+	filter := bson.M{}
+	if query.Namespace != "*" {
+		filter["namespace"] = query.Namespace
+	}
+	if query.ResourceType != "" {
+		filter["resourceType"] = query.ResourceType
+	}
+	if query.Name != "" {
+		filter["name"] = query.Name
+	}
+	opts := FindOptions{
+		Sort:   []string{"-_id"},
+		Filter: filter,
+		Skip:   query.Skip,
+		Limit:  query.Limit,
+	}
+
+	var out []AuditEvent
+	err := s.Documents.Find(ctx, CollectionAuditEvents, opts, &out)
+	return out, err
+}
+
+func (s *AuditingStore) Insert(ctx context.Context, collection string, opts InsertOptions) error {
+	resourceType := auditResourceType(collection)
+	if resourceType == "" {
+		return s.Store.Insert(ctx, collection, opts)
+	}
+
+	if err := s.Store.Insert(ctx, collection, opts); err != nil {
+		return err
+	}
+
+	events := make([]AuditEvent, 0, len(opts.Documents))
+	for _, doc := range opts.Documents {
+		fields, err := toAuditFields(doc)
+		if err != nil {
+			return err
+		}
+		events = append(events, s.newEvent(ctx, AuditActionCreate, resourceType, fields, nil))
+	}
+	return s.record(ctx, events...)
+}
+
+func (s *AuditingStore) Update(ctx context.Context, collection string, opts UpdateOptions) error {
+	resourceType := auditResourceType(collection)
+	if resourceType == "" {
+		return s.Store.Update(ctx, collection, opts)
+	}
+
+	pluginOpts, err := opts.ToPluginOptions(collection)
+	if err != nil {
+		return err
+	}
+	var existing []map[string]interface{}
+	if err = s.Store.Find(ctx, collection, FindOptions{Filter: pluginOpts.Filter, Limit: 1}, &existing); err != nil {
+		return err
+	}
+
+	if err = s.Store.Update(ctx, collection, opts); err != nil {
+		return err
+	}
+
+	fields, err := toAuditFields(opts.Document)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		// The document was created by an upsert
+		return s.record(ctx, s.newEvent(ctx, AuditActionCreate, resourceType, fields, nil))
+	}
+
+	changes := diffAuditFields(existing[0], fields)
+	if len(changes) == 0 {
+		return nil
+	}
+	return s.record(ctx, s.newEvent(ctx, AuditActionUpdate, resourceType, fields, changes))
+}
+
+func (s *AuditingStore) Remove(ctx context.Context, collection string, opts RemoveOptions) error {
+	resourceType := auditResourceType(collection)
+	if resourceType == "" {
+		return s.Store.Remove(ctx, collection, opts)
+	}
+
+	findOpts := FindOptions{
+		Filter: opts.ToPluginOptions(collection).Filter,
+		Select: bson.D{{Key: "namespace", Value: 1}, {Key: "name", Value: 1}},
+	}
+	if !opts.All {
+		findOpts.Limit = 1
+	}
+	var removed []map[string]interface{}
+	if err := s.Store.Find(ctx, collection, findOpts, &removed); err != nil {
+		return err
+	}
+
+	if err := s.Store.Remove(ctx, collection, opts); err != nil {
+		return err
+	}
+
+	events := make([]AuditEvent, 0, len(removed))
+	for _, doc := range removed {
+		events = append(events, s.newEvent(ctx, AuditActionDelete, resourceType, doc, nil))
+	}
+	return s.record(ctx, events...)
+}
+
+func (s *AuditingStore) newEvent(ctx context.Context, action string, resourceType string, doc map[string]interface{}, changes []string) AuditEvent {
+	namespace, _ := doc["namespace"].(string)
+	name, _ := doc["name"].(string)
+	event := NewAuditEvent(action, resourceType, namespace, name)
+	event.Identity = s.config.Data.Access.GetIdentity()
+	event.Command = getCommand(ctx)
+	event.Changes = changes
+	return event
+}
+
+// record saves audit events after the resources were modified.
+func (s *AuditingStore) record(ctx context.Context, events ...AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(events))
+	for i, event := range events {
+		docs[i] = event
+	}
+	if err := s.Store.Insert(ctx, CollectionAuditEvents, InsertOptions{Documents: docs}); err != nil {
+		return fmt.Errorf("the %s was saved but the audit event could not be recorded: %w", events[0].ResourceType, err)
+	}
+	return nil
+}
+
+// toAuditFields converts a document to its json representation, so that it
+// can be compared with the documents returned by Find.
+func toAuditFields(doc interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize the document for the audit log: %w", err)
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("could not serialize the document for the audit log: %w", err)
+	}
+	return fields, nil
+}
+
+// diffAuditFields returns the names of the top level fields that are different
+// between two versions of a document.
+func diffAuditFields(before map[string]interface{}, after map[string]interface{}) []string {
+	var changes []string
+	for key, value := range after {
+		if key == "_id" {
+			continue
+		}
+		if !reflect.DeepEqual(normalizeAuditValue(before[key]), normalizeAuditValue(value)) {
+			changes = append(changes, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok && key != "_id" {
+			changes = append(changes, key)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// normalizeAuditValue round trips a value through json so that equivalent
+// values, such as int and float64 numbers, compare as equal.
+func normalizeAuditValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err = json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditingStore(t *testing.T) {
+	ctx := WithCommand(context.Background(), "porter credentials apply")
+	tc := config.NewTestConfig(t)
+	tc.Data.Access.Identity = "alice"
+	testStore := NewTestStore(tc)
+	defer testStore.Close()
+
+	s := NewCredentialStore(NewAuditingStore(tc.Config, testStore), secrets.NewTestSecretsProvider())
+	audit := NewAuditStore(testStore)
+
+	cs := NewCredentialSet("dev", "mycreds")
+	require.NoError(t, s.InsertCredentialSet(ctx, cs))
+
+	require.NoError(t, s.UpsertCredentialSet(ctx, cs), "saving a credential set without changes should not record an event")
+
+	cs.Labels = map[string]string{"team": "red"}
+	require.NoError(t, s.UpsertCredentialSet(ctx, cs))
+
+	require.NoError(t, s.RemoveCredentialSet(ctx, "dev", "mycreds"))
+
+	// Changes to other resources are not returned when filtering by resource
+	require.NoError(t, s.InsertCredentialSet(ctx, NewCredentialSet("dev", "othercreds")))
+
+	events, err := audit.ListAuditEvents(ctx, AuditQuery{Namespace: "dev", ResourceType: AuditResourceCredentialSet, Name: "mycreds"})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	// The most recent event is returned first
+	assert.Equal(t, AuditActionDelete, events[0].Action)
+	assert.Equal(t, AuditActionUpdate, events[1].Action)
+	assert.Equal(t, []string{"labels"}, events[1].Changes)
+	assert.Equal(t, AuditActionCreate, events[2].Action)
+	for _, e := range events {
+		assert.Equal(t, "alice", e.Identity)
+		assert.Equal(t, "porter credentials apply", e.Command)
+		assert.Equal(t, "credentialset/dev/mycreds", e.Resource())
+	}
+
+	events, err = audit.ListAuditEvents(ctx, AuditQuery{Namespace: "*"})
+	require.NoError(t, err)
+	assert.Len(t, events, 4)
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuditResourceType(t *testing.T) {
+	testcases := map[string]string{
+		"installation":  AuditResourceInstallation,
+		"installations": AuditResourceInstallation,
+		"creds":         AuditResourceCredentialSet,
+		"CredentialSet": AuditResourceCredentialSet,
+		"params":        AuditResourceParameterSet,
+		"parameters":    AuditResourceParameterSet,
+	}
+	for value, want := range testcases {
+		t.Run(value, func(t *testing.T) {
+			got, err := ParseAuditResourceType(value)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	_, err := ParseAuditResourceType("bundles")
+	require.EqualError(t, err, "invalid resource type bundles, allowed values are: installation, credentialset, parameterset")
+}
+
+func TestAuditEvent_Resource(t *testing.T) {
+	e := NewAuditEvent(AuditActionCreate, AuditResourceCredentialSet, "", "mycreds")
+	assert.Equal(t, "credentialset/mycreds", e.Resource())
+
+	e.Namespace = "dev"
+	assert.Equal(t, "credentialset/dev/mycreds", e.Resource())
+}
@@ -0,0 +1,453 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"get.porter.sh/porter/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AuthorizationRequest describes an action that an identity is performing on
+// the resources in a namespace.
+type AuthorizationRequest struct {
+	// Identity of the user running Porter.
+	Identity string `json:"identity"`
+
+	// Action performed on the resources: read, write or delete.
+	Action string `json:"action"`
+
+	// Collection of the resources, such as installations or credentials.
+	Collection string `json:"collection"`
+
+	// Namespace of the resources. The global namespace is an empty string.
+	Namespace string `json:"namespace"`
+}
+
+// Authorizer decides if an identity may access the resources in a namespace.
+// Implement this interface to enforce access control on the data managed by Porter.
+type Authorizer interface {
+	// Authorize returns ErrAccessDenied when the request is not allowed.
+	Authorize(ctx context.Context, req AuthorizationRequest) error
+}
+
+// ErrAccessDenied indicates that an identity is not allowed to access the resources in a namespace.
+// You can test for this error using errors.Is(err, storage.ErrAccessDenied{})
+type ErrAccessDenied struct {
+	AuthorizationRequest
+
+	// Reason that access was denied, when it is known.
+	Reason string
+}
+
+func (e ErrAccessDenied) Error() string {
+	namespace := "namespace " + e.Namespace
+	if e.Namespace == "" {
+		namespace = "the global namespace"
+	}
+	msg := fmt.Sprintf("%s is not allowed to %s %s in %s", e.Identity, e.Action, resourceName(e.Collection), namespace)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+func (e ErrAccessDenied) Is(err error) bool {
+	_, ok := err.(ErrAccessDenied)
+	return ok
+}
+
+var _ Authorizer = localAuthorizer{}
+
+// localAuthorizer allows access with the policies defined in the configuration file.
+type localAuthorizer struct {
+	policies []config.AccessPolicy
+}
+
+// NewLocalAuthorizer creates an Authorizer that allows the namespaces and actions
+// granted to an identity by the specified policies, and denies everything else.
+func NewLocalAuthorizer(policies []config.AccessPolicy) Authorizer {
+	return localAuthorizer{policies: policies}
+}
+
+func (a localAuthorizer) Authorize(_ context.Context, req AuthorizationRequest) error {
+	for _, policy := range a.policies {
+		if policy.Identity != "*" && policy.Identity != req.Identity {
+			continue
+		}
+		if len(policy.Actions) > 0 && !containsString(policy.Actions, req.Action) {
+			continue
+		}
+		for _, pattern := range policy.Namespaces {
+			if pattern == req.Namespace {
+				return nil
+			}
+			// Namespaces cannot contain /, so the namespace is matched as a single path segment
+			if matched, _ := path.Match(pattern, req.Namespace); matched {
+				return nil
+			}
+		}
+	}
+	return ErrAccessDenied{AuthorizationRequest: req}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var _ Authorizer = &webhookAuthorizer{}
+
+// webhookAuthorizer authorizes access by calling an external service, caching
+// its decisions for the lifetime of the command.
+type webhookAuthorizer struct {
+	url    string
+	token  string
+	client *http.Client
+
+	mu        sync.Mutex
+	decisions map[AuthorizationRequest]error
+}
+
+// NewWebhookAuthorizer creates an Authorizer that sends each request as json
+// to the specified url. Access is allowed when the service responds with a
+// 2xx status code, and denied when it responds with 401 or 403.
+func NewWebhookAuthorizer(cfg config.AccessWebhookConfig) Authorizer {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 // default to 10 seconds
+	}
+	return &webhookAuthorizer{
+		url:       cfg.URL,
+		token:     cfg.Token,
+		client:    &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		decisions: make(map[AuthorizationRequest]error),
+	}
+}
+
+func (a *webhookAuthorizer) Authorize(ctx context.Context, req AuthorizationRequest) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err, ok := a.decisions[req]; ok {
+		return err
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("error serializing the authorization request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating the authorization request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		// Do not cache the failure so that the request is retried
+		return fmt.Errorf("could not connect to the authorization service at %s: %w", a.url, err)
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		err = nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		err = ErrAccessDenied{AuthorizationRequest: req, Reason: strings.TrimSpace(string(msg))}
+	default:
+		return fmt.Errorf("the authorization service at %s returned %s: %s", a.url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	a.decisions[req] = err
+	return err
+}
+
+var _ Store = &AuthorizingStore{}
+
+// AuthorizingStore wraps a Store and authorizes every read, write and delete of
+// installations, their runs, credential sets and parameter sets, with the
+// access policies from the Porter configuration file. Documents in namespaces
+// that the identity cannot read are excluded from the results of a query.
+type AuthorizingStore struct {
+	Store
+	config *config.Config
+
+	// Authorizer overrides the authorizer selected by the configuration file.
+	Authorizer Authorizer
+
+	// Identity overrides the identity from the configuration file.
+	Identity string
+}
+
+// NewAuthorizingStore wraps a Store, authorizing access to the resources in each namespace.
+func NewAuthorizingStore(c *config.Config, store Store) *AuthorizingStore {
+	return &AuthorizingStore{config: c, Store: store}
+}
+
+// IsEnabled determines if access to namespaces is authorized.
+func (s *AuthorizingStore) IsEnabled() bool {
+	return s.Authorizer != nil || s.config.Data.Access.IsEnabled()
+}
+
+func (s *AuthorizingStore) getAuthorizer() (Authorizer, error) {
+	if s.Authorizer != nil {
+		return s.Authorizer, nil
+	}
+
+	cfg := s.config.Data.Access
+	switch cfg.GetProvider() {
+	case config.AccessProviderLocal:
+		s.Authorizer = NewLocalAuthorizer(cfg.Policies)
+		return s.Authorizer, nil
+	case config.AccessProviderWebhook:
+		s.Authorizer = NewWebhookAuthorizer(cfg.Webhook)
+		return s.Authorizer, nil
+	default:
+		return nil, fmt.Errorf("unsupported access provider %s, available values are: %s, %s", cfg.Provider, config.AccessProviderLocal, config.AccessProviderWebhook)
+	}
+}
+
+// isAuthorized determines if access to the documents in a collection is authorized.
+func isAuthorized(collection string) bool {
+	switch collection {
+	case CollectionInstallations, CollectionRuns, CollectionResults, CollectionOutputs, CollectionLogs,
+		CollectionCredentials, CollectionParameters, CollectionAuditEvents:
+		return true
+	default:
+		return false
+	}
+}
+
+// authorize an action on the documents in the specified namespaces.
+func (s *AuthorizingStore) authorize(ctx context.Context, action string, collection string, namespaces ...string) error {
+	authorizer, err := s.getAuthorizer()
+	if err != nil {
+		return err
+	}
+
+	identity := s.Identity
+	if identity == "" {
+		identity = s.config.Data.Access.GetIdentity()
+	}
+	for _, namespace := range namespaces {
+		req := AuthorizationRequest{Identity: identity, Action: action, Collection: collection, Namespace: namespace}
+		if err = authorizer.Authorize(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findNamespaces returns the namespaces of the documents that match the filter.
+func (s *AuthorizingStore) findNamespaces(ctx context.Context, collection string, filter bson.M) ([]string, error) {
+	// Avoid a query when the filter selects a single namespace
+	if namespace, ok := filter["namespace"].(string); ok {
+		return []string{namespace}, nil
+	}
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+	var groups []struct {
+		Namespace string `json:"_id"`
+	}
+	opts := AggregateOptions{Pipeline: []bson.D{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$namespace"}}}},
+	}}
+	if err := s.Store.Aggregate(ctx, collection, opts, &groups); err != nil {
+		return nil, fmt.Errorf("could not determine the namespaces of the %s to authorize: %w", resourceName(collection), err)
+	}
+
+	namespaces := make([]string, len(groups))
+	for i, group := range groups {
+		namespaces[i] = group.Namespace
+	}
+	return namespaces, nil
+}
+
+// authorizeMatches authorizes an action on all the documents that match the filter.
+func (s *AuthorizingStore) authorizeMatches(ctx context.Context, action string, collection string, filter bson.M) error {
+	namespaces, err := s.findNamespaces(ctx, collection, filter)
+	if err != nil {
+		return err
+	}
+	return s.authorize(ctx, action, collection, namespaces...)
+}
+
+// restrictRead returns a filter that excludes the documents in namespaces that
+// the identity cannot read. When the filter selects a single namespace that
+// cannot be read, ErrAccessDenied is returned instead.
+func (s *AuthorizingStore) restrictRead(ctx context.Context, collection string, filter bson.M) (bson.M, error) {
+	if _, ok := filter["namespace"].(string); ok {
+		return filter, s.authorizeMatches(ctx, config.AccessActionRead, collection, filter)
+	}
+
+	namespaces, err := s.findNamespaces(ctx, collection, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var denied []string
+	for _, namespace := range namespaces {
+		err = s.authorize(ctx, config.AccessActionRead, collection, namespace)
+		if errors.Is(err, ErrAccessDenied{}) {
+			denied = append(denied, namespace)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if len(denied) == 0 {
+		return filter, nil
+	}
+
+	restriction := bson.M{"namespace": bson.M{"$nin": denied}}
+	if len(filter) == 0 {
+		return restriction, nil
+	}
+	return bson.M{"$and": []interface{}{filter, restriction}}, nil
+}
+
+func (s *AuthorizingStore) Aggregate(ctx context.Context, collection string, opts AggregateOptions, out interface{}) error {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.Aggregate(ctx, collection, opts, out)
+	}
+
+	// Only consider the namespaces of the documents selected by the pipeline
+	var filter bson.M
+	if len(opts.Pipeline) > 0 && len(opts.Pipeline[0]) == 1 && opts.Pipeline[0][0].Key == "$match" {
+		filter, _ = opts.Pipeline[0][0].Value.(bson.M)
+	}
+	restriction, err := s.restrictRead(ctx, collection, filter)
+	if err != nil {
+		return err
+	}
+
+	pipeline := make([]bson.D, 0, len(opts.Pipeline)+1)
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: restriction}})
+	opts.Pipeline = append(pipeline, opts.Pipeline...)
+	return s.Store.Aggregate(ctx, collection, opts, out)
+}
+
+func (s *AuthorizingStore) Count(ctx context.Context, collection string, opts CountOptions) (int64, error) {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.Count(ctx, collection, opts)
+	}
+
+	filter, err := s.restrictRead(ctx, collection, opts.Filter)
+	if err != nil {
+		return 0, err
+	}
+	opts.Filter = filter
+	return s.Store.Count(ctx, collection, opts)
+}
+
+func (s *AuthorizingStore) Find(ctx context.Context, collection string, opts FindOptions, out interface{}) error {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.Find(ctx, collection, opts, out)
+	}
+
+	filter, err := s.restrictRead(ctx, collection, opts.Filter)
+	if err != nil {
+		return err
+	}
+	opts.Filter = filter
+	return s.Store.Find(ctx, collection, opts, out)
+}
+
+func (s *AuthorizingStore) FindOne(ctx context.Context, collection string, opts FindOptions, out interface{}) error {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.FindOne(ctx, collection, opts, out)
+	}
+
+	filter, err := s.restrictRead(ctx, collection, opts.Filter)
+	if err != nil {
+		return err
+	}
+	opts.Filter = filter
+	return s.Store.FindOne(ctx, collection, opts, out)
+}
+
+func (s *AuthorizingStore) Get(ctx context.Context, collection string, opts GetOptions, out interface{}) error {
+	return s.FindOne(ctx, collection, opts.ToFindOptions(), out)
+}
+
+func (s *AuthorizingStore) Insert(ctx context.Context, collection string, opts InsertOptions) error {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.Insert(ctx, collection, opts)
+	}
+
+	pluginOpts, err := opts.ToPluginOptions(collection)
+	if err != nil {
+		return err
+	}
+	namespaces := make([]string, 0, len(pluginOpts.Documents))
+	for _, doc := range pluginOpts.Documents {
+		namespace, _ := doc["namespace"].(string)
+		namespaces = append(namespaces, namespace)
+	}
+	if err = s.authorize(ctx, config.AccessActionWrite, collection, namespaces...); err != nil {
+		return err
+	}
+	return s.Store.Insert(ctx, collection, opts)
+}
+
+func (s *AuthorizingStore) Patch(ctx context.Context, collection string, opts PatchOptions) error {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.Patch(ctx, collection, opts)
+	}
+
+	if err := s.authorizeMatches(ctx, config.AccessActionWrite, collection, opts.QueryDocument); err != nil {
+		return err
+	}
+	return s.Store.Patch(ctx, collection, opts)
+}
+
+func (s *AuthorizingStore) Remove(ctx context.Context, collection string, opts RemoveOptions) error {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.Remove(ctx, collection, opts)
+	}
+
+	if err := s.authorizeMatches(ctx, config.AccessActionDelete, collection, opts.ToPluginOptions(collection).Filter); err != nil {
+		return err
+	}
+	return s.Store.Remove(ctx, collection, opts)
+}
+
+func (s *AuthorizingStore) Update(ctx context.Context, collection string, opts UpdateOptions) error {
+	if !isAuthorized(collection) || !s.IsEnabled() {
+		return s.Store.Update(ctx, collection, opts)
+	}
+
+	pluginOpts, err := opts.ToPluginOptions(collection)
+	if err != nil {
+		return err
+	}
+
+	// The document must be writable in both its current namespace and its new namespace
+	if err = s.authorizeMatches(ctx, config.AccessActionWrite, collection, pluginOpts.Filter); err != nil {
+		return err
+	}
+	namespace, _ := pluginOpts.Document["namespace"].(string)
+	if err = s.authorize(ctx, config.AccessActionWrite, collection, namespace); err != nil {
+		return err
+	}
+	return s.Store.Update(ctx, collection, opts)
+}
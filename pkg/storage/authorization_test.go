@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalAuthorizer(t *testing.T) {
+	a := NewLocalAuthorizer([]config.AccessPolicy{
+		{Identity: "alice", Namespaces: []string{"team-a-*", ""}},
+		{Identity: "bob", Namespaces: []string{"prod"}, Actions: []string{config.AccessActionRead}},
+		{Identity: "*", Namespaces: []string{"sandbox"}},
+	})
+
+	testcases := []struct {
+		identity  string
+		action    string
+		namespace string
+		allowed   bool
+	}{
+		{identity: "alice", action: config.AccessActionWrite, namespace: "team-a-dev", allowed: true},
+		{identity: "alice", action: config.AccessActionDelete, namespace: "", allowed: true},
+		{identity: "alice", action: config.AccessActionRead, namespace: "prod", allowed: false},
+		{identity: "bob", action: config.AccessActionRead, namespace: "prod", allowed: true},
+		{identity: "bob", action: config.AccessActionDelete, namespace: "prod", allowed: false},
+		{identity: "bob", action: config.AccessActionRead, namespace: "", allowed: false},
+		{identity: "carol", action: config.AccessActionWrite, namespace: "sandbox", allowed: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.identity+" "+tc.action+" "+tc.namespace, func(t *testing.T) {
+			req := AuthorizationRequest{Identity: tc.identity, Action: tc.action, Collection: CollectionInstallations, Namespace: tc.namespace}
+			err := a.Authorize(context.Background(), req)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrAccessDenied{})
+			}
+		})
+	}
+}
+
+func TestWebhookAuthorizer(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+
+		var req AuthorizationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Namespace == "prod" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("only the release pipeline may access prod"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	a := NewWebhookAuthorizer(config.AccessWebhookConfig{URL: server.URL, Token: "mytoken"})
+
+	req := AuthorizationRequest{Identity: "alice", Action: config.AccessActionRead, Collection: CollectionCredentials, Namespace: "dev"}
+	require.NoError(t, a.Authorize(ctx, req))
+	require.NoError(t, a.Authorize(ctx, req))
+	assert.Equal(t, 1, calls, "the decision should be cached")
+
+	req.Namespace = "prod"
+	err := a.Authorize(ctx, req)
+	require.ErrorIs(t, err, ErrAccessDenied{})
+	assert.EqualError(t, err, "alice is not allowed to read credential sets in namespace prod: only the release pipeline may access prod")
+}
+
+func TestAuthorizingStore(t *testing.T) {
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	testStore := NewTestStore(tc)
+	defer testStore.Close()
+
+	// Create test data before access is authorized
+	cp := NewTestCredentialProviderFor(t, testStore, secrets.NewTestSecretsProvider())
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("dev", "github")))
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("prod", "github")))
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("", "aws")))
+
+	tc.Data.Access.Identity = "alice"
+	tc.Data.Access.Policies = []config.AccessPolicy{
+		{Identity: "alice", Namespaces: []string{"dev", ""}},
+		{Identity: "alice", Namespaces: []string{"prod"}, Actions: []string{config.AccessActionRead}},
+	}
+	s := NewCredentialStore(NewAuthorizingStore(tc.Config, testStore), secrets.NewTestSecretsProvider())
+
+	t.Run("list", func(t *testing.T) {
+		creds, err := s.ListCredentialSets(ctx, ListOptions{Namespace: "*"})
+		require.NoError(t, err)
+		assert.Len(t, creds, 3)
+
+		tc.Data.Access.Policies[1].Identity = "bob"
+		defer func() { tc.Data.Access.Policies[1].Identity = "alice" }()
+		creds, err = s.ListCredentialSets(ctx, ListOptions{Namespace: "*"})
+		require.NoError(t, err)
+		require.Len(t, creds, 2, "credential sets in namespaces that cannot be read should not be listed")
+		for _, cs := range creds {
+			assert.NotEqual(t, "prod", cs.Namespace)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		_, err := s.GetCredentialSet(ctx, "prod", "github")
+		require.NoError(t, err)
+
+		s.Documents.(*AuthorizingStore).Identity = "bob"
+		defer func() { s.Documents.(*AuthorizingStore).Identity = "" }()
+		_, err = s.GetCredentialSet(ctx, "prod", "github")
+		require.ErrorIs(t, err, ErrAccessDenied{})
+		assert.EqualError(t, err, "bob is not allowed to read credential sets in namespace prod")
+	})
+
+	t.Run("write", func(t *testing.T) {
+		require.NoError(t, s.InsertCredentialSet(ctx, NewCredentialSet("dev", "azure")))
+
+		err := s.InsertCredentialSet(ctx, NewCredentialSet("prod", "azure"))
+		require.ErrorIs(t, err, ErrAccessDenied{})
+
+		cs := NewCredentialSet("prod", "github")
+		err = s.UpdateCredentialSet(ctx, cs)
+		require.ErrorIs(t, err, ErrAccessDenied{})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, s.RemoveCredentialSet(ctx, "dev", "azure"))
+
+		err := s.RemoveCredentialSet(ctx, "prod", "github")
+		require.ErrorIs(t, err, ErrAccessDenied{})
+		assert.EqualError(t, err, "alice is not allowed to delete credential sets in namespace prod")
+	})
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ContinueToken identifies the last document of a page of results, so that
+// the next page can be requested. Unlike Skip, the storage plugin does not
+// need to scan past the earlier pages, which keeps listing responsive on
+// datastores with tens of thousands of documents.
+type ContinueToken struct {
+	// Namespace of the last document on the page.
+	Namespace string `json:"namespace"`
+
+	// Name of the last document on the page.
+	Name string `json:"name"`
+}
+
+// NewContinueToken creates a token that continues listing after the specified document.
+func NewContinueToken(namespace string, name string) ContinueToken {
+	return ContinueToken{Namespace: namespace, Name: name}
+}
+
+// ParseContinueToken decodes a token that was printed with a previous page of results.
+func ParseContinueToken(value string) (ContinueToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ContinueToken{}, fmt.Errorf("invalid continue token %q: %w", value, err)
+	}
+
+	var token ContinueToken
+	if err = json.Unmarshal(data, &token); err != nil {
+		return ContinueToken{}, fmt.Errorf("invalid continue token %q: %w", value, err)
+	}
+	return token, nil
+}
+
+// String encodes the token so that it can be passed to a list command.
+func (t ContinueToken) String() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// toFilter returns a query filter for the documents sorted after the token,
+// when the results are sorted by namespace and then name.
+func (t ContinueToken) toFilter() bson.A {
+	return bson.A{
+		bson.M{"namespace": bson.M{"$gt": t.Namespace}},
+		bson.M{"namespace": t.Namespace, "name": bson.M{"$gt": t.Name}},
+	}
+}
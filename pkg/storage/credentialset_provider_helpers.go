@@ -42,7 +42,7 @@ func NewTestCredentialProviderFor(t *testing.T, testStore Store, testSecrets sec
 		TestSecrets: testSecrets,
 		TestStorage: testStore,
 		CredentialStore: &CredentialStore{
-			Documents: testStore,
+			Documents: NewNamespacePolicyStore(testStore),
 			Secrets:   testSecrets,
 		},
 	}
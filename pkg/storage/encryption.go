@@ -0,0 +1,472 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/tracing"
+)
+
+const (
+	// CollectionDataKeys is the collection that stores the wrapped data keys used
+	// to encrypt sensitive data.
+	CollectionDataKeys = "dataKeys"
+
+	// SourceEncrypted is the source key for a value that has been encrypted with
+	// a data key and stored inline in the document.
+	SourceEncrypted = "encrypted"
+
+	// dataKeySize is the size in bytes of generated data keys, selecting AES-256.
+	dataKeySize = 32
+)
+
+// ErrEncryptionNotConfigured is returned when an operation requires encryption
+// but an encryption key is not specified in the Porter configuration file.
+var ErrEncryptionNotConfigured = errors.New("encryption is not configured, set encryption.key, or encryption.vault.key when using the vault provider, in the Porter configuration file")
+
+var _ Document = DataKey{}
+
+// DataKey is a data encryption key used to encrypt sensitive values. The key is
+// stored wrapped (encrypted) by the configured key encryption key.
+type DataKey struct {
+	// ID of the data key.
+	ID string `json:"_id"`
+
+	// Created timestamp of the data key.
+	Created time.Time `json:"created"`
+
+	// Provider that wrapped the data key.
+	Provider string `json:"provider"`
+
+	// WrappedKey is the data key, encrypted by the key encryption key.
+	WrappedKey []byte `json:"wrappedKey"`
+}
+
+func (k DataKey) DefaultDocumentFilter() map[string]interface{} {
+	return map[string]interface{}{"_id": k.ID}
+}
+
+// KeyWrapper encrypts and decrypts data keys with a key encryption key, such as
+// a key held by a key management service.
+type KeyWrapper interface {
+	// WrapKey encrypts a data key.
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data key.
+	UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+var _ KeyWrapper = localKeyWrapper{}
+
+// localKeyWrapper wraps data keys with a key specified in the configuration file.
+type localKeyWrapper struct {
+	key []byte
+}
+
+// NewLocalKeyWrapper creates a KeyWrapper that derives an AES-256 key encryption
+// key from the specified secret.
+func NewLocalKeyWrapper(secret string) KeyWrapper {
+	key := sha256.Sum256([]byte(secret))
+	return localKeyWrapper{key: key[:]}
+}
+
+func (w localKeyWrapper) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	return sealAESGCM(w.key, dataKey)
+}
+
+func (w localKeyWrapper) UnwrapKey(_ context.Context, wrappedKey []byte) ([]byte, error) {
+	return openAESGCM(w.key, wrappedKey)
+}
+
+var _ KeyWrapper = vaultKeyWrapper{}
+
+// vaultKeyWrapper wraps data keys with a key held by the HashiCorp Vault transit
+// secrets engine, so that the key encryption key never leaves Vault.
+type vaultKeyWrapper struct {
+	address string
+	token   string
+	mount   string
+	key     string
+	client  *http.Client
+}
+
+// NewVaultKeyWrapper creates a KeyWrapper that encrypts and decrypts data keys
+// with the specified key in the Vault transit secrets engine.
+func NewVaultKeyWrapper(cfg config.EncryptionVaultConfig) (KeyWrapper, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("encryption.vault.address must be set when using the vault encryption provider")
+	}
+	if cfg.Key == "" {
+		return nil, ErrEncryptionNotConfigured
+	}
+
+	mount := strings.Trim(cfg.Mount, "/")
+	if mount == "" {
+		mount = "transit"
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 // default to 10 seconds
+	}
+	return vaultKeyWrapper{
+		address: strings.TrimSuffix(cfg.Address, "/"),
+		token:   cfg.Token,
+		mount:   mount,
+		key:     cfg.Key,
+		client:  &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}, nil
+}
+
+func (w vaultKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var result struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := w.call(ctx, "encrypt", req, &result); err != nil {
+		return nil, err
+	}
+	return []byte(result.Ciphertext), nil
+}
+
+func (w vaultKeyWrapper) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	var result struct {
+		Plaintext string `json:"plaintext"`
+	}
+	req := map[string]string{"ciphertext": string(wrappedKey)}
+	if err := w.call(ctx, "decrypt", req, &result); err != nil {
+		return nil, err
+	}
+
+	dataKey, err := base64.StdEncoding.DecodeString(result.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key returned by vault: %w", err)
+	}
+	return dataKey, nil
+}
+
+// call sends a request to the transit secrets engine, e.g. encrypt or decrypt,
+// and reads the data from the response into result.
+func (w vaultKeyWrapper) call(ctx context.Context, operation string, body interface{}, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error serializing the vault %s request: %w", operation, err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/%s/%s", w.address, w.mount, operation, url.PathEscape(w.key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating the vault %s request: %w", operation, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.token != "" {
+		req.Header.Set("X-Vault-Token", w.token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not connect to vault at %s: %w", w.address, err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil && resp.StatusCode < 300 {
+		return fmt.Errorf("error reading the vault %s response: %w", operation, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("vault returned %s for the %s request with key %s: %s", resp.Status, operation, w.key, strings.Join(response.Errors, ", "))
+	}
+
+	if err = json.Unmarshal(response.Data, result); err != nil {
+		return fmt.Errorf("error reading the vault %s response: %w", operation, err)
+	}
+	return nil
+}
+
+// Encryptor performs envelope encryption of sensitive values. Values are
+// encrypted with a data key, and the data key is saved to storage wrapped by
+// the key encryption key from the Porter configuration file.
+// An Encryptor is safe for concurrent use.
+type Encryptor struct {
+	config *config.Config
+	store  Store
+
+	// Wrapper overrides the key wrapper selected by the configuration file.
+	Wrapper KeyWrapper
+
+	// mu protects the wrapper and the data key cache
+	mu      sync.Mutex
+	wrapper KeyWrapper
+
+	// cache of unwrapped data keys by id
+	keys      map[string][]byte
+	activeKey string
+}
+
+// NewEncryptor creates an Encryptor that saves data keys to the specified store.
+func NewEncryptor(c *config.Config, store Store) *Encryptor {
+	return &Encryptor{
+		config: c,
+		store:  store,
+		keys:   make(map[string][]byte),
+	}
+}
+
+// IsEnabled determines if sensitive values should be encrypted.
+func (e *Encryptor) IsEnabled() bool {
+	if e == nil {
+		return false
+	}
+	return e.Wrapper != nil || e.config.Data.Encryption.IsEnabled()
+}
+
+// getWrapper returns the key wrapper selected by the configuration file.
+// The caller must hold e.mu.
+func (e *Encryptor) getWrapper() (KeyWrapper, error) {
+	if e.Wrapper != nil {
+		return e.Wrapper, nil
+	}
+	if e.wrapper != nil {
+		return e.wrapper, nil
+	}
+
+	cfg := e.config.Data.Encryption
+	if !cfg.IsEnabled() {
+		return nil, ErrEncryptionNotConfigured
+	}
+
+	switch cfg.GetProvider() {
+	case config.EncryptionProviderLocal:
+		e.wrapper = NewLocalKeyWrapper(cfg.Key)
+	case config.EncryptionProviderVault:
+		wrapper, err := NewVaultKeyWrapper(cfg.Vault)
+		if err != nil {
+			return nil, err
+		}
+		e.wrapper = wrapper
+	default:
+		return nil, fmt.Errorf("unsupported encryption provider %s, available values are: %s, %s", cfg.Provider, config.EncryptionProviderLocal, config.EncryptionProviderVault)
+	}
+	return e.wrapper, nil
+}
+
+// Encrypt a value with the active data key. A data key is generated when one does not exist.
+func (e *Encryptor) Encrypt(ctx context.Context, value string) (string, error) {
+	keyID, dataKey, err := e.getActiveDataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := sealAESGCM(dataKey, []byte(value))
+	if err != nil {
+		return "", fmt.Errorf("could not encrypt value: %w", err)
+	}
+	return keyID + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// getActiveDataKey returns the id and value of the data key that encrypts new values,
+// generating a data key when one does not exist.
+func (e *Encryptor) getActiveDataKey(ctx context.Context) (string, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.activeKey == "" {
+		var key DataKey
+		err := e.store.FindOne(ctx, CollectionDataKeys, FindOptions{Sort: []string{"-_id"}}, &key)
+		if errors.Is(err, ErrNotFound{}) {
+			key, err = e.rotateDataKey(ctx)
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("could not retrieve the active data key: %w", err)
+		}
+		e.activeKey = key.ID
+	}
+
+	dataKey, err := e.getDataKey(ctx, e.activeKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return e.activeKey, dataKey, nil
+}
+
+// Decrypt a value that was encrypted with Encrypt.
+func (e *Encryptor) Decrypt(ctx context.Context, value string) (string, error) {
+	keyID, encoded, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", errors.New("invalid encrypted value, expected the format KEY_ID:CIPHERTEXT")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+
+	e.mu.Lock()
+	dataKey, err := e.getDataKey(ctx, keyID)
+	e.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := openAESGCM(dataKey, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt value with data key %s: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// RotateDataKey generates a new data key which is used to encrypt all new values.
+// Previous data keys are still available for decrypting existing values until removed with RemoveDataKeys.
+func (e *Encryptor) RotateDataKey(ctx context.Context) (DataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.rotateDataKey(ctx)
+}
+
+// rotateDataKey generates a new active data key. The caller must hold e.mu.
+func (e *Encryptor) rotateDataKey(ctx context.Context) (DataKey, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	wrapper, err := e.getWrapper()
+	if err != nil {
+		return DataKey{}, span.Error(err)
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return DataKey{}, span.Error(fmt.Errorf("could not generate a data key: %w", err))
+	}
+
+	wrappedKey, err := wrapper.WrapKey(ctx, dataKey)
+	if err != nil {
+		return DataKey{}, span.Error(fmt.Errorf("could not wrap the data key: %w", err))
+	}
+
+	key := DataKey{
+		ID:         cnab.NewULID(),
+		Created:    time.Now(),
+		Provider:   e.config.Data.Encryption.GetProvider(),
+		WrappedKey: wrappedKey,
+	}
+	err = e.store.Insert(ctx, CollectionDataKeys, InsertOptions{Documents: []interface{}{key}})
+	if err != nil {
+		return DataKey{}, span.Error(fmt.Errorf("could not save the data key: %w", err))
+	}
+
+	span.Debugf("Generated data key %s", key.ID)
+	e.keys[key.ID] = dataKey
+	e.activeKey = key.ID
+	return key, nil
+}
+
+// RemoveDataKeys removes every data key except the active data key.
+// Values encrypted with a removed data key can no longer be decrypted.
+func (e *Encryptor) RemoveDataKeys(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.activeKey == "" {
+		return errors.New("cannot remove the previous data keys because there is no active data key")
+	}
+
+	var keys []DataKey
+	if err := e.store.Find(ctx, CollectionDataKeys, FindOptions{}, &keys); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if key.ID == e.activeKey {
+			continue
+		}
+		if err := e.store.Remove(ctx, CollectionDataKeys, RemoveOptions{ID: key.ID}); err != nil {
+			return fmt.Errorf("could not remove data key %s: %w", key.ID, err)
+		}
+		delete(e.keys, key.ID)
+	}
+	return nil
+}
+
+// getDataKey returns the unwrapped data key with the specified id. The caller must hold e.mu.
+func (e *Encryptor) getDataKey(ctx context.Context, id string) ([]byte, error) {
+	if dataKey, ok := e.keys[id]; ok {
+		return dataKey, nil
+	}
+
+	wrapper, err := e.getWrapper()
+	if err != nil {
+		return nil, err
+	}
+
+	var key DataKey
+	if err := e.store.Get(ctx, CollectionDataKeys, GetOptions{ID: id}, &key); err != nil {
+		return nil, fmt.Errorf("could not retrieve data key %s: %w", id, err)
+	}
+
+	dataKey, err := wrapper.UnwrapKey(ctx, key.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not unwrap data key %s, check that the encryption key in the Porter configuration file has not changed: %w", id, err)
+	}
+
+	e.keys[id] = dataKey
+	return dataKey, nil
+}
+
+// sealAESGCM encrypts the data with AES-GCM, prefixing the result with the nonce.
+func sealAESGCM(key []byte, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// openAESGCM decrypts data encrypted by sealAESGCM.
+func openAESGCM(key []byte, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/secrets"
+	"github.com/cnabio/cnab-go/bundle"
+	"github.com/cnabio/cnab-go/bundle/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalKeyWrapper(t *testing.T) {
+	ctx := context.Background()
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+
+	w := NewLocalKeyWrapper("my-secret-key")
+	wrapped, err := w.WrapKey(ctx, dataKey)
+	require.NoError(t, err)
+	assert.NotContains(t, string(wrapped), string(dataKey), "the data key should not be stored in plaintext")
+
+	unwrapped, err := w.UnwrapKey(ctx, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	_, err = NewLocalKeyWrapper("another-key").UnwrapKey(ctx, wrapped)
+	require.Error(t, err, "unwrapping with a different key should fail")
+}
+
+func TestEncryptor_IsEnabled(t *testing.T) {
+	c := config.NewTestConfig(t)
+
+	e := NewEncryptor(c.Config, nil)
+	assert.False(t, e.IsEnabled(), "encryption should be disabled by default")
+
+	c.Data.Encryption.Key = "my-secret-key"
+	assert.True(t, e.IsEnabled(), "encryption should be enabled when a key is configured")
+
+	var nilEncryptor *Encryptor
+	assert.False(t, nilEncryptor.IsEnabled(), "a nil encryptor should be disabled")
+}
+
+func TestEncryptor_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	c.Data.Encryption.Key = "my-secret-key"
+
+	e := NewEncryptor(c.Config, nil)
+	// Seed the data key cache so that storage isn't required
+	e.keys["key1"] = []byte("0123456789abcdef0123456789abcdef")
+	e.activeKey = "key1"
+
+	encrypted, err := e.Encrypt(ctx, "top secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "key1:"), "the encrypted value should record the data key used")
+	assert.NotContains(t, encrypted, "top secret")
+
+	decrypted, err := e.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "top secret", decrypted)
+
+	_, err = e.Decrypt(ctx, "missing-separator")
+	require.ErrorContains(t, err, "invalid encrypted value")
+}
+
+func TestEncryptor_UnsupportedProvider(t *testing.T) {
+	c := config.NewTestConfig(t)
+	c.Data.Encryption.Key = "my-secret-key"
+	c.Data.Encryption.Provider = "kms"
+
+	e := NewEncryptor(c.Config, nil)
+	_, err := e.getWrapper()
+	require.EqualError(t, err, "unsupported encryption provider kms, available values are: local, vault")
+}
+
+// newFakeVaultServer implements the encrypt and decrypt endpoints of the vault
+// transit secrets engine for the key named porter.
+func newFakeVaultServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/porter":
+			data = map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]}
+		case "/v1/transit/decrypt/porter":
+			data = map[string]string{"plaintext": strings.TrimPrefix(req["ciphertext"], "vault:v1:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":["unknown key"]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVaultKeyWrapper(t *testing.T) {
+	ctx := context.Background()
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	srv := newFakeVaultServer(t)
+
+	w, err := NewVaultKeyWrapper(config.EncryptionVaultConfig{Address: srv.URL, Token: "my-token", Key: "porter"})
+	require.NoError(t, err)
+
+	wrapped, err := w.WrapKey(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "vault:v1:"+base64.StdEncoding.EncodeToString(dataKey), string(wrapped))
+
+	unwrapped, err := w.UnwrapKey(ctx, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	t.Run("unauthorized", func(t *testing.T) {
+		w, err := NewVaultKeyWrapper(config.EncryptionVaultConfig{Address: srv.URL, Token: "wrong-token", Key: "porter"})
+		require.NoError(t, err)
+
+		_, err = w.WrapKey(ctx, dataKey)
+		require.ErrorContains(t, err, "vault returned 403 Forbidden for the encrypt request with key porter: permission denied")
+	})
+
+	t.Run("missing address", func(t *testing.T) {
+		_, err := NewVaultKeyWrapper(config.EncryptionVaultConfig{Key: "porter"})
+		require.EqualError(t, err, "encryption.vault.address must be set when using the vault encryption provider")
+	})
+}
+
+func TestEncryptor_Vault(t *testing.T) {
+	ctx := context.Background()
+	srv := newFakeVaultServer(t)
+	c := config.NewTestConfig(t)
+	c.Data.Encryption.Provider = config.EncryptionProviderVault
+	c.Data.Encryption.Vault = config.EncryptionVaultConfig{Address: srv.URL, Token: "my-token", Key: "porter"}
+	testStore := NewTestStore(c)
+	defer testStore.Close()
+
+	e := NewEncryptor(c.Config, testStore)
+	require.True(t, e.IsEnabled(), "encryption should be enabled when a vault key is configured")
+
+	encrypted, err := e.Encrypt(ctx, "top secret")
+	require.NoError(t, err)
+
+	var key DataKey
+	require.NoError(t, testStore.FindOne(ctx, CollectionDataKeys, FindOptions{}, &key))
+	assert.Equal(t, config.EncryptionProviderVault, key.Provider)
+	assert.True(t, strings.HasPrefix(string(key.WrappedKey), "vault:v1:"), "the data key should be wrapped by vault")
+
+	// Use a new encryptor so that the data key is unwrapped by vault
+	decrypted, err := NewEncryptor(c.Config, testStore).Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "top secret", decrypted)
+}
+
+func TestEncryptor_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	c.Data.Encryption.Key = "my-secret-key"
+	testStore := NewTestStore(c)
+	defer testStore.Close()
+
+	e := NewEncryptor(c.Config, testStore)
+
+	// Encrypt and decrypt values at the same time before a data key exists
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			encrypted, err := e.Encrypt(ctx, "top secret")
+			if err == nil {
+				_, err = e.Decrypt(ctx, encrypted)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	count, err := testStore.Count(ctx, CollectionDataKeys, CountOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "only one data key should be generated")
+}
+
+func TestSanitizer_Encryption(t *testing.T) {
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	c.Data.Encryption.Key = "my-secret-key"
+	testStore := NewTestStore(c)
+	defer testStore.Close()
+	testSecrets := secrets.NewTestSecretsProvider()
+
+	e := NewEncryptor(c.Config, testStore)
+	paramStore := NewParameterStore(testStore, testSecrets)
+	paramStore.Encryptor = e
+	sanitizer := NewSanitizer(paramStore, testSecrets)
+	sanitizer.UseEncryption(e)
+
+	sensitive := true
+	bun := cnab.NewBundle(bundle.Bundle{
+		Definitions: definition.Definitions{
+			"secret": &definition.Schema{Type: "string", WriteOnly: &sensitive},
+		},
+		Parameters: map[string]bundle.Parameter{
+			"password": {Definition: "secret"},
+		},
+		Outputs: map[string]bundle.Output{
+			"token": {Definition: "secret"},
+		},
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		params, err := sanitizer.CleanRawParameters(ctx, map[string]interface{}{"password": "top secret"}, bun, "INSTALLATION_ID")
+		require.NoError(t, err)
+		require.Len(t, params, 1)
+		assert.Equal(t, SourceEncrypted, params[0].Source.Key)
+		assert.NotContains(t, params[0].Source.Value, "top secret")
+
+		resolved, err := sanitizer.RestoreParameterSet(ctx, NewParameterSet("", "INSTALLATION_ID", params...), bun)
+		require.NoError(t, err)
+		assert.Equal(t, "top secret", resolved["password"])
+	})
+
+	t.Run("outputs", func(t *testing.T) {
+		output, err := sanitizer.CleanOutput(ctx, Output{Name: "token", RunID: "RUN_ID", Value: []byte("top secret")}, bun)
+		require.NoError(t, err)
+		assert.True(t, output.Encrypted)
+		assert.NotContains(t, string(output.Value), "top secret")
+
+		restored, err := sanitizer.RestoreOutput(ctx, output)
+		require.NoError(t, err)
+		assert.False(t, restored.Encrypted)
+		assert.Equal(t, "top secret", string(restored.Value))
+	})
+}
@@ -62,6 +62,9 @@ type InstallationSpec struct {
 	// Does not include defaults, or values resolved from parameter sources.
 	Parameters ParameterSet `json:"parameters,omitempty"`
 
+	// Schedule runs an action against the installation on a recurring basis.
+	Schedule *InstallationSchedule `json:"schedule,omitempty"`
+
 	// Status of the installation.
 	Status InstallationStatus `json:"status,omitempty"`
 }
@@ -107,6 +110,9 @@ func (i *Installation) ApplyResult(run Run, result Result) {
 		i.Status.BundleReference = run.BundleReference
 		i.Status.BundleVersion = run.Bundle.Version
 		i.Status.BundleDigest = run.BundleDigest
+		if metadata, err := cnab.NewBundle(run.Bundle).GetBundleMetadata(); err == nil {
+			i.Status.BundleAnnotations = metadata.Annotations
+		}
 		i.Status.RunID = run.ID
 		i.Status.Action = run.Action
 		i.Status.ResultID = result.ID
@@ -132,6 +138,7 @@ func (i *InstallationSpec) Apply(input InstallationSpec) {
 	i.CredentialSets = input.CredentialSets
 	i.ParameterSets = input.ParameterSets
 	i.Labels = input.Labels
+	i.Schedule = input.Schedule
 }
 
 // Validate the installation document and report the first error.
@@ -151,6 +158,12 @@ func (i *Installation) Validate() error {
 		return fmt.Errorf("could not determine the fully-qualified bundle reference: %w", err)
 	}
 
+	if i.Schedule != nil {
+		if err := i.Schedule.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -227,6 +240,17 @@ type InstallationStatus struct {
 
 	// BundleDigest is the digest of the bundle that last altered the installation state.
 	BundleDigest string `json:"bundleDigest" yaml:"bundleDigest" toml:"bundleDigest"`
+
+	// BundleAnnotations are the annotations of the bundle that last altered the installation state,
+	// so that installations can be filtered by the annotations of their bundle.
+	BundleAnnotations map[string]string `json:"bundleAnnotations,omitempty" yaml:"bundleAnnotations,omitempty" toml:"bundleAnnotations,omitempty"`
+
+	// Suspended indicates that the installation is paused and should not be reconciled
+	// until it is resumed. Set with porter installations pause and resume.
+	Suspended bool `json:"suspended,omitempty" yaml:"suspended,omitempty" toml:"suspended,omitempty"`
+
+	// LastScheduled is when porter scheduler run last started the installation's scheduled action.
+	LastScheduled *time.Time `json:"lastScheduled,omitempty" yaml:"lastScheduled,omitempty" toml:"lastScheduled,omitempty"`
 }
 
 // IsInstalled checks if the installation is currently installed.
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+)
+
+// CollectionLocks stores the advisory locks held on installations.
+const CollectionLocks = "locks"
+
+var _ Document = InstallationLock{}
+
+// InstallationLock is an advisory lock that prevents more than one bundle
+// action from executing against the same installation at the same time.
+// A lock that is not refreshed before it expires, for example because porter
+// was killed, may be acquired by another process.
+type InstallationLock struct {
+	// ID of the lock, which is the namespace/name of the installation.
+	ID string `json:"_id"`
+
+	// Namespace of the installation.
+	Namespace string `json:"namespace"`
+
+	// Installation name that is locked.
+	Installation string `json:"installation"`
+
+	// Owner is a unique identifier for the holder of the lock.
+	Owner string `json:"owner"`
+
+	// Action that is executed while the lock is held.
+	Action string `json:"action"`
+
+	// Host where the porter process holding the lock is running.
+	Host string `json:"host,omitempty"`
+
+	// PID of the porter process holding the lock.
+	PID int `json:"pid,omitempty"`
+
+	// Acquired timestamp of the lock.
+	Acquired time.Time `json:"acquired"`
+
+	// Expires is when the lock may be acquired by another process, unless it
+	// is refreshed by its owner.
+	Expires time.Time `json:"expires"`
+}
+
+// NewInstallationLock creates a lock on an installation for the current process,
+// that expires after the specified lease unless it is refreshed.
+func NewInstallationLock(namespace string, installation string, action string, lease time.Duration) InstallationLock {
+	now := time.Now()
+	host, _ := os.Hostname()
+	return InstallationLock{
+		ID:           InstallationSpec{Namespace: namespace, Name: installation}.String(),
+		Namespace:    namespace,
+		Installation: installation,
+		Owner:        cnab.NewULID(),
+		Action:       action,
+		Host:         host,
+		PID:          os.Getpid(),
+		Acquired:     now,
+		Expires:      now.Add(lease),
+	}
+}
+
+func (l InstallationLock) DefaultDocumentFilter() map[string]interface{} {
+	return map[string]interface{}{"_id": l.ID, "owner": l.Owner}
+}
+
+// IsExpired determines if the lock was not refreshed by its owner in time, and
+// may be acquired by another process.
+func (l InstallationLock) IsExpired(now time.Time) bool {
+	return now.After(l.Expires)
+}
+
+// ErrInstallationLocked indicates that an installation is locked by another process.
+// You can test for this error using errors.Is(err, storage.ErrInstallationLocked{})
+type ErrInstallationLocked struct {
+	Lock InstallationLock
+}
+
+func (e ErrInstallationLocked) Error() string {
+	holder := e.Lock.Owner
+	if e.Lock.Host != "" {
+		holder = fmt.Sprintf("porter (pid %d) on %s", e.Lock.PID, e.Lock.Host)
+	}
+	return fmt.Sprintf("installation %s is locked by %s, which has been running %s since %s",
+		e.Lock.ID, holder, e.Lock.Action, e.Lock.Acquired.Format(time.RFC3339))
+}
+
+func (e ErrInstallationLocked) Is(err error) bool {
+	_, ok := err.(ErrInstallationLocked)
+	return ok
+}
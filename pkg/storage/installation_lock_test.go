@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInstallationLock(t *testing.T) {
+	lock := NewInstallationLock("dev", "mysql", "upgrade", time.Minute)
+
+	assert.Equal(t, "dev/mysql", lock.ID)
+	assert.NotEmpty(t, lock.Owner)
+	assert.Equal(t, time.Minute, lock.Expires.Sub(lock.Acquired))
+	assert.False(t, lock.IsExpired(lock.Acquired), "a new lock should not be expired")
+	assert.True(t, lock.IsExpired(lock.Expires.Add(time.Second)), "the lock should expire when it is not refreshed")
+
+	other := NewInstallationLock("dev", "mysql", "upgrade", time.Minute)
+	assert.NotEqual(t, lock.Owner, other.Owner, "each lock should have a unique owner")
+}
+
+func TestErrInstallationLocked(t *testing.T) {
+	lock := InstallationLock{
+		ID:       "dev/mysql",
+		Owner:    "1",
+		Action:   "upgrade",
+		Host:     "myhost",
+		PID:      123,
+		Acquired: time.Date(2020, 4, 18, 1, 2, 3, 0, time.UTC),
+	}
+
+	err := fmt.Errorf("could not upgrade: %w", ErrInstallationLocked{Lock: lock})
+	require.True(t, errors.Is(err, ErrInstallationLocked{}))
+	assert.EqualError(t, err, "could not upgrade: installation dev/mysql is locked by porter (pid 123) on myhost, which has been running upgrade since 2020-04-18T01:02:03Z")
+}
@@ -18,9 +18,15 @@ type InstallationProvider interface {
 	// InsertOutput saves a new Output document.
 	InsertOutput(ctx context.Context, output Output) error
 
+	// InsertLogChunk saves a portion of the logs of a run while it is executing.
+	InsertLogChunk(ctx context.Context, chunk LogChunk) error
+
 	// UpdateInstallation saves changes to an existing Installation document.
 	UpdateInstallation(ctx context.Context, installation Installation) error
 
+	// UpdateOutput saves changes to an existing Output document.
+	UpdateOutput(ctx context.Context, output Output) error
+
 	// UpsertRun saves changes a Run document, creating it if it doesn't already exist.
 	UpsertRun(ctx context.Context, run Run) error
 
@@ -46,6 +52,10 @@ type InstallationProvider interface {
 	// ListOutputs returns Output documents sorted in ascending order by name.
 	ListOutputs(ctx context.Context, resultID string) ([]Output, error)
 
+	// ListLogChunks returns the logs saved for a run while it was executing,
+	// that come after the specified sequence, sorted in ascending order by sequence.
+	ListLogChunks(ctx context.Context, runID string, afterSequence int) ([]LogChunk, error)
+
 	// GetRun returns a Run document by ID.
 	GetRun(ctx context.Context, id string) (Run, error)
 
@@ -66,6 +76,28 @@ type InstallationProvider interface {
 	// RemoveInstallation by its name.
 	RemoveInstallation(ctx context.Context, namespace string, name string) error
 
+	// RemoveInstallationRecords removes the runs, results, outputs and logs of an
+	// installation, including records that remain after the installation was removed.
+	RemoveInstallationRecords(ctx context.Context, namespace string, installation string) (int64, error)
+
+	// MoveInstallation changes the namespace and name of an installation, along
+	// with its runs, results, outputs and logs.
+	MoveInstallation(ctx context.Context, namespace string, name string, newNamespace string, newName string) (Installation, error)
+
+	// AcquireInstallationLock locks an installation so that only one bundle action
+	// executes against it at a time. Returns ErrInstallationLocked when the
+	// installation is already locked.
+	AcquireInstallationLock(ctx context.Context, lock InstallationLock) error
+
+	// RefreshInstallationLock extends the expiration of a lock held by the current process.
+	RefreshInstallationLock(ctx context.Context, lock InstallationLock) error
+
+	// ReleaseInstallationLock removes a lock held by the current process.
+	ReleaseInstallationLock(ctx context.Context, lock InstallationLock) error
+
+	// RemoveRun by its ID, including its associated results, outputs and logs.
+	RemoveRun(ctx context.Context, id string) error
+
 	// GetLogs returns the logs from the specified Run.
 	GetLogs(ctx context.Context, runID string) (logs string, hasLogs bool, err error)
 
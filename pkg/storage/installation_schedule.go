@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/cron"
+)
+
+// InstallationSchedule defines an action that is run against an installation
+// on a recurring basis by porter scheduler run.
+type InstallationSchedule struct {
+	// Action to run, such as upgrade or a custom action defined by the bundle.
+	Action string `json:"action" yaml:"action" toml:"action"`
+
+	// Cron expression that determines when the action is run, e.g. "0 3 * * *".
+	// The expression is evaluated in the time zone of the scheduler.
+	Cron string `json:"cron" yaml:"cron" toml:"cron"`
+}
+
+// Validate the schedule.
+func (s InstallationSchedule) Validate() error {
+	if s.Action == "" {
+		return errors.New("schedule.action is required")
+	}
+	if s.Action == cnab.ActionInstall {
+		return errors.New("schedule.action cannot be install, the installation must be installed before it is scheduled")
+	}
+
+	if _, err := cron.Parse(s.Cron); err != nil {
+		return fmt.Errorf("invalid schedule.cron: %w", err)
+	}
+	return nil
+}
+
+// Next returns the first time that the scheduled action is due after the specified time.
+func (s InstallationSchedule) Next(after time.Time) (time.Time, error) {
+	schedule, err := cron.Parse(s.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule.cron: %w", err)
+	}
+	return schedule.Next(after)
+}
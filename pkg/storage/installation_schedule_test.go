@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationSchedule_Validate(t *testing.T) {
+	testcases := []struct {
+		name     string
+		schedule InstallationSchedule
+		wantErr  string
+	}{
+		{name: "valid", schedule: InstallationSchedule{Action: "upgrade", Cron: "0 3 * * *"}},
+		{name: "custom action", schedule: InstallationSchedule{Action: "rotate-certs", Cron: "@weekly"}},
+		{name: "missing action", schedule: InstallationSchedule{Cron: "0 3 * * *"}, wantErr: "schedule.action is required"},
+		{name: "install", schedule: InstallationSchedule{Action: "install", Cron: "0 3 * * *"}, wantErr: "schedule.action cannot be install"},
+		{name: "invalid cron", schedule: InstallationSchedule{Action: "upgrade", Cron: "every day"}, wantErr: "invalid schedule.cron"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"get.porter.sh/porter/pkg/tracing"
+	"github.com/hashicorp/go-multierror"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -22,6 +25,10 @@ type InstallationStore struct {
 	store   Store
 	encrypt EncryptionHandler
 	decrypt EncryptionHandler
+
+	// LargeOutputs deletes the values of outputs that were saved to the large
+	// output store when the outputs are removed.
+	LargeOutputs *LargeOutputs
 }
 
 // NewInstallationStore creates a persistent store for installations using the specified
@@ -57,6 +64,10 @@ func EnsureInstallationIndices(ctx context.Context, store Store) error {
 			{Collection: CollectionOutputs, Keys: []string{"resultId", "name"}, Unique: true},
 			// query most recent outputs by name for an installation
 			{Collection: CollectionOutputs, Keys: []string{"namespace", "installation", "name", "-resultId"}},
+			// query logs by run (porter logs show --follow)
+			{Collection: CollectionLogs, Keys: []string{"runId", "sequence"}, Unique: true},
+			// query logs by installation (delete)
+			{Collection: CollectionLogs, Keys: []string{"namespace", "installation"}},
 		},
 	}
 
@@ -276,6 +287,21 @@ func (s InstallationStore) GetLastLogs(ctx context.Context, namespace string, in
 	return string(out.Value), err == nil, err
 }
 
+// ListLogChunks returns the logs saved for a run while it was executing, that
+// come after the specified sequence, sorted in ascending order by sequence.
+func (s InstallationStore) ListLogChunks(ctx context.Context, runID string, afterSequence int) ([]LogChunk, error) {
+	var out []LogChunk
+	opts := FindOptions{
+		Sort: []string{"sequence"},
+		Filter: bson.M{
+			"runId":    runID,
+			"sequence": bson.M{"$gt": afterSequence},
+		},
+	}
+	err := s.store.Find(ctx, CollectionLogs, opts, &out)
+	return out, err
+}
+
 func (s InstallationStore) InsertInstallation(ctx context.Context, installation Installation) error {
 	installation.SchemaVersion = InstallationSchemaVersion
 	opts := InsertOptions{
@@ -305,6 +331,13 @@ func (s InstallationStore) InsertOutput(ctx context.Context, output Output) erro
 	return s.store.Insert(ctx, CollectionOutputs, opts)
 }
 
+func (s InstallationStore) InsertLogChunk(ctx context.Context, chunk LogChunk) error {
+	opts := InsertOptions{
+		Documents: []interface{}{chunk},
+	}
+	return s.store.Insert(ctx, CollectionLogs, opts)
+}
+
 func (s InstallationStore) UpdateInstallation(ctx context.Context, installation Installation) error {
 	installation.SchemaVersion = InstallationSchemaVersion
 	opts := UpdateOptions{
@@ -313,6 +346,13 @@ func (s InstallationStore) UpdateInstallation(ctx context.Context, installation
 	return s.store.Update(ctx, CollectionInstallations, opts)
 }
 
+func (s InstallationStore) UpdateOutput(ctx context.Context, output Output) error {
+	opts := UpdateOptions{
+		Document: output,
+	}
+	return s.store.Update(ctx, CollectionOutputs, opts)
+}
+
 func (s InstallationStore) UpsertRun(ctx context.Context, run Run) error {
 	opts := UpdateOptions{
 		Upsert:   true,
@@ -332,28 +372,74 @@ func (s InstallationStore) UpsertInstallation(ctx context.Context, installation
 
 // RemoveInstallation and all associated data.
 func (s InstallationStore) RemoveInstallation(ctx context.Context, namespace string, name string) error {
+	// Find associated documents
+	removeChildDocs := RemoveOptions{
+		Filter: bson.M{
+			"namespace":    namespace,
+			"installation": name,
+		},
+		All: true,
+	}
+
+	err := s.removeLargeOutputs(ctx, removeChildDocs.Filter)
+	if err != nil {
+		return err
+	}
+
 	removeInstallation := RemoveOptions{
 		Filter: bson.M{
 			"namespace": namespace,
 			"name":      name,
 		},
 	}
-	err := s.store.Remove(ctx, CollectionInstallations, removeInstallation)
+	err = s.store.Remove(ctx, CollectionInstallations, removeInstallation)
 	if err != nil {
 		return err
 	}
 
+	// Delete runs
+	err = s.store.Remove(ctx, CollectionRuns, removeChildDocs)
+	if err != nil {
+		return err
+	}
+
+	// Delete results
+	err = s.store.Remove(ctx, CollectionResults, removeChildDocs)
+	if err != nil {
+		return err
+	}
+
+	// Delete outputs
+	err = s.store.Remove(ctx, CollectionOutputs, removeChildDocs)
+	if err != nil {
+		return err
+	}
+
+	// Delete logs
+	err = s.store.Remove(ctx, CollectionLogs, removeChildDocs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveRun and its associated results, outputs and logs.
+func (s InstallationStore) RemoveRun(ctx context.Context, id string) error {
 	// Find associated documents
 	removeChildDocs := RemoveOptions{
 		Filter: bson.M{
-			"namespace":    namespace,
-			"installation": name,
+			"runId": id,
 		},
 		All: true,
 	}
 
-	// Delete runs
-	err = s.store.Remove(ctx, CollectionRuns, removeChildDocs)
+	err := s.removeLargeOutputs(ctx, removeChildDocs.Filter)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Remove(ctx, CollectionRuns, RemoveOptions{ID: id})
 	if err != nil {
 		return err
 	}
@@ -370,9 +456,295 @@ func (s InstallationStore) RemoveInstallation(ctx context.Context, namespace str
 		return err
 	}
 
+	// Delete logs
+	return s.store.Remove(ctx, CollectionLogs, removeChildDocs)
+}
+
+// RemoveInstallationRecords removes the runs, results, outputs and logs of an
+// installation, regardless of whether the installation document exists. Unlike
+// RemoveInstallation, results and outputs that reference the runs of the
+// installation by ID, but not by the installation name, are also removed.
+// Returns the number of documents removed.
+func (s InstallationStore) RemoveInstallationRecords(ctx context.Context, namespace string, installation string) (int64, error) {
+	byInstallation := bson.M{
+		"namespace":    namespace,
+		"installation": installation,
+	}
+
+	var runs []struct {
+		ID string `json:"_id"`
+	}
+	err := s.store.Find(ctx, CollectionRuns, FindOptions{Filter: byInstallation, Select: bson.D{{Key: "_id", Value: 1}}}, &runs)
+	if err != nil {
+		return 0, err
+	}
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		runIDs = append(runIDs, run.ID)
+	}
+
+	resultsFilter := bson.M{"$or": []bson.M{byInstallation, {"runId": bson.M{"$in": runIDs}}}}
+	var results []struct {
+		ID string `json:"_id"`
+	}
+	err = s.store.Find(ctx, CollectionResults, FindOptions{Filter: resultsFilter, Select: bson.D{{Key: "_id", Value: 1}}}, &results)
+	if err != nil {
+		return 0, err
+	}
+	resultIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		resultIDs = append(resultIDs, result.ID)
+	}
+
+	outputsFilter := bson.M{"$or": []bson.M{byInstallation, {"runId": bson.M{"$in": runIDs}}, {"resultId": bson.M{"$in": resultIDs}}}}
+	logsFilter := bson.M{"$or": []bson.M{byInstallation, {"runId": bson.M{"$in": runIDs}}}}
+
+	if err = s.removeLargeOutputs(ctx, outputsFilter); err != nil {
+		return 0, err
+	}
+
+	var removed int64
+	for _, c := range []struct {
+		collection string
+		filter     bson.M
+	}{
+		{CollectionRuns, byInstallation},
+		{CollectionResults, resultsFilter},
+		{CollectionOutputs, outputsFilter},
+		{CollectionLogs, logsFilter},
+	} {
+		count, err := s.store.Count(ctx, c.collection, CountOptions{Filter: c.filter})
+		if err != nil {
+			return removed, err
+		}
+		if count == 0 {
+			continue
+		}
+		if err = s.store.Remove(ctx, c.collection, RemoveOptions{Filter: c.filter, All: true}); err != nil {
+			return removed, err
+		}
+		removed += count
+	}
+
+	return removed, nil
+}
+
+// removeLargeOutputs deletes the values of the matching outputs that were saved
+// to the large output store, so that they are not left behind when the output
+// documents are removed.
+func (s InstallationStore) removeLargeOutputs(ctx context.Context, filter bson.M) error {
+	if s.LargeOutputs == nil {
+		return nil
+	}
+
+	var outputs []Output
+	err := s.store.Find(ctx, CollectionOutputs, FindOptions{Filter: filter, Select: bson.D{{Key: "location", Value: 1}}}, &outputs)
+	if err != nil {
+		return err
+	}
+	for _, output := range outputs {
+		if output.Location == "" {
+			continue
+		}
+		if err = s.LargeOutputs.Delete(ctx, output.Location); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// MoveInstallation changes the namespace and name of an installation, and of its
+// runs, results, outputs and logs. If any document cannot be moved, the documents
+// that were already moved are restored so that the installation is not split
+// between the old and new names.
+func (s InstallationStore) MoveInstallation(ctx context.Context, namespace string, name string, newNamespace string, newName string) (Installation, error) {
+	installation, err := s.GetInstallation(ctx, namespace, name)
+	if err != nil {
+		return Installation{}, err
+	}
+
+	_, err = s.GetInstallation(ctx, newNamespace, newName)
+	if err == nil {
+		return Installation{}, fmt.Errorf("installation %s/%s already exists", newNamespace, newName)
+	}
+	if !errors.Is(err, ErrNotFound{}) {
+		return Installation{}, err
+	}
+
+	childDocs := FindOptions{
+		Filter: bson.M{
+			"namespace":    namespace,
+			"installation": name,
+		},
+	}
+	var runs []Run
+	if err = s.store.Find(ctx, CollectionRuns, childDocs, &runs); err != nil {
+		return Installation{}, err
+	}
+	var results []Result
+	if err = s.store.Find(ctx, CollectionResults, childDocs, &results); err != nil {
+		return Installation{}, err
+	}
+	var outputs []Output
+	if err = s.store.Find(ctx, CollectionOutputs, childDocs, &outputs); err != nil {
+		return Installation{}, err
+	}
+	var logs []LogChunk
+	if err = s.store.Find(ctx, CollectionLogs, childDocs, &logs); err != nil {
+		return Installation{}, err
+	}
+
+	// Keep track of how to undo each change, in case a later change fails
+	var undo []func() error
+	rollback := func(moveErr error) error {
+		var bigErr *multierror.Error
+		bigErr = multierror.Append(bigErr, moveErr)
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				bigErr = multierror.Append(bigErr, fmt.Errorf("could not restore installation %s/%s: %w", namespace, name, err))
+			}
+		}
+		return bigErr.ErrorOrNil()
+	}
+
+	// The installation is moved first, so that the unique index on the
+	// installation name prevents two installations from moving to the same name
+	moved := installation
+	moved.Namespace = newNamespace
+	moved.Name = newName
+	moved.Parameters.Namespace = newNamespace
+	moved.SchemaVersion = InstallationSchemaVersion
+	err = s.store.Update(ctx, CollectionInstallations, UpdateOptions{
+		Filter:   bson.M{"namespace": namespace, "name": name},
+		Document: moved,
+	})
+	if err != nil {
+		return Installation{}, err
+	}
+	undo = append(undo, func() error {
+		return s.store.Update(ctx, CollectionInstallations, UpdateOptions{
+			Filter:   bson.M{"namespace": newNamespace, "name": newName},
+			Document: installation,
+		})
+	})
+
+	for _, run := range runs {
+		original := run
+		run.Namespace = newNamespace
+		run.Installation = newName
+		run.Parameters.Namespace = newNamespace
+		run.ParameterOverrides.Namespace = newNamespace
+		if err = s.store.Update(ctx, CollectionRuns, UpdateOptions{Document: run}); err != nil {
+			return Installation{}, rollback(fmt.Errorf("could not move run %s: %w", run.ID, err))
+		}
+		undo = append(undo, func() error {
+			return s.store.Update(ctx, CollectionRuns, UpdateOptions{Document: original})
+		})
+	}
+
+	for _, result := range results {
+		original := result
+		result.Namespace = newNamespace
+		result.Installation = newName
+		if err = s.store.Update(ctx, CollectionResults, UpdateOptions{Document: result}); err != nil {
+			return Installation{}, rollback(fmt.Errorf("could not move result %s: %w", result.ID, err))
+		}
+		undo = append(undo, func() error {
+			return s.store.Update(ctx, CollectionResults, UpdateOptions{Document: original})
+		})
+	}
+
+	for _, output := range outputs {
+		original := output
+		output.Namespace = newNamespace
+		output.Installation = newName
+		if err = s.store.Update(ctx, CollectionOutputs, UpdateOptions{Document: output}); err != nil {
+			return Installation{}, rollback(fmt.Errorf("could not move output %s from result %s: %w", output.Name, output.ResultID, err))
+		}
+		undo = append(undo, func() error {
+			return s.store.Update(ctx, CollectionOutputs, UpdateOptions{Document: original})
+		})
+	}
+
+	for _, chunk := range logs {
+		original := chunk
+		chunk.Namespace = newNamespace
+		chunk.Installation = newName
+		if err = s.store.Update(ctx, CollectionLogs, UpdateOptions{Document: chunk}); err != nil {
+			return Installation{}, rollback(fmt.Errorf("could not move logs from run %s: %w", chunk.RunID, err))
+		}
+		undo = append(undo, func() error {
+			return s.store.Update(ctx, CollectionLogs, UpdateOptions{Document: original})
+		})
+	}
+
+	return moved, nil
+}
+
+// AcquireInstallationLock saves the lock, unless the installation is already
+// locked by another process, in which case ErrInstallationLocked is returned.
+// An expired lock is replaced.
+func (s InstallationStore) AcquireInstallationLock(ctx context.Context, lock InstallationLock) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		// The lock id is the installation, so the insert fails when a lock already exists
+		insertErr := s.store.Insert(ctx, CollectionLocks, InsertOptions{Documents: []interface{}{lock}})
+		if insertErr == nil {
+			return nil
+		}
+
+		var existing InstallationLock
+		err := s.store.Get(ctx, CollectionLocks, GetOptions{ID: lock.ID}, &existing)
+		if err != nil {
+			if errors.Is(err, ErrNotFound{}) {
+				// The lock was released between our attempt to insert and lookup, try again
+				continue
+			}
+			return fmt.Errorf("could not acquire lock on installation %s: %w", lock.ID, insertErr)
+		}
+
+		if !existing.IsExpired(time.Now()) {
+			return ErrInstallationLocked{Lock: existing}
+		}
+
+		// Only remove the lock held by the expired owner, in case another process already replaced it
+		err = s.store.Remove(ctx, CollectionLocks, RemoveOptions{Filter: existing.DefaultDocumentFilter()})
+		if err != nil && !errors.Is(err, ErrNotFound{}) {
+			return fmt.Errorf("could not remove expired lock on installation %s: %w", lock.ID, err)
+		}
+	}
+
+	var existing InstallationLock
+	if err := s.store.Get(ctx, CollectionLocks, GetOptions{ID: lock.ID}, &existing); err != nil {
+		return fmt.Errorf("could not acquire lock on installation %s: %w", lock.ID, err)
+	}
+	return ErrInstallationLocked{Lock: existing}
+}
+
+// RefreshInstallationLock extends the expiration of a lock held by the current
+// process. Returns ErrInstallationLocked when the lock is no longer held by its owner.
+func (s InstallationStore) RefreshInstallationLock(ctx context.Context, lock InstallationLock) error {
+	var existing InstallationLock
+	err := s.store.Get(ctx, CollectionLocks, GetOptions{ID: lock.ID}, &existing)
+	if err != nil && !errors.Is(err, ErrNotFound{}) {
+		return err
+	}
+	if err != nil || existing.Owner != lock.Owner {
+		return ErrInstallationLocked{Lock: existing}
+	}
+
+	return s.store.Update(ctx, CollectionLocks, UpdateOptions{Document: lock})
+}
+
+// ReleaseInstallationLock removes a lock held by the current process. Locks held
+// by other processes are not removed.
+func (s InstallationStore) ReleaseInstallationLock(ctx context.Context, lock InstallationLock) error {
+	err := s.store.Remove(ctx, CollectionLocks, RemoveOptions{Filter: lock.DefaultDocumentFilter()})
+	if errors.Is(err, ErrNotFound{}) {
+		return nil
+	}
+	return err
+}
+
 // EncryptionHandler is a function that transforms data by encrypting or decrypting it.
 type EncryptionHandler func([]byte) ([]byte, error)
 
@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"testing"
+	"time"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
 	"github.com/cnabio/cnab-go/bundle"
 	"github.com/cnabio/cnab-go/bundle/definition"
 	"github.com/stretchr/testify/assert"
@@ -268,6 +270,165 @@ func TestInstallationStorageProvider_DeleteInstallation(t *testing.T) {
 	require.ErrorIs(t, err, ErrNotFound{})
 }
 
+func TestInstallationStorageProvider_RemoveRun(t *testing.T) {
+	cp := generateInstallationData(t)
+	defer cp.Close()
+
+	runs, _, err := cp.ListRuns(context.Background(), "dev", "foo")
+	require.NoError(t, err, "ListRuns failed")
+	require.Len(t, runs, 4, "expected 4 runs")
+	installRun := runs[0]
+
+	err = cp.RemoveRun(context.Background(), installRun.ID)
+	require.NoError(t, err, "RemoveRun failed")
+
+	runs, resultsMap, err := cp.ListRuns(context.Background(), "dev", "foo")
+	require.NoError(t, err, "ListRuns failed")
+	assert.Len(t, runs, 3, "expected the install run to be deleted")
+	assert.NotContains(t, resultsMap, installRun.ID, "expected the results of the install run to be deleted")
+
+	_, hasLogs, err := cp.GetLogs(context.Background(), installRun.ID)
+	require.NoError(t, err, "GetLogs failed")
+	assert.False(t, hasLogs, "expected the outputs of the install run to be deleted")
+}
+
+func TestInstallationStorageProvider_RemoveInstallationRecords(t *testing.T) {
+	cp := generateInstallationData(t)
+	defer cp.Close()
+	ctx := context.Background()
+
+	// Remove only the installation document, leaving behind its runs, results and outputs
+	err := cp.store.Remove(ctx, CollectionInstallations, RemoveOptions{Filter: bson.M{"namespace": "dev", "name": "foo"}})
+	require.NoError(t, err, "Remove failed")
+
+	count, err := cp.RemoveInstallationRecords(ctx, "dev", "foo")
+	require.NoError(t, err, "RemoveInstallationRecords failed")
+	assert.Equal(t, int64(14), count, "expected the 4 runs, 4 results and 6 outputs to be removed")
+
+	runs, _, err := cp.ListRuns(ctx, "dev", "foo")
+	require.NoError(t, err, "ListRuns failed")
+	assert.Empty(t, runs, "expected the runs to be removed")
+
+	outputs, err := cp.GetLastOutputs(ctx, "dev", "foo")
+	require.NoError(t, err, "GetLastOutputs failed")
+	assert.Equal(t, 0, outputs.Len(), "expected the outputs to be removed")
+
+	count, err = cp.RemoveInstallationRecords(ctx, "dev", "foo")
+	require.NoError(t, err, "RemoveInstallationRecords failed")
+	assert.Equal(t, int64(0), count, "expected nothing to remove the second time")
+}
+
+func TestInstallationStorageProvider_RemoveLargeOutputs(t *testing.T) {
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	tc.Data.Outputs.Store = config.OutputStoreFilesystem
+	tc.Data.Outputs.Path = "/outputs"
+	cp := NewTestInstallationProviderFor(t, NewTestStore(tc))
+	defer cp.Close()
+	cp.LargeOutputs = NewLargeOutputs(tc.Config)
+
+	createLargeOutput := func(i Installation, action string) Output {
+		run := cp.CreateRun(i.NewRun(action))
+		result := cp.CreateResult(run.NewResult(cnab.StatusSucceeded))
+		output := result.NewOutput("kubeconfig", nil)
+		location, err := cp.LargeOutputs.Save(ctx, result.ID+"/kubeconfig", []byte("my large output"))
+		require.NoError(t, err, "Save failed")
+		output.Location = location
+		return cp.CreateOutput(output)
+	}
+
+	foo := cp.CreateInstallation(NewInstallation("dev", "foo"))
+	installOutput := createLargeOutput(foo, cnab.ActionInstall)
+	upgradeOutput := createLargeOutput(foo, cnab.ActionUpgrade)
+	bar := cp.CreateInstallation(NewInstallation("dev", "bar"))
+	barOutput := createLargeOutput(bar, cnab.ActionInstall)
+
+	err := cp.RemoveRun(ctx, installOutput.RunID)
+	require.NoError(t, err, "RemoveRun failed")
+	_, err = cp.LargeOutputs.Load(ctx, installOutput.Location)
+	require.Error(t, err, "expected the large output of the removed run to be deleted")
+	_, err = cp.LargeOutputs.Load(ctx, upgradeOutput.Location)
+	require.NoError(t, err, "expected the large outputs of the other runs to be kept")
+
+	err = cp.RemoveInstallation(ctx, "dev", "foo")
+	require.NoError(t, err, "RemoveInstallation failed")
+	_, err = cp.LargeOutputs.Load(ctx, upgradeOutput.Location)
+	require.Error(t, err, "expected the large outputs of the removed installation to be deleted")
+
+	_, err = cp.RemoveInstallationRecords(ctx, "dev", "bar")
+	require.NoError(t, err, "RemoveInstallationRecords failed")
+	_, err = cp.LargeOutputs.Load(ctx, barOutput.Location)
+	require.Error(t, err, "expected the large outputs of the removed records to be deleted")
+}
+
+func TestInstallationStorageProvider_InstallationLock(t *testing.T) {
+	cp := NewTestInstallationProvider(t)
+	defer cp.Close()
+	ctx := context.Background()
+
+	lock := NewInstallationLock("dev", "foo", "upgrade", time.Minute)
+	require.NoError(t, cp.AcquireInstallationLock(ctx, lock), "AcquireInstallationLock failed")
+
+	other := NewInstallationLock("dev", "foo", "upgrade", time.Minute)
+	err := cp.AcquireInstallationLock(ctx, other)
+	require.ErrorIs(t, err, ErrInstallationLocked{}, "the installation should already be locked")
+	assert.Equal(t, lock.Owner, err.(ErrInstallationLocked).Lock.Owner)
+
+	require.NoError(t, cp.AcquireInstallationLock(ctx, NewInstallationLock("dev", "bar", "upgrade", time.Minute)),
+		"locks on other installations should not conflict")
+
+	require.ErrorIs(t, cp.RefreshInstallationLock(ctx, other), ErrInstallationLocked{}, "only the owner should refresh the lock")
+	require.NoError(t, cp.RefreshInstallationLock(ctx, lock), "RefreshInstallationLock failed")
+
+	require.NoError(t, cp.ReleaseInstallationLock(ctx, other), "releasing a lock held by another process should be a no-op")
+	require.ErrorIs(t, cp.AcquireInstallationLock(ctx, other), ErrInstallationLocked{}, "the lock should be held by its owner")
+
+	require.NoError(t, cp.ReleaseInstallationLock(ctx, lock), "ReleaseInstallationLock failed")
+	require.NoError(t, cp.AcquireInstallationLock(ctx, other), "the lock should be acquired after it is released")
+
+	t.Run("expired lock", func(t *testing.T) {
+		expired := NewInstallationLock("dev", "baz", "install", -time.Second)
+		require.NoError(t, cp.AcquireInstallationLock(ctx, expired), "AcquireInstallationLock failed")
+
+		replacement := NewInstallationLock("dev", "baz", "upgrade", time.Minute)
+		require.NoError(t, cp.AcquireInstallationLock(ctx, replacement), "an expired lock should be replaced")
+	})
+}
+
+func TestInstallationStorageProvider_MoveInstallation(t *testing.T) {
+	cp := generateInstallationData(t)
+	defer cp.Close()
+	ctx := context.Background()
+
+	_, err := cp.MoveInstallation(ctx, "dev", "foo", "dev", "bar")
+	require.EqualError(t, err, "installation dev/bar already exists")
+
+	moved, err := cp.MoveInstallation(ctx, "dev", "foo", "prod", "foo2")
+	require.NoError(t, err, "MoveInstallation failed")
+	assert.Equal(t, "prod/foo2", moved.String())
+	assert.Equal(t, "prod", moved.Parameters.Namespace, "the internal parameter set should be moved")
+
+	_, err = cp.GetInstallation(ctx, "dev", "foo")
+	require.ErrorIs(t, err, ErrNotFound{}, "the installation should no longer exist with its old name")
+
+	i, err := cp.GetInstallation(ctx, "prod", "foo2")
+	require.NoError(t, err, "GetInstallation failed")
+	assert.Equal(t, "1", i.ID, "the installation id should not change")
+
+	runs, resultsMap, err := cp.ListRuns(ctx, "prod", "foo2")
+	require.NoError(t, err, "ListRuns failed")
+	assert.Len(t, runs, 4, "expected the runs to be moved")
+	assert.Len(t, resultsMap, 4, "expected the results to be moved")
+
+	output, err := cp.GetLastOutput(ctx, "prod", "foo2", "output1")
+	require.NoError(t, err, "GetLastOutput failed")
+	assert.Equal(t, "upgrade output1", string(output.Value), "expected the outputs to be moved")
+
+	runs, _, err = cp.ListRuns(ctx, "dev", "foo")
+	require.NoError(t, err, "ListRuns failed")
+	assert.Empty(t, runs, "expected no runs to remain with the old name")
+}
+
 func TestInstallationStorageProvider_Run(t *testing.T) {
 	cp := generateInstallationData(t)
 
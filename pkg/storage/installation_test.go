@@ -87,6 +87,19 @@ func TestInstallation_ApplyResult(t *testing.T) {
 		assert.Equal(t, &result.Created, inst.Status.Installed, "the installed timestamp should be set to the result timestamp")
 	})
 
+	t.Run("bundle annotations", func(t *testing.T) {
+		inst := NewInstallation("dev", "mybuns")
+		run := inst.NewRun(cnab.ActionInstall)
+		run.Bundle.Custom = map[string]interface{}{
+			cnab.BundleMetadataKey: cnab.BundleMetadata{Annotations: map[string]string{"team": "platform"}},
+		}
+		result := run.NewResult(cnab.StatusSucceeded)
+
+		inst.ApplyResult(run, result)
+
+		assert.Equal(t, map[string]string{"team": "platform"}, inst.Status.BundleAnnotations, "the annotations of the bundle should be cached on the installation")
+	})
+
 	t.Run("uninstall failed", func(t *testing.T) {
 		// Make an installed bundle
 		inst := NewInstallation("dev", "mybuns")
@@ -0,0 +1,439 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"get.porter.sh/porter/pkg"
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/portercontext"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// locationSchemeFile is the scheme of the location of outputs saved to the filesystem.
+	locationSchemeFile = "file"
+
+	// locationSchemeS3 is the scheme of the location of outputs saved to Amazon S3.
+	locationSchemeS3 = "s3"
+
+	// locationSchemeAzureBlob is the scheme of the location of outputs saved to Azure Blob Storage.
+	locationSchemeAzureBlob = "azureblob"
+)
+
+// LargeOutputStore saves the value of large outputs outside of Porter's database.
+// The output document references the value by its location.
+type LargeOutputStore interface {
+	// Save the value of an output, returning the location of the value.
+	Save(ctx context.Context, key string, value []byte) (string, error)
+
+	// Load the value of an output from its location.
+	Load(ctx context.Context, location string) ([]byte, error)
+
+	// Delete the value of an output from its location. Deleting a value that
+	// does not exist is not an error.
+	Delete(ctx context.Context, location string) error
+}
+
+// LargeOutputs saves outputs that are larger than the configured threshold to
+// the large output store selected in the Porter configuration file.
+type LargeOutputs struct {
+	config *config.Config
+
+	// Store overrides the store selected by the configuration file.
+	Store LargeOutputStore
+
+	// stores by location scheme
+	stores map[string]LargeOutputStore
+}
+
+// NewLargeOutputs creates a LargeOutputs that uses the store from the Porter configuration file.
+func NewLargeOutputs(c *config.Config) *LargeOutputs {
+	return &LargeOutputs{
+		config: c,
+		stores: make(map[string]LargeOutputStore),
+	}
+}
+
+// IsEnabled determines if large outputs are saved outside of Porter's database.
+func (o *LargeOutputs) IsEnabled() bool {
+	if o == nil {
+		return false
+	}
+	return o.Store != nil || o.config.Data.Outputs.IsEnabled()
+}
+
+// ShouldSave determines if the value of an output should be saved to the large output store.
+func (o *LargeOutputs) ShouldSave(value []byte) bool {
+	return o.IsEnabled() && len(value) > o.config.Data.Outputs.GetThreshold()
+}
+
+// Save the value of an output to the configured store, returning its location.
+func (o *LargeOutputs) Save(ctx context.Context, key string, value []byte) (string, error) {
+	store, err := o.getStore(o.config.Data.Outputs.Store)
+	if err != nil {
+		return "", err
+	}
+
+	location, err := store.Save(ctx, key, value)
+	if err != nil {
+		return "", fmt.Errorf("could not save the output value to the %s store: %w", o.config.Data.Outputs.Store, err)
+	}
+	return location, nil
+}
+
+// Load the value of an output from its location. The store is selected by the
+// location, so that outputs remain available after the configured store changes.
+func (o *LargeOutputs) Load(ctx context.Context, location string) ([]byte, error) {
+	store, err := o.getStoreForLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := store.Load(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the output value from %s: %w", location, err)
+	}
+	return value, nil
+}
+
+// Delete the value of an output from its location.
+func (o *LargeOutputs) Delete(ctx context.Context, location string) error {
+	store, err := o.getStoreForLocation(location)
+	if err != nil {
+		return err
+	}
+
+	if err = store.Delete(ctx, location); err != nil {
+		return fmt.Errorf("could not delete the output value from %s: %w", location, err)
+	}
+	return nil
+}
+
+// getStoreForLocation returns the store that saved the output to the specified location.
+func (o *LargeOutputs) getStoreForLocation(location string) (LargeOutputStore, error) {
+	scheme, _, _ := strings.Cut(location, "://")
+	switch scheme {
+	case locationSchemeFile:
+		return o.getStore(config.OutputStoreFilesystem)
+	case locationSchemeS3:
+		return o.getStore(config.OutputStoreS3)
+	case locationSchemeAzureBlob:
+		return o.getStore(config.OutputStoreAzureBlob)
+	default:
+		return nil, fmt.Errorf("invalid output location %s", location)
+	}
+}
+
+func (o *LargeOutputs) getStore(name string) (LargeOutputStore, error) {
+	if o.Store != nil {
+		return o.Store, nil
+	}
+	if store, ok := o.stores[name]; ok {
+		return store, nil
+	}
+
+	var store LargeOutputStore
+	cfg := o.config.Data.Outputs
+	switch name {
+	case config.OutputStoreFilesystem:
+		dir := cfg.Path
+		if dir == "" {
+			home, err := o.config.GetHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			dir = filepath.Join(home, "outputs")
+		}
+		store = NewFilesystemOutputStore(o.config.Context, dir)
+	case config.OutputStoreS3:
+		if cfg.S3.Bucket == "" {
+			return nil, errors.New("outputs.s3.bucket must be set in the Porter configuration file to use the s3 output store")
+		}
+		store = NewS3OutputStore(cfg.S3)
+	case config.OutputStoreAzureBlob:
+		if cfg.AzureBlob.Account == "" || cfg.AzureBlob.Container == "" {
+			return nil, errors.New("outputs.azureblob.account and outputs.azureblob.container must be set in the Porter configuration file to use the azureblob output store")
+		}
+		store = NewAzureBlobOutputStore(cfg.AzureBlob)
+	default:
+		return nil, fmt.Errorf("unsupported output store %s, available values are: %s, %s, %s",
+			name, config.OutputStoreFilesystem, config.OutputStoreS3, config.OutputStoreAzureBlob)
+	}
+	o.stores[name] = store
+	return store, nil
+}
+
+var _ LargeOutputStore = filesystemOutputStore{}
+
+// filesystemOutputStore saves outputs to files in a directory.
+type filesystemOutputStore struct {
+	*portercontext.Context
+	dir string
+}
+
+// NewFilesystemOutputStore creates a LargeOutputStore that saves outputs to the specified directory.
+func NewFilesystemOutputStore(c *portercontext.Context, dir string) LargeOutputStore {
+	return filesystemOutputStore{Context: c, dir: dir}
+}
+
+func (s filesystemOutputStore) Save(_ context.Context, key string, value []byte) (string, error) {
+	file := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := s.FileSystem.MkdirAll(filepath.Dir(file), pkg.FileModeDirectory); err != nil {
+		return "", err
+	}
+	if err := s.FileSystem.WriteFile(file, value, pkg.FileModeWritable); err != nil {
+		return "", err
+	}
+	return locationSchemeFile + "://" + filepath.ToSlash(file), nil
+}
+
+func (s filesystemOutputStore) Load(_ context.Context, location string) ([]byte, error) {
+	return s.FileSystem.ReadFile(s.getFile(location))
+}
+
+func (s filesystemOutputStore) Delete(_ context.Context, location string) error {
+	err := s.FileSystem.Remove(s.getFile(location))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s filesystemOutputStore) getFile(location string) string {
+	return filepath.FromSlash(strings.TrimPrefix(location, locationSchemeFile+"://"))
+}
+
+var _ LargeOutputStore = &s3OutputStore{}
+
+// s3OutputStore saves outputs to objects in an Amazon S3 bucket, using the
+// standard AWS credentials chain.
+type s3OutputStore struct {
+	cfg config.S3OutputsConfig
+
+	// client is created on first use
+	client *s3.Client
+}
+
+// NewS3OutputStore creates a LargeOutputStore that saves outputs to an Amazon S3 bucket.
+func NewS3OutputStore(cfg config.S3OutputsConfig) LargeOutputStore {
+	return &s3OutputStore{cfg: cfg}
+}
+
+func (s *s3OutputStore) Save(ctx context.Context, key string, value []byte) (string, error) {
+	client, err := s.getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	key = path.Join(s.cfg.Prefix, key)
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(value),
+	})
+	if err != nil {
+		return "", err
+	}
+	return locationSchemeS3 + "://" + s.cfg.Bucket + "/" + key, nil
+}
+
+func (s *s3OutputStore) Load(ctx context.Context, location string) ([]byte, error) {
+	bucket, key, err := parseS3Location(location)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3OutputStore) Delete(ctx context.Context, location string) error {
+	bucket, key, err := parseS3Location(location)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.getClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// S3 does not return an error when the object does not exist
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func parseS3Location(location string) (bucket string, key string, err error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(location, locationSchemeS3+"://"), "/")
+	if !ok {
+		return "", "", fmt.Errorf("invalid s3 location %s, expected the format s3://BUCKET/KEY", location)
+	}
+	return bucket, key, nil
+}
+
+// getClient creates the s3 client from the AWS configuration. When a custom
+// endpoint is configured, such as MinIO or LocalStack, path-style addressing is used.
+func (s *s3OutputStore) getClient(ctx context.Context) (*s3.Client, error) {
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	awsConfig, err := s.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.client = s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if s.cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(s.cfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return s.client, nil
+}
+
+func (s *s3OutputStore) loadConfig(ctx context.Context) (aws.Config, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if s.cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(s.cfg.Region))
+	}
+	if s.cfg.Profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(s.cfg.Profile))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("could not load the AWS configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		return aws.Config{}, errors.New("the region of the s3 bucket is not set, set outputs.s3.region in the Porter configuration file or the AWS_REGION environment variable")
+	}
+	return cfg, nil
+}
+
+var _ LargeOutputStore = azureBlobOutputStore{}
+
+// azureBlobOutputStore saves outputs to block blobs in an Azure Blob Storage
+// container, authenticating with a shared access signature.
+type azureBlobOutputStore struct {
+	cfg    config.AzureBlobOutputsConfig
+	client *http.Client
+}
+
+// NewAzureBlobOutputStore creates a LargeOutputStore that saves outputs to an Azure Blob Storage container.
+func NewAzureBlobOutputStore(cfg config.AzureBlobOutputsConfig) LargeOutputStore {
+	return azureBlobOutputStore{cfg: cfg, client: http.DefaultClient}
+}
+
+func (s azureBlobOutputStore) Save(ctx context.Context, key string, value []byte) (string, error) {
+	blob := path.Join(s.cfg.Prefix, key)
+	resp, err := s.do(ctx, http.MethodPut, s.cfg.Container, blob, value)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return locationSchemeAzureBlob + "://" + s.cfg.Account + "/" + s.cfg.Container + "/" + blob, nil
+}
+
+func (s azureBlobOutputStore) Load(ctx context.Context, location string) ([]byte, error) {
+	container, blob, err := s.parseLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, container, blob, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (s azureBlobOutputStore) Delete(ctx context.Context, location string) error {
+	container, blob, err := s.parseLocation(location)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, container, blob, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// parseLocation returns the container and blob of an output saved to the configured storage account.
+func (s azureBlobOutputStore) parseLocation(location string) (container string, blob string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(location, locationSchemeAzureBlob+"://"), "/", 3)
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("invalid azureblob location %s, expected the format azureblob://ACCOUNT/CONTAINER/BLOB", location)
+	}
+	if parts[0] != s.cfg.Account {
+		return "", "", fmt.Errorf("the output was saved to the %s storage account but outputs.azureblob.account is %s", parts[0], s.cfg.Account)
+	}
+	return parts[1], parts[2], nil
+}
+
+// do sends a request for a blob, and returns the response when it succeeds.
+func (s azureBlobOutputStore) do(ctx context.Context, method string, container string, blob string, body []byte) (*http.Response, error) {
+	endpoint := s.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", s.cfg.Account)
+	}
+	blobURL, err := url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + container + "/" + blob)
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure blob url: %w", err)
+	}
+	blobURL.RawQuery = strings.TrimPrefix(s.cfg.SASToken, "?")
+
+	req, err := http.NewRequestWithContext(ctx, method, blobURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", "2021-08-06")
+	if method == http.MethodPut {
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Do not include the url in the error, it contains the shared access signature
+		return nil, fmt.Errorf("could not connect to azure blob storage account %s", s.cfg.Account)
+	}
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		// The blob was already deleted
+		return resp, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("azure blob storage returned %s for %s/%s: %s", resp.Status, container, blob, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlobServer stores the bodies of PUT requests, returns them from GET requests
+// and removes them with DELETE requests.
+type fakeBlobServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	blobs    map[string][]byte
+	requests []*http.Request
+}
+
+func newFakeBlobServer(t *testing.T) *fakeBlobServer {
+	s := &fakeBlobServer{blobs: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r)
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			s.blobs[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			body, ok := s.blobs[r.URL.Path]
+			if !ok {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.Write(body)
+		case http.MethodDelete:
+			if _, ok := s.blobs[r.URL.Path]; !ok {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			delete(s.blobs, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestLargeOutputs_IsEnabled(t *testing.T) {
+	c := config.NewTestConfig(t)
+
+	o := NewLargeOutputs(c.Config)
+	assert.False(t, o.IsEnabled(), "large outputs should be disabled by default")
+	assert.False(t, o.ShouldSave(make([]byte, config.DefaultOutputThreshold+1)))
+
+	c.Data.Outputs.Store = config.OutputStoreFilesystem
+	assert.True(t, o.IsEnabled(), "large outputs should be enabled when a store is configured")
+	assert.False(t, o.ShouldSave(make([]byte, config.DefaultOutputThreshold)), "outputs at the threshold should be kept in the database")
+	assert.True(t, o.ShouldSave(make([]byte, config.DefaultOutputThreshold+1)), "outputs over the threshold should be saved to the store")
+
+	var nilOutputs *LargeOutputs
+	assert.False(t, nilOutputs.IsEnabled(), "nil large outputs should be disabled")
+	assert.False(t, nilOutputs.ShouldSave([]byte("value")))
+}
+
+func TestLargeOutputs_Filesystem(t *testing.T) {
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	c.Data.Outputs.Store = config.OutputStoreFilesystem
+	c.Data.Outputs.Path = "/outputs"
+
+	o := NewLargeOutputs(c.Config)
+	location, err := o.Save(ctx, "01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", []byte("my large output"))
+	require.NoError(t, err)
+	assert.Equal(t, "file:///outputs/01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", location)
+
+	value, err := o.Load(ctx, location)
+	require.NoError(t, err)
+	assert.Equal(t, "my large output", string(value))
+
+	require.NoError(t, o.Delete(ctx, location))
+	_, err = o.Load(ctx, location)
+	require.Error(t, err, "the output value should be deleted")
+	require.NoError(t, o.Delete(ctx, location), "deleting a missing output value should not fail")
+}
+
+func TestLargeOutputs_S3(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	server := newFakeBlobServer(t)
+
+	c := config.NewTestConfig(t)
+	c.Data.Outputs.Store = config.OutputStoreS3
+	c.Data.Outputs.S3 = config.S3OutputsConfig{
+		Bucket:   "porter",
+		Prefix:   "outputs",
+		Region:   "us-east-1",
+		Endpoint: server.URL,
+	}
+
+	o := NewLargeOutputs(c.Config)
+	location, err := o.Save(ctx, "01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", []byte("my large output"))
+	require.NoError(t, err)
+	assert.Equal(t, "s3://porter/outputs/01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", location)
+
+	value, err := o.Load(ctx, location)
+	require.NoError(t, err)
+	assert.Equal(t, "my large output", string(value))
+
+	require.Len(t, server.requests, 2)
+	put := server.requests[0]
+	assert.Equal(t, http.MethodPut, put.Method)
+	assert.Equal(t, "/porter/outputs/01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", put.URL.Path)
+	assert.True(t, strings.HasPrefix(put.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), "the request should be signed")
+	assert.Contains(t, put.Header.Get("Authorization"), "/us-east-1/s3/aws4_request")
+	assert.NotEmpty(t, put.Header.Get("X-Amz-Content-Sha256"))
+
+	_, err = o.Load(ctx, "s3://porter/missing")
+	require.ErrorContains(t, err, "StatusCode: 404")
+
+	require.NoError(t, o.Delete(ctx, location))
+	del := server.requests[len(server.requests)-1]
+	assert.Equal(t, http.MethodDelete, del.Method)
+	assert.Equal(t, "/porter/outputs/01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", del.URL.Path)
+	assert.Empty(t, server.blobs, "the output value should be deleted")
+}
+
+func TestLargeOutputs_AzureBlob(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeBlobServer(t)
+
+	c := config.NewTestConfig(t)
+	c.Data.Outputs.Store = config.OutputStoreAzureBlob
+	c.Data.Outputs.AzureBlob = config.AzureBlobOutputsConfig{
+		Account:   "porterstorage",
+		Container: "outputs",
+		SASToken:  "?sv=2021-08-06&sig=abc123",
+		Endpoint:  server.URL,
+	}
+
+	o := NewLargeOutputs(c.Config)
+	location, err := o.Save(ctx, "01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", []byte("my large output"))
+	require.NoError(t, err)
+	assert.Equal(t, "azureblob://porterstorage/outputs/01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", location)
+
+	value, err := o.Load(ctx, location)
+	require.NoError(t, err)
+	assert.Equal(t, "my large output", string(value))
+
+	require.Len(t, server.requests, 2)
+	put := server.requests[0]
+	assert.Equal(t, http.MethodPut, put.Method)
+	assert.Equal(t, "/outputs/01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig", put.URL.Path)
+	assert.Equal(t, "abc123", put.URL.Query().Get("sig"), "the shared access signature should be passed in the query string")
+	assert.Equal(t, "BlockBlob", put.Header.Get("x-ms-blob-type"))
+
+	_, err = o.Load(ctx, "azureblob://otheraccount/outputs/01FZVC5AVP8Z7A78CSCP1EJ604/kubeconfig")
+	require.ErrorContains(t, err, "otheraccount storage account")
+
+	require.NoError(t, o.Delete(ctx, location))
+	assert.Empty(t, server.blobs, "the output value should be deleted")
+	require.NoError(t, o.Delete(ctx, location), "deleting a missing output value should not fail")
+}
+
+func TestLargeOutputs_InvalidConfiguration(t *testing.T) {
+	ctx := context.Background()
+	c := config.NewTestConfig(t)
+	o := NewLargeOutputs(c.Config)
+
+	c.Data.Outputs.Store = "ftp"
+	_, err := o.Save(ctx, "key", []byte("value"))
+	require.ErrorContains(t, err, "unsupported output store ftp")
+
+	c.Data.Outputs.Store = config.OutputStoreS3
+	_, err = o.Save(ctx, "key", []byte("value"))
+	require.ErrorContains(t, err, "outputs.s3.bucket must be set")
+
+	_, err = o.Load(ctx, "ftp://example.com/key")
+	require.ErrorContains(t, err, "invalid output location")
+}
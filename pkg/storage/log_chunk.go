@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"time"
+)
+
+// CollectionLogs stores the logs of a run, as they are written by the bundle.
+const CollectionLogs = "logs"
+
+var _ Document = LogChunk{}
+
+// LogChunk is a portion of the logs generated by a run. Chunks are saved while
+// the bundle is executing so that the logs can be followed from another process
+// before the run completes.
+type LogChunk struct {
+	// Namespace of the installation.
+	Namespace string `json:"namespace"`
+
+	// Installation name that generated the logs.
+	Installation string `json:"installation"`
+
+	// RunID is the run that generated the logs.
+	RunID string `json:"runId"`
+
+	// Sequence of the chunk within the run, starting at 1.
+	Sequence int `json:"sequence"`
+
+	// Timestamp when the chunk was saved.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Data is the log content.
+	Data string `json:"data"`
+}
+
+func (c LogChunk) DefaultDocumentFilter() map[string]interface{} {
+	return map[string]interface{}{"runId": c.RunID, "sequence": c.Sequence}
+}
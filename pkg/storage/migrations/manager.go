@@ -112,6 +112,16 @@ Once your data has been backed up, run the following command to perform the migr
 		if err != nil {
 			return err
 		}
+
+		err = storage.EnsureNamespaceIndices(ctx, m.store)
+		if err != nil {
+			return err
+		}
+
+		err = storage.EnsureAuditIndices(ctx, m.store)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
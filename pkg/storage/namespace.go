@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var _ Document = Namespace{}
+
+// Namespace holds the metadata of a namespace, which groups installations,
+// credential sets and parameter sets. Resources may be defined in a namespace
+// that was not created with porter namespaces create, the metadata is optional.
+type Namespace struct {
+	// Name of the namespace.
+	Name string `json:"name" yaml:"name" toml:"name"`
+
+	// Description of the namespace, such as the team or environment that uses it.
+	Description string `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+
+	// Labels applied to the namespace.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" toml:"labels,omitempty"`
+
+	// Protected namespaces require --force to delete their installations,
+	// credential sets and parameter sets.
+	Protected bool `json:"protected,omitempty" yaml:"protected,omitempty" toml:"protected,omitempty"`
+
+	// Limits on the resources defined in the namespace.
+	Limits NamespaceLimits `json:"limits,omitempty" yaml:"limits,omitempty" toml:"limits,omitempty"`
+
+	// Status of the namespace that is set by Porter.
+	Status NamespaceStatus `json:"status" yaml:"status" toml:"status"`
+}
+
+// NamespaceStatus contains additional status metadata that has been set by Porter.
+type NamespaceStatus struct {
+	// Created timestamp.
+	Created time.Time `json:"created" yaml:"created" toml:"created"`
+
+	// Modified timestamp.
+	Modified time.Time `json:"modified" yaml:"modified" toml:"modified"`
+}
+
+// NamespaceLimits are the limits on the resources defined in a namespace.
+// A limit of zero means that the resource is not limited.
+type NamespaceLimits struct {
+	// MaxInstallations is the maximum number of installations in the namespace.
+	MaxInstallations int64 `json:"maxInstallations,omitempty" yaml:"maxInstallations,omitempty" toml:"maxInstallations,omitempty"`
+}
+
+// NewNamespace creates a new Namespace with the required fields initialized.
+func NewNamespace(name string) Namespace {
+	now := time.Now()
+	return Namespace{
+		Name: name,
+		Status: NamespaceStatus{
+			Created:  now,
+			Modified: now,
+		},
+	}
+}
+
+func (n Namespace) DefaultDocumentFilter() map[string]interface{} {
+	return map[string]interface{}{"name": n.Name}
+}
+
+// Validate the namespace name.
+func (n Namespace) Validate() error {
+	if n.Name == "" {
+		return errors.New("the namespace name is required, resources that are not in a namespace are in the global namespace")
+	}
+	if strings.ContainsAny(n.Name, "*/") || strings.TrimSpace(n.Name) != n.Name {
+		return fmt.Errorf("invalid namespace name %q, the name cannot contain * or / characters, or leading and trailing whitespace", n.Name)
+	}
+	if n.Limits.MaxInstallations < 0 {
+		return fmt.Errorf("invalid limits.maxInstallations %d, the limit cannot be negative", n.Limits.MaxInstallations)
+	}
+	return nil
+}
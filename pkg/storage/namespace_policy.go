@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var _ Store = NamespacePolicyStore{}
+
+// NamespacePolicyStore wraps a Store and enforces the protection and limits
+// of namespaces when installations, credential sets and parameter sets are
+// saved or removed. This allows several teams to share a datastore without
+// deleting each other's resources by accident.
+type NamespacePolicyStore struct {
+	Store
+}
+
+// NewNamespacePolicyStore wraps a Store, enforcing the policies defined on namespaces.
+func NewNamespacePolicyStore(store Store) NamespacePolicyStore {
+	return NamespacePolicyStore{Store: store}
+}
+
+type contextKeyForce struct{}
+
+// WithForce returns a context that allows resources to be removed from a
+// protected namespace, when the user explicitly overrides the protection,
+// e.g. with --force or --allow-protected.
+func WithForce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyForce{}, true)
+}
+
+func isForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(contextKeyForce{}).(bool)
+	return forced
+}
+
+// ErrNamespaceProtected indicates that a resource cannot be removed from a
+// protected namespace without overriding the protection.
+// You can test for this error using errors.Is(err, storage.ErrNamespaceProtected{})
+type ErrNamespaceProtected struct {
+	Namespace  string
+	Collection string
+}
+
+func (e ErrNamespaceProtected) Error() string {
+	return fmt.Sprintf("namespace %s is protected, %s cannot be deleted from it unless the command is run with the %s flag",
+		e.Namespace, resourceName(e.Collection), protectionOverrideFlag(e.Collection))
+}
+
+func (e ErrNamespaceProtected) Is(err error) bool {
+	_, ok := err.(ErrNamespaceProtected)
+	return ok
+}
+
+// ErrNamespaceLimitExceeded indicates that a namespace already has the maximum
+// number of installations.
+// You can test for this error using errors.Is(err, storage.ErrNamespaceLimitExceeded{})
+type ErrNamespaceLimitExceeded struct {
+	Namespace        string
+	MaxInstallations int64
+}
+
+func (e ErrNamespaceLimitExceeded) Error() string {
+	return fmt.Sprintf("namespace %s has reached its limit of %d installations", e.Namespace, e.MaxInstallations)
+}
+
+func (e ErrNamespaceLimitExceeded) Is(err error) bool {
+	_, ok := err.(ErrNamespaceLimitExceeded)
+	return ok
+}
+
+// resourceName returns the name of the resources stored in a collection, for error messages.
+func resourceName(collection string) string {
+	switch collection {
+	case CollectionCredentials:
+		return "credential sets"
+	case CollectionParameters:
+		return "parameter sets"
+	default:
+		return collection
+	}
+}
+
+// protectionOverrideFlag returns the flag that allows resources stored in a
+// collection to be removed from a protected namespace. Installations use a
+// dedicated flag because --force already skips the checks for unsafe deletes.
+func protectionOverrideFlag(collection string) string {
+	if collection == CollectionInstallations {
+		return "--allow-protected"
+	}
+	return "--force"
+}
+
+// isNamespaced determines if the policies of a namespace apply to the
+// documents in a collection.
+func isNamespaced(collection string) bool {
+	switch collection {
+	case CollectionInstallations, CollectionCredentials, CollectionParameters:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s NamespacePolicyStore) Insert(ctx context.Context, collection string, opts InsertOptions) error {
+	if collection == CollectionInstallations {
+		pluginOpts, err := opts.ToPluginOptions(collection)
+		if err != nil {
+			return err
+		}
+
+		added := make(map[string]int64)
+		for _, doc := range pluginOpts.Documents {
+			namespace, _ := doc["namespace"].(string)
+			added[namespace]++
+		}
+		for namespace, count := range added {
+			if err = s.checkLimits(ctx, namespace, count); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.Store.Insert(ctx, collection, opts)
+}
+
+func (s NamespacePolicyStore) Update(ctx context.Context, collection string, opts UpdateOptions) error {
+	if !isNamespaced(collection) {
+		return s.Store.Update(ctx, collection, opts)
+	}
+
+	pluginOpts, err := opts.ToPluginOptions(collection)
+	if err != nil {
+		return err
+	}
+
+	// An update that keeps the document in the same namespace does not change
+	// the resources in the namespace, and is the most common, so avoid looking
+	// up the existing document.
+	newNamespace, _ := pluginOpts.Document["namespace"].(string)
+	oldNamespace, hasNamespace := pluginOpts.Filter["namespace"].(string)
+	if !opts.Upsert && hasNamespace && oldNamespace == newNamespace {
+		return s.Store.Update(ctx, collection, opts)
+	}
+
+	var existing []struct {
+		Namespace string `json:"namespace"`
+	}
+	findOpts := FindOptions{Filter: pluginOpts.Filter, Limit: 1, Select: bson.D{{Key: "namespace", Value: 1}}}
+	if err = s.Store.Find(ctx, collection, findOpts, &existing); err != nil {
+		return err
+	}
+
+	switch {
+	case len(existing) == 0 && opts.Upsert:
+		// The document is created
+		err = s.checkLimits(ctx, newNamespace, 1)
+	case len(existing) > 0 && existing[0].Namespace != newNamespace:
+		// The document is moved to another namespace
+		if err = s.checkProtected(ctx, collection, existing[0].Namespace); err == nil && collection == CollectionInstallations {
+			err = s.checkLimits(ctx, newNamespace, 1)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.Store.Update(ctx, collection, opts)
+}
+
+func (s NamespacePolicyStore) Remove(ctx context.Context, collection string, opts RemoveOptions) error {
+	if !isNamespaced(collection) || isForced(ctx) {
+		return s.Store.Remove(ctx, collection, opts)
+	}
+
+	findOpts := FindOptions{
+		Filter: opts.ToPluginOptions(collection).Filter,
+		Select: bson.D{{Key: "namespace", Value: 1}},
+	}
+	if !opts.All {
+		findOpts.Limit = 1
+	}
+	var existing []struct {
+		Namespace string `json:"namespace"`
+	}
+	if err := s.Store.Find(ctx, collection, findOpts, &existing); err != nil {
+		return err
+	}
+
+	checked := make(map[string]bool)
+	for _, doc := range existing {
+		if checked[doc.Namespace] {
+			continue
+		}
+		checked[doc.Namespace] = true
+		if err := s.checkProtected(ctx, collection, doc.Namespace); err != nil {
+			return err
+		}
+	}
+
+	return s.Store.Remove(ctx, collection, opts)
+}
+
+// getNamespace returns the metadata of a namespace, and false when the
+// namespace was not created.
+func (s NamespacePolicyStore) getNamespace(ctx context.Context, name string) (Namespace, bool, error) {
+	if name == "" {
+		// The global namespace does not have any policies
+		return Namespace{}, false, nil
+	}
+
+	ns, err := NewNamespaceStore(s.Store).GetNamespace(ctx, name)
+	if errors.Is(err, ErrNotFound{}) {
+		return Namespace{}, false, nil
+	}
+	if err != nil {
+		return Namespace{}, false, fmt.Errorf("could not read the policies of namespace %s: %w", name, err)
+	}
+	return ns, true, nil
+}
+
+// checkProtected returns an error when documents cannot be removed from the
+// namespace, because it is protected and the user did not override the protection.
+func (s NamespacePolicyStore) checkProtected(ctx context.Context, collection string, namespace string) error {
+	if isForced(ctx) {
+		return nil
+	}
+
+	ns, found, err := s.getNamespace(ctx, namespace)
+	if err != nil || !found {
+		return err
+	}
+	if ns.Protected {
+		return ErrNamespaceProtected{Namespace: namespace, Collection: collection}
+	}
+	return nil
+}
+
+// checkLimits returns an error when adding installations to the namespace
+// would exceed its limits.
+func (s NamespacePolicyStore) checkLimits(ctx context.Context, namespace string, added int64) error {
+	ns, found, err := s.getNamespace(ctx, namespace)
+	if err != nil || !found || ns.Limits.MaxInstallations == 0 {
+		return err
+	}
+
+	count, err := s.Store.Count(ctx, CollectionInstallations, CountOptions{Filter: bson.M{"namespace": namespace}})
+	if err != nil {
+		return fmt.Errorf("could not count the installations in namespace %s: %w", namespace, err)
+	}
+	if count+added > ns.Limits.MaxInstallations {
+		return ErrNamespaceLimitExceeded{Namespace: namespace, MaxInstallations: ns.Limits.MaxInstallations}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespacePolicyStore_Protected(t *testing.T) {
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	testStore := NewTestStore(tc)
+	defer testStore.Close()
+
+	prod := NewNamespace("prod")
+	prod.Protected = true
+	require.NoError(t, NewNamespaceStore(testStore).InsertNamespace(ctx, prod))
+
+	cp := NewTestCredentialProviderFor(t, testStore, secrets.NewTestSecretsProvider())
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("prod", "github")))
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("dev", "github")))
+
+	err := cp.RemoveCredentialSet(ctx, "prod", "github")
+	require.ErrorIs(t, err, ErrNamespaceProtected{})
+	assert.EqualError(t, err, "namespace prod is protected, credential sets cannot be deleted from it unless the command is run with the --force flag")
+
+	require.NoError(t, cp.RemoveCredentialSet(ctx, "dev", "github"), "a namespace that was not created should not be protected")
+	require.NoError(t, cp.RemoveCredentialSet(WithForce(ctx), "prod", "github"), "--force should remove the credential set from a protected namespace")
+
+	_, err = cp.GetCredentialSet(ctx, "prod", "github")
+	require.ErrorIs(t, err, ErrNotFound{})
+}
+
+func TestNamespacePolicyStore_MaxInstallations(t *testing.T) {
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	testStore := NewTestStore(tc)
+	defer testStore.Close()
+
+	dev := NewNamespace("dev")
+	dev.Limits.MaxInstallations = 1
+	require.NoError(t, NewNamespaceStore(testStore).InsertNamespace(ctx, dev))
+
+	ip := NewTestInstallationProviderFor(t, testStore)
+	require.NoError(t, ip.InsertInstallation(ctx, NewInstallation("dev", "mysql")))
+
+	err := ip.InsertInstallation(ctx, NewInstallation("dev", "redis"))
+	require.ErrorIs(t, err, ErrNamespaceLimitExceeded{})
+	assert.EqualError(t, err, "namespace dev has reached its limit of 1 installations")
+
+	err = ip.UpsertInstallation(ctx, NewInstallation("dev", "redis"))
+	require.ErrorIs(t, err, ErrNamespaceLimitExceeded{}, "an upsert that creates an installation should respect the limit")
+
+	mysql, err := ip.GetInstallation(ctx, "dev", "mysql")
+	require.NoError(t, err)
+	mysql.Labels = map[string]string{"team": "red"}
+	require.NoError(t, ip.UpsertInstallation(ctx, mysql), "updating an existing installation should not count against the limit")
+
+	require.NoError(t, ip.InsertInstallation(ctx, NewInstallation("", "redis")), "the global namespace should not have a limit")
+}
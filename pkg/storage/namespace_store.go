@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+
+	"get.porter.sh/porter/pkg/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var _ NamespaceProvider = &NamespaceStore{}
+
+// CollectionNamespaces stores the metadata of namespaces.
+const CollectionNamespaces = "namespaces"
+
+// NamespaceProvider is Porter's interface for managing namespaces.
+type NamespaceProvider interface {
+	InsertNamespace(ctx context.Context, ns Namespace) error
+	GetNamespace(ctx context.Context, name string) (Namespace, error)
+	ListNamespaces(ctx context.Context, opts ListOptions) ([]Namespace, error)
+	UpdateNamespace(ctx context.Context, ns Namespace) error
+	RemoveNamespace(ctx context.Context, name string) error
+
+	// CountByNamespace returns the number of documents in a collection, such
+	// as installations, in each namespace.
+	CountByNamespace(ctx context.Context, collection string) (map[string]int64, error)
+
+	// CheckProtected returns ErrNamespaceProtected when documents in a collection
+	// cannot be removed from the namespace, unless the context allows it with WithForce.
+	CheckProtected(ctx context.Context, collection string, namespace string) error
+}
+
+// NamespaceStore provides access to namespaces by instantiating plugins that
+// implement CRUD storage.
+type NamespaceStore struct {
+	Documents Store
+}
+
+func NewNamespaceStore(storage Store) *NamespaceStore {
+	return &NamespaceStore{
+		Documents: storage,
+	}
+}
+
+// EnsureNamespaceIndices creates indices on the namespaces collection.
+func EnsureNamespaceIndices(ctx context.Context, store Store) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	span.Debug("Initializing namespace collection indices")
+	indices := EnsureIndexOptions{
+		Indices: []Index{
+			// query namespaces by name
+			{Collection: CollectionNamespaces, Keys: []string{"name"}, Unique: true},
+		},
+	}
+	err := store.EnsureIndex(ctx, indices)
+	return span.Error(err)
+}
+
+func (s NamespaceStore) InsertNamespace(ctx context.Context, ns Namespace) error {
+	opts := InsertOptions{
+		Documents: []interface{}{ns},
+	}
+	return s.Documents.Insert(ctx, CollectionNamespaces, opts)
+}
+
+func (s NamespaceStore) GetNamespace(ctx context.Context, name string) (Namespace, error) {
+	var out Namespace
+	opts := FindOptions{
+		Filter: map[string]interface{}{
+			"name": name,
+		},
+	}
+	err := s.Documents.FindOne(ctx, CollectionNamespaces, opts, &out)
+	return out, err
+}
+
+// ListNamespaces returns the namespaces that match the name and labels of the
+// list options. The namespace of the list options is ignored.
+func (s NamespaceStore) ListNamespaces(ctx context.Context, listOptions ListOptions) ([]Namespace, error) {
+	listOptions.Namespace = "*"
+	opts := listOptions.ToFindOptions()
+	opts.Sort = []string{"name"}
+
+	var out []Namespace
+	err := s.Documents.Find(ctx, CollectionNamespaces, opts, &out)
+	return out, err
+}
+
+func (s NamespaceStore) UpdateNamespace(ctx context.Context, ns Namespace) error {
+	opts := UpdateOptions{
+		Document: ns,
+	}
+	return s.Documents.Update(ctx, CollectionNamespaces, opts)
+}
+
+func (s NamespaceStore) RemoveNamespace(ctx context.Context, name string) error {
+	opts := RemoveOptions{
+		Filter: map[string]interface{}{
+			"name": name,
+		},
+	}
+	return s.Documents.Remove(ctx, CollectionNamespaces, opts)
+}
+
+func (s NamespaceStore) CountByNamespace(ctx context.Context, collection string) (map[string]int64, error) {
+	opts := AggregateOptions{
+		Pipeline: []bson.D{
+			{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$namespace"},
+				{Key: "count", Value: bson.M{"$sum": 1}},
+			}}},
+		},
+	}
+
+	var results []struct {
+		Namespace string `json:"_id"`
+		Count     int64  `json:"count"`
+	}
+	if err := s.Documents.Aggregate(ctx, collection, opts, &results); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(results))
+	for _, result := range results {
+		counts[result.Namespace] = result.Count
+	}
+	return counts, nil
+}
+
+func (s NamespaceStore) CheckProtected(ctx context.Context, collection string, namespace string) error {
+	return NewNamespacePolicyStore(s.Documents).checkProtected(ctx, collection, namespace)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceStore_CRUD(t *testing.T) {
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	testStore := NewTestStore(tc)
+	defer testStore.Close()
+	s := NewNamespaceStore(testStore)
+
+	dev := NewNamespace("dev")
+	dev.Description = "Development environment"
+	dev.Labels = map[string]string{"team": "red"}
+	require.NoError(t, s.InsertNamespace(ctx, dev))
+	require.NoError(t, s.InsertNamespace(ctx, NewNamespace("prod")))
+
+	ns, err := s.GetNamespace(ctx, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "Development environment", ns.Description)
+
+	namespaces, err := s.ListNamespaces(ctx, ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, namespaces, 2, "the namespace of the list options should be ignored")
+	assert.Equal(t, "dev", namespaces[0].Name)
+	assert.Equal(t, "prod", namespaces[1].Name)
+
+	namespaces, err = s.ListNamespaces(ctx, ListOptions{Labels: map[string]string{"team": "red"}})
+	require.NoError(t, err)
+	require.Len(t, namespaces, 1)
+	assert.Equal(t, "dev", namespaces[0].Name)
+
+	require.NoError(t, s.RemoveNamespace(ctx, "dev"))
+	_, err = s.GetNamespace(ctx, "dev")
+	require.ErrorIs(t, err, ErrNotFound{})
+}
+
+func TestNamespaceStore_CountByNamespace(t *testing.T) {
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	testStore := NewTestStore(tc)
+	defer testStore.Close()
+	s := NewNamespaceStore(testStore)
+
+	cp := NewTestCredentialProviderFor(t, testStore, secrets.NewTestSecretsProvider())
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("dev", "github")))
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("dev", "azure")))
+	require.NoError(t, cp.InsertCredentialSet(ctx, NewCredentialSet("", "aws")))
+
+	counts, err := s.CountByNamespace(ctx, CollectionCredentials)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"dev": 2, "": 1}, counts)
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespace_Validate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: "dev"},
+		{name: "team-a.dev"},
+		{name: "", wantErr: "the namespace name is required"},
+		{name: "*", wantErr: "invalid namespace name"},
+		{name: "dev/test", wantErr: "invalid namespace name"},
+		{name: " dev", wantErr: "invalid namespace name"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewNamespace(tc.name).Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -21,6 +21,14 @@ type Output struct {
 	// Key holds the secret key to retrieve a sensitive output value
 	Key   string `json:"key"`
 	Value []byte `json:"value"`
+
+	// Encrypted indicates that Value holds a sensitive output value that was
+	// encrypted with a data key, instead of being saved to the secret store.
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// Location of an output value that was too large to store in the database,
+	// and was saved to the large output store instead.
+	Location string `json:"location,omitempty"`
 }
 
 func (o Output) DefaultDocumentFilter() map[string]interface{} {
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -22,6 +23,12 @@ const (
 type ParameterStore struct {
 	Documents Store
 	Secrets   secrets.Store
+
+	// Encryptor decrypts parameter values that were encrypted before they were saved.
+	Encryptor *Encryptor
+
+	// LargeOutputs loads the value of outputs that were saved to the large output store.
+	LargeOutputs *LargeOutputs
 }
 
 func NewParameterStore(storage Store, secrets secrets.Store) *ParameterStore {
@@ -56,7 +63,7 @@ func (s ParameterStore) ResolveAll(ctx context.Context, params ParameterSet) (se
 	var resolveErrors error
 
 	for _, param := range params.Parameters {
-		value, err := s.Secrets.Resolve(ctx, param.Source.Key, param.Source.Value)
+		value, err := s.resolve(ctx, params.Namespace, param.Source)
 		if err != nil {
 			resolveErrors = multierror.Append(resolveErrors, fmt.Errorf("unable to resolve parameter %s.%s from %s %s: %w", params.Name, param.Name, param.Source.Key, param.Source.Value, err))
 		}
@@ -67,11 +74,72 @@ func (s ParameterStore) ResolveAll(ctx context.Context, params ParameterSet) (se
 	return resolvedParams, resolveErrors
 }
 
+// resolve the value of a parameter source, decrypting values that were encrypted before they were saved.
+func (s ParameterStore) resolve(ctx context.Context, namespace string, source secrets.Source) (string, error) {
+	switch source.Key {
+	case SourceEncrypted:
+		if s.Encryptor == nil {
+			return "", ErrEncryptionNotConfigured
+		}
+		return s.Encryptor.Decrypt(ctx, source.Value)
+	case secrets.SourceOutput:
+		return s.resolveInstallationOutput(ctx, namespace, source)
+	}
+
+	return s.Secrets.Resolve(ctx, source.Key, source.Value)
+}
+
+// resolveInstallationOutput returns the last value of an output generated by another installation.
+// When the installation does not specify a namespace, it is assumed to be in the same namespace as
+// the parameter set.
+func (s ParameterStore) resolveInstallationOutput(ctx context.Context, namespace string, source secrets.Source) (string, error) {
+	installationNamespace, installationName := ParseInstallationReference(namespace, source.Installation)
+	if installationName == "" {
+		return "", fmt.Errorf("the %s source must specify the installation that generated the output", secrets.SourceOutput)
+	}
+
+	installations := NewInstallationStore(s.Documents)
+	output, err := installations.GetLastOutput(ctx, installationNamespace, installationName, source.Value)
+	if err != nil {
+		if errors.Is(err, ErrNotFound{}) {
+			return "", fmt.Errorf("installation %s/%s has no output named %s", installationNamespace, installationName, source.Value)
+		}
+		return "", fmt.Errorf("could not retrieve output %s from installation %s/%s: %w", source.Value, installationNamespace, installationName, err)
+	}
+
+	// The value may be saved in the secret store, encrypted, or saved to the large output store
+	sanitizer := NewSanitizer(&s, s.Secrets)
+	sanitizer.UseEncryption(s.Encryptor)
+	sanitizer.UseLargeOutputs(s.LargeOutputs)
+	output, err = sanitizer.RestoreOutput(ctx, output)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve output %s from installation %s/%s: %w", source.Value, installationNamespace, installationName, err)
+	}
+	return string(output.Value), nil
+}
+
+// ParseInstallationReference splits a reference to an installation, NAMESPACE/NAME or NAME,
+// into its namespace and name. When the reference does not include a namespace, the
+// default namespace is used.
+func ParseInstallationReference(defaultNamespace string, ref string) (string, string) {
+	if namespace, name, ok := strings.Cut(ref, "/"); ok {
+		return namespace, name
+	}
+	return defaultNamespace, ref
+}
+
 func (s ParameterStore) Validate(ctx context.Context, params ParameterSet) error {
-	validSources := []string{secrets.SourceSecret, host.SourceValue, host.SourceEnv, host.SourcePath, host.SourceCommand}
+	validSources := []string{secrets.SourceSecret, host.SourceValue, host.SourceEnv, host.SourcePath, host.SourceCommand, secrets.SourceOutput}
 	var errors error
 
 	for _, cs := range params.Parameters {
+		if cs.Source.Key == secrets.SourceOutput && cs.Source.Installation == "" {
+			errors = multierror.Append(errors, fmt.Errorf(
+				"parameter %s uses the %s source without specifying the %s that generated the output",
+				cs.Name, secrets.SourceOutput, secrets.SourceInstallation,
+			))
+		}
+
 		valid := false
 		for _, validSource := range validSources {
 			if cs.Source.Key == validSource {
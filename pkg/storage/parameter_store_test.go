@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/secrets"
 	"github.com/stretchr/testify/require"
 )
@@ -144,6 +145,68 @@ func TestParameterStorage_ResolveAll(t *testing.T) {
 	})
 }
 
+func TestParameterStorage_ResolveAll_InstallationOutput(t *testing.T) {
+	ctx := context.Background()
+	paramStore := NewTestParameterProvider(t)
+	defer paramStore.Close()
+
+	installations := NewInstallationStore(paramStore.TestDocuments)
+	result := NewResult()
+	result.Namespace = "infra"
+	result.Installation = "network"
+	result.RunID = "run1"
+	require.NoError(t, installations.InsertOutput(ctx, result.NewOutput("vnet_id", []byte("vnet-123"))))
+
+	// Sensitive outputs are saved in the secret store, and the output only has a reference to the secret
+	sensitiveOutput := result.NewOutput("connstr", nil)
+	sensitiveOutput.Key = "run1-connstr"
+	require.NoError(t, installations.InsertOutput(ctx, sensitiveOutput))
+	paramStore.AddSecret("run1-connstr", "top-secret")
+
+	// Large outputs are saved to the large output store, and the output only has its location
+	tc := config.NewTestConfig(t)
+	paramStore.LargeOutputs = NewLargeOutputs(tc.Config)
+	paramStore.LargeOutputs.Store = NewFilesystemOutputStore(tc.Context, "/outputs")
+	largeOutput := result.NewOutput("kubeconfig", nil)
+	location, err := paramStore.LargeOutputs.Store.Save(ctx, "run1/kubeconfig", []byte("apiVersion: v1"))
+	require.NoError(t, err)
+	largeOutput.Location = location
+	require.NoError(t, installations.InsertOutput(ctx, largeOutput))
+
+	// Encrypted outputs are saved with the ciphertext as the value
+	tc.Data.Encryption.Key = "my-secret-key"
+	paramStore.Encryptor = NewEncryptor(tc.Config, nil)
+	paramStore.Encryptor.keys["key1"] = []byte("0123456789abcdef0123456789abcdef")
+	paramStore.Encryptor.activeKey = "key1"
+	encrypted, err := paramStore.Encryptor.Encrypt(ctx, "top-secret-token")
+	require.NoError(t, err)
+	encryptedOutput := result.NewOutput("token", []byte(encrypted))
+	encryptedOutput.Encrypted = true
+	require.NoError(t, installations.InsertOutput(ctx, encryptedOutput))
+
+	t.Run("resolve outputs", func(t *testing.T) {
+		pset := NewParameterSet("infra", "myparams",
+			secrets.Strategy{Name: "vnet", Source: secrets.Source{Key: secrets.SourceOutput, Value: "vnet_id", Installation: "network"}},
+			secrets.Strategy{Name: "connstr", Source: secrets.Source{Key: secrets.SourceOutput, Value: "connstr", Installation: "infra/network"}},
+			secrets.Strategy{Name: "kubeconfig", Source: secrets.Source{Key: secrets.SourceOutput, Value: "kubeconfig", Installation: "network"}},
+			secrets.Strategy{Name: "token", Source: secrets.Source{Key: secrets.SourceOutput, Value: "token", Installation: "network"}},
+		)
+
+		resolved, err := paramStore.ResolveAll(ctx, pset)
+		require.NoError(t, err)
+		require.Equal(t, secrets.Set{"vnet": "vnet-123", "connstr": "top-secret", "kubeconfig": "apiVersion: v1", "token": "top-secret-token"}, resolved)
+	})
+
+	t.Run("missing output", func(t *testing.T) {
+		pset := NewParameterSet("app", "myparams",
+			secrets.Strategy{Name: "vnet", Source: secrets.Source{Key: secrets.SourceOutput, Value: "vnet_id", Installation: "network"}},
+		)
+
+		_, err := paramStore.ResolveAll(ctx, pset)
+		require.ErrorContains(t, err, "unable to resolve parameter myparams.vnet from output vnet_id: installation app/network has no output named vnet_id")
+	})
+}
+
 func TestParameterStorage_Validate(t *testing.T) {
 	t.Run("valid sources", func(t *testing.T) {
 		s := ParameterStore{}
@@ -178,6 +241,13 @@ func TestParameterStorage_Validate(t *testing.T) {
 					Key:   "secret",
 					Value: "secret",
 				},
+			},
+			secrets.Strategy{
+				Source: secrets.Source{
+					Key:          "output",
+					Value:        "vnet_id",
+					Installation: "infra/network",
+				},
 			})
 
 		err := s.Validate(context.Background(), testParameterSet)
@@ -203,4 +273,18 @@ func TestParameterStorage_Validate(t *testing.T) {
 		err := s.Validate(context.Background(), testParameterSet)
 		require.Error(t, err, "Validate returned errors")
 	})
+	t.Run("output source without an installation", func(t *testing.T) {
+		s := ParameterStore{}
+		testParameterSet := NewParameterSet("", "myparams",
+			secrets.Strategy{
+				Name: "vnet",
+				Source: secrets.Source{
+					Key:   "output",
+					Value: "vnet_id",
+				},
+			})
+
+		err := s.Validate(context.Background(), testParameterSet)
+		require.ErrorContains(t, err, "parameter vnet uses the output source without specifying the installation that generated the output")
+	})
 }
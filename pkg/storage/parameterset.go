@@ -33,6 +33,11 @@ type ParameterSetSpec struct {
 	// Labels applied to the parameter set.
 	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" toml:"labels,omitempty"`
 
+	// Inherits is an ordered list of parameter set names whose parameters are
+	// merged into this parameter set. Parameters defined on this parameter set,
+	// or on a parent later in the list, take precedence.
+	Inherits []string `json:"inherits,omitempty" yaml:"inherits,omitempty" toml:"inherits,omitempty"`
+
 	// Parameters is a list of parameter specs.
 	Parameters []secrets.Strategy `json:"parameters" yaml:"parameters" toml:"parameters"`
 }
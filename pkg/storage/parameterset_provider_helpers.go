@@ -38,7 +38,7 @@ func NewTestParameterProviderFor(t *testing.T, testStore Store, testSecrets secr
 		TestDocuments: testStore,
 		TestSecrets:   testSecrets,
 		ParameterStore: &ParameterStore{
-			Documents: testStore,
+			Documents: NewNamespacePolicyStore(testStore),
 			Secrets:   testSecrets,
 		},
 	}
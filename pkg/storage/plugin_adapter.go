@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"get.porter.sh/porter/pkg/metrics"
 	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var _ Store = PluginAdapter{}
@@ -39,6 +43,10 @@ func (a PluginAdapter) Close() error {
 }
 
 func (a PluginAdapter) Aggregate(ctx context.Context, collection string, opts AggregateOptions, out interface{}) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("aggregate", collection, time.Now())
+
 	rawResults, err := a.plugin.Aggregate(ctx, opts.ToPluginOptions(collection))
 	if err != nil {
 		return err
@@ -48,14 +56,25 @@ func (a PluginAdapter) Aggregate(ctx context.Context, collection string, opts Ag
 }
 
 func (a PluginAdapter) EnsureIndex(ctx context.Context, opts EnsureIndexOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
 	return a.plugin.EnsureIndex(ctx, opts.ToPluginOptions())
 }
 
 func (a PluginAdapter) Count(ctx context.Context, collection string, opts CountOptions) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("count", collection, time.Now())
+
 	return a.plugin.Count(ctx, opts.ToPluginOptions(collection))
 }
 
 func (a PluginAdapter) Find(ctx context.Context, collection string, opts FindOptions, out interface{}) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("find", collection, time.Now())
+
 	rawResults, err := a.plugin.Find(ctx, opts.ToPluginOptions(collection))
 	if err != nil {
 		return a.handleError(err, collection)
@@ -68,6 +87,10 @@ func (a PluginAdapter) Find(ctx context.Context, collection string, opts FindOpt
 // FindOne queries a collection and returns the first result, returning
 // ErrNotFound when no results are returned.
 func (a PluginAdapter) FindOne(ctx context.Context, collection string, opts FindOptions, out interface{}) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("find-one", collection, time.Now())
+
 	rawResults, err := a.plugin.Find(ctx, opts.ToPluginOptions(collection))
 	if err != nil {
 		return a.handleError(err, collection)
@@ -158,6 +181,10 @@ func (a PluginAdapter) Get(ctx context.Context, collection string, opts GetOptio
 }
 
 func (a PluginAdapter) Insert(ctx context.Context, collection string, opts InsertOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("insert", collection, time.Now())
+
 	pluginOpts, err := opts.ToPluginOptions(collection)
 	if err != nil {
 		return err
@@ -168,16 +195,28 @@ func (a PluginAdapter) Insert(ctx context.Context, collection string, opts Inser
 }
 
 func (a PluginAdapter) Patch(ctx context.Context, collection string, opts PatchOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("patch", collection, time.Now())
+
 	err := a.plugin.Patch(ctx, opts.ToPluginOptions(collection))
 	return a.handleError(err, collection)
 }
 
 func (a PluginAdapter) Remove(ctx context.Context, collection string, opts RemoveOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("remove", collection, time.Now())
+
 	err := a.plugin.Remove(ctx, opts.ToPluginOptions(collection))
 	return a.handleError(err, collection)
 }
 
 func (a PluginAdapter) Update(ctx context.Context, collection string, opts UpdateOptions) error {
+	ctx, span := tracing.StartSpan(ctx, attribute.String("collection", collection))
+	defer span.EndSpan()
+	defer metrics.ObserveStorageOperation("update", collection, time.Now())
+
 	pluginOpts, err := opts.ToPluginOptions(collection)
 	if err != nil {
 		return err
@@ -214,6 +253,8 @@ func (e ErrNotFound) Error() string {
 		docType = "Result"
 	case "output":
 		docType = "Output"
+	case "namespaces":
+		docType = "Namespace"
 	case "credentials", "parameters":
 		if len(e.Item) > 0 {
 			docType = e.Item
@@ -0,0 +1,530 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/plugins/documents"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var _ plugins.StorageProtocol = &Store{}
+
+// indexesBucket stores the definition of the unique indexes. Mongodb does not
+// allow $ in collection names, so it does not conflict with a collection.
+var indexesBucket = []byte("$indexes")
+
+// Store implements the Porter plugin.StoragePlugin interface with an embedded
+// bbolt database.
+//
+// Each collection is stored in a bucket with the same name, where the key is
+// the _id of the document and the value is the json representation of the
+// document. Queries are evaluated by reading the documents in the collection,
+// which is suitable for the amount of data managed by a single porter client.
+//
+// The database file is only opened while an operation is executed, because
+// bbolt holds an exclusive lock on the file while it is open, and other porter
+// commands would not be able to access their data.
+type Store struct {
+	*portercontext.Context
+
+	path    string
+	timeout time.Duration
+
+	// connected is set once the database file has been created, or migrated
+	connected bool
+
+	// openMigrationSource returns the storage to copy into a new database, or
+	// nil when there is nothing to migrate.
+	openMigrationSource func(ctx context.Context) (migrationSource, error)
+}
+
+// NewStore creates a new storage engine that uses an embedded bbolt database.
+func NewStore(c *portercontext.Context, cfg PluginConfig) *Store {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 // default to 10 seconds
+	}
+	s := &Store{
+		Context: c,
+		path:    cfg.Path,
+		timeout: time.Duration(timeout) * time.Second,
+	}
+	s.openMigrationSource = s.openMongoDBDocker
+	return s
+}
+
+// Connect initializes the plugin for use.
+// The plugin itself is responsible for ensuring it was called.
+// Close is called automatically when the plugin is used by Porter.
+func (s *Store) Connect(ctx context.Context) error {
+	if s.connected {
+		return nil
+	}
+
+	ctx, span := tracing.StartSpan(ctx, attribute.String("path", s.path))
+	defer span.EndSpan()
+
+	_, err := os.Stat(s.path)
+	newDatabase := errors.Is(err, os.ErrNotExist)
+	if err != nil && !newDatabase {
+		return span.Error(fmt.Errorf("could not access the database file %s: %w", s.path, err))
+	}
+
+	if err = os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return span.Error(fmt.Errorf("could not create the directory for the database file %s: %w", s.path, err))
+	}
+
+	if newDatabase {
+		// Copy the data from the previous default storage plugin the first time the database is used
+		if err = s.migrate(ctx); err != nil {
+			os.Remove(s.path)
+			return span.Error(err)
+		}
+	}
+
+	// Create the database file if it wasn't created by the migration
+	if err = s.update(func(tx *bbolt.Tx) error { return nil }); err != nil {
+		return span.Error(err)
+	}
+
+	s.connected = true
+	return nil
+}
+
+func (s *Store) Close() error {
+	s.connected = false
+	return nil
+}
+
+// open the database file, waiting for other porter commands to release their
+// lock on the file.
+func (s *Store) open() (*bbolt.DB, error) {
+	db, err := bbolt.Open(s.path, 0600, &bbolt.Options{Timeout: s.timeout})
+	if errors.Is(err, bbolt.ErrTimeout) {
+		return nil, fmt.Errorf("timed out waiting for another porter command to release the lock on the database file %s", s.path)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not open the database file %s: %w", s.path, err)
+	}
+	return db, nil
+}
+
+// view executes a read-only transaction against the database.
+func (s *Store) view(fn func(tx *bbolt.Tx) error) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(fn)
+}
+
+// update executes a read-write transaction against the database.
+func (s *Store) update(fn func(tx *bbolt.Tx) error) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(fn)
+}
+
+func (s *Store) Aggregate(ctx context.Context, opts plugins.AggregateOptions) ([]bson.Raw, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	var docs []map[string]interface{}
+	err := s.view(func(tx *bbolt.Tx) error {
+		var err error
+		docs, err = findDocuments(tx, opts.Collection, nil, false)
+		return err
+	})
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	docs, err = documents.Aggregate(docs, opts.Pipeline)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	results, err := documents.ToRawDocuments(docs)
+	return results, span.Error(err)
+}
+
+// EnsureIndex makes sure that the specified indexes exist and are
+// defined appropriately. Queries read all the documents in a collection, so
+// only the definition of unique indexes is saved, to enforce them when
+// documents are modified.
+func (s *Store) EnsureIndex(ctx context.Context, opts plugins.EnsureIndexOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(indexesBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, index := range opts.Indices {
+			// Name the index after its keys, like mongodb, e.g. installations_namespace_1_name_1
+			def := indexDefinition{Collection: index.Collection}
+			name := index.Collection
+			for _, key := range index.Keys {
+				direction, ok := documents.ToInt(key.Value)
+				if !ok || (direction != 1 && direction != -1) {
+					return fmt.Errorf("invalid index %s on %s, the sort order must be 1 or -1", key.Key, index.Collection)
+				}
+				name += fmt.Sprintf("_%s_%d", key.Key, direction)
+				def.Keys = append(def.Keys, key.Key)
+			}
+
+			if !index.Unique {
+				continue
+			}
+
+			data, err := json.Marshal(def)
+			if err != nil {
+				return fmt.Errorf("invalid index specified: %s: %w", name, err)
+			}
+			if err = bucket.Put([]byte(name), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return span.Error(err)
+}
+
+func (s *Store) Count(ctx context.Context, opts plugins.CountOptions) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return 0, err
+	}
+
+	var docs []map[string]interface{}
+	err := s.view(func(tx *bbolt.Tx) error {
+		var err error
+		docs, err = findDocuments(tx, opts.Collection, opts.Filter, false)
+		return err
+	})
+	return int64(len(docs)), span.Error(err)
+}
+
+func (s *Store) Find(ctx context.Context, opts plugins.FindOptions) ([]bson.Raw, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	var docs []map[string]interface{}
+	err := s.view(func(tx *bbolt.Tx) error {
+		var err error
+		docs, err = findDocuments(tx, opts.Collection, opts.Filter, false)
+		return err
+	})
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	docs, err = documents.Find(docs, opts)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	results, err := documents.ToRawDocuments(docs)
+	return results, span.Error(err)
+}
+
+func (s *Store) Insert(ctx context.Context, opts plugins.InsertOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(opts.Collection))
+		if err != nil {
+			return err
+		}
+
+		for _, doc := range opts.Documents {
+			id, data, err := documents.Marshal(doc)
+			if err != nil {
+				return err
+			}
+			if bucket.Get([]byte(id)) != nil {
+				return fmt.Errorf("duplicate key error: a document with _id %s already exists in the %s collection", id, opts.Collection)
+			}
+			if err = putDocument(tx, opts.Collection, id, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return span.Error(err)
+}
+
+func (s *Store) Patch(ctx context.Context, opts plugins.PatchOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.update(func(tx *bbolt.Tx) error {
+		docs, err := findDocuments(tx, opts.Collection, opts.QueryDocument, true)
+		if err != nil || len(docs) == 0 {
+			return err
+		}
+
+		doc := docs[0]
+		if err = documents.ApplyPatch(doc, opts.Transformation); err != nil {
+			return err
+		}
+
+		id, data, err := documents.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		return putDocument(tx, opts.Collection, id, data)
+	})
+	return span.Error(err)
+}
+
+func (s *Store) Remove(ctx context.Context, opts plugins.RemoveOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.update(func(tx *bbolt.Tx) error {
+		docs, err := findDocuments(tx, opts.Collection, opts.Filter, !opts.All)
+		if err != nil || len(docs) == 0 {
+			return err
+		}
+
+		bucket := tx.Bucket([]byte(opts.Collection))
+		for _, doc := range docs {
+			id, _, err := documents.Marshal(doc)
+			if err != nil {
+				return err
+			}
+			if err = bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return span.Error(err)
+}
+
+func (s *Store) Update(ctx context.Context, opts plugins.UpdateOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.update(func(tx *bbolt.Tx) error {
+		docs, err := findDocuments(tx, opts.Collection, opts.Filter, true)
+		if err != nil {
+			return err
+		}
+		if len(docs) == 0 && !opts.Upsert {
+			return nil
+		}
+
+		// Replace the first matching document, keeping its id when the replacement does not have one
+		doc := make(map[string]interface{}, len(opts.Document))
+		for key, value := range opts.Document {
+			doc[key] = value
+		}
+		if len(docs) > 0 {
+			existingID, _, err := documents.Marshal(docs[0])
+			if err != nil {
+				return err
+			}
+			if _, hasID := doc["_id"]; !hasID {
+				doc["_id"] = docs[0]["_id"]
+			}
+			id, _, err := documents.Marshal(doc)
+			if err != nil {
+				return err
+			}
+			if id != existingID {
+				return fmt.Errorf("the _id of document %s in the %s collection cannot be changed to %s", existingID, opts.Collection, id)
+			}
+		}
+
+		id, data, err := documents.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		return putDocument(tx, opts.Collection, id, data)
+	})
+	return span.Error(err)
+}
+
+// RemoveDatabase removes all the collections from the database.
+func (s *Store) RemoveDatabase(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.update(func(tx *bbolt.Tx) error {
+		var buckets [][]byte
+		err := tx.ForEach(func(name []byte, _ *bbolt.Bucket) error {
+			buckets = append(buckets, append([]byte(nil), name...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range buckets {
+			if err = tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return span.Error(err)
+}
+
+// indexDefinition is the definition of a unique index saved in the database.
+type indexDefinition struct {
+	Collection string   `json:"collection"`
+	Keys       []string `json:"keys"`
+}
+
+// findDocuments returns the documents in a collection that match a filter.
+// When first is true, only the first matching document is returned.
+func findDocuments(tx *bbolt.Tx, collection string, filter bson.M, first bool) ([]map[string]interface{}, error) {
+	bucket := tx.Bucket([]byte(collection))
+	if bucket == nil {
+		return nil, nil
+	}
+
+	var docs []map[string]interface{}
+	c := bucket.Cursor()
+	for key, data := c.First(); key != nil; key, data = c.Next() {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("could not parse document %s from the %s collection: %w", key, collection, err)
+		}
+
+		matched, err := documents.Match(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		docs = append(docs, doc)
+		if first {
+			break
+		}
+	}
+	return docs, nil
+}
+
+// putDocument saves a document in a collection, after checking that it does
+// not conflict with another document on a unique index.
+func putDocument(tx *bbolt.Tx, collection string, id string, data []byte) error {
+	bucket, err := tx.CreateBucketIfNotExists([]byte(collection))
+	if err != nil {
+		return err
+	}
+
+	indexes := tx.Bucket(indexesBucket)
+	if indexes != nil {
+		var doc map[string]interface{}
+		if err = json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+
+		err = indexes.ForEach(func(name []byte, indexData []byte) error {
+			var index indexDefinition
+			if err := json.Unmarshal(indexData, &index); err != nil {
+				return fmt.Errorf("could not parse the definition of index %s: %w", name, err)
+			}
+			if index.Collection != collection {
+				return nil
+			}
+			return checkUniqueIndex(bucket, string(name), index, id, doc)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return bucket.Put([]byte(id), data)
+}
+
+// checkUniqueIndex returns an error when another document in the collection
+// has the same values for the keys of a unique index.
+func checkUniqueIndex(bucket *bbolt.Bucket, name string, index indexDefinition, id string, doc map[string]interface{}) error {
+	values := indexValues(index, doc)
+
+	c := bucket.Cursor()
+	for key, data := c.First(); key != nil; key, data = c.Next() {
+		if string(key) == id {
+			continue
+		}
+
+		var existing map[string]interface{}
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("could not parse document %s from the %s collection: %w", key, index.Collection, err)
+		}
+		if reflect.DeepEqual(values, indexValues(index, existing)) {
+			return fmt.Errorf("duplicate key error: the document %s has the same values as the document %s for the unique index %s", id, key, name)
+		}
+	}
+	return nil
+}
+
+// indexValues returns the values of the keys of an index in a document.
+// Similar to mongodb, a missing field is indexed as null.
+func indexValues(index indexDefinition, doc map[string]interface{}) []interface{} {
+	values := make([]interface{}, len(index.Keys))
+	for i, key := range index.Keys {
+		values[i], _ = documents.GetField(doc, key)
+	}
+	return values
+}
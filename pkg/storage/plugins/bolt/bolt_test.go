@@ -0,0 +1,266 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/plugins/mongodb_docker"
+	"get.porter.sh/porter/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newTestStore(t *testing.T) *Store {
+	tc := portercontext.NewTestContext(t)
+	s := NewStore(tc.Context, PluginConfig{Path: filepath.Join(t.TempDir(), DefaultDatabaseFile)})
+	s.openMigrationSource = func(ctx context.Context) (migrationSource, error) { return nil, nil }
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func unmarshalResults(t *testing.T, results []bson.Raw) []bson.M {
+	docs := make([]bson.M, len(results))
+	for i, result := range results {
+		require.NoError(t, bson.Unmarshal(result, &docs[i]))
+	}
+	return docs
+}
+
+func TestStore_CRUD(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	err := s.Insert(ctx, plugins.InsertOptions{
+		Collection: "installations",
+		Documents: []bson.M{
+			{"_id": "1", "namespace": "dev", "name": "mybuns", "labels": bson.M{"team": "red"}},
+			{"_id": "2", "namespace": "dev", "name": "wordpress"},
+			{"_id": "3", "namespace": "", "name": "mysql"},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("find", func(t *testing.T) {
+		results, err := s.Find(ctx, plugins.FindOptions{
+			Collection: "installations",
+			Filter:     bson.M{"$or": []bson.M{{"namespace": "dev"}, {"namespace": ""}}},
+			Sort:       bson.D{{Key: "name", Value: -1}},
+			Skip:       1,
+			Limit:      1,
+			Select:     bson.D{{Key: "name", Value: 1}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []bson.M{{"_id": "3", "name": "mysql"}}, unmarshalResults(t, results))
+	})
+
+	t.Run("count", func(t *testing.T) {
+		count, err := s.Count(ctx, plugins.CountOptions{Collection: "installations", Filter: bson.M{"labels.team": "red"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		count, err = s.Count(ctx, plugins.CountOptions{Collection: "missing"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count, "a collection that does not exist should be empty")
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		err := s.Patch(ctx, plugins.PatchOptions{
+			Collection:     "installations",
+			QueryDocument:  bson.M{"_id": "2"},
+			Transformation: bson.D{{Key: "$set", Value: bson.M{"uninstalled": true}}},
+		})
+		require.NoError(t, err)
+
+		count, err := s.Count(ctx, plugins.CountOptions{Collection: "installations", Filter: bson.M{"uninstalled": true}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		err := s.Update(ctx, plugins.UpdateOptions{
+			Collection: "installations",
+			Filter:     bson.M{"_id": "3"},
+			Document:   bson.M{"namespace": "", "name": "mysql", "uninstalled": true},
+		})
+		require.NoError(t, err)
+
+		err = s.Update(ctx, plugins.UpdateOptions{
+			Collection: "installations",
+			Filter:     bson.M{"_id": "4"},
+			Upsert:     true,
+			Document:   bson.M{"_id": "4", "namespace": "test", "name": "redis"},
+		})
+		require.NoError(t, err)
+
+		results, err := s.Find(ctx, plugins.FindOptions{
+			Collection: "installations",
+			Filter:     bson.M{"_id": bson.M{"$in": []string{"3", "4"}}},
+			Sort:       bson.D{{Key: "_id", Value: 1}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []bson.M{
+			{"_id": "3", "namespace": "", "name": "mysql", "uninstalled": true},
+			{"_id": "4", "namespace": "test", "name": "redis"},
+		}, unmarshalResults(t, results))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := s.Remove(ctx, plugins.RemoveOptions{Collection: "installations", Filter: bson.M{"uninstalled": true}, All: true})
+		require.NoError(t, err)
+
+		count, err := s.Count(ctx, plugins.CountOptions{Collection: "installations"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestStore_Insert_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	err := s.EnsureIndex(ctx, plugins.EnsureIndexOptions{Indices: []plugins.Index{
+		{Collection: "installations", Keys: bson.D{{Key: "namespace", Value: 1}, {Key: "name", Value: 1}}, Unique: true},
+	}})
+	require.NoError(t, err)
+
+	err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "1", "namespace": "dev", "name": "mybuns"}}})
+	require.NoError(t, err)
+
+	t.Run("duplicate id", func(t *testing.T) {
+		err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "1", "namespace": "dev", "name": "other"}}})
+		require.EqualError(t, err, "duplicate key error: a document with _id 1 already exists in the installations collection")
+	})
+
+	t.Run("unique index", func(t *testing.T) {
+		err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "2", "namespace": "dev", "name": "mybuns"}}})
+		require.EqualError(t, err, "duplicate key error: the document 2 has the same values as the document 1 for the unique index installations_namespace_1_name_1")
+	})
+
+	t.Run("different namespace", func(t *testing.T) {
+		err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "3", "namespace": "test", "name": "mybuns"}}})
+		require.NoError(t, err)
+	})
+}
+
+func TestStore_Aggregate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	err := s.Insert(ctx, plugins.InsertOptions{
+		Collection: "outputs",
+		Documents: []bson.M{
+			{"namespace": "dev", "installation": "test", "name": "thing1", "resultId": "111"},
+			{"namespace": "dev", "installation": "test", "name": "thing2", "resultId": "111"},
+			{"namespace": "dev", "installation": "test", "name": "thing2", "resultId": "222"},
+			{"namespace": "dev", "installation": "other", "name": "thing2", "resultId": "333"},
+		},
+	})
+	require.NoError(t, err)
+
+	results, err := s.Aggregate(ctx, plugins.AggregateOptions{
+		Collection: "outputs",
+		Pipeline: []bson.D{
+			{{Key: "$match", Value: bson.M{"namespace": "dev", "installation": "test"}}},
+			{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}, {Key: "resultId", Value: -1}}}},
+			{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$name"},
+				{Key: "lastOutput", Value: bson.M{"$first": "$$ROOT"}},
+			}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "thing1", results[0].Lookup("_id").StringValue())
+	assert.Equal(t, "111", results[0].Lookup("lastOutput", "resultId").StringValue())
+	assert.Equal(t, "thing2", results[1].Lookup("_id").StringValue())
+	assert.Equal(t, "222", results[1].Lookup("lastOutput", "resultId").StringValue(), "the most recent output should be selected")
+}
+
+func TestStore_Connect_NewDatabase(t *testing.T) {
+	ctx := context.Background()
+	tc := portercontext.NewTestContext(t)
+	// The mongodb-docker plugin was never used, so there is nothing to migrate
+	tc.Setenv(test.ExpectedCommandEnv, "docker volume inspect "+mongodb_docker.DataVolumeName)
+	tc.Setenv(test.ExpectedCommandExitCodeEnv, "1")
+	path := filepath.Join(t.TempDir(), "data", DefaultDatabaseFile)
+	s := NewStore(tc.Context, PluginConfig{Path: path})
+
+	require.NoError(t, s.Connect(ctx))
+	assert.FileExists(t, path, "the database file and its parent directory should be created")
+
+	count, err := s.Count(ctx, plugins.CountOptions{Collection: "installations"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count, "no data should be migrated")
+}
+
+type testMigrationSource struct {
+	collections map[string][]bson.M
+	closed      bool
+}
+
+func (s *testMigrationSource) ListCollections(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (s *testMigrationSource) Find(ctx context.Context, opts plugins.FindOptions) ([]bson.Raw, error) {
+	var results []bson.Raw
+	for _, doc := range s.collections[opts.Collection] {
+		b, err := bson.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, b)
+	}
+	return results, nil
+}
+
+func (s *testMigrationSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestStore_Connect_Migrate(t *testing.T) {
+	ctx := context.Background()
+	tc := portercontext.NewTestContext(t)
+	path := filepath.Join(t.TempDir(), DefaultDatabaseFile)
+
+	src := &testMigrationSource{collections: map[string][]bson.M{
+		"installations": {{"_id": "1", "namespace": "dev", "name": "mybuns"}},
+		"runs":          {{"_id": "a", "installation": "mybuns"}, {"_id": "b", "installation": "mybuns"}},
+	}}
+	s := NewStore(tc.Context, PluginConfig{Path: path})
+	s.openMigrationSource = func(ctx context.Context) (migrationSource, error) { return src, nil }
+
+	require.NoError(t, s.Connect(ctx))
+	assert.True(t, src.closed, "the migration source should be closed")
+
+	results, err := s.Find(ctx, plugins.FindOptions{Collection: "installations"})
+	require.NoError(t, err)
+	assert.Equal(t, []bson.M{{"_id": "1", "namespace": "dev", "name": "mybuns"}}, unmarshalResults(t, results))
+
+	count, err := s.Count(ctx, plugins.CountOptions{Collection: "runs"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	t.Run("failed migration", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), DefaultDatabaseFile)
+		s := NewStore(tc.Context, PluginConfig{Path: path})
+		s.openMigrationSource = func(ctx context.Context) (migrationSource, error) {
+			return nil, errors.New("Docker is not available")
+		}
+
+		err := s.Connect(ctx)
+		require.ErrorContains(t, err, "Docker is not available")
+		assert.NoFileExists(t, path, "the database should be removed so that the migration is attempted again")
+	})
+}
@@ -0,0 +1,5 @@
+// Package bolt implements the plugins.StorageProtocol interface, storing data
+// as json documents in an embedded bbolt database file in the porter home
+// directory. It is the default storage plugin, and does not require a
+// database server or Docker.
+package bolt
@@ -0,0 +1,95 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/plugins/documents"
+	"get.porter.sh/porter/pkg/storage/plugins/mongodb_docker"
+	"get.porter.sh/porter/pkg/tracing"
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// migrationSource is the storage of a previous storage plugin that is copied
+// into a new database.
+type migrationSource interface {
+	ListCollections(ctx context.Context) ([]string, error)
+	Find(ctx context.Context, opts plugins.FindOptions) ([]bson.Raw, error)
+	Close() error
+}
+
+// openMongoDBDocker connects to the database of the mongodb-docker plugin,
+// which was the default storage plugin before the bolt plugin. No source is
+// returned when Docker is not available, or when the mongodb-docker plugin was
+// never used.
+func (s *Store) openMongoDBDocker(ctx context.Context) (migrationSource, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	// The volume is only created when the mongodb-docker plugin was used, and
+	// the command fails when Docker is not available
+	if err := s.NewCommand(ctx, "docker", "volume", "inspect", mongodb_docker.DataVolumeName).Run(); err != nil {
+		span.Debugf("skipping the migration of data from the mongodb-docker plugin because the %s docker volume was not found", mongodb_docker.DataVolumeName)
+		return nil, nil
+	}
+
+	src, err := mongodb_docker.EnsureMongoIsRunning(ctx, s.Context, mongodb_docker.ContainerName, mongodb_docker.DefaultPort,
+		mongodb_docker.DataVolumeName, mongodb_docker.DefaultDatabase, int(s.timeout.Seconds()))
+	if err != nil {
+		return nil, span.Error(fmt.Errorf("could not migrate data from the mongodb-docker plugin. Set default-storage-plugin to mongodb-docker in the porter config file to continue using it: %w", err))
+	}
+	return src, nil
+}
+
+// migrate copies the data from the previous default storage plugin into a new
+// database. Nothing is copied when there is no data to migrate.
+func (s *Store) migrate(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	src, err := s.openMigrationSource(ctx)
+	if err != nil {
+		return span.Error(err)
+	}
+	if src == nil {
+		return nil
+	}
+	defer src.Close()
+
+	span.Infof("Migrating data from the mongodb-docker plugin to %s", s.path)
+
+	collections, err := src.ListCollections(ctx)
+	if err != nil {
+		return span.Error(fmt.Errorf("could not list the collections of the mongodb-docker plugin: %w", err))
+	}
+
+	err = s.update(func(tx *bbolt.Tx) error {
+		for _, collection := range collections {
+			results, err := src.Find(ctx, plugins.FindOptions{Collection: collection})
+			if err != nil {
+				return fmt.Errorf("could not read the %s collection of the mongodb-docker plugin: %w", collection, err)
+			}
+
+			for _, result := range results {
+				var doc bson.M
+				if err = bson.Unmarshal(result, &doc); err != nil {
+					return fmt.Errorf("could not parse a document from the %s collection of the mongodb-docker plugin: %w", collection, err)
+				}
+
+				id, data, err := documents.Marshal(doc)
+				if err != nil {
+					return err
+				}
+				if err = putDocument(tx, collection, id, data); err != nil {
+					return err
+				}
+			}
+			span.Debug("Migrated collection", attribute.String("collection", collection), attribute.Int("documents", len(results)))
+		}
+		return nil
+	})
+	return span.Error(err)
+}
@@ -0,0 +1,49 @@
+package bolt
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"get.porter.sh/porter/pkg/config"
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/pluginstore"
+	"github.com/hashicorp/go-plugin"
+	"github.com/mitchellh/mapstructure"
+)
+
+// PluginKey is the identifier of the internal bolt plugin.
+const PluginKey = plugins.PluginInterface + ".porter.bolt"
+
+// DefaultDatabaseFile is the name of the database file in the porter home directory.
+const DefaultDatabaseFile = "porter.db"
+
+// PluginConfig supported by the bolt plugin as defined in porter.yaml
+type PluginConfig struct {
+	// Path to the database file. Defaults to PORTER_HOME/porter.db.
+	Path string `mapstructure:"path,omitempty"`
+
+	// Timeout in seconds to wait for the lock on the database file, which is
+	// held by another porter command that is using the database.
+	Timeout int `mapstructure:"timeout,omitempty"`
+}
+
+// NewPlugin creates an instance of the storage.porter.bolt plugin
+func NewPlugin(c *config.Config, rawCfg interface{}) (plugin.Plugin, error) {
+	cfg := PluginConfig{
+		Timeout: 10,
+	}
+	if err := mapstructure.Decode(rawCfg, &cfg); err != nil {
+		return nil, fmt.Errorf("error reading plugin configuration: %w", err)
+	}
+
+	if cfg.Path == "" {
+		home, err := c.GetHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Path = filepath.Join(home, DefaultDatabaseFile)
+	}
+
+	store := NewStore(c.Context, cfg)
+	return pluginstore.NewPlugin(c.Context, store), nil
+}
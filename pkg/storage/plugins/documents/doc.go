@@ -0,0 +1,5 @@
+// Package documents implements the subset of the mongodb query language used
+// by Porter against documents held in memory. It is used by the storage
+// plugins that store documents in a database without a mongodb compatible
+// query engine.
+package documents
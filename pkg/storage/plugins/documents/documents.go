@@ -0,0 +1,405 @@
+package documents
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IsOperatorDocument determines if the document contains query operators,
+// e.g. {"$in": ["a", "b"]}, instead of a value to match.
+func IsOperatorDocument(doc map[string]interface{}) bool {
+	if len(doc) == 0 {
+		return false
+	}
+	for key := range doc {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// Project applies a projection document to a document.
+// See https://docs.mongodb.com/manual/tutorial/project-fields-from-query-results/
+func Project(doc map[string]interface{}, projection bson.D) map[string]interface{} {
+	if len(projection) == 0 {
+		return doc
+	}
+
+	// A projection that only specifies _id is an inclusion when _id is included
+	include := len(projection) == 1 && IsTruthy(projection[0].Value)
+	includeID := true
+	for _, field := range projection {
+		if field.Key == "_id" {
+			includeID = IsTruthy(field.Value)
+			continue
+		}
+		include = IsTruthy(field.Value)
+	}
+
+	if !include {
+		// Remove the excluded fields from the document
+		for _, field := range projection {
+			if !IsTruthy(field.Value) {
+				RemoveField(doc, field.Key)
+			}
+		}
+		return doc
+	}
+
+	// Only keep the included fields
+	result := make(map[string]interface{}, len(projection))
+	if includeID {
+		if id, ok := doc["_id"]; ok {
+			result["_id"] = id
+		}
+	}
+	for _, field := range projection {
+		if field.Key == "_id" || !IsTruthy(field.Value) {
+			continue
+		}
+		if value, ok := GetField(doc, field.Key); ok {
+			SetField(result, field.Key, value)
+		}
+	}
+	return result
+}
+
+// Group applies a $group stage of an aggregation pipeline to the documents,
+// which are grouped in the order they were found. The $first, $last, $push
+// and $sum accumulators are supported.
+// See https://docs.mongodb.com/manual/reference/operator/aggregation/group/
+func Group(docs []map[string]interface{}, spec interface{}) ([]map[string]interface{}, error) {
+	fields, ok := ToOrderedMap(spec)
+	if !ok {
+		return nil, fmt.Errorf("the value of $group must be a document")
+	}
+
+	var idExpr interface{}
+	hasID := false
+	accumulators := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		if field.Key == "_id" {
+			idExpr = field.Value
+			hasID = true
+			continue
+		}
+		accumulators = append(accumulators, field)
+	}
+	if !hasID {
+		return nil, fmt.Errorf("a group specification must include an _id")
+	}
+
+	var groups []map[string]interface{}
+	groupsByKey := make(map[string]map[string]interface{})
+	for _, doc := range docs {
+		id := evaluate(doc, idExpr)
+		data, err := json.Marshal(ToJSONValue(id))
+		if err != nil {
+			return nil, fmt.Errorf("could not group by %v: %w", id, err)
+		}
+
+		result, ok := groupsByKey[string(data)]
+		if !ok {
+			result = map[string]interface{}{"_id": id}
+			groupsByKey[string(data)] = result
+			groups = append(groups, result)
+		}
+
+		for _, field := range accumulators {
+			accumulator, ok := ToMap(field.Value)
+			if !ok || len(accumulator) != 1 {
+				return nil, fmt.Errorf("the accumulator for %s must be a document with a single operator", field.Key)
+			}
+			for operator, expr := range accumulator {
+				value := evaluate(doc, expr)
+				existing, found := result[field.Key]
+				switch operator {
+				case "$first":
+					if !found {
+						result[field.Key] = value
+					}
+				case "$last":
+					result[field.Key] = value
+				case "$push":
+					items, _ := existing.([]interface{})
+					result[field.Key] = append(items, value)
+				case "$sum":
+					sum, _ := existing.(float64)
+					if number, ok := ToFloat(value); ok {
+						sum += number
+					}
+					result[field.Key] = sum
+				default:
+					return nil, fmt.Errorf("the %s accumulator is not supported", operator)
+				}
+			}
+		}
+	}
+	return groups, nil
+}
+
+// evaluate an aggregation expression against a document. Field paths, such
+// as $name, and the $$ROOT variable are supported, other values are
+// returned as-is.
+func evaluate(doc map[string]interface{}, expr interface{}) interface{} {
+	path, ok := expr.(string)
+	if !ok || !strings.HasPrefix(path, "$") {
+		return expr
+	}
+	if path == "$$ROOT" {
+		return doc
+	}
+	value, _ := GetField(doc, strings.TrimPrefix(path, "$"))
+	return value
+}
+
+// GetField returns the value of a field, using dots to specify nested fields.
+func GetField(doc map[string]interface{}, field string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, part := range strings.Split(field, ".") {
+		m, ok := ToMap(value)
+		if !ok {
+			return nil, false
+		}
+		value, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// SetField sets the value of a field, creating the parent documents of a
+// nested field when they do not exist.
+func SetField(doc map[string]interface{}, field string, value interface{}) {
+	parts := strings.Split(field, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := ToMap(doc[part])
+		if !ok {
+			child = map[string]interface{}{}
+			doc[part] = child
+		}
+		doc = child
+	}
+	doc[parts[len(parts)-1]] = value
+}
+
+// RemoveField removes a field, using dots to specify nested fields.
+func RemoveField(doc map[string]interface{}, field string) {
+	parts := strings.Split(field, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := ToMap(doc[part])
+		if !ok {
+			return
+		}
+		doc = child
+	}
+	delete(doc, parts[len(parts)-1])
+}
+
+// ApplyPatch applies the $set and $unset update operators of a transformation
+// document to a document.
+// See https://docs.mongodb.com/manual/reference/operator/update/
+func ApplyPatch(doc map[string]interface{}, transformation bson.D) error {
+	for _, operation := range transformation {
+		fields, ok := ToMap(operation.Value)
+		if !ok {
+			return fmt.Errorf("the value of %s must be a document", operation.Key)
+		}
+		for field, value := range fields {
+			switch operation.Key {
+			case "$set":
+				SetField(doc, field, ToJSONValue(value))
+			case "$unset":
+				RemoveField(doc, field)
+			default:
+				return fmt.Errorf("the %s update operator is not supported", operation.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// ToJSONValue converts bson documents to values that can be marshaled to json,
+// because bson.D is otherwise marshaled as an array of key/value pairs.
+func ToJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		m := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			m[e.Key] = ToJSONValue(e.Value)
+		}
+		return m
+	case bson.M:
+		return ToJSONValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = ToJSONValue(item)
+		}
+		return m
+	case bson.A:
+		return ToJSONValue([]interface{}(v))
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = ToJSONValue(item)
+		}
+		return items
+	case primitive.DateTime:
+		return v.Time()
+	default:
+		return v
+	}
+}
+
+// ToMap returns the value as a map when it is a document.
+func ToMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case bson.M:
+		return v, true
+	case bson.D:
+		return v.Map(), true
+	default:
+		return nil, false
+	}
+}
+
+// ToOrderedMap returns the value as an ordered document.
+func ToOrderedMap(value interface{}) (bson.D, bool) {
+	if d, ok := value.(bson.D); ok {
+		return d, true
+	}
+
+	m, ok := ToMap(value)
+	if !ok {
+		return nil, false
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	d := make(bson.D, len(keys))
+	for i, key := range keys {
+		d[i] = bson.E{Key: key, Value: m[key]}
+	}
+	return d, true
+}
+
+// ToSlice returns the value as a slice when it is an array.
+func ToSlice(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case bson.A:
+		return v, true
+	case []bson.M:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	default:
+		return nil, false
+	}
+}
+
+// ToInt returns the value as an integer when it is a whole number.
+func ToInt(value interface{}) (int64, bool) {
+	number, ok := ToFloat(value)
+	if !ok || number != float64(int64(number)) {
+		return 0, false
+	}
+	return int64(number), true
+}
+
+// ToFloat returns the value as a float when it is a number.
+func ToFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// IsTruthy determines if the value of a projection field includes the field.
+func IsTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case nil:
+		return false
+	default:
+		number, ok := ToFloat(v)
+		return !ok || number != 0
+	}
+}
+
+// Marshal returns the id and json representation of a document. An
+// id is generated for documents that do not have an _id, like mongodb.
+func Marshal(doc map[string]interface{}) (string, []byte, error) {
+	value, ok := doc["_id"]
+	if !ok || value == nil {
+		value = primitive.NewObjectID().Hex()
+		doc["_id"] = value
+	}
+
+	id, ok := value.(string)
+	if !ok {
+		idData, err := json.Marshal(ToJSONValue(value))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid document _id %v: %w", value, err)
+		}
+		id = string(idData)
+	}
+
+	data, err := json.Marshal(ToJSONValue(doc))
+	if err != nil {
+		return "", nil, fmt.Errorf("could not convert the document %s to json: %w", id, err)
+	}
+	return id, data, nil
+}
+
+// ToRawDocuments converts documents to bson, which is returned by the plugin.
+func ToRawDocuments(docs []map[string]interface{}) ([]bson.Raw, error) {
+	results := make([]bson.Raw, len(docs))
+	for i, doc := range docs {
+		data, err := bson.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert the document to bson: %w", err)
+		}
+		results[i] = data
+	}
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProject(t *testing.T) {
+	newDoc := func() map[string]interface{} {
+		return map[string]interface{}{
+			"_id":    "1",
+			"name":   "mybuns",
+			"status": map[string]interface{}{"action": "install", "runId": "2"},
+		}
+	}
+
+	t.Run("include", func(t *testing.T) {
+		got := Project(newDoc(), bson.D{{Key: "status.runId", Value: 1}})
+		assert.Equal(t, map[string]interface{}{"_id": "1", "status": map[string]interface{}{"runId": "2"}}, got)
+	})
+
+	t.Run("include only id", func(t *testing.T) {
+		got := Project(newDoc(), bson.D{{Key: "_id", Value: int32(1)}})
+		assert.Equal(t, map[string]interface{}{"_id": "1"}, got)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		got := Project(newDoc(), bson.D{{Key: "_id", Value: 0}, {Key: "status.action", Value: false}})
+		assert.Equal(t, map[string]interface{}{"name": "mybuns", "status": map[string]interface{}{"runId": "2"}}, got)
+	})
+}
+
+func TestGroup(t *testing.T) {
+	// The outputs are sorted by resultId in descending order, so $first selects the most recent output
+	docs := []map[string]interface{}{
+		{"_id": "3", "name": "port", "resultId": "b"},
+		{"_id": "2", "name": "host", "resultId": "b"},
+		{"_id": "1", "name": "port", "resultId": "a"},
+	}
+
+	spec := bson.D{
+		{Key: "_id", Value: "$name"},
+		{Key: "lastOutput", Value: map[string]interface{}{"$first": "$$ROOT"}},
+		{Key: "results", Value: map[string]interface{}{"$push": "$resultId"}},
+		{Key: "count", Value: map[string]interface{}{"$sum": 1}},
+	}
+	got, err := Group(docs, spec)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "port", got[0]["_id"])
+	assert.Equal(t, docs[0], got[0]["lastOutput"])
+	assert.Equal(t, []interface{}{"b", "a"}, got[0]["results"])
+	assert.Equal(t, float64(2), got[0]["count"])
+
+	assert.Equal(t, "host", got[1]["_id"])
+	assert.Equal(t, float64(1), got[1]["count"])
+
+	_, err = Group(docs, bson.D{{Key: "_id", Value: "$name"}, {Key: "avg", Value: bson.M{"$avg": "$value"}}})
+	require.EqualError(t, err, "the $avg accumulator is not supported")
+}
+
+func TestMarshal(t *testing.T) {
+	doc := map[string]interface{}{"name": "mybuns", "labels": bson.D{{Key: "team", Value: "red"}}}
+	id, data, err := Marshal(doc)
+	require.NoError(t, err)
+	assert.NotEmpty(t, id, "an id should be generated when the document does not have an _id")
+	assert.Equal(t, id, doc["_id"])
+	assert.JSONEq(t, `{"_id":"`+id+`","name":"mybuns","labels":{"team":"red"}}`, string(data))
+
+	id, _, err = Marshal(map[string]interface{}{"_id": "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", id)
+}
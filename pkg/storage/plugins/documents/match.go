@@ -0,0 +1,448 @@
+package documents
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Match determines if a document matches a query filter document.
+// The document must be in its json representation, i.e. read from json.
+// See https://docs.mongodb.com/manual/core/document/#std-label-document-query-filter
+func Match(doc map[string]interface{}, filter map[string]interface{}) (bool, error) {
+	// Sort the fields so that the same filter always evaluates the same way
+	keys := make([]string, 0, len(filter))
+	for key := range filter {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var matched bool
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			matched, err = matchLogical(doc, key, filter[key])
+		default:
+			if strings.HasPrefix(key, "$") {
+				return false, fmt.Errorf("the %s query operator is not supported", key)
+			}
+			matched, err = matchField(doc, key, filter[key])
+		}
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchLogical evaluates a logical query operator, such as $or.
+func matchLogical(doc map[string]interface{}, operator string, value interface{}) (bool, error) {
+	items, ok := ToSlice(value)
+	if !ok || len(items) == 0 {
+		return false, fmt.Errorf("%s must be a nonempty array", operator)
+	}
+
+	for _, item := range items {
+		filter, ok := ToMap(item)
+		if !ok {
+			return false, fmt.Errorf("the elements of %s must be query documents", operator)
+		}
+		matched, err := Match(doc, filter)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case operator == "$and" && !matched:
+			return false, nil
+		case operator == "$or" && matched:
+			return true, nil
+		case operator == "$nor" && matched:
+			return false, nil
+		}
+	}
+	return operator != "$or", nil
+}
+
+// matchField evaluates the condition on a field of the document. The
+// condition is either a value that the field must equal, or a document of
+// query operators, such as {"$gt": 1}.
+func matchField(doc map[string]interface{}, name string, value interface{}) (bool, error) {
+	actual, found := GetField(doc, name)
+
+	operators, ok := ToMap(value)
+	if !ok || !IsOperatorDocument(operators) {
+		return equals(actual, value)
+	}
+
+	for operator, operand := range operators {
+		var matched bool
+		var err error
+		switch operator {
+		case "$eq":
+			matched, err = equals(actual, operand)
+		case "$ne":
+			matched, err = equals(actual, operand)
+			matched = !matched
+		case "$in", "$nin":
+			matched, err = in(actual, operand)
+			if operator == "$nin" {
+				matched = !matched
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			matched, err = compareTo(actual, operator, operand)
+		case "$exists":
+			exists, _ := operand.(bool)
+			matched = found == exists
+		case "$regex":
+			options, _ := operators["$options"].(string)
+			matched, err = matchRegex(actual, operand, options)
+		case "$options":
+			// Handled with $regex
+			continue
+		default:
+			return false, fmt.Errorf("the %s query operator is not supported", operator)
+		}
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// equals determines if a field equals a value. Similar to mongodb, matching on
+// null also matches documents where the field is not set, and an array field
+// matches when one of its elements is equal to the value.
+func equals(actual interface{}, expected interface{}) (bool, error) {
+	if regex, ok := expected.(primitive.Regex); ok {
+		return matchRegex(actual, regex.Pattern, regex.Options)
+	}
+
+	want, err := normalize(expected)
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(actual, want) {
+		return true, nil
+	}
+
+	if items, ok := actual.([]interface{}); ok {
+		if _, wantArray := want.([]interface{}); !wantArray {
+			for _, item := range items {
+				if reflect.DeepEqual(item, want) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// in determines if a field equals any of the values in an array.
+func in(actual interface{}, value interface{}) (bool, error) {
+	items, ok := ToSlice(value)
+	if !ok {
+		return false, fmt.Errorf("the value of $in and $nin must be an array")
+	}
+
+	for _, item := range items {
+		matched, err := equals(actual, item)
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
+
+// compareTo evaluates a comparison query operator, such as $gt. Similar to
+// mongodb, only values of the same type are compared.
+func compareTo(actual interface{}, operator string, value interface{}) (bool, error) {
+	want, err := normalize(value)
+	if err != nil {
+		return false, err
+	}
+
+	candidates := []interface{}{actual}
+	if items, ok := actual.([]interface{}); ok {
+		candidates = items
+	}
+	for _, candidate := range candidates {
+		if typeOrder(candidate) != typeOrder(want) {
+			continue
+		}
+
+		result := Compare(candidate, want)
+		switch {
+		case operator == "$gt" && result > 0,
+			operator == "$gte" && result >= 0,
+			operator == "$lt" && result < 0,
+			operator == "$lte" && result <= 0:
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchRegex determines if a string field, or an element of an array field,
+// matches a regular expression.
+func matchRegex(actual interface{}, pattern interface{}, options string) (bool, error) {
+	var expr string
+	switch p := pattern.(type) {
+	case string:
+		expr = p
+	case primitive.Regex:
+		expr = p.Pattern
+		options += p.Options
+	default:
+		return false, fmt.Errorf("the value of $regex must be a string")
+	}
+
+	for _, option := range options {
+		if !strings.ContainsRune("ims", option) {
+			return false, fmt.Errorf("the %c $regex option is not supported", option)
+		}
+	}
+	if options != "" {
+		expr = "(?" + options + ")" + expr
+	}
+
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid $regex %s: %w", expr, err)
+	}
+
+	candidates := []interface{}{actual}
+	if items, ok := actual.([]interface{}); ok {
+		candidates = items
+	}
+	for _, candidate := range candidates {
+		if s, ok := candidate.(string); ok && regex.MatchString(s) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// normalize converts a value from a query to its json representation, so that
+// it can be compared with the fields of a document read from json.
+func normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(ToJSONValue(value))
+	if err != nil {
+		return nil, fmt.Errorf("could not convert %v to json: %w", value, err)
+	}
+
+	var result interface{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// Sort the documents by a sort document, e.g. {"namespace": 1, "name": -1}.
+// Similar to mongodb, documents that do not have the field are sorted first.
+func Sort(docs []map[string]interface{}, sortDoc bson.D) error {
+	if len(sortDoc) == 0 {
+		return nil
+	}
+
+	directions := make([]int, len(sortDoc))
+	for i, key := range sortDoc {
+		direction, ok := ToInt(key.Value)
+		if !ok || (direction != 1 && direction != -1) {
+			return fmt.Errorf("invalid sort order %v for %s, the sort order must be 1 or -1", key.Value, key.Key)
+		}
+		directions[i] = int(direction)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		for k, key := range sortDoc {
+			a, _ := GetField(docs[i], key.Key)
+			b, _ := GetField(docs[j], key.Key)
+			if result := Compare(a, b) * directions[k]; result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// Compare two json values, returning -1 when a is less than b, 0 when they
+// are equal, and 1 when a is greater than b. Values of different types are
+// ordered by their type, similar to mongodb: null, numbers, strings,
+// documents, arrays and then booleans.
+func Compare(a interface{}, b interface{}) int {
+	if orderA, orderB := typeOrder(a), typeOrder(b); orderA != orderB {
+		return compareInts(orderA, orderB)
+	}
+
+	switch x := a.(type) {
+	case float64:
+		y := b.(float64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	case string:
+		return strings.Compare(x, b.(string))
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case []interface{}:
+		y := b.([]interface{})
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if result := Compare(x[i], y[i]); result != 0 {
+				return result
+			}
+		}
+		return compareInts(len(x), len(y))
+	case map[string]interface{}:
+		// json sorts the keys of a map, so the documents are compared field by field
+		dataA, _ := json.Marshal(x)
+		dataB, _ := json.Marshal(b)
+		return strings.Compare(string(dataA), string(dataB))
+	default:
+		return 0
+	}
+}
+
+// typeOrder returns the position of the type of a json value in the sort order.
+func typeOrder(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case float64:
+		return 1
+	case string:
+		return 2
+	case map[string]interface{}:
+		return 3
+	case []interface{}:
+		return 4
+	case bool:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Find sorts the documents that matched the filter of a find query, then
+// applies the skip, limit and projection of the query.
+func Find(docs []map[string]interface{}, opts plugins.FindOptions) ([]map[string]interface{}, error) {
+	if err := Sort(docs, opts.Sort); err != nil {
+		return nil, err
+	}
+
+	if opts.Skip > 0 {
+		if opts.Skip > int64(len(docs)) {
+			opts.Skip = int64(len(docs))
+		}
+		docs = docs[opts.Skip:]
+	}
+	if opts.Limit > 0 && opts.Limit < int64(len(docs)) {
+		docs = docs[:opts.Limit]
+	}
+	for i := range docs {
+		docs[i] = Project(docs[i], opts.Select)
+	}
+	return docs, nil
+}
+
+// Aggregate executes an aggregation pipeline against the documents. The
+// $match, $sort, $skip, $limit, $project and $group stages are supported.
+// See https://docs.mongodb.com/manual/reference/operator/aggregation-pipeline/
+func Aggregate(docs []map[string]interface{}, pipeline []bson.D) ([]map[string]interface{}, error) {
+	for _, stage := range pipeline {
+		if len(stage) != 1 {
+			return nil, fmt.Errorf("each stage of an aggregation pipeline must be a document with a single operator")
+		}
+
+		var err error
+		operator, value := stage[0].Key, stage[0].Value
+		switch operator {
+		case "$match":
+			filter, ok := ToMap(value)
+			if !ok {
+				return nil, fmt.Errorf("the value of $match must be a document")
+			}
+			matches := make([]map[string]interface{}, 0, len(docs))
+			for _, doc := range docs {
+				matched, err := Match(doc, filter)
+				if err != nil {
+					return nil, err
+				}
+				if matched {
+					matches = append(matches, doc)
+				}
+			}
+			docs = matches
+		case "$sort":
+			sortDoc, ok := ToOrderedMap(value)
+			if !ok {
+				return nil, fmt.Errorf("the value of $sort must be a document")
+			}
+			err = Sort(docs, sortDoc)
+		case "$skip":
+			skip, ok := ToInt(value)
+			if !ok || skip < 0 {
+				return nil, fmt.Errorf("the value of $skip must be a positive integer")
+			}
+			if skip > int64(len(docs)) {
+				skip = int64(len(docs))
+			}
+			docs = docs[skip:]
+		case "$limit":
+			limit, ok := ToInt(value)
+			if !ok || limit <= 0 {
+				return nil, fmt.Errorf("the value of $limit must be a positive integer")
+			}
+			if limit < int64(len(docs)) {
+				docs = docs[:limit]
+			}
+		case "$project":
+			projection, ok := ToOrderedMap(value)
+			if !ok {
+				return nil, fmt.Errorf("the value of $project must be a document")
+			}
+			for i := range docs {
+				docs[i] = Project(docs[i], projection)
+			}
+		case "$group":
+			docs, err = Group(docs, value)
+		default:
+			return nil, fmt.Errorf("the %s aggregation stage is not supported", operator)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
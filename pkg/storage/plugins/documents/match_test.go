@@ -0,0 +1,106 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatch(t *testing.T) {
+	doc := map[string]interface{}{
+		"_id":            "1",
+		"namespace":      "dev",
+		"name":           "mybuns",
+		"labels":         map[string]interface{}{"team": "red"},
+		"credentialSets": []interface{}{"azure", "github"},
+		"sequence":       float64(5),
+		"uninstalled":    nil,
+	}
+
+	testcases := []struct {
+		name    string
+		filter  bson.M
+		want    bool
+		wantErr string
+	}{
+		{name: "empty", filter: bson.M{}, want: true},
+		{name: "equals", filter: bson.M{"namespace": "dev", "name": "mybuns"}, want: true},
+		{name: "not equal", filter: bson.M{"namespace": "dev", "name": "wordpress"}, want: false},
+		{name: "nested field", filter: bson.M{"labels.team": "red"}, want: true},
+		{name: "document", filter: bson.M{"labels": bson.M{"team": "red"}}, want: true},
+		{name: "array element", filter: bson.M{"credentialSets": "github"}, want: true},
+		{name: "null", filter: bson.M{"uninstalled": nil}, want: true},
+		{name: "missing is null", filter: bson.M{"schedule": nil}, want: true},
+		{name: "or", filter: bson.M{"$or": []bson.M{{"namespace": ""}, {"namespace": "dev"}}}, want: true},
+		{name: "nor", filter: bson.M{"$nor": []interface{}{bson.M{"namespace": "dev"}}}, want: false},
+		{name: "in", filter: bson.M{"_id": bson.M{"$in": []string{"1", "2"}}}, want: true},
+		{name: "nin", filter: bson.M{"_id": bson.M{"$nin": []string{"1", "2"}}}, want: false},
+		{name: "ne", filter: bson.M{"namespace": bson.M{"$ne": "test"}}, want: true},
+		{name: "gt", filter: bson.M{"sequence": bson.M{"$gt": int64(4)}}, want: true},
+		{name: "lte", filter: bson.M{"sequence": bson.M{"$lte": int32(4)}}, want: false},
+		{name: "gt different type", filter: bson.M{"sequence": bson.M{"$gt": "4"}}, want: false},
+		{name: "exists", filter: bson.M{"labels": bson.M{"$exists": true}}, want: true},
+		{name: "not exists", filter: bson.M{"schedule": bson.M{"$exists": false}}, want: true},
+		{name: "regex", filter: bson.M{"name": bson.M{"$regex": "^MY", "$options": "i"}}, want: true},
+		{name: "regex value", filter: bson.M{"name": primitive.Regex{Pattern: "buns$"}}, want: true},
+		{name: "unsupported operator", filter: bson.M{"name": bson.M{"$elemMatch": bson.M{}}}, wantErr: "the $elemMatch query operator is not supported"},
+		{name: "unsupported logical operator", filter: bson.M{"$where": "true"}, wantErr: "the $where query operator is not supported"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Match(doc, tc.filter)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	docs := []map[string]interface{}{
+		{"_id": "1", "namespace": "dev", "name": "b"},
+		{"_id": "2", "namespace": "dev", "name": "a"},
+		{"_id": "3", "name": "c"},
+		{"_id": "4", "namespace": "", "name": "d"},
+	}
+
+	err := Sort(docs, bson.D{{Key: "namespace", Value: 1}, {Key: "name", Value: -1}})
+	require.NoError(t, err)
+
+	ids := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc["_id"]
+	}
+	assert.Equal(t, []interface{}{"3", "4", "1", "2"}, ids, "documents without the field should be sorted first")
+
+	err = Sort(docs, bson.D{{Key: "name", Value: 0}})
+	require.EqualError(t, err, "invalid sort order 0 for name, the sort order must be 1 or -1")
+}
+
+func TestAggregate(t *testing.T) {
+	docs := []map[string]interface{}{
+		{"_id": "1", "name": "port", "resultId": "a"},
+		{"_id": "2", "name": "host", "resultId": "b"},
+		{"_id": "3", "name": "port", "resultId": "b"},
+	}
+
+	got, err := Aggregate(docs, []bson.D{
+		{{Key: "$match", Value: bson.M{"name": "port"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "resultId", Value: -1}}}},
+		{{Key: "$limit", Value: int64(1)}},
+		{{Key: "$project", Value: bson.D{{Key: "resultId", Value: 1}}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"_id": "3", "resultId": "b"}}, got)
+
+	_, err = Aggregate(docs, []bson.D{{{Key: "$unwind", Value: "$name"}}})
+	require.EqualError(t, err, "the $unwind aggregation stage is not supported")
+}
@@ -0,0 +1,5 @@
+// Package dynamodb implements the plugins.StorageProtocol interface, storing
+// data as json documents in a single Amazon DynamoDB table. It lets porter
+// clients that do not have a database server, such as ephemeral CI runners,
+// share their data.
+package dynamodb
@@ -0,0 +1,728 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/plugins/documents"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var _ plugins.StorageProtocol = &Store{}
+
+const (
+	// Attributes of the items in the table
+	attrPartitionKey = "pk"
+	attrSortKey      = "sk"
+	attrDocument     = "doc"
+	attrVersion      = "version"
+	attrDocumentID   = "docId"
+
+	// indexesPartition stores the definition of the unique indexes. Mongodb
+	// does not allow $ in collection names, so it does not conflict with a
+	// collection.
+	indexesPartition = "$indexes"
+
+	// uniquePrefix is the prefix of the partitions that store the values of
+	// a unique index, one partition per index.
+	uniquePrefix = "$unique#"
+
+	// Conditions of the writes to the table
+	conditionNotExists = "attribute_not_exists(" + attrPartitionKey + ")"
+	conditionVersion   = attrVersion + " = :version"
+
+	// maxAttempts is the number of times that a modification is attempted
+	// when another process modifies the same document concurrently.
+	maxAttempts = 5
+)
+
+// errConflict is returned when a document was modified by another process
+// after it was read, and the modification should be retried.
+var errConflict = errors.New("the document was modified by another process")
+
+// client is the subset of the DynamoDB api used by the plugin.
+type client interface {
+	awsdynamodb.QueryAPIClient
+	awsdynamodb.DescribeTableAPIClient
+	CreateTable(ctx context.Context, params *awsdynamodb.CreateTableInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.CreateTableOutput, error)
+	TransactWriteItems(ctx context.Context, params *awsdynamodb.TransactWriteItemsInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.TransactWriteItemsOutput, error)
+}
+
+// Store implements the Porter plugin.StoragePlugin interface for Amazon
+// DynamoDB.
+//
+// All collections are stored in a single table. The partition key of an item
+// is the name of the collection, the sort key is the _id of the document, and
+// the document is saved as json. Queries are evaluated by reading the
+// documents in the collection.
+//
+// Each item has a version that is incremented when the document is modified.
+// Documents are only saved when their version has not changed since they were
+// read, and the modification is retried otherwise, so that concurrent updates
+// to an installation from multiple porter clients are not lost.
+type Store struct {
+	*portercontext.Context
+
+	table    string
+	region   string
+	endpoint string
+	profile  string
+	timeout  time.Duration
+	client   client
+
+	// connected is set once the table is available
+	connected bool
+}
+
+// NewStore creates a new storage engine that uses Amazon DynamoDB.
+func NewStore(c *portercontext.Context, cfg PluginConfig) *Store {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60 // default to 60 seconds
+	}
+	table := cfg.Table
+	if table == "" {
+		table = DefaultTable
+	}
+	return &Store{
+		Context:  c,
+		table:    table,
+		region:   cfg.Region,
+		endpoint: cfg.Endpoint,
+		profile:  cfg.Profile,
+		timeout:  time.Duration(timeout) * time.Second,
+	}
+}
+
+// Connect initializes the plugin for use.
+// The plugin itself is responsible for ensuring it was called.
+// Close is called automatically when the plugin is used by Porter.
+func (s *Store) Connect(ctx context.Context) error {
+	if s.connected {
+		return nil
+	}
+
+	ctx, span := tracing.StartSpan(ctx, attribute.String("table", s.table))
+	defer span.EndSpan()
+
+	if s.client == nil {
+		var opts []func(*awsconfig.LoadOptions) error
+		if s.region != "" {
+			opts = append(opts, awsconfig.WithRegion(s.region))
+		}
+		if s.profile != "" {
+			opts = append(opts, awsconfig.WithSharedConfigProfile(s.profile))
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			return span.Error(fmt.Errorf("could not load the AWS configuration: %w", err))
+		}
+
+		s.client = awsdynamodb.NewFromConfig(cfg, func(o *awsdynamodb.Options) {
+			if s.endpoint != "" {
+				o.BaseEndpoint = aws.String(s.endpoint)
+			}
+		})
+	}
+
+	if err := s.ensureTable(ctx); err != nil {
+		return span.Error(err)
+	}
+
+	s.connected = true
+	return nil
+}
+
+func (s *Store) Close() error {
+	s.connected = false
+	return nil
+}
+
+// ensureTable creates the table when it does not exist, and waits for it to
+// be available.
+func (s *Store) ensureTable(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	_, err := s.client.DescribeTable(ctx, &awsdynamodb.DescribeTableInput{TableName: aws.String(s.table)})
+	if err == nil {
+		return nil
+	}
+
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return fmt.Errorf("could not connect to dynamodb table %s: %w", s.table, err)
+	}
+
+	span.Debugf("Creating dynamodb table %s", s.table)
+	_, err = s.client.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+		TableName:   aws.String(s.table),
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String(attrPartitionKey), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String(attrSortKey), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String(attrPartitionKey), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String(attrSortKey), KeyType: types.KeyTypeRange},
+		},
+	})
+	var inUse *types.ResourceInUseException
+	if err != nil && !errors.As(err, &inUse) { // Another porter client may have created it first
+		return fmt.Errorf("could not create dynamodb table %s: %w", s.table, err)
+	}
+
+	waiter := awsdynamodb.NewTableExistsWaiter(s.client)
+	if err = waiter.Wait(ctx, &awsdynamodb.DescribeTableInput{TableName: aws.String(s.table)}, s.timeout); err != nil {
+		return fmt.Errorf("timed out waiting for dynamodb table %s to be created: %w", s.table, err)
+	}
+	return nil
+}
+
+func (s *Store) Aggregate(ctx context.Context, opts plugins.AggregateOptions) ([]bson.Raw, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	items, err := s.findItems(ctx, opts.Collection, nil, false)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	docs, err := documents.Aggregate(toDocuments(items), opts.Pipeline)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	results, err := documents.ToRawDocuments(docs)
+	return results, span.Error(err)
+}
+
+// EnsureIndex makes sure that the specified indexes exist and are
+// defined appropriately. Queries read all the documents in a collection, so
+// only the definition of unique indexes is saved, to enforce them when
+// documents are modified.
+func (s *Store) EnsureIndex(ctx context.Context, opts plugins.EnsureIndexOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	for _, index := range opts.Indices {
+		// Name the index after its keys, like mongodb, e.g. installations_namespace_1_name_1
+		def := indexDefinition{Collection: index.Collection}
+		name := index.Collection
+		for _, key := range index.Keys {
+			direction, ok := documents.ToInt(key.Value)
+			if !ok || (direction != 1 && direction != -1) {
+				return span.Error(fmt.Errorf("invalid index %s on %s, the sort order must be 1 or -1", key.Key, index.Collection))
+			}
+			name += fmt.Sprintf("_%s_%d", key.Key, direction)
+			def.Keys = append(def.Keys, key.Key)
+		}
+
+		if !index.Unique {
+			continue
+		}
+
+		data, err := json.Marshal(def)
+		if err != nil {
+			return span.Error(fmt.Errorf("invalid index specified: %s: %w", name, err))
+		}
+
+		// Saving the definition again is harmless, the same index always has the same definition
+		_, err = s.client.TransactWriteItems(ctx, &awsdynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{
+				{Put: &types.Put{
+					TableName: aws.String(s.table),
+					Item: map[string]types.AttributeValue{
+						attrPartitionKey: &types.AttributeValueMemberS{Value: indexesPartition},
+						attrSortKey:      &types.AttributeValueMemberS{Value: name},
+						attrDocument:     &types.AttributeValueMemberS{Value: string(data)},
+					},
+				}},
+			},
+		})
+		if err != nil {
+			return span.Error(fmt.Errorf("could not save index %s: %w", name, err))
+		}
+	}
+	return nil
+}
+
+func (s *Store) Count(ctx context.Context, opts plugins.CountOptions) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return 0, err
+	}
+
+	items, err := s.findItems(ctx, opts.Collection, opts.Filter, false)
+	return int64(len(items)), span.Error(err)
+}
+
+func (s *Store) Find(ctx context.Context, opts plugins.FindOptions) ([]bson.Raw, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	items, err := s.findItems(ctx, opts.Collection, opts.Filter, false)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	docs, err := documents.Find(toDocuments(items), opts)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	results, err := documents.ToRawDocuments(docs)
+	return results, span.Error(err)
+}
+
+func (s *Store) Insert(ctx context.Context, opts plugins.InsertOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	for _, doc := range opts.Documents {
+		id, data, err := documents.Marshal(doc)
+		if err != nil {
+			return span.Error(err)
+		}
+
+		var parsed map[string]interface{}
+		if err = json.Unmarshal(data, &parsed); err != nil {
+			return span.Error(err)
+		}
+
+		err = s.write(ctx, opts.Collection, nil, id, parsed)
+		if errors.Is(err, errConflict) {
+			return span.Error(fmt.Errorf("duplicate key error: a document with _id %s already exists in the %s collection", id, opts.Collection))
+		} else if err != nil {
+			return span.Error(err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) Patch(ctx context.Context, opts plugins.PatchOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.retry(ctx, opts.Collection, func() error {
+		items, err := s.findItems(ctx, opts.Collection, opts.QueryDocument, true)
+		if err != nil || len(items) == 0 {
+			return err
+		}
+
+		existing := items[0]
+		doc, err := copyDocument(existing.doc)
+		if err != nil {
+			return err
+		}
+		if err = documents.ApplyPatch(doc, opts.Transformation); err != nil {
+			return err
+		}
+
+		id, _, err := documents.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if id != existing.id {
+			return fmt.Errorf("the _id of document %s in the %s collection cannot be changed to %s", existing.id, opts.Collection, id)
+		}
+		return s.write(ctx, opts.Collection, &existing, id, doc)
+	})
+	return span.Error(err)
+}
+
+func (s *Store) Remove(ctx context.Context, opts plugins.RemoveOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.retry(ctx, opts.Collection, func() error {
+		items, err := s.findItems(ctx, opts.Collection, opts.Filter, !opts.All)
+		if err != nil {
+			return err
+		}
+
+		for _, existing := range items {
+			existing := existing
+			if err = s.write(ctx, opts.Collection, &existing, existing.id, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return span.Error(err)
+}
+
+func (s *Store) Update(ctx context.Context, opts plugins.UpdateOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return err
+	}
+
+	err := s.retry(ctx, opts.Collection, func() error {
+		items, err := s.findItems(ctx, opts.Collection, opts.Filter, true)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 && !opts.Upsert {
+			return nil
+		}
+
+		// Replace the first matching document, keeping its id when the replacement does not have one
+		doc := make(bson.M, len(opts.Document))
+		for key, value := range opts.Document {
+			doc[key] = value
+		}
+		var existing *item
+		if len(items) > 0 {
+			existing = &items[0]
+			if _, hasID := doc["_id"]; !hasID {
+				doc["_id"] = existing.doc["_id"]
+			}
+		}
+
+		id, data, err := documents.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if existing != nil && id != existing.id {
+			return fmt.Errorf("the _id of document %s in the %s collection cannot be changed to %s", existing.id, opts.Collection, id)
+		}
+
+		var parsed map[string]interface{}
+		if err = json.Unmarshal(data, &parsed); err != nil {
+			return err
+		}
+		return s.write(ctx, opts.Collection, existing, id, parsed)
+	})
+	return span.Error(err)
+}
+
+// indexDefinition is the definition of a unique index saved in the table.
+type indexDefinition struct {
+	Collection string   `json:"collection"`
+	Keys       []string `json:"keys"`
+}
+
+// item is a document read from the table.
+type item struct {
+	id      string
+	version int64
+	doc     map[string]interface{}
+}
+
+// retry executes an operation that reads documents and then modifies them,
+// repeating it when another process modified one of the documents in between.
+func (s *Store) retry(ctx context.Context, collection string, operation func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if !errors.Is(err, errConflict) {
+			return err
+		}
+		if attempt == maxAttempts {
+			return fmt.Errorf("could not modify the %s collection after %d attempts: %w", collection, attempt, err)
+		}
+
+		tracing.LoggerFromContext(ctx).Debugf("retrying the modification of the %s collection: %s", collection, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+		}
+	}
+}
+
+// query returns all the items in a partition of the table.
+func (s *Store) query(ctx context.Context, partition string) ([]map[string]types.AttributeValue, error) {
+	paginator := awsdynamodb.NewQueryPaginator(s.client, &awsdynamodb.QueryInput{
+		TableName:              aws.String(s.table),
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String(attrPartitionKey + " = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: partition},
+		},
+	})
+
+	var results []map[string]types.AttributeValue
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not query dynamodb table %s: %w", s.table, err)
+		}
+		results = append(results, page.Items...)
+	}
+	return results, nil
+}
+
+// findItems returns the documents in a collection that match a filter.
+// When first is true, only the first matching document is returned.
+func (s *Store) findItems(ctx context.Context, collection string, filter bson.M, first bool) ([]item, error) {
+	results, err := s.query(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []item
+	for _, result := range results {
+		i, err := parseItem(result)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse a document from the %s collection: %w", collection, err)
+		}
+
+		matched, err := documents.Match(i.doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		items = append(items, i)
+		if first {
+			break
+		}
+	}
+	return items, nil
+}
+
+// findIndexes returns the unique indexes defined on a collection, by name.
+func (s *Store) findIndexes(ctx context.Context, collection string) (map[string]indexDefinition, error) {
+	results, err := s.query(ctx, indexesPartition)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[string]indexDefinition)
+	for _, result := range results {
+		name := stringAttribute(result, attrSortKey)
+		var index indexDefinition
+		if err := json.Unmarshal([]byte(stringAttribute(result, attrDocument)), &index); err != nil {
+			return nil, fmt.Errorf("could not parse the definition of index %s: %w", name, err)
+		}
+		if index.Collection == collection {
+			indexes[name] = index
+		}
+	}
+	return indexes, nil
+}
+
+// write saves the modification of a document, and the values of its unique
+// indexes, in a single transaction. existing is the document that was read
+// before it was modified, or nil when the document is inserted, and doc is nil
+// when the document is removed. errConflict is returned when the document was
+// modified by another process since it was read, or when an inserted document
+// already exists.
+func (s *Store) write(ctx context.Context, collection string, existing *item, id string, doc map[string]interface{}) error {
+	indexes, err := s.findIndexes(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	docKey := key(collection, id)
+	var ops []types.TransactWriteItem
+	if doc == nil {
+		ops = append(ops, types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 aws.String(s.table),
+			Key:                       docKey,
+			ConditionExpression:       aws.String(conditionVersion),
+			ExpressionAttributeValues: versionValue(existing.version),
+		}})
+	} else {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("could not marshal document %s to json: %w", id, err)
+		}
+
+		put := &types.Put{
+			TableName: aws.String(s.table),
+			Item:      key(collection, id),
+		}
+		put.Item[attrDocument] = &types.AttributeValueMemberS{Value: string(data)}
+		if existing == nil {
+			put.Item[attrVersion] = &types.AttributeValueMemberN{Value: "1"}
+			put.ConditionExpression = aws.String(conditionNotExists)
+		} else {
+			put.Item[attrVersion] = &types.AttributeValueMemberN{Value: strconv.FormatInt(existing.version+1, 10)}
+			put.ConditionExpression = aws.String(conditionVersion)
+			put.ExpressionAttributeValues = versionValue(existing.version)
+		}
+		ops = append(ops, types.TransactWriteItem{Put: put})
+	}
+
+	// Claim the values of the unique indexes for the document, releasing its previous values.
+	// opIndexes is the name of the index for each operation, to report which one was violated.
+	names := make([]string, 0, len(indexes))
+	for name := range indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	opIndexes := make([]string, len(ops), len(ops)+2*len(names))
+	for _, name := range names {
+		index := indexes[name]
+		var oldValue, newValue string
+		if existing != nil {
+			if oldValue, err = indexValues(index, existing.doc); err != nil {
+				return err
+			}
+		}
+		if doc != nil {
+			if newValue, err = indexValues(index, doc); err != nil {
+				return err
+			}
+		}
+		if existing != nil && doc != nil && oldValue == newValue {
+			continue
+		}
+
+		if existing != nil {
+			ops = append(ops, types.TransactWriteItem{Delete: &types.Delete{
+				TableName: aws.String(s.table),
+				Key:       key(uniquePrefix+name, oldValue),
+			}})
+			opIndexes = append(opIndexes, name)
+		}
+		if doc != nil {
+			put := &types.Put{
+				TableName:           aws.String(s.table),
+				Item:                key(uniquePrefix+name, newValue),
+				ConditionExpression: aws.String(conditionNotExists),
+			}
+			put.Item[attrDocumentID] = &types.AttributeValueMemberS{Value: id}
+			ops = append(ops, types.TransactWriteItem{Put: put})
+			opIndexes = append(opIndexes, name)
+		}
+	}
+
+	_, err = s.client.TransactWriteItems(ctx, &awsdynamodb.TransactWriteItemsInput{TransactItems: ops})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for i, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) != "ConditionalCheckFailed" {
+				continue
+			}
+			if i == 0 {
+				return errConflict
+			}
+			return fmt.Errorf("duplicate key error: the document %s has the same values as another document for the unique index %s", id, opIndexes[i])
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("could not save document %s in the %s collection: %w", id, collection, err)
+	}
+	return nil
+}
+
+// key returns the primary key of an item in the table.
+func key(partition string, sortKey string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		attrPartitionKey: &types.AttributeValueMemberS{Value: partition},
+		attrSortKey:      &types.AttributeValueMemberS{Value: sortKey},
+	}
+}
+
+// versionValue returns the value of the version in a conditional write.
+func versionValue(version int64) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		":version": &types.AttributeValueMemberN{Value: strconv.FormatInt(version, 10)},
+	}
+}
+
+// indexValues returns the json representation of the values of the keys of
+// an index in a document. Similar to mongodb, a missing field is indexed as
+// null.
+func indexValues(index indexDefinition, doc map[string]interface{}) (string, error) {
+	values := make([]interface{}, len(index.Keys))
+	for i, key := range index.Keys {
+		values[i], _ = documents.GetField(doc, key)
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal the values of the index on %s: %w", index.Collection, err)
+	}
+	return string(data), nil
+}
+
+// parseItem reads a document and its version from an item in the table.
+func parseItem(result map[string]types.AttributeValue) (item, error) {
+	i := item{id: stringAttribute(result, attrSortKey)}
+	if err := json.Unmarshal([]byte(stringAttribute(result, attrDocument)), &i.doc); err != nil {
+		return item{}, fmt.Errorf("invalid document %s: %w", i.id, err)
+	}
+
+	if version, ok := result[attrVersion].(*types.AttributeValueMemberN); ok {
+		var err error
+		if i.version, err = strconv.ParseInt(version.Value, 10, 64); err != nil {
+			return item{}, fmt.Errorf("invalid version %s of document %s: %w", version.Value, i.id, err)
+		}
+	}
+	return i, nil
+}
+
+// stringAttribute returns the value of a string attribute of an item.
+func stringAttribute(result map[string]types.AttributeValue, name string) string {
+	if value, ok := result[name].(*types.AttributeValueMemberS); ok {
+		return value.Value
+	}
+	return ""
+}
+
+// copyDocument returns a copy of a document read from json, so that it can be
+// modified without changing the document that was read.
+func copyDocument(doc map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// toDocuments returns the documents of the items.
+func toDocuments(items []item) []map[string]interface{} {
+	docs := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		docs[i] = item.doc
+	}
+	return docs
+}
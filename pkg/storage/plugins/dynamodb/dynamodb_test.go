@@ -0,0 +1,338 @@
+package dynamodb
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var _ client = &testClient{}
+
+// testClient is an in-memory DynamoDB table that supports the operations and
+// conditions used by the plugin.
+type testClient struct {
+	mu      sync.Mutex
+	created bool
+	items   map[string]map[string]map[string]types.AttributeValue
+
+	// beforeWrite is called before a transaction is executed
+	beforeWrite func(c *testClient)
+}
+
+func newTestClient() *testClient {
+	return &testClient{items: make(map[string]map[string]map[string]types.AttributeValue)}
+}
+
+func (c *testClient) DescribeTable(ctx context.Context, params *awsdynamodb.DescribeTableInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.DescribeTableOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.created {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("table not found")}
+	}
+	return &awsdynamodb.DescribeTableOutput{Table: &types.TableDescription{TableName: params.TableName, TableStatus: types.TableStatusActive}}, nil
+}
+
+func (c *testClient) CreateTable(ctx context.Context, params *awsdynamodb.CreateTableInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.CreateTableOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.created = true
+	return &awsdynamodb.CreateTableOutput{}, nil
+}
+
+func (c *testClient) Query(ctx context.Context, params *awsdynamodb.QueryInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.QueryOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	partition := c.items[params.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS).Value]
+	keys := make([]string, 0, len(partition))
+	for key := range partition {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := &awsdynamodb.QueryOutput{}
+	for _, key := range keys {
+		out.Items = append(out.Items, partition[key])
+	}
+	return out, nil
+}
+
+func (c *testClient) TransactWriteItems(ctx context.Context, params *awsdynamodb.TransactWriteItemsInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.TransactWriteItemsOutput, error) {
+	if c.beforeWrite != nil {
+		c.beforeWrite(c)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Check all the conditions before modifying the table
+	reasons := make([]types.CancellationReason, len(params.TransactItems))
+	canceled := false
+	for i, op := range params.TransactItems {
+		reasons[i].Code = aws.String("None")
+		var itemKey map[string]types.AttributeValue
+		var condition *string
+		var values map[string]types.AttributeValue
+		if op.Put != nil {
+			itemKey, condition, values = op.Put.Item, op.Put.ConditionExpression, op.Put.ExpressionAttributeValues
+		} else {
+			itemKey, condition, values = op.Delete.Key, op.Delete.ConditionExpression, op.Delete.ExpressionAttributeValues
+		}
+
+		existing := c.get(itemKey)
+		ok := true
+		switch aws.ToString(condition) {
+		case "":
+		case conditionNotExists:
+			ok = existing == nil
+		case conditionVersion:
+			ok = existing != nil && existing[attrVersion].(*types.AttributeValueMemberN).Value == values[":version"].(*types.AttributeValueMemberN).Value
+		default:
+			panic("unsupported condition " + aws.ToString(condition))
+		}
+		if !ok {
+			reasons[i].Code = aws.String("ConditionalCheckFailed")
+			canceled = true
+		}
+	}
+	if canceled {
+		return nil, &types.TransactionCanceledException{Message: aws.String("transaction canceled"), CancellationReasons: reasons}
+	}
+
+	for _, op := range params.TransactItems {
+		if op.Put != nil {
+			pk, sk := keyValues(op.Put.Item)
+			if c.items[pk] == nil {
+				c.items[pk] = make(map[string]map[string]types.AttributeValue)
+			}
+			c.items[pk][sk] = op.Put.Item
+		} else {
+			pk, sk := keyValues(op.Delete.Key)
+			delete(c.items[pk], sk)
+		}
+	}
+	return &awsdynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func (c *testClient) get(itemKey map[string]types.AttributeValue) map[string]types.AttributeValue {
+	pk, sk := keyValues(itemKey)
+	return c.items[pk][sk]
+}
+
+func keyValues(itemKey map[string]types.AttributeValue) (string, string) {
+	return stringAttribute(itemKey, attrPartitionKey), stringAttribute(itemKey, attrSortKey)
+}
+
+func newTestStore(t *testing.T) (*Store, *testClient) {
+	tc := portercontext.NewTestContext(t)
+	s := NewStore(tc.Context, PluginConfig{})
+	c := newTestClient()
+	s.client = c
+	t.Cleanup(func() { s.Close() })
+	return s, c
+}
+
+func unmarshalResults(t *testing.T, results []bson.Raw) []bson.M {
+	docs := make([]bson.M, len(results))
+	for i, result := range results {
+		require.NoError(t, bson.Unmarshal(result, &docs[i]))
+	}
+	return docs
+}
+
+func TestStore_Connect_CreateTable(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStore(t)
+
+	require.NoError(t, s.Connect(ctx))
+	assert.True(t, c.created, "the table should be created when it does not exist")
+}
+
+func TestStore_CRUD(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(t)
+
+	err := s.Insert(ctx, plugins.InsertOptions{
+		Collection: "installations",
+		Documents: []bson.M{
+			{"_id": "1", "namespace": "dev", "name": "mybuns", "labels": bson.M{"team": "red"}},
+			{"_id": "2", "namespace": "dev", "name": "wordpress"},
+			{"_id": "3", "namespace": "", "name": "mysql"},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("find", func(t *testing.T) {
+		results, err := s.Find(ctx, plugins.FindOptions{
+			Collection: "installations",
+			Filter:     bson.M{"$or": []bson.M{{"namespace": "dev"}, {"namespace": ""}}},
+			Sort:       bson.D{{Key: "name", Value: -1}},
+			Skip:       1,
+			Limit:      1,
+			Select:     bson.D{{Key: "name", Value: 1}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []bson.M{{"_id": "3", "name": "mysql"}}, unmarshalResults(t, results))
+	})
+
+	t.Run("count", func(t *testing.T) {
+		count, err := s.Count(ctx, plugins.CountOptions{Collection: "installations", Filter: bson.M{"labels.team": "red"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		err := s.Patch(ctx, plugins.PatchOptions{
+			Collection:     "installations",
+			QueryDocument:  bson.M{"_id": "2"},
+			Transformation: bson.D{{Key: "$set", Value: bson.M{"uninstalled": true}}},
+		})
+		require.NoError(t, err)
+
+		count, err := s.Count(ctx, plugins.CountOptions{Collection: "installations", Filter: bson.M{"uninstalled": true}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		err := s.Update(ctx, plugins.UpdateOptions{
+			Collection: "installations",
+			Filter:     bson.M{"_id": "3"},
+			Document:   bson.M{"namespace": "", "name": "mysql", "uninstalled": true},
+		})
+		require.NoError(t, err)
+
+		err = s.Update(ctx, plugins.UpdateOptions{
+			Collection: "installations",
+			Filter:     bson.M{"_id": "4"},
+			Upsert:     true,
+			Document:   bson.M{"_id": "4", "namespace": "test", "name": "redis"},
+		})
+		require.NoError(t, err)
+
+		results, err := s.Find(ctx, plugins.FindOptions{
+			Collection: "installations",
+			Filter:     bson.M{"_id": bson.M{"$in": []string{"3", "4"}}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []bson.M{
+			{"_id": "3", "namespace": "", "name": "mysql", "uninstalled": true},
+			{"_id": "4", "namespace": "test", "name": "redis"},
+		}, unmarshalResults(t, results))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := s.Remove(ctx, plugins.RemoveOptions{Collection: "installations", Filter: bson.M{"uninstalled": true}, All: true})
+		require.NoError(t, err)
+
+		count, err := s.Count(ctx, plugins.CountOptions{Collection: "installations"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestStore_UniqueIndex(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(t)
+
+	err := s.EnsureIndex(ctx, plugins.EnsureIndexOptions{Indices: []plugins.Index{
+		{Collection: "installations", Keys: bson.D{{Key: "namespace", Value: 1}, {Key: "name", Value: 1}}, Unique: true},
+	}})
+	require.NoError(t, err)
+
+	err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "1", "namespace": "dev", "name": "mybuns"}}})
+	require.NoError(t, err)
+
+	t.Run("duplicate id", func(t *testing.T) {
+		err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "1", "namespace": "dev", "name": "other"}}})
+		require.EqualError(t, err, "duplicate key error: a document with _id 1 already exists in the installations collection")
+	})
+
+	t.Run("duplicate values", func(t *testing.T) {
+		err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "2", "namespace": "dev", "name": "mybuns"}}})
+		require.EqualError(t, err, "duplicate key error: the document 2 has the same values as another document for the unique index installations_namespace_1_name_1")
+	})
+
+	t.Run("values released", func(t *testing.T) {
+		err = s.Patch(ctx, plugins.PatchOptions{
+			Collection:     "installations",
+			QueryDocument:  bson.M{"_id": "1"},
+			Transformation: bson.D{{Key: "$set", Value: bson.M{"name": "renamed"}}},
+		})
+		require.NoError(t, err)
+
+		err = s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "2", "namespace": "dev", "name": "mybuns"}}})
+		require.NoError(t, err, "the previous values of a modified document should be available to other documents")
+	})
+}
+
+func TestStore_Patch_ConcurrentModification(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStore(t)
+
+	err := s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "1", "name": "mybuns", "revision": 1}}})
+	require.NoError(t, err)
+
+	// Simulate another porter client that modifies the document after it was read
+	conflicts := 0
+	c.beforeWrite = func(c *testClient) {
+		if conflicts > 0 {
+			return
+		}
+		conflicts++
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		existing := c.items["installations"]["1"]
+		version, _ := strconv.Atoi(existing[attrVersion].(*types.AttributeValueMemberN).Value)
+		existing[attrDocument] = &types.AttributeValueMemberS{Value: `{"_id":"1","name":"mybuns","revision":2,"labels":{"team":"red"}}`}
+		existing[attrVersion] = &types.AttributeValueMemberN{Value: strconv.Itoa(version + 1)}
+	}
+
+	err = s.Patch(ctx, plugins.PatchOptions{
+		Collection:     "installations",
+		QueryDocument:  bson.M{"_id": "1"},
+		Transformation: bson.D{{Key: "$set", Value: bson.M{"uninstalled": true}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, conflicts)
+
+	results, err := s.Find(ctx, plugins.FindOptions{Collection: "installations"})
+	require.NoError(t, err)
+	assert.Equal(t, []bson.M{
+		{"_id": "1", "name": "mybuns", "revision": float64(2), "labels": bson.M{"team": "red"}, "uninstalled": true},
+	}, unmarshalResults(t, results), "the patch should be applied to the document saved by the other client")
+}
+
+func TestStore_Update_TooManyConflicts(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStore(t)
+
+	err := s.Insert(ctx, plugins.InsertOptions{Collection: "installations", Documents: []bson.M{{"_id": "1", "name": "mybuns"}}})
+	require.NoError(t, err)
+
+	// Every write conflicts with another client
+	c.beforeWrite = func(c *testClient) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		existing := c.items["installations"]["1"]
+		version, _ := strconv.Atoi(existing[attrVersion].(*types.AttributeValueMemberN).Value)
+		existing[attrVersion] = &types.AttributeValueMemberN{Value: strconv.Itoa(version + 1)}
+	}
+
+	err = s.Update(ctx, plugins.UpdateOptions{Collection: "installations", Filter: bson.M{"_id": "1"}, Document: bson.M{"name": "mybuns", "uninstalled": true}})
+	require.EqualError(t, err, "could not modify the installations collection after 5 attempts: the document was modified by another process")
+}
@@ -0,0 +1,52 @@
+package dynamodb
+
+import (
+	"fmt"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/pluginstore"
+	"github.com/hashicorp/go-plugin"
+	"github.com/mitchellh/mapstructure"
+)
+
+// PluginKey is the identifier of the internal dynamodb plugin.
+const PluginKey = plugins.PluginInterface + ".porter.dynamodb"
+
+// DefaultTable is the name of the table used when one is not configured.
+const DefaultTable = "porter"
+
+// PluginConfig are the configuration settings that can be defined for the
+// dynamodb plugin in porter.yaml
+type PluginConfig struct {
+	// Table where the data is stored. It is created when it does not exist.
+	Table string `mapstructure:"table,omitempty"`
+
+	// Region of the table. Defaults to the region of the AWS configuration,
+	// e.g. the AWS_REGION environment variable.
+	Region string `mapstructure:"region,omitempty"`
+
+	// Endpoint overrides the url of the DynamoDB service, for example to use
+	// DynamoDB Local.
+	Endpoint string `mapstructure:"endpoint,omitempty"`
+
+	// Profile is the name of the AWS shared configuration profile to use.
+	Profile string `mapstructure:"profile,omitempty"`
+
+	// Timeout in seconds to wait for the table to be available.
+	Timeout int `mapstructure:"timeout,omitempty"`
+}
+
+// NewPlugin creates an instance of the storage.porter.dynamodb plugin
+func NewPlugin(c *portercontext.Context, rawCfg interface{}) (plugin.Plugin, error) {
+	cfg := PluginConfig{
+		Table:   DefaultTable,
+		Timeout: 60,
+	}
+	if err := mapstructure.Decode(rawCfg, &cfg); err != nil {
+		return nil, fmt.Errorf("error reading plugin configuration: %w", err)
+	}
+
+	impl := NewStore(c, cfg)
+	return pluginstore.NewPlugin(c, impl), nil
+}
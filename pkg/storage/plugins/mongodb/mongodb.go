@@ -105,6 +105,22 @@ func (s *Store) Ping(ctx context.Context) error {
 	return s.client.Ping(cxt, readpref.Primary())
 }
 
+// ListCollections returns the names of the collections in the database.
+func (s *Store) ListCollections(ctx context.Context) ([]string, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	cxt, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	names, err := s.client.Database(s.database).ListCollectionNames(cxt, bson.D{})
+	return names, span.Error(err)
+}
+
 func (s *Store) Aggregate(ctx context.Context, opts plugins.AggregateOptions) ([]bson.Raw, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.EndSpan()
@@ -13,6 +13,14 @@ import (
 // PluginKey is the identifier of the internal mongodb run in docker plugin.
 const PluginKey = plugins.PluginInterface + ".porter.mongodb-docker"
 
+const (
+	// DefaultPort is the port on the host where mongodb is exposed by default.
+	DefaultPort = "27018"
+
+	// DefaultDatabase is the name of the database used by default.
+	DefaultDatabase = "porter"
+)
+
 // PluginConfig supported by the mongodb-docker plugin as defined in porter.yaml
 type PluginConfig struct {
 	Port     string `mapstructure:"port,omitempty"`
@@ -25,8 +33,8 @@ type PluginConfig struct {
 // NewPlugin creates an instance of the storage.porter.mongodb-docker plugin
 func NewPlugin(c *portercontext.Context, rawCfg interface{}) (plugin.Plugin, error) {
 	cfg := PluginConfig{
-		Port:     "27018",
-		Database: "porter",
+		Port:     DefaultPort,
+		Database: DefaultDatabase,
 		Timeout:  10,
 	}
 	if err := mapstructure.Decode(rawCfg, &cfg); err != nil {
@@ -18,6 +18,14 @@ import (
 
 var _ plugins.StorageProtocol = &Store{}
 
+const (
+	// ContainerName is the name of the container that runs mongodb.
+	ContainerName = "porter-mongodb-docker-plugin"
+
+	// DataVolumeName is the name of the docker volume where mongodb stores its data.
+	DataVolumeName = ContainerName + "-data"
+)
+
 // Store is a storage plugin for porter suitable for running on machines
 // that have not configured proper storage, i.e. a mongo database.
 // It runs mongodb in a docker container and stores its data in a docker volume.
@@ -51,10 +59,7 @@ func (s *Store) Connect(ctx context.Context) error {
 	}
 
 	// Run mongo in a container storing its data in a volume
-	container := "porter-mongodb-docker-plugin"
-	dataVol := container + "-data"
-
-	conn, err := EnsureMongoIsRunning(ctx, s.context, container, s.config.Port, dataVol, s.config.Database, s.config.Timeout)
+	conn, err := EnsureMongoIsRunning(ctx, s.context, ContainerName, s.config.Port, DataVolumeName, s.config.Database, s.config.Timeout)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,3 @@
+// Package postgres implements the plugins.StorageProtocol interface, storing
+// data as jsonb documents in a PostgreSQL database.
+package postgres
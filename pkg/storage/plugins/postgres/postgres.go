@@ -0,0 +1,539 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"get.porter.sh/porter/pkg/portercontext"
+	"get.porter.sh/porter/pkg/storage/plugins"
+	"get.porter.sh/porter/pkg/storage/plugins/documents"
+	"get.porter.sh/porter/pkg/tracing"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var _ plugins.StorageProtocol = &Store{}
+
+// Store implements the Porter plugin.StoragePlugin interface for PostgreSQL.
+//
+// Each collection is stored in a table with the same name, that has an id
+// column with the _id of the document, and a jsonb doc column with the
+// document. The mongodb queries used by Porter are translated to SQL that
+// queries the jsonb documents.
+type Store struct {
+	*portercontext.Context
+
+	url     string
+	db      *sql.DB
+	timeout time.Duration
+
+	// tables that exist in the database, so that they are only created once.
+	tables   map[string]bool
+	tablesMu sync.Mutex
+}
+
+// NewStore creates a new storage engine that uses PostgreSQL.
+func NewStore(c *portercontext.Context, cfg PluginConfig) *Store {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 // default to 10 seconds
+	}
+	return &Store{
+		Context: c,
+		url:     cfg.URL,
+		timeout: time.Duration(timeout) * time.Second,
+		tables:  make(map[string]bool),
+	}
+}
+
+// Connect initializes the plugin for use.
+// The plugin itself is responsible for ensuring it was called.
+// Close is called automatically when the plugin is used by Porter.
+func (s *Store) Connect(ctx context.Context) error {
+	if s.db != nil {
+		return nil
+	}
+
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	db, err := sql.Open("postgres", s.url)
+	if err != nil {
+		// I'm not tracing additional information like the url since it is sensitive data
+		return span.Error(fmt.Errorf("invalid postgres connection string"))
+	}
+
+	cxt, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err = db.PingContext(cxt); err != nil {
+		db.Close()
+		return span.Error(fmt.Errorf("could not connect to postgres: %w", err))
+	}
+
+	s.db = db
+	return nil
+}
+
+func (s *Store) Close() error {
+	if s.db != nil {
+		s.db.Close()
+		s.db = nil
+	}
+	return nil
+}
+
+// ensureTable creates the table for a collection if it does not exist.
+func (s *Store) ensureTable(ctx context.Context, collection string) error {
+	s.tablesMu.Lock()
+	defer s.tablesMu.Unlock()
+
+	if s.tables[collection] {
+		return nil
+	}
+
+	stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id text PRIMARY KEY, doc jsonb NOT NULL)", pq.QuoteIdentifier(collection))
+	if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("could not create the %s table: %w", collection, err)
+	}
+
+	s.tables[collection] = true
+	return nil
+}
+
+// prepare connects to the database and creates the table for the collection.
+func (s *Store) prepare(ctx context.Context, collection string) (context.Context, context.CancelFunc, error) {
+	if err := s.Connect(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	cxt, cancel := context.WithTimeout(ctx, s.timeout)
+	if err := s.ensureTable(cxt, collection); err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return cxt, cancel, nil
+}
+
+func (s *Store) Aggregate(ctx context.Context, opts plugins.AggregateOptions) ([]bson.Raw, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cxt, cancel, err := s.prepare(ctx, opts.Collection)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	defer cancel()
+
+	// Stages before the first $group are executed by the database, the
+	// remaining stages are applied to the results
+	var b queryBuilder
+	var conditions []string
+	var sortDoc bson.D
+	var skip, limit int64
+	stages := opts.Pipeline
+pushdown:
+	for len(stages) > 0 && len(stages[0]) == 1 {
+		stage := stages[0][0]
+		switch stage.Key {
+		case "$match":
+			// Filtering after $skip or $limit changes the results
+			if skip > 0 || limit > 0 {
+				break pushdown
+			}
+			filter, ok := documents.ToMap(stage.Value)
+			if !ok {
+				return nil, span.Error(errors.New("the value of $match must be a document"))
+			}
+			condition, err := b.where(filter)
+			if err != nil {
+				return nil, span.Error(err)
+			}
+			conditions = append(conditions, condition)
+		case "$sort":
+			if skip > 0 || limit > 0 {
+				break pushdown
+			}
+			if sortDoc, _ = documents.ToOrderedMap(stage.Value); sortDoc == nil {
+				return nil, span.Error(errors.New("the value of $sort must be a document"))
+			}
+		case "$skip":
+			if skip > 0 || limit > 0 {
+				break pushdown
+			}
+			skip, _ = documents.ToInt(stage.Value)
+		case "$limit":
+			if limit > 0 {
+				break pushdown
+			}
+			limit, _ = documents.ToInt(stage.Value)
+		default:
+			break pushdown
+		}
+		stages = stages[1:]
+	}
+
+	where := "TRUE"
+	if len(conditions) > 0 {
+		where = strings.Join(conditions, " AND ")
+	}
+	docs, err := s.query(cxt, opts.Collection, &b, where, sortDoc, skip, limit)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	for _, stage := range stages {
+		if len(stage) != 1 || stage[0].Key != "$group" {
+			return nil, span.Error(fmt.Errorf("the aggregation pipeline is not supported by the postgres plugin, only $match, $sort, $skip and $limit stages followed by $group stages are supported"))
+		}
+		if docs, err = documents.Group(docs, stage[0].Value); err != nil {
+			return nil, span.Error(err)
+		}
+	}
+
+	results, err := documents.ToRawDocuments(docs)
+	return results, span.Error(err)
+}
+
+// EnsureIndexes makes sure that the specified indexes exist and are
+// defined appropriately.
+func (s *Store) EnsureIndex(ctx context.Context, opts plugins.EnsureIndexOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	for _, index := range opts.Indices {
+		if err := s.ensureIndex(ctx, index); err != nil {
+			return span.Error(err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) ensureIndex(ctx context.Context, index plugins.Index) error {
+	cxt, cancel, err := s.prepare(ctx, index.Collection)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	// Name the index after its keys, like mongodb, e.g. installations_namespace_1_name_1
+	name := index.Collection
+	terms := make([]string, len(index.Keys))
+	for i, key := range index.Keys {
+		direction, ok := documents.ToInt(key.Value)
+		if !ok || (direction != 1 && direction != -1) {
+			return fmt.Errorf("invalid index %s on %s, the sort order must be 1 or -1", key.Key, index.Collection)
+		}
+
+		name += fmt.Sprintf("_%s_%d", key.Key, direction)
+		terms[i] = jsonPath(key.Key)
+		if direction == -1 {
+			terms[i] += " DESC"
+		}
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	stmt := fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s (%s)",
+		unique, pq.QuoteIdentifier(name), pq.QuoteIdentifier(index.Collection), strings.Join(terms, ", "))
+	if _, err := s.db.ExecContext(cxt, stmt); err != nil {
+		return fmt.Errorf("invalid index specified: %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Store) Count(ctx context.Context, opts plugins.CountOptions) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cxt, cancel, err := s.prepare(ctx, opts.Collection)
+	if err != nil {
+		return 0, span.Error(err)
+	}
+	defer cancel()
+
+	var b queryBuilder
+	where, err := b.where(opts.Filter)
+	if err != nil {
+		return 0, span.Error(err)
+	}
+
+	var count int64
+	stmt := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s", pq.QuoteIdentifier(opts.Collection), where)
+	err = s.db.QueryRowContext(cxt, stmt, b.args...).Scan(&count)
+	return count, span.Error(err)
+}
+
+func (s *Store) Find(ctx context.Context, opts plugins.FindOptions) ([]bson.Raw, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cxt, cancel, err := s.prepare(ctx, opts.Collection)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	defer cancel()
+
+	var b queryBuilder
+	where, err := b.where(opts.Filter)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	docs, err := s.query(cxt, opts.Collection, &b, where, opts.Sort, opts.Skip, opts.Limit)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+
+	for i := range docs {
+		docs[i] = documents.Project(docs[i], opts.Select)
+	}
+	results, err := documents.ToRawDocuments(docs)
+	return results, span.Error(err)
+}
+
+// query selects the documents in a collection that match a condition.
+func (s *Store) query(ctx context.Context, collection string, b *queryBuilder, where string, sortDoc bson.D, skip int64, limit int64) ([]map[string]interface{}, error) {
+	order, err := orderBy(sortDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := fmt.Sprintf("SELECT doc FROM %s WHERE %s%s", pq.QuoteIdentifier(collection), where, order)
+	if limit > 0 {
+		stmt += " LIMIT " + b.addParam(limit)
+	}
+	if skip > 0 {
+		stmt += " OFFSET " + b.addParam(skip)
+	}
+
+	rows, err := s.db.QueryContext(ctx, stmt, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []map[string]interface{}
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var doc map[string]interface{}
+		if err = json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("could not parse a document from the %s table: %w", collection, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+func (s *Store) Insert(ctx context.Context, opts plugins.InsertOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cxt, cancel, err := s.prepare(ctx, opts.Collection)
+	if err != nil {
+		return span.Error(err)
+	}
+	defer cancel()
+
+	tx, err := s.db.BeginTx(cxt, nil)
+	if err != nil {
+		return span.Error(err)
+	}
+	defer tx.Rollback()
+
+	stmt := fmt.Sprintf("INSERT INTO %s (id, doc) VALUES ($1, $2::jsonb)", pq.QuoteIdentifier(opts.Collection))
+	for _, doc := range opts.Documents {
+		id, data, err := documents.Marshal(doc)
+		if err != nil {
+			return span.Error(err)
+		}
+		if _, err = tx.ExecContext(cxt, stmt, id, data); err != nil {
+			return span.Error(err)
+		}
+	}
+
+	return span.Error(tx.Commit())
+}
+
+func (s *Store) Patch(ctx context.Context, opts plugins.PatchOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cxt, cancel, err := s.prepare(ctx, opts.Collection)
+	if err != nil {
+		return span.Error(err)
+	}
+	defer cancel()
+
+	var b queryBuilder
+	where, err := b.where(opts.QueryDocument)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	tx, err := s.db.BeginTx(cxt, nil)
+	if err != nil {
+		return span.Error(err)
+	}
+	defer tx.Rollback()
+
+	// Lock the first matching document while it is modified
+	var id string
+	var data []byte
+	table := pq.QuoteIdentifier(opts.Collection)
+	stmt := fmt.Sprintf("SELECT id, doc FROM %s WHERE %s LIMIT 1 FOR UPDATE", table, where)
+	err = tx.QueryRowContext(cxt, stmt, b.args...).Scan(&id, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return span.Error(err)
+	}
+
+	var doc map[string]interface{}
+	if err = json.Unmarshal(data, &doc); err != nil {
+		return span.Error(fmt.Errorf("could not parse a document from the %s table: %w", opts.Collection, err))
+	}
+
+	if err = documents.ApplyPatch(doc, opts.Transformation); err != nil {
+		return span.Error(err)
+	}
+
+	_, data, err = documents.Marshal(doc)
+	if err != nil {
+		return span.Error(err)
+	}
+	stmt = fmt.Sprintf("UPDATE %s SET doc = $1::jsonb WHERE id = $2", table)
+	if _, err = tx.ExecContext(cxt, stmt, data, id); err != nil {
+		return span.Error(err)
+	}
+
+	return span.Error(tx.Commit())
+}
+
+func (s *Store) Remove(ctx context.Context, opts plugins.RemoveOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cxt, cancel, err := s.prepare(ctx, opts.Collection)
+	if err != nil {
+		return span.Error(err)
+	}
+	defer cancel()
+
+	var b queryBuilder
+	where, err := b.where(opts.Filter)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	table := pq.QuoteIdentifier(opts.Collection)
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where)
+	if !opts.All {
+		stmt = fmt.Sprintf("DELETE FROM %s WHERE id = (SELECT id FROM %s WHERE %s LIMIT 1)", table, table, where)
+	}
+
+	_, err = s.db.ExecContext(cxt, stmt, b.args...)
+	return span.Error(err)
+}
+
+func (s *Store) Update(ctx context.Context, opts plugins.UpdateOptions) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	cxt, cancel, err := s.prepare(ctx, opts.Collection)
+	if err != nil {
+		return span.Error(err)
+	}
+	defer cancel()
+
+	var b queryBuilder
+	where, err := b.where(opts.Filter)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	// Replace the first matching document, keeping its id when the replacement does not have one
+	doc := opts.Document
+	_, hasID := doc["_id"]
+	id, data, err := documents.Marshal(doc)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	table := pq.QuoteIdentifier(opts.Collection)
+	idParam, docParam := b.addParam(id), b.addParam(data)
+	set := fmt.Sprintf("id = %s, doc = %s::jsonb", idParam, docParam)
+	if !hasID {
+		set = fmt.Sprintf("doc = jsonb_set(%s::jsonb, '{_id}', to_jsonb(id))", docParam)
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE id = (SELECT id FROM %s WHERE %s LIMIT 1)", table, set, table, where)
+	result, err := s.db.ExecContext(cxt, stmt, b.args...)
+	if err != nil {
+		return span.Error(err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return span.Error(err)
+	}
+	if updated > 0 || !opts.Upsert {
+		return nil
+	}
+
+	stmt = fmt.Sprintf("INSERT INTO %s (id, doc) VALUES ($1, $2::jsonb) ON CONFLICT (id) DO UPDATE SET doc = EXCLUDED.doc", table)
+	_, err = s.db.ExecContext(cxt, stmt, id, data)
+	return span.Error(err)
+}
+
+// RemoveDatabase removes the tables of all the collections in the current schema.
+func (s *Store) RemoveDatabase(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.EndSpan()
+
+	if err := s.Connect(ctx); err != nil {
+		return span.Error(err)
+	}
+
+	cxt, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(cxt, "SELECT tablename FROM pg_tables WHERE schemaname = current_schema()")
+	if err != nil {
+		return span.Error(err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			rows.Close()
+			return span.Error(err)
+		}
+		tables = append(tables, pq.QuoteIdentifier(table))
+	}
+	rows.Close()
+	if len(tables) == 0 {
+		return nil
+	}
+
+	span.Info("Dropping tables", attribute.StringSlice("tables", tables))
+	if _, err = s.db.ExecContext(cxt, "DROP TABLE "+strings.Join(tables, ", ")); err != nil {
+		return span.Error(err)
+	}
+
+	s.tablesMu.Lock()
+	s.tables = make(map[string]bool)
+	s.tablesMu.Unlock()
+	return nil
+}
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"get.porter.sh/porter/pkg/storage/plugins/documents"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// queryBuilder translates the mongodb query documents used by Porter into SQL
+// that queries the jsonb documents stored in a table, accumulating the
+// parameters of the statement.
+type queryBuilder struct {
+	args []interface{}
+}
+
+// addParam adds a parameter to the statement and returns its placeholder.
+func (b *queryBuilder) addParam(value interface{}) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// addJSONParam adds a value as a jsonb parameter to the statement and returns
+// its placeholder.
+func (b *queryBuilder) addJSONParam(value interface{}) (string, error) {
+	data, err := json.Marshal(documents.ToJSONValue(value))
+	if err != nil {
+		return "", fmt.Errorf("could not convert %v to json: %w", value, err)
+	}
+	return b.addParam(string(data)) + "::jsonb", nil
+}
+
+// where converts a query filter document to a SQL condition.
+// See https://docs.mongodb.com/manual/core/document/#std-label-document-query-filter
+func (b *queryBuilder) where(filter map[string]interface{}) (string, error) {
+	if len(filter) == 0 {
+		return "TRUE", nil
+	}
+
+	// Sort the fields so that the same filter always generates the same statement
+	keys := make([]string, 0, len(filter))
+	for key := range filter {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	conditions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var condition string
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			condition, err = b.logical(key, filter[key])
+		default:
+			if strings.HasPrefix(key, "$") {
+				return "", fmt.Errorf("the %s query operator is not supported by the postgres plugin", key)
+			}
+			condition, err = b.field(key, filter[key])
+		}
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", nil
+}
+
+// logical converts a logical query operator, such as $or, to a SQL condition.
+func (b *queryBuilder) logical(operator string, value interface{}) (string, error) {
+	items, ok := documents.ToSlice(value)
+	if !ok || len(items) == 0 {
+		return "", fmt.Errorf("%s must be a nonempty array", operator)
+	}
+
+	conditions := make([]string, len(items))
+	for i, item := range items {
+		filter, ok := documents.ToMap(item)
+		if !ok {
+			return "", fmt.Errorf("the elements of %s must be query documents", operator)
+		}
+		condition, err := b.where(filter)
+		if err != nil {
+			return "", err
+		}
+		conditions[i] = condition
+	}
+
+	switch operator {
+	case "$and":
+		return "(" + strings.Join(conditions, " AND ") + ")", nil
+	case "$or":
+		return "(" + strings.Join(conditions, " OR ") + ")", nil
+	default: // $nor
+		return "NOT COALESCE((" + strings.Join(conditions, " OR ") + "), FALSE)", nil
+	}
+}
+
+// field converts the condition on a field of the document to SQL. The
+// condition is either a value that the field must equal, or a document of
+// query operators, such as {"$gt": 1}.
+func (b *queryBuilder) field(name string, value interface{}) (string, error) {
+	operators, ok := documents.ToMap(value)
+	if !ok || !documents.IsOperatorDocument(operators) {
+		return b.equals(name, value)
+	}
+
+	keys := make([]string, 0, len(operators))
+	for key := range operators {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	conditions := make([]string, 0, len(keys))
+	for _, operator := range keys {
+		operand := operators[operator]
+		path := jsonPath(name)
+
+		var condition string
+		var err error
+		switch operator {
+		case "$eq":
+			condition, err = b.equals(name, operand)
+		case "$ne":
+			condition, err = b.equals(name, operand)
+			condition = "NOT COALESCE(" + condition + ", FALSE)"
+		case "$in", "$nin":
+			condition, err = b.in(name, operand)
+			if operator == "$nin" {
+				condition = "NOT COALESCE(" + condition + ", FALSE)"
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			var param string
+			param, err = b.addJSONParam(operand)
+			condition = fmt.Sprintf("%s %s %s", path, comparisonOperators[operator], param)
+		case "$exists":
+			if exists, _ := operand.(bool); exists {
+				condition = path + " IS NOT NULL"
+			} else {
+				condition = path + " IS NULL"
+			}
+		case "$regex":
+			options, _ := operators["$options"].(string)
+			condition, err = b.regex(name, operand, options)
+		case "$options":
+			// Handled with $regex
+			continue
+		default:
+			return "", fmt.Errorf("the %s query operator is not supported by the postgres plugin", operator)
+		}
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", nil
+}
+
+var comparisonOperators = map[string]string{
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+}
+
+// equals converts an equality condition on a field to SQL. Similar to mongodb,
+// matching on null also matches documents where the field is not set.
+func (b *queryBuilder) equals(name string, value interface{}) (string, error) {
+	path := jsonPath(name)
+	switch v := value.(type) {
+	case nil:
+		return fmt.Sprintf("(%s IS NULL OR %s = 'null'::jsonb)", path, path), nil
+	case primitive.Regex:
+		return b.regex(name, v.Pattern, v.Options)
+	}
+
+	param, err := b.addJSONParam(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s = %s", path, param), nil
+}
+
+// in converts an $in condition on a field to SQL.
+func (b *queryBuilder) in(name string, value interface{}) (string, error) {
+	items, ok := documents.ToSlice(value)
+	if !ok {
+		return "", fmt.Errorf("the value of $in and $nin must be an array")
+	}
+	if len(items) == 0 {
+		return "FALSE", nil
+	}
+
+	conditions := make([]string, len(items))
+	for i, item := range items {
+		condition, err := b.equals(name, item)
+		if err != nil {
+			return "", err
+		}
+		conditions[i] = condition
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", nil
+}
+
+// regex converts a regular expression match on a field to SQL, using the
+// POSIX regular expressions supported by PostgreSQL.
+func (b *queryBuilder) regex(name string, pattern interface{}, options string) (string, error) {
+	var expr string
+	switch p := pattern.(type) {
+	case string:
+		expr = p
+	case primitive.Regex:
+		expr = p.Pattern
+		options += p.Options
+	default:
+		return "", fmt.Errorf("the value of $regex must be a string")
+	}
+
+	for _, option := range options {
+		if option != 'i' {
+			return "", fmt.Errorf("the %c $regex option is not supported by the postgres plugin", option)
+		}
+	}
+
+	operator := "~"
+	if strings.Contains(options, "i") {
+		operator = "~*"
+	}
+	return fmt.Sprintf("%s %s %s", jsonTextPath(name), operator, b.addParam(expr)), nil
+}
+
+// orderBy converts a sort document to a SQL ORDER BY clause. Similar to
+// mongodb, documents that do not have the field are sorted first.
+func orderBy(sortDoc bson.D) (string, error) {
+	if len(sortDoc) == 0 {
+		return "", nil
+	}
+
+	terms := make([]string, len(sortDoc))
+	for i, key := range sortDoc {
+		direction, ok := documents.ToInt(key.Value)
+		if !ok || (direction != 1 && direction != -1) {
+			return "", fmt.Errorf("invalid sort order %v for %s, the sort order must be 1 or -1", key.Value, key.Key)
+		}
+
+		if direction == 1 {
+			terms[i] = jsonPath(key.Key) + " ASC NULLS FIRST"
+		} else {
+			terms[i] = jsonPath(key.Key) + " DESC NULLS LAST"
+		}
+	}
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// jsonPath returns the SQL expression that selects a field of the document as
+// jsonb. Nested fields are specified with dots, e.g. status.runId.
+func jsonPath(field string) string {
+	var path strings.Builder
+	path.WriteString("doc")
+	for _, part := range strings.Split(field, ".") {
+		path.WriteString(" -> ")
+		path.WriteString(pq.QuoteLiteral(part))
+	}
+	return "(" + path.String() + ")"
+}
+
+// jsonTextPath returns the SQL expression that selects a field of the document
+// as text.
+func jsonTextPath(field string) string {
+	path := jsonPath(field)
+	i := strings.LastIndex(path, " -> ")
+	return path[:i] + " ->> " + path[i+len(" -> "):]
+}
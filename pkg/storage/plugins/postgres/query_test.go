@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestQueryBuilder_Where(t *testing.T) {
+	testcases := []struct {
+		name     string
+		filter   bson.M
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  string
+	}{
+		{
+			name:    "empty",
+			filter:  bson.M{},
+			wantSQL: "TRUE",
+		},
+		{
+			name:     "equals",
+			filter:   bson.M{"namespace": "dev", "name": "mybuns"},
+			wantSQL:  `((doc -> 'name') = $1::jsonb AND (doc -> 'namespace') = $2::jsonb)`,
+			wantArgs: []interface{}{`"mybuns"`, `"dev"`},
+		},
+		{
+			name:     "nested field",
+			filter:   bson.M{"labels.team": "o'reilly"},
+			wantSQL:  `(doc -> 'labels' -> 'team') = $1::jsonb`,
+			wantArgs: []interface{}{`"o'reilly"`},
+		},
+		{
+			name:    "null",
+			filter:  bson.M{"uninstalled": nil},
+			wantSQL: `((doc -> 'uninstalled') IS NULL OR (doc -> 'uninstalled') = 'null'::jsonb)`,
+		},
+		{
+			name: "or",
+			filter: bson.M{"name": "mycreds", "$or": []interface{}{
+				map[string]interface{}{"namespace": ""},
+				map[string]interface{}{"namespace": "dev"},
+			}},
+			wantSQL:  `(((doc -> 'namespace') = $1::jsonb OR (doc -> 'namespace') = $2::jsonb) AND (doc -> 'name') = $3::jsonb)`,
+			wantArgs: []interface{}{`""`, `"dev"`, `"mycreds"`},
+		},
+		{
+			name:     "in",
+			filter:   bson.M{"runId": bson.M{"$in": []string{"1", "2"}}},
+			wantSQL:  `((doc -> 'runId') = $1::jsonb OR (doc -> 'runId') = $2::jsonb)`,
+			wantArgs: []interface{}{`"1"`, `"2"`},
+		},
+		{
+			name:    "empty in",
+			filter:  bson.M{"runId": bson.M{"$in": []interface{}{}}},
+			wantSQL: `FALSE`,
+		},
+		{
+			name:     "gt",
+			filter:   bson.M{"runId": "1", "sequence": bson.M{"$gt": int64(5)}},
+			wantSQL:  `((doc -> 'runId') = $1::jsonb AND (doc -> 'sequence') > $2::jsonb)`,
+			wantArgs: []interface{}{`"1"`, `5`},
+		},
+		{
+			name:    "exists",
+			filter:  bson.M{"schedule": bson.M{"$exists": true}},
+			wantSQL: `(doc -> 'schedule') IS NOT NULL`,
+		},
+		{
+			name:     "regex",
+			filter:   bson.M{"name": bson.M{"$regex": "^my", "$options": "i"}},
+			wantSQL:  `(doc ->> 'name') ~* $1`,
+			wantArgs: []interface{}{"^my"},
+		},
+		{
+			name:     "ne",
+			filter:   bson.M{"status.action": bson.M{"$ne": "install"}},
+			wantSQL:  `NOT COALESCE((doc -> 'status' -> 'action') = $1::jsonb, FALSE)`,
+			wantArgs: []interface{}{`"install"`},
+		},
+		{
+			name:     "document",
+			filter:   bson.M{"bundle": bson.M{"repository": "example.com/mybuns"}},
+			wantSQL:  `(doc -> 'bundle') = $1::jsonb`,
+			wantArgs: []interface{}{`{"repository":"example.com/mybuns"}`},
+		},
+		{
+			name:    "unsupported operator",
+			filter:  bson.M{"name": bson.M{"$elemMatch": bson.M{}}},
+			wantErr: "the $elemMatch query operator is not supported by the postgres plugin",
+		},
+		{
+			name:    "unsupported regex option",
+			filter:  bson.M{"name": bson.M{"$regex": "^my", "$options": "x"}},
+			wantErr: "the x $regex option is not supported by the postgres plugin",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var b queryBuilder
+			sql, err := b.where(tc.filter)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSQL, sql)
+			assert.Equal(t, tc.wantArgs, b.args)
+		})
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	sql, err := orderBy(bson.D{{Key: "namespace", Value: 1}, {Key: "resultId", Value: float64(-1)}})
+	require.NoError(t, err)
+	assert.Equal(t, ` ORDER BY (doc -> 'namespace') ASC NULLS FIRST, (doc -> 'resultId') DESC NULLS LAST`, sql)
+
+	_, err = orderBy(bson.D{{Key: "name", Value: 2}})
+	require.EqualError(t, err, "invalid sort order 2 for name, the sort order must be 1 or -1")
+}
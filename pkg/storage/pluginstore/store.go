@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/plugins/pluggable"
@@ -19,16 +20,21 @@ var _ plugins.StorageProtocol = &Store{}
 // plugin based on Porter's config and implements the plugins.StorageProtocol interface
 // using the backing plugin.
 //
-// Connects just-in-time, but you must call Close to release resources.
+// Connects just-in-time using plugins from the pool, which is responsible for
+// stopping the plugin. The store is safe for concurrent use.
 type Store struct {
 	*config.Config
+	pool *pluggable.PluginPool
+
+	// mu ensures that the plugin is only requested from the pool once.
+	mu     sync.Mutex
 	plugin plugins.StorageProtocol
-	conn   *pluggable.PluginConnection
 }
 
-func NewStore(c *config.Config) *Store {
+func NewStore(c *config.Config, pool *pluggable.PluginPool) *Store {
 	return &Store{
 		Config: c,
+		pool:   pool,
 	}
 }
 
@@ -54,6 +60,9 @@ func NewStoragePluginConfig() pluggable.PluginTypeConfig {
 // The plugin itself is responsible for ensuring it was called.
 // Close is called automatically when the plugin is used by Porter.
 func (s *Store) Connect(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.plugin != nil {
 		return nil
 	}
@@ -63,16 +72,13 @@ func (s *Store) Connect(ctx context.Context) error {
 
 	pluginType := NewStoragePluginConfig()
 
-	l := pluggable.NewPluginLoader(s.Config)
-	conn, err := l.Load(ctx, pluginType)
+	conn, err := s.pool.Get(ctx, pluginType)
 	if err != nil {
 		return span.Error(fmt.Errorf("could not load %s plugin: %w", pluginType.Interface, err))
 	}
-	s.conn = conn
 
 	store, ok := conn.GetClient().(plugins.StorageProtocol)
 	if !ok {
-		conn.Close(ctx)
 		return span.Error(fmt.Errorf("the interface (%T) exposed by the %s plugin was not plugins.StorageProtocol", conn.GetClient(), conn))
 	}
 
@@ -81,11 +87,13 @@ func (s *Store) Connect(ctx context.Context) error {
 	return nil
 }
 
+// Close releases the store's connection to the plugin. The plugin keeps
+// running until the pool is closed.
 func (s *Store) Close() error {
-	if s.conn != nil {
-		s.conn.Close(context.Background())
-		s.conn = nil
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin = nil
 	return nil
 }
 
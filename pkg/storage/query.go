@@ -300,16 +300,25 @@ type ListOptions struct {
 	// Labels is used to filter result list based on a key-value pair.
 	Labels map[string]string
 
+	// BundleAnnotations is used to filter installations based on the
+	// annotations of the bundle that last altered the installation.
+	BundleAnnotations map[string]string
+
 	// Skip is the number of results to skip past and exclude from the results.
 	Skip int64
 
 	// Limit is the number of results to return.
 	Limit int64
+
+	// Continue returns the results sorted after the last document of a
+	// previous page of results.
+	Continue *ContinueToken
 }
 
 // ToFindOptions builds a query for a list of documents with these conditions:
 // * sorted in ascending order by namespace first and then name
-// * filtered by matching namespace, name contains substring, and labels contain all matches
+// * filtered by matching namespace, name contains substring, and labels and bundle annotations contain all matches
+// * continued after the last document of a previous page
 // * skipped and limited to a certain number of result
 func (o ListOptions) ToFindOptions() FindOptions {
 	filter := make(map[string]interface{}, 3)
@@ -322,6 +331,12 @@ func (o ListOptions) ToFindOptions() FindOptions {
 	for k, v := range o.Labels {
 		filter["labels."+k] = v
 	}
+	for k, v := range o.BundleAnnotations {
+		filter["status.bundleAnnotations."+k] = v
+	}
+	if o.Continue != nil {
+		filter["$or"] = o.Continue.toFilter()
+	}
 
 	return FindOptions{
 		Sort:   []string{"namespace", "name"},
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"get.porter.sh/porter/pkg/storage/plugins/documents"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -81,6 +82,8 @@ func TestListOptions_ToFindOptions(t *testing.T) {
 		Labels:    map[string]string{"key": "value"},
 		Skip:      1,
 		Limit:     1,
+
+		BundleAnnotations: map[string]string{"team": "platform"},
 	}
 
 	wantOpts := FindOptions{
@@ -88,12 +91,67 @@ func TestListOptions_ToFindOptions(t *testing.T) {
 		Skip:  1,
 		Limit: 1,
 		Filter: primitive.M{
-			"labels.key": "value",
-			"name":       map[string]interface{}{"$regex": "name"},
-			"namespace":  "dev",
+			"labels.key":                    "value",
+			"name":                          map[string]interface{}{"$regex": "name"},
+			"namespace":                     "dev",
+			"status.bundleAnnotations.team": "platform",
+		},
+	}
+
+	gotOpts := opts.ToFindOptions()
+	require.Equal(t, wantOpts, gotOpts)
+}
+
+func TestListOptions_ToFindOptions_Continue(t *testing.T) {
+	token := NewContinueToken("dev", "mysql")
+	opts := ListOptions{
+		Namespace: "*",
+		Limit:     10,
+		Continue:  &token,
+	}
+
+	wantOpts := FindOptions{
+		Sort:  []string{"namespace", "name"},
+		Limit: 10,
+		Filter: primitive.M{
+			"$or": bson.A{
+				bson.M{"namespace": bson.M{"$gt": "dev"}},
+				bson.M{"namespace": "dev", "name": bson.M{"$gt": "mysql"}},
+			},
 		},
 	}
 
 	gotOpts := opts.ToFindOptions()
 	require.Equal(t, wantOpts, gotOpts)
+
+	// Check that only the documents after the token are matched
+	for _, doc := range []struct {
+		namespace string
+		name      string
+		want      bool
+	}{
+		{"", "wordpress", false},
+		{"dev", "aks", false},
+		{"dev", "mysql", false},
+		{"dev", "wordpress", true},
+		{"prod", "aks", true},
+	} {
+		matched, err := documents.Match(map[string]interface{}{"namespace": doc.namespace, "name": doc.name}, gotOpts.Filter)
+		require.NoError(t, err)
+		require.Equal(t, doc.want, matched, "unexpected match for %s/%s", doc.namespace, doc.name)
+	}
+}
+
+func TestParseContinueToken(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		token := NewContinueToken("dev", "mysql")
+		got, err := ParseContinueToken(token.String())
+		require.NoError(t, err)
+		require.Equal(t, token, got)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseContinueToken("not a token")
+		require.ErrorContains(t, err, `invalid continue token "not a token"`)
+	})
 }
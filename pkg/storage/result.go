@@ -40,6 +40,20 @@ type Result struct {
 
 	// Custom extension data applicable to a given runtime.
 	Custom interface{} `json:"custom,omitempty"`
+
+	// Started is when the bundle began executing. Only set on the final result of a run.
+	Started *time.Time `json:"started,omitempty"`
+
+	// Stopped is when the bundle finished executing. Only set on the final result of a run.
+	Stopped *time.Time `json:"stopped,omitempty"`
+
+	// Steps records how long each step of the action took to execute, in the
+	// order that they were executed.
+	Steps []cnab.StepMetric `json:"steps,omitempty"`
+
+	// TimedOut indicates that the bundle was stopped because the action, or one
+	// of its steps, did not complete in time.
+	TimedOut bool `json:"timedOut,omitempty"`
 }
 
 func (r Result) DefaultDocumentFilter() map[string]interface{} {
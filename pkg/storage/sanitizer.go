@@ -14,6 +14,8 @@ import (
 type Sanitizer struct {
 	parameter ParameterSetProvider
 	secrets   secrets.Store
+	encryptor *Encryptor
+	outputs   *LargeOutputs
 }
 
 // NewSanitizer creates a new service for sanitizing sensitive data and save them
@@ -25,6 +27,20 @@ func NewSanitizer(parameterstore ParameterSetProvider, secretstore secrets.Store
 	}
 }
 
+// UseEncryption configures the sanitizer to encrypt sensitive data with the
+// specified encryptor when encryption is enabled, storing the encrypted value
+// on the record instead of saving it to the secret store.
+func (s *Sanitizer) UseEncryption(encryptor *Encryptor) {
+	s.encryptor = encryptor
+}
+
+// UseLargeOutputs configures the sanitizer to save the value of outputs that
+// are larger than the configured threshold to a large output store, storing
+// its location on the output record instead of the value.
+func (s *Sanitizer) UseLargeOutputs(outputs *LargeOutputs) {
+	s.outputs = outputs
+}
+
 // CleanRawParameters clears out sensitive data in raw parameter values (resolved parameter values stored on a Run) before
 // transform the raw value into secret strategies.
 // The id argument is used to associate the reference key with the corresponding
@@ -56,8 +72,18 @@ func (s *Sanitizer) CleanRawParameters(ctx context.Context, params map[string]in
 func (s *Sanitizer) CleanParameters(ctx context.Context, dirtyParams []secrets.Strategy, bun cnab.ExtendedBundle, id string) ([]secrets.Strategy, error) {
 	cleanedParams := make([]secrets.Strategy, 0, len(dirtyParams))
 	for _, param := range dirtyParams {
-		// Store sensitive hard-coded values in a secret store
-		if param.Source.Key == host.SourceValue && bun.IsSensitiveParameter(param.Name) {
+		// Encrypt sensitive hard-coded values when encryption is configured
+		if param.Source.Key == host.SourceValue && bun.IsSensitiveParameter(param.Name) && s.encryptor.IsEnabled() {
+			encrypted, err := s.encryptor.Encrypt(ctx, param.Source.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt sensitive param %s: %w", param.Name, err)
+			}
+
+			cleaned := param
+			cleaned.Source = secrets.Source{Key: SourceEncrypted, Value: encrypted}
+			cleanedParams = append(cleanedParams, cleaned)
+		} else if param.Source.Key == host.SourceValue && bun.IsSensitiveParameter(param.Name) {
+			// Store sensitive hard-coded values in a secret store
 			cleaned := sanitizedParam(param, id)
 			err := s.secrets.Create(ctx, cleaned.Source.Key, cleaned.Source.Value, cleaned.Value)
 			if err != nil {
@@ -84,7 +110,8 @@ func (s *Sanitizer) CleanParameters(ctx context.Context, dirtyParams []secrets.S
 // run or installation record in porter's database.
 func LinkSensitiveParametersToSecrets(pset ParameterSet, bun cnab.ExtendedBundle, id string) ParameterSet {
 	for i, param := range pset.Parameters {
-		if !bun.IsSensitiveParameter(param.Name) {
+		// Encrypted values are already safe to store and are not saved in the secret store
+		if !bun.IsSensitiveParameter(param.Name) || param.Source.Key == SourceEncrypted {
 			continue
 		}
 		pset.Parameters[i] = sanitizedParam(param, id)
@@ -122,7 +149,8 @@ func (s *Sanitizer) RestoreParameterSet(ctx context.Context, pset ParameterSet,
 
 // CleanOutput clears data that's defined as sensitive on the bundle definition
 // by storing the raw data into a secret store and store it's reference key onto
-// the output record.
+// the output record. When encryption is configured, the sensitive data is
+// encrypted and stored on the output record instead.
 func (s *Sanitizer) CleanOutput(ctx context.Context, output Output, bun cnab.ExtendedBundle) (Output, error) {
 	// Skip outputs not defined in the bundle, e.g. io.cnab.outputs.invocationImageLogs
 	_, ok := output.GetSchema(bun)
@@ -137,8 +165,19 @@ func (s *Sanitizer) CleanOutput(ctx context.Context, output Output, bun cnab.Ext
 	}
 
 	if !sensitive {
-		return output, nil
+		return s.saveLargeOutput(ctx, output)
+	}
 
+	// Encrypt sensitive values when encryption is configured
+	if s.encryptor.IsEnabled() {
+		encrypted, err := s.encryptor.Encrypt(ctx, string(output.Value))
+		if err != nil {
+			output.Value = nil
+			return output, fmt.Errorf("failed to encrypt sensitive output %s: %w", output.Name, err)
+		}
+		output.Value = []byte(encrypted)
+		output.Encrypted = true
+		return output, nil
 	}
 
 	secretOt := sanitizedOutput(output)
@@ -151,6 +190,22 @@ func (s *Sanitizer) CleanOutput(ctx context.Context, output Output, bun cnab.Ext
 	return secretOt, nil
 }
 
+// saveLargeOutput saves the value of an output to the large output store when
+// it is larger than the configured threshold.
+func (s *Sanitizer) saveLargeOutput(ctx context.Context, output Output) (Output, error) {
+	if !s.outputs.ShouldSave(output.Value) {
+		return output, nil
+	}
+
+	location, err := s.outputs.Save(ctx, output.RunID+"/"+output.Name, output.Value)
+	if err != nil {
+		return output, err
+	}
+	output.Location = location
+	output.Value = nil
+	return output, nil
+}
+
 func sanitizedOutput(output Output) Output {
 	output.Key = output.RunID + "-" + output.Name
 	output.Value = nil
@@ -176,6 +231,29 @@ func (s *Sanitizer) RestoreOutputs(ctx context.Context, o Outputs) (Outputs, err
 // RestoreOutput retrieves the raw output value and return the restored output
 // record.
 func (s *Sanitizer) RestoreOutput(ctx context.Context, output Output) (Output, error) {
+	if output.Encrypted {
+		if s.encryptor == nil {
+			return output, fmt.Errorf("the value of output %s is encrypted but encryption is not configured", output.Name)
+		}
+		value, err := s.encryptor.Decrypt(ctx, string(output.Value))
+		if err != nil {
+			return output, err
+		}
+		output.Value = []byte(value)
+		output.Encrypted = false
+		return output, nil
+	}
+	if output.Location != "" {
+		if s.outputs == nil {
+			return output, fmt.Errorf("the value of output %s was saved to %s but large outputs are not configured", output.Name, output.Location)
+		}
+		value, err := s.outputs.Load(ctx, output.Location)
+		if err != nil {
+			return output, err
+		}
+		output.Value = value
+		return output, nil
+	}
 	if output.Key == "" {
 		return output, nil
 	}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"get.porter.sh/porter/pkg/cnab"
+	"get.porter.sh/porter/pkg/config"
 	"get.porter.sh/porter/pkg/porter"
 	"get.porter.sh/porter/pkg/portercontext"
 	"get.porter.sh/porter/pkg/secrets"
@@ -170,3 +171,63 @@ func TestSanitizer_Output(t *testing.T) {
 	require.Truef(t, reflect.DeepEqual(expectedOutputs, resolved), "expected outputs: %v, got outputs: %v", expectedOutputs, resolved)
 
 }
+
+func TestSanitizer_LargeOutput(t *testing.T) {
+	c := portercontext.New()
+	bun, err := cnab.LoadBundle(c, filepath.Join("../porter/testdata/bundle.json"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	r := porter.NewTestPorter(t)
+	defer r.Close()
+
+	r.Data.Outputs.Store = config.OutputStoreFilesystem
+	r.Data.Outputs.Path = "/outputs"
+	r.Data.Outputs.Threshold = 10
+
+	recordID := "01FZVC5AVP8Z7A78CSCP1EJ604"
+	smallOutput := storage.Output{Name: "my-second-output", Value: []byte("true"), RunID: recordID}
+	largeOutput := storage.Output{Name: "kubeconfig", Value: []byte("apiVersion: v1\nkind: Config\n"), RunID: recordID}
+
+	smallResult, err := r.TestSanitizer.CleanOutput(ctx, smallOutput, bun)
+	require.NoError(t, err)
+	require.Equal(t, smallOutput, smallResult, "outputs under the threshold should be stored in the database")
+
+	largeResult, err := r.TestSanitizer.CleanOutput(ctx, largeOutput, bun)
+	require.NoError(t, err)
+	require.Nil(t, largeResult.Value, "the value of large outputs should not be stored in the database")
+	require.Equal(t, "file:///outputs/"+recordID+"/kubeconfig", largeResult.Location)
+
+	resolved, err := r.TestSanitizer.RestoreOutput(ctx, largeResult)
+	require.NoError(t, err)
+	require.Equal(t, largeOutput.Value, resolved.Value)
+}
+
+func TestSanitizer_EncryptedOutput(t *testing.T) {
+	c := portercontext.New()
+	bun, err := cnab.LoadBundle(c, filepath.Join("../porter/testdata/bundle.json"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	tc := config.NewTestConfig(t)
+	tc.Data.Encryption.Key = "my-secret-key"
+	store := storage.NewTestStore(tc)
+	defer store.Close()
+
+	s := storage.NewSanitizer(nil, secrets.NewTestSecretsProvider())
+	s.UseEncryption(storage.NewEncryptor(tc.Config, store))
+
+	recordID := "01FZVC5AVP8Z7A78CSCP1EJ604"
+	sensitiveOutput := storage.Output{Name: "my-first-output", Value: []byte("this is secret output"), RunID: recordID}
+
+	result, err := s.CleanOutput(ctx, sensitiveOutput, bun)
+	require.NoError(t, err)
+	require.True(t, result.Encrypted, "the sensitive output should be encrypted")
+	require.Empty(t, result.Key, "the encrypted output should not be saved to the secret store")
+	require.NotContains(t, string(result.Value), "this is secret output")
+
+	resolved, err := s.RestoreOutput(ctx, result)
+	require.NoError(t, err)
+	require.False(t, resolved.Encrypted)
+	require.Equal(t, sensitiveOutput.Value, resolved.Value)
+}
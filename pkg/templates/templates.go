@@ -3,6 +3,7 @@ package templates
 import (
 	"embed"
 	"fmt"
+	iofs "io/fs"
 
 	"get.porter.sh/porter/pkg/config"
 )
@@ -97,3 +98,12 @@ func (t *Templates) GetParameterSetJSON() ([]byte, error) {
 func (t *Templates) GetParameterSetYAML() ([]byte, error) {
 	return t.fs.ReadFile("templates/parameters/create/parameter-set.yaml")
 }
+
+// GetScaffold returns the templates of the files in a new mixin or plugin
+// project: mixin, plugin, plugin-secrets or plugin-storage.
+func (t *Templates) GetScaffold(name string) (iofs.FS, error) {
+	if _, err := t.fs.ReadDir("templates/scaffold/" + name); err != nil {
+		return nil, fmt.Errorf("no scaffold named %s: %w", name, err)
+	}
+	return iofs.Sub(t.fs, "templates/scaffold/"+name)
+}
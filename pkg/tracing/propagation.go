@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// EnvTraceParent is the environment variable that propagates the trace
+	// context to another process, formatted as a W3C traceparent header.
+	EnvTraceParent = "TRACEPARENT"
+
+	// EnvTraceState is the environment variable that propagates vendor specific
+	// trace data to another process, formatted as a W3C tracestate header.
+	EnvTraceState = "TRACESTATE"
+)
+
+// propagator formats the trace context using the W3C Trace Context specification.
+var propagator = propagation.TraceContext{}
+
+// InjectEnvironment returns the environment variables that propagate the trace
+// context of the current span to another process, such as a mixin or the
+// invocation image, so that the process can continue the trace.
+// No variables are returned when the context does not have a span.
+func InjectEnvironment(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	env := make(map[string]string, len(carrier))
+	if traceParent := carrier.Get("traceparent"); traceParent != "" {
+		env[EnvTraceParent] = traceParent
+	}
+	if traceState := carrier.Get("tracestate"); traceState != "" {
+		env[EnvTraceState] = traceState
+	}
+	return env
+}
+
+// ExtractEnvironment returns a context that continues the trace propagated
+// by the parent process with the TRACEPARENT environment variable.
+// The context is returned unchanged when a trace was not propagated.
+func ExtractEnvironment(ctx context.Context, getenv func(key string) string) context.Context {
+	carrier := propagation.MapCarrier{}
+	if traceParent := getenv(EnvTraceParent); traceParent != "" {
+		carrier.Set("traceparent", traceParent)
+		if traceState := getenv(EnvTraceState); traceState != "" {
+			carrier.Set("tracestate", traceState)
+		}
+	}
+	return propagator.Extract(ctx, carrier)
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEnvironmentPropagation(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	env := InjectEnvironment(ctx)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", env[EnvTraceParent])
+
+	extracted := trace.SpanContextFromContext(ExtractEnvironment(context.Background(), func(key string) string {
+		return env[key]
+	}))
+	assert.Equal(t, traceID, extracted.TraceID())
+	assert.Equal(t, spanID, extracted.SpanID())
+	assert.True(t, extracted.IsRemote(), "the extracted span should be marked as remote")
+}
+
+func TestEnvironmentPropagation_NoTrace(t *testing.T) {
+	assert.Empty(t, InjectEnvironment(context.Background()))
+
+	ctx := ExtractEnvironment(context.Background(), func(string) string { return "" })
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
@@ -56,9 +56,17 @@ func TestArchive_StableDigest(t *testing.T) {
 	require.NoError(t, err, "Second archive failed")
 	assert.Equal(p.T(), hash1, getHash(p, archiveFile2), "shasum of archive did not stay the same on the second call to archive")
 
-	// the archive should match the hash below regardless of OS architecture, user and execution time
-	consistentHash := "27c2cf6ef0dfac6f120290a79def2e3417656d0d44adc80991d864d7c8c398ed"
-	assert.Equal(p.T(), consistentHash, hash1, "shasum of archive did not match expected hash")
+	// Archive the bundle with each compression, the archive should be stable regardless of the compression used
+	for _, compression := range []string{porter.ArchiveCompressionZstd, porter.ArchiveCompressionNone} {
+		compressedOpts := porter.ArchiveOptions{Compression: compression}
+		compressedOpts.Reference = reference
+		compressedFile := "mybuns-" + compression + ".tar"
+		require.NoError(p.T(), compressedOpts.Validate(ctx, []string{compressedFile}, p.Porter), "validation of archive opts for bundle failed")
+		require.NoError(p.T(), p.Archive(ctx, compressedOpts), "archive with %s compression failed", compression)
+		hash := getHash(p, compressedFile)
+		require.NoError(p.T(), p.Archive(ctx, compressedOpts), "second archive with %s compression failed", compression)
+		assert.Equal(p.T(), hash, getHash(p, compressedFile), "shasum of the %s archive did not stay the same on the second call to archive", compression)
+	}
 
 	// Publish bundle from archive, with new reference
 	localReference := "localhost:5000/archived-whalegap:v0.2.0"